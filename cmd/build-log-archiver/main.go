@@ -0,0 +1,59 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The build-log-archiver runs alongside the controller and uploads the logs of
+// completed builds to the bucket set on their Space.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/google/kf/pkg/reconciler/buildlogs"
+	"github.com/google/kf/pkg/reconciler/leaderelection"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/signals"
+)
+
+const component = "build-log-archiver"
+
+func main() {
+	var (
+		masterURL  = flag.String("master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+		kubeconfig = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+		election   = leaderelection.NewDefaultConfig()
+	)
+
+	// The archiver runs in every controller replica so it needs its own lock.
+	election.ResourceName = "kf-build-log-archiver"
+	election.AddFlags(flag.CommandLine)
+	flag.Parse()
+
+	cfg, err := clientcmd.BuildConfigFromFlags(*masterURL, *kubeconfig)
+	if err != nil {
+		log.Fatalf("Error building kubeconfig: %s", err)
+	}
+
+	err = election.Run(signals.NewContext(), kubernetes.NewForConfigOrDie(cfg), func(ctx context.Context) {
+		sharedmain.MainWithConfig(ctx, component, cfg,
+			buildlogs.NewController,
+		)
+	})
+	if err != nil {
+		log.Fatalf("Error running %s: %s", component, err)
+	}
+}
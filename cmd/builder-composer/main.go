@@ -0,0 +1,45 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// The builder-composer adds buildpacks to a builder image. It's the last step
+// of the Builds Spaces use to compose builders with custom buildpacks.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/kf/pkg/kf/buildpacks"
+)
+
+func main() {
+	var (
+		base  = flag.String("base", "", "The builder image to add the buildpacks to.")
+		image = flag.String("image", "", "The image to push the composed builder to.")
+	)
+	flag.Parse()
+
+	if *base == "" || *image == "" {
+		log.Fatal("--base and --image are required")
+	}
+
+	// Buildpacks are either directories the Build cloned or images.
+	composer := buildpacks.NewBuilderComposer(remote.Image, remote.Write)
+	if err := composer.Compose(*base, *image, flag.Args()); err != nil {
+		log.Fatalf("Error composing builder: %s", err)
+	}
+
+	log.Printf("Pushed builder %s with %d custom buildpacks", *image, flag.NArg())
+}
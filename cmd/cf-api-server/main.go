@@ -0,0 +1,55 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/kf/pkg/cfapi"
+	kfv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+var (
+	masterURL  = flag.String("master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+	kubeconfig = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+	port       = flag.Int("port", 8080, "The port to serve the CF API on.")
+)
+
+func main() {
+	flag.Parse()
+
+	clusterConfig, err := clientcmd.BuildConfigFromFlags(*masterURL, *kubeconfig)
+	if err != nil {
+		log.Fatalf("Failed to get cluster config: %s", err)
+	}
+
+	// Requests are made with the caller's credentials rather than the
+	// server's so callers can't see or change anything they couldn't with
+	// kubectl.
+	clients := func(token string) (kfv1alpha1.KfV1alpha1Interface, error) {
+		cfg := rest.AnonymousClientConfig(clusterConfig)
+		cfg.BearerToken = token
+		return kfv1alpha1.NewForConfig(cfg)
+	}
+
+	addr := fmt.Sprintf(":%d", *port)
+	log.Printf("Serving the CF API on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, cfapi.NewHandler(clients)))
+}
@@ -15,19 +15,51 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"log"
+
 	"github.com/google/kf/pkg/reconciler/app"
+	"github.com/google/kf/pkg/reconciler/gittrigger"
+	"github.com/google/kf/pkg/reconciler/leaderelection"
 	"github.com/google/kf/pkg/reconciler/route"
 	"github.com/google/kf/pkg/reconciler/source"
 	"github.com/google/kf/pkg/reconciler/space"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
 	"knative.dev/pkg/injection/sharedmain"
+	"knative.dev/pkg/signals"
 )
 
+const component = "controller"
+
 func main() {
-	sharedmain.Main("controller",
-		// Append all controllers here
-		space.NewController,
-		source.NewController,
-		route.NewController,
-		app.NewController,
+	var (
+		masterURL  = flag.String("master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
+		kubeconfig = flag.String("kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
+		election   = leaderelection.NewDefaultConfig()
 	)
+	election.AddFlags(flag.CommandLine)
+	flag.Parse()
+
+	cfg, err := clientcmd.BuildConfigFromFlags(*masterURL, *kubeconfig)
+	if err != nil {
+		log.Fatalf("Error building kubeconfig: %s", err)
+	}
+
+	// Only the elected replica runs the reconcilers, otherwise every replica
+	// would reconcile the same objects.
+	err = election.Run(signals.NewContext(), kubernetes.NewForConfigOrDie(cfg), func(ctx context.Context) {
+		sharedmain.MainWithConfig(ctx, component, cfg,
+			// Append all controllers here
+			space.NewController,
+			source.NewController,
+			route.NewController,
+			app.NewController,
+			gittrigger.NewController,
+		)
+	})
+	if err != nil {
+		log.Fatalf("Error running %s: %s", component, err)
+	}
 }
@@ -18,10 +18,11 @@ import (
 	"os"
 
 	"github.com/google/kf/pkg/kf/commands"
+	"github.com/google/kf/pkg/kf/machine"
 )
 
 func main() {
 	if err := commands.NewKfCommand().Execute(); err != nil {
-		os.Exit(1)
+		os.Exit(machine.ExitCode(err))
 	}
 }
@@ -0,0 +1,36 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/kf/pkg/maintenance"
+)
+
+var (
+	port = flag.Int("port", 8080, "The port to serve maintenance pages on.")
+)
+
+func main() {
+	flag.Parse()
+
+	addr := fmt.Sprintf(":%d", *port)
+	log.Printf("Serving maintenance pages on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, maintenance.NewHandler()))
+}
@@ -24,6 +24,8 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kfv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	"github.com/google/kf/pkg/deletionprotection"
 	"github.com/google/kf/pkg/system"
 	apiconfig "github.com/knative/serving/pkg/apis/config"
 	"github.com/knative/serving/pkg/apis/serving/v1beta1"
@@ -86,6 +88,11 @@ func main() {
 		logger.Fatalw("Failed to get the istio client set", zap.Error(err))
 	}
 
+	kfClient, err := kfv1alpha1.NewForConfig(clusterConfig)
+	if err != nil {
+		logger.Fatalw("Failed to get the Kf client", zap.Error(err))
+	}
+
 	// Watch the logging config map and dynamically update logging levels.
 	configMapWatcher := configmap.NewInformedWatcher(kubeClient, system.Namespace())
 	configMapWatcher.Watch(logging.ConfigMapName(), logging.UpdateLevelFromConfigMap(logger, atomicLevel, component))
@@ -121,6 +128,7 @@ func main() {
 			v1alpha1.SchemeGroupVersion.WithKind("App"):        &v1alpha1.App{},
 			v1alpha1.SchemeGroupVersion.WithKind("Route"):      &v1alpha1.Route{},
 			v1alpha1.SchemeGroupVersion.WithKind("RouteClaim"): &v1alpha1.RouteClaim{},
+			v1alpha1.SchemeGroupVersion.WithKind("GitTrigger"): &v1alpha1.GitTrigger{},
 		},
 		Logger:                logger,
 		DisallowUnknownFields: true,
@@ -131,11 +139,33 @@ func main() {
 			// deployed.
 			ctx = v1alpha1.SetupIstioClient(ctx, istioClient)
 
+			// App webhook checks that the Secrets referenced by environment
+			// variables exist.
+			ctx = v1alpha1.SetupSecretsClient(ctx, kubeClient.CoreV1())
+
 			ctx = routeStore.ToContext(ctx)
 
 			return v1beta1.WithUpgradeViaDefaulting(store.ToContext(ctx))
 		},
 	}
+	// Knative's admission controller only handles creates and updates, deletes
+	// are checked by a separate webhook.
+	go func() {
+		handler := deletionprotection.NewHandler(kfClient, kubeClient.CoreV1())
+		deletionOptions := deletionprotection.Options{
+			WebhookName:    "deletion-protection.webhook.kf.dev",
+			ServiceName:    "deletion-protection-webhook",
+			DeploymentName: "webhook",
+			SecretName:     "deletion-protection-webhook-certs",
+			Namespace:      system.Namespace(),
+			Port:           8444,
+		}
+
+		if err := deletionprotection.Run(stopCh, kubeClient, handler, deletionOptions, logger.Named("deletion-protection")); err != nil {
+			logger.Fatalw("Failed to start the deletion protection webhook", zap.Error(err))
+		}
+	}()
+
 	if err = controller.Run(stopCh); err != nil {
 		logger.Fatalw("Failed to start the admission controller", zap.Error(err))
 	}
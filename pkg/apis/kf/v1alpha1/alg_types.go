@@ -16,266 +16,140 @@ package v1alpha1
 
 import (
 	"path"
+	"sort"
+	"strings"
 
-	"github.com/google/kf/pkg/kf/algorithms"
 	servicecatalogv1beta1 "github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/apis/istio/v1alpha3"
 )
 
-// TODO(poy): This file SHOULD be generated, but it has been written by hand.
+// This file holds the compare functions used with the algorithms package.
 
-// OwnerReferences implements the necessary interfaces for the algorithms
-// package.
-type OwnerReferences []metav1.OwnerReference
-
-// Set implements Interface.
-func (d OwnerReferences) Set(i int, a algorithms.Interface, j int, b algorithms.Interface) {
-	a.(OwnerReferences)[i] = b.(OwnerReferences)[j]
-}
-
-// Append implements Interface.
-func (d OwnerReferences) Append(a algorithms.Interface) algorithms.Interface {
-	return append(d, a.(OwnerReferences)...)
-}
-
-// Clone implements Interface.
-func (d OwnerReferences) Clone() algorithms.Interface {
-	return append(OwnerReferences{}, d...)
+// CompareOwnerReferences orders OwnerReferences by UID.
+func CompareOwnerReferences(a, b metav1.OwnerReference) int {
+	return strings.Compare(string(a.UID), string(b.UID))
 }
 
-// Slice implements Interface.
-func (d OwnerReferences) Slice(i int, j int) algorithms.Interface {
-	return d[i:j]
-}
-
-// Len implements Interface.
-func (d OwnerReferences) Len() int {
-	return len(d)
-}
+// CompareHTTPRoutes orders HTTPRoutes by their URI matchers. HTTPRoutes with
+// the same URI matchers are ordered so those that match on headers come first.
+func CompareHTTPRoutes(a, b v1alpha3.HTTPRoute) int {
+	if c := strings.Compare(httpRouteURIs(a), httpRouteURIs(b)); c != 0 {
+		return c
+	}
 
-// Less implements Interface.
-func (d OwnerReferences) Less(i int, j int) bool {
-	return d[i].UID < d[j].UID
+	return compareHTTPRouteHeaders(a, b)
 }
 
-// Swap implements Interface.
-func (d OwnerReferences) Swap(i int, j int) {
-	d[i], d[j] = d[j], d[i]
-}
+// CompareHTTPRoutePrecedence orders HTTPRoutes in the order Istio should
+// evaluate them. Istio uses the first matching HTTPRoute so longer paths
+// come first, followed by those that match on headers. HTTPRoutes that only
+// reserve a path come after those sending traffic to an App.
+func CompareHTTPRoutePrecedence(a, b v1alpha3.HTTPRoute) int {
+	if c := strings.Compare(HTTPRoutePath(b), HTTPRoutePath(a)); c != 0 {
+		return c
+	}
 
-// HTTPRoutes implements the necessary interfaces for the algorithms
-// package.
-type HTTPRoutes []v1alpha3.HTTPRoute
+	if c := compareHTTPRouteHeaders(a, b); c != 0 {
+		return c
+	}
 
-// Set implements Interface.
-func (h HTTPRoutes) Set(i int, a algorithms.Interface, j int, b algorithms.Interface) {
-	a.(HTTPRoutes)[i] = b.(HTTPRoutes)[j]
+	switch {
+	case (a.Fault == nil) == (b.Fault == nil):
+		return 0
+	case a.Fault != nil:
+		return 1
+	default:
+		return -1
+	}
 }
 
-// Append implements Interface.
-func (h HTTPRoutes) Append(a algorithms.Interface) algorithms.Interface {
-	return append(h, a.(HTTPRoutes)...)
-}
+// HTTPRoutePath returns the URL path an HTTPRoute matches without a trailing
+// slash, regardless of whether it matches the path with a regular
+// expression, an exact match, or a prefix. The root path is blank.
+func HTTPRoutePath(h v1alpha3.HTTPRoute) string {
+	for _, s := range h.Match {
+		if s.URI == nil {
+			continue
+		}
 
-// Clone implements Interface.
-func (h HTTPRoutes) Clone() algorithms.Interface {
-	return append(HTTPRoutes{}, h...)
-}
+		switch {
+		case s.URI.Regex != "":
+			// Regular expressions are built by BuildPathRegexp.
+			p := strings.TrimPrefix(s.URI.Regex, "^")
+			p = strings.TrimSuffix(p, "(/.*)?")
+			return strings.Replace(p, `\`, "", -1)
+		case s.URI.Prefix != "":
+			return strings.TrimSuffix(s.URI.Prefix, "/")
+		case s.URI.Exact != "":
+			return strings.TrimSuffix(s.URI.Exact, "/")
+		}
+	}
 
-// Slice implements Interface.
-func (h HTTPRoutes) Slice(i int, j int) algorithms.Interface {
-	return h[i:j]
+	return ""
 }
 
-// Len implements Interface.
-func (h HTTPRoutes) Len() int {
-	return len(h)
+func httpRouteURIs(h v1alpha3.HTTPRoute) string {
+	var m string
+	for _, s := range h.Match {
+		if s.URI == nil {
+			continue
+		}
+		m += s.URI.Exact + s.URI.Prefix + s.URI.Suffix + s.URI.Regex
+	}
+	return m
 }
 
-// Less implements Interface.
-func (h HTTPRoutes) Less(i int, j int) bool {
-	f := func(h v1alpha3.HTTPRoute) string {
-		var m string
+// compareHTTPRouteHeaders orders HTTPRoutes by their header matchers, those
+// without any come last.
+func compareHTTPRouteHeaders(a, b v1alpha3.HTTPRoute) int {
+	headers := func(h v1alpha3.HTTPRoute) string {
+		var m []string
 		for _, s := range h.Match {
-			if s.URI == nil {
-				continue
+			for name, match := range s.Headers {
+				m = append(m, name+"="+match.Exact+match.Prefix+match.Suffix+match.Regex)
 			}
-			m += s.URI.Exact + s.URI.Prefix + s.URI.Suffix + s.URI.Regex
 		}
-		return m
+		sort.Strings(m)
+		return strings.Join(m, ",")
 	}
 
-	return f(h[i]) < f(h[j])
-}
-
-// Swap implements Interface.
-func (h HTTPRoutes) Swap(i int, j int) {
-	h[i], h[j] = h[j], h[i]
-}
-
-// SpaceDomains implements the necessary interfaces for the algorithms
-// package.
-type SpaceDomains []SpaceDomain
-
-// Set implements Interface.
-func (d SpaceDomains) Set(i int, a algorithms.Interface, j int, b algorithms.Interface) {
-	a.(SpaceDomains)[i] = b.(SpaceDomains)[j]
-}
-
-// Append implements Interface.
-func (d SpaceDomains) Append(a algorithms.Interface) algorithms.Interface {
-	return append(d, a.(SpaceDomains)...)
-}
-
-// Clone implements Interface.
-func (d SpaceDomains) Clone() algorithms.Interface {
-	return append(SpaceDomains{}, d...)
-}
-
-// Slice implements Interface.
-func (d SpaceDomains) Slice(i int, j int) algorithms.Interface {
-	return d[i:j]
-}
-
-// Len implements Interface.
-func (d SpaceDomains) Len() int {
-	return len(d)
-}
-
-// Less implements Interface.
-func (d SpaceDomains) Less(i int, j int) bool {
-	// We don't want to lose default information.
-	if d[i].Domain == d[j].Domain {
-		d[i].Default = d[i].Default || d[j].Default
-		d[j].Default = d[i].Default || d[j].Default
+	ha, hb := headers(a), headers(b)
+	switch {
+	case ha == hb:
+		return 0
+	case ha == "":
+		return 1
+	case hb == "":
+		return -1
+	default:
+		return strings.Compare(ha, hb)
 	}
-
-	return d[i].Domain < d[j].Domain
 }
 
-// Swap implements Interface.
-func (d SpaceDomains) Swap(i int, j int) {
-	d[i], d[j] = d[j], d[i]
+// CompareSpaceDomains orders SpaceDomains by domain.
+func CompareSpaceDomains(a, b SpaceDomain) int {
+	return strings.Compare(a.Domain, b.Domain)
 }
 
-// ServiceBindings implements the necessary interfaces for the algorithms package.
-type ServiceBindings []servicecatalogv1beta1.ServiceBinding
-
-// Set implements Interface.
-func (d ServiceBindings) Set(i int, a algorithms.Interface, j int, b algorithms.Interface) {
-	a.(ServiceBindings)[i] = b.(ServiceBindings)[j]
+// CompareServiceBindings orders ServiceBindings by name.
+func CompareServiceBindings(a, b servicecatalogv1beta1.ServiceBinding) int {
+	return strings.Compare(a.Name, b.Name)
 }
 
-// Append implements Interface.
-func (d ServiceBindings) Append(a algorithms.Interface) algorithms.Interface {
-	return append(d, a.(ServiceBindings)...)
+// CompareRoutes orders Routes by name.
+func CompareRoutes(a, b Route) int {
+	return strings.Compare(a.Name, b.Name)
 }
 
-// Clone implements Interface.
-func (s ServiceBindings) Clone() algorithms.Interface {
-	return append(ServiceBindings{}, s...)
-}
-
-// Slice implements Interface.
-func (s ServiceBindings) Slice(i int, j int) algorithms.Interface {
-	return s[i:j]
-}
-
-// Len implements Interface.
-func (s ServiceBindings) Len() int {
-	return len(s)
-}
-
-// Less implements Interface.
-func (s ServiceBindings) Less(i int, j int) bool {
-	return s[i].Name < s[j].Name
-}
-
-// Swap implements Interface.
-func (s ServiceBindings) Swap(i int, j int) {
-	s[i], s[j] = s[j], s[i]
-}
-
-// Routes implements the necessary interfaces for the algorithms package.
-type Routes []Route
-
-// Set implements Interface.
-func (d Routes) Set(i int, a algorithms.Interface, j int, b algorithms.Interface) {
-	a.(Routes)[i] = b.(Routes)[j]
-}
-
-// Append implements Interface.
-func (d Routes) Append(a algorithms.Interface) algorithms.Interface {
-	return append(d, a.(Routes)...)
-}
-
-// Clone implements Interface.
-func (d Routes) Clone() algorithms.Interface {
-	return append(Routes{}, d...)
-}
-
-// Slice implements Interface.
-func (d Routes) Slice(i int, j int) algorithms.Interface {
-	return d[i:j]
-}
-
-// Len implements Interface.
-func (d Routes) Len() int {
-	return len(d)
-}
-
-// Less implements Interface.
-func (d Routes) Less(i int, j int) bool {
-	return d[i].Name < d[j].Name
-}
-
-// Swap implements Interface.
-func (d Routes) Swap(i int, j int) {
-	d[i], d[j] = d[j], d[i]
-}
-
-// RouteSpecFieldsSlice implements the necessary interfaces for the algorithms
-// package.
-type RouteSpecFieldsSlice []RouteSpecFields
-
-// Set implements Interface.
-func (d RouteSpecFieldsSlice) Set(i int, a algorithms.Interface, j int, b algorithms.Interface) {
-	a.(RouteSpecFieldsSlice)[i] = b.(RouteSpecFieldsSlice)[j]
-}
-
-// Append implements Interface.
-func (d RouteSpecFieldsSlice) Append(a algorithms.Interface) algorithms.Interface {
-	return append(d, a.(RouteSpecFieldsSlice)...)
-}
-
-// Clone implements Interface.
-func (d RouteSpecFieldsSlice) Clone() algorithms.Interface {
-	return append(RouteSpecFieldsSlice{}, d...)
-}
-
-// Slice implements Interface.
-func (d RouteSpecFieldsSlice) Slice(i int, j int) algorithms.Interface {
-	return d[i:j]
-}
-
-// Len implements Interface.
-func (d RouteSpecFieldsSlice) Len() int {
-	return len(d)
-}
-
-// Less implements Interface.
-func (d RouteSpecFieldsSlice) Less(i int, j int) bool {
+// CompareRouteSpecFields orders RouteSpecFields by the name of the Route they
+// generate.
+func CompareRouteSpecFields(a, b RouteSpecFields) int {
 	// TODO(https://github.com/knative/pkg/issues/542):
 	// We can't garuntee that the path will have the '/' or not
 	// because webhooks can't yet modify slices.
-	d[i].Path = path.Join("/", d[i].Path)
-	d[j].Path = path.Join("/", d[j].Path)
-
-	return GenerateRouteNameFromSpec(d[i], "") < GenerateRouteNameFromSpec(d[j], "")
-}
+	a.Path = path.Join("/", a.Path)
+	b.Path = path.Join("/", b.Path)
 
-// Swap implements Interface.
-func (d RouteSpecFieldsSlice) Swap(i int, j int) {
-	d[i], d[j] = d[j], d[i]
+	return strings.Compare(GenerateRouteNameFromSpec(a, ""), GenerateRouteNameFromSpec(b, ""))
 }
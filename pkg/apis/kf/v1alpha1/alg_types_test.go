@@ -0,0 +1,109 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	istio "knative.dev/pkg/apis/istio/common/v1alpha1"
+	"knative.dev/pkg/apis/istio/v1alpha3"
+)
+
+func TestHTTPRoutePath(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		match istio.StringMatch
+		want  string
+	}{
+		"root regex":  {match: istio.StringMatch{Regex: "^(/.*)?"}, want: ""},
+		"path regex":  {match: istio.StringMatch{Regex: `^/some\.path(/.*)?`}, want: "/some.path"},
+		"root prefix": {match: istio.StringMatch{Prefix: "/"}, want: ""},
+		"path prefix": {match: istio.StringMatch{Prefix: "/some-path/"}, want: "/some-path"},
+		"exact":       {match: istio.StringMatch{Exact: "/some-path"}, want: "/some-path"},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			match := tc.match
+			got := HTTPRoutePath(v1alpha3.HTTPRoute{
+				Match: []v1alpha3.HTTPMatchRequest{{URI: &match}},
+			})
+
+			testutil.AssertEqual(t, "path", tc.want, got)
+		})
+	}
+}
+
+func TestCompareHTTPRoutePrecedence(t *testing.T) {
+	t.Parallel()
+
+	route := func(match istio.StringMatch, headers map[string]string, fault bool) v1alpha3.HTTPRoute {
+		r := v1alpha3.HTTPRoute{
+			Match: []v1alpha3.HTTPMatchRequest{{URI: &match}},
+		}
+
+		for name, value := range headers {
+			if r.Match[0].Headers == nil {
+				r.Match[0].Headers = map[string]istio.StringMatch{}
+			}
+			r.Match[0].Headers[name] = istio.StringMatch{Exact: value}
+		}
+
+		if fault {
+			r.Fault = &v1alpha3.HTTPFaultInjection{}
+		}
+
+		return r
+	}
+
+	cases := map[string]struct {
+		a, b v1alpha3.HTTPRoute
+		want int
+	}{
+		"longer paths first": {
+			a:    route(istio.StringMatch{Regex: "^/some-path(/.*)?"}, nil, false),
+			b:    route(istio.StringMatch{Regex: "^(/.*)?"}, nil, false),
+			want: -1,
+		},
+		"longer prefix before shorter regex": {
+			a:    route(istio.StringMatch{Prefix: "/some-path/"}, nil, false),
+			b:    route(istio.StringMatch{Regex: "^(/.*)?"}, nil, false),
+			want: -1,
+		},
+		"headers first": {
+			a:    route(istio.StringMatch{Regex: "^/some-path(/.*)?"}, nil, false),
+			b:    route(istio.StringMatch{Regex: "^/some-path(/.*)?"}, map[string]string{"x-beta": "true"}, false),
+			want: 1,
+		},
+		"reserved paths last": {
+			a:    route(istio.StringMatch{Regex: "^/some-path(/.*)?"}, nil, true),
+			b:    route(istio.StringMatch{Prefix: "/some-path/"}, nil, false),
+			want: 1,
+		},
+		"equal": {
+			a:    route(istio.StringMatch{Exact: "/some-path"}, nil, false),
+			b:    route(istio.StringMatch{Prefix: "/some-path/"}, nil, false),
+			want: 0,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "compare", tc.want, CompareHTTPRoutePrecedence(tc.a, tc.b))
+		})
+	}
+}
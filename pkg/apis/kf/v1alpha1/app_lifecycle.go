@@ -56,6 +56,7 @@ func (status *AppStatus) manage() apis.ConditionManager {
 		AppConditionKnativeServiceReady,
 		AppConditionSpaceReady,
 		AppConditionEnvVarSecretReady,
+		AppConditionServiceBindingsReady,
 	).Manage(status)
 }
 
@@ -131,6 +132,20 @@ func (status *AppStatus) PropagateKnativeServiceStatus(service *serving.Service)
 	}
 }
 
+// PropagateRevisionStatus records whether the App's latest ready revision has
+// been scaled to zero.
+func (status *AppStatus) PropagateRevisionStatus(revision *serving.Revision) {
+	status.ScaledToZero = false
+
+	if revision == nil {
+		return
+	}
+
+	if cond := revision.Status.GetCondition(serving.RevisionConditionActive); cond != nil {
+		status.ScaledToZero = cond.IsFalse()
+	}
+}
+
 // PropagateEnvVarSecretStatus updates the env var secret readiness status.
 func (status *AppStatus) PropagateEnvVarSecretStatus(secret *v1.Secret) {
 	status.manage().MarkTrue(AppConditionEnvVarSecretReady)
@@ -210,17 +225,36 @@ func (status *AppStatus) PropagateServiceBindingsStatus(bindings []servicecatalo
 	status.ServiceBindingConditions = duckStatus.Conditions
 }
 
+// MarkCredentialsRotating notes that a service binding is being recreated to
+// get new credentials from its broker.
+func (status *AppStatus) MarkCredentialsRotating(bindingName string) {
+	status.manage().MarkUnknown(AppConditionServiceBindingsReady, "RotatingCredentials",
+		"Waiting for binding %s to be recreated with new credentials", bindingName)
+}
+
 // MarkSpaceHealthy notes that the space was able to be retrieved and
 // defaults can be applied from it.
 func (status *AppStatus) MarkSpaceHealthy() {
 	status.manage().MarkTrue(AppConditionSpaceReady)
 }
 
+// MarkRoutesReady notes that the app's Routes, RouteClaims and
+// DestinationRule were all reconciled.
+func (status *AppStatus) MarkRoutesReady() {
+	status.manage().MarkTrue(AppConditionRouteReady)
+}
+
 // MarkSpaceUnhealthy notes that the space was could not be retrieved.
 func (status *AppStatus) MarkSpaceUnhealthy(reason, message string) {
 	status.manage().MarkFalse(AppConditionSpaceReady, reason, message)
 }
 
+// MarkDeleting notes that the App is being deleted and is waiting for the
+// cleanup described by the message.
+func (status *AppStatus) MarkDeleting(message string) {
+	status.manage().MarkFalse(AppConditionReady, "Deleting", "%s", message)
+}
+
 func (status *AppStatus) duck() *duckv1beta1.Status {
 	return &status.Status
 }
@@ -16,6 +16,7 @@ package v1alpha1
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/kf/pkg/kf/testutil"
@@ -136,6 +137,7 @@ func initTestAppStatus(t *testing.T) *AppStatus {
 	apitesting.CheckConditionOngoing(status.duck(), AppConditionSpaceReady, t)
 	apitesting.CheckConditionOngoing(status.duck(), AppConditionSourceReady, t)
 	apitesting.CheckConditionOngoing(status.duck(), AppConditionEnvVarSecretReady, t)
+	apitesting.CheckConditionOngoing(status.duck(), AppConditionServiceBindingsReady, t)
 	apitesting.CheckConditionOngoing(status.duck(), AppConditionKnativeServiceReady, t)
 
 	return status
@@ -295,6 +297,11 @@ func TestAppHappyPath(t *testing.T) {
 
 	apitesting.CheckConditionSucceeded(status.duck(), AppConditionEnvVarSecretReady, t)
 
+	// App has no service bindings
+	status.PropagateServiceBindingsStatus(nil)
+
+	apitesting.CheckConditionSucceeded(status.duck(), AppConditionServiceBindingsReady, t)
+
 	// Knative Serving starts out pending
 	status.PropagateKnativeServiceStatus(pendingKnativeService())
 
@@ -328,15 +335,51 @@ func TestAppStatus_lifecycle(t *testing.T) {
 				status.MarkSpaceHealthy()
 				status.PropagateSourceStatus(happySource())
 				status.PropagateEnvVarSecretStatus(envVarSecret())
+				status.PropagateServiceBindingsStatus(nil)
 				status.PropagateKnativeServiceStatus(happyKnativeService())
 			},
 			ExpectSucceeded: []apis.ConditionType{
 				AppConditionReady,
+				AppConditionSpaceReady,
+				AppConditionSourceReady,
+				AppConditionEnvVarSecretReady,
+				AppConditionServiceBindingsReady,
+				AppConditionKnativeServiceReady,
+			},
+		},
+		"service binding failed": {
+			Init: func(status *AppStatus) {
+				status.MarkSpaceHealthy()
+				status.PropagateSourceStatus(happySource())
+				status.PropagateEnvVarSecretStatus(envVarSecret())
+				status.PropagateServiceBindingsStatus([]servicecatalogv1beta1.ServiceBinding{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "kf-binding-my-app-db",
+							Labels: map[string]string{ComponentLabel: "db"},
+						},
+						Status: servicecatalogv1beta1.ServiceBindingStatus{
+							Conditions: []servicecatalogv1beta1.ServiceBindingCondition{{
+								Type:    servicecatalogv1beta1.ServiceBindingConditionReady,
+								Status:  servicecatalogv1beta1.ConditionFalse,
+								Reason:  "BindCallFailed",
+								Message: "broker returned 500",
+							}},
+						},
+					},
+				})
+				status.PropagateKnativeServiceStatus(happyKnativeService())
+			},
+			ExpectSucceeded: []apis.ConditionType{
 				AppConditionSpaceReady,
 				AppConditionSourceReady,
 				AppConditionEnvVarSecretReady,
 				AppConditionKnativeServiceReady,
 			},
+			ExpectFailed: []apis.ConditionType{
+				AppConditionReady,
+				AppConditionServiceBindingsReady,
+			},
 		},
 		"stopped app": {
 			Init: func(status *AppStatus) {
@@ -375,6 +418,33 @@ func TestAppStatus_lifecycle(t *testing.T) {
 				AppConditionReady,
 			},
 		},
+		"rotating credentials": {
+			Init: func(status *AppStatus) {
+				status.MarkSpaceHealthy()
+				status.PropagateSourceStatus(happySource())
+				status.PropagateServiceBindingsStatus(nil)
+				status.MarkCredentialsRotating("kf-binding-my-app-db")
+			},
+			ExpectSucceeded: []apis.ConditionType{
+				AppConditionSpaceReady,
+				AppConditionSourceReady,
+			},
+			ExpectOngoing: []apis.ConditionType{
+				AppConditionReady,
+				AppConditionServiceBindingsReady,
+			},
+		},
+		"routes ready": {
+			Init: func(status *AppStatus) {
+				status.MarkRoutesReady()
+			},
+			ExpectSucceeded: []apis.ConditionType{
+				AppConditionRouteReady,
+			},
+			ExpectOngoing: []apis.ConditionType{
+				AppConditionReady,
+			},
+		},
 		"space unhealthy": {
 			Init: func(status *AppStatus) {
 				status.MarkSpaceUnhealthy("Terminating", "Namespace is terminating")
@@ -445,3 +515,80 @@ func TestServiceBindingConditionType(t *testing.T) {
 		})
 	}
 }
+
+func TestAppStatus_PropagateRevisionStatus(t *testing.T) {
+	cases := map[string]struct {
+		revision func() *serving.Revision
+		expected bool
+	}{
+		"no revision": {
+			revision: func() *serving.Revision { return nil },
+			expected: false,
+		},
+		"new revision": {
+			revision: func() *serving.Revision { return &serving.Revision{} },
+			expected: false,
+		},
+		"active": {
+			revision: func() *serving.Revision {
+				revision := &serving.Revision{}
+				revision.Status.MarkActive()
+				return revision
+			},
+			expected: false,
+		},
+		"scaled to zero": {
+			revision: func() *serving.Revision {
+				revision := &serving.Revision{}
+				revision.Status.MarkInactive("NoTraffic", "The target is not receiving traffic.")
+				return revision
+			},
+			expected: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			status := &AppStatus{ScaledToZero: true}
+			status.PropagateRevisionStatus(tc.revision())
+
+			testutil.AssertEqual(t, "ScaledToZero", tc.expected, status.ScaledToZero)
+		})
+	}
+}
+
+func TestAppStatus_MarkDeleting(t *testing.T) {
+	status := &AppStatus{}
+	status.InitializeConditions()
+
+	status.MarkDeleting("Waiting for 1 route(s) to be deleted")
+
+	apitesting.CheckConditionFailed(status.duck(), AppConditionReady, t)
+	testutil.AssertEqual(t, "reason", "Deleting", status.GetCondition(AppConditionReady).Reason)
+}
+
+func TestAppStatus_PropagateSourceStatus_rebased(t *testing.T) {
+	builtDigest := "sha256:" + strings.Repeat("ab", 32)
+	rebasedDigest := "sha256:" + strings.Repeat("cd", 32)
+
+	build := happyBuild()
+	build.Status.StepStates = []corev1.ContainerState{
+		{Terminated: &corev1.ContainerStateTerminated{Message: builtDigest}},
+	}
+
+	source := &Source{}
+	source.Name = "some-source"
+	source.Status.InitializeConditions()
+	source.Status.PropagateBuildStatus(build)
+
+	status := &AppStatus{}
+	status.InitializeConditions()
+	status.PropagateSourceStatus(source)
+	testutil.AssertEqual(t, "built image", "some-container-image@"+builtDigest, status.ImageReference())
+
+	// kf rebase-apps pushes over the tag and records the new digest on the
+	// Source; the next reconcile of the App must deploy it.
+	source.Status.ImageDigest = rebasedDigest
+	status.PropagateSourceStatus(source)
+	testutil.AssertEqual(t, "rebased image", "some-container-image@"+rebasedDigest, status.ImageReference())
+}
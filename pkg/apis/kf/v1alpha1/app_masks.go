@@ -35,6 +35,7 @@ func AppSpecSourceMask(in SourceSpec) SourceSpec {
 	out.ContainerImage.Image = in.ContainerImage.Image
 	out.Dockerfile.Source = in.Dockerfile.Source
 	out.Dockerfile.Path = in.Dockerfile.Path
+	out.Git = in.Git
 
 	// Disallowed fields
 	// This list is unnecessary, but added here for clarity
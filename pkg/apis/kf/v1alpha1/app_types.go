@@ -17,7 +17,10 @@ package v1alpha1
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/google/kf/pkg/internal/cron"
 	"github.com/knative/serving/pkg/apis/autoscaling"
 	serving "github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,6 +37,23 @@ const (
 	// ComponentLabel holds the standard label key for Kubernetes app component
 	// identifiers.
 	ComponentLabel = "app.kubernetes.io/component"
+
+	// BindingFormatVCAP projects service binding credentials into the
+	// VCAP_SERVICES environment variable. It's the default.
+	BindingFormatVCAP = "vcap"
+
+	// BindingFormatK8s projects service binding credentials as files under
+	// /bindings following the Kubernetes Service Binding specification.
+	BindingFormatK8s = "k8s"
+
+	// InstanceIndexAnnotation is set on each of an App's Pods to the index of
+	// the instance it runs. An index is kept for the life of the Pod and is
+	// reused by later Pods once it's free.
+	InstanceIndexAnnotation = "kf.dev/instance-index"
+
+	// PromotionAnnotation holds a JSON record of the last time an App's image
+	// was promoted from or to another space.
+	PromotionAnnotation = "kf.dev/last-promotion"
 )
 
 // +genclient
@@ -90,6 +110,107 @@ type AppSpec struct {
 	// +optional
 	// +patchStrategy=merge
 	ServiceBindings []AppSpecServiceBinding `json:"serviceBindings,omitempty"`
+
+	// BindingFormat is how service binding credentials are provided to the
+	// App, either BindingFormatVCAP or BindingFormatK8s. Defaults to
+	// BindingFormatVCAP.
+	// +optional
+	BindingFormat string `json:"bindingFormat,omitempty"`
+
+	// RestartOnChange lists Secrets and ConfigMaps in the App's namespace
+	// that roll the App's instances when their data changes, so rotated
+	// credentials and configuration take effect.
+	// +optional
+	RestartOnChange []AppSpecRestartOnChange `json:"restartOnChange,omitempty"`
+
+	// EgressIPPool is the cluster egress IP pool the App's outbound traffic
+	// uses. Pods are annotated with the pool so the cluster's egress solution
+	// gives them the pool's static source IPs.
+	// +optional
+	EgressIPPool string `json:"egressIPPool,omitempty"`
+
+	// Processes are the process types the App defines, e.g. in a Procfile.
+	// The web process is served by the App's template, other processes run
+	// in their own Deployment with the App's image.
+	// +optional
+	Processes []AppSpecProcess `json:"processes,omitempty"`
+
+	// Rollout splits the App's traffic between an earlier revision and the
+	// latest ready one while a new version is rolled out. All traffic goes to
+	// the latest ready revision if it's not set.
+	// +optional
+	Rollout *AppSpecRollout `json:"rollout,omitempty"`
+}
+
+// AppSpecRollout pins part of an App's traffic to a stable revision.
+type AppSpecRollout struct {
+	// StableRevisionName is the revision that receives the traffic the
+	// latest revision doesn't.
+	StableRevisionName string `json:"stableRevisionName"`
+
+	// LatestPercent is the percent of traffic, between 0 and 100, sent to
+	// the latest ready revision.
+	// +optional
+	LatestPercent int `json:"latestPercent,omitempty"`
+}
+
+// ProcessTypeWeb is the process type that serves the App's routes.
+const ProcessTypeWeb = "web"
+
+// AppSpecProcess defines a process type of an App.
+type AppSpecProcess struct {
+	// Type is the name of the process, e.g. web or worker.
+	Type string `json:"type"`
+
+	// Command starts the process, it's passed to the image's entrypoint.
+	Command string `json:"command"`
+
+	// Instances is the number of instances of the process to run. It's
+	// ignored for the web process which is scaled with the App's Instances.
+	// Defaults to 0.
+	// +optional
+	Instances *int `json:"instances,omitempty"`
+}
+
+// Process returns the App's process with the given type or nil if it doesn't
+// have one.
+func (spec *AppSpec) Process(processType string) *AppSpecProcess {
+	for i := range spec.Processes {
+		if spec.Processes[i].Type == processType {
+			return &spec.Processes[i]
+		}
+	}
+
+	return nil
+}
+
+const (
+	// RestartOnChangeKindSecret references a Secret.
+	RestartOnChangeKindSecret = "Secret"
+
+	// RestartOnChangeKindConfigMap references a ConfigMap.
+	RestartOnChangeKindConfigMap = "ConfigMap"
+)
+
+// AppSpecRestartOnChange references an object the App restarts on changes
+// to.
+type AppSpecRestartOnChange struct {
+	// Kind is RestartOnChangeKindSecret or RestartOnChangeKindConfigMap.
+	Kind string `json:"kind"`
+
+	// Name is the name of the object in the App's namespace.
+	Name string `json:"name"`
+}
+
+// String formats the reference the way kubectl does, e.g. secret/NAME.
+func (ref AppSpecRestartOnChange) String() string {
+	return strings.ToLower(ref.Kind) + "/" + ref.Name
+}
+
+// UsesK8sBindingFormat returns true if service binding credentials should be
+// mounted as files rather than set in VCAP_SERVICES.
+func (spec *AppSpec) UsesK8sBindingFormat() bool {
+	return spec.BindingFormat == BindingFormatK8s
 }
 
 // AppSpecTemplate defines an app's runtime configuration.
@@ -122,6 +243,73 @@ type AppSpecInstances struct {
 
 	// Max defines a maximum auto-scaling limit.
 	Max *int `json:"max,omitempty"`
+
+	// Schedule starts and stops the App at set times, for example to scale
+	// development Apps to zero outside of working hours.
+	// +optional
+	Schedule *AppSpecInstancesSchedule `json:"schedule,omitempty"`
+
+	// ColdStartTimeoutSeconds is the maximum time a request may take,
+	// including waiting for an App that was scaled to zero to start.
+	// Defaults to 300 seconds.
+	// +optional
+	ColdStartTimeoutSeconds *int64 `json:"coldStartTimeoutSeconds,omitempty"`
+}
+
+// AppSpecInstancesSchedule defines when an App should be running.
+type AppSpecInstancesSchedule struct {
+
+	// Start is a cron expression for when the App should be started.
+	Start string `json:"start"`
+
+	// Stop is a cron expression for when the App should be stopped.
+	Stop string `json:"stop"`
+
+	// TimeZone is the IANA time zone Start and Stop are evaluated in.
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// StoppedAt returns true if the schedule has the App stopped at the given
+// time along with the next time the App's state will change. The returned
+// time is zero if the state never changes.
+func (schedule *AppSpecInstancesSchedule) StoppedAt(now time.Time) (bool, time.Time, error) {
+	location := time.UTC
+	if schedule.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(schedule.TimeZone); err != nil {
+			return false, time.Time{}, err
+		}
+	}
+
+	start, err := cron.Parse(schedule.Start)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	stop, err := cron.Parse(schedule.Stop)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+
+	now = now.In(location)
+	nextStart := start.Next(now)
+	nextStop := stop.Next(now)
+
+	// The App is running if the next thing that happens is it being stopped.
+	switch {
+	case nextStart.IsZero() && nextStop.IsZero():
+		return false, time.Time{}, nil
+	case nextStop.IsZero():
+		return true, nextStart, nil
+	case nextStart.IsZero():
+		return false, nextStop, nil
+	case nextStop.Before(nextStart):
+		return false, nextStop, nil
+	default:
+		return true, nextStart, nil
+	}
 }
 
 // AppSpecServiceBinding is a binding to an external service.
@@ -138,6 +326,76 @@ type AppSpecServiceBinding struct {
 	// If unspecified it will default to the service name
 	// +optional
 	BindingName string `json:"bindingName,omitempty"`
+
+	// Rotation configures automatically getting new credentials for the
+	// binding from the broker.
+	// +optional
+	Rotation *AppSpecServiceBindingRotation `json:"rotation,omitempty"`
+
+	// BackupHooks are commands run in the App's instances so the service's
+	// data can be captured consistently by backup tools such as Velero.
+	// +optional
+	BackupHooks *AppSpecServiceBindingBackupHooks `json:"backupHooks,omitempty"`
+}
+
+// AppSpecServiceBindingBackupHooks are shell commands run in the App's
+// container around backups and restores of the space.
+type AppSpecServiceBindingBackupHooks struct {
+
+	// PreBackup is run before the space is backed up, for example to flush
+	// or lock the service.
+	// +optional
+	PreBackup string `json:"preBackup,omitempty"`
+
+	// PostRestore is run after the space is restored, for example to
+	// reload data into the service.
+	// +optional
+	PostRestore string `json:"postRestore,omitempty"`
+}
+
+// AppSpecServiceBindingRotation defines when a binding's credentials are
+// rotated. Credentials are rotated by recreating the binding, after which
+// the App is restarted to pick them up.
+type AppSpecServiceBindingRotation struct {
+
+	// Period is how long credentials are used before they're rotated.
+	Period metav1.Duration `json:"period"`
+
+	// Window restricts rotations to a recurring maintenance window.
+	// Credentials may be rotated at any time if it's unset.
+	// +optional
+	Window *MaintenanceWindow `json:"window,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time disruptive operations are
+// allowed in.
+type MaintenanceWindow struct {
+
+	// Start is a cron expression for when the window opens.
+	Start string `json:"start"`
+
+	// Stop is a cron expression for when the window closes.
+	Stop string `json:"stop"`
+
+	// TimeZone is the IANA time zone Start and Stop are evaluated in.
+	// Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// OpenAt returns true if the window is open at the given time along with the
+// next time it opens or closes. The returned time is zero if the window never
+// changes.
+func (window *MaintenanceWindow) OpenAt(now time.Time) (bool, time.Time, error) {
+	// A window is open when a schedule with the same bounds would be running.
+	schedule := AppSpecInstancesSchedule{
+		Start:    window.Start,
+		Stop:     window.Stop,
+		TimeZone: window.TimeZone,
+	}
+
+	stopped, next, err := schedule.StoppedAt(now)
+	return !stopped, next, err
 }
 
 // MinAnnotationValue returns the value autoscaling.knative.dev/minScale should
@@ -214,6 +472,25 @@ type AppStatus struct {
 
 	// ServiceBindingConditions are the conditions of the service bindings.
 	ServiceBindingConditions duckv1beta1.Conditions `json:"serviceBindingConditions"`
+
+	// ScaledToZero is true if the latest ready revision of the App has no
+	// running instances and will be started by the next request.
+	ScaledToZero bool `json:"scaledToZero,omitempty"`
+
+	// CredentialsRotatedAt is the last time the credentials of one of the
+	// App's service bindings were automatically rotated.
+	// +optional
+	CredentialsRotatedAt *metav1.Time `json:"credentialsRotatedAt,omitempty"`
+
+	// RestartOnChangeChecksum is a checksum of the data of the objects in
+	// RestartOnChange, the App is restarted when it changes.
+	// +optional
+	RestartOnChangeChecksum string `json:"restartOnChangeChecksum,omitempty"`
+
+	// EgressIPs are the source IPs of the App's egress IP pool, if the
+	// cluster has them configured.
+	// +optional
+	EgressIPs []string `json:"egressIPs,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -15,8 +15,10 @@
 package v1alpha1
 
 import (
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/google/kf/pkg/kf/testutil"
 	"github.com/knative/serving/pkg/apis/autoscaling"
@@ -26,6 +28,10 @@ func intPtr(val int) *int {
 	return &val
 }
 
+func int64Ptr(val int64) *int64 {
+	return &val
+}
+
 func TestAppSpecInstances_MinAnnotationValue(t *testing.T) {
 	cases := map[string]struct {
 		instances AppSpecInstances
@@ -152,6 +158,101 @@ func TestAppSpecInstances_ScalingAnnotations(t *testing.T) {
 	}
 }
 
+func TestAppSpecInstancesSchedule_StoppedAt(t *testing.T) {
+	officeHours := AppSpecInstancesSchedule{
+		Start: "0 8 * * 1-5",
+		Stop:  "0 20 * * 1-5",
+	}
+
+	cases := map[string]struct {
+		schedule    AppSpecInstancesSchedule
+		now         time.Time
+		wantStopped bool
+		wantNext    time.Time
+		wantErr     error
+	}{
+		"during working hours": {
+			schedule:    officeHours,
+			now:         time.Date(2019, time.August, 1, 12, 0, 0, 0, time.UTC),
+			wantStopped: false,
+			wantNext:    time.Date(2019, time.August, 1, 20, 0, 0, 0, time.UTC),
+		},
+		"overnight": {
+			schedule:    officeHours,
+			now:         time.Date(2019, time.August, 1, 22, 0, 0, 0, time.UTC),
+			wantStopped: true,
+			wantNext:    time.Date(2019, time.August, 2, 8, 0, 0, 0, time.UTC),
+		},
+		"weekend": {
+			schedule:    officeHours,
+			now:         time.Date(2019, time.August, 3, 12, 0, 0, 0, time.UTC),
+			wantStopped: true,
+			wantNext:    time.Date(2019, time.August, 5, 8, 0, 0, 0, time.UTC),
+		},
+		"time zone": {
+			schedule: AppSpecInstancesSchedule{
+				Start:    officeHours.Start,
+				Stop:     officeHours.Stop,
+				TimeZone: "America/New_York",
+			},
+			// 06:00 in New York
+			now:         time.Date(2019, time.August, 1, 10, 0, 0, 0, time.UTC),
+			wantStopped: true,
+			wantNext:    time.Date(2019, time.August, 1, 12, 0, 0, 0, time.UTC),
+		},
+		"bad expression": {
+			schedule: AppSpecInstancesSchedule{Start: "bad", Stop: officeHours.Stop},
+			wantErr:  errors.New(`expected 5 fields in cron expression "bad", got 1`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			stopped, next, err := tc.schedule.StoppedAt(tc.now)
+			if tc.wantErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "stopped", tc.wantStopped, stopped)
+			testutil.AssertEqual(t, "next", tc.wantNext.Unix(), next.Unix())
+		})
+	}
+}
+
+func TestMaintenanceWindow_OpenAt(t *testing.T) {
+	saturdayNight := MaintenanceWindow{
+		Start: "0 2 * * 6",
+		Stop:  "0 4 * * 6",
+	}
+
+	cases := map[string]struct {
+		now      time.Time
+		wantOpen bool
+		wantNext time.Time
+	}{
+		"in window": {
+			now:      time.Date(2019, time.August, 3, 3, 0, 0, 0, time.UTC),
+			wantOpen: true,
+			wantNext: time.Date(2019, time.August, 3, 4, 0, 0, 0, time.UTC),
+		},
+		"outside window": {
+			now:      time.Date(2019, time.August, 1, 12, 0, 0, 0, time.UTC),
+			wantOpen: false,
+			wantNext: time.Date(2019, time.August, 3, 2, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			open, next, err := saturdayNight.OpenAt(tc.now)
+			testutil.AssertNil(t, "error", err)
+			testutil.AssertEqual(t, "open", tc.wantOpen, open)
+			testutil.AssertEqual(t, "next", tc.wantNext.Unix(), next.Unix())
+		})
+	}
+}
+
 func ExampleApp_ComponentLabels() {
 	app := App{}
 	app.Name = "my-app"
@@ -168,3 +269,18 @@ func ExampleApp_ComponentLabels() {
 	// managed-by: kf
 	// component: database
 }
+
+func ExampleAppSpec_Process() {
+	spec := AppSpec{
+		Processes: []AppSpecProcess{
+			{Type: ProcessTypeWeb, Command: "bundle exec puma"},
+			{Type: "worker", Command: "bundle exec sidekiq"},
+		},
+	}
+
+	fmt.Println("worker:", spec.Process("worker").Command)
+	fmt.Println("clock:", spec.Process("clock"))
+
+	// Output: worker: bundle exec sidekiq
+	// clock: <nil>
+}
@@ -18,18 +18,46 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/google/kf/pkg/internal/cron"
 	"github.com/knative/serving/pkg/apis/serving"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
+// reservedEnvNames are environment variables Kf sets on every App.
+var reservedEnvNames = sets.NewString("PORT")
+
+// reservedEnvPrefixes are prefixes of environment variables Kf sets on every
+// App, such as VCAP_SERVICES and VCAP_APPLICATION.
+var reservedEnvPrefixes = []string{"VCAP_"}
+
 // Validate checks for errors in the App's spec or status fields.
 func (app *App) Validate(ctx context.Context) (errs *apis.FieldError) {
 	// If we're specifically updating status, don't reject the change because
 	// of a spec issue.
 	if !apis.IsInStatusUpdate(ctx) {
+		// Missing names are reported by the API server.
+		if app.Name != "" {
+			errs = errs.Also(ValidateDNS1123Label(app.Name, "name").ViaField("metadata"))
+		}
+
 		errs = errs.Also(app.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec"))
+
+		if app.envChanged(ctx) {
+			podSpec := app.Spec.Template.Spec
+			for i, container := range podSpec.Containers {
+				errs = errs.Also(ValidateEnv(container.Env).ViaField("env").ViaFieldIndex("containers", i).ViaField("spec", "template", "spec"))
+			}
+			errs = errs.Also(checkSecretRefs(ctx, app.Namespace, podSpec).ViaField("spec", "template", "spec"))
+		}
 	}
 
 	return errs
@@ -44,6 +72,92 @@ func (spec *AppSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 	errs = errs.Also(spec.ValidateSourceSpec(ctx).ViaField("source"))
 	errs = errs.Also(spec.ValidateServiceBindings(ctx).ViaField("serviceBindings"))
 
+	switch spec.BindingFormat {
+	case "", BindingFormatVCAP, BindingFormatK8s:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(spec.BindingFormat, "bindingFormat"))
+	}
+
+	for i, route := range spec.Routes {
+		if route.Hostname != "" {
+			errs = errs.Also(ValidateDNS1123Label(route.Hostname, "hostname").ViaFieldIndex("routes", i))
+		}
+	}
+
+	for i, ref := range spec.RestartOnChange {
+		errs = errs.Also(ref.Validate(ctx).ViaFieldIndex("restartOnChange", i))
+	}
+
+	if pool := spec.EgressIPPool; pool != "" && len(validation.IsDNS1123Subdomain(pool)) > 0 {
+		errs = errs.Also(apis.ErrInvalidValue(pool, "egressIPPool"))
+	}
+
+	seenProcesses := make(map[string]bool)
+	for i, process := range spec.Processes {
+		errs = errs.Also(process.Validate(ctx).ViaFieldIndex("processes", i))
+
+		if seenProcesses[process.Type] {
+			errs = errs.Also((&apis.FieldError{
+				Message: fmt.Sprintf("duplicate process type %q", process.Type),
+				Paths:   []string{"type"},
+			}).ViaFieldIndex("processes", i))
+		}
+		seenProcesses[process.Type] = true
+	}
+
+	if spec.Rollout != nil {
+		errs = errs.Also(spec.Rollout.Validate(ctx).ViaField("rollout"))
+	}
+
+	return errs
+}
+
+// Validate checks the rollout names a revision and has a valid percent.
+func (rollout *AppSpecRollout) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if rollout.StableRevisionName == "" {
+		errs = errs.Also(apis.ErrMissingField("stableRevisionName"))
+	}
+
+	if rollout.LatestPercent < 0 || rollout.LatestPercent > 100 {
+		errs = errs.Also(apis.ErrOutOfBoundsValue(rollout.LatestPercent, 0, 100, "latestPercent"))
+	}
+
+	return errs
+}
+
+// Validate checks the process has a valid type and a command.
+func (process *AppSpecProcess) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if process.Type == "" {
+		errs = errs.Also(apis.ErrMissingField("type"))
+	} else {
+		errs = errs.Also(ValidateDNS1123Label(process.Type, "type"))
+	}
+
+	if process.Command == "" {
+		errs = errs.Also(apis.ErrMissingField("command"))
+	}
+
+	if process.Instances != nil && *process.Instances < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*process.Instances, "instances"))
+	}
+
+	return errs
+}
+
+// Validate checks that the reference is to a Secret or ConfigMap.
+func (ref *AppSpecRestartOnChange) Validate(ctx context.Context) (errs *apis.FieldError) {
+	switch ref.Kind {
+	case RestartOnChangeKindSecret, RestartOnChangeKindConfigMap:
+	case "":
+		errs = errs.Also(apis.ErrMissingField("kind"))
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(ref.Kind, "kind"))
+	}
+
+	if ref.Name == "" {
+		errs = errs.Also(apis.ErrMissingField("name"))
+	}
+
 	return errs
 }
 
@@ -101,6 +215,38 @@ func (instances *AppSpecInstances) Validate(ctx context.Context) (errs *apis.Fie
 		errs = errs.Also(&apis.FieldError{Message: "max must be >= min", Paths: []string{"min", "max"}})
 	}
 
+	if instances.ColdStartTimeoutSeconds != nil && *instances.ColdStartTimeoutSeconds <= 0 {
+		errs = errs.Also(apis.ErrInvalidValue(*instances.ColdStartTimeoutSeconds, "coldStartTimeoutSeconds"))
+	}
+
+	if instances.Schedule != nil {
+		errs = errs.Also(instances.Schedule.Validate(ctx).ViaField("schedule"))
+	}
+
+	return errs
+}
+
+// Validate checks that the schedule's cron expressions and time zone can be
+// parsed.
+func (schedule *AppSpecInstancesSchedule) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if schedule.Start == "" {
+		errs = errs.Also(apis.ErrMissingField("start"))
+	} else if _, err := cron.Parse(schedule.Start); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(schedule.Start, "start"))
+	}
+
+	if schedule.Stop == "" {
+		errs = errs.Also(apis.ErrMissingField("stop"))
+	} else if _, err := cron.Parse(schedule.Stop); err != nil {
+		errs = errs.Also(apis.ErrInvalidValue(schedule.Stop, "stop"))
+	}
+
+	if schedule.TimeZone != "" {
+		if _, err := time.LoadLocation(schedule.TimeZone); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(schedule.TimeZone, "timeZone"))
+		}
+	}
+
 	return errs
 }
 
@@ -130,6 +276,125 @@ func ValidatePodSpec(podSpec v1.PodSpec) (errs *apis.FieldError) {
 	return errs
 }
 
+// ValidateEnv checks that environment variables aren't reserved by Kf and
+// aren't declared more than once.
+func ValidateEnv(env []v1.EnvVar) (errs *apis.FieldError) {
+	seen := sets.NewString()
+
+	for i, envVar := range env {
+		switch {
+		case IsReservedEnvName(envVar.Name):
+			errs = errs.Also((&apis.FieldError{
+				Message: fmt.Sprintf("environment variable %s is reserved by Kf", envVar.Name),
+				Paths:   []string{"name"},
+			}).ViaIndex(i))
+		case seen.Has(envVar.Name):
+			errs = errs.Also((&apis.FieldError{
+				Message: fmt.Sprintf("environment variable %s is declared more than once", envVar.Name),
+				Paths:   []string{"name"},
+			}).ViaIndex(i))
+		}
+
+		seen.Insert(envVar.Name)
+	}
+
+	return errs
+}
+
+// IsReservedEnvName returns true if Kf sets the environment variable on every
+// App so users can't.
+func IsReservedEnvName(name string) bool {
+	if reservedEnvNames.Has(name) {
+		return true
+	}
+
+	for _, prefix := range reservedEnvPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// envChanged returns true if the App's environment is new or changed and needs
+// to be checked. Apps being deleted aren't checked so Secrets removed first
+// don't block the finalizers, and unchanged environments aren't so Apps that
+// predate a check can still be scaled, restarted and deleted.
+func (app *App) envChanged(ctx context.Context) bool {
+	if app.DeletionTimestamp != nil {
+		return false
+	}
+
+	base, ok := apis.GetBaseline(ctx).(*App)
+	if !ok || base == nil {
+		return true
+	}
+
+	if len(base.Spec.Template.Spec.Containers) != len(app.Spec.Template.Spec.Containers) {
+		return true
+	}
+
+	for i, container := range app.Spec.Template.Spec.Containers {
+		if !equality.Semantic.DeepEqual(base.Spec.Template.Spec.Containers[i].Env, container.Env) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkSecretRefs ensures the Secrets and keys environment variables read
+// from exist. The check is skipped if the context has no Secrets client.
+func checkSecretRefs(ctx context.Context, namespace string, podSpec v1.PodSpec) (errs *apis.FieldError) {
+	secretsClient := SecretsClientFromContext(ctx)
+	if secretsClient == nil {
+		return nil
+	}
+
+	for ci, container := range podSpec.Containers {
+		for ei, envVar := range container.Env {
+			if envVar.ValueFrom == nil || envVar.ValueFrom.SecretKeyRef == nil {
+				continue
+			}
+
+			ref := envVar.ValueFrom.SecretKeyRef
+			if ref.Optional != nil && *ref.Optional {
+				continue
+			}
+
+			secret, err := secretsClient.Secrets(namespace).Get(ref.Name, metav1.GetOptions{})
+			var refErr *apis.FieldError
+			switch {
+			case apierrs.IsNotFound(err):
+				refErr = &apis.FieldError{
+					Message: fmt.Sprintf("Secret %s doesn't exist", ref.Name),
+					Paths:   []string{"name"},
+				}
+			case err != nil:
+				refErr = &apis.FieldError{
+					Message: "failed to validate Secret reference",
+					Details: fmt.Sprintf("failed to fetch Secret: %s", err),
+				}
+			default:
+				if _, ok := secret.Data[ref.Key]; !ok {
+					refErr = &apis.FieldError{
+						Message: fmt.Sprintf("Secret %s has no key %s", ref.Name, ref.Key),
+						Paths:   []string{"key"},
+					}
+				}
+			}
+
+			errs = errs.Also(refErr.
+				ViaField("valueFrom", "secretKeyRef").
+				ViaFieldIndex("env", ei).
+				ViaFieldIndex("containers", ci))
+		}
+	}
+
+	return errs
+}
+
 // ValidateServiceBindings validates each AppSpecServiceBinding for an App.
 func (spec *AppSpec) ValidateServiceBindings(ctx context.Context) (errs *apis.FieldError) {
 	for _, binding := range spec.ServiceBindings {
@@ -152,5 +417,46 @@ func (binding *AppSpecServiceBinding) Validate(ctx context.Context) (errs *apis.
 		errs = errs.Also(apis.ErrMissingField("parameters"))
 	}
 
+	if binding.Rotation != nil {
+		errs = errs.Also(binding.Rotation.Validate(ctx).ViaField("rotation"))
+	}
+
+	if binding.BackupHooks != nil {
+		errs = errs.Also(binding.BackupHooks.Validate(ctx).ViaField("backupHooks"))
+	}
+
+	return errs
+}
+
+// Validate checks that at least one hook is set.
+func (hooks *AppSpecServiceBindingBackupHooks) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if hooks.PreBackup == "" && hooks.PostRestore == "" {
+		errs = errs.Also(apis.ErrMissingOneOf("preBackup", "postRestore"))
+	}
+
+	return errs
+}
+
+// MinCredentialRotationPeriod is the shortest period credentials can be
+// rotated at so Apps aren't constantly restarting.
+const MinCredentialRotationPeriod = time.Hour
+
+// Validate checks that the rotation period is long enough and the window can
+// be parsed.
+func (rotation *AppSpecServiceBindingRotation) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if rotation.Period.Duration < MinCredentialRotationPeriod {
+		msg := fmt.Sprintf("must be at least %v", MinCredentialRotationPeriod)
+		errs = errs.Also(&apis.FieldError{Message: msg, Paths: []string{"period"}})
+	}
+
+	if rotation.Window != nil {
+		schedule := AppSpecInstancesSchedule{
+			Start:    rotation.Window.Start,
+			Stop:     rotation.Window.Stop,
+			TimeZone: rotation.Window.TimeZone,
+		}
+		errs = errs.Also(schedule.Validate(ctx).ViaField("window"))
+	}
+
 	return errs
 }
@@ -18,10 +18,12 @@ import (
 	"context"
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/google/kf/pkg/kf/testutil"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"knative.dev/pkg/apis"
 )
 
@@ -96,6 +98,189 @@ func TestApp_Validate(t *testing.T) {
 			},
 			want: apis.ErrDisallowedFields("spec.source.serviceAccount"),
 		},
+		"k8s binding format": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:      goodTemplate,
+					Instances:     goodInstances,
+					Source:        goodSource,
+					BindingFormat: BindingFormatK8s,
+				},
+			},
+		},
+		"invalid binding format": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:      goodTemplate,
+					Instances:     goodInstances,
+					Source:        goodSource,
+					BindingFormat: "json",
+				},
+			},
+			want: apis.ErrInvalidValue("json", "spec.bindingFormat"),
+		},
+		"valid restart on change": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:  goodTemplate,
+					Instances: goodInstances,
+					Source:    goodSource,
+					RestartOnChange: []AppSpecRestartOnChange{
+						{Kind: RestartOnChangeKindSecret, Name: "db-creds"},
+						{Kind: RestartOnChangeKindConfigMap, Name: "settings"},
+					},
+				},
+			},
+		},
+		"invalid restart on change": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:  goodTemplate,
+					Instances: goodInstances,
+					Source:    goodSource,
+					RestartOnChange: []AppSpecRestartOnChange{
+						{Kind: "Pod", Name: "some-pod"},
+						{Kind: RestartOnChangeKindSecret},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("Pod", "spec.restartOnChange[0].kind").
+				Also(apis.ErrMissingField("spec.restartOnChange[1].name")),
+		},
+		"valid egress IP pool": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:     goodTemplate,
+					Instances:    goodInstances,
+					Source:       goodSource,
+					EgressIPPool: "partner-allowlist",
+				},
+			},
+		},
+		"invalid egress IP pool": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:     goodTemplate,
+					Instances:    goodInstances,
+					Source:       goodSource,
+					EgressIPPool: "Partner Allowlist",
+				},
+			},
+			want: apis.ErrInvalidValue("Partner Allowlist", "spec.egressIPPool"),
+		},
+		"valid processes": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:  goodTemplate,
+					Instances: goodInstances,
+					Source:    goodSource,
+					Processes: []AppSpecProcess{
+						{Type: ProcessTypeWeb, Command: "bundle exec puma"},
+						{Type: "worker", Command: "bundle exec sidekiq", Instances: intPtr(2)},
+					},
+				},
+			},
+		},
+		"invalid processes": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:  goodTemplate,
+					Instances: goodInstances,
+					Source:    goodSource,
+					Processes: []AppSpecProcess{
+						{Type: "worker", Command: "bundle exec sidekiq"},
+						{Type: "worker", Command: "bundle exec sidekiq"},
+						{Type: "release"},
+					},
+				},
+			},
+			want: (&apis.FieldError{
+				Message: `duplicate process type "worker"`,
+				Paths:   []string{"spec.processes[1].type"},
+			}).Also(apis.ErrMissingField("spec.processes[2].command")),
+		},
+		"valid rollout": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:  goodTemplate,
+					Instances: goodInstances,
+					Source:    goodSource,
+					Rollout:   &AppSpecRollout{StableRevisionName: "valid-abc12", LatestPercent: 20},
+				},
+			},
+		},
+		"invalid rollout": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:  goodTemplate,
+					Instances: goodInstances,
+					Source:    goodSource,
+					Rollout:   &AppSpecRollout{LatestPercent: 120},
+				},
+			},
+			want: apis.ErrMissingField("spec.rollout.stableRevisionName").
+				Also(apis.ErrOutOfBoundsValue(120, 0, 100, "spec.rollout.latestPercent")),
+		},
+		"invalid name": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "My_App",
+				},
+				Spec: AppSpec{
+					Template:  goodTemplate,
+					Instances: goodInstances,
+					Source:    goodSource,
+				},
+			},
+			want: ValidateDNS1123Label("My_App", "metadata.name"),
+		},
+		"invalid route hostname": {
+			spec: App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: AppSpec{
+					Template:  goodTemplate,
+					Instances: goodInstances,
+					Source:    goodSource,
+					Routes: []RouteSpecFields{
+						{Hostname: "my-app", Domain: "example.com"},
+						{Hostname: "my_app", Domain: "example.com"},
+					},
+				},
+			},
+			want: ValidateDNS1123Label("my_app", "spec.routes[1].hostname"),
+		},
 	}
 
 	for tn, tc := range cases {
@@ -212,6 +397,33 @@ func TestAppSpecInstances_Validate(t *testing.T) {
 			spec: AppSpecInstances{Max: intPtr(1), Min: intPtr(50)},
 			want: &apis.FieldError{Message: "max must be >= min", Paths: []string{"min", "max"}},
 		},
+		"valid cold start timeout": {
+			spec: AppSpecInstances{Min: intPtr(0), ColdStartTimeoutSeconds: int64Ptr(60)},
+		},
+		"cold start timeout lte 0": {
+			spec: AppSpecInstances{ColdStartTimeoutSeconds: int64Ptr(0)},
+			want: apis.ErrInvalidValue(0, "coldStartTimeoutSeconds"),
+		},
+		"valid schedule": {
+			spec: AppSpecInstances{Schedule: &AppSpecInstancesSchedule{
+				Start:    "0 8 * * 1-5",
+				Stop:     "0 20 * * 1-5",
+				TimeZone: "UTC",
+			}},
+		},
+		"schedule missing fields": {
+			spec: AppSpecInstances{Schedule: &AppSpecInstancesSchedule{}},
+			want: apis.ErrMissingField("schedule.start", "schedule.stop"),
+		},
+		"schedule invalid": {
+			spec: AppSpecInstances{Schedule: &AppSpecInstancesSchedule{
+				Start:    "0 25 * * *",
+				Stop:     "0 20 * * 1-5",
+				TimeZone: "Not/AZone",
+			}},
+			want: apis.ErrInvalidValue("0 25 * * *", "schedule.start").
+				Also(apis.ErrInvalidValue("Not/AZone", "schedule.timeZone")),
+		},
 	}
 
 	for tn, tc := range cases {
@@ -273,6 +485,230 @@ func TestValidatePodSpec(t *testing.T) {
 	}
 }
 
+func TestValidateEnv(t *testing.T) {
+	cases := map[string]struct {
+		env  []corev1.EnvVar
+		want *apis.FieldError
+	}{
+		"empty": {},
+		"valid": {
+			env: []corev1.EnvVar{
+				{Name: "DATABASE_URL", Value: "mysql://localhost"},
+				{Name: "SERVICE_PORT", Value: "8080"},
+			},
+		},
+		"reserved name": {
+			env: []corev1.EnvVar{{Name: "PORT", Value: "8080"}},
+			want: &apis.FieldError{
+				Message: "environment variable PORT is reserved by Kf",
+				Paths:   []string{"[0].name"},
+			},
+		},
+		"reserved prefix": {
+			env: []corev1.EnvVar{
+				{Name: "FOO", Value: "bar"},
+				{Name: "VCAP_APPLICATION", Value: "{}"},
+			},
+			want: &apis.FieldError{
+				Message: "environment variable VCAP_APPLICATION is reserved by Kf",
+				Paths:   []string{"[1].name"},
+			},
+		},
+		"duplicate": {
+			env: []corev1.EnvVar{
+				{Name: "FOO", Value: "bar"},
+				{Name: "FOO", Value: "baz"},
+			},
+			want: &apis.FieldError{
+				Message: "environment variable FOO is declared more than once",
+				Paths:   []string{"[1].name"},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got := ValidateEnv(tc.env)
+
+			testutil.AssertEqual(t, "validation errors", tc.want.Error(), got.Error())
+		})
+	}
+}
+
+func TestApp_Validate_env(t *testing.T) {
+	reserved := []corev1.EnvVar{{Name: "VCAP_SERVICES", Value: "{}"}}
+	duplicated := []corev1.EnvVar{{Name: "FOO", Value: "a"}, {Name: "FOO", Value: "b"}}
+
+	cases := map[string]struct {
+		env        []corev1.EnvVar
+		baseEnv    []corev1.EnvVar
+		wantErrMsg string
+	}{
+		"reserved env on create": {
+			env:        reserved,
+			wantErrMsg: "environment variable VCAP_SERVICES is reserved by Kf: spec.template.spec.containers[0].env[0].name",
+		},
+		"reserved env added on update": {
+			env:        reserved,
+			baseEnv:    []corev1.EnvVar{},
+			wantErrMsg: "environment variable VCAP_SERVICES is reserved by Kf: spec.template.spec.containers[0].env[0].name",
+		},
+		"pre-existing reserved env": {
+			env:     reserved,
+			baseEnv: reserved,
+		},
+		"pre-existing duplicate env": {
+			env:     duplicated,
+			baseEnv: duplicated,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			app := &App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-app",
+					Namespace: "my-space",
+				},
+				Spec: AppSpec{
+					Template: AppSpecTemplate{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Env: tc.env}},
+						},
+					},
+					Instances: AppSpecInstances{Stopped: true},
+					Source: SourceSpec{
+						ContainerImage: SourceSpecContainerImage{Image: "mysql"},
+					},
+				},
+			}
+
+			ctx := context.Background()
+			if tc.baseEnv != nil {
+				// The update only changes the scale, like kf scale would.
+				base := app.DeepCopy()
+				base.Spec.Template.Spec.Containers[0].Env = tc.baseEnv
+				base.Spec.Instances.Stopped = false
+				ctx = apis.WithinUpdate(ctx, base)
+			}
+
+			got := app.Validate(ctx)
+
+			testutil.AssertEqual(t, "validation errors", tc.wantErrMsg, got.Error())
+		})
+	}
+}
+
+func TestApp_Validate_secretRefs(t *testing.T) {
+	secretEnv := func(name, key string, optional *bool) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: "SECRET",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					Key:                  key,
+					Optional:             optional,
+				},
+			},
+		}
+	}
+
+	optional := true
+
+	cases := map[string]struct {
+		env        corev1.EnvVar
+		noClient   bool
+		deleting   bool
+		baseEnv    []corev1.EnvVar
+		wantErrMsg string
+	}{
+		"secret exists": {
+			env: secretEnv("creds", "password", nil),
+		},
+		"secret missing": {
+			env:        secretEnv("missing", "password", nil),
+			wantErrMsg: "Secret missing doesn't exist: spec.template.spec.containers[0].env[0].valueFrom.secretKeyRef.name",
+		},
+		"key missing": {
+			env:        secretEnv("creds", "username", nil),
+			wantErrMsg: "Secret creds has no key username: spec.template.spec.containers[0].env[0].valueFrom.secretKeyRef.key",
+		},
+		"optional reference": {
+			env: secretEnv("missing", "password", &optional),
+		},
+		"no client": {
+			env:      secretEnv("missing", "password", nil),
+			noClient: true,
+		},
+		"deleting": {
+			env:      secretEnv("missing", "password", nil),
+			deleting: true,
+		},
+		"env unchanged": {
+			env:     secretEnv("missing", "password", nil),
+			baseEnv: []corev1.EnvVar{secretEnv("missing", "password", nil)},
+		},
+		"env changed": {
+			env:        secretEnv("missing", "password", nil),
+			baseEnv:    []corev1.EnvVar{secretEnv("creds", "password", nil)},
+			wantErrMsg: "Secret missing doesn't exist: spec.template.spec.containers[0].env[0].valueFrom.secretKeyRef.name",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			app := &App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-app",
+					Namespace: "my-space",
+				},
+				Spec: AppSpec{
+					Template: AppSpecTemplate{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{
+								Env: []corev1.EnvVar{tc.env},
+							}},
+						},
+					},
+					Instances: AppSpecInstances{Stopped: true},
+					Source: SourceSpec{
+						ContainerImage: SourceSpecContainerImage{Image: "mysql"},
+					},
+				},
+			}
+
+			ctx := context.Background()
+			if !tc.noClient {
+				client := k8sfake.NewSimpleClientset(&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "creds",
+						Namespace: "my-space",
+					},
+					Data: map[string][]byte{
+						"password": []byte("hunter2"),
+					},
+				})
+				ctx = SetupSecretsClient(ctx, client.CoreV1())
+			}
+
+			if tc.deleting {
+				now := metav1.Now()
+				app.DeletionTimestamp = &now
+			}
+
+			if tc.baseEnv != nil {
+				base := app.DeepCopy()
+				base.Spec.Template.Spec.Containers[0].Env = tc.baseEnv
+				ctx = apis.WithinUpdate(ctx, base)
+			}
+
+			got := app.Validate(ctx)
+
+			testutil.AssertEqual(t, "validation errors", tc.wantErrMsg, got.Error())
+		})
+	}
+}
+
 func TestAppSpecServiceBinding_Validate(t *testing.T) {
 	cases := map[string]struct {
 		binding *AppSpecServiceBinding
@@ -306,6 +742,57 @@ func TestAppSpecServiceBinding_Validate(t *testing.T) {
 				Parameters:  json.RawMessage("null"),
 			},
 		},
+		"valid rotation": {
+			binding: &AppSpecServiceBinding{
+				BindingName: "my-cool-binding",
+				Instance:    "my-cool-instance",
+				Parameters:  json.RawMessage("null"),
+				Rotation: &AppSpecServiceBindingRotation{
+					Period: metav1.Duration{Duration: 30 * 24 * time.Hour},
+					Window: &MaintenanceWindow{Start: "0 2 * * 6", Stop: "0 4 * * 6"},
+				},
+			},
+		},
+		"rotation period too short": {
+			binding: &AppSpecServiceBinding{
+				BindingName: "my-cool-binding",
+				Instance:    "my-cool-instance",
+				Parameters:  json.RawMessage("null"),
+				Rotation: &AppSpecServiceBindingRotation{
+					Period: metav1.Duration{Duration: time.Minute},
+				},
+			},
+			want: (&apis.FieldError{Message: "must be at least 1h0m0s", Paths: []string{"period"}}).ViaField("rotation"),
+		},
+		"bad rotation window": {
+			binding: &AppSpecServiceBinding{
+				BindingName: "my-cool-binding",
+				Instance:    "my-cool-instance",
+				Parameters:  json.RawMessage("null"),
+				Rotation: &AppSpecServiceBindingRotation{
+					Period: metav1.Duration{Duration: time.Hour},
+					Window: &MaintenanceWindow{Start: "not cron", Stop: "0 4 * * 6"},
+				},
+			},
+			want: apis.ErrInvalidValue("not cron", "start").ViaField("window").ViaField("rotation"),
+		},
+		"valid backup hooks": {
+			binding: &AppSpecServiceBinding{
+				BindingName: "my-cool-binding",
+				Instance:    "my-cool-instance",
+				Parameters:  json.RawMessage("null"),
+				BackupHooks: &AppSpecServiceBindingBackupHooks{PreBackup: "./flush.sh"},
+			},
+		},
+		"empty backup hooks": {
+			binding: &AppSpecServiceBinding{
+				BindingName: "my-cool-binding",
+				Instance:    "my-cool-instance",
+				Parameters:  json.RawMessage("null"),
+				BackupHooks: &AppSpecServiceBindingBackupHooks{},
+			},
+			want: apis.ErrMissingOneOf("preBackup", "postRestore").ViaField("backupHooks"),
+		},
 	}
 
 	for tn, tc := range cases {
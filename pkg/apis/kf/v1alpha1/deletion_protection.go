@@ -0,0 +1,49 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DeletionProtectionAnnotation is set to "true" on Apps and Spaces that
+	// the webhook won't allow to be deleted.
+	DeletionProtectionAnnotation = "kf.dev/deletion-protection"
+)
+
+// IsDeletionProtected returns true if the object has deletion protection
+// enabled.
+func IsDeletionProtected(obj metav1.Object) bool {
+	return obj.GetAnnotations()[DeletionProtectionAnnotation] == "true"
+}
+
+// SetDeletionProtection enables or disables deletion protection on the
+// object.
+func SetDeletionProtection(obj metav1.Object, enabled bool) {
+	annotations := obj.GetAnnotations()
+
+	if !enabled {
+		delete(annotations, DeletionProtectionAnnotation)
+		obj.SetAnnotations(annotations)
+		return
+	}
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[DeletionProtectionAnnotation] = "true"
+	obj.SetAnnotations(annotations)
+}
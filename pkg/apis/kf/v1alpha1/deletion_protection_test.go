@@ -0,0 +1,40 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestSetDeletionProtection(t *testing.T) {
+	t.Parallel()
+
+	app := &App{}
+	testutil.AssertEqual(t, "default", false, IsDeletionProtected(app))
+
+	SetDeletionProtection(app, true)
+	testutil.AssertEqual(t, "enabled", true, IsDeletionProtected(app))
+	testutil.AssertEqual(t, "annotation", "true", app.Annotations[DeletionProtectionAnnotation])
+
+	SetDeletionProtection(app, false)
+	testutil.AssertEqual(t, "disabled", false, IsDeletionProtected(app))
+	testutil.AssertEqual(t, "annotations", map[string]string{}, app.Annotations)
+
+	space := &Space{}
+	SetDeletionProtection(space, false)
+	testutil.AssertEqual(t, "no annotations", 0, len(space.Annotations))
+}
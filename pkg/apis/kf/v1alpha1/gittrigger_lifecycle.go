@@ -0,0 +1,80 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/apis"
+)
+
+// GetGroupVersionKind returns the GroupVersionKind.
+func (r *GitTrigger) GetGroupVersionKind() schema.GroupVersionKind {
+	return SchemeGroupVersion.WithKind("GitTrigger")
+}
+
+const (
+	// GitTriggerConditionReady is set when the App has been rebuilt from the
+	// latest revision of the branch.
+	GitTriggerConditionReady = apis.ConditionReady
+	// GitTriggerConditionAppUpdated is set when the App's source has been
+	// updated to the latest revision of the branch.
+	GitTriggerConditionAppUpdated apis.ConditionType = "AppUpdated"
+)
+
+func (status *GitTriggerStatus) manage() apis.ConditionManager {
+	return apis.NewLivingConditionSet(GitTriggerConditionAppUpdated).Manage(status)
+}
+
+// IsReady returns if the App is up to date with the branch.
+func (status *GitTriggerStatus) IsReady() bool {
+	return status.manage().IsHappy()
+}
+
+// GetCondition returns the condition by name.
+func (status *GitTriggerStatus) GetCondition(t apis.ConditionType) *apis.Condition {
+	return status.manage().GetCondition(t)
+}
+
+// InitializeConditions sets the initial values to the conditions.
+func (status *GitTriggerStatus) InitializeConditions() {
+	status.manage().InitializeConditions()
+}
+
+// MarkWaitingForPush notes that no push to the branch has been received yet.
+func (status *GitTriggerStatus) MarkWaitingForPush(branch string) {
+	status.manage().MarkUnknown(GitTriggerConditionAppUpdated, "WaitingForPush",
+		"Waiting for the first push to branch %q", branch)
+}
+
+// MarkAppNotFound notes that the App the trigger rebuilds doesn't exist.
+func (status *GitTriggerStatus) MarkAppNotFound(name string) {
+	status.manage().MarkFalse(GitTriggerConditionAppUpdated, "AppNotFound",
+		fmt.Sprintf("The App %q doesn't exist.", name))
+}
+
+// MarkAppNotBuildable notes that the App is deployed from a container image so
+// it can't be built from Git.
+func (status *GitTriggerStatus) MarkAppNotBuildable(name string) {
+	status.manage().MarkFalse(GitTriggerConditionAppUpdated, "AppNotBuildable",
+		fmt.Sprintf("The App %q is deployed from a container image and can't be built from Git.", name))
+}
+
+// MarkAppUpdated records that the App is being rebuilt from revision.
+func (status *GitTriggerStatus) MarkAppUpdated(revision string) {
+	status.TriggeredRevision = revision
+	status.manage().MarkTrue(GitTriggerConditionAppUpdated)
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestGitTriggerStatus_lifecycle(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		mark       func(status *GitTriggerStatus)
+		wantStatus corev1.ConditionStatus
+		wantReason string
+		wantRev    string
+	}{
+		"initialized": {
+			mark:       func(status *GitTriggerStatus) {},
+			wantStatus: corev1.ConditionUnknown,
+		},
+		"waiting for push": {
+			mark: func(status *GitTriggerStatus) {
+				status.MarkWaitingForPush("master")
+			},
+			wantStatus: corev1.ConditionUnknown,
+			wantReason: "WaitingForPush",
+		},
+		"app not found": {
+			mark: func(status *GitTriggerStatus) {
+				status.MarkAppNotFound("some-app")
+			},
+			wantStatus: corev1.ConditionFalse,
+			wantReason: "AppNotFound",
+		},
+		"app not buildable": {
+			mark: func(status *GitTriggerStatus) {
+				status.MarkAppNotBuildable("some-app")
+			},
+			wantStatus: corev1.ConditionFalse,
+			wantReason: "AppNotBuildable",
+		},
+		"app updated": {
+			mark: func(status *GitTriggerStatus) {
+				status.MarkAppUpdated("abc123")
+			},
+			wantStatus: corev1.ConditionTrue,
+			wantRev:    "abc123",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			status := &GitTriggerStatus{}
+			status.InitializeConditions()
+			tc.mark(status)
+
+			ready := status.GetCondition(apis.ConditionReady)
+			testutil.AssertEqual(t, "ready status", tc.wantStatus, ready.Status)
+			testutil.AssertEqual(t, "ready reason", tc.wantReason, ready.Reason)
+			testutil.AssertEqual(t, "is ready", tc.wantStatus == corev1.ConditionTrue, status.IsReady())
+			testutil.AssertEqual(t, "triggered revision", tc.wantRev, status.TriggeredRevision)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+)
+
+const (
+	// GitTriggerSecretKey is the key in a GitTrigger's Secret that holds the
+	// shared secret webhooks are authenticated with.
+	GitTriggerSecretKey = "secret"
+
+	// DefaultGitTriggerBranch is the branch a GitTrigger follows if none is
+	// set.
+	DefaultGitTriggerBranch = "master"
+)
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GitTrigger rebuilds an App from its Git repository each time a branch of
+// the repository is updated. The webhook receiver records new commits in the
+// spec and the controller applies them to the App.
+type GitTrigger struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Spec GitTriggerSpec `json:"spec,omitempty"`
+
+	// +optional
+	Status GitTriggerStatus `json:"status,omitempty"`
+}
+
+// GitTriggerSpec contains the specification for a GitTrigger.
+type GitTriggerSpec struct {
+	// AppName is the name of the App in the same Space to rebuild.
+	AppName string `json:"appName"`
+
+	// Repository is the URL of the Git repository the App is built from.
+	Repository string `json:"repository"`
+
+	// Branch is the branch of the repository updates are followed on.
+	// +optional
+	Branch string `json:"branch,omitempty"`
+
+	// SecretName is the name of the Secret in the same Space holding the
+	// shared secret webhooks are authenticated with.
+	SecretName string `json:"secretName"`
+
+	// Revision is the latest commit pushed to the branch. It's set by the
+	// webhook receiver when it's notified of a push.
+	// +optional
+	Revision string `json:"revision,omitempty"`
+}
+
+// GitTriggerStatus is the current state of a GitTrigger.
+type GitTriggerStatus struct {
+	// Pull in the fields from Knative's duckv1beta1 status field.
+	duckv1beta1.Status `json:",inline"`
+
+	// TriggeredRevision is the latest revision the App was rebuilt from.
+	// +optional
+	TriggeredRevision string `json:"triggeredRevision,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// GitTriggerList is a list of GitTrigger resources.
+type GitTriggerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []GitTrigger `json:"items"`
+}
@@ -0,0 +1,53 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+
+	"knative.dev/pkg/apis"
+)
+
+// Validate checks for errors in the GitTrigger's spec or status fields.
+func (t *GitTrigger) Validate(ctx context.Context) (errs *apis.FieldError) {
+	// If we're specifically updating status, don't reject the change because
+	// of a spec issue.
+	if apis.IsInStatusUpdate(ctx) {
+		return
+	}
+
+	return errs.Also(t.Spec.Validate(apis.WithinSpec(ctx)).ViaField("spec"))
+}
+
+// Validate makes sure that a GitTriggerSpec is properly configured.
+func (spec *GitTriggerSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if spec.AppName == "" {
+		errs = errs.Also(apis.ErrMissingField("appName"))
+	}
+
+	if spec.Repository == "" {
+		errs = errs.Also(apis.ErrMissingField("repository"))
+	}
+
+	if spec.Branch == "" {
+		errs = errs.Also(apis.ErrMissingField("branch"))
+	}
+
+	if spec.SecretName == "" {
+		errs = errs.Also(apis.ErrMissingField("secretName"))
+	}
+
+	return errs
+}
@@ -0,0 +1,77 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	"knative.dev/pkg/apis"
+)
+
+func TestGitTrigger_Validate(t *testing.T) {
+	goodSpec := GitTriggerSpec{
+		AppName:    "some-app",
+		Repository: "https://github.com/some/repo",
+		Branch:     "master",
+		SecretName: "some-secret",
+	}
+
+	cases := map[string]struct {
+		trigger GitTrigger
+		ctx     context.Context
+		want    *apis.FieldError
+	}{
+		"valid": {
+			trigger: GitTrigger{Spec: goodSpec},
+		},
+		"missing fields": {
+			trigger: GitTrigger{},
+			want: apis.ErrMissingField(
+				"spec.appName",
+				"spec.repository",
+				"spec.branch",
+				"spec.secretName",
+			),
+		},
+		"status update skips spec": {
+			trigger: GitTrigger{},
+			ctx:     apis.WithinSubResourceUpdate(context.Background(), &GitTrigger{}, "status"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			if tc.ctx == nil {
+				tc.ctx = context.Background()
+			}
+
+			got := tc.trigger.Validate(tc.ctx)
+
+			testutil.AssertEqual(t, "validation errors", tc.want.Error(), got.Error())
+		})
+	}
+}
+
+func TestGitTrigger_SetDefaults(t *testing.T) {
+	trigger := &GitTrigger{}
+	trigger.SetDefaults(context.Background())
+	testutil.AssertEqual(t, "branch", DefaultGitTriggerBranch, trigger.Spec.Branch)
+
+	trigger = &GitTrigger{Spec: GitTriggerSpec{Branch: "release"}}
+	trigger.SetDefaults(context.Background())
+	testutil.AssertEqual(t, "branch", "release", trigger.Spec.Branch)
+}
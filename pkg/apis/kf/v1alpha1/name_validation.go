@@ -0,0 +1,77 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/apis"
+)
+
+// dnsLabelHashLength is the number of hex characters of a hash appended to
+// names that are truncated so different long names stay distinct.
+const dnsLabelHashLength = 8
+
+// SuggestDNS1123Label converts name into a valid DNS-1123 label, e.g.
+// My_App becomes my-app. Names that are too long are truncated and suffixed
+// with a hash of the original so the same name always gets the same
+// suggestion. An empty string is returned if name has no usable characters.
+func SuggestDNS1123Label(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		case !strings.HasSuffix(sb.String(), "-"):
+			// Underscores, dots, spaces, and anything else become a single
+			// dash.
+			sb.WriteRune('-')
+		}
+	}
+
+	suggestion := strings.Trim(sb.String(), "-")
+	if len(suggestion) > validation.DNS1123LabelMaxLength {
+		sum := sha256.Sum256([]byte(name))
+		hash := hex.EncodeToString(sum[:])[:dnsLabelHashLength]
+		prefix := suggestion[:validation.DNS1123LabelMaxLength-dnsLabelHashLength-1]
+		suggestion = strings.TrimRight(prefix, "-") + "-" + hash
+	}
+
+	return suggestion
+}
+
+// ValidateDNS1123Label checks that value can be used as a DNS-1123 label,
+// which App names and route hostnames must be because they're used in the
+// names of Kubernetes objects and in hostnames. The error details include a
+// valid name the user may have meant.
+func ValidateDNS1123Label(value, field string) *apis.FieldError {
+	problems := validation.IsDNS1123Label(value)
+	if len(problems) == 0 {
+		return nil
+	}
+
+	details := strings.Join(problems, "; ")
+	if suggestion := SuggestDNS1123Label(value); suggestion != "" {
+		details = fmt.Sprintf("%s; did you mean %q?", details, suggestion)
+	}
+
+	err := apis.ErrInvalidValue(value, field)
+	err.Details = details
+	return err
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+func TestSuggestDNS1123Label(t *testing.T) {
+	t.Parallel()
+
+	long := strings.Repeat("a", 70)
+
+	cases := map[string]struct {
+		name string
+		want string
+	}{
+		"valid": {
+			name: "my-app",
+			want: "my-app",
+		},
+		"underscores and capitals": {
+			name: "My_App",
+			want: "my-app",
+		},
+		"dots and spaces": {
+			name: "my.app v2",
+			want: "my-app-v2",
+		},
+		"repeated and trailing invalid characters": {
+			name: "__my--app__",
+			want: "my-app",
+		},
+		"no usable characters": {
+			name: "___",
+			want: "",
+		},
+		"too long": {
+			name: long,
+			want: strings.Repeat("a", 54) + "-" + "6bd5e503",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got := SuggestDNS1123Label(tc.name)
+			testutil.AssertEqual(t, "suggestion", tc.want, got)
+
+			if got != "" {
+				testutil.AssertEqual(t, "problems", 0, len(validation.IsDNS1123Label(got)))
+			}
+		})
+	}
+}
+
+func TestSuggestDNS1123Label_deterministic(t *testing.T) {
+	t.Parallel()
+
+	a := strings.Repeat("a", 70)
+	b := strings.Repeat("a", 71)
+
+	testutil.AssertEqual(t, "same name", SuggestDNS1123Label(a), SuggestDNS1123Label(a))
+	if SuggestDNS1123Label(a) == SuggestDNS1123Label(b) {
+		t.Fatalf("expected different suggestions for %q and %q", a, b)
+	}
+}
+
+func TestValidateDNS1123Label(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidateDNS1123Label("my-app", "name"); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	err := ValidateDNS1123Label("My_App", "name")
+	testutil.AssertEqual(t, "message", "invalid value: My_App", err.Message)
+	testutil.AssertEqual(t, "paths", []string{"name"}, err.Paths)
+	testutil.AssertContainsAll(t, err.Details, []string{"DNS-1123 label", `did you mean "my-app"?`})
+
+	err = ValidateDNS1123Label("___", "name")
+	testutil.AssertEqual(t, "no suggestion", false, strings.Contains(err.Details, "did you mean"))
+}
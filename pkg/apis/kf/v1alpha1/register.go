@@ -48,6 +48,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		SchemeGroupVersion,
 		&App{},
 		&AppList{},
+		&GitTrigger{},
+		&GitTriggerList{},
 		&Source{},
 		&SourceList{},
 		&Space{},
@@ -19,6 +19,7 @@ import (
 	"hash/crc64"
 	"path"
 	"strconv"
+	"strings"
 
 	"github.com/knative/serving/pkg/resources"
 )
@@ -45,8 +46,15 @@ func GenerateRouteName(hostname, domain, urlPath, appName string) string {
 }
 
 // GenerateRouteNameFromSpec creates the deterministic name for a Route.
+// Routes that match on headers include them in their name so an App can be
+// mapped to the same path with different headers.
 func GenerateRouteNameFromSpec(spec RouteSpecFields, appName string) string {
-	return GenerateName(spec.Hostname, spec.Domain, spec.Path, appName)
+	parts := []string{spec.Hostname, spec.Domain, path.Join("/", spec.Path), appName}
+	if len(spec.Headers) > 0 {
+		parts = append(parts, spec.HeadersString())
+	}
+
+	return GenerateName(parts...)
 }
 
 // SetDefaults implements apis.Defaultable
@@ -63,6 +71,18 @@ func (k *RouteSpec) SetDefaults(ctx context.Context) {
 // SetDefaults implements apis.Defaultable
 func (k *RouteSpecFields) SetDefaults(ctx context.Context) {
 	k.Path = path.Join("/", k.Path)
+
+	if k.RewritePath != "" {
+		k.RewritePath = path.Join("/", k.RewritePath)
+	}
+
+	// Istio only matches lowercase header names.
+	for name, value := range k.Headers {
+		if lower := strings.ToLower(name); lower != name {
+			delete(k.Headers, name)
+			k.Headers[lower] = value
+		}
+	}
 }
 
 func (k *RouteSpecFields) labels() map[string]string {
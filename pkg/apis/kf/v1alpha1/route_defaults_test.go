@@ -58,3 +58,13 @@ func ExampleRouteClaim_SetDefaults_labels() {
 	// Domain: example.com
 	// Path: pvdf1ls1w14a
 }
+
+func ExampleRoute_SetDefaults_headers() {
+	r := &Route{}
+	r.Spec.Headers = map[string]string{"X-Beta": "true"}
+	r.SetDefaults(context.Background())
+
+	fmt.Println("Headers:", r.Spec.Headers)
+
+	// Output: Headers: map[x-beta:true]
+}
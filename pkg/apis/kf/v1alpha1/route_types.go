@@ -16,6 +16,8 @@ package v1alpha1
 
 import (
 	"path"
+	"sort"
+	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -94,6 +96,22 @@ type RouteSpecFields struct {
 	// Path is the URL path of the route.
 	// +optional
 	Path string `json:"path,omitempty"`
+
+	// Headers restricts the route to requests carrying all of the given
+	// HTTP headers with exactly matching values. Routes with headers take
+	// precedence over routes for the same path without them.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// StripPathPrefix removes the route's path from requests before they're
+	// sent to the App so Apps mounted at a path can be served at /.
+	// +optional
+	StripPathPrefix bool `json:"stripPathPrefix,omitempty"`
+
+	// RewritePath replaces the route's path in requests with the given path
+	// before they're sent to the App.
+	// +optional
+	RewritePath string `json:"rewritePath,omitempty"`
 }
 
 // String returns a RouteSpecFields converted into an address.
@@ -105,8 +123,113 @@ func (route RouteSpecFields) String() string {
 	return hostnamePrefix + route.Domain + path.Join("/", route.Path)
 }
 
+// HeadersString returns the route's header matches in a canonical form with
+// lowercase names sorted alphabetically, e.g. "x-beta=true,x-tier=gold". It
+// returns a blank string if the route doesn't match on headers.
+func (route RouteSpecFields) HeadersString() string {
+	var matches []string
+	for name, value := range route.Headers {
+		matches = append(matches, strings.ToLower(name)+"="+value)
+	}
+	sort.Strings(matches)
+
+	return strings.Join(matches, ",")
+}
+
+// PathRewrite returns the path that replaces the route's path in requests
+// sent to the App. It returns a blank string if the path isn't rewritten.
+func (route RouteSpecFields) PathRewrite() string {
+	if route.StripPathPrefix {
+		return "/"
+	}
+
+	return route.RewritePath
+}
+
 // RouteClaimSpec contains the specification for a RouteClaim.
 type RouteClaimSpec struct {
 	// RouteSpecFields contains the fields of a route.
 	RouteSpecFields `json:",inline"`
+
+	// Maintenance, if set, causes all traffic for the route to be served a
+	// static maintenance page rather than being sent to Apps.
+	// +optional
+	Maintenance *RouteMaintenance `json:"maintenance,omitempty"`
+
+	// SessionAffinity, if set, causes requests from the same client to be
+	// sent to the same App instance.
+	// +optional
+	SessionAffinity *RouteSessionAffinity `json:"sessionAffinity,omitempty"`
+
+	// CORSPolicy, if set, allows browsers to make cross-origin requests to
+	// the route.
+	// +optional
+	CORSPolicy *RouteCORSPolicy `json:"corsPolicy,omitempty"`
+
+	// ResponseHeaders are set on every response served for the route. They
+	// override headers with the same name set on the space.
+	// +optional
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+}
+
+// RouteMaintenance holds the configuration for a route that's down for
+// maintenance.
+type RouteMaintenance struct {
+	// Page is the HTML page served while the route is under maintenance. If
+	// blank, a default page is served.
+	// +optional
+	Page string `json:"page,omitempty"`
+}
+
+// SessionAffinityType is the way clients are pinned to App instances.
+type SessionAffinityType string
+
+const (
+	// SessionAffinityCookie pins clients using a cookie set on the first
+	// response.
+	SessionAffinityCookie SessionAffinityType = "cookie"
+
+	// SessionAffinitySourceIP pins clients using their IP address.
+	SessionAffinitySourceIP SessionAffinityType = "source-ip"
+)
+
+// SessionAffinityTypes returns the supported SessionAffinityTypes.
+func SessionAffinityTypes() []SessionAffinityType {
+	return []SessionAffinityType{SessionAffinityCookie, SessionAffinitySourceIP}
+}
+
+// RouteSessionAffinity holds the sticky session configuration for a route.
+type RouteSessionAffinity struct {
+	// Type is the way clients are pinned to App instances.
+	Type SessionAffinityType `json:"type"`
+}
+
+// RouteCORSPolicy holds the Cross-Origin Resource Sharing configuration for
+// a route.
+type RouteCORSPolicy struct {
+	// AllowOrigins are the origins allowed to make requests, e.g.
+	// https://app.example.com, or * for any origin.
+	AllowOrigins []string `json:"allowOrigins"`
+
+	// AllowMethods are the HTTP methods allowed in requests.
+	// +optional
+	AllowMethods []string `json:"allowMethods,omitempty"`
+
+	// AllowHeaders are the HTTP headers allowed in requests.
+	// +optional
+	AllowHeaders []string `json:"allowHeaders,omitempty"`
+
+	// ExposeHeaders are the response headers browsers are allowed to read.
+	// +optional
+	ExposeHeaders []string `json:"exposeHeaders,omitempty"`
+
+	// MaxAgeSeconds is how long browsers can cache the result of a preflight
+	// request.
+	// +optional
+	MaxAgeSeconds int64 `json:"maxAgeSeconds,omitempty"`
+
+	// AllowCredentials allows requests to include credentials such as
+	// cookies.
+	// +optional
+	AllowCredentials bool `json:"allowCredentials,omitempty"`
 }
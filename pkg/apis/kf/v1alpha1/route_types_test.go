@@ -49,3 +49,31 @@ func ExampleRouteSpecFields_String_without_path() {
 
 	// Output: foo.example.com/
 }
+
+func ExampleRouteSpecFields_HeadersString() {
+	r := RouteSpecFields{
+		Domain: "example.com",
+		Headers: map[string]string{
+			"X-Tier": "gold",
+			"x-beta": "true",
+		},
+	}
+
+	fmt.Println(r.HeadersString())
+
+	// Output: x-beta=true,x-tier=gold
+}
+
+func ExampleRouteSpecFields_PathRewrite() {
+	strip := RouteSpecFields{Path: "/service", StripPathPrefix: true}
+	rewrite := RouteSpecFields{Path: "/service", RewritePath: "/v2"}
+	none := RouteSpecFields{Path: "/service"}
+
+	fmt.Printf("strip: %q\n", strip.PathRewrite())
+	fmt.Printf("rewrite: %q\n", rewrite.PathRewrite())
+	fmt.Printf("none: %q\n", none.PathRewrite())
+
+	// Output: strip: "/"
+	// rewrite: "/v2"
+	// none: ""
+}
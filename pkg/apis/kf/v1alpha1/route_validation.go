@@ -17,6 +17,10 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/gorilla/mux"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
@@ -98,10 +102,61 @@ func (r *RouteSpecFields) Validate(ctx context.Context) (errs *apis.FieldError)
 		errs = errs.Also(apis.ErrInvalidValue("hostname", r.Hostname))
 	}
 
+	if r.Hostname != "" {
+		errs = errs.Also(ValidateDNS1123Label(r.Hostname, "hostname"))
+	}
+
 	if _, err := BuildPathRegexp(r.Path); err != nil {
 		errs = errs.Also(apis.ErrInvalidValue("path", r.Path))
 	}
 
+	for _, name := range sortedHeaderNames(r.Headers) {
+		if !headerNameRegexp.MatchString(name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, "headers"))
+		}
+
+		if r.Headers[name] == "" {
+			errs = errs.Also(apis.ErrMissingField(name).ViaField("headers"))
+		}
+	}
+
+	if r.StripPathPrefix && r.RewritePath != "" {
+		errs = errs.Also(apis.ErrMultipleOneOf("stripPathPrefix", "rewritePath"))
+	}
+
+	if r.RewritePath != "" && !strings.HasPrefix(r.RewritePath, "/") {
+		errs = errs.Also(apis.ErrInvalidValue(r.RewritePath, "rewritePath"))
+	}
+
+	return errs
+}
+
+// headerNameRegexp matches valid HTTP header names (tokens in RFC 7230).
+var headerNameRegexp = regexp.MustCompile("^[a-zA-Z0-9!#$%&'*+.^_|~-]+$")
+
+func sortedHeaderNames(headers map[string]string) []string {
+	var names []string
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ValidateResponseHeaders validates headers set on responses, names must be
+// valid HTTP header names and values must not be blank.
+func ValidateResponseHeaders(headers map[string]string) (errs *apis.FieldError) {
+	for _, name := range sortedHeaderNames(headers) {
+		if !headerNameRegexp.MatchString(name) {
+			errs = errs.Also(apis.ErrInvalidKeyName(name, apis.CurrentField))
+		}
+
+		if strings.TrimSpace(headers[name]) == "" {
+			errs = errs.Also(apis.ErrMissingField(name))
+		}
+	}
+
 	return errs
 }
 
@@ -129,7 +184,51 @@ func (r *RouteClaim) Validate(ctx context.Context) (errs *apis.FieldError) {
 
 // Validate validates a RouteClaimSpec.
 func (r *RouteClaimSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
-	return errs.Also(r.RouteSpecFields.Validate(ctx).ViaField("routeSpecFields"))
+	errs = errs.Also(r.RouteSpecFields.Validate(ctx).ViaField("routeSpecFields"))
+
+	if r.Maintenance != nil {
+		errs = errs.Also(r.Maintenance.Validate(ctx).ViaField("maintenance"))
+	}
+
+	if r.SessionAffinity != nil {
+		errs = errs.Also(r.SessionAffinity.Validate(ctx).ViaField("sessionAffinity"))
+	}
+
+	if r.CORSPolicy != nil {
+		errs = errs.Also(r.CORSPolicy.Validate(ctx).ViaField("corsPolicy"))
+	}
+
+	errs = errs.Also(ValidateResponseHeaders(r.ResponseHeaders).ViaField("responseHeaders"))
+
+	return errs
+}
+
+// MaxMaintenancePageSize is the maximum size of a custom maintenance page in
+// bytes. Pages are passed to the maintenance server in a request header so
+// they must be kept small.
+const MaxMaintenancePageSize = 16 * 1024
+
+// Validate validates a RouteMaintenance.
+func (m *RouteMaintenance) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if len(m.Page) > MaxMaintenancePageSize {
+		errs = errs.Also(&apis.FieldError{
+			Message: fmt.Sprintf("page must be at most %d bytes", MaxMaintenancePageSize),
+			Paths:   []string{"page"},
+		})
+	}
+
+	return errs
+}
+
+// Validate validates a RouteSessionAffinity.
+func (a *RouteSessionAffinity) Validate(ctx context.Context) (errs *apis.FieldError) {
+	for _, t := range SessionAffinityTypes() {
+		if a.Type == t {
+			return nil
+		}
+	}
+
+	return apis.ErrInvalidValue(a.Type, "type")
 }
 
 // BuildPathRegexp uses gorilla/mux to convert a path into regular expression
@@ -146,3 +245,45 @@ func BuildPathRegexp(path string) (string, error) {
 	}
 	return p + `(/.*)?`, nil
 }
+
+// Validate validates a RouteCORSPolicy.
+func (c *RouteCORSPolicy) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if len(c.AllowOrigins) == 0 {
+		errs = errs.Also(apis.ErrMissingField("allowOrigins"))
+	}
+
+	for i, origin := range c.AllowOrigins {
+		if origin == "*" {
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || strings.TrimSuffix(u.Path, "/") != "" {
+			errs = errs.Also(apis.ErrInvalidArrayValue(origin, "allowOrigins", i))
+		}
+	}
+
+	for i, method := range c.AllowMethods {
+		if !headerNameRegexp.MatchString(method) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(method, "allowMethods", i))
+		}
+	}
+
+	for i, header := range c.AllowHeaders {
+		if !headerNameRegexp.MatchString(header) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(header, "allowHeaders", i))
+		}
+	}
+
+	for i, header := range c.ExposeHeaders {
+		if !headerNameRegexp.MatchString(header) {
+			errs = errs.Also(apis.ErrInvalidArrayValue(header, "exposeHeaders", i))
+		}
+	}
+
+	if c.MaxAgeSeconds < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(c.MaxAgeSeconds, "maxAgeSeconds"))
+	}
+
+	return errs
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/kf/pkg/kf/testutil"
@@ -85,6 +86,73 @@ func TestRouteValidation(t *testing.T) {
 			},
 			want: apis.ErrMissingField("spec.appName"),
 		},
+		"valid headers": {
+			route: &Route{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteSpec{
+					AppName: "some-app",
+					RouteSpecFields: RouteSpecFields{
+						Domain:  "example.com",
+						Headers: map[string]string{"x-beta": "true"},
+					},
+				},
+			},
+		},
+		"invalid header name": {
+			route: &Route{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteSpec{
+					AppName: "some-app",
+					RouteSpecFields: RouteSpecFields{
+						Domain:  "example.com",
+						Headers: map[string]string{"x beta": "true"},
+					},
+				},
+			},
+			want: apis.ErrInvalidKeyName("x beta", "spec.routeSpecFields.headers"),
+		},
+		"blank header value": {
+			route: &Route{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteSpec{
+					AppName: "some-app",
+					RouteSpecFields: RouteSpecFields{
+						Domain:  "example.com",
+						Headers: map[string]string{"x-beta": ""},
+					},
+				},
+			},
+			want: apis.ErrMissingField("spec.routeSpecFields.headers.x-beta"),
+		},
+		"strip path prefix and rewrite path": {
+			route: &Route{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteSpec{
+					AppName: "some-app",
+					RouteSpecFields: RouteSpecFields{
+						Domain:          "example.com",
+						Path:            "/service",
+						StripPathPrefix: true,
+						RewritePath:     "/new",
+					},
+				},
+			},
+			want: apis.ErrMultipleOneOf("spec.routeSpecFields.stripPathPrefix", "spec.routeSpecFields.rewritePath"),
+		},
+		"relative rewrite path": {
+			route: &Route{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteSpec{
+					AppName: "some-app",
+					RouteSpecFields: RouteSpecFields{
+						Domain:      "example.com",
+						Path:        "/service",
+						RewritePath: "new",
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("new", "spec.routeSpecFields.rewritePath"),
+		},
 		"missing domain": {
 			route: &Route{
 				ObjectMeta: goodObjMeta,
@@ -113,6 +181,19 @@ func TestRouteValidation(t *testing.T) {
 				Paths:   []string{"spec.routeSpecFields.www"},
 			},
 		},
+		"hostname isn't a DNS label": {
+			route: &Route{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteSpec{
+					AppName: "some-app",
+					RouteSpecFields: RouteSpecFields{
+						Hostname: "Some_Host",
+						Domain:   "domain.com",
+					},
+				},
+			},
+			want: ValidateDNS1123Label("Some_Host", "spec.routeSpecFields.hostname"),
+		},
 		"invalid path": {
 			route: &Route{
 				ObjectMeta: goodObjMeta,
@@ -304,6 +385,118 @@ func TestRouteClaimValidation(t *testing.T) {
 				Paths:   []string{"spec.routeSpecFields.}invalid{"},
 			},
 		},
+		"maintenance with custom page": {
+			route: &RouteClaim{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteClaimSpec{
+					RouteSpecFields: goodRouteSpec.RouteSpecFields,
+					Maintenance: &RouteMaintenance{
+						Page: "<h1>Back soon</h1>",
+					},
+				},
+			},
+		},
+		"maintenance page too large": {
+			route: &RouteClaim{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteClaimSpec{
+					RouteSpecFields: goodRouteSpec.RouteSpecFields,
+					Maintenance: &RouteMaintenance{
+						Page: strings.Repeat("a", MaxMaintenancePageSize+1),
+					},
+				},
+			},
+			want: &apis.FieldError{
+				Message: "page must be at most 16384 bytes",
+				Paths:   []string{"spec.maintenance.page"},
+			},
+		},
+		"cookie session affinity": {
+			route: &RouteClaim{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteClaimSpec{
+					RouteSpecFields: goodRouteSpec.RouteSpecFields,
+					SessionAffinity: &RouteSessionAffinity{
+						Type: SessionAffinityCookie,
+					},
+				},
+			},
+		},
+		"invalid session affinity": {
+			route: &RouteClaim{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteClaimSpec{
+					RouteSpecFields: goodRouteSpec.RouteSpecFields,
+					SessionAffinity: &RouteSessionAffinity{
+						Type: "header",
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("header", "spec.sessionAffinity.type"),
+		},
+		"valid CORS policy": {
+			route: &RouteClaim{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteClaimSpec{
+					RouteSpecFields: goodRouteSpec.RouteSpecFields,
+					CORSPolicy: &RouteCORSPolicy{
+						AllowOrigins:  []string{"https://app.example.com", "*"},
+						AllowMethods:  []string{"GET", "POST"},
+						MaxAgeSeconds: 60,
+					},
+				},
+			},
+		},
+		"invalid CORS policy": {
+			route: &RouteClaim{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteClaimSpec{
+					RouteSpecFields: goodRouteSpec.RouteSpecFields,
+					CORSPolicy: &RouteCORSPolicy{
+						AllowOrigins:  []string{"app.example.com", "https://app.example.com/path"},
+						AllowMethods:  []string{"GET POST"},
+						MaxAgeSeconds: -1,
+					},
+				},
+			},
+			want: apis.ErrInvalidArrayValue("app.example.com", "spec.corsPolicy.allowOrigins", 0).
+				Also(apis.ErrInvalidArrayValue("https://app.example.com/path", "spec.corsPolicy.allowOrigins", 1)).
+				Also(apis.ErrInvalidArrayValue("GET POST", "spec.corsPolicy.allowMethods", 0)).
+				Also(apis.ErrInvalidValue(int64(-1), "spec.corsPolicy.maxAgeSeconds")),
+		},
+		"CORS policy without origins": {
+			route: &RouteClaim{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteClaimSpec{
+					RouteSpecFields: goodRouteSpec.RouteSpecFields,
+					CORSPolicy:      &RouteCORSPolicy{},
+				},
+			},
+			want: apis.ErrMissingField("spec.corsPolicy.allowOrigins"),
+		},
+		"valid response headers": {
+			route: &RouteClaim{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteClaimSpec{
+					RouteSpecFields: goodRouteSpec.RouteSpecFields,
+					ResponseHeaders: map[string]string{"X-Frame-Options": "DENY"},
+				},
+			},
+		},
+		"invalid response headers": {
+			route: &RouteClaim{
+				ObjectMeta: goodObjMeta,
+				Spec: RouteClaimSpec{
+					RouteSpecFields: goodRouteSpec.RouteSpecFields,
+					ResponseHeaders: map[string]string{
+						"X Frame Options": "DENY",
+						"X-Empty":         "",
+					},
+				},
+			},
+			want: apis.ErrInvalidKeyName("X Frame Options", "spec.responseHeaders").
+				Also(apis.ErrMissingField("spec.responseHeaders.X-Empty")),
+		},
 		"fetching VirtualServices returns an error": {
 			setup: func(t *testing.T, fake *fake.FakeNetworkingV1alpha3) {
 				fake.AddReactor("get", "virtualservices", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
@@ -16,7 +16,10 @@ package v1alpha1
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/google/kf/pkg/internal/imageutil"
 	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -40,6 +43,13 @@ const (
 	BuildArgBuildpackBuilder  = "BUILDER_IMAGE"
 	BuildArgBuildpackRunImage = "RUN_IMAGE"
 	BuildArgDockerfile        = "DOCKERFILE"
+	BuildArgDockerfileArgs    = "BUILD_ARGS"
+	BuildArgLabels            = "LABELS"
+
+	// DockerfileBuildArgEnvPrefix is prepended to the name of each Dockerfile
+	// build arg when it's passed to the build as an environment variable so
+	// it can't clash with variables the build itself relies on.
+	DockerfileBuildArgEnvPrefix = "KF_BUILD_ARG_"
 )
 
 func (status *SourceStatus) manage() apis.ConditionManager {
@@ -67,6 +77,13 @@ func (status *SourceStatus) MarkBuildNotOwned(name string) {
 		fmt.Sprintf("There is an existing Build %q that we do not own.", name))
 }
 
+// MarkBuildQueued marks the Build as waiting for other builds in the space to
+// complete before it can be created.
+func (status *SourceStatus) MarkBuildQueued(position int) {
+	status.manage().MarkUnknown(SourceConditionBuildSucceeded, "Queued",
+		"Build queued at position %d waiting for concurrent builds in the space to finish", position)
+}
+
 // PropagateBuildStatus copies fields from the Build status to Space
 // and updates the readiness based on the current phase.
 func (status *SourceStatus) PropagateBuildStatus(build *build.Build) {
@@ -83,6 +100,7 @@ func (status *SourceStatus) PropagateBuildStatus(build *build.Build) {
 			switch condition.Status {
 			case corev1.ConditionTrue:
 				status.Image = GetBuildArg(build, BuildArgImage)
+				status.ImageDigest = GetBuildImageDigest(build)
 
 				status.manage().MarkTrue(SourceConditionBuildSucceeded)
 			case corev1.ConditionFalse:
@@ -103,6 +121,38 @@ func GetBuildArg(b *build.Build, key string) string {
 	return ""
 }
 
+// imageDigestPattern matches the image digests build steps write to their
+// termination message.
+var imageDigestPattern = regexp.MustCompile(`^sha256:[a-f0-9]{64}$`)
+
+// GetBuildImageDigest gets the digest of the image pushed by the build from
+// the termination message of its steps. An empty string is returned if no
+// step reported one.
+func GetBuildImageDigest(b *build.Build) string {
+	for i := len(b.Status.StepStates) - 1; i >= 0; i-- {
+		terminated := b.Status.StepStates[i].Terminated
+		if terminated == nil {
+			continue
+		}
+
+		if message := strings.TrimSpace(terminated.Message); imageDigestPattern.MatchString(message) {
+			return message
+		}
+	}
+
+	return ""
+}
+
+// ImageReference gets the reference apps should run. It pins Image to
+// ImageDigest if the digest is known, otherwise it returns Image.
+func (fields *SourceStatusFields) ImageReference() string {
+	if fields.ImageDigest == "" || fields.Image == "" {
+		return fields.Image
+	}
+
+	return imageutil.Repository(fields.Image) + "@" + fields.ImageDigest
+}
+
 func (status *SourceStatus) duck() *duckv1beta1.Status {
 	return &status.Status
 }
@@ -15,6 +15,7 @@
 package v1alpha1
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/kf/pkg/kf/testutil"
@@ -242,6 +243,95 @@ func TestSourceHappyPath(t *testing.T) {
 	apitesting.CheckConditionSucceeded(status.duck(), SourceConditionBuildSucceeded, t)
 	testutil.AssertEqual(t, "BuildName", "some-build-name", status.BuildName)
 	testutil.AssertEqual(t, "Image", "some-container-image", status.Image)
+	testutil.AssertEqual(t, "ImageDigest", "", status.ImageDigest)
+}
+
+func TestGetBuildImageDigest(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+
+	terminated := func(message string) corev1.ContainerState {
+		return corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{Message: message},
+		}
+	}
+
+	cases := map[string]struct {
+		stepStates []corev1.ContainerState
+		want       string
+	}{
+		"no steps": {
+			want: "",
+		},
+		"digest in last step": {
+			stepStates: []corev1.ContainerState{terminated(""), terminated(digest + "\n")},
+			want:       digest,
+		},
+		"digest in earlier step": {
+			stepStates: []corev1.ContainerState{terminated(digest), terminated("done")},
+			want:       digest,
+		},
+		"step still running": {
+			stepStates: []corev1.ContainerState{{Running: &corev1.ContainerStateRunning{}}},
+			want:       "",
+		},
+		"message isn't a digest": {
+			stepStates: []corev1.ContainerState{terminated("couldn't resolve digest")},
+			want:       "",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			b := happyBuild()
+			b.Status.StepStates = tc.stepStates
+
+			testutil.AssertEqual(t, "digest", tc.want, GetBuildImageDigest(b))
+
+			status := initTestSourceStatus(t)
+			status.PropagateBuildStatus(b)
+			testutil.AssertEqual(t, "ImageDigest", tc.want, status.ImageDigest)
+		})
+	}
+}
+
+func TestSourceStatusFields_ImageReference(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+
+	cases := map[string]struct {
+		fields SourceStatusFields
+		want   string
+	}{
+		"no image": {
+			fields: SourceStatusFields{ImageDigest: digest},
+			want:   "",
+		},
+		"no digest": {
+			fields: SourceStatusFields{Image: "gcr.io/my-project/app:1"},
+			want:   "gcr.io/my-project/app:1",
+		},
+		"tagged image": {
+			fields: SourceStatusFields{Image: "gcr.io/my-project/app:1", ImageDigest: digest},
+			want:   "gcr.io/my-project/app@" + digest,
+		},
+		"untagged image": {
+			fields: SourceStatusFields{Image: "mysql", ImageDigest: digest},
+			want:   "mysql@" + digest,
+		},
+		"registry with port": {
+			fields: SourceStatusFields{Image: "localhost:5000/app", ImageDigest: digest},
+			want:   "localhost:5000/app@" + digest,
+		},
+		"image already pinned": {
+			fields: SourceStatusFields{Image: "gcr.io/app@sha256:old", ImageDigest: digest},
+			want:   "gcr.io/app@" + digest,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "reference", tc.want, tc.fields.ImageReference())
+		})
+	}
 }
 
 func TestSourceStatus_lifecycle(t *testing.T) {
@@ -22,6 +22,50 @@ import (
 	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
 )
 
+const (
+	// BuildPriorityAnnotation is set on Apps to indicate how urgently the
+	// build for their Source should be scheduled when the Space limits the
+	// number of concurrent builds. It is copied to the Source on creation.
+	BuildPriorityAnnotation = "kf.dev/build-priority"
+
+	// BuildPriorityInteractive is used for builds a developer is waiting on
+	// e.g. pushes. It's the default if no priority is set.
+	BuildPriorityInteractive = "interactive"
+
+	// BuildPriorityScheduled is used for builds triggered by automation like
+	// mass rebuilds, they are run after all queued interactive builds.
+	BuildPriorityScheduled = "scheduled"
+
+	// BuildLogArchiveAnnotation is set on Sources to the URL of their build's
+	// logs once they've been archived to the Space's log bucket.
+	BuildLogArchiveAnnotation = "kf.dev/build-log-archive"
+)
+
+// Labels recording the provenance of images built by Kf.
+const (
+	// ImageSpaceLabel holds the space the image was built in.
+	ImageSpaceLabel = "dev.kf.image.space"
+
+	// ImageAppLabel holds the name of the App the image was built for.
+	ImageAppLabel = "dev.kf.image.app"
+
+	// ImageSourceLabel holds the source the image was built from, either the
+	// source image uploaded by the CLI, whose tag is the hash of the source,
+	// or the Git repository and revision.
+	ImageSourceLabel = "dev.kf.image.source"
+
+	// ImageBuilderLabel holds the builder image used to build the image.
+	ImageBuilderLabel = "dev.kf.image.builder"
+
+	// ImageKfVersionLabel holds the version of Kf that built the image.
+	ImageKfVersionLabel = "dev.kf.image.kf-version"
+
+	// ImageOCISourceLabel and ImageOCIRevisionLabel are the standard OCI
+	// labels for the repository and revision of images built from Git.
+	ImageOCISourceLabel   = "org.opencontainers.image.source"
+	ImageOCIRevisionLabel = "org.opencontainers.image.revision"
+)
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
@@ -64,6 +108,12 @@ type SourceSpec struct {
 	// Dockerfile defines Dockerfile information for source.
 	// +optional
 	Dockerfile SourceSpecDockerfile `json:"dockerfile,omitempty"`
+
+	// Git overrides where buildpack and Dockerfile builds get their source
+	// from, the repository is cloned instead of using the uploaded source
+	// image.
+	// +optional
+	Git *SourceSpecGit `json:"git,omitempty"`
 }
 
 // NeedsUpdateRequestsIncrement returns true if UpdateRequests needs to be
@@ -83,6 +133,16 @@ func (spec *SourceSpec) NeedsUpdateRequestsIncrement(old SourceSpec) bool {
 	return false
 }
 
+// SourceSpecGit defines a revision of a Git repository to build an App from.
+type SourceSpecGit struct {
+
+	// URL is the location of the Git repository.
+	URL string `json:"url"`
+
+	// Revision is the commit, branch, or tag to build.
+	Revision string `json:"revision"`
+}
+
 // SourceSpecContainerImage defines a container image for an App.
 type SourceSpecContainerImage struct {
 
@@ -126,6 +186,11 @@ type SourceSpecDockerfile struct {
 
 	// Image is the location to store the built image.
 	Image string `json:"image"`
+
+	// BuildArgs are passed to the Dockerfile build as --build-arg values.
+	// Values may be read from Secrets using ValueFrom so they don't appear in
+	// the Source or Build.
+	BuildArgs []corev1.EnvVar `json:"buildArgs,omitempty"`
 }
 
 // SourceStatus is the current configuration and running state for an App's Source.
@@ -144,6 +209,12 @@ type SourceStatusFields struct {
 	// +optional
 	Image string `json:"image,omitempty"`
 
+	// ImageDigest is the digest of the latest successfully built image. Apps
+	// are deployed by digest when it's known so tags that get overwritten
+	// can't change what runs.
+	// +optional
+	ImageDigest string `json:"imageDigest,omitempty"`
+
 	// BuildName is the name of the build that produced the image.
 	// +optional
 	BuildName string `json:"buildName,omitempty"`
@@ -173,3 +244,38 @@ func (spec *SourceSpec) IsBuildpackBuild() bool {
 func (spec *SourceSpec) IsDockerfileBuild() bool {
 	return spec.Dockerfile.Source != ""
 }
+
+// BuildPriority returns the scheduling priority of the Source's build, either
+// BuildPriorityInteractive or BuildPriorityScheduled.
+func (source *Source) BuildPriority() string {
+	if source.GetAnnotations()[BuildPriorityAnnotation] == BuildPriorityScheduled {
+		return BuildPriorityScheduled
+	}
+
+	return BuildPriorityInteractive
+}
+
+// SetBuildPriority sets the priority the next build of the App should be
+// scheduled with. BuildPriorityInteractive is the default so it removes the
+// annotation.
+func SetBuildPriority(obj metav1.Object, priority string) {
+	annotations := obj.GetAnnotations()
+
+	if priority == BuildPriorityInteractive {
+		delete(annotations, BuildPriorityAnnotation)
+		obj.SetAnnotations(annotations)
+		return
+	}
+
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[BuildPriorityAnnotation] = priority
+	obj.SetAnnotations(annotations)
+}
+
+// BuildLogArchive returns the URL the Source's build logs were archived to or
+// blank if they haven't been.
+func (source *Source) BuildLogArchive() string {
+	return source.GetAnnotations()[BuildLogArchiveAnnotation]
+}
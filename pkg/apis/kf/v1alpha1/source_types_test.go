@@ -69,3 +69,31 @@ func TestSourceSpec_NeedsUpdateRequestsIncrement(t *testing.T) {
 		})
 	}
 }
+
+func TestSource_BuildPriority(t *testing.T) {
+	cases := map[string]struct {
+		annotations map[string]string
+		expected    string
+	}{
+		"no annotations": {
+			expected: BuildPriorityInteractive,
+		},
+		"scheduled": {
+			annotations: map[string]string{BuildPriorityAnnotation: BuildPriorityScheduled},
+			expected:    BuildPriorityScheduled,
+		},
+		"unknown value": {
+			annotations: map[string]string{BuildPriorityAnnotation: "urgent"},
+			expected:    BuildPriorityInteractive,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			source := &Source{}
+			source.Annotations = tc.annotations
+
+			testutil.AssertEqual(t, "priority", tc.expected, source.BuildPriority())
+		})
+	}
+}
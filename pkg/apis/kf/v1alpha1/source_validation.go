@@ -16,7 +16,9 @@ package v1alpha1
 
 import (
 	"context"
+	"strings"
 
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
@@ -53,6 +55,14 @@ func (spec *SourceSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 		errs = errs.Also(spec.Dockerfile.Validate(ctx))
 	}
 
+	if spec.Git != nil {
+		if spec.IsContainerBuild() {
+			errs = errs.Also(apis.ErrMultipleOneOf("containerImage", "git"))
+		}
+
+		errs = errs.Also(spec.Git.Validate(ctx).ViaField("git"))
+	}
+
 	return errs
 }
 
@@ -98,6 +108,19 @@ func (buildpackBuild *SourceSpecBuildpackBuild) Validate(ctx context.Context) (e
 	return errs
 }
 
+// Validate makes sure that a SourceSpecGit is properly configured.
+func (git *SourceSpecGit) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if git.URL == "" {
+		errs = errs.Also(apis.ErrMissingField("url"))
+	}
+
+	if git.Revision == "" {
+		errs = errs.Also(apis.ErrMissingField("revision"))
+	}
+
+	return errs
+}
+
 // Validate makes sure that a SourceSpecDockerfile is properly configured.
 func (dockerfile *SourceSpecDockerfile) Validate(ctx context.Context) (errs *apis.FieldError) {
 	if dockerfile.Image == "" {
@@ -112,5 +135,27 @@ func (dockerfile *SourceSpecDockerfile) Validate(ctx context.Context) (errs *api
 		errs = errs.Also(apis.ErrMissingField("source"))
 	}
 
+	seen := make(map[string]bool)
+	for i, arg := range dockerfile.BuildArgs {
+		// Names are expanded by a shell in the build so they're restricted
+		// to identifiers.
+		if msgs := validation.IsCIdentifier(arg.Name); len(msgs) > 0 {
+			fe := apis.ErrInvalidArrayValue(arg.Name, "buildArgs", i)
+			fe.Details = strings.Join(msgs, ", ")
+			errs = errs.Also(fe)
+		} else if seen[arg.Name] {
+			fe := apis.ErrInvalidArrayValue(arg.Name, "buildArgs", i)
+			fe.Details = "build arg is declared more than once"
+			errs = errs.Also(fe)
+		}
+		seen[arg.Name] = true
+
+		if arg.ValueFrom != nil && (arg.ValueFrom.SecretKeyRef == nil || arg.Value != "") {
+			fe := apis.ErrInvalidArrayValue(arg.Name, "buildArgs", i)
+			fe.Details = "build args can only be read from a literal value or a Secret"
+			errs = errs.Also(fe)
+		}
+	}
+
 	return errs
 }
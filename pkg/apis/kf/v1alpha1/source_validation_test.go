@@ -19,7 +19,9 @@ import (
 	"testing"
 
 	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
@@ -96,6 +98,41 @@ func TestSource_Validate(t *testing.T) {
 			},
 			want: apis.ErrMissingField("spec.stack"),
 		},
+		"valid git": {
+			spec: Source{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: SourceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Git:            &SourceSpecGit{URL: "https://github.com/some/repo", Revision: "abc123"},
+				},
+			},
+		},
+		"invalid git": {
+			spec: Source{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: SourceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Git:            &SourceSpecGit{URL: "https://github.com/some/repo"},
+				},
+			},
+			want: apis.ErrMissingField("spec.git.revision"),
+		},
+		"git with containerImage": {
+			spec: Source{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "valid",
+				},
+				Spec: SourceSpec{
+					ContainerImage: goodContainerImage,
+					Git:            &SourceSpecGit{URL: "https://github.com/some/repo", Revision: "abc123"},
+				},
+			},
+			want: apis.ErrMultipleOneOf("spec.containerImage", "spec.git"),
+		},
 	}
 
 	for tn, tc := range cases {
@@ -202,6 +239,71 @@ func TestSourceSpecDockerfile_Valdiate(t *testing.T) {
 			},
 			want: apis.ErrMissingField("image"),
 		},
+		"valid build args": {
+			spec: SourceSpecDockerfile{
+				Image:  "some-image",
+				Path:   "some-path",
+				Source: "some-source",
+				BuildArgs: []corev1.EnvVar{
+					{Name: "VERSION", Value: "1.2.3"},
+					{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "creds"},
+							Key:                  "token",
+						},
+					}},
+				},
+			},
+		},
+		"invalid build arg name": {
+			spec: SourceSpecDockerfile{
+				Image:     "some-image",
+				Path:      "some-path",
+				Source:    "some-source",
+				BuildArgs: []corev1.EnvVar{{Name: "NOT-VALID", Value: "x"}},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidArrayValue("NOT-VALID", "buildArgs", 0)
+				fe.Details = validation.IsCIdentifier("NOT-VALID")[0]
+				return fe
+			}(),
+		},
+		"duplicate build arg": {
+			spec: SourceSpecDockerfile{
+				Image:  "some-image",
+				Path:   "some-path",
+				Source: "some-source",
+				BuildArgs: []corev1.EnvVar{
+					{Name: "VERSION", Value: "1"},
+					{Name: "VERSION", Value: "2"},
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidArrayValue("VERSION", "buildArgs", 1)
+				fe.Details = "build arg is declared more than once"
+				return fe
+			}(),
+		},
+		"build arg from config map": {
+			spec: SourceSpecDockerfile{
+				Image:  "some-image",
+				Path:   "some-path",
+				Source: "some-source",
+				BuildArgs: []corev1.EnvVar{
+					{Name: "VERSION", ValueFrom: &corev1.EnvVarSource{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "config"},
+							Key:                  "version",
+						},
+					}},
+				},
+			},
+			want: func() *apis.FieldError {
+				fe := apis.ErrInvalidArrayValue("VERSION", "buildArgs", 0)
+				fe.Details = "build args can only be read from a literal value or a Secret"
+				return fe
+			}(),
+		},
 	}
 
 	for tn, tc := range cases {
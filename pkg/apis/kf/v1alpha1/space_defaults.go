@@ -24,7 +24,8 @@ import (
 
 // TODO(#396): We should pull these from a ConfigMap
 const (
-	// DefaultBuilderImage contains the default buildpack builder image.
+	// DefaultBuilderImage contains the buildpack builder image used when
+	// neither the space nor the cluster's defaults set one.
 	DefaultBuilderImage = "gcr.io/kf-releases/buildpack-builder:latest"
 
 	// DefaultDomainTemplate contains the default domain template. It should
@@ -53,9 +54,9 @@ func (k *SpaceSpecSecurity) SetDefaults(ctx context.Context) {
 
 // SetDefaults implements apis.Defaultable
 func (k *SpaceSpecBuildpackBuild) SetDefaults(ctx context.Context) {
-	if k.BuilderImage == "" {
-		k.BuilderImage = DefaultBuilderImage
-	}
+	// The builder image and container registry are left blank so the
+	// controller can fill them in from the cluster's defaults, see
+	// SpaceStatus.PropagateBuildpackBuildDefaults.
 }
 
 // SetDefaults implements apis.Defaultable
@@ -70,9 +71,24 @@ func (k *SpaceSpecExecution) SetDefaults(ctx context.Context, name string) {
 		)
 	}
 
-	k.Domains = []SpaceDomain(algorithms.Dedupe(
-		SpaceDomains(k.Domains),
-	).(SpaceDomains))
+	// We don't want to lose default information when removing duplicates.
+	defaults := make(map[string]bool)
+	hasDefault := false
+	for _, domain := range k.Domains {
+		defaults[domain.Domain] = defaults[domain.Domain] || domain.Default
+		hasDefault = hasDefault || domain.Default
+	}
+
+	// Spaces must have a default domain, use the first one listed if none was
+	// chosen.
+	if !hasDefault {
+		defaults[k.Domains[0].Domain] = true
+	}
+
+	k.Domains = algorithms.Dedupe(k.Domains, CompareSpaceDomains)
+	for i := range k.Domains {
+		k.Domains[i].Default = defaults[k.Domains[i].Domain]
+	}
 }
 
 // DefaultDomain gets the default domain to use for spaces from the context.
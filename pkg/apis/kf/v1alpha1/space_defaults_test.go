@@ -48,11 +48,58 @@ func ExampleSpace_SetDefaults() {
 		domainNames = append(domainNames, domain.Domain)
 	}
 
-	fmt.Println("Builder:", space.Spec.BuildpackBuild.BuilderImage)
 	fmt.Println("Domains:", strings.Join(domainNames, ", "))
 
-	// Output: Builder: gcr.io/kf-releases/buildpack-builder:latest
-	// Domains: *mynamespace.custom.example.com
+	// Output: Domains: *mynamespace.custom.example.com
+}
+
+func ExampleSpace_EffectiveBuilderImage() {
+	space := Space{}
+	fmt.Println("Built-in:", space.EffectiveBuilderImage())
+
+	space.Status.BuildpackBuild.BuilderImage = "gcr.io/cluster-builder"
+	fmt.Println("Cluster:", space.EffectiveBuilderImage())
+
+	space.Spec.BuildpackBuild.BuilderImage = "gcr.io/space-builder"
+	fmt.Println("Space:", space.EffectiveBuilderImage())
+
+	// Output: Built-in: gcr.io/kf-releases/buildpack-builder:latest
+	// Cluster: gcr.io/cluster-builder
+	// Space: gcr.io/space-builder
+}
+
+func ExampleSpace_EffectiveBuilderImage_customBuildpacks() {
+	space := Space{}
+	space.Spec.BuildpackBuild.BuilderImage = "gcr.io/space-builder"
+	space.Spec.BuildpackBuild.Buildpacks = []SpaceBuildpack{
+		{Name: "node", Git: "https://github.com/example/node-buildpack@v1"},
+	}
+	fmt.Println("Composing:", space.EffectiveBuilderImage())
+
+	space.Status.CustomBuilder.Image = "gcr.io/project/kf-builder-space:1234"
+	fmt.Println("Composed:", space.EffectiveBuilderImage())
+	fmt.Println("Base:", space.BaseBuilderImage())
+
+	// Output: Composing: gcr.io/space-builder
+	// Composed: gcr.io/project/kf-builder-space:1234
+	// Base: gcr.io/space-builder
+}
+
+func ExampleSpaceBuildpack_GitURLAndRef() {
+	for _, git := range []string{
+		"https://github.com/example/node-buildpack@v1.2.0",
+		"https://github.com/example/node-buildpack",
+		"git@github.com:example/node-buildpack.git",
+		"git@github.com:example/node-buildpack.git@feature/http2",
+	} {
+		url, ref := (&SpaceBuildpack{Git: git}).GitURLAndRef()
+		fmt.Printf("%s %q\n", url, ref)
+	}
+
+	// Output: https://github.com/example/node-buildpack "v1.2.0"
+	// https://github.com/example/node-buildpack ""
+	// git@github.com:example/node-buildpack.git ""
+	// git@github.com:example/node-buildpack.git "feature/http2"
 }
 
 func ExampleSpaceSpecExecution_SetDefaults_dedupe() {
@@ -82,6 +129,30 @@ func ExampleSpaceSpecExecution_SetDefaults_dedupe() {
 
 }
 
+func ExampleSpaceSpecExecution_SetDefaults_firstDomainDefault() {
+	space := Space{}
+	space.Spec.Execution = SpaceSpecExecution{
+		Domains: []SpaceDomain{
+			{Domain: "other-example.com"},
+			{Domain: "example.com"},
+		},
+	}
+	space.SetDefaults(context.Background())
+
+	var domainNames []string
+	for _, domain := range space.Spec.Execution.Domains {
+		if domain.Default {
+			domainNames = append(domainNames, "*"+domain.Domain)
+			continue
+		}
+		domainNames = append(domainNames, domain.Domain)
+	}
+
+	fmt.Println(strings.Join(domainNames, ", "))
+
+	// Output: example.com, *other-example.com
+}
+
 func ExampleSpaceSpecSecurity_SetDefaults_dedupe() {
 	space := Space{}
 	space.Spec.Security = SpaceSpecSecurity{
@@ -16,11 +16,14 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strings"
 
+	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
 	v1 "k8s.io/api/core/v1"
 	rv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"knative.dev/pkg/apis"
+	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
 	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
 )
 
@@ -48,6 +51,15 @@ const (
 	// SpaceConditionLimitRangeReady is set when the limit range is
 	// ready.
 	SpaceConditionLimitRangeReady apis.ConditionType = "LimitRangeReady"
+	// SpaceConditionNoDrift is set when the namespace matches the
+	// requirements of the space that Kf doesn't enforce itself. It's
+	// informational and doesn't affect readiness.
+	SpaceConditionNoDrift apis.ConditionType = "NoDrift"
+	// SpaceConditionCustomBuilderReady is set when the builder composed with
+	// the space's custom buildpacks is up to date. It's informational and
+	// doesn't affect readiness, builds use the last composed builder or the
+	// base builder until a new one is ready.
+	SpaceConditionCustomBuilderReady apis.ConditionType = "CustomBuilderReady"
 )
 
 func (status *SpaceStatus) manage() apis.ConditionManager {
@@ -105,6 +117,12 @@ func (status *SpaceStatus) MarkLimitRangeNotOwned(name string) {
 		fmt.Sprintf("There is an existing limitrange %q that we do not own.", name))
 }
 
+// MarkDeleting notes that the Space is being deleted and is waiting for the
+// cleanup described by the message.
+func (status *SpaceStatus) MarkDeleting(message string) {
+	status.manage().MarkFalse(SpaceConditionReady, "Deleting", "%s", message)
+}
+
 // PropagateNamespaceStatus copies fields from the Namespace status to Space
 // and updates the readiness based on the current phase.
 func (status *SpaceStatus) PropagateNamespaceStatus(ns *v1.Namespace) {
@@ -147,6 +165,73 @@ func (status *SpaceStatus) PropagateLimitRangeStatus(limitRange *v1.LimitRange)
 	status.manage().MarkTrue(SpaceConditionLimitRangeReady)
 }
 
+// PropagateNetworkPolicyDrift records which of the space's required
+// NetworkPolicies are missing from its namespace.
+func (status *SpaceStatus) PropagateNetworkPolicyDrift(missing []string) {
+	if len(missing) == 0 {
+		status.manage().MarkTrue(SpaceConditionNoDrift)
+		return
+	}
+
+	status.manage().MarkFalse(SpaceConditionNoDrift, "MissingNetworkPolicies",
+		"Required NetworkPolicies are missing: %s", strings.Join(missing, ", "))
+}
+
+// PropagateBuildpackBuildDefaults records the build settings in effect for
+// the space. Fields the space doesn't set are taken from the cluster's
+// defaults.
+func (status *SpaceStatus) PropagateBuildpackBuildDefaults(spec SpaceSpecBuildpackBuild, defaults SpaceStatusBuildpackBuild) {
+	status.BuildpackBuild = defaults
+
+	if spec.BuilderImage != "" {
+		status.BuildpackBuild.BuilderImage = spec.BuilderImage
+	}
+
+	if spec.ContainerRegistry != "" {
+		status.BuildpackBuild.ContainerRegistry = spec.ContainerRegistry
+	}
+}
+
+// MarkNoCustomBuilder notes that the space has no custom buildpacks so builds
+// use the base builder.
+func (status *SpaceStatus) MarkNoCustomBuilder() {
+	status.CustomBuilder = SpaceStatusCustomBuilder{}
+	status.manage().MarkTrue(SpaceConditionCustomBuilderReady)
+}
+
+// MarkCustomBuilderNoRegistry notes that the custom builder can't be composed
+// because there's nowhere to push it.
+func (status *SpaceStatus) MarkCustomBuilderNoRegistry() {
+	status.manage().MarkFalse(SpaceConditionCustomBuilderReady, "NoContainerRegistry",
+		"A container registry is needed to store the builder with the space's buildpacks")
+}
+
+// MarkCustomBuilderBuildNotOwned marks the custom builder as not ready because
+// a Build with the same name exists that the Space doesn't own.
+func (status *SpaceStatus) MarkCustomBuilderBuildNotOwned(name string) {
+	status.manage().MarkFalse(SpaceConditionCustomBuilderReady, "NotOwned",
+		fmt.Sprintf("There is an existing Build %q that we do not own.", name))
+}
+
+// PropagateCustomBuilderStatus records the state of the Build composing the
+// custom builder. The image is recorded once the Build succeeds.
+func (status *SpaceStatus) PropagateCustomBuilderStatus(b *build.Build, image string) {
+	status.CustomBuilder.BuildName = b.Name
+
+	cond := b.Status.GetCondition(duckv1alpha1.ConditionSucceeded)
+	switch {
+	case cond == nil:
+		status.manage().MarkUnknown(SpaceConditionCustomBuilderReady, "Building", "Composing the builder")
+	case cond.IsTrue():
+		status.CustomBuilder.Image = image
+		status.manage().MarkTrue(SpaceConditionCustomBuilderReady)
+	case cond.IsFalse():
+		status.manage().MarkFalse(SpaceConditionCustomBuilderReady, "BuildFailed", "Composing the builder failed: %s", cond.Message)
+	default:
+		status.manage().MarkUnknown(SpaceConditionCustomBuilderReady, "Building", "Composing the builder")
+	}
+}
+
 func (status *SpaceStatus) duck() *duckv1beta1.Status {
 	return &status.Status
 }
@@ -18,10 +18,12 @@ import (
 	"testing"
 
 	"github.com/google/kf/pkg/kf/testutil"
+	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"knative.dev/pkg/apis"
 	"knative.dev/pkg/apis/duck"
+	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
 	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
 	apitesting "knative.dev/pkg/apis/testing"
 )
@@ -203,6 +205,126 @@ func TestPropagateNamespaceStatus_terminating(t *testing.T) {
 	apitesting.CheckConditionFailed(status.duck(), SpaceConditionNamespaceReady, t)
 }
 
+func TestSpaceStatus_MarkDeleting(t *testing.T) {
+	t.Parallel()
+	status := initTestStatus(t)
+
+	status.MarkDeleting("Waiting for 1 app(s) to be deleted")
+
+	apitesting.CheckConditionFailed(status.duck(), SpaceConditionReady, t)
+	testutil.AssertEqual(t, "reason", "Deleting", status.GetCondition(SpaceConditionReady).Reason)
+}
+
+func TestPropagateNetworkPolicyDrift(t *testing.T) {
+	t.Parallel()
+	status := initTestStatus(t)
+	status.PropagateDeveloperRoleStatus(nil)
+	status.PropagateAuditorRoleStatus(nil)
+	status.PropagateNamespaceStatus(&corev1.Namespace{Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}})
+	status.PropagateResourceQuotaStatus(&corev1.ResourceQuota{})
+	status.PropagateLimitRangeStatus(nil)
+
+	status.PropagateNetworkPolicyDrift([]string{"deny-all", "allow-ingress"})
+
+	// Drift is informational so the space stays ready.
+	apitesting.CheckConditionSucceeded(status.duck(), SpaceConditionReady, t)
+	apitesting.CheckConditionFailed(status.duck(), SpaceConditionNoDrift, t)
+	testutil.AssertEqual(t, "message",
+		"Required NetworkPolicies are missing: deny-all, allow-ingress",
+		status.GetCondition(SpaceConditionNoDrift).Message)
+
+	status.PropagateNetworkPolicyDrift(nil)
+	apitesting.CheckConditionSucceeded(status.duck(), SpaceConditionNoDrift, t)
+}
+
+func TestPropagateCustomBuilderStatus(t *testing.T) {
+	t.Parallel()
+	status := initTestStatus(t)
+	status.PropagateDeveloperRoleStatus(nil)
+	status.PropagateAuditorRoleStatus(nil)
+	status.PropagateNamespaceStatus(&corev1.Namespace{Status: corev1.NamespaceStatus{Phase: corev1.NamespaceActive}})
+	status.PropagateResourceQuotaStatus(&corev1.ResourceQuota{})
+	status.PropagateLimitRangeStatus(nil)
+
+	b := &build.Build{}
+	b.Name = "kf-builder-1234"
+
+	status.PropagateCustomBuilderStatus(b, "gcr.io/project/kf-builder:1234")
+	apitesting.CheckConditionOngoing(status.duck(), SpaceConditionCustomBuilderReady, t)
+	testutil.AssertEqual(t, "build name", "kf-builder-1234", status.CustomBuilder.BuildName)
+	testutil.AssertEqual(t, "image while building", "", status.CustomBuilder.Image)
+
+	b.Status.SetCondition(&duckv1alpha1.Condition{
+		Type:    duckv1alpha1.ConditionSucceeded,
+		Status:  corev1.ConditionFalse,
+		Message: "clone failed",
+	})
+	status.PropagateCustomBuilderStatus(b, "gcr.io/project/kf-builder:1234")
+
+	// The custom builder is informational so the space stays ready.
+	apitesting.CheckConditionSucceeded(status.duck(), SpaceConditionReady, t)
+	apitesting.CheckConditionFailed(status.duck(), SpaceConditionCustomBuilderReady, t)
+	testutil.AssertEqual(t, "message",
+		"Composing the builder failed: clone failed",
+		status.GetCondition(SpaceConditionCustomBuilderReady).Message)
+
+	b.Status.SetCondition(&duckv1alpha1.Condition{
+		Type:   duckv1alpha1.ConditionSucceeded,
+		Status: corev1.ConditionTrue,
+	})
+	status.PropagateCustomBuilderStatus(b, "gcr.io/project/kf-builder:1234")
+	apitesting.CheckConditionSucceeded(status.duck(), SpaceConditionCustomBuilderReady, t)
+	testutil.AssertEqual(t, "image", "gcr.io/project/kf-builder:1234", status.CustomBuilder.Image)
+
+	status.MarkNoCustomBuilder()
+	apitesting.CheckConditionSucceeded(status.duck(), SpaceConditionCustomBuilderReady, t)
+	testutil.AssertEqual(t, "custom builder", SpaceStatusCustomBuilder{}, status.CustomBuilder)
+}
+
+func TestPropagateBuildpackBuildDefaults(t *testing.T) {
+	t.Parallel()
+
+	defaults := SpaceStatusBuildpackBuild{
+		BuilderImage:      "gcr.io/cluster-builder",
+		ContainerRegistry: "gcr.io/cluster-registry",
+	}
+
+	cases := map[string]struct {
+		spec SpaceSpecBuildpackBuild
+		want SpaceStatusBuildpackBuild
+	}{
+		"space sets nothing": {
+			want: defaults,
+		},
+		"space sets registry": {
+			spec: SpaceSpecBuildpackBuild{ContainerRegistry: "gcr.io/space-registry"},
+			want: SpaceStatusBuildpackBuild{
+				BuilderImage:      "gcr.io/cluster-builder",
+				ContainerRegistry: "gcr.io/space-registry",
+			},
+		},
+		"space sets everything": {
+			spec: SpaceSpecBuildpackBuild{
+				BuilderImage:      "gcr.io/space-builder",
+				ContainerRegistry: "gcr.io/space-registry",
+			},
+			want: SpaceStatusBuildpackBuild{
+				BuilderImage:      "gcr.io/space-builder",
+				ContainerRegistry: "gcr.io/space-registry",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			status := initTestStatus(t)
+			status.PropagateBuildpackBuildDefaults(tc.spec, defaults)
+
+			testutil.AssertEqual(t, "buildpack build", tc.want, status.BuildpackBuild)
+		})
+	}
+}
+
 func TestPropagateResourceQuotaStatus(t *testing.T) {
 	t.Parallel()
 	status := initTestStatus(t)
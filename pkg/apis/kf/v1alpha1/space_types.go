@@ -14,6 +14,11 @@
 
 package v1alpha1
 
+import (
+	"fmt"
+	"strings"
+)
+
 import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 import corev1 "k8s.io/api/core/v1"
 import duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
@@ -57,6 +62,55 @@ type SpaceSpec struct {
 	// SpaceSpecResourceLimits contains definitions for resource usage limits.
 	// +optional
 	ResourceLimits SpaceSpecResourceLimits `json:"resourceLimits,omitempty"`
+
+	// Notifications send build and push events in the space to webhooks.
+	// +optional
+	Notifications []SpaceNotification `json:"notifications,omitempty"`
+}
+
+const (
+	// NotificationEventBuildSucceeded is sent when a Source finishes
+	// building.
+	NotificationEventBuildSucceeded = "build-succeeded"
+
+	// NotificationEventBuildFailed is sent when a Source fails to build.
+	NotificationEventBuildFailed = "build-failed"
+
+	// NotificationEventPushComplete is sent when an App becomes ready after
+	// being pushed or changed.
+	NotificationEventPushComplete = "push-complete"
+
+	// NotificationEventPushFailed is sent when an App fails to become ready.
+	NotificationEventPushFailed = "push-failed"
+)
+
+// NotificationEvents are the events that can be sent to webhooks.
+var NotificationEvents = []string{
+	NotificationEventBuildSucceeded,
+	NotificationEventBuildFailed,
+	NotificationEventPushComplete,
+	NotificationEventPushFailed,
+}
+
+// SpaceNotification posts events in the space to a webhook.
+type SpaceNotification struct {
+	// Webhook is the URL JSON payloads describing the events are posted to,
+	// e.g. a Slack incoming webhook.
+	Webhook string `json:"webhook"`
+
+	// Events are the names of the events sent to the webhook.
+	Events []string `json:"events"`
+}
+
+// Subscribed returns true if the event is sent to the webhook.
+func (n *SpaceNotification) Subscribed(event string) bool {
+	for _, e := range n.Events {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
 }
 
 // SpaceSpecSecurity holds fields for creating RBAC in the space.
@@ -67,10 +121,39 @@ type SpaceSpecSecurity struct {
 	// +optional
 	EnableDeveloperLogsAccess bool `json:"enableDeveloperLogsAccess,omitempty"`
 
+	// EnableSSH allows developers to open shells in their App instances
+	// with kf ssh.
+	// +optional
+	EnableSSH bool `json:"enableSSH,omitempty"`
+
 	// BuildServiceAccount sets the service account that will be propagated to
 	// all builds.
 	// +optional
 	BuildServiceAccount string `json:"buildServiceAccount,omitempty"`
+
+	// RequiredNetworkPolicies lists NetworkPolicies that must exist in the
+	// space's namespace. Kf doesn't create them, it reports drift if any are
+	// missing.
+	// +optional
+	RequiredNetworkPolicies []string `json:"requiredNetworkPolicies,omitempty"`
+
+	// Egress restricts the destinations outside the cluster that Apps in the
+	// space can reach.
+	// +optional
+	Egress SpaceEgressPolicy `json:"egress,omitempty"`
+}
+
+// SpaceEgressPolicy configures outbound traffic from Apps in a space.
+type SpaceEgressPolicy struct {
+	// Allow lists external destinations in HOST:PORT form that Apps may
+	// reach. HOST may start with a "*." wildcard.
+	// +optional
+	Allow []string `json:"allow,omitempty"`
+
+	// DenyAll blocks traffic to any external destination that isn't listed in
+	// Allow.
+	// +optional
+	DenyAll bool `json:"denyAll,omitempty"`
 }
 
 // SpaceSpecBuildpackBuild holds fields for managing building via buildpacks.
@@ -90,6 +173,61 @@ type SpaceSpecBuildpackBuild struct {
 	// +patchMergeKey=name
 	// +patchStrategy=merge
 	Env []corev1.EnvVar `json:"env,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+
+	// DefaultStack is the name of the cluster Stack buildpack apps in the
+	// space use if they don't choose one.
+	// +optional
+	DefaultStack string `json:"defaultStack,omitempty"`
+
+	// MaxConcurrentBuilds limits the number of builds that can run in the space
+	// at once. Builds over the limit are queued, interactive pushes ahead of
+	// scheduled rebuilds. Zero means there is no limit.
+	// +optional
+	MaxConcurrentBuilds int `json:"maxConcurrentBuilds,omitempty"`
+
+	// LogBucket is the URL of a Cloud Storage bucket e.g. gs://my-logs that
+	// the logs of completed builds are archived to so they can be read after
+	// the build's pod is gone. Logs aren't archived if it's blank.
+	// +optional
+	LogBucket string `json:"logBucket,omitempty"`
+
+	// Buildpacks are custom buildpacks added to the builder for the space.
+	// They're tried in order before the builder's own buildpacks. The
+	// controller composes a new builder whenever they change.
+	//
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Buildpacks []SpaceBuildpack `json:"buildpacks,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+}
+
+// SpaceBuildpack is a custom buildpack fetched from Git or a container image.
+// Exactly one of Git or Image is set.
+type SpaceBuildpack struct {
+	// Name identifies the buildpack within the space.
+	Name string `json:"name"`
+
+	// Git is the repository holding the buildpack in the form URL@REF, the
+	// default branch is used if there's no ref.
+	// +optional
+	Git string `json:"git,omitempty"`
+
+	// Image is a container image holding the buildpack at its root.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// GitURLAndRef splits Git into the repository URL and ref. The ref is blank
+// if none was given.
+func (b *SpaceBuildpack) GitURLAndRef() (string, string) {
+	i := strings.LastIndex(b.Git, "@")
+
+	// SSH URLs like git@github.com:org/repo.git use @ before the host.
+	if i < 0 || strings.Contains(b.Git[i+1:], ":") {
+		return b.Git, ""
+	}
+
+	return b.Git[:i], b.Git[i+1:]
 }
 
 // SpaceSpecExecution contains settings for the execution environment.
@@ -106,6 +244,85 @@ type SpaceSpecExecution struct {
 	// +patchMergeKey=name
 	// +patchStrategy=merge
 	Domains []SpaceDomain `json:"domains,omitempty" patchStrategy:"merge" patchMergeKey:"domain"`
+
+	// ResponseHeaders are set on every response served by Apps in the space,
+	// e.g. Strict-Transport-Security. Headers set on a route take precedence.
+	// +optional
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+
+	// DefaultHealthCheck is used by Apps in the space that don't set their own
+	// health check when they're pushed.
+	// +optional
+	DefaultHealthCheck SpaceDefaultHealthCheck `json:"defaultHealthCheck,omitempty"`
+
+	// ReservedHostnames can't be claimed by Apps in the space on any of its
+	// domains unless they're granted, e.g. api, www or admin.
+	// +optional
+	ReservedHostnames []SpaceReservedHostname `json:"reservedHostnames,omitempty"`
+
+	// SidecarResources overrides the resources requested by the Istio sidecar
+	// injected into App pods in the space.
+	// +optional
+	SidecarResources SpaceSidecarResources `json:"sidecarResources,omitempty"`
+}
+
+// SpaceSidecarResources holds the resources requested by the Istio sidecar in
+// App pods. Empty fields use Istio's defaults.
+type SpaceSidecarResources struct {
+	// CPU is the CPU requested by the sidecar, e.g. 50m.
+	// +optional
+	CPU string `json:"cpu,omitempty"`
+
+	// Memory is the memory requested by the sidecar, e.g. 64Mi.
+	// +optional
+	Memory string `json:"memory,omitempty"`
+}
+
+// SpaceReservedHostname is a hostname only the granted Apps may claim.
+type SpaceReservedHostname struct {
+	// Hostname is the reserved hostname.
+	Hostname string `json:"hostname"`
+
+	// AllowedApps are the names of the Apps that may claim the hostname.
+	// +optional
+	AllowedApps []string `json:"allowedApps,omitempty"`
+}
+
+// CheckHostname returns an error if the hostname is reserved in the space and
+// the App wasn't granted it.
+func (e *SpaceSpecExecution) CheckHostname(hostname, appName string) error {
+	for _, reserved := range e.ReservedHostnames {
+		if !strings.EqualFold(reserved.Hostname, hostname) {
+			continue
+		}
+
+		for _, allowed := range reserved.AllowedApps {
+			if allowed == appName {
+				return nil
+			}
+		}
+
+		return fmt.Errorf("the hostname %s is reserved in the space and App %s isn't allowed to use it", hostname, appName)
+	}
+
+	return nil
+}
+
+// SpaceDefaultHealthCheck holds the health check settings Apps in a space use
+// unless they choose their own. Empty fields use Kf's defaults.
+type SpaceDefaultHealthCheck struct {
+	// Type is the kind of health check, either port or http.
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// HTTPEndpoint is the path requested by http health checks.
+	// +optional
+	HTTPEndpoint string `json:"httpEndpoint,omitempty"`
+
+	// StartupTimeoutSeconds is how long an App has to pass its health check
+	// after it starts.
+	// +optional
+	StartupTimeoutSeconds int `json:"startupTimeoutSeconds,omitempty"`
 }
 
 // SpaceSpecResourceLimits contains definitions for resource usage limits.
@@ -132,6 +349,28 @@ type SpaceDomain struct {
 	// specified. There can only be a single default set to true per space.
 	// NOTE: This may change in the future.
 	Default bool `json:"default,omitempty"`
+
+	// TLSSecretName is the name of a Secret in the Istio ingress gateway's
+	// namespace holding the certificate and key used to serve HTTPS for the
+	// domain and its subdomains.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// RedirectHTTPS redirects HTTP requests for the domain and its subdomains
+	// to HTTPS. It requires TLSSecretName to be set.
+	// +optional
+	RedirectHTTPS bool `json:"redirectHTTPS,omitempty"`
+}
+
+// ServesHTTPS returns true if the domain has a certificate to serve HTTPS.
+func (d *SpaceDomain) ServesHTTPS() bool {
+	return d.TLSSecretName != ""
+}
+
+// SpaceGatewayName returns the name of the Istio Gateway in the Kf namespace
+// that serves HTTPS and redirects for the named space's domains.
+func SpaceGatewayName(spaceName string) string {
+	return "space-gateway-" + spaceName
 }
 
 // SpaceStatus represents information about the status of a Space.
@@ -140,6 +379,71 @@ type SpaceStatus struct {
 	duckv1beta1.Status `json:",inline"`
 
 	Quota corev1.ResourceQuotaStatus `json:"quota,omitempty"`
+
+	// BuildpackBuild holds the build settings in effect for the space, fields
+	// the space doesn't set are filled in from the cluster's defaults.
+	BuildpackBuild SpaceStatusBuildpackBuild `json:"buildpackBuild,omitempty"`
+
+	// CustomBuilder holds the builder composed with the space's custom
+	// buildpacks.
+	CustomBuilder SpaceStatusCustomBuilder `json:"customBuilder,omitempty"`
+}
+
+// SpaceStatusBuildpackBuild holds the effective settings for buildpack builds
+// in a space.
+type SpaceStatusBuildpackBuild struct {
+	// BuilderImage is the buildpacks.io builder image builds use.
+	BuilderImage string `json:"builderImage,omitempty"`
+
+	// ContainerRegistry is the container registry builds are stored in.
+	ContainerRegistry string `json:"containerRegistry,omitempty"`
+}
+
+// SpaceStatusCustomBuilder holds the state of the builder composed with a
+// space's custom buildpacks.
+type SpaceStatusCustomBuilder struct {
+	// Image is the latest builder that was composed successfully.
+	Image string `json:"image,omitempty"`
+
+	// BuildName is the name of the Build composing the builder for the
+	// current buildpacks.
+	BuildName string `json:"buildName,omitempty"`
+}
+
+// EffectiveBuilderImage gets the builder image buildpack builds in the space
+// use. If the space has custom buildpacks it's the last builder composed with
+// them, otherwise it's the BaseBuilderImage.
+func (k *Space) EffectiveBuilderImage() string {
+	if len(k.Spec.BuildpackBuild.Buildpacks) > 0 && k.Status.CustomBuilder.Image != "" {
+		return k.Status.CustomBuilder.Image
+	}
+
+	return k.BaseBuilderImage()
+}
+
+// BaseBuilderImage gets the builder image the space's custom buildpacks are
+// added to. The space's own value wins over the one recorded by the controller
+// from the cluster's defaults.
+func (k *Space) BaseBuilderImage() string {
+	switch {
+	case k.Spec.BuildpackBuild.BuilderImage != "":
+		return k.Spec.BuildpackBuild.BuilderImage
+	case k.Status.BuildpackBuild.BuilderImage != "":
+		return k.Status.BuildpackBuild.BuilderImage
+	default:
+		return DefaultBuilderImage
+	}
+}
+
+// EffectiveContainerRegistry gets the container registry builds in the space
+// are stored in. The space's own value wins over the one recorded by the
+// controller from the cluster's defaults. It's blank if neither is set.
+func (k *Space) EffectiveContainerRegistry() string {
+	if k.Spec.BuildpackBuild.ContainerRegistry != "" {
+		return k.Spec.BuildpackBuild.ContainerRegistry
+	}
+
+	return k.Status.BuildpackBuild.ContainerRegistry
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -16,7 +16,16 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
@@ -48,24 +57,139 @@ func (s *SpaceSpec) Validate(ctx context.Context) (errs *apis.FieldError) {
 	errs = errs.Also(s.BuildpackBuild.Validate(ctx).ViaField("buildpackBuild"))
 	errs = errs.Also(s.Execution.Validate(ctx).ViaField("execution"))
 	errs = errs.Also(s.ResourceLimits.Validate(ctx).ViaField("resourceLimits"))
+	errs = errs.Also(ValidateNotifications(s.Notifications).ViaField("notifications"))
 
 	return errs
 }
 
 // Validate makes sure that SpaceSpecSecurity is properly configured.
 func (s *SpaceSpecSecurity) Validate(ctx context.Context) (errs *apis.FieldError) {
-	// XXX: no validation
+	errs = errs.Also(s.Egress.Validate(ctx).ViaField("egress"))
+
+	return errs
+}
+
+// Validate makes sure that SpaceEgressPolicy is properly configured.
+func (s *SpaceEgressPolicy) Validate(ctx context.Context) (errs *apis.FieldError) {
+	for i, dest := range s.Allow {
+		if _, _, err := ParseEgressDestination(dest); err != nil {
+			fe := apis.ErrInvalidArrayValue(dest, "allow", i)
+			fe.Details = err.Error()
+			errs = errs.Also(fe)
+		}
+	}
+
 	return errs
 }
 
+// ParseEgressDestination splits an egress destination in HOST:PORT form into
+// its host and port. The host may start with a "*." wildcard.
+func ParseEgressDestination(dest string) (string, int32, error) {
+	host, portStr, err := net.SplitHostPort(dest)
+	if err != nil {
+		return "", 0, fmt.Errorf("destination must be in the form HOST:PORT")
+	}
+
+	var hostErrs []string
+	if strings.HasPrefix(host, "*.") {
+		hostErrs = validation.IsWildcardDNS1123Subdomain(host)
+	} else {
+		hostErrs = validation.IsDNS1123Subdomain(host)
+	}
+	if len(hostErrs) > 0 {
+		return "", 0, fmt.Errorf("invalid host %q: %s", host, strings.Join(hostErrs, ", "))
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil || len(validation.IsValidPortNum(port)) > 0 {
+		return "", 0, fmt.Errorf("invalid port %q: must be between 1 and 65535", portStr)
+	}
+
+	return host, int32(port), nil
+}
+
 // Validate makes sure that SpaceSpecBuildpackBuild is properly configured.
 func (s *SpaceSpecBuildpackBuild) Validate(ctx context.Context) (errs *apis.FieldError) {
-	if s.BuilderImage == "" {
-		errs = errs.Also(apis.ErrMissingField("builderImage"))
+	// The builder image and container registry are optional, the cluster's
+	// defaults are used for the ones that aren't set.
+
+	if s.MaxConcurrentBuilds < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.MaxConcurrentBuilds, "maxConcurrentBuilds"))
 	}
 
-	if s.ContainerRegistry == "" {
-		errs = errs.Also(apis.ErrMissingField("containerRegistry"))
+	if s.LogBucket != "" {
+		if err := ValidateBuildLogBucket(s.LogBucket); err != nil {
+			fe := apis.ErrInvalidValue(s.LogBucket, "logBucket")
+			fe.Details = err.Error()
+			errs = errs.Also(fe)
+		}
+	}
+
+	errs = errs.Also(ValidateSpaceBuildpacks(s.Buildpacks).ViaField("buildpacks"))
+
+	return errs
+}
+
+// bucketNamePattern matches valid Cloud Storage bucket names.
+var bucketNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{1,220}[a-z0-9]$`)
+
+// ValidateBuildLogBucket checks that bucket is the URL of a Cloud Storage
+// bucket e.g. gs://my-logs.
+func ValidateBuildLogBucket(bucket string) error {
+	if !strings.HasPrefix(bucket, "gs://") {
+		return fmt.Errorf("bucket %q must be a Cloud Storage URL starting with gs://", bucket)
+	}
+
+	name := strings.TrimPrefix(bucket, "gs://")
+	switch {
+	case name == "":
+		return errors.New("bucket name can't be empty")
+	case strings.Contains(name, "/"):
+		return fmt.Errorf("bucket %q can't contain a path", bucket)
+	case !bucketNamePattern.MatchString(name):
+		return fmt.Errorf("invalid bucket name %q", name)
+	}
+
+	return nil
+}
+
+// maxBuildpackNameLength keeps the names of the steps that fetch buildpacks
+// within the limits of container names.
+const maxBuildpackNameLength = 50
+
+// ValidateSpaceBuildpacks checks that each custom buildpack has a unique name
+// and is fetched from exactly one of Git or an image.
+func ValidateSpaceBuildpacks(buildpacks []SpaceBuildpack) (errs *apis.FieldError) {
+	seen := make(map[string]bool)
+	for i, bp := range buildpacks {
+		switch {
+		case bp.Name == "":
+			errs = errs.Also(apis.ErrMissingField("name").ViaIndex(i))
+		case len(bp.Name) > maxBuildpackNameLength || len(validation.IsDNS1123Label(bp.Name)) > 0:
+			fe := apis.ErrInvalidValue(bp.Name, "name").ViaIndex(i)
+			fe.Details = fmt.Sprintf("names must be DNS labels of at most %d characters", maxBuildpackNameLength)
+			errs = errs.Also(fe)
+		case seen[bp.Name]:
+			errs = errs.Also((&apis.FieldError{
+				Message: "duplicate buildpack",
+				Paths:   []string{"name"},
+				Details: fmt.Sprintf("%s is listed more than once", bp.Name),
+			}).ViaIndex(i))
+		}
+		seen[bp.Name] = true
+
+		switch {
+		case bp.Git == "" && bp.Image == "":
+			errs = errs.Also(apis.ErrMissingOneOf("git", "image").ViaIndex(i))
+		case bp.Git != "" && bp.Image != "":
+			errs = errs.Also(apis.ErrMultipleOneOf("git", "image").ViaIndex(i))
+		case bp.Git != "":
+			if url, _ := bp.GitURLAndRef(); url == "" || strings.ContainsAny(bp.Git, " \t\n") {
+				errs = errs.Also(apis.ErrInvalidValue(bp.Git, "git").ViaIndex(i))
+			}
+		case strings.ContainsAny(bp.Image, " \t\n"):
+			errs = errs.Also(apis.ErrInvalidValue(bp.Image, "image").ViaIndex(i))
+		}
 	}
 
 	return errs
@@ -73,10 +197,43 @@ func (s *SpaceSpecBuildpackBuild) Validate(ctx context.Context) (errs *apis.Fiel
 
 // Validate makes sure that SpaceSpecExecution is properly configured.
 func (s *SpaceSpecExecution) Validate(ctx context.Context) (errs *apis.FieldError) {
+	errs = errs.Also(ValidateResponseHeaders(s.ResponseHeaders).ViaField("responseHeaders"))
+	errs = errs.Also(s.DefaultHealthCheck.Validate(ctx).ViaField("defaultHealthCheck"))
+	errs = errs.Also(ValidateReservedHostnames(s.ReservedHostnames).ViaField("reservedHostnames"))
+	errs = errs.Also(s.SidecarResources.Validate(ctx).ViaField("sidecarResources"))
+
 	if len(s.Domains) == 0 {
 		return errs.Also(apis.ErrMissingField("domains"))
 	}
 
+	seen := make(map[string]bool)
+	for i, d := range s.Domains {
+		if err := ValidateSpaceDomain(d.Domain); err != nil {
+			fe := apis.ErrInvalidArrayValue(d.Domain, "domains", i)
+			fe.Details = err.Error()
+			errs = errs.Also(fe)
+		}
+
+		if seen[d.Domain] {
+			errs = errs.Also((&apis.FieldError{
+				Message: "duplicate domain",
+				Paths:   []string{"domain"},
+				Details: fmt.Sprintf("%s is listed more than once", d.Domain),
+			}).ViaFieldIndex("domains", i))
+		}
+		seen[d.Domain] = true
+
+		if d.RedirectHTTPS && !d.ServesHTTPS() {
+			errs = errs.Also(
+				(&apis.FieldError{
+					Paths:   []string{"redirectHTTPS"},
+					Message: "HTTPS redirects require a TLS secret",
+					Details: "set tlsSecretName before enabling redirectHTTPS",
+				}).ViaFieldIndex("domains", i),
+			)
+		}
+	}
+
 	lastDefault := -1
 	for i, d := range s.Domains {
 		if !d.Default {
@@ -108,8 +265,127 @@ func (s *SpaceSpecExecution) Validate(ctx context.Context) (errs *apis.FieldErro
 	return errs
 }
 
+// ValidateSpaceDomain checks that the domain can be used by a space. Routes
+// are created on subdomains of the space's domains so the domain itself can't
+// be a wildcard.
+func ValidateSpaceDomain(domain string) error {
+	switch {
+	case domain == "":
+		return errors.New("domain can't be empty")
+	case strings.HasPrefix(domain, "*."):
+		return fmt.Errorf("domain can't be a wildcard, use %q which includes its subdomains", strings.TrimPrefix(domain, "*."))
+	case strings.Contains(domain, "*"):
+		return errors.New("domain can't contain wildcards")
+	}
+
+	if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+		return fmt.Errorf("invalid domain %q: %s", domain, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
+
+// Validate makes sure that SpaceDefaultHealthCheck is properly configured.
+func (s *SpaceDefaultHealthCheck) Validate(ctx context.Context) (errs *apis.FieldError) {
+	switch s.Type {
+	case "", "port":
+		if s.HTTPEndpoint != "" {
+			errs = errs.Also(&apis.FieldError{
+				Paths:   []string{"httpEndpoint"},
+				Message: "HTTP endpoints can only be used with http health checks",
+			})
+		}
+	case "http":
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(s.Type, "type"))
+	}
+
+	if s.StartupTimeoutSeconds < 0 {
+		errs = errs.Also(apis.ErrInvalidValue(s.StartupTimeoutSeconds, "startupTimeoutSeconds"))
+	}
+
+	return errs
+}
+
+// Validate makes sure that SpaceSidecarResources holds valid quantities.
+func (s *SpaceSidecarResources) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if s.CPU != "" {
+		if _, err := resource.ParseQuantity(s.CPU); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(s.CPU, "cpu"))
+		}
+	}
+
+	if s.Memory != "" {
+		if _, err := resource.ParseQuantity(s.Memory); err != nil {
+			errs = errs.Also(apis.ErrInvalidValue(s.Memory, "memory"))
+		}
+	}
+
+	return errs
+}
+
 // Validate makes sure that SpaceSpecResourceLimits is properly configured.
 func (s *SpaceSpecResourceLimits) Validate(ctx context.Context) (errs *apis.FieldError) {
 	// XXX: no validation
 	return errs
 }
+
+// ValidateReservedHostnames checks that reserved hostnames are valid DNS
+// labels and are only reserved once.
+func ValidateReservedHostnames(reserved []SpaceReservedHostname) (errs *apis.FieldError) {
+	seen := make(map[string]bool)
+	for i, r := range reserved {
+		hostname := strings.ToLower(r.Hostname)
+		switch {
+		case hostname == "":
+			errs = errs.Also(apis.ErrMissingField("hostname").ViaIndex(i))
+		case len(validation.IsDNS1123Label(hostname)) > 0:
+			errs = errs.Also(apis.ErrInvalidValue(r.Hostname, "hostname").ViaIndex(i))
+		case seen[hostname]:
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("hostname %s is reserved more than once", r.Hostname),
+				Paths:   []string{"hostname"},
+			}).ViaIndex(i)
+		}
+		seen[hostname] = true
+	}
+
+	return errs
+}
+
+// ValidateNotifications checks that notifications post to HTTP(S) webhooks,
+// each webhook is only configured once and the events are known.
+func ValidateNotifications(notifications []SpaceNotification) (errs *apis.FieldError) {
+	known := make(map[string]bool)
+	for _, event := range NotificationEvents {
+		known[event] = true
+	}
+
+	seen := make(map[string]bool)
+	for i, n := range notifications {
+		switch u, err := url.Parse(n.Webhook); {
+		case n.Webhook == "":
+			errs = errs.Also(apis.ErrMissingField("webhook").ViaIndex(i))
+		case err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "":
+			errs = errs.Also(apis.ErrInvalidValue(n.Webhook, "webhook").ViaIndex(i))
+		case seen[n.Webhook]:
+			errs = errs.Also(&apis.FieldError{
+				Message: fmt.Sprintf("webhook %s is configured more than once", n.Webhook),
+				Paths:   []string{"webhook"},
+			}).ViaIndex(i)
+		}
+		seen[n.Webhook] = true
+
+		if len(n.Events) == 0 {
+			errs = errs.Also(apis.ErrMissingField("events").ViaIndex(i))
+		}
+
+		for j, event := range n.Events {
+			if !known[event] {
+				errs = errs.Also(apis.ErrInvalidArrayValue(event, "events", j).ViaIndex(i))
+			}
+		}
+	}
+
+	return errs
+}
@@ -16,10 +16,13 @@ package v1alpha1
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/google/kf/pkg/kf/testutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
@@ -68,41 +71,73 @@ func TestSpaceValidation(t *testing.T) {
 			},
 			want: apis.ErrInvalidValue("default", "name"),
 		},
-		"no container registry": {
+		"no container registry or builder image": {
 			space: &Space{
 				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
 				Spec: SpaceSpec{
 					Execution: goodExecuton,
-					BuildpackBuild: SpaceSpecBuildpackBuild{
-						BuilderImage: DefaultBuilderImage,
-					},
 				},
 			},
-			want: apis.ErrMissingField("spec.buildpackBuild.containerRegistry"),
 		},
-		"no builder image": {
+		"no domains": {
 			space: &Space{
 				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
 				Spec: SpaceSpec{
-					Execution: goodExecuton,
 					BuildpackBuild: SpaceSpecBuildpackBuild{
 						ContainerRegistry: "gcr.io/test",
+						BuilderImage:      DefaultBuilderImage,
 					},
 				},
 			},
-			want: apis.ErrMissingField("spec.buildpackBuild.builderImage"),
+			want: apis.ErrMissingField("spec.execution.domains"),
 		},
-		"no domains": {
+		"invalid domains": {
 			space: &Space{
 				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
 				Spec: SpaceSpec{
-					BuildpackBuild: SpaceSpecBuildpackBuild{
-						ContainerRegistry: "gcr.io/test",
-						BuilderImage:      DefaultBuilderImage,
+					Execution: SpaceSpecExecution{
+						Domains: []SpaceDomain{
+							{Domain: "example.com", Default: true},
+							{Domain: "*.example.com"},
+							{Domain: "apps.*.example.com"},
+							{Domain: "Example_Apps.com"},
+						},
 					},
+					BuildpackBuild: goodBuildpackBuild,
 				},
 			},
-			want: apis.ErrMissingField("spec.execution.domains"),
+			want: (&apis.FieldError{
+				Message: "invalid value: *.example.com",
+				Paths:   []string{"spec.execution.domains[1]"},
+				Details: `domain can't be a wildcard, use "example.com" which includes its subdomains`,
+			}).Also(&apis.FieldError{
+				Message: "invalid value: apps.*.example.com",
+				Paths:   []string{"spec.execution.domains[2]"},
+				Details: "domain can't contain wildcards",
+			}).Also(&apis.FieldError{
+				Message: "invalid value: Example_Apps.com",
+				Paths:   []string{"spec.execution.domains[3]"},
+				Details: `invalid domain "Example_Apps.com": ` + strings.Join(validation.IsDNS1123Subdomain("Example_Apps.com"), ", "),
+			}),
+		},
+		"duplicate domains": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: SpaceSpecExecution{
+						Domains: []SpaceDomain{
+							{Domain: "example.com", Default: true},
+							{Domain: "example.com"},
+						},
+					},
+					BuildpackBuild: goodBuildpackBuild,
+				},
+			},
+			want: &apis.FieldError{
+				Message: "duplicate domain",
+				Paths:   []string{"spec.execution.domains[1].domain"},
+				Details: "example.com is listed more than once",
+			},
 		},
 		"no default domain": {
 			space: &Space{
@@ -147,6 +182,367 @@ func TestSpaceValidation(t *testing.T) {
 				Details: "one domain must be set to default",
 			},
 		},
+		"domain with TLS and redirect": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: SpaceSpecExecution{
+						Domains: []SpaceDomain{
+							{Domain: "example.com", Default: true, TLSSecretName: "example-cert", RedirectHTTPS: true},
+						},
+					},
+					BuildpackBuild: SpaceSpecBuildpackBuild{
+						ContainerRegistry: "gcr.io/test",
+						BuilderImage:      DefaultBuilderImage,
+					},
+				},
+			},
+		},
+		"redirect without TLS": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: SpaceSpecExecution{
+						Domains: []SpaceDomain{
+							{Domain: "example.com", Default: true},
+							{Domain: "other-example.com", RedirectHTTPS: true},
+						},
+					},
+					BuildpackBuild: SpaceSpecBuildpackBuild{
+						ContainerRegistry: "gcr.io/test",
+						BuilderImage:      DefaultBuilderImage,
+					},
+				},
+			},
+			want: &apis.FieldError{
+				Paths:   []string{"spec.execution.domains[1].redirectHTTPS"},
+				Message: "HTTPS redirects require a TLS secret",
+				Details: "set tlsSecretName before enabling redirectHTTPS",
+			},
+		},
+		"invalid response headers": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: SpaceSpecExecution{
+						Domains:         goodExecuton.Domains,
+						ResponseHeaders: map[string]string{"Strict Transport Security": "max-age=31536000"},
+					},
+					BuildpackBuild: SpaceSpecBuildpackBuild{
+						ContainerRegistry: "gcr.io/test",
+						BuilderImage:      DefaultBuilderImage,
+					},
+				},
+			},
+			want: apis.ErrInvalidKeyName("Strict Transport Security", "spec.execution.responseHeaders"),
+		},
+		"negative max concurrent builds": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: goodExecuton,
+					BuildpackBuild: SpaceSpecBuildpackBuild{
+						ContainerRegistry:   "gcr.io/test",
+						BuilderImage:        DefaultBuilderImage,
+						MaxConcurrentBuilds: -1,
+					},
+				},
+			},
+			want: apis.ErrInvalidValue(-1, "spec.buildpackBuild.maxConcurrentBuilds"),
+		},
+		"valid buildpacks": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: goodExecuton,
+					BuildpackBuild: SpaceSpecBuildpackBuild{
+						Buildpacks: []SpaceBuildpack{
+							{Name: "node", Git: "https://github.com/example/node-buildpack@v1"},
+							{Name: "java", Image: "gcr.io/example/java-buildpack:v2"},
+						},
+					},
+				},
+			},
+		},
+		"invalid buildpacks": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: goodExecuton,
+					BuildpackBuild: SpaceSpecBuildpackBuild{
+						Buildpacks: []SpaceBuildpack{
+							{Name: "node", Git: "https://github.com/example/node-buildpack"},
+							{Name: "node", Image: "gcr.io/example/node"},
+							{Name: "Java_8"},
+							{Name: "both", Git: "https://github.com/example/bp", Image: "gcr.io/example/bp"},
+						},
+					},
+				},
+			},
+			want: (&apis.FieldError{
+				Message: "duplicate buildpack",
+				Paths:   []string{"spec.buildpackBuild.buildpacks[1].name"},
+				Details: "node is listed more than once",
+			}).Also(
+				&apis.FieldError{
+					Message: "invalid value: Java_8",
+					Paths:   []string{"spec.buildpackBuild.buildpacks[2].name"},
+					Details: "names must be DNS labels of at most 50 characters",
+				},
+				apis.ErrMissingOneOf("spec.buildpackBuild.buildpacks[2].git", "spec.buildpackBuild.buildpacks[2].image"),
+				apis.ErrMultipleOneOf("spec.buildpackBuild.buildpacks[3].git", "spec.buildpackBuild.buildpacks[3].image"),
+			),
+		},
+		"valid log bucket": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: goodExecuton,
+					BuildpackBuild: SpaceSpecBuildpackBuild{
+						LogBucket: "gs://build-logs",
+					},
+				},
+			},
+		},
+		"invalid log bucket": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: goodExecuton,
+					BuildpackBuild: SpaceSpecBuildpackBuild{
+						LogBucket: "s3://build-logs",
+					},
+				},
+			},
+			want: &apis.FieldError{
+				Message: "invalid value: s3://build-logs",
+				Paths:   []string{"spec.buildpackBuild.logBucket"},
+				Details: `bucket "s3://build-logs" must be a Cloud Storage URL starting with gs://`,
+			},
+		},
+		"valid default health check": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: SpaceSpecExecution{
+						Domains: goodExecuton.Domains,
+						DefaultHealthCheck: SpaceDefaultHealthCheck{
+							Type:                  "http",
+							HTTPEndpoint:          "/healthz",
+							StartupTimeoutSeconds: 180,
+						},
+					},
+					BuildpackBuild: goodBuildpackBuild,
+				},
+			},
+		},
+		"invalid default health check": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: SpaceSpecExecution{
+						Domains: goodExecuton.Domains,
+						DefaultHealthCheck: SpaceDefaultHealthCheck{
+							Type:                  "process",
+							StartupTimeoutSeconds: -1,
+						},
+					},
+					BuildpackBuild: goodBuildpackBuild,
+				},
+			},
+			want: apis.ErrInvalidValue("process", "spec.execution.defaultHealthCheck.type").Also(
+				apis.ErrInvalidValue(-1, "spec.execution.defaultHealthCheck.startupTimeoutSeconds"),
+			),
+		},
+		"default health check endpoint without http": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					Execution: SpaceSpecExecution{
+						Domains: goodExecuton.Domains,
+						DefaultHealthCheck: SpaceDefaultHealthCheck{
+							Type:         "port",
+							HTTPEndpoint: "/healthz",
+						},
+					},
+					BuildpackBuild: goodBuildpackBuild,
+				},
+			},
+			want: &apis.FieldError{
+				Paths:   []string{"spec.execution.defaultHealthCheck.httpEndpoint"},
+				Message: "HTTP endpoints can only be used with http health checks",
+			},
+		},
+		"valid reserved hostnames": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution: SpaceSpecExecution{
+						Domains: goodExecuton.Domains,
+						ReservedHostnames: []SpaceReservedHostname{
+							{Hostname: "api", AllowedApps: []string{"api-gateway"}},
+							{Hostname: "www"},
+						},
+					},
+				},
+			},
+		},
+		"invalid reserved hostname": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution: SpaceSpecExecution{
+						Domains: goodExecuton.Domains,
+						ReservedHostnames: []SpaceReservedHostname{
+							{Hostname: "api.example"},
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("api.example", "spec.execution.reservedHostnames[0].hostname"),
+		},
+		"duplicate reserved hostname": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution: SpaceSpecExecution{
+						Domains: goodExecuton.Domains,
+						ReservedHostnames: []SpaceReservedHostname{
+							{Hostname: "api"},
+							{Hostname: "API"},
+						},
+					},
+				},
+			},
+			want: &apis.FieldError{
+				Message: "hostname API is reserved more than once",
+				Paths:   []string{"spec.execution.reservedHostnames[1].hostname"},
+			},
+		},
+		"valid sidecar resources": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution: SpaceSpecExecution{
+						Domains: goodExecuton.Domains,
+						SidecarResources: SpaceSidecarResources{
+							CPU:    "50m",
+							Memory: "64Mi",
+						},
+					},
+				},
+			},
+		},
+		"invalid sidecar resources": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution: SpaceSpecExecution{
+						Domains: goodExecuton.Domains,
+						SidecarResources: SpaceSidecarResources{
+							CPU:    "lots",
+							Memory: "64Mi",
+						},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("lots", "spec.execution.sidecarResources.cpu"),
+		},
+		"valid notifications": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution:      goodExecuton,
+					Notifications: []SpaceNotification{
+						{Webhook: "https://hooks.slack.com/services/T0/B0/X", Events: []string{"build-failed", "push-complete"}},
+						{Webhook: "http://ci.example.com/hook", Events: NotificationEvents},
+					},
+				},
+			},
+		},
+		"invalid notification webhook": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution:      goodExecuton,
+					Notifications: []SpaceNotification{
+						{Webhook: "ftp://example.com", Events: []string{"build-failed"}},
+					},
+				},
+			},
+			want: apis.ErrInvalidValue("ftp://example.com", "spec.notifications[0].webhook"),
+		},
+		"unknown notification event": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution:      goodExecuton,
+					Notifications: []SpaceNotification{
+						{Webhook: "https://example.com/hook", Events: []string{"build-failed", "deleted"}},
+					},
+				},
+			},
+			want: apis.ErrInvalidArrayValue("deleted", "spec.notifications[0].events", 1),
+		},
+		"duplicate notification webhook": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution:      goodExecuton,
+					Notifications: []SpaceNotification{
+						{Webhook: "https://example.com/hook", Events: []string{"build-failed"}},
+						{Webhook: "https://example.com/hook", Events: []string{"push-failed"}},
+					},
+				},
+			},
+			want: &apis.FieldError{
+				Message: "webhook https://example.com/hook is configured more than once",
+				Paths:   []string{"spec.notifications[1].webhook"},
+			},
+		},
+		"valid egress policy": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution:      goodExecuton,
+					Security: SpaceSpecSecurity{
+						Egress: SpaceEgressPolicy{
+							Allow:   []string{"api.stripe.com:443", "*.googleapis.com:443"},
+							DenyAll: true,
+						},
+					},
+				},
+			},
+		},
+		"invalid egress destination": {
+			space: &Space{
+				ObjectMeta: metav1.ObjectMeta{Name: "valid"},
+				Spec: SpaceSpec{
+					BuildpackBuild: goodBuildpackBuild,
+					Execution:      goodExecuton,
+					Security: SpaceSpecSecurity{
+						Egress: SpaceEgressPolicy{
+							Allow: []string{"api.stripe.com:443", "api.stripe.com"},
+						},
+					},
+				},
+			},
+			want: &apis.FieldError{
+				Message: "invalid value: api.stripe.com",
+				Paths:   []string{"spec.security.egress.allow[1]"},
+				Details: "destination must be in the form HOST:PORT",
+			},
+		},
 	}
 
 	for tn, tc := range cases {
@@ -157,3 +553,82 @@ func TestSpaceValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestParseEgressDestination(t *testing.T) {
+	cases := map[string]struct {
+		dest     string
+		wantHost string
+		wantPort int32
+		wantErr  error
+	}{
+		"host and port": {
+			dest:     "api.stripe.com:443",
+			wantHost: "api.stripe.com",
+			wantPort: 443,
+		},
+		"wildcard host": {
+			dest:     "*.googleapis.com:443",
+			wantHost: "*.googleapis.com",
+			wantPort: 443,
+		},
+		"missing port": {
+			dest:    "api.stripe.com",
+			wantErr: errors.New("destination must be in the form HOST:PORT"),
+		},
+		"invalid host": {
+			dest:    "API_STRIPE:443",
+			wantErr: errors.New(`invalid host "API_STRIPE": a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`),
+		},
+		"port out of range": {
+			dest:    "api.stripe.com:70000",
+			wantErr: errors.New(`invalid port "70000": must be between 1 and 65535`),
+		},
+		"non-numeric port": {
+			dest:    "api.stripe.com:https",
+			wantErr: errors.New(`invalid port "https": must be between 1 and 65535`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			host, port, err := ParseEgressDestination(tc.dest)
+
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "host", tc.wantHost, host)
+			testutil.AssertEqual(t, "port", tc.wantPort, port)
+		})
+	}
+}
+
+func TestValidateBuildLogBucket(t *testing.T) {
+	cases := map[string]struct {
+		bucket  string
+		wantErr error
+	}{
+		"valid": {
+			bucket: "gs://my-build-logs",
+		},
+		"not cloud storage": {
+			bucket:  "my-build-logs",
+			wantErr: errors.New(`bucket "my-build-logs" must be a Cloud Storage URL starting with gs://`),
+		},
+		"no name": {
+			bucket:  "gs://",
+			wantErr: errors.New("bucket name can't be empty"),
+		},
+		"path": {
+			bucket:  "gs://my-build-logs/space",
+			wantErr: errors.New(`bucket "gs://my-build-logs/space" can't contain a path`),
+		},
+		"invalid name": {
+			bucket:  "gs://My_Logs",
+			wantErr: errors.New(`invalid bucket name "My_Logs"`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertErrorsEqual(t, tc.wantErr, ValidateBuildLogBucket(tc.bucket))
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"knative.dev/pkg/apis"
 	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
 	cv1alpha3 "knative.dev/pkg/client/clientset/versioned/typed/istio/v1alpha3"
@@ -170,6 +171,21 @@ func IstioClientFromContext(ctx context.Context) cv1alpha3.VirtualServicesGetter
 	return ctx.Value(istioClientKey{}).(cv1alpha3.VirtualServicesGetter)
 }
 
+type secretsClientKey struct{}
+
+// SetupSecretsClient adds a client to the context that validation can use to
+// check the Secrets referenced by objects exist.
+func SetupSecretsClient(ctx context.Context, secretsClient v1.SecretsGetter) context.Context {
+	return context.WithValue(ctx, secretsClientKey{}, secretsClient)
+}
+
+// SecretsClientFromContext returns the client added by SetupSecretsClient or
+// nil if there isn't one.
+func SecretsClientFromContext(ctx context.Context) v1.SecretsGetter {
+	secretsClient, _ := ctx.Value(secretsClientKey{}).(v1.SecretsGetter)
+	return secretsClient
+}
+
 // IsStatusFinal returns true if the Ready or Succeeded conditions are True or
 // False for a Status.
 func IsStatusFinal(duck duckv1beta1.Status) bool {
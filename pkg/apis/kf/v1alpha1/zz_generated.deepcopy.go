@@ -96,7 +96,9 @@ func (in *AppSpec) DeepCopyInto(out *AppSpec) {
 	if in.Routes != nil {
 		in, out := &in.Routes, &out.Routes
 		*out = make([]RouteSpecFields, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.ServiceBindings != nil {
 		in, out := &in.ServiceBindings, &out.ServiceBindings
@@ -105,6 +107,23 @@ func (in *AppSpec) DeepCopyInto(out *AppSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.RestartOnChange != nil {
+		in, out := &in.RestartOnChange, &out.RestartOnChange
+		*out = make([]AppSpecRestartOnChange, len(*in))
+		copy(*out, *in)
+	}
+	if in.Processes != nil {
+		in, out := &in.Processes, &out.Processes
+		*out = make([]AppSpecProcess, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(AppSpecRollout)
+		**out = **in
+	}
 	return
 }
 
@@ -136,6 +155,16 @@ func (in *AppSpecInstances) DeepCopyInto(out *AppSpecInstances) {
 		*out = new(int)
 		**out = **in
 	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(AppSpecInstancesSchedule)
+		**out = **in
+	}
+	if in.ColdStartTimeoutSeconds != nil {
+		in, out := &in.ColdStartTimeoutSeconds, &out.ColdStartTimeoutSeconds
+		*out = new(int64)
+		**out = **in
+	}
 	return
 }
 
@@ -149,6 +178,75 @@ func (in *AppSpecInstances) DeepCopy() *AppSpecInstances {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSpecInstancesSchedule) DeepCopyInto(out *AppSpecInstancesSchedule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSpecInstancesSchedule.
+func (in *AppSpecInstancesSchedule) DeepCopy() *AppSpecInstancesSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSpecInstancesSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSpecProcess) DeepCopyInto(out *AppSpecProcess) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSpecProcess.
+func (in *AppSpecProcess) DeepCopy() *AppSpecProcess {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSpecProcess)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSpecRestartOnChange) DeepCopyInto(out *AppSpecRestartOnChange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSpecRestartOnChange.
+func (in *AppSpecRestartOnChange) DeepCopy() *AppSpecRestartOnChange {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSpecRestartOnChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSpecRollout) DeepCopyInto(out *AppSpecRollout) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSpecRollout.
+func (in *AppSpecRollout) DeepCopy() *AppSpecRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSpecRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppSpecServiceBinding) DeepCopyInto(out *AppSpecServiceBinding) {
 	*out = *in
@@ -157,6 +255,16 @@ func (in *AppSpecServiceBinding) DeepCopyInto(out *AppSpecServiceBinding) {
 		*out = make(json.RawMessage, len(*in))
 		copy(*out, *in)
 	}
+	if in.Rotation != nil {
+		in, out := &in.Rotation, &out.Rotation
+		*out = new(AppSpecServiceBindingRotation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackupHooks != nil {
+		in, out := &in.BackupHooks, &out.BackupHooks
+		*out = new(AppSpecServiceBindingBackupHooks)
+		**out = **in
+	}
 	return
 }
 
@@ -170,6 +278,44 @@ func (in *AppSpecServiceBinding) DeepCopy() *AppSpecServiceBinding {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSpecServiceBindingBackupHooks) DeepCopyInto(out *AppSpecServiceBindingBackupHooks) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSpecServiceBindingBackupHooks.
+func (in *AppSpecServiceBindingBackupHooks) DeepCopy() *AppSpecServiceBindingBackupHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSpecServiceBindingBackupHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AppSpecServiceBindingRotation) DeepCopyInto(out *AppSpecServiceBindingRotation) {
+	*out = *in
+	out.Period = in.Period
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(MaintenanceWindow)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AppSpecServiceBindingRotation.
+func (in *AppSpecServiceBindingRotation) DeepCopy() *AppSpecServiceBindingRotation {
+	if in == nil {
+		return nil
+	}
+	out := new(AppSpecServiceBindingRotation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AppSpecTemplate) DeepCopyInto(out *AppSpecTemplate) {
 	*out = *in
@@ -206,6 +352,15 @@ func (in *AppStatus) DeepCopyInto(out *AppStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CredentialsRotatedAt != nil {
+		in, out := &in.CredentialsRotatedAt, &out.CredentialsRotatedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.EgressIPs != nil {
+		in, out := &in.EgressIPs, &out.EgressIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -220,47 +375,113 @@ func (in *AppStatus) DeepCopy() *AppStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in HTTPRoutes) DeepCopyInto(out *HTTPRoutes) {
-	{
-		in := &in
-		*out = make(HTTPRoutes, len(*in))
+func (in *GitTrigger) DeepCopyInto(out *GitTrigger) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTrigger.
+func (in *GitTrigger) DeepCopy() *GitTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitTrigger) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTriggerList) DeepCopyInto(out *GitTriggerList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GitTrigger, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
-		return
 	}
+	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRoutes.
-func (in HTTPRoutes) DeepCopy() HTTPRoutes {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTriggerList.
+func (in *GitTriggerList) DeepCopy() *GitTriggerList {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPRoutes)
+	out := new(GitTriggerList)
 	in.DeepCopyInto(out)
-	return *out
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GitTriggerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in OwnerReferences) DeepCopyInto(out *OwnerReferences) {
-	{
-		in := &in
-		*out = make(OwnerReferences, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-		return
+func (in *GitTriggerSpec) DeepCopyInto(out *GitTriggerSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTriggerSpec.
+func (in *GitTriggerSpec) DeepCopy() *GitTriggerSpec {
+	if in == nil {
+		return nil
 	}
+	out := new(GitTriggerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitTriggerStatus) DeepCopyInto(out *GitTriggerStatus) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitTriggerStatus.
+func (in *GitTriggerStatus) DeepCopy() *GitTriggerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GitTriggerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OwnerReferences.
-func (in OwnerReferences) DeepCopy() OwnerReferences {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
 	if in == nil {
 		return nil
 	}
-	out := new(OwnerReferences)
+	out := new(MaintenanceWindow)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -268,7 +489,7 @@ func (in *Route) DeepCopyInto(out *Route) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
@@ -290,12 +511,48 @@ func (in *Route) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RouteCORSPolicy) DeepCopyInto(out *RouteCORSPolicy) {
+	*out = *in
+	if in.AllowOrigins != nil {
+		in, out := &in.AllowOrigins, &out.AllowOrigins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowMethods != nil {
+		in, out := &in.AllowMethods, &out.AllowMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowHeaders != nil {
+		in, out := &in.AllowHeaders, &out.AllowHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExposeHeaders != nil {
+		in, out := &in.ExposeHeaders, &out.ExposeHeaders
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteCORSPolicy.
+func (in *RouteCORSPolicy) DeepCopy() *RouteCORSPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RouteCORSPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteClaim) DeepCopyInto(out *RouteClaim) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
 
@@ -353,7 +610,29 @@ func (in *RouteClaimList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RouteClaimSpec) DeepCopyInto(out *RouteClaimSpec) {
 	*out = *in
-	out.RouteSpecFields = in.RouteSpecFields
+	in.RouteSpecFields.DeepCopyInto(&out.RouteSpecFields)
+	if in.Maintenance != nil {
+		in, out := &in.Maintenance, &out.Maintenance
+		*out = new(RouteMaintenance)
+		**out = **in
+	}
+	if in.SessionAffinity != nil {
+		in, out := &in.SessionAffinity, &out.SessionAffinity
+		*out = new(RouteSessionAffinity)
+		**out = **in
+	}
+	if in.CORSPolicy != nil {
+		in, out := &in.CORSPolicy, &out.CORSPolicy
+		*out = new(RouteCORSPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -401,100 +680,75 @@ func (in *RouteList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
+func (in *RouteMaintenance) DeepCopyInto(out *RouteMaintenance) {
 	*out = *in
-	out.RouteSpecFields = in.RouteSpecFields
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpec.
-func (in *RouteSpec) DeepCopy() *RouteSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteMaintenance.
+func (in *RouteMaintenance) DeepCopy() *RouteMaintenance {
 	if in == nil {
 		return nil
 	}
-	out := new(RouteSpec)
+	out := new(RouteMaintenance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *RouteSpecFields) DeepCopyInto(out *RouteSpecFields) {
+func (in *RouteSessionAffinity) DeepCopyInto(out *RouteSessionAffinity) {
 	*out = *in
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpecFields.
-func (in *RouteSpecFields) DeepCopy() *RouteSpecFields {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSessionAffinity.
+func (in *RouteSessionAffinity) DeepCopy() *RouteSessionAffinity {
 	if in == nil {
 		return nil
 	}
-	out := new(RouteSpecFields)
+	out := new(RouteSessionAffinity)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in RouteSpecFieldsSlice) DeepCopyInto(out *RouteSpecFieldsSlice) {
-	{
-		in := &in
-		*out = make(RouteSpecFieldsSlice, len(*in))
-		copy(*out, *in)
-		return
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpecFieldsSlice.
-func (in RouteSpecFieldsSlice) DeepCopy() RouteSpecFieldsSlice {
-	if in == nil {
-		return nil
-	}
-	out := new(RouteSpecFieldsSlice)
-	in.DeepCopyInto(out)
-	return *out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in Routes) DeepCopyInto(out *Routes) {
-	{
-		in := &in
-		*out = make(Routes, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-		return
-	}
+func (in *RouteSpec) DeepCopyInto(out *RouteSpec) {
+	*out = *in
+	in.RouteSpecFields.DeepCopyInto(&out.RouteSpecFields)
+	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Routes.
-func (in Routes) DeepCopy() Routes {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpec.
+func (in *RouteSpec) DeepCopy() *RouteSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(Routes)
+	out := new(RouteSpec)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in ServiceBindings) DeepCopyInto(out *ServiceBindings) {
-	{
-		in := &in
-		*out = make(ServiceBindings, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
+func (in *RouteSpecFields) DeepCopyInto(out *RouteSpecFields) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
-		return
 	}
+	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceBindings.
-func (in ServiceBindings) DeepCopy() ServiceBindings {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RouteSpecFields.
+func (in *RouteSpecFields) DeepCopy() *RouteSpecFields {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceBindings)
+	out := new(RouteSpecFields)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -563,7 +817,12 @@ func (in *SourceSpec) DeepCopyInto(out *SourceSpec) {
 	*out = *in
 	out.ContainerImage = in.ContainerImage
 	in.BuildpackBuild.DeepCopyInto(&out.BuildpackBuild)
-	out.Dockerfile = in.Dockerfile
+	in.Dockerfile.DeepCopyInto(&out.Dockerfile)
+	if in.Git != nil {
+		in, out := &in.Git, &out.Git
+		*out = new(SourceSpecGit)
+		**out = **in
+	}
 	return
 }
 
@@ -619,6 +878,13 @@ func (in *SourceSpecContainerImage) DeepCopy() *SourceSpecContainerImage {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SourceSpecDockerfile) DeepCopyInto(out *SourceSpecDockerfile) {
 	*out = *in
+	if in.BuildArgs != nil {
+		in, out := &in.BuildArgs, &out.BuildArgs
+		*out = make([]v1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -632,6 +898,22 @@ func (in *SourceSpecDockerfile) DeepCopy() *SourceSpecDockerfile {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceSpecGit) DeepCopyInto(out *SourceSpecGit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceSpecGit.
+func (in *SourceSpecGit) DeepCopy() *SourceSpecGit {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceSpecGit)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SourceStatus) DeepCopyInto(out *SourceStatus) {
 	*out = *in
@@ -694,6 +976,38 @@ func (in *Space) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceBuildpack) DeepCopyInto(out *SpaceBuildpack) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceBuildpack.
+func (in *SpaceBuildpack) DeepCopy() *SpaceBuildpack {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceBuildpack)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceDefaultHealthCheck) DeepCopyInto(out *SpaceDefaultHealthCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceDefaultHealthCheck.
+func (in *SpaceDefaultHealthCheck) DeepCopy() *SpaceDefaultHealthCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceDefaultHealthCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpaceDomain) DeepCopyInto(out *SpaceDomain) {
 	*out = *in
@@ -711,23 +1025,24 @@ func (in *SpaceDomain) DeepCopy() *SpaceDomain {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in SpaceDomains) DeepCopyInto(out *SpaceDomains) {
-	{
-		in := &in
-		*out = make(SpaceDomains, len(*in))
+func (in *SpaceEgressPolicy) DeepCopyInto(out *SpaceEgressPolicy) {
+	*out = *in
+	if in.Allow != nil {
+		in, out := &in.Allow, &out.Allow
+		*out = make([]string, len(*in))
 		copy(*out, *in)
-		return
 	}
+	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceDomains.
-func (in SpaceDomains) DeepCopy() SpaceDomains {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceEgressPolicy.
+func (in *SpaceEgressPolicy) DeepCopy() *SpaceEgressPolicy {
 	if in == nil {
 		return nil
 	}
-	out := new(SpaceDomains)
+	out := new(SpaceEgressPolicy)
 	in.DeepCopyInto(out)
-	return *out
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -763,13 +1078,78 @@ func (in *SpaceList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceNotification) DeepCopyInto(out *SpaceNotification) {
+	*out = *in
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceNotification.
+func (in *SpaceNotification) DeepCopy() *SpaceNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceReservedHostname) DeepCopyInto(out *SpaceReservedHostname) {
+	*out = *in
+	if in.AllowedApps != nil {
+		in, out := &in.AllowedApps, &out.AllowedApps
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceReservedHostname.
+func (in *SpaceReservedHostname) DeepCopy() *SpaceReservedHostname {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceReservedHostname)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceSidecarResources) DeepCopyInto(out *SpaceSidecarResources) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceSidecarResources.
+func (in *SpaceSidecarResources) DeepCopy() *SpaceSidecarResources {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceSidecarResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpaceSpec) DeepCopyInto(out *SpaceSpec) {
 	*out = *in
-	out.Security = in.Security
+	in.Security.DeepCopyInto(&out.Security)
 	in.BuildpackBuild.DeepCopyInto(&out.BuildpackBuild)
 	in.Execution.DeepCopyInto(&out.Execution)
 	in.ResourceLimits.DeepCopyInto(&out.ResourceLimits)
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]SpaceNotification, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -793,6 +1173,11 @@ func (in *SpaceSpecBuildpackBuild) DeepCopyInto(out *SpaceSpecBuildpackBuild) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Buildpacks != nil {
+		in, out := &in.Buildpacks, &out.Buildpacks
+		*out = make([]SpaceBuildpack, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -821,6 +1206,22 @@ func (in *SpaceSpecExecution) DeepCopyInto(out *SpaceSpecExecution) {
 		*out = make([]SpaceDomain, len(*in))
 		copy(*out, *in)
 	}
+	if in.ResponseHeaders != nil {
+		in, out := &in.ResponseHeaders, &out.ResponseHeaders
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	out.DefaultHealthCheck = in.DefaultHealthCheck
+	if in.ReservedHostnames != nil {
+		in, out := &in.ReservedHostnames, &out.ReservedHostnames
+		*out = make([]SpaceReservedHostname, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.SidecarResources = in.SidecarResources
 	return
 }
 
@@ -867,6 +1268,12 @@ func (in *SpaceSpecResourceLimits) DeepCopy() *SpaceSpecResourceLimits {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SpaceSpecSecurity) DeepCopyInto(out *SpaceSpecSecurity) {
 	*out = *in
+	if in.RequiredNetworkPolicies != nil {
+		in, out := &in.RequiredNetworkPolicies, &out.RequiredNetworkPolicies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.Egress.DeepCopyInto(&out.Egress)
 	return
 }
 
@@ -885,6 +1292,8 @@ func (in *SpaceStatus) DeepCopyInto(out *SpaceStatus) {
 	*out = *in
 	in.Status.DeepCopyInto(&out.Status)
 	in.Quota.DeepCopyInto(&out.Quota)
+	out.BuildpackBuild = in.BuildpackBuild
+	out.CustomBuilder = in.CustomBuilder
 	return
 }
 
@@ -897,3 +1306,35 @@ func (in *SpaceStatus) DeepCopy() *SpaceStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceStatusBuildpackBuild) DeepCopyInto(out *SpaceStatusBuildpackBuild) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceStatusBuildpackBuild.
+func (in *SpaceStatusBuildpackBuild) DeepCopy() *SpaceStatusBuildpackBuild {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceStatusBuildpackBuild)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpaceStatusCustomBuilder) DeepCopyInto(out *SpaceStatusCustomBuilder) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpaceStatusCustomBuilder.
+func (in *SpaceStatusCustomBuilder) DeepCopy() *SpaceStatusCustomBuilder {
+	if in == nil {
+		return nil
+	}
+	out := new(SpaceStatusCustomBuilder)
+	in.DeepCopyInto(out)
+	return out
+}
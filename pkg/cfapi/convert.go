@@ -0,0 +1,189 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfapi
+
+import (
+	"path"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// AppStateStarted is the state of Apps that are running.
+	AppStateStarted = "STARTED"
+	// AppStateStopped is the state of Apps that have been stopped.
+	AppStateStopped = "STOPPED"
+
+	// BuildStateStaging is the state of Builds that are still running.
+	BuildStateStaging = "STAGING"
+	// BuildStateStaged is the state of Builds that succeeded.
+	BuildStateStaged = "STAGED"
+	// BuildStateFailed is the state of Builds that failed.
+	BuildStateFailed = "FAILED"
+
+	lifecycleBuildpack = "buildpack"
+	lifecycleDocker    = "docker"
+)
+
+// formatTime formats a Kubernetes timestamp the way CF does. Kubernetes
+// doesn't track when objects were last updated so the creation time is used
+// for both created_at and updated_at.
+func formatTime(t metav1.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+func toOne(guid string) Relationship {
+	if guid == "" {
+		return Relationship{}
+	}
+
+	return Relationship{Data: &RelationshipData{GUID: guid}}
+}
+
+func convertSpace(baseURL string, space *v1alpha1.Space) Space {
+	guid := string(space.UID)
+
+	return Space{
+		GUID:      guid,
+		Name:      space.Name,
+		CreatedAt: formatTime(space.CreationTimestamp),
+		UpdatedAt: formatTime(space.CreationTimestamp),
+		Links: map[string]Link{
+			"self": {Href: baseURL + "/v3/spaces/" + guid},
+			"apps": {Href: baseURL + "/v3/apps?space_guids=" + guid},
+		},
+	}
+}
+
+func convertLifecycle(spec v1alpha1.SourceSpec) Lifecycle {
+	if spec.IsContainerBuild() {
+		return Lifecycle{Type: lifecycleDocker, Data: map[string]interface{}{}}
+	}
+
+	buildpacks := []string{}
+	if spec.BuildpackBuild.Buildpack != "" {
+		buildpacks = append(buildpacks, spec.BuildpackBuild.Buildpack)
+	}
+
+	return Lifecycle{
+		Type: lifecycleBuildpack,
+		Data: map[string]interface{}{
+			"buildpacks": buildpacks,
+			"stack":      spec.BuildpackBuild.Stack,
+		},
+	}
+}
+
+func convertApp(baseURL string, app *v1alpha1.App, spaceGUID string) App {
+	guid := string(app.UID)
+	self := baseURL + "/v3/apps/" + guid
+
+	state := AppStateStarted
+	if app.Spec.Instances.Stopped {
+		state = AppStateStopped
+	}
+
+	return App{
+		GUID:      guid,
+		Name:      app.Name,
+		State:     state,
+		CreatedAt: formatTime(app.CreationTimestamp),
+		UpdatedAt: formatTime(app.CreationTimestamp),
+		Lifecycle: convertLifecycle(app.Spec.Source),
+		Relationships: map[string]Relationship{
+			"space": toOne(spaceGUID),
+		},
+		Links: map[string]Link{
+			"self":   {Href: self},
+			"space":  {Href: baseURL + "/v3/spaces/" + spaceGUID},
+			"builds": {Href: baseURL + "/v3/builds?app_guids=" + guid},
+			"routes": {Href: baseURL + "/v3/routes?app_guids=" + guid},
+			"start":  {Href: self + "/actions/start", Method: "POST"},
+			"stop":   {Href: self + "/actions/stop", Method: "POST"},
+		},
+	}
+}
+
+func convertBuild(baseURL string, source *v1alpha1.Source, appGUID string) Build {
+	guid := string(source.UID)
+
+	state := BuildStateStaging
+	var buildErr *string
+	if cond := source.Status.GetCondition(v1alpha1.SourceConditionSucceeded); cond != nil {
+		switch {
+		case cond.IsTrue():
+			state = BuildStateStaged
+		case cond.IsFalse():
+			state = BuildStateFailed
+			msg := cond.Message
+			buildErr = &msg
+		}
+	}
+
+	return Build{
+		GUID:      guid,
+		State:     state,
+		Error:     buildErr,
+		CreatedAt: formatTime(source.CreationTimestamp),
+		UpdatedAt: formatTime(source.CreationTimestamp),
+		Lifecycle: convertLifecycle(source.Spec),
+		Relationships: map[string]Relationship{
+			"app": toOne(appGUID),
+		},
+		Links: map[string]Link{
+			"self": {Href: baseURL + "/v3/builds/" + guid},
+			"app":  {Href: baseURL + "/v3/apps/" + appGUID},
+		},
+	}
+}
+
+func convertRoute(baseURL string, claim *v1alpha1.RouteClaim, spaceGUID string, appGUIDs []string) Route {
+	guid := string(claim.UID)
+	fields := claim.Spec.RouteSpecFields
+
+	urlPath := ""
+	if fields.Path != "" {
+		urlPath = path.Join("/", fields.Path)
+	}
+
+	url := fields.Domain
+	if fields.Hostname != "" {
+		url = fields.Hostname + "." + url
+	}
+
+	destinations := []Destination{}
+	for _, appGUID := range appGUIDs {
+		destinations = append(destinations, Destination{App: DestinationApp{GUID: appGUID}})
+	}
+
+	return Route{
+		GUID:         guid,
+		Host:         fields.Hostname,
+		Path:         urlPath,
+		URL:          url + urlPath,
+		CreatedAt:    formatTime(claim.CreationTimestamp),
+		UpdatedAt:    formatTime(claim.CreationTimestamp),
+		Destinations: destinations,
+		Relationships: map[string]Relationship{
+			"space": toOne(spaceGUID),
+		},
+		Links: map[string]Link{
+			"self":  {Href: baseURL + "/v3/routes/" + guid},
+			"space": {Href: baseURL + "/v3/spaces/" + spaceGUID},
+		},
+	}
+}
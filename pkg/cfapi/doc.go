@@ -0,0 +1,21 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cfapi serves a subset of the Cloud Foundry v3 API backed by Kf
+// resources so existing CF tooling such as CI plugins and dashboards can
+// read and manage Apps on a Kf cluster.
+//
+// Requests are made to Kubernetes with the caller's bearer token so users
+// only see the Spaces and Apps they already have access to.
+package cfapi
@@ -0,0 +1,472 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfapi
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kfv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	"github.com/gorilla/mux"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClientFactory creates a Kf client that authenticates to Kubernetes with the
+// given bearer token.
+type ClientFactory func(token string) (kfv1alpha1.KfV1alpha1Interface, error)
+
+// apiError is an error that's reported to the caller with the given HTTP
+// status.
+type apiError struct {
+	status int
+	body   Error
+}
+
+func (e *apiError) Error() string {
+	return e.body.Detail
+}
+
+func notFound(kind string) error {
+	return &apiError{
+		status: http.StatusNotFound,
+		body:   Error{Code: 10010, Title: "CF-ResourceNotFound", Detail: kind + " not found"},
+	}
+}
+
+// toAPIError converts errors from Kubernetes into their CF equivalents.
+func toAPIError(err error) *apiError {
+	if apiErr, ok := err.(*apiError); ok {
+		return apiErr
+	}
+
+	switch {
+	case apierrs.IsUnauthorized(err):
+		return &apiError{
+			status: http.StatusUnauthorized,
+			body:   Error{Code: 1000, Title: "CF-InvalidAuthToken", Detail: "Invalid Auth Token"},
+		}
+	case apierrs.IsForbidden(err):
+		return &apiError{
+			status: http.StatusForbidden,
+			body:   Error{Code: 10003, Title: "CF-NotAuthorized", Detail: "You are not authorized to perform the requested action"},
+		}
+	case apierrs.IsNotFound(err):
+		return &apiError{
+			status: http.StatusNotFound,
+			body:   Error{Code: 10010, Title: "CF-ResourceNotFound", Detail: "Resource not found"},
+		}
+	case apierrs.IsConflict(err):
+		return &apiError{
+			status: http.StatusUnprocessableEntity,
+			body:   Error{Code: 10008, Title: "CF-UnprocessableEntity", Detail: err.Error()},
+		}
+	default:
+		log.Printf("Unexpected error: %s", err)
+		return &apiError{
+			status: http.StatusInternalServerError,
+			body:   Error{Code: 10001, Title: "UnknownError", Detail: "An unknown error occurred."},
+		}
+	}
+}
+
+// request holds the state of a single authenticated API call.
+type request struct {
+	*http.Request
+
+	client  kfv1alpha1.KfV1alpha1Interface
+	baseURL string
+	filters url.Values
+}
+
+// matches returns true if the value is allowed by the comma separated filter
+// in the query parameter key. Missing filters allow every value.
+func (r *request) matches(key, value string) bool {
+	raw, ok := r.filters[key]
+	if !ok {
+		return true
+	}
+
+	for _, list := range raw {
+		for _, allowed := range strings.Split(list, ",") {
+			if allowed == value {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// eachSpace calls fn for every Space the caller can see that matches the
+// space_guids filter. Spaces the caller isn't allowed to read resources in
+// are skipped.
+func (r *request) eachSpace(fn func(space *v1alpha1.Space) error) error {
+	spaces, err := r.client.Spaces().List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for i := range spaces.Items {
+		space := &spaces.Items[i]
+		if !r.matches("space_guids", string(space.UID)) {
+			continue
+		}
+
+		if err := fn(space); apierrs.IsForbidden(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type handlerFunc func(r *request) (interface{}, error)
+
+type server struct {
+	clients ClientFactory
+}
+
+// NewHandler creates a handler that serves the CF v3 API for apps, builds,
+// routes and spaces using clients created by the factory.
+func NewHandler(clients ClientFactory) http.Handler {
+	s := &server{clients: clients}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/", s.root).Methods(http.MethodGet)
+	router.HandleFunc("/v3", s.v3Root).Methods(http.MethodGet)
+
+	router.Handle("/v3/spaces", s.list(s.spaces)).Methods(http.MethodGet)
+	router.Handle("/v3/spaces/{guid}", s.get("Space", s.spaces)).Methods(http.MethodGet)
+	router.Handle("/v3/apps", s.list(s.apps)).Methods(http.MethodGet)
+	router.Handle("/v3/apps/{guid}", s.get("App", s.apps)).Methods(http.MethodGet)
+	router.Handle("/v3/apps/{guid}/actions/start", s.handle(s.setStopped(false))).Methods(http.MethodPost)
+	router.Handle("/v3/apps/{guid}/actions/stop", s.handle(s.setStopped(true))).Methods(http.MethodPost)
+	router.Handle("/v3/routes", s.list(s.routes)).Methods(http.MethodGet)
+	router.Handle("/v3/routes/{guid}", s.get("Route", s.routes)).Methods(http.MethodGet)
+	router.Handle("/v3/builds", s.list(s.builds)).Methods(http.MethodGet)
+	router.Handle("/v3/builds/{guid}", s.get("Build", s.builds)).Methods(http.MethodGet)
+
+	router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &apiError{
+			status: http.StatusNotFound,
+			body:   Error{Code: 10000, Title: "CF-NotFound", Detail: "Unknown request"},
+		})
+	})
+
+	return router
+}
+
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	return scheme + "://" + r.Host
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	writeJSON(w, err.status, ErrorList{Errors: []Error{err.body}})
+}
+
+func (s *server) root(w http.ResponseWriter, r *http.Request) {
+	base := baseURL(r)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"links": map[string]interface{}{
+			"self": Link{Href: base},
+			"cloud_controller_v3": map[string]interface{}{
+				"href": base + "/v3",
+				"meta": map[string]string{"version": "3.0.0"},
+			},
+		},
+	})
+}
+
+func (s *server) v3Root(w http.ResponseWriter, r *http.Request) {
+	base := baseURL(r) + "/v3"
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"links": map[string]Link{
+			"self":   {Href: base},
+			"apps":   {Href: base + "/apps"},
+			"builds": {Href: base + "/builds"},
+			"routes": {Href: base + "/routes"},
+			"spaces": {Href: base + "/spaces"},
+		},
+	})
+}
+
+// handle authenticates the request and writes the result of fn as JSON.
+func (s *server) handle(fn handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if len(auth) < len("bearer ") || !strings.EqualFold(auth[:len("bearer ")], "bearer ") {
+			writeError(w, &apiError{
+				status: http.StatusUnauthorized,
+				body:   Error{Code: 10002, Title: "CF-NotAuthenticated", Detail: "Authentication error"},
+			})
+			return
+		}
+
+		client, err := s.clients(strings.TrimSpace(auth[len("bearer "):]))
+		if err != nil {
+			writeError(w, toAPIError(err))
+			return
+		}
+
+		body, err := fn(&request{
+			Request: r,
+			client:  client,
+			baseURL: baseURL(r),
+			filters: r.URL.Query(),
+		})
+		if err != nil {
+			writeError(w, toAPIError(err))
+			return
+		}
+
+		writeJSON(w, http.StatusOK, body)
+	})
+}
+
+// list wraps the results of fn in a single page List.
+func (s *server) list(fn func(r *request) ([]interface{}, error)) http.Handler {
+	return s.handle(func(r *request) (interface{}, error) {
+		resources, err := fn(r)
+		if err != nil {
+			return nil, err
+		}
+
+		self := Link{Href: r.baseURL + r.URL.RequestURI()}
+		return List{
+			Pagination: Pagination{
+				TotalResults: len(resources),
+				TotalPages:   1,
+				First:        self,
+				Last:         self,
+			},
+			Resources: resources,
+		}, nil
+	})
+}
+
+// get returns the single result of fn filtered by the guid in the path.
+func (s *server) get(kind string, fn func(r *request) ([]interface{}, error)) http.Handler {
+	return s.handle(func(r *request) (interface{}, error) {
+		r.filters = url.Values{"guids": {mux.Vars(r.Request)["guid"]}}
+
+		resources, err := fn(r)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(resources) == 0 {
+			return nil, notFound(kind)
+		}
+
+		return resources[0], nil
+	})
+}
+
+func (s *server) spaces(r *request) ([]interface{}, error) {
+	spaces, err := r.client.Spaces().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := []interface{}{}
+	for i := range spaces.Items {
+		space := &spaces.Items[i]
+		if r.matches("guids", string(space.UID)) && r.matches("names", space.Name) {
+			out = append(out, convertSpace(r.baseURL, space))
+		}
+	}
+
+	return out, nil
+}
+
+func (s *server) apps(r *request) ([]interface{}, error) {
+	out := []interface{}{}
+	err := r.eachSpace(func(space *v1alpha1.Space) error {
+		apps, err := r.client.Apps(space.Name).List(metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		for i := range apps.Items {
+			app := &apps.Items[i]
+			if r.matches("guids", string(app.UID)) && r.matches("names", app.Name) {
+				out = append(out, convertApp(r.baseURL, app, string(space.UID)))
+			}
+		}
+
+		return nil
+	})
+
+	return out, err
+}
+
+func (s *server) setStopped(stopped bool) handlerFunc {
+	return func(r *request) (interface{}, error) {
+		guid := mux.Vars(r.Request)["guid"]
+
+		var (
+			app   *v1alpha1.App
+			space *v1alpha1.Space
+		)
+		if err := r.eachSpace(func(candidate *v1alpha1.Space) error {
+			apps, err := r.client.Apps(candidate.Name).List(metav1.ListOptions{})
+			if err != nil {
+				return err
+			}
+
+			for i := range apps.Items {
+				if string(apps.Items[i].UID) == guid {
+					app, space = &apps.Items[i], candidate
+				}
+			}
+
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		if app == nil {
+			return nil, notFound("App")
+		}
+
+		if app.Spec.Instances.Stopped != stopped {
+			app.Spec.Instances.Stopped = stopped
+
+			updated, err := r.client.Apps(app.Namespace).Update(app)
+			if err != nil {
+				return nil, err
+			}
+			app = updated
+		}
+
+		return convertApp(r.baseURL, app, string(space.UID)), nil
+	}
+}
+
+func (s *server) routes(r *request) ([]interface{}, error) {
+	out := []interface{}{}
+	err := r.eachSpace(func(space *v1alpha1.Space) error {
+		claims, err := r.client.RouteClaims(space.Name).List(metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		routes, err := r.client.Routes(space.Name).List(metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		apps, err := r.client.Apps(space.Name).List(metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		appGUIDs := make(map[string]string)
+		for _, app := range apps.Items {
+			appGUIDs[app.Name] = string(app.UID)
+		}
+
+		for i := range claims.Items {
+			claim := &claims.Items[i]
+			if !r.matches("guids", string(claim.UID)) || !r.matches("hosts", claim.Spec.Hostname) {
+				continue
+			}
+
+			var destinations []string
+			matchesApp := false
+			for _, route := range routes.Items {
+				if !sameRoute(route.Spec.RouteSpecFields, claim.Spec.RouteSpecFields) {
+					continue
+				}
+
+				appGUID, ok := appGUIDs[route.Spec.AppName]
+				if !ok {
+					continue
+				}
+
+				destinations = append(destinations, appGUID)
+				matchesApp = matchesApp || r.matches("app_guids", appGUID)
+			}
+
+			if _, filtered := r.filters["app_guids"]; filtered && !matchesApp {
+				continue
+			}
+
+			out = append(out, convertRoute(r.baseURL, claim, string(space.UID), destinations))
+		}
+
+		return nil
+	})
+
+	return out, err
+}
+
+// sameRoute returns true if a and b share a hostname, domain and path.
+func sameRoute(a, b v1alpha1.RouteSpecFields) bool {
+	return a.Hostname == b.Hostname &&
+		a.Domain == b.Domain &&
+		path.Join("/", a.Path) == path.Join("/", b.Path)
+}
+
+func (s *server) builds(r *request) ([]interface{}, error) {
+	out := []interface{}{}
+	err := r.eachSpace(func(space *v1alpha1.Space) error {
+		sources, err := r.client.Sources(space.Name).List(metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		for i := range sources.Items {
+			source := &sources.Items[i]
+
+			appGUID := ""
+			if owner := metav1.GetControllerOf(source); owner != nil && owner.Kind == "App" {
+				appGUID = string(owner.UID)
+			}
+
+			build := convertBuild(r.baseURL, source, appGUID)
+			if r.matches("guids", build.GUID) && r.matches("app_guids", appGUID) && r.matches("states", build.State) {
+				out = append(out, build)
+			}
+		}
+
+		return nil
+	})
+
+	return out, err
+}
@@ -0,0 +1,274 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/client/clientset/versioned/fake"
+	kfv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	ktesting "k8s.io/client-go/testing"
+	"knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+)
+
+func testObjects() []runtime.Object {
+	isController := true
+
+	return []runtime.Object{
+		&v1alpha1.Space{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-space", UID: "space-guid"},
+		},
+		&v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "my-space", UID: "app-guid"},
+			Spec: v1alpha1.AppSpec{
+				Source: v1alpha1.SourceSpec{
+					BuildpackBuild: v1alpha1.SourceSpecBuildpackBuild{Stack: "cflinuxfs3"},
+				},
+			},
+		},
+		&v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-app", Namespace: "my-space", UID: "other-guid"},
+			Spec: v1alpha1.AppSpec{
+				Source: v1alpha1.SourceSpec{
+					ContainerImage: v1alpha1.SourceSpecContainerImage{Image: "nginx"},
+				},
+				Instances: v1alpha1.AppSpecInstances{Stopped: true},
+			},
+		},
+		&v1alpha1.RouteClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-claim", Namespace: "my-space", UID: "route-guid"},
+			Spec: v1alpha1.RouteClaimSpec{
+				RouteSpecFields: v1alpha1.RouteSpecFields{Hostname: "my-app", Domain: "example.com", Path: "api"},
+			},
+		},
+		&v1alpha1.Route{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-route", Namespace: "my-space"},
+			Spec: v1alpha1.RouteSpec{
+				AppName:         "my-app",
+				RouteSpecFields: v1alpha1.RouteSpecFields{Hostname: "my-app", Domain: "example.com", Path: "/api"},
+			},
+		},
+		&v1alpha1.Source{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-source",
+				Namespace: "my-space",
+				UID:       "build-guid",
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "App", Name: "my-app", UID: "app-guid", Controller: &isController},
+				},
+			},
+			Status: v1alpha1.SourceStatus{
+				Status: duckv1beta1.Status{
+					Conditions: duckv1beta1.Conditions{
+						{Type: apis.ConditionSucceeded, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestHandler(t *testing.T) {
+	cases := map[string]struct {
+		method          string
+		path            string
+		token           string
+		expectedStatus  int
+		expectedStrings []string
+	}{
+		"root": {
+			path:            "/",
+			expectedStatus:  http.StatusOK,
+			expectedStrings: []string{`"cloud_controller_v3":{"href":"http://cf.example.com/v3"`},
+		},
+		"v3 root": {
+			path:            "/v3",
+			expectedStatus:  http.StatusOK,
+			expectedStrings: []string{`"apps":{"href":"http://cf.example.com/v3/apps"}`},
+		},
+		"missing token": {
+			path:            "/v3/apps",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedStrings: []string{"CF-NotAuthenticated"},
+		},
+		"invalid token": {
+			path:            "/v3/apps",
+			token:           "bad-token",
+			expectedStatus:  http.StatusUnauthorized,
+			expectedStrings: []string{"CF-InvalidAuthToken"},
+		},
+		"unknown path": {
+			path:            "/v2/info",
+			token:           "some-token",
+			expectedStatus:  http.StatusNotFound,
+			expectedStrings: []string{"CF-NotFound"},
+		},
+		"list spaces": {
+			path:           "/v3/spaces",
+			token:          "some-token",
+			expectedStatus: http.StatusOK,
+			expectedStrings: []string{
+				`"total_results":1`,
+				`"guid":"space-guid","name":"my-space"`,
+			},
+		},
+		"list apps": {
+			path:           "/v3/apps",
+			token:          "some-token",
+			expectedStatus: http.StatusOK,
+			expectedStrings: []string{
+				`"total_results":2`,
+				`"name":"my-app"`,
+				`"name":"other-app"`,
+			},
+		},
+		"list apps by name": {
+			path:           "/v3/apps?names=other-app",
+			token:          "some-token",
+			expectedStatus: http.StatusOK,
+			expectedStrings: []string{
+				`"total_results":1`,
+				`"name":"other-app","state":"STOPPED"`,
+				`"lifecycle":{"type":"docker","data":{}}`,
+			},
+		},
+		"list apps in other space": {
+			path:            "/v3/apps?space_guids=missing",
+			token:           "some-token",
+			expectedStatus:  http.StatusOK,
+			expectedStrings: []string{`"total_results":0`},
+		},
+		"get app": {
+			path:           "/v3/apps/app-guid",
+			token:          "some-token",
+			expectedStatus: http.StatusOK,
+			expectedStrings: []string{
+				`"guid":"app-guid","name":"my-app","state":"STARTED"`,
+				`"lifecycle":{"type":"buildpack","data":{"buildpacks":[],"stack":"cflinuxfs3"}}`,
+				`"space":{"data":{"guid":"space-guid"}}`,
+			},
+		},
+		"get missing app": {
+			path:            "/v3/apps/missing",
+			token:           "some-token",
+			expectedStatus:  http.StatusNotFound,
+			expectedStrings: []string{"CF-ResourceNotFound", "App not found"},
+		},
+		"stop app": {
+			method:          http.MethodPost,
+			path:            "/v3/apps/app-guid/actions/stop",
+			token:           "some-token",
+			expectedStatus:  http.StatusOK,
+			expectedStrings: []string{`"name":"my-app","state":"STOPPED"`},
+		},
+		"start app": {
+			method:          http.MethodPost,
+			path:            "/v3/apps/other-guid/actions/start",
+			token:           "some-token",
+			expectedStatus:  http.StatusOK,
+			expectedStrings: []string{`"name":"other-app","state":"STARTED"`},
+		},
+		"list routes for app": {
+			path:           "/v3/routes?app_guids=app-guid",
+			token:          "some-token",
+			expectedStatus: http.StatusOK,
+			expectedStrings: []string{
+				`"total_results":1`,
+				`"host":"my-app","path":"/api","url":"my-app.example.com/api"`,
+				`"destinations":[{"app":{"guid":"app-guid"}}]`,
+			},
+		},
+		"list routes for other app": {
+			path:            "/v3/routes?app_guids=other-guid",
+			token:           "some-token",
+			expectedStatus:  http.StatusOK,
+			expectedStrings: []string{`"total_results":0`},
+		},
+		"get build": {
+			path:           "/v3/builds/build-guid",
+			token:          "some-token",
+			expectedStatus: http.StatusOK,
+			expectedStrings: []string{
+				`"guid":"build-guid","state":"STAGED","error":null`,
+				`"app":{"data":{"guid":"app-guid"}}`,
+			},
+		},
+		"list failed builds": {
+			path:            "/v3/builds?states=FAILED",
+			token:           "some-token",
+			expectedStatus:  http.StatusOK,
+			expectedStrings: []string{`"total_results":0`},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			client := fake.NewSimpleClientset(testObjects()...).KfV1alpha1()
+			handler := NewHandler(func(token string) (kfv1alpha1.KfV1alpha1Interface, error) {
+				if token != "some-token" {
+					return nil, apierrs.NewUnauthorized("invalid token")
+				}
+
+				return client, nil
+			})
+
+			method := tc.method
+			if method == "" {
+				method = http.MethodGet
+			}
+
+			req := httptest.NewRequest(method, "http://cf.example.com"+tc.path, nil)
+			if tc.token != "" {
+				req.Header.Set("Authorization", "bearer "+tc.token)
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+
+			testutil.AssertEqual(t, "status", tc.expectedStatus, recorder.Code)
+			testutil.AssertEqual(t, "content type", "application/json", recorder.Header().Get("Content-Type"))
+			testutil.AssertContainsAll(t, recorder.Body.String(), tc.expectedStrings)
+		})
+	}
+}
+
+func TestHandler_forbiddenSpace(t *testing.T) {
+	clientset := fake.NewSimpleClientset(testObjects()...)
+	clientset.PrependReactor("list", "apps", func(action ktesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrs.NewForbidden(schema.GroupResource{Resource: "apps"}, "", nil)
+	})
+
+	handler := NewHandler(func(token string) (kfv1alpha1.KfV1alpha1Interface, error) {
+		return clientset.KfV1alpha1(), nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v3/apps", nil)
+	req.Header.Set("Authorization", "Bearer some-token")
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	testutil.AssertEqual(t, "status", http.StatusOK, recorder.Code)
+	testutil.AssertContainsAll(t, recorder.Body.String(), []string{`"total_results":0`})
+}
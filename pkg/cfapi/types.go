@@ -0,0 +1,122 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cfapi
+
+// Link is a hyperlink to a related resource.
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method,omitempty"`
+}
+
+// Relationship is a to-one relationship to another resource.
+type Relationship struct {
+	Data *RelationshipData `json:"data"`
+}
+
+// RelationshipData identifies the target of a Relationship.
+type RelationshipData struct {
+	GUID string `json:"guid"`
+}
+
+// Lifecycle describes how an App is staged.
+type Lifecycle struct {
+	Type string                 `json:"type"`
+	Data map[string]interface{} `json:"data"`
+}
+
+// Pagination describes the pages of a List. Kf returns every result on a
+// single page.
+type Pagination struct {
+	TotalResults int   `json:"total_results"`
+	TotalPages   int   `json:"total_pages"`
+	First        Link  `json:"first"`
+	Last         Link  `json:"last"`
+	Next         *Link `json:"next"`
+	Previous     *Link `json:"previous"`
+}
+
+// List is a paginated list of resources.
+type List struct {
+	Pagination Pagination  `json:"pagination"`
+	Resources  interface{} `json:"resources"`
+}
+
+// App is a CF v3 app.
+type App struct {
+	GUID          string                  `json:"guid"`
+	Name          string                  `json:"name"`
+	State         string                  `json:"state"`
+	CreatedAt     string                  `json:"created_at"`
+	UpdatedAt     string                  `json:"updated_at"`
+	Lifecycle     Lifecycle               `json:"lifecycle"`
+	Relationships map[string]Relationship `json:"relationships"`
+	Links         map[string]Link         `json:"links"`
+}
+
+// Space is a CF v3 space.
+type Space struct {
+	GUID      string          `json:"guid"`
+	Name      string          `json:"name"`
+	CreatedAt string          `json:"created_at"`
+	UpdatedAt string          `json:"updated_at"`
+	Links     map[string]Link `json:"links"`
+}
+
+// Route is a CF v3 route.
+type Route struct {
+	GUID          string                  `json:"guid"`
+	Host          string                  `json:"host"`
+	Path          string                  `json:"path"`
+	URL           string                  `json:"url"`
+	CreatedAt     string                  `json:"created_at"`
+	UpdatedAt     string                  `json:"updated_at"`
+	Destinations  []Destination           `json:"destinations"`
+	Relationships map[string]Relationship `json:"relationships"`
+	Links         map[string]Link         `json:"links"`
+}
+
+// Destination is an App a Route sends traffic to.
+type Destination struct {
+	App DestinationApp `json:"app"`
+}
+
+// DestinationApp identifies the App of a Destination.
+type DestinationApp struct {
+	GUID string `json:"guid"`
+}
+
+// Build is a CF v3 build.
+type Build struct {
+	GUID          string                  `json:"guid"`
+	State         string                  `json:"state"`
+	Error         *string                 `json:"error"`
+	CreatedAt     string                  `json:"created_at"`
+	UpdatedAt     string                  `json:"updated_at"`
+	Lifecycle     Lifecycle               `json:"lifecycle"`
+	Relationships map[string]Relationship `json:"relationships"`
+	Links         map[string]Link         `json:"links"`
+}
+
+// Error is a single CF v3 API error.
+type Error struct {
+	Code   int    `json:"code"`
+	Title  string `json:"title"`
+	Detail string `json:"detail"`
+}
+
+// ErrorList is the body of every failed CF v3 API response.
+type ErrorList struct {
+	Errors []Error `json:"errors"`
+}
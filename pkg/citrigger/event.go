@@ -0,0 +1,129 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package citrigger
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+const (
+	githubEventHeader        = "X-GitHub-Event"
+	githubSignatureHeader    = "X-Hub-Signature"
+	githubSignature256Header = "X-Hub-Signature-256"
+	gitlabTokenHeader        = "X-Gitlab-Token"
+
+	// zeroCommit is the commit pushes that delete a branch point to.
+	zeroCommit = "0000000000000000000000000000000000000000"
+)
+
+var (
+	// ErrUnauthenticated is returned if a webhook isn't signed with the
+	// trigger's secret.
+	ErrUnauthenticated = errors.New("webhook isn't signed with the trigger's secret")
+
+	// ErrNotPush is returned for webhook events other than pushes.
+	ErrNotPush = errors.New("webhook isn't a push event")
+)
+
+// PushEvent holds the fields common to GitHub and GitLab push events.
+type PushEvent struct {
+	// Ref is the full name of the pushed ref e.g. refs/heads/master.
+	Ref string `json:"ref"`
+
+	// After is the commit the ref points to after the push.
+	After string `json:"after"`
+}
+
+// Branch returns the branch that was pushed to, or an empty string if a tag
+// was pushed.
+func (e *PushEvent) Branch() string {
+	if !strings.HasPrefix(e.Ref, "refs/heads/") {
+		return ""
+	}
+
+	return strings.TrimPrefix(e.Ref, "refs/heads/")
+}
+
+// Deleted returns true if the push deleted the ref.
+func (e *PushEvent) Deleted() bool {
+	return e.After == "" || e.After == zeroCommit
+}
+
+// Authenticate checks the webhook was sent by a provider configured with the
+// secret. GitHub signs the body with an HMAC and GitLab sends the secret
+// as-is.
+func Authenticate(header http.Header, body, secret []byte) error {
+	switch {
+	case header.Get(githubSignature256Header) != "":
+		return checkSignature(header.Get(githubSignature256Header), "sha256=", sha256.New, body, secret)
+	case header.Get(githubSignatureHeader) != "":
+		return checkSignature(header.Get(githubSignatureHeader), "sha1=", sha1.New, body, secret)
+	case header.Get(gitlabTokenHeader) != "":
+		if subtle.ConstantTimeCompare([]byte(header.Get(gitlabTokenHeader)), secret) != 1 {
+			return ErrUnauthenticated
+		}
+		return nil
+	default:
+		return ErrUnauthenticated
+	}
+}
+
+func checkSignature(signature, prefix string, newHash func() hash.Hash, body, secret []byte) error {
+	if !strings.HasPrefix(signature, prefix) {
+		return ErrUnauthenticated
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return ErrUnauthenticated
+	}
+
+	mac := hmac.New(newHash, secret)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return ErrUnauthenticated
+	}
+
+	return nil
+}
+
+// ParsePushEvent reads a push event from an authenticated webhook body.
+// GitHub identifies the event type in a header, GitLab's push events have the
+// same fields so any body with a ref is treated as a push.
+func ParsePushEvent(header http.Header, body []byte) (*PushEvent, error) {
+	if event := header.Get(githubEventHeader); event != "" && event != "push" {
+		return nil, ErrNotPush
+	}
+
+	event := &PushEvent{}
+	if err := json.Unmarshal(body, event); err != nil {
+		return nil, err
+	}
+
+	if event.Ref == "" {
+		return nil, ErrNotPush
+	}
+
+	return event, nil
+}
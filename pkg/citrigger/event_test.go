@@ -0,0 +1,140 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package citrigger_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"net/http"
+	"testing"
+
+	"github.com/google/kf/pkg/citrigger"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func sign(newHash func() hash.Hash, secret, body string) string {
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestAuthenticate(t *testing.T) {
+	t.Parallel()
+
+	body := `{"ref":"refs/heads/master"}`
+
+	cases := map[string]struct {
+		header  http.Header
+		wantErr error
+	}{
+		"GitHub sha256": {
+			header: http.Header{"X-Hub-Signature-256": {"sha256=" + sign(sha256.New, "some-secret", body)}},
+		},
+		"GitHub sha1": {
+			header: http.Header{"X-Hub-Signature": {"sha1=" + sign(sha1.New, "some-secret", body)}},
+		},
+		"GitHub wrong secret": {
+			header:  http.Header{"X-Hub-Signature-256": {"sha256=" + sign(sha256.New, "other-secret", body)}},
+			wantErr: citrigger.ErrUnauthenticated,
+		},
+		"GitHub malformed signature": {
+			header:  http.Header{"X-Hub-Signature-256": {"sha256=not-hex"}},
+			wantErr: citrigger.ErrUnauthenticated,
+		},
+		"GitHub wrong algorithm prefix": {
+			header:  http.Header{"X-Hub-Signature-256": {"sha1=" + sign(sha256.New, "some-secret", body)}},
+			wantErr: citrigger.ErrUnauthenticated,
+		},
+		"GitLab token": {
+			header: http.Header{"X-Gitlab-Token": {"some-secret"}},
+		},
+		"GitLab wrong token": {
+			header:  http.Header{"X-Gitlab-Token": {"other-secret"}},
+			wantErr: citrigger.ErrUnauthenticated,
+		},
+		"unsigned": {
+			header:  http.Header{},
+			wantErr: citrigger.ErrUnauthenticated,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			err := citrigger.Authenticate(tc.header, []byte(body), []byte("some-secret"))
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestParsePushEvent(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		header      http.Header
+		body        string
+		wantBranch  string
+		wantDeleted bool
+		wantErr     error
+	}{
+		"GitHub push": {
+			header:     http.Header{"X-Github-Event": {"push"}},
+			body:       `{"ref":"refs/heads/master","after":"abc123"}`,
+			wantBranch: "master",
+		},
+		"GitHub ping": {
+			header:  http.Header{"X-Github-Event": {"ping"}},
+			body:    `{"zen":"Keep it logically awesome."}`,
+			wantErr: citrigger.ErrNotPush,
+		},
+		"GitLab push": {
+			header:     http.Header{"X-Gitlab-Event": {"Push Hook"}},
+			body:       `{"object_kind":"push","ref":"refs/heads/release","after":"abc123"}`,
+			wantBranch: "release",
+		},
+		"tag push": {
+			body: `{"ref":"refs/tags/v1.0.0","after":"abc123"}`,
+		},
+		"branch deleted": {
+			body:        `{"ref":"refs/heads/master","after":"0000000000000000000000000000000000000000"}`,
+			wantBranch:  "master",
+			wantDeleted: true,
+		},
+		"no ref": {
+			body:    `{"object_kind":"issue"}`,
+			wantErr: citrigger.ErrNotPush,
+		},
+		"bad JSON": {
+			body:    `{`,
+			wantErr: errors.New("unexpected end of JSON input"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			event, err := citrigger.ParsePushEvent(tc.header, []byte(tc.body))
+			if tc.wantErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "branch", tc.wantBranch, event.Branch())
+			testutil.AssertEqual(t, "deleted", tc.wantDeleted, event.Deleted())
+		})
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package citrigger
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kfv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	"github.com/gorilla/mux"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// maxBodySize is the largest webhook body that's read, GitHub caps payloads
+// at 25MB.
+const maxBodySize = 25 << 20
+
+// WebhookPath returns the path on the receiver webhooks for a GitTrigger are
+// sent to.
+func WebhookPath(namespace, name string) string {
+	return path.Join("/", namespace, name)
+}
+
+type receiver struct {
+	triggers kfv1alpha1.GitTriggersGetter
+	secrets  corev1.SecretsGetter
+}
+
+// NewHandler creates a handler that records pushes on the GitTriggers
+// webhooks are sent for.
+func NewHandler(triggers kfv1alpha1.GitTriggersGetter, secrets corev1.SecretsGetter) http.Handler {
+	r := &receiver{
+		triggers: triggers,
+		secrets:  secrets,
+	}
+
+	router := mux.NewRouter()
+	router.HandleFunc("/{namespace}/{name}", r.receive).Methods(http.MethodPost)
+	return router
+}
+
+func (r *receiver) receive(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+	namespace, name := vars["namespace"], vars["name"]
+
+	body, err := ioutil.ReadAll(io.LimitReader(req.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, "couldn't read the webhook body", http.StatusBadRequest)
+		return
+	}
+
+	trigger, err := r.triggers.GitTriggers(namespace).Get(name, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		http.Error(w, "trigger not found", http.StatusNotFound)
+		return
+	case err != nil:
+		log.Printf("couldn't get GitTrigger %s/%s: %s", namespace, name, err)
+		http.Error(w, "couldn't get the trigger", http.StatusInternalServerError)
+		return
+	}
+
+	// Errors about the Secret are only logged so callers can't use them to
+	// learn about the Space.
+	secret, err := r.secrets.Secrets(namespace).Get(trigger.Spec.SecretName, metav1.GetOptions{})
+	if err != nil {
+		log.Printf("couldn't get Secret for GitTrigger %s/%s: %s", namespace, name, err)
+		http.Error(w, "couldn't authenticate the webhook", http.StatusInternalServerError)
+		return
+	}
+
+	key := secret.Data[v1alpha1.GitTriggerSecretKey]
+	if len(key) == 0 {
+		log.Printf("Secret for GitTrigger %s/%s has no %q key", namespace, name, v1alpha1.GitTriggerSecretKey)
+		http.Error(w, "couldn't authenticate the webhook", http.StatusInternalServerError)
+		return
+	}
+
+	if err := Authenticate(req.Header, body, key); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	event, err := ParsePushEvent(req.Header, body)
+	switch {
+	case err == ErrNotPush:
+		fmt.Fprintln(w, "ignored, not a push event")
+		return
+	case err != nil:
+		http.Error(w, fmt.Sprintf("couldn't parse the push event: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if event.Branch() != trigger.Spec.Branch || event.Deleted() {
+		fmt.Fprintf(w, "ignored, trigger follows branch %q\n", trigger.Spec.Branch)
+		return
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest, err := r.triggers.GitTriggers(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if latest.Spec.Revision == event.After {
+			return nil
+		}
+
+		toUpdate := latest.DeepCopy()
+		toUpdate.Spec.Revision = event.After
+		_, err = r.triggers.GitTriggers(namespace).Update(toUpdate)
+		return err
+	})
+	if err != nil {
+		log.Printf("couldn't update GitTrigger %s/%s: %s", namespace, name, err)
+		http.Error(w, "couldn't update the trigger", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	fmt.Fprintf(w, "rebuilding App %q from %s\n", trigger.Spec.AppName, event.After)
+}
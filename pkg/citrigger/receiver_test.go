@@ -0,0 +1,134 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package citrigger_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/citrigger"
+	kffake "github.com/google/kf/pkg/client/clientset/versioned/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWebhookPath(t *testing.T) {
+	t.Parallel()
+
+	testutil.AssertEqual(t, "path", "/some-space/some-app", citrigger.WebhookPath("some-space", "some-app"))
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Parallel()
+
+	pushBody := `{"ref":"refs/heads/master","after":"abc123"}`
+
+	cases := map[string]struct {
+		path         string
+		header       http.Header
+		body         string
+		emptySecret  bool
+		wantStatus   int
+		wantRevision string
+	}{
+		"push to followed branch": {
+			path:         "/some-space/some-app",
+			header:       http.Header{"X-Gitlab-Token": {"some-secret"}},
+			body:         pushBody,
+			wantStatus:   http.StatusAccepted,
+			wantRevision: "abc123",
+		},
+		"push to other branch": {
+			path:       "/some-space/some-app",
+			header:     http.Header{"X-Gitlab-Token": {"some-secret"}},
+			body:       `{"ref":"refs/heads/feature","after":"abc123"}`,
+			wantStatus: http.StatusOK,
+		},
+		"not a push": {
+			path:       "/some-space/some-app",
+			header:     http.Header{"X-Gitlab-Token": {"some-secret"}, "X-Github-Event": {"ping"}},
+			body:       `{}`,
+			wantStatus: http.StatusOK,
+		},
+		"wrong secret": {
+			path:       "/some-space/some-app",
+			header:     http.Header{"X-Gitlab-Token": {"other-secret"}},
+			body:       pushBody,
+			wantStatus: http.StatusUnauthorized,
+		},
+		"empty secret": {
+			path:        "/some-space/some-app",
+			header:      http.Header{"X-Gitlab-Token": {"some-secret"}},
+			body:        pushBody,
+			emptySecret: true,
+			wantStatus:  http.StatusInternalServerError,
+		},
+		"unknown trigger": {
+			path:       "/some-space/other-app",
+			header:     http.Header{"X-Gitlab-Token": {"some-secret"}},
+			body:       pushBody,
+			wantStatus: http.StatusNotFound,
+		},
+		"malformed push": {
+			path:       "/some-space/some-app",
+			header:     http.Header{"X-Gitlab-Token": {"some-secret"}},
+			body:       `{`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			secretValue := "some-secret"
+			if tc.emptySecret {
+				secretValue = ""
+			}
+
+			kfClient := kffake.NewSimpleClientset(&v1alpha1.GitTrigger{
+				ObjectMeta: metav1.ObjectMeta{Name: "some-app", Namespace: "some-space"},
+				Spec: v1alpha1.GitTriggerSpec{
+					AppName:    "some-app",
+					Repository: "https://github.com/some/repo",
+					Branch:     "master",
+					SecretName: "some-app-ci-trigger",
+				},
+			})
+			k8sClient := k8sfake.NewSimpleClientset(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "some-app-ci-trigger", Namespace: "some-space"},
+				Data:       map[string][]byte{v1alpha1.GitTriggerSecretKey: []byte(secretValue)},
+			})
+
+			handler := citrigger.NewHandler(kfClient.KfV1alpha1(), k8sClient.CoreV1())
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			for k, v := range tc.header {
+				req.Header[k] = v
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			testutil.AssertEqual(t, "status", tc.wantStatus, rec.Code)
+
+			trigger, err := kfClient.KfV1alpha1().GitTriggers("some-space").Get("some-app", metav1.GetOptions{})
+			testutil.AssertNil(t, "get err", err)
+			testutil.AssertEqual(t, "revision", tc.wantRevision, trigger.Spec.Revision)
+		})
+	}
+}
@@ -30,6 +30,10 @@ func (c *FakeKfV1alpha1) Apps(namespace string) v1alpha1.AppInterface {
 	return &FakeApps{c, namespace}
 }
 
+func (c *FakeKfV1alpha1) GitTriggers(namespace string) v1alpha1.GitTriggerInterface {
+	return &FakeGitTriggers{c, namespace}
+}
+
 func (c *FakeKfV1alpha1) Routes(namespace string) v1alpha1.RouteInterface {
 	return &FakeRoutes{c, namespace}
 }
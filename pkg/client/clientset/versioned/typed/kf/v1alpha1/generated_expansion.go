@@ -18,6 +18,8 @@ package v1alpha1
 
 type AppExpansion interface{}
 
+type GitTriggerExpansion interface{}
+
 type RouteExpansion interface{}
 
 type RouteClaimExpansion interface{}
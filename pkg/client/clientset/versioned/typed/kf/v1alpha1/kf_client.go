@@ -26,6 +26,7 @@ import (
 type KfV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	AppsGetter
+	GitTriggersGetter
 	RoutesGetter
 	RouteClaimsGetter
 	SourcesGetter
@@ -41,6 +42,10 @@ func (c *KfV1alpha1Client) Apps(namespace string) AppInterface {
 	return newApps(c, namespace)
 }
 
+func (c *KfV1alpha1Client) GitTriggers(namespace string) GitTriggerInterface {
+	return newGitTriggers(c, namespace)
+}
+
 func (c *KfV1alpha1Client) Routes(namespace string) RouteInterface {
 	return newRoutes(c, namespace)
 }
@@ -24,6 +24,8 @@ import (
 type Interface interface {
 	// Apps returns a AppInformer.
 	Apps() AppInformer
+	// GitTriggers returns a GitTriggerInformer.
+	GitTriggers() GitTriggerInformer
 	// Routes returns a RouteInformer.
 	Routes() RouteInformer
 	// RouteClaims returns a RouteClaimInformer.
@@ -50,6 +52,11 @@ func (v *version) Apps() AppInformer {
 	return &appInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// GitTriggers returns a GitTriggerInformer.
+func (v *version) GitTriggers() GitTriggerInformer {
+	return &gitTriggerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Routes returns a RouteInformer.
 func (v *version) Routes() RouteInformer {
 	return &routeInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -24,6 +24,14 @@ type AppListerExpansion interface{}
 // AppNamespaceLister.
 type AppNamespaceListerExpansion interface{}
 
+// GitTriggerListerExpansion allows custom methods to be added to
+// GitTriggerLister.
+type GitTriggerListerExpansion interface{}
+
+// GitTriggerNamespaceListerExpansion allows custom methods to be added to
+// GitTriggerNamespaceLister.
+type GitTriggerNamespaceListerExpansion interface{}
+
 // RouteListerExpansion allows custom methods to be added to
 // RouteLister.
 type RouteListerExpansion interface{}
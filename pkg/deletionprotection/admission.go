@@ -0,0 +1,125 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deletionprotection
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kfv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// Path is the path the webhook is served on.
+const Path = "/deletion-protection"
+
+type admitter struct {
+	kf         kfv1alpha1.KfV1alpha1Interface
+	namespaces corev1client.NamespacesGetter
+}
+
+// NewHandler creates a handler for AdmissionReviews that rejects deletes of
+// protected Apps and Spaces.
+func NewHandler(kf kfv1alpha1.KfV1alpha1Interface, namespaces corev1client.NamespacesGetter) http.Handler {
+	a := &admitter{
+		kf:         kf,
+		namespaces: namespaces,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, a.serve)
+	return mux
+}
+
+func (a *admitter) serve(w http.ResponseWriter, r *http.Request) {
+	var review admissionv1beta1.AdmissionReview
+	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+		http.Error(w, fmt.Sprintf("couldn't decode the AdmissionReview: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if review.Request == nil {
+		http.Error(w, "the AdmissionReview has no request", http.StatusBadRequest)
+		return
+	}
+
+	response := a.admit(review.Request)
+	response.UID = review.Request.UID
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(admissionv1beta1.AdmissionReview{Response: response}); err != nil {
+		log.Printf("couldn't write the AdmissionReview: %s", err)
+	}
+}
+
+func (a *admitter) admit(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	allowed := &admissionv1beta1.AdmissionResponse{Allowed: true}
+	if req.Operation != admissionv1beta1.Delete {
+		return allowed
+	}
+
+	// Deletes don't include the object before Kubernetes 1.15 so it's always
+	// fetched.
+	obj, err := a.get(req)
+	switch {
+	case apierrs.IsNotFound(err):
+		return allowed
+	case err != nil:
+		return deny(req, fmt.Sprintf("couldn't check deletion protection: %s", err))
+	case obj == nil || !v1alpha1.IsDeletionProtected(obj):
+		return allowed
+	}
+
+	// Apps are deleted with the namespace of a Space that's being deleted,
+	// only the Space itself is protected from that.
+	if req.Namespace != "" {
+		ns, err := a.namespaces.Namespaces().Get(req.Namespace, metav1.GetOptions{})
+		if err == nil && ns.Status.Phase == corev1.NamespaceTerminating {
+			return allowed
+		}
+	}
+
+	return deny(req, "deletion protection is enabled, turn it off or delete with --force-delete-protected")
+}
+
+func (a *admitter) get(req *admissionv1beta1.AdmissionRequest) (metav1.Object, error) {
+	switch req.Resource.Resource {
+	case "apps":
+		return a.kf.Apps(req.Namespace).Get(req.Name, metav1.GetOptions{})
+	case "spaces":
+		return a.kf.Spaces().Get(req.Name, metav1.GetOptions{})
+	default:
+		return nil, nil
+	}
+}
+
+func deny(req *admissionv1beta1.AdmissionRequest, reason string) *admissionv1beta1.AdmissionResponse {
+	gr := schema.GroupResource{Group: req.Resource.Group, Resource: req.Resource.Resource}
+	status := apierrs.NewForbidden(gr, req.Name, errors.New(reason)).Status()
+
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: false,
+		Result:  &status,
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deletionprotection_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kffake "github.com/google/kf/pkg/client/clientset/versioned/fake"
+	"github.com/google/kf/pkg/deletionprotection"
+	"github.com/google/kf/pkg/kf/testutil"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewHandler(t *testing.T) {
+	t.Parallel()
+
+	app := func(protected bool) *v1alpha1.App {
+		app := &v1alpha1.App{ObjectMeta: metav1.ObjectMeta{Name: "some-app", Namespace: "some-space"}}
+		v1alpha1.SetDeletionProtection(app, protected)
+		return app
+	}
+
+	appsResource := metav1.GroupVersionResource{Group: "kf.dev", Version: "v1alpha1", Resource: "apps"}
+	spacesResource := metav1.GroupVersionResource{Group: "kf.dev", Version: "v1alpha1", Resource: "spaces"}
+
+	cases := map[string]struct {
+		operation   admissionv1beta1.Operation
+		resource    metav1.GroupVersionResource
+		namespace   string
+		name        string
+		objects     []runtime.Object
+		terminating bool
+		wantAllowed bool
+	}{
+		"protected app": {
+			operation: admissionv1beta1.Delete,
+			resource:  appsResource,
+			namespace: "some-space",
+			name:      "some-app",
+			objects: []runtime.Object{
+				app(true),
+			},
+			wantAllowed: false,
+		},
+		"unprotected app": {
+			operation: admissionv1beta1.Delete,
+			resource:  appsResource,
+			namespace: "some-space",
+			name:      "some-app",
+			objects: []runtime.Object{
+				app(false),
+			},
+			wantAllowed: true,
+		},
+		"protected app in a space being deleted": {
+			operation: admissionv1beta1.Delete,
+			resource:  appsResource,
+			namespace: "some-space",
+			name:      "some-app",
+			objects: []runtime.Object{
+				app(true),
+			},
+			terminating: true,
+			wantAllowed: true,
+		},
+		"protected space": {
+			operation: admissionv1beta1.Delete,
+			resource:  spacesResource,
+			name:      "some-space",
+			objects: []runtime.Object{
+				&v1alpha1.Space{ObjectMeta: metav1.ObjectMeta{
+					Name:        "some-space",
+					Annotations: map[string]string{v1alpha1.DeletionProtectionAnnotation: "true"},
+				}},
+			},
+			wantAllowed: false,
+		},
+		"missing app": {
+			operation:   admissionv1beta1.Delete,
+			resource:    appsResource,
+			namespace:   "some-space",
+			name:        "some-app",
+			wantAllowed: true,
+		},
+		"not a delete": {
+			operation:   admissionv1beta1.Update,
+			resource:    appsResource,
+			namespace:   "some-space",
+			name:        "some-app",
+			wantAllowed: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			phase := corev1.NamespaceActive
+			if tc.terminating {
+				phase = corev1.NamespaceTerminating
+			}
+
+			kubeClient := k8sfake.NewSimpleClientset(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "some-space"},
+				Status:     corev1.NamespaceStatus{Phase: phase},
+			})
+			kfClient := kffake.NewSimpleClientset(tc.objects...)
+
+			handler := deletionprotection.NewHandler(kfClient.KfV1alpha1(), kubeClient.CoreV1())
+
+			body, err := json.Marshal(admissionv1beta1.AdmissionReview{
+				Request: &admissionv1beta1.AdmissionRequest{
+					UID:       "some-uid",
+					Operation: tc.operation,
+					Resource:  tc.resource,
+					Namespace: tc.namespace,
+					Name:      tc.name,
+				},
+			})
+			testutil.AssertNil(t, "marshal err", err)
+
+			req := httptest.NewRequest(http.MethodPost, deletionprotection.Path, bytes.NewReader(body))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			testutil.AssertEqual(t, "status", http.StatusOK, rec.Code)
+
+			var review admissionv1beta1.AdmissionReview
+			testutil.AssertNil(t, "unmarshal err", json.Unmarshal(rec.Body.Bytes(), &review))
+			testutil.AssertEqual(t, "uid", "some-uid", string(review.Response.UID))
+			testutil.AssertEqual(t, "allowed", tc.wantAllowed, review.Response.Allowed)
+
+			if !tc.wantAllowed {
+				testutil.AssertContainsAll(t, review.Response.Result.Message, []string{
+					"deletion protection is enabled",
+					"--force-delete-protected",
+				})
+			}
+		})
+	}
+}
+
+func TestNewHandler_badRequest(t *testing.T) {
+	t.Parallel()
+
+	handler := deletionprotection.NewHandler(kffake.NewSimpleClientset().KfV1alpha1(), k8sfake.NewSimpleClientset().CoreV1())
+
+	req := httptest.NewRequest(http.MethodPost, deletionprotection.Path, bytes.NewReader([]byte("{}")))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	testutil.AssertEqual(t, "status", http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,230 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deletionprotection
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"go.uber.org/zap"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/webhook"
+)
+
+// Options configure the webhook server.
+type Options struct {
+	// WebhookName is the name of the ValidatingWebhookConfiguration.
+	WebhookName string
+
+	// ServiceName is the name of the Service in front of the server, the
+	// server's certificate is generated for it.
+	ServiceName string
+
+	// DeploymentName is the Deployment running the server, it owns the
+	// ValidatingWebhookConfiguration.
+	DeploymentName string
+
+	// SecretName is the Secret the server's certificates are stored in so
+	// every replica serves the same ones.
+	SecretName string
+
+	// Namespace holds the Service, Deployment and Secret.
+	Namespace string
+
+	// Port is the port the server listens on.
+	Port int
+}
+
+// Keys of the certificates in the Secret.
+const (
+	secretServerKey  = "server-key.pem"
+	secretServerCert = "server-cert.pem"
+	secretCACert     = "ca-cert.pem"
+)
+
+// Run loads the certificate for the Service, registers the webhook, and
+// serves it until stop is closed.
+func Run(stop <-chan struct{}, client kubernetes.Interface, handler http.Handler, opts Options, logger *zap.SugaredLogger) error {
+	ctx := logging.WithLogger(context.Background(), logger)
+
+	serverKey, serverCert, caCert, err := getOrCreateCerts(ctx, client, opts)
+	if err != nil {
+		return fmt.Errorf("couldn't get certificates: %s", err)
+	}
+
+	cert, err := tls.X509KeyPair(serverCert, serverKey)
+	if err != nil {
+		return err
+	}
+
+	if err := Register(client, opts, caCert); err != nil {
+		return fmt.Errorf("couldn't register the webhook: %s", err)
+	}
+	logger.Infof("Registered webhook %s", opts.WebhookName)
+
+	server := &http.Server{
+		Handler:   handler,
+		Addr:      fmt.Sprintf(":%d", opts.Port),
+		TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}},
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServeTLS("", "")
+	}()
+
+	select {
+	case <-stop:
+		return server.Close()
+	case err := <-errs:
+		return err
+	}
+}
+
+// getOrCreateCerts reads the server's certificates from the Secret so all
+// replicas serve the same ones. The first replica to start creates them.
+func getOrCreateCerts(ctx context.Context, client kubernetes.Interface, opts Options) (serverKey, serverCert, caCert []byte, err error) {
+	secrets := client.CoreV1().Secrets(opts.Namespace)
+
+	secret, err := secrets.Get(opts.SecretName, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) {
+		secret, err = createCertsSecret(ctx, secrets, opts)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	var ok bool
+	if serverKey, ok = secret.Data[secretServerKey]; !ok {
+		return nil, nil, nil, errors.New("server key missing")
+	}
+	if serverCert, ok = secret.Data[secretServerCert]; !ok {
+		return nil, nil, nil, errors.New("server cert missing")
+	}
+	if caCert, ok = secret.Data[secretCACert]; !ok {
+		return nil, nil, nil, errors.New("ca cert missing")
+	}
+
+	return serverKey, serverCert, caCert, nil
+}
+
+// createCertsSecret generates certificates for the Service and stores them in
+// the Secret. If another replica created the Secret first, its certificates
+// are returned instead.
+func createCertsSecret(ctx context.Context, secrets corev1client.SecretInterface, opts Options) (*corev1.Secret, error) {
+	serverKey, serverCert, caCert, err := webhook.CreateCerts(ctx, opts.ServiceName, opts.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := secrets.Create(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      opts.SecretName,
+			Namespace: opts.Namespace,
+		},
+		Data: map[string][]byte{
+			secretServerKey:  serverKey,
+			secretServerCert: serverCert,
+			secretCACert:     caCert,
+		},
+	})
+	if apierrs.IsAlreadyExists(err) {
+		return secrets.Get(opts.SecretName, metav1.GetOptions{})
+	}
+
+	return secret, err
+}
+
+// Register creates or updates the ValidatingWebhookConfiguration that sends
+// deletes of Apps and Spaces to the webhook.
+func Register(client kubernetes.Interface, opts Options, caCert []byte) error {
+	path := Path
+
+	// Deletes are rejected while the webhook is unavailable, otherwise an
+	// outage would silently turn protection off. To limit what an outage
+	// blocks, only Apps in namespaces managed by Kf are sent to the webhook,
+	// Spaces are cluster scoped so they're always sent.
+	failurePolicy := admissionregistrationv1beta1.Fail
+
+	config := &admissionregistrationv1beta1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: opts.WebhookName,
+		},
+		Webhooks: []admissionregistrationv1beta1.Webhook{{
+			Name: opts.WebhookName,
+			Rules: []admissionregistrationv1beta1.RuleWithOperations{{
+				Operations: []admissionregistrationv1beta1.OperationType{
+					admissionregistrationv1beta1.Delete,
+				},
+				Rule: admissionregistrationv1beta1.Rule{
+					APIGroups:   []string{v1alpha1.SchemeGroupVersion.Group},
+					APIVersions: []string{v1alpha1.SchemeGroupVersion.Version},
+					Resources:   []string{"apps", "spaces"},
+				},
+			}},
+			ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
+				Service: &admissionregistrationv1beta1.ServiceReference{
+					Namespace: opts.Namespace,
+					Name:      opts.ServiceName,
+					Path:      &path,
+				},
+				CABundle: caCert,
+			},
+			FailurePolicy: &failurePolicy,
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					v1alpha1.ManagedByLabel: "kf",
+				},
+			},
+		}},
+	}
+
+	// The Deployment owns the configuration so it's removed when Kf is
+	// uninstalled.
+	deployment, err := client.AppsV1().Deployments(opts.Namespace).Get(opts.DeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("couldn't get Deployment %s: %s", opts.DeploymentName, err)
+	}
+	config.OwnerReferences = []metav1.OwnerReference{
+		*metav1.NewControllerRef(deployment, appsv1.SchemeGroupVersion.WithKind("Deployment")),
+	}
+
+	configs := client.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations()
+	existing, err := configs.Get(opts.WebhookName, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		_, err = configs.Create(config)
+		return err
+	case err != nil:
+		return err
+	}
+
+	// The configuration is updated in case a previous version of Kf
+	// registered it differently.
+	config.ResourceVersion = existing.ResourceVersion
+	_, err = configs.Update(config)
+	return err
+}
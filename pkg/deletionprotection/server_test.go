@@ -0,0 +1,58 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deletionprotection_test
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/deletionprotection"
+	"github.com/google/kf/pkg/kf/testutil"
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRegister(t *testing.T) {
+	t.Parallel()
+
+	opts := deletionprotection.Options{
+		WebhookName:    "deletion-protection.webhook.kf.dev",
+		ServiceName:    "deletion-protection-webhook",
+		DeploymentName: "webhook",
+		SecretName:     "deletion-protection-webhook-certs",
+		Namespace:      "kf",
+	}
+
+	client := k8sfake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook", Namespace: "kf"},
+	})
+
+	// Registering twice, as replicas do, updates the existing configuration.
+	for _, caCert := range []string{"old-ca", "new-ca"} {
+		err := deletionprotection.Register(client, opts, []byte(caCert))
+		testutil.AssertNil(t, "err", err)
+	}
+
+	config, err := client.AdmissionregistrationV1beta1().ValidatingWebhookConfigurations().Get(opts.WebhookName, metav1.GetOptions{})
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "webhooks", 1, len(config.Webhooks))
+	testutil.AssertEqual(t, "owner", "webhook", config.OwnerReferences[0].Name)
+
+	webhook := config.Webhooks[0]
+	testutil.AssertEqual(t, "caBundle", "new-ca", string(webhook.ClientConfig.CABundle))
+	testutil.AssertEqual(t, "failurePolicy", admissionregistrationv1beta1.Fail, *webhook.FailurePolicy)
+	testutil.AssertEqual(t, "namespaceSelector", map[string]string{"app.kubernetes.io/managed-by": "kf"}, webhook.NamespaceSelector.MatchLabels)
+}
@@ -0,0 +1,143 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func ExampleSchedule_Next() {
+	schedule, err := Parse("0 8 * * 1-5")
+	if err != nil {
+		panic(err)
+	}
+
+	// Friday evening
+	now := time.Date(2019, time.August, 2, 20, 0, 0, 0, time.UTC)
+	fmt.Println(schedule.Next(now))
+
+	// Output: 2019-08-05 08:00:00 +0000 UTC
+}
+
+func TestParse_errors(t *testing.T) {
+	cases := map[string]struct {
+		spec    string
+		wantErr error
+	}{
+		"too few fields": {
+			spec:    "0 8 * *",
+			wantErr: errors.New(`expected 5 fields in cron expression "0 8 * *", got 4`),
+		},
+		"not a number": {
+			spec:    "0 eight * * *",
+			wantErr: errors.New(`invalid value "eight" in hour field`),
+		},
+		"out of range": {
+			spec:    "60 8 * * *",
+			wantErr: errors.New("value 60 out of range [0, 59] in minute field"),
+		},
+		"backwards range": {
+			spec:    "0 8 * * 5-1",
+			wantErr: errors.New(`invalid range "5-1" in day of week field`),
+		},
+		"bad step": {
+			spec:    "*/0 8 * * *",
+			wantErr: errors.New(`invalid step "0" in minute field`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			_, err := Parse(tc.spec)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+		})
+	}
+}
+
+func TestSchedule_Next(t *testing.T) {
+	// Thursday, August 1st 2019
+	now := time.Date(2019, time.August, 1, 12, 30, 15, 0, time.UTC)
+
+	cases := map[string]struct {
+		spec     string
+		now      time.Time
+		expected time.Time
+	}{
+		"every minute": {
+			spec:     "* * * * *",
+			expected: time.Date(2019, time.August, 1, 12, 31, 0, 0, time.UTC),
+		},
+		"later today": {
+			spec:     "0 20 * * *",
+			expected: time.Date(2019, time.August, 1, 20, 0, 0, 0, time.UTC),
+		},
+		"tomorrow": {
+			spec:     "0 8 * * *",
+			expected: time.Date(2019, time.August, 2, 8, 0, 0, 0, time.UTC),
+		},
+		"skips weekend": {
+			spec:     "0 8 * * 1-5",
+			now:      time.Date(2019, time.August, 3, 12, 0, 0, 0, time.UTC),
+			expected: time.Date(2019, time.August, 5, 8, 0, 0, 0, time.UTC),
+		},
+		"sunday as seven": {
+			spec:     "0 8 * * 7",
+			expected: time.Date(2019, time.August, 4, 8, 0, 0, 0, time.UTC),
+		},
+		"steps": {
+			spec:     "*/15 * * * *",
+			expected: time.Date(2019, time.August, 1, 12, 45, 0, 0, time.UTC),
+		},
+		"lists": {
+			spec:     "0 6,18 * * *",
+			expected: time.Date(2019, time.August, 1, 18, 0, 0, 0, time.UTC),
+		},
+		"day of month or day of week": {
+			spec:     "0 0 15 * 1",
+			expected: time.Date(2019, time.August, 5, 0, 0, 0, 0, time.UTC),
+		},
+		"next year": {
+			spec:     "0 0 1 1 *",
+			expected: time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		"exact match moves forward": {
+			spec:     "0 8 * * *",
+			now:      time.Date(2019, time.August, 1, 8, 0, 0, 0, time.UTC),
+			expected: time.Date(2019, time.August, 2, 8, 0, 0, 0, time.UTC),
+		},
+		"impossible": {
+			spec:     "0 0 31 2 *",
+			expected: time.Time{},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			schedule, err := Parse(tc.spec)
+			testutil.AssertNil(t, "Parse error", err)
+
+			if tc.now.IsZero() {
+				tc.now = now
+			}
+
+			testutil.AssertEqual(t, "next", tc.expected, schedule.Next(tc.now))
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diffutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// ChangeType describes how a field differs between two objects.
+type ChangeType string
+
+const (
+	// Added fields exist in the new object but not the old one.
+	Added ChangeType = "added"
+
+	// Removed fields exist in the old object but not the new one.
+	Removed ChangeType = "removed"
+
+	// Changed fields exist in both objects with different values.
+	Changed ChangeType = "changed"
+)
+
+// Change is a single field that differs between two objects.
+type Change struct {
+	// Type is how the field changed.
+	Type ChangeType `json:"type"`
+
+	// Path is the JSONPath of the field e.g. .spec.instances.replicas
+	Path string `json:"path"`
+
+	// Old is the value in the old object, unset for added fields.
+	Old interface{} `json:"old,omitempty"`
+
+	// New is the value in the new object, unset for removed fields.
+	New interface{} `json:"new,omitempty"`
+}
+
+// Compute returns the fields that differ between the JSON representations of
+// left and right. Changes are ordered by path so the output is stable.
+func Compute(left, right interface{}) ([]Change, error) {
+	l, err := toGeneric(left)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := toGeneric(right)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	walk("", l, r, &changes)
+
+	return changes, nil
+}
+
+// toGeneric converts the object to the maps, slices and scalars that
+// encoding/json produces so objects can be compared field by field.
+func toGeneric(obj interface{}) (interface{}, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	// Keep numbers as they were written so large integers aren't rounded.
+	decoder.UseNumber()
+
+	var out interface{}
+	if err := decoder.Decode(&out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func walk(path string, left, right interface{}, changes *[]Change) {
+	switch l := left.(type) {
+	case map[string]interface{}:
+		if r, ok := right.(map[string]interface{}); ok {
+			walkMap(path, l, r, changes)
+			return
+		}
+
+	case []interface{}:
+		if r, ok := right.([]interface{}); ok {
+			walkSlice(path, l, r, changes)
+			return
+		}
+	}
+
+	if !reflect.DeepEqual(left, right) {
+		*changes = append(*changes, Change{
+			Type: Changed,
+			Path: rootPath(path),
+			Old:  left,
+			New:  right,
+		})
+	}
+}
+
+func walkMap(path string, left, right map[string]interface{}, changes *[]Change) {
+	keys := make(map[string]bool)
+	for k := range left {
+		keys[k] = true
+	}
+	for k := range right {
+		keys[k] = true
+	}
+
+	var sorted []string
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	for _, k := range sorted {
+		l, inLeft := left[k]
+		r, inRight := right[k]
+		child := fieldPath(path, k)
+
+		switch {
+		case !inLeft:
+			*changes = append(*changes, Change{Type: Added, Path: child, New: r})
+		case !inRight:
+			*changes = append(*changes, Change{Type: Removed, Path: child, Old: l})
+		default:
+			walk(child, l, r, changes)
+		}
+	}
+}
+
+func walkSlice(path string, left, right []interface{}, changes *[]Change) {
+	for i := 0; i < len(left) || i < len(right); i++ {
+		child := path + "[" + strconv.Itoa(i) + "]"
+
+		switch {
+		case i >= len(left):
+			*changes = append(*changes, Change{Type: Added, Path: child, New: right[i]})
+		case i >= len(right):
+			*changes = append(*changes, Change{Type: Removed, Path: child, Old: left[i]})
+		default:
+			walk(child, left[i], right[i], changes)
+		}
+	}
+}
+
+var simpleKey = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+// fieldPath appends the key to the path, quoting keys that contain characters
+// like dots or slashes which are common in labels and annotations.
+func fieldPath(path, key string) string {
+	if simpleKey.MatchString(key) {
+		return path + "." + key
+	}
+
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+func rootPath(path string) string {
+	if path == "" {
+		return "."
+	}
+
+	return path
+}
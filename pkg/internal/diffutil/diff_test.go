@@ -0,0 +1,109 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diffutil_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/kf/pkg/internal/diffutil"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+type testObject struct {
+	Name        string            `json:"name,omitempty"`
+	Replicas    int               `json:"replicas,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Args        []string          `json:"args,omitempty"`
+}
+
+// number converts a literal into the number type Compute produces.
+func number(n string) interface{} {
+	return json.Number(n)
+}
+
+func TestCompute(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		left, right interface{}
+		want        []diffutil.Change
+	}{
+		"no changes": {
+			left:  testObject{Name: "a", Args: []string{"x"}},
+			right: testObject{Name: "a", Args: []string{"x"}},
+		},
+		"changed scalar": {
+			left:  testObject{Name: "a", Replicas: 1},
+			right: testObject{Name: "a", Replicas: 3},
+			want: []diffutil.Change{
+				{Type: diffutil.Changed, Path: ".replicas", Old: number("1"), New: number("3")},
+			},
+		},
+		"added and removed fields": {
+			left:  testObject{Name: "a"},
+			right: testObject{Replicas: 2},
+			want: []diffutil.Change{
+				{Type: diffutil.Removed, Path: ".name", Old: "a"},
+				{Type: diffutil.Added, Path: ".replicas", New: number("2")},
+			},
+		},
+		"annotation keys are quoted": {
+			left: testObject{},
+			right: testObject{Annotations: map[string]string{
+				"kf.dev/owner": "me",
+			}},
+			want: []diffutil.Change{
+				{Type: diffutil.Added, Path: ".annotations", New: map[string]interface{}{"kf.dev/owner": "me"}},
+			},
+		},
+		"nested map keys": {
+			left: testObject{Annotations: map[string]string{
+				"kf.dev/owner": "me",
+				"plain":        "same",
+			}},
+			right: testObject{Annotations: map[string]string{
+				"kf.dev/owner": "you",
+				"plain":        "same",
+			}},
+			want: []diffutil.Change{
+				{Type: diffutil.Changed, Path: `.annotations["kf.dev/owner"]`, Old: "me", New: "you"},
+			},
+		},
+		"slices": {
+			left:  testObject{Args: []string{"a", "b", "c"}},
+			right: testObject{Args: []string{"a", "x"}},
+			want: []diffutil.Change{
+				{Type: diffutil.Changed, Path: ".args[1]", Old: "b", New: "x"},
+				{Type: diffutil.Removed, Path: ".args[2]", Old: "c"},
+			},
+		},
+		"root type change": {
+			left:  "a",
+			right: 1,
+			want: []diffutil.Change{
+				{Type: diffutil.Changed, Path: ".", Old: "a", New: number("1")},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got, err := diffutil.Compute(tc.left, tc.right)
+			testutil.AssertNil(t, "err", err)
+			testutil.AssertEqual(t, "changes", tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diffutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	isatty "github.com/mattn/go-isatty"
+)
+
+// Format is an output format for diffs.
+type Format string
+
+const (
+	// FormatText prints one line per change for humans.
+	FormatText Format = "text"
+
+	// FormatJSON prints the changes as a JSON document for automation.
+	FormatJSON Format = "json"
+)
+
+// Formats returns the names of the supported formats.
+func Formats() []string {
+	return []string{string(FormatText), string(FormatJSON)}
+}
+
+// ParseFormat converts a user supplied string into a Format.
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case FormatText, FormatJSON:
+		return Format(format), nil
+	default:
+		return "", fmt.Errorf("unknown diff format %q, must be one of: %s", format, strings.Join(Formats(), ", "))
+	}
+}
+
+type options struct {
+	format Format
+	color  *bool
+}
+
+// Option configures how diffs are printed.
+type Option func(*options)
+
+// WithFormat sets the output format, the default is FormatText.
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// WithColor forces colored text output on or off. By default text output is
+// colored if the writer is a terminal.
+func WithColor(enabled bool) Option {
+	return func(o *options) {
+		o.color = &enabled
+	}
+}
+
+// jsonOutput is the document written by FormatJSON.
+type jsonOutput struct {
+	Changes []Change `json:"changes"`
+}
+
+// Fprint computes the diff between left and right and writes it to w. Text
+// output starts with the title, JSON output omits it.
+func Fprint(w io.Writer, title string, left, right interface{}, opts ...Option) error {
+	o := options{format: FormatText}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	changes, err := Compute(left, right)
+	if err != nil {
+		return err
+	}
+
+	switch o.format {
+	case FormatJSON:
+		// Always write a list so consumers don't need to handle null.
+		if changes == nil {
+			changes = []Change{}
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(jsonOutput{Changes: changes})
+
+	case FormatText:
+		colorize := isTerminal(w)
+		if o.color != nil {
+			colorize = *o.color
+		}
+
+		printText(w, title, changes, colorize)
+		return nil
+
+	default:
+		return fmt.Errorf("unknown diff format %q", o.format)
+	}
+}
+
+func printText(w io.Writer, title string, changes []Change, colorize bool) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+
+	added := newColor(colorize, color.FgGreen)
+	removed := newColor(colorize, color.FgRed)
+	changed := newColor(colorize, color.FgYellow)
+
+	fmt.Fprintln(w, title)
+	for _, change := range changes {
+		switch change.Type {
+		case Added:
+			added.Fprintf(w, "+ %s: %s\n", change.Path, formatValue(change.New))
+		case Removed:
+			removed.Fprintf(w, "- %s: %s\n", change.Path, formatValue(change.Old))
+		case Changed:
+			changed.Fprintf(w, "~ %s: %s -> %s\n", change.Path, formatValue(change.Old), formatValue(change.New))
+		}
+	}
+}
+
+func newColor(enabled bool, attr color.Attribute) *color.Color {
+	c := color.New(attr)
+	if enabled {
+		c.EnableColor()
+	} else {
+		c.DisableColor()
+	}
+
+	return c
+}
+
+// formatValue renders values as compact JSON so strings are quoted and
+// nested objects fit on one line.
+func formatValue(value interface{}) string {
+	out, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(out)
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && isatty.IsTerminal(f.Fd())
+}
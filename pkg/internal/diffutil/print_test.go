@@ -0,0 +1,122 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diffutil_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/google/kf/pkg/internal/diffutil"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func ExampleFprint_text() {
+	before := testObject{Name: "my-app", Replicas: 1, Args: []string{"serve"}}
+	after := testObject{Replicas: 3, Args: []string{"serve", "--debug"}}
+
+	diffutil.Fprint(os.Stdout, "App Diff (-old +new):", before, after)
+
+	// Output: App Diff (-old +new):
+	// + .args[1]: "--debug"
+	// - .name: "my-app"
+	// ~ .replicas: 1 -> 3
+}
+
+func ExampleFprint_noChanges() {
+	obj := testObject{Name: "my-app"}
+
+	diffutil.Fprint(os.Stdout, "App Diff (-old +new):", obj, obj)
+
+	// Output: No changes
+}
+
+func ExampleFprint_json() {
+	before := testObject{Name: "my-app", Replicas: 1}
+	after := testObject{Name: "my-app", Replicas: 3}
+
+	diffutil.Fprint(os.Stdout, "App Diff (-old +new):", before, after, diffutil.WithFormat(diffutil.FormatJSON))
+
+	// Output: {
+	//   "changes": [
+	//     {
+	//       "type": "changed",
+	//       "path": ".replicas",
+	//       "old": 1,
+	//       "new": 3
+	//     }
+	//   ]
+	// }
+}
+
+func ExampleFprint_jsonNoChanges() {
+	obj := testObject{Name: "my-app"}
+
+	diffutil.Fprint(os.Stdout, "App Diff (-old +new):", obj, obj, diffutil.WithFormat(diffutil.FormatJSON))
+
+	// Output: {
+	//   "changes": []
+	// }
+}
+
+func TestFprint_color(t *testing.T) {
+	t.Parallel()
+
+	before := testObject{Name: "my-app"}
+	after := testObject{Name: "other-app"}
+
+	buf := &bytes.Buffer{}
+	err := diffutil.Fprint(buf, "Diff:", before, after, diffutil.WithColor(true))
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertContainsAll(t, buf.String(), []string{"\x1b[33m", `~ .name: "my-app" -> "other-app"`})
+
+	buf.Reset()
+	err = diffutil.Fprint(buf, "Diff:", before, after)
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "output", "Diff:\n~ .name: \"my-app\" -> \"other-app\"\n", buf.String())
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		format  string
+		want    diffutil.Format
+		wantErr error
+	}{
+		"text": {format: "text", want: diffutil.FormatText},
+		"json": {format: "json", want: diffutil.FormatJSON},
+		"unknown": {
+			format:  "yaml",
+			wantErr: errors.New(`unknown diff format "yaml", must be one of: text, json`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got, err := diffutil.ParseFormat(tc.format)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "format", tc.want, got)
+		})
+	}
+}
+
+func ExampleFormats() {
+	fmt.Println(diffutil.Formats())
+
+	// Output: [text json]
+}
@@ -17,14 +17,48 @@ package envutil
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"sort"
 	"strings"
 
 	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+// MergeStrategy controls how MergeEnvVars resolves a variable that's declared
+// in more than one layer.
+type MergeStrategy int
+
+const (
+	// MergeOverride lets variables in later layers replace those in earlier
+	// ones.
+	MergeOverride MergeStrategy = iota
+
+	// MergeKeepExisting keeps the first declaration of a variable and ignores
+	// any from later layers.
+	MergeKeepExisting
+
+	// MergeErrorOnConflict returns an error if two layers declare the same
+	// variable with different values.
+	MergeErrorOnConflict
+)
+
+// String implements fmt.Stringer.
+func (m MergeStrategy) String() string {
+	switch m {
+	case MergeOverride:
+		return "override"
+	case MergeKeepExisting:
+		return "keep-existing"
+	case MergeErrorOnConflict:
+		return "error-on-conflict"
+	default:
+		return fmt.Sprintf("MergeStrategy(%d)", int(m))
+	}
+}
+
 // EnvVarsToMap constructs a map of environment name to value from a slice of
 // env vars. Vars with duplicate names will be resolved to the latest one in the
 // list.
@@ -84,16 +118,137 @@ func ParseCLIEnvVars(cliEnv []string) ([]corev1.EnvVar, error) {
 			return nil, fmt.Errorf("malformed environment variable: %s", kv)
 		}
 
+		if err := ValidateEnvVarName(parts[0]); err != nil {
+			return nil, err
+		}
+
 		out[parts[0]] = parts[1]
 	}
 
 	return MapToEnvVars(out), nil
 }
 
+// MergeEnvVars combines layers of environment variables from lowest to highest
+// precedence using the given strategy. Duplicates within a single layer are
+// resolved to the latest one in the layer. The result is sorted by name.
+//
+// Unlike the map based helpers, the whole EnvVar is preserved so variables
+// using ValueFrom survive the merge.
+func MergeEnvVars(strategy MergeStrategy, layers ...[]corev1.EnvVar) ([]corev1.EnvVar, error) {
+	merged := make(map[string]corev1.EnvVar)
+
+	for _, layer := range layers {
+		for _, env := range dedupeLayer(layer) {
+			existing, ok := merged[env.Name]
+			if !ok {
+				merged[env.Name] = env
+				continue
+			}
+
+			switch strategy {
+			case MergeOverride:
+				merged[env.Name] = env
+			case MergeKeepExisting:
+				// Keep the earlier declaration.
+			case MergeErrorOnConflict:
+				if !reflect.DeepEqual(existing, env) {
+					return nil, fmt.Errorf("conflicting values for environment variable %s", env.Name)
+				}
+			default:
+				return nil, fmt.Errorf("unknown merge strategy: %s", strategy)
+			}
+		}
+	}
+
+	var out []corev1.EnvVar
+	for _, env := range merged {
+		out = append(out, env)
+	}
+
+	SortEnvVars(out)
+
+	return out, nil
+}
+
+// OverrideEnvVars combines layers of environment variables with later layers
+// taking priority. It's MergeEnvVars with MergeOverride, which can't fail.
+func OverrideEnvVars(layers ...[]corev1.EnvVar) []corev1.EnvVar {
+	out, _ := MergeEnvVars(MergeOverride, layers...)
+	return out
+}
+
+// dedupeLayer resolves duplicate names in a single layer to the latest
+// declaration.
+func dedupeLayer(layer []corev1.EnvVar) []corev1.EnvVar {
+	latest := make(map[string]int)
+	for i, env := range layer {
+		latest[env.Name] = i
+	}
+
+	var out []corev1.EnvVar
+	for i, env := range layer {
+		if latest[env.Name] == i {
+			out = append(out, env)
+		}
+	}
+
+	return out
+}
+
+// DuplicateEnvVarNames returns the sorted names of variables declared more than
+// once in the list.
+func DuplicateEnvVarNames(envs []corev1.EnvVar) []string {
+	counts := make(map[string]int)
+	for _, env := range envs {
+		counts[env.Name]++
+	}
+
+	var out []string
+	for name, count := range counts {
+		if count > 1 {
+			out = append(out, name)
+		}
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+// ValidateEnvVarName checks that the name is a valid environment variable name
+// and isn't reserved by Kf.
+func ValidateEnvVarName(name string) error {
+	if errs := validation.IsEnvVarName(name); len(errs) > 0 {
+		return fmt.Errorf("invalid environment variable name %q: %s", name, strings.Join(errs, ", "))
+	}
+
+	if v1alpha1.IsReservedEnvName(name) {
+		return fmt.Errorf("environment variable %s is reserved by Kf", name)
+	}
+
+	return nil
+}
+
+// ValidateEnvVars checks the names of every variable in the list and that none
+// are declared more than once.
+func ValidateEnvVars(envs []corev1.EnvVar) error {
+	for _, env := range envs {
+		if err := ValidateEnvVarName(env.Name); err != nil {
+			return err
+		}
+	}
+
+	if dupes := DuplicateEnvVarNames(envs); len(dupes) > 0 {
+		return fmt.Errorf("environment variables declared more than once: %s", strings.Join(dupes, ", "))
+	}
+
+	return nil
+}
+
 // DeduplicateEnvVars deduplicates environment variables and returns the
 // canonical version of them (last environment variable takes preccidence).
 func DeduplicateEnvVars(env []corev1.EnvVar) []corev1.EnvVar {
-	return MapToEnvVars(EnvVarsToMap(env))
+	return OverrideEnvVars(env)
 }
 
 // NewJSONEnvVar converts a value to a JSON string and sets it on the
@@ -107,6 +262,34 @@ func NewJSONEnvVar(key string, value interface{}) (corev1.EnvVar, error) {
 	return corev1.EnvVar{Name: key, Value: string(valueBytes)}, nil
 }
 
+// NewSecretEnvVar creates an environment variable that reads its value from a
+// Secret. The reference is in the form SECRET_NAME[:KEY], if KEY is omitted
+// the variable's name is used as the key.
+func NewSecretEnvVar(name, ref string) (corev1.EnvVar, error) {
+	secretName, key := ref, name
+	if i := strings.Index(ref, ":"); i >= 0 {
+		secretName, key = ref[:i], ref[i+1:]
+	}
+
+	if errs := validation.IsDNS1123Subdomain(secretName); len(errs) > 0 {
+		return corev1.EnvVar{}, fmt.Errorf("invalid Secret name %q: %s", secretName, strings.Join(errs, ", "))
+	}
+
+	if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+		return corev1.EnvVar{}, fmt.Errorf("invalid Secret key %q: %s", key, strings.Join(errs, ", "))
+	}
+
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  key,
+			},
+		},
+	}, nil
+}
+
 // GetAppEnvVars reads the environment variables off a app.
 // Prefer using this function directly rather than accessing nested objects
 // on app so kf can adapt to future changes.
@@ -140,6 +140,10 @@ func TestParseCLIEnvVars(t *testing.T) {
 			vars:        []string{"foo"},
 			expectedErr: errors.New("malformed environment variable: foo"),
 		},
+		"reserved-name": {
+			vars:        []string{"VCAP_SERVICES={}"},
+			expectedErr: errors.New("environment variable VCAP_SERVICES is reserved by Kf"),
+		},
 	}
 
 	for tn, tc := range cases {
@@ -174,6 +178,199 @@ func ExampleDeduplicateEnvVars() {
 	// Key FOO Value 2
 }
 
+func TestMergeEnvVars(t *testing.T) {
+	t.Parallel()
+
+	space := []corev1.EnvVar{
+		{Name: "ENVIRONMENT", Value: "staging"},
+		{Name: "REGION", Value: "us-central1"},
+	}
+	manifest := []corev1.EnvVar{
+		{Name: "ENVIRONMENT", Value: "production"},
+		{Name: "DEBUG", Value: "false"},
+	}
+	cli := []corev1.EnvVar{
+		{Name: "DEBUG", Value: "false"},
+		{Name: "DEBUG", Value: "true"},
+	}
+
+	cases := map[string]struct {
+		strategy    envutil.MergeStrategy
+		layers      [][]corev1.EnvVar
+		expectedEnv []corev1.EnvVar
+		expectedErr error
+	}{
+		"empty": {
+			strategy: envutil.MergeOverride,
+		},
+		"override": {
+			strategy: envutil.MergeOverride,
+			layers:   [][]corev1.EnvVar{space, manifest, cli},
+			expectedEnv: []corev1.EnvVar{
+				{Name: "DEBUG", Value: "true"},
+				{Name: "ENVIRONMENT", Value: "production"},
+				{Name: "REGION", Value: "us-central1"},
+			},
+		},
+		"keep-existing": {
+			strategy: envutil.MergeKeepExisting,
+			layers:   [][]corev1.EnvVar{space, manifest, cli},
+			expectedEnv: []corev1.EnvVar{
+				{Name: "DEBUG", Value: "false"},
+				{Name: "ENVIRONMENT", Value: "staging"},
+				{Name: "REGION", Value: "us-central1"},
+			},
+		},
+		"error-on-conflict with conflict": {
+			strategy:    envutil.MergeErrorOnConflict,
+			layers:      [][]corev1.EnvVar{space, manifest},
+			expectedErr: errors.New("conflicting values for environment variable ENVIRONMENT"),
+		},
+		"error-on-conflict with matching values": {
+			strategy: envutil.MergeErrorOnConflict,
+			layers: [][]corev1.EnvVar{
+				{{Name: "DEBUG", Value: "false"}},
+				manifest,
+			},
+			expectedEnv: []corev1.EnvVar{
+				{Name: "DEBUG", Value: "false"},
+				{Name: "ENVIRONMENT", Value: "production"},
+			},
+		},
+		"duplicates within a layer aren't conflicts": {
+			strategy: envutil.MergeErrorOnConflict,
+			layers:   [][]corev1.EnvVar{cli},
+			expectedEnv: []corev1.EnvVar{
+				{Name: "DEBUG", Value: "true"},
+			},
+		},
+		"value from is preserved": {
+			strategy: envutil.MergeOverride,
+			layers: [][]corev1.EnvVar{
+				{{Name: "POD_IP", Value: "unknown"}},
+				{{Name: "POD_IP", ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+				}}},
+			},
+			expectedEnv: []corev1.EnvVar{
+				{Name: "POD_IP", ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "status.podIP"},
+				}},
+			},
+		},
+		"unknown strategy": {
+			strategy:    envutil.MergeStrategy(42),
+			layers:      [][]corev1.EnvVar{space, space},
+			expectedErr: errors.New("unknown merge strategy: MergeStrategy(42)"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			merged, actualErr := envutil.MergeEnvVars(tc.strategy, tc.layers...)
+			if tc.expectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.expectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertEqual(t, "result", tc.expectedEnv, merged)
+		})
+	}
+}
+
+func ExampleOverrideEnvVars() {
+	space := []corev1.EnvVar{
+		{Name: "ENVIRONMENT", Value: "staging"},
+		{Name: "REGION", Value: "us-central1"},
+	}
+	app := []corev1.EnvVar{
+		{Name: "ENVIRONMENT", Value: "production"},
+	}
+
+	out := envutil.OverrideEnvVars(space, app)
+	for _, e := range out {
+		fmt.Println("Key", e.Name, "Value", e.Value)
+	}
+
+	// Output: Key ENVIRONMENT Value production
+	// Key REGION Value us-central1
+}
+
+func ExampleDuplicateEnvVarNames() {
+	envs := []corev1.EnvVar{
+		{Name: "FOO", Value: "2"},
+		{Name: "BAZZ", Value: "1"},
+		{Name: "FOO", Value: "3"},
+		{Name: "BAR", Value: "0"},
+		{Name: "BAZZ", Value: "1.5"},
+	}
+
+	fmt.Println(envutil.DuplicateEnvVarNames(envs))
+
+	// Output: [BAZZ FOO]
+}
+
+func TestValidateEnvVarName(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		name    string
+		wantErr bool
+	}{
+		"valid":           {name: "JAVA_OPTS"},
+		"dots and dashes": {name: "my.app-config"},
+		"empty":           {name: "", wantErr: true},
+		"leading digit":   {name: "1FOO", wantErr: true},
+		"contains equals": {name: "FOO=BAR", wantErr: true},
+		"reserved":        {name: "PORT", wantErr: true},
+		"reserved prefix": {name: "VCAP_APPLICATION", wantErr: true},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			err := envutil.ValidateEnvVarName(tc.name)
+			testutil.AssertEqual(t, "wantErr", tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestValidateEnvVars(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		envs        []corev1.EnvVar
+		expectedErr error
+	}{
+		"empty": {},
+		"valid": {
+			envs: []corev1.EnvVar{
+				{Name: "FOO", Value: "1"},
+				{Name: "BAR", Value: "2"},
+			},
+		},
+		"reserved": {
+			envs: []corev1.EnvVar{
+				{Name: "PORT", Value: "8080"},
+			},
+			expectedErr: errors.New("environment variable PORT is reserved by Kf"),
+		},
+		"duplicates": {
+			envs: []corev1.EnvVar{
+				{Name: "FOO", Value: "1"},
+				{Name: "BAR", Value: "2"},
+				{Name: "FOO", Value: "3"},
+			},
+			expectedErr: errors.New("environment variables declared more than once: FOO"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertErrorsEqual(t, tc.expectedErr, envutil.ValidateEnvVars(tc.envs))
+		})
+	}
+}
+
 func ExampleNewJSONEnvVar() {
 	env, err := envutil.NewJSONEnvVar("INVENTORY", map[string]bool{
 		"Apples": true,
@@ -188,6 +385,59 @@ func ExampleNewJSONEnvVar() {
 	// Output: INVENTORY {"Apples":true,"Bread":false}
 }
 
+func ExampleNewSecretEnvVar() {
+	env, err := envutil.NewSecretEnvVar("DB_PASSWORD", "db-creds:password")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(env.Name, env.ValueFrom.SecretKeyRef.Name, env.ValueFrom.SecretKeyRef.Key)
+
+	// Output: DB_PASSWORD db-creds password
+}
+
+func TestNewSecretEnvVar(t *testing.T) {
+	cases := map[string]struct {
+		ref        string
+		wantSecret string
+		wantKey    string
+		wantErr    error
+	}{
+		"name and key": {
+			ref:        "db-creds:password",
+			wantSecret: "db-creds",
+			wantKey:    "password",
+		},
+		"key defaults to variable name": {
+			ref:        "db-creds",
+			wantSecret: "db-creds",
+			wantKey:    "API_KEY",
+		},
+		"invalid name": {
+			ref:     "DB_CREDS",
+			wantErr: errors.New(`invalid Secret name "DB_CREDS": a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`),
+		},
+		"empty key": {
+			ref:     "db-creds:",
+			wantErr: errors.New(`invalid Secret key "": a valid config key must consist of alphanumeric characters, '-', '_' or '.' (e.g. 'key.name',  or 'KEY_NAME',  or 'key-name', regex used for validation is '[-._a-zA-Z0-9]+')`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			env, err := envutil.NewSecretEnvVar("API_KEY", tc.ref)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			testutil.AssertEqual(t, "name", "API_KEY", env.Name)
+			testutil.AssertEqual(t, "secret", tc.wantSecret, env.ValueFrom.SecretKeyRef.Name)
+			testutil.AssertEqual(t, "key", tc.wantKey, env.ValueFrom.SecretKeyRef.Key)
+		})
+	}
+}
+
 func ExampleGetAppEnvVars() {
 	var app v1alpha1.App
 	envutil.SetAppEnvVars(&app, []corev1.EnvVar{
@@ -0,0 +1,32 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package imageutil works with container image references.
+package imageutil
+
+import "strings"
+
+// Repository strips the tag or digest from an image reference.
+func Repository(image string) string {
+	if at := strings.Index(image, "@"); at >= 0 {
+		return image[:at]
+	}
+
+	// A colon after the last slash starts a tag, earlier ones are ports.
+	if colon := strings.LastIndex(image, ":"); colon > strings.LastIndex(image, "/") {
+		return image[:colon]
+	}
+
+	return image
+}
@@ -0,0 +1,33 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package imageutil_test
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/internal/imageutil"
+)
+
+func ExampleRepository() {
+	fmt.Println(imageutil.Repository("gcr.io/my-project/src-ns-app:1234"))
+	fmt.Println(imageutil.Repository("localhost:5000/src-ns-app"))
+	fmt.Println(imageutil.Repository("src-ns-app:1234"))
+	fmt.Println(imageutil.Repository("gcr.io/my-project/app@sha256:abc"))
+
+	// Output: gcr.io/my-project/src-ns-app
+	// localhost:5000/src-ns-app
+	// src-ns-app
+	// gcr.io/my-project/app
+}
@@ -0,0 +1,191 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package iomux multiplexes output from concurrent operations onto a single
+// writer a line at a time with bounded buffering.
+//
+// Each stream buffers at most one line before handing it to the mux, and the
+// mux holds a fixed number of lines waiting to be written. Writers block once
+// the buffer is full, so a slow terminal applies backpressure to the producer
+// rather than the CLI accumulating output in memory.
+package iomux
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+)
+
+const (
+	// DefaultBufferedLines is the default number of lines the mux holds
+	// before writers block.
+	DefaultBufferedLines = 256
+
+	// MaxLineLength is the longest line that's written as a unit. Longer
+	// lines are split into chunks of this size so memory stays bounded.
+	MaxLineLength = 16 * 1024
+)
+
+// ErrClosed is returned when writing to a stream of a closed Mux.
+var ErrClosed = errors.New("iomux: write to closed mux")
+
+// Mux serializes lines written to its streams onto an underlying writer.
+type Mux struct {
+	out    io.Writer
+	lines  chan []byte
+	closed chan struct{}
+	done   chan struct{}
+	once   sync.Once
+
+	// err is only set by the writing goroutine and read after done is
+	// closed.
+	err error
+}
+
+// New creates a Mux writing to out that holds up to bufferedLines lines
+// before writers block. Close must be called to release the writing
+// goroutine.
+func New(out io.Writer, bufferedLines int) *Mux {
+	if bufferedLines < 1 {
+		bufferedLines = 1
+	}
+
+	m := &Mux{
+		out:    out,
+		lines:  make(chan []byte, bufferedLines),
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	go m.run()
+
+	return m
+}
+
+func (m *Mux) run() {
+	defer close(m.done)
+
+	for {
+		select {
+		case line := <-m.lines:
+			m.write(line)
+		case <-m.closed:
+			// Flush whatever was queued before the mux was closed.
+			for {
+				select {
+				case line := <-m.lines:
+					m.write(line)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (m *Mux) write(line []byte) {
+	// After the first error lines are discarded so writers don't block
+	// forever on a broken output.
+	if m.err == nil {
+		_, m.err = m.out.Write(line)
+	}
+}
+
+// Stream returns a new writer whose output is written to the mux in whole
+// lines. Streams are safe for concurrent use and should be closed to flush
+// any trailing partial line.
+func (m *Mux) Stream() io.WriteCloser {
+	return &stream{mux: m}
+}
+
+// Close flushes the queued lines, stops the mux and returns the first error
+// encountered writing to the underlying writer. Writes to streams after
+// Close return ErrClosed.
+func (m *Mux) Close() error {
+	m.once.Do(func() { close(m.closed) })
+	<-m.done
+
+	return m.err
+}
+
+func (m *Mux) send(line []byte) error {
+	select {
+	case <-m.closed:
+		return ErrClosed
+	default:
+	}
+
+	select {
+	case m.lines <- line:
+		return nil
+	case <-m.closed:
+		return ErrClosed
+	}
+}
+
+type stream struct {
+	mux *Mux
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+// Write implements io.Writer.
+func (s *stream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	written := 0
+	for len(p) > 0 {
+		chunk := p
+		if i := bytes.IndexByte(p, '\n'); i >= 0 {
+			chunk = p[:i+1]
+		}
+		if room := MaxLineLength - len(s.buf); len(chunk) > room {
+			chunk = chunk[:room]
+		}
+
+		s.buf = append(s.buf, chunk...)
+		p = p[len(chunk):]
+		written += len(chunk)
+
+		if len(s.buf) == MaxLineLength || s.buf[len(s.buf)-1] == '\n' {
+			if err := s.flush(); err != nil {
+				return written, err
+			}
+		}
+	}
+
+	return written, nil
+}
+
+// Close implements io.Closer, it flushes any partial line.
+func (s *stream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.buf) == 0 {
+		return nil
+	}
+
+	return s.flush()
+}
+
+func (s *stream) flush() error {
+	line := s.buf
+	s.buf = nil
+
+	return s.mux.send(line)
+}
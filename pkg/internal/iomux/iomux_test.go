@@ -0,0 +1,156 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iomux
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func ExampleMux() {
+	buf := &bytes.Buffer{}
+	mux := New(buf, DefaultBufferedLines)
+
+	build := mux.Stream()
+	fmt.Fprint(build, "Step 1/2")
+	fmt.Fprint(build, " done\n")
+	fmt.Fprint(build, "Step 2/2")
+	build.Close()
+
+	mux.Close()
+	fmt.Print(buf.String())
+
+	// Output: Step 1/2 done
+	// Step 2/2
+}
+
+func TestMux_concurrentLinesAreWhole(t *testing.T) {
+	buf := &bytes.Buffer{}
+	mux := New(buf, 4)
+
+	var wg sync.WaitGroup
+	for _, name := range []string{"build", "status"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			s := mux.Stream()
+			defer s.Close()
+
+			for i := 0; i < 100; i++ {
+				// Split each line across writes to force buffering.
+				fmt.Fprintf(s, "%s ", name)
+				fmt.Fprintf(s, "line %d\n", i)
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	testutil.AssertNil(t, "close err", mux.Close())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	testutil.AssertEqual(t, "line count", 200, len(lines))
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "build line ") && !strings.HasPrefix(line, "status line ") {
+			t.Fatalf("interleaved line: %q", line)
+		}
+	}
+}
+
+func TestMux_longLinesAreChunked(t *testing.T) {
+	out := &recordingWriter{}
+	mux := New(out, 1)
+	s := mux.Stream()
+
+	n, err := s.Write([]byte(strings.Repeat("x", MaxLineLength*2+1)))
+	testutil.AssertNil(t, "write err", err)
+	testutil.AssertEqual(t, "written", MaxLineLength*2+1, n)
+	testutil.AssertNil(t, "stream close err", s.Close())
+	testutil.AssertNil(t, "close err", mux.Close())
+
+	testutil.AssertEqual(t, "writes", []int{MaxLineLength, MaxLineLength, 1}, out.sizes)
+}
+
+func TestMux_backpressure(t *testing.T) {
+	out := &blockingWriter{unblock: make(chan struct{})}
+	mux := New(out, 1)
+	s := mux.Stream()
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for i := 0; i < 10; i++ {
+			fmt.Fprintln(s, "line")
+		}
+	}()
+
+	select {
+	case <-finished:
+		t.Fatal("expected writer to block while output is blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(out.unblock)
+	<-finished
+	testutil.AssertNil(t, "close err", mux.Close())
+}
+
+func TestMux_writeAfterClose(t *testing.T) {
+	mux := New(&bytes.Buffer{}, 1)
+	s := mux.Stream()
+	testutil.AssertNil(t, "close err", mux.Close())
+
+	_, err := fmt.Fprintln(s, "late")
+	testutil.AssertEqual(t, "err", ErrClosed, err)
+}
+
+func TestMux_outputError(t *testing.T) {
+	mux := New(&failingWriter{}, 1)
+	s := mux.Stream()
+	fmt.Fprintln(s, "first")
+	fmt.Fprintln(s, "second")
+
+	testutil.AssertErrorsEqual(t, errors.New("broken pipe"), mux.Close())
+}
+
+type recordingWriter struct {
+	sizes []int
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	w.sizes = append(w.sizes, len(p))
+	return len(p), nil
+}
+
+type blockingWriter struct {
+	unblock chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.unblock
+	return len(p), nil
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
@@ -0,0 +1,70 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parallel
+
+import "sync"
+
+// DefaultLimit is the number of calls made at once by commands that fan out.
+// It stays within the default burst of Kubernetes clients so requests aren't
+// throttled client side.
+const DefaultLimit = 8
+
+// ForEach calls f for every i in [0, n) with at most limit calls running at
+// once and returns after they've all finished. A limit less than one runs the
+// calls one at a time.
+//
+// Callers that collect results should write them to index i of a slice so the
+// output order doesn't depend on scheduling.
+func ForEach(n, limit int, f func(i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			f(i)
+		}(i)
+	}
+
+	wg.Wait()
+}
+
+// Do runs the funcs concurrently and waits for them all to finish. It returns
+// the error of the earliest func in the argument list that failed so the
+// result is the same as running them in order.
+func Do(funcs ...func() error) error {
+	errs := make([]error, len(funcs))
+	ForEach(len(funcs), DefaultLimit, func(i int) {
+		errs[i] = funcs[i]()
+	})
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
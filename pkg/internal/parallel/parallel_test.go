@@ -0,0 +1,112 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parallel
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestForEach(t *testing.T) {
+	cases := map[string]struct {
+		n           int
+		limit       int
+		wantMaxRuns int
+	}{
+		"no calls": {
+			n:     0,
+			limit: 2,
+		},
+		"bounded": {
+			n:           20,
+			limit:       3,
+			wantMaxRuns: 3,
+		},
+		"invalid limit runs serially": {
+			n:           5,
+			limit:       0,
+			wantMaxRuns: 1,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var (
+				mu      sync.Mutex
+				running int
+				maxRuns int
+			)
+			called := make([]bool, tc.n)
+
+			ForEach(tc.n, tc.limit, func(i int) {
+				mu.Lock()
+				running++
+				if running > maxRuns {
+					maxRuns = running
+				}
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+
+				mu.Lock()
+				running--
+				called[i] = true
+				mu.Unlock()
+			})
+
+			if maxRuns > tc.wantMaxRuns {
+				t.Errorf("wanted at most %d concurrent calls, got %d", tc.wantMaxRuns, maxRuns)
+			}
+
+			for i, c := range called {
+				if !c {
+					t.Errorf("call %d wasn't made", i)
+				}
+			}
+		})
+	}
+}
+
+func TestDo(t *testing.T) {
+	cases := map[string]struct {
+		errs    []error
+		wantErr error
+	}{
+		"no funcs": {},
+		"all succeed": {
+			errs: []error{nil, nil, nil},
+		},
+		"earliest error wins": {
+			errs:    []error{nil, errors.New("second"), errors.New("third")},
+			wantErr: errors.New("second"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var funcs []func() error
+			for _, err := range tc.errs {
+				err := err
+				funcs = append(funcs, func() error { return err })
+			}
+
+			testutil.AssertErrorsEqual(t, tc.wantErr, Do(funcs...))
+		})
+	}
+}
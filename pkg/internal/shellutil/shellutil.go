@@ -0,0 +1,48 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package shellutil builds commands that are run by or shown to a POSIX
+// shell.
+package shellutil
+
+import "strings"
+
+// Quote quotes s so the shell treats it literally. Strings made only of
+// characters the shell doesn't interpret are returned as-is so commands stay
+// readable.
+func Quote(s string) string {
+	if s != "" && strings.IndexFunc(s, isUnsafe) < 0 {
+		return s
+	}
+
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+func isUnsafe(r rune) bool {
+	return !(r >= 'a' && r <= 'z' ||
+		r >= 'A' && r <= 'Z' ||
+		r >= '0' && r <= '9' ||
+		strings.ContainsRune("-_./=:,@", r))
+}
+
+// Command joins the arguments into a command, quoting any that aren't safe
+// to pass to the shell as-is.
+func Command(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = Quote(arg)
+	}
+
+	return strings.Join(quoted, " ")
+}
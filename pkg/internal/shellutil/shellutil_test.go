@@ -0,0 +1,50 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shellutil_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/kf/pkg/internal/shellutil"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func ExampleCommand() {
+	fmt.Println(shellutil.Command("kf", "set-env", "my-app", "GREETING", "it's a nice day"))
+
+	// Output: kf set-env my-app GREETING 'it'\''s a nice day'
+}
+
+func TestQuote(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"":                 "''",
+		"my-app":           "my-app",
+		"/health":          "/health",
+		"user@example.com": "user@example.com",
+		"two words":        "'two words'",
+		"$HOME":            "'$HOME'",
+		"*":                "'*'",
+		"it's":             `'it'\''s'`,
+	}
+
+	for in, want := range cases {
+		t.Run(in, func(t *testing.T) {
+			testutil.AssertEqual(t, "quoted", want, shellutil.Quote(in))
+		})
+	}
+}
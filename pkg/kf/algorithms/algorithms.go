@@ -15,186 +15,81 @@
 package algorithms
 
 import (
-	"sort"
+	"cmp"
+	"slices"
 )
 
-// Interface is the interface used by the algorithms.
-type Interface interface {
-	sort.Interface
+// Dedupe returns the elements of s sorted by compare with duplicates removed.
+// When elements compare equal the first one in s is kept. s isn't modified.
+func Dedupe[S ~[]E, E any](s S, compare func(a, b E) int) S {
+	out := slices.Clone(s)
+	slices.SortStableFunc(out, compare)
 
-	// Set stores the value located at b[j] to a[i].
-	Set(i int, a Interface, j int, b Interface)
-
-	// Slice returns a slice (e.g., s[i:j]) of the object.
-	Slice(i, j int) Interface
-
-	// Append returns i and a appended to each other (e.g., append(i, a...)).
-	Append(a Interface) Interface
-
-	// Clone returns a clone of the object.
-	Clone() Interface
-}
-
-// Strings implements Interface for a slice of strings.
-type Strings []string
-
-// Len implements Interface.
-func (s Strings) Len() int {
-	return len(s)
-}
-
-// Swap implements Interface.
-func (s Strings) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
-}
-
-// Less implements Interface.
-func (s Strings) Less(i, j int) bool {
-	return s[i] < s[j]
-}
-
-// Set implements Interface.
-func (s Strings) Set(i int, a Interface, j int, b Interface) {
-	a.(Strings)[i] = b.(Strings)[j]
-}
-
-// Clone implements Interface.
-func (s Strings) Clone() Interface {
-	return append(Strings{}, s...)
-}
-
-// Append implements Interface.
-func (s Strings) Append(a Interface) Interface {
-	return append(s, a.(Strings)...)
-}
-
-// Slice implements Interface.
-func (s Strings) Slice(i, j int) Interface {
-	return s[i:j]
-}
-
-// Ints implements Interface for a slice of ints.
-type Ints []int
-
-// Len implements Interface.
-func (s Ints) Len() int {
-	return len(s)
-}
-
-// Swap implements Interface.
-func (s Ints) Swap(i, j int) {
-	s[i], s[j] = s[j], s[i]
-}
-
-// Less implements Interface.
-func (s Ints) Less(i, j int) bool {
-	return s[i] < s[j]
-}
-
-// Set implements Interface.
-func (s Ints) Set(i int, a Interface, j int, b Interface) {
-	a.(Ints)[i] = b.(Ints)[j]
-}
-
-// Clone implements Interface.
-func (s Ints) Clone() Interface {
-	return append(Ints{}, s...)
+	return slices.CompactFunc(out, func(a, b E) bool {
+		return compare(a, b) == 0
+	})
 }
 
-// Append implements Interface.
-func (s Ints) Append(a Interface) Interface {
-	return append(s, a.(Ints)...)
+// Union returns the deduplicated elements of a and b sorted by compare. When
+// elements of a and b compare equal the one from a is kept. Neither input is
+// modified.
+func Union[S ~[]E, E any](a, b S, compare func(a, b E) int) S {
+	return Dedupe(append(slices.Clone(a), b...), compare)
 }
 
-// Slice implements Interface.
-func (s Ints) Slice(i, j int) Interface {
-	return s[i:j]
+// Merge returns the deduplicated elements of a and b sorted by compare. When
+// elements of a and b compare equal the one from b replaces the one from a.
+// The sort is stable so merging the same inputs always gives the same result.
+// Neither input is modified.
+func Merge[S ~[]E, E any](a, b S, compare func(a, b E) int) S {
+	return Dedupe(append(slices.Clone(b), a...), compare)
 }
 
-// Dedupe removes any duplicates from the given collection. This does not
-// alter the input. First element is always chosen.
-func Dedupe(s Interface) Interface {
-	s = s.Clone()
-	sort.Stable(s)
-
-	var idx int
+// Intersect returns the deduplicated elements of a that are also in b sorted
+// by compare. Neither input is modified.
+func Intersect[S ~[]E, E any](a, b S, compare func(a, b E) int) S {
+	sorted := Dedupe(b, compare)
 
-	for i := 0; i < s.Len(); i++ {
-		// We know that the interface is ascending order. Therefore, each
-		// value should be greater than the one previous. If they are equal,
-		// then they are not greater.
-		if i != 0 && !greater(i, i-1, s) {
-			continue
+	var out S
+	for _, x := range Dedupe(a, compare) {
+		if contains(sorted, x, compare) {
+			out = append(out, x)
 		}
-		s.Set(idx, s, i, s)
-		idx++
 	}
-	return s.Slice(0, idx)
-}
-
-// Delete removes any items in 'b' from the 'a' collection. This does not
-// alter the input. It outputs the lengh of the new collection. Therefore, if
-// the interface is wrapping a slice, then the slice should be truncated via
-// the result (e.g., slice[:returnValue]).
-func Delete(a, b Interface) Interface {
-	a = a.Clone()
-	b = b.Clone()
-	sort.Sort(b)
 
-	var currentIdx int
-	for i := 0; i < a.Len(); i++ {
-		if Search(i, a, b) {
-			continue
-		}
-		a.Set(currentIdx, a, i, a)
-		currentIdx++
-	}
-
-	return a.Slice(0, currentIdx)
-}
-
-func equal(i, j int, s sort.Interface) bool {
-	// If something is neither less, nor greater than the given value, then it
-	// must be equal.
-	return !s.Less(i, j) && !s.Less(j, i)
+	return out
 }
 
-func greater(i, j int, s sort.Interface) bool {
-	// sort.Interface only gives us less. Therefore, if we reverse that
-	// operation (via sort.Reverse), we get greater.
-	return sort.Reverse(s).Less(i, j)
-}
-
-// Search will look for the item at a[i] in b. If found, it will return true.
-// Otherwise it will return false.
-func Search(i int, a, b Interface) bool {
-	// b must be sorted, but we don't want to destroy the input.
-	b = b.Clone()
-	sort.Sort(b)
-
-	idx := index(i, a, b)
+// Delete returns the elements of a that aren't in b, keeping the order of a.
+// Neither input is modified.
+func Delete[S ~[]E, E any](a, b S, compare func(a, b E) int) S {
+	sorted := Dedupe(b, compare)
 
-	if idx >= b.Len() {
-		return false
+	var out S
+	for _, x := range a {
+		if !contains(sorted, x, compare) {
+			out = append(out, x)
+		}
 	}
 
-	n := a.Clone().Slice(i, i+1).Append(b.Slice(idx, idx+1))
-	return equal(0, 1, n)
+	return out
 }
 
-func index(i int, a, b Interface) int {
-	return sort.Search(b.Len(), func(j int) bool {
-		n := b.Clone().Slice(j, j+1).Append(a.Slice(i, i+1))
-		return greater(0, 1, n) || equal(0, 1, n)
+// Contains returns true if x compares equal to any element of s.
+func Contains[S ~[]E, E any](s S, x E, compare func(a, b E) int) bool {
+	return slices.ContainsFunc(s, func(e E) bool {
+		return compare(e, x) == 0
 	})
 }
 
-// Merge will combine the two collections. It replaces values from a with b if
-// there is a collision. It assumes both a and b have been Deduped. It uses
-// Append to create new memory and therefore does not destroy the input.
-func Merge(a, b Interface) Interface {
-	b = b.Clone()
-	sort.Sort(b)
+// contains searches s, which must be sorted by compare, for x.
+func contains[S ~[]E, E any](s S, x E, compare func(a, b E) int) bool {
+	_, found := slices.BinarySearchFunc(s, x, compare)
+	return found
+}
 
-	return Dedupe(b.Append(a))
+// Ordered can be used as the compare function for slices of ordered types
+// like strings and ints.
+func Ordered[E cmp.Ordered](a, b E) int {
+	return cmp.Compare(a, b)
 }
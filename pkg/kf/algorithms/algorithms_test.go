@@ -31,33 +31,45 @@ func TestDedupe(t *testing.T) {
 	// We use 0 so we get the same tests every time.
 	rand := rand.New(rand.NewSource(0))
 	for i := 0; i < 5000; i++ {
-		var slice algorithms.Ints
+		var slice []int
 		for j := 0; j < rand.Intn(1000)+1000; j++ {
 			slice = append(slice, rand.Intn(10))
 		}
 
-		slice = algorithms.Dedupe(algorithms.Ints(slice)).(algorithms.Ints)
-
-		// While it might already be sorted, the requirements  of the
-		// algorithm don't require it. We'll do it here to make testing it
-		// easier (even if it's redundant).
-		sort.Ints(slice)
+		slice = algorithms.Dedupe(slice, algorithms.Ordered[int])
 
 		testutil.AssertEqual(t, "len", 10, len(slice))
-		testutil.AssertEqual(t, "values", algorithms.Ints{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, slice)
+		testutil.AssertEqual(t, "values", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, slice)
+	}
+}
+
+func TestDedupe_keepsFirst(t *testing.T) {
+	t.Parallel()
+
+	type pair struct {
+		key, value string
+	}
+	byKey := func(a, b pair) int {
+		return strings.Compare(a.key, b.key)
 	}
+
+	in := []pair{{"b", "1"}, {"a", "2"}, {"b", "3"}, {"a", "4"}}
+	out := algorithms.Dedupe(in, byKey)
+
+	testutil.AssertEqual(t, "values", []pair{{"a", "2"}, {"b", "1"}}, out)
+	testutil.AssertEqual(t, "input unchanged", []pair{{"b", "1"}, {"a", "2"}, {"b", "3"}, {"a", "4"}}, in)
 }
 
 func ExampleDedupe() {
 	s := []string{"a", "b", "a", "d", "b", "d", "c"}
-	s = []string(algorithms.Dedupe(algorithms.Strings(s)).(algorithms.Strings))
+	s = algorithms.Dedupe(s, algorithms.Ordered[string])
 
 	fmt.Println(strings.Join(s, ", "))
 
-	// Outputs: a, b, c, d
+	// Output: a, b, c, d
 }
 
-func TestSearch(t *testing.T) {
+func TestContains(t *testing.T) {
 	t.Parallel()
 
 	// We use 0 so we get the same tests every time.
@@ -68,7 +80,8 @@ func TestSearch(t *testing.T) {
 			slice = append(slice, rand.Intn(1000))
 		}
 
-		sort.Sort(algorithms.Ints(slice))
+		sorted := append([]int{}, slice...)
+		sort.Ints(sorted)
 
 		stdlibSearch := func(a []int, x int) bool {
 			idx := sort.SearchInts(a, x)
@@ -79,26 +92,17 @@ func TestSearch(t *testing.T) {
 		testutil.AssertEqual(
 			t,
 			"found",
-			stdlibSearch(slice, value),
-			algorithms.Search(0, algorithms.Ints{value}, algorithms.Ints(slice)),
+			stdlibSearch(sorted, value),
+			algorithms.Contains(slice, value, algorithms.Ordered[int]),
 		)
 	}
-
-	a := algorithms.Strings([]string{"a", "x"})
-	b := algorithms.Strings([]string{"b", "a", "c", "d"})
-	sort.Sort(b)
-
-	testutil.AssertEqual(t, "found", true, algorithms.Search(0, a, b))
-	testutil.AssertEqual(t, "not found", false, algorithms.Search(1, a, b))
 }
 
-func ExampleSearch() {
-	haystack := algorithms.Strings{"a", "c", "d", "b", "x"}
-	sort.Sort(haystack)
-	needles := algorithms.Strings{"x", "y", "z", "c"}
+func ExampleContains() {
+	haystack := []string{"a", "c", "d", "b", "x"}
 
-	for needleIdx := range needles {
-		fmt.Println(algorithms.Search(needleIdx, needles, haystack))
+	for _, needle := range []string{"x", "y", "z", "c"} {
+		fmt.Println(algorithms.Contains(haystack, needle, algorithms.Ordered[string]))
 	}
 
 	// Output: true
@@ -110,56 +114,44 @@ func ExampleSearch() {
 func TestDelete(t *testing.T) {
 	t.Parallel()
 
-	a := algorithms.Strings{"c", "b", "a", "d"}
-	b := algorithms.Strings{"d", "c"}
-
-	a = algorithms.Delete(a, b).(algorithms.Strings)
-
-	// While it might already be sorted, the requirements  of the
-	// algorithm don't require it. We'll do it here to make testing it
-	// easier (even if it's redundant).
-	sort.Strings(a)
+	a := []string{"c", "b", "a", "d"}
+	b := []string{"d", "c"}
 
-	testutil.AssertEqual(t, "len", 2, len(a))
-	testutil.AssertEqual(t, "values", algorithms.Strings{"a", "b"}, a)
+	testutil.AssertEqual(t, "values", []string{"b", "a"}, algorithms.Delete(a, b, algorithms.Ordered[string]))
+	testutil.AssertEqual(t, "input unchanged", []string{"c", "b", "a", "d"}, a)
 }
 
 func ExampleDelete() {
 	a := []string{"c", "b", "a", "d"}
 	b := []string{"d", "c"}
 
-	a = []string(algorithms.Delete(algorithms.Strings(a), algorithms.Strings(b)).(algorithms.Strings))
-
-	// Sort for readability
-	sort.Sort(algorithms.Strings(a))
+	fmt.Println(strings.Join(algorithms.Delete(a, b, algorithms.Ordered[string]), ", "))
 
-	fmt.Println(strings.Join(a, ", "))
-
-	// Outputs: a, b
+	// Output: b, a
 }
 
 func TestMerge(t *testing.T) {
 	t.Parallel()
 
-	a := algorithms.Strings{"c", "b", "a", "d"}
-	b := algorithms.Strings{"d", "c", "e"}
+	type pair struct {
+		key, value string
+	}
+	byKey := func(a, b pair) int {
+		return strings.Compare(a.key, b.key)
+	}
 
-	r := algorithms.Merge(a, b)
+	a := []pair{{"c", "old"}, {"a", "old"}}
+	b := []pair{{"c", "new"}, {"b", "new"}}
 
-	sort.Sort(r)
-	testutil.AssertEqual(t, "values", algorithms.Strings{"a", "b", "c", "d", "e"}, r.(algorithms.Strings))
+	testutil.AssertEqual(t, "values", []pair{{"a", "old"}, {"b", "new"}, {"c", "new"}}, algorithms.Merge(a, b, byKey))
+	testutil.AssertEqual(t, "union keeps a", []pair{{"a", "old"}, {"b", "new"}, {"c", "old"}}, algorithms.Union(a, b, byKey))
 }
 
 func ExampleMerge() {
-	a := algorithms.Strings{"c", "b", "a", "d"}
-	b := algorithms.Strings{"d", "c", "e"}
-
-	r := algorithms.Merge(a, b)
-
-	// Sort for display purposes.
-	sort.Sort(r)
+	a := []string{"c", "b", "a", "d"}
+	b := []string{"d", "c", "e"}
 
-	for _, x := range r.(algorithms.Strings) {
+	for _, x := range algorithms.Merge(a, b, algorithms.Ordered[string]) {
 		fmt.Println(x)
 	}
 
@@ -169,3 +161,31 @@ func ExampleMerge() {
 	// d
 	// e
 }
+
+func ExampleUnion() {
+	a := []int{3, 1}
+	b := []int{2, 3}
+
+	fmt.Println(algorithms.Union(a, b, algorithms.Ordered[int]))
+
+	// Output: [1 2 3]
+}
+
+func TestIntersect(t *testing.T) {
+	t.Parallel()
+
+	a := []string{"c", "b", "a", "d", "c"}
+	b := []string{"d", "c", "e"}
+
+	testutil.AssertEqual(t, "values", []string{"c", "d"}, algorithms.Intersect(a, b, algorithms.Ordered[string]))
+	testutil.AssertEqual(t, "empty", 0, len(algorithms.Intersect(a, nil, algorithms.Ordered[string])))
+}
+
+func ExampleIntersect() {
+	a := []string{"c", "b", "a", "d"}
+	b := []string{"d", "c", "e"}
+
+	fmt.Println(algorithms.Intersect(a, b, algorithms.Ordered[string]))
+
+	// Output: [c d]
+}
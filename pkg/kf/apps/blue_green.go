@@ -0,0 +1,50 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"errors"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+)
+
+// VenerableAppName is the name of the copy of an App that serves its routes
+// while a blue-green deployment replaces it.
+func VenerableAppName(appName string) string {
+	return appName + "-venerable"
+}
+
+// MakeVenerableApp creates a copy of app that runs the image app is running,
+// so nothing is rebuilt, with the same routes, environment, scaling and
+// service bindings.
+func MakeVenerableApp(app *v1alpha1.App) (*v1alpha1.App, error) {
+	image := app.Status.ImageReference()
+	if image == "" {
+		return nil, errors.New("the App isn't running an image to copy")
+	}
+
+	out := &v1alpha1.App{}
+	out.Name = VenerableAppName(app.Name)
+	out.Namespace = app.Namespace
+	out.Spec = *app.Spec.DeepCopy()
+	out.Spec.Source = v1alpha1.SourceSpec{
+		ServiceAccount: app.Spec.Source.ServiceAccount,
+		ContainerImage: v1alpha1.SourceSpecContainerImage{Image: image},
+	}
+	out.Spec.Template.UpdateRequests = 0
+	out.Spec.Rollout = nil
+
+	return out, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kffake "github.com/google/kf/pkg/client/clientset/versioned/fake"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestMakeVenerableApp(t *testing.T) {
+	t.Parallel()
+
+	app := &v1alpha1.App{}
+	app.Name = "my-app"
+	app.Namespace = "my-space"
+	app.Spec.Source.ServiceAccount = "some-sa"
+	app.Spec.Source.UpdateRequests = 3
+	app.Spec.Source.BuildpackBuild.Source = "gcr.io/my-app-source"
+	app.Spec.Template.UpdateRequests = 2
+	app.Spec.Template.Spec.Containers = []corev1.Container{
+		{Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}}},
+	}
+	app.Spec.Routes = []v1alpha1.RouteSpecFields{{Hostname: "www", Domain: "example.com"}}
+	app.Spec.ServiceBindings = []v1alpha1.AppSpecServiceBinding{{Instance: "my-db"}}
+	app.Spec.Rollout = &v1alpha1.AppSpecRollout{StableRevisionName: "my-app-00001"}
+	app.Status.Image = "gcr.io/my-app:latest"
+	app.Status.ImageDigest = "sha256:abc"
+
+	venerable, err := apps.MakeVenerableApp(app)
+	testutil.AssertNil(t, "err", err)
+
+	testutil.AssertEqual(t, "name", "my-app-venerable", venerable.Name)
+	testutil.AssertEqual(t, "namespace", "my-space", venerable.Namespace)
+	testutil.AssertEqual(t, "source", v1alpha1.SourceSpec{
+		ServiceAccount: "some-sa",
+		ContainerImage: v1alpha1.SourceSpecContainerImage{Image: "gcr.io/my-app@sha256:abc"},
+	}, venerable.Spec.Source)
+	testutil.AssertEqual(t, "template update requests", 0, venerable.Spec.Template.UpdateRequests)
+	testutil.AssertEqual(t, "containers", app.Spec.Template.Spec.Containers, venerable.Spec.Template.Spec.Containers)
+	testutil.AssertEqual(t, "routes", app.Spec.Routes, venerable.Spec.Routes)
+	testutil.AssertEqual(t, "bindings", app.Spec.ServiceBindings, venerable.Spec.ServiceBindings)
+	testutil.AssertEqual(t, "rollout", (*v1alpha1.AppSpecRollout)(nil), venerable.Spec.Rollout)
+
+	_, err = apps.MakeVenerableApp(&v1alpha1.App{})
+	testutil.AssertErrorsEqual(t, errors.New("the App isn't running an image to copy"), err)
+}
+
+func TestClient_SwapRoutes(t *testing.T) {
+	t.Parallel()
+
+	routes := []v1alpha1.RouteSpecFields{{Hostname: "www", Domain: "example.com"}}
+
+	from := &v1alpha1.App{ObjectMeta: metav1.ObjectMeta{Name: "my-app-venerable", Namespace: "my-space"}}
+	from.Spec.Routes = routes
+
+	to := &v1alpha1.App{ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "my-space"}}
+	to.Spec.Routes = []v1alpha1.RouteSpecFields{{Hostname: "my-app-smoke-test", Domain: "example.com"}}
+	to.Status.Conditions = append(to.Status.Conditions, apis.Condition{
+		Type:   v1alpha1.AppConditionRouteReady,
+		Status: corev1.ConditionTrue,
+	})
+
+	kclient := kffake.NewSimpleClientset(from, to).KfV1alpha1()
+	client := apps.NewClient(kclient, nil)
+
+	testutil.AssertNil(t, "err", client.SwapRoutes(context.Background(), "my-space", "my-app-venerable", "my-app"))
+
+	gotTo, err := kclient.Apps("my-space").Get("my-app", metav1.GetOptions{})
+	testutil.AssertNil(t, "get err", err)
+	testutil.AssertEqual(t, "to routes", routes, gotTo.Spec.Routes)
+
+	gotFrom, err := kclient.Apps("my-space").Get("my-app-venerable", metav1.GetOptions{})
+	testutil.AssertNil(t, "get err", err)
+	testutil.AssertEqual(t, "from routes", 0, len(gotFrom.Spec.Routes))
+}
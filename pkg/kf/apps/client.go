@@ -15,7 +15,10 @@
 package apps
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"time"
 
 	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
 	cv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
@@ -27,11 +30,12 @@ import (
 type ClientExtension interface {
 	DeleteInForeground(namespace string, name string) error
 	DeployLogsForApp(out io.Writer, app *v1alpha1.App) error
-	DeployLogs(out io.Writer, appName, resourceVersion, namespace string, noStart bool) error
+	DeployLogs(out io.Writer, appName, resourceVersion, namespace string, noStart bool, timeouts PushTimeouts) error
 	Restart(namespace, name string) error
 	Restage(namespace, name string) (*v1alpha1.App, error)
 	BindService(namespace, name string, binding *v1alpha1.AppSpecServiceBinding) (*v1alpha1.App, error)
 	UnbindService(namespace, name, bindingName string) (*v1alpha1.App, error)
+	SwapRoutes(ctx context.Context, namespace, from, to string) error
 }
 
 type appsClient struct {
@@ -50,11 +54,7 @@ func NewClient(
 				// Dedupe Routes
 				// TODO(https://github.com/knative/pkg/issues/542): Route
 				// already exists and the webhook can't dedupe for us.
-				app.Spec.Routes = []v1alpha1.RouteSpecFields(
-					algorithms.Dedupe(
-						v1alpha1.RouteSpecFieldsSlice(app.Spec.Routes),
-					).(v1alpha1.RouteSpecFieldsSlice),
-				)
+				app.Spec.Routes = algorithms.Dedupe(app.Spec.Routes, v1alpha1.CompareRouteSpecFields)
 
 				return nil
 			},
@@ -90,6 +90,10 @@ func (ac *appsClient) Restage(namespace, name string) (app *v1alpha1.App, err er
 
 	app.Spec.Source.UpdateRequests++
 
+	// Restages through this path are triggered by people so they shouldn't
+	// inherit a scheduled priority from a previous rebuild.
+	v1alpha1.SetBuildPriority(app, v1alpha1.BuildPriorityInteractive)
+
 	return ac.coreClient.Update(namespace, app)
 }
 
@@ -108,3 +112,36 @@ func (ac *appsClient) UnbindService(namespace, name, bindingName string) (app *v
 		return nil
 	})
 }
+
+// swapRoutesPollInterval is how often SwapRoutes checks whether the App
+// receiving the routes is serving them.
+const swapRoutesPollInterval = time.Second
+
+// SwapRoutes gives the routes of the App named from to the App named to,
+// replacing the routes to had. The routes are only removed from from once to
+// is serving them so they're never left without an App.
+func (ac *appsClient) SwapRoutes(ctx context.Context, namespace, from, to string) error {
+	source, err := ac.coreClient.Get(namespace, from)
+	if err != nil {
+		return err
+	}
+
+	routes := source.Spec.Routes
+	if _, err := ac.coreClient.Transform(namespace, to, func(app *v1alpha1.App) error {
+		app.Spec.Routes = append([]v1alpha1.RouteSpecFields(nil), routes...)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if _, err := ac.coreClient.WaitForConditionRoutesReadyTrue(ctx, namespace, to, swapRoutesPollInterval); err != nil {
+		return fmt.Errorf("waiting for %s to serve the routes: %s", to, err)
+	}
+
+	_, err = ac.coreClient.Transform(namespace, from, func(app *v1alpha1.App) error {
+		app.Spec.Routes = nil
+		return nil
+	})
+
+	return err
+}
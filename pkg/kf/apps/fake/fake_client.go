@@ -121,17 +121,17 @@ func (mr *FakeClientMockRecorder) DeleteInForeground(arg0, arg1 interface{}) *go
 }
 
 // DeployLogs mocks base method
-func (m *FakeClient) DeployLogs(arg0 io.Writer, arg1, arg2, arg3 string, arg4 bool) error {
+func (m *FakeClient) DeployLogs(arg0 io.Writer, arg1, arg2, arg3 string, arg4 bool, arg5 apps.PushTimeouts) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeployLogs", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "DeployLogs", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeployLogs indicates an expected call of DeployLogs
-func (mr *FakeClientMockRecorder) DeployLogs(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *FakeClientMockRecorder) DeployLogs(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployLogs", reflect.TypeOf((*FakeClient)(nil).DeployLogs), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeployLogs", reflect.TypeOf((*FakeClient)(nil).DeployLogs), arg0, arg1, arg2, arg3, arg4, arg5)
 }
 
 // DeployLogsForApp mocks base method
@@ -188,6 +188,27 @@ func (mr *FakeClientMockRecorder) List(arg0 interface{}, arg1 ...interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*FakeClient)(nil).List), varargs...)
 }
 
+// ListPage mocks base method
+func (m *FakeClient) ListPage(arg0 string, arg1 ...apps.ListOption) ([]v1alpha1.App, string, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListPage", varargs...)
+	ret0, _ := ret[0].([]v1alpha1.App)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPage indicates an expected call of ListPage
+func (mr *FakeClientMockRecorder) ListPage(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*FakeClient)(nil).ListPage), varargs...)
+}
+
 // Restage mocks base method
 func (m *FakeClient) Restage(arg0, arg1 string) (*v1alpha1.App, error) {
 	m.ctrl.T.Helper()
@@ -217,6 +238,20 @@ func (mr *FakeClientMockRecorder) Restart(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restart", reflect.TypeOf((*FakeClient)(nil).Restart), arg0, arg1)
 }
 
+// SwapRoutes mocks base method
+func (m *FakeClient) SwapRoutes(arg0 context.Context, arg1, arg2, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SwapRoutes", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SwapRoutes indicates an expected call of SwapRoutes
+func (mr *FakeClientMockRecorder) SwapRoutes(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SwapRoutes", reflect.TypeOf((*FakeClient)(nil).SwapRoutes), arg0, arg1, arg2, arg3)
+}
+
 // Transform mocks base method
 func (m *FakeClient) Transform(arg0, arg1 string, arg2 apps.Mutator) (*v1alpha1.App, error) {
 	m.ctrl.T.Helper()
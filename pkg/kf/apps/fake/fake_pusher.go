@@ -21,6 +21,7 @@ package fake
 
 import (
 	gomock "github.com/golang/mock/gomock"
+	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
 	apps "github.com/google/kf/pkg/kf/apps"
 	reflect "reflect"
 )
@@ -48,6 +49,26 @@ func (m *FakePusher) EXPECT() *FakePusherMockRecorder {
 	return m.recorder
 }
 
+// Preview mocks base method
+func (m *FakePusher) Preview(arg0 string, arg1 ...apps.PushOption) (*v1alpha1.App, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Preview", varargs...)
+	ret0, _ := ret[0].(*v1alpha1.App)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Preview indicates an expected call of Preview
+func (mr *FakePusherMockRecorder) Preview(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Preview", reflect.TypeOf((*FakePusher)(nil).Preview), varargs...)
+}
+
 // Push mocks base method
 func (m *FakePusher) Push(arg0 string, arg1 ...apps.PushOption) error {
 	m.ctrl.T.Helper()
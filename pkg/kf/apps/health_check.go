@@ -17,13 +17,17 @@ package apps
 import (
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/shellutil"
 	corev1 "k8s.io/api/core/v1"
 )
 
 // NewHealthCheck creates a corev1.Probe that maps the health checks CloudFoundry
-// does.
-func NewHealthCheck(healthCheckType string, endpoint string, timeoutSeconds int) (*corev1.Probe, error) {
+// does. If expect is set, HTTP checks also require the response body to
+// contain it.
+func NewHealthCheck(healthCheckType, endpoint, expect string, timeoutSeconds int) (*corev1.Probe, error) {
 	if timeoutSeconds < 0 {
 		return nil, errors.New("health check timeouts can't be negative")
 	}
@@ -32,6 +36,13 @@ func NewHealthCheck(healthCheckType string, endpoint string, timeoutSeconds int)
 
 	switch healthCheckType {
 	case "http":
+		if expect != "" {
+			probe.Handler.Exec = &corev1.ExecAction{
+				Command: []string{"/bin/sh", "-c", expectScript(endpoint, expect)},
+			}
+			return probe, nil
+		}
+
 		probe.Handler.HTTPGet = &corev1.HTTPGetAction{Path: endpoint}
 		return probe, nil
 
@@ -40,6 +51,10 @@ func NewHealthCheck(healthCheckType string, endpoint string, timeoutSeconds int)
 			return nil, errors.New("health check endpoints can only be used with http checks")
 		}
 
+		if expect != "" {
+			return nil, errors.New("health check expectations can only be used with http checks")
+		}
+
 		probe.Handler.TCPSocket = &corev1.TCPSocketAction{}
 		return probe, nil
 
@@ -50,3 +65,25 @@ func NewHealthCheck(healthCheckType string, endpoint string, timeoutSeconds int)
 		return nil, fmt.Errorf("unknown health check type %s, supported types are http and port", healthCheckType)
 	}
 }
+
+// expectScript generates a shell script that succeeds if the body of the
+// app's response at endpoint contains expect. Probes can't check response
+// bodies, many frameworks report 200 even while degraded. The app's image
+// needs curl or wget.
+func expectScript(endpoint, expect string) string {
+	if endpoint == "" {
+		endpoint = v1alpha1.DefaultHealthCheckProbeEndpoint
+	}
+
+	if !strings.HasPrefix(endpoint, "/") {
+		endpoint = "/" + endpoint
+	}
+
+	return strings.Join([]string{
+		fmt.Sprintf(`url="http://127.0.0.1:${PORT:-8080}"%s`, shellutil.Quote(endpoint)),
+		`if command -v curl >/dev/null 2>&1; then body="$(curl -fsS "$url")"; else body="$(wget -q -O - "$url")"; fi || exit 1`,
+		fmt.Sprintf(`case "$body" in *%s*) exit 0 ;; esac`, shellutil.Quote(expect)),
+		fmt.Sprintf(`echo "response from $url doesn't contain "%s >&2`, shellutil.Quote(expect)),
+		`exit 1`,
+	}, "\n")
+}
@@ -16,6 +16,8 @@ package apps
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/kf/pkg/kf/testutil"
@@ -26,6 +28,7 @@ func TestNewHealthCheck(t *testing.T) {
 	cases := map[string]struct {
 		checkType string
 		endpoint  string
+		expect    string
 		timeout   int
 
 		expectProbe *corev1.Probe
@@ -98,14 +101,50 @@ func TestNewHealthCheck(t *testing.T) {
 			endpoint:  "/healthz",
 			expectErr: errors.New("health check endpoints can only be used with http checks"),
 		},
+		"port with expect": {
+			checkType: "port",
+			expect:    "UP",
+			expectErr: errors.New("health check expectations can only be used with http checks"),
+		},
+		"http with expect": {
+			checkType: "http",
+			endpoint:  "/actuator/health",
+			expect:    `"status":"UP"`,
+			timeout:   10,
+			expectProbe: &corev1.Probe{
+				TimeoutSeconds: int32(10),
+				Handler: corev1.Handler{
+					Exec: &corev1.ExecAction{
+						Command: []string{"/bin/sh", "-c", strings.Join([]string{
+							`url="http://127.0.0.1:${PORT:-8080}"/actuator/health`,
+							`if command -v curl >/dev/null 2>&1; then body="$(curl -fsS "$url")"; else body="$(wget -q -O - "$url")"; fi || exit 1`,
+							`case "$body" in *'"status":"UP"'*) exit 0 ;; esac`,
+							`echo "response from $url doesn't contain "'"status":"UP"' >&2`,
+							`exit 1`,
+						}, "\n")},
+					},
+				},
+			},
+		},
 	}
 
 	for tn, tc := range cases {
 		t.Run(tn, func(t *testing.T) {
-			actualProbe, actualErr := NewHealthCheck(tc.checkType, tc.endpoint, tc.timeout)
+			actualProbe, actualErr := NewHealthCheck(tc.checkType, tc.endpoint, tc.expect, tc.timeout)
 
 			testutil.AssertErrorsEqual(t, tc.expectErr, actualErr)
 			testutil.AssertEqual(t, "probe", tc.expectProbe, actualProbe)
 		})
 	}
 }
+
+func ExampleNewHealthCheck_expectQuoting() {
+	probe, _ := NewHealthCheck("http", "health", "it's up", 0)
+	fmt.Println(probe.Exec.Command[2])
+
+	// Output: url="http://127.0.0.1:${PORT:-8080}"/health
+	// if command -v curl >/dev/null 2>&1; then body="$(curl -fsS "$url")"; else body="$(wget -q -O - "$url")"; fi || exit 1
+	// case "$body" in *'it'\''s up'*) exit 0 ;; esac
+	// echo "response from $url doesn't contain "'it'\''s up' >&2
+	// exit 1
+}
@@ -145,7 +145,7 @@ func (k *KfApp) SetEnvVars(env []corev1.EnvVar) {
 // MergeEnvVars adds the environment variables listed to the existing ones,
 // overwriting duplicates by key.
 func (k *KfApp) MergeEnvVars(env []corev1.EnvVar) {
-	k.SetEnvVars(envutil.DeduplicateEnvVars(append(k.GetEnvVars(), env...)))
+	k.SetEnvVars(envutil.OverrideEnvVars(k.GetEnvVars(), env))
 }
 
 // DeleteEnvVars removes environment variables with the given key.
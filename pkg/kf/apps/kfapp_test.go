@@ -177,7 +177,7 @@ func ExampleKfApp_GetContainerPorts() {
 }
 
 func ExampleKfApp_GetHealthCheck() {
-	check, err := NewHealthCheck("http", "/healthz", 50)
+	check, err := NewHealthCheck("http", "/healthz", "", 50)
 	if err != nil {
 		panic(err)
 	}
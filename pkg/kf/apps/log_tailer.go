@@ -23,20 +23,30 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
 
 	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/iomux"
+	"github.com/google/kf/pkg/kf/machine"
 	corev1 "k8s.io/api/core/v1"
 	k8smeta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
 )
 
 type pushLogTailer struct {
 	client               *appsClient
-	out                  io.Writer
+	buildOut             io.Writer
 	logger               *log.Logger
+	events               *machine.Writer
 	appName              string
 	resourceVersion      string
 	namespace            string
 	noStart              bool
+	timeouts             PushTimeouts
+	timeoutPhase         string
+	timeoutLimit         time.Duration
+	timeoutTimer         *time.Timer
+	lastConditions       duckv1beta1.Conditions
 	buildStartTime       time.Time
 	deployStartTime      time.Time
 	ctx                  context.Context
@@ -45,27 +55,46 @@ type pushLogTailer struct {
 	checkSourceReadyOnce sync.Once
 }
 
+// Phases and completion percentages reported to machine mode as the push
+// progresses.
+const (
+	phaseBuild  = "build"
+	phaseDeploy = "deploy"
+	phaseDone   = "done"
+
+	percentBuildStarted  = 25
+	percentBuilt         = 60
+	percentDeployStarted = 70
+	percentDone          = 100
+)
+
 func newPushLogTailer(
 	client *appsClient,
-	out io.Writer,
+	statusOut io.Writer,
+	buildOut io.Writer,
+	events *machine.Writer,
 	appName string,
 	resourceVersion string,
 	namespace string,
 	noStart bool,
+	timeouts PushTimeouts,
 ) *pushLogTailer {
 
 	t := &pushLogTailer{
 		client:          client,
-		out:             out,
+		buildOut:        buildOut,
+		events:          events,
 		appName:         appName,
 		resourceVersion: resourceVersion,
 		namespace:       namespace,
 		noStart:         noStart,
+		timeouts:        timeouts,
 	}
 
-	t.logger = log.New(out, "\033[32m[build]\033[0m ", 0)
-	t.logger.Printf("Starting app: %s\n", appName)
+	t.logger = log.New(statusOut, "\033[32m[build]\033[0m ", 0)
+	t.progress(phaseBuild, percentBuildStarted, "Starting app: %s", appName)
 	t.buildStartTime = time.Now()
+	t.startTimeoutPhase(TimeoutPhaseBuild, timeouts.Build)
 	t.ctx, t.ctxCancel = context.WithCancel(context.Background())
 	return t
 }
@@ -73,21 +102,40 @@ func newPushLogTailer(
 // DeployLogsForApp gets the deployment logs for an application. It blocks until
 // the operation has completed.
 func (a *appsClient) DeployLogsForApp(out io.Writer, app *v1alpha1.App) error {
-	return a.DeployLogs(out, app.Name, app.ResourceVersion, app.Namespace, app.Spec.Instances.Stopped)
+	return a.DeployLogs(out, app.Name, app.ResourceVersion, app.Namespace, app.Spec.Instances.Stopped, PushTimeouts{})
 }
 
 // DeployLogs writes the logs for the deploy step for the resourceVersion
-// to out. It blocks until the operation has completed.
+// to out. It blocks until the operation has completed or one of its phases
+// exceeds the limit set in timeouts.
 func (a *appsClient) DeployLogs(
 	out io.Writer,
 	appName string,
 	resourceVersion string,
 	namespace string,
 	noStart bool,
+	timeouts PushTimeouts,
 ) error {
 
-	t := newPushLogTailer(a, out, appName, resourceVersion, namespace, noStart)
+	// Build logs are tailed concurrently with status updates so both go
+	// through a mux to keep lines whole and memory bounded.
+	mux := iomux.New(out, iomux.DefaultBufferedLines)
+	statusOut := mux.Stream()
+	buildOut := mux.Stream()
+	defer func() {
+		statusOut.Close()
+		buildOut.Close()
+		mux.Close()
+	}()
+
+	// In machine mode status updates are emitted as events directly so they
+	// carry the phase and progress of the push.
+	events, _ := machine.EventWriter(out)
+
+	t := newPushLogTailer(a, statusOut, buildOut, events, appName, resourceVersion, namespace, noStart, timeouts)
 	defer t.ctxCancel()
+	// Clearing the phase stops any pending timer.
+	defer t.startTimeoutPhase("", 0)
 
 	for {
 		done, err := t.handleWatch()
@@ -113,7 +161,19 @@ func (t *pushLogTailer) handleWatch() (bool, error) {
 	}
 	defer ws.Stop()
 
-	for e := range ws.ResultChan() {
+	results := ws.ResultChan()
+	for {
+		var e watch.Event
+		select {
+		case <-t.timeoutC():
+			return true, NewPhaseTimeoutError(t.timeoutPhase, t.timeoutLimit, t.lastConditions)
+		case event, ok := <-results:
+			if !ok {
+				return false, nil
+			}
+			e = event
+		}
+
 		app, ok := e.Object.(*v1alpha1.App)
 		if !ok {
 			t.logger.Printf("Unexpected type in watch stream: %T\n", e.Object)
@@ -125,6 +185,8 @@ func (t *pushLogTailer) handleWatch() (bool, error) {
 			continue
 		}
 
+		t.lastConditions = app.Status.Conditions
+
 		done, err := t.handleUpdate(app)
 		if err != nil {
 			return true, err
@@ -133,8 +195,31 @@ func (t *pushLogTailer) handleWatch() (bool, error) {
 			return true, nil
 		}
 	}
+}
 
-	return false, nil
+// startTimeoutPhase restarts the clock for the given phase. A zero limit
+// disables the timeout.
+func (t *pushLogTailer) startTimeoutPhase(phase string, limit time.Duration) {
+	if t.timeoutTimer != nil {
+		t.timeoutTimer.Stop()
+		t.timeoutTimer = nil
+	}
+
+	t.timeoutPhase = phase
+	t.timeoutLimit = limit
+	if limit > 0 {
+		t.timeoutTimer = time.NewTimer(limit)
+	}
+}
+
+// timeoutC returns a channel that fires when the current phase times out or
+// nil if the phase has no limit.
+func (t *pushLogTailer) timeoutC() <-chan time.Time {
+	if t.timeoutTimer == nil {
+		return nil
+	}
+
+	return t.timeoutTimer.C
 }
 
 func (t *pushLogTailer) handleUpdate(
@@ -147,7 +232,7 @@ func (t *pushLogTailer) handleUpdate(
 		return false, nil
 	}
 	if sourceReady.Message != "" {
-		t.logger.Printf("Updated state to: %s\n", sourceReady.Message)
+		t.progress(phaseBuild, percentBuildStarted, "Updated state to: %s", sourceReady.Message)
 	}
 
 	switch sourceReady.Status {
@@ -155,14 +240,15 @@ func (t *pushLogTailer) handleUpdate(
 		// Only handle source success case once
 		t.checkSourceReadyOnce.Do(func() {
 			duration := time.Now().Sub(t.buildStartTime)
-			t.logger.Printf("Built in %0.2f seconds\n", duration.Seconds())
+			t.progress(phaseBuild, percentBuilt, "Built in %0.2f seconds", duration.Seconds())
 			t.ctxCancel()
 			t.deployStartTime = time.Now()
+			t.startTimeoutPhase(TimeoutPhaseDeploy, t.timeouts.Deploy)
 		})
 	case corev1.ConditionFalse:
-		t.logger.Printf("Failed to build: %s\n", sourceReady.Message)
+		t.progress(phaseBuild, percentBuildStarted, "Failed to build: %s", sourceReady.Message)
 		t.ctxCancel()
-		return true, fmt.Errorf("build failed: %s", sourceReady.Message)
+		return true, machine.WithExitCode(machine.ExitBuildFailed, fmt.Errorf("build failed: %s", sourceReady.Message))
 	default:
 
 		// This case should mean the Source is still in progress.
@@ -170,37 +256,63 @@ func (t *pushLogTailer) handleUpdate(
 		go t.tailBuildLogsOnce.Do(
 			func() {
 				// ignoring tail errs because they are spurious
-				t.client.sourcesClient.Tail(t.ctx, t.namespace, app.Status.LatestCreatedSourceName, t.out)
+				t.client.sourcesClient.Tail(t.ctx, t.namespace, app.Status.LatestCreatedSourceName, t.buildOut)
 			},
 		)
 		return false, nil
 	}
 
 	if t.noStart {
-		t.logger.Printf("Total deploy time %0.2f seconds\n", time.Now().Sub(t.deployStartTime).Seconds())
+		t.progress(phaseDone, percentDone, "Total deploy time %0.2f seconds", time.Now().Sub(t.deployStartTime).Seconds())
 		return true, nil
 	}
 
+	serviceReady := app.Status.GetCondition(v1alpha1.AppConditionKnativeServiceReady)
+	if serviceReady.IsTrue() && t.timeoutPhase == TimeoutPhaseDeploy {
+		t.startTimeoutPhase(TimeoutPhaseRoute, t.timeouts.Route)
+	}
+
 	appReady := app.Status.GetCondition(v1alpha1.AppConditionReady)
 	if appReady == nil {
 		return false, nil
 	}
 	if appReady.Message != "" {
-		t.logger.Printf("Updated state to: %s\n", appReady.Message)
+		t.progress(phaseDeploy, percentDeployStarted, "Updated state to: %s", appReady.Message)
 	}
 
 	switch appReady.Status {
 	case corev1.ConditionTrue:
+		// Controllers that predate route status don't set RouteReady, so only
+		// wait on it when it's present. Route errors are retried by the
+		// controller.
+		if routeReady := app.Status.GetCondition(v1alpha1.AppConditionRouteReady); routeReady != nil && !routeReady.IsTrue() {
+			if routeReady.Message != "" {
+				t.progress(phaseDeploy, percentDeployStarted, "Updated route state to: %s", routeReady.Message)
+			}
+			return false, nil
+		}
+
 		now := time.Now()
 		duration := now.Sub(t.buildStartTime)
 		deployDuration := now.Sub(t.deployStartTime)
-		t.logger.Printf("App took %0.2f seconds to become ready.\n", deployDuration.Seconds())
-		t.logger.Printf("Total deploy time %0.2f seconds\n", duration.Seconds())
+		t.progress(phaseDeploy, percentDone, "App took %0.2f seconds to become ready.", deployDuration.Seconds())
+		t.progress(phaseDone, percentDone, "Total deploy time %0.2f seconds", duration.Seconds())
 		return true, nil
 	case corev1.ConditionFalse:
-		t.logger.Printf("Failed to deploy: %s\n", appReady.Message)
-		return true, fmt.Errorf("deployment failed: %s", appReady.Message)
+		t.progress(phaseDeploy, percentDeployStarted, "Failed to deploy: %s", appReady.Message)
+		return true, machine.WithExitCode(machine.ExitDeployFailed, fmt.Errorf("deployment failed: %s", appReady.Message))
 	}
 
 	return false, nil
 }
+
+// progress reports a status update, as an event in machine mode and as a log
+// line otherwise.
+func (t *pushLogTailer) progress(phase string, percent int, format string, args ...interface{}) {
+	if t.events != nil {
+		t.events.Emit(phase, percent, fmt.Sprintf(format, args...))
+		return
+	}
+
+	t.logger.Printf(format+"\n", args...)
+}
@@ -19,11 +19,13 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	v1alpha1fake "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1/fake"
 	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/machine"
 	sourcesfake "github.com/google/kf/pkg/kf/sources/fake"
 	"github.com/google/kf/pkg/kf/testutil"
 	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
@@ -136,6 +138,7 @@ func TestLogTailer_DeployLogs_ServiceLogs(t *testing.T) {
 				tc.resourceVersion,
 				tc.namespace,
 				tc.noStart,
+				apps.PushTimeouts{},
 			)
 			if tc.wantErr != nil || gotErr != nil {
 				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
@@ -159,6 +162,138 @@ func TestLogTailer_DeployLogs_ServiceLogs(t *testing.T) {
 	}
 }
 
+func TestLogTailer_DeployLogs_MachineMode(t *testing.T) {
+	t.Parallel()
+
+	for tn, tc := range map[string]struct {
+		conditions   duckv1beta1.Conditions
+		wantedMsgs   []string
+		wantExitCode int
+	}{
+		"successful deploy": {
+			conditions: duckv1beta1.Conditions{
+				{Type: "SourceReady", Status: "True"},
+				{Type: "Ready", Status: "True"},
+			},
+			wantedMsgs: []string{
+				`{"phase":"build","percent":25,"message":"Starting app: some-app"}`,
+				`{"phase":"build","percent":60,"message":"Built in`,
+				`{"phase":"done","percent":100,"message":"Total deploy time`,
+			},
+			wantExitCode: machine.ExitOK,
+		},
+		"failed build": {
+			conditions: duckv1beta1.Conditions{
+				{Type: "SourceReady", Status: "False", Message: "some-error"},
+			},
+			wantedMsgs: []string{
+				`{"phase":"build","percent":25,"message":"Failed to build: some-error"}`,
+			},
+			wantExitCode: machine.ExitBuildFailed,
+		},
+		"failed deploy": {
+			conditions: duckv1beta1.Conditions{
+				{Type: "SourceReady", Status: "True"},
+				{Type: "Ready", Status: "False", Message: "some-error"},
+			},
+			wantedMsgs: []string{
+				`{"phase":"deploy","percent":70,"message":"Failed to deploy: some-error"}`,
+			},
+			wantExitCode: machine.ExitDeployFailed,
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl, fakeApps := buildLogWatchFakes(
+				t,
+				createMsgEvents("some-app", tc.conditions), nil,
+				nil, nil,
+			)
+
+			sourceClient := sourcesfake.NewFakeClient(ctrl)
+			lt := apps.NewClient(fakeApps, sourceClient)
+
+			var buffer bytes.Buffer
+			gotErr := lt.DeployLogs(
+				machine.NewWriter(&buffer),
+				"some-app",
+				"some-version",
+				"default",
+				false,
+				apps.PushTimeouts{},
+			)
+
+			testutil.AssertEqual(t, "exit code", tc.wantExitCode, machine.ExitCode(gotErr))
+			testutil.AssertContainsAll(t, buffer.String(), tc.wantedMsgs)
+
+			ctrl.Finish()
+		})
+	}
+}
+
+func TestLogTailer_DeployLogs_Timeouts(t *testing.T) {
+	t.Parallel()
+
+	for tn, tc := range map[string]struct {
+		conditions duckv1beta1.Conditions
+		timeouts   apps.PushTimeouts
+		wantErrs   []string
+	}{
+		"deploy stalls": {
+			conditions: duckv1beta1.Conditions{
+				{Type: "SourceReady", Status: "True"},
+				{Type: "KnativeServiceReady", Status: "Unknown", Reason: "RevisionMissing", Message: "waiting for revision"},
+				{Type: "Ready", Status: "Unknown", Reason: "RevisionMissing", Message: "waiting for revision"},
+			},
+			timeouts: apps.PushTimeouts{Deploy: 10 * time.Millisecond},
+			wantErrs: []string{
+				"deploy phase timed out after 10ms",
+				"--deploy-timeout",
+				"KnativeServiceReady: Unknown (RevisionMissing) waiting for revision",
+			},
+		},
+		"routes stall": {
+			conditions: duckv1beta1.Conditions{
+				{Type: "SourceReady", Status: "True"},
+				{Type: "KnativeServiceReady", Status: "True"},
+				{Type: "Ready", Status: "True"},
+				{Type: "RouteReady", Status: "False", Reason: "ReconciliationError", Message: "route conflict"},
+			},
+			timeouts: apps.PushTimeouts{Deploy: time.Hour, Route: 10 * time.Millisecond},
+			wantErrs: []string{
+				"route phase timed out after 10ms",
+				"--route-timeout",
+				"RouteReady: False (ReconciliationError) route conflict",
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl, fakeApps := buildLogWatchFakes(
+				t,
+				createMsgEvents("some-app", tc.conditions), nil,
+				nil, nil,
+			)
+
+			sourceClient := sourcesfake.NewFakeClient(ctrl)
+			lt := apps.NewClient(fakeApps, sourceClient)
+
+			var buffer bytes.Buffer
+			gotErr := lt.DeployLogs(
+				&buffer,
+				"some-app",
+				"some-version",
+				"default",
+				false,
+				tc.timeouts,
+			)
+
+			testutil.AssertEqual(t, "exit code", machine.ExitTimeout, machine.ExitCode(gotErr))
+			testutil.AssertErrorContainsAll(t, gotErr, tc.wantErrs)
+
+			ctrl.Finish()
+		})
+	}
+}
+
 func testWatch(t *testing.T, action ktesting.Action, resource, namespace, resourceVersion string) {
 	t.Helper()
 	testutil.AssertEqual(t, "namespace", namespace, action.GetNamespace())
@@ -0,0 +1,116 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// RouteStrategyKeep keeps the routes of an App that already exists in the
+	// target space, new Apps get the space's default route.
+	RouteStrategyKeep = "keep"
+
+	// RouteStrategyDefault replaces the App's routes with the target space's
+	// default route.
+	RouteStrategyDefault = "default"
+
+	// RouteStrategyNone removes all of the App's routes so it can be checked
+	// before it receives traffic.
+	RouteStrategyNone = "none"
+)
+
+// RouteStrategies are the strategies that can be used to pick the routes of a
+// promoted App.
+var RouteStrategies = []string{RouteStrategyKeep, RouteStrategyDefault, RouteStrategyNone}
+
+// Promotion records an App's built image being copied between spaces.
+type Promotion struct {
+	FromSpace string      `json:"fromSpace"`
+	ToSpace   string      `json:"toSpace"`
+	Image     string      `json:"image"`
+	Time      metav1.Time `json:"time"`
+}
+
+// RecordPromotion sets the PromotionAnnotation on the App.
+func RecordPromotion(app *v1alpha1.App, promotion Promotion) error {
+	record, err := json.Marshal(promotion)
+	if err != nil {
+		return err
+	}
+
+	if app.Annotations == nil {
+		app.Annotations = make(map[string]string)
+	}
+	app.Annotations[v1alpha1.PromotionAnnotation] = string(record)
+
+	return nil
+}
+
+// PromoteApp creates the App that runs the promoted image in the target space.
+// If target is nil, a new App is created from the source's runtime
+// configuration. Service bindings aren't copied because service instances
+// belong to a single space.
+func PromoteApp(
+	source *v1alpha1.App,
+	target *v1alpha1.App,
+	promotion Promotion,
+	routeStrategy string,
+	defaultDomain string,
+) (*v1alpha1.App, error) {
+	var out *v1alpha1.App
+	if target != nil {
+		out = target.DeepCopy()
+	} else {
+		out = &v1alpha1.App{}
+		out.Name = source.Name
+		out.Namespace = promotion.ToSpace
+		out.Spec.Template = *source.Spec.Template.DeepCopy()
+		out.Spec.Instances = *source.Spec.Instances.DeepCopy()
+		out.Spec.BindingFormat = source.Spec.BindingFormat
+	}
+
+	out.Spec.Source.ContainerImage = v1alpha1.SourceSpecContainerImage{Image: promotion.Image}
+	out.Spec.Source.BuildpackBuild = v1alpha1.SourceSpecBuildpackBuild{}
+	out.Spec.Source.Dockerfile = v1alpha1.SourceSpecDockerfile{}
+	out.Spec.Source.Git = nil
+
+	defaultRoutes := []v1alpha1.RouteSpecFields{
+		{Hostname: out.Name, Domain: defaultDomain},
+	}
+
+	switch routeStrategy {
+	case RouteStrategyKeep:
+		if target == nil {
+			out.Spec.Routes = defaultRoutes
+		}
+	case RouteStrategyDefault:
+		out.Spec.Routes = defaultRoutes
+	case RouteStrategyNone:
+		out.Spec.Routes = nil
+	default:
+		return nil, fmt.Errorf("unknown route strategy %q, supported strategies are %v", routeStrategy, RouteStrategies)
+	}
+
+	if err := RecordPromotion(out, promotion); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
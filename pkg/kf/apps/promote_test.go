@@ -0,0 +1,123 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPromoteApp(t *testing.T) {
+	t.Parallel()
+
+	promotion := apps.Promotion{
+		FromSpace: "staging",
+		ToSpace:   "production",
+		Image:     "gcr.io/my-app@sha256:abc",
+		Time:      metav1.NewTime(time.Date(2019, 10, 1, 12, 0, 0, 0, time.UTC)),
+	}
+	wantRecord := `{"fromSpace":"staging","toSpace":"production","image":"gcr.io/my-app@sha256:abc","time":"2019-10-01T12:00:00Z"}`
+
+	source := &v1alpha1.App{}
+	source.Name = "my-app"
+	source.Namespace = "staging"
+	source.Spec.Source.BuildpackBuild.Source = "gcr.io/my-app-source"
+	source.Spec.Template.Spec.Containers = []corev1.Container{
+		{Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}},
+	}
+	source.Spec.Routes = []v1alpha1.RouteSpecFields{{Hostname: "my-app", Domain: "staging.example.com"}}
+	source.Spec.ServiceBindings = []v1alpha1.AppSpecServiceBinding{{Instance: "staging-db"}}
+
+	existingRoutes := []v1alpha1.RouteSpecFields{{Hostname: "www", Domain: "example.com"}}
+	target := &v1alpha1.App{}
+	target.Name = "my-app"
+	target.Namespace = "production"
+	target.Spec.Template.Spec.Containers = []corev1.Container{
+		{Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}}},
+	}
+	target.Spec.Routes = existingRoutes
+	target.Spec.ServiceBindings = []v1alpha1.AppSpecServiceBinding{{Instance: "production-db"}}
+
+	defaultRoutes := []v1alpha1.RouteSpecFields{{Hostname: "my-app", Domain: "prod.example.com"}}
+
+	cases := map[string]struct {
+		target        *v1alpha1.App
+		routeStrategy string
+		wantErr       error
+		wantRoutes    []v1alpha1.RouteSpecFields
+		wantEnv       string
+		wantBindings  int
+	}{
+		"new app keeps source runtime config": {
+			routeStrategy: apps.RouteStrategyKeep,
+			wantRoutes:    defaultRoutes,
+			wantEnv:       "debug",
+		},
+		"existing app keeps its config and routes": {
+			target:        target,
+			routeStrategy: apps.RouteStrategyKeep,
+			wantRoutes:    existingRoutes,
+			wantEnv:       "info",
+			wantBindings:  1,
+		},
+		"default routes": {
+			target:        target,
+			routeStrategy: apps.RouteStrategyDefault,
+			wantRoutes:    defaultRoutes,
+			wantEnv:       "info",
+			wantBindings:  1,
+		},
+		"no routes": {
+			target:        target,
+			routeStrategy: apps.RouteStrategyNone,
+			wantEnv:       "info",
+			wantBindings:  1,
+		},
+		"unknown strategy": {
+			routeStrategy: "copy",
+			wantErr:       errors.New(`unknown route strategy "copy", supported strategies are [keep default none]`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			app, err := apps.PromoteApp(source, tc.target, promotion, tc.routeStrategy, "prod.example.com")
+			if tc.wantErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "name", "my-app", app.Name)
+			testutil.AssertEqual(t, "namespace", "production", app.Namespace)
+			testutil.AssertEqual(t, "image", "gcr.io/my-app@sha256:abc", app.Spec.Source.ContainerImage.Image)
+			testutil.AssertEqual(t, "buildpack build", v1alpha1.SourceSpecBuildpackBuild{}, app.Spec.Source.BuildpackBuild)
+			testutil.AssertEqual(t, "routes", tc.wantRoutes, app.Spec.Routes)
+			testutil.AssertEqual(t, "env", tc.wantEnv, app.Spec.Template.Spec.Containers[0].Env[0].Value)
+			testutil.AssertEqual(t, "bindings", tc.wantBindings, len(app.Spec.ServiceBindings))
+			testutil.AssertEqual(t, "record", wantRecord, app.Annotations[v1alpha1.PromotionAnnotation])
+
+			// The inputs must not be modified.
+			testutil.AssertEqual(t, "target routes", existingRoutes, target.Spec.Routes)
+			testutil.AssertEqual(t, "target annotations", 0, len(target.Annotations))
+		})
+	}
+}
@@ -15,35 +15,49 @@
 package apps
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
-	"strconv"
 	"strings"
 	"time"
 
 	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/internal/envutil"
+	"github.com/google/kf/pkg/kf/routeclaims"
 	"github.com/google/kf/pkg/kf/sources"
+	"github.com/google/kf/pkg/kf/tracing"
+	"github.com/knative/serving/pkg/resources"
+	"go.opencensus.io/trace"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 //go:generate go run ../internal/tools/option-builder/option-builder.go push-options.yml push_options.go
 
 // pusher deploys source code to Knative. It should be created via NewPusher.
 type pusher struct {
-	appsClient Client
+	appsClient        Client
+	routeClaimsClient routeclaims.Client
+	random            *rand.Rand
 }
 
 // Pusher deploys applications.
 type Pusher interface {
 	// Push deploys an application.
 	Push(appName string, opts ...PushOption) error
+
+	// Preview returns the App Push would create or update without changing
+	// the cluster.
+	Preview(appName string, opts ...PushOption) (*v1alpha1.App, error)
 }
 
 // NewPusher creates a new Pusher.
-func NewPusher(appsClient Client) Pusher {
+func NewPusher(appsClient Client, routeClaimsClient routeclaims.Client) Pusher {
 	return &pusher{
-		appsClient: appsClient,
+		appsClient:        appsClient,
+		routeClaimsClient: routeClaimsClient,
+		random:            rand.New(rand.NewSource(time.Now().UnixNano())),
 	}
 }
 
@@ -64,6 +78,7 @@ func newApp(appName string, opts ...PushOption) (*v1alpha1.App, error) {
 	case cfg.DockerfilePath != "":
 		src.SetDockerfilePath(cfg.DockerfilePath)
 		src.SetDockerfileSource(cfg.SourceImage)
+		src.SetDockerfileBuildArgs(cfg.DockerfileBuildArgs)
 
 	default: // default to buildpack build
 		src.SetBuildpackBuildEnv(envs)
@@ -81,6 +96,9 @@ func newApp(appName string, opts ...PushOption) (*v1alpha1.App, error) {
 	app.SetHealthCheck(cfg.HealthCheck)
 	app.Spec.Routes = cfg.Routes
 	app.Spec.ServiceBindings = cfg.ServiceBindings
+	app.Spec.BindingFormat = cfg.BindingFormat
+	app.Spec.Processes = cfg.Processes
+	app.Spec.Rollout = cfg.Rollout
 	app.SetCommand(cfg.Command)
 	app.SetArgs(cfg.Args)
 
@@ -97,80 +115,264 @@ func newApp(appName string, opts ...PushOption) (*v1alpha1.App, error) {
 
 // Push deploys an application to Knative. It can be configured via
 // Optionapp.
-func (p *pusher) Push(appName string, opts ...PushOption) error {
+func (p *pusher) Push(appName string, opts ...PushOption) (err error) {
 	cfg := PushOptionDefaults().Extend(opts).toConfig()
 
-	app, err := newApp(appName, opts...)
+	ctx, span := tracing.StartSpan(cfg.Context, "Push app", trace.StringAttribute("kf.dev/app", appName))
+	defer func() { tracing.EndSpan(span, err) }()
+
+	app, hasDefaultRoutes, err := p.desiredApp(cfg, appName, opts)
 	if err != nil {
-		return fmt.Errorf("failed to create app: %s", err)
+		return err
 	}
 
-	var hasDefaultRoutes bool
-	app.Spec.Routes, hasDefaultRoutes = setupRoutes(cfg, app.Name, app.Spec.Routes)
+	merge := mergeApps(cfg, hasDefaultRoutes)
 
-	// Scaling
-	if noScaling(app.Spec.Instances) {
-		// Default to 1
-		singleInstance := 1
-		app.Spec.Instances.Exactly = &singleInstance
+	if !cfg.ForceBuild {
+		oldApp, err := p.existingApp(app)
+		if err != nil {
+			return fmt.Errorf("failed to push app: %s", err)
+		}
+
+		if oldApp != nil && specsAreSemanticallyEqual(merge(app.DeepCopy(), oldApp.DeepCopy()), oldApp) {
+			if _, err := fmt.Fprintf(cfg.Output, "%q is unchanged since the last push, skipping build and deploy\n", appName); err != nil {
+				return err
+			}
+
+			return p.reportReady(ctx, cfg, oldApp)
+		}
 	}
 
+	_, upsertSpan := tracing.StartSpan(ctx, "Update app")
 	resultingApp, err := p.appsClient.Upsert(
 		app.Namespace,
 		app,
-		mergeApps(cfg, hasDefaultRoutes),
+		func(newapp, oldapp *v1alpha1.App) *v1alpha1.App {
+			newapp = merge(newapp, oldapp)
+
+			if cfg.ForceBuild {
+				newapp.Spec.Source.UpdateRequests = oldapp.Spec.Source.UpdateRequests + 1
+			}
+
+			return newapp
+		},
 	)
+	tracing.EndSpan(upsertSpan, err)
 	if err != nil {
 		return fmt.Errorf("failed to push app: %s", err)
 	}
 
-	if err := p.appsClient.DeployLogsForApp(cfg.Output, resultingApp); err != nil {
+	return p.deploy(ctx, cfg, resultingApp)
+}
+
+// reportReady reports the readiness of an App that wasn't updated. Apps that
+// are still reconciling are waited on like a normal deploy.
+func (p *pusher) reportReady(ctx context.Context, cfg pushConfig, app *v1alpha1.App) error {
+	if app.Generation == app.Status.ObservedGeneration {
+		if cond := app.Status.GetCondition(v1alpha1.AppConditionReady); cond != nil {
+			switch cond.Status {
+			case corev1.ConditionTrue:
+				return p.reportDeployed(cfg, app)
+			case corev1.ConditionFalse:
+				return fmt.Errorf("%q isn't ready: %s", app.Name, cond.Message)
+			}
+		}
+	}
+
+	return p.deploy(ctx, cfg, app)
+}
+
+// deploy waits for the App to be built and deployed.
+func (p *pusher) deploy(ctx context.Context, cfg pushConfig, resultingApp *v1alpha1.App) error {
+	_, deploySpan := tracing.StartSpan(ctx, "Build and deploy")
+	err := p.appsClient.DeployLogs(
+		cfg.Output,
+		resultingApp.Name,
+		resultingApp.ResourceVersion,
+		resultingApp.Namespace,
+		resultingApp.Spec.Instances.Stopped,
+		cfg.Timeouts,
+	)
+	tracing.EndSpan(deploySpan, err)
+	if err != nil {
 		return err
 	}
 
+	return p.reportDeployed(cfg, resultingApp)
+}
+
+// reportDeployed tells the user the App is running.
+func (p *pusher) reportDeployed(cfg pushConfig, resultingApp *v1alpha1.App) error {
 	status := "deployed"
 	if resultingApp.Spec.Instances.Stopped {
 		status = "deployed without starting"
 	}
 
-	_, err = fmt.Fprintf(cfg.Output, "%q successfully %s\n", appName, status)
+	_, err := fmt.Fprintf(cfg.Output, "%q successfully %s\n", resultingApp.Name, status)
 	return err
 }
 
-func setupRoutes(cfg pushConfig, appName string, r []v1alpha1.RouteSpecFields) (routes []v1alpha1.RouteSpecFields, hasDefaultRoutes bool) {
+// Preview implements Pusher. The App is merged with the existing one the same
+// way Push does it, so its routes, scale and environment are the ones that
+// would be deployed.
+func (p *pusher) Preview(appName string, opts ...PushOption) (*v1alpha1.App, error) {
+	cfg := PushOptionDefaults().Extend(opts).toConfig()
+
+	app, hasDefaultRoutes, err := p.desiredApp(cfg, appName, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	oldApp, err := p.existingApp(app)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldApp != nil {
+		return mergeApps(cfg, hasDefaultRoutes)(app, oldApp), nil
+	}
+
+	return app, nil
+}
+
+// existingApp gets the deployed version of app or nil if it doesn't exist.
+func (p *pusher) existingApp(app *v1alpha1.App) (*v1alpha1.App, error) {
+	existing, err := p.appsClient.List(app.Namespace, WithListFieldSelector(map[string]string{"metadata.name": app.Name}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the existing app: %s", err)
+	}
+
+	for i := range existing {
+		if existing[i].Name == app.Name {
+			return &existing[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// desiredApp creates the App described by the options with its routes and
+// scaling defaults resolved.
+func (p *pusher) desiredApp(cfg pushConfig, appName string, opts []PushOption) (app *v1alpha1.App, hasDefaultRoutes bool, err error) {
+	app, err = newApp(appName, opts...)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create app: %s", err)
+	}
+
+	app.Spec.Routes, hasDefaultRoutes, err = p.setupRoutes(cfg, app.Name, app.Spec.Routes)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Scaling
+	if noScaling(app.Spec.Instances) {
+		// Default to 1
+		singleInstance := 1
+		app.Spec.Instances.Exactly = &singleInstance
+	}
+
+	return app, hasDefaultRoutes, nil
+}
+
+// setupRoutes picks the routes the App is pushed with. Default and random
+// routes are only used if the App doesn't already have routes, which is
+// signaled by hasDefaultRoutes.
+func (p *pusher) setupRoutes(cfg pushConfig, appName string, r []v1alpha1.RouteSpecFields) (routes []v1alpha1.RouteSpecFields, hasDefaultRoutes bool, err error) {
 	switch {
+	case cfg.NoRoute:
+		// Removes any routes left over from previous pushes.
+		return nil, false, nil
 	case len(r) != 0:
 		// Don't overwrite the routes
-		return r, false
+		return r, false, nil
 	case cfg.DefaultRouteDomain != "":
 		return []v1alpha1.RouteSpecFields{
 			{
 				Domain:   cfg.DefaultRouteDomain,
 				Hostname: appName,
 			},
-		}, true
+		}, true, nil
 	case cfg.RandomRouteDomain != "":
-		return []v1alpha1.RouteSpecFields{
-			{
-				Domain: cfg.RandomRouteDomain,
-				Hostname: strings.Join([]string{
-					appName,
-					strconv.FormatUint(rand.Uint64(), 36),
-					strconv.FormatUint(uint64(time.Now().UnixNano()), 36),
-				}, "-"),
-			},
-		}, true
+		route, err := p.randomRoute(cfg.Namespace, appName, cfg.RandomRouteDomain)
+		if err != nil {
+			return nil, false, err
+		}
+
+		return []v1alpha1.RouteSpecFields{route}, true, nil
 	default:
-		return nil, false
+		return nil, false, nil
 	}
 }
 
+// randomRouteAttempts is the number of hostnames tried before giving up on
+// finding an unused random route.
+const randomRouteAttempts = 10
+
+// randomRoute generates a route on the domain with a random hostname that
+// isn't claimed yet.
+func (p *pusher) randomRoute(namespace, appName, domain string) (v1alpha1.RouteSpecFields, error) {
+	// Routes from every space share the gateway so hostnames are checked
+	// across the cluster if the user is allowed to.
+	claims, err := p.routeClaimsClient.List(metav1.NamespaceAll)
+	if err != nil {
+		if claims, err = p.routeClaimsClient.List(namespace); err != nil {
+			return v1alpha1.RouteSpecFields{}, fmt.Errorf("failed to list RouteClaims: %s", err)
+		}
+	}
+
+	used := make(map[string]bool)
+	for _, claim := range claims {
+		used[claim.Spec.Hostname+"."+claim.Spec.Domain] = true
+	}
+
+	for i := 0; i < randomRouteAttempts; i++ {
+		hostname := RandomHostname(appName, p.random)
+		if !used[hostname+"."+domain] {
+			return v1alpha1.RouteSpecFields{Hostname: hostname, Domain: domain}, nil
+		}
+	}
+
+	return v1alpha1.RouteSpecFields{}, fmt.Errorf("couldn't find an unused random route for %s on %s", appName, domain)
+}
+
+// RandomHostname generates a hostname for the App with a random suffix. The
+// App's name is truncated so the result is a valid DNS label.
+func RandomHostname(appName string, r *rand.Rand) string {
+	const (
+		maxLabelLength = 63
+		suffixLength   = 8
+		suffixAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	)
+
+	suffix := make([]byte, suffixLength)
+	for i := range suffix {
+		suffix[i] = suffixAlphabet[r.Intn(len(suffixAlphabet))]
+	}
+
+	prefix := appName
+	if max := maxLabelLength - suffixLength - 1; len(prefix) > max {
+		prefix = strings.TrimRight(prefix[:max], "-")
+	}
+
+	return prefix + "-" + string(suffix)
+}
+
 func noScaling(instances v1alpha1.AppSpecInstances) bool {
 	return instances.Exactly == nil &&
 		instances.Min == nil &&
 		instances.Max == nil
 }
 
+// serviceBindingName returns the name of the binding after defaults are
+// applied.
+func serviceBindingName(binding v1alpha1.AppSpecServiceBinding) string {
+	if binding.BindingName != "" {
+		return binding.BindingName
+	}
+
+	return binding.Instance
+}
+
 func mergeApps(cfg pushConfig, hasDefaultRoutes bool) func(newapp, oldapp *v1alpha1.App) *v1alpha1.App {
 	return func(newapp, oldapp *v1alpha1.App) *v1alpha1.App {
 
@@ -186,6 +388,16 @@ func mergeApps(cfg pushConfig, hasDefaultRoutes bool) func(newapp, oldapp *v1alp
 			newapp.Spec.Instances.Max = oldapp.Spec.Instances.Max
 		}
 
+		// Schedules are set with kf configure-app rather than pushes.
+		if newapp.Spec.Instances.Schedule == nil {
+			newapp.Spec.Instances.Schedule = oldapp.Spec.Instances.Schedule
+		}
+
+		// Cold start timeouts aren't set by pushes either.
+		if newapp.Spec.Instances.ColdStartTimeoutSeconds == nil {
+			newapp.Spec.Instances.ColdStartTimeoutSeconds = oldapp.Spec.Instances.ColdStartTimeoutSeconds
+		}
+
 		// Default scaling
 		if noScaling(cfg.AppSpecInstances) && noScaling(oldapp.Spec.Instances) {
 			// No scaling in old or new, go with a default of 1. This is to
@@ -195,18 +407,75 @@ func mergeApps(cfg pushConfig, hasDefaultRoutes bool) func(newapp, oldapp *v1alp
 			newapp.Spec.Instances.Exactly = &singleInstance
 		}
 
+		// Credential rotation is configured with kf configure-binding rather
+		// than in manifests so keep it for bindings that still exist.
+		for i := range newapp.Spec.ServiceBindings {
+			newBinding := &newapp.Spec.ServiceBindings[i]
+			for _, oldBinding := range oldapp.Spec.ServiceBindings {
+				if newBinding.Rotation == nil && serviceBindingName(*newBinding) == serviceBindingName(oldBinding) {
+					newBinding.Rotation = oldBinding.Rotation
+				}
+			}
+		}
+
+		// Processes are scaled with kf scale --process so keep the instances
+		// of processes that are still defined.
+		for i := range newapp.Spec.Processes {
+			newProcess := &newapp.Spec.Processes[i]
+			if oldProcess := oldapp.Spec.Process(newProcess.Type); oldProcess != nil && newProcess.Instances == nil {
+				newProcess.Instances = oldProcess.Instances
+			}
+		}
+
+		// Objects to restart on are configured with kf configure-app.
+		if len(newapp.Spec.RestartOnChange) == 0 {
+			newapp.Spec.RestartOnChange = oldapp.Spec.RestartOnChange
+		}
+
+		// Egress IP pools are configured with kf configure-app.
+		if newapp.Spec.EgressIPPool == "" {
+			newapp.Spec.EgressIPPool = oldapp.Spec.EgressIPPool
+		}
+
+		// Finalizers, labels and annotations are set by the controller and
+		// other commands, pushes only add to them.
+		newapp.Finalizers = oldapp.Finalizers
+		newapp.Labels = resources.UnionMaps(oldapp.Labels, newapp.Labels)
+		newapp.Annotations = resources.UnionMaps(oldapp.Annotations, newapp.Annotations)
+
+		// Someone is waiting on the push so it mustn't inherit a scheduled
+		// priority from an earlier automated rebuild.
+		v1alpha1.SetBuildPriority(newapp, v1alpha1.BuildPriorityInteractive)
+
 		newapp.ResourceVersion = oldapp.ResourceVersion
 		newEnvs := envutil.GetAppEnvVars(newapp)
 		oldEnvs := envutil.GetAppEnvVars(oldapp)
-		envutil.SetAppEnvVars(newapp, envutil.DeduplicateEnvVars(append(oldEnvs, newEnvs...)))
+		envutil.SetAppEnvVars(newapp, envutil.OverrideEnvVars(oldEnvs, newEnvs))
 
 		return newapp
 	}
 }
 
+// specsAreSemanticallyEqual returns true if pushing newapp wouldn't change the
+// deployed oldapp. UpdateRequests are ignored because pushes don't set them.
+// A rollout starting on an App without one is ignored too, pinning traffic
+// to the revision that's already running changes nothing.
+func specsAreSemanticallyEqual(newapp, oldapp *v1alpha1.App) bool {
+	desired := newapp.Spec.DeepCopy()
+	desired.Source.UpdateRequests = oldapp.Spec.Source.UpdateRequests
+	desired.Template.UpdateRequests = oldapp.Spec.Template.UpdateRequests
+	if oldapp.Spec.Rollout == nil {
+		desired.Rollout = nil
+	}
+
+	return equality.Semantic.DeepEqual(*desired, oldapp.Spec)
+}
+
 // SourceImageName gets the image name for source code for an application.
-func SourceImageName(namespace, appName string) string {
-	return fmt.Sprintf("src-%s-%s:%d", namespace, appName, time.Now().UnixNano())
+// The digest of the source is used as the tag so pushing identical source
+// results in an identical image name and doesn't trigger a new build.
+func SourceImageName(namespace, appName, sourceDigest string) string {
+	return fmt.Sprintf("src-%s-%s:%s", namespace, appName, sourceDigest)
 }
 
 // JoinRepositoryImage joins a repository and image name.
@@ -17,6 +17,7 @@
 package apps
 
 import (
+	"context"
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"io"
 	corev1 "k8s.io/api/core/v1"
@@ -28,30 +29,44 @@ type pushConfig struct {
 	AppSpecInstances v1alpha1.AppSpecInstances
 	// Args is the app container arguments
 	Args []string
+	// BindingFormat is how service binding credentials are provided to the app, vcap or k8s
+	BindingFormat string
 	// Buildpack is skip the detect buildpack step and use the given name
 	Buildpack string
 	// Command is the app container entrypoint
 	Command []string
 	// ContainerImage is the container to deploy
 	ContainerImage string
+	// Context is the context to trace the push in
+	Context context.Context
 	// DefaultRouteDomain is Domain for a defaultroute. Only used if a route doesn't already exist
 	DefaultRouteDomain string
+	// DockerfileBuildArgs is build args to pass to the Dockerfile build
+	DockerfileBuildArgs []corev1.EnvVar
 	// DockerfilePath is the path to a Dockerfile to build
 	DockerfilePath string
 	// EnvironmentVariables is set environment variables
 	EnvironmentVariables map[string]string
+	// ForceBuild is rebuild the app even if nothing changed since the last push
+	ForceBuild bool
 	// Grpc is setup the ports for the container to allow gRPC to work
 	Grpc bool
 	// HealthCheck is the health check to use on the app
 	HealthCheck *corev1.Probe
 	// Namespace is the Kubernetes namespace to use
 	Namespace string
+	// NoRoute is don't map any routes to the app and remove routes from previous pushes
+	NoRoute bool
 	// Output is the io.Writer to write output such as build logs
 	Output io.Writer
+	// Processes is the process types the app defines, e.g. in a Procfile
+	Processes []v1alpha1.AppSpecProcess
 	// RandomRouteDomain is Domain for a random route. Only used if a route doesn't already exist
 	RandomRouteDomain string
 	// ResourceRequests is Resource requests for the container
 	ResourceRequests corev1.ResourceList
+	// Rollout is the traffic split between the app's stable and latest revisions, nil sends all traffic to the latest
+	Rollout *v1alpha1.AppSpecRollout
 	// Routes is routes for the app
 	Routes []v1alpha1.RouteSpecFields
 	// ServiceBindings is a list of Services to bind to the app
@@ -60,6 +75,8 @@ type pushConfig struct {
 	SourceImage string
 	// Stack is the builder stack to use for buildpack based apps
 	Stack string
+	// Timeouts is limits for each phase of the push, zero values are unlimited
+	Timeouts PushTimeouts
 }
 
 // PushOption is a single option for configuring a pushConfig
@@ -100,6 +117,12 @@ func (opts PushOptions) Args() []string {
 	return opts.toConfig().Args
 }
 
+// BindingFormat returns the last set value for BindingFormat or the empty value
+// if not set.
+func (opts PushOptions) BindingFormat() string {
+	return opts.toConfig().BindingFormat
+}
+
 // Buildpack returns the last set value for Buildpack or the empty value
 // if not set.
 func (opts PushOptions) Buildpack() string {
@@ -118,12 +141,24 @@ func (opts PushOptions) ContainerImage() string {
 	return opts.toConfig().ContainerImage
 }
 
+// Context returns the last set value for Context or the empty value
+// if not set.
+func (opts PushOptions) Context() context.Context {
+	return opts.toConfig().Context
+}
+
 // DefaultRouteDomain returns the last set value for DefaultRouteDomain or the empty value
 // if not set.
 func (opts PushOptions) DefaultRouteDomain() string {
 	return opts.toConfig().DefaultRouteDomain
 }
 
+// DockerfileBuildArgs returns the last set value for DockerfileBuildArgs or the empty value
+// if not set.
+func (opts PushOptions) DockerfileBuildArgs() []corev1.EnvVar {
+	return opts.toConfig().DockerfileBuildArgs
+}
+
 // DockerfilePath returns the last set value for DockerfilePath or the empty value
 // if not set.
 func (opts PushOptions) DockerfilePath() string {
@@ -136,6 +171,12 @@ func (opts PushOptions) EnvironmentVariables() map[string]string {
 	return opts.toConfig().EnvironmentVariables
 }
 
+// ForceBuild returns the last set value for ForceBuild or the empty value
+// if not set.
+func (opts PushOptions) ForceBuild() bool {
+	return opts.toConfig().ForceBuild
+}
+
 // Grpc returns the last set value for Grpc or the empty value
 // if not set.
 func (opts PushOptions) Grpc() bool {
@@ -154,12 +195,24 @@ func (opts PushOptions) Namespace() string {
 	return opts.toConfig().Namespace
 }
 
+// NoRoute returns the last set value for NoRoute or the empty value
+// if not set.
+func (opts PushOptions) NoRoute() bool {
+	return opts.toConfig().NoRoute
+}
+
 // Output returns the last set value for Output or the empty value
 // if not set.
 func (opts PushOptions) Output() io.Writer {
 	return opts.toConfig().Output
 }
 
+// Processes returns the last set value for Processes or the empty value
+// if not set.
+func (opts PushOptions) Processes() []v1alpha1.AppSpecProcess {
+	return opts.toConfig().Processes
+}
+
 // RandomRouteDomain returns the last set value for RandomRouteDomain or the empty value
 // if not set.
 func (opts PushOptions) RandomRouteDomain() string {
@@ -172,6 +225,12 @@ func (opts PushOptions) ResourceRequests() corev1.ResourceList {
 	return opts.toConfig().ResourceRequests
 }
 
+// Rollout returns the last set value for Rollout or the empty value
+// if not set.
+func (opts PushOptions) Rollout() *v1alpha1.AppSpecRollout {
+	return opts.toConfig().Rollout
+}
+
 // Routes returns the last set value for Routes or the empty value
 // if not set.
 func (opts PushOptions) Routes() []v1alpha1.RouteSpecFields {
@@ -196,6 +255,12 @@ func (opts PushOptions) Stack() string {
 	return opts.toConfig().Stack
 }
 
+// Timeouts returns the last set value for Timeouts or the empty value
+// if not set.
+func (opts PushOptions) Timeouts() PushTimeouts {
+	return opts.toConfig().Timeouts
+}
+
 // WithPushAppSpecInstances creates an Option that sets Scaling information for the service
 func WithPushAppSpecInstances(val v1alpha1.AppSpecInstances) PushOption {
 	return func(cfg *pushConfig) {
@@ -210,6 +275,13 @@ func WithPushArgs(val []string) PushOption {
 	}
 }
 
+// WithPushBindingFormat creates an Option that sets how service binding credentials are provided to the app, vcap or k8s
+func WithPushBindingFormat(val string) PushOption {
+	return func(cfg *pushConfig) {
+		cfg.BindingFormat = val
+	}
+}
+
 // WithPushBuildpack creates an Option that sets skip the detect buildpack step and use the given name
 func WithPushBuildpack(val string) PushOption {
 	return func(cfg *pushConfig) {
@@ -231,6 +303,13 @@ func WithPushContainerImage(val string) PushOption {
 	}
 }
 
+// WithPushContext creates an Option that sets the context to trace the push in
+func WithPushContext(val context.Context) PushOption {
+	return func(cfg *pushConfig) {
+		cfg.Context = val
+	}
+}
+
 // WithPushDefaultRouteDomain creates an Option that sets Domain for a defaultroute. Only used if a route doesn't already exist
 func WithPushDefaultRouteDomain(val string) PushOption {
 	return func(cfg *pushConfig) {
@@ -238,6 +317,13 @@ func WithPushDefaultRouteDomain(val string) PushOption {
 	}
 }
 
+// WithPushDockerfileBuildArgs creates an Option that sets build args to pass to the Dockerfile build
+func WithPushDockerfileBuildArgs(val []corev1.EnvVar) PushOption {
+	return func(cfg *pushConfig) {
+		cfg.DockerfileBuildArgs = val
+	}
+}
+
 // WithPushDockerfilePath creates an Option that sets the path to a Dockerfile to build
 func WithPushDockerfilePath(val string) PushOption {
 	return func(cfg *pushConfig) {
@@ -252,6 +338,13 @@ func WithPushEnvironmentVariables(val map[string]string) PushOption {
 	}
 }
 
+// WithPushForceBuild creates an Option that sets rebuild the app even if nothing changed since the last push
+func WithPushForceBuild(val bool) PushOption {
+	return func(cfg *pushConfig) {
+		cfg.ForceBuild = val
+	}
+}
+
 // WithPushGrpc creates an Option that sets setup the ports for the container to allow gRPC to work
 func WithPushGrpc(val bool) PushOption {
 	return func(cfg *pushConfig) {
@@ -273,6 +366,13 @@ func WithPushNamespace(val string) PushOption {
 	}
 }
 
+// WithPushNoRoute creates an Option that sets don't map any routes to the app and remove routes from previous pushes
+func WithPushNoRoute(val bool) PushOption {
+	return func(cfg *pushConfig) {
+		cfg.NoRoute = val
+	}
+}
+
 // WithPushOutput creates an Option that sets the io.Writer to write output such as build logs
 func WithPushOutput(val io.Writer) PushOption {
 	return func(cfg *pushConfig) {
@@ -280,6 +380,13 @@ func WithPushOutput(val io.Writer) PushOption {
 	}
 }
 
+// WithPushProcesses creates an Option that sets the process types the app defines, e.g. in a Procfile
+func WithPushProcesses(val []v1alpha1.AppSpecProcess) PushOption {
+	return func(cfg *pushConfig) {
+		cfg.Processes = val
+	}
+}
+
 // WithPushRandomRouteDomain creates an Option that sets Domain for a random route. Only used if a route doesn't already exist
 func WithPushRandomRouteDomain(val string) PushOption {
 	return func(cfg *pushConfig) {
@@ -294,6 +401,13 @@ func WithPushResourceRequests(val corev1.ResourceList) PushOption {
 	}
 }
 
+// WithPushRollout creates an Option that sets the traffic split between the app's stable and latest revisions, nil sends all traffic to the latest
+func WithPushRollout(val *v1alpha1.AppSpecRollout) PushOption {
+	return func(cfg *pushConfig) {
+		cfg.Rollout = val
+	}
+}
+
 // WithPushRoutes creates an Option that sets routes for the app
 func WithPushRoutes(val []v1alpha1.RouteSpecFields) PushOption {
 	return func(cfg *pushConfig) {
@@ -322,11 +436,19 @@ func WithPushStack(val string) PushOption {
 	}
 }
 
+// WithPushTimeouts creates an Option that sets limits for each phase of the push, zero values are unlimited
+func WithPushTimeouts(val PushTimeouts) PushOption {
+	return func(cfg *pushConfig) {
+		cfg.Timeouts = val
+	}
+}
+
 // PushOptionDefaults gets the default values for Push.
 func PushOptionDefaults() PushOptions {
 	return PushOptions{
 		WithPushNamespace("default"),
 		WithPushOutput(os.Stdout),
+		WithPushContext(context.Background()),
 	}
 }
 
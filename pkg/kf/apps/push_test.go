@@ -17,17 +17,24 @@ package apps_test
 import (
 	"errors"
 	"io"
+	"math/rand"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/internal/envutil"
 	"github.com/google/kf/pkg/kf/apps"
 	appsfake "github.com/google/kf/pkg/kf/apps/fake"
+	routeclaimsfake "github.com/google/kf/pkg/kf/routeclaims/fake"
 	"github.com/google/kf/pkg/kf/testutil"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+	"knative.dev/pkg/ptr"
 )
 
 func TestPush_Logs(t *testing.T) {
@@ -40,6 +47,7 @@ func TestPush_Logs(t *testing.T) {
 		wantErr        error
 		logErr         error
 		noStart        bool
+		timeouts       apps.PushTimeouts
 	}{
 		"fetching logs succeeds": {
 			appName:  "some-app",
@@ -50,6 +58,11 @@ func TestPush_Logs(t *testing.T) {
 			srcImage: "some-image",
 			noStart:  true,
 		},
+		"Timeouts get passed through": {
+			appName:  "some-app",
+			srcImage: "some-image",
+			timeouts: apps.PushTimeouts{Build: time.Minute, Route: time.Second},
+		},
 		"fetching logs returns an error, no error": {
 			appName:  "some-app",
 			srcImage: "some-image",
@@ -62,6 +75,8 @@ func TestPush_Logs(t *testing.T) {
 			expectedNamespace := "some-namespace"
 
 			fakeApps := appsfake.NewFakeClient(ctrl)
+			fakeApps.EXPECT().
+				List(gomock.Any(), gomock.Any())
 			fakeApps.EXPECT().
 				Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
 				Return(&v1alpha1.App{
@@ -78,15 +93,17 @@ func TestPush_Logs(t *testing.T) {
 				}, nil)
 
 			fakeApps.EXPECT().
-				DeployLogsForApp(gomock.Not(gomock.Nil()), gomock.Any()).Do(func(_ io.Writer, app *v1alpha1.App) {
-				testutil.AssertEqual(t, "Name", tc.appName, app.Name)
-				testutil.AssertEqual(t, "ResourceVersion", tc.appName+"-version", app.ResourceVersion)
-				testutil.AssertEqual(t, "Namespace", expectedNamespace, app.Namespace)
-				testutil.AssertEqual(t, "resourceVersion", tc.noStart, app.Spec.Instances.Stopped)
+				DeployLogs(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Do(func(_ io.Writer, appName, resourceVersion, namespace string, noStart bool, timeouts apps.PushTimeouts) {
+				testutil.AssertEqual(t, "Name", tc.appName, appName)
+				testutil.AssertEqual(t, "ResourceVersion", tc.appName+"-version", resourceVersion)
+				testutil.AssertEqual(t, "Namespace", expectedNamespace, namespace)
+				testutil.AssertEqual(t, "resourceVersion", tc.noStart, noStart)
+				testutil.AssertEqual(t, "timeouts", tc.timeouts, timeouts)
 			}).Return(tc.logErr)
 
 			p := apps.NewPusher(
 				fakeApps,
+				routeclaimsfake.NewFakeClient(ctrl),
 			)
 
 			gotErr := p.Push(
@@ -95,6 +112,7 @@ func TestPush_Logs(t *testing.T) {
 				apps.WithPushContainerImage(tc.containerImage),
 				apps.WithPushNamespace(expectedNamespace),
 				apps.WithPushAppSpecInstances(v1alpha1.AppSpecInstances{Stopped: tc.noStart}),
+				apps.WithPushTimeouts(tc.timeouts),
 			)
 
 			testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
@@ -115,6 +133,7 @@ func TestPush(t *testing.T) {
 		srcImage  string
 		buildpack string
 		opts      apps.PushOptions
+		claimsErr error
 		setup     func(t *testing.T, appsClient *appsfake.FakeClient)
 		assert    func(t *testing.T, err error)
 	}{
@@ -334,6 +353,22 @@ func TestPush(t *testing.T) {
 					Return(&v1alpha1.App{}, nil)
 			},
 		},
+		"pushes a docker source with build args": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushSourceImage("some-image"),
+				apps.WithPushDockerfilePath("Dockerfile"),
+				apps.WithPushDockerfileBuildArgs([]corev1.EnvVar{{Name: "VERSION", Value: "1.2.3"}}),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newApp *v1alpha1.App, merge apps.Merger) {
+						testutil.AssertEqual(t, "Dockerfile.BuildArgs", []corev1.EnvVar{{Name: "VERSION", Value: "1.2.3"}}, newApp.Spec.Source.Dockerfile.BuildArgs)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+		},
 		"pushes app with routes": {
 			appName: "some-app",
 			opts: apps.PushOptions{
@@ -399,6 +434,216 @@ func TestPush(t *testing.T) {
 				testutil.AssertNil(t, "err", err)
 			},
 		},
+		"credential rotation is kept": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushServiceBindings([]v1alpha1.AppSpecServiceBinding{
+					{Instance: "some-db"},
+				}),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newObj *v1alpha1.App, merge apps.Merger) {
+						rotation := &v1alpha1.AppSpecServiceBindingRotation{}
+						oldApp := &v1alpha1.App{}
+						oldApp.Spec.ServiceBindings = []v1alpha1.AppSpecServiceBinding{
+							{Instance: "some-db", BindingName: "some-db", Rotation: rotation},
+						}
+
+						app := merge(newObj.DeepCopy(), oldApp)
+
+						testutil.AssertEqual(t, "len(ServiceBindings)", 1, len(app.Spec.ServiceBindings))
+						testutil.AssertEqual(t, "Rotation", rotation, app.Spec.ServiceBindings[0].Rotation)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"process instances are kept": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushProcesses([]v1alpha1.AppSpecProcess{
+					{Type: "web", Command: "bundle exec puma"},
+					{Type: "worker", Command: "bundle exec sidekiq"},
+				}),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newObj *v1alpha1.App, merge apps.Merger) {
+						two := 2
+						oldApp := &v1alpha1.App{}
+						oldApp.Spec.Processes = []v1alpha1.AppSpecProcess{
+							{Type: "worker", Command: "bundle exec sidekiq -c 5", Instances: &two},
+							{Type: "clock", Command: "bundle exec clockwork"},
+						}
+
+						app := merge(newObj.DeepCopy(), oldApp)
+
+						testutil.AssertEqual(t, "processes", []v1alpha1.AppSpecProcess{
+							{Type: "web", Command: "bundle exec puma"},
+							{Type: "worker", Command: "bundle exec sidekiq", Instances: &two},
+						}, app.Spec.Processes)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"schedule is kept": {
+			appName: "some-app",
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newObj *v1alpha1.App, merge apps.Merger) {
+						schedule := &v1alpha1.AppSpecInstancesSchedule{Start: "0 9 * * 1-5", Stop: "0 17 * * 1-5"}
+						oldApp := &v1alpha1.App{}
+						oldApp.Spec.Instances.Schedule = schedule
+
+						app := merge(newObj.DeepCopy(), oldApp)
+
+						testutil.AssertEqual(t, "schedule", schedule, app.Spec.Instances.Schedule)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"cold start timeout is kept": {
+			appName: "some-app",
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newObj *v1alpha1.App, merge apps.Merger) {
+						oldApp := &v1alpha1.App{}
+						oldApp.Spec.Instances.ColdStartTimeoutSeconds = ptr.Int64(120)
+
+						app := merge(newObj.DeepCopy(), oldApp)
+
+						testutil.AssertEqual(t, "cold start timeout", ptr.Int64(120), app.Spec.Instances.ColdStartTimeoutSeconds)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"restart on change is kept": {
+			appName: "some-app",
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newObj *v1alpha1.App, merge apps.Merger) {
+						restartOnChange := []v1alpha1.AppSpecRestartOnChange{{Kind: v1alpha1.RestartOnChangeKindSecret, Name: "db-credentials"}}
+						oldApp := &v1alpha1.App{}
+						oldApp.Spec.RestartOnChange = restartOnChange
+
+						app := merge(newObj.DeepCopy(), oldApp)
+
+						testutil.AssertEqual(t, "restart on change", restartOnChange, app.Spec.RestartOnChange)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"egress IP pool is kept": {
+			appName: "some-app",
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newObj *v1alpha1.App, merge apps.Merger) {
+						oldApp := &v1alpha1.App{}
+						oldApp.Spec.EgressIPPool = "partner-allowlist"
+
+						app := merge(newObj.DeepCopy(), oldApp)
+
+						testutil.AssertEqual(t, "egress IP pool", "partner-allowlist", app.Spec.EgressIPPool)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"finalizers, labels and annotations are kept but not the build priority": {
+			appName: "some-app",
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newObj *v1alpha1.App, merge apps.Merger) {
+						oldApp := &v1alpha1.App{}
+						oldApp.Finalizers = []string{"apps.kf.dev"}
+						oldApp.Labels = map[string]string{"team": "payments", "tier": "old"}
+						oldApp.Annotations = map[string]string{
+							v1alpha1.DeletionProtectionAnnotation: "true",
+							v1alpha1.BuildPriorityAnnotation:      v1alpha1.BuildPriorityScheduled,
+						}
+
+						newApp := newObj.DeepCopy()
+						newApp.Labels = map[string]string{"tier": "new"}
+
+						app := merge(newApp, oldApp)
+
+						testutil.AssertEqual(t, "finalizers", []string{"apps.kf.dev"}, app.Finalizers)
+						testutil.AssertEqual(t, "labels", map[string]string{"team": "payments", "tier": "new"}, app.Labels)
+						testutil.AssertEqual(t, "annotations", map[string]string{
+							v1alpha1.DeletionProtectionAnnotation: "true",
+						}, app.Annotations)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"deletion protection is kept": {
+			appName: "some-app",
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newObj *v1alpha1.App, merge apps.Merger) {
+						oldApp := &v1alpha1.App{}
+						oldApp.Annotations = map[string]string{v1alpha1.DeletionProtectionAnnotation: "true"}
+
+						app := merge(newObj.DeepCopy(), oldApp)
+
+						testutil.AssertEqual(t, "deletion protected", true, v1alpha1.IsDeletionProtected(app))
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"sets the rollout": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushRollout(&v1alpha1.AppSpecRollout{StableRevisionName: "some-app-abc12"}),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newObj *v1alpha1.App, merge apps.Merger) {
+						oldApp := &v1alpha1.App{}
+						oldApp.Spec.Rollout = &v1alpha1.AppSpecRollout{StableRevisionName: "some-app-xyz89", LatestPercent: 60}
+
+						app := merge(newObj.DeepCopy(), oldApp)
+
+						testutil.AssertEqual(t, "rollout", &v1alpha1.AppSpecRollout{StableRevisionName: "some-app-abc12"}, app.Spec.Rollout)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
 		"pushes app with random route": {
 			appName: "some-app",
 			opts: apps.PushOptions{
@@ -446,6 +691,43 @@ func TestPush(t *testing.T) {
 				testutil.AssertNil(t, "err", err)
 			},
 		},
+		"random route fails if RouteClaims can't be listed": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushRandomRouteDomain("example.com"),
+			},
+			claimsErr: errors.New("some-error"),
+			assert: func(t *testing.T, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("failed to list RouteClaims: some-error"), err)
+			},
+		},
+		"no route removes routes from previous pushes": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushNoRoute(true),
+				apps.WithPushDefaultRouteDomain("example.com"),
+				apps.WithPushRoutes([]v1alpha1.RouteSpecFields{{Hostname: "ignored", Domain: "example.com"}}),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newApp *v1alpha1.App, merge apps.Merger) {
+						testutil.AssertEqual(t, "new Routes", 0, len(newApp.Spec.Routes))
+
+						oldApp := &v1alpha1.App{}
+						oldApp.Spec.Routes = []v1alpha1.RouteSpecFields{
+							{Hostname: "some-app", Domain: "example.com"},
+						}
+						merged := merge(newApp.DeepCopy(), oldApp)
+						testutil.AssertEqual(t, "merged Routes", 0, len(merged.Spec.Routes))
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
 		"pushes with resource requests": {
 			appName: "some-app",
 			opts: apps.PushOptions{
@@ -524,6 +806,113 @@ func TestPush(t *testing.T) {
 				testutil.AssertNil(t, "err", err)
 			},
 		},
+		"unchanged app isn't updated": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushSourceImage("some-image"),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				deployed := deployedApp(t, "some-app", apps.PushOptions{apps.WithPushSourceImage("some-image")}, corev1.ConditionTrue)
+				deployed.Spec.Source.UpdateRequests = 3
+
+				appsClient.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{*deployed}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"unchanged app isn't updated to start a rollout": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushSourceImage("some-image"),
+				apps.WithPushRollout(&v1alpha1.AppSpecRollout{StableRevisionName: "some-app-00001"}),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				deployed := deployedApp(t, "some-app", apps.PushOptions{apps.WithPushSourceImage("some-image")}, corev1.ConditionTrue)
+
+				appsClient.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{*deployed}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"unchanged app that isn't ready returns an error": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushSourceImage("some-image"),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				deployed := deployedApp(t, "some-app", apps.PushOptions{apps.WithPushSourceImage("some-image")}, corev1.ConditionFalse)
+
+				appsClient.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{*deployed}, nil)
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(`"some-app" isn't ready: some-message`), err)
+			},
+		},
+		"unchanged app that is reconciling is waited on": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushSourceImage("some-image"),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				deployed := deployedApp(t, "some-app", apps.PushOptions{apps.WithPushSourceImage("some-image")}, corev1.ConditionUnknown)
+				deployed.ResourceVersion = "some-version"
+
+				appsClient.EXPECT().
+					List(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{*deployed}, nil)
+				appsClient.EXPECT().
+					DeployLogs(gomock.Any(), "some-app", "some-version", gomock.Any(), false, gomock.Any()).
+					Return(errors.New("some-error"))
+			},
+			assert: func(t *testing.T, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("some-error"), err)
+			},
+		},
+		"changed app is updated": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushSourceImage("some-new-image"),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newApp *v1alpha1.App, merge apps.Merger) {
+						deployed := merge(newApp.DeepCopy(), &v1alpha1.App{})
+						deployed.Spec.Source.BuildpackBuild.Source = "some-image"
+
+						result := merge(newApp, deployed)
+						testutil.AssertEqual(t, "source", "some-new-image", result.Spec.Source.BuildpackBuild.Source)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+		},
+		"ForceBuild increments UpdateRequests": {
+			appName: "some-app",
+			opts: apps.PushOptions{
+				apps.WithPushSourceImage("some-image"),
+				apps.WithPushForceBuild(true),
+			},
+			setup: func(t *testing.T, appsClient *appsfake.FakeClient) {
+				appsClient.EXPECT().
+					Upsert(gomock.Not(gomock.Nil()), gomock.Any(), gomock.Any()).
+					Do(func(namespace string, newApp *v1alpha1.App, merge apps.Merger) {
+						deployed := merge(newApp.DeepCopy(), &v1alpha1.App{})
+						deployed.Spec.Source.UpdateRequests = 3
+
+						result := merge(newApp, deployed)
+						testutil.AssertEqual(t, "UpdateRequests", 4, result.Spec.Source.UpdateRequests)
+					}).
+					Return(&v1alpha1.App{}, nil)
+			},
+		},
 		"NoStart sets stopped": {
 			appName:   "some-app",
 			srcImage:  "some-image",
@@ -556,13 +945,25 @@ func TestPush(t *testing.T) {
 
 			ctrl := gomock.NewController(t)
 			fakeApps := appsfake.NewFakeClient(ctrl)
+
+			tc.setup(t, fakeApps)
+
+			// Defaults are added after the setup so they don't shadow its
+			// expectations.
+			fakeApps.EXPECT().
+				List(gomock.Any(), gomock.Any()).
+				AnyTimes()
 			fakeApps.EXPECT().
-				DeployLogsForApp(gomock.Any(), gomock.Any()).
+				DeployLogs(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
 				AnyTimes()
 
-			tc.setup(t, fakeApps)
+			fakeClaims := routeclaimsfake.NewFakeClient(ctrl)
+			fakeClaims.EXPECT().
+				List(gomock.Any()).
+				Return(nil, tc.claimsErr).
+				AnyTimes()
 
-			p := apps.NewPusher(fakeApps)
+			p := apps.NewPusher(fakeApps, fakeClaims)
 			gotErr := p.Push(tc.appName, tc.opts...)
 			tc.assert(t, gotErr)
 			if gotErr != nil {
@@ -574,6 +975,130 @@ func TestPush(t *testing.T) {
 	}
 }
 
+func TestPusher_Preview(t *testing.T) {
+	existing := v1alpha1.App{}
+	existing.Name = "some-app"
+	existing.Spec.Routes = []v1alpha1.RouteSpecFields{{Hostname: "existing", Domain: "example.com"}}
+	existing.Spec.Instances.Exactly = intPtr(3)
+	existing.Spec.Template.Spec.Containers = []corev1.Container{{
+		Env: []corev1.EnvVar{{Name: "OLD", Value: "value"}},
+	}}
+
+	opts := apps.PushOptions{
+		apps.WithPushNamespace("some-namespace"),
+		apps.WithPushSourceImage("some-image"),
+		apps.WithPushDefaultRouteDomain("example.com"),
+		apps.WithPushEnvironmentVariables(map[string]string{"NEW": "value"}),
+	}
+
+	for tn, tc := range map[string]struct {
+		existing []v1alpha1.App
+		listErr  error
+		assert   func(t *testing.T, app *v1alpha1.App, err error)
+	}{
+		"new app": {
+			assert: func(t *testing.T, app *v1alpha1.App, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "namespace", "some-namespace", app.Namespace)
+				testutil.AssertEqual(t, "routes", []v1alpha1.RouteSpecFields{{Hostname: "some-app", Domain: "example.com"}}, app.Spec.Routes)
+				testutil.AssertEqual(t, "instances", intPtr(1), app.Spec.Instances.Exactly)
+				testutil.AssertEqual(t, "source", "some-image", app.Spec.Source.BuildpackBuild.Source)
+			},
+		},
+		"existing app is merged": {
+			existing: []v1alpha1.App{existing},
+			assert: func(t *testing.T, app *v1alpha1.App, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "routes", existing.Spec.Routes, app.Spec.Routes)
+				testutil.AssertEqual(t, "instances", intPtr(3), app.Spec.Instances.Exactly)
+				testutil.AssertEqual(t, "env", map[string]string{"NEW": "value", "OLD": "value"}, envutil.EnvVarsToMap(envutil.GetAppEnvVars(app)))
+			},
+		},
+		"list error": {
+			listErr: errors.New("some-error"),
+			assert: func(t *testing.T, app *v1alpha1.App, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("failed to get the existing app: some-error"), err)
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeApps := appsfake.NewFakeClient(ctrl)
+			fakeApps.EXPECT().
+				List("some-namespace", gomock.Any()).
+				Return(tc.existing, tc.listErr)
+
+			// Nothing may be changed on the cluster.
+			p := apps.NewPusher(fakeApps, routeclaimsfake.NewFakeClient(ctrl))
+			app, err := p.Preview("some-app", opts...)
+			tc.assert(t, app, err)
+		})
+	}
+}
+
+// deployedApp returns the App a push with opts would create, as it would be
+// after reconciling with its Ready condition set to ready.
+func deployedApp(t *testing.T, appName string, opts apps.PushOptions, ready corev1.ConditionStatus) *v1alpha1.App {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	fakeApps := appsfake.NewFakeClient(ctrl)
+	fakeApps.EXPECT().
+		List(gomock.Any(), gomock.Any())
+
+	fakeClaims := routeclaimsfake.NewFakeClient(ctrl)
+	fakeClaims.EXPECT().
+		List(gomock.Any()).
+		AnyTimes()
+
+	app, err := apps.NewPusher(fakeApps, fakeClaims).Preview(appName, opts...)
+	testutil.AssertNil(t, "err", err)
+
+	app.Generation = 1
+	app.Status.ObservedGeneration = 1
+	app.Status.Conditions = duckv1beta1.Conditions{{
+		Type:    v1alpha1.AppConditionReady,
+		Status:  ready,
+		Message: "some-message",
+	}}
+
+	return app
+}
+
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestRandomHostname(t *testing.T) {
+	t.Parallel()
+
+	for tn, tc := range map[string]struct {
+		appName    string
+		wantPrefix string
+	}{
+		"short name": {
+			appName:    "some-app",
+			wantPrefix: "some-app-",
+		},
+		"long name is truncated": {
+			appName:    strings.Repeat("a", 60),
+			wantPrefix: strings.Repeat("a", 54) + "-",
+		},
+		"truncation doesn't leave a trailing dash": {
+			appName:    strings.Repeat("a", 53) + "-bbbb",
+			wantPrefix: strings.Repeat("a", 53) + "-",
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			hostname := apps.RandomHostname(tc.appName, rand.New(rand.NewSource(1)))
+
+			testutil.AssertEqual(t, "prefix", true, strings.HasPrefix(hostname, tc.wantPrefix))
+			testutil.AssertEqual(t, "length", len(tc.wantPrefix)+8, len(hostname))
+			testutil.AssertEqual(t, "DNS label errors", []string(nil), validation.IsDNS1123Label(hostname))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/kf/machine"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+)
+
+// Phases a push can time out in. Each one has its own limit so a slow build
+// doesn't eat into the time an App has to become healthy.
+const (
+	TimeoutPhaseUpload = "upload"
+	TimeoutPhaseBuild  = "build"
+	TimeoutPhaseDeploy = "deploy"
+	TimeoutPhaseRoute  = "route"
+)
+
+// PushTimeouts holds the maximum time each phase of a push may take. A zero
+// value means the phase has no limit.
+type PushTimeouts struct {
+	// Upload limits packaging and uploading the source.
+	Upload time.Duration
+
+	// Build limits the time from the App being updated until its Source
+	// succeeds.
+	Build time.Duration
+
+	// Deploy limits the time from the build finishing until the Knative
+	// Service is ready.
+	Deploy time.Duration
+
+	// Route limits the time from the Knative Service being ready until the
+	// App's routes are reconciled and the App is ready.
+	Route time.Duration
+}
+
+// PhaseTimeoutError is returned when a phase of a push exceeds its limit.
+type PhaseTimeoutError struct {
+	// Phase is the phase that stalled.
+	Phase string
+
+	// Timeout is the limit the phase exceeded.
+	Timeout time.Duration
+
+	// Conditions are the most recent App conditions seen before the timeout,
+	// if any.
+	Conditions duckv1beta1.Conditions
+}
+
+var _ error = (*PhaseTimeoutError)(nil)
+
+// Error implements error.
+func (e *PhaseTimeoutError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s phase timed out after %s, raise the limit with --%s-timeout", e.Phase, e.Timeout, e.Phase)
+
+	if len(e.Conditions) > 0 {
+		b.WriteString("\nlatest App conditions:")
+		for _, c := range e.Conditions {
+			fmt.Fprintf(&b, "\n  %s: %s", c.Type, c.Status)
+			if c.Reason != "" {
+				fmt.Fprintf(&b, " (%s)", c.Reason)
+			}
+			if c.Message != "" {
+				fmt.Fprintf(&b, " %s", c.Message)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// NewPhaseTimeoutError creates a PhaseTimeoutError annotated with the
+// timeout exit code.
+func NewPhaseTimeoutError(phase string, timeout time.Duration, conditions duckv1beta1.Conditions) error {
+	return machine.WithExitCode(machine.ExitTimeout, &PhaseTimeoutError{
+		Phase:      phase,
+		Timeout:    timeout,
+		Conditions: conditions,
+	})
+}
+
+// RunWithTimeout runs f and returns its error. If timeout is non-zero and f
+// doesn't return within it a PhaseTimeoutError for phase is returned instead
+// and f is left to finish in the background.
+func RunWithTimeout(phase string, timeout time.Duration, f func() error) error {
+	if timeout <= 0 {
+		return f()
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- f()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		return err
+	case <-timer.C:
+		return NewPhaseTimeoutError(phase, timeout, nil)
+	}
+}
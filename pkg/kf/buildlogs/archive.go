@@ -0,0 +1,57 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildlogs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned by Archive.Open when there are no logs at the URL.
+var ErrNotFound = errors.New("build logs not found in the archive")
+
+// Archive stores build logs.
+type Archive interface {
+	// Upload writes the logs to the object at the URL, replacing any that
+	// exist.
+	Upload(ctx context.Context, url string, logs io.Reader) error
+
+	// Open reads the logs stored at the URL.
+	Open(ctx context.Context, url string) (io.ReadCloser, error)
+}
+
+// ObjectURL gets the URL the logs of a Source's build are archived to in the
+// bucket.
+func ObjectURL(bucket, namespace, sourceName string) string {
+	return fmt.Sprintf("%s/%s/%s.log", strings.TrimSuffix(bucket, "/"), namespace, sourceName)
+}
+
+// ParseObjectURL splits a gs://BUCKET/OBJECT URL into the bucket and object
+// names.
+func ParseObjectURL(url string) (bucket, object string, err error) {
+	if !strings.HasPrefix(url, "gs://") {
+		return "", "", fmt.Errorf("%q isn't a Cloud Storage URL", url)
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(url, "gs://"), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("%q must be in the form gs://BUCKET/OBJECT", url)
+	}
+
+	return parts[0], parts[1], nil
+}
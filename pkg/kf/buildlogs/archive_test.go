@@ -0,0 +1,66 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildlogs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func ExampleObjectURL() {
+	fmt.Println(ObjectURL("gs://build-logs", "my-space", "my-app-source"))
+
+	// Output: gs://build-logs/my-space/my-app-source.log
+}
+
+func TestParseObjectURL(t *testing.T) {
+	cases := map[string]struct {
+		url        string
+		wantBucket string
+		wantObject string
+		wantErr    error
+	}{
+		"valid": {
+			url:        "gs://build-logs/my-space/my-app-source.log",
+			wantBucket: "build-logs",
+			wantObject: "my-space/my-app-source.log",
+		},
+		"not cloud storage": {
+			url:     "https://example.com/build.log",
+			wantErr: errors.New(`"https://example.com/build.log" isn't a Cloud Storage URL`),
+		},
+		"bucket only": {
+			url:     "gs://build-logs",
+			wantErr: errors.New(`"gs://build-logs" must be in the form gs://BUCKET/OBJECT`),
+		},
+		"empty object": {
+			url:     "gs://build-logs/",
+			wantErr: errors.New(`"gs://build-logs/" must be in the form gs://BUCKET/OBJECT`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			bucket, object, err := ParseObjectURL(tc.url)
+
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "bucket", tc.wantBucket, bucket)
+			testutil.AssertEqual(t, "object", tc.wantObject, object)
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildlogs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// storageEndpoint is the Cloud Storage JSON API.
+	storageEndpoint = "https://storage.googleapis.com"
+
+	// storageScope grants reading and writing objects.
+	storageScope = "https://www.googleapis.com/auth/devstorage.read_write"
+)
+
+type gcsArchive struct {
+	endpoint string
+
+	clientOnce sync.Once
+	newClient  func(ctx context.Context) (*http.Client, error)
+	client     *http.Client
+	clientErr  error
+}
+
+var _ Archive = (*gcsArchive)(nil)
+
+// NewGCSArchive creates an Archive backed by Cloud Storage. It authenticates
+// using the application default credentials the first time it's used.
+func NewGCSArchive() Archive {
+	return &gcsArchive{
+		endpoint: storageEndpoint,
+		newClient: func(ctx context.Context) (*http.Client, error) {
+			return google.DefaultClient(ctx, storageScope)
+		},
+	}
+}
+
+func (a *gcsArchive) httpClient(ctx context.Context) (*http.Client, error) {
+	a.clientOnce.Do(func() {
+		a.client, a.clientErr = a.newClient(ctx)
+	})
+
+	if a.clientErr != nil {
+		return nil, fmt.Errorf("couldn't authenticate to Cloud Storage: %v", a.clientErr)
+	}
+
+	return a.client, nil
+}
+
+// Upload implements Archive.
+func (a *gcsArchive) Upload(ctx context.Context, objectURL string, logs io.Reader) error {
+	bucket, object, err := ParseObjectURL(objectURL)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.httpClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	query.Set("uploadType", "media")
+	query.Set("name", object)
+	target := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", a.endpoint, url.PathEscape(bucket), query.Encode())
+
+	req, err := http.NewRequest(http.MethodPost, target, logs)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("couldn't upload build logs to %s: %v", objectURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("couldn't upload build logs to %s: %s", objectURL, responseError(resp))
+	}
+
+	return nil
+}
+
+// Open implements Archive.
+func (a *gcsArchive) Open(ctx context.Context, objectURL string) (io.ReadCloser, error) {
+	bucket, object, err := ParseObjectURL(objectURL)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := a.httpClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	target := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", a.endpoint, url.PathEscape(bucket), url.PathEscape(object))
+
+	req, err := http.NewRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read build logs from %s: %v", objectURL, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("couldn't read build logs from %s: %s", objectURL, responseError(resp))
+	}
+}
+
+// responseError summarizes a failed Cloud Storage response.
+func responseError(resp *http.Response) string {
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	if len(body) == 0 {
+		return resp.Status
+	}
+
+	return fmt.Sprintf("%s: %s", resp.Status, body)
+}
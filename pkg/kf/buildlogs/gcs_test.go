@@ -0,0 +1,115 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildlogs
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func newTestArchive(t *testing.T, handler http.HandlerFunc) *gcsArchive {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return &gcsArchive{
+		endpoint: server.URL,
+		newClient: func(context.Context) (*http.Client, error) {
+			return server.Client(), nil
+		},
+	}
+}
+
+func TestGCSArchive_Upload(t *testing.T) {
+	var gotPath, gotName, gotBody string
+	archive := newTestArchive(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		gotPath = r.URL.Path
+		gotName = r.URL.Query().Get("name")
+		gotBody = string(body)
+	})
+
+	err := archive.Upload(context.Background(), "gs://build-logs/my-space/src.log", strings.NewReader("Step 1/2"))
+
+	testutil.AssertNil(t, "error", err)
+	testutil.AssertEqual(t, "path", "/upload/storage/v1/b/build-logs/o", gotPath)
+	testutil.AssertEqual(t, "object name", "my-space/src.log", gotName)
+	testutil.AssertEqual(t, "body", "Step 1/2", gotBody)
+}
+
+func TestGCSArchive_Upload_error(t *testing.T) {
+	archive := newTestArchive(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no access", http.StatusForbidden)
+	})
+
+	err := archive.Upload(context.Background(), "gs://build-logs/my-space/src.log", strings.NewReader(""))
+
+	testutil.AssertErrorContainsAll(t, err, []string{"gs://build-logs/my-space/src.log", "403 Forbidden", "no access"})
+}
+
+func TestGCSArchive_Open(t *testing.T) {
+	cases := map[string]struct {
+		handler  http.HandlerFunc
+		wantLogs string
+		wantErr  string
+	}{
+		"found": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.EscapedPath() != "/storage/v1/b/build-logs/o/my-space%2Fsrc.log" {
+					http.Error(w, r.URL.EscapedPath(), http.StatusBadRequest)
+					return
+				}
+				w.Write([]byte("Step 1/2"))
+			},
+			wantLogs: "Step 1/2",
+		},
+		"not found": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.NotFound(w, r)
+			},
+			wantErr: ErrNotFound.Error(),
+		},
+		"server error": {
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			},
+			wantErr: "couldn't read build logs from gs://build-logs/my-space/src.log: 503 Service Unavailable: unavailable",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			archive := newTestArchive(t, tc.handler)
+
+			rc, err := archive.Open(context.Background(), "gs://build-logs/my-space/src.log")
+			if tc.wantErr != "" {
+				testutil.AssertErrorContainsAll(t, err, []string{tc.wantErr})
+				return
+			}
+
+			testutil.AssertNil(t, "error", err)
+			defer rc.Close()
+			logs, _ := ioutil.ReadAll(rc)
+			testutil.AssertEqual(t, "logs", tc.wantLogs, string(logs))
+		})
+	}
+}
@@ -0,0 +1,453 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildpacks
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+// BuilderComposer creates builders with extra buildpacks.
+type BuilderComposer interface {
+	// Compose adds the buildpacks to the base builder and pushes the result
+	// to image. Buildpacks are tried in the order given, before the groups
+	// of the base builder. Each buildpack is either an absolute path to a
+	// directory or an image with buildpack.toml in its root directory.
+	Compose(base, image string, buildpacks []string) error
+}
+
+type builderComposer struct {
+	imageFetcher RemoteImageFetcher
+	imageWriter  RemoteImageWriter
+	keychain     authn.Keychain
+}
+
+// NewBuilderComposer creates a new BuilderComposer that uses the local Docker
+// credentials to read and write images.
+func NewBuilderComposer(
+	imageFetcher RemoteImageFetcher,
+	imageWriter RemoteImageWriter,
+) BuilderComposer {
+	return &builderComposer{
+		imageFetcher: imageFetcher,
+		imageWriter:  imageWriter,
+		keychain:     authn.DefaultKeychain,
+	}
+}
+
+const (
+	// builderBuildpacksDir holds the buildpacks of a builder.
+	builderBuildpacksDir = "buildpacks"
+
+	// builderOrderFile lists the buildpack groups the lifecycle tries.
+	builderOrderFile = "buildpacks/order.toml"
+)
+
+// builderGroup is a group of buildpacks that are detected together.
+type builderGroup struct {
+	Buildpacks []builderGroupBuildpack `json:"buildpacks"`
+}
+
+type builderGroupBuildpack struct {
+	ID       string `json:"id"`
+	Version  string `json:"version,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// buildpackFile is a file or directory of a buildpack relative to its root.
+type buildpackFile struct {
+	name     string
+	mode     int64
+	isDir    bool
+	contents []byte
+}
+
+type composedBuildpack struct {
+	id      string
+	version string
+	files   []buildpackFile
+}
+
+// Compose implements BuilderComposer.
+func (c *builderComposer) Compose(base, image string, buildpacks []string) error {
+	baseRef, err := name.ParseReference(base, name.WeakValidation)
+	if err != nil {
+		return err
+	}
+
+	imageRef, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return err
+	}
+
+	baseImage, err := c.imageFetcher(baseRef, remote.WithAuthFromKeychain(c.keychain))
+	if err != nil {
+		return err
+	}
+
+	baseCfg, err := baseImage.ConfigFile()
+	if err != nil {
+		return err
+	}
+
+	rawMetadata, ok := baseCfg.Config.Labels[metadataLabel]
+	if !ok {
+		return fmt.Errorf("image %s isn't a buildpack builder", base)
+	}
+
+	metadata := make(map[string]json.RawMessage)
+	if err := json.Unmarshal([]byte(rawMetadata), &metadata); err != nil {
+		return fmt.Errorf("couldn't read the builder metadata of %s: %s", base, err)
+	}
+
+	var baseGroups []builderGroup
+	if err := json.Unmarshal(metadata["groups"], &baseGroups); err != nil || len(baseGroups) == 0 {
+		return fmt.Errorf("builder %s doesn't list its buildpack groups", base)
+	}
+
+	var baseBuildpacks []Buildpack
+	if raw, ok := metadata["buildpacks"]; ok {
+		if err := json.Unmarshal(raw, &baseBuildpacks); err != nil {
+			return fmt.Errorf("couldn't read the buildpacks of %s: %s", base, err)
+		}
+	}
+
+	var composed []composedBuildpack
+	for _, source := range buildpacks {
+		bp, err := c.readBuildpack(source)
+		if err != nil {
+			return fmt.Errorf("buildpack %s: %s", source, err)
+		}
+
+		composed = append(composed, *bp)
+	}
+
+	var (
+		groups          []builderGroup
+		buildpackLabels []Buildpack
+	)
+	for _, bp := range composed {
+		groups = append(groups, builderGroup{
+			Buildpacks: []builderGroupBuildpack{{ID: bp.id, Version: bp.version}},
+		})
+		buildpackLabels = append(buildpackLabels, Buildpack{ID: bp.id, Version: bp.version, Latest: true})
+	}
+	groups = append(groups, baseGroups...)
+	buildpackLabels = append(buildpackLabels, baseBuildpacks...)
+
+	layer, err := buildpacksLayer(composed, groups)
+	if err != nil {
+		return err
+	}
+
+	composedImage, err := mutate.AppendLayers(baseImage, layer)
+	if err != nil {
+		return err
+	}
+
+	if metadata["groups"], err = json.Marshal(groups); err != nil {
+		return err
+	}
+
+	if metadata["buildpacks"], err = json.Marshal(buildpackLabels); err != nil {
+		return err
+	}
+
+	updatedMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	cfg := baseCfg.Config.DeepCopy()
+	cfg.Labels[metadataLabel] = string(updatedMetadata)
+
+	composedImage, err = mutate.Config(composedImage, *cfg)
+	if err != nil {
+		return err
+	}
+
+	auth, err := c.keychain.Resolve(imageRef.Context().Registry)
+	if err != nil {
+		return err
+	}
+
+	return c.imageWriter(imageRef, composedImage, auth, http.DefaultTransport)
+}
+
+// readBuildpack reads the files of a buildpack from a directory or image.
+func (c *builderComposer) readBuildpack(source string) (*composedBuildpack, error) {
+	var (
+		files []buildpackFile
+		err   error
+	)
+	if filepath.IsAbs(source) {
+		files, err = readBuildpackDir(source)
+	} else {
+		files, err = c.readBuildpackImage(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range files {
+		if f.name != "buildpack.toml" {
+			continue
+		}
+
+		id, version, err := parseBuildpackTOML(f.contents)
+		if err != nil {
+			return nil, err
+		}
+
+		return &composedBuildpack{id: id, version: version, files: files}, nil
+	}
+
+	return nil, fmt.Errorf("no buildpack.toml found")
+}
+
+func readBuildpackDir(dir string) ([]buildpackFile, error) {
+	var files []buildpackFile
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil || rel == "." {
+			return err
+		}
+
+		// Git metadata isn't part of the buildpack.
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+
+		f := buildpackFile{
+			name:  filepath.ToSlash(rel),
+			mode:  int64(info.Mode().Perm()),
+			isDir: info.IsDir(),
+		}
+
+		switch {
+		case info.IsDir():
+		case info.Mode().IsRegular():
+			if f.contents, err = ioutil.ReadFile(p); err != nil {
+				return err
+			}
+		default:
+			// Links and devices can point outside of the buildpack.
+			return nil
+		}
+
+		files = append(files, f)
+		return nil
+	})
+
+	return files, err
+}
+
+func (c *builderComposer) readBuildpackImage(image string) ([]buildpackFile, error) {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := c.imageFetcher(ref, remote.WithAuthFromKeychain(c.keychain))
+	if err != nil {
+		return nil, err
+	}
+
+	fs := mutate.Extract(img)
+	defer fs.Close()
+
+	var files []buildpackFile
+	tr := tar.NewReader(fs)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		rel := path.Clean(strings.TrimPrefix(hdr.Name, "/"))
+		if rel == "." {
+			continue
+		}
+
+		f := buildpackFile{name: rel, mode: hdr.Mode & 0777}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			f.isDir = true
+		case tar.TypeReg, tar.TypeRegA:
+			if f.contents, err = ioutil.ReadAll(tr); err != nil {
+				return nil, err
+			}
+		default:
+			continue
+		}
+
+		files = append(files, f)
+	}
+}
+
+// parseBuildpackTOML reads the ID and version from the [buildpack] table of a
+// buildpack.toml file.
+func parseBuildpackTOML(contents []byte) (id, version string, err error) {
+	table := ""
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			table = strings.Trim(line, "[] ")
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if table != "buildpack" || len(kv) != 2 {
+			continue
+		}
+
+		unquoted, err := strconv.Unquote(strings.TrimSpace(kv[1]))
+		if err != nil {
+			continue
+		}
+
+		switch strings.TrimSpace(kv[0]) {
+		case "id":
+			id = unquoted
+		case "version":
+			version = unquoted
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", "", err
+	}
+
+	if id == "" || version == "" {
+		return "", "", fmt.Errorf("buildpack.toml must set the buildpack id and version")
+	}
+
+	return id, version, nil
+}
+
+// buildpacksLayer creates a layer with the buildpacks laid out the way the
+// lifecycle expects and an order.toml listing the groups.
+func buildpacksLayer(buildpacks []composedBuildpack, groups []builderGroup) (gcrv1.Layer, error) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+
+	writeDir := func(name string) error {
+		return tw.WriteHeader(&tar.Header{Name: name + "/", Typeflag: tar.TypeDir, Mode: 0755})
+	}
+
+	writeFile := func(name string, mode int64, contents []byte) error {
+		hdr := &tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: mode, Size: int64(len(contents))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		_, err := tw.Write(contents)
+		return err
+	}
+
+	for _, bp := range buildpacks {
+		// IDs may contain slashes which can't be used in directory names.
+		bpDir := path.Join(builderBuildpacksDir, strings.Replace(bp.id, "/", "_", -1))
+		versionDir := path.Join(bpDir, bp.version)
+		for _, dir := range []string{bpDir, versionDir} {
+			if err := writeDir(dir); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, f := range bp.files {
+			var err error
+			if f.isDir {
+				err = writeDir(path.Join(versionDir, f.name))
+			} else {
+				err = writeFile(path.Join(versionDir, f.name), f.mode|0444, f.contents)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		latest := &tar.Header{
+			Name:     path.Join(bpDir, "latest"),
+			Typeflag: tar.TypeSymlink,
+			Linkname: bp.version,
+			Mode:     0777,
+		}
+		if err := tw.WriteHeader(latest); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeFile(builderOrderFile, 0644, orderTOML(groups)); err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	layer := buf.Bytes()
+	return tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(layer)), nil
+	})
+}
+
+// orderTOML writes the groups in the format of the builder's order.toml.
+func orderTOML(groups []builderGroup) []byte {
+	buf := &bytes.Buffer{}
+	for _, group := range groups {
+		fmt.Fprintln(buf, "[[groups]]")
+		for _, bp := range group.Buildpacks {
+			fmt.Fprintln(buf)
+			fmt.Fprintln(buf, "  [[groups.buildpacks]]")
+			fmt.Fprintf(buf, "    id = %s\n", strconv.Quote(bp.ID))
+			if bp.Version != "" {
+				fmt.Fprintf(buf, "    version = %s\n", strconv.Quote(bp.Version))
+			}
+			if bp.Optional {
+				fmt.Fprintln(buf, "    optional = true")
+			}
+		}
+		fmt.Fprintln(buf)
+	}
+
+	return buf.Bytes()
+}
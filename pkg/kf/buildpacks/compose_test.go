@@ -0,0 +1,248 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildpacks_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/google/kf/pkg/kf/buildpacks"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+const baseBuilderMetadata = `{
+	"description": "base builder",
+	"buildpacks": [{"id": "org.example.go", "version": "0.1.0", "latest": true}],
+	"groups": [{"buildpacks": [{"id": "org.example.go", "version": "0.1.0"}]}]
+}`
+
+// builderImage creates a builder image with the given metadata label.
+func builderImage(t *testing.T, metadata string) gcrv1.Image {
+	t.Helper()
+
+	image, err := mutate.Config(randomImage(t, 1), gcrv1.Config{
+		Labels: map[string]string{
+			"io.buildpacks.builder.metadata": metadata,
+		},
+	})
+	testutil.AssertNil(t, "config err", err)
+
+	return image
+}
+
+// buildpackImage creates an image with the files in its root directory.
+func buildpackImage(t *testing.T, files map[string]string) gcrv1.Image {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+	for name, contents := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0755, Size: int64(len(contents))})
+		testutil.AssertNil(t, "header err", err)
+		_, err = tw.Write([]byte(contents))
+		testutil.AssertNil(t, "write err", err)
+	}
+	testutil.AssertNil(t, "close err", tw.Close())
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	testutil.AssertNil(t, "layer err", err)
+
+	image, err := mutate.AppendLayers(randomImage(t, 0), layer)
+	testutil.AssertNil(t, "append err", err)
+
+	return image
+}
+
+// imageFiles reads the regular files and symlinks of the image.
+func imageFiles(t *testing.T, image gcrv1.Image) map[string]string {
+	t.Helper()
+
+	fs := mutate.Extract(image)
+	defer fs.Close()
+
+	files := make(map[string]string)
+	tr := tar.NewReader(fs)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files
+		}
+		testutil.AssertNil(t, "tar err", err)
+
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			contents, err := ioutil.ReadAll(tr)
+			testutil.AssertNil(t, "read err", err)
+			files[hdr.Name] = string(contents)
+		case tar.TypeSymlink:
+			files[hdr.Name] = "-> " + hdr.Linkname
+		}
+	}
+}
+
+func TestBuilderComposer_Compose(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "kf-compose-test")
+	testutil.AssertNil(t, "temp dir err", err)
+	defer os.RemoveAll(dir)
+
+	gitBuildpack := filepath.Join(dir, "node")
+	testutil.AssertNil(t, "mkdir err", os.MkdirAll(filepath.Join(gitBuildpack, "bin"), 0755))
+	testutil.AssertNil(t, "mkdir err", os.MkdirAll(filepath.Join(gitBuildpack, ".git"), 0755))
+	writeFile := func(name, contents string) {
+		err := ioutil.WriteFile(filepath.Join(gitBuildpack, name), []byte(contents), 0755)
+		testutil.AssertNil(t, "write err", err)
+	}
+	writeFile("buildpack.toml", "[buildpack]\nid = \"org.example/node\"\nversion = \"1.2.0\"\n\n[[stacks]]\nid = \"org.example.stack\"\n")
+	writeFile("bin/detect", "#!/bin/sh")
+	writeFile(".git/HEAD", "ref: refs/heads/main")
+
+	javaBuildpack := buildpackImage(t, map[string]string{
+		"buildpack.toml": "[buildpack]\n  id = \"org.example.java\"\n  version = \"2.0.0\"\n",
+		"bin/build":      "#!/bin/sh",
+	})
+
+	cases := map[string]struct {
+		base          gcrv1.Image
+		buildpacks    []string
+		writeErr      error
+		wantErr       error
+		validateImage func(t *testing.T, image gcrv1.Image)
+	}{
+		"adds buildpacks before the base groups": {
+			base:       builderImage(t, baseBuilderMetadata),
+			buildpacks: []string{gitBuildpack, "gcr.io/java-buildpack"},
+			validateImage: func(t *testing.T, image gcrv1.Image) {
+				files := imageFiles(t, image)
+				testutil.AssertEqual(t, "git buildpack", "#!/bin/sh", files["buildpacks/org.example_node/1.2.0/bin/detect"])
+				testutil.AssertEqual(t, "git buildpack latest", "-> 1.2.0", files["buildpacks/org.example_node/latest"])
+				testutil.AssertEqual(t, "image buildpack", "#!/bin/sh", files["buildpacks/org.example.java/2.0.0/bin/build"])
+				if _, ok := files["buildpacks/org.example_node/1.2.0/.git/HEAD"]; ok {
+					t.Error("expected Git metadata to be skipped")
+				}
+
+				testutil.AssertEqual(t, "order.toml", `[[groups]]
+
+  [[groups.buildpacks]]
+    id = "org.example/node"
+    version = "1.2.0"
+
+[[groups]]
+
+  [[groups.buildpacks]]
+    id = "org.example.java"
+    version = "2.0.0"
+
+[[groups]]
+
+  [[groups.buildpacks]]
+    id = "org.example.go"
+    version = "0.1.0"
+
+`, files["buildpacks/order.toml"])
+
+				cfg, err := image.ConfigFile()
+				testutil.AssertNil(t, "config err", err)
+
+				var metadata struct {
+					Description string                 `json:"description"`
+					Buildpacks  []buildpacks.Buildpack `json:"buildpacks"`
+				}
+				err = json.Unmarshal([]byte(cfg.Config.Labels["io.buildpacks.builder.metadata"]), &metadata)
+				testutil.AssertNil(t, "metadata err", err)
+				testutil.AssertEqual(t, "description", "base builder", metadata.Description)
+				testutil.AssertEqual(t, "buildpacks", []buildpacks.Buildpack{
+					{ID: "org.example/node", Version: "1.2.0", Latest: true},
+					{ID: "org.example.java", Version: "2.0.0", Latest: true},
+					{ID: "org.example.go", Version: "0.1.0", Latest: true},
+				}, metadata.Buildpacks)
+			},
+		},
+		"base isn't a builder": {
+			base:       randomImage(t, 1),
+			buildpacks: []string{gitBuildpack},
+			wantErr:    errors.New("image gcr.io/builder:base isn't a buildpack builder"),
+		},
+		"base has no groups": {
+			base:       builderImage(t, `{"buildpacks":[]}`),
+			buildpacks: []string{gitBuildpack},
+			wantErr:    errors.New("builder gcr.io/builder:base doesn't list its buildpack groups"),
+		},
+		"buildpack without buildpack.toml": {
+			base:       builderImage(t, baseBuilderMetadata),
+			buildpacks: []string{dir},
+			wantErr:    fmt.Errorf("buildpack %s: no buildpack.toml found", dir),
+		},
+		"buildpack.toml without a version": {
+			base:       builderImage(t, baseBuilderMetadata),
+			buildpacks: []string{"gcr.io/unversioned-buildpack"},
+			wantErr:    errors.New("buildpack gcr.io/unversioned-buildpack: buildpack.toml must set the buildpack id and version"),
+		},
+		"writing fails": {
+			base:       builderImage(t, baseBuilderMetadata),
+			buildpacks: []string{gitBuildpack},
+			writeErr:   errors.New("some-error"),
+			wantErr:    errors.New("some-error"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			fetcher := func(ref name.Reference, options ...remote.ImageOption) (gcrv1.Image, error) {
+				switch ref.Name() {
+				case "gcr.io/builder:base":
+					return tc.base, nil
+				case "gcr.io/java-buildpack:latest":
+					return javaBuildpack, nil
+				case "gcr.io/unversioned-buildpack:latest":
+					return buildpackImage(t, map[string]string{"buildpack.toml": "[buildpack]\nid = \"org.example.none\"\n"}), nil
+				default:
+					return nil, fmt.Errorf("unexpected image %s", ref.Name())
+				}
+			}
+
+			var written gcrv1.Image
+			writer := func(ref name.Reference, img gcrv1.Image, auth authn.Authenticator, t http.RoundTripper) error {
+				written = img
+				return tc.writeErr
+			}
+
+			err := buildpacks.NewBuilderComposer(fetcher, writer).Compose("gcr.io/builder:base", "gcr.io/builder:composed", tc.buildpacks)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+
+			if tc.validateImage != nil {
+				tc.validateImage(t, written)
+			}
+		})
+	}
+}
@@ -17,8 +17,20 @@ package fake
 import "github.com/google/kf/pkg/kf/buildpacks"
 
 //go:generate mockgen --package=fake --copyright_file ../../internal/tools/option-builder/LICENSE_HEADER --destination=fake_client.go --mock_names=Client=FakeClient github.com/google/kf/pkg/kf/buildpacks/fake Client
+//go:generate mockgen --package=fake --copyright_file ../../internal/tools/option-builder/LICENSE_HEADER --destination=fake_rebaser.go --mock_names=Rebaser=FakeRebaser github.com/google/kf/pkg/kf/buildpacks/fake Rebaser
+//go:generate mockgen --package=fake --copyright_file ../../internal/tools/option-builder/LICENSE_HEADER --destination=fake_provenance_reader.go --mock_names=ProvenanceReader=FakeProvenanceReader github.com/google/kf/pkg/kf/buildpacks/fake ProvenanceReader
 
 // Client is implemented by buildpacks.Client.
 type Client interface {
 	buildpacks.Client
 }
+
+// Rebaser is implemented by buildpacks.Rebaser.
+type Rebaser interface {
+	buildpacks.Rebaser
+}
+
+// ProvenanceReader is implemented by buildpacks.ProvenanceReader.
+type ProvenanceReader interface {
+	buildpacks.ProvenanceReader
+}
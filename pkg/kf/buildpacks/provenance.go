@@ -0,0 +1,145 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildpacks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+)
+
+// ProvenanceReader reads where images built by Kf came from.
+type ProvenanceReader interface {
+	// Provenance reads the provenance labels Kf and the buildpacks lifecycle
+	// recorded on the image.
+	Provenance(image string) (*Provenance, error)
+}
+
+// Provenance describes where an image built by Kf came from.
+type Provenance struct {
+	Image      string             `json:"image"`
+	Digest     string             `json:"digest"`
+	Space      string             `json:"space,omitempty"`
+	App        string             `json:"app,omitempty"`
+	Source     string             `json:"source,omitempty"`
+	Revision   string             `json:"revision,omitempty"`
+	Builder    string             `json:"builder,omitempty"`
+	KfVersion  string             `json:"kfVersion,omitempty"`
+	Buildpacks []BuildpackVersion `json:"buildpacks,omitempty"`
+}
+
+// BuildpackVersion is a buildpack that contributed to an image.
+type BuildpackVersion struct {
+	ID      string `json:"id"`
+	Version string `json:"version"`
+}
+
+type provenanceReader struct {
+	imageFetcher RemoteImageFetcher
+	keychain     authn.Keychain
+}
+
+// NewProvenanceReader creates a new ProvenanceReader that uses the local
+// Docker credentials to read images.
+func NewProvenanceReader(imageFetcher RemoteImageFetcher) ProvenanceReader {
+	return &provenanceReader{
+		imageFetcher: imageFetcher,
+		keychain:     authn.DefaultKeychain,
+	}
+}
+
+const buildMetadataLabel = "io.buildpacks.build.metadata"
+
+// Provenance implements ProvenanceReader.
+func (r *provenanceReader) Provenance(image string) (*Provenance, error) {
+	imageRef, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := r.imageFetcher(imageRef, remote.WithAuthFromKeychain(r.keychain))
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	labels := cfg.Config.Labels
+	buildpacks, err := buildpackVersions(labels)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't read the buildpacks metadata of %s: %s", image, err)
+	}
+
+	return &Provenance{
+		Image:      image,
+		Digest:     digest.String(),
+		Space:      labels[v1alpha1.ImageSpaceLabel],
+		App:        labels[v1alpha1.ImageAppLabel],
+		Source:     labels[v1alpha1.ImageSourceLabel],
+		Revision:   labels[v1alpha1.ImageOCIRevisionLabel],
+		Builder:    labels[v1alpha1.ImageBuilderLabel],
+		KfVersion:  labels[v1alpha1.ImageKfVersionLabel],
+		Buildpacks: buildpacks,
+	}, nil
+}
+
+// buildpackVersions reads the buildpacks that built the image. Newer
+// lifecycles record them in the build metadata, older ones in the lifecycle
+// metadata where the ID is called the key.
+func buildpackVersions(labels map[string]string) ([]BuildpackVersion, error) {
+	if raw, ok := labels[buildMetadataLabel]; ok {
+		var metadata struct {
+			Buildpacks []BuildpackVersion `json:"buildpacks"`
+		}
+		if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+			return nil, err
+		}
+
+		return metadata.Buildpacks, nil
+	}
+
+	if raw, ok := labels[lifecycleMetadataLabel]; ok {
+		var metadata struct {
+			Buildpacks []struct {
+				Key     string `json:"key"`
+				Version string `json:"version"`
+			} `json:"buildpacks"`
+		}
+		if err := json.Unmarshal([]byte(raw), &metadata); err != nil {
+			return nil, err
+		}
+
+		var buildpacks []BuildpackVersion
+		for _, bp := range metadata.Buildpacks {
+			buildpacks = append(buildpacks, BuildpackVersion{ID: bp.Key, Version: bp.Version})
+		}
+
+		return buildpacks, nil
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildpacks_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/kf/pkg/kf/buildpacks"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestProvenanceReader_Provenance(t *testing.T) {
+	t.Parallel()
+
+	labeledImage := func(t *testing.T, labels map[string]string) gcrv1.Image {
+		t.Helper()
+
+		image, err := mutate.Config(randomImage(t, 1), gcrv1.Config{Labels: labels})
+		testutil.AssertNil(t, "config err", err)
+
+		return image
+	}
+
+	for tn, tc := range map[string]struct {
+		image  string
+		labels map[string]string
+		err    error
+		assert func(t *testing.T, p *buildpacks.Provenance, err error)
+	}{
+		"invalid image": {
+			image: "INVALID!",
+			assert: func(t *testing.T, p *buildpacks.Provenance, err error) {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+			},
+		},
+		"fetching fails": {
+			image: "some-image",
+			err:   errors.New("some-error"),
+			assert: func(t *testing.T, p *buildpacks.Provenance, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("some-error"), err)
+			},
+		},
+		"kf labels": {
+			image: "some-image",
+			labels: map[string]string{
+				"dev.kf.image.space":                "some-space",
+				"dev.kf.image.app":                  "some-app",
+				"dev.kf.image.source":               "https://github.com/some/repo#main",
+				"dev.kf.image.builder":              "some-builder",
+				"dev.kf.image.kf-version":           "v1.2.3",
+				"org.opencontainers.image.revision": "main",
+			},
+			assert: func(t *testing.T, p *buildpacks.Provenance, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "image", "some-image", p.Image)
+				testutil.AssertEqual(t, "space", "some-space", p.Space)
+				testutil.AssertEqual(t, "app", "some-app", p.App)
+				testutil.AssertEqual(t, "source", "https://github.com/some/repo#main", p.Source)
+				testutil.AssertEqual(t, "revision", "main", p.Revision)
+				testutil.AssertEqual(t, "builder", "some-builder", p.Builder)
+				testutil.AssertEqual(t, "kf version", "v1.2.3", p.KfVersion)
+				testutil.AssertEqual(t, "buildpacks", 0, len(p.Buildpacks))
+				if p.Digest == "" {
+					t.Fatal("expected digest")
+				}
+			},
+		},
+		"build metadata": {
+			image: "some-image",
+			labels: map[string]string{
+				"io.buildpacks.build.metadata":     `{"buildpacks":[{"id":"bp-a","version":"1.0"},{"id":"bp-b","version":"2.0"}]}`,
+				"io.buildpacks.lifecycle.metadata": `{"buildpacks":[{"key":"ignored","version":"0.0"}]}`,
+			},
+			assert: func(t *testing.T, p *buildpacks.Provenance, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "buildpacks", []buildpacks.BuildpackVersion{
+					{ID: "bp-a", Version: "1.0"},
+					{ID: "bp-b", Version: "2.0"},
+				}, p.Buildpacks)
+			},
+		},
+		"lifecycle metadata": {
+			image: "some-image",
+			labels: map[string]string{
+				"io.buildpacks.lifecycle.metadata": `{"buildpacks":[{"key":"bp-a","version":"1.0"}]}`,
+			},
+			assert: func(t *testing.T, p *buildpacks.Provenance, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "buildpacks", []buildpacks.BuildpackVersion{
+					{ID: "bp-a", Version: "1.0"},
+				}, p.Buildpacks)
+			},
+		},
+		"invalid metadata": {
+			image: "some-image",
+			labels: map[string]string{
+				"io.buildpacks.build.metadata": `{`,
+			},
+			assert: func(t *testing.T, p *buildpacks.Provenance, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("couldn't read the buildpacks metadata of some-image: unexpected end of JSON input"), err)
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			image := labeledImage(t, tc.labels)
+			r := buildpacks.NewProvenanceReader(func(ref name.Reference, options ...remote.ImageOption) (gcrv1.Image, error) {
+				return image, tc.err
+			})
+
+			p, err := r.Provenance(tc.image)
+			tc.assert(t, p, err)
+		})
+	}
+}
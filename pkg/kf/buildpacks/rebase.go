@@ -0,0 +1,201 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildpacks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// Rebaser swaps the run image of images built with buildpacks without
+// rebuilding them.
+type Rebaser interface {
+	// Rebase replaces the layers the image got from its run image with the
+	// layers of runImage, and pushes the result to the same tag. It returns
+	// the digest of the pushed image, or an empty string if the image is
+	// already based on runImage.
+	Rebase(image, runImage string) (string, error)
+}
+
+// RemoteImageWriter is implemented by
+// github.com/google/go-containerregistry/pkg/v1/remote.Write
+type RemoteImageWriter func(ref name.Reference, img gcrv1.Image, auth authn.Authenticator, t http.RoundTripper) error
+
+type rebaser struct {
+	imageFetcher RemoteImageFetcher
+	imageWriter  RemoteImageWriter
+	keychain     authn.Keychain
+}
+
+// NewRebaser creates a new Rebaser that uses the local Docker credentials to
+// read and write images.
+func NewRebaser(
+	imageFetcher RemoteImageFetcher,
+	imageWriter RemoteImageWriter,
+) Rebaser {
+	return &rebaser{
+		imageFetcher: imageFetcher,
+		imageWriter:  imageWriter,
+		keychain:     authn.DefaultKeychain,
+	}
+}
+
+const lifecycleMetadataLabel = "io.buildpacks.lifecycle.metadata"
+
+// Rebase implements Rebaser.
+func (r *rebaser) Rebase(image, runImage string) (string, error) {
+	imageRef, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return "", err
+	}
+
+	runImageRef, err := name.ParseReference(runImage, name.WeakValidation)
+	if err != nil {
+		return "", err
+	}
+
+	orig, err := r.imageFetcher(imageRef, remote.WithAuthFromKeychain(r.keychain))
+	if err != nil {
+		return "", err
+	}
+
+	newBase, err := r.imageFetcher(runImageRef, remote.WithAuthFromKeychain(r.keychain))
+	if err != nil {
+		return "", err
+	}
+
+	origCfg, err := orig.ConfigFile()
+	if err != nil {
+		return "", err
+	}
+
+	rawMetadata, ok := origCfg.Config.Labels[lifecycleMetadataLabel]
+	if !ok {
+		return "", fmt.Errorf("image %s wasn't built with buildpacks", image)
+	}
+
+	metadata := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(rawMetadata), &metadata); err != nil {
+		return "", fmt.Errorf("couldn't read the buildpacks metadata of %s: %s", image, err)
+	}
+
+	runImageMetadata, _ := metadata["runImage"].(map[string]interface{})
+	oldTopLayer, _ := runImageMetadata["topLayer"].(string)
+	if oldTopLayer == "" {
+		return "", fmt.Errorf("image %s doesn't record its run image", image)
+	}
+
+	newTopLayer, err := topLayer(newBase)
+	if err != nil {
+		return "", err
+	}
+
+	if newTopLayer.String() == oldTopLayer {
+		return "", nil
+	}
+
+	appLayers, err := layersAbove(orig, oldTopLayer)
+	if err != nil {
+		return "", fmt.Errorf("image %s: %s", image, err)
+	}
+
+	rebased, err := mutate.AppendLayers(newBase, appLayers...)
+	if err != nil {
+		return "", err
+	}
+
+	newBaseDigest, err := newBase.Digest()
+	if err != nil {
+		return "", err
+	}
+
+	runImageMetadata["topLayer"] = newTopLayer.String()
+	runImageMetadata["reference"] = newBaseDigest.String()
+	if _, ok := runImageMetadata["sha"]; ok {
+		runImageMetadata["sha"] = newBaseDigest.String()
+	}
+
+	updatedMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	cfg := origCfg.Config.DeepCopy()
+	cfg.Labels[lifecycleMetadataLabel] = string(updatedMetadata)
+
+	rebased, err = mutate.Config(rebased, *cfg)
+	if err != nil {
+		return "", err
+	}
+
+	auth, err := r.keychain.Resolve(imageRef.Context().Registry)
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.imageWriter(imageRef, rebased, auth, http.DefaultTransport); err != nil {
+		return "", err
+	}
+
+	digest, err := rebased.Digest()
+	if err != nil {
+		return "", err
+	}
+
+	return digest.String(), nil
+}
+
+// topLayer returns the diff ID of the last layer in the image.
+func topLayer(image gcrv1.Image) (gcrv1.Hash, error) {
+	layers, err := image.Layers()
+	if err != nil {
+		return gcrv1.Hash{}, err
+	}
+
+	if len(layers) == 0 {
+		return gcrv1.Hash{}, fmt.Errorf("run image has no layers")
+	}
+
+	return layers[len(layers)-1].DiffID()
+}
+
+// layersAbove returns the layers of the image above the layer with the given
+// diff ID.
+func layersAbove(image gcrv1.Image, diffID string) ([]gcrv1.Layer, error) {
+	layers, err := image.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, layer := range layers {
+		layerDiffID, err := layer.DiffID()
+		if err != nil {
+			return nil, err
+		}
+
+		if layerDiffID.String() == diffID {
+			return layers[i+1:], nil
+		}
+	}
+
+	return nil, fmt.Errorf("run image layer %s not found", diffID)
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildpacks_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/kf/pkg/kf/buildpacks"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func randomImage(t *testing.T, layers int64) gcrv1.Image {
+	t.Helper()
+
+	image, err := random.Image(64, layers)
+	testutil.AssertNil(t, "random image err", err)
+
+	return image
+}
+
+func topLayerDiffID(t *testing.T, image gcrv1.Image) string {
+	t.Helper()
+
+	layers, err := image.Layers()
+	testutil.AssertNil(t, "layers err", err)
+
+	diffID, err := layers[len(layers)-1].DiffID()
+	testutil.AssertNil(t, "diff ID err", err)
+
+	return diffID.String()
+}
+
+// appImage creates an image with the given app layers on top of the run
+// image, labeled the way the buildpacks lifecycle labels it.
+func appImage(t *testing.T, runImage gcrv1.Image, metadata string) gcrv1.Image {
+	t.Helper()
+
+	appLayers, err := randomImage(t, 2).Layers()
+	testutil.AssertNil(t, "app layers err", err)
+
+	image, err := mutate.AppendLayers(runImage, appLayers...)
+	testutil.AssertNil(t, "append err", err)
+
+	if metadata != "" {
+		image, err = mutate.Config(image, gcrv1.Config{
+			Labels: map[string]string{
+				"io.buildpacks.lifecycle.metadata": metadata,
+			},
+		})
+		testutil.AssertNil(t, "config err", err)
+	}
+
+	return image
+}
+
+func TestRebaser_Rebase(t *testing.T) {
+	t.Parallel()
+
+	oldRunImage := randomImage(t, 2)
+	newRunImage := randomImage(t, 3)
+	oldMetadata := fmt.Sprintf(`{"runImage":{"topLayer":%q,"reference":"some-digest"}}`, topLayerDiffID(t, oldRunImage))
+
+	cases := map[string]struct {
+		image         gcrv1.Image
+		fetchErr      error
+		writeErr      error
+		wantRebased   bool
+		wantErr       error
+		validateImage func(t *testing.T, image gcrv1.Image)
+	}{
+		"rebases onto the new run image": {
+			image:       appImage(t, oldRunImage, oldMetadata),
+			wantRebased: true,
+			validateImage: func(t *testing.T, image gcrv1.Image) {
+				layers, err := image.Layers()
+				testutil.AssertNil(t, "layers err", err)
+				testutil.AssertEqual(t, "layer count", 5, len(layers))
+
+				runImageTop, err := layers[2].DiffID()
+				testutil.AssertNil(t, "diff ID err", err)
+				testutil.AssertEqual(t, "run image top layer", topLayerDiffID(t, newRunImage), runImageTop.String())
+
+				cfg, err := image.ConfigFile()
+				testutil.AssertNil(t, "config err", err)
+
+				var metadata struct {
+					RunImage struct {
+						TopLayer  string `json:"topLayer"`
+						Reference string `json:"reference"`
+					} `json:"runImage"`
+				}
+				err = json.Unmarshal([]byte(cfg.Config.Labels["io.buildpacks.lifecycle.metadata"]), &metadata)
+				testutil.AssertNil(t, "metadata err", err)
+				testutil.AssertEqual(t, "top layer", topLayerDiffID(t, newRunImage), metadata.RunImage.TopLayer)
+
+				newDigest, err := newRunImage.Digest()
+				testutil.AssertNil(t, "digest err", err)
+				testutil.AssertEqual(t, "reference", newDigest.String(), metadata.RunImage.Reference)
+			},
+		},
+		"already on the run image": {
+			image: appImage(t, newRunImage, fmt.Sprintf(`{"runImage":{"topLayer":%q}}`, topLayerDiffID(t, newRunImage))),
+		},
+		"not built with buildpacks": {
+			image:   appImage(t, oldRunImage, ""),
+			wantErr: errors.New("image gcr.io/my-app:1 wasn't built with buildpacks"),
+		},
+		"no run image recorded": {
+			image:   appImage(t, oldRunImage, `{}`),
+			wantErr: errors.New("image gcr.io/my-app:1 doesn't record its run image"),
+		},
+		"recorded run image not in image": {
+			image:   appImage(t, oldRunImage, `{"runImage":{"topLayer":"sha256:0000000000000000000000000000000000000000000000000000000000000000"}}`),
+			wantErr: errors.New("image gcr.io/my-app:1: run image layer sha256:0000000000000000000000000000000000000000000000000000000000000000 not found"),
+		},
+		"fetching fails": {
+			fetchErr: errors.New("some-error"),
+			wantErr:  errors.New("some-error"),
+		},
+		"writing fails": {
+			image:    appImage(t, oldRunImage, oldMetadata),
+			writeErr: errors.New("some-error"),
+			wantErr:  errors.New("some-error"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			fetcher := func(ref name.Reference, options ...remote.ImageOption) (gcrv1.Image, error) {
+				if tc.fetchErr != nil {
+					return nil, tc.fetchErr
+				}
+
+				switch ref.Name() {
+				case "gcr.io/my-app:1":
+					return tc.image, nil
+				case "gcr.io/run:latest":
+					return newRunImage, nil
+				default:
+					return nil, fmt.Errorf("unexpected image %s", ref.Name())
+				}
+			}
+
+			var written gcrv1.Image
+			writer := func(ref name.Reference, img gcrv1.Image, auth authn.Authenticator, t http.RoundTripper) error {
+				written = img
+				return tc.writeErr
+			}
+
+			digest, err := buildpacks.NewRebaser(fetcher, writer).Rebase("gcr.io/my-app:1", "gcr.io/run:latest")
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "rebased", tc.wantRebased, digest != "")
+
+			if tc.wantRebased {
+				writtenDigest, err := written.Digest()
+				testutil.AssertNil(t, "written digest err", err)
+				testutil.AssertEqual(t, "digest", writtenDigest.String(), digest)
+			}
+
+			if tc.validateImage != nil {
+				tc.validateImage(t, written)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ci
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	// ProviderGitHubActions generates a GitHub Actions workflow.
+	ProviderGitHubActions = "github-actions"
+
+	// ProviderCloudBuild generates a Cloud Build config.
+	ProviderCloudBuild = "cloud-build"
+
+	// ProviderGitLab generates a GitLab CI/CD pipeline.
+	ProviderGitLab = "gitlab"
+
+	// KfURL is where pipelines download the kf CLI from.
+	KfURL = "https://storage.googleapis.com/artifacts.kf-releases.appspot.com/nightly/latest/bin/kf-linux"
+)
+
+// Providers holds the supported CI systems.
+var Providers = []string{
+	ProviderGitHubActions,
+	ProviderCloudBuild,
+	ProviderGitLab,
+}
+
+// GKECluster identifies the GKE cluster a pipeline deploys to.
+type GKECluster struct {
+	Project  string
+	Location string
+	Name     string
+}
+
+// GKEClusterFromContext parses the cluster from the name gcloud gives
+// kubeconfig clusters, e.g. gke_my-project_us-central1_my-cluster. The
+// second value is false if the name wasn't created by gcloud.
+func GKEClusterFromContext(name string) (GKECluster, bool) {
+	parts := strings.SplitN(name, "_", 4)
+	if len(parts) != 4 || parts[0] != "gke" {
+		return GKECluster{}, false
+	}
+
+	for _, part := range parts {
+		if part == "" {
+			return GKECluster{}, false
+		}
+	}
+
+	return GKECluster{
+		Project:  parts[1],
+		Location: parts[2],
+		Name:     parts[3],
+	}, true
+}
+
+// Pipeline holds the values a generated pipeline is customized with.
+type Pipeline struct {
+	// App is the name of the App to deploy.
+	App string
+
+	// Space is the space the App is deployed to.
+	Space string
+
+	// Manifest is the path of the manifest to push with, relative to the
+	// root of the repository. If blank the App is pushed without one.
+	Manifest string
+
+	// Cluster is the GKE cluster to authenticate to.
+	Cluster GKECluster
+}
+
+// Generate writes the pipeline definition for the given provider to w.
+func Generate(w io.Writer, provider string, pipeline Pipeline) error {
+	tmpl, ok := providerTemplates[provider]
+	if !ok {
+		return fmt.Errorf("unknown CI provider %q, supported providers are %s", provider, strings.Join(Providers, ", "))
+	}
+
+	if pipeline.App == "" {
+		return errors.New("the pipeline needs an App")
+	}
+
+	if pipeline.Space == "" {
+		return errors.New("the pipeline needs a space")
+	}
+
+	script := &bytes.Buffer{}
+	if err := deployScript.Execute(script, pipeline); err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, struct {
+		Pipeline
+		Script string
+	}{
+		Pipeline: pipeline,
+		Script:   script.String(),
+	})
+}
+
+var templateFuncs = template.FuncMap{
+	"quote": strconv.Quote,
+
+	// indent prefixes every non-empty line of s with n spaces so multi-line
+	// scripts can be embedded in YAML block scalars.
+	"indent": func(n int, s string) string {
+		prefix := strings.Repeat(" ", n)
+		lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = prefix + line
+			}
+		}
+		return strings.Join(lines, "\n")
+	},
+}
+
+// deployScript is shared by every provider. It expects kubectl and kf to be
+// installed and authenticated to the cluster. The App is pushed in machine
+// mode, its route is checked and the previously running image is restored
+// if the App fails to deploy or serve traffic.
+var deployScript = template.Must(template.New("deploy").Parse(`set -euo pipefail
+
+kf target -s "$KF_SPACE"
+
+# Remember the running image so a failed deployment can be rolled back.
+PREVIOUS_IMAGE="$(kf app "$KF_APP" -o jsonpath='{.status.image}' 2>/dev/null || true)"
+
+rollback() {
+  if [ -z "$PREVIOUS_IMAGE" ]; then
+    echo "No previous image of $KF_APP to roll back to"
+    exit 1
+  fi
+
+  echo "Rolling back $KF_APP to $PREVIOUS_IMAGE"
+  kubectl patch apps.kf.dev "$KF_APP" -n "$KF_SPACE" --type merge -p \
+    "{\"spec\":{\"source\":{\"containerImage\":{\"image\":\"$PREVIOUS_IMAGE\"},\"buildpackBuild\":null,\"dockerfile\":null,\"git\":null}}}"
+  kubectl wait apps.kf.dev "$KF_APP" -n "$KF_SPACE" --for=condition=Ready --timeout=5m
+  exit 1
+}
+
+kf push "$KF_APP"{{ if .Manifest }} --manifest "$KF_MANIFEST"{{ end }} --machine || {
+  status=$?
+  # Exit code 4 means the App built but failed to deploy, other failures
+  # leave the running version untouched.
+  if [ "$status" -eq 4 ]; then
+    rollback
+  fi
+  exit "$status"
+}
+
+# Verify the route serves traffic.
+URL="$(kf app "$KF_APP" -o jsonpath='{.status.url}')"
+curl --fail --silent --show-error --output /dev/null \
+  --retry 5 --retry-delay 10 --retry-connrefused "$URL" || rollback
+`))
+
+const installKf = `curl -sSfL "` + KfURL + `" -o /usr/local/bin/kf && chmod +x /usr/local/bin/kf`
+
+var providerTemplates = map[string]*template.Template{
+	ProviderGitHubActions: template.Must(template.New(ProviderGitHubActions).Funcs(templateFuncs).Parse(`# Generated by kf ci generate, deploys {{ .App }} to space {{ .Space }}.
+# Store the JSON key of a service account that can deploy to the space in the
+# GCP_CREDENTIALS secret.
+name: Deploy {{ .App }}
+
+on:
+  push:
+    branches:
+    - main
+
+env:
+  KF_APP: {{ quote .App }}
+  KF_SPACE: {{ quote .Space }}
+{{- if .Manifest }}
+  KF_MANIFEST: {{ quote .Manifest }}
+{{- end }}
+
+jobs:
+  deploy:
+    runs-on: ubuntu-latest
+    steps:
+    - uses: actions/checkout@v4
+    - uses: google-github-actions/auth@v2
+      with:
+        credentials_json: ${{"{{"}} secrets.GCP_CREDENTIALS {{"}}"}}
+    - uses: google-github-actions/get-gke-credentials@v2
+      with:
+        project_id: {{ quote .Cluster.Project }}
+        location: {{ quote .Cluster.Location }}
+        cluster_name: {{ quote .Cluster.Name }}
+    - name: Install kf
+      run: sudo sh -c '` + installKf + `'
+    - name: Deploy
+      run: |
+{{ indent 8 .Script }}
+`)),
+
+	ProviderCloudBuild: template.Must(template.New(ProviderCloudBuild).Funcs(templateFuncs).Parse(`# Generated by kf ci generate, deploys {{ .App }} to space {{ .Space }}.
+# The Cloud Build service account needs permission to deploy to the space.
+steps:
+- id: deploy
+  name: gcr.io/google.com/cloudsdktool/cloud-sdk
+  env:
+  - KF_APP={{ .App }}
+  - KF_SPACE={{ .Space }}
+{{- if .Manifest }}
+  - KF_MANIFEST={{ .Manifest }}
+{{- end }}
+  script: |
+    #!/usr/bin/env bash
+    set -euo pipefail
+    gcloud container clusters get-credentials {{ quote .Cluster.Name }} --location {{ quote .Cluster.Location }} --project {{ quote .Cluster.Project }}
+    ` + installKf + `
+
+{{ indent 4 .Script }}
+`)),
+
+	ProviderGitLab: template.Must(template.New(ProviderGitLab).Funcs(templateFuncs).Parse(`# Generated by kf ci generate, deploys {{ .App }} to space {{ .Space }}.
+# Store the JSON key of a service account that can deploy to the space in the
+# GCP_SERVICE_ACCOUNT_KEY file variable.
+deploy:
+  stage: deploy
+  image: gcr.io/google.com/cloudsdktool/cloud-sdk
+  rules:
+  - if: $CI_COMMIT_BRANCH == $CI_DEFAULT_BRANCH
+  variables:
+    KF_APP: {{ quote .App }}
+    KF_SPACE: {{ quote .Space }}
+{{- if .Manifest }}
+    KF_MANIFEST: {{ quote .Manifest }}
+{{- end }}
+  script:
+  - gcloud auth activate-service-account --key-file "$GCP_SERVICE_ACCOUNT_KEY"
+  - gcloud container clusters get-credentials {{ quote .Cluster.Name }} --location {{ quote .Cluster.Location }} --project {{ quote .Cluster.Project }}
+  - ` + installKf + `
+  - |
+{{ indent 4 .Script }}
+`)),
+}
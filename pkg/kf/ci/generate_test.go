@@ -0,0 +1,149 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ci_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/ci"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestGKEClusterFromContext(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Name            string
+		ExpectedCluster ci.GKECluster
+		ExpectedOK      bool
+	}{
+		"gcloud context": {
+			Name: "gke_my-project_us-central1-a_my_cluster",
+			ExpectedCluster: ci.GKECluster{
+				Project:  "my-project",
+				Location: "us-central1-a",
+				Name:     "my_cluster",
+			},
+			ExpectedOK: true,
+		},
+		"other context": {
+			Name: "minikube",
+		},
+		"missing parts": {
+			Name: "gke_my-project__my-cluster",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			cluster, ok := ci.GKEClusterFromContext(tc.Name)
+
+			testutil.AssertEqual(t, "cluster", tc.ExpectedCluster, cluster)
+			testutil.AssertEqual(t, "ok", tc.ExpectedOK, ok)
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	pipeline := ci.Pipeline{
+		App:      "my-app",
+		Space:    "production",
+		Manifest: "manifest.yml",
+		Cluster: ci.GKECluster{
+			Project:  "my-project",
+			Location: "us-central1",
+			Name:     "my-cluster",
+		},
+	}
+
+	deploySteps := []string{
+		`kf target -s "$KF_SPACE"`,
+		`kf app "$KF_APP" -o jsonpath='{.status.image}'`,
+		`kf push "$KF_APP" --manifest "$KF_MANIFEST" --machine || {`,
+		`kf app "$KF_APP" -o jsonpath='{.status.url}'`,
+		`if [ "$status" -eq 4 ]; then`,
+		`kubectl patch apps.kf.dev "$KF_APP"`,
+		ci.KfURL,
+	}
+
+	cases := map[string]struct {
+		Provider        string
+		Pipeline        ci.Pipeline
+		ExpectedErr     error
+		ExpectedStrings []string
+	}{
+		"github actions": {
+			Provider: ci.ProviderGitHubActions,
+			Pipeline: pipeline,
+			ExpectedStrings: append([]string{
+				`KF_APP: "my-app"`,
+				`KF_SPACE: "production"`,
+				"credentials_json: ${{ secrets.GCP_CREDENTIALS }}",
+				`cluster_name: "my-cluster"`,
+				"      run: |\n        set -euo pipefail\n",
+			}, deploySteps...),
+		},
+		"cloud build": {
+			Provider: ci.ProviderCloudBuild,
+			Pipeline: pipeline,
+			ExpectedStrings: append([]string{
+				"- KF_APP=my-app",
+				`gcloud container clusters get-credentials "my-cluster" --location "us-central1" --project "my-project"`,
+			}, deploySteps...),
+		},
+		"gitlab": {
+			Provider: ci.ProviderGitLab,
+			Pipeline: pipeline,
+			ExpectedStrings: append([]string{
+				`KF_MANIFEST: "manifest.yml"`,
+				`--key-file "$GCP_SERVICE_ACCOUNT_KEY"`,
+			}, deploySteps...),
+		},
+		"no manifest": {
+			Provider: ci.ProviderGitLab,
+			Pipeline: ci.Pipeline{App: "my-app", Space: "production"},
+			ExpectedStrings: []string{
+				`kf push "$KF_APP" --machine || {`,
+			},
+		},
+		"unknown provider": {
+			Provider:    "jenkins",
+			Pipeline:    pipeline,
+			ExpectedErr: errors.New(`unknown CI provider "jenkins", supported providers are github-actions, cloud-build, gitlab`),
+		},
+		"missing app": {
+			Provider:    ci.ProviderGitLab,
+			Pipeline:    ci.Pipeline{Space: "production"},
+			ExpectedErr: errors.New("the pipeline needs an App"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			buffer := &bytes.Buffer{}
+			err := ci.Generate(buffer, tc.Provider, tc.Pipeline)
+			if tc.ExpectedErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, err)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buffer.String(), tc.ExpectedStrings)
+		})
+	}
+}
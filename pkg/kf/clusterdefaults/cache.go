@@ -0,0 +1,102 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterdefaults
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheEntry is the format the defaults are cached on disk in.
+type cacheEntry struct {
+	Defaults   Defaults  `json:"defaults"`
+	ResolvedAt time.Time `json:"resolvedAt"`
+}
+
+type cachedClient struct {
+	client Client
+	path   string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewCachedClient creates a Client that caches the defaults from client in a
+// file at path. Cached defaults are used until they're older than ttl.
+// Failing to read or write the cache isn't an error, the defaults are fetched
+// from the cluster instead.
+func NewCachedClient(client Client, path string, ttl time.Duration) Client {
+	return &cachedClient{
+		client: client,
+		path:   path,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// Get implements Client.
+func (c *cachedClient) Get() (*Defaults, error) {
+	if entry, ok := c.read(); ok && c.now().Sub(entry.ResolvedAt) < c.ttl {
+		return &entry.Defaults, nil
+	}
+
+	return c.Refresh()
+}
+
+// Refresh implements Client.
+func (c *cachedClient) Refresh() (*Defaults, error) {
+	defaults, err := c.client.Refresh()
+	if err != nil {
+		return nil, err
+	}
+
+	c.write(cacheEntry{
+		Defaults:   *defaults,
+		ResolvedAt: c.now(),
+	})
+
+	return defaults, nil
+}
+
+// read loads the cached defaults, ok is false if there aren't any.
+func (c *cachedClient) read() (entry cacheEntry, ok bool) {
+	contents, err := ioutil.ReadFile(c.path)
+	if err != nil {
+		return entry, false
+	}
+
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		return entry, false
+	}
+
+	return entry, true
+}
+
+// write saves the defaults, this is best effort because the worst case is the
+// defaults get fetched again next time.
+func (c *cachedClient) write(entry cacheEntry) {
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return
+	}
+
+	ioutil.WriteFile(c.path, contents, 0644)
+}
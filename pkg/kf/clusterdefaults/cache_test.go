@@ -0,0 +1,125 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterdefaults
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+// countingClient is a Client that counts how many times the cluster is
+// contacted.
+type countingClient struct {
+	defaults *Defaults
+	err      error
+	calls    int
+}
+
+func (c *countingClient) Get() (*Defaults, error) {
+	return c.Refresh()
+}
+
+func (c *countingClient) Refresh() (*Defaults, error) {
+	c.calls++
+	return c.defaults, c.err
+}
+
+func TestCachedClient(t *testing.T) {
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	defaults := &Defaults{Domain: "example.com", BuilderImage: "builder"}
+
+	cases := map[string]struct {
+		err       error
+		run       func(t *testing.T, c *cachedClient, now *time.Time) (*Defaults, error)
+		wantCalls int
+		wantErr   error
+	}{
+		"fetches when not cached": {
+			run: func(t *testing.T, c *cachedClient, now *time.Time) (*Defaults, error) {
+				return c.Get()
+			},
+			wantCalls: 1,
+		},
+		"uses cached defaults": {
+			run: func(t *testing.T, c *cachedClient, now *time.Time) (*Defaults, error) {
+				c.Get()
+				*now = now.Add(time.Minute)
+				return c.Get()
+			},
+			wantCalls: 1,
+		},
+		"fetches expired defaults": {
+			run: func(t *testing.T, c *cachedClient, now *time.Time) (*Defaults, error) {
+				c.Get()
+				*now = now.Add(time.Hour)
+				return c.Get()
+			},
+			wantCalls: 2,
+		},
+		"refresh bypasses the cache": {
+			run: func(t *testing.T, c *cachedClient, now *time.Time) (*Defaults, error) {
+				c.Get()
+				return c.Refresh()
+			},
+			wantCalls: 2,
+		},
+		"corrupt cache": {
+			run: func(t *testing.T, c *cachedClient, now *time.Time) (*Defaults, error) {
+				testutil.AssertNil(t, "mkdir", os.MkdirAll(filepath.Dir(c.path), 0755))
+				testutil.AssertNil(t, "write", ioutil.WriteFile(c.path, []byte("{"), 0644))
+				return c.Get()
+			},
+			wantCalls: 1,
+		},
+		"errors aren't cached": {
+			err: errors.New("connection refused"),
+			run: func(t *testing.T, c *cachedClient, now *time.Time) (*Defaults, error) {
+				c.Get()
+				return c.Get()
+			},
+			wantCalls: 2,
+			wantErr:   errors.New("connection refused"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "cluster-defaults")
+			testutil.AssertNil(t, "TempDir", err)
+			defer os.RemoveAll(dir)
+
+			client := &countingClient{defaults: defaults, err: tc.err}
+			now := start
+
+			c := NewCachedClient(client, filepath.Join(dir, "cache", "defaults.json"), 10*time.Minute).(*cachedClient)
+			c.now = func() time.Time { return now }
+
+			got, err := tc.run(t, c, &now)
+			testutil.AssertEqual(t, "calls", tc.wantCalls, client.calls)
+			if tc.wantErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "defaults", defaults, got)
+		})
+	}
+}
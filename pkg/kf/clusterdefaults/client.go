@@ -0,0 +1,130 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterdefaults
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	routecfg "knative.dev/serving/pkg/reconciler/route/config"
+)
+
+// DomainConfigMapNamespace is the namespace Knative's domain ConfigMap lives
+// in.
+const DomainConfigMapNamespace = "knative-serving"
+
+// Defaults holds the defaults the cluster applies to Spaces that don't
+// override them.
+type Defaults struct {
+	// Domain is the domain Spaces are given a subdomain of.
+	Domain string `json:"domain"`
+
+	// BuilderImage is the buildpack builder used to build Apps.
+	BuilderImage string `json:"builderImage"`
+
+	// ContainerRegistry is the registry built images are stored in, it's
+	// blank if the cluster doesn't have one.
+	ContainerRegistry string `json:"containerRegistry,omitempty"`
+}
+
+// SpaceDomain gets the default domain of the given Space.
+func (d *Defaults) SpaceDomain(space string) string {
+	return fmt.Sprintf(v1alpha1.DefaultDomainTemplate, space, d.Domain)
+}
+
+// Client gets the cluster's defaults.
+type Client interface {
+	// Get returns the cluster's defaults, possibly from a cache.
+	Get() (*Defaults, error)
+
+	// Refresh returns the cluster's defaults, bypassing any cache.
+	Refresh() (*Defaults, error)
+}
+
+type client struct {
+	configMaps v1.ConfigMapsGetter
+}
+
+// NewClient creates a new Client that reads the defaults from the cluster on
+// every call.
+func NewClient(configMaps v1.ConfigMapsGetter) Client {
+	return &client{
+		configMaps: configMaps,
+	}
+}
+
+// Get implements Client.
+func (c *client) Get() (*Defaults, error) {
+	return c.Refresh()
+}
+
+// Refresh implements Client.
+func (c *client) Refresh() (*Defaults, error) {
+	domain, err := c.domain()
+	if err != nil {
+		return nil, err
+	}
+
+	build, err := c.buildConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Defaults{
+		Domain:            domain,
+		BuilderImage:      build.BuilderImage,
+		ContainerRegistry: build.ContainerRegistry,
+	}, nil
+}
+
+// buildConfig gets the build settings the controller gives Spaces that don't
+// set their own.
+func (c *client) buildConfig() (*Config, error) {
+	cm, err := c.configMaps.
+		ConfigMaps(ConfigMapNamespace).
+		Get(ConfigMapName, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		return NewConfigFromMap(nil), nil
+	case err != nil:
+		return nil, fmt.Errorf("couldn't get the cluster's build defaults: %v", err)
+	}
+
+	return NewConfigFromConfigMap(cm), nil
+}
+
+// domain gets the domain Knative routes Apps without labels under, the same
+// one the webhook defaults Spaces with.
+func (c *client) domain() (string, error) {
+	cm, err := c.configMaps.
+		ConfigMaps(DomainConfigMapNamespace).
+		Get(routecfg.DomainConfigName, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		return routecfg.DefaultDomain, nil
+	case err != nil:
+		return "", fmt.Errorf("couldn't get the cluster's domain: %v", err)
+	}
+
+	cfg, err := routecfg.NewDomainFromConfigMap(cm)
+	if err != nil {
+		return "", fmt.Errorf("invalid domain configuration: %v", err)
+	}
+
+	return cfg.LookupDomainForLabels(map[string]string{}), nil
+}
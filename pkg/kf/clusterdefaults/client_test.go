@@ -0,0 +1,125 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterdefaults
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func ExampleDefaults_SpaceDomain() {
+	defaults := &Defaults{Domain: "apps.example.com"}
+
+	fmt.Println(defaults.SpaceDomain("my-space"))
+
+	// Output: my-space.apps.example.com
+}
+
+func TestClient_Get(t *testing.T) {
+	domainConfig := func(data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "config-domain",
+				Namespace: DomainConfigMapNamespace,
+			},
+			Data: data,
+		}
+	}
+
+	cases := map[string]struct {
+		objects      []runtime.Object
+		reactor      ktesting.ReactionFunc
+		wantDefaults *Defaults
+		wantErr      []string
+	}{
+		"missing ConfigMap": {
+			wantDefaults: &Defaults{
+				Domain:       "example.com",
+				BuilderImage: v1alpha1.DefaultBuilderImage,
+			},
+		},
+		"default domain": {
+			objects: []runtime.Object{domainConfig(map[string]string{
+				"_example":         "some docs",
+				"apps.example.com": "",
+				"internal.example": "selector:\n  app: internal\n",
+			})},
+			wantDefaults: &Defaults{
+				Domain:       "apps.example.com",
+				BuilderImage: v1alpha1.DefaultBuilderImage,
+			},
+		},
+		"build defaults": {
+			objects: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      ConfigMapName,
+						Namespace: ConfigMapNamespace,
+					},
+					Data: map[string]string{
+						"builderImage":      "gcr.io/my-project/builder",
+						"containerRegistry": "gcr.io/my-project",
+					},
+				},
+			},
+			wantDefaults: &Defaults{
+				Domain:            "example.com",
+				BuilderImage:      "gcr.io/my-project/builder",
+				ContainerRegistry: "gcr.io/my-project",
+			},
+		},
+		"invalid ConfigMap": {
+			objects: []runtime.Object{domainConfig(map[string]string{
+				"apps.example.com": "[",
+			})},
+			wantErr: []string{"invalid domain configuration"},
+		},
+		"server error": {
+			reactor: func(ktesting.Action) (bool, runtime.Object, error) {
+				return true, nil, errors.New("connection refused")
+			},
+			wantErr: []string{"couldn't get the cluster's domain: connection refused"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			k8s := k8sfake.NewSimpleClientset(tc.objects...)
+			if tc.reactor != nil {
+				k8s.PrependReactor("get", "configmaps", tc.reactor)
+			}
+
+			defaults, err := NewClient(k8s.CoreV1()).Get()
+			if tc.wantErr != nil {
+				testutil.AssertNotNil(t, "err", err)
+				testutil.AssertErrorContainsAll(t, err, tc.wantErr)
+				return
+			}
+
+			testutil.AssertNil(t, "err", err)
+
+			testutil.AssertEqual(t, "defaults", tc.wantDefaults, defaults)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterdefaults
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/configmap"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap in the kf namespace that
+	// holds the build defaults for Spaces.
+	ConfigMapName = "config-defaults"
+
+	// ConfigMapNamespace is the namespace the defaults ConfigMap lives in.
+	ConfigMapNamespace = "kf"
+
+	builderImageKey      = "builderImage"
+	containerRegistryKey = "containerRegistry"
+)
+
+// Config holds the build settings Spaces use when they don't set their own.
+type Config struct {
+	// BuilderImage is the buildpack builder used to build Apps.
+	BuilderImage string
+
+	// ContainerRegistry is the registry built images are stored in, it's
+	// blank if the cluster doesn't have one.
+	ContainerRegistry string
+}
+
+// SpaceBuildpackBuild converts the Config into the defaults recorded on a
+// Space's status.
+func (c *Config) SpaceBuildpackBuild() v1alpha1.SpaceStatusBuildpackBuild {
+	return v1alpha1.SpaceStatusBuildpackBuild{
+		BuilderImage:      c.BuilderImage,
+		ContainerRegistry: c.ContainerRegistry,
+	}
+}
+
+// NewConfigFromMap creates a Config from the data of the defaults ConfigMap.
+// The builder image falls back to Kf's built-in one.
+func NewConfigFromMap(data map[string]string) *Config {
+	cfg := &Config{
+		BuilderImage:      strings.TrimSpace(data[builderImageKey]),
+		ContainerRegistry: strings.TrimSpace(data[containerRegistryKey]),
+	}
+
+	if cfg.BuilderImage == "" {
+		cfg.BuilderImage = v1alpha1.DefaultBuilderImage
+	}
+
+	return cfg
+}
+
+// NewConfigFromConfigMap creates a Config from the defaults ConfigMap.
+func NewConfigFromConfigMap(cm *corev1.ConfigMap) *Config {
+	return NewConfigFromMap(cm.Data)
+}
+
+// Store holds the latest Config read from the cluster so reconcilers can
+// default Spaces without fetching the ConfigMap. The zero value holds the
+// built-in defaults.
+type Store struct {
+	config atomic.Value
+}
+
+// Load returns the latest Config.
+func (s *Store) Load() *Config {
+	if cfg, ok := s.config.Load().(*Config); ok {
+		return cfg
+	}
+
+	return NewConfigFromMap(nil)
+}
+
+// WatchConfigs updates the Store whenever the defaults ConfigMap changes.
+func (s *Store) WatchConfigs(cmw configmap.Watcher, logger *zap.SugaredLogger) {
+	cmw.Watch(ConfigMapName, func(cm *corev1.ConfigMap) {
+		cfg := NewConfigFromConfigMap(cm)
+		logger.Infow("Updated cluster defaults",
+			zap.String("builderImage", cfg.BuilderImage),
+			zap.String("containerRegistry", cfg.ContainerRegistry))
+
+		s.config.Store(cfg)
+	})
+}
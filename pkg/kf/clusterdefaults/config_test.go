@@ -0,0 +1,97 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clusterdefaults
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	"go.uber.org/zap/zaptest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/configmap"
+)
+
+func TestNewConfigFromMap(t *testing.T) {
+	cases := map[string]struct {
+		data     map[string]string
+		expected *Config
+	}{
+		"empty": {
+			data: map[string]string{},
+			expected: &Config{
+				BuilderImage: v1alpha1.DefaultBuilderImage,
+			},
+		},
+		"example only": {
+			data: map[string]string{
+				configmap.ExampleKey: "builderImage: gcr.io/example",
+			},
+			expected: &Config{
+				BuilderImage: v1alpha1.DefaultBuilderImage,
+			},
+		},
+		"values": {
+			data: map[string]string{
+				"builderImage":      "gcr.io/my-project/builder ",
+				"containerRegistry": "gcr.io/my-project",
+			},
+			expected: &Config{
+				BuilderImage:      "gcr.io/my-project/builder",
+				ContainerRegistry: "gcr.io/my-project",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "config", tc.expected, NewConfigFromMap(tc.data))
+		})
+	}
+}
+
+func TestConfig_SpaceBuildpackBuild(t *testing.T) {
+	cfg := &Config{
+		BuilderImage:      "gcr.io/builder",
+		ContainerRegistry: "gcr.io/registry",
+	}
+
+	testutil.AssertEqual(t, "buildpack build", v1alpha1.SpaceStatusBuildpackBuild{
+		BuilderImage:      "gcr.io/builder",
+		ContainerRegistry: "gcr.io/registry",
+	}, cfg.SpaceBuildpackBuild())
+}
+
+func TestStore(t *testing.T) {
+	store := &Store{}
+	testutil.AssertEqual(t, "initial config", &Config{
+		BuilderImage: v1alpha1.DefaultBuilderImage,
+	}, store.Load())
+
+	cmw := &configmap.ManualWatcher{Namespace: ConfigMapNamespace}
+	store.WatchConfigs(cmw, zaptest.NewLogger(t).Sugar())
+
+	cmw.OnChange(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: ConfigMapNamespace},
+		Data: map[string]string{
+			"containerRegistry": "gcr.io/my-project",
+		},
+	})
+	testutil.AssertEqual(t, "config after update", &Config{
+		BuilderImage:      v1alpha1.DefaultBuilderImage,
+		ContainerRegistry: "gcr.io/my-project",
+	}, store.Load())
+}
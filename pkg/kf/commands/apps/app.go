@@ -19,26 +19,58 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
 	"github.com/google/kf/pkg/kf/describe"
+	"github.com/google/kf/pkg/kf/events"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/metrics"
+	servicebindings "github.com/google/kf/pkg/kf/service-bindings"
+	"github.com/google/kf/pkg/kf/services"
+	"github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 // NewGetAppCommand creates a command to get details about a single application.
-func NewGetAppCommand(p *config.KfParams, appsClient apps.Client) *cobra.Command {
+func NewGetAppCommand(
+	p *config.KfParams,
+	appsClient apps.Client,
+	metricsClient metrics.Client,
+	eventsClient v1.EventsGetter,
+	bindingsClient servicebindings.ClientInterface,
+	servicesClient services.Client,
+) *cobra.Command {
 	printFlags := genericclioptions.NewPrintFlags("")
 
+	var (
+		showMetrics bool
+		since       time.Duration
+	)
+
 	var cmd = &cobra.Command{
-		Use:     "app APP_NAME",
-		Short:   "Print information about a deployed app",
-		Long:    `Prints information about a deployed app.`,
-		Example: `kf app my-app`,
-		Args:    cobra.ExactArgs(1),
+		Use:   "app APP_NAME",
+		Short: "Print information about a deployed app",
+		Long: `Prints information about a deployed app.
+
+		Each service binding is listed with the state of the last operation
+		the broker ran for it and for its service instance, so failed
+		provisions and binds are visible next to the app.
+
+		The --metrics flag adds the CPU, memory and request rate history of each
+		instance to help right-size the app. History is read from Prometheus if
+		it's installed, otherwise only current usage is available from
+		metrics-server.
+		`,
+		Example: `
+		kf app my-app
+		kf app my-app --metrics --since 6h
+		`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := utils.ValidateNamespace(p); err != nil {
 				return err
@@ -77,6 +109,11 @@ func NewGetAppCommand(p *config.KfParams, appsClient apps.Client) *cobra.Command
 			describe.AppSpecInstances(w, app.Spec.Instances)
 			fmt.Fprintln(w)
 
+			if app.Status.ScaledToZero {
+				fmt.Fprintln(w, "Warning: the app is scaled to zero, the next request will wait for it to start.")
+				fmt.Fprintln(w)
+			}
+
 			describe.AppSpecTemplate(w, app.Spec.Template)
 			fmt.Fprintln(w)
 
@@ -91,18 +128,97 @@ func NewGetAppCommand(p *config.KfParams, appsClient apps.Client) *cobra.Command
 				kfApp := apps.NewFromApp(app)
 				fmt.Fprintf(w, "Cluster URL\t%s\n", kfApp.GetClusterURL())
 
+				if pool := app.Spec.EgressIPPool; pool != "" {
+					egressIPs := "<unknown>"
+					if len(status.EgressIPs) > 0 {
+						egressIPs = strings.Join(status.EgressIPs, ", ")
+					}
+
+					fmt.Fprintf(w, "Egress IP Pool:\t%s\n", pool)
+					fmt.Fprintf(w, "Egress IPs:\t%s\n", egressIPs)
+				}
+
 				describe.HealthCheck(w, kfApp.GetHealthCheck())
 				describe.EnvVars(w, kfApp.GetEnvVars())
 				describe.RouteSpecFieldsList(w, app.Spec.Routes)
 			})
 			fmt.Fprintln(w)
 
+			// Only query the service catalog for apps with bindings so apps
+			// can be described in clusters without it.
+			var (
+				bindings  []v1beta1.ServiceBinding
+				instances []v1beta1.ServiceInstance
+			)
+			if len(app.Spec.ServiceBindings) > 0 {
+				bindings, err = bindingsClient.List(
+					servicebindings.WithListAppName(appName),
+					servicebindings.WithListNamespace(p.Namespace))
+				if err != nil {
+					return err
+				}
+
+				instances, err = servicesClient.List(p.Namespace)
+				if err != nil {
+					return err
+				}
+			}
+
+			describe.ServiceBindings(w, bindings, instances)
+			fmt.Fprintln(w)
+
+			if showMetrics {
+				history, err := metricsClient.History(p.Namespace, appName, since)
+				if err != nil {
+					return err
+				}
+
+				if history.Source == metrics.SourceMetricsServer {
+					fmt.Fprintln(cmd.ErrOrStderr(), "Prometheus isn't installed, showing current usage from metrics-server")
+				}
+
+				describe.AppMetrics(w, history)
+				fmt.Fprintln(w)
+			}
+
+			// Show the events of the app and its latest source together so
+			// build failures are visible along with deployment failures.
+			appEvents, err := events.List(eventsClient, p.Namespace, "App", app.Name)
+			if err != nil {
+				return err
+			}
+
+			if sourceName := app.Status.LatestCreatedSourceName; sourceName != "" {
+				sourceEvents, err := events.List(eventsClient, p.Namespace, "Source", sourceName)
+				if err != nil {
+					return err
+				}
+				appEvents = append(appEvents, sourceEvents...)
+			}
+
+			describe.Events(w, appEvents)
+			fmt.Fprintln(w)
+
 			return nil
 		},
 	}
 
 	printFlags.AddFlags(cmd)
 
+	cmd.Flags().BoolVar(
+		&showMetrics,
+		"metrics",
+		false,
+		"Show the CPU, memory and request rate history of each instance.",
+	)
+
+	cmd.Flags().DurationVar(
+		&since,
+		"since",
+		time.Hour,
+		"How far back to show metrics history when --metrics is set.",
+	)
+
 	// Override output format to be sorted so our generated documents are deterministic
 	{
 		allowedFormats := printFlags.AllowedFormats()
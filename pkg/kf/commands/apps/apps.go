@@ -20,6 +20,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/commands/config"
 	"github.com/google/kf/pkg/kf/describe"
@@ -30,42 +31,72 @@ import (
 
 // NewAppsCommand creates a apps command.
 func NewAppsCommand(p *config.KfParams, appsClient apps.Client) *cobra.Command {
-	return &cobra.Command{
-		Use:     "apps",
-		Short:   "List pushed apps",
-		Long:    ``,
-		Example: `kf apps`,
-		Args:    cobra.ExactArgs(0),
+	var (
+		allSpaces utils.AllSpacesFlags
+		listFlags utils.ListFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apps",
+		Short: "List pushed apps",
+		Long: `List the apps in the targeted space.
+
+		The --all-spaces flag lists the apps in every space with a single
+		cluster-wide request, so it requires permission to list apps in all
+		namespaces.
+
+		Large spaces can be listed a page at a time with --limit, the command
+		prints the --continue token to use for the next page. Sorting applies
+		to the apps in the current page.
+		`,
+		Example: `
+		kf apps
+		kf apps --all-spaces
+		kf apps --filter payments- --sort-by status
+		kf apps --limit 100
+		kf apps --limit 100 --continue eyJ2IjoibWV0YS5rOHMuaW8vdjEi...
+		`,
+		Args: cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := utils.ValidateNamespace(p); err != nil {
+			if err := listFlags.Validate(); err != nil {
+				return err
+			}
+
+			namespace, err := allSpaces.Namespace(p)
+			if err != nil {
 				return err
 			}
 			cmd.SilenceUsage = true
 
-			fmt.Fprintf(cmd.OutOrStdout(), "Getting apps in space %s\n\n", p.Namespace)
+			fmt.Fprintf(cmd.OutOrStdout(), "Getting apps in %s\n\n", allSpaces.Description(p))
 
-			applist, err := appsClient.List(p.Namespace)
+			applist, continueToken, err := appsClient.ListPage(
+				namespace,
+				apps.WithListLimit(listFlags.Limit()),
+				apps.WithListContinueToken(listFlags.ContinueToken()),
+				apps.WithListFilter(func(app *v1alpha1.App) bool {
+					return listFlags.MatchesName(app.Name)
+				}),
+			)
 			if err != nil {
 				return err
 			}
 
+			listFlags.Sort(applist, func(i int) utils.ListSortKey {
+				return utils.ListSortKey{
+					Name:    applist[i].Name,
+					Created: applist[i].CreationTimestamp.Time,
+					Status:  requestedState(&applist[i]),
+				}
+			})
+
 			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+				if allSpaces.IsAllSpaces() {
+					fmt.Fprint(w, "Space\t")
+				}
 				fmt.Fprintln(w, "Name\tRequested State\tInstances\tMemory\tDisk\tURLs\tCluster URL")
 				for _, app := range applist {
 
-					// Requested State
-					var requestedState string
-					switch {
-					case !app.DeletionTimestamp.IsZero():
-						requestedState = "deleting"
-					case app.Spec.Instances.Stopped:
-						requestedState = "stopped"
-					case !app.Status.IsReady():
-						requestedState = "not ready"
-					default:
-						requestedState = "ready"
-					}
-
 					// Instances
 					var instances string
 					switch {
@@ -116,9 +147,12 @@ func NewAppsCommand(p *config.KfParams, appsClient apps.Client) *cobra.Command {
 
 					kfApp := apps.NewFromApp(&app)
 
+					if allSpaces.IsAllSpaces() {
+						fmt.Fprintf(w, "%s\t", app.Namespace)
+					}
 					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
 						app.Name,
-						requestedState,
+						requestedState(&app),
 						instances,
 						memory,
 						disk,
@@ -128,7 +162,29 @@ func NewAppsCommand(p *config.KfParams, appsClient apps.Client) *cobra.Command {
 				}
 			})
 
+			listFlags.PrintNextPage(cmd.OutOrStdout(), continueToken)
+
 			return nil
 		},
 	}
+
+	allSpaces.Add(cmd)
+	listFlags.Add(cmd)
+
+	return cmd
+}
+
+// requestedState summarizes the state the user asked the app to be in and
+// whether it got there.
+func requestedState(app *v1alpha1.App) string {
+	switch {
+	case !app.DeletionTimestamp.IsZero():
+		return "deleting"
+	case app.Spec.Instances.Stopped:
+		return "stopped"
+	case !app.Status.IsReady():
+		return "not ready"
+	default:
+		return "ready"
+	}
 }
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
@@ -51,7 +52,7 @@ func TestAppsCommand(t *testing.T) {
 			setup: func(t *testing.T, fakeLister *fake.FakeClient) {
 				fakeLister.
 					EXPECT().
-					List("some-namespace")
+					ListPage("some-namespace", gomock.Any())
 			},
 		},
 		"configured namespace": {
@@ -59,7 +60,23 @@ func TestAppsCommand(t *testing.T) {
 			setup: func(t *testing.T, fakeLister *fake.FakeClient) {
 				fakeLister.
 					EXPECT().
-					List("some-namespace")
+					ListPage("some-namespace", gomock.Any())
+			},
+		},
+		"all spaces": {
+			args: []string{"--all-spaces"},
+			setup: func(t *testing.T, fakeLister *fake.FakeClient) {
+				fakeLister.
+					EXPECT().
+					ListPage("", gomock.Any()).
+					Return([]v1alpha1.App{
+						{ObjectMeta: metav1.ObjectMeta{Name: "app-a", Namespace: "space-a"}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "app-b", Namespace: "space-b"}},
+					}, "", nil)
+			},
+			assert: func(t *testing.T, buffer *bytes.Buffer) {
+				header1 := "Getting apps in all spaces"
+				testutil.AssertContainsAll(t, buffer.String(), []string{header1, "Space", "space-a", "app-a", "space-b", "app-b"})
 			},
 		},
 		"formats multiple apps": {
@@ -67,11 +84,11 @@ func TestAppsCommand(t *testing.T) {
 			setup: func(t *testing.T, fakeLister *fake.FakeClient) {
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
+					ListPage(gomock.Any(), gomock.Any()).
 					Return([]v1alpha1.App{
 						{ObjectMeta: metav1.ObjectMeta{Name: "app-a"}},
 						{ObjectMeta: metav1.ObjectMeta{Name: "app-b"}},
-					}, nil)
+					}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				header1 := "Getting apps in space "
@@ -83,10 +100,10 @@ func TestAppsCommand(t *testing.T) {
 			setup: func(t *testing.T, fakeLister *fake.FakeClient) {
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
+					ListPage(gomock.Any(), gomock.Any()).
 					Return([]v1alpha1.App{
 						{ObjectMeta: metav1.ObjectMeta{Name: "app-a"}, Status: happyStatus()},
-					}, nil)
+					}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				header1 := "Getting apps in space "
@@ -98,10 +115,10 @@ func TestAppsCommand(t *testing.T) {
 			setup: func(t *testing.T, fakeLister *fake.FakeClient) {
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
+					ListPage(gomock.Any(), gomock.Any()).
 					Return([]v1alpha1.App{
 						{ObjectMeta: metav1.ObjectMeta{Name: "app-a"}, Status: v1alpha1.AppStatus{}},
-					}, nil)
+					}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				header1 := "Getting apps in space "
@@ -117,8 +134,8 @@ func TestAppsCommand(t *testing.T) {
 
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
-					Return([]v1alpha1.App{app}, nil)
+					ListPage(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{app}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				header1 := "Getting apps in space "
@@ -131,10 +148,10 @@ func TestAppsCommand(t *testing.T) {
 				dt := metav1.Now()
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
+					ListPage(gomock.Any(), gomock.Any()).
 					Return([]v1alpha1.App{
 						{ObjectMeta: metav1.ObjectMeta{Name: "app-a", DeletionTimestamp: &dt}},
-					}, nil)
+					}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				header1 := "Getting apps in space "
@@ -150,8 +167,8 @@ func TestAppsCommand(t *testing.T) {
 
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
-					Return([]v1alpha1.App{app}, nil)
+					ListPage(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{app}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				header1 := "Getting apps in space "
@@ -168,8 +185,8 @@ func TestAppsCommand(t *testing.T) {
 
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
-					Return([]v1alpha1.App{app}, nil)
+					ListPage(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{app}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				header1 := "Getting apps in space "
@@ -185,8 +202,8 @@ func TestAppsCommand(t *testing.T) {
 
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
-					Return([]v1alpha1.App{app}, nil)
+					ListPage(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{app}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				header1 := "Getting apps in space "
@@ -202,8 +219,8 @@ func TestAppsCommand(t *testing.T) {
 
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
-					Return([]v1alpha1.App{app}, nil)
+					ListPage(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{app}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				header1 := "Getting apps in space "
@@ -221,8 +238,8 @@ func TestAppsCommand(t *testing.T) {
 
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
-					Return([]v1alpha1.App{app}, nil)
+					ListPage(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{app}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				header1 := "Getting apps in space "
@@ -245,22 +262,64 @@ func TestAppsCommand(t *testing.T) {
 
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
-					Return([]v1alpha1.App{app}, nil)
+					ListPage(gomock.Any(), gomock.Any()).
+					Return([]v1alpha1.App{app}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				testutil.AssertContainsAll(t, buffer.String(), []string{"http://app-a.some-namespace.svc.cluster.local"})
 			},
 		},
 
+		"invalid sort": {
+			namespace: "some-namespace",
+			args:      []string{"--sort-by", "size"},
+			wantErr:   errors.New(`unsupported --sort-by "size", must be one of: name, age, status`),
+		},
+		"prints next page token": {
+			namespace: "some-namespace",
+			args:      []string{"--limit", "1"},
+			setup: func(t *testing.T, fakeLister *fake.FakeClient) {
+				fakeLister.
+					EXPECT().
+					ListPage("some-namespace", gomock.Any()).
+					Return([]v1alpha1.App{
+						{ObjectMeta: metav1.ObjectMeta{Name: "app-a"}},
+					}, "next-token", nil)
+			},
+			assert: func(t *testing.T, buffer *bytes.Buffer) {
+				testutil.AssertContainsAll(t, buffer.String(), []string{"app-a", "--continue next-token"})
+			},
+		},
+		"sorts by age": {
+			namespace: "some-namespace",
+			args:      []string{"--sort-by", "age"},
+			setup: func(t *testing.T, fakeLister *fake.FakeClient) {
+				newer := metav1.NewTime(time.Now())
+				older := metav1.NewTime(newer.Add(-time.Hour))
+
+				fakeLister.
+					EXPECT().
+					ListPage("some-namespace", gomock.Any()).
+					Return([]v1alpha1.App{
+						{ObjectMeta: metav1.ObjectMeta{Name: "app-a", CreationTimestamp: newer}},
+						{ObjectMeta: metav1.ObjectMeta{Name: "app-b", CreationTimestamp: older}},
+					}, "", nil)
+			},
+			assert: func(t *testing.T, buffer *bytes.Buffer) {
+				out := buffer.String()
+				if strings.Index(out, "app-b") > strings.Index(out, "app-a") {
+					t.Fatalf("expected older app-b before app-a, got:\n%s", out)
+				}
+			},
+		},
 		"listing apps fails": {
 			namespace: "some-namespace",
 			wantErr:   errors.New("some-error"),
 			setup: func(t *testing.T, fakeLister *fake.FakeClient) {
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
-					Return(nil, errors.New("some-error"))
+					ListPage(gomock.Any(), gomock.Any()).
+					Return(nil, "", errors.New("some-error"))
 			},
 		},
 		"filters out apps without a name": {
@@ -268,11 +327,11 @@ func TestAppsCommand(t *testing.T) {
 			setup: func(t *testing.T, fakeLister *fake.FakeClient) {
 				fakeLister.
 					EXPECT().
-					List(gomock.Any()).
+					ListPage(gomock.Any(), gomock.Any()).
 					Return([]v1alpha1.App{
 						{Status: v1alpha1.AppStatus{Status: duckv1beta1.Status{Conditions: []apis.Condition{{Type: "Ready", Status: "should-not-see-this"}}}}},
 						{ObjectMeta: metav1.ObjectMeta{Name: "app-b"}},
-					}, nil)
+					}, "", nil)
 			},
 			assert: func(t *testing.T, buffer *bytes.Buffer) {
 				if strings.Contains(buffer.String(), "should-not-see-this") {
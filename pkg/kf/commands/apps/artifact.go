@@ -0,0 +1,123 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// artifactExtensions are the prebuilt archives that can be pushed instead of
+// source. JARs and WARs are ZIPs, the Java buildpack detects them by their
+// META-INF/MANIFEST.MF once they're extracted.
+var artifactExtensions = []string{".jar", ".war", ".zip"}
+
+// isArtifact returns true if path is a file with an artifact extension.
+func isArtifact(path string) bool {
+	if !hasArtifactExtension(path) {
+		return false
+	}
+
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+func hasArtifactExtension(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, artifactExt := range artifactExtensions {
+		if ext == artifactExt {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stageArtifact extracts a prebuilt artifact into a temporary directory so it
+// can be uploaded like source, the same way Cloud Foundry stages artifacts.
+// The returned cleanup func removes the directory.
+func stageArtifact(artifact string) (string, func(), error) {
+	if !hasArtifactExtension(artifact) {
+		return "", nil, fmt.Errorf("artifact %s must be one of: %s", artifact, strings.Join(artifactExtensions, ", "))
+	}
+
+	reader, err := zip.OpenReader(artifact)
+	if err != nil {
+		return "", nil, fmt.Errorf("couldn't read artifact %s: %v", artifact, err)
+	}
+	defer reader.Close()
+
+	staged, err := ioutil.TempDir("", "kf-artifact")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(staged) }
+
+	for _, f := range reader.File {
+		if err := extractZipFile(f, staged); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("couldn't extract artifact %s: %v", artifact, err)
+		}
+	}
+
+	return staged, cleanup, nil
+}
+
+// extractZipFile writes f under dst, rejecting entries that would escape it.
+func extractZipFile(f *zip.File, dst string) error {
+	target := filepath.Join(dst, filepath.FromSlash(f.Name))
+	if target != dst && !strings.HasPrefix(target, dst+string(filepath.Separator)) {
+		return fmt.Errorf("%s is outside of the artifact", f.Name)
+	}
+
+	if f.FileInfo().IsDir() {
+		return os.MkdirAll(target, 0755)
+	}
+
+	// Not every archiver records directory entries.
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	// Archives rarely record useful permissions, only executability is kept
+	// so the staged source is the same no matter who built the artifact.
+	mode := os.FileMode(0644)
+	if f.Mode()&0111 != 0 {
+		mode = 0755
+	}
+
+	in, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,122 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"archive/zip"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+// writeTestArtifact writes a ZIP with the given files and contents to path.
+func writeTestArtifact(t *testing.T, path string, files map[string]string) {
+	t.Helper()
+
+	f, err := os.Create(path)
+	testutil.AssertNil(t, "create", err)
+	defer f.Close()
+
+	w := zip.NewWriter(f)
+	for name, contents := range files {
+		fw, err := w.Create(name)
+		testutil.AssertNil(t, "create entry", err)
+		_, err = fw.Write([]byte(contents))
+		testutil.AssertNil(t, "write entry", err)
+	}
+	testutil.AssertNil(t, "close", w.Close())
+}
+
+func TestStageArtifact(t *testing.T) {
+	cases := map[string]struct {
+		name      string
+		files     map[string]string
+		wantFiles []string
+		wantErr   error
+	}{
+		"extracts jar": {
+			name: "app.jar",
+			files: map[string]string{
+				"META-INF/MANIFEST.MF":       "Main-Class: App",
+				"BOOT-INF/classes/App.class": "class",
+			},
+			wantFiles: []string{"META-INF/MANIFEST.MF", "BOOT-INF/classes/App.class"},
+		},
+		"extracts war": {
+			name:      "app.WAR",
+			files:     map[string]string{"WEB-INF/web.xml": "<web-app/>"},
+			wantFiles: []string{"WEB-INF/web.xml"},
+		},
+		"unsupported extension": {
+			name:    "app.tar.gz",
+			wantErr: errors.New("artifact DIR/app.tar.gz must be one of: .jar, .war, .zip"),
+		},
+		"entry outside of artifact": {
+			name:    "app.zip",
+			files:   map[string]string{"../escape.txt": "nope"},
+			wantErr: errors.New("couldn't extract artifact DIR/app.zip: ../escape.txt is outside of the artifact"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "kf-artifact-test")
+			testutil.AssertNil(t, "temp dir", err)
+			defer os.RemoveAll(dir)
+
+			artifact := filepath.Join(dir, tc.name)
+			writeTestArtifact(t, artifact, tc.files)
+
+			staged, cleanup, err := stageArtifact(artifact)
+			if tc.wantErr != nil || err != nil {
+				if err != nil {
+					err = errors.New(strings.Replace(err.Error(), dir, "DIR", -1))
+				}
+				testutil.AssertErrorsEqual(t, tc.wantErr, err)
+				return
+			}
+			defer cleanup()
+
+			for _, want := range tc.wantFiles {
+				contents, err := ioutil.ReadFile(filepath.Join(staged, filepath.FromSlash(want)))
+				testutil.AssertNil(t, want+" extracted", err)
+				testutil.AssertEqual(t, want+" contents", tc.files[want], string(contents))
+			}
+
+			cleanup()
+			_, err = os.Stat(staged)
+			testutil.AssertEqual(t, "cleaned up", true, os.IsNotExist(err))
+		})
+	}
+}
+
+func TestIsArtifact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kf-artifact-test")
+	testutil.AssertNil(t, "temp dir", err)
+	defer os.RemoveAll(dir)
+
+	writeTestArtifact(t, filepath.Join(dir, "app.jar"), nil)
+	testutil.AssertNil(t, "mkdir", os.Mkdir(filepath.Join(dir, "dir.zip"), 0755))
+
+	testutil.AssertEqual(t, "jar", true, isArtifact(filepath.Join(dir, "app.jar")))
+	testutil.AssertEqual(t, "directory", false, isArtifact(filepath.Join(dir, "dir.zip")))
+	testutil.AssertEqual(t, "missing", false, isArtifact(filepath.Join(dir, "missing.jar")))
+	testutil.AssertEqual(t, "source", false, isArtifact(dir))
+}
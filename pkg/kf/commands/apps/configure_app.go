@@ -0,0 +1,601 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// NewConfigureAppCommand creates a command that can set facets of an app.
+func NewConfigureAppCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "configure-app [subcommand]",
+		Aliases: []string{"config-app"},
+		Short:   "Set configuration for an app",
+		Long: `The configure-app sub-command allows developers to configure
+		individual fields on an app that aren't set by pushes.
+		`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newSetScheduleCommand(p, client),
+		newUnsetScheduleCommand(p, client),
+		newGetScheduleCommand(p, client),
+		newSetMinInstancesCommand(p, client),
+		newSetRestartOnChangeCommand(p, client),
+		newUnsetRestartOnChangeCommand(p, client),
+		newSetEgressIPPoolCommand(p, client),
+		newUnsetEgressIPPoolCommand(p, client),
+		newSetDeletionProtectionCommand(p, client),
+	)
+
+	return cmd
+}
+
+func newSetScheduleCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var (
+		schedule  v1alpha1.AppSpecInstancesSchedule
+		diffFlags utils.DiffFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-schedule APP_NAME --start CRON --stop CRON",
+		Short: "Start and stop the app on a schedule.",
+		Long: `Start and stop the app on a schedule.
+
+		Schedules use the five field cron format (minute, hour, day of month,
+		month and day of week) and are commonly used to scale development apps to
+		zero outside of working hours.
+		`,
+		Example: `kf configure-app set-schedule myapp --start "0 8 * * 1-5" --stop "0 20 * * 1-5"`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			if err := schedule.Validate(context.Background()); err != nil {
+				return err
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(app *v1alpha1.App) error {
+				app.Spec.Instances.Schedule = schedule.DeepCopy()
+				return nil
+			}
+
+			_, err = client.Transform(p.Namespace, args[0], apps.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...))
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&schedule.Start,
+		"start",
+		"",
+		"Cron expression for when the app should be started.",
+	)
+
+	cmd.Flags().StringVar(
+		&schedule.Stop,
+		"stop",
+		"",
+		"Cron expression for when the app should be stopped.",
+	)
+
+	cmd.Flags().StringVar(
+		&schedule.TimeZone,
+		"timezone",
+		"",
+		"IANA time zone the schedule is evaluated in, e.g. America/New_York (default: UTC).",
+	)
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+func newUnsetScheduleCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var diffFlags utils.DiffFlags
+
+	cmd := &cobra.Command{
+		Use:     "unset-schedule APP_NAME",
+		Short:   "Remove the start and stop schedule from the app.",
+		Example: "kf configure-app unset-schedule myapp",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(app *v1alpha1.App) error {
+				app.Spec.Instances.Schedule = nil
+				return nil
+			}
+
+			_, err = client.Transform(p.Namespace, args[0], apps.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...))
+			return err
+		},
+	}
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+func newGetScheduleCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "get-schedule APP_NAME",
+		Short:   "Get the start and stop schedule of the app.",
+		Example: "kf configure-app get-schedule myapp",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			app, err := client.Get(p.Namespace, args[0])
+			if err != nil {
+				return err
+			}
+
+			if app.Spec.Instances.Schedule == nil {
+				fmt.Fprintln(cmd.OutOrStdout(), "No schedule set")
+				return nil
+			}
+
+			m, err := k8syaml.Marshal(app.Spec.Instances.Schedule)
+			if err != nil {
+				return fmt.Errorf("couldn't convert value to YAML: %s", err)
+			}
+
+			fmt.Fprint(cmd.OutOrStdout(), string(m))
+			return nil
+		},
+	}
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+func newSetMinInstancesCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var (
+		enableWakeup     bool
+		coldStartTimeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-min-instances APP_NAME MIN",
+		Short: "Set the minimum number of instances the app autoscales to.",
+		Long: `Set the minimum number of instances the app autoscales to.
+
+		Setting the minimum to zero allows the app to scale to zero when it isn't
+		receiving traffic. The next request wakes the app up and waits for it to
+		start, so --enable-wakeup must be set to acknowledge the cold start.
+		`,
+		Example: `
+		kf configure-app set-min-instances myapp 1
+		kf configure-app set-min-instances myapp 0 --enable-wakeup --cold-start-timeout 2m
+		`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			min, err := strconv.Atoi(args[1])
+			if err != nil || min < 0 {
+				return fmt.Errorf("invalid minimum instances %q, must be a non-negative integer", args[1])
+			}
+
+			if min == 0 && !enableWakeup {
+				return errors.New("apps scaled to zero only start when they receive a request, set --enable-wakeup to allow it")
+			}
+
+			if min > 0 && enableWakeup {
+				return errors.New("--enable-wakeup can only be set when the minimum is 0")
+			}
+
+			var timeoutSeconds *int64
+			if cmd.Flags().Changed("cold-start-timeout") {
+				seconds := int64(coldStartTimeout / time.Second)
+				if seconds <= 0 {
+					return fmt.Errorf("invalid cold start timeout %s, must be at least 1s", coldStartTimeout)
+				}
+				timeoutSeconds = &seconds
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(app *v1alpha1.App) error {
+				instances := &app.Spec.Instances
+				instances.Exactly = nil
+				instances.Min = &min
+
+				// Keep the bounds valid if the new minimum exceeds the old maximum.
+				if instances.Max != nil && *instances.Max < min {
+					instances.Max = &min
+				}
+
+				if timeoutSeconds != nil {
+					instances.ColdStartTimeoutSeconds = timeoutSeconds
+				}
+
+				return nil
+			}
+
+			_, err = client.Transform(p.Namespace, args[0], apps.DiffWrapper(cmd.OutOrStdout(), mutator))
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&enableWakeup,
+		"enable-wakeup",
+		false,
+		"Allow the app to scale to zero and be started by the next request.",
+	)
+
+	cmd.Flags().DurationVar(
+		&coldStartTimeout,
+		"cold-start-timeout",
+		0,
+		"Maximum time a request may take, including waiting for the app to start (e.g. 2m, default: 5m).",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+func newSetRestartOnChangeCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var diffFlags utils.DiffFlags
+
+	cmd := &cobra.Command{
+		Use:   "set-restart-on-change APP_NAME secret/NAME|configmap/NAME...",
+		Short: "Restart the app when Secrets or ConfigMaps change.",
+		Long: `Restart the app when the data of Secrets or ConfigMaps in its space
+		changes.
+
+		Apps read Secrets and ConfigMaps when they start, so rotated
+		credentials and changed configuration only take effect once the app
+		is restarted. The app's instances are rolled whenever the data of any
+		of the listed objects changes, including when they're created or
+		deleted.
+		`,
+		Example: `
+		kf configure-app set-restart-on-change myapp secret/db-creds
+		kf configure-app set-restart-on-change myapp secret/db-creds configmap/feature-flags
+		`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			refs, err := parseRestartOnChangeRefs(args[1:])
+			if err != nil {
+				return err
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(app *v1alpha1.App) error {
+				for _, ref := range refs {
+					if !hasRestartOnChangeRef(app.Spec.RestartOnChange, ref) {
+						app.Spec.RestartOnChange = append(app.Spec.RestartOnChange, ref)
+					}
+				}
+				return nil
+			}
+
+			_, err = client.Transform(p.Namespace, args[0], apps.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...))
+			return err
+		},
+	}
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+func newUnsetRestartOnChangeCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var diffFlags utils.DiffFlags
+
+	cmd := &cobra.Command{
+		Use:   "unset-restart-on-change APP_NAME [secret/NAME|configmap/NAME...]",
+		Short: "Stop restarting the app when Secrets or ConfigMaps change.",
+		Long: `Stop restarting the app when the listed Secrets or ConfigMaps change,
+		or when any of them change if none are listed.
+		`,
+		Example: `
+		kf configure-app unset-restart-on-change myapp secret/db-creds
+		kf configure-app unset-restart-on-change myapp
+		`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			refs, err := parseRestartOnChangeRefs(args[1:])
+			if err != nil {
+				return err
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(app *v1alpha1.App) error {
+				if len(refs) == 0 {
+					app.Spec.RestartOnChange = nil
+					return nil
+				}
+
+				var kept []v1alpha1.AppSpecRestartOnChange
+				for _, ref := range app.Spec.RestartOnChange {
+					if !hasRestartOnChangeRef(refs, ref) {
+						kept = append(kept, ref)
+					}
+				}
+				app.Spec.RestartOnChange = kept
+				return nil
+			}
+
+			_, err = client.Transform(p.Namespace, args[0], apps.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...))
+			return err
+		},
+	}
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+func newSetEgressIPPoolCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var diffFlags utils.DiffFlags
+
+	cmd := &cobra.Command{
+		Use:   "set-egress-ip-pool APP_NAME POOL",
+		Short: "Send the app's outbound traffic from an egress IP pool.",
+		Long: `Send the app's outbound traffic from one of the cluster's egress IP
+		pools so services that allowlist source IPs see deterministic
+		addresses.
+
+		The app's instances are annotated with kf.dev/egress-ip-pool for the
+		cluster's egress solution to route. The pool's IPs are shown by kf app
+		once an operator has recorded them in the config-egress-ip-pools
+		ConfigMap.
+		`,
+		Example: `
+		kf configure-app set-egress-ip-pool myapp partner-allowlist
+		`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			pool := args[1]
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(app *v1alpha1.App) error {
+				app.Spec.EgressIPPool = pool
+				return nil
+			}
+
+			_, err = client.Transform(p.Namespace, args[0], apps.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...))
+			return err
+		},
+	}
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+func newUnsetEgressIPPoolCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var diffFlags utils.DiffFlags
+
+	cmd := &cobra.Command{
+		Use:   "unset-egress-ip-pool APP_NAME",
+		Short: "Send the app's outbound traffic from the cluster's default IPs.",
+		Example: `
+		kf configure-app unset-egress-ip-pool myapp
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(app *v1alpha1.App) error {
+				app.Spec.EgressIPPool = ""
+				return nil
+			}
+
+			_, err = client.Transform(p.Namespace, args[0], apps.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...))
+			return err
+		},
+	}
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+const (
+	deletionProtectionOn  = "on"
+	deletionProtectionOff = "off"
+)
+
+func newSetDeletionProtectionCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var diffFlags utils.DiffFlags
+
+	cmd := &cobra.Command{
+		Use:   "set-deletion-protection APP_NAME on|off",
+		Short: "Set whether the app can be deleted without --force-delete-protected.",
+		Long: `Set whether the app can be deleted without --force-delete-protected.
+
+		Protected apps can't be deleted by kf delete, kubectl, or any other
+		client until protection is turned off. kf delete
+		--force-delete-protected turns it off and deletes the app in one step.
+		Apps are still deleted with the space they're in.
+		`,
+		Example: `
+		kf configure-app set-deletion-protection myapp on
+		kf configure-app set-deletion-protection myapp off
+		`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			var enabled bool
+			switch args[1] {
+			case deletionProtectionOn:
+				enabled = true
+			case deletionProtectionOff:
+				enabled = false
+			default:
+				return fmt.Errorf("setting must be %s or %s, got %q", deletionProtectionOn, deletionProtectionOff, args[1])
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(app *v1alpha1.App) error {
+				v1alpha1.SetDeletionProtection(app, enabled)
+				return nil
+			}
+
+			_, err = client.Transform(p.Namespace, args[0], apps.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...))
+			return err
+		},
+	}
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+// parseRestartOnChangeRefs parses references in the form secret/NAME or
+// configmap/NAME.
+func parseRestartOnChangeRefs(args []string) ([]v1alpha1.AppSpecRestartOnChange, error) {
+	var refs []v1alpha1.AppSpecRestartOnChange
+	for _, arg := range args {
+		split := strings.SplitN(arg, "/", 2)
+		if len(split) != 2 || split[1] == "" {
+			return nil, fmt.Errorf("invalid reference %q, must be secret/NAME or configmap/NAME", arg)
+		}
+
+		ref := v1alpha1.AppSpecRestartOnChange{Name: split[1]}
+		switch strings.ToLower(split[0]) {
+		case "secret":
+			ref.Kind = v1alpha1.RestartOnChangeKindSecret
+		case "configmap":
+			ref.Kind = v1alpha1.RestartOnChangeKindConfigMap
+		default:
+			return nil, fmt.Errorf("invalid reference %q, must be secret/NAME or configmap/NAME", arg)
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
+
+func hasRestartOnChangeRef(refs []v1alpha1.AppSpecRestartOnChange, ref v1alpha1.AppSpecRestartOnChange) bool {
+	for _, r := range refs {
+		if r == ref {
+			return true
+		}
+	}
+
+	return false
+}
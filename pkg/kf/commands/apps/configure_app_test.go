@@ -0,0 +1,307 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestConfigureApp(t *testing.T) {
+	t.Parallel()
+
+	officeHours := &v1alpha1.AppSpecInstancesSchedule{
+		Start: "0 8 * * 1-5",
+		Stop:  "0 20 * * 1-5",
+	}
+
+	cases := map[string]struct {
+		Namespace       string
+		Args            []string
+		ExpectedStrings []string
+		ExpectedErr     error
+		Setup           func(t *testing.T, fake *fake.FakeClient)
+	}{
+		"set-schedule": {
+			Namespace: "default",
+			Args:      []string{"set-schedule", "my-app", "--start", "0 8 * * 1-5", "--stop", "0 20 * * 1-5"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						var app v1alpha1.App
+						testutil.AssertNil(t, "mutator error", mutator(&app))
+						testutil.AssertEqual(t, "app.spec.instances.schedule", officeHours, app.Spec.Instances.Schedule)
+					})
+			},
+		},
+		"set-schedule invalid": {
+			Namespace:   "default",
+			Args:        []string{"set-schedule", "my-app", "--start", "0 8 * *", "--stop", "0 20 * * 1-5"},
+			ExpectedErr: errors.New("invalid value: 0 8 * *: start"),
+		},
+		"set-schedule missing stop": {
+			Namespace:   "default",
+			Args:        []string{"set-schedule", "my-app", "--start", "0 8 * * 1-5"},
+			ExpectedErr: errors.New("missing field(s): stop"),
+		},
+		"set-schedule no namespace": {
+			Args:        []string{"set-schedule", "my-app", "--start", "0 8 * * 1-5", "--stop", "0 20 * * 1-5"},
+			ExpectedErr: errors.New(utils.EmptyNamespaceError),
+		},
+		"unset-schedule": {
+			Namespace: "default",
+			Args:      []string{"unset-schedule", "my-app"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						app := v1alpha1.App{}
+						app.Spec.Instances.Schedule = officeHours.DeepCopy()
+						testutil.AssertNil(t, "mutator error", mutator(&app))
+						testutil.AssertEqual(t, "app.spec.instances.schedule", (*v1alpha1.AppSpecInstancesSchedule)(nil), app.Spec.Instances.Schedule)
+					})
+			},
+		},
+		"get-schedule": {
+			Namespace:       "default",
+			Args:            []string{"get-schedule", "my-app"},
+			ExpectedStrings: []string{"start: 0 8 * * 1-5", "stop: 0 20 * * 1-5"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				app := &v1alpha1.App{}
+				app.Spec.Instances.Schedule = officeHours.DeepCopy()
+				fake.EXPECT().Get("default", "my-app").Return(app, nil)
+			},
+		},
+		"get-schedule not set": {
+			Namespace:       "default",
+			Args:            []string{"get-schedule", "my-app"},
+			ExpectedStrings: []string{"No schedule set"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Get("default", "my-app").Return(&v1alpha1.App{}, nil)
+			},
+		},
+		"set-min-instances zero with wakeup": {
+			Namespace: "default",
+			Args:      []string{"set-min-instances", "my-app", "0", "--enable-wakeup", "--cold-start-timeout", "2m"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						exactly := 3
+						app := v1alpha1.App{}
+						app.Spec.Instances.Exactly = &exactly
+						testutil.AssertNil(t, "mutator error", mutator(&app))
+
+						instances := app.Spec.Instances
+						testutil.AssertEqual(t, "exactly", (*int)(nil), instances.Exactly)
+						testutil.AssertEqual(t, "min", 0, *instances.Min)
+						testutil.AssertEqual(t, "cold start timeout", int64(120), *instances.ColdStartTimeoutSeconds)
+					})
+			},
+		},
+		"set-min-instances raises max": {
+			Namespace: "default",
+			Args:      []string{"set-min-instances", "my-app", "5"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						max := 2
+						app := v1alpha1.App{}
+						app.Spec.Instances.Max = &max
+						testutil.AssertNil(t, "mutator error", mutator(&app))
+
+						instances := app.Spec.Instances
+						testutil.AssertEqual(t, "min", 5, *instances.Min)
+						testutil.AssertEqual(t, "max", 5, *instances.Max)
+						testutil.AssertEqual(t, "cold start timeout", (*int64)(nil), instances.ColdStartTimeoutSeconds)
+					})
+			},
+		},
+		"set-min-instances zero without wakeup": {
+			Namespace:   "default",
+			Args:        []string{"set-min-instances", "my-app", "0"},
+			ExpectedErr: errors.New("apps scaled to zero only start when they receive a request, set --enable-wakeup to allow it"),
+		},
+		"set-min-instances wakeup with nonzero min": {
+			Namespace:   "default",
+			Args:        []string{"set-min-instances", "my-app", "1", "--enable-wakeup"},
+			ExpectedErr: errors.New("--enable-wakeup can only be set when the minimum is 0"),
+		},
+		"set-min-instances invalid min": {
+			Namespace:   "default",
+			Args:        []string{"set-min-instances", "my-app", "some"},
+			ExpectedErr: errors.New(`invalid minimum instances "some", must be a non-negative integer`),
+		},
+		"set-min-instances invalid timeout": {
+			Namespace:   "default",
+			Args:        []string{"set-min-instances", "my-app", "0", "--enable-wakeup", "--cold-start-timeout", "10ms"},
+			ExpectedErr: errors.New("invalid cold start timeout 10ms, must be at least 1s"),
+		},
+		"set-restart-on-change": {
+			Namespace: "default",
+			Args:      []string{"set-restart-on-change", "my-app", "secret/db-creds", "ConfigMap/flags"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						app := v1alpha1.App{}
+						app.Spec.RestartOnChange = []v1alpha1.AppSpecRestartOnChange{
+							{Kind: v1alpha1.RestartOnChangeKindSecret, Name: "db-creds"},
+						}
+						testutil.AssertNil(t, "mutator error", mutator(&app))
+						testutil.AssertEqual(t, "app.spec.restartOnChange", []v1alpha1.AppSpecRestartOnChange{
+							{Kind: v1alpha1.RestartOnChangeKindSecret, Name: "db-creds"},
+							{Kind: v1alpha1.RestartOnChangeKindConfigMap, Name: "flags"},
+						}, app.Spec.RestartOnChange)
+					})
+			},
+		},
+		"set-restart-on-change invalid reference": {
+			Namespace:   "default",
+			Args:        []string{"set-restart-on-change", "my-app", "pod/some-pod"},
+			ExpectedErr: errors.New(`invalid reference "pod/some-pod", must be secret/NAME or configmap/NAME`),
+		},
+		"set-restart-on-change missing name": {
+			Namespace:   "default",
+			Args:        []string{"set-restart-on-change", "my-app", "secret/"},
+			ExpectedErr: errors.New(`invalid reference "secret/", must be secret/NAME or configmap/NAME`),
+		},
+		"unset-restart-on-change": {
+			Namespace: "default",
+			Args:      []string{"unset-restart-on-change", "my-app", "secret/db-creds"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						app := v1alpha1.App{}
+						app.Spec.RestartOnChange = []v1alpha1.AppSpecRestartOnChange{
+							{Kind: v1alpha1.RestartOnChangeKindSecret, Name: "db-creds"},
+							{Kind: v1alpha1.RestartOnChangeKindConfigMap, Name: "flags"},
+						}
+						testutil.AssertNil(t, "mutator error", mutator(&app))
+						testutil.AssertEqual(t, "app.spec.restartOnChange", []v1alpha1.AppSpecRestartOnChange{
+							{Kind: v1alpha1.RestartOnChangeKindConfigMap, Name: "flags"},
+						}, app.Spec.RestartOnChange)
+					})
+			},
+		},
+		"unset-restart-on-change all": {
+			Namespace: "default",
+			Args:      []string{"unset-restart-on-change", "my-app"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						app := v1alpha1.App{}
+						app.Spec.RestartOnChange = []v1alpha1.AppSpecRestartOnChange{
+							{Kind: v1alpha1.RestartOnChangeKindSecret, Name: "db-creds"},
+						}
+						testutil.AssertNil(t, "mutator error", mutator(&app))
+						testutil.AssertEqual(t, "app.spec.restartOnChange", ([]v1alpha1.AppSpecRestartOnChange)(nil), app.Spec.RestartOnChange)
+					})
+			},
+		},
+		"set-egress-ip-pool": {
+			Namespace: "default",
+			Args:      []string{"set-egress-ip-pool", "my-app", "partner-allowlist"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						app := v1alpha1.App{}
+						testutil.AssertNil(t, "mutator error", mutator(&app))
+						testutil.AssertEqual(t, "app.spec.egressIPPool", "partner-allowlist", app.Spec.EgressIPPool)
+					})
+			},
+		},
+		"set-egress-ip-pool missing pool": {
+			Namespace:   "default",
+			Args:        []string{"set-egress-ip-pool", "my-app"},
+			ExpectedErr: errors.New("accepts 2 arg(s), received 1"),
+		},
+		"unset-egress-ip-pool": {
+			Namespace: "default",
+			Args:      []string{"unset-egress-ip-pool", "my-app"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						app := v1alpha1.App{}
+						app.Spec.EgressIPPool = "partner-allowlist"
+						testutil.AssertNil(t, "mutator error", mutator(&app))
+						testutil.AssertEqual(t, "app.spec.egressIPPool", "", app.Spec.EgressIPPool)
+					})
+			},
+		},
+		"set-deletion-protection on": {
+			Namespace: "default",
+			Args:      []string{"set-deletion-protection", "my-app", "on"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						app := v1alpha1.App{}
+						testutil.AssertNil(t, "mutator error", mutator(&app))
+						testutil.AssertEqual(t, "protected", true, v1alpha1.IsDeletionProtected(&app))
+					})
+			},
+		},
+		"set-deletion-protection invalid": {
+			Namespace:   "default",
+			Args:        []string{"set-deletion-protection", "my-app", "maybe"},
+			ExpectedErr: errors.New(`setting must be on or off, got "maybe"`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fake := fake.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fake)
+			}
+
+			buf := new(bytes.Buffer)
+			p := &config.KfParams{
+				Namespace: tc.Namespace,
+			}
+
+			cmd := NewConfigureAppCommand(p, fake)
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+
+			ctrl.Finish()
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
@@ -28,7 +29,10 @@ import (
 
 // NewDeleteCommand creates a delete command.
 func NewDeleteCommand(p *config.KfParams, appsClient apps.Client) *cobra.Command {
-	var async utils.AsyncFlags
+	var (
+		async                utils.AsyncFlags
+		forceDeleteProtected bool
+	)
 
 	cmd := &cobra.Command{
 		Use:     "delete APP_NAME",
@@ -56,6 +60,9 @@ func NewDeleteCommand(p *config.KfParams, appsClient apps.Client) *cobra.Command
 		* there are still connections waiting to be served
 		* bindings fail to deprovision
 		* the cluster is in an unhealthy state
+
+		Apps with deletion protection enabled can only be deleted with
+		--force-delete-protected, which turns protection off first.
 		`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
@@ -67,6 +74,15 @@ func NewDeleteCommand(p *config.KfParams, appsClient apps.Client) *cobra.Command
 			// Cobra ensures we are only called with a single argument.
 			appName := args[0]
 
+			if forceDeleteProtected {
+				if _, err := appsClient.Transform(p.Namespace, appName, func(app *v1alpha1.App) error {
+					v1alpha1.SetDeletionProtection(app, false)
+					return nil
+				}); err != nil {
+					return fmt.Errorf("couldn't turn off deletion protection: %s", err)
+				}
+			}
+
 			if err := appsClient.DeleteInForeground(p.Namespace, appName); err != nil {
 				return err
 			}
@@ -83,6 +99,13 @@ func NewDeleteCommand(p *config.KfParams, appsClient apps.Client) *cobra.Command
 
 	async.Add(cmd)
 
+	cmd.Flags().BoolVar(
+		&forceDeleteProtected,
+		"force-delete-protected",
+		false,
+		"Turn off deletion protection and delete the app.",
+	)
+
 	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
 
 	return cmd
@@ -20,6 +20,8 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/apps/fake"
 	"github.com/google/kf/pkg/kf/commands/config"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
@@ -53,6 +55,33 @@ func TestDeleteCommand(t *testing.T) {
 				fc.EXPECT().DeleteInForeground("some-namespace", "some-app")
 			},
 		},
+		"force delete protected": {
+			namespace: "some-namespace",
+			args:      []string{"--async", "--force-delete-protected", "some-app"},
+
+			setup: func(t *testing.T, fc *fake.FakeClient) {
+				fc.EXPECT().
+					Transform("some-namespace", "some-app", gomock.Any()).
+					Do(func(namespace, name string, mutator apps.Mutator) {
+						app := &v1alpha1.App{}
+						v1alpha1.SetDeletionProtection(app, true)
+						testutil.AssertNil(t, "mutator err", mutator(app))
+						testutil.AssertEqual(t, "protected", false, v1alpha1.IsDeletionProtected(app))
+					})
+				fc.EXPECT().DeleteInForeground("some-namespace", "some-app")
+			},
+		},
+		"force delete protected error": {
+			namespace: "some-namespace",
+			args:      []string{"--force-delete-protected", "some-app"},
+
+			setup: func(t *testing.T, fc *fake.FakeClient) {
+				fc.EXPECT().
+					Transform(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("some error"))
+			},
+			wantErr: errors.New("couldn't turn off deletion protection: some error"),
+		},
 		"delete app error": {
 			namespace: "some-namespace",
 			args:      []string{"some-app"},
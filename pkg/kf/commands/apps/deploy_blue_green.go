@@ -0,0 +1,247 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/hooks"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+)
+
+// PushCommandFactory creates the push command a blue-green deployment pushes
+// the new version of an App with.
+type PushCommandFactory func() *cobra.Command
+
+// blueGreenBlockedPushFlags would stop a blue-green deployment from
+// controlling the new version's routes or from deploying it.
+var blueGreenBlockedPushFlags = []string{
+	"--route",
+	"--no-route",
+	"--random-route",
+	"--no-start",
+	"--dry-run",
+	"--strategy",
+}
+
+// NewDeployBlueGreenCommand creates a command that deploys a new version of an
+// App alongside the running one and swaps their routes once it's ready.
+func NewDeployBlueGreenCommand(
+	p *config.KfParams,
+	client apps.Client,
+	newPush PushCommandFactory,
+) *cobra.Command {
+	var (
+		smokeTests        []string
+		smokeTestHostname string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "deploy-blue-green APP_NAME [-- PUSH_FLAGS...]",
+		Short: "Push a new version of an app alongside the running one then swap their routes",
+		Example: `
+		kf deploy-blue-green myapp
+		kf deploy-blue-green myapp --smoke-test ./smoke-test.sh -- --path ./build --buildpack java_buildpack
+		`,
+		Long: `The deploy-blue-green command replaces an app without downtime.
+
+		The running version is copied to APP_NAME-venerable with the same
+		routes, image and service bindings, so it keeps serving while the
+		new version is pushed to APP_NAME with only a temporary route. Flags
+		after -- are passed to push, except for flags that change routes.
+
+		The smoke test hooks then run locally with SMOKE_TEST_URL set to
+		the temporary route. If they pass, the app's routes are moved from
+		APP_NAME-venerable to the new version, which serves them before the
+		copy stops, and the copy is deleted.
+
+		If the push or a smoke test fails, APP_NAME is rolled back to the
+		image it was running and the copy is deleted.
+		`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			if dash := cmd.ArgsLenAtDash(); dash > 1 || (dash == -1 && len(args) > 1) {
+				return fmt.Errorf("accepts 1 arg(s) before --, received %d", len(args))
+			}
+
+			appName := args[0]
+			pushArgs := args[1:]
+			for _, arg := range pushArgs {
+				for _, flag := range blueGreenBlockedPushFlags {
+					if arg == flag || strings.HasPrefix(arg, flag+"=") {
+						return fmt.Errorf("%s can't be passed to push, blue-green deployments manage the new version's routes", flag)
+					}
+				}
+			}
+
+			space, err := p.GetTargetSpaceOrDefault()
+			if err != nil {
+				return err
+			}
+
+			domain, err := spaceDefaultDomain(space)
+			if err != nil {
+				return err
+			}
+
+			if smokeTestHostname == "" {
+				smokeTestHostname = appName + "-smoke-test"
+			}
+
+			cmd.SilenceUsage = true
+
+			blue, err := client.Get(p.Namespace, appName)
+			if err != nil {
+				return err
+			}
+
+			if len(blue.Spec.Routes) == 0 {
+				return fmt.Errorf("%s has no routes to swap, use kf push to deploy it", appName)
+			}
+
+			venerable, err := apps.MakeVenerableApp(blue)
+			if err != nil {
+				return fmt.Errorf("%s can't be deployed blue-green: %s", appName, err)
+			}
+
+			existing, err := findApp(client, p.Namespace, venerable.Name)
+			if err != nil {
+				return err
+			}
+			if existing != nil {
+				return fmt.Errorf("%s already exists, delete it if a previous blue-green deployment of %s was interrupted", venerable.Name, appName)
+			}
+
+			d := &blueGreenDeployment{
+				out:       cmd.OutOrStdout(),
+				client:    client,
+				namespace: p.Namespace,
+				blue:      blue,
+				venerable: venerable.Name,
+			}
+
+			fmt.Fprintf(d.out, "Copying %s to %s to serve its routes during the deployment\n", appName, venerable.Name)
+			created, err := client.Create(p.Namespace, venerable)
+			if err != nil {
+				return err
+			}
+
+			if err := client.DeployLogsForApp(d.out, created); err != nil {
+				return d.deleteVenerable(fmt.Errorf("copying %s failed: %s", appName, err))
+			}
+
+			tempRoute := smokeTestHostname + "." + domain
+			fmt.Fprintf(d.out, "Pushing the new version of %s with the temporary route %s\n", appName, tempRoute)
+
+			push := newPush()
+			push.SetOutput(d.out)
+			push.SetIn(cmd.InOrStdin())
+			push.SetArgs(append(append([]string{appName}, pushArgs...), "--route", tempRoute))
+			push.SilenceErrors = true
+			if err := push.Execute(); err != nil {
+				return d.rollBack(err)
+			}
+
+			if len(smokeTests) > 0 {
+				env := map[string]string{"SMOKE_TEST_URL": "http://" + tempRoute}
+				if err := hooks.RunLocal(d.out, hooks.PhaseSmokeTest, ".", env, smokeTests); err != nil {
+					return d.rollBack(err)
+				}
+			}
+
+			fmt.Fprintf(d.out, "Moving routes from %s to %s\n", venerable.Name, appName)
+			if err := client.SwapRoutes(context.Background(), p.Namespace, venerable.Name, appName); err != nil {
+				return d.rollBack(err)
+			}
+
+			fmt.Fprintf(d.out, "Deleting %s\n", venerable.Name)
+			if err := client.DeleteInForeground(p.Namespace, venerable.Name); err != nil {
+				return fmt.Errorf("%s is deployed but %s couldn't be deleted: %s", appName, venerable.Name, err)
+			}
+
+			fmt.Fprintf(d.out, "%s is deployed\n", appName)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(
+		&smokeTests,
+		"smoke-test",
+		nil,
+		"Command run locally against the new version before it receives traffic, can be repeated. SMOKE_TEST_URL is set to its temporary route.",
+	)
+
+	cmd.Flags().StringVar(
+		&smokeTestHostname,
+		"smoke-test-hostname",
+		"",
+		"Hostname of the new version's temporary route on the space's default domain (default: APP_NAME-smoke-test)",
+	)
+
+	return cmd
+}
+
+// blueGreenDeployment undoes a failed blue-green deployment.
+type blueGreenDeployment struct {
+	out       io.Writer
+	client    apps.Client
+	namespace string
+	blue      *v1alpha1.App
+	venerable string
+}
+
+// rollBack restores the App to the version running before the deployment,
+// including its routes, then deletes the copy. The copy is kept if the App
+// isn't ready again so the routes are still served.
+func (d *blueGreenDeployment) rollBack(cause error) error {
+	mutator, err := hooks.Rollback(d.blue)
+	if err != nil {
+		return fmt.Errorf("%s, %s still serves the routes: %s", cause, d.venerable, err)
+	}
+
+	fmt.Fprintf(d.out, "Rolling back %s to %s\n", d.blue.Name, d.blue.Status.Image)
+	rolledBack, err := d.client.Transform(d.namespace, d.blue.Name, mutator)
+	if err != nil {
+		return fmt.Errorf("%s, failed to roll back so %s still serves the routes: %s", cause, d.venerable, err)
+	}
+
+	if err := d.client.DeployLogsForApp(d.out, rolledBack); err != nil {
+		return fmt.Errorf("%s, rolled back to %s but it isn't ready so %s still serves the routes: %s", cause, d.blue.Status.Image, d.venerable, err)
+	}
+
+	return d.deleteVenerable(fmt.Errorf("%s, rolled back to %s", cause, d.blue.Status.Image))
+}
+
+// deleteVenerable deletes the copy of the App. The returned error always
+// includes cause.
+func (d *blueGreenDeployment) deleteVenerable(cause error) error {
+	fmt.Fprintf(d.out, "Deleting %s\n", d.venerable)
+	if err := d.client.DeleteInForeground(d.namespace, d.venerable); err != nil {
+		return fmt.Errorf("%s, %s couldn't be deleted: %s", cause, d.venerable, err)
+	}
+
+	return cause
+}
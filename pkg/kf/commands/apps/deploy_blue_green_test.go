@@ -0,0 +1,199 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	appsfake "github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/spf13/cobra"
+)
+
+func TestDeployBlueGreen(t *testing.T) {
+	t.Parallel()
+
+	runningApp := func() *v1alpha1.App {
+		app := &v1alpha1.App{}
+		app.Name = "my-app"
+		app.Namespace = "some-namespace"
+		app.Spec.Routes = []v1alpha1.RouteSpecFields{{Hostname: "www", Domain: "example.com"}}
+		app.Status.Image = "gcr.io/my-app@sha256:old"
+		return app
+	}
+
+	expectVenerable := func(t *testing.T, fakeApps *appsfake.FakeClient) {
+		fakeApps.EXPECT().Get("some-namespace", "my-app").Return(runningApp(), nil)
+		fakeApps.EXPECT().List("some-namespace", gomock.Any()).Return(nil, nil)
+		fakeApps.EXPECT().
+			Create("some-namespace", gomock.Any()).
+			DoAndReturn(func(namespace string, app *v1alpha1.App, opts ...apps.CreateOption) (*v1alpha1.App, error) {
+				testutil.AssertEqual(t, "name", "my-app-venerable", app.Name)
+				testutil.AssertEqual(t, "routes", runningApp().Spec.Routes, app.Spec.Routes)
+				testutil.AssertEqual(t, "image", "gcr.io/my-app@sha256:old", app.Spec.Source.ContainerImage.Image)
+				return app, nil
+			})
+		fakeApps.EXPECT().DeployLogsForApp(gomock.Any(), gomock.Any())
+	}
+
+	expectRollback := func(t *testing.T, fakeApps *appsfake.FakeClient) {
+		fakeApps.EXPECT().
+			Transform("some-namespace", "my-app", gomock.Any()).
+			DoAndReturn(func(namespace, name string, mutator apps.Mutator) (*v1alpha1.App, error) {
+				app := &v1alpha1.App{}
+				testutil.AssertNil(t, "mutator err", mutator(app))
+				testutil.AssertEqual(t, "image", "gcr.io/my-app@sha256:old", app.Spec.Source.ContainerImage.Image)
+				testutil.AssertEqual(t, "routes", runningApp().Spec.Routes, app.Spec.Routes)
+				return app, nil
+			})
+		fakeApps.EXPECT().DeployLogsForApp(gomock.Any(), gomock.Any())
+		fakeApps.EXPECT().DeleteInForeground("some-namespace", "my-app-venerable")
+	}
+
+	cases := map[string]struct {
+		Args         []string
+		PushErr      error
+		Setup        func(t *testing.T, fakeApps *appsfake.FakeClient)
+		WantPushArgs []string
+		ExpectedErr  error
+		WantOutput   []string
+	}{
+		"too many args": {
+			Args:        []string{"my-app", "other-app"},
+			ExpectedErr: errors.New("accepts 1 arg(s) before --, received 2"),
+		},
+		"route flags can't be passed to push": {
+			Args:        []string{"my-app", "--", "--route=www.example.com"},
+			ExpectedErr: errors.New("--route can't be passed to push, blue-green deployments manage the new version's routes"),
+		},
+		"app without routes": {
+			Args: []string{"my-app"},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient) {
+				app := runningApp()
+				app.Spec.Routes = nil
+				fakeApps.EXPECT().Get("some-namespace", "my-app").Return(app, nil)
+			},
+			ExpectedErr: errors.New("my-app has no routes to swap, use kf push to deploy it"),
+		},
+		"interrupted deployment": {
+			Args: []string{"my-app"},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient) {
+				fakeApps.EXPECT().Get("some-namespace", "my-app").Return(runningApp(), nil)
+
+				venerable := v1alpha1.App{}
+				venerable.Name = "my-app-venerable"
+				fakeApps.EXPECT().List("some-namespace", gomock.Any()).Return([]v1alpha1.App{venerable}, nil)
+			},
+			ExpectedErr: errors.New("my-app-venerable already exists, delete it if a previous blue-green deployment of my-app was interrupted"),
+		},
+		"swaps routes": {
+			Args: []string{
+				"my-app",
+				"--smoke-test", `test "$SMOKE_TEST_URL" = http://my-app-smoke-test.example.com`,
+				"--",
+				"--path", "./build",
+			},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient) {
+				expectVenerable(t, fakeApps)
+				fakeApps.EXPECT().SwapRoutes(gomock.Any(), "some-namespace", "my-app-venerable", "my-app")
+				fakeApps.EXPECT().DeleteInForeground("some-namespace", "my-app-venerable")
+			},
+			WantPushArgs: []string{"my-app", "--path", "./build", "--route", "my-app-smoke-test.example.com"},
+			WantOutput: []string{
+				"Copying my-app to my-app-venerable",
+				"Running smoke-test hook",
+				"Moving routes from my-app-venerable to my-app",
+				"my-app is deployed",
+			},
+		},
+		"custom smoke test hostname": {
+			Args: []string{"my-app", "--smoke-test-hostname", "my-app-next"},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient) {
+				expectVenerable(t, fakeApps)
+				fakeApps.EXPECT().SwapRoutes(gomock.Any(), "some-namespace", "my-app-venerable", "my-app")
+				fakeApps.EXPECT().DeleteInForeground("some-namespace", "my-app-venerable")
+			},
+			WantPushArgs: []string{"my-app", "--route", "my-app-next.example.com"},
+		},
+		"failed push rolls back": {
+			Args:    []string{"my-app"},
+			PushErr: errors.New("build failed: some-error"),
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient) {
+				expectVenerable(t, fakeApps)
+				expectRollback(t, fakeApps)
+			},
+			ExpectedErr: errors.New("build failed: some-error, rolled back to gcr.io/my-app@sha256:old"),
+		},
+		"failed smoke test rolls back": {
+			Args: []string{"my-app", "--smoke-test", "false"},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient) {
+				expectVenerable(t, fakeApps)
+				expectRollback(t, fakeApps)
+			},
+			ExpectedErr: errors.New(`smoke-test hook "false" failed: exit status 1, rolled back to gcr.io/my-app@sha256:old`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeApps := appsfake.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeApps)
+			}
+
+			p := &config.KfParams{
+				Namespace: "some-namespace",
+			}
+			p.SetTargetSpaceToDefault()
+			p.TargetSpace.Spec.Execution.Domains = []v1alpha1.SpaceDomain{{Domain: "example.com", Default: true}}
+
+			var gotPushArgs []string
+			newPush := func() *cobra.Command {
+				return &cobra.Command{
+					Use:                "push",
+					DisableFlagParsing: true,
+					RunE: func(cmd *cobra.Command, args []string) error {
+						gotPushArgs = args
+						return tc.PushErr
+					},
+				}
+			}
+
+			buffer := new(bytes.Buffer)
+			cmd := NewDeployBlueGreenCommand(p, fakeApps, newPush)
+			cmd.SetOutput(buffer)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				ctrl.Finish()
+				return
+			}
+
+			testutil.AssertEqual(t, "push args", tc.WantPushArgs, gotPushArgs)
+			testutil.AssertContainsAll(t, buffer.String(), tc.WantOutput)
+
+			ctrl.Finish()
+		})
+	}
+}
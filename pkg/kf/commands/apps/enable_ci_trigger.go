@@ -0,0 +1,203 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/citrigger"
+	kfv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// ciTriggerReceiverURL is the in-cluster address of the optional receiver
+// installed from config/optional/ci-trigger-receiver.yaml.
+const ciTriggerReceiverURL = "http://ci-trigger-receiver.kf.svc.cluster.local"
+
+// NewEnableCITriggerCommand creates a command that redeploys an App whenever
+// its Git repository receives a push.
+func NewEnableCITriggerCommand(
+	p *config.KfParams,
+	client apps.Client,
+	triggers kfv1alpha1.GitTriggersGetter,
+	secrets typedcorev1.SecretsGetter,
+) *cobra.Command {
+	var (
+		repo   string
+		branch string
+		secret string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "enable-ci-trigger APP_NAME",
+		Short: "Rebuild and deploy an app when its Git repository receives a push",
+		Long: `Registers a GitTrigger that rebuilds the App from its Git repository
+whenever GitHub or GitLab reports a push to the configured branch.
+
+The repository root must contain the App source as it would be pushed with
+kf push. The webhook is served by the optional ci-trigger-receiver
+component, which must be reachable by the Git provider.`,
+		Example: `kf enable-ci-trigger myapp --repo https://github.com/org/myapp.git --secret s3cr3t`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			if repo == "" {
+				return errors.New("--repo is required")
+			}
+
+			if secret == "" {
+				return errors.New("--secret is required")
+			}
+
+			appName := args[0]
+
+			cmd.SilenceUsage = true
+
+			app, err := client.Get(p.Namespace, appName)
+			if err != nil {
+				return fmt.Errorf("failed to get app: %s", err)
+			}
+
+			if app.Spec.Source.IsContainerBuild() {
+				return fmt.Errorf("app %q is deployed from a container image and can't be built from Git", appName)
+			}
+
+			secretName := ciTriggerSecretName(appName)
+			if err := upsertCITriggerSecret(secrets, p.Namespace, secretName, secret); err != nil {
+				return fmt.Errorf("failed to store webhook secret: %s", err)
+			}
+
+			spec := v1alpha1.GitTriggerSpec{
+				AppName:    appName,
+				Repository: repo,
+				Branch:     branch,
+				SecretName: secretName,
+			}
+			if err := upsertGitTrigger(triggers, p.Namespace, appName, spec); err != nil {
+				return fmt.Errorf("failed to enable CI trigger: %s", err)
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Pushes to %s on %s will now redeploy %q.\n", branch, repo, appName)
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, "Configure a push webhook on the repository with:")
+			fmt.Fprintf(out, "  URL:    %s%s\n", ciTriggerReceiverURL, citrigger.WebhookPath(p.Namespace, appName))
+			fmt.Fprintln(out, "  Secret: the value passed to --secret")
+			fmt.Fprintln(out)
+			fmt.Fprintln(out, "The host must be replaced with the address the ci-trigger-receiver is exposed on.")
+			fmt.Fprintln(out, "GitHub webhooks must use the application/json content type.")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&repo,
+		"repo",
+		"",
+		"Git repository to build the app from.",
+	)
+
+	cmd.Flags().StringVar(
+		&branch,
+		"branch",
+		v1alpha1.DefaultGitTriggerBranch,
+		"Branch whose pushes trigger a redeploy.",
+	)
+
+	cmd.Flags().StringVar(
+		&secret,
+		"secret",
+		"",
+		"Shared secret used to authenticate webhook deliveries.",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+func ciTriggerSecretName(appName string) string {
+	return appName + "-ci-trigger"
+}
+
+func upsertCITriggerSecret(secrets typedcorev1.SecretsGetter, namespace, name, value string) error {
+	client := secrets.Secrets(namespace)
+
+	existing, err := client.Get(name, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		_, err = client.Create(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Data: map[string][]byte{
+				v1alpha1.GitTriggerSecretKey: []byte(value),
+			},
+		})
+		return err
+	case err != nil:
+		return err
+	}
+
+	toUpdate := existing.DeepCopy()
+	if toUpdate.Data == nil {
+		toUpdate.Data = make(map[string][]byte)
+	}
+	toUpdate.Data[v1alpha1.GitTriggerSecretKey] = []byte(value)
+	_, err = client.Update(toUpdate)
+	return err
+}
+
+func upsertGitTrigger(triggers kfv1alpha1.GitTriggersGetter, namespace, name string, spec v1alpha1.GitTriggerSpec) error {
+	client := triggers.GitTriggers(namespace)
+
+	existing, err := client.Get(name, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		_, err = client.Create(&v1alpha1.GitTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+			},
+			Spec: spec,
+		})
+		return err
+	case err != nil:
+		return err
+	}
+
+	// Keep the last pushed revision so re-enabling the trigger doesn't cause
+	// a spurious rebuild.
+	toUpdate := existing.DeepCopy()
+	spec.Revision = existing.Spec.Revision
+	toUpdate.Spec = spec
+	_, err = client.Update(toUpdate)
+	return err
+}
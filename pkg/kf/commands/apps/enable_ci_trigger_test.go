@@ -0,0 +1,166 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kffake "github.com/google/kf/pkg/client/clientset/versioned/fake"
+	"github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnableCITrigger(t *testing.T) {
+	t.Parallel()
+
+	existingTrigger := &v1alpha1.GitTrigger{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "default"},
+		Spec: v1alpha1.GitTriggerSpec{
+			AppName:    "my-app",
+			Repository: "https://example.com/old.git",
+			Branch:     "develop",
+			SecretName: "my-app-ci-trigger",
+			Revision:   "abc123",
+		},
+	}
+
+	existingSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-ci-trigger", Namespace: "default"},
+		Data:       map[string][]byte{v1alpha1.GitTriggerSecretKey: []byte("old")},
+	}
+
+	cases := map[string]struct {
+		Namespace       string
+		Args            []string
+		Triggers        []runtime.Object
+		Secrets         []runtime.Object
+		Setup           func(t *testing.T, fake *fake.FakeClient)
+		ExpectedStrings []string
+		ExpectedErr     error
+		ExpectedSpec    v1alpha1.GitTriggerSpec
+	}{
+		"creates trigger": {
+			Namespace: "default",
+			Args:      []string{"my-app", "--repo", "https://example.com/app.git", "--secret", "s3cr3t"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Get("default", "my-app").Return(&v1alpha1.App{}, nil)
+			},
+			ExpectedStrings: []string{
+				"Pushes to master on https://example.com/app.git",
+				"http://ci-trigger-receiver.kf.svc.cluster.local/default/my-app",
+			},
+			ExpectedSpec: v1alpha1.GitTriggerSpec{
+				AppName:    "my-app",
+				Repository: "https://example.com/app.git",
+				Branch:     "master",
+				SecretName: "my-app-ci-trigger",
+			},
+		},
+		"updates existing trigger": {
+			Namespace: "default",
+			Args:      []string{"my-app", "--repo", "https://example.com/app.git", "--secret", "s3cr3t", "--branch", "main"},
+			Triggers:  []runtime.Object{existingTrigger},
+			Secrets:   []runtime.Object{existingSecret},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Get("default", "my-app").Return(&v1alpha1.App{}, nil)
+			},
+			ExpectedSpec: v1alpha1.GitTriggerSpec{
+				AppName:    "my-app",
+				Repository: "https://example.com/app.git",
+				Branch:     "main",
+				SecretName: "my-app-ci-trigger",
+				Revision:   "abc123",
+			},
+		},
+		"missing repo": {
+			Namespace:   "default",
+			Args:        []string{"my-app", "--secret", "s3cr3t"},
+			ExpectedErr: errors.New("--repo is required"),
+		},
+		"missing secret": {
+			Namespace:   "default",
+			Args:        []string{"my-app", "--repo", "https://example.com/app.git"},
+			ExpectedErr: errors.New("--secret is required"),
+		},
+		"container app": {
+			Namespace:   "default",
+			Args:        []string{"my-app", "--repo", "https://example.com/app.git", "--secret", "s3cr3t"},
+			ExpectedErr: errors.New(`app "my-app" is deployed from a container image and can't be built from Git`),
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				app := &v1alpha1.App{}
+				app.Spec.Source.ContainerImage.Image = "nginx"
+				fake.EXPECT().Get("default", "my-app").Return(app, nil)
+			},
+		},
+		"get app fails": {
+			Namespace:   "default",
+			Args:        []string{"my-app", "--repo", "https://example.com/app.git", "--secret", "s3cr3t"},
+			ExpectedErr: errors.New("failed to get app: some-error"),
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Get("default", "my-app").Return(nil, errors.New("some-error"))
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeApps := fake.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeApps)
+			}
+
+			kfClient := kffake.NewSimpleClientset(tc.Triggers...).KfV1alpha1()
+			k8sClient := k8sfake.NewSimpleClientset(tc.Secrets...).CoreV1()
+
+			buf := new(bytes.Buffer)
+			p := &config.KfParams{
+				Namespace: tc.Namespace,
+			}
+
+			cmd := NewEnableCITriggerCommand(p, fakeApps, kfClient, k8sClient)
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+			testutil.AssertEqual(t, "SilenceUsage", true, cmd.SilenceUsage)
+
+			trigger, err := kfClient.GitTriggers("default").Get("my-app", metav1.GetOptions{})
+			testutil.AssertNil(t, "get trigger", err)
+			testutil.AssertEqual(t, "trigger spec", tc.ExpectedSpec, trigger.Spec)
+
+			secret, err := k8sClient.Secrets("default").Get("my-app-ci-trigger", metav1.GetOptions{})
+			testutil.AssertNil(t, "get secret", err)
+			testutil.AssertEqual(t, "secret", "s3cr3t", string(secret.Data[v1alpha1.GitTriggerSecretKey]))
+
+			ctrl.Finish()
+		})
+	}
+}
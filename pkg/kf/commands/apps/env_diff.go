@@ -0,0 +1,219 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/envutil"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/describe"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// unsetValue is shown in diffs for values that only exist in one space.
+const unsetValue = "<unset>"
+
+// NewEnvDiffCommand creates a command that compares the environment of an app
+// in two spaces.
+func NewEnvDiffCommand(
+	p *config.KfParams,
+	appsClient apps.Client,
+	spacesClient spaces.Client,
+) *cobra.Command {
+	var otherSpace string
+
+	cmd := &cobra.Command{
+		Use:     "env-diff APP_NAME --with-space OTHER_SPACE",
+		Short:   "Compare the environment of an app in two spaces",
+		Example: `kf env-diff myapp --space staging --with-space production`,
+		Args:    cobra.ExactArgs(1),
+		Long: `The env-diff command compares the environment an app runs with in
+		the targeted space to the same app in another space and prints the
+		differences. It's useful as a check before promoting an app.
+
+		The environment includes the space's environment variables, the
+		app's environment variables, which take precedence, and the names of
+		the app's service bindings mapped to the instances they bind.
+
+		Values read from Secrets and ConfigMaps aren't fetched, the reference
+		is compared instead.
+		`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			if otherSpace == "" {
+				return errors.New("--with-space is required")
+			}
+
+			appName := args[0]
+			cmd.SilenceUsage = true
+
+			env, bindings, err := resolvedAppEnv(appsClient, spacesClient, p.Namespace, appName)
+			if err != nil {
+				return err
+			}
+
+			otherEnv, otherBindings, err := resolvedAppEnv(appsClient, spacesClient, otherSpace, appName)
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			fmt.Fprintf(w, "Comparing app %s in space %s with space %s\n", appName, p.Namespace, otherSpace)
+
+			differences := printMapDiff(w, "Environment", p.Namespace, otherSpace, env, otherEnv)
+			differences += printMapDiff(w, "Service Bindings", p.Namespace, otherSpace, bindings, otherBindings)
+
+			if differences == 0 {
+				fmt.Fprintln(w, "No differences found")
+			} else {
+				fmt.Fprintf(w, "%d difference(s) found\n", differences)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&otherSpace,
+		"with-space",
+		"",
+		"Space to compare the app's environment with",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+// resolvedAppEnv gets the environment variables and service bindings an app
+// runs with in a space.
+func resolvedAppEnv(
+	appsClient apps.Client,
+	spacesClient spaces.Client,
+	namespace string,
+	appName string,
+) (env map[string]string, bindings map[string]string, err error) {
+	space, err := spacesClient.Get(namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't get space %s: %s", namespace, err)
+	}
+
+	app, err := appsClient.Get(namespace, appName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("couldn't get app %s in space %s: %s", appName, namespace, err)
+	}
+
+	env, bindings = appEnvironment(space, app)
+	return env, bindings, nil
+}
+
+// appEnvironment gets the environment variables and service bindings the app
+// runs with in the space.
+func appEnvironment(space *v1alpha1.Space, app *v1alpha1.App) (env map[string]string, bindings map[string]string) {
+	env = make(map[string]string)
+	for _, e := range envutil.OverrideEnvVars(space.Spec.Execution.Env, envutil.GetAppEnvVars(app)) {
+		env[e.Name] = envVarValue(e)
+	}
+
+	bindings = make(map[string]string)
+	for _, binding := range app.Spec.ServiceBindings {
+		bindings[bindingName(binding)] = binding.Instance
+	}
+
+	return env, bindings
+}
+
+// bindingName gets the name a binding is exposed to the app with.
+func bindingName(binding v1alpha1.AppSpecServiceBinding) string {
+	if binding.BindingName != "" {
+		return binding.BindingName
+	}
+
+	return binding.Instance
+}
+
+// envVarValue gets a comparable value for the variable. Values that are read
+// at runtime are shown as a reference to their source.
+func envVarValue(e corev1.EnvVar) string {
+	ref := e.ValueFrom
+	switch {
+	case ref == nil:
+		return e.Value
+	case ref.SecretKeyRef != nil:
+		return fmt.Sprintf("<from Secret %s key %s>", ref.SecretKeyRef.Name, ref.SecretKeyRef.Key)
+	case ref.ConfigMapKeyRef != nil:
+		return fmt.Sprintf("<from ConfigMap %s key %s>", ref.ConfigMapKeyRef.Name, ref.ConfigMapKeyRef.Key)
+	case ref.FieldRef != nil:
+		return fmt.Sprintf("<from field %s>", ref.FieldRef.FieldPath)
+	default:
+		return "<from resource>"
+	}
+}
+
+// printMapDiff writes a section with the keys whose values differ between the
+// two maps and returns the number of differences.
+func printMapDiff(w io.Writer, section, nameA, nameB string, a, b map[string]string) int {
+	keys := make(map[string]bool)
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+
+	var changed []string
+	for k := range keys {
+		valueA, okA := a[k]
+		valueB, okB := b[k]
+		if okA != okB || valueA != valueB {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+
+	describe.SectionWriter(w, section, func(w io.Writer) {
+		if len(changed) == 0 {
+			return
+		}
+
+		fmt.Fprintf(w, "NAME\t%s\t%s\n", nameA, nameB)
+		for _, k := range changed {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", k, mapValueOrUnset(a, k), mapValueOrUnset(b, k))
+		}
+	})
+
+	return len(changed)
+}
+
+func mapValueOrUnset(m map[string]string, key string) string {
+	if v, ok := m[key]; ok {
+		return v
+	}
+
+	return unsetValue
+}
@@ -0,0 +1,150 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	appsfake "github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	spacesfake "github.com/google/kf/pkg/kf/spaces/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestEnvDiff(t *testing.T) {
+	t.Parallel()
+
+	space := func(name string, env ...corev1.EnvVar) *v1alpha1.Space {
+		s := &v1alpha1.Space{}
+		s.Name = name
+		s.Spec.Execution.Env = env
+		return s
+	}
+
+	app := func(env []corev1.EnvVar, bindings ...v1alpha1.AppSpecServiceBinding) *v1alpha1.App {
+		a := &v1alpha1.App{}
+		a.Name = "my-app"
+		a.Spec.Template.Spec.Containers = []corev1.Container{{Env: env}}
+		a.Spec.ServiceBindings = bindings
+		return a
+	}
+
+	cases := map[string]struct {
+		Args        []string
+		Setup       func(t *testing.T, fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient)
+		ExpectedErr error
+		WantOutput  []string
+	}{
+		"missing with-space": {
+			Args:        []string{"my-app"},
+			ExpectedErr: errors.New("--with-space is required"),
+		},
+		"app missing in other space": {
+			Args: []string{"my-app", "--with-space", "prod"},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("dev").Return(space("dev"), nil)
+				fakeApps.EXPECT().Get("dev", "my-app").Return(app(nil), nil)
+				fakeSpaces.EXPECT().Get("prod").Return(space("prod"), nil)
+				fakeApps.EXPECT().Get("prod", "my-app").Return(nil, errors.New("not found"))
+			},
+			ExpectedErr: errors.New("couldn't get app my-app in space prod: not found"),
+		},
+		"no differences": {
+			Args: []string{"my-app", "--with-space", "prod"},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("dev").Return(space("dev", corev1.EnvVar{Name: "REGION", Value: "us"}), nil)
+				fakeApps.EXPECT().Get("dev", "my-app").Return(app(nil), nil)
+				fakeSpaces.EXPECT().Get("prod").Return(space("prod"), nil)
+				fakeApps.EXPECT().Get("prod", "my-app").Return(app([]corev1.EnvVar{{Name: "REGION", Value: "us"}}), nil)
+			},
+			WantOutput: []string{
+				"Comparing app my-app in space dev with space prod",
+				"Environment: <empty>",
+				"Service Bindings: <empty>",
+				"No differences found",
+			},
+		},
+		"differences": {
+			Args: []string{"my-app", "--with-space", "prod"},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("dev").Return(space("dev", corev1.EnvVar{Name: "LOG_LEVEL", Value: "info"}), nil)
+				fakeApps.EXPECT().Get("dev", "my-app").Return(app(
+					[]corev1.EnvVar{
+						{Name: "LOG_LEVEL", Value: "debug"},
+						{Name: "DEBUG_TOOLS", Value: "true"},
+					},
+					v1alpha1.AppSpecServiceBinding{Instance: "dev-db", BindingName: "db"},
+				), nil)
+				fakeSpaces.EXPECT().Get("prod").Return(space("prod", corev1.EnvVar{Name: "LOG_LEVEL", Value: "info"}), nil)
+				fakeApps.EXPECT().Get("prod", "my-app").Return(app(
+					[]corev1.EnvVar{
+						{Name: "API_KEY", ValueFrom: &corev1.EnvVarSource{
+							SecretKeyRef: &corev1.SecretKeySelector{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "creds"},
+								Key:                  "api-key",
+							},
+						}},
+					},
+					v1alpha1.AppSpecServiceBinding{Instance: "prod-db", BindingName: "db"},
+				), nil)
+			},
+			WantOutput: []string{
+				"Environment:",
+				"NAME", "dev", "prod",
+				"API_KEY", "<unset>", "<from Secret creds key api-key>",
+				"DEBUG_TOOLS", "true",
+				"LOG_LEVEL", "debug", "info",
+				"Service Bindings:",
+				"db", "dev-db", "prod-db",
+				"4 difference(s) found",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeApps := appsfake.NewFakeClient(ctrl)
+			fakeSpaces := spacesfake.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeApps, fakeSpaces)
+			}
+
+			p := &config.KfParams{
+				Namespace: "dev",
+			}
+
+			buffer := new(bytes.Buffer)
+			cmd := NewEnvDiffCommand(p, fakeApps, fakeSpaces)
+			cmd.SetOutput(buffer)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buffer.String(), tc.WantOutput)
+
+			ctrl.Finish()
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/buildpacks"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/describe"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+)
+
+// NewImageInfoCommand creates a command that prints where an app's image came
+// from.
+func NewImageInfoCommand(
+	p *config.KfParams,
+	appsClient apps.Client,
+	provenanceReader buildpacks.ProvenanceReader,
+) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "image-info APP_NAME",
+		Short: "Print the provenance of the image an app is running",
+		Long: `Reads the provenance labels Kf wrote on the app's image when it was
+		built: the space and app it was built for, the source it was built
+		from, the builder, the buildpacks and their versions and the version
+		of Kf that built it.
+
+		The image is read from the space's container registry using your
+		local Docker credentials.
+		`,
+		Example: `
+		kf image-info myapp
+		kf image-info myapp -o json
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if output != "" && output != "json" {
+				return fmt.Errorf("unsupported output format %q, only json is supported", output)
+			}
+
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			appName := args[0]
+			cmd.SilenceUsage = true
+
+			app, err := appsClient.Get(p.Namespace, appName)
+			if err != nil {
+				return err
+			}
+
+			image := app.Status.ImageReference()
+			if image == "" {
+				return fmt.Errorf("app %s hasn't been built yet", appName)
+			}
+
+			provenance, err := provenanceReader.Provenance(image)
+			if err != nil {
+				return fmt.Errorf("failed to read image %s: %s", image, err)
+			}
+
+			if output == "json" {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(provenance)
+			}
+
+			describeProvenance(cmd.OutOrStdout(), provenance)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		"",
+		"Output format, the only supported value is json",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+func describeProvenance(w io.Writer, provenance *buildpacks.Provenance) {
+	orUnknown := func(value string) string {
+		if value == "" {
+			return "<unknown>"
+		}
+		return value
+	}
+
+	describe.TabbedWriter(w, func(w io.Writer) {
+		fmt.Fprintf(w, "Image:\t%s\n", provenance.Image)
+		fmt.Fprintf(w, "Digest:\t%s\n", provenance.Digest)
+		fmt.Fprintf(w, "Space:\t%s\n", orUnknown(provenance.Space))
+		fmt.Fprintf(w, "App:\t%s\n", orUnknown(provenance.App))
+		fmt.Fprintf(w, "Source:\t%s\n", orUnknown(provenance.Source))
+		fmt.Fprintf(w, "Builder:\t%s\n", orUnknown(provenance.Builder))
+		fmt.Fprintf(w, "Kf Version:\t%s\n", orUnknown(provenance.KfVersion))
+	})
+
+	describe.SectionWriter(w, "Buildpacks", func(w io.Writer) {
+		if len(provenance.Buildpacks) == 0 {
+			return
+		}
+
+		fmt.Fprintln(w, "ID\tVersion")
+		for _, bp := range provenance.Buildpacks {
+			fmt.Fprintf(w, "%s\t%s\n", bp.ID, bp.Version)
+		}
+	})
+}
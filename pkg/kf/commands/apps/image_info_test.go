@@ -0,0 +1,162 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	appsfake "github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/buildpacks"
+	buildpacksfake "github.com/google/kf/pkg/kf/buildpacks/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestImageInfoCommand(t *testing.T) {
+	t.Parallel()
+
+	builtApp := func() *v1alpha1.App {
+		app := &v1alpha1.App{}
+		app.Name = "some-app"
+		app.Status.Image = "gcr.io/some-image:latest"
+		app.Status.ImageDigest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+		return app
+	}
+
+	provenance := &buildpacks.Provenance{
+		Image:     "gcr.io/some-image@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		Digest:    "sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		Space:     "some-namespace",
+		App:       "some-app",
+		Source:    "gcr.io/src-some-namespace-some-app:abc",
+		Builder:   "some-builder",
+		KfVersion: "v1.2.3",
+		Buildpacks: []buildpacks.BuildpackVersion{
+			{ID: "some-buildpack", Version: "1.0.0"},
+		},
+	}
+
+	cases := map[string]struct {
+		namespace string
+		args      []string
+		setup     func(t *testing.T, fa *appsfake.FakeClient, fp *buildpacksfake.FakeProvenanceReader)
+		wantErr   error
+		wantOut   []string
+	}{
+		"prints provenance": {
+			namespace: "some-namespace",
+			args:      []string{"some-app"},
+			setup: func(t *testing.T, fa *appsfake.FakeClient, fp *buildpacksfake.FakeProvenanceReader) {
+				fa.EXPECT().Get("some-namespace", "some-app").Return(builtApp(), nil)
+				fp.EXPECT().Provenance(provenance.Image).Return(provenance, nil)
+			},
+			wantOut: []string{
+				"Space:", "some-namespace",
+				"Source:", "gcr.io/src-some-namespace-some-app:abc",
+				"Builder:", "some-builder",
+				"Kf Version:", "v1.2.3",
+				"Buildpacks:", "some-buildpack", "1.0.0",
+			},
+		},
+		"prints json": {
+			namespace: "some-namespace",
+			args:      []string{"some-app", "-o", "json"},
+			setup: func(t *testing.T, fa *appsfake.FakeClient, fp *buildpacksfake.FakeProvenanceReader) {
+				fa.EXPECT().Get("some-namespace", "some-app").Return(builtApp(), nil)
+				fp.EXPECT().Provenance(gomock.Any()).Return(provenance, nil)
+			},
+			wantOut: []string{
+				`"kfVersion": "v1.2.3"`,
+				`"id": "some-buildpack"`,
+			},
+		},
+		"unknown labels": {
+			namespace: "some-namespace",
+			args:      []string{"some-app"},
+			setup: func(t *testing.T, fa *appsfake.FakeClient, fp *buildpacksfake.FakeProvenanceReader) {
+				fa.EXPECT().Get("some-namespace", "some-app").Return(builtApp(), nil)
+				fp.EXPECT().Provenance(gomock.Any()).Return(&buildpacks.Provenance{Image: "some-image"}, nil)
+			},
+			wantOut: []string{"Builder:", "<unknown>", "Buildpacks: <empty>"},
+		},
+		"app not built": {
+			namespace: "some-namespace",
+			args:      []string{"some-app"},
+			setup: func(t *testing.T, fa *appsfake.FakeClient, fp *buildpacksfake.FakeProvenanceReader) {
+				fa.EXPECT().Get("some-namespace", "some-app").Return(&v1alpha1.App{}, nil)
+			},
+			wantErr: errors.New("app some-app hasn't been built yet"),
+		},
+		"getting app fails": {
+			namespace: "some-namespace",
+			args:      []string{"some-app"},
+			setup: func(t *testing.T, fa *appsfake.FakeClient, fp *buildpacksfake.FakeProvenanceReader) {
+				fa.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("some-error"),
+		},
+		"reading image fails": {
+			namespace: "some-namespace",
+			args:      []string{"some-app"},
+			setup: func(t *testing.T, fa *appsfake.FakeClient, fp *buildpacksfake.FakeProvenanceReader) {
+				fa.EXPECT().Get(gomock.Any(), gomock.Any()).Return(builtApp(), nil)
+				fp.EXPECT().Provenance(gomock.Any()).Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("failed to read image " + provenance.Image + ": some-error"),
+		},
+		"unsupported output": {
+			namespace: "some-namespace",
+			args:      []string{"some-app", "-o", "yaml"},
+			wantErr:   errors.New(`unsupported output format "yaml", only json is supported`),
+		},
+		"bad namespace": {
+			args:    []string{"some-app"},
+			wantErr: errors.New(utils.EmptyNamespaceError),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeApps := appsfake.NewFakeClient(ctrl)
+			fakeProvenance := buildpacksfake.NewFakeProvenanceReader(ctrl)
+			if tc.setup != nil {
+				tc.setup(t, fakeApps, fakeProvenance)
+			}
+
+			buffer := &bytes.Buffer{}
+			c := NewImageInfoCommand(&config.KfParams{
+				Namespace: tc.namespace,
+			}, fakeApps, fakeProvenance)
+			c.SetOutput(buffer)
+			c.SetArgs(tc.args)
+			gotErr := c.Execute()
+
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buffer.String(), tc.wantOut)
+		})
+	}
+}
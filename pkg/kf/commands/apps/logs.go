@@ -34,6 +34,12 @@ func NewLogsCommand(p *config.KfParams, tailer logs.Tailer) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "logs APP_NAME",
 		Short: "Tail or show logs for an app",
+		Long: `Tail or show logs for an app.
+
+		When following, logs from new instances are picked up automatically as
+		pushes and restarts replace them. The first log from each new revision
+		of the app is preceded by a marker like "--- new revision 7 ---".
+		`,
 		Example: `
 		# Follow/tail the log stream
 		kf logs myapp
@@ -0,0 +1,172 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/istio"
+	"github.com/spf13/cobra"
+)
+
+// BrowserOpener opens a URL in the user's browser.
+type BrowserOpener func(url string) error
+
+// NewOpenCommand creates a command that opens an app's route in a browser.
+func NewOpenCommand(
+	p *config.KfParams,
+	appsClient apps.Client,
+	ingressLister istio.IngressLister,
+	openBrowser BrowserOpener,
+) *cobra.Command {
+	var (
+		printOnly bool
+		useProxy  bool
+		gateway   string
+		port      int
+		noStart   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "open APP_NAME",
+		Short: "Open an app's route in the default browser",
+		Example: `
+		kf open myapp
+		kf open myapp --print-only
+		kf open myapp --proxy --port 8080
+		`,
+		Long: `
+	This command opens the app's primary route in the default browser.
+
+	If the route's domain can't be resolved from this machine, for example
+	because the cluster uses a private domain, the app is opened through an
+	ephemeral local proxy to the cluster's gateway like "kf proxy". The proxy
+	runs until the command is interrupted. Use --proxy to choose explicitly.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			appName := args[0]
+
+			cmd.SilenceUsage = true
+
+			app, err := appsClient.Get(p.Namespace, appName)
+			if err != nil {
+				return err
+			}
+
+			url := app.Status.URL
+			if url == nil {
+				return fmt.Errorf("No route for app %s", appName)
+			}
+
+			w := cmd.OutOrStdout()
+
+			if printOnly {
+				fmt.Fprintln(w, url.String())
+				return nil
+			}
+
+			if !cmd.Flags().Changed("proxy") {
+				_, err := net.LookupHost(url.Host)
+				useProxy = err != nil
+			}
+
+			if !useProxy {
+				fmt.Fprintf(w, "Opening %s\n", url.String())
+				return openBrowser(url.String())
+			}
+
+			if gateway == "" {
+				fmt.Fprintln(w, "Autodetecting app gateway. Specify a custom gateway using the --gateway flag.")
+
+				ingress, err := istio.ExtractIngressFromList(ingressLister.ListIngresses())
+				if err != nil {
+					return err
+				}
+				gateway = ingress
+			}
+
+			listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
+			if err != nil {
+				return err
+			}
+			defer listener.Close()
+
+			localURL := fmt.Sprintf("http://%s%s", listener.Addr().String(), url.Path)
+			fmt.Fprintf(w, "Opening %s through a local proxy at %s\n", url.String(), localURL)
+
+			if noStart {
+				fmt.Fprintln(w, "exiting because no-start flag was provided")
+				return nil
+			}
+
+			if err := openBrowser(localURL); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(w, "Press Ctrl+C to stop the proxy.")
+			return http.Serve(listener, utils.CreateProxy(w, url.Host, gateway))
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&printOnly,
+		"print-only",
+		false,
+		"Print the app's URL instead of opening it",
+	)
+
+	cmd.Flags().BoolVar(
+		&useProxy,
+		"proxy",
+		false,
+		"Open the app through a local proxy (default: when the domain can't be resolved)",
+	)
+
+	cmd.Flags().StringVar(
+		&gateway,
+		"gateway",
+		"",
+		"HTTP gateway the proxy routes requests to (default: autodetected from cluster)",
+	)
+
+	cmd.Flags().IntVar(
+		&port,
+		"port",
+		0,
+		"Local port the proxy listens on (default: a random free port)",
+	)
+
+	cmd.Flags().BoolVar(
+		&noStart,
+		"no-start",
+		false,
+		"Exit before starting the proxy",
+	)
+	cmd.Flags().MarkHidden("no-start")
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
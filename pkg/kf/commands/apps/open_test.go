@@ -0,0 +1,156 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
+	fakeapps "github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/istio/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	serving "github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/apis"
+)
+
+func TestNewOpenCommand(t *testing.T) {
+	t.Parallel()
+
+	appWithURL := &v1alpha1.App{
+		Status: v1alpha1.AppStatus{
+			RouteStatusFields: serving.RouteStatusFields{
+				URL: &apis.URL{
+					Scheme: "http",
+					Host:   "my-app.example.com",
+					Path:   "/home",
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		Namespace       string
+		Args            []string
+		ExpectedStrings []string
+		ExpectedErr     error
+		ExpectedOpened  string
+		BrowserErr      error
+		Setup           func(t *testing.T, lister *fakeapps.FakeClient, istio *fake.FakeIstioClient)
+	}{
+		"no app name": {
+			Namespace:   "default",
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"no url for app": {
+			Namespace:   "default",
+			Args:        []string{"my-app"},
+			ExpectedErr: errors.New("No route for app my-app"),
+			Setup: func(t *testing.T, lister *fakeapps.FakeClient, istio *fake.FakeIstioClient) {
+				lister.EXPECT().Get("default", "my-app").Return(&v1alpha1.App{}, nil)
+			},
+		},
+		"print only": {
+			Namespace:       "default",
+			Args:            []string{"my-app", "--print-only"},
+			ExpectedStrings: []string{"http://my-app.example.com/home"},
+			Setup: func(t *testing.T, lister *fakeapps.FakeClient, istio *fake.FakeIstioClient) {
+				lister.EXPECT().Get("default", "my-app").Return(appWithURL, nil)
+			},
+		},
+		"opens route": {
+			Namespace:       "default",
+			Args:            []string{"my-app", "--proxy=false"},
+			ExpectedStrings: []string{"Opening http://my-app.example.com/home"},
+			ExpectedOpened:  "http://my-app.example.com/home",
+			Setup: func(t *testing.T, lister *fakeapps.FakeClient, istio *fake.FakeIstioClient) {
+				lister.EXPECT().Get("default", "my-app").Return(appWithURL, nil)
+			},
+		},
+		"browser failure": {
+			Namespace:   "default",
+			Args:        []string{"my-app", "--proxy=false"},
+			BrowserErr:  errors.New("no-browser"),
+			ExpectedErr: errors.New("no-browser"),
+			Setup: func(t *testing.T, lister *fakeapps.FakeClient, istio *fake.FakeIstioClient) {
+				lister.EXPECT().Get("default", "my-app").Return(appWithURL, nil)
+			},
+		},
+		"proxy": {
+			Namespace: "default",
+			Args:      []string{"my-app", "--proxy", "--no-start"},
+			ExpectedStrings: []string{
+				"Autodetecting app gateway",
+				"through a local proxy at http://127.0.0.1:",
+				"/home",
+			},
+			Setup: func(t *testing.T, lister *fakeapps.FakeClient, istio *fake.FakeIstioClient) {
+				lister.EXPECT().Get("default", "my-app").Return(appWithURL, nil)
+				istio.EXPECT().ListIngresses(gomock.Any()).Return([]corev1.LoadBalancerIngress{{IP: "8.8.8.8"}}, nil)
+			},
+		},
+		"proxy autodetect failure": {
+			Namespace:   "default",
+			Args:        []string{"my-app", "--proxy"},
+			ExpectedErr: errors.New("istio-failure"),
+			Setup: func(t *testing.T, lister *fakeapps.FakeClient, istio *fake.FakeIstioClient) {
+				lister.EXPECT().Get("default", "my-app").Return(appWithURL, nil)
+				istio.EXPECT().ListIngresses(gomock.Any()).Return(nil, errors.New("istio-failure"))
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeAppClient := fakeapps.NewFakeClient(ctrl)
+			fakeIstio := fake.NewFakeIstioClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeAppClient, fakeIstio)
+			}
+
+			var opened string
+			openBrowser := func(url string) error {
+				opened = url
+				return tc.BrowserErr
+			}
+
+			buf := new(bytes.Buffer)
+			p := &config.KfParams{
+				Namespace: tc.Namespace,
+			}
+
+			cmd := NewOpenCommand(p, fakeAppClient, fakeIstio, openBrowser)
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+			testutil.AssertEqual(t, "opened", tc.ExpectedOpened, opened)
+
+			ctrl.Finish()
+		})
+	}
+}
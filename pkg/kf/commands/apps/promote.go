@@ -0,0 +1,274 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/describe"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewPromoteCommand creates a command that copies an app's built image to
+// another space.
+func NewPromoteCommand(
+	p *config.KfParams,
+	appsClient apps.Client,
+	spacesClient spaces.Client,
+) *cobra.Command {
+	var (
+		fromSpace     string
+		toSpace       string
+		routeStrategy string
+		yes           bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "promote APP_NAME --to SPACE",
+		Short: "Promote the built image of an app to another space",
+		Example: `
+		kf promote myapp --from staging --to production
+		kf promote myapp --from staging --to production --route-strategy none --yes
+		`,
+		Args: cobra.ExactArgs(1),
+		Long: `The promote command deploys the exact image an app was built with to
+		the same app in another space, it isn't built again.
+
+		An app that already exists in the target space keeps its
+		environment variables, service bindings and scaling, only its image
+		changes. An app that doesn't exist is created with the source app's
+		configuration, except for service bindings which belong to a single
+		space.
+
+		Before promoting, the differences in environment and service
+		bindings between the two apps and the routes the promoted app will
+		have are printed for review. Confirm to continue or use --yes to
+		skip the confirmation in automation.
+
+		The route strategy picks the promoted app's routes:
+
+		  keep:    keep the routes of an existing app, new apps get the
+		           space's default route
+		  default: use the space's default route
+		  none:    remove all routes so the app can be checked before it
+		           receives traffic
+
+		The promotion is recorded in the kf.dev/last-promotion annotation
+		on both apps.
+		`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromSpace == "" {
+				if err := utils.ValidateNamespace(p); err != nil {
+					return err
+				}
+				fromSpace = p.Namespace
+			}
+
+			if toSpace == "" {
+				return errors.New("--to is required")
+			}
+
+			if toSpace == fromSpace {
+				return errors.New("--from and --to must be different spaces")
+			}
+
+			appName := args[0]
+			cmd.SilenceUsage = true
+
+			source, err := appsClient.Get(fromSpace, appName)
+			if err != nil {
+				return err
+			}
+
+			if source.Status.Image == "" {
+				return fmt.Errorf("app %s in space %s hasn't been built yet, there's no image to promote", appName, fromSpace)
+			}
+
+			sourceSpace, err := spacesClient.Get(fromSpace)
+			if err != nil {
+				return err
+			}
+
+			targetSpace, err := spacesClient.Get(toSpace)
+			if err != nil {
+				return err
+			}
+
+			target, err := findApp(appsClient, toSpace, appName)
+			if err != nil {
+				return err
+			}
+
+			var defaultDomain string
+			if usesDefaultRoute(routeStrategy, target) {
+				if defaultDomain, err = spaceDefaultDomain(targetSpace); err != nil {
+					return err
+				}
+			}
+
+			promotion := apps.Promotion{
+				FromSpace: fromSpace,
+				ToSpace:   toSpace,
+				Image:     source.Status.ImageReference(),
+				Time:      metav1.Now(),
+			}
+
+			promoted, err := apps.PromoteApp(source, target, promotion, routeStrategy, defaultDomain)
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			fmt.Fprintf(w, "Promoting app %s from space %s to space %s\n", appName, fromSpace, toSpace)
+			fmt.Fprintf(w, "Image: %s\n", promotion.Image)
+			if target == nil {
+				fmt.Fprintf(w, "The app doesn't exist in space %s and will be created without service bindings\n", toSpace)
+			}
+
+			env, bindings := appEnvironment(sourceSpace, source)
+			promotedEnv, promotedBindings := appEnvironment(targetSpace, promoted)
+			printMapDiff(w, "Environment", fromSpace, toSpace, env, promotedEnv)
+			printMapDiff(w, "Service Bindings", fromSpace, toSpace, bindings, promotedBindings)
+			describe.RouteSpecFieldsList(w, promoted.Spec.Routes)
+
+			if !yes {
+				confirmed, err := confirm(cmd.InOrStdin(), w, "Promote the app?")
+				if err != nil {
+					return err
+				}
+
+				if !confirmed {
+					fmt.Fprintln(w, "Promotion cancelled")
+					return nil
+				}
+			}
+
+			var result *v1alpha1.App
+			if target == nil {
+				result, err = appsClient.Create(toSpace, promoted)
+			} else {
+				result, err = appsClient.Update(toSpace, promoted)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to promote app: %s", err)
+			}
+
+			if _, err := appsClient.Transform(fromSpace, appName, func(app *v1alpha1.App) error {
+				return apps.RecordPromotion(app, promotion)
+			}); err != nil {
+				return fmt.Errorf("app was promoted but the promotion couldn't be recorded on the source app: %s", err)
+			}
+
+			if err := appsClient.DeployLogsForApp(w, result); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(w, "%q successfully promoted to space %s\n", appName, toSpace)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&fromSpace,
+		"from",
+		"",
+		"Space to promote the app from, defaults to the targeted space",
+	)
+
+	cmd.Flags().StringVar(
+		&toSpace,
+		"to",
+		"",
+		"Space to promote the app to",
+	)
+
+	cmd.Flags().StringVar(
+		&routeStrategy,
+		"route-strategy",
+		apps.RouteStrategyKeep,
+		fmt.Sprintf("How the promoted app's routes are picked, one of %s", strings.Join(apps.RouteStrategies, ", ")),
+	)
+
+	cmd.Flags().BoolVar(
+		&yes,
+		"yes",
+		false,
+		"Promote without asking for confirmation",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+// usesDefaultRoute returns true if the promoted app will get the space's
+// default route.
+func usesDefaultRoute(routeStrategy string, target *v1alpha1.App) bool {
+	switch routeStrategy {
+	case apps.RouteStrategyDefault:
+		return true
+	case apps.RouteStrategyKeep:
+		return target == nil
+	default:
+		return false
+	}
+}
+
+// findApp gets the app with the given name or nil if it doesn't exist.
+func findApp(appsClient apps.Client, namespace, name string) (*v1alpha1.App, error) {
+	existing, err := appsClient.List(namespace, apps.WithListFieldSelector(map[string]string{"metadata.name": name}))
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range existing {
+		if existing[i].Name == name {
+			return &existing[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// confirm asks a yes or no question, any answer other than yes is a no.
+func confirm(in io.Reader, out io.Writer, question string) (bool, error) {
+	fmt.Fprintf(out, "%s (y/n): ", question)
+
+	scanner := bufio.NewScanner(in)
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return false, err
+		}
+		return false, errors.New("no answer was given, use --yes to skip the confirmation")
+	}
+
+	switch strings.ToLower(strings.TrimSpace(scanner.Text())) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
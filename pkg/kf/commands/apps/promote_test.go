@@ -0,0 +1,207 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	appsfake "github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	spacesfake "github.com/google/kf/pkg/kf/spaces/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPromote(t *testing.T) {
+	t.Parallel()
+
+	space := func(name, domain string) *v1alpha1.Space {
+		s := &v1alpha1.Space{}
+		s.Name = name
+		s.Spec.Execution.Domains = []v1alpha1.SpaceDomain{{Domain: domain, Default: true}}
+		return s
+	}
+
+	sourceApp := func() *v1alpha1.App {
+		app := &v1alpha1.App{}
+		app.Name = "my-app"
+		app.Namespace = "staging"
+		app.Spec.Template.Spec.Containers = []corev1.Container{
+			{Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}},
+		}
+		app.Status.Image = "gcr.io/my-app@sha256:abc"
+		return app
+	}
+
+	targetApp := func() *v1alpha1.App {
+		app := &v1alpha1.App{}
+		app.Name = "my-app"
+		app.Namespace = "production"
+		app.Spec.Template.Spec.Containers = []corev1.Container{
+			{Env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "info"}}},
+		}
+		app.Spec.Routes = []v1alpha1.RouteSpecFields{{Hostname: "www", Domain: "example.com"}}
+		return app
+	}
+
+	expectLookups := func(fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient, target *v1alpha1.App) {
+		fakeApps.EXPECT().Get("staging", "my-app").Return(sourceApp(), nil)
+		fakeSpaces.EXPECT().Get("staging").Return(space("staging", "staging.example.com"), nil)
+		fakeSpaces.EXPECT().Get("production").Return(space("production", "example.com"), nil)
+
+		var existing []v1alpha1.App
+		if target != nil {
+			existing = append(existing, *target)
+		}
+		fakeApps.EXPECT().List("production", gomock.Any()).Return(existing, nil)
+	}
+
+	expectRecorded := func(t *testing.T, fakeApps *appsfake.FakeClient) {
+		fakeApps.EXPECT().
+			Transform("staging", "my-app", gomock.Any()).
+			DoAndReturn(func(namespace, name string, mutator apps.Mutator) (*v1alpha1.App, error) {
+				app := sourceApp()
+				testutil.AssertNil(t, "mutator err", mutator(app))
+				testutil.AssertContainsAll(t, app.Annotations[v1alpha1.PromotionAnnotation], []string{
+					`"fromSpace":"staging"`,
+					`"toSpace":"production"`,
+				})
+				return app, nil
+			})
+		fakeApps.EXPECT().DeployLogsForApp(gomock.Any(), gomock.Any())
+	}
+
+	cases := map[string]struct {
+		Args        []string
+		Stdin       string
+		Setup       func(t *testing.T, fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient)
+		ExpectedErr error
+		WantOutput  []string
+	}{
+		"missing to": {
+			Args:        []string{"my-app", "--from", "staging"},
+			ExpectedErr: errors.New("--to is required"),
+		},
+		"same space": {
+			Args:        []string{"my-app", "--to", "staging"},
+			ExpectedErr: errors.New("--from and --to must be different spaces"),
+		},
+		"not built": {
+			Args: []string{"my-app", "--from", "staging", "--to", "production"},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient) {
+				fakeApps.EXPECT().Get("staging", "my-app").Return(&v1alpha1.App{}, nil)
+			},
+			ExpectedErr: errors.New("app my-app in space staging hasn't been built yet, there's no image to promote"),
+		},
+		"cancelled": {
+			Args:  []string{"my-app", "--from", "staging", "--to", "production"},
+			Stdin: "n\n",
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient) {
+				expectLookups(fakeApps, fakeSpaces, targetApp())
+			},
+			WantOutput: []string{
+				"Promoting app my-app from space staging to space production",
+				"Image: gcr.io/my-app@sha256:abc",
+				"LOG_LEVEL", "debug", "info",
+				"Promote the app? (y/n)",
+				"Promotion cancelled",
+			},
+		},
+		"no answer": {
+			Args: []string{"my-app", "--from", "staging", "--to", "production"},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient) {
+				expectLookups(fakeApps, fakeSpaces, targetApp())
+			},
+			ExpectedErr: errors.New("no answer was given, use --yes to skip the confirmation"),
+		},
+		"existing app confirmed": {
+			Args:  []string{"my-app", "--from", "staging", "--to", "production"},
+			Stdin: "y\n",
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient) {
+				expectLookups(fakeApps, fakeSpaces, targetApp())
+				fakeApps.EXPECT().
+					Update("production", gomock.Any()).
+					DoAndReturn(func(namespace string, app *v1alpha1.App, opts ...apps.UpdateOption) (*v1alpha1.App, error) {
+						testutil.AssertEqual(t, "image", "gcr.io/my-app@sha256:abc", app.Spec.Source.ContainerImage.Image)
+						testutil.AssertEqual(t, "routes", targetApp().Spec.Routes, app.Spec.Routes)
+						testutil.AssertEqual(t, "env", "info", app.Spec.Template.Spec.Containers[0].Env[0].Value)
+						return app, nil
+					})
+				expectRecorded(t, fakeApps)
+			},
+			WantOutput: []string{
+				"www.example.com",
+				`"my-app" successfully promoted to space production`,
+			},
+		},
+		"new app with yes": {
+			Args: []string{"my-app", "--to", "production", "--yes", "--route-strategy", "default"},
+			Setup: func(t *testing.T, fakeApps *appsfake.FakeClient, fakeSpaces *spacesfake.FakeClient) {
+				expectLookups(fakeApps, fakeSpaces, nil)
+				fakeApps.EXPECT().
+					Create("production", gomock.Any()).
+					DoAndReturn(func(namespace string, app *v1alpha1.App, opts ...apps.CreateOption) (*v1alpha1.App, error) {
+						testutil.AssertEqual(t, "routes", []v1alpha1.RouteSpecFields{
+							{Hostname: "my-app", Domain: "example.com"},
+						}, app.Spec.Routes)
+						testutil.AssertEqual(t, "env", "debug", app.Spec.Template.Spec.Containers[0].Env[0].Value)
+						return app, nil
+					})
+				expectRecorded(t, fakeApps)
+			},
+			WantOutput: []string{
+				"The app doesn't exist in space production and will be created without service bindings",
+				`"my-app" successfully promoted to space production`,
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeApps := appsfake.NewFakeClient(ctrl)
+			fakeSpaces := spacesfake.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeApps, fakeSpaces)
+			}
+
+			p := &config.KfParams{
+				Namespace: "staging",
+			}
+
+			buffer := new(bytes.Buffer)
+			cmd := NewPromoteCommand(p, fakeApps, fakeSpaces)
+			cmd.SetOutput(buffer)
+			cmd.SetIn(strings.NewReader(tc.Stdin))
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buffer.String(), tc.WantOutput)
+
+			ctrl.Finish()
+		})
+	}
+}
@@ -18,28 +18,40 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"io"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/internal/envutil"
 	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/failures"
+	"github.com/google/kf/pkg/kf/hooks"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/machine"
 	"github.com/google/kf/pkg/kf/manifest"
+	"github.com/google/kf/pkg/kf/procfile"
 	servicebindings "github.com/google/kf/pkg/kf/service-bindings"
+	"github.com/google/kf/pkg/kf/sourceimage"
+	"github.com/google/kf/pkg/kf/stacks"
+	"github.com/google/kf/pkg/kf/tracing"
 	ignore "github.com/sabhiram/go-gitignore"
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"knative.dev/pkg/ptr"
 )
 
 // SrcImageBuilder creates and uploads a container image that contains the
-// contents of the argument 'dir'.
+// contents of the argument 'dir'. Progress is written to out and the upload
+// stops when ctx is done.
 type SrcImageBuilder interface {
-	BuildSrcImage(dir, srcImage string, filter KontextFilter) error
+	BuildSrcImage(ctx context.Context, out io.Writer, dir, srcImage string, filter KontextFilter) error
 }
 
 // KontextFilter is used to select which files should be packaged into the
@@ -47,26 +59,23 @@ type SrcImageBuilder interface {
 type KontextFilter = func(path string) (bool, error)
 
 // SrcImageBuilderFunc converts a func into a SrcImageBuilder.
-type SrcImageBuilderFunc func(dir, srcImage string, rebase bool, filter KontextFilter) error
+type SrcImageBuilderFunc func(ctx context.Context, out io.Writer, dir, srcImage string, filter KontextFilter) error
 
 // BuildSrcImage implements SrcImageBuilder.
-func (f SrcImageBuilderFunc) BuildSrcImage(dir, srcImage string, filter KontextFilter) error {
-	oldPrefix := log.Prefix()
-	oldFlags := log.Flags()
-
-	log.SetPrefix("\033[32m[source upload]\033[0m ")
-	log.SetFlags(0)
-	log.SetOutput(os.Stdout)
-
-	log.Printf("Uploading %s to image %s", dir, srcImage)
-	err := f(dir, srcImage, false, filter)
+func (f SrcImageBuilderFunc) BuildSrcImage(ctx context.Context, out io.Writer, dir, srcImage string, filter KontextFilter) error {
+	return f(ctx, out, dir, srcImage, filter)
+}
 
-	log.SetPrefix(oldPrefix)
-	log.SetFlags(oldFlags)
-	log.SetOutput(os.Stderr)
+// The phase and completion percentages of source uploads reported in machine
+// mode, the rest of the push is reported by the apps client.
+const (
+	phaseUpload          = "upload"
+	percentUploadStarted = 0
+	percentUploaded      = 20
+)
 
-	return err
-}
+// TracingConfigLoader reads the tracing configuration pushes are traced with.
+type TracingConfigLoader func() (*tracing.Config, error)
 
 // NewPushCommand creates a push command.
 func NewPushCommand(
@@ -75,7 +84,13 @@ func NewPushCommand(
 	pusher apps.Pusher,
 	b SrcImageBuilder,
 	serviceBindingClient servicebindings.ClientInterface,
+	tasks hooks.TaskRunner,
+	stacksClient stacks.Client,
+	loadTracingConfig TracingConfigLoader,
+	explainer failures.Explainer,
 ) *cobra.Command {
+	printFlags := genericclioptions.NewPrintFlags("")
+
 	var (
 		containerRegistry   string
 		sourceImage         string
@@ -86,22 +101,45 @@ func NewPushCommand(
 		minScale            int
 		maxScale            int
 		path                string
+		artifact            string
+		staticSite          string
 		buildpack           string
 		stack               string
 		envs                []string
+		buildArgs           []string
+		secretBuildArgs     []string
 		enableHTTP2         bool
 		noManifest          bool
+		interactive         bool
 		noStart             bool
+		forceBuild          bool
+		noHooks             bool
+		dryRun              bool
 		healthCheckType     string
 		healthCheckTimeout  int
+		healthCheckExpect   string
 		startupCommand      string
 		containerEntrypoint string
 		containerArgs       []string
+		bindingFormat       string
+
+		// Timeout Flags
+		uploadTimeout time.Duration
+		buildTimeout  time.Duration
+		deployTimeout time.Duration
+		routeTimeout  time.Duration
+
+		// Rollout Flags
+		strategy     string
+		stepPercent  int
+		stepInterval time.Duration
 
 		// Route Flags
 		rawRoutes         []string
 		noRoute           bool
 		randomRouteDomain bool
+
+		sourceUpload SourceUploadFlags
 	)
 
 	var pushCmd = &cobra.Command{
@@ -112,18 +150,61 @@ func NewPushCommand(
   kf push myapp --buildpack my.special.buildpack # Discover via kf buildpacks
   kf push myapp --env FOO=bar --env BAZ=foo
   kf push myapp --stack cloudfoundry/cflinuxfs3 # Use a cflinuxfs3 runtime
+  kf push myapp --source-image gcr.io/my-project/src:abc123 # Use source packaged by CI
+  kf push myapp --artifact target/myapp.jar # Stage a prebuilt JAR, WAR or ZIP
+  kf push mysite --static ./public # Serve a directory of static files with nginx
+  kf push myapp --dockerfile Dockerfile --build-arg VERSION=1.2.3 --build-arg-from-secret NPM_TOKEN=npm-creds:token
+  kf push --interactive # Answer prompts to configure the app and save a manifest
+  kf push myapp --build-timeout 15m --deploy-timeout 5m # Fail fast if a phase stalls
+  kf push myapp --no-hooks # Skip the hooks and tasks in the manifest
+  kf push myapp --strategy rolling --step-percent 25 --step-interval 2m # Shift traffic to the new version gradually
+  kf push myapp --dry-run -o yaml # Print the resources that would be applied
   `,
 		Args: cobra.MaximumNArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			if err := utils.ValidateNamespace(p); err != nil {
 				return err
 			}
 
+			// Tracing is best effort, a missing or broken configuration shouldn't
+			// stop the push.
+			if tracingConfig, err := loadTracingConfig(); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: couldn't load the tracing configuration: %s\n", err)
+			} else if err := tracing.Setup("kf-cli", tracingConfig); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: couldn't set up tracing: %s\n", err)
+			}
+			defer tracing.Flush()
+
+			ctx, span := tracing.StartSpan(context.Background(), "kf push")
+			defer func() { tracing.EndSpan(span, err) }()
+
 			space, err := p.GetTargetSpaceOrDefault()
 			if err != nil {
 				return err
 			}
 
+			if printFlags.OutputFlagSpecified() && !dryRun {
+				return errors.New("--output can only be used with --dry-run")
+			}
+
+			switch strategy {
+			case pushStrategyDefault:
+				for _, flag := range []string{"step-percent", "step-interval"} {
+					if cmd.Flags().Lookup(flag).Changed {
+						return fmt.Errorf("--%s can only be used with --strategy %s", flag, pushStrategyRolling)
+					}
+				}
+			case pushStrategyRolling:
+				if stepPercent < 1 || stepPercent > 100 {
+					return errors.New("--step-percent must be between 1 and 100")
+				}
+				if stepInterval < 0 {
+					return errors.New("--step-interval can't be negative")
+				}
+			default:
+				return fmt.Errorf("--strategy must be %s or %s", pushStrategyDefault, pushStrategyRolling)
+			}
+
 			cmd.SilenceUsage = true
 
 			appName := ""
@@ -133,6 +214,28 @@ func NewPushCommand(
 
 			var pushManifest *manifest.Manifest
 			switch {
+			case interactive:
+				if manifestFile != "" {
+					return errors.New("--interactive can't be used with --manifest")
+				}
+
+				if !noManifest {
+					existing, err := manifest.CheckForManifest(path)
+					if err != nil {
+						return fmt.Errorf("error checking directory %s for manifest file: %v", path, err)
+					}
+
+					if existing != nil {
+						return errors.New("a manifest already exists, use --no-manifest to ignore it and push interactively")
+					}
+				}
+
+				if pushManifest, err = pushInteractively(cmd.InOrStdin(), cmd.OutOrStdout(), path, appName); err != nil {
+					return err
+				}
+
+				// The user may have chosen a different name in the prompts.
+				appName = pushManifest.Applications[0].Name
 			case noManifest:
 				if pushManifest, err = manifest.New(appName); err != nil {
 					return err
@@ -142,8 +245,11 @@ func NewPushCommand(
 					return fmt.Errorf("supplied manifest file %s resulted in error: %v", manifestFile, err)
 				}
 			default:
-				if pushManifest, err = manifest.CheckForManifest(path); err != nil {
-					return fmt.Errorf("error checking directory %s for manifest file: %v", path, err)
+				// Artifacts pushed with --path can't contain a manifest.
+				if !isArtifact(path) {
+					if pushManifest, err = manifest.CheckForManifest(path); err != nil {
+						return fmt.Errorf("error checking directory %s for manifest file: %v", path, err)
+					}
 				}
 
 				if pushManifest == nil {
@@ -180,6 +286,14 @@ func NewPushCommand(
 				}
 				overrides.Env = envutil.EnvVarsToMap(envVars)
 
+				if overrides.Docker.BuildArgs, err = parseBuildArgs(buildArgs); err != nil {
+					return err
+				}
+
+				if overrides.Docker.BuildArgsFromSecrets, err = parseBuildArgs(secretBuildArgs); err != nil {
+					return err
+				}
+
 				if buildpack != "" {
 					overrides.Buildpacks = []string{buildpack}
 				}
@@ -190,6 +304,12 @@ func NewPushCommand(
 					overrides.HealthCheckType = healthCheckType
 				}
 
+				overrides.HealthCheckExpect = healthCheckExpect
+
+				if noRoute && len(rawRoutes) > 0 {
+					return errors.New("--no-route can't be used with --route")
+				}
+
 				if len(rawRoutes) > 0 {
 					overrides.Routes = nil
 					for _, rr := range rawRoutes {
@@ -231,6 +351,26 @@ func NewPushCommand(
 				if cmd.Flags().Lookup("no-start").Changed {
 					overrides.NoStart = ptr.Bool(noStart)
 				}
+
+				if cmd.Flags().Lookup("binding-format").Changed {
+					overrides.BindingFormat = bindingFormat
+				}
+
+				if cmd.Flags().Lookup("upload-timeout").Changed {
+					overrides.PushTimeouts.Upload = uploadTimeout.String()
+				}
+
+				if cmd.Flags().Lookup("build-timeout").Changed {
+					overrides.PushTimeouts.Build = buildTimeout.String()
+				}
+
+				if cmd.Flags().Lookup("deploy-timeout").Changed {
+					overrides.PushTimeouts.Deploy = deployTimeout.String()
+				}
+
+				if cmd.Flags().Lookup("route-timeout").Changed {
+					overrides.PushTimeouts.Route = routeTimeout.String()
+				}
 			}
 
 			for _, app := range appsToDeploy {
@@ -253,6 +393,16 @@ func NewPushCommand(
 					return err
 				}
 
+				dockerfileBuildArgs, err := app.DockerfileBuildArgs()
+				if err != nil {
+					return err
+				}
+
+				timeouts, err := pushTimeouts(app.PushTimeouts)
+				if err != nil {
+					return err
+				}
+
 				defaultDomain, err := spaceDefaultDomain(space)
 				if err != nil {
 					return err
@@ -263,33 +413,53 @@ func NewPushCommand(
 					return err
 				}
 
-				healthCheck, err := apps.NewHealthCheck(app.HealthCheckType, app.HealthCheckHTTPEndpoint, app.HealthCheckTimeout)
+				applySpaceHealthCheckDefaults(space, &app)
+
+				// Dry runs don't run anything locally or in the cluster.
+				runHooks := !noHooks && !dryRun
+				env := hookEnv(space, p.Namespace, app)
+				if runHooks && len(app.Hooks.PrePush) > 0 {
+					if err := hooks.RunLocal(cmd.OutOrStdout(), hooks.PhasePrePush, path, env, app.Hooks.PrePush); err != nil {
+						return err
+					}
+				}
+
+				healthCheck, err := apps.NewHealthCheck(app.HealthCheckType, app.HealthCheckHTTPEndpoint, app.HealthCheckExpect, app.HealthCheckTimeout)
 				if err != nil {
 					return err
 				}
 
-				var randomRouteDomain string
-				if app.RandomRoute != nil && *app.RandomRoute {
-					randomRouteDomain = defaultDomain
+				// A Procfile in the source defines the App's process types,
+				// the web process is used if the App has no start command.
+				var processes []v1alpha1.AppSpecProcess
+				if app.Docker.Image == "" && sourceImage == "" {
+					if processes, err = readProcfile(filepath.Join(path, app.Path)); err != nil {
+						return err
+					}
 				}
 
-				var defaultRouteDomain string
-				if len(routes) == 0 && randomRouteDomain == "" && (app.NoRoute == nil || !*app.NoRoute) {
-					defaultRouteDomain = defaultDomain
+				commandArgs := app.CommandArgs()
+				for _, process := range processes {
+					if process.Type == v1alpha1.ProcessTypeWeb && commandArgs == nil {
+						commandArgs = []string{process.Command}
+					}
 				}
 
 				pushOpts := []apps.PushOption{
 					apps.WithPushNamespace(p.Namespace),
 					apps.WithPushEnvironmentVariables(app.Env),
-					apps.WithPushRoutes(routes),
 					apps.WithPushHealthCheck(healthCheck),
-					apps.WithPushRandomRouteDomain(randomRouteDomain),
-					apps.WithPushDefaultRouteDomain(defaultRouteDomain),
 					apps.WithPushCommand(app.CommandEntrypoint()),
-					apps.WithPushArgs(app.CommandArgs()),
+					apps.WithPushArgs(commandArgs),
+					apps.WithPushProcesses(processes),
 					apps.WithPushResourceRequests(resourceRequests),
 					apps.WithPushAppSpecInstances(app.ToAppSpecInstances()),
+					apps.WithPushForceBuild(forceBuild),
+					apps.WithPushBindingFormat(app.BindingFormat),
+					apps.WithPushTimeouts(timeouts),
+					apps.WithPushContext(ctx),
 				}
+				pushOpts = append(pushOpts, routeOptions(app, routes, defaultDomain)...)
 
 				if app.EnableHTTP2 != nil {
 					pushOpts = append(pushOpts, apps.WithPushGrpc(*app.EnableHTTP2))
@@ -302,23 +472,106 @@ func NewPushCommand(
 					case registry != "":
 						break
 					default:
-						registry = space.Spec.BuildpackBuild.ContainerRegistry
+						registry = space.EffectiveContainerRegistry()
 					}
 
 					var imageName string
 					srcPath := filepath.Join(path, app.Path)
+
+					// Like Cloud Foundry, a path pointing to an archive is
+					// pushed as an artifact.
+					artifactPath := artifact
+					if artifactPath == "" && isArtifact(srcPath) {
+						artifactPath = srcPath
+					}
+
 					switch {
 					case sourceImage != "":
+						if artifactPath != "" {
+							return errors.New("cannot use artifact and source image simultaneously")
+						}
+						if staticSite != "" {
+							return errors.New("cannot use static and source image simultaneously")
+						}
 						imageName = sourceImage
+					case dryRun:
+						// Nothing is packaged so the source's digest isn't known.
+						imageName = apps.JoinRepositoryImage(registry, apps.SourceImageName(p.Namespace, app.Name, dryRunSourceDigest))
 					default:
-						imageName = apps.JoinRepositoryImage(registry, apps.SourceImageName(p.Namespace, app.Name))
-
 						// Kontext has to have a absolute path.
 						srcPath, err = filepath.Abs(srcPath)
 						if err != nil {
 							return err
 						}
 
+						var filter KontextFilter
+						switch {
+						case staticSite != "":
+							switch {
+							case artifactPath != "":
+								return errors.New("cannot use static and artifact simultaneously")
+							case app.Dockerfile.Path != "":
+								return errors.New("cannot use static and Dockerfile simultaneously")
+							case app.Buildpack() != "":
+								return errors.New("cannot use static and buildpack simultaneously")
+							case len(app.SharedPaths) > 0:
+								return errors.New("cannot use static and shared-paths simultaneously")
+							}
+
+							staged, cleanup, err := stageStaticSite(staticSite)
+							if err != nil {
+								return err
+							}
+							defer cleanup()
+
+							fmt.Fprintf(cmd.OutOrStdout(), "Packaging static site %s for %s\n", staticSite, app.Name)
+							srcPath, filter = staged, includeAllFilter
+							app.Dockerfile.Path = staticSiteDockerfile
+
+						case artifactPath != "":
+							if app.Dockerfile.Path != "" {
+								return errors.New("cannot use artifact and Dockerfile simultaneously")
+							}
+							if len(app.SharedPaths) > 0 {
+								return errors.New("cannot use artifact and shared-paths simultaneously")
+							}
+
+							staged, cleanup, err := stageArtifact(artifactPath)
+							if err != nil {
+								return err
+							}
+							defer cleanup()
+
+							fmt.Fprintf(cmd.OutOrStdout(), "Staging artifact %s for %s\n", artifactPath, app.Name)
+							srcPath, filter = staged, includeAllFilter
+
+						// Apps in a monorepo can share directories outside of
+						// their path, only those and the App's source are uploaded.
+						case len(app.SharedPaths) > 0:
+							root, err := filepath.Abs(path)
+							if err != nil {
+								return err
+							}
+
+							staged, cleanup, err := stageSharedPaths(srcPath, root, app.SharedPaths, buildIgnoreFilter(srcPath))
+							if err != nil {
+								return err
+							}
+							defer cleanup()
+
+							fmt.Fprintf(cmd.OutOrStdout(), "Packaging %s with shared paths %s\n", app.Name, strings.Join(app.SharedPaths, ", "))
+							srcPath, filter = staged, includeAllFilter
+
+						default:
+							filter = buildIgnoreFilter(srcPath)
+						}
+
+						digest, err := sourceimage.Digest(srcPath, filter)
+						if err != nil {
+							return err
+						}
+						imageName = apps.JoinRepositoryImage(registry, apps.SourceImageName(p.Namespace, app.Name, digest))
+
 						// Sanity check that the Dockerfile is in the source
 						if app.Dockerfile.Path != "" {
 							absDockerPath := filepath.Join(srcPath, filepath.FromSlash(app.Dockerfile.Path))
@@ -328,26 +581,70 @@ func NewPushCommand(
 							}
 						}
 
-						if err := b.BuildSrcImage(srcPath, imageName, buildIgnoreFilter(srcPath)); err != nil {
+						if existingSourceImage(client, p.Namespace, app.Name) == imageName {
+							fmt.Fprintln(cmd.OutOrStdout(), "Source unchanged since the last push, skipping upload")
+							break
+						}
+
+						machine.Emit(cmd.OutOrStdout(), phaseUpload, percentUploadStarted, fmt.Sprintf("Uploading source for %s", app.Name))
+						_, uploadSpan := tracing.StartSpan(ctx, "Upload source")
+						uploadCtx, cancelUpload := ctx, func() {}
+						if timeouts.Upload > 0 {
+							uploadCtx, cancelUpload = context.WithTimeout(ctx, timeouts.Upload)
+						}
+						uploadedImage, err := sourceUpload.Upload(
+							uploadCtx,
+							cmd.OutOrStdout(),
+							b,
+							srcPath,
+							imageName,
+							digest,
+							filter,
+						)
+						if err != nil && uploadCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+							err = apps.NewPhaseTimeoutError(apps.TimeoutPhaseUpload, timeouts.Upload, nil)
+						}
+						cancelUpload()
+						tracing.EndSpan(uploadSpan, err)
+						if err != nil {
 							return err
 						}
+						imageName = uploadedImage
+						machine.Emit(cmd.OutOrStdout(), phaseUpload, percentUploaded, fmt.Sprintf("Uploaded source for %s", app.Name))
+					}
+					if len(dockerfileBuildArgs) > 0 && !dryRun {
+						fmt.Fprintf(cmd.OutOrStdout(), "Building %s with build args %s\n", app.Name, describeBuildArgs(dockerfileBuildArgs))
 					}
+
 					pushOpts = append(pushOpts,
 						apps.WithPushSourceImage(imageName),
 						apps.WithPushBuildpack(app.Buildpack()),
 						apps.WithPushStack(app.Stack),
 						apps.WithPushDockerfilePath(app.Dockerfile.Path),
+						apps.WithPushDockerfileBuildArgs(dockerfileBuildArgs),
 					)
 				} else {
 					if containerRegistry != "" {
 						return errors.New("--container-registry can only be used with source pushes, not containers")
 					}
+					if sourceImage != "" {
+						return errors.New("cannot use source image and docker image simultaneously")
+					}
+					if artifact != "" {
+						return errors.New("cannot use artifact and docker image simultaneously")
+					}
+					if staticSite != "" {
+						return errors.New("cannot use static and docker image simultaneously")
+					}
 					if app.Buildpack() != "" {
 						return errors.New("cannot use buildpack and docker image simultaneously")
 					}
 					if app.Path != "" {
 						return errors.New("cannot use path and docker image simultaneously")
 					}
+					if len(app.SharedPaths) > 0 {
+						return errors.New("cannot use shared-paths and docker image simultaneously")
+					}
 
 					pushOpts = append(pushOpts, apps.WithPushContainerImage(app.Docker.Image))
 				}
@@ -362,13 +659,60 @@ func NewPushCommand(
 				}
 				pushOpts = append(pushOpts, apps.WithPushServiceBindings(bindings))
 
+				if dryRun {
+					preview, err := newPushPreview(pusher, stacksClient, space, app.Name, pushOpts)
+					if err != nil {
+						return err
+					}
+
+					if err := printPushPreview(cmd.OutOrStdout(), printFlags, space, preview); err != nil {
+						return err
+					}
+
+					continue
+				}
+
+				// The App is rolled back to the version running before the
+				// push if a post-deploy hook or task fails.
+				var previous *v1alpha1.App
+				if runHooks && app.Hooks.HasPostDeploy() {
+					previous, _ = client.Get(p.Namespace, app.Name)
+				}
+
+				// Rolling pushes keep traffic on the running revision until
+				// the new one is ready then shift it over in steps.
+				var rolling *rollingPush
+				if strategy == pushStrategyRolling {
+					rolling = newRollingPush(cmd.OutOrStdout(), client, explainer, p.Namespace, app.Name, stepPercent, stepInterval)
+				}
+				if rolling != nil {
+					pushOpts = append(pushOpts, apps.WithPushRollout(rolling.Pin()))
+				}
+
 				err = pusher.Push(app.Name, pushOpts...)
 
 				cmd.SilenceUsage = !utils.ConfigError(err)
 
 				if err != nil {
+					explainPushFailure(cmd.OutOrStdout(), explainer, p.Namespace, app.Name, err)
+
+					if rolling != nil {
+						return rolling.Abort(ctx, err)
+					}
 					return err
 				}
+
+				if rolling != nil {
+					if err := rolling.Run(ctx); err != nil {
+						return err
+					}
+				}
+
+				if runHooks && app.Hooks.HasPostDeploy() {
+					if err := runPostDeployHooks(ctx, cmd.OutOrStdout(), client, tasks, p.Namespace, path, env, app); err != nil {
+						return rollbackApp(cmd.OutOrStdout(), client, p.Namespace, previous, err)
+					}
+				}
 			}
 
 			return nil
@@ -392,6 +736,20 @@ func NewPushCommand(
 		"Path to the source code (default: current directory)",
 	)
 
+	pushCmd.Flags().StringVar(
+		&artifact,
+		"artifact",
+		"",
+		"Path to a prebuilt JAR, WAR or ZIP to stage instead of the source code",
+	)
+
+	pushCmd.Flags().StringVar(
+		&staticSite,
+		"static",
+		"",
+		"Directory of static files to serve with nginx instead of building the source code",
+	)
+
 	pushCmd.Flags().StringArrayVarP(
 		&envs,
 		"env",
@@ -414,6 +772,13 @@ func NewPushCommand(
 		"Ignore the manifest file.",
 	)
 
+	pushCmd.Flags().BoolVar(
+		&interactive,
+		"interactive",
+		false,
+		"Prompt for the app's configuration when there's no manifest and optionally save the answers as a manifest.",
+	)
+
 	pushCmd.Flags().StringVarP(
 		&buildpack,
 		"buildpack",
@@ -434,9 +799,10 @@ func NewPushCommand(
 		&sourceImage,
 		"source-image",
 		"",
-		"Kontext image containing the source code.",
+		"Pre-built image containing the source code, e.g. produced by CI. Skips packaging and uploading the local source.",
 	)
-	pushCmd.Flags().MarkHidden("source-image")
+
+	sourceUpload.Add(pushCmd)
 
 	pushCmd.Flags().StringVar(
 		&containerImage,
@@ -452,6 +818,20 @@ func NewPushCommand(
 		"Path to the Dockerfile to build. Relative to the source root.",
 	)
 
+	pushCmd.Flags().StringArrayVar(
+		&buildArgs,
+		"build-arg",
+		nil,
+		"Set a build arg for the Dockerfile build. Multiple can be set by using the flag multiple times (e.g., NAME=VALUE).",
+	)
+
+	pushCmd.Flags().StringArrayVar(
+		&secretBuildArgs,
+		"build-arg-from-secret",
+		nil,
+		"Set a build arg for the Dockerfile build from a Secret so its value isn't stored or logged (e.g., NAME=SECRET_NAME[:KEY]).",
+	)
+
 	pushCmd.Flags().StringVarP(
 		&manifestFile,
 		"manifest",
@@ -489,6 +869,43 @@ func NewPushCommand(
 		"Do not start an app after pushing",
 	)
 
+	pushCmd.Flags().BoolVar(
+		&forceBuild,
+		"force-build",
+		false,
+		"Rebuild and redeploy the app even if the source and configuration haven't changed since the last push",
+	)
+
+	pushCmd.Flags().BoolVar(
+		&noHooks,
+		"no-hooks",
+		false,
+		"Skip the pre-push and post-deploy hooks and tasks in the manifest",
+	)
+
+	pushCmd.Flags().BoolVar(
+		&dryRun,
+		"dry-run",
+		false,
+		"Print the resources that would be created or updated without packaging the source or changing the cluster.",
+	)
+
+	printFlags.AddFlags(pushCmd)
+
+	// Override output format to be sorted so our generated documents are deterministic
+	{
+		allowedFormats := printFlags.AllowedFormats()
+		sort.Strings(allowedFormats)
+		pushCmd.Flag("output").Usage = fmt.Sprintf("Output format of --dry-run. One of: %s.", strings.Join(allowedFormats, "|"))
+	}
+
+	pushCmd.Flags().StringVar(
+		&bindingFormat,
+		"binding-format",
+		"",
+		"How service binding credentials are provided to the app: vcap for VCAP_SERVICES or k8s for files under /bindings (default: vcap)",
+	)
+
 	pushCmd.Flags().StringVarP(
 		&healthCheckType,
 		"health-check-type",
@@ -505,6 +922,62 @@ func NewPushCommand(
 		"Time (in seconds) allowed to elapse between starting up an app and the first healthy response from the app.",
 	)
 
+	pushCmd.Flags().StringVar(
+		&healthCheckExpect,
+		"health-check-expect",
+		"",
+		"Text the body of http health check responses must contain, e.g. '\"status\":\"UP\"'. The app's image needs curl or wget.",
+	)
+
+	pushCmd.Flags().DurationVar(
+		&uploadTimeout,
+		"upload-timeout",
+		0,
+		"Maximum time to upload source including retries, e.g. 5m (default: no limit)",
+	)
+
+	pushCmd.Flags().DurationVar(
+		&buildTimeout,
+		"build-timeout",
+		0,
+		"Maximum time for the app to build, e.g. 15m (default: no limit)",
+	)
+
+	pushCmd.Flags().DurationVar(
+		&deployTimeout,
+		"deploy-timeout",
+		0,
+		"Maximum time for the built app to roll out, e.g. 5m (default: no limit)",
+	)
+
+	pushCmd.Flags().DurationVar(
+		&routeTimeout,
+		"route-timeout",
+		0,
+		"Maximum time for routes to be reconciled after the app rolls out, e.g. 1m (default: no limit)",
+	)
+
+	pushCmd.Flags().StringVar(
+		&strategy,
+		"strategy",
+		pushStrategyDefault,
+		"How traffic moves to the new version of the app: default sends it all once the app is ready, rolling shifts it in steps and rolls back if the new version fails",
+	)
+
+	pushCmd.Flags().IntVar(
+		&stepPercent,
+		"step-percent",
+		20,
+		"Percent of traffic added to the new version at each step of a rolling push",
+	)
+
+	pushCmd.Flags().DurationVar(
+		&stepInterval,
+		"step-interval",
+		time.Minute,
+		"Time between the steps of a rolling push, e.g. 30s",
+	)
+
 	pushCmd.Flags().BoolVar(
 		&noRoute,
 		"no-route",
@@ -551,6 +1024,33 @@ func NewPushCommand(
 	return pushCmd
 }
 
+// pushTimeouts converts the timeouts from a manifest to durations. Unset
+// timeouts are left as zero so the phase has no limit.
+func pushTimeouts(t manifest.PushTimeouts) (apps.PushTimeouts, error) {
+	var out apps.PushTimeouts
+	for _, timeout := range []struct {
+		value string
+		dest  *time.Duration
+	}{
+		{value: t.Upload, dest: &out.Upload},
+		{value: t.Build, dest: &out.Build},
+		{value: t.Deploy, dest: &out.Deploy},
+		{value: t.Route, dest: &out.Route},
+	} {
+		if timeout.value == "" {
+			continue
+		}
+
+		d, err := time.ParseDuration(timeout.value)
+		if err != nil {
+			return apps.PushTimeouts{}, err
+		}
+		*timeout.dest = d
+	}
+
+	return out, nil
+}
+
 func createRoute(routeStr, namespace string) (v1alpha1.RouteSpecFields, error) {
 	hostname, domain, path, err := parseRouteStr(routeStr)
 	if err != nil {
@@ -618,6 +1118,110 @@ func spaceDefaultDomain(space *v1alpha1.Space) (string, error) {
 	return "", errors.New("space does not have a default domain")
 }
 
+// readProcfile reads the process types from the Procfile at the root of the
+// App's source. Artifacts don't have one.
+func readProcfile(srcPath string) ([]v1alpha1.AppSpecProcess, error) {
+	if info, err := os.Stat(srcPath); err != nil || !info.IsDir() {
+		return nil, nil
+	}
+
+	return procfile.Read(srcPath)
+}
+
+// hookEnv is the environment hooks run with. It holds the App's environment
+// on top of the space's along with the names of the App and space.
+func hookEnv(space *v1alpha1.Space, namespace string, app manifest.Application) map[string]string {
+	env := envutil.EnvVarsToMap(space.Spec.Execution.Env)
+	for k, v := range app.Env {
+		env[k] = v
+	}
+
+	env["KF_APP"] = app.Name
+	env["KF_SPACE"] = namespace
+
+	return env
+}
+
+// runPostDeployHooks runs the App's post-deploy tasks in the cluster followed
+// by its local post-deploy hooks.
+func runPostDeployHooks(
+	ctx context.Context,
+	out io.Writer,
+	client apps.Client,
+	tasks hooks.TaskRunner,
+	namespace string,
+	dir string,
+	env map[string]string,
+	app manifest.Application,
+) error {
+	if len(app.Hooks.PostDeployTasks) > 0 {
+		deployed, err := client.Get(namespace, app.Name)
+		if err != nil {
+			return err
+		}
+
+		for _, task := range app.Hooks.PostDeployTasks {
+			// Tasks can name one of the App's processes, e.g. release.
+			if process := deployed.Spec.Process(task); process != nil {
+				task = process.Command
+			}
+
+			if err := tasks.Run(ctx, out, deployed, task); err != nil {
+				return err
+			}
+		}
+	}
+
+	return hooks.RunLocal(out, hooks.PhasePostDeploy, dir, env, app.Hooks.PostDeploy)
+}
+
+// explainPushFailure prints why the App failed to build or deploy so users
+// don't have to dig through its Builds, Pods and events. Other errors, such as
+// invalid configuration, already explain themselves.
+func explainPushFailure(out io.Writer, explainer failures.Explainer, namespace, appName string, pushErr error) {
+	switch machine.ExitCode(pushErr) {
+	case machine.ExitBuildFailed, machine.ExitDeployFailed:
+	default:
+		return
+	}
+
+	fmt.Fprintf(out, "Pushing %s failed: %s\n", appName, pushErr)
+	if err := explainer.Explain(out, namespace, appName); err != nil {
+		fmt.Fprintf(out, "Couldn't find out why: %s\n", err)
+	}
+}
+
+// rollbackApp restores the version of the App running before the push after
+// a post-deploy hook failed. The returned error always includes hookErr.
+func rollbackApp(out io.Writer, client apps.Client, namespace string, previous *v1alpha1.App, hookErr error) error {
+	mutator, err := hooks.Rollback(previous)
+	if err != nil {
+		return fmt.Errorf("%s, %s", hookErr, err)
+	}
+
+	fmt.Fprintf(out, "Rolling back %s to %s\n", previous.Name, previous.Status.Image)
+	if _, err := client.Transform(namespace, previous.Name, mutator); err != nil {
+		return fmt.Errorf("%s, failed to roll back: %s", hookErr, err)
+	}
+
+	return fmt.Errorf("%s, rolled back to %s", hookErr, previous.Status.Image)
+}
+
+// applySpaceHealthCheckDefaults fills in the health check settings the app
+// doesn't choose with the space's defaults.
+func applySpaceHealthCheckDefaults(space *v1alpha1.Space, app *manifest.Application) {
+	defaults := space.Spec.Execution.DefaultHealthCheck
+
+	if app.HealthCheckType == "" && app.HealthCheckHTTPEndpoint == "" {
+		app.HealthCheckType = defaults.Type
+		app.HealthCheckHTTPEndpoint = defaults.HTTPEndpoint
+	}
+
+	if app.HealthCheckTimeout == 0 {
+		app.HealthCheckTimeout = defaults.StartupTimeoutSeconds
+	}
+}
+
 func setupRoutes(space *v1alpha1.Space, app manifest.Application) (routes []v1alpha1.RouteSpecFields, err error) {
 	if app.NoRoute != nil && *app.NoRoute {
 		return nil, nil
@@ -629,12 +1233,58 @@ func setupRoutes(space *v1alpha1.Space, app manifest.Application) (routes []v1al
 		if err != nil {
 			return nil, err
 		}
+		if newRoute.Hostname != "" {
+			if err := v1alpha1.ValidateDNS1123Label(newRoute.Hostname, "hostname"); err != nil {
+				return nil, fmt.Errorf("invalid route %s: %s", route.Route, err)
+			}
+		}
+		if err := space.Spec.Execution.CheckHostname(newRoute.Hostname, app.Name); err != nil {
+			return nil, err
+		}
 		routes = append(routes, newRoute)
 	}
 
 	return routes, nil
 }
 
+// routeOptions converts the App's routing configuration into push options.
+// With no-route the App gets no routes and loses any from previous pushes.
+// Otherwise explicit routes are used, falling back to a random route or the
+// default route on the space's domain if the App doesn't have any routes yet.
+func routeOptions(app manifest.Application, routes []v1alpha1.RouteSpecFields, defaultDomain string) []apps.PushOption {
+	if app.NoRoute != nil && *app.NoRoute {
+		return []apps.PushOption{apps.WithPushNoRoute(true)}
+	}
+
+	opts := []apps.PushOption{apps.WithPushRoutes(routes)}
+	switch {
+	case app.RandomRoute != nil && *app.RandomRoute:
+		opts = append(opts, apps.WithPushRandomRouteDomain(defaultDomain))
+	case len(routes) == 0:
+		opts = append(opts, apps.WithPushDefaultRouteDomain(defaultDomain))
+	}
+
+	return opts
+}
+
+// existingSourceImage returns the source image of the currently deployed app
+// or a blank string if it can't be determined.
+func existingSourceImage(client apps.Client, namespace, appName string) string {
+	app, err := client.Get(namespace, appName)
+	if err != nil {
+		return ""
+	}
+
+	switch {
+	case app.Spec.Source.IsBuildpackBuild():
+		return app.Spec.Source.BuildpackBuild.Source
+	case app.Spec.Source.IsDockerfileBuild():
+		return app.Spec.Source.Dockerfile.Source
+	default:
+		return ""
+	}
+}
+
 func buildIgnoreFilter(srcPath string) KontextFilter {
 	ignoreFiles := []string{
 		".kfignore",
@@ -682,3 +1332,39 @@ func buildIgnoreFilter(srcPath string) KontextFilter {
 		return !gitignore.MatchesPath(path), nil
 	}
 }
+
+// parseBuildArgs turns a slice of strings formatted as NAME=VALUE into a map.
+// Unlike environment variables, build arg values may contain an equals sign.
+func parseBuildArgs(raw []string) (map[string]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	out := make(map[string]string)
+	for _, kv := range raw {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("malformed build arg: %s", kv)
+		}
+
+		out[parts[0]] = parts[1]
+	}
+
+	return out, nil
+}
+
+// describeBuildArgs formats build args for output, masking the values of args
+// read from Secrets.
+func describeBuildArgs(args []corev1.EnvVar) string {
+	var out []string
+	for _, arg := range args {
+		value := arg.Value
+		if arg.ValueFrom != nil {
+			value = "*****"
+		}
+
+		out = append(out, fmt.Sprintf("%s=%s", arg.Name, value))
+	}
+
+	return strings.Join(out, ", ")
+}
@@ -0,0 +1,175 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/envutil"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/describe"
+	"github.com/google/kf/pkg/kf/stacks"
+	"github.com/google/kf/pkg/reconciler/app/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// dryRunSourceDigest stands in for the digest of the source in the source
+// image shown by dry runs because the source isn't packaged.
+const dryRunSourceDigest = "dry-run"
+
+// pushPreview holds the resources pushing an App would create or update.
+type pushPreview struct {
+	App         *v1alpha1.App
+	Source      *v1alpha1.Source
+	Routes      []v1alpha1.Route
+	RouteClaims []v1alpha1.RouteClaim
+}
+
+// newPushPreview resolves the resources the App's controller would create
+// for the App pushed with the options.
+func newPushPreview(
+	pusher apps.Pusher,
+	stacksClient stacks.Client,
+	space *v1alpha1.Space,
+	appName string,
+	opts []apps.PushOption,
+) (*pushPreview, error) {
+	app, err := pusher.Preview(appName, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	stackConfig := &stacks.Config{}
+	if app.Spec.Source.IsBuildpackBuild() {
+		if stackConfig.Stacks, err = stacksClient.List(); err != nil {
+			return nil, fmt.Errorf("couldn't get the cluster's stacks: %v", err)
+		}
+	}
+
+	source, err := resources.MakeSource(app, space, stackConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, claims, err := resources.MakeRoutes(app, space)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pushPreview{
+		App:         app,
+		Source:      source,
+		Routes:      routes,
+		RouteClaims: claims,
+	}, nil
+}
+
+// objects gets the resources in the preview with their types set so they
+// can be printed as manifests.
+func (preview *pushPreview) objects() []runtime.Object {
+	setKind := func(obj runtime.Object, kind string) runtime.Object {
+		obj.GetObjectKind().SetGroupVersionKind(v1alpha1.SchemeGroupVersion.WithKind(kind))
+		return obj
+	}
+
+	objects := []runtime.Object{
+		setKind(preview.App, "App"),
+		setKind(preview.Source, "Source"),
+	}
+
+	for i := range preview.Routes {
+		objects = append(objects, setKind(&preview.Routes[i], "Route"))
+	}
+
+	for i := range preview.RouteClaims {
+		objects = append(objects, setKind(&preview.RouteClaims[i], "RouteClaim"))
+	}
+
+	return objects
+}
+
+// printPushPreview prints the preview as manifests if an output format was
+// requested and as a summary otherwise.
+func printPushPreview(
+	w io.Writer,
+	printFlags *genericclioptions.PrintFlags,
+	space *v1alpha1.Space,
+	preview *pushPreview,
+) error {
+	if printFlags.OutputFlagSpecified() {
+		printer, err := printFlags.ToPrinter()
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range preview.objects() {
+			if err := printer.PrintObj(obj, w); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	app := preview.App
+	action := "created"
+	if app.ResourceVersion != "" {
+		action = "updated"
+	}
+
+	fmt.Fprintf(w, "Dry run, App %s would be %s in space %s:\n", app.Name, action, app.Namespace)
+	fmt.Fprintln(w)
+
+	describe.SourceSpec(w, preview.Source.Spec)
+	fmt.Fprintln(w)
+
+	var routes []v1alpha1.RouteSpecFields
+	for _, route := range preview.Routes {
+		routes = append(routes, route.Spec.RouteSpecFields)
+	}
+	describe.RouteSpecFieldsList(w, routes)
+	fmt.Fprintln(w)
+
+	describe.AppSpecInstances(w, app.Spec.Instances)
+	fmt.Fprintln(w)
+
+	describeResolvedEnv(w, space.Spec.Execution.Env, envutil.GetAppEnvVars(app))
+
+	return nil
+}
+
+// describeResolvedEnv prints the environment the App will run with, space
+// defaults are overridden by the App's own variables.
+func describeResolvedEnv(w io.Writer, spaceEnv, appEnv []corev1.EnvVar) {
+	fromApp := make(map[string]bool)
+	for _, env := range appEnv {
+		fromApp[env.Name] = true
+	}
+
+	describe.SectionWriter(w, "Environment", func(w io.Writer) {
+		for _, env := range envutil.OverrideEnvVars(spaceEnv, appEnv) {
+			origin := "space"
+			if fromApp[env.Name] {
+				origin = "app"
+			}
+
+			fmt.Fprintf(w, "%s:\t%s\t(%s)\n", env.Name, env.Value, origin)
+		}
+	})
+}
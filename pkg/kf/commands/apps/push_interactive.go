@@ -0,0 +1,180 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/kf/pkg/kf/manifest"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/ptr"
+)
+
+// pushInteractively asks the user to describe the app they're pushing,
+// builds a manifest from the answers and optionally saves it to the source
+// directory so the next push doesn't need the prompts.
+func pushInteractively(in io.Reader, out io.Writer, path, appName string) (*manifest.Manifest, error) {
+	prompt := &linePrompter{scanner: bufio.NewScanner(in), out: out}
+
+	if appName == "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			appName = defaultAppName(filepath.Base(abs))
+		}
+	}
+
+	app := manifest.Application{}
+
+	name, err := prompt.Ask("App name", appName, func(answer string) error {
+		if errs := validation.IsDNS1123Label(answer); len(errs) > 0 {
+			return errors.New(strings.Join(errs, ", "))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	app.Name = name
+
+	memory, err := prompt.Ask("Memory", "1G", func(answer string) error {
+		_, err := (&manifest.Application{Memory: answer}).ToResourceRequests()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	app.Memory = memory
+
+	instances, err := prompt.Ask("Instances", "1", func(answer string) error {
+		if n, err := strconv.Atoi(answer); err != nil || n < 0 {
+			return errors.New("must be a non-negative integer")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(instances)
+	app.Instances = &n
+
+	route, err := prompt.Ask("Route (default, random, none or a route like host.example.com/path)", "default", nil)
+	if err != nil {
+		return nil, err
+	}
+	switch route {
+	case "default":
+		// The default route is added by the push.
+	case "random":
+		app.RandomRoute = ptr.Bool(true)
+	case "none":
+		app.NoRoute = ptr.Bool(true)
+	default:
+		app.Routes = []manifest.Route{{Route: route}}
+	}
+
+	buildpack, err := prompt.Ask("Buildpack (blank to detect)", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	if buildpack != "" {
+		app.Buildpacks = []string{buildpack}
+	}
+
+	pushManifest := &manifest.Manifest{Applications: []manifest.Application{app}}
+
+	save, err := prompt.Ask("Save the answers to manifest.yml? (y/n)", "y", func(answer string) error {
+		switch strings.ToLower(answer) {
+		case "y", "yes", "n", "no":
+			return nil
+		default:
+			return errors.New("answer y or n")
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(strings.ToLower(save), "y") {
+		manifestPath := filepath.Join(path, "manifest.yml")
+		if err := pushManifest.WriteFile(manifestPath); err != nil {
+			return nil, fmt.Errorf("couldn't save manifest: %s", err)
+		}
+		fmt.Fprintf(out, "Saved manifest to %s\n", manifestPath)
+	}
+
+	fmt.Fprintln(out)
+
+	return pushManifest, nil
+}
+
+// defaultAppName converts a directory name into a suggested app name.
+func defaultAppName(dir string) string {
+	name := strings.ToLower(dir)
+	name = strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '-'
+	}, name)
+
+	return strings.Trim(name, "-")
+}
+
+// linePrompter asks questions on an output and reads the answers line by line
+// from an input.
+type linePrompter struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+// Ask prints the label and reads an answer, using def if the answer is blank.
+// If the answer fails validation the question is asked again.
+func (p *linePrompter) Ask(label, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", label)
+		}
+
+		if !p.scanner.Scan() {
+			if err := p.scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", errors.New("no answer was given, interactive push needs a terminal")
+		}
+
+		answer := strings.TrimSpace(p.scanner.Text())
+		if answer == "" {
+			answer = def
+		}
+
+		if validate == nil {
+			return answer, nil
+		}
+
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(p.out, "Invalid answer: %s\n", err)
+			continue
+		}
+
+		return answer, nil
+	}
+}
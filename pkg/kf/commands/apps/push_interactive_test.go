@@ -0,0 +1,152 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/manifest"
+	"github.com/google/kf/pkg/kf/testutil"
+	"knative.dev/pkg/ptr"
+)
+
+func TestPushInteractively(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		appName      string
+		input        string
+		wantManifest *manifest.Manifest
+		wantSaved    bool
+		wantOutput   []string
+		wantErr      error
+	}{
+		"defaults": {
+			appName: "my-app",
+			input:   "\n\n\n\n\nn\n",
+			wantManifest: &manifest.Manifest{
+				Applications: []manifest.Application{{
+					Name:      "my-app",
+					Memory:    "1G",
+					Instances: intPtr(1),
+				}},
+			},
+			wantOutput: []string{"App name [my-app]", "Memory [1G]", "Instances [1]"},
+		},
+		"custom answers saved": {
+			appName: "my-app",
+			input:   "other-app\n512M\n3\nrandom\njava\ny\n",
+			wantManifest: &manifest.Manifest{
+				Applications: []manifest.Application{{
+					Name:        "other-app",
+					Memory:      "512M",
+					Instances:   intPtr(3),
+					RandomRoute: ptr.Bool(true),
+					Buildpacks:  []string{"java"},
+				}},
+			},
+			wantSaved:  true,
+			wantOutput: []string{"Saved manifest to"},
+		},
+		"custom route": {
+			appName: "my-app",
+			input:   "\n\n\nhost.example.com/path\n\nn\n",
+			wantManifest: &manifest.Manifest{
+				Applications: []manifest.Application{{
+					Name:      "my-app",
+					Memory:    "1G",
+					Instances: intPtr(1),
+					Routes:    []manifest.Route{{Route: "host.example.com/path"}},
+				}},
+			},
+		},
+		"no route": {
+			appName: "my-app",
+			input:   "\n\n\nnone\n\nn\n",
+			wantManifest: &manifest.Manifest{
+				Applications: []manifest.Application{{
+					Name:      "my-app",
+					Memory:    "1G",
+					Instances: intPtr(1),
+					NoRoute:   ptr.Bool(true),
+				}},
+			},
+		},
+		"invalid answers are asked again": {
+			appName: "my-app",
+			input:   "Bad_Name\nok-app\nlots\n1G\n-1\n2\n\n\nmaybe\nn\n",
+			wantManifest: &manifest.Manifest{
+				Applications: []manifest.Application{{
+					Name:      "ok-app",
+					Memory:    "1G",
+					Instances: intPtr(2),
+				}},
+			},
+			wantOutput: []string{"Invalid answer", "must be a non-negative integer", "answer y or n"},
+		},
+		"input ends early": {
+			appName: "my-app",
+			input:   "my-app\n",
+			wantErr: errors.New("no answer was given, interactive push needs a terminal"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "kf-push-interactive")
+			testutil.AssertNil(t, "error creating test directory", err)
+			defer os.RemoveAll(dir)
+
+			out := &bytes.Buffer{}
+			gotManifest, gotErr := pushInteractively(strings.NewReader(tc.input), out, dir, tc.appName)
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+				return
+			}
+
+			testutil.AssertEqual(t, "manifest", tc.wantManifest, gotManifest)
+			testutil.AssertContainsAll(t, out.String(), tc.wantOutput)
+
+			saved, err := manifest.CheckForManifest(dir)
+			testutil.AssertNil(t, "check for manifest error", err)
+			if tc.wantSaved {
+				testutil.AssertEqual(t, "saved manifest", tc.wantManifest, saved)
+			} else {
+				testutil.AssertEqual(t, "saved manifest", (*manifest.Manifest)(nil), saved)
+			}
+		})
+	}
+}
+
+func TestDefaultAppName(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"my-app":    "my-app",
+		"My_App":    "my-app",
+		"_src.dir_": "src-dir",
+	}
+
+	for dir, want := range cases {
+		t.Run(dir, func(t *testing.T) {
+			testutil.AssertEqual(t, "name", want, defaultAppName(dir))
+		})
+	}
+}
@@ -0,0 +1,215 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/failures"
+	"github.com/google/kf/pkg/kf/hooks"
+)
+
+const (
+	// pushStrategyDefault sends all traffic to the new version of an App as
+	// soon as it's ready.
+	pushStrategyDefault = "default"
+
+	// pushStrategyRolling shifts traffic to the new version of an App in
+	// steps and rolls back if it stops being ready.
+	pushStrategyRolling = "rolling"
+
+	// rollingReadyTimeout is how long a rolling push waits for the App to be
+	// ready after each change.
+	rollingReadyTimeout = 5 * time.Minute
+
+	// rollingPollInterval is how often the App is checked while waiting.
+	rollingPollInterval = 2 * time.Second
+)
+
+// rollingPush shifts an App's traffic from the revision that was running
+// before the push to the new one.
+type rollingPush struct {
+	out       io.Writer
+	client    apps.Client
+	explainer failures.Explainer
+	namespace string
+	previous  *v1alpha1.App
+
+	stepPercent  int
+	stepInterval time.Duration
+}
+
+// newRollingPush creates a rollingPush for the App. It returns nil if the App
+// has no ready revision to roll from so the push can deploy normally.
+func newRollingPush(
+	out io.Writer,
+	client apps.Client,
+	explainer failures.Explainer,
+	namespace string,
+	appName string,
+	stepPercent int,
+	stepInterval time.Duration,
+) *rollingPush {
+	previous, err := client.Get(namespace, appName)
+	if err != nil || previous.Spec.Instances.Stopped || previous.Status.LatestReadyRevisionName == "" {
+		fmt.Fprintf(out, "%q has no running revision to roll from, traffic will move to the new version once it's ready\n", appName)
+		return nil
+	}
+
+	return &rollingPush{
+		out:          out,
+		client:       client,
+		explainer:    explainer,
+		namespace:    namespace,
+		previous:     previous,
+		stepPercent:  stepPercent,
+		stepInterval: stepInterval,
+	}
+}
+
+// stableRevision is the revision traffic is shifted from.
+func (r *rollingPush) stableRevision() string {
+	return r.previous.Status.LatestReadyRevisionName
+}
+
+// Pin keeps all traffic on the stable revision while the new version is
+// pushed.
+func (r *rollingPush) Pin() *v1alpha1.AppSpecRollout {
+	return &v1alpha1.AppSpecRollout{StableRevisionName: r.stableRevision()}
+}
+
+// Run shifts traffic to the new revision once the push deployed it. If the
+// new revision stops being ready the App is rolled back.
+func (r *rollingPush) Run(ctx context.Context) error {
+	deployed, err := r.waitForReady(ctx)
+	if err != nil {
+		r.explain()
+		return r.rollBack(ctx, err)
+	}
+
+	latest := deployed.Status.LatestReadyRevisionName
+	if latest != r.stableRevision() {
+		for percent := r.stepPercent; percent < 100; percent += r.stepPercent {
+			if err := r.setLatestPercent(percent); err != nil {
+				return err
+			}
+			fmt.Fprintf(r.out, "Sending %d%% of traffic to revision %s\n", percent, latest)
+
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(r.stepInterval):
+			}
+
+			if _, err := r.waitForReady(ctx); err != nil {
+				r.explain()
+				return r.rollBack(ctx, fmt.Errorf("revision %s isn't ready: %s", latest, err))
+			}
+		}
+	}
+
+	if err := r.unpin(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(r.out, "Sending all traffic to revision %s\n", latest)
+	return nil
+}
+
+// Abort rolls the App back after the push failed. Nothing is done if the
+// push never changed the App.
+func (r *rollingPush) Abort(ctx context.Context, pushErr error) error {
+	app, err := r.client.Get(r.namespace, r.previous.Name)
+	if err != nil || app.Spec.Rollout == nil || app.Spec.Rollout.StableRevisionName != r.stableRevision() {
+		return pushErr
+	}
+
+	return r.rollBack(ctx, pushErr)
+}
+
+// rollBack restores the App to its version before the push. Traffic stays
+// on the stable revision until the restored version is ready.
+func (r *rollingPush) rollBack(ctx context.Context, cause error) error {
+	rollback, err := hooks.Rollback(r.previous)
+	if err != nil {
+		return fmt.Errorf("%s, %s", cause, err)
+	}
+
+	fmt.Fprintf(r.out, "Rolling back %s to %s, traffic stays on revision %s\n", r.previous.Name, r.previous.Status.Image, r.stableRevision())
+	_, err = r.client.Transform(r.namespace, r.previous.Name, func(app *v1alpha1.App) error {
+		if err := rollback(app); err != nil {
+			return err
+		}
+
+		app.Spec.Rollout = r.Pin()
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s, failed to roll back: %s", cause, err)
+	}
+
+	if _, err := r.waitForReady(ctx); err != nil {
+		return fmt.Errorf("%s, rolled back to %s but it isn't ready so traffic is still pinned to revision %s: %s", cause, r.previous.Status.Image, r.stableRevision(), err)
+	}
+
+	if err := r.unpin(); err != nil {
+		return fmt.Errorf("%s, rolled back to %s but traffic is still pinned to revision %s: %s", cause, r.previous.Status.Image, r.stableRevision(), err)
+	}
+
+	return fmt.Errorf("%s, rolled back to %s", cause, r.previous.Status.Image)
+}
+
+// explain prints why the new revision failed before it's rolled back.
+func (r *rollingPush) explain() {
+	fmt.Fprintf(r.out, "The new version of %s isn't ready:\n", r.previous.Name)
+	if err := r.explainer.Explain(r.out, r.namespace, r.previous.Name); err != nil {
+		fmt.Fprintf(r.out, "Couldn't find out why: %s\n", err)
+	}
+}
+
+func (r *rollingPush) setLatestPercent(percent int) error {
+	_, err := r.client.Transform(r.namespace, r.previous.Name, func(app *v1alpha1.App) error {
+		if app.Spec.Rollout == nil {
+			return errors.New("the rollout was cancelled by another change to the App")
+		}
+
+		app.Spec.Rollout.LatestPercent = percent
+		return nil
+	})
+
+	return err
+}
+
+func (r *rollingPush) unpin() error {
+	_, err := r.client.Transform(r.namespace, r.previous.Name, func(app *v1alpha1.App) error {
+		app.Spec.Rollout = nil
+		return nil
+	})
+
+	return err
+}
+
+func (r *rollingPush) waitForReady(ctx context.Context) (*v1alpha1.App, error) {
+	ctx, cancel := context.WithTimeout(ctx, rollingReadyTimeout)
+	defer cancel()
+
+	return r.client.WaitForConditionKnativeServiceReadyTrue(ctx, r.namespace, r.previous.Name, rollingPollInterval)
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	appsfake "github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestRollingPush_Run_cancelled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	previous := &v1alpha1.App{}
+	previous.Name = "app-name"
+	previous.Status.LatestReadyRevisionName = "app-name-00001"
+
+	deployed := &v1alpha1.App{}
+	deployed.Status.LatestReadyRevisionName = "app-name-00002"
+
+	client := appsfake.NewFakeClient(ctrl)
+	gomock.InOrder(
+		client.EXPECT().
+			WaitForConditionKnativeServiceReadyTrue(gomock.Any(), "some-namespace", "app-name", gomock.Any()).
+			Return(deployed, nil),
+		client.EXPECT().
+			Transform("some-namespace", "app-name", gomock.Any()).
+			Do(func(_, _ string, _ apps.Mutator) {
+				cancel()
+			}),
+	)
+
+	r := &rollingPush{
+		out:          ioutil.Discard,
+		client:       client,
+		namespace:    "some-namespace",
+		previous:     previous,
+		stepPercent:  50,
+		stepInterval: time.Hour,
+	}
+
+	testutil.AssertErrorsEqual(t, context.Canceled, r.Run(ctx))
+}
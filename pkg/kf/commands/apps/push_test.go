@@ -16,21 +16,32 @@ package apps
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/apps"
 	appsfake "github.com/google/kf/pkg/kf/apps/fake"
 	"github.com/google/kf/pkg/kf/commands/config"
+	failuresfake "github.com/google/kf/pkg/kf/failures/fake"
+	hooksfake "github.com/google/kf/pkg/kf/hooks/fake"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/machine"
 	svbFake "github.com/google/kf/pkg/kf/service-bindings/fake"
+	"github.com/google/kf/pkg/kf/sourceimage"
+	"github.com/google/kf/pkg/kf/stacks"
+	stacksfake "github.com/google/kf/pkg/kf/stacks/fake"
 	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/google/kf/pkg/kf/tracing"
 	"github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -46,6 +57,9 @@ func dummyBindingInstance(appName, instanceName string) *v1beta1.ServiceBinding
 func TestPushCommand(t *testing.T) {
 	t.Parallel()
 
+	// Resuming uploads would make the result depend on previous test runs.
+	uploadManifestPath = func() string { return "" }
+
 	wantMemory := resource.MustParse("2Gi")
 	wantDiskQuota := resource.MustParse("2Gi")
 	wantCPU := resource.MustParse("2")
@@ -67,6 +81,12 @@ func TestPushCommand(t *testing.T) {
 		apps.WithPushDefaultRouteDomain("example.com"),
 	}
 
+	artifactDir, err := ioutil.TempDir("", "kf-push-artifact")
+	testutil.AssertNil(t, "temp dir", err)
+	defer os.RemoveAll(artifactDir)
+	artifact := filepath.Join(artifactDir, "app.jar")
+	writeTestArtifact(t, artifact, map[string]string{"META-INF/MANIFEST.MF": "Main-Class: App"})
+
 	for tn, tc := range map[string]struct {
 		args            []string
 		namespace       string
@@ -77,6 +97,8 @@ func TestPushCommand(t *testing.T) {
 		targetSpace     *v1alpha1.Space
 		wantOpts        []apps.PushOption
 		setup           func(t *testing.T, f *svbFake.FakeClientInterface)
+		existingApp     func(t *testing.T) *v1alpha1.App
+		setupHooks      func(t *testing.T, a *appsfake.FakeClient, r *hooksfake.FakeTaskRunner)
 	}{
 		"uses configured properties": {
 			namespace: "some-namespace",
@@ -98,7 +120,7 @@ func TestPushCommand(t *testing.T) {
 				"--args", "b",
 			},
 			wantImagePrefix: "some-reg.io/src-some-namespace-example-app",
-			srcImageBuilder: func(dir, srcImage string, rebase bool, filter func(path string) (bool, error)) error {
+			srcImageBuilder: func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
 				testutil.AssertEqual(t, "path", true, strings.Contains(dir, "example-app"))
 				testutil.AssertEqual(t, "path is abs", true, filepath.IsAbs(dir))
 				return nil
@@ -128,7 +150,7 @@ func TestPushCommand(t *testing.T) {
 			args: []string{
 				"app-name",
 			},
-			srcImageBuilder: func(dir, srcImage string, rebase bool, filter func(path string) (bool, error)) error {
+			srcImageBuilder: func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
 				cwd, err := os.Getwd()
 				testutil.AssertNil(t, "cwd err", err)
 				testutil.AssertEqual(t, "path", cwd, dir)
@@ -149,6 +171,247 @@ func TestPushCommand(t *testing.T) {
 				apps.WithPushNamespace("some-namespace"),
 			),
 		},
+		"custom-source skips upload": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--source-image", "custom-reg.io/source-image:latest",
+			},
+			srcImageBuilder: func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
+				t.Fatal("source shouldn't be uploaded when a source image is provided")
+				return nil
+			},
+			wantImagePrefix: "custom-reg.io/source-image:latest",
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+			),
+		},
+		"unchanged source skips upload": {
+			namespace: "some-namespace",
+			args: []string{
+				"example-app",
+				"--container-registry", "some-reg.io",
+				"--path", "testdata/example-app",
+			},
+			existingApp: func(t *testing.T) *v1alpha1.App {
+				srcPath, err := filepath.Abs("testdata/example-app")
+				testutil.AssertNil(t, "Abs", err)
+				digest, err := sourceimage.Digest(srcPath, buildIgnoreFilter(srcPath))
+				testutil.AssertNil(t, "Digest", err)
+
+				app := &v1alpha1.App{}
+				app.Spec.Source.BuildpackBuild.Source = "some-reg.io/" + apps.SourceImageName("some-namespace", "example-app", digest)
+				return app
+			},
+			srcImageBuilder: func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
+				t.Fatal("unchanged source shouldn't be uploaded")
+				return nil
+			},
+			wantImagePrefix: "some-reg.io/src-some-namespace-example-app",
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+			),
+		},
+		"monorepo app packages shared paths": {
+			namespace: "some-namespace",
+			args: []string{
+				"api",
+				"--container-registry", "some-reg.io",
+				"--path", "testdata/monorepo",
+			},
+			srcImageBuilder: func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
+				for _, want := range []string{"main.go", "libs/common/util.go"} {
+					_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(want)))
+					testutil.AssertNil(t, want+" staged", err)
+				}
+
+				_, err := os.Stat(filepath.Join(dir, "services"))
+				testutil.AssertEqual(t, "other apps staged", true, os.IsNotExist(err))
+				return nil
+			},
+			wantImagePrefix: "some-reg.io/src-some-namespace-api",
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+			),
+		},
+		"force build": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--force-build",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushForceBuild(true),
+			),
+		},
+		"k8s binding format": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--binding-format", "k8s",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushBindingFormat("k8s"),
+			),
+		},
+		"invalid binding format": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--binding-format", "json",
+			},
+			wantErr: errors.New("invalid value: json: binding-format"),
+		},
+		"push timeouts": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--build-timeout", "15m",
+				"--route-timeout", "90s",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushTimeouts(apps.PushTimeouts{Build: 15 * time.Minute, Route: 90 * time.Second}),
+			),
+		},
+		"invalid push timeout": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--deploy-timeout", "-5m",
+			},
+			wantErr: errors.New("invalid value: -5m0s: push-timeouts.deploy\ntimeouts must be positive durations such as 90s or 10m"),
+		},
+		"artifact": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--container-registry", "some-reg.io",
+				"--artifact", artifact,
+			},
+			srcImageBuilder: func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
+				_, err := os.Stat(filepath.Join(dir, "META-INF", "MANIFEST.MF"))
+				testutil.AssertNil(t, "artifact extracted", err)
+				return nil
+			},
+			wantImagePrefix: "some-reg.io/src-some-namespace-app-name",
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+			),
+		},
+		"path to artifact": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--container-registry", "some-reg.io",
+				"--path", artifact,
+			},
+			srcImageBuilder: func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
+				_, err := os.Stat(filepath.Join(dir, "META-INF", "MANIFEST.MF"))
+				testutil.AssertNil(t, "artifact extracted", err)
+				return nil
+			},
+			wantImagePrefix: "some-reg.io/src-some-namespace-app-name",
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+			),
+		},
+		"artifact with source image": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--artifact", artifact,
+				"--source-image", "custom-reg.io/source-image:latest",
+			},
+			wantErr: errors.New("cannot use artifact and source image simultaneously"),
+		},
+		"artifact with docker image": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--artifact", artifact,
+				"--docker-image", "some-image",
+			},
+			wantErr: errors.New("cannot use artifact and docker image simultaneously"),
+		},
+		"artifact with Dockerfile": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--artifact", artifact,
+				"--dockerfile", "Dockerfile",
+			},
+			wantErr: errors.New("cannot use artifact and Dockerfile simultaneously"),
+		},
+		"static site": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--container-registry", "some-reg.io",
+				"--static", "testdata/static-site",
+			},
+			srcImageBuilder: func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
+				for _, want := range []string{"Dockerfile", "public/index.html"} {
+					_, err := os.Stat(filepath.Join(dir, filepath.FromSlash(want)))
+					testutil.AssertNil(t, want+" staged", err)
+				}
+				return nil
+			},
+			wantImagePrefix: "some-reg.io/src-some-namespace-app-name",
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushDockerfilePath("Dockerfile"),
+			),
+		},
+		"static site with buildpack": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--static", "testdata/static-site",
+				"--buildpack", "some-buildpack",
+			},
+			wantErr: errors.New("cannot use static and buildpack simultaneously"),
+		},
+		"static site with docker image": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--static", "testdata/static-site",
+				"--docker-image", "some-image",
+			},
+			wantErr: errors.New("cannot use static and docker image simultaneously"),
+		},
+		"health check expect": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--health-check-type", "http",
+				"--health-check-expect", "UP",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushHealthCheck(mustHealthCheck("http", "", "UP", 0)),
+			),
+		},
+		"health check expect with port": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--health-check-expect", "UP",
+			},
+			wantErr: errors.New("health check expectations can only be used with http checks"),
+		},
+		"custom-source with docker image": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--source-image", "custom-reg.io/source-image:latest",
+				"--docker-image", "some-image",
+			},
+			wantErr: errors.New("cannot use source image and docker image simultaneously"),
+		},
 		"override manifest instances": {
 			namespace: "some-namespace",
 			args: []string{
@@ -215,7 +478,7 @@ func TestPushCommand(t *testing.T) {
 				"app-name",
 				"--manifest", "testdata/manifest-services.yaml",
 			},
-			srcImageBuilder: func(dir, srcImage string, rebase bool, filter func(path string) (bool, error)) error {
+			srcImageBuilder: func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
 				cwd, err := os.Getwd()
 				testutil.AssertNil(t, "cwd err", err)
 				testutil.AssertEqual(t, "path", cwd, dir)
@@ -253,6 +516,7 @@ func TestPushCommand(t *testing.T) {
 			args: []string{
 				"buildpack-app",
 				"--manifest", "testdata/manifest.yml",
+				"--path", "testdata",
 			},
 			targetSpace: &v1alpha1.Space{
 				Spec: v1alpha1.SpaceSpec{
@@ -271,7 +535,7 @@ func TestPushCommand(t *testing.T) {
 			namespace: "some-namespace",
 			args:      []string{"app-name"},
 			wantErr:   errors.New("some error"),
-			srcImageBuilder: func(dir, srcImage string, rebase bool, filter func(path string) (bool, error)) error {
+			srcImageBuilder: func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
 				return errors.New("some error")
 			},
 		},
@@ -336,6 +600,37 @@ func TestPushCommand(t *testing.T) {
 			},
 			wantErr: errors.New("cannot use path and docker image simultaneously"),
 		},
+		"processes from Procfile": {
+			namespace: "some-namespace",
+			args: []string{
+				"procfile-app",
+				"--path", "testdata/procfile-app",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushArgs([]string{"bundle exec puma -C config/puma.rb"}),
+				apps.WithPushProcesses([]v1alpha1.AppSpecProcess{
+					{Type: "web", Command: "bundle exec puma -C config/puma.rb"},
+					{Type: "worker", Command: "bundle exec sidekiq"},
+				}),
+			),
+		},
+		"command overrides Procfile": {
+			namespace: "some-namespace",
+			args: []string{
+				"procfile-app",
+				"--path", "testdata/procfile-app",
+				"--command", "bin/start",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushArgs([]string{"bin/start"}),
+				apps.WithPushProcesses([]v1alpha1.AppSpecProcess{
+					{Type: "web", Command: "bundle exec puma -C config/puma.rb"},
+					{Type: "worker", Command: "bundle exec sidekiq"},
+				}),
+			),
+		},
 		"docker app from manifest": {
 			namespace: "some-namespace",
 			args: []string{
@@ -352,12 +647,243 @@ func TestPushCommand(t *testing.T) {
 			args: []string{
 				"buildpack-app",
 				"--manifest", "testdata/manifest.yml",
+				"--path", "testdata",
 			},
 			wantOpts: append(defaultOptions,
 				apps.WithPushNamespace("some-namespace"),
 				apps.WithPushBuildpack("java,tomcat"),
 			),
 		},
+		"failing pre-push hook": {
+			namespace: "some-namespace",
+			args: []string{
+				"failing-pre-push-app",
+				"--manifest", "testdata/manifest.yml",
+			},
+			wantErr: errors.New(`pre-push hook "exit 1" failed: exit status 1`),
+		},
+		"skip hooks": {
+			namespace: "some-namespace",
+			args: []string{
+				"failing-pre-push-app",
+				"--manifest", "testdata/manifest.yml",
+				"--no-hooks",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushContainerImage("gcr.io/failing-pre-push-app"),
+			),
+		},
+		"post-deploy task": {
+			namespace: "some-namespace",
+			args: []string{
+				"post-deploy-task-app",
+				"--manifest", "testdata/manifest.yml",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushContainerImage("gcr.io/post-deploy-task-app"),
+			),
+			existingApp: func(t *testing.T) *v1alpha1.App {
+				app := &v1alpha1.App{}
+				app.Name = "post-deploy-task-app"
+				app.Status.Image = "gcr.io/post-deploy-task-app"
+				return app
+			},
+			setupHooks: func(t *testing.T, a *appsfake.FakeClient, r *hooksfake.FakeTaskRunner) {
+				r.EXPECT().
+					Run(gomock.Any(), gomock.Any(), gomock.Any(), "bundle exec rake db:migrate").
+					Return(nil)
+			},
+		},
+		"post-deploy task runs process": {
+			namespace: "some-namespace",
+			args: []string{
+				"release-task-app",
+				"--manifest", "testdata/manifest.yml",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushContainerImage("gcr.io/release-task-app"),
+			),
+			existingApp: func(t *testing.T) *v1alpha1.App {
+				app := &v1alpha1.App{}
+				app.Name = "release-task-app"
+				app.Status.Image = "gcr.io/release-task-app"
+				app.Spec.Processes = []v1alpha1.AppSpecProcess{
+					{Type: "release", Command: "bundle exec rake db:migrate"},
+				}
+				return app
+			},
+			setupHooks: func(t *testing.T, a *appsfake.FakeClient, r *hooksfake.FakeTaskRunner) {
+				r.EXPECT().
+					Run(gomock.Any(), gomock.Any(), gomock.Any(), "bundle exec rake db:migrate").
+					Return(nil)
+			},
+		},
+		"failing post-deploy task rolls back": {
+			namespace: "some-namespace",
+			args: []string{
+				"post-deploy-task-app",
+				"--manifest", "testdata/manifest.yml",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushContainerImage("gcr.io/post-deploy-task-app"),
+			),
+			existingApp: func(t *testing.T) *v1alpha1.App {
+				app := &v1alpha1.App{}
+				app.Name = "post-deploy-task-app"
+				app.Status.Image = "gcr.io/post-deploy-task-app@sha256:old"
+				return app
+			},
+			setupHooks: func(t *testing.T, a *appsfake.FakeClient, r *hooksfake.FakeTaskRunner) {
+				r.EXPECT().
+					Run(gomock.Any(), gomock.Any(), gomock.Any(), "bundle exec rake db:migrate").
+					Return(errors.New("task post-deploy-task-app-task-abcde failed: bundle exec rake db:migrate"))
+
+				a.EXPECT().
+					Transform("some-namespace", "post-deploy-task-app", gomock.Any()).
+					Do(func(_, _ string, mutator apps.Mutator) {
+						app := &v1alpha1.App{}
+						testutil.AssertNil(t, "mutator err", mutator(app))
+						testutil.AssertEqual(t, "image", "gcr.io/post-deploy-task-app@sha256:old", app.Spec.Source.ContainerImage.Image)
+					})
+			},
+			wantErr: errors.New("task post-deploy-task-app-task-abcde failed: bundle exec rake db:migrate, rolled back to gcr.io/post-deploy-task-app@sha256:old"),
+		},
+		"rolling push shifts traffic in steps": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--docker-image", "some-image",
+				"--strategy", "rolling",
+				"--step-percent", "50",
+				"--step-interval", "0s",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushContainerImage("some-image"),
+				apps.WithPushRollout(&v1alpha1.AppSpecRollout{StableRevisionName: "app-name-00001"}),
+			),
+			existingApp: func(t *testing.T) *v1alpha1.App {
+				app := &v1alpha1.App{}
+				app.Name = "app-name"
+				app.Status.Image = "some-old-image"
+				app.Status.LatestReadyRevisionName = "app-name-00001"
+				return app
+			},
+			setupHooks: func(t *testing.T, a *appsfake.FakeClient, r *hooksfake.FakeTaskRunner) {
+				deployed := &v1alpha1.App{}
+				deployed.Status.LatestReadyRevisionName = "app-name-00002"
+
+				gomock.InOrder(
+					a.EXPECT().
+						WaitForConditionKnativeServiceReadyTrue(gomock.Any(), "some-namespace", "app-name", gomock.Any()).
+						Return(deployed, nil),
+					a.EXPECT().
+						Transform("some-namespace", "app-name", gomock.Any()).
+						Do(func(_, _ string, mutator apps.Mutator) {
+							app := &v1alpha1.App{}
+							app.Spec.Rollout = &v1alpha1.AppSpecRollout{StableRevisionName: "app-name-00001"}
+							testutil.AssertNil(t, "mutator err", mutator(app))
+							testutil.AssertEqual(t, "latest percent", 50, app.Spec.Rollout.LatestPercent)
+						}),
+					a.EXPECT().
+						WaitForConditionKnativeServiceReadyTrue(gomock.Any(), "some-namespace", "app-name", gomock.Any()).
+						Return(deployed, nil),
+					a.EXPECT().
+						Transform("some-namespace", "app-name", gomock.Any()).
+						Do(func(_, _ string, mutator apps.Mutator) {
+							app := &v1alpha1.App{}
+							app.Spec.Rollout = &v1alpha1.AppSpecRollout{StableRevisionName: "app-name-00001", LatestPercent: 50}
+							testutil.AssertNil(t, "mutator err", mutator(app))
+							testutil.AssertEqual(t, "rollout", (*v1alpha1.AppSpecRollout)(nil), app.Spec.Rollout)
+						}),
+				)
+			},
+		},
+		"failing rolling push rolls back": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--docker-image", "some-image",
+				"--strategy", "rolling",
+				"--step-interval", "0s",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushContainerImage("some-image"),
+				apps.WithPushRollout(&v1alpha1.AppSpecRollout{StableRevisionName: "app-name-00001"}),
+			),
+			existingApp: func(t *testing.T) *v1alpha1.App {
+				app := &v1alpha1.App{}
+				app.Name = "app-name"
+				app.Status.Image = "some-old-image"
+				app.Status.LatestReadyRevisionName = "app-name-00001"
+				return app
+			},
+			setupHooks: func(t *testing.T, a *appsfake.FakeClient, r *hooksfake.FakeTaskRunner) {
+				gomock.InOrder(
+					a.EXPECT().
+						WaitForConditionKnativeServiceReadyTrue(gomock.Any(), "some-namespace", "app-name", gomock.Any()).
+						Return(nil, errors.New("revision app-name-00002 failed")),
+					a.EXPECT().
+						Transform("some-namespace", "app-name", gomock.Any()).
+						Do(func(_, _ string, mutator apps.Mutator) {
+							app := &v1alpha1.App{}
+							testutil.AssertNil(t, "mutator err", mutator(app))
+							testutil.AssertEqual(t, "image", "some-old-image", app.Spec.Source.ContainerImage.Image)
+							testutil.AssertEqual(t, "rollout", &v1alpha1.AppSpecRollout{StableRevisionName: "app-name-00001"}, app.Spec.Rollout)
+						}),
+					a.EXPECT().
+						WaitForConditionKnativeServiceReadyTrue(gomock.Any(), "some-namespace", "app-name", gomock.Any()),
+					a.EXPECT().
+						Transform("some-namespace", "app-name", gomock.Any()),
+				)
+			},
+			wantErr: errors.New("revision app-name-00002 failed, rolled back to some-old-image"),
+		},
+		"rolling push without a running revision": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--docker-image", "some-image",
+				"--strategy", "rolling",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushContainerImage("some-image"),
+			),
+		},
+		"step flags without rolling strategy": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--docker-image", "some-image",
+				"--step-percent", "10",
+			},
+			wantErr: errors.New("--step-percent can only be used with --strategy rolling"),
+		},
+		"invalid strategy": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--docker-image", "some-image",
+				"--strategy", "blue-green",
+			},
+			wantErr: errors.New("--strategy must be default or rolling"),
+		},
+		"step percent out of range": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--docker-image", "some-image",
+				"--strategy", "rolling",
+				"--step-percent", "0",
+			},
+			wantErr: errors.New("--step-percent must be between 1 and 100"),
+		},
 		"manifest missing app": {
 			namespace: "some-namespace",
 			args: []string{
@@ -408,6 +934,7 @@ func TestPushCommand(t *testing.T) {
 				apps.WithPushNamespace("some-namespace"),
 				apps.WithPushRoutes(nil),
 				apps.WithPushDefaultRouteDomain(""),
+				apps.WithPushNoRoute(true),
 			),
 		},
 		"no-route overrides manifest": {
@@ -421,6 +948,7 @@ func TestPushCommand(t *testing.T) {
 				apps.WithPushNamespace("some-namespace"),
 				apps.WithPushRoutes(nil),
 				apps.WithPushDefaultRouteDomain(""),
+				apps.WithPushNoRoute(true),
 			),
 		},
 		"random-route and no-route both set": {
@@ -446,6 +974,15 @@ func TestPushCommand(t *testing.T) {
 				apps.WithPushDefaultRouteDomain(""),
 			),
 		},
+		"no-route and route flags both set": {
+			namespace: "some-namespace",
+			args: []string{
+				"routes-app",
+				"--no-route",
+				"--route", "example.com",
+			},
+			wantErr: errors.New("--no-route can't be used with --route"),
+		},
 		"create and map routes from flags": {
 			namespace: "some-namespace",
 			args: []string{
@@ -496,6 +1033,64 @@ func TestPushCommand(t *testing.T) {
 				}),
 			),
 		},
+		"health check from space defaults": {
+			namespace: "some-namespace",
+			args: []string{
+				"dockerfile-app",
+				"--path", "testdata",
+			},
+			targetSpace: &v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						Domains: defaultSpaceSpecExecution.Domains,
+						DefaultHealthCheck: v1alpha1.SpaceDefaultHealthCheck{
+							Type:                  "http",
+							HTTPEndpoint:          "/healthz",
+							StartupTimeoutSeconds: 180,
+						},
+					},
+				},
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushDockerfilePath("Dockerfile"),
+				apps.WithPushHealthCheck(&corev1.Probe{
+					TimeoutSeconds: 180,
+					Handler: corev1.Handler{
+						HTTPGet: &corev1.HTTPGetAction{Path: "/healthz"},
+					},
+				}),
+			),
+		},
+		"app health check overrides space defaults": {
+			namespace: "some-namespace",
+			args: []string{
+				"tcp-health-check-app",
+				"--manifest", "testdata/manifest.yml",
+			},
+			targetSpace: &v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						Domains: defaultSpaceSpecExecution.Domains,
+						DefaultHealthCheck: v1alpha1.SpaceDefaultHealthCheck{
+							Type:                  "http",
+							HTTPEndpoint:          "/healthz",
+							StartupTimeoutSeconds: 180,
+						},
+					},
+				},
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushContainerImage("gcr.io/tcp-health-check-app"),
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushHealthCheck(&corev1.Probe{
+					TimeoutSeconds: 33,
+					Handler: corev1.Handler{
+						TCPSocket: &corev1.TCPSocketAction{},
+					},
+				}),
+			),
+		},
 		"bad timeout": {
 			namespace: "some-namespace",
 			args: []string{
@@ -538,6 +1133,47 @@ func TestPushCommand(t *testing.T) {
 				apps.WithPushDockerfilePath("Dockerfile"),
 			),
 		},
+		"dockerfile build args": {
+			namespace: "some-namespace",
+			args: []string{
+				"dockerfile-app",
+				"--path", "testdata",
+				"--build-arg", "VERSION=1.2.3",
+				"--build-arg", "FLAGS=--opt=1",
+				"--build-arg-from-secret", "NPM_TOKEN=npm-creds:token",
+			},
+			wantOpts: append(defaultOptions,
+				apps.WithPushNamespace("some-namespace"),
+				apps.WithPushDockerfilePath("Dockerfile"),
+				apps.WithPushDockerfileBuildArgs([]corev1.EnvVar{
+					{Name: "FLAGS", Value: "--opt=1"},
+					{Name: "NPM_TOKEN", ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "npm-creds"},
+							Key:                  "token",
+						},
+					}},
+					{Name: "VERSION", Value: "1.2.3"},
+				}),
+			),
+		},
+		"malformed build arg": {
+			namespace: "some-namespace",
+			args: []string{
+				"dockerfile-app",
+				"--path", "testdata",
+				"--build-arg", "VERSION",
+			},
+			wantErr: errors.New("malformed build arg: VERSION"),
+		},
+		"build args without dockerfile": {
+			namespace: "some-namespace",
+			args: []string{
+				"app-name",
+				"--build-arg", "VERSION=1.2.3",
+			},
+			wantErr: errors.New("build args can only be used when building a Dockerfile: docker.build_args"),
+		},
 		"provided dockerfile": {
 			namespace: "some-namespace",
 			args: []string{
@@ -552,13 +1188,27 @@ func TestPushCommand(t *testing.T) {
 	} {
 		t.Run(tn, func(t *testing.T) {
 			if tc.srcImageBuilder == nil {
-				tc.srcImageBuilder = func(dir, srcImage string, rebase bool, filter func(path string) (bool, error)) error { return nil }
+				tc.srcImageBuilder = func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
+					return nil
+				}
 			}
 
 			ctrl := gomock.NewController(t)
 			fakeApps := appsfake.NewFakeClient(ctrl)
 			fakePusher := appsfake.NewFakePusher(ctrl)
 			svbClient := svbFake.NewFakeClientInterface(ctrl)
+			fakeTasks := hooksfake.NewFakeTaskRunner(ctrl)
+
+			var existingApp *v1alpha1.App
+			var getErr error = errors.New("not found")
+			if tc.existingApp != nil {
+				existingApp, getErr = tc.existingApp(t), nil
+			}
+			fakeApps.
+				EXPECT().
+				Get(gomock.Any(), gomock.Any()).
+				Return(existingApp, getErr).
+				AnyTimes()
 
 			fakePusher.
 				EXPECT().
@@ -578,9 +1228,16 @@ func TestPushCommand(t *testing.T) {
 					testutil.AssertEqual(t, "health check", expectOpts.HealthCheck(), actualOpts.HealthCheck())
 					testutil.AssertEqual(t, "default route", expectOpts.DefaultRouteDomain(), actualOpts.DefaultRouteDomain())
 					testutil.AssertEqual(t, "random route", expectOpts.RandomRouteDomain(), actualOpts.RandomRouteDomain())
+					testutil.AssertEqual(t, "no route", expectOpts.NoRoute(), actualOpts.NoRoute())
 					testutil.AssertEqual(t, "command", expectOpts.Command(), actualOpts.Command())
 					testutil.AssertEqual(t, "args", expectOpts.Args(), actualOpts.Args())
 					testutil.AssertEqual(t, "Dockerfile path", expectOpts.DockerfilePath(), actualOpts.DockerfilePath())
+					testutil.AssertEqual(t, "Dockerfile build args", expectOpts.DockerfileBuildArgs(), actualOpts.DockerfileBuildArgs())
+					testutil.AssertEqual(t, "force build", expectOpts.ForceBuild(), actualOpts.ForceBuild())
+					testutil.AssertEqual(t, "binding format", expectOpts.BindingFormat(), actualOpts.BindingFormat())
+					testutil.AssertEqual(t, "timeouts", expectOpts.Timeouts(), actualOpts.Timeouts())
+					testutil.AssertEqual(t, "processes", expectOpts.Processes(), actualOpts.Processes())
+					testutil.AssertEqual(t, "rollout", expectOpts.Rollout(), actualOpts.Rollout())
 
 					if !strings.HasPrefix(actualOpts.SourceImage(), tc.wantImagePrefix) {
 						t.Errorf("Wanted srcImage to start with %s got: %s", tc.wantImagePrefix, actualOpts.SourceImage())
@@ -604,7 +1261,19 @@ func TestPushCommand(t *testing.T) {
 				tc.setup(t, svbClient)
 			}
 
-			c := NewPushCommand(params, fakeApps, fakePusher, tc.srcImageBuilder, svbClient)
+			if tc.setupHooks != nil {
+				tc.setupHooks(t, fakeApps, fakeTasks)
+			}
+
+			loadTracingConfig := func() (*tracing.Config, error) {
+				return &tracing.Config{}, nil
+			}
+
+			// Explanations are covered by TestExplainPushFailure.
+			fakeExplainer := failuresfake.NewFakeExplainer(ctrl)
+			fakeExplainer.EXPECT().Explain(gomock.Any(), gomock.Any(), gomock.Any()).AnyTimes()
+
+			c := NewPushCommand(params, fakeApps, fakePusher, tc.srcImageBuilder, svbClient, fakeTasks, stacksfake.NewFakeClient(ctrl), loadTracingConfig, fakeExplainer)
 			buffer := &bytes.Buffer{}
 			c.SetOutput(buffer)
 			c.SetArgs(tc.args)
@@ -641,3 +1310,202 @@ func buildTestRoutes() []v1alpha1.RouteSpecFields {
 func intPtr(i int) *int {
 	return &i
 }
+
+func mustHealthCheck(healthCheckType, endpoint, expect string, timeout int) *corev1.Probe {
+	probe, err := apps.NewHealthCheck(healthCheckType, endpoint, expect, timeout)
+	if err != nil {
+		panic(err)
+	}
+	return probe
+}
+
+func TestExplainPushFailure(t *testing.T) {
+	t.Parallel()
+
+	for tn, tc := range map[string]struct {
+		err         error
+		wantExplain bool
+		wantOutput  string
+	}{
+		"build failure": {
+			err:         machine.WithExitCode(machine.ExitBuildFailed, errors.New("build failed: step exited 1")),
+			wantExplain: true,
+			wantOutput:  "Pushing my-app failed: build failed: step exited 1\nsome-explanation\n",
+		},
+		"deploy failure": {
+			err:         machine.WithExitCode(machine.ExitDeployFailed, errors.New("deployment failed: crashed")),
+			wantExplain: true,
+			wantOutput:  "Pushing my-app failed: deployment failed: crashed\nsome-explanation\n",
+		},
+		"other errors aren't explained": {
+			err: errors.New("failed to push app: some-error"),
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeExplainer := failuresfake.NewFakeExplainer(ctrl)
+			if tc.wantExplain {
+				fakeExplainer.EXPECT().
+					Explain(gomock.Any(), "some-namespace", "my-app").
+					DoAndReturn(func(out io.Writer, _, _ string) error {
+						fmt.Fprintln(out, "some-explanation")
+						return nil
+					})
+			}
+
+			buffer := &bytes.Buffer{}
+			explainPushFailure(buffer, fakeExplainer, "some-namespace", "my-app", tc.err)
+
+			testutil.AssertEqual(t, "output", tc.wantOutput, buffer.String())
+			ctrl.Finish()
+		})
+	}
+}
+
+func TestPushCommand_dryRun(t *testing.T) {
+	t.Parallel()
+
+	space := &v1alpha1.Space{}
+	space.Name = "some-namespace"
+	space.Spec.BuildpackBuild.ContainerRegistry = "some-reg.io"
+	space.Spec.Execution = v1alpha1.SpaceSpecExecution{
+		Env: []corev1.EnvVar{
+			{Name: "FROM_SPACE", Value: "space-value"},
+			{Name: "SHARED", Value: "space-value"},
+		},
+		Domains: []v1alpha1.SpaceDomain{
+			{Domain: "example.com", Default: true},
+		},
+	}
+
+	previewApp := func(appName string, opts ...apps.PushOption) (*v1alpha1.App, error) {
+		cfg := apps.PushOptions(opts)
+
+		app := &v1alpha1.App{}
+		app.Name = appName
+		app.Namespace = cfg.Namespace()
+		app.Spec.Source.BuildpackBuild.Source = cfg.SourceImage()
+		app.Spec.Source.BuildpackBuild.Stack = "cflinuxfs3"
+		app.Spec.Template.Spec.Containers = []corev1.Container{{
+			Env: []corev1.EnvVar{{Name: "SHARED", Value: "app-value"}},
+		}}
+		app.Spec.Routes = []v1alpha1.RouteSpecFields{
+			buildRoute(appName, "example.com", ""),
+		}
+		return app, nil
+	}
+
+	for tn, tc := range map[string]struct {
+		args       []string
+		setup      func(t *testing.T, pusher *appsfake.FakePusher, stacksClient *stacksfake.FakeClient)
+		wantErr    error
+		wantOutput []string
+	}{
+		"summarizes the resources": {
+			args: []string{"example-app", "--dry-run", "--path", "testdata/example-app"},
+			setup: func(t *testing.T, pusher *appsfake.FakePusher, stacksClient *stacksfake.FakeClient) {
+				pusher.EXPECT().
+					Preview("example-app", gomock.Any()).
+					DoAndReturn(func(appName string, opts ...apps.PushOption) (*v1alpha1.App, error) {
+						testutil.AssertEqual(t, "source image",
+							"some-reg.io/src-some-namespace-example-app:dry-run",
+							apps.PushOptions(opts).SourceImage())
+						return previewApp(appName, opts...)
+					})
+				stacksClient.EXPECT().List().Return([]stacks.Stack{{
+					Name:       "cflinuxfs3",
+					BuildImage: "cflinuxfs3-build",
+					RunImage:   "cflinuxfs3-run",
+				}}, nil)
+			},
+			wantOutput: []string{
+				"Dry run, App example-app would be created in space some-namespace:",
+				"some-reg.io/src-some-namespace-example-app:dry-run",
+				"cflinuxfs3-run",
+				"cflinuxfs3-build",
+				"example-app",
+				"example.com",
+				"FROM_SPACE", "(space)",
+				"SHARED", "app-value", "(app)",
+			},
+		},
+		"prints manifests": {
+			args: []string{"example-app", "--dry-run", "--path", "testdata/example-app", "-o", "yaml"},
+			setup: func(t *testing.T, pusher *appsfake.FakePusher, stacksClient *stacksfake.FakeClient) {
+				pusher.EXPECT().Preview("example-app", gomock.Any()).DoAndReturn(previewApp)
+				stacksClient.EXPECT().List()
+			},
+			wantOutput: []string{
+				"kind: App",
+				"kind: Source",
+				"kind: Route",
+				"kind: RouteClaim",
+			},
+		},
+		"preview fails": {
+			args: []string{"example-app", "--dry-run", "--path", "testdata/example-app"},
+			setup: func(t *testing.T, pusher *appsfake.FakePusher, stacksClient *stacksfake.FakeClient) {
+				pusher.EXPECT().Preview("example-app", gomock.Any()).Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("some-error"),
+		},
+		"listing stacks fails": {
+			args: []string{"example-app", "--dry-run", "--path", "testdata/example-app"},
+			setup: func(t *testing.T, pusher *appsfake.FakePusher, stacksClient *stacksfake.FakeClient) {
+				pusher.EXPECT().Preview("example-app", gomock.Any()).DoAndReturn(previewApp)
+				stacksClient.EXPECT().List().Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("couldn't get the cluster's stacks: some-error"),
+		},
+		"output without dry run": {
+			args:    []string{"example-app", "--path", "testdata/example-app", "-o", "yaml"},
+			wantErr: errors.New("--output can only be used with --dry-run"),
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeApps := appsfake.NewFakeClient(ctrl)
+			fakePusher := appsfake.NewFakePusher(ctrl)
+			fakeStacks := stacksfake.NewFakeClient(ctrl)
+
+			// Nothing is pushed so only the preview is expected.
+			if tc.setup != nil {
+				tc.setup(t, fakePusher, fakeStacks)
+			}
+
+			params := &config.KfParams{
+				Namespace:   "some-namespace",
+				TargetSpace: space,
+			}
+
+			srcImageBuilder := SrcImageBuilderFunc(func(ctx context.Context, out io.Writer, dir, srcImage string, filter func(path string) (bool, error)) error {
+				t.Fatal("source shouldn't be packaged during a dry run")
+				return nil
+			})
+
+			loadTracingConfig := func() (*tracing.Config, error) {
+				return &tracing.Config{}, nil
+			}
+
+			c := NewPushCommand(
+				params,
+				fakeApps,
+				fakePusher,
+				srcImageBuilder,
+				svbFake.NewFakeClientInterface(ctrl),
+				hooksfake.NewFakeTaskRunner(ctrl),
+				fakeStacks,
+				loadTracingConfig,
+				failuresfake.NewFakeExplainer(ctrl),
+			)
+			buffer := &bytes.Buffer{}
+			c.SetOutput(buffer)
+			c.SetArgs(tc.args)
+			gotErr := c.Execute()
+
+			testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+			testutil.AssertContainsAll(t, buffer.String(), tc.wantOutput)
+			ctrl.Finish()
+		})
+	}
+}
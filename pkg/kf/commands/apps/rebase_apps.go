@@ -0,0 +1,149 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/parallel"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/buildpacks"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/sources"
+	"github.com/spf13/cobra"
+)
+
+// NewRebaseAppsCommand creates a command that swaps the run image of buildpack
+// apps without rebuilding them.
+func NewRebaseAppsCommand(
+	p *config.KfParams,
+	appsClient apps.Client,
+	sourcesClient sources.Client,
+	rebaser buildpacks.Rebaser,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "rebase-apps [SPACE]",
+		Short:   "Move buildpack apps onto the latest run image of their stack",
+		Example: `kf rebase-apps my-space`,
+		Long: `Rebase swaps the layers buildpack apps got from their stack's run image
+		with the layers of the current run image, then restarts the apps that
+		changed. Apps aren't rebuilt, so patches to the run image like CVE fixes
+		roll out in seconds.
+
+		The run image is the one the app was last built with. Apps that aren't
+		built with buildpacks are skipped.
+
+		Rebasing writes to the space's container registry using your local
+		Docker credentials.
+		`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				p.Namespace = args[0]
+			}
+
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			appList, err := appsClient.List(p.Namespace)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			fmt.Fprintf(out, "Rebasing apps in space %s\n", p.Namespace)
+
+			var toRebase []v1alpha1.App
+			for _, app := range appList {
+				if !app.Spec.Source.IsBuildpackBuild() || app.Status.Image == "" || app.Status.LatestReadySourceName == "" {
+					continue
+				}
+
+				toRebase = append(toRebase, app)
+			}
+
+			// Rebasing is mostly waiting on the registry, so apps are rebased
+			// concurrently and the results are printed in order afterwards.
+			rebased := make([]bool, len(toRebase))
+			errs := make([]error, len(toRebase))
+			parallel.ForEach(len(toRebase), parallel.DefaultLimit, func(i int) {
+				app := toRebase[i]
+				rebased[i], errs[i] = rebaseApp(appsClient, sourcesClient, rebaser, app.Namespace, app.Name, app.Status.Image, app.Status.LatestReadySourceName)
+			})
+
+			failures := 0
+			for i, app := range toRebase {
+				switch {
+				case errs[i] != nil:
+					failures++
+					fmt.Fprintf(out, "%s: failed: %s\n", app.Name, errs[i])
+				case rebased[i]:
+					fmt.Fprintf(out, "%s: rebased and restarting\n", app.Name)
+				default:
+					fmt.Fprintf(out, "%s: already up to date\n", app.Name)
+				}
+			}
+
+			if failures > 0 {
+				return fmt.Errorf("failed to rebase %d app(s)", failures)
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// rebaseApp rebases the app's image onto the run image of the Source it was
+// built from and restarts it if the image changed.
+func rebaseApp(
+	appsClient apps.Client,
+	sourcesClient sources.Client,
+	rebaser buildpacks.Rebaser,
+	namespace, appName, image, sourceName string,
+) (bool, error) {
+	source, err := sourcesClient.Get(namespace, sourceName)
+	if err != nil {
+		return false, err
+	}
+
+	runImage := source.Spec.BuildpackBuild.Stack
+	if runImage == "" {
+		return false, fmt.Errorf("source %s has no run image", sourceName)
+	}
+
+	digest, err := rebaser.Rebase(image, runImage)
+	if err != nil || digest == "" {
+		return false, err
+	}
+
+	// Apps run the image by digest, so the rebased image only rolls out once
+	// the Source records its digest and the App is reconciled again.
+	if err := sourcesClient.SetImageDigest(namespace, sourceName, digest); err != nil {
+		return false, fmt.Errorf("rebased but failed to record the new digest: %s", err)
+	}
+
+	if err := appsClient.Restart(namespace, appName); err != nil {
+		return false, fmt.Errorf("rebased but failed to restart: %s", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	appsfake "github.com/google/kf/pkg/kf/apps/fake"
+	buildpacksfake "github.com/google/kf/pkg/kf/buildpacks/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	sourcesfake "github.com/google/kf/pkg/kf/sources/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRebaseApps(t *testing.T) {
+	t.Parallel()
+
+	buildpackApp := func(name string) v1alpha1.App {
+		app := v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "my-space"},
+		}
+		app.Spec.Source.BuildpackBuild.Source = "gcr.io/source"
+		app.Status.Image = "gcr.io/" + name
+		app.Status.LatestReadySourceName = name + "-1"
+		return app
+	}
+
+	source := func(runImage string) *v1alpha1.Source {
+		s := &v1alpha1.Source{}
+		s.Spec.BuildpackBuild.Stack = runImage
+		return s
+	}
+
+	type fakes struct {
+		apps    *appsfake.FakeClient
+		sources *sourcesfake.FakeClient
+		rebaser *buildpacksfake.FakeRebaser
+	}
+
+	cases := map[string]struct {
+		Namespace       string
+		Args            []string
+		ExpectedStrings []string
+		ExpectedErr     error
+		Setup           func(t *testing.T, f fakes)
+	}{
+		"too many args": {
+			Args:        []string{"a", "b"},
+			ExpectedErr: errors.New("accepts at most 1 arg(s), received 2"),
+		},
+		"listing fails": {
+			Namespace:   "my-space",
+			ExpectedErr: errors.New("some-error"),
+			Setup: func(t *testing.T, f fakes) {
+				f.apps.EXPECT().List("my-space").Return(nil, errors.New("some-error"))
+			},
+		},
+		"space from argument": {
+			Namespace: "other-space",
+			Args:      []string{"my-space"},
+			Setup: func(t *testing.T, f fakes) {
+				f.apps.EXPECT().List("my-space")
+			},
+			ExpectedStrings: []string{"Rebasing apps in space my-space"},
+		},
+		"rebases and restarts apps": {
+			Namespace: "my-space",
+			Setup: func(t *testing.T, f fakes) {
+				containerApp := v1alpha1.App{}
+				containerApp.Name = "container-app"
+				containerApp.Spec.Source.ContainerImage.Image = "nginx"
+
+				f.apps.EXPECT().List("my-space").Return([]v1alpha1.App{
+					buildpackApp("app-a"),
+					buildpackApp("app-b"),
+					containerApp,
+				}, nil)
+
+				f.sources.EXPECT().Get("my-space", "app-a-1").Return(source("gcr.io/run"), nil)
+				f.rebaser.EXPECT().Rebase("gcr.io/app-a", "gcr.io/run").Return("sha256:rebased", nil)
+				gomock.InOrder(
+					f.sources.EXPECT().SetImageDigest("my-space", "app-a-1", "sha256:rebased"),
+					f.apps.EXPECT().Restart("my-space", "app-a"),
+				)
+
+				f.sources.EXPECT().Get("my-space", "app-b-1").Return(source("gcr.io/run"), nil)
+				f.rebaser.EXPECT().Rebase("gcr.io/app-b", "gcr.io/run").Return("", nil)
+			},
+			ExpectedStrings: []string{
+				"app-a: rebased and restarting",
+				"app-b: already up to date",
+			},
+		},
+		"failures are reported": {
+			Namespace:   "my-space",
+			ExpectedErr: errors.New("failed to rebase 3 app(s)"),
+			Setup: func(t *testing.T, f fakes) {
+				f.apps.EXPECT().List("my-space").Return([]v1alpha1.App{
+					buildpackApp("app-a"),
+					buildpackApp("app-b"),
+					buildpackApp("app-c"),
+				}, nil)
+
+				f.sources.EXPECT().Get("my-space", "app-a-1").Return(source(""), nil)
+
+				f.sources.EXPECT().Get("my-space", "app-b-1").Return(source("gcr.io/run"), nil)
+				f.rebaser.EXPECT().Rebase("gcr.io/app-b", "gcr.io/run").Return("sha256:rebased-b", nil)
+				f.sources.EXPECT().SetImageDigest("my-space", "app-b-1", "sha256:rebased-b")
+				f.apps.EXPECT().Restart("my-space", "app-b").Return(errors.New("some-error"))
+
+				f.sources.EXPECT().Get("my-space", "app-c-1").Return(source("gcr.io/run"), nil)
+				f.rebaser.EXPECT().Rebase("gcr.io/app-c", "gcr.io/run").Return("sha256:rebased-c", nil)
+				f.sources.EXPECT().SetImageDigest("my-space", "app-c-1", "sha256:rebased-c").Return(errors.New("some-error"))
+			},
+			ExpectedStrings: []string{
+				"app-a: failed: source app-a-1 has no run image",
+				"app-b: failed: rebased but failed to restart: some-error",
+				"app-c: failed: rebased but failed to record the new digest: some-error",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			f := fakes{
+				apps:    appsfake.NewFakeClient(ctrl),
+				sources: sourcesfake.NewFakeClient(ctrl),
+				rebaser: buildpacksfake.NewFakeRebaser(ctrl),
+			}
+
+			if tc.Setup != nil {
+				tc.Setup(t, f)
+			}
+
+			buf := new(bytes.Buffer)
+			p := &config.KfParams{
+				Namespace: tc.Namespace,
+			}
+
+			cmd := NewRebaseAppsCommand(p, f.apps, f.sources, f.rebaser)
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+
+			ctrl.Finish()
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/report"
+	"github.com/spf13/cobra"
+)
+
+// NewReportCommand creates a command that collects a support bundle for an
+// app.
+func NewReportCommand(p *config.KfParams, client report.Client) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "report APP_NAME",
+		Short: "Collect a support bundle for an app",
+		Long: `Collects a support bundle for an app to attach to support tickets.
+
+		The bundle is a tarball containing the app, its sources, builds and
+		build logs, pods, routes, VirtualServices, recent events and the
+		controller logs. Values of environment variables and build arguments
+		that look like secrets, and service binding parameters, are redacted.
+		Values of the Secrets and service bindings the app reads are redacted
+		everywhere they appear, including logs. Review the bundle before
+		sharing it.
+		`,
+		Example: `
+		kf report myapp
+		kf report myapp -o myapp-report.tar.gz
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			appName := args[0]
+			if output == "" {
+				output = fmt.Sprintf("kf-report-%s-%s.tar.gz", appName, time.Now().Format("20060102-150405"))
+			}
+
+			f, err := os.Create(output)
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "Collecting report for app %s in namespace: %s\n", appName, p.Namespace)
+
+			if err := client.Collect(f, p.Namespace, appName); err != nil {
+				f.Close()
+				os.Remove(output)
+				return fmt.Errorf("failed to collect report: %s", err)
+			}
+
+			if err := f.Close(); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote report to %s\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		"",
+		"Path to write the report to (default: kf-report-APP_NAME-TIMESTAMP.tar.gz).",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
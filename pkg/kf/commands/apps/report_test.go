@@ -0,0 +1,112 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/report/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestReportCommand(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		namespace string
+		args      []string
+
+		wantErr      error
+		wantContents string
+		setup        func(t *testing.T, fc *fake.FakeClient)
+	}{
+		"writes report": {
+			namespace: "some-namespace",
+			args:      []string{"some-app"},
+
+			setup: func(t *testing.T, fc *fake.FakeClient) {
+				fc.EXPECT().
+					Collect(gomock.Any(), "some-namespace", "some-app").
+					DoAndReturn(func(w io.Writer, _, _ string) error {
+						_, err := w.Write([]byte("some-report"))
+						return err
+					})
+			},
+			wantContents: "some-report",
+		},
+		"collect error": {
+			namespace: "some-namespace",
+			args:      []string{"some-app"},
+
+			setup: func(t *testing.T, fc *fake.FakeClient) {
+				fc.EXPECT().Collect(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("some error"))
+			},
+			wantErr: errors.New("failed to collect report: some error"),
+		},
+		"bad namespace error": {
+			args: []string{"some-app"},
+
+			setup: func(t *testing.T, fc *fake.FakeClient) {
+				// expect no calls
+			},
+			wantErr: errors.New(utils.EmptyNamespaceError),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			fakeReporter := fake.NewFakeClient(ctrl)
+
+			tc.setup(t, fakeReporter)
+
+			dir, err := ioutil.TempDir("", "kf-report")
+			testutil.AssertNil(t, "temp dir error", err)
+			defer os.RemoveAll(dir)
+			output := filepath.Join(dir, "report.tar.gz")
+
+			buffer := &bytes.Buffer{}
+			c := NewReportCommand(&config.KfParams{
+				Namespace: tc.namespace,
+			}, fakeReporter)
+			c.SetOutput(buffer)
+			c.SetArgs(append(tc.args, "--output", output))
+			gotErr := c.Execute()
+
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+
+				_, statErr := os.Stat(output)
+				testutil.AssertEqual(t, "report removed", true, os.IsNotExist(statErr))
+				return
+			}
+
+			contents, err := ioutil.ReadFile(output)
+			testutil.AssertNil(t, "read error", err)
+			testutil.AssertEqual(t, "contents", tc.wantContents, string(contents))
+			testutil.AssertContainsAll(t, buffer.String(), []string{"Wrote report to " + output})
+		})
+	}
+}
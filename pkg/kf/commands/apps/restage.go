@@ -17,6 +17,7 @@ package apps
 import (
 	"fmt"
 
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
@@ -29,7 +30,10 @@ func NewRestageCommand(
 	p *config.KfParams,
 	client apps.Client,
 ) *cobra.Command {
-	var async utils.AsyncFlags
+	var (
+		async     utils.AsyncFlags
+		scheduled bool
+	)
 
 	cmd := &cobra.Command{
 		Use:     "restage APP_NAME",
@@ -46,7 +50,20 @@ func NewRestageCommand(
 
 			cmd.SilenceUsage = true
 
-			app, err := client.Restage(p.Namespace, appName)
+			var (
+				app *v1alpha1.App
+				err error
+			)
+			if scheduled {
+				app, err = client.Transform(p.Namespace, appName, func(app *v1alpha1.App) error {
+					v1alpha1.SetBuildPriority(app, v1alpha1.BuildPriorityScheduled)
+					app.Spec.Source.UpdateRequests++
+
+					return nil
+				})
+			} else {
+				app, err = client.Restage(p.Namespace, appName)
+			}
 			if err != nil {
 				return fmt.Errorf("failed to restage app: %s", err)
 			}
@@ -65,6 +82,13 @@ func NewRestageCommand(
 
 	async.Add(cmd)
 
+	cmd.Flags().BoolVar(
+		&scheduled,
+		"scheduled",
+		false,
+		"Queue the build behind interactive pushes if the space limits concurrent builds.",
+	)
+
 	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
 
 	return cmd
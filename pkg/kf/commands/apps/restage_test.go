@@ -20,6 +20,8 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/apps/fake"
 	"github.com/google/kf/pkg/kf/commands/config"
 	"github.com/google/kf/pkg/kf/testutil"
@@ -58,6 +60,21 @@ func TestRestage(t *testing.T) {
 					Return(nil, errors.New("some-error"))
 			},
 		},
+		"restages app scheduled": {
+			Namespace: "default",
+			Args:      []string{"--async", "--scheduled", "my-app"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					DoAndReturn(func(namespace, name string, mutator apps.Mutator) (*v1alpha1.App, error) {
+						app := &v1alpha1.App{}
+						testutil.AssertNil(t, "mutator error", mutator(app))
+						testutil.AssertEqual(t, "priority", v1alpha1.BuildPriorityScheduled, app.Annotations[v1alpha1.BuildPriorityAnnotation])
+						testutil.AssertEqual(t, "update requests", 1, app.Spec.Source.UpdateRequests)
+						return app, nil
+					})
+			},
+		},
 		"restages app async": {
 			Namespace: "default",
 			Args:      []string{"--async", "my-app"},
@@ -0,0 +1,122 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/events"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// NewRestartAppInstanceCommand creates a command that restarts a single
+// instance of an app.
+func NewRestartAppInstanceCommand(
+	p *config.KfParams,
+	appsClient apps.Client,
+	coreClient v1.CoreV1Interface,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "restart-app-instance APP_NAME INDEX",
+		Short:   "Restart a single instance of the app",
+		Example: `kf restart-app-instance myapp 0`,
+		Long: `Restart a single instance of the app without restarting the others.
+
+		The instance's pod is deleted and replaced by a new one that takes over
+		its index, indexes are shown in the kf.dev/instance-index annotation
+		on the app's pods.
+		`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			appName := args[0]
+			index, err := strconv.Atoi(args[1])
+			if err != nil || index < 0 {
+				return fmt.Errorf("invalid instance index %q, it must be a non-negative integer", args[1])
+			}
+
+			cmd.SilenceUsage = true
+
+			app, err := appsClient.Get(p.Namespace, appName)
+			if err != nil {
+				return err
+			}
+
+			pod, err := indexedInstance(coreClient, p.Namespace, appName, index)
+			if err != nil {
+				return err
+			}
+
+			ref := corev1.ObjectReference{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "App",
+				Namespace:  app.Namespace,
+				Name:       app.Name,
+				UID:        app.UID,
+			}
+			message := fmt.Sprintf("Instance %d (%s) restarted", index, pod)
+			if err := events.Create(coreClient, ref, "RestartInstance", message); err != nil {
+				return fmt.Errorf("failed to record audit event: %s", err)
+			}
+
+			if err := coreClient.Pods(p.Namespace).Delete(pod, &metav1.DeleteOptions{}); err != nil {
+				return fmt.Errorf("failed to restart instance: %s", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Restarting instance %d of app %q (pod %s)\n", index, appName, pod)
+			return nil
+		},
+	}
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+// indexedInstance gets the name of the pod running the app's instance with
+// the given index.
+func indexedInstance(coreClient v1.PodsGetter, namespace, appName string, index int) (string, error) {
+	pods, err := coreClient.Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: "serving.knative.dev/service=" + appName,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	want := strconv.Itoa(index)
+	for _, pod := range pods.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		if pod.Annotations[v1alpha1.InstanceIndexAnnotation] == want {
+			return pod.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("app %s has no instance with index %d", appName, index)
+}
@@ -0,0 +1,134 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/events"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRestartAppInstance(t *testing.T) {
+	t.Parallel()
+
+	appPod := func(name, index string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "my-space",
+				Labels:      map[string]string{"serving.knative.dev/service": "my-app"},
+				Annotations: map[string]string{v1alpha1.InstanceIndexAnnotation: index},
+			},
+		}
+	}
+
+	terminatingPod := appPod("terminating-pod", "1")
+	terminatingPod.DeletionTimestamp = &metav1.Time{}
+
+	setupApp := func(t *testing.T, fake *fake.FakeClient) {
+		app := &v1alpha1.App{}
+		app.Name = "my-app"
+		app.Namespace = "my-space"
+		fake.EXPECT().Get("my-space", "my-app").Return(app, nil)
+	}
+
+	cases := map[string]struct {
+		Args        []string
+		Pods        []runtime.Object
+		Setup       func(t *testing.T, fake *fake.FakeClient)
+		ExpectedErr error
+		WantDeleted string
+		WantOutput  string
+	}{
+		"missing index": {
+			Args:        []string{"my-app"},
+			ExpectedErr: errors.New("accepts 2 arg(s), received 1"),
+		},
+		"invalid index": {
+			Args:        []string{"my-app", "first"},
+			ExpectedErr: errors.New(`invalid instance index "first", it must be a non-negative integer`),
+		},
+		"getting app fails": {
+			Args: []string{"my-app", "0"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Get("my-space", "my-app").Return(nil, errors.New("some-error"))
+			},
+			ExpectedErr: errors.New("some-error"),
+		},
+		"no instance with index": {
+			Args:        []string{"my-app", "1"},
+			Pods:        []runtime.Object{appPod("pod-a", "0"), terminatingPod},
+			Setup:       setupApp,
+			ExpectedErr: errors.New("app my-app has no instance with index 1"),
+		},
+		"restarts instance": {
+			Args:        []string{"my-app", "1"},
+			Pods:        []runtime.Object{appPod("pod-a", "0"), appPod("pod-b", "1")},
+			Setup:       setupApp,
+			WantDeleted: "pod-b",
+			WantOutput:  `Restarting instance 1 of app "my-app" (pod pod-b)`,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeApps := fake.NewFakeClient(ctrl)
+			coreClient := k8sfake.NewSimpleClientset(tc.Pods...).CoreV1()
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeApps)
+			}
+
+			p := &config.KfParams{
+				Namespace: "my-space",
+			}
+
+			buffer := new(bytes.Buffer)
+			cmd := NewRestartAppInstanceCommand(p, fakeApps, coreClient)
+			cmd.SetOutput(buffer)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buffer.String(), []string{tc.WantOutput})
+
+			_, err := coreClient.Pods("my-space").Get(tc.WantDeleted, metav1.GetOptions{})
+			testutil.AssertEqual(t, "pod deleted", true, apierrs.IsNotFound(err))
+
+			auditEvents, err := events.List(coreClient, "my-space", "App", "my-app")
+			testutil.AssertNil(t, "events err", err)
+			testutil.AssertEqual(t, "audit events", 1, len(auditEvents))
+			testutil.AssertEqual(t, "audit reason", "RestartInstance", auditEvents[0].Reason)
+
+			ctrl.Finish()
+		})
+	}
+}
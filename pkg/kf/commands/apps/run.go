@@ -0,0 +1,187 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/events"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// PodAttacher attaches the given streams to a container in a pod.
+type PodAttacher func(namespace, pod, container string, stdin io.Reader, stdout, stderr io.Writer) error
+
+// KubectlPodAttacher creates a PodAttacher that uses kubectl attach with the
+// kubeconfig and impersonation settings in p.
+func KubectlPodAttacher(p *config.KfParams) PodAttacher {
+	return func(namespace, pod, container string, stdin io.Reader, stdout, stderr io.Writer) error {
+		args := kubectlArgs(p, "attach", "--stdin", "--tty", "--namespace", namespace, "--container", container)
+		args = append(args, pod)
+
+		cmd := exec.Command("kubectl", args...)
+		cmd.Stdin = stdin
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd.Run()
+	}
+}
+
+// ephemeralContainer is the subset of the Kubernetes EphemeralContainer type
+// Kf sets. It's defined here because the vendored API predates it.
+type ephemeralContainer struct {
+	Name                string   `json:"name"`
+	Image               string   `json:"image"`
+	Command             []string `json:"command,omitempty"`
+	Stdin               bool     `json:"stdin"`
+	TTY                 bool     `json:"tty"`
+	TargetContainerName string   `json:"targetContainerName"`
+}
+
+// ephemeralContainerPatch creates a strategic merge patch for the
+// ephemeralcontainers subresource of a Pod that adds the container.
+func ephemeralContainerPatch(container ephemeralContainer) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"ephemeralContainers": []ephemeralContainer{container},
+		},
+	})
+}
+
+// NewRunCommand creates a command that runs an ephemeral debug container in an
+// app instance.
+func NewRunCommand(
+	p *config.KfParams,
+	appsClient apps.Client,
+	coreClient v1.CoreV1Interface,
+	attacher PodAttacher,
+) *cobra.Command {
+	var image string
+
+	cmd := &cobra.Command{
+		Use:   "run APP_NAME [-- COMMAND...]",
+		Short: "Run a debug container alongside an instance of the app",
+		Example: `
+		kf run myapp
+		kf run myapp --image nicolaka/netshoot -- bash
+		`,
+		Long: `Run an ephemeral debug container in a running instance of the app
+		and attach to it.
+
+		The container shares the process namespace and network of the app's
+		container, so images built without a shell or debugging tools can be
+		inspected. The app's filesystem is available under
+		/proc/1/root.
+
+		Ephemeral containers can't be removed once they're added, they stay
+		on the pod until it's replaced. The cluster must have the
+		EphemeralContainers feature enabled.
+
+		Running a debug container requires the same space policy as
+		kf ssh and is recorded as an Event on the app.
+		`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			appName := args[0]
+			command := args[1:]
+
+			space, err := p.GetTargetSpaceOrDefault()
+			if err != nil {
+				return err
+			}
+
+			if !space.Spec.Security.EnableSSH {
+				return fmt.Errorf(
+					"SSH is disabled in space %s, an operator can enable it with kf configure-space set-ssh-policy %s enabled",
+					p.Namespace,
+					p.Namespace,
+				)
+			}
+
+			cmd.SilenceUsage = true
+
+			app, err := appsClient.Get(p.Namespace, appName)
+			if err != nil {
+				return err
+			}
+
+			pod, err := runningInstance(coreClient, p.Namespace, appName)
+			if err != nil {
+				return err
+			}
+
+			container := ephemeralContainer{
+				Name:                "kf-run-" + utilrand.String(5),
+				Image:               image,
+				Command:             command,
+				Stdin:               true,
+				TTY:                 true,
+				TargetContainerName: "user-container",
+			}
+
+			ref := corev1.ObjectReference{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "App",
+				Namespace:  app.Namespace,
+				Name:       app.Name,
+				UID:        app.UID,
+			}
+			message := fmt.Sprintf("Debug container %s running %s started in instance %s", container.Name, image, pod)
+			if err := events.Create(coreClient, ref, "Run", message); err != nil {
+				return fmt.Errorf("failed to record audit event: %s", err)
+			}
+
+			patch, err := ephemeralContainerPatch(container)
+			if err != nil {
+				return err
+			}
+
+			if _, err := coreClient.Pods(p.Namespace).Patch(pod, types.StrategicMergePatchType, patch, "ephemeralcontainers"); err != nil {
+				return fmt.Errorf("failed to add debug container: %s", err)
+			}
+
+			fmt.Fprintf(cmd.ErrOrStderr(), "Attaching to debug container %s in instance %s...\n", container.Name, pod)
+
+			return attacher(p.Namespace, pod, container.Name, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&image,
+		"image",
+		"busybox",
+		"Container image to run, it should include the debugging tools you need",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/events"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	runningPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "running-pod",
+			Namespace: "my-space",
+			Labels:    map[string]string{"serving.knative.dev/service": "my-app"},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	setupApp := func(t *testing.T, fake *fake.FakeClient) {
+		app := &v1alpha1.App{}
+		app.Name = "my-app"
+		app.Namespace = "my-space"
+		fake.EXPECT().Get("my-space", "my-app").Return(app, nil)
+	}
+
+	cases := map[string]struct {
+		Args        []string
+		SSHDisabled bool
+		Pods        []runtime.Object
+		PatchErr    error
+		Setup       func(t *testing.T, fake *fake.FakeClient)
+		ExpectedErr error
+		WantImage   string
+		WantCommand []string
+	}{
+		"no app name": {
+			Args:        []string{},
+			ExpectedErr: errors.New("requires at least 1 arg(s), only received 0"),
+		},
+		"ssh disabled": {
+			Args:        []string{"my-app"},
+			SSHDisabled: true,
+			ExpectedErr: errors.New("SSH is disabled in space my-space, an operator can enable it with kf configure-space set-ssh-policy my-space enabled"),
+		},
+		"no running instances": {
+			Args:        []string{"my-app"},
+			Setup:       setupApp,
+			ExpectedErr: errors.New("app my-app has no running instances"),
+		},
+		"adding container fails": {
+			Args:        []string{"my-app"},
+			Pods:        []runtime.Object{runningPod},
+			PatchErr:    errors.New("some-error"),
+			Setup:       setupApp,
+			ExpectedErr: errors.New("failed to add debug container: some-error"),
+		},
+		"defaults": {
+			Args:      []string{"my-app"},
+			Pods:      []runtime.Object{runningPod},
+			Setup:     setupApp,
+			WantImage: "busybox",
+		},
+		"custom image and command": {
+			Args:        []string{"my-app", "--image", "nicolaka/netshoot", "--", "ls", "-la"},
+			Pods:        []runtime.Object{runningPod},
+			Setup:       setupApp,
+			WantImage:   "nicolaka/netshoot",
+			WantCommand: []string{"ls", "-la"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeApps := fake.NewFakeClient(ctrl)
+			clientset := k8sfake.NewSimpleClientset(tc.Pods...)
+			coreClient := clientset.CoreV1()
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeApps)
+			}
+
+			var gotPatch ktesting.PatchAction
+			clientset.PrependReactor("patch", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+				gotPatch = action.(ktesting.PatchAction)
+				return true, &corev1.Pod{}, tc.PatchErr
+			})
+
+			var gotContainer string
+			attacher := func(namespace, pod, container string, stdin io.Reader, stdout, stderr io.Writer) error {
+				testutil.AssertEqual(t, "namespace", "my-space", namespace)
+				testutil.AssertEqual(t, "pod", "running-pod", pod)
+				gotContainer = container
+				return nil
+			}
+
+			p := &config.KfParams{
+				Namespace: "my-space",
+			}
+			p.SetTargetSpaceToDefault()
+			p.TargetSpace.Spec.Security.EnableSSH = !tc.SSHDisabled
+
+			cmd := NewRunCommand(p, fakeApps, coreClient, attacher)
+			cmd.SetOutput(new(bytes.Buffer))
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertEqual(t, "patched pod", "running-pod", gotPatch.GetName())
+			testutil.AssertEqual(t, "subresource", "ephemeralcontainers", gotPatch.GetSubresource())
+
+			var patch struct {
+				Spec struct {
+					EphemeralContainers []ephemeralContainer `json:"ephemeralContainers"`
+				} `json:"spec"`
+			}
+			testutil.AssertNil(t, "patch err", json.Unmarshal(gotPatch.GetPatch(), &patch))
+			testutil.AssertEqual(t, "container count", 1, len(patch.Spec.EphemeralContainers))
+
+			container := patch.Spec.EphemeralContainers[0]
+			testutil.AssertEqual(t, "attached container", container.Name, gotContainer)
+			testutil.AssertEqual(t, "name prefix", true, strings.HasPrefix(container.Name, "kf-run-"))
+			testutil.AssertEqual(t, "image", tc.WantImage, container.Image)
+			testutil.AssertEqual(t, "command", tc.WantCommand, container.Command)
+			testutil.AssertEqual(t, "target", "user-container", container.TargetContainerName)
+
+			auditEvents, err := events.List(coreClient, "my-space", "App", "my-app")
+			testutil.AssertNil(t, "events err", err)
+			testutil.AssertEqual(t, "audit events", 1, len(auditEvents))
+			testutil.AssertEqual(t, "audit reason", "Run", auditEvents[0].Reason)
+
+			ctrl.Finish()
+		})
+	}
+}
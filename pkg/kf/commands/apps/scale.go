@@ -17,6 +17,7 @@ package apps
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
@@ -39,6 +40,7 @@ func NewScaleCommand(
 		instances    int
 		autoscaleMin int
 		autoscaleMax int
+		processType  string
 	)
 
 	cmd := &cobra.Command{
@@ -55,6 +57,8 @@ func NewScaleCommand(
 		kf scale myapp --max 5
 		# Scale between 3 and 5 instances depending on traffic
 		kf scale myapp --min 3 --max 5
+		# Run 2 instances of the worker process from the app's Procfile
+		kf scale myapp --process worker --instances 2
 		`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -65,6 +69,10 @@ func NewScaleCommand(
 
 			appName := args[0]
 
+			if processType != "" && processType != v1alpha1.ProcessTypeWeb {
+				return scaleProcess(cmd.OutOrStdout(), client, p.Namespace, appName, processType, instances, autoscaleMin, autoscaleMax)
+			}
+
 			if instances < 0 && autoscaleMin < 0 && autoscaleMax < 0 {
 				// Display current scaling properties.
 				app, err := client.Get(p.Namespace, appName)
@@ -143,7 +151,78 @@ func NewScaleCommand(
 		"Maximum number of instances to allow the autoscaler to scale to. 0 implies the app can be scaled to ∞.",
 	)
 
+	cmd.Flags().StringVar(
+		&processType,
+		"process",
+		v1alpha1.ProcessTypeWeb,
+		"Process type to scale. Processes other than web can't autoscale.",
+	)
+
 	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
 
 	return cmd
 }
+
+// scaleProcess displays or sets the number of instances of one of the App's
+// processes other than web. They run in a Deployment so they can't
+// autoscale.
+func scaleProcess(
+	w io.Writer,
+	client apps.Client,
+	namespace string,
+	appName string,
+	processType string,
+	instances int,
+	autoscaleMin int,
+	autoscaleMax int,
+) error {
+	if autoscaleMin >= 0 || autoscaleMax >= 0 {
+		return fmt.Errorf("only the %s process can autoscale, use --instances", v1alpha1.ProcessTypeWeb)
+	}
+
+	if instances < 0 {
+		app, err := client.Get(namespace, appName)
+		if err != nil {
+			return fmt.Errorf("failed to get app: %s", err)
+		}
+
+		process, err := findProcess(app, processType)
+		if err != nil {
+			return err
+		}
+
+		current := 0
+		if process.Instances != nil {
+			current = *process.Instances
+		}
+		fmt.Fprintf(w, "Process %s: %d instance(s)\n", processType, current)
+
+		return nil
+	}
+
+	mutator := func(app *v1alpha1.App) error {
+		process, err := findProcess(app, processType)
+		if err != nil {
+			return err
+		}
+
+		process.Instances = &instances
+		return nil
+	}
+
+	if _, err := client.Transform(namespace, appName, mutator); err != nil {
+		return fmt.Errorf("failed to scale app: %s", err)
+	}
+
+	fmt.Fprintf(w, "Scaled process %s of app %q in space %q to %d instance(s)\n", processType, appName, namespace, instances)
+	return nil
+}
+
+// findProcess gets the App's process with the given type.
+func findProcess(app *v1alpha1.App, processType string) (*v1alpha1.AppSpecProcess, error) {
+	if process := app.Spec.Process(processType); process != nil {
+		return process, nil
+	}
+
+	return nil, fmt.Errorf("App %s doesn't have a %s process, processes are defined by the Procfile in the App's source", app.Name, processType)
+}
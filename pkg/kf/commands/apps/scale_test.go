@@ -145,6 +145,56 @@ func TestNewScaleCommand(t *testing.T) {
 					})
 			},
 		},
+		"scales process": {
+			Namespace:       "default",
+			Args:            []string{"my-app", "--process", "worker", "-i", "2"},
+			ExpectedStrings: []string{"Scaled process worker", "2 instance(s)"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					Do(func(_, _ string, m apps.Mutator) {
+						app := v1alpha1.App{}
+						app.Spec.Processes = []v1alpha1.AppSpecProcess{
+							{Type: "web", Command: "bundle exec puma"},
+							{Type: "worker", Command: "bundle exec sidekiq"},
+						}
+						testutil.AssertNil(t, "mutator error", m(&app))
+						testutil.AssertEqual(t, "worker instances", 2, *app.Spec.Processes[1].Instances)
+						testutil.AssertEqual(t, "web instances", true, app.Spec.Processes[0].Instances == nil)
+					})
+			},
+		},
+		"displays process instances": {
+			Namespace:       "default",
+			Args:            []string{"my-app", "--process", "worker"},
+			ExpectedStrings: []string{"Process worker: 0 instance(s)"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				app := &v1alpha1.App{}
+				app.Spec.Processes = []v1alpha1.AppSpecProcess{
+					{Type: "worker", Command: "bundle exec sidekiq"},
+				}
+				fake.EXPECT().Get("default", "my-app").Return(app, nil)
+			},
+		},
+		"missing process": {
+			Namespace:   "default",
+			Args:        []string{"my-app", "--process", "clock", "-i", "1"},
+			ExpectedErr: errors.New("failed to scale app: App my-app doesn't have a clock process, processes are defined by the Procfile in the App's source"),
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().
+					Transform("default", "my-app", gomock.Any()).
+					DoAndReturn(func(_, _ string, m apps.Mutator) (*v1alpha1.App, error) {
+						app := v1alpha1.App{}
+						app.Name = "my-app"
+						return nil, m(&app)
+					})
+			},
+		},
+		"process can't autoscale": {
+			Namespace:   "default",
+			Args:        []string{"my-app", "--process", "worker", "--max", "3"},
+			ExpectedErr: errors.New("only the web process can autoscale, use --instances"),
+		},
 		"updating app fails": {
 			Namespace:   "default",
 			Args:        []string{"my-app", "-i=3"},
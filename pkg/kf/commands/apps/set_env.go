@@ -16,27 +16,62 @@ package apps
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/envutil"
 	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/externalsecrets"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
 )
 
 // NewSetEnvCommand creates a SetEnv command.
-func NewSetEnvCommand(p *config.KfParams, client apps.Client) *cobra.Command {
-	var async utils.AsyncFlags
+func NewSetEnvCommand(
+	p *config.KfParams,
+	client apps.Client,
+	externalSecrets externalsecrets.Client,
+) *cobra.Command {
+	var (
+		async      utils.AsyncFlags
+		fromSecret string
+		fromGSM    string
+	)
 
 	cmd := &cobra.Command{
-		Use:     "set-env APP_NAME ENV_VAR_NAME ENV_VAR_VALUE",
-		Short:   "Set an environment variable for an app",
-		Example: `kf set-env myapp ENV production`,
-		Args:    cobra.ExactArgs(3),
+		Use:   "set-env APP_NAME ENV_VAR_NAME (ENV_VAR_VALUE | --from-secret SECRET_NAME[:KEY] | --from-gsm SECRET_VERSION)",
+		Short: "Set an environment variable for an app",
+		Long: `Set an environment variable for an app.
+
+		Use --from-secret to read the value from a Secret in the space, for
+		example one created with kf create-secret. If KEY is omitted the
+		variable's name is used as the key.
+
+		Use --from-gsm to read the value from Google Secret Manager so it's
+		never stored on the App. Kf creates an ExternalSecret in the space that
+		the kubernetes-external-secrets controller syncs into a Secret, then
+		reads the variable from that Secret. The controller must be installed
+		on the cluster with access to the secret.
+		`,
+		Example: `
+		kf set-env myapp ENV production
+		kf set-env myapp DB_PASSWORD --from-secret db-creds:password
+		kf set-env myapp DB_PASSWORD --from-gsm projects/my-project/secrets/db-password/versions/latest
+		`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if fromSecret != "" || fromGSM != "" {
+				return cobra.ExactArgs(2)(cmd, args)
+			}
+
+			return cobra.ExactArgs(3)(cmd, args)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := utils.ValidateNamespace(p); err != nil {
 				return err
@@ -44,14 +79,53 @@ func NewSetEnvCommand(p *config.KfParams, client apps.Client) *cobra.Command {
 
 			appName := args[0]
 			name := args[1]
-			value := args[2]
 
-			cmd.SilenceUsage = true
+			if err := envutil.ValidateEnvVarName(name); err != nil {
+				return err
+			}
 
-			toSet := []corev1.EnvVar{
-				{Name: name, Value: value},
+			if fromSecret != "" && fromGSM != "" {
+				return errors.New("--from-secret and --from-gsm can't be used together")
 			}
 
+			var toSet []corev1.EnvVar
+			switch {
+			case fromSecret != "":
+				env, err := envutil.NewSecretEnvVar(name, fromSecret)
+				if err != nil {
+					return err
+				}
+				toSet = append(toSet, env)
+
+			case fromGSM != "":
+				version, err := externalsecrets.ParseGSMSecretVersion(fromGSM)
+				if err != nil {
+					return err
+				}
+
+				cmd.SilenceUsage = true
+
+				secretName := gsmSecretName(appName, name)
+				fmt.Fprintf(cmd.OutOrStdout(), "Syncing %s into Secret %q\n", version, secretName)
+
+				ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+				defer cancel()
+				if err := externalSecrets.SyncGSM(ctx, p.Namespace, secretName, name, *version); err != nil {
+					return err
+				}
+
+				env, err := envutil.NewSecretEnvVar(name, secretName)
+				if err != nil {
+					return err
+				}
+				toSet = append(toSet, env)
+
+			default:
+				toSet = append(toSet, corev1.EnvVar{Name: name, Value: args[2]})
+			}
+
+			cmd.SilenceUsage = true
+
 			_, err := client.Transform(p.Namespace, appName, func(app *v1alpha1.App) error {
 				kfapp := (*apps.KfApp)(app)
 				kfapp.MergeEnvVars(toSet)
@@ -72,7 +146,30 @@ func NewSetEnvCommand(p *config.KfParams, client apps.Client) *cobra.Command {
 
 	async.Add(cmd)
 
+	cmd.Flags().StringVar(
+		&fromSecret,
+		"from-secret",
+		"",
+		"Read the value from a Secret in the space, in the form SECRET_NAME[:KEY].",
+	)
+
+	cmd.Flags().StringVar(
+		&fromGSM,
+		"from-gsm",
+		"",
+		"Read the value from a Google Secret Manager secret, in the form projects/PROJECT/secrets/SECRET[/versions/VERSION].",
+	)
+
 	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
 
 	return cmd
 }
+
+var invalidSecretNameChars = regexp.MustCompile("[^a-z0-9]+")
+
+// gsmSecretName gets the name of the Secret an environment variable read from
+// Secret Manager is synced into.
+func gsmSecretName(appName, envName string) string {
+	suffix := invalidSecretNameChars.ReplaceAllString(strings.ToLower(envName), "-")
+	return strings.Trim(appName+"-"+suffix, "-")
+}
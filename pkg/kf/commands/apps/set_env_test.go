@@ -25,6 +25,8 @@ import (
 	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/apps/fake"
 	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/externalsecrets"
+	esfake "github.com/google/kf/pkg/kf/externalsecrets/fake"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
 	"github.com/google/kf/pkg/kf/testutil"
 )
@@ -38,6 +40,7 @@ func TestSetEnvCommand(t *testing.T) {
 		ExpectedStrings []string
 		ExpectedErr     error
 		Setup           func(t *testing.T, fake *fake.FakeClient)
+		SetupSecrets    func(t *testing.T, fake *esfake.FakeClient)
 	}{
 		"wrong number of params": {
 			Args:        []string{},
@@ -82,6 +85,80 @@ func TestSetEnvCommand(t *testing.T) {
 				fake.EXPECT().WaitForConditionKnativeServiceReadyTrue(gomock.Any(), "some-namespace", "app-name", gomock.Any())
 			},
 		},
+		"from-secret with a value": {
+			Args:        []string{"app-name", "NAME", "VALUE", "--from-secret", "creds"},
+			Namespace:   "some-namespace",
+			ExpectedErr: errors.New("accepts 2 arg(s), received 3"),
+		},
+		"from-secret invalid reference": {
+			Args:        []string{"app-name", "NAME", "--from-secret", "creds:"},
+			Namespace:   "some-namespace",
+			ExpectedErr: errors.New(`invalid Secret key "": a valid config key must consist of alphanumeric characters, '-', '_' or '.' (e.g. 'key.name',  or 'KEY_NAME',  or 'key-name', regex used for validation is '[-._a-zA-Z0-9]+')`),
+		},
+		"sets values from secret": {
+			Args:      []string{"app-name", "DB_PASSWORD", "--from-secret", "db-creds:password"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Transform(gomock.Any(), "app-name", gomock.Any()).Do(func(namespace, appName string, mutator apps.Mutator) {
+					out := &v1alpha1.App{}
+					err := mutator(out)
+					testutil.AssertNil(t, "mutator err", err)
+
+					app := (*apps.KfApp)(out)
+					env := app.GetEnvVars()
+					testutil.AssertEqual(t, "len(env)", 1, len(env))
+					testutil.AssertEqual(t, "name", "DB_PASSWORD", env[0].Name)
+					testutil.AssertEqual(t, "secret", "db-creds", env[0].ValueFrom.SecretKeyRef.Name)
+					testutil.AssertEqual(t, "key", "password", env[0].ValueFrom.SecretKeyRef.Key)
+				})
+				fake.EXPECT().WaitForConditionKnativeServiceReadyTrue(gomock.Any(), "some-namespace", "app-name", gomock.Any())
+			},
+		},
+		"from-secret and from-gsm": {
+			Args:        []string{"app-name", "NAME", "--from-secret", "creds", "--from-gsm", "projects/p/secrets/s"},
+			Namespace:   "some-namespace",
+			ExpectedErr: errors.New("--from-secret and --from-gsm can't be used together"),
+		},
+		"from-gsm invalid name": {
+			Args:        []string{"app-name", "NAME", "--from-gsm", "p/s"},
+			Namespace:   "some-namespace",
+			ExpectedErr: errors.New(`invalid Secret Manager secret "p/s", must be in the form projects/PROJECT/secrets/SECRET[/versions/VERSION]`),
+		},
+		"from-gsm sync fails": {
+			Args:        []string{"app-name", "DB_PASSWORD", "--from-gsm", "projects/p/secrets/s"},
+			Namespace:   "some-namespace",
+			ExpectedErr: errors.New("some-error"),
+			SetupSecrets: func(t *testing.T, fake *esfake.FakeClient) {
+				fake.EXPECT().SyncGSM(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("some-error"))
+			},
+		},
+		"sets values from gsm": {
+			Args:            []string{"app-name", "DB_PASSWORD", "--from-gsm", "projects/p/secrets/s/versions/2"},
+			Namespace:       "some-namespace",
+			ExpectedStrings: []string{`Syncing projects/p/secrets/s/versions/2 into Secret "app-name-db-password"`},
+			SetupSecrets: func(t *testing.T, fake *esfake.FakeClient) {
+				fake.EXPECT().SyncGSM(
+					gomock.Any(),
+					"some-namespace",
+					"app-name-db-password",
+					"DB_PASSWORD",
+					externalsecrets.GSMSecretVersion{Project: "p", Secret: "s", Version: "2"},
+				)
+			},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Transform(gomock.Any(), "app-name", gomock.Any()).Do(func(namespace, appName string, mutator apps.Mutator) {
+					out := &v1alpha1.App{}
+					err := mutator(out)
+					testutil.AssertNil(t, "mutator err", err)
+
+					env := (*apps.KfApp)(out).GetEnvVars()
+					testutil.AssertEqual(t, "len(env)", 1, len(env))
+					testutil.AssertEqual(t, "secret", "app-name-db-password", env[0].ValueFrom.SecretKeyRef.Name)
+					testutil.AssertEqual(t, "key", "DB_PASSWORD", env[0].ValueFrom.SecretKeyRef.Key)
+				})
+				fake.EXPECT().WaitForConditionKnativeServiceReadyTrue(gomock.Any(), "some-namespace", "app-name", gomock.Any())
+			},
+		},
 		"async call does not wait": {
 			Args:      []string{"app-name", "NAME", "VALUE", "--async"},
 			Namespace: "some-namespace",
@@ -98,12 +175,17 @@ func TestSetEnvCommand(t *testing.T) {
 				tc.Setup(t, fake)
 			}
 
+			fakeSecrets := esfake.NewFakeClient(ctrl)
+			if tc.SetupSecrets != nil {
+				tc.SetupSecrets(t, fakeSecrets)
+			}
+
 			buf := new(bytes.Buffer)
 			p := &config.KfParams{
 				Namespace: tc.Namespace,
 			}
 
-			cmd := NewSetEnvCommand(p, fake)
+			cmd := NewSetEnvCommand(p, fake, fakeSecrets)
 			cmd.SetOutput(buf)
 			cmd.SetArgs(tc.Args)
 			_, actualErr := cmd.ExecuteC()
@@ -0,0 +1,141 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// includeAllFilter is used for sources that were already filtered when they
+// were staged.
+func includeAllFilter(string) (bool, error) {
+	return true, nil
+}
+
+// stageSharedPaths copies the App's source and the shared paths, relative to
+// root, into a temporary directory so they can be uploaded together. Shared
+// paths keep their path relative to root inside the staged source. The
+// returned cleanup func removes the directory.
+func stageSharedPaths(srcPath, root string, sharedPaths []string, filter KontextFilter) (string, func(), error) {
+	staged, err := ioutil.TempDir("", "kf-source")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(staged) }
+
+	if err := copyFiltered(srcPath, staged, filter); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	for _, sharedPath := range sharedPaths {
+		from := filepath.Join(root, filepath.FromSlash(sharedPath))
+		to := filepath.Join(staged, filepath.FromSlash(sharedPath))
+
+		if info, err := os.Stat(from); err != nil {
+			cleanup()
+			return "", nil, fmt.Errorf("couldn't read shared path %s: %v", sharedPath, err)
+		} else if !info.IsDir() {
+			cleanup()
+			return "", nil, fmt.Errorf("shared path %s must be a directory", sharedPath)
+		}
+
+		if _, err := os.Lstat(to); err == nil {
+			cleanup()
+			return "", nil, fmt.Errorf("shared path %s conflicts with a path in the App's source", sharedPath)
+		}
+
+		if err := copyFiltered(from, to, buildIgnoreFilter(from)); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	return staged, cleanup, nil
+}
+
+// copyFiltered copies the files in src that match filter to dst, keeping
+// modes and symlinks.
+func copyFiltered(src, dst string, filter KontextFilter) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+		if rel == "." {
+			return os.MkdirAll(target, 0755)
+		}
+
+		include, err := filter(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+
+		if !include {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+
+		default:
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
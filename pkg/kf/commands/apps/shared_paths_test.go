@@ -0,0 +1,115 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestStageSharedPaths(t *testing.T) {
+	writeFiles := func(t *testing.T, root string, files ...string) {
+		for _, file := range files {
+			path := filepath.Join(root, filepath.FromSlash(file))
+			testutil.AssertNil(t, "mkdir", os.MkdirAll(filepath.Dir(path), 0755))
+			testutil.AssertNil(t, "write", ioutil.WriteFile(path, []byte(file), 0644))
+		}
+	}
+
+	cases := map[string]struct {
+		files       []string
+		sharedPaths []string
+		wantFiles   []string
+		wantMissing []string
+		wantErr     error
+	}{
+		"copies app and shared paths": {
+			files: []string{
+				"services/api/main.go",
+				"services/api/.git/HEAD",
+				"services/web/index.js",
+				"libs/common/util.go",
+				"libs/common/testdata/big.bin",
+				"libs/other/other.go",
+			},
+			sharedPaths: []string{"libs/common"},
+			wantFiles:   []string{"main.go", "libs/common/util.go"},
+			wantMissing: []string{".git", "services", "libs/other", "libs/common/testdata"},
+		},
+		"missing shared path": {
+			files:       []string{"services/api/main.go"},
+			sharedPaths: []string{"libs/missing"},
+			wantErr:     errors.New("couldn't read shared path libs/missing: stat ROOT/libs/missing: no such file or directory"),
+		},
+		"shared path is a file": {
+			files:       []string{"services/api/main.go", "libs/common.go"},
+			sharedPaths: []string{"libs/common.go"},
+			wantErr:     errors.New("shared path libs/common.go must be a directory"),
+		},
+		"shared path conflicts with source": {
+			files:       []string{"services/api/libs/common/util.go", "libs/common/util.go"},
+			sharedPaths: []string{"libs/common"},
+			wantErr:     errors.New("shared path libs/common conflicts with a path in the App's source"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			root, err := ioutil.TempDir("", "monorepo")
+			testutil.AssertNil(t, "TempDir", err)
+			defer os.RemoveAll(root)
+
+			writeFiles(t, root, tc.files...)
+			// Shared paths use their own ignore files.
+			ioutil.WriteFile(filepath.Join(root, "libs", "common", ".kfignore"), []byte("testdata\n"), 0644)
+
+			srcPath := filepath.Join(root, "services", "api")
+			staged, cleanup, err := stageSharedPaths(srcPath, root, tc.sharedPaths, buildIgnoreFilter(srcPath))
+			if tc.wantErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, errors.New(replaceRoot(tc.wantErr, root)), err)
+				return
+			}
+			defer cleanup()
+
+			for _, file := range tc.wantFiles {
+				_, err := os.Stat(filepath.Join(staged, filepath.FromSlash(file)))
+				testutil.AssertNil(t, file, err)
+			}
+
+			for _, file := range tc.wantMissing {
+				_, err := os.Stat(filepath.Join(staged, filepath.FromSlash(file)))
+				testutil.AssertEqual(t, file+" missing", true, os.IsNotExist(err))
+			}
+
+			cleanup()
+			_, err = os.Stat(staged)
+			testutil.AssertEqual(t, "cleaned up", true, os.IsNotExist(err))
+		})
+	}
+}
+
+func replaceRoot(err error, root string) string {
+	if err == nil {
+		return ""
+	}
+
+	return strings.Replace(err.Error(), "ROOT", root, -1)
+}
@@ -0,0 +1,169 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/events"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// PodExecer runs a command in the user container of a pod with the given
+// streams attached.
+type PodExecer func(namespace, pod string, command []string, stdin io.Reader, stdout, stderr io.Writer) error
+
+// KubectlPodExecer creates a PodExecer that uses kubectl exec with the
+// kubeconfig and impersonation settings in p, an empty kubeconfig uses
+// kubectl's default.
+func KubectlPodExecer(p *config.KfParams) PodExecer {
+	return func(namespace, pod string, command []string, stdin io.Reader, stdout, stderr io.Writer) error {
+		args := kubectlArgs(p, "exec", "--stdin", "--tty", "--namespace", namespace, "--container", "user-container")
+		args = append(args, pod, "--")
+		args = append(args, command...)
+
+		cmd := exec.Command("kubectl", args...)
+		cmd.Stdin = stdin
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		return cmd.Run()
+	}
+}
+
+// kubectlArgs appends the kubeconfig and impersonation settings in p to the
+// given kubectl arguments.
+func kubectlArgs(p *config.KfParams, args ...string) []string {
+	if p.KubeCfgFile != "" {
+		args = append(args, "--kubeconfig", p.KubeCfgFile)
+	}
+	if p.Impersonate != "" {
+		args = append(args, "--as", p.Impersonate)
+	}
+	for _, group := range p.ImpersonateGroups {
+		args = append(args, "--as-group", group)
+	}
+	return args
+}
+
+// NewSSHCommand creates a command that opens a shell in an app instance.
+func NewSSHCommand(
+	p *config.KfParams,
+	appsClient apps.Client,
+	coreClient v1.CoreV1Interface,
+	execer PodExecer,
+) *cobra.Command {
+	var command []string
+
+	cmd := &cobra.Command{
+		Use:     "ssh APP_NAME",
+		Short:   "Open a shell in an instance of the app",
+		Example: `kf ssh myapp`,
+		Long: `Open a shell in a running instance of the app.
+
+		SSH has to be enabled in the space by an operator with
+		kf configure-space set-ssh-policy SPACE enabled.
+
+		Every session is recorded as an Event on the app so it shows up in
+		kf app, Kubernetes audit logs also record the user that opened it.
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			appName := args[0]
+
+			space, err := p.GetTargetSpaceOrDefault()
+			if err != nil {
+				return err
+			}
+
+			if !space.Spec.Security.EnableSSH {
+				return fmt.Errorf(
+					"SSH is disabled in space %s, an operator can enable it with kf configure-space set-ssh-policy %s enabled",
+					p.Namespace,
+					p.Namespace,
+				)
+			}
+
+			cmd.SilenceUsage = true
+
+			app, err := appsClient.Get(p.Namespace, appName)
+			if err != nil {
+				return err
+			}
+
+			pod, err := runningInstance(coreClient, p.Namespace, appName)
+			if err != nil {
+				return err
+			}
+
+			ref := corev1.ObjectReference{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "App",
+				Namespace:  app.Namespace,
+				Name:       app.Name,
+				UID:        app.UID,
+			}
+			message := fmt.Sprintf("SSH session opened in instance %s running %v", pod, command)
+			if err := events.Create(coreClient, ref, "SSH", message); err != nil {
+				return fmt.Errorf("failed to record audit event: %s", err)
+			}
+
+			return execer(p.Namespace, pod, command, cmd.InOrStdin(), cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(
+		&command,
+		"command",
+		"c",
+		[]string{"/bin/bash"},
+		"Command to run instead of a shell, can be repeated for arguments",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+// runningInstance gets the name of a running pod for the app.
+func runningInstance(coreClient v1.PodsGetter, namespace, appName string) (string, error) {
+	pods, err := coreClient.Pods(namespace).List(metav1.ListOptions{
+		LabelSelector: "serving.knative.dev/service=" + appName,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			return pod.Name, nil
+		}
+	}
+
+	return "", fmt.Errorf("app %s has no running instances", appName)
+}
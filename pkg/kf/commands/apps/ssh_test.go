@@ -0,0 +1,169 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/events"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestSSH(t *testing.T) {
+	t.Parallel()
+
+	appPod := func(name string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "my-space",
+				Labels:    map[string]string{"serving.knative.dev/service": "my-app"},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+
+	cases := map[string]struct {
+		Args        []string
+		SSHDisabled bool
+		Pods        []runtime.Object
+		ExecErr     error
+		Setup       func(t *testing.T, fake *fake.FakeClient)
+		ExpectedErr error
+		WantPod     string
+		WantCommand []string
+	}{
+		"no app name": {
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"ssh disabled": {
+			Args:        []string{"my-app"},
+			SSHDisabled: true,
+			ExpectedErr: errors.New("SSH is disabled in space my-space, an operator can enable it with kf configure-space set-ssh-policy my-space enabled"),
+		},
+		"getting app fails": {
+			Args: []string{"my-app"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Get("my-space", "my-app").Return(nil, errors.New("some-error"))
+			},
+			ExpectedErr: errors.New("some-error"),
+		},
+		"no running instances": {
+			Args: []string{"my-app"},
+			Pods: []runtime.Object{appPod("pending-pod", corev1.PodPending)},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Get("my-space", "my-app").Return(&v1alpha1.App{}, nil)
+			},
+			ExpectedErr: errors.New("app my-app has no running instances"),
+		},
+		"opens a shell": {
+			Args: []string{"my-app"},
+			Pods: []runtime.Object{
+				appPod("pending-pod", corev1.PodPending),
+				appPod("running-pod", corev1.PodRunning),
+			},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				app := &v1alpha1.App{}
+				app.Name = "my-app"
+				app.Namespace = "my-space"
+				fake.EXPECT().Get("my-space", "my-app").Return(app, nil)
+			},
+			WantPod:     "running-pod",
+			WantCommand: []string{"/bin/bash"},
+		},
+		"custom command": {
+			Args: []string{"my-app", "-c", "ls", "-c", "/"},
+			Pods: []runtime.Object{appPod("running-pod", corev1.PodRunning)},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				app := &v1alpha1.App{}
+				app.Name = "my-app"
+				app.Namespace = "my-space"
+				fake.EXPECT().Get("my-space", "my-app").Return(app, nil)
+			},
+			WantPod:     "running-pod",
+			WantCommand: []string{"ls", "/"},
+		},
+		"exec fails": {
+			Args:    []string{"my-app"},
+			Pods:    []runtime.Object{appPod("running-pod", corev1.PodRunning)},
+			ExecErr: errors.New("exit status 1"),
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				app := &v1alpha1.App{}
+				app.Name = "my-app"
+				app.Namespace = "my-space"
+				fake.EXPECT().Get("my-space", "my-app").Return(app, nil)
+			},
+			ExpectedErr: errors.New("exit status 1"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeApps := fake.NewFakeClient(ctrl)
+			coreClient := k8sfake.NewSimpleClientset(tc.Pods...).CoreV1()
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeApps)
+			}
+
+			var gotPod string
+			var gotCommand []string
+			execer := func(namespace, pod string, command []string, stdin io.Reader, stdout, stderr io.Writer) error {
+				testutil.AssertEqual(t, "namespace", "my-space", namespace)
+				gotPod = pod
+				gotCommand = command
+				return tc.ExecErr
+			}
+
+			p := &config.KfParams{
+				Namespace: "my-space",
+			}
+			p.SetTargetSpaceToDefault()
+			p.TargetSpace.Spec.Security.EnableSSH = !tc.SSHDisabled
+
+			cmd := NewSSHCommand(p, fakeApps, coreClient, execer)
+			cmd.SetOutput(new(bytes.Buffer))
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertEqual(t, "pod", tc.WantPod, gotPod)
+			testutil.AssertEqual(t, "command", tc.WantCommand, gotCommand)
+
+			auditEvents, err := events.List(coreClient, "my-space", "App", "my-app")
+			testutil.AssertNil(t, "events err", err)
+			testutil.AssertEqual(t, "audit events", 1, len(auditEvents))
+			testutil.AssertEqual(t, "audit reason", "SSH", auditEvents[0].Reason)
+
+			ctrl.Finish()
+		})
+	}
+}
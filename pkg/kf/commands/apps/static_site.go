@@ -0,0 +1,92 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// staticSiteDockerfile is the path of the generated Dockerfile in a staged
+// static site.
+const staticSiteDockerfile = "Dockerfile"
+
+// staticSiteDockerfileContents serves the site with nginx. The nginx image
+// renders the templates with the container's environment on start, which sets
+// the port Kf assigns the app.
+const staticSiteDockerfileContents = `FROM nginx:1.19-alpine
+COPY default.conf.template /etc/nginx/templates/default.conf.template
+COPY public /usr/share/nginx/html
+`
+
+// staticSiteNginxTemplate caches assets for a week and makes browsers
+// revalidate HTML so new deployments show up right away. Only variables set
+// in the environment are substituted, so nginx's own variables are kept.
+const staticSiteNginxTemplate = `server {
+  listen ${PORT};
+  root /usr/share/nginx/html;
+  index index.html index.htm;
+
+  gzip on;
+  gzip_types text/plain text/css application/javascript application/json image/svg+xml;
+
+  location / {
+    try_files $uri $uri/ =404;
+    add_header Cache-Control "no-cache";
+  }
+
+  location ~* \.(css|js|map|png|jpe?g|gif|svg|ico|webp|woff2?|ttf|eot)$ {
+    expires 7d;
+    add_header Cache-Control "public";
+  }
+}
+`
+
+// stageStaticSite copies a directory of static files into a temporary
+// directory next to a generated Dockerfile that serves them with nginx. The
+// returned cleanup func removes the directory.
+func stageStaticSite(siteDir string) (string, func(), error) {
+	if info, err := os.Stat(siteDir); err != nil {
+		return "", nil, fmt.Errorf("couldn't read static site %s: %v", siteDir, err)
+	} else if !info.IsDir() {
+		return "", nil, fmt.Errorf("static site %s must be a directory", siteDir)
+	}
+
+	staged, err := ioutil.TempDir("", "kf-static")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(staged) }
+
+	if err := copyFiltered(siteDir, filepath.Join(staged, "public"), buildIgnoreFilter(siteDir)); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	files := map[string]string{
+		staticSiteDockerfile:    staticSiteDockerfileContents,
+		"default.conf.template": staticSiteNginxTemplate,
+	}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(staged, name), []byte(contents), 0644); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	return staged, cleanup, nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestStageStaticSite(t *testing.T) {
+	cases := map[string]struct {
+		siteDir   string
+		wantFiles []string
+		wantErr   error
+	}{
+		"stages site with nginx": {
+			siteDir: "testdata/static-site",
+			wantFiles: []string{
+				"Dockerfile",
+				"default.conf.template",
+				"public/index.html",
+				"public/style.css",
+			},
+		},
+		"missing site": {
+			siteDir: "testdata/missing-site",
+			wantErr: errors.New("couldn't read static site testdata/missing-site: stat testdata/missing-site: no such file or directory"),
+		},
+		"site is a file": {
+			siteDir: "testdata/static-site/index.html",
+			wantErr: errors.New("static site testdata/static-site/index.html must be a directory"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			staged, cleanup, err := stageStaticSite(tc.siteDir)
+			if tc.wantErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, err)
+				return
+			}
+			defer cleanup()
+
+			for _, want := range tc.wantFiles {
+				_, err := os.Stat(filepath.Join(staged, filepath.FromSlash(want)))
+				testutil.AssertNil(t, want+" staged", err)
+			}
+
+			dockerfile, err := ioutil.ReadFile(filepath.Join(staged, staticSiteDockerfile))
+			testutil.AssertNil(t, "read Dockerfile", err)
+			testutil.AssertEqual(t, "serves with nginx", true, strings.HasPrefix(string(dockerfile), "FROM nginx"))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fatih/color"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/logs"
+	"github.com/spf13/cobra"
+)
+
+// tailKindColors are the colors each kind of entry is prefixed with.
+var tailKindColors = map[string]*color.Color{
+	logs.EntryKindApp:   color.New(color.FgGreen),
+	logs.EntryKindBuild: color.New(color.FgCyan),
+	logs.EntryKindEvent: color.New(color.FgYellow),
+	logs.EntryKindRoute: color.New(color.FgMagenta),
+}
+
+// NewTailCommand creates a Tail command.
+func NewTailCommand(p *config.KfParams, tailer logs.UnifiedTailer) *cobra.Command {
+	var numberLines int
+
+	cmd := &cobra.Command{
+		Use:   "tail APP_NAME",
+		Short: "Follow an app's logs, builds, events, and routes in one stream",
+		Long: `Follow an app's logs, builds, events, and routes in one stream.
+
+		Tail merges the app's logs, the logs of builds started while tailing,
+		Kubernetes events about the app and its instances, and routes being
+		mapped or unmapped into a single stream ordered by time. Each line is
+		prefixed with the kind of entry it is.
+
+		Entries are held briefly so ones from slower sources can be written in
+		order, so output lags real time by a couple of seconds.
+		`,
+		Example: `
+		# Follow everything happening to the app
+		kf tail myapp
+
+		# Include the last 50 lines of the app's logs
+		kf tail myapp -n 50
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			out := cmd.OutOrStdout()
+			appName := args[0]
+			if err := tailer.Tail(ctx, p.Namespace, appName, numberLines, func(e logs.Entry) {
+				kind := fmt.Sprintf("[%s]", e.Kind)
+				if c, ok := tailKindColors[e.Kind]; ok {
+					kind = c.Sprint(kind)
+				}
+
+				fmt.Fprintf(out, "%s %s %s\n", e.Time.Format("15:04:05"), kind, e.Text)
+			}); err != nil {
+				cmd.SilenceUsage = !utils.ConfigError(err)
+				return fmt.Errorf("failed to tail %s: %s", appName, err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(
+		&numberLines,
+		"number",
+		"n",
+		10,
+		"Show the last N lines of the app's logs before following.",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
@@ -0,0 +1,129 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/logs"
+	"github.com/google/kf/pkg/kf/logs/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/spf13/cobra"
+)
+
+func TestTailCommand(t *testing.T) {
+	t.Parallel()
+	for tn, tc := range map[string]struct {
+		Namespace string
+		Args      []string
+		Setup     func(t *testing.T, fake *fake.FakeUnifiedTailer)
+		Assert    func(t *testing.T, cmd *cobra.Command, out string, err error)
+	}{
+		"missing app name": {
+			Assert: func(t *testing.T, cmd *cobra.Command, out string, err error) {
+				testutil.AssertEqual(t, "SilenceUsage", false, cmd.SilenceUsage)
+				testutil.AssertErrorsEqual(t, errors.New("accepts 1 arg(s), received 0"), err)
+			},
+		},
+		"missing namespace": {
+			Args: []string{"some-app"},
+			Assert: func(t *testing.T, cmd *cobra.Command, out string, err error) {
+				testutil.AssertEqual(t, "SilenceUsage", false, cmd.SilenceUsage)
+				testutil.AssertErrorsEqual(t, errors.New(utils.EmptyNamespaceError), err)
+			},
+		},
+		"tailer returns error": {
+			Args:      []string{"some-app"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fake *fake.FakeUnifiedTailer) {
+				fake.EXPECT().
+					Tail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("some-error"))
+			},
+			Assert: func(t *testing.T, cmd *cobra.Command, out string, err error) {
+				testutil.AssertEqual(t, "SilenceUsage", true, cmd.SilenceUsage)
+				testutil.AssertErrorsEqual(t, errors.New("failed to tail some-app: some-error"), err)
+			},
+		},
+		"uses configuration": {
+			Namespace: "some-namespace",
+			Args:      []string{"some-app", "-n=15"},
+			Setup: func(t *testing.T, fake *fake.FakeUnifiedTailer) {
+				fake.EXPECT().
+					Tail(gomock.Not(gomock.Nil()), "some-namespace", "some-app", 15, gomock.Not(gomock.Nil()))
+			},
+		},
+		"writes entries": {
+			Namespace: "some-namespace",
+			Args:      []string{"some-app"},
+			Setup: func(t *testing.T, fake *fake.FakeUnifiedTailer) {
+				fake.EXPECT().
+					Tail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Do(func(ctx context.Context, namespace, appName string, numberLines int, f func(logs.Entry)) {
+						at := time.Date(2019, 1, 1, 10, 4, 5, 0, time.UTC)
+						f(logs.Entry{Time: at, Kind: logs.EntryKindBuild, Text: "Step 1/2"})
+						f(logs.Entry{Time: at.Add(time.Second), Kind: logs.EntryKindRoute, Text: "mapped example.com"})
+					})
+			},
+			Assert: func(t *testing.T, cmd *cobra.Command, out string, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertContainsAll(t, out, []string{
+					"10:04:05", "[build]", "Step 1/2",
+					"10:04:06", "[route]", "mapped example.com",
+				})
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			if tc.Setup == nil {
+				tc.Setup = func(t *testing.T, fake *fake.FakeUnifiedTailer) {
+					// NOP
+				}
+			}
+			if tc.Assert == nil {
+				tc.Assert = func(t *testing.T, cmd *cobra.Command, out string, err error) {
+					testutil.AssertNil(t, "err", err)
+				}
+			}
+
+			ctrl := gomock.NewController(t)
+			fake := fake.NewFakeUnifiedTailer(ctrl)
+			tc.Setup(t, fake)
+
+			var buf bytes.Buffer
+			cmd := NewTailCommand(
+				&config.KfParams{Namespace: tc.Namespace},
+				fake,
+			)
+			cmd.SetArgs(tc.Args)
+			cmd.SetOutput(&buf)
+
+			gotErr := cmd.Execute()
+			tc.Assert(t, cmd, buf.String(), gotErr)
+			if gotErr != nil {
+				return
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
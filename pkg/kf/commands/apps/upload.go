@@ -0,0 +1,212 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/kf/pkg/internal/imageutil"
+	"github.com/google/kf/pkg/kf/sourceimage"
+	"github.com/spf13/cobra"
+)
+
+// uploadManifestPath returns the path of the file that records completed
+// source uploads. An empty path disables the manifest.
+var uploadManifestPath = func() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "kf", "uploads.json")
+}
+
+// sourceImageExists checks that a previously uploaded source image is still
+// in the registry before it's reused.
+var sourceImageExists = sourceimage.ImageExists
+
+// uploadRetryBackoff is the time to wait before the first retry, it doubles
+// for each subsequent attempt.
+var uploadRetryBackoff = 2 * time.Second
+
+// SourceUploadFlags is a flag set for controlling how source code is
+// uploaded to the container registry.
+type SourceUploadFlags struct {
+	timeout time.Duration
+	retries int
+}
+
+// Add adds the source upload flags to the Cobra command.
+func (flags *SourceUploadFlags) Add(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(
+		&flags.timeout,
+		"source-upload-timeout",
+		0,
+		"Maximum time to wait for a single source upload attempt, 0 waits forever (e.g. 10m).",
+	)
+
+	cmd.Flags().IntVar(
+		&flags.retries,
+		"source-upload-retries",
+		0,
+		"Number of times to retry a failed source upload.",
+	)
+}
+
+// Upload builds and uploads the source image using the builder. Uploads that
+// completed in a previous push of the same source, identified by its digest,
+// are reused if the registry still has the image so an interrupted push can
+// resume without sending the source again. Each attempt is canceled when it
+// times out so it can't keep uploading in the background while the next one
+// runs. The name of the image holding the source is returned.
+func (flags *SourceUploadFlags) Upload(
+	ctx context.Context,
+	w io.Writer,
+	b SrcImageBuilder,
+	dir string,
+	srcImage string,
+	digest string,
+	filter KontextFilter,
+) (string, error) {
+	manifestPath := uploadManifestPath()
+	repository := imageutil.Repository(srcImage)
+
+	var manifest *uploadManifest
+	if manifestPath != "" {
+		// A corrupt manifest shouldn't block pushes, it just means we can't
+		// resume.
+		manifest, _ = readUploadManifest(manifestPath)
+		if image, ok := manifest.Lookup(repository, digest); ok {
+			if exists, err := sourceImageExists(ctx, image); err == nil && exists {
+				fmt.Fprintf(w, "Source is unchanged since a previous upload, resuming with %s\n", image)
+				return image, nil
+			}
+
+			fmt.Fprintf(w, "Previously uploaded source %s couldn't be found, uploading again\n", image)
+			manifest.Remove(repository, digest)
+		}
+	}
+
+	backoff := uploadRetryBackoff
+	var err error
+	for attempt := 0; attempt <= flags.retries; attempt++ {
+		if attempt > 0 {
+			fmt.Fprintf(w, "Source upload failed: %v\n", err)
+			fmt.Fprintf(w, "Retrying upload in %s (attempt %d of %d)\n", backoff, attempt, flags.retries)
+
+			select {
+			case <-ctx.Done():
+				return "", ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+
+		if err = flags.attempt(ctx, w, b, dir, srcImage, filter); err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if manifest != nil {
+		manifest.Record(repository, digest, srcImage)
+		if err := manifest.Write(manifestPath); err != nil {
+			fmt.Fprintf(w, "couldn't record upload, future pushes won't be able to resume: %v\n", err)
+		}
+	}
+
+	return srcImage, nil
+}
+
+// attempt runs a single upload, canceling it if it exceeds the timeout.
+func (flags *SourceUploadFlags) attempt(ctx context.Context, w io.Writer, b SrcImageBuilder, dir, srcImage string, filter KontextFilter) error {
+	if flags.timeout <= 0 {
+		return b.BuildSrcImage(ctx, w, dir, srcImage, filter)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, flags.timeout)
+	defer cancel()
+
+	err := b.BuildSrcImage(attemptCtx, w, dir, srcImage, filter)
+	if err != nil && attemptCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return fmt.Errorf("source upload timed out after %s", flags.timeout)
+	}
+
+	return err
+}
+
+// uploadManifest records source uploads that have completed, keyed by the
+// image repository and the digest of the source.
+type uploadManifest struct {
+	Uploads map[string]string `json:"uploads"`
+}
+
+func readUploadManifest(path string) (*uploadManifest, error) {
+	manifest := &uploadManifest{Uploads: make(map[string]string)}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return manifest, err
+	}
+
+	if err := json.Unmarshal(contents, manifest); err != nil {
+		return &uploadManifest{Uploads: make(map[string]string)}, err
+	}
+
+	if manifest.Uploads == nil {
+		manifest.Uploads = make(map[string]string)
+	}
+
+	return manifest, nil
+}
+
+// Lookup returns the image a source with the given digest was uploaded to.
+func (m *uploadManifest) Lookup(repository, digest string) (string, bool) {
+	image, ok := m.Uploads[repository+"@"+digest]
+	return image, ok
+}
+
+// Remove forgets the upload of a source with the given digest.
+func (m *uploadManifest) Remove(repository, digest string) {
+	delete(m.Uploads, repository+"@"+digest)
+}
+
+// Record saves the image a source with the given digest was uploaded to.
+func (m *uploadManifest) Record(repository, digest, image string) {
+	m.Uploads[repository+"@"+digest] = image
+}
+
+// Write saves the manifest to the given path.
+func (m *uploadManifest) Write(path string) error {
+	contents, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, contents, 0600)
+}
@@ -0,0 +1,196 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apps
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/sourceimage"
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/spf13/cobra"
+)
+
+func ExampleSourceUploadFlags() {
+	cmd := &cobra.Command{}
+	flags := SourceUploadFlags{}
+	flags.Add(cmd)
+
+	cmd.ParseFlags([]string{"--source-upload-timeout", "5m", "--source-upload-retries", "3"})
+	fmt.Println("Timeout:", flags.timeout)
+	fmt.Println("Retries:", flags.retries)
+
+	// Output: Timeout: 5m0s
+	// Retries: 3
+}
+
+func ExampleSourceUploadFlags_default() {
+	cmd := &cobra.Command{}
+	flags := SourceUploadFlags{}
+	flags.Add(cmd)
+
+	cmd.ParseFlags(nil)
+	fmt.Println("Timeout:", flags.timeout)
+	fmt.Println("Retries:", flags.retries)
+
+	// Output: Timeout: 0s
+	// Retries: 0
+}
+
+func TestSourceUploadFlags_Upload(t *testing.T) {
+	oldBackoff := uploadRetryBackoff
+	oldManifestPath := uploadManifestPath
+	oldImageExists := sourceImageExists
+	defer func() {
+		uploadRetryBackoff = oldBackoff
+		uploadManifestPath = oldManifestPath
+		sourceImageExists = oldImageExists
+	}()
+	uploadRetryBackoff = time.Millisecond
+
+	includeAll := func(string) (bool, error) { return true, nil }
+
+	cases := map[string]struct {
+		flags         SourceUploadFlags
+		previousImage string
+		imageMissing  bool
+		failures      int
+		hang          bool
+		canceled      bool
+
+		wantErr   error
+		wantCalls int
+		wantImage string
+	}{
+		"success": {
+			wantCalls: 1,
+			wantImage: "gcr.io/proj/src-ns-app:2",
+		},
+		"failure without retries": {
+			failures:  1,
+			wantCalls: 1,
+			wantErr:   errors.New("upload failed"),
+		},
+		"retries until success": {
+			flags:     SourceUploadFlags{retries: 3},
+			failures:  2,
+			wantCalls: 3,
+			wantImage: "gcr.io/proj/src-ns-app:2",
+		},
+		"retries exhausted": {
+			flags:     SourceUploadFlags{retries: 2},
+			failures:  5,
+			wantCalls: 3,
+			wantErr:   errors.New("upload failed"),
+		},
+		"timeout": {
+			flags:     SourceUploadFlags{timeout: 10 * time.Millisecond},
+			hang:      true,
+			wantCalls: 1,
+			wantErr:   errors.New("source upload timed out after 10ms"),
+		},
+		"canceled": {
+			flags:     SourceUploadFlags{retries: 3},
+			canceled:  true,
+			hang:      true,
+			wantCalls: 1,
+			wantErr:   context.Canceled,
+		},
+		"resumes previous upload": {
+			previousImage: "gcr.io/proj/src-ns-app:1",
+			wantCalls:     0,
+			wantImage:     "gcr.io/proj/src-ns-app:1",
+		},
+		"previous upload missing from registry": {
+			previousImage: "gcr.io/proj/src-ns-app:1",
+			imageMissing:  true,
+			wantCalls:     1,
+			wantImage:     "gcr.io/proj/src-ns-app:2",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			srcDir, err := ioutil.TempDir("", "upload-src")
+			testutil.AssertNil(t, "TempDir", err)
+			defer os.RemoveAll(srcDir)
+			testutil.AssertNil(t, "WriteFile", ioutil.WriteFile(filepath.Join(srcDir, "main.go"), []byte("package main"), 0600))
+
+			cacheDir, err := ioutil.TempDir("", "upload-cache")
+			testutil.AssertNil(t, "TempDir", err)
+			defer os.RemoveAll(cacheDir)
+			manifestPath := filepath.Join(cacheDir, "kf", "uploads.json")
+			uploadManifestPath = func() string { return manifestPath }
+
+			digest, err := sourceimage.Digest(srcDir, includeAll)
+			testutil.AssertNil(t, "Digest", err)
+
+			if tc.previousImage != "" {
+				manifest := &uploadManifest{Uploads: make(map[string]string)}
+				manifest.Record("gcr.io/proj/src-ns-app", digest, tc.previousImage)
+				testutil.AssertNil(t, "Write", manifest.Write(manifestPath))
+			}
+
+			sourceImageExists = func(ctx context.Context, image string) (bool, error) {
+				testutil.AssertEqual(t, "checked image", tc.previousImage, image)
+				return !tc.imageMissing, nil
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var calls int32
+			builder := SrcImageBuilderFunc(func(ctx context.Context, out io.Writer, dir, srcImage string, filter KontextFilter) error {
+				call := atomic.AddInt32(&calls, 1)
+				if tc.canceled {
+					cancel()
+				}
+				if tc.hang {
+					// Attempts must be canceled rather than abandoned.
+					<-ctx.Done()
+					return ctx.Err()
+				}
+				if int(call) <= tc.failures {
+					return errors.New("upload failed")
+				}
+				return nil
+			})
+
+			image, gotErr := tc.flags.Upload(ctx, &bytes.Buffer{}, builder, srcDir, "gcr.io/proj/src-ns-app:2", digest, includeAll)
+			testutil.AssertEqual(t, "calls", tc.wantCalls, int(atomic.LoadInt32(&calls)))
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+				return
+			}
+
+			testutil.AssertEqual(t, "image", tc.wantImage, image)
+
+			manifest, err := readUploadManifest(manifestPath)
+			testutil.AssertNil(t, "readUploadManifest", err)
+			recorded, ok := manifest.Lookup("gcr.io/proj/src-ns-app", digest)
+			testutil.AssertEqual(t, "recorded", true, ok)
+			testutil.AssertEqual(t, "recorded image", tc.wantImage, recorded)
+		})
+	}
+}
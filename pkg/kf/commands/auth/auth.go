@@ -0,0 +1,51 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// IdentityLoader gets the identity Kf connects to the cluster as.
+type IdentityLoader func() (*config.Identity, error)
+
+// NewAuthCommand creates a command with sub-commands to inspect the
+// credentials Kf uses.
+func NewAuthCommand(
+	p *config.KfParams,
+	loadIdentity IdentityLoader,
+	reviews authorizationv1.SelfSubjectAccessReviewsGetter,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth [subcommand]",
+		Short: "Inspect the credentials Kf uses to connect to the cluster",
+		Long: `The auth sub-command shows who Kf connects to the cluster as and
+		checks whether they have the permissions Kf needs, so permission
+		errors can be diagnosed before a command fails part way through.
+		`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newWhoAmICommand(p, loadIdentity),
+		newCanICommand(p, reviews),
+	)
+
+	return cmd
+}
@@ -0,0 +1,228 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/describe"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+	authv1 "k8s.io/api/authorization/v1"
+	authorizationv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
+)
+
+// permission is a single RBAC rule Kf needs to carry out an action.
+type permission struct {
+	Group       string
+	Resource    string
+	Subresource string
+	Verb        string
+
+	// ClusterScoped is set for resources that don't live in the space's
+	// namespace.
+	ClusterScoped bool
+}
+
+func (p permission) resourceName() string {
+	name := p.Resource
+	if p.Subresource != "" {
+		name += "/" + p.Subresource
+	}
+	if p.Group != "" {
+		name += "." + p.Group
+	}
+	return name
+}
+
+func (p permission) attributes(namespace string) *authv1.ResourceAttributes {
+	attrs := &authv1.ResourceAttributes{
+		Group:       p.Group,
+		Resource:    p.Resource,
+		Subresource: p.Subresource,
+		Verb:        p.Verb,
+	}
+	if !p.ClusterScoped {
+		attrs.Namespace = namespace
+	}
+	return attrs
+}
+
+// actionPermissions holds the permissions Kf needs for each action that can
+// be checked with can-i.
+var actionPermissions = map[string][]permission{
+	"push": {
+		{Group: "kf.dev", Resource: "spaces", Verb: "get", ClusterScoped: true},
+		{Group: "kf.dev", Resource: "apps", Verb: "get"},
+		{Group: "kf.dev", Resource: "apps", Verb: "create"},
+		{Group: "kf.dev", Resource: "apps", Verb: "update"},
+		{Group: "kf.dev", Resource: "apps", Verb: "watch"},
+		{Group: "kf.dev", Resource: "sources", Verb: "list"},
+		{Group: "kf.dev", Resource: "sources", Verb: "watch"},
+	},
+	"delete": {
+		{Group: "kf.dev", Resource: "apps", Verb: "get"},
+		{Group: "kf.dev", Resource: "apps", Verb: "delete"},
+	},
+	"set-env": {
+		{Group: "kf.dev", Resource: "apps", Verb: "get"},
+		{Group: "kf.dev", Resource: "apps", Verb: "update"},
+	},
+	"scale": {
+		{Group: "kf.dev", Resource: "apps", Verb: "get"},
+		{Group: "kf.dev", Resource: "apps", Verb: "update"},
+	},
+	"logs": {
+		{Resource: "pods", Verb: "list"},
+		{Resource: "pods", Verb: "watch"},
+		{Resource: "pods", Subresource: "log", Verb: "get"},
+	},
+	"ssh": {
+		{Resource: "pods", Verb: "list"},
+		{Resource: "pods", Subresource: "exec", Verb: "create"},
+	},
+	"run": {
+		{Resource: "pods", Verb: "list"},
+		{Resource: "pods", Subresource: "ephemeralcontainers", Verb: "patch"},
+		{Resource: "pods", Subresource: "attach", Verb: "create"},
+	},
+	"bind-service": {
+		{Group: "kf.dev", Resource: "apps", Verb: "get"},
+		{Group: "servicecatalog.k8s.io", Resource: "servicebindings", Verb: "create"},
+	},
+	"create-route": {
+		{Group: "kf.dev", Resource: "routeclaims", Verb: "create"},
+	},
+	"create-space": {
+		{Group: "kf.dev", Resource: "spaces", Verb: "create", ClusterScoped: true},
+	},
+	"configure-space": {
+		{Group: "kf.dev", Resource: "spaces", Verb: "get", ClusterScoped: true},
+		{Group: "kf.dev", Resource: "spaces", Verb: "update", ClusterScoped: true},
+	},
+}
+
+func supportedActions() []string {
+	var actions []string
+	for action := range actionPermissions {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+func newCanICommand(
+	p *config.KfParams,
+	reviews authorizationv1.SelfSubjectAccessReviewsGetter,
+) *cobra.Command {
+	var space string
+
+	cmd := &cobra.Command{
+		Use:   "can-i ACTION",
+		Short: "Check whether you have the permissions Kf needs for an action",
+		Long: fmt.Sprintf(`Check whether you have the permissions Kf needs for an action.
+
+		Each RBAC rule the action needs is checked with a
+		SelfSubjectAccessReview and the results are printed. The command fails
+		if any rule is denied.
+
+		Supported actions: %s
+		`, strings.Join(supportedActions(), ", ")),
+		Example: `
+		kf auth can-i push
+		kf auth can-i push --space my-space`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			action := args[0]
+
+			permissions, ok := actionPermissions[action]
+			if !ok {
+				return fmt.Errorf(
+					"unknown action %q, supported actions are: %s",
+					action,
+					strings.Join(supportedActions(), ", "),
+				)
+			}
+
+			if space == "" {
+				space = p.Namespace
+			}
+			if space == "" {
+				return errors.New(utils.EmptyNamespaceError)
+			}
+
+			cmd.SilenceUsage = true
+
+			type result struct {
+				permission
+				allowed bool
+				reason  string
+			}
+
+			var results []result
+			denied := 0
+			for _, perm := range permissions {
+				review, err := reviews.SelfSubjectAccessReviews().Create(&authv1.SelfSubjectAccessReview{
+					Spec: authv1.SelfSubjectAccessReviewSpec{
+						ResourceAttributes: perm.attributes(space),
+					},
+				})
+				if err != nil {
+					return fmt.Errorf("failed to check %s %s: %s", perm.Verb, perm.resourceName(), err)
+				}
+
+				if !review.Status.Allowed {
+					denied++
+				}
+				results = append(results, result{
+					permission: perm,
+					allowed:    review.Status.Allowed,
+					reason:     review.Status.Reason,
+				})
+			}
+
+			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+				fmt.Fprintln(w, "Verb\tResource\tAllowed\tReason")
+				for _, r := range results {
+					allowed := "no"
+					if r.allowed {
+						allowed = "yes"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Verb, r.resourceName(), allowed, r.reason)
+				}
+			})
+
+			if denied > 0 {
+				return fmt.Errorf("missing %d permission(s) to %s in space %q", denied, action, space)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&space,
+		"space",
+		"",
+		"Space to check permissions in, defaults to the targeted space.",
+	)
+
+	return cmd
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/testutil"
+	authv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func TestNewCanICommand(t *testing.T) {
+	cases := map[string]struct {
+		Namespace       string
+		Args            []string
+		Denied          map[string]bool
+		ReviewErr       error
+		ExpectedStrings []string
+		ExpectedErr     error
+		ExpectedSpace   string
+	}{
+		"wrong number of params": {
+			Namespace:   "my-space",
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"unknown action": {
+			Namespace:   "my-space",
+			Args:        []string{"fly"},
+			ExpectedErr: errors.New(`unknown action "fly", supported actions are: bind-service, configure-space, create-route, create-space, delete, logs, push, run, scale, set-env, ssh`),
+		},
+		"namespace is not provided": {
+			Args:        []string{"push"},
+			ExpectedErr: errors.New(utils.EmptyNamespaceError),
+		},
+		"all allowed": {
+			Namespace:       "my-space",
+			Args:            []string{"push"},
+			ExpectedStrings: []string{"Verb", "create", "apps.kf.dev", "sources.kf.dev", "yes"},
+			ExpectedSpace:   "my-space",
+		},
+		"space flag overrides target": {
+			Namespace:     "my-space",
+			Args:          []string{"delete", "--space", "other-space"},
+			ExpectedSpace: "other-space",
+		},
+		"subresources": {
+			Namespace:       "my-space",
+			Args:            []string{"logs"},
+			ExpectedStrings: []string{"pods/log"},
+			ExpectedSpace:   "my-space",
+		},
+		"some denied": {
+			Namespace: "my-space",
+			Args:      []string{"push"},
+			Denied: map[string]bool{
+				"create": true,
+				"update": true,
+			},
+			ExpectedErr:   errors.New(`missing 2 permission(s) to push in space "my-space"`),
+			ExpectedSpace: "my-space",
+		},
+		"review fails": {
+			Namespace:   "my-space",
+			Args:        []string{"scale"},
+			ReviewErr:   errors.New("some-error"),
+			ExpectedErr: errors.New("failed to check get apps.kf.dev: some-error"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			client := k8sfake.NewSimpleClientset()
+			client.PrependReactor("create", "selfsubjectaccessreviews", func(action ktesting.Action) (bool, runtime.Object, error) {
+				if tc.ReviewErr != nil {
+					// The fake casts the returned object even on errors.
+					return true, &authv1.SelfSubjectAccessReview{}, tc.ReviewErr
+				}
+
+				review := action.(ktesting.CreateAction).GetObject().(*authv1.SelfSubjectAccessReview)
+				attrs := review.Spec.ResourceAttributes
+				if attrs.Group == "kf.dev" && attrs.Resource == "spaces" {
+					testutil.AssertEqual(t, "cluster scoped namespace", "", attrs.Namespace)
+				} else {
+					testutil.AssertEqual(t, "namespace", tc.ExpectedSpace, attrs.Namespace)
+				}
+
+				review.Status.Allowed = !tc.Denied[attrs.Verb]
+				if !review.Status.Allowed {
+					review.Status.Reason = "no RBAC policy matched"
+				}
+				return true, review, nil
+			})
+
+			buf := &bytes.Buffer{}
+			p := &config.KfParams{Namespace: tc.Namespace}
+
+			cmd := newCanICommand(p, client.AuthorizationV1())
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.Args)
+
+			gotErr := cmd.Execute()
+			if tc.ExpectedErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, gotErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+		})
+	}
+}
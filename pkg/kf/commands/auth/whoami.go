@@ -0,0 +1,71 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/describe"
+	"github.com/spf13/cobra"
+)
+
+func newWhoAmICommand(p *config.KfParams, loadIdentity IdentityLoader) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "whoami",
+		Short: "Show the identity Kf connects to the cluster as",
+		Long: `Show the identity Kf connects to the cluster as.
+
+		The identity is read from the kubeconfig. For auth providers and exec
+		plugins the user is the name of the kubeconfig entry, the account it
+		maps to is decided by the provider. If --as is set, the user being
+		impersonated is also shown.
+		`,
+		Example: "kf auth whoami",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			identity, err := loadIdentity()
+			if err != nil {
+				return err
+			}
+
+			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+				fmt.Fprintf(w, "User:\t%s\n", identity.User)
+				if len(identity.Groups) > 0 {
+					fmt.Fprintf(w, "Groups:\t%s\n", strings.Join(identity.Groups, ", "))
+				}
+				fmt.Fprintf(w, "Auth Method:\t%s\n", identity.AuthMethod)
+				if identity.ImpersonatedUser != "" {
+					fmt.Fprintf(w, "Impersonating:\t%s\n", identity.ImpersonatedUser)
+				}
+				if len(identity.ImpersonatedGroups) > 0 {
+					fmt.Fprintf(w, "Impersonating Groups:\t%s\n", strings.Join(identity.ImpersonatedGroups, ", "))
+				}
+				fmt.Fprintf(w, "Context:\t%s\n", identity.Context)
+				fmt.Fprintf(w, "Cluster:\t%s\n", identity.Cluster)
+				fmt.Fprintf(w, "Server:\t%s\n", identity.Server)
+				fmt.Fprintf(w, "Space:\t%s\n", p.Namespace)
+			})
+
+			return nil
+		},
+	}
+
+	return cmd
+}
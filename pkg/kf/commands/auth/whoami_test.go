@@ -0,0 +1,93 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/testutil"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewWhoAmICommand(t *testing.T) {
+	cases := map[string]struct {
+		Identity        *config.Identity
+		IdentityErr     error
+		ExpectedStrings []string
+		ExpectedErr     error
+	}{
+		"bad config": {
+			IdentityErr: errors.New("kubeconfig has no current context"),
+			ExpectedErr: errors.New("kubeconfig has no current context"),
+		},
+		"prints identity": {
+			Identity: &config.Identity{
+				Context:    "my-context",
+				Cluster:    "my-cluster",
+				Server:     "https://1.2.3.4",
+				User:       "jane",
+				Groups:     []string{"devs", "ops"},
+				AuthMethod: "client certificate",
+			},
+			ExpectedStrings: []string{
+				"jane",
+				"devs, ops",
+				"client certificate",
+				"my-context",
+				"my-cluster",
+				"https://1.2.3.4",
+				"my-space",
+			},
+		},
+		"prints impersonation": {
+			Identity: &config.Identity{
+				User:               "admin",
+				AuthMethod:         "bearer token",
+				ImpersonatedUser:   "jane@example.com",
+				ImpersonatedGroups: []string{"devs"},
+			},
+			ExpectedStrings: []string{
+				"Impersonating:",
+				"jane@example.com",
+				"Impersonating Groups:",
+				"devs",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			p := &config.KfParams{Namespace: "my-space"}
+
+			cmd := NewAuthCommand(p, func() (*config.Identity, error) {
+				return tc.Identity, tc.IdentityErr
+			}, k8sfake.NewSimpleClientset().AuthorizationV1())
+			cmd.SetOutput(buf)
+			cmd.SetArgs([]string{"whoami"})
+
+			gotErr := cmd.Execute()
+			if tc.ExpectedErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, gotErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+		})
+	}
+}
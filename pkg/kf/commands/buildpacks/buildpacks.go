@@ -50,7 +50,7 @@ func NewBuildpacksCommand(p *config.KfParams, l buildpacks.Client) *cobra.Comman
 
 			fmt.Fprintf(cmd.OutOrStdout(), "Getting buildpacks in space: %s\n", p.Namespace)
 
-			bps, err := l.List(space.Spec.BuildpackBuild.BuilderImage)
+			bps, err := l.List(space.EffectiveBuilderImage())
 			if err != nil {
 				cmd.SilenceUsage = !utils.ConfigError(err)
 				return err
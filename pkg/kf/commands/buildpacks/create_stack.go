@@ -0,0 +1,106 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildpacks
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/stacks"
+	"github.com/spf13/cobra"
+)
+
+// NewCreateStackCommand creates a CreateStack command.
+func NewCreateStackCommand(p *config.KfParams, client stacks.Client) *cobra.Command {
+	var stack stacks.Stack
+
+	cmd := &cobra.Command{
+		Use:     "create-stack NAME --build-image IMAGE --run-image IMAGE",
+		Short:   "Create a stack apps can be built with",
+		Example: `kf create-stack cflinuxfs3 --build-image gcr.io/my-project/builder --run-image cloudfoundry/cflinuxfs3`,
+		Long: `Create a stack on the cluster.
+
+		A stack pairs a buildpacks.io builder image used to build apps with the
+		run image built apps use as their base. Spaces can choose a default stack
+		with configure-space set-default-stack and apps can choose a stack with
+		the stack field in their manifest.
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			stack.Name = args[0]
+			if err := stack.Validate(); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			if err := client.Create(stack); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Stack %s created\n", stack.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&stack.BuildImage,
+		"build-image",
+		"",
+		"Builder image used to build apps on the stack",
+	)
+
+	cmd.Flags().StringVar(
+		&stack.RunImage,
+		"run-image",
+		"",
+		"Base image built apps on the stack run on",
+	)
+
+	cmd.Flags().StringVar(
+		&stack.Description,
+		"description",
+		"",
+		"Description shown when listing stacks",
+	)
+
+	return cmd
+}
+
+// NewDeleteStackCommand creates a DeleteStack command.
+func NewDeleteStackCommand(p *config.KfParams, client stacks.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:     "delete-stack NAME",
+		Short:   "Delete a stack from the cluster",
+		Example: `kf delete-stack cflinuxfs3`,
+		Long: `Delete a stack from the cluster.
+
+		Apps that were built with the stack keep running, but they'll fail to
+		build again until they choose another stack.
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			name := args[0]
+			if err := client.Delete(name); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Stack %s deleted\n", name)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildpacks_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	cbuildpacks "github.com/google/kf/pkg/kf/commands/buildpacks"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/stacks"
+	"github.com/google/kf/pkg/kf/stacks/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestCreateStack(t *testing.T) {
+	t.Parallel()
+
+	for tn, tc := range map[string]struct {
+		Args        []string
+		ExpectedErr error
+		Setup       func(t *testing.T, fake *fake.FakeClient)
+		BufferF     func(t *testing.T, buffer *bytes.Buffer)
+	}{
+		"wrong number of args": {
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"missing build image": {
+			Args:        []string{"my-stack", "--run-image", "run"},
+			ExpectedErr: errors.New("stack my-stack must have a build image"),
+		},
+		"missing run image": {
+			Args:        []string{"my-stack", "--build-image", "build"},
+			ExpectedErr: errors.New("stack my-stack must have a run image"),
+		},
+		"creating fails": {
+			Args:        []string{"my-stack", "--build-image", "build", "--run-image", "run"},
+			ExpectedErr: errors.New("some-error"),
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Create(gomock.Any()).Return(errors.New("some-error"))
+			},
+		},
+		"creates the stack": {
+			Args: []string{"my-stack", "--build-image", "build", "--run-image", "run", "--description", "desc"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Create(stacks.Stack{
+					Name:        "my-stack",
+					Description: "desc",
+					BuildImage:  "build",
+					RunImage:    "run",
+				})
+			},
+			BufferF: func(t *testing.T, buffer *bytes.Buffer) {
+				testutil.AssertContainsAll(t, buffer.String(), []string{"my-stack"})
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fake := fake.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fake)
+			}
+
+			var buffer bytes.Buffer
+			cmd := cbuildpacks.NewCreateStackCommand(&config.KfParams{}, fake)
+			cmd.SetArgs(tc.Args)
+			cmd.SetOutput(&buffer)
+
+			gotErr := cmd.Execute()
+			if gotErr != nil || tc.ExpectedErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, gotErr)
+				return
+			}
+
+			if tc.BufferF != nil {
+				tc.BufferF(t, &buffer)
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
+
+func TestDeleteStack(t *testing.T) {
+	t.Parallel()
+
+	for tn, tc := range map[string]struct {
+		Args        []string
+		ExpectedErr error
+		Setup       func(t *testing.T, fake *fake.FakeClient)
+	}{
+		"wrong number of args": {
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"deleting fails": {
+			Args:        []string{"my-stack"},
+			ExpectedErr: errors.New("stack my-stack doesn't exist"),
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Delete("my-stack").Return(errors.New("stack my-stack doesn't exist"))
+			},
+		},
+		"deletes the stack": {
+			Args: []string{"my-stack"},
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Delete("my-stack")
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fake := fake.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fake)
+			}
+
+			var buffer bytes.Buffer
+			cmd := cbuildpacks.NewDeleteStackCommand(&config.KfParams{}, fake)
+			cmd.SetArgs(tc.Args)
+			cmd.SetOutput(&buffer)
+
+			gotErr := cmd.Execute()
+			testutil.AssertErrorsEqual(t, tc.ExpectedErr, gotErr)
+
+			ctrl.Finish()
+		})
+	}
+}
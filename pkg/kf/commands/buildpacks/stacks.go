@@ -22,11 +22,16 @@ import (
 	"github.com/google/kf/pkg/kf/commands/config"
 	"github.com/google/kf/pkg/kf/describe"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/stacks"
 	"github.com/spf13/cobra"
 )
 
 // NewStacksCommand creates a Stacks command.
-func NewStacksCommand(p *config.KfParams, l buildpacks.Client) *cobra.Command {
+func NewStacksCommand(
+	p *config.KfParams,
+	l buildpacks.Client,
+	stacksClient stacks.Client,
+) *cobra.Command {
 	var buildpacksCmd = &cobra.Command{
 		Use:     "stacks",
 		Short:   "List stacks available in the space",
@@ -35,8 +40,12 @@ func NewStacksCommand(p *config.KfParams, l buildpacks.Client) *cobra.Command {
 		Long: `List the stacks available in the space to applications being built
 		with buildpacks.
 
-		Stack support is determined by the buildpack builder image so they can
-		change from one space to the next.
+		Stacks configured on the cluster pair a builder image with a run image
+		and can be chosen by apps using the stack field in their manifest. The
+		space's default stack is marked with an asterisk.
+
+		Stacks supported by the space's buildpack builder image are listed too,
+		so they can change from one space to the next.
 		`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := utils.ValidateNamespace(p); err != nil {
@@ -50,16 +59,42 @@ func NewStacksCommand(p *config.KfParams, l buildpacks.Client) *cobra.Command {
 
 			fmt.Fprintf(cmd.OutOrStdout(), "Getting stacks in space: %s\n", p.Namespace)
 
-			stacks, err := l.Stacks(space.Spec.BuildpackBuild.BuilderImage)
+			clusterStacks, err := stacksClient.List()
+			if err != nil {
+				cmd.SilenceUsage = !utils.ConfigError(err)
+				return err
+			}
+
+			builderStacks, err := l.Stacks(space.EffectiveBuilderImage())
 			if err != nil {
 				cmd.SilenceUsage = !utils.ConfigError(err)
 				return err
 			}
 
+			if len(clusterStacks) > 0 {
+				defaultStack := space.Spec.BuildpackBuild.DefaultStack
+
+				describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+					fmt.Fprintln(w, "Name\tDefault\tBuild Image\tRun Image\tDescription")
+
+					for _, s := range clusterStacks {
+						isDefault := ""
+						if s.Name == defaultStack {
+							isDefault = "*"
+						}
+
+						fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Name, isDefault, s.BuildImage, s.RunImage, s.Description)
+					}
+				})
+
+				fmt.Fprintln(cmd.OutOrStdout())
+				fmt.Fprintln(cmd.OutOrStdout(), "Stacks supported by the builder image:")
+			}
+
 			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
 				fmt.Fprintln(w, "Name")
 
-				for _, s := range stacks {
+				for _, s := range builderStacks {
 					fmt.Fprintf(w, "%s\n", s)
 				}
 			})
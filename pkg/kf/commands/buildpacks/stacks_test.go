@@ -24,6 +24,8 @@ import (
 	cbuildpacks "github.com/google/kf/pkg/kf/commands/buildpacks"
 	"github.com/google/kf/pkg/kf/commands/config"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/stacks"
+	stacksfake "github.com/google/kf/pkg/kf/stacks/fake"
 	"github.com/google/kf/pkg/kf/testutil"
 )
 
@@ -35,6 +37,7 @@ func TestStacks(t *testing.T) {
 		ExpectedErr error
 		Args        []string
 		Setup       func(t *testing.T, fake *fake.FakeClient, params *config.KfParams)
+		SetupStacks func(t *testing.T, fake *stacksfake.FakeClient)
 		BufferF     func(t *testing.T, buffer *bytes.Buffer)
 	}{
 		"wrong number of args": {ExpectedErr: errors.New("accepts 0 arg(s), received 1"),
@@ -64,10 +67,47 @@ func TestStacks(t *testing.T) {
 				testutil.AssertContainsAll(t, buffer.String(), []string{"s-1", "s-2"})
 			},
 		},
+		"listing cluster stacks fails": {
+			Namespace:   "my-space",
+			ExpectedErr: errors.New("some-error"),
+			SetupStacks: func(t *testing.T, fake *stacksfake.FakeClient) {
+				fake.EXPECT().List().Return(nil, errors.New("some-error"))
+			},
+		},
+		"lists cluster stacks": {
+			Namespace: "my-space",
+			Setup: func(t *testing.T, fake *fake.FakeClient, params *config.KfParams) {
+				params.TargetSpace.Spec.BuildpackBuild.BuilderImage = "my-image"
+				params.TargetSpace.Spec.BuildpackBuild.DefaultStack = "cflinuxfs3"
+
+				fake.EXPECT().Stacks("my-image").Return([]string{"s-1"}, nil)
+			},
+			SetupStacks: func(t *testing.T, fake *stacksfake.FakeClient) {
+				fake.EXPECT().List().Return([]stacks.Stack{
+					{
+						Name:        "cflinuxfs3",
+						Description: "some-description",
+						BuildImage:  "some-build-image",
+						RunImage:    "some-run-image",
+					},
+				}, nil)
+			},
+			BufferF: func(t *testing.T, buffer *bytes.Buffer) {
+				testutil.AssertContainsAll(t, buffer.String(), []string{
+					"cflinuxfs3",
+					"*",
+					"some-description",
+					"some-build-image",
+					"some-run-image",
+					"s-1",
+				})
+			},
+		},
 	} {
 		t.Run(tn, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			fake := fake.NewFakeClient(ctrl)
+			fakeStacks := stacksfake.NewFakeClient(ctrl)
 
 			params := &config.KfParams{
 				Namespace: tc.Namespace,
@@ -78,10 +118,17 @@ func TestStacks(t *testing.T) {
 				tc.Setup(t, fake, params)
 			}
 
+			if tc.SetupStacks != nil {
+				tc.SetupStacks(t, fakeStacks)
+			} else {
+				fakeStacks.EXPECT().List().Return(nil, nil).AnyTimes()
+			}
+
 			var buffer bytes.Buffer
 			cmd := cbuildpacks.NewStacksCommand(
 				params,
 				fake,
+				fakeStacks,
 			)
 			cmd.SetArgs(tc.Args)
 			cmd.SetOutput(&buffer)
@@ -29,24 +29,60 @@ import (
 
 // NewListBuildsCommand allows users to list spaces.
 func NewListBuildsCommand(p *config.KfParams, client sources.Client) *cobra.Command {
+	var (
+		allSpaces utils.AllSpacesFlags
+		listFlags utils.ListFlags
+	)
+
 	cmd := &cobra.Command{
-		Use:     "builds",
-		Short:   "List the builds in the current space",
-		Example: `kf builds`,
-		Args:    cobra.ExactArgs(0),
+		Use:   "builds",
+		Short: "List the builds in the current space",
+		Example: `
+		kf builds
+		kf builds --all-spaces
+		kf builds --filter my-app- --sort-by age
+		kf builds --limit 20
+		`,
+		Args: cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := utils.ValidateNamespace(p); err != nil {
+			if err := listFlags.Validate(); err != nil {
+				return err
+			}
+
+			namespace, err := allSpaces.Namespace(p)
+			if err != nil {
 				return err
 			}
 
 			cmd.SilenceUsage = true
 
-			list, err := client.List(p.Namespace)
+			list, continueToken, err := client.ListPage(
+				namespace,
+				sources.WithListLimit(listFlags.Limit()),
+				sources.WithListContinueToken(listFlags.ContinueToken()),
+				sources.WithListFilter(func(source *v1alpha1.Source) bool {
+					return listFlags.MatchesName(source.Name)
+				}),
+			)
 			if err != nil {
 				return err
 			}
 
+			listFlags.Sort(list, func(i int) utils.ListSortKey {
+				key := utils.ListSortKey{
+					Name:    list[i].Name,
+					Created: list[i].CreationTimestamp.Time,
+				}
+				if cond := list[i].Status.GetCondition(v1alpha1.SourceConditionSucceeded); cond != nil {
+					key.Status = fmt.Sprintf("%v", cond.Status)
+				}
+				return key
+			})
+
 			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+				if allSpaces.IsAllSpaces() {
+					fmt.Fprint(w, "Space\t")
+				}
 				fmt.Fprintln(w, "Name\tAge\tReady\tReason\tImage")
 
 				for _, source := range list {
@@ -57,6 +93,9 @@ func NewListBuildsCommand(p *config.KfParams, client sources.Client) *cobra.Comm
 						reason = cond.Reason
 					}
 
+					if allSpaces.IsAllSpaces() {
+						fmt.Fprintf(w, "%s\t", source.Namespace)
+					}
 					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s",
 						source.Name,
 						table.ConvertToHumanReadableDateType(source.CreationTimestamp),
@@ -68,9 +107,14 @@ func NewListBuildsCommand(p *config.KfParams, client sources.Client) *cobra.Comm
 				}
 			})
 
+			listFlags.PrintNextPage(cmd.OutOrStdout(), continueToken)
+
 			return nil
 		},
 	}
 
+	allSpaces.Add(cmd)
+	listFlags.Add(cmd)
+
 	return cmd
 }
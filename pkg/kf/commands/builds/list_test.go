@@ -52,8 +52,8 @@ func TestNewListBuildsCommand(t *testing.T) {
 				list := []v1alpha1.Source{}
 				fakeSources.
 					EXPECT().
-					List("my-ns").
-					Return(list, nil)
+					ListPage("my-ns", gomock.Any()).
+					Return(list, "", nil)
 			},
 			expectedStrings: []string{"Name", "Age", "Ready", "Reason"},
 		},
@@ -72,18 +72,51 @@ func TestNewListBuildsCommand(t *testing.T) {
 				list := []v1alpha1.Source{bld}
 				fakeSources.
 					EXPECT().
-					List("my-ns").
-					Return(list, nil)
+					ListPage("my-ns", gomock.Any()).
+					Return(list, "", nil)
 			},
 			expectedStrings: []string{"my-build", "TESTING", "SomeMessage", "gcr.io/my-image"},
 		},
+		"all spaces": {
+			args: []string{"--all-spaces"},
+			setup: func(t *testing.T, fakeSources *fake.FakeClient) {
+				bld := v1alpha1.Source{}
+				bld.Name = "my-build"
+				bld.Namespace = "other-ns"
+
+				fakeSources.
+					EXPECT().
+					ListPage("", gomock.Any()).
+					Return([]v1alpha1.Source{bld}, "", nil)
+			},
+			expectedStrings: []string{"Space", "other-ns", "my-build"},
+		},
+		"sorts by status": {
+			namespace: "my-ns",
+			args:      []string{"--sort-by", "status"},
+			setup: func(t *testing.T, fakeSources *fake.FakeClient) {
+				failed := v1alpha1.Source{}
+				failed.Name = "a-build"
+				failed.Status.Conditions = []apis.Condition{{Type: "Succeeded", Status: "False"}}
+
+				succeeded := v1alpha1.Source{}
+				succeeded.Name = "b-build"
+				succeeded.Status.Conditions = []apis.Condition{{Type: "Succeeded", Status: "True"}}
+
+				fakeSources.
+					EXPECT().
+					ListPage("my-ns", gomock.Any()).
+					Return([]v1alpha1.Source{succeeded, failed}, "next-token", nil)
+			},
+			expectedStrings: []string{"a-build", "b-build", "--continue next-token"},
+		},
 		"server failure": {
 			namespace: "my-ns",
 			setup: func(t *testing.T, fakeSources *fake.FakeClient) {
 				fakeSources.
 					EXPECT().
-					List("my-ns").
-					Return(nil, errors.New("some-server-error"))
+					ListPage("my-ns", gomock.Any()).
+					Return(nil, "", errors.New("some-server-error"))
 			},
 			wantErr: errors.New("some-server-error"),
 		},
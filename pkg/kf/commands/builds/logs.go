@@ -27,8 +27,13 @@ import (
 // NewBuildLogsCommand allows users to list spaces.
 func NewBuildLogsCommand(p *config.KfParams, client sources.Client) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:     "build-logs BUILD_NAME",
-		Short:   "Get the logs of the given build",
+		Use:   "build-logs BUILD_NAME",
+		Short: "Get the logs of the given build",
+		Long: `Get the logs of the given build.
+
+If the build's pod has been cleaned up and the space archives build logs to a
+Cloud Storage bucket, the archived logs are read instead. Reading the archive
+uses your application default credentials.`,
 		Example: "kf build-logs build-12345",
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
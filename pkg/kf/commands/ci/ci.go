@@ -0,0 +1,40 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ci
+
+import (
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/spf13/cobra"
+)
+
+// NewCICommand creates a command that groups helpers for deploying apps from
+// CI systems.
+func NewCICommand(p *config.KfParams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "ci [subcommand]",
+		Short: "Set up CI pipelines that deploy apps with kf",
+		Long: `The ci sub-command contains helpers to deploy apps from CI systems
+		with kf.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		NewGenerateCommand(p),
+	)
+
+	return cmd
+}
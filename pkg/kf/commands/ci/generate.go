@@ -0,0 +1,139 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ci
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/google/kf/pkg/kf/ci"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/manifest"
+	"github.com/spf13/cobra"
+)
+
+// placeholderCluster is used in pipelines when the GKE cluster can't be
+// detected from the kubeconfig.
+var placeholderCluster = ci.GKECluster{
+	Project:  "PROJECT_ID",
+	Location: "CLUSTER_LOCATION",
+	Name:     "CLUSTER_NAME",
+}
+
+// NewGenerateCommand creates a command that prints a pipeline definition to
+// deploy an app from a CI system.
+func NewGenerateCommand(p *config.KfParams) *cobra.Command {
+	var (
+		appName      string
+		manifestPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   fmt.Sprintf("generate (%s)", strings.Join(ci.Providers, "|")),
+		Short: "Print a CI pipeline that deploys an app to the targeted space",
+		Long: `Prints a pipeline definition for the given CI system that deploys an
+		app to the targeted space every time the main branch changes.
+
+		The pipeline authenticates to the GKE cluster in the current
+		kubeconfig context, targets the space, pushes the app in machine mode,
+		then checks that the app's route serves traffic. If the push or the
+		check fails, the app is rolled back to the image it ran before.
+
+		The app name is read from the manifest if it has a single app,
+		otherwise use --app. Save the output to the file the CI system reads:
+
+		  github-actions: .github/workflows/kf-deploy.yml
+		  cloud-build:    cloudbuild.yaml
+		  gitlab:         .gitlab-ci.yml
+		`,
+		Example: `
+		kf ci generate github-actions > .github/workflows/kf-deploy.yml
+		kf ci generate cloud-build --app myapp > cloudbuild.yaml
+		kf ci generate gitlab -f deploy/manifest.yml > .gitlab-ci.yml
+		`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: ci.Providers,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			provider := args[0]
+
+			pipeline := ci.Pipeline{
+				App:   appName,
+				Space: p.Namespace,
+			}
+
+			if _, err := os.Stat(manifestPath); err == nil {
+				pipeline.Manifest = manifestPath
+			}
+
+			if pipeline.App == "" {
+				if pipeline.Manifest == "" {
+					return fmt.Errorf("no manifest found at %s, use --app to set the app", manifestPath)
+				}
+
+				m, err := manifest.NewFromFile(manifestPath)
+				if err != nil {
+					return err
+				}
+
+				if len(m.Applications) != 1 {
+					return fmt.Errorf("%s has %d apps, use --app to pick one", manifestPath, len(m.Applications))
+				}
+
+				pipeline.App = m.Applications[0].Name
+			}
+
+			cmd.SilenceUsage = true
+
+			pipeline.Cluster = placeholderCluster
+			if identity, err := config.GetIdentity(p); err == nil {
+				if cluster, ok := ci.GKEClusterFromContext(identity.Cluster); ok {
+					pipeline.Cluster = cluster
+				}
+			}
+
+			if pipeline.Cluster == placeholderCluster {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Couldn't detect the GKE cluster from the kubeconfig, replace %s, %s and %s in the pipeline\n",
+					placeholderCluster.Project,
+					placeholderCluster.Location,
+					placeholderCluster.Name)
+			}
+
+			return ci.Generate(cmd.OutOrStdout(), provider, pipeline)
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&appName,
+		"app",
+		"",
+		"Name of the app to deploy, defaults to the only app in the manifest",
+	)
+
+	cmd.Flags().StringVarP(
+		&manifestPath,
+		"manifest",
+		"f",
+		"manifest.yml",
+		"Path to the manifest the pipeline pushes with, relative to the root of the repository",
+	)
+
+	return cmd
+}
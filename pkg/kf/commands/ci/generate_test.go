@@ -0,0 +1,144 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ci_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/commands/ci"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+const gkeKubeconfig = `
+apiVersion: v1
+kind: Config
+current-context: gke
+contexts:
+- name: gke
+  context:
+    cluster: gke_my-project_us-central1_my-cluster
+    user: gke-user
+clusters:
+- name: gke_my-project_us-central1_my-cluster
+  cluster:
+    server: https://10.0.0.2
+users:
+- name: gke-user
+  user:
+    token: abc123
+`
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Args            []string
+		Namespace       string
+		Manifest        string
+		Kubeconfig      string
+		ExpectedErr     []string
+		ExpectedStrings []string
+		ExpectedStderr  []string
+	}{
+		"no space": {
+			Args:        []string{"gitlab"},
+			ExpectedErr: []string{utils.EmptyNamespaceError},
+		},
+		"app from manifest": {
+			Args:       []string{"github-actions"},
+			Namespace:  "production",
+			Manifest:   "applications:\n- name: my-app\n",
+			Kubeconfig: gkeKubeconfig,
+			ExpectedStrings: []string{
+				`KF_APP: "my-app"`,
+				`KF_SPACE: "production"`,
+				"KF_MANIFEST:",
+				`cluster_name: "my-cluster"`,
+				`project_id: "my-project"`,
+			},
+		},
+		"multiple apps in manifest": {
+			Args:        []string{"gitlab"},
+			Namespace:   "production",
+			Manifest:    "applications:\n- name: a\n- name: b\n",
+			ExpectedErr: []string{"manifest.yml has 2 apps, use --app to pick one"},
+		},
+		"no manifest": {
+			Args:        []string{"gitlab"},
+			Namespace:   "production",
+			ExpectedErr: []string{"no manifest found at", "use --app to set the app"},
+		},
+		"app flag without gke cluster": {
+			Args:      []string{"cloud-build", "--app", "other-app"},
+			Namespace: "production",
+			ExpectedStrings: []string{
+				"- KF_APP=other-app",
+				`get-credentials "CLUSTER_NAME" --location "CLUSTER_LOCATION" --project "PROJECT_ID"`,
+			},
+			ExpectedStderr: []string{
+				"Couldn't detect the GKE cluster from the kubeconfig",
+			},
+		},
+		"unknown provider": {
+			Args:        []string{"jenkins", "--app", "my-app"},
+			Namespace:   "production",
+			ExpectedErr: []string{`unknown CI provider "jenkins"`},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "ci-generate")
+			testutil.AssertNil(t, "err", err)
+			defer os.RemoveAll(dir)
+
+			if tc.Manifest != "" {
+				testutil.AssertNil(t, "err", ioutil.WriteFile(filepath.Join(dir, "manifest.yml"), []byte(tc.Manifest), 0644))
+			}
+
+			kubeconfig := filepath.Join(dir, "kubeconfig")
+			testutil.AssertNil(t, "err", ioutil.WriteFile(kubeconfig, []byte(tc.Kubeconfig), 0600))
+
+			p := &config.KfParams{
+				Namespace:   tc.Namespace,
+				KubeCfgFile: kubeconfig,
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+			cmd := ci.NewGenerateCommand(p)
+			cmd.SetOut(stdout)
+			cmd.SetErr(stderr)
+			cmd.SetArgs(append(tc.Args, "--manifest", filepath.Join(dir, "manifest.yml")))
+
+			err = cmd.Execute()
+			if tc.ExpectedErr != nil {
+				testutil.AssertErrorContainsAll(t, err, tc.ExpectedErr)
+				return
+			}
+
+			testutil.AssertNil(t, "err", err)
+
+			testutil.AssertContainsAll(t, stdout.String(), tc.ExpectedStrings)
+			testutil.AssertContainsAll(t, stderr.String(), tc.ExpectedStderr)
+		})
+	}
+}
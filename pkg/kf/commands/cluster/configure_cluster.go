@@ -0,0 +1,46 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"github.com/google/kf/pkg/kf/clusterdefaults"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigureClusterCommand creates a command with sub-commands to inspect
+// the configuration shared by every space on the cluster.
+func NewConfigureClusterCommand(p *config.KfParams, client clusterdefaults.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "configure-cluster [subcommand]",
+		Aliases: []string{"config-cluster"},
+		Short:   "Show configuration shared by every space on the cluster",
+		Long: `The configure-cluster sub-command shows the defaults the cluster
+		applies to spaces that don't override them.
+
+		The defaults are cached locally so commands that only need them for
+		display don't have to contact the cluster.
+		`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newGetCommand(p, client),
+	)
+
+	return cmd
+}
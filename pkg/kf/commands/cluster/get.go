@@ -0,0 +1,77 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/kf/pkg/kf/clusterdefaults"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/describe"
+	"github.com/spf13/cobra"
+)
+
+func newGetCommand(p *config.KfParams, client clusterdefaults.Client) *cobra.Command {
+	var refresh bool
+
+	cmd := &cobra.Command{
+		Use:   "get",
+		Short: "Show the defaults the cluster applies to spaces",
+		Long: `Show the defaults the cluster applies to spaces.
+
+		The defaults are read from a local cache if it's fresh. Use --refresh
+		to read them from the cluster and update the cache.
+		`,
+		Example: `
+		kf configure-cluster get
+		kf configure-cluster get --refresh
+		`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			get := client.Get
+			if refresh {
+				get = client.Refresh
+			}
+
+			defaults, err := get()
+			if err != nil {
+				return err
+			}
+
+			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+				fmt.Fprintf(w, "Domain:\t%s\n", defaults.Domain)
+				if p.Namespace != "" {
+					fmt.Fprintf(w, "Space Domain:\t%s\n", defaults.SpaceDomain(p.Namespace))
+				}
+				fmt.Fprintf(w, "Buildpack Builder:\t%s\n", defaults.BuilderImage)
+				fmt.Fprintf(w, "Container Registry:\t%s\n", defaults.ContainerRegistry)
+			})
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&refresh,
+		"refresh",
+		false,
+		"Read the defaults from the cluster instead of the local cache.",
+	)
+
+	return cmd
+}
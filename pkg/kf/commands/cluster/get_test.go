@@ -0,0 +1,101 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/kf/clusterdefaults"
+	"github.com/google/kf/pkg/kf/clusterdefaults/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestNewGetCommand(t *testing.T) {
+	defaults := &clusterdefaults.Defaults{
+		Domain:            "apps.example.com",
+		BuilderImage:      "gcr.io/my-builder",
+		ContainerRegistry: "gcr.io/my-project",
+	}
+
+	cases := map[string]struct {
+		namespace       string
+		args            []string
+		setup           func(t *testing.T, fakeClient *fake.FakeClient)
+		expectedStrings []string
+		expectedErr     error
+	}{
+		"uses cached defaults": {
+			setup: func(t *testing.T, fakeClient *fake.FakeClient) {
+				fakeClient.EXPECT().Get().Return(defaults, nil)
+			},
+			expectedStrings: []string{"apps.example.com", "gcr.io/my-builder", "gcr.io/my-project"},
+		},
+		"refresh": {
+			args: []string{"--refresh"},
+			setup: func(t *testing.T, fakeClient *fake.FakeClient) {
+				fakeClient.EXPECT().Refresh().Return(defaults, nil)
+			},
+			expectedStrings: []string{"apps.example.com", "gcr.io/my-builder"},
+		},
+		"shows the space domain": {
+			namespace: "my-space",
+			setup: func(t *testing.T, fakeClient *fake.FakeClient) {
+				fakeClient.EXPECT().Get().Return(defaults, nil)
+			},
+			expectedStrings: []string{"Space Domain:", "my-space.apps.example.com"},
+		},
+		"client error": {
+			setup: func(t *testing.T, fakeClient *fake.FakeClient) {
+				fakeClient.EXPECT().Get().Return(nil, errors.New("some-error"))
+			},
+			expectedErr: errors.New("some-error"),
+		},
+		"too many args": {
+			args:        []string{"extra"},
+			expectedErr: errors.New("accepts 0 arg(s), received 1"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			fakeClient := fake.NewFakeClient(ctrl)
+			if tc.setup != nil {
+				tc.setup(t, fakeClient)
+			}
+
+			buf := &bytes.Buffer{}
+			p := &config.KfParams{Namespace: tc.namespace}
+
+			cmd := NewConfigureClusterCommand(p, fakeClient)
+			cmd.SetOutput(buf)
+			cmd.SetArgs(append([]string{"get"}, tc.args...))
+
+			gotErr := cmd.Execute()
+			if tc.expectedErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.expectedErr, gotErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buf.String(), tc.expectedStrings)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	// DefaultCacheRefreshInterval is how often the completion daemon lists
+	// names from the cluster.
+	DefaultCacheRefreshInterval = 30 * time.Second
+
+	// cacheMaxAge is how old cached names can be before they're ignored. It
+	// allows for a few slow refreshes so completion doesn't fall back to the
+	// cluster while the daemon is still running.
+	cacheMaxAge = 3 * DefaultCacheRefreshInterval
+
+	// clusterCacheKey is the directory cluster scoped types are cached in.
+	clusterCacheKey = "_cluster"
+)
+
+// nameCacheDir returns the directory completion names are cached in. An empty
+// path disables the cache.
+var nameCacheDir = func() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "kf", "completion")
+}
+
+// cachePath returns the file names of the type in the namespace are cached
+// in.
+func cachePath(dir, namespace, k8sType string) string {
+	if _, ok := globalTypes[k8sType]; ok || namespace == "" {
+		namespace = clusterCacheKey
+	}
+
+	return filepath.Join(dir, namespace, k8sType)
+}
+
+// readCachedNames returns the cached names of the type in the namespace if
+// they were written within maxAge.
+func readCachedNames(dir, namespace, k8sType string, maxAge time.Duration) ([]string, bool) {
+	if dir == "" {
+		return nil, false
+	}
+
+	path := cachePath(dir, namespace, k8sType)
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > maxAge {
+		return nil, false
+	}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	return strings.Fields(string(contents)), true
+}
+
+// writeCachedNames replaces the cached names of the type in the namespace.
+// The file is replaced atomically so completion never reads a partial list.
+func writeCachedNames(dir, namespace, k8sType string, names []string) error {
+	path := cachePath(dir, namespace, k8sType)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(path), k8sType+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := fmt.Fprintln(tmp, strings.Join(names, " ")); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// RunCacheDaemon keeps the names of every completion type warm in the cache
+// until the context is cancelled. The namespace is looked up before each
+// refresh so the daemon follows the targeted space.
+//
+// Failures to list a type are reported to w and retried on the next refresh.
+func RunCacheDaemon(ctx context.Context, w io.Writer, client dynamic.Interface, namespace func() string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("refresh interval must be greater than 0, got %s", interval)
+	}
+
+	dir := nameCacheDir()
+	if dir == "" {
+		return errors.New("couldn't find a directory to cache names in")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		refreshCache(w, dir, client, namespace())
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// refreshCache lists and caches the names of every completion type in the
+// namespace.
+func refreshCache(w io.Writer, dir string, client dynamic.Interface, namespace string) {
+	for _, k8sType := range KnownGenericTypes() {
+		resources, err := getResourceInterface(client, k8sType, namespace)
+		if err != nil {
+			fmt.Fprintf(w, "couldn't refresh %s: %v\n", k8sType, err)
+			continue
+		}
+
+		ul, err := resources.List(metav1.ListOptions{})
+		if err != nil {
+			fmt.Fprintf(w, "couldn't refresh %s: %v\n", k8sType, err)
+			continue
+		}
+
+		if err := writeCachedNames(dir, namespace, k8sType, sortedNames(ul)); err != nil {
+			fmt.Fprintf(w, "couldn't cache %s: %v\n", k8sType, err)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package completion
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestCachedNames(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "completion-cache")
+	testutil.AssertNil(t, "TempDir error", err)
+	defer os.RemoveAll(dir)
+
+	testutil.AssertNil(t, "write error", writeCachedNames(dir, "my-space", AppCompletion, []string{"app-a", "app-z"}))
+
+	names, ok := readCachedNames(dir, "my-space", AppCompletion, time.Minute)
+	testutil.AssertEqual(t, "found", true, ok)
+	testutil.AssertEqual(t, "names", []string{"app-a", "app-z"}, names)
+
+	_, ok = readCachedNames(dir, "other-space", AppCompletion, time.Minute)
+	testutil.AssertEqual(t, "found in other space", false, ok)
+
+	stale := time.Now().Add(-time.Hour)
+	path := cachePath(dir, "my-space", AppCompletion)
+	testutil.AssertNil(t, "Chtimes error", os.Chtimes(path, stale, stale))
+
+	_, ok = readCachedNames(dir, "my-space", AppCompletion, time.Minute)
+	testutil.AssertEqual(t, "found stale", false, ok)
+
+	_, ok = readCachedNames("", "my-space", AppCompletion, time.Minute)
+	testutil.AssertEqual(t, "found without dir", false, ok)
+}
+
+func TestCachePath(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		namespace string
+		k8sType   string
+		want      string
+	}{
+		"namespaced type": {
+			namespace: "my-space",
+			k8sType:   AppCompletion,
+			want:      filepath.Join("cache", "my-space", "apps"),
+		},
+		"cluster type ignores namespace": {
+			namespace: "my-space",
+			k8sType:   SpaceCompletion,
+			want:      filepath.Join("cache", clusterCacheKey, "spaces"),
+		},
+		"no namespace": {
+			k8sType: AppCompletion,
+			want:    filepath.Join("cache", clusterCacheKey, "apps"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "path", tc.want, cachePath("cache", tc.namespace, tc.k8sType))
+		})
+	}
+}
+
+func TestRunCacheDaemon_badInterval(t *testing.T) {
+	t.Parallel()
+
+	err := RunCacheDaemon(context.Background(), ioutil.Discard, nil, func() string { return "my-space" }, 0)
+	testutil.AssertErrorsEqual(t, errors.New("refresh interval must be greater than 0, got 0s"), err)
+}
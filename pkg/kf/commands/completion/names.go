@@ -38,7 +38,10 @@ func NewNamesCommand(p *config.KfParams, client dynamic.Interface) *cobra.Comman
 		Long: `The names command gets a list of the objects and prints the names in
 		alphabetical order.
 
-		If the type is namespaced, the objects in the targeted space are printed.`,
+		If the type is namespaced, the objects in the targeted space are printed.
+
+		Names kept warm by kf completion --daemon are used instead of querying
+		the cluster while they're fresh.`,
 		ValidArgs: KnownGenericTypes(),
 		Args:      cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -48,6 +51,11 @@ func NewNamesCommand(p *config.KfParams, client dynamic.Interface) *cobra.Comman
 
 			k8sType := args[0]
 
+			if names, ok := readCachedNames(nameCacheDir(), p.Namespace, k8sType, cacheMaxAge); ok {
+				fmt.Fprintln(cmd.OutOrStdout(), strings.Join(names, " "))
+				return nil
+			}
+
 			client, err := getResourceInterface(client, k8sType, p.Namespace)
 			if err != nil {
 				return err
@@ -68,6 +76,12 @@ func NewNamesCommand(p *config.KfParams, client dynamic.Interface) *cobra.Comman
 // PrintNames prints the names of objects in the given list in alphabetical
 // order.
 func PrintNames(w io.Writer, ul *unstructured.UnstructuredList) {
+	fmt.Fprintln(w, strings.Join(sortedNames(ul), " "))
+}
+
+// sortedNames returns the names of objects in the given list in alphabetical
+// order.
+func sortedNames(ul *unstructured.UnstructuredList) []string {
 	var names []string
 	for _, li := range ul.Items {
 		names = append(names, li.GetName())
@@ -75,5 +89,5 @@ func PrintNames(w io.Writer, ul *unstructured.UnstructuredList) {
 
 	sort.Strings(names)
 
-	fmt.Fprintln(w, strings.Join(names, " "))
+	return names
 }
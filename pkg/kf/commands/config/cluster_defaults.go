@@ -0,0 +1,48 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/kf/pkg/kf/clusterdefaults"
+)
+
+// clusterDefaultsCacheTTL is how long cached cluster defaults are trusted.
+// Defaults rarely change so they're kept longer than discovery data.
+const clusterDefaultsCacheTTL = 24 * time.Hour
+
+// GetClusterDefaultsClient gets a client for the cluster's defaults that
+// caches them on disk per API server so informational commands don't need to
+// contact the cluster to resolve them.
+func GetClusterDefaultsClient(p *KfParams) clusterdefaults.Client {
+	client := clusterdefaults.NewClient(GetKubernetes(p).CoreV1())
+
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return client
+	}
+
+	cachePath := filepath.Join(
+		userCacheDir,
+		"kf",
+		"cluster-defaults",
+		cacheKey(getRestConfig(p).Host)+".json",
+	)
+
+	return clusterdefaults.NewCachedClient(client, cachePath, clusterDefaultsCacheTTL)
+}
@@ -16,17 +16,23 @@ package config
 
 import (
 	"context"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
+	"sync"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	kf "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
 	servicecatalogclient "github.com/google/kf/pkg/client/servicecatalog/clientset/versioned"
+	"github.com/google/kf/pkg/kf/clusterdefaults"
 	"github.com/google/kf/pkg/kf/marketplace"
 	build "github.com/google/kf/third_party/knative-build/pkg/client/clientset/versioned/typed/build/v1alpha1"
 	"github.com/imdario/mergo"
@@ -59,6 +65,27 @@ type KfParams struct {
 	// LogHTTP enables HTTP tracing for all Kubernetes calls.
 	LogHTTP bool `json:"logHTTP"`
 
+	// Impersonate is the user to act as for all Kubernetes calls.
+	// This field isn't serialized when the config is saved.
+	Impersonate string `json:"-"`
+
+	// ImpersonateGroups are the groups to act as for all Kubernetes calls.
+	// This field isn't serialized when the config is saved.
+	ImpersonateGroups []string `json:"-"`
+
+	// ReadOnly blocks all requests that could change the cluster.
+	// This field isn't serialized when the config is saved.
+	ReadOnly bool `json:"-"`
+
+	// Machine emits output as line-delimited JSON events for pipelines.
+	// This field isn't serialized when the config is saved.
+	Machine bool `json:"-"`
+
+	// ClusterDefaults holds the cluster's defaults, if they've been resolved,
+	// so the default space matches what the cluster would create.
+	// This field isn't serialized when the config is saved.
+	ClusterDefaults *clusterdefaults.Defaults `json:"-"`
+
 	// TargetSpace caches the space specified by Namespace to prevent it from
 	// being computed multiple times.
 	// Prefer using GetSpaceOrDefault instead of accessing this value directly.
@@ -75,6 +102,14 @@ func (p *KfParams) GetTargetSpaceOrDefault() (*v1alpha1.Space, error) {
 	}
 
 	res, err := GetKfClient(p).Spaces().Get(p.Namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) && p.ClusterDefaults == nil {
+		// The defaults are usually cached so this doesn't cost a round-trip,
+		// the built-in defaults are used if they can't be resolved.
+		if defaults, err := GetClusterDefaultsClient(p).Get(); err == nil {
+			p.ClusterDefaults = defaults
+		}
+	}
+
 	return p.cacheSpace(res, err)
 }
 
@@ -94,9 +129,20 @@ func (p *KfParams) cacheSpace(space *v1alpha1.Space, err error) (*v1alpha1.Space
 }
 
 // SetTargetSpaceToDefault sets TargetSpace to the default, overwriting
-// any existing values.
+// any existing values. ClusterDefaults are used if they've been resolved.
 func (p *KfParams) SetTargetSpaceToDefault() {
 	out := &v1alpha1.Space{}
+	if defaults := p.ClusterDefaults; defaults != nil {
+		// Record the build defaults the same way the controller does for
+		// Spaces that don't set their own.
+		out.Status.PropagateBuildpackBuildDefaults(out.Spec.BuildpackBuild, v1alpha1.SpaceStatusBuildpackBuild{
+			BuilderImage:      defaults.BuilderImage,
+			ContainerRegistry: defaults.ContainerRegistry,
+		})
+		out.Spec.Execution.Domains = []v1alpha1.SpaceDomain{
+			{Domain: defaults.SpaceDomain(p.Namespace), Default: true},
+		}
+	}
 	out.SetDefaults(context.Background())
 	p.TargetSpace = out
 }
@@ -265,12 +311,93 @@ func GetSvcatApp(p *KfParams) marketplace.SClientFactory {
 	}
 }
 
+// sharedRestConfig is the rest.Config shared by the clients of a KfParams
+// and the kubeconfig it was loaded from. The kubeconfig is kept because it can
+// change once flags and the config file are loaded.
+type sharedRestConfig struct {
+	kubeconfig string
+	config     *rest.Config
+}
+
+var (
+	restConfigsMu sync.Mutex
+	restConfigs   = map[*KfParams]sharedRestConfig{}
+)
+
+// getRestConfig gets a copy of the rest.Config shared by every client created
+// for p. The copies use the same transport so connections, including
+// multiplexed HTTP/2 connections to the API server, are reused across clients
+// rather than each client loading the kubeconfig and authenticating on its
+// own. Only the config for the current kubeconfig is kept for each p.
 func getRestConfig(p *KfParams) *rest.Config {
+	restConfigsMu.Lock()
+	defer restConfigsMu.Unlock()
+
+	shared, ok := restConfigs[p]
+	if !ok || shared.kubeconfig != p.KubeCfgFile {
+		shared = sharedRestConfig{
+			kubeconfig: p.KubeCfgFile,
+			config:     shareTransport(loadRestConfig(p)),
+		}
+		restConfigs[p] = shared
+	}
+
+	return rest.CopyConfig(shared.config)
+}
+
+// shareTransport builds the transport for config once, including
+// authentication and Kf's wrappers, and returns a config that uses it
+// directly.
+func shareTransport(config *rest.Config) *rest.Config {
+	rt, err := rest.TransportFor(config)
+	if err != nil {
+		// Leave the config as is so the error surfaces when a client uses it.
+		return config
+	}
+
+	shared := rest.AnonymousClientConfig(config)
+	shared.TLSClientConfig = rest.TLSClientConfig{}
+	shared.Transport = rt
+	shared.WrapTransport = nil
+	shared.Dial = nil
+	return shared
+}
+
+// loadRestConfig reads the configuration to connect to the cluster with.
+func loadRestConfig(p *KfParams) *rest.Config {
 	config, err := rest.InClusterConfig()
 	if err == nil {
+		impersonating := ImpersonatingRoundTripperWrapper(p)
+		readOnly := ReadOnlyRoundTripperWrapper(p)
+		config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+			return readOnly(impersonating(rt))
+		}
 		return config
 	}
 
+	restCfg, err := getClientConfig(p).ClientConfig()
+	if err != nil {
+		return &rest.Config{
+			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+				return nil, fmt.Errorf("failed to build clientcmd: %s", err)
+			},
+		}
+	}
+
+	logging := LoggingRoundTripperWrapper(p)
+	impersonating := ImpersonatingRoundTripperWrapper(p)
+	readOnly := ReadOnlyRoundTripperWrapper(p)
+	restCfg.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		// Impersonation headers are added first so they show up in the logs,
+		// blocked requests are never sent or logged.
+		return readOnly(impersonating(logging(rt)))
+	}
+
+	return restCfg
+}
+
+// getClientConfig gets the kubeconfig Kf connects to the cluster with.
+func getClientConfig(p *KfParams) clientcmd.ClientConfig {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
 	if p.KubeCfgFile != "" {
 		fileList := filepath.SplitList(p.KubeCfgFile)
@@ -281,19 +408,128 @@ func getRestConfig(p *KfParams) *rest.Config {
 		}
 	}
 
-	clientCfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
-	restCfg, err := clientCfg.ClientConfig()
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{})
+}
+
+// Identity describes who Kf connects to the cluster as.
+type Identity struct {
+	// Context is the kubeconfig context in use.
+	Context string
+	// Cluster is the name of the cluster in the kubeconfig.
+	Cluster string
+	// Server is the address of the Kubernetes API server.
+	Server string
+	// User is the name of the user in the kubeconfig, or the subject of the
+	// client certificate if one is used.
+	User string
+	// Groups are the organizations in the client certificate, if one is
+	// used.
+	Groups []string
+	// AuthMethod describes how the user authenticates to the cluster.
+	AuthMethod string
+	// ImpersonatedUser is the user requests are sent as, if any.
+	ImpersonatedUser string
+	// ImpersonatedGroups are the groups requests are sent as, if any.
+	ImpersonatedGroups []string
+}
+
+// GetIdentity reads the identity Kf connects to the cluster as from the
+// kubeconfig. When running in a cluster the Pod's service account is used.
+func GetIdentity(p *KfParams) (*Identity, error) {
+	identity, err := getIdentity(p)
 	if err != nil {
-		return &rest.Config{
-			Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-				return nil, fmt.Errorf("failed to build clientcmd: %s", err)
-			},
+		return nil, err
+	}
+
+	identity.ImpersonatedUser = p.Impersonate
+	identity.ImpersonatedGroups = p.ImpersonateGroups
+	return identity, nil
+}
+
+func getIdentity(p *KfParams) (*Identity, error) {
+	if cfg, err := rest.InClusterConfig(); err == nil {
+		return &Identity{
+			Server:     cfg.Host,
+			User:       "in-cluster service account",
+			AuthMethod: "service account token",
+		}, nil
+	}
+
+	raw, err := getClientConfig(p).RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read kubeconfig: %s", err)
+	}
+
+	kubeContext, ok := raw.Contexts[raw.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no current context")
+	}
+
+	identity := &Identity{
+		Context: raw.CurrentContext,
+		Cluster: kubeContext.Cluster,
+		User:    kubeContext.AuthInfo,
+	}
+
+	if cluster, ok := raw.Clusters[kubeContext.Cluster]; ok {
+		identity.Server = cluster.Server
+	}
+
+	authInfo, ok := raw.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		identity.AuthMethod = "none"
+		return identity, nil
+	}
+
+	switch {
+	case authInfo.AuthProvider != nil:
+		identity.AuthMethod = fmt.Sprintf("auth provider (%s)", authInfo.AuthProvider.Name)
+
+	case authInfo.Exec != nil:
+		identity.AuthMethod = fmt.Sprintf("exec (%s)", authInfo.Exec.Command)
+
+	case authInfo.Token != "" || authInfo.TokenFile != "":
+		identity.AuthMethod = "bearer token"
+
+	case authInfo.ClientCertificate != "" || len(authInfo.ClientCertificateData) > 0:
+		identity.AuthMethod = "client certificate"
+
+		certData := authInfo.ClientCertificateData
+		if len(certData) == 0 {
+			if certData, err = ioutil.ReadFile(authInfo.ClientCertificate); err != nil {
+				return nil, fmt.Errorf("failed to read client certificate: %s", err)
+			}
+		}
+
+		if subject, err := certificateSubject(certData); err == nil {
+			identity.User = subject.CommonName
+			identity.Groups = subject.Organization
 		}
+
+	case authInfo.Username != "":
+		identity.User = authInfo.Username
+		identity.AuthMethod = "basic auth"
+
+	default:
+		identity.AuthMethod = "none"
 	}
 
-	restCfg.WrapTransport = LoggingRoundTripperWrapper(p)
+	return identity, nil
+}
 
-	return restCfg
+// certificateSubject parses the subject out of a PEM encoded certificate.
+func certificateSubject(certData []byte) (*pkix.Name, error) {
+	block, _ := pem.Decode(certData)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cert.Subject, nil
 }
 
 func initKubeConfig() KfParams {
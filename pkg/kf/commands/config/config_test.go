@@ -19,6 +19,8 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
@@ -26,6 +28,7 @@ import (
 	"testing"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/clusterdefaults"
 	"github.com/google/kf/pkg/kf/testutil"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/client-go/util/homedir"
@@ -173,6 +176,27 @@ func TestLoad(t *testing.T) {
 
 }
 
+func TestWrite_transientFields(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kfcfg")
+	testutil.AssertNil(t, "err", err)
+	defer os.RemoveAll(dir)
+
+	cfgPath := filepath.Join(dir, "config.yml")
+	err = Write(cfgPath, &KfParams{
+		Namespace:   "my-space",
+		Impersonate: "jane@example.com",
+		ReadOnly:    true,
+		Machine:     true,
+	})
+	testutil.AssertNil(t, "err", err)
+
+	// Flags like --readonly only apply to the command they're passed to, so
+	// kf target saving the config mustn't make them stick.
+	actual, err := NewKfParamsFromFile(cfgPath)
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "config", &KfParams{Namespace: "my-space"}, actual)
+}
+
 func ExampleKfParams_GetTargetSpaceOrDefault() {
 	target := &v1alpha1.Space{}
 	target.Name = "cached-target"
@@ -201,6 +225,26 @@ func ExampleKfParams_SetTargetSpaceToDefault() {
 	// Output: Set to default: true
 }
 
+func ExampleKfParams_SetTargetSpaceToDefault_clusterDefaults() {
+	p := &KfParams{
+		Namespace: "my-space",
+		ClusterDefaults: &clusterdefaults.Defaults{
+			Domain:            "apps.example.com",
+			BuilderImage:      "gcr.io/my-builder",
+			ContainerRegistry: "gcr.io/my-project",
+		},
+	}
+	p.SetTargetSpaceToDefault()
+
+	fmt.Println("Domain:", p.TargetSpace.Spec.Execution.Domains[0].Domain)
+	fmt.Println("Builder:", p.TargetSpace.EffectiveBuilderImage())
+	fmt.Println("Registry:", p.TargetSpace.EffectiveContainerRegistry())
+
+	// Output: Domain: my-space.apps.example.com
+	// Builder: gcr.io/my-builder
+	// Registry: gcr.io/my-project
+}
+
 func TestKfParams_cacheSpace(t *testing.T) {
 	goodSpace := &v1alpha1.Space{}
 	goodSpace.Name = "test-space"
@@ -243,3 +287,184 @@ func TestKfParams_cacheSpace(t *testing.T) {
 		})
 	}
 }
+
+func TestGetIdentity(t *testing.T) {
+	cases := map[string]struct {
+		kubeconfig        string
+		impersonate       string
+		impersonateGroups []string
+		wantIdentity      *Identity
+		wantErr           error
+	}{
+		"bearer token": {
+			kubeconfig: `
+apiVersion: v1
+kind: Config
+current-context: dev
+contexts:
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: alex
+clusters:
+- name: dev-cluster
+  cluster:
+    server: https://10.0.0.1
+users:
+- name: alex
+  user:
+    token: abc123
+`,
+			wantIdentity: &Identity{
+				Context:    "dev",
+				Cluster:    "dev-cluster",
+				Server:     "https://10.0.0.1",
+				User:       "alex",
+				AuthMethod: "bearer token",
+			},
+		},
+		"auth provider": {
+			kubeconfig: `
+apiVersion: v1
+kind: Config
+current-context: gke
+contexts:
+- name: gke
+  context:
+    cluster: gke-cluster
+    user: gke-user
+clusters:
+- name: gke-cluster
+  cluster:
+    server: https://10.0.0.2
+users:
+- name: gke-user
+  user:
+    auth-provider:
+      name: gcp
+`,
+			wantIdentity: &Identity{
+				Context:    "gke",
+				Cluster:    "gke-cluster",
+				Server:     "https://10.0.0.2",
+				User:       "gke-user",
+				AuthMethod: "auth provider (gcp)",
+			},
+		},
+		"impersonation": {
+			kubeconfig: `
+apiVersion: v1
+kind: Config
+current-context: dev
+contexts:
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: admin
+clusters:
+- name: dev-cluster
+  cluster:
+    server: https://10.0.0.1
+users:
+- name: admin
+  user:
+    token: abc123
+`,
+			impersonate:       "jane@example.com",
+			impersonateGroups: []string{"devs"},
+			wantIdentity: &Identity{
+				Context:            "dev",
+				Cluster:            "dev-cluster",
+				Server:             "https://10.0.0.1",
+				User:               "admin",
+				AuthMethod:         "bearer token",
+				ImpersonatedUser:   "jane@example.com",
+				ImpersonatedGroups: []string{"devs"},
+			},
+		},
+		"no current context": {
+			kubeconfig: `
+apiVersion: v1
+kind: Config
+`,
+			wantErr: errors.New("kubeconfig has no current context"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "kubeconfig")
+			testutil.AssertNil(t, "err", err)
+			defer os.RemoveAll(dir)
+
+			kubeconfig := filepath.Join(dir, "config")
+			testutil.AssertNil(t, "err", ioutil.WriteFile(kubeconfig, []byte(tc.kubeconfig), 0600))
+
+			identity, err := GetIdentity(&KfParams{
+				KubeCfgFile:       kubeconfig,
+				Impersonate:       tc.impersonate,
+				ImpersonateGroups: tc.impersonateGroups,
+			})
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "identity", tc.wantIdentity, identity)
+		})
+	}
+}
+
+func TestGetRestConfig_sharedTransport(t *testing.T) {
+	var gotAuth string
+	// Credentials from the kubeconfig are only used for TLS connections.
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "kubeconfig")
+	testutil.AssertNil(t, "err", err)
+	defer os.RemoveAll(dir)
+
+	kubeconfig := filepath.Join(dir, "config")
+	testutil.AssertNil(t, "err", ioutil.WriteFile(kubeconfig, []byte(`
+apiVersion: v1
+kind: Config
+current-context: dev
+contexts:
+- name: dev
+  context:
+    cluster: dev-cluster
+    user: alex
+clusters:
+- name: dev-cluster
+  cluster:
+    server: `+server.URL+`
+    insecure-skip-tls-verify: true
+users:
+- name: alex
+  user:
+    token: abc123
+`), 0600))
+
+	p := &KfParams{KubeCfgFile: kubeconfig}
+	first := getRestConfig(p)
+	second := getRestConfig(p)
+
+	testutil.AssertEqual(t, "host", server.URL, first.Host)
+	testutil.AssertEqual(t, "same transport", true, first.Transport == second.Transport)
+	testutil.AssertEqual(t, "bearer token", "", first.BearerToken)
+
+	// Credentials are applied by the shared transport.
+	resp, err := (&http.Client{Transport: first.Transport}).Get(server.URL)
+	testutil.AssertNil(t, "err", err)
+	resp.Body.Close()
+	testutil.AssertEqual(t, "Authorization", "Bearer abc123", gotAuth)
+
+	// Changing the kubeconfig gets a new transport.
+	p.KubeCfgFile = filepath.Join(dir, "other-config")
+	third := getRestConfig(p)
+	testutil.AssertEqual(t, "same transport", false, first.Transport == third.Transport)
+
+	// Only the config for the current kubeconfig is kept.
+	restConfigsMu.Lock()
+	testutil.AssertEqual(t, "kubeconfig", p.KubeCfgFile, restConfigs[p].kubeconfig)
+	restConfigsMu.Unlock()
+}
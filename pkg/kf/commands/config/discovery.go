@@ -0,0 +1,158 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached"
+)
+
+// discoveryCacheTTL is how long cached discovery data is trusted. The cache is
+// also dropped whenever the API server's version changes.
+const discoveryCacheTTL = 6 * time.Hour
+
+// unsafeCacheKeyChars matches characters that shouldn't be in a cache
+// directory name.
+var unsafeCacheKeyChars = regexp.MustCompile(`[^\w.-]`)
+
+// GetDiscoveryClient gets a discovery client that caches API groups and
+// resources on disk so commands don't pay for discovery round-trips on every
+// invocation. OpenAPI documents, which hold CRD schemas, are cached by HTTP
+// ETag alongside them.
+//
+// The cache is kept per API server under the user's cache directory and is
+// invalidated when the server's version changes.
+func GetDiscoveryClient(p *KfParams) discovery.CachedDiscoveryInterface {
+	config := getRestConfig(p)
+
+	userCacheDir, err := os.UserCacheDir()
+	if err != nil {
+		// Without a place to store the cache, fall back to caching for the
+		// life of the command.
+		client, err := discovery.NewDiscoveryClientForConfig(config)
+		if err != nil {
+			log.Fatalf("failed to create a discovery client: %s", err)
+		}
+		return cached.NewMemCacheClient(client)
+	}
+
+	cacheDir := filepath.Join(userCacheDir, "kf", "discovery", cacheKey(config.Host))
+	client, err := discovery.NewCachedDiscoveryClientForConfig(
+		config,
+		filepath.Join(cacheDir, "resources"),
+		filepath.Join(cacheDir, "http"),
+		discoveryCacheTTL,
+	)
+	if err != nil {
+		log.Fatalf("failed to create a discovery client: %s", err)
+	}
+
+	return newVersionCheckedDiscovery(client, filepath.Join(cacheDir, "server-version"))
+}
+
+// cacheKey converts an API server address into a directory name.
+func cacheKey(host string) string {
+	key := unsafeCacheKeyChars.ReplaceAllString(host, "_")
+	if key == "" {
+		return "default"
+	}
+
+	return key
+}
+
+// versionCheckedDiscovery invalidates its cache the first time it's used if
+// the API server's version differs from the one the cache was built against.
+type versionCheckedDiscovery struct {
+	discovery.CachedDiscoveryInterface
+
+	versionPath string
+	checkOnce   sync.Once
+}
+
+var _ discovery.CachedDiscoveryInterface = (*versionCheckedDiscovery)(nil)
+
+func newVersionCheckedDiscovery(client discovery.CachedDiscoveryInterface, versionPath string) *versionCheckedDiscovery {
+	return &versionCheckedDiscovery{
+		CachedDiscoveryInterface: client,
+		versionPath:              versionPath,
+	}
+}
+
+// checkVersion compares the server's version with the cached one. Checking is
+// deferred until discovery data is needed so creating the client never
+// contacts the cluster.
+func (d *versionCheckedDiscovery) checkVersion() {
+	d.checkOnce.Do(func() {
+		info, err := d.CachedDiscoveryInterface.ServerVersion()
+		if err != nil {
+			// The cache can't be validated so it shouldn't be trusted.
+			d.Invalidate()
+			return
+		}
+
+		if cached, err := ioutil.ReadFile(d.versionPath); err == nil && string(cached) == info.GitVersion {
+			return
+		}
+
+		d.Invalidate()
+
+		// Writing the version is best effort, the worst case is the cache gets
+		// rebuilt next time.
+		if err := os.MkdirAll(filepath.Dir(d.versionPath), 0755); err == nil {
+			ioutil.WriteFile(d.versionPath, []byte(info.GitVersion), 0644)
+		}
+	})
+}
+
+// ServerGroups implements discovery.ServerGroupsInterface.
+func (d *versionCheckedDiscovery) ServerGroups() (*metav1.APIGroupList, error) {
+	d.checkVersion()
+	return d.CachedDiscoveryInterface.ServerGroups()
+}
+
+// ServerResourcesForGroupVersion implements
+// discovery.ServerResourcesInterface.
+func (d *versionCheckedDiscovery) ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error) {
+	d.checkVersion()
+	return d.CachedDiscoveryInterface.ServerResourcesForGroupVersion(groupVersion)
+}
+
+// ServerResources implements discovery.ServerResourcesInterface.
+func (d *versionCheckedDiscovery) ServerResources() ([]*metav1.APIResourceList, error) {
+	d.checkVersion()
+	return d.CachedDiscoveryInterface.ServerResources()
+}
+
+// ServerPreferredResources implements discovery.ServerResourcesInterface.
+func (d *versionCheckedDiscovery) ServerPreferredResources() ([]*metav1.APIResourceList, error) {
+	d.checkVersion()
+	return d.CachedDiscoveryInterface.ServerPreferredResources()
+}
+
+// ServerPreferredNamespacedResources implements
+// discovery.ServerResourcesInterface.
+func (d *versionCheckedDiscovery) ServerPreferredNamespacedResources() ([]*metav1.APIResourceList, error) {
+	d.checkVersion()
+	return d.CachedDiscoveryInterface.ServerPreferredNamespacedResources()
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+// fakeCachedDiscovery records invalidations of a fake discovery client.
+type fakeCachedDiscovery struct {
+	*fakediscovery.FakeDiscovery
+
+	invalidations int
+}
+
+func (f *fakeCachedDiscovery) Fresh() bool {
+	return true
+}
+
+func (f *fakeCachedDiscovery) Invalidate() {
+	f.invalidations++
+}
+
+func TestVersionCheckedDiscovery(t *testing.T) {
+	cases := map[string]struct {
+		cachedVersion     string
+		serverVersion     string
+		wantInvalidations int
+	}{
+		"no cached version": {
+			serverVersion:     "v1.14.3",
+			wantInvalidations: 1,
+		},
+		"same version": {
+			cachedVersion:     "v1.14.3",
+			serverVersion:     "v1.14.3",
+			wantInvalidations: 0,
+		},
+		"server upgraded": {
+			cachedVersion:     "v1.13.7",
+			serverVersion:     "v1.14.3",
+			wantInvalidations: 1,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "kf-discovery")
+			testutil.AssertNil(t, "TempDir err", err)
+			defer os.RemoveAll(dir)
+
+			versionPath := filepath.Join(dir, "cache", "server-version")
+			if tc.cachedVersion != "" {
+				testutil.AssertNil(t, "MkdirAll err", os.MkdirAll(filepath.Dir(versionPath), 0755))
+				testutil.AssertNil(t, "WriteFile err", ioutil.WriteFile(versionPath, []byte(tc.cachedVersion), 0644))
+			}
+
+			fake := &fakeCachedDiscovery{
+				FakeDiscovery: &fakediscovery.FakeDiscovery{
+					Fake:               &ktesting.Fake{},
+					FakedServerVersion: &version.Info{GitVersion: tc.serverVersion},
+				},
+			}
+			client := newVersionCheckedDiscovery(fake, versionPath)
+
+			// The version is only checked once no matter how many lookups
+			// are made.
+			client.ServerResourcesForGroupVersion("v1")
+			client.ServerResources()
+
+			testutil.AssertEqual(t, "invalidations", tc.wantInvalidations, fake.invalidations)
+
+			gotVersion, err := ioutil.ReadFile(versionPath)
+			testutil.AssertNil(t, "ReadFile err", err)
+			testutil.AssertEqual(t, "cached version", tc.serverVersion, string(gotVersion))
+		})
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	cases := map[string]struct {
+		host string
+		want string
+	}{
+		"https address": {
+			host: "https://35.1.2.3:443",
+			want: "https___35.1.2.3_443",
+		},
+		"path": {
+			host: "https://example.com/k8s/clusters/c-1",
+			want: "https___example.com_k8s_clusters_c-1",
+		},
+		"empty": {
+			host: "",
+			want: "default",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "key", tc.want, cacheKey(tc.host))
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"net/http"
+
+	"k8s.io/client-go/transport"
+)
+
+// ImpersonatingRoundTripperWrapper returns a WrapperFunc that adds
+// impersonation headers to requests if params.Impersonate is set.
+func ImpersonatingRoundTripperWrapper(params *KfParams) WrapperFunc {
+	return func(in http.RoundTripper) http.RoundTripper {
+		return &ImpersonatingRoundTripper{
+			params: params,
+			inner:  in,
+		}
+	}
+}
+
+// ImpersonatingRoundTripper sends requests as another user the same way
+// kubectl --as and --as-group do.
+//
+// The params are read for each request rather than when the client is built
+// because clients are created before flags are parsed.
+type ImpersonatingRoundTripper struct {
+	params *KfParams
+	inner  http.RoundTripper
+}
+
+var _ http.RoundTripper = (*ImpersonatingRoundTripper)(nil)
+
+// RoundTrip implements http.RoundTripper.
+func (t *ImpersonatingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if t.params.Impersonate == "" {
+		return t.inner.RoundTrip(r)
+	}
+
+	// RoundTrippers must not modify the request they're given.
+	reqCopy := *r
+	reqCopy.Header = http.Header{}
+	for k, v := range r.Header {
+		reqCopy.Header[k] = v
+	}
+
+	reqCopy.Header.Set(transport.ImpersonateUserHeader, t.params.Impersonate)
+	for _, group := range t.params.ImpersonateGroups {
+		reqCopy.Header.Add(transport.ImpersonateGroupHeader, group)
+	}
+
+	return t.inner.RoundTrip(&reqCopy)
+}
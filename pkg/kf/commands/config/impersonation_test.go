@@ -0,0 +1,67 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestImpersonatingRoundTripper_RoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		params          KfParams
+		expectedStrings []string
+		expectHeaders   bool
+	}{
+		"no impersonation": {
+			params: KfParams{},
+		},
+		"user": {
+			params:          KfParams{Impersonate: "jane@example.com"},
+			expectedStrings: []string{"Impersonate-User: jane@example.com"},
+			expectHeaders:   true,
+		},
+		"user and groups": {
+			params: KfParams{
+				Impersonate:       "jane@example.com",
+				ImpersonateGroups: []string{"devs", "ops"},
+			},
+			expectedStrings: []string{
+				"Impersonate-User: jane@example.com",
+				"Impersonate-Group: devs",
+				"Impersonate-Group: ops",
+			},
+			expectHeaders: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dt := &dummyTransport{}
+			transport := ImpersonatingRoundTripperWrapper(&tc.params)(dt)
+
+			req, _ := http.NewRequest("GET", "http://example.com", nil)
+			_, err := transport.RoundTrip(req)
+			testutil.AssertNil(t, "RoundTrip error", err)
+
+			testutil.AssertContainsAll(t, dt.requestDump, tc.expectedStrings)
+			testutil.AssertEqual(t, "sent impersonation headers", tc.expectHeaders, strings.Contains(dt.requestDump, "Impersonate-"))
+			testutil.AssertEqual(t, "original request headers", 0, len(req.Header))
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// readOnlyReviewResources are created with POST but only ask the API server
+// questions so they're allowed in read-only mode.
+var readOnlyReviewResources = []string{
+	"/selfsubjectaccessreviews",
+	"/selfsubjectrulesreviews",
+}
+
+// ReadOnlyRoundTripperWrapper returns a WrapperFunc that rejects mutating
+// requests if params.ReadOnly is set.
+func ReadOnlyRoundTripperWrapper(params *KfParams) WrapperFunc {
+	return func(in http.RoundTripper) http.RoundTripper {
+		return &ReadOnlyRoundTripper{
+			params: params,
+			inner:  in,
+		}
+	}
+}
+
+// ReadOnlyRoundTripper blocks every request that could change the cluster so
+// auditors can run Kf without risk of modifying anything.
+type ReadOnlyRoundTripper struct {
+	params *KfParams
+	inner  http.RoundTripper
+}
+
+var _ http.RoundTripper = (*ReadOnlyRoundTripper)(nil)
+
+// RoundTrip implements http.RoundTripper.
+func (t *ReadOnlyRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	if !t.params.ReadOnly || isReadOnlyRequest(r) {
+		return t.inner.RoundTrip(r)
+	}
+
+	return nil, fmt.Errorf("kf is in read-only mode, refusing to %s %s", r.Method, r.URL.Path)
+}
+
+func isReadOnlyRequest(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	case http.MethodPost:
+		for _, suffix := range readOnlyReviewResources {
+			if strings.HasSuffix(r.URL.Path, suffix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,88 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestReadOnlyRoundTripper_RoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		readOnly bool
+		method   string
+		url      string
+		wantErr  error
+	}{
+		"disabled allows writes": {
+			method: "POST",
+			url:    "http://example.com/apis/kf.dev/v1alpha1/namespaces/my-space/apps",
+		},
+		"get allowed": {
+			readOnly: true,
+			method:   "GET",
+			url:      "http://example.com/apis/kf.dev/v1alpha1/namespaces/my-space/apps",
+		},
+		"watch allowed": {
+			readOnly: true,
+			method:   "GET",
+			url:      "http://example.com/apis/kf.dev/v1alpha1/namespaces/my-space/apps?watch=true",
+		},
+		"access reviews allowed": {
+			readOnly: true,
+			method:   "POST",
+			url:      "http://example.com/apis/authorization.k8s.io/v1/selfsubjectaccessreviews",
+		},
+		"create blocked": {
+			readOnly: true,
+			method:   "POST",
+			url:      "http://example.com/apis/kf.dev/v1alpha1/namespaces/my-space/apps",
+			wantErr:  errors.New("kf is in read-only mode, refusing to POST /apis/kf.dev/v1alpha1/namespaces/my-space/apps"),
+		},
+		"update blocked": {
+			readOnly: true,
+			method:   "PUT",
+			url:      "http://example.com/apis/kf.dev/v1alpha1/namespaces/my-space/apps/my-app",
+			wantErr:  errors.New("kf is in read-only mode, refusing to PUT /apis/kf.dev/v1alpha1/namespaces/my-space/apps/my-app"),
+		},
+		"patch blocked": {
+			readOnly: true,
+			method:   "PATCH",
+			url:      "http://example.com/apis/kf.dev/v1alpha1/namespaces/my-space/apps/my-app",
+			wantErr:  errors.New("kf is in read-only mode, refusing to PATCH /apis/kf.dev/v1alpha1/namespaces/my-space/apps/my-app"),
+		},
+		"delete blocked": {
+			readOnly: true,
+			method:   "DELETE",
+			url:      "http://example.com/apis/kf.dev/v1alpha1/namespaces/my-space/apps/my-app",
+			wantErr:  errors.New("kf is in read-only mode, refusing to DELETE /apis/kf.dev/v1alpha1/namespaces/my-space/apps/my-app"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dt := &dummyTransport{}
+			transport := ReadOnlyRoundTripperWrapper(&KfParams{ReadOnly: tc.readOnly})(dt)
+
+			req, _ := http.NewRequest(tc.method, tc.url, nil)
+			_, err := transport.RoundTrip(req)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "request sent", tc.wantErr == nil, dt.requestDump != "")
+		})
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewGCPCommand creates a command that groups helpers for running kf on
+// Google Cloud Platform.
+func NewGCPCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gcp [subcommand]",
+		Short: "Integrate kf with Google Cloud Platform",
+		Long: `The gcp sub-command contains helpers that configure Google Cloud
+		Platform resources for kf. You MUST have gcloud and kubectl installed and
+		available on the path, and kubectl must target the cluster kf runs on.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		NewSetupBrokerCommand(),
+	)
+
+	return cmd
+}
@@ -0,0 +1,446 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	. "github.com/google/kf/pkg/kf/commands/install/util"
+	servicecatalogv1beta1 "github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/spf13/cobra"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	brokerName             = "gcp-service-broker"
+	brokerServiceAccount   = "kf-gcp-service-broker"
+	defaultBrokerNamespace = "gcp-service-broker"
+	defaultBrokerImage     = "gcr.io/gcp-service-broker/gcp-service-broker:v4.3.0"
+	brokerPort             = 8080
+	brokerUser             = "kf"
+
+	// brokerStorageSize is the size of the volume holding the broker's
+	// database of provisioned instances and bindings.
+	brokerStorageSize = "1Gi"
+
+	// credentialsChecksumAnnotation rolls the broker's pods when its
+	// password changes so they pick up the new one.
+	credentialsChecksumAnnotation = "kf.dev/credentials-checksum"
+)
+
+// brokerAPIs are the Google Cloud APIs the broker needs to provision
+// services.
+var brokerAPIs = []string{
+	"iam.googleapis.com",
+	"cloudresourcemanager.googleapis.com",
+	"sqladmin.googleapis.com",
+	"pubsub.googleapis.com",
+}
+
+// brokerRoles are granted to the broker's Google service account so it can
+// create instances and the service accounts used for bindings.
+var brokerRoles = []string{
+	"roles/cloudsql.admin",
+	"roles/pubsub.admin",
+	"roles/iam.serviceAccountAdmin",
+	"roles/iam.serviceAccountKeyAdmin",
+	"roles/resourcemanager.projectIamAdmin",
+}
+
+// NewSetupBrokerCommand creates a command that installs the GCP service
+// broker into the cluster and registers it with the service catalog.
+func NewSetupBrokerCommand() *cobra.Command {
+	var (
+		cfg     brokerConfig
+		verbose bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "setup-broker --project PROJECT_ID",
+		Short: "Install and register the GCP service broker",
+		Long: `
+			Installs the GCP service broker into the cluster and registers it with
+			the service catalog so Google Cloud services like Cloud SQL and Pub/Sub
+			can be created with kf create-service.
+
+			The broker runs as a Kubernetes service account that's mapped to a
+			Google service account using Workload Identity, so no service account
+			keys are created. Workload Identity MUST be enabled on the cluster.
+
+			The broker keeps the instances and bindings it has created in a
+			database on a PersistentVolumeClaim so they survive restarts. The
+			cluster needs a default StorageClass.
+
+			The command is safe to re-run, for example to upgrade the broker image.`,
+		Example: `  kf gcp setup-broker --project my-project`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if cfg.Project == "" {
+				return errors.New("--project must be set")
+			}
+
+			cmd.SilenceUsage = true
+			ctx := SetContextOutput(context.Background(), cmd.ErrOrStderr())
+			ctx = SetLogPrefix(ctx, "Setup GCP Service Broker")
+			ctx = SetVerbosity(ctx, verbose)
+
+			password, err := randomPassword()
+			if err != nil {
+				return err
+			}
+			cfg.Password = password
+
+			if err := setupBrokerIAM(ctx, cfg); err != nil {
+				return err
+			}
+
+			if err := applyBrokerManifest(ctx, cfg); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "The GCP service broker was registered as %s. Run 'kf marketplace' to see its services once it's ready.\n", brokerName)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&cfg.Project,
+		"project",
+		"",
+		"Google Cloud project the broker creates services in",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Namespace,
+		"namespace",
+		defaultBrokerNamespace,
+		"Kubernetes namespace to run the broker in",
+	)
+
+	cmd.Flags().StringVar(
+		&cfg.Image,
+		"image",
+		defaultBrokerImage,
+		"Container image of the broker",
+	)
+
+	cmd.Flags().BoolVarP(
+		&verbose,
+		"verbose",
+		"v",
+		false,
+		"Display the gcloud and kubectl commands",
+	)
+
+	return cmd
+}
+
+// brokerConfig holds the settings for a broker installation.
+type brokerConfig struct {
+	Project   string
+	Namespace string
+	Image     string
+	Password  string
+}
+
+// GoogleServiceAccount returns the email of the Google service account the
+// broker runs as.
+func (cfg brokerConfig) GoogleServiceAccount() string {
+	return fmt.Sprintf("%s@%s.iam.gserviceaccount.com", brokerServiceAccount, cfg.Project)
+}
+
+// WorkloadIdentityMember returns the IAM member of the broker's Kubernetes
+// service account.
+func (cfg brokerConfig) WorkloadIdentityMember() string {
+	return fmt.Sprintf("serviceAccount:%s.svc.id.goog[%s/%s]", cfg.Project, cfg.Namespace, brokerServiceAccount)
+}
+
+func randomPassword() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
+
+// setupBrokerIAM enables the APIs the broker needs and creates a Google
+// service account for it that the broker's Kubernetes service account can
+// act as.
+func setupBrokerIAM(ctx context.Context, cfg brokerConfig) error {
+	ctx = SetLogPrefix(ctx, "IAM")
+
+	Logf(ctx, "enabling service APIs. This may take a moment")
+	if _, err := gcloud(ctx, append([]string{"-q", "services", "enable", "--project", cfg.Project}, brokerAPIs...)...); err != nil {
+		return err
+	}
+
+	email := cfg.GoogleServiceAccount()
+	if _, err := gcloud(ctx, "iam", "service-accounts", "describe", email, "--project", cfg.Project); err != nil {
+		Logf(ctx, "creating service account %s", email)
+		if _, err := gcloud(
+			ctx,
+			"iam",
+			"service-accounts",
+			"create",
+			brokerServiceAccount,
+			"--display-name", "kf GCP service broker",
+			"--project", cfg.Project,
+		); err != nil {
+			return err
+		}
+	}
+
+	for _, role := range brokerRoles {
+		Logf(ctx, "granting %s to %s", role, email)
+		if _, err := gcloud(
+			ctx,
+			"projects",
+			"add-iam-policy-binding",
+			cfg.Project,
+			"--member", "serviceAccount:"+email,
+			"--role", role,
+		); err != nil {
+			return err
+		}
+	}
+
+	Logf(ctx, "allowing %s to act as %s", cfg.WorkloadIdentityMember(), email)
+	_, err := gcloud(
+		ctx,
+		"iam",
+		"service-accounts",
+		"add-iam-policy-binding",
+		email,
+		"--project", cfg.Project,
+		"--role", "roles/iam.workloadIdentityUser",
+		"--member", cfg.WorkloadIdentityMember(),
+	)
+	return err
+}
+
+// applyBrokerManifest installs the broker and registers it with the service
+// catalog.
+func applyBrokerManifest(ctx context.Context, cfg brokerConfig) error {
+	ctx = SetLogPrefix(ctx, "Kubernetes")
+
+	manifest, err := brokerManifest(cfg)
+	if err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile("", "kf-gcp-service-broker-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(manifest); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	Logf(ctx, "applying broker manifest")
+	_, err = Kubectl(ctx, "apply", "--filename", f.Name())
+	return err
+}
+
+// brokerManifest returns the YAML for the Kubernetes objects that run and
+// register the broker.
+func brokerManifest(cfg brokerConfig) (string, error) {
+	labels := map[string]string{
+		"app.kubernetes.io/name":       brokerName,
+		"app.kubernetes.io/managed-by": "kf",
+	}
+
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cfg.Namespace,
+			Labels:    labels,
+		}
+	}
+
+	checksum := sha256.Sum256([]byte(cfg.Password))
+	replicas := int32(1)
+
+	objects := []interface{}{
+		&corev1.Namespace{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Namespace"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   cfg.Namespace,
+				Labels: labels,
+			},
+		},
+		&corev1.ServiceAccount{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      brokerServiceAccount,
+				Namespace: cfg.Namespace,
+				Labels:    labels,
+				Annotations: map[string]string{
+					"iam.gke.io/gcp-service-account": cfg.GoogleServiceAccount(),
+				},
+			},
+		},
+		&corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: meta(brokerName),
+			StringData: map[string]string{
+				"username": brokerUser,
+				"password": cfg.Password,
+			},
+		},
+		&corev1.PersistentVolumeClaim{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+			ObjectMeta: meta(brokerName),
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceStorage: resource.MustParse(brokerStorageSize),
+					},
+				},
+			},
+		},
+		&appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "apps/v1", Kind: "Deployment"},
+			ObjectMeta: meta(brokerName),
+			Spec: appsv1.DeploymentSpec{
+				Replicas: &replicas,
+				Selector: &metav1.LabelSelector{MatchLabels: labels},
+				// The volume can only be mounted by one node, so the old pod
+				// has to stop before the new one starts.
+				Strategy: appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType},
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: labels,
+						Annotations: map[string]string{
+							credentialsChecksumAnnotation: hex.EncodeToString(checksum[:]),
+						},
+					},
+					Spec: corev1.PodSpec{
+						ServiceAccountName: brokerServiceAccount,
+						Containers: []corev1.Container{
+							{
+								Name:  "broker",
+								Image: cfg.Image,
+								Args:  []string{"serve"},
+								Ports: []corev1.ContainerPort{
+									{Name: "http", ContainerPort: brokerPort},
+								},
+								Env: []corev1.EnvVar{
+									{Name: "PORT", Value: fmt.Sprint(brokerPort)},
+									{Name: "GOOGLE_CLOUD_PROJECT", Value: cfg.Project},
+									{Name: "DB_TYPE", Value: "sqlite3"},
+									{Name: "DB_PATH", Value: "/var/lib/gsb/service-broker.db"},
+									secretEnvVar("SECURITY_USER_NAME", "username"),
+									secretEnvVar("SECURITY_USER_PASSWORD", "password"),
+								},
+								VolumeMounts: []corev1.VolumeMount{
+									{Name: "data", MountPath: "/var/lib/gsb"},
+								},
+							},
+						},
+						Volumes: []corev1.Volume{
+							{
+								Name: "data",
+								VolumeSource: corev1.VolumeSource{
+									PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+										ClaimName: brokerName,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		&corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: meta(brokerName),
+			Spec: corev1.ServiceSpec{
+				Selector: labels,
+				Ports: []corev1.ServicePort{
+					{Name: "http", Port: 80, TargetPort: intstr.FromInt(brokerPort)},
+				},
+			},
+		},
+		&servicecatalogv1beta1.ClusterServiceBroker{
+			TypeMeta: metav1.TypeMeta{APIVersion: "servicecatalog.k8s.io/v1beta1", Kind: "ClusterServiceBroker"},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   brokerName,
+				Labels: labels,
+			},
+			Spec: servicecatalogv1beta1.ClusterServiceBrokerSpec{
+				CommonServiceBrokerSpec: servicecatalogv1beta1.CommonServiceBrokerSpec{
+					URL: fmt.Sprintf("http://%s.%s.svc.cluster.local", brokerName, cfg.Namespace),
+				},
+				AuthInfo: &servicecatalogv1beta1.ClusterServiceBrokerAuthInfo{
+					Basic: &servicecatalogv1beta1.ClusterBasicAuthConfig{
+						SecretRef: &servicecatalogv1beta1.ObjectReference{
+							Namespace: cfg.Namespace,
+							Name:      brokerName,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var docs []string
+	for _, obj := range objects {
+		doc, err := yaml.Marshal(obj)
+		if err != nil {
+			return "", err
+		}
+		docs = append(docs, string(doc))
+	}
+
+	return strings.Join(docs, "---\n"), nil
+}
+
+func secretEnvVar(name, key string) corev1.EnvVar {
+	return corev1.EnvVar{
+		Name: name,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: brokerName},
+				Key:                  key,
+			},
+		},
+	}
+}
+
+// gcloud will run the command and block until its done.
+func gcloud(ctx context.Context, args ...string) ([]string, error) {
+	return Command(ctx, "gcloud", args...)
+}
@@ -0,0 +1,67 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gcp
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func Example_brokerConfig() {
+	cfg := brokerConfig{Project: "my-project", Namespace: "gcp-service-broker"}
+
+	fmt.Println(cfg.GoogleServiceAccount())
+	fmt.Println(cfg.WorkloadIdentityMember())
+
+	// Output: kf-gcp-service-broker@my-project.iam.gserviceaccount.com
+	// serviceAccount:my-project.svc.id.goog[gcp-service-broker/kf-gcp-service-broker]
+}
+
+func TestBrokerManifest(t *testing.T) {
+	manifest, err := brokerManifest(brokerConfig{
+		Project:   "my-project",
+		Namespace: "brokers",
+		Image:     "some-image",
+		Password:  "some-password",
+	})
+
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertContainsAll(t, manifest, []string{
+		"kind: Namespace",
+		"kind: ServiceAccount",
+		"iam.gke.io/gcp-service-account: kf-gcp-service-broker@my-project.iam.gserviceaccount.com",
+		"password: some-password",
+		"image: some-image",
+		"kind: PersistentVolumeClaim",
+		"storage: 1Gi",
+		"claimName: gcp-service-broker",
+		"type: Recreate",
+		"value: my-project",
+		"value: sqlite3",
+		"kind: ClusterServiceBroker",
+		"url: http://gcp-service-broker.brokers.svc.cluster.local",
+	})
+
+	other, err := brokerManifest(brokerConfig{
+		Project:   "my-project",
+		Namespace: "brokers",
+		Image:     "some-image",
+		Password:  "other-password",
+	})
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "pods roll with new password", true, manifest != other)
+}
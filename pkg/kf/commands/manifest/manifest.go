@@ -0,0 +1,123 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/google/kf/pkg/kf/manifest"
+	"github.com/spf13/cobra"
+)
+
+// NewManifestCommand creates a command with sub-commands to lint manifests.
+func NewManifestCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "manifest [subcommand]",
+		Short: "Validate App manifests and export their JSON schema",
+		Long: `The manifest sub-command checks App manifests without deploying
+		them so problems can be caught in CI, and exports the JSON schema of
+		manifests for editors and other linters.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newValidateCommand(),
+		newSchemaCommand(),
+	)
+
+	return cmd
+}
+
+func newValidateCommand() *cobra.Command {
+	var manifestFile string
+
+	cmd := &cobra.Command{
+		Use:   "validate [-f MANIFEST]",
+		Short: "Check a manifest against the manifest schema",
+		Long: `Check a manifest against the manifest schema and Kf's validation
+		rules.
+
+		Each problem is printed with the line and column it was found at. The
+		command fails if there are any problems so it can be used to lint
+		manifests in CI.
+		`,
+		Example: `
+		kf manifest validate
+		kf manifest validate -f deploy/manifest.yml`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			contents, err := ioutil.ReadFile(manifestFile)
+			if err != nil {
+				return err
+			}
+
+			lintErrs, err := manifest.Lint(context.Background(), contents)
+			if err != nil {
+				return fmt.Errorf("%s isn't valid YAML: %s", manifestFile, err)
+			}
+
+			if len(lintErrs) == 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s is valid\n", manifestFile)
+				return nil
+			}
+
+			for _, lintErr := range lintErrs {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s:%s\n", manifestFile, lintErr)
+			}
+
+			return fmt.Errorf("%s has %d problem(s)", manifestFile, len(lintErrs))
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&manifestFile,
+		"manifest",
+		"f",
+		"manifest.yml",
+		"Path to manifest",
+	)
+
+	return cmd
+}
+
+func newSchemaCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON schema of manifests",
+		Long: `Print the JSON schema of App manifests.
+
+		The schema can be used by editors to complete and check manifests or by
+		other JSON schema validators.
+		`,
+		Example: "kf manifest schema > manifest.schema.json",
+		Args:    cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, err := json.MarshalIndent(manifest.JSONSchema(), "", "  ")
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			return nil
+		},
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/manifest"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestValidateCommand(t *testing.T) {
+	cases := map[string]struct {
+		manifest        string
+		ExpectedStrings []string
+		ExpectedErr     error
+	}{
+		"valid": {
+			manifest:        "applications:\n- name: my-app\n",
+			ExpectedStrings: []string{"manifest.yml is valid"},
+		},
+		"invalid yaml": {
+			manifest:    "applications: [",
+			ExpectedErr: errors.New("manifest.yml isn't valid YAML: yaml: line 1: did not find expected node content"),
+		},
+		"problems": {
+			manifest: "applications:\n- name: my-app\n  memory: 512\n",
+			ExpectedStrings: []string{
+				"manifest.yml:3:3: applications[0].memory: expected string, got integer",
+			},
+			ExpectedErr: errors.New("manifest.yml has 1 problem(s)"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "manifest")
+			testutil.AssertNil(t, "err", err)
+			defer os.RemoveAll(dir)
+
+			manifestFile := filepath.Join(dir, "manifest.yml")
+			testutil.AssertNil(t, "err", ioutil.WriteFile(manifestFile, []byte(tc.manifest), 0600))
+
+			cwd, err := os.Getwd()
+			testutil.AssertNil(t, "err", err)
+			testutil.AssertNil(t, "err", os.Chdir(dir))
+			defer os.Chdir(cwd)
+
+			buf := &bytes.Buffer{}
+			cmd := NewManifestCommand()
+			cmd.SetOutput(buf)
+			cmd.SetArgs([]string{"validate"})
+
+			gotErr := cmd.Execute()
+			testutil.AssertErrorsEqual(t, tc.ExpectedErr, gotErr)
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+		})
+	}
+}
+
+func TestValidateCommand_missingFile(t *testing.T) {
+	cmd := NewManifestCommand()
+	cmd.SetOutput(&bytes.Buffer{})
+	cmd.SetArgs([]string{"validate", "-f", "/does/not/exist.yml"})
+
+	testutil.AssertErrorsEqual(t, errors.New("open /does/not/exist.yml: no such file or directory"), cmd.Execute())
+}
+
+func TestSchemaCommand(t *testing.T) {
+	buf := &bytes.Buffer{}
+	cmd := NewManifestCommand()
+	cmd.SetOutput(buf)
+	cmd.SetArgs([]string{"schema"})
+	testutil.AssertNil(t, "err", cmd.Execute())
+
+	got := &manifest.Schema{}
+	testutil.AssertNil(t, "unmarshal err", json.Unmarshal(buf.Bytes(), got))
+	testutil.AssertEqual(t, "$schema", manifest.SchemaVersion, got.Schema)
+	testutil.AssertEqual(t, "required", []string{"applications"}, got.Required)
+}
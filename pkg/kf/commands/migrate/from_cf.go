@@ -0,0 +1,165 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/google/kf/pkg/kf/migrate"
+	"github.com/spf13/cobra"
+)
+
+const (
+	migratedManifestName = "manifest.yml"
+	migrateScriptName    = "migrate.sh"
+)
+
+// NewFromCFCommand creates a command that converts a CF manifest and the
+// exported environments of its apps into a kf manifest and a script to
+// recreate the apps.
+func NewFromCFCommand() *cobra.Command {
+	var (
+		manifestPath string
+		cfEnvPaths   []string
+		outputDir    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "from-cf [--manifest MANIFEST] [--cf-env ENV_JSON]... [--output-dir DIR]",
+		Short: "Generate the kf commands to recreate Cloud Foundry apps",
+		Long: `Reads a Cloud Foundry manifest and, optionally, the environment of each
+		app exported with:
+
+		  cf curl /v3/apps/$(cf app APP_NAME --guid)/env > APP_NAME-env.json
+
+		then writes a kf manifest and a migrate.sh script to the output
+		directory. The script creates the services the apps are bound to and
+		pushes the apps. Environment variables set with cf set-env are copied
+		into the manifest, service credentials are not.
+
+		Features kf doesn't support are listed as warnings and in the script,
+		review them before running it.`,
+		Example: `
+  kf migrate from-cf --manifest manifest.yml
+  kf migrate from-cf --manifest manifest.yml --cf-env my-app-env.json --output-dir migration
+  `,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rawManifest, err := ioutil.ReadFile(manifestPath)
+			if err != nil {
+				return fmt.Errorf("failed to read manifest: %s", err)
+			}
+
+			var envs []*migrate.CFEnv
+			for _, envPath := range cfEnvPaths {
+				env, err := readCFEnv(envPath)
+				if err != nil {
+					return err
+				}
+				envs = append(envs, env)
+			}
+
+			cmd.SilenceUsage = true
+
+			plan, err := migrate.FromCF(rawManifest, envs)
+			if err != nil {
+				return err
+			}
+
+			absManifestPath, err := filepath.Abs(manifestPath)
+			if err != nil {
+				return err
+			}
+
+			absOutputDir, err := filepath.Abs(outputDir)
+			if err != nil {
+				return err
+			}
+
+			if err := plan.RebasePaths(filepath.Dir(absManifestPath), absOutputDir); err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(absOutputDir, 0755); err != nil {
+				return fmt.Errorf("failed to create output directory: %s", err)
+			}
+
+			if err := plan.Manifest.WriteFile(filepath.Join(absOutputDir, migratedManifestName)); err != nil {
+				return fmt.Errorf("failed to write manifest: %s", err)
+			}
+
+			script, err := os.OpenFile(filepath.Join(absOutputDir, migrateScriptName), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+			if err != nil {
+				return fmt.Errorf("failed to write script: %s", err)
+			}
+			plan.WriteScript(script, migratedManifestName)
+			if err := script.Close(); err != nil {
+				return fmt.Errorf("failed to write script: %s", err)
+			}
+
+			w := cmd.OutOrStdout()
+			for _, warning := range plan.Warnings {
+				fmt.Fprintf(w, "Warning: %s\n", warning)
+			}
+
+			fmt.Fprintf(w, "Wrote %s and %s to %s\n", migratedManifestName, migrateScriptName, outputDir)
+			fmt.Fprintf(w, "Review them then run %s to recreate the apps.\n", filepath.Join(outputDir, migrateScriptName))
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(
+		&manifestPath,
+		"manifest",
+		"f",
+		"manifest.yml",
+		"Path to the Cloud Foundry manifest",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&cfEnvPaths,
+		"cf-env",
+		nil,
+		"Path to the exported environment JSON of an app, can be repeated for each app",
+	)
+
+	cmd.Flags().StringVar(
+		&outputDir,
+		"output-dir",
+		"kf-migration",
+		"Directory to write the kf manifest and script to",
+	)
+
+	return cmd
+}
+
+func readCFEnv(path string) (*migrate.CFEnv, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CF environment: %s", err)
+	}
+	defer f.Close()
+
+	env, err := migrate.ParseCFEnv(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %s", path, err)
+	}
+
+	return env, nil
+}
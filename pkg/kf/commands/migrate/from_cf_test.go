@@ -0,0 +1,106 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/commands/migrate"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestFromCF(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		Manifest        string
+		CFEnv           string
+		ExpectedErr     []string
+		ExpectedStrings []string
+		ExpectedScript  []string
+	}{
+		"missing manifest": {
+			ExpectedErr: []string{"failed to read manifest"},
+		},
+		"invalid env": {
+			Manifest:    "applications:\n- name: my-app\n",
+			CFEnv:       "{",
+			ExpectedErr: []string{"env.json", "couldn't parse CF environment"},
+		},
+		"migrates apps": {
+			Manifest: "applications:\n- name: my-app\n  sidecars: []\n",
+			CFEnv: `{
+				"environment_variables": {"GREETING": "hello"},
+				"system_env_json": {"VCAP_SERVICES": {"cleardb": [{"name": "my-db", "label": "cleardb", "plan": "spark"}]}}
+			}`,
+			ExpectedStrings: []string{
+				"Warning: app my-app uses manifest field sidecars",
+				"Wrote manifest.yml and migrate.sh",
+			},
+			ExpectedScript: []string{
+				"kf create-service cleardb spark my-db",
+				"kf push my-app --manifest manifest.yml",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "migrate-from-cf")
+			testutil.AssertNil(t, "err", err)
+			defer os.RemoveAll(dir)
+
+			manifestPath := filepath.Join(dir, "manifest.yml")
+			outputDir := filepath.Join(dir, "out")
+			args := []string{"--manifest", manifestPath, "--output-dir", outputDir}
+
+			if tc.Manifest != "" {
+				testutil.AssertNil(t, "err", ioutil.WriteFile(manifestPath, []byte(tc.Manifest), 0644))
+			}
+
+			if tc.CFEnv != "" {
+				envPath := filepath.Join(dir, "env.json")
+				testutil.AssertNil(t, "err", ioutil.WriteFile(envPath, []byte(tc.CFEnv), 0644))
+				args = append(args, "--cf-env", envPath)
+			}
+
+			buffer := &bytes.Buffer{}
+			cmd := migrate.NewFromCFCommand()
+			cmd.SetArgs(args)
+			cmd.SetOutput(buffer)
+
+			gotErr := cmd.Execute()
+			if tc.ExpectedErr != nil {
+				testutil.AssertErrorContainsAll(t, gotErr, tc.ExpectedErr)
+				return
+			}
+
+			testutil.AssertNil(t, "err", gotErr)
+			testutil.AssertContainsAll(t, buffer.String(), tc.ExpectedStrings)
+
+			script, err := ioutil.ReadFile(filepath.Join(outputDir, "migrate.sh"))
+			testutil.AssertNil(t, "err", err)
+			testutil.AssertContainsAll(t, string(script), tc.ExpectedScript)
+
+			manifest, err := ioutil.ReadFile(filepath.Join(outputDir, "manifest.yml"))
+			testutil.AssertNil(t, "err", err)
+			testutil.AssertContainsAll(t, string(manifest), []string{"GREETING: hello", "path: ..", "- my-db"})
+		})
+	}
+}
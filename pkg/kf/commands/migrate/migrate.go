@@ -0,0 +1,40 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewMigrateCommand creates a command that groups helpers for moving apps
+// to kf from other platforms.
+func NewMigrateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "migrate [subcommand]",
+		Short: "Move apps to kf from other platforms",
+		Long: `The migrate sub-command contains helpers that convert the configuration
+		of apps on other platforms into the kf resources and commands needed to
+		recreate them.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		NewFromCFCommand(),
+	)
+
+	return cmd
+}
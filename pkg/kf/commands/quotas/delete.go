@@ -20,6 +20,7 @@ import (
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
 	"github.com/google/kf/pkg/kf/spaces"
 
 	"github.com/spf13/cobra"
@@ -27,6 +28,8 @@ import (
 
 // NewDeleteQuotaCommand allows users to delete quotas.
 func NewDeleteQuotaCommand(p *config.KfParams, client spaces.Client) *cobra.Command {
+	var diffFlags utils.DiffFlags
+
 	cmd := &cobra.Command{
 		Use:        "delete-quota SPACE_NAME",
 		Short:      "Remove all quotas for the space",
@@ -36,10 +39,15 @@ func NewDeleteQuotaCommand(p *config.KfParams, client spaces.Client) *cobra.Comm
 		RunE: func(cmd *cobra.Command, args []string) error {
 			spaceName := args[0]
 
-			_, err := client.Transform(spaceName, spaces.DiffWrapper(cmd.OutOrStdout(), func(space *v1alpha1.Space) error {
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			_, err = client.Transform(spaceName, spaces.DiffWrapper(cmd.OutOrStdout(), func(space *v1alpha1.Space) error {
 				kfspace := spaces.NewFromSpace(space)
 				return kfspace.DeleteQuota()
-			}))
+			}, diffOpts...))
 
 			if err != nil {
 				return err
@@ -52,6 +60,8 @@ func NewDeleteQuotaCommand(p *config.KfParams, client spaces.Client) *cobra.Comm
 		},
 	}
 
+	diffFlags.Add(cmd)
+
 	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
 
 	return cmd
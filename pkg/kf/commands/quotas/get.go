@@ -27,13 +27,23 @@ import (
 
 // NewGetQuotaCommand allows users to get quota info.
 func NewGetQuotaCommand(p *config.KfParams, client spaces.Client) *cobra.Command {
+	var interactive bool
+
 	cmd := &cobra.Command{
-		Use:     "quota SPACE_NAME",
-		Short:   "Show quota info for a space",
-		Example: `kf quota my-space`,
-		Args:    cobra.ExactArgs(1),
+		Use:   "quota SPACE_NAME",
+		Short: "Show quota info for a space",
+		Example: `
+  kf quota my-space
+  kf quota my-space --interactive`,
+		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			spaceName := args[0]
+
+			if interactive {
+				cmd.SilenceUsage = true
+				return runQuotaWizard(cmd.InOrStdin(), cmd.OutOrStdout(), client, spaceName)
+			}
+
 			fmt.Fprintf(cmd.OutOrStdout(), "Getting info for quota in space: %s\n\n", spaceName)
 
 			space, err := client.Get(spaceName)
@@ -57,6 +67,13 @@ func NewGetQuotaCommand(p *config.KfParams, client spaces.Client) *cobra.Command
 		},
 	}
 
+	cmd.Flags().BoolVar(
+		&interactive,
+		"interactive",
+		false,
+		"Show usage, suggest limits based on it and update the quota after previewing it.",
+	)
+
 	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
 
 	return cmd
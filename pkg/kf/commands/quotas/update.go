@@ -18,6 +18,7 @@ import (
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
 	"github.com/google/kf/pkg/kf/spaces"
 	"github.com/spf13/cobra"
 )
@@ -34,6 +35,8 @@ func NewUpdateQuotaCommand(p *config.KfParams, client spaces.Client) *cobra.Comm
 		memory string
 		cpu    string
 		routes string
+
+		diffFlags utils.DiffFlags
 	)
 
 	cmd := &cobra.Command{
@@ -44,14 +47,19 @@ func NewUpdateQuotaCommand(p *config.KfParams, client spaces.Client) *cobra.Comm
 		Aliases:    []string{"create-quota"},
 		SuggestFor: []string{"create-space-quota", "update-space-quota"},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
 			cmd.SilenceUsage = true
 
 			spaceName := args[0]
 
-			_, err := client.Transform(spaceName, spaces.DiffWrapper(cmd.OutOrStdout(), func(space *v1alpha1.Space) error {
+			_, err = client.Transform(spaceName, spaces.DiffWrapper(cmd.OutOrStdout(), func(space *v1alpha1.Space) error {
 				kfspace := spaces.NewFromSpace(space)
 				return setQuotaValues(memory, cpu, routes, kfspace)
-			}))
+			}, diffOpts...))
 
 			return err
 		},
@@ -81,6 +89,8 @@ func NewUpdateQuotaCommand(p *config.KfParams, client spaces.Client) *cobra.Comm
 		"Maximum number of routes the space can have (default: unlimited)",
 	)
 
+	diffFlags.Add(cmd)
+
 	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
 
 	return cmd
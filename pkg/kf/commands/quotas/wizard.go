@@ -0,0 +1,209 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotas
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/describe"
+	"github.com/google/kf/pkg/kf/spaces"
+	spaceresources "github.com/google/kf/pkg/reconciler/space/resources"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// quotaResource describes a resource the quota wizard asks about.
+type quotaResource struct {
+	Label string
+	Name  v1.ResourceName
+
+	// Step is what suggestions get rounded up to a multiple of.
+	Step resource.Quantity
+}
+
+var wizardResources = []quotaResource{
+	{Label: "Memory", Name: v1.ResourceMemory, Step: resource.MustParse("256Mi")},
+	{Label: "CPU", Name: v1.ResourceCPU, Step: resource.MustParse("100m")},
+	{Label: "Routes", Name: v1.ResourceServices, Step: resource.MustParse("1")},
+}
+
+// runQuotaWizard shows the space's quota usage, asks for new limits using
+// suggestions based on that usage, previews the resulting ResourceQuota and
+// applies it once confirmed.
+func runQuotaWizard(in io.Reader, out io.Writer, client spaces.Client, spaceName string) error {
+	space, err := client.Get(spaceName)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Quota usage for space %s:\n\n", spaceName)
+
+	defaults := make(map[v1.ResourceName]string)
+	describe.TabbedWriter(out, func(w io.Writer) {
+		fmt.Fprintln(w, "Resource\tUsed\tLimit\tSuggested")
+
+		for _, r := range wizardResources {
+			used := space.Status.Quota.Used[r.Name]
+
+			// Existing limits are kept by default, unlimited resources
+			// default to the suggestion if there's usage to base it on.
+			defaults[r.Name] = "0"
+
+			suggested := "-"
+			if !used.IsZero() {
+				suggestion := suggestQuota(used, r.Step)
+				suggested = suggestion.String()
+				defaults[r.Name] = suggested
+			}
+
+			limit := "unlimited"
+			if hard, ok := space.Spec.ResourceLimits.SpaceQuota[r.Name]; ok {
+				limit = hard.String()
+				defaults[r.Name] = limit
+			}
+
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Label, used.String(), limit, suggested)
+		}
+	})
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Suggestions leave 50% headroom over current usage, enter 0 for no limit.")
+
+	prompt := &linePrompter{scanner: bufio.NewScanner(in), out: out}
+
+	answers := make(map[v1.ResourceName]string)
+	for _, r := range wizardResources {
+		answer, err := prompt.Ask(r.Label, defaults[r.Name], func(answer string) error {
+			_, err := resource.ParseQuantity(answer)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		answers[r.Name] = answer
+	}
+
+	setQuota := func(space *v1alpha1.Space) error {
+		return setQuotaValues(
+			answers[v1.ResourceMemory],
+			answers[v1.ResourceCPU],
+			answers[v1.ResourceServices],
+			spaces.NewFromSpace(space),
+		)
+	}
+
+	preview := space.DeepCopy()
+	if err := setQuota(preview); err != nil {
+		return err
+	}
+
+	quota, err := spaceresources.MakeResourceQuota(preview)
+	if err != nil {
+		return err
+	}
+	quota.APIVersion = "v1"
+	quota.Kind = "ResourceQuota"
+
+	quotaYAML, err := yaml.Marshal(quota)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "ResourceQuota preview:")
+	fmt.Fprintln(out, string(quotaYAML))
+
+	apply, err := prompt.Ask("Apply the quota? (y/n)", "y", func(answer string) error {
+		switch strings.ToLower(answer) {
+		case "y", "yes", "n", "no":
+			return nil
+		default:
+			return errors.New("answer y or n")
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasPrefix(strings.ToLower(apply), "y") {
+		fmt.Fprintln(out, "The quota wasn't changed.")
+		return nil
+	}
+
+	_, err = client.Transform(spaceName, spaces.DiffWrapper(out, setQuota))
+	return err
+}
+
+// suggestQuota suggests a limit with 50% headroom over the used amount,
+// rounded up to a multiple of step.
+func suggestQuota(used, step resource.Quantity) resource.Quantity {
+	stepMilli := step.MilliValue()
+	target := (used.MilliValue()*3 + 1) / 2
+	rounded := (target + stepMilli - 1) / stepMilli * stepMilli
+
+	if rounded%1000 == 0 {
+		return *resource.NewQuantity(rounded/1000, step.Format)
+	}
+
+	return *resource.NewMilliQuantity(rounded, step.Format)
+}
+
+// linePrompter asks questions on an output and reads the answers line by line
+// from an input.
+type linePrompter struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+// Ask prints the label and reads an answer, using def if the answer is blank.
+// If the answer fails validation the question is asked again.
+func (p *linePrompter) Ask(label, def string, validate func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(p.out, "%s [%s]: ", label, def)
+		} else {
+			fmt.Fprintf(p.out, "%s: ", label)
+		}
+
+		if !p.scanner.Scan() {
+			if err := p.scanner.Err(); err != nil {
+				return "", err
+			}
+			return "", errors.New("no answer was given, the quota wizard needs a terminal")
+		}
+
+		answer := strings.TrimSpace(p.scanner.Text())
+		if answer == "" {
+			answer = def
+		}
+
+		if validate == nil {
+			return answer, nil
+		}
+
+		if err := validate(answer); err != nil {
+			fmt.Fprintf(p.out, "Invalid answer: %s\n", err)
+			continue
+		}
+
+		return answer, nil
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package quotas
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/google/kf/pkg/kf/spaces/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestGetQuotaCommand_interactive(t *testing.T) {
+	t.Parallel()
+
+	space := &v1alpha1.Space{}
+	space.Name = "space-a"
+	space.Spec.ResourceLimits.SpaceQuota = v1.ResourceList{
+		v1.ResourceCPU: resource.MustParse("2"),
+	}
+	space.Status.Quota.Used = v1.ResourceList{
+		v1.ResourceMemory:   resource.MustParse("1Gi"),
+		v1.ResourceCPU:      resource.MustParse("250m"),
+		v1.ResourceServices: resource.MustParse("3"),
+	}
+
+	for tn, tc := range map[string]struct {
+		stdin      string
+		wantApply  bool
+		wantQuota  v1.ResourceList
+		wantOutput []string
+	}{
+		"accepts defaults": {
+			stdin:     "\n\n\n\n",
+			wantApply: true,
+			wantQuota: v1.ResourceList{
+				v1.ResourceMemory:   resource.MustParse("1536Mi"),
+				v1.ResourceCPU:      resource.MustParse("2"),
+				v1.ResourceServices: resource.MustParse("5"),
+			},
+			wantOutput: []string{
+				"Quota usage for space space-a",
+				"Memory [1536Mi]",
+				"CPU [2]",
+				"Routes [5]",
+				"kind: ResourceQuota",
+				"name: space-quota",
+			},
+		},
+		"custom limits": {
+			stdin:     "8Gi\n0\nlots\n20\ny\n",
+			wantApply: true,
+			wantQuota: v1.ResourceList{
+				v1.ResourceMemory:   resource.MustParse("8Gi"),
+				v1.ResourceServices: resource.MustParse("20"),
+			},
+			wantOutput: []string{"Invalid answer"},
+		},
+		"declines": {
+			stdin:      "\n\n\nn\n",
+			wantOutput: []string{"The quota wasn't changed."},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+
+			fakeSpaces.EXPECT().Get("space-a").Return(space.DeepCopy(), nil)
+
+			output := space.DeepCopy()
+			if tc.wantApply {
+				fakeSpaces.EXPECT().
+					Transform("space-a", gomock.Any()).
+					DoAndReturn(func(spaceName string, transformer spaces.Mutator) (*v1alpha1.Space, error) {
+						return output, transformer(output)
+					})
+			}
+
+			buffer := &bytes.Buffer{}
+
+			c := NewGetQuotaCommand(&config.KfParams{}, fakeSpaces)
+			c.SetOutput(buffer)
+			c.SetIn(strings.NewReader(tc.stdin))
+			c.SetArgs([]string{"space-a", "--interactive"})
+
+			testutil.AssertNil(t, "Command err", c.Execute())
+			testutil.AssertContainsAll(t, buffer.String(), tc.wantOutput)
+
+			if tc.wantApply {
+				testutil.AssertEqual(t, "quota", quotaStrings(tc.wantQuota), quotaStrings(output.Spec.ResourceLimits.SpaceQuota))
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
+
+// quotaStrings formats quantities so they can be compared regardless of how
+// they were created.
+func quotaStrings(list v1.ResourceList) map[v1.ResourceName]string {
+	out := make(map[v1.ResourceName]string)
+	for name, quantity := range list {
+		out[name] = quantity.String()
+	}
+
+	return out
+}
+
+func Example_suggestQuota() {
+	for _, tc := range []struct {
+		used string
+		step string
+	}{
+		{used: "1Gi", step: "256Mi"},
+		{used: "300Mi", step: "256Mi"},
+		{used: "250m", step: "100m"},
+		{used: "2", step: "100m"},
+		{used: "3", step: "1"},
+	} {
+		suggestion := suggestQuota(resource.MustParse(tc.used), resource.MustParse(tc.step))
+		fmt.Printf("%s: %s\n", tc.used, suggestion.String())
+	}
+
+	// Output: 1Gi: 1536Mi
+	// 300Mi: 512Mi
+	// 250m: 400m
+	// 2: 3
+	// 3: 5
+}
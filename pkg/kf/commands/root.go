@@ -15,17 +15,28 @@
 package commands
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"runtime"
 	"strings"
+	"syscall"
+	"time"
 
+	"github.com/fatih/color"
+	"github.com/google/kf/pkg/kf/commands/ci"
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
 	"github.com/google/kf/pkg/kf/commands/doctor"
+	"github.com/google/kf/pkg/kf/commands/gcp"
 	"github.com/google/kf/pkg/kf/commands/group"
 	"github.com/google/kf/pkg/kf/commands/install"
+	"github.com/google/kf/pkg/kf/commands/manifest"
+	"github.com/google/kf/pkg/kf/commands/migrate"
 	pkgdoctor "github.com/google/kf/pkg/kf/doctor"
+	"github.com/google/kf/pkg/kf/machine"
 	templates "github.com/google/kf/third_party/kubectl-templates"
 	"github.com/imdario/mergo"
 	"github.com/spf13/cobra"
@@ -56,12 +67,28 @@ func NewKfCommand() *cobra.Command {
 			`),
 		DisableAutoGenTag: false,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if p.Machine {
+				// Pipelines parse stdout so it must only contain events, errors
+				// are still written to stderr.
+				cmd.SetOut(machine.NewWriter(cmd.OutOrStdout()))
+				cmd.SilenceUsage = true
+				color.NoColor = true
+			}
+
+			if len(p.ImpersonateGroups) > 0 && p.Impersonate == "" {
+				return errors.New("--as-group requires --as to be set")
+			}
+
 			loadedConfig, err := config.Load(p.Config, p)
 			if err != nil {
 				return err
 			}
 
-			return mergo.Map(p, loadedConfig)
+			if err := mergo.Map(p, loadedConfig); err != nil {
+				return err
+			}
+
+			return checkReadOnly(p, cmd)
 		},
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.Help()
@@ -73,38 +100,65 @@ func NewKfCommand() *cobra.Command {
 	rootCmd.PersistentFlags().StringVar(&p.Namespace, "namespace", "", "Kubernetes namespace to target")
 	completion.MarkFlagCompletionSupported(rootCmd.PersistentFlags(), "namespace", "spaces")
 
+	rootCmd.PersistentFlags().StringVar(&p.Impersonate, "as", "", "Username to impersonate for Kubernetes calls")
+	rootCmd.PersistentFlags().StringArrayVar(&p.ImpersonateGroups, "as-group", nil, "Group to impersonate for Kubernetes calls, can be repeated")
+	rootCmd.PersistentFlags().BoolVar(&p.ReadOnly, "readonly", false, "Block every command and request that could change the cluster")
 	rootCmd.PersistentFlags().BoolVar(&p.LogHTTP, "log-http", false, "Log HTTP requests to stderr")
+	rootCmd.PersistentFlags().BoolVar(&p.Machine, "machine", false, "Emit output as line-delimited JSON events for CI pipelines")
 
 	rootCmd = group.AddCommandGroups(rootCmd, group.CommandGroups{
 		{
 			Name: "App Management",
 			Commands: []*cobra.Command{
 				InjectPush(p),
+				InjectDeployBlueGreen(p),
 				InjectDelete(p),
 				InjectApps(p),
 				InjectGetApp(p),
 				InjectStart(p),
 				InjectStop(p),
 				InjectRestart(p),
+				InjectRestartAppInstance(p),
 				InjectRestage(p),
+				InjectPromote(p),
+				InjectEnableCITrigger(p),
 				InjectScale(p),
+				InjectConfigureApp(p),
 				InjectLogs(p),
+				InjectTail(p),
 				InjectProxy(p),
+				InjectOpen(p),
+				InjectSSH(p),
+				InjectRun(p),
+				InjectReport(p),
 			},
 		},
 		{
 			Name: "Environment Variables",
 			Commands: []*cobra.Command{
 				InjectEnv(p),
+				InjectEnvDiff(p),
 				InjectSetEnv(p),
 				InjectUnsetEnv(p),
 			},
 		},
+		{
+			Name: "Secrets",
+			Commands: []*cobra.Command{
+				InjectCreateSecret(p),
+				InjectListSecrets(p),
+				InjectDeleteSecret(p),
+			},
+		},
 		{
 			Name: "Buildpacks",
 			Commands: []*cobra.Command{
 				InjectBuildpacks(p),
 				InjectStacks(p),
+				InjectCreateStack(p),
+				InjectDeleteStack(p),
+				InjectRebaseApps(p),
+				InjectImageInfo(p),
 			},
 		},
 		{
@@ -116,6 +170,14 @@ func NewKfCommand() *cobra.Command {
 				InjectMapRoute(p),
 				InjectUnmapRoute(p),
 				InjectProxyRoute(p),
+				InjectSetMaintenance(p),
+				InjectSetRoutePolicy(p),
+				InjectSetRouteCORS(p),
+				InjectSetRouteHeader(p),
+				InjectRouterLogs(p),
+				InjectTrace(p),
+				InjectCreateSLO(p),
+				InjectSLOs(p),
 			},
 		},
 		{
@@ -133,7 +195,11 @@ func NewKfCommand() *cobra.Command {
 				InjectDeleteService(p),
 				InjectGetService(p),
 				InjectListServices(p),
+				InjectUpdateService(p),
 				InjectMarketplace(p),
+				InjectShareService(p),
+				InjectUnshareService(p),
+				InjectServiceShares(p),
 			},
 		},
 		{
@@ -142,6 +208,7 @@ func NewKfCommand() *cobra.Command {
 				InjectBindingService(p),
 				InjectListBindings(p),
 				InjectUnbindService(p),
+				InjectConfigureBinding(p),
 				InjectVcapServices(p),
 			},
 		},
@@ -150,6 +217,8 @@ func NewKfCommand() *cobra.Command {
 			Commands: []*cobra.Command{
 				InjectCreateServiceBroker(p),
 				InjectDeleteServiceBroker(p),
+				InjectDevServices(p),
+				gcp.NewGCPCommand(),
 			},
 		},
 		{
@@ -160,6 +229,16 @@ func NewKfCommand() *cobra.Command {
 				InjectCreateSpace(p),
 				InjectDeleteSpace(p),
 				InjectConfigSpace(p),
+				InjectBackupSpace(p),
+				InjectSnapshot(p),
+				InjectCloneSpace(p),
+				InjectConfigureCluster(p),
+			},
+		},
+		{
+			Name: "Auth",
+			Commands: []*cobra.Command{
+				InjectAuth(p),
 			},
 		},
 		{
@@ -178,12 +257,15 @@ func NewKfCommand() *cobra.Command {
 				// testing the cluster because if the cluster isn't working then all the
 				// app tests will fail.
 				doctor.NewDoctorCommand(p, []doctor.DoctorTest{
-					{Name: "cluster", Test: pkgdoctor.NewClusterDiagnostic(config.GetKubernetes(p))},
+					{Name: "cluster", Test: pkgdoctor.NewClusterDiagnostic(config.GetDiscoveryClient(p))},
 					{Name: "buildpacks", Test: InjectBuildpacksClient(p)},
 				}),
 
-				completionCommand(rootCmd),
+				completionCommand(p, rootCmd),
 				install.NewInstallCommand(),
+				migrate.NewMigrateCommand(),
+				manifest.NewManifestCommand(),
+				ci.NewCICommand(p),
 				NewTargetCommand(p),
 				NewVersionCommand(Version, runtime.GOOS),
 				NewDebugCommand(p),
@@ -201,20 +283,110 @@ func NewKfCommand() *cobra.Command {
 
 	rootCmd = templates.NormalizeAll(rootCmd)
 
+	rootCmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return machine.WithExitCode(machine.ExitUsage, err)
+	})
+	markArgErrors(rootCmd)
+
 	return rootCmd
 }
 
-func completionCommand(rootCmd *cobra.Command) *cobra.Command {
-	return &cobra.Command{
+// readOnlyBlockedCommands change things without going through Kf's
+// Kubernetes clients, e.g. by writing to a registry or running kubectl, so
+// their requests can't be blocked in read-only mode and they're refused
+// before they start.
+var readOnlyBlockedCommands = map[string]bool{
+	"push":              true,
+	"deploy-blue-green": true,
+	"rebase-apps":       true,
+	"ssh":               true,
+	"run":               true,
+	"install":           true,
+	"gcp":               true,
+}
+
+// checkReadOnly returns an error if cmd or one of its parents can't be run in
+// read-only mode.
+func checkReadOnly(p *config.KfParams, cmd *cobra.Command) error {
+	if !p.ReadOnly {
+		return nil
+	}
+
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if readOnlyBlockedCommands[c.Name()] {
+			return fmt.Errorf("kf is in read-only mode, %q can't be run", cmd.CommandPath())
+		}
+	}
+
+	return nil
+}
+
+// markArgErrors gives argument validation errors of cmd and its children the
+// usage exit code so they can be told apart from failed operations.
+func markArgErrors(cmd *cobra.Command) {
+	if validateArgs := cmd.Args; validateArgs != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			return machine.WithExitCode(machine.ExitUsage, validateArgs(cmd, args))
+		}
+	}
+
+	for _, child := range cmd.Commands() {
+		markArgErrors(child)
+	}
+}
+
+func completionCommand(p *config.KfParams, rootCmd *cobra.Command) *cobra.Command {
+	var (
+		daemon          bool
+		refreshInterval time.Duration
+	)
+
+	cmd := &cobra.Command{
 		Use:   "completion bash|zsh",
 		Short: "Generate auto-completion files for kf commands",
 		Example: `
   eval "$(kf completion bash)"
   eval "$(kf completion zsh)"
+  kf completion --daemon &
 		`,
-		Long: `completion is used to create set up bash/zsh auto-completion for kf commands.`,
-		Args: cobra.ExactArgs(1),
+		Long: `completion is used to create set up bash/zsh auto-completion for kf commands.
+
+With --daemon, names of apps, sources and spaces are periodically cached so
+completion doesn't query the cluster on every TAB press. The daemon follows
+the targeted space unless --namespace is set and runs until it's interrupted.`,
+		Args: cobra.RangeArgs(0, 1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if daemon {
+				if len(args) != 0 {
+					return machine.WithExitCode(machine.ExitUsage, errors.New("--daemon doesn't take a shell"))
+				}
+
+				cmd.SilenceUsage = true
+
+				namespaceOverridden := cmd.Flags().Changed("namespace")
+				namespace := func() string {
+					if namespaceOverridden {
+						return p.Namespace
+					}
+
+					// Reload the config so the daemon follows kf target.
+					if loaded, err := config.NewKfParamsFromFile(p.Config); err == nil && loaded.Namespace != "" {
+						return loaded.Namespace
+					}
+
+					return p.Namespace
+				}
+
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+				defer stop()
+
+				return completion.RunCacheDaemon(ctx, cmd.ErrOrStderr(), config.GetDynamicClient(p), namespace, refreshInterval)
+			}
+
+			if len(args) != 1 {
+				return machine.WithExitCode(machine.ExitUsage, errors.New("accepts 1 arg(s), received 0"))
+			}
+
 			switch shell := strings.ToLower(args[0]); shell {
 			case "bash":
 				return rootCmd.GenBashCompletion(os.Stdout)
@@ -225,4 +397,20 @@ func completionCommand(rootCmd *cobra.Command) *cobra.Command {
 			}
 		},
 	}
+
+	cmd.Flags().BoolVar(
+		&daemon,
+		"daemon",
+		false,
+		"Keep names used for completion cached in the background instead of generating completion files.",
+	)
+
+	cmd.Flags().DurationVar(
+		&refreshInterval,
+		"refresh-interval",
+		completion.DefaultCacheRefreshInterval,
+		"How often the daemon refreshes cached names.",
+	)
+
+	return cmd
 }
@@ -15,9 +15,12 @@
 package commands
 
 import (
+	"bytes"
+	"errors"
 	"strings"
 	"testing"
 
+	"github.com/google/kf/pkg/kf/machine"
 	"github.com/google/kf/pkg/kf/testutil"
 
 	"github.com/spf13/cobra"
@@ -30,6 +33,60 @@ func TestNewKfCommand_style(t *testing.T) {
 	checkCommandStyle(t, root)
 }
 
+func TestNewKfCommand_usageExitCode(t *testing.T) {
+	cases := map[string][]string{
+		"unknown flag":      {"version", "--no-such-flag"},
+		"too many args":     {"delete", "app-a", "app-b"},
+		"missing arguments": {"delete"},
+		"missing shell":     {"completion"},
+		"daemon with shell": {"completion", "bash", "--daemon"},
+	}
+
+	for tn, args := range cases {
+		t.Run(tn, func(t *testing.T) {
+			root := NewKfCommand()
+			root.SetArgs(args)
+			root.SetOutput(&bytes.Buffer{})
+
+			err := root.Execute()
+			testutil.AssertEqual(t, "exit code", machine.ExitUsage, machine.ExitCode(err))
+		})
+	}
+}
+
+func TestNewKfCommand_readOnly(t *testing.T) {
+	cases := map[string]struct {
+		args    []string
+		wantErr error
+	}{
+		"blocked command": {
+			args:    []string{"push", "my-app", "--readonly"},
+			wantErr: errors.New(`kf is in read-only mode, "kf push" can't be run`),
+		},
+		"blocked registry writer": {
+			args:    []string{"rebase-apps", "my-space", "--readonly"},
+			wantErr: errors.New(`kf is in read-only mode, "kf rebase-apps" can't be run`),
+		},
+		"blocked parent command": {
+			args:    []string{"install", "gke", "--readonly"},
+			wantErr: errors.New(`kf is in read-only mode, "kf install gke" can't be run`),
+		},
+		"allowed command": {
+			args: []string{"version", "--readonly"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			root := NewKfCommand()
+			root.SetArgs(tc.args)
+			root.SetOutput(&bytes.Buffer{})
+
+			testutil.AssertErrorsEqual(t, tc.wantErr, root.Execute())
+		})
+	}
+}
+
 func checkCommandStyle(t *testing.T, cmd *cobra.Command) {
 	if cmd.Hidden {
 		return
@@ -70,6 +70,12 @@ Use the --namespace flag instead.`)
 				return errors.New("--hostname is required")
 			}
 
+			if hostname != "" {
+				if err := v1alpha1.ValidateDNS1123Label(hostname, "hostname"); err != nil {
+					return fmt.Errorf("invalid --hostname: %s", err)
+				}
+			}
+
 			cmd.SilenceUsage = true
 
 			urlPath = path.Join("/", urlPath)
@@ -58,6 +58,16 @@ func TestCreateRoute(t *testing.T) {
 				testutil.AssertErrorsEqual(t, errors.New("--hostname is required"), err)
 			},
 		},
+		"hostname isn't a DNS label": {
+			Args:      []string{"some-space", "example.com", "--hostname=My_App"},
+			Namespace: "some-space",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorContainsAll(t, err, []string{
+					"invalid --hostname: invalid value: My_App",
+					`did you mean "my-app"?`,
+				})
+			},
+		},
 		"creating route fails": {
 			Args:      []string{"some-space", "example.com", "--hostname=some-hostname"},
 			Namespace: "some-space",
@@ -0,0 +1,120 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/slos"
+	"github.com/spf13/cobra"
+)
+
+// NewCreateSLOCommand creates a CreateSLO command.
+func NewCreateSLOCommand(
+	p *config.KfParams,
+	c slos.Client,
+) *cobra.Command {
+	var (
+		hostname, urlPath string
+		availability      float64
+		latencyP95        time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create-slo DOMAIN [--hostname HOSTNAME] [--path PATH] [--availability PERCENT] [--latency-p95 DURATION]",
+		Short: "Monitor the availability and latency of a route against targets",
+		Long: `Creates or updates a service level objective for a route. Kf records
+		the percentage of requests that don't fail with a 5xx response and the
+		95th percentile latency of the Apps mapped to the route, and alerts when
+		either misses its target for 10 minutes.
+
+		The rules are stored as a PrometheusRule so the Prometheus Operator
+		must be installed for alerts to fire. The Apps measured are the ones
+		mapped to the route when the SLO is created, run create-slo again after
+		mapping new Apps.
+		`,
+		Example: `
+  kf create-slo example.com --hostname myapp --availability 99.9 --latency-p95 300ms
+  kf create-slo example.com --hostname myapp --path /api --availability 99.5
+  `,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			if err := slos.ValidateTargets(availability, latencyP95); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			slo, err := c.Create(p.Namespace, slos.SLO{
+				Route: v1alpha1.RouteSpecFields{
+					Hostname: hostname,
+					Domain:   args[0],
+					Path:     urlPath,
+				},
+				Availability: availability,
+				LatencyP95:   latencyP95,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create SLO: %s", err)
+			}
+
+			fmt.Fprintf(
+				cmd.OutOrStdout(),
+				"Created SLO %s for %s measuring Apps: %s\n",
+				slo.Name,
+				slo.Route,
+				strings.Join(slo.Apps, ", "),
+			)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&hostname,
+		"hostname",
+		"",
+		"Hostname for the route",
+	)
+	cmd.Flags().StringVar(
+		&urlPath,
+		"path",
+		"",
+		"URL Path for the route",
+	)
+	cmd.Flags().Float64Var(
+		&availability,
+		"availability",
+		0,
+		"Percentage of requests that must not fail with a 5xx response, e.g. 99.9",
+	)
+	cmd.Flags().DurationVar(
+		&latencyP95,
+		"latency-p95",
+		0,
+		"Latency 95% of requests must be served within, e.g. 300ms",
+	)
+
+	return cmd
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/commands/routes"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/slos"
+	fakeslos "github.com/google/kf/pkg/kf/slos/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestCreateSLO(t *testing.T) {
+	t.Parallel()
+
+	route := v1alpha1.RouteSpecFields{
+		Hostname: "some-hostname",
+		Domain:   "example.com",
+		Path:     "somepath",
+	}
+
+	for tn, tc := range map[string]struct {
+		Namespace string
+		Args      []string
+		Setup     func(t *testing.T, fakeSLOs *fakeslos.FakeClient)
+		Assert    func(t *testing.T, buffer *bytes.Buffer, err error)
+	}{
+		"wrong number of args": {
+			Args:      []string{"example.com", "extra", "--availability=99.9"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("accepts 1 arg(s), received 2"), err)
+			},
+		},
+		"without namespace": {
+			Args: []string{"example.com", "--availability=99.9"},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(utils.EmptyNamespaceError), err)
+			},
+		},
+		"no targets": {
+			Args:      []string{"example.com"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("at least one of the availability or p95 latency targets must be set"), err)
+			},
+		},
+		"create fails": {
+			Args:      []string{"example.com", "--availability=99.9"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeSLOs *fakeslos.FakeClient) {
+				fakeSLOs.EXPECT().
+					Create(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("some-error"))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("failed to create SLO: some-error"), err)
+			},
+		},
+		"creates SLO": {
+			Args:      []string{"example.com", "--hostname=some-hostname", "--path=somepath", "--availability=99.9", "--latency-p95=300ms"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeSLOs *fakeslos.FakeClient) {
+				fakeSLOs.EXPECT().
+					Create("some-namespace", slos.SLO{
+						Route:        route,
+						Availability: 99.9,
+						LatencyP95:   300 * time.Millisecond,
+					}).
+					Return(&slos.SLO{
+						Name:  "slo-some-hostname",
+						Route: route,
+						Apps:  []string{"app-a", "app-b"},
+					}, nil)
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertContainsAll(t, buffer.String(), []string{
+					"slo-some-hostname",
+					"some-hostname.example.com/somepath",
+					"app-a, app-b",
+				})
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSLOs := fakeslos.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeSLOs)
+			}
+
+			var buffer bytes.Buffer
+			cmd := routes.NewCreateSLOCommand(
+				&config.KfParams{
+					Namespace: tc.Namespace,
+				},
+				fakeSLOs,
+			)
+			cmd.SetArgs(tc.Args)
+			cmd.SetOutput(&buffer)
+
+			gotErr := cmd.Execute()
+
+			if tc.Assert != nil {
+				tc.Assert(t, &buffer, gotErr)
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
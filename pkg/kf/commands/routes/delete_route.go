@@ -64,11 +64,7 @@ func NewDeleteRouteCommand(
 			apps, err := a.List(
 				p.Namespace,
 				apps.WithListFilter(func(app *v1alpha1.App) bool {
-					return algorithms.Search(
-						0,
-						v1alpha1.RouteSpecFieldsSlice{route},
-						v1alpha1.RouteSpecFieldsSlice(app.Spec.Routes),
-					)
+					return algorithms.Contains(app.Spec.Routes, route, v1alpha1.CompareRouteSpecFields)
 				}),
 			)
 			if err != nil {
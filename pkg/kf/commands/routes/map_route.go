@@ -16,11 +16,14 @@ package routes
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/algorithms"
 	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/commands/config"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
@@ -33,18 +36,24 @@ func NewMapRouteCommand(
 	appsClient apps.Client,
 ) *cobra.Command {
 	var (
-		async    utils.AsyncFlags
-		hostname string
-		urlPath  string
+		async           utils.AsyncFlags
+		hostname        string
+		urlPath         string
+		headers         []string
+		stripPathPrefix bool
+		rewritePath     string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "map-route APP_NAME DOMAIN [--hostname HOSTNAME] [--path PATH]",
+		Use:   "map-route APP_NAME DOMAIN [--hostname HOSTNAME] [--path PATH] [--match-header HEADER] [--strip-path-prefix | --rewrite-path PATH]",
 		Short: "Map a route to an app",
 		Example: `
   kf map-route myapp example.com --hostname myapp # myapp.example.com
   kf map-route --namespace myspace myapp example.com --hostname myapp # myapp.example.com
   kf map-route myapp example.com --hostname myapp --path /mypath # myapp.example.com/mypath
+  kf map-route myapp-beta example.com --hostname myapp --match-header "X-Beta: true" # beta users only
+  kf map-route myapp example.com --path /service --strip-path-prefix # myapp.example.com/service/foo is served as /foo
+  kf map-route myapp example.com --path /service --rewrite-path /v2 # myapp.example.com/service/foo is served as /v2/foo
   `,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -55,14 +64,43 @@ func NewMapRouteCommand(
 			}
 			appName, domain := args[0], args[1]
 
+			if hostname != "" {
+				if err := v1alpha1.ValidateDNS1123Label(hostname, "hostname"); err != nil {
+					return fmt.Errorf("invalid --hostname: %s", err)
+				}
+			}
+
+			headerMatches, err := parseHeaderMatches(headers)
+			if err != nil {
+				return err
+			}
+
+			if stripPathPrefix && rewritePath != "" {
+				return errors.New("--strip-path-prefix and --rewrite-path can't be used together")
+			}
+
+			if rewritePath != "" {
+				rewritePath = path.Join("/", rewritePath)
+			}
+
+			route := v1alpha1.RouteSpecFields{
+				Hostname:        hostname,
+				Domain:          domain,
+				Path:            path.Join("/", urlPath),
+				Headers:         headerMatches,
+				StripPathPrefix: stripPathPrefix,
+				RewritePath:     rewritePath,
+			}
+
 			mutator := func(app *v1alpha1.App) error {
+				// Remapping a route replaces its path rewrite options.
 				app.Spec.Routes = append(
-					app.Spec.Routes,
-					v1alpha1.RouteSpecFields{
-						Hostname: hostname,
-						Domain:   domain,
-						Path:     path.Join("/", urlPath),
-					},
+					algorithms.Delete(
+						app.Spec.Routes,
+						[]v1alpha1.RouteSpecFields{route},
+						v1alpha1.CompareRouteSpecFields,
+					),
+					route,
 				)
 				return nil
 			}
@@ -93,6 +131,56 @@ func NewMapRouteCommand(
 		"",
 		"URL Path for the route",
 	)
+	addMatchHeaderFlag(cmd, &headers)
+	cmd.Flags().BoolVar(
+		&stripPathPrefix,
+		"strip-path-prefix",
+		false,
+		"Remove the route's path from requests before they're sent to the app",
+	)
+	cmd.Flags().StringVar(
+		&rewritePath,
+		"rewrite-path",
+		"",
+		"Replace the route's path with this path in requests sent to the app",
+	)
 
 	return cmd
 }
+
+// addMatchHeaderFlag adds the --match-header flag used to restrict routes to
+// requests carrying specific headers.
+func addMatchHeaderFlag(cmd *cobra.Command, headers *[]string) {
+	cmd.Flags().StringArrayVar(
+		headers,
+		"match-header",
+		nil,
+		"Only match requests with the header, formatted as NAME: VALUE (can be repeated)",
+	)
+}
+
+// parseHeaderMatches converts headers in the form NAME: VALUE into a map of
+// lowercase header names to values.
+func parseHeaderMatches(headers []string) (map[string]string, error) {
+	if len(headers) == 0 {
+		return nil, nil
+	}
+
+	out := make(map[string]string)
+	for _, header := range headers {
+		parts := strings.SplitN(header, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid header %q, must be in the form NAME: VALUE", header)
+		}
+
+		name := strings.ToLower(strings.TrimSpace(parts[0]))
+		value := strings.TrimSpace(parts[1])
+		if name == "" || value == "" {
+			return nil, fmt.Errorf("invalid header %q, must be in the form NAME: VALUE", header)
+		}
+
+		out[name] = value
+	}
+
+	return out, nil
+}
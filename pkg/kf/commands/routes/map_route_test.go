@@ -91,6 +91,85 @@ func TestMapRoute(t *testing.T) {
 				appsfake.EXPECT().WaitForConditionRoutesReadyTrue(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
 			},
 		},
+		"transform App with header matches": {
+			Args:      []string{"some-app", "example.com", "--match-header", "X-Beta: true", "--match-header=x-tier:gold"},
+			Namespace: "some-space",
+			Setup: func(t *testing.T, appsfake *appsfake.FakeClient) {
+				appsfake.EXPECT().
+					Transform(gomock.Any(), gomock.Any(), gomock.Any()).
+					Do(func(_, _ string, m apps.Mutator) {
+						oldApp := v1alpha1.App{}
+						testutil.AssertNil(t, "err", m(&oldApp))
+
+						testutil.AssertEqual(t, "Headers", map[string]string{
+							"x-beta": "true",
+							"x-tier": "gold",
+						}, oldApp.Spec.Routes[0].Headers)
+					})
+				appsfake.EXPECT().WaitForConditionRoutesReadyTrue(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			},
+		},
+		"transform App with stripped path prefix": {
+			Args:      []string{"some-app", "example.com", "--path", "/service", "--strip-path-prefix"},
+			Namespace: "some-space",
+			Setup: func(t *testing.T, appsfake *appsfake.FakeClient) {
+				appsfake.EXPECT().
+					Transform(gomock.Any(), gomock.Any(), gomock.Any()).
+					Do(func(_, _ string, m apps.Mutator) {
+						oldApp := v1alpha1.App{}
+						testutil.AssertNil(t, "err", m(&oldApp))
+
+						testutil.AssertEqual(t, "StripPathPrefix", true, oldApp.Spec.Routes[0].StripPathPrefix)
+						testutil.AssertEqual(t, "RewritePath", "", oldApp.Spec.Routes[0].RewritePath)
+					})
+				appsfake.EXPECT().WaitForConditionRoutesReadyTrue(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			},
+		},
+		"transform App with rewritten path": {
+			Args:      []string{"some-app", "example.com", "--path", "/service", "--rewrite-path", "v2"},
+			Namespace: "some-space",
+			Setup: func(t *testing.T, appsfake *appsfake.FakeClient) {
+				appsfake.EXPECT().
+					Transform(gomock.Any(), gomock.Any(), gomock.Any()).
+					Do(func(_, _ string, m apps.Mutator) {
+						oldApp := v1alpha1.App{}
+						testutil.AssertNil(t, "err", m(&oldApp))
+
+						testutil.AssertEqual(t, "RewritePath", "/v2", oldApp.Spec.Routes[0].RewritePath)
+					})
+				appsfake.EXPECT().WaitForConditionRoutesReadyTrue(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			},
+		},
+		"remapping replaces path rewrite": {
+			Args:      []string{"some-app", "example.com", "--path", "/service", "--rewrite-path", "/v2"},
+			Namespace: "some-space",
+			Setup: func(t *testing.T, appsfake *appsfake.FakeClient) {
+				appsfake.EXPECT().
+					Transform(gomock.Any(), gomock.Any(), gomock.Any()).
+					Do(func(_, _ string, m apps.Mutator) {
+						oldApp := v1alpha1.App{}
+						oldApp.Spec.Routes = []v1alpha1.RouteSpecFields{
+							{Domain: "example.com", Path: "/service", StripPathPrefix: true},
+						}
+						testutil.AssertNil(t, "err", m(&oldApp))
+
+						testutil.AssertEqual(t, "Routes", []v1alpha1.RouteSpecFields{
+							{Domain: "example.com", Path: "/service", RewritePath: "/v2"},
+						}, oldApp.Spec.Routes)
+					})
+				appsfake.EXPECT().WaitForConditionRoutesReadyTrue(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			},
+		},
+		"strip path prefix and rewrite path": {
+			Args:        []string{"some-app", "example.com", "--strip-path-prefix", "--rewrite-path", "/v2"},
+			Namespace:   "some-space",
+			ExpectedErr: errors.New("--strip-path-prefix and --rewrite-path can't be used together"),
+		},
+		"invalid header match": {
+			Args:        []string{"some-app", "example.com", "--match-header", "X-Beta"},
+			Namespace:   "some-space",
+			ExpectedErr: errors.New(`invalid header "X-Beta", must be in the form NAME: VALUE`),
+		},
 		"transform App and keep old routes": {
 			Args:      []string{"some-app", "example.com", "--hostname=some-host", "--path=some-path"},
 			Namespace: "some-space",
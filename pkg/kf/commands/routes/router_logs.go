@@ -0,0 +1,162 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/logs"
+	"github.com/google/kf/pkg/kf/routeclaims"
+	"github.com/spf13/cobra"
+)
+
+// NewRouterLogsCommand creates a RouterLogs command. The logs are read from
+// the ingress gateway so users don't need access to its namespace.
+func NewRouterLogsCommand(
+	p *config.KfParams,
+	c routeclaims.Client,
+	tailer logs.RouterTailer,
+) *cobra.Command {
+	var (
+		numberLines int
+		recent      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "router-logs [ROUTE]",
+		Short: "Tail or show the gateway's access logs for routes",
+		Long: `Tail or show requests served by the ingress gateway.
+
+		Each request is printed with its status, sizes, latency and the
+		upstream instance that served it, in the style of Cloud Foundry
+		router logs. If ROUTE isn't given, requests for all the routes in
+		the space are shown.
+
+		When following, only new requests are shown. With --recent the last
+		requests from the past hour are shown.
+		`,
+		Example: `
+		# Follow requests for all routes in the space
+		kf router-logs
+
+		# Follow requests for a single route
+		kf router-logs myapp.example.com/api
+
+		# Get the most recent 50 requests for a route
+		kf router-logs myapp.example.com --recent -n 50
+  `,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			var routes []v1alpha1.RouteSpecFields
+			if len(args) == 1 {
+				routeHost, urlPath := args[0], "/"
+				if i := strings.Index(routeHost, "/"); i >= 0 {
+					routeHost, urlPath = routeHost[:i], routeHost[i:]
+				}
+
+				routes = append(routes, v1alpha1.RouteSpecFields{
+					Domain: routeHost,
+					Path:   urlPath,
+				})
+			} else {
+				claims, err := c.List(p.Namespace)
+				if err != nil {
+					return fmt.Errorf("failed to list Routes: %s", err)
+				}
+
+				for _, claim := range claims {
+					routes = append(routes, claim.Spec.RouteSpecFields)
+				}
+
+				if len(routes) == 0 {
+					return fmt.Errorf("no Routes found in space %q", p.Namespace)
+				}
+			}
+
+			if err := tailer.Tail(
+				context.Background(),
+				routeFilter(routes),
+				cmd.OutOrStdout(),
+				logs.WithTailNamespace(logs.GatewayNamespace),
+				logs.WithTailNumberLines(numberLines),
+				logs.WithTailFollow(!recent),
+			); err != nil {
+				return fmt.Errorf("failed to tail router logs: %s", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(
+		&numberLines,
+		"number",
+		"n",
+		10,
+		"Show the last N requests when used with --recent.",
+	)
+
+	cmd.Flags().BoolVarP(
+		&recent,
+		"recent",
+		"",
+		false,
+		"Dump recent requests instead of tailing.",
+	)
+
+	return cmd
+}
+
+// routeFilter matches requests for any of the routes. Only the host and path
+// of the routes are checked.
+func routeFilter(routes []v1alpha1.RouteSpecFields) logs.RouterLogFilter {
+	return func(e logs.RouterLogEntry) bool {
+		host := strings.ToLower(e.Host())
+		reqPath := e.Path
+		if i := strings.IndexAny(reqPath, "?#"); i >= 0 {
+			reqPath = reqPath[:i]
+		}
+
+		for _, route := range routes {
+			routeHost := route.Domain
+			if route.Hostname != "" {
+				routeHost = route.Hostname + "." + route.Domain
+			}
+
+			if host != strings.ToLower(routeHost) {
+				continue
+			}
+
+			routePath := strings.TrimSuffix(path.Join("/", route.Path), "/")
+			if routePath == "" || reqPath == routePath || strings.HasPrefix(reqPath, routePath+"/") {
+				return true
+			}
+		}
+
+		return false
+	}
+}
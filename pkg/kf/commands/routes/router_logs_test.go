@@ -0,0 +1,184 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/commands/routes"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/logs"
+	fakelogs "github.com/google/kf/pkg/kf/logs/fake"
+	fakerouteclaims "github.com/google/kf/pkg/kf/routeclaims/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestRouterLogs(t *testing.T) {
+	t.Parallel()
+
+	entry := func(authority, path string) logs.RouterLogEntry {
+		return logs.RouterLogEntry{Authority: authority, Path: path}
+	}
+
+	// assertFilter checks which requests the filter passed to the tailer
+	// matches.
+	assertFilter := func(t *testing.T, matches map[logs.RouterLogEntry]bool) func(context.Context, logs.RouterLogFilter, io.Writer, ...logs.TailOption) {
+		return func(ctx context.Context, filter logs.RouterLogFilter, out io.Writer, opts ...logs.TailOption) {
+			for e, want := range matches {
+				testutil.AssertEqual(t, e.Authority+e.Path, want, filter(e))
+			}
+		}
+	}
+
+	for tn, tc := range map[string]struct {
+		Namespace string
+		Args      []string
+		Setup     func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient, fakeTailer *fakelogs.FakeRouterTailer)
+		Assert    func(t *testing.T, buffer *bytes.Buffer, err error)
+	}{
+		"too many args": {
+			Args:      []string{"a.example.com", "b.example.com"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("accepts at most 1 arg(s), received 2"), err)
+			},
+		},
+		"without namespace": {
+			Args: []string{"myapp.example.com"},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(utils.EmptyNamespaceError), err)
+			},
+		},
+		"single route": {
+			Args:      []string{"myapp.example.com/api", "--recent", "-n=50"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient, fakeTailer *fakelogs.FakeRouterTailer) {
+				fakeTailer.EXPECT().
+					Tail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Do(func(ctx context.Context, filter logs.RouterLogFilter, out io.Writer, opts ...logs.TailOption) {
+						testutil.AssertEqual(t, "namespace", "istio-system", logs.TailOptions(opts).Namespace())
+						testutil.AssertEqual(t, "number lines", 50, logs.TailOptions(opts).NumberLines())
+						testutil.AssertEqual(t, "follow", false, logs.TailOptions(opts).Follow())
+
+						assertFilter(t, map[logs.RouterLogEntry]bool{
+							entry("myapp.example.com", "/api"):          true,
+							entry("MyApp.example.com:80", "/api/users"): true,
+							entry("myapp.example.com", "/api?q=1"):      true,
+							entry("myapp.example.com", "/apiv2"):        false,
+							entry("myapp.example.com", "/"):             false,
+							entry("other.example.com", "/api"):          false,
+						})(ctx, filter, out, opts...)
+					})
+			},
+		},
+		"all routes in the space": {
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient, fakeTailer *fakelogs.FakeRouterTailer) {
+				fakeRouteClaims.EXPECT().
+					List("some-namespace").
+					Return([]v1alpha1.RouteClaim{
+						{Spec: v1alpha1.RouteClaimSpec{RouteSpecFields: v1alpha1.RouteSpecFields{Hostname: "myapp", Domain: "example.com"}}},
+						{Spec: v1alpha1.RouteClaimSpec{RouteSpecFields: v1alpha1.RouteSpecFields{Domain: "example.com", Path: "/docs"}}},
+					}, nil)
+
+				fakeTailer.EXPECT().
+					Tail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Do(func(ctx context.Context, filter logs.RouterLogFilter, out io.Writer, opts ...logs.TailOption) {
+						testutil.AssertEqual(t, "follow", true, logs.TailOptions(opts).Follow())
+
+						assertFilter(t, map[logs.RouterLogEntry]bool{
+							entry("myapp.example.com", "/"):       true,
+							entry("myapp.example.com", "/foo"):    true,
+							entry("example.com", "/docs/install"): true,
+							entry("example.com", "/"):             false,
+							entry("other.example.com", "/"):       false,
+						})(ctx, filter, out, opts...)
+					})
+			},
+		},
+		"no routes in the space": {
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient, fakeTailer *fakelogs.FakeRouterTailer) {
+				fakeRouteClaims.EXPECT().
+					List("some-namespace").
+					Return(nil, nil)
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(`no Routes found in space "some-namespace"`), err)
+			},
+		},
+		"listing routes fails": {
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient, fakeTailer *fakelogs.FakeRouterTailer) {
+				fakeRouteClaims.EXPECT().
+					List(gomock.Any()).
+					Return(nil, errors.New("some-error"))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("failed to list Routes: some-error"), err)
+			},
+		},
+		"tailer fails": {
+			Args:      []string{"myapp.example.com"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient, fakeTailer *fakelogs.FakeRouterTailer) {
+				fakeTailer.EXPECT().
+					Tail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("some-error"))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("failed to tail router logs: some-error"), err)
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeRouteClaims := fakerouteclaims.NewFakeClient(ctrl)
+			fakeTailer := fakelogs.NewFakeRouterTailer(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeRouteClaims, fakeTailer)
+			}
+
+			var buffer bytes.Buffer
+			cmd := routes.NewRouterLogsCommand(
+				&config.KfParams{
+					Namespace: tc.Namespace,
+				},
+				fakeRouteClaims,
+				fakeTailer,
+			)
+			cmd.SetArgs(tc.Args)
+			cmd.SetOutput(&buffer)
+
+			gotErr := cmd.Execute()
+
+			if tc.Assert != nil {
+				tc.Assert(t, &buffer, gotErr)
+			} else {
+				testutil.AssertNil(t, "err", gotErr)
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"strings"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/parallel"
 	"github.com/google/kf/pkg/kf/algorithms"
 	"github.com/google/kf/pkg/kf/apps"
 	"github.com/google/kf/pkg/kf/commands/config"
@@ -38,63 +39,173 @@ func NewRoutesCommand(
 	c routeclaims.Client,
 	a apps.Client,
 ) *cobra.Command {
-	return &cobra.Command{
+	var (
+		allSpaces utils.AllSpacesFlags
+		format    string
+	)
+
+	cmd := &cobra.Command{
 		Use:   "routes",
 		Short: "List routes in space",
+		Long: `List the routes in the targeted space and the apps they're mapped to.
+
+		Use --format dot to print the space's topology as a Graphviz graph with
+		domain, route, app and service nodes. The graph can be rendered with the
+		dot tool, for example: kf routes --format dot | dot -Tsvg > routes.svg
+		`,
 		Example: `
   kf routes
+  kf routes --all-spaces
+  kf routes --format dot | dot -Tpng > routes.png
   `,
 		Args: cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := utils.ValidateNamespace(p); err != nil {
+			if err := validateRoutesFormat(format); err != nil {
 				return err
 			}
 
-			cmd.SilenceUsage = true
+			namespace, err := allSpaces.Namespace(p)
+			if err != nil {
+				return err
+			}
 
-			fmt.Fprintf(cmd.OutOrStdout(), "Getting routes in space: %s\n", p.Namespace)
-			fmt.Fprintln(cmd.OutOrStdout())
+			cmd.SilenceUsage = true
 
-			routes, err := r.List(p.Namespace)
-			if err != nil {
-				return fmt.Errorf("failed to fetch Routes: %s", err)
+			// Graphs are only printed so they can be piped to other tools.
+			if format == formatTable {
+				fmt.Fprintf(cmd.OutOrStdout(), "Getting routes in %s\n", allSpaces.Description(p))
+				fmt.Fprintln(cmd.OutOrStdout())
 			}
 
-			routeClaims, err := c.List(p.Namespace)
-			if err != nil {
-				return fmt.Errorf("failed to fetch RouteClaims: %s", err)
+			var (
+				routes      []v1alpha1.Route
+				routeClaims []v1alpha1.RouteClaim
+				apps        []v1alpha1.App
+			)
+
+			if err := parallel.Do(
+				func() (err error) {
+					if routes, err = r.List(namespace); err != nil {
+						return fmt.Errorf("failed to fetch Routes: %s", err)
+					}
+					return nil
+				},
+				func() (err error) {
+					if routeClaims, err = c.List(namespace); err != nil {
+						return fmt.Errorf("failed to fetch RouteClaims: %s", err)
+					}
+					return nil
+				},
+				func() (err error) {
+					if apps, err = a.List(namespace); err != nil {
+						return fmt.Errorf("failed to fetch Apps: %s", err)
+					}
+					return nil
+				},
+			); err != nil {
+				return err
 			}
 
-			apps, err := a.List(p.Namespace)
-			if err != nil {
-				return fmt.Errorf("failed to fetch Apps: %s", err)
+			if format == formatDOT {
+				writeRoutesGraph(cmd.OutOrStdout(), groupBySpace(routes, routeClaims, apps))
+				return nil
 			}
 
 			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+				if allSpaces.IsAllSpaces() {
+					fmt.Fprint(w, "Space\t")
+				}
 				fmt.Fprintln(w, "Host\tDomain\tPath\tApps")
-				for _, route := range groupRoutes(routes, routeClaims) {
-					names := strings.Join(appNames(apps, route), ", ")
-					fmt.Fprintf(
-						w,
-						"%s\t%s\t%s\t%s\n",
-						route.Hostname,
-						route.Domain,
-						route.Path,
-						names,
-					)
+
+				// Routes are only bound to Apps in the same space, so each
+				// space is grouped separately.
+				for _, space := range groupBySpace(routes, routeClaims, apps) {
+					for _, route := range groupRoutes(space.routes, space.claims) {
+						if allSpaces.IsAllSpaces() {
+							fmt.Fprintf(w, "%s\t", space.name)
+						}
+
+						names := strings.Join(appNames(space.apps, route), ", ")
+						fmt.Fprintf(
+							w,
+							"%s\t%s\t%s\t%s\n",
+							route.Hostname,
+							route.Domain,
+							route.Path,
+							names,
+						)
+					}
 				}
 			})
 
 			return nil
 		},
 	}
+
+	allSpaces.Add(cmd)
+
+	cmd.Flags().StringVar(
+		&format,
+		"format",
+		formatTable,
+		fmt.Sprintf("Format to print routes in, one of: %s", strings.Join(routesFormats, ", ")),
+	)
+
+	return cmd
+}
+
+// spaceRoutes holds the route related objects of a single space.
+type spaceRoutes struct {
+	name   string
+	routes []v1alpha1.Route
+	claims []v1alpha1.RouteClaim
+	apps   []v1alpha1.App
+}
+
+// groupBySpace splits the objects by namespace and returns the spaces sorted
+// by name.
+func groupBySpace(
+	routes []v1alpha1.Route,
+	claims []v1alpha1.RouteClaim,
+	apps []v1alpha1.App,
+) []*spaceRoutes {
+	spaces := make(map[string]*spaceRoutes)
+	space := func(name string) *spaceRoutes {
+		if _, ok := spaces[name]; !ok {
+			spaces[name] = &spaceRoutes{name: name}
+		}
+		return spaces[name]
+	}
+
+	for _, r := range routes {
+		s := space(r.Namespace)
+		s.routes = append(s.routes, r)
+	}
+	for _, c := range claims {
+		s := space(c.Namespace)
+		s.claims = append(s.claims, c)
+	}
+	for _, a := range apps {
+		s := space(a.Namespace)
+		s.apps = append(s.apps, a)
+	}
+
+	var out []*spaceRoutes
+	for _, s := range spaces {
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].name < out[j].name
+	})
+
+	return out
 }
 
 func groupRoutes(
 	routes []v1alpha1.Route,
 	claims []v1alpha1.RouteClaim,
 ) []v1alpha1.RouteSpecFields {
-	var fields v1alpha1.RouteSpecFieldsSlice
+	var fields []v1alpha1.RouteSpecFields
 	for _, r := range routes {
 		fields = append(fields, r.Spec.RouteSpecFields)
 	}
@@ -102,12 +213,7 @@ func groupRoutes(
 		fields = append(fields, c.Spec.RouteSpecFields)
 	}
 
-	fields = algorithms.Dedupe(
-		v1alpha1.RouteSpecFieldsSlice(fields),
-	).(v1alpha1.RouteSpecFieldsSlice)
-	sort.Sort(fields)
-
-	return []v1alpha1.RouteSpecFields(fields)
+	return algorithms.Dedupe(fields, v1alpha1.CompareRouteSpecFields)
 }
 
 func appNames(apps []v1alpha1.App, route v1alpha1.RouteSpecFields) []string {
@@ -118,11 +224,7 @@ func appNames(apps []v1alpha1.App, route v1alpha1.RouteSpecFields) []string {
 		}
 
 		// Look to see if App already has Route
-		if !algorithms.Search(
-			0,
-			v1alpha1.RouteSpecFieldsSlice{route},
-			v1alpha1.RouteSpecFieldsSlice(app.Spec.Routes),
-		) {
+		if !algorithms.Contains(app.Spec.Routes, route, v1alpha1.CompareRouteSpecFields) {
 			continue
 		}
 
@@ -0,0 +1,124 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/kf/pkg/kf/algorithms"
+)
+
+const (
+	// formatTable prints routes as a table.
+	formatTable = "table"
+
+	// formatDOT prints routes as a Graphviz DOT graph.
+	formatDOT = "dot"
+)
+
+// routesFormats are the formats the routes command can print in.
+var routesFormats = []string{formatTable, formatDOT}
+
+// writeRoutesGraph writes the topology of the spaces as a Graphviz digraph
+// with domain -> route -> app -> service edges. Each space is drawn as a
+// cluster so the same names in different spaces stay separate.
+func writeRoutesGraph(w io.Writer, spaces []*spaceRoutes) {
+	fmt.Fprintln(w, "digraph routes {")
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box];")
+
+	for i, space := range spaces {
+		g := &graphWriter{w: w, space: space.name, seen: make(map[string]bool)}
+
+		fmt.Fprintf(w, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(w, "    label=%s;\n", dotQuote(space.name))
+
+		for _, route := range groupRoutes(space.routes, space.claims) {
+			domainID := g.node("domain", route.Domain, route.Domain, "ellipse")
+
+			label := route.String()
+			if headers := route.HeadersString(); headers != "" {
+				label += " [" + headers + "]"
+			}
+			routeID := g.node("route", label, label, "box")
+			g.edge(domainID, routeID)
+
+			for _, name := range appNames(space.apps, route) {
+				g.edge(routeID, g.node("app", name, name, "component"))
+			}
+		}
+
+		// Apps without routes are still shown if they're bound to services.
+		for _, app := range space.apps {
+			if app.GetDeletionTimestamp() != nil || len(app.Spec.ServiceBindings) == 0 {
+				continue
+			}
+
+			appID := g.node("app", app.Name, app.Name, "component")
+			for _, binding := range app.Spec.ServiceBindings {
+				g.edge(appID, g.node("service", binding.Instance, binding.Instance, "cylinder"))
+			}
+		}
+
+		fmt.Fprintln(w, "  }")
+	}
+
+	fmt.Fprintln(w, "}")
+}
+
+// graphWriter writes the nodes and edges of a single space, each is only
+// written once.
+type graphWriter struct {
+	w     io.Writer
+	space string
+	seen  map[string]bool
+}
+
+// node writes a node if it hasn't been written yet and returns its ID.
+func (g *graphWriter) node(kind, name, label, shape string) string {
+	id := dotQuote(strings.Join([]string{g.space, kind, name}, "/"))
+	if !g.seen[id] {
+		g.seen[id] = true
+		fmt.Fprintf(g.w, "    %s [label=%s, shape=%s];\n", id, dotQuote(label), shape)
+	}
+
+	return id
+}
+
+// edge writes an edge if it hasn't been written yet.
+func (g *graphWriter) edge(from, to string) {
+	key := from + " -> " + to
+	if !g.seen[key] {
+		g.seen[key] = true
+		fmt.Fprintf(g.w, "    %s;\n", key)
+	}
+}
+
+// dotQuote quotes the value as a DOT string.
+func dotQuote(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// validateRoutesFormat checks that the format is one routes can be printed
+// in.
+func validateRoutesFormat(format string) error {
+	if !algorithms.Contains(routesFormats, format, strings.Compare) {
+		return fmt.Errorf("unknown format %q, must be one of: %s", format, strings.Join(routesFormats, ", "))
+	}
+
+	return nil
+}
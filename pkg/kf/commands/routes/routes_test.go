@@ -130,6 +130,85 @@ func TestRoutes(t *testing.T) {
 				testutil.AssertContainsAll(t, buffer.String(), []string{"host-2", "example.com", "/path2", "app-2"})
 			},
 		},
+		"invalid format": {
+			Namespace:   "some-namespace",
+			Args:        []string{"--format", "svg"},
+			ExpectedErr: errors.New(`unknown format "svg", must be one of: table, dot`),
+		},
+		"dot format": {
+			Namespace: "some-namespace",
+			Args:      []string{"--format", "dot"},
+			Setup: func(t *testing.T, fakeRoute *fakeroutes.FakeClient, fakeRouteClaim *fakerouteclaims.FakeClient, fakeApp *fakeapps.FakeClient) {
+				route := buildRoute("host-1", "example.com", "/path1")
+				route.Namespace = "some-namespace"
+				claim := buildRouteClaim("", "example.com", "")
+				claim.Namespace = "some-namespace"
+
+				app := buildApp("app-1", "host-1", "example.com", "path1")
+				app.Namespace = "some-namespace"
+				app.Spec.ServiceBindings = []v1alpha1.AppSpecServiceBinding{{Instance: "db"}}
+
+				worker := buildApp("worker", "", "", "")
+				worker.Namespace = "some-namespace"
+				worker.Spec.Routes = nil
+				worker.Spec.ServiceBindings = []v1alpha1.AppSpecServiceBinding{{Instance: "db"}}
+
+				fakeRouteClaim.EXPECT().List(gomock.Any()).Return([]v1alpha1.RouteClaim{claim}, nil)
+				fakeRoute.EXPECT().List(gomock.Any()).Return([]v1alpha1.Route{route}, nil)
+				fakeApp.EXPECT().List(gomock.Any()).Return([]v1alpha1.App{app, worker}, nil)
+			},
+			BufferF: func(t *testing.T, buffer *bytes.Buffer) {
+				out := buffer.String()
+				if !strings.HasPrefix(out, "digraph routes {") {
+					t.Fatalf("expected the output to only contain the graph, got:\n%s", out)
+				}
+
+				testutil.AssertContainsAll(t, out, []string{
+					`label="some-namespace";`,
+					`"some-namespace/domain/example.com" [label="example.com", shape=ellipse];`,
+					`"some-namespace/domain/example.com" -> "some-namespace/route/host-1.example.com/path1";`,
+					`"some-namespace/domain/example.com" -> "some-namespace/route/example.com/";`,
+					`"some-namespace/route/host-1.example.com/path1" -> "some-namespace/app/app-1";`,
+					`"some-namespace/app/app-1" -> "some-namespace/service/db";`,
+					`"some-namespace/app/worker" -> "some-namespace/service/db";`,
+				})
+
+				// Shared nodes are only declared once.
+				testutil.AssertEqual(t, "domain nodes", 1, strings.Count(out, `"some-namespace/domain/example.com" [`))
+				testutil.AssertEqual(t, "service nodes", 1, strings.Count(out, `"some-namespace/service/db" [`))
+			},
+		},
+		"all spaces": {
+			Args: []string{"--all-spaces"},
+			Setup: func(t *testing.T, fakeRoute *fakeroutes.FakeClient, fakeRouteClaim *fakerouteclaims.FakeClient, fakeApp *fakeapps.FakeClient) {
+				routeA := buildRoute("host-1", "example.com", "/path1")
+				routeA.Namespace = "space-a"
+				routeB := buildRoute("host-1", "example.com", "/path1")
+				routeB.Namespace = "space-b"
+				app := buildApp("app-1", "host-1", "example.com", "path1")
+				app.Namespace = "space-b"
+
+				fakeRouteClaim.EXPECT().List("")
+				fakeRoute.EXPECT().List("").Return([]v1alpha1.Route{routeA, routeB}, nil)
+				fakeApp.EXPECT().List("").Return([]v1alpha1.App{app}, nil)
+			},
+			BufferF: func(t *testing.T, buffer *bytes.Buffer) {
+				testutil.AssertContainsAll(t, buffer.String(), []string{"Getting routes in all spaces", "Space"})
+
+				// Apps are only bound to routes in their own space.
+				lines := strings.Split(buffer.String(), "\n")
+				for _, line := range lines {
+					switch {
+					case strings.HasPrefix(line, "space-a"):
+						if strings.Contains(line, "app-1") {
+							t.Fatalf("expected app-1 to only be bound in space-b, got %q", line)
+						}
+					case strings.HasPrefix(line, "space-b"):
+						testutil.AssertContainsAll(t, line, []string{"host-1", "app-1"})
+					}
+				}
+			},
+		},
 	} {
 		t.Run(tn, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
@@ -0,0 +1,136 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/routeclaims"
+	"github.com/spf13/cobra"
+)
+
+// NewSetMaintenanceCommand creates a SetMaintenance command. Routes in
+// maintenance mode have all their traffic sent to a static page served by
+// the Kf maintenance server rather than their Apps.
+func NewSetMaintenanceCommand(
+	p *config.KfParams,
+	c routeclaims.Client,
+) *cobra.Command {
+	var (
+		hostname, urlPath, messageFile string
+		on, off                        bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-maintenance DOMAIN [--hostname HOSTNAME] [--path PATH] (--on [--message-file FILE] | --off)",
+		Short: "Serve a maintenance page for a route instead of its apps",
+		Long: `Puts a route into or takes it out of maintenance mode. While in
+		maintenance mode, all requests for the route get a 503 response with
+		a static HTML page rather than being sent to the apps mapped to it.
+
+		A custom page can be supplied with --message-file, otherwise a default
+		page is served.
+		`,
+		Example: `
+  kf set-maintenance example.com --hostname myapp --on
+  kf set-maintenance example.com --hostname myapp --on --message-file page.html
+  kf set-maintenance example.com --hostname myapp --off
+  `,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			if on == off {
+				return errors.New("exactly one of --on or --off must be set")
+			}
+
+			if off && messageFile != "" {
+				return errors.New("--message-file can only be used with --on")
+			}
+
+			domain := args[0]
+			cmd.SilenceUsage = true
+
+			var maintenance *v1alpha1.RouteMaintenance
+			if on {
+				maintenance = &v1alpha1.RouteMaintenance{}
+
+				if messageFile != "" {
+					page, err := ioutil.ReadFile(messageFile)
+					if err != nil {
+						return fmt.Errorf("failed to read message file: %s", err)
+					}
+					maintenance.Page = string(page)
+				}
+			}
+
+			name := v1alpha1.GenerateRouteClaimName(hostname, domain, urlPath)
+			if _, err := c.Transform(p.Namespace, name, func(claim *v1alpha1.RouteClaim) error {
+				claim.Spec.Maintenance = maintenance
+				return nil
+			}); err != nil {
+				return fmt.Errorf("failed to update Route: %s", err)
+			}
+
+			if on {
+				fmt.Fprintf(cmd.OutOrStdout(), "Maintenance mode enabled %s", utils.AsyncLogSuffix)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Maintenance mode disabled %s", utils.AsyncLogSuffix)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&hostname,
+		"hostname",
+		"",
+		"Hostname for the route",
+	)
+	cmd.Flags().StringVar(
+		&urlPath,
+		"path",
+		"",
+		"URL Path for the route",
+	)
+	cmd.Flags().BoolVar(
+		&on,
+		"on",
+		false,
+		"Put the route into maintenance mode",
+	)
+	cmd.Flags().BoolVar(
+		&off,
+		"off",
+		false,
+		"Take the route out of maintenance mode",
+	)
+	cmd.Flags().StringVar(
+		&messageFile,
+		"message-file",
+		"",
+		"Path to an HTML page to serve while in maintenance mode",
+	)
+
+	return cmd
+}
@@ -0,0 +1,187 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/commands/routes"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/routeclaims"
+	fakerouteclaims "github.com/google/kf/pkg/kf/routeclaims/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestSetMaintenance(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "set-maintenance")
+	testutil.AssertNil(t, "err", err)
+	defer os.RemoveAll(dir)
+
+	pagePath := filepath.Join(dir, "page.html")
+	testutil.AssertNil(t, "err", ioutil.WriteFile(pagePath, []byte("<h1>Back soon</h1>"), 0644))
+
+	expectedName := v1alpha1.GenerateRouteClaimName("some-hostname", "example.com", "/somepath")
+
+	// transformAndCheck runs the mutator against an existing claim and checks
+	// the resulting maintenance config.
+	transformAndCheck := func(t *testing.T, want *v1alpha1.RouteMaintenance) func(string, string, routeclaims.Mutator) (*v1alpha1.RouteClaim, error) {
+		return func(namespace, name string, m routeclaims.Mutator) (*v1alpha1.RouteClaim, error) {
+			claim := &v1alpha1.RouteClaim{
+				Spec: v1alpha1.RouteClaimSpec{
+					Maintenance: &v1alpha1.RouteMaintenance{Page: "old"},
+				},
+			}
+			testutil.AssertNil(t, "mutator err", m(claim))
+			testutil.AssertEqual(t, "maintenance", want, claim.Spec.Maintenance)
+			return claim, nil
+		}
+	}
+
+	for tn, tc := range map[string]struct {
+		Namespace string
+		Args      []string
+		Setup     func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient)
+		Assert    func(t *testing.T, buffer *bytes.Buffer, err error)
+	}{
+		"wrong number of args": {
+			Args:      []string{"example.com", "extra", "--on"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("accepts 1 arg(s), received 2"), err)
+			},
+		},
+		"without namespace": {
+			Args: []string{"example.com", "--on"},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(utils.EmptyNamespaceError), err)
+			},
+		},
+		"neither on nor off": {
+			Args:      []string{"example.com"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("exactly one of --on or --off must be set"), err)
+			},
+		},
+		"both on and off": {
+			Args:      []string{"example.com", "--on", "--off"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("exactly one of --on or --off must be set"), err)
+			},
+		},
+		"message file with off": {
+			Args:      []string{"example.com", "--off", "--message-file", pagePath},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("--message-file can only be used with --on"), err)
+			},
+		},
+		"missing message file": {
+			Args:      []string{"example.com", "--on", "--message-file", filepath.Join(dir, "missing.html")},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorContainsAll(t, err, []string{"failed to read message file"})
+			},
+		},
+		"transform fails": {
+			Args:      []string{"example.com", "--on"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("some-error"))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("failed to update Route: some-error"), err)
+			},
+		},
+		"on with default page": {
+			Args:      []string{"example.com", "--hostname=some-hostname", "--path=somepath", "--on"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform("some-namespace", expectedName, gomock.Any()).
+					DoAndReturn(transformAndCheck(t, &v1alpha1.RouteMaintenance{}))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertContainsAll(t, buffer.String(), []string{"Maintenance mode enabled"})
+			},
+		},
+		"on with custom page": {
+			Args:      []string{"example.com", "--hostname=some-hostname", "--path=somepath", "--on", "--message-file", pagePath},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform("some-namespace", expectedName, gomock.Any()).
+					DoAndReturn(transformAndCheck(t, &v1alpha1.RouteMaintenance{Page: "<h1>Back soon</h1>"}))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"off": {
+			Args:      []string{"example.com", "--hostname=some-hostname", "--path=somepath", "--off"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform("some-namespace", expectedName, gomock.Any()).
+					DoAndReturn(transformAndCheck(t, nil))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertContainsAll(t, buffer.String(), []string{"Maintenance mode disabled"})
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeRouteClaims := fakerouteclaims.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeRouteClaims)
+			}
+
+			var buffer bytes.Buffer
+			cmd := routes.NewSetMaintenanceCommand(
+				&config.KfParams{
+					Namespace: tc.Namespace,
+				},
+				fakeRouteClaims,
+			)
+			cmd.SetArgs(tc.Args)
+			cmd.SetOutput(&buffer)
+
+			gotErr := cmd.Execute()
+
+			if tc.Assert != nil {
+				tc.Assert(t, &buffer, gotErr)
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
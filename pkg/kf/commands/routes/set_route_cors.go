@@ -0,0 +1,182 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/routeclaims"
+	"github.com/spf13/cobra"
+)
+
+// NewSetRouteCORSCommand creates a SetRouteCORS command. The CORS policy
+// lets browsers on other origins, such as a separately hosted frontend, call
+// the apps mapped to the route.
+func NewSetRouteCORSCommand(
+	p *config.KfParams,
+	c routeclaims.Client,
+) *cobra.Command {
+	var (
+		hostname, urlPath string
+		origins           []string
+		methods           []string
+		allowHeaders      []string
+		exposeHeaders     []string
+		maxAge            time.Duration
+		allowCredentials  bool
+		clearPolicy       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-route-cors DOMAIN [--hostname HOSTNAME] [--path PATH] (--allow-origin ORIGIN... | --clear)",
+		Short: "Configure which origins browsers can call a route from",
+		Long: `Sets the Cross-Origin Resource Sharing (CORS) policy for a route.
+
+		The policy is enforced by the cluster's gateway, which answers
+		preflight requests and adds the CORS headers to responses, so apps
+		don't need to handle CORS themselves. Setting a policy replaces any
+		existing one, --clear removes it.
+		`,
+		Example: `
+  kf set-route-cors example.com --hostname api --allow-origin https://app.example.com --allow-methods GET,POST
+  kf set-route-cors example.com --hostname api --allow-origin '*' --max-age 1h
+  kf set-route-cors example.com --hostname api --clear
+  `,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			var policy *v1alpha1.RouteCORSPolicy
+			if clearPolicy {
+				if len(origins) > 0 || len(methods) > 0 || len(allowHeaders) > 0 ||
+					len(exposeHeaders) > 0 || maxAge != 0 || allowCredentials {
+					return errors.New("--clear can't be used with other CORS flags")
+				}
+			} else {
+				if len(origins) == 0 {
+					return errors.New("--allow-origin is required unless --clear is set")
+				}
+
+				if maxAge < 0 {
+					return fmt.Errorf("--max-age must not be negative, got %s", maxAge)
+				}
+
+				policy = &v1alpha1.RouteCORSPolicy{
+					AllowOrigins:     origins,
+					AllowMethods:     upperAll(methods),
+					AllowHeaders:     allowHeaders,
+					ExposeHeaders:    exposeHeaders,
+					MaxAgeSeconds:    int64(maxAge / time.Second),
+					AllowCredentials: allowCredentials,
+				}
+
+				if errs := policy.Validate(context.Background()); errs != nil {
+					return fmt.Errorf("invalid CORS policy: %s", errs.Error())
+				}
+			}
+
+			domain := args[0]
+			cmd.SilenceUsage = true
+
+			name := v1alpha1.GenerateRouteClaimName(hostname, domain, urlPath)
+			if _, err := c.Transform(p.Namespace, name, func(claim *v1alpha1.RouteClaim) error {
+				claim.Spec.CORSPolicy = policy
+				return nil
+			}); err != nil {
+				return fmt.Errorf("failed to update Route: %s", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Route CORS policy updated %s", utils.AsyncLogSuffix)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&hostname,
+		"hostname",
+		"",
+		"Hostname for the route",
+	)
+	cmd.Flags().StringVar(
+		&urlPath,
+		"path",
+		"",
+		"URL Path for the route",
+	)
+	cmd.Flags().StringArrayVar(
+		&origins,
+		"allow-origin",
+		nil,
+		"Origin allowed to call the route e.g. https://app.example.com, or * for any (can be repeated)",
+	)
+	cmd.Flags().StringSliceVar(
+		&methods,
+		"allow-methods",
+		nil,
+		"Comma separated HTTP methods allowed in requests",
+	)
+	cmd.Flags().StringSliceVar(
+		&allowHeaders,
+		"allow-headers",
+		nil,
+		"Comma separated HTTP headers allowed in requests",
+	)
+	cmd.Flags().StringSliceVar(
+		&exposeHeaders,
+		"expose-headers",
+		nil,
+		"Comma separated response headers browsers can read",
+	)
+	cmd.Flags().DurationVar(
+		&maxAge,
+		"max-age",
+		0,
+		"How long browsers can cache preflight responses",
+	)
+	cmd.Flags().BoolVar(
+		&allowCredentials,
+		"allow-credentials",
+		false,
+		"Allow requests to include credentials such as cookies",
+	)
+	cmd.Flags().BoolVar(
+		&clearPolicy,
+		"clear",
+		false,
+		"Remove the route's CORS policy",
+	)
+
+	return cmd
+}
+
+// upperAll returns a copy of values converted to uppercase.
+func upperAll(values []string) []string {
+	var out []string
+	for _, v := range values {
+		out = append(out, strings.ToUpper(strings.TrimSpace(v)))
+	}
+
+	return out
+}
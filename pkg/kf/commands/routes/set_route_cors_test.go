@@ -0,0 +1,173 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/commands/routes"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/routeclaims"
+	fakerouteclaims "github.com/google/kf/pkg/kf/routeclaims/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestSetRouteCORS(t *testing.T) {
+	t.Parallel()
+
+	expectedName := v1alpha1.GenerateRouteClaimName("some-hostname", "example.com", "/somepath")
+
+	// transformAndCheck runs the mutator against an existing claim and checks
+	// the resulting CORS policy.
+	transformAndCheck := func(t *testing.T, want *v1alpha1.RouteCORSPolicy) func(string, string, routeclaims.Mutator) (*v1alpha1.RouteClaim, error) {
+		return func(namespace, name string, m routeclaims.Mutator) (*v1alpha1.RouteClaim, error) {
+			claim := &v1alpha1.RouteClaim{
+				Spec: v1alpha1.RouteClaimSpec{
+					CORSPolicy: &v1alpha1.RouteCORSPolicy{AllowOrigins: []string{"https://old.example.com"}},
+				},
+			}
+			testutil.AssertNil(t, "mutator err", m(claim))
+			testutil.AssertEqual(t, "corsPolicy", want, claim.Spec.CORSPolicy)
+			return claim, nil
+		}
+	}
+
+	for tn, tc := range map[string]struct {
+		Namespace string
+		Args      []string
+		Setup     func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient)
+		Assert    func(t *testing.T, buffer *bytes.Buffer, err error)
+	}{
+		"wrong number of args": {
+			Args:      []string{"example.com", "extra", "--allow-origin=*"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("accepts 1 arg(s), received 2"), err)
+			},
+		},
+		"without namespace": {
+			Args: []string{"example.com", "--allow-origin=*"},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(utils.EmptyNamespaceError), err)
+			},
+		},
+		"missing origin": {
+			Args:      []string{"example.com", "--allow-methods=GET"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("--allow-origin is required unless --clear is set"), err)
+			},
+		},
+		"clear with other flags": {
+			Args:      []string{"example.com", "--clear", "--allow-origin=*"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("--clear can't be used with other CORS flags"), err)
+			},
+		},
+		"invalid origin": {
+			Args:      []string{"example.com", "--allow-origin=app.example.com"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorContainsAll(t, err, []string{"invalid CORS policy", "app.example.com"})
+			},
+		},
+		"transform fails": {
+			Args:      []string{"example.com", "--allow-origin=*"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("some-error"))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("failed to update Route: some-error"), err)
+			},
+		},
+		"sets policy": {
+			Args: []string{
+				"example.com",
+				"--hostname=some-hostname",
+				"--path=somepath",
+				"--allow-origin=https://app.example.com",
+				"--allow-origin=https://admin.example.com",
+				"--allow-methods=get,POST",
+				"--allow-headers=Authorization",
+				"--max-age=1h",
+				"--allow-credentials",
+			},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform("some-namespace", expectedName, gomock.Any()).
+					DoAndReturn(transformAndCheck(t, &v1alpha1.RouteCORSPolicy{
+						AllowOrigins:     []string{"https://app.example.com", "https://admin.example.com"},
+						AllowMethods:     []string{"GET", "POST"},
+						AllowHeaders:     []string{"Authorization"},
+						MaxAgeSeconds:    3600,
+						AllowCredentials: true,
+					}))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertContainsAll(t, buffer.String(), []string{"Route CORS policy updated"})
+			},
+		},
+		"clear": {
+			Args:      []string{"example.com", "--hostname=some-hostname", "--path=somepath", "--clear"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform("some-namespace", expectedName, gomock.Any()).
+					DoAndReturn(transformAndCheck(t, nil))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeRouteClaims := fakerouteclaims.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeRouteClaims)
+			}
+
+			var buffer bytes.Buffer
+			cmd := routes.NewSetRouteCORSCommand(
+				&config.KfParams{
+					Namespace: tc.Namespace,
+				},
+				fakeRouteClaims,
+			)
+			cmd.SetArgs(tc.Args)
+			cmd.SetOutput(&buffer)
+
+			gotErr := cmd.Execute()
+
+			if tc.Assert != nil {
+				tc.Assert(t, &buffer, gotErr)
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
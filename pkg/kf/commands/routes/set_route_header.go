@@ -0,0 +1,131 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/routeclaims"
+	"github.com/spf13/cobra"
+)
+
+// NewSetRouteHeaderCommand creates a SetRouteHeader command. Headers set on
+// a route override headers with the same name set for the whole space.
+func NewSetRouteHeaderCommand(
+	p *config.KfParams,
+	c routeclaims.Client,
+) *cobra.Command {
+	var (
+		hostname, urlPath string
+		unset             bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-route-header DOMAIN HEADER_NAME (HEADER_VALUE | --unset) [--hostname HOSTNAME] [--path PATH]",
+		Short: "Set a header on every response served for a route",
+		Long: `Sets a header on every response served for a route, e.g. to add
+		security headers such as X-Frame-Options.
+
+		The header is added by the cluster's gateway and replaces any header
+		with the same name sent by the app. Headers set on a route take
+		precedence over those set for the space with
+		kf configure-space set-response-header.
+		`,
+		Example: `
+  kf set-route-header example.com X-Frame-Options DENY --hostname admin
+  kf set-route-header example.com Cache-Control no-store --path /account
+  kf set-route-header example.com X-Frame-Options --hostname admin --unset
+  `,
+		Args: cobra.RangeArgs(2, 3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			domain := args[0]
+			name := http.CanonicalHeaderKey(args[1])
+
+			var value string
+			switch {
+			case unset && len(args) == 3:
+				return errors.New("HEADER_VALUE can't be used with --unset")
+			case !unset && len(args) == 2:
+				return errors.New("HEADER_VALUE is required unless --unset is set")
+			case !unset:
+				value = args[2]
+
+				headers := map[string]string{name: value}
+				if errs := v1alpha1.ValidateResponseHeaders(headers).ViaField("responseHeaders"); errs != nil {
+					return fmt.Errorf("invalid response header: %s", errs.Error())
+				}
+			}
+
+			cmd.SilenceUsage = true
+
+			claimName := v1alpha1.GenerateRouteClaimName(hostname, domain, urlPath)
+			if _, err := c.Transform(p.Namespace, claimName, func(claim *v1alpha1.RouteClaim) error {
+				for k := range claim.Spec.ResponseHeaders {
+					if strings.EqualFold(k, name) {
+						delete(claim.Spec.ResponseHeaders, k)
+					}
+				}
+
+				if unset {
+					return nil
+				}
+
+				if claim.Spec.ResponseHeaders == nil {
+					claim.Spec.ResponseHeaders = make(map[string]string)
+				}
+
+				claim.Spec.ResponseHeaders[name] = value
+				return nil
+			}); err != nil {
+				return fmt.Errorf("failed to update Route: %s", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Route response headers updated %s", utils.AsyncLogSuffix)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&hostname,
+		"hostname",
+		"",
+		"Hostname for the route",
+	)
+	cmd.Flags().StringVar(
+		&urlPath,
+		"path",
+		"",
+		"URL Path for the route",
+	)
+	cmd.Flags().BoolVar(
+		&unset,
+		"unset",
+		false,
+		"Stop setting the header on responses",
+	)
+
+	return cmd
+}
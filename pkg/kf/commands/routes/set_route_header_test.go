@@ -0,0 +1,165 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/commands/routes"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/routeclaims"
+	fakerouteclaims "github.com/google/kf/pkg/kf/routeclaims/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestSetRouteHeader(t *testing.T) {
+	t.Parallel()
+
+	expectedName := v1alpha1.GenerateRouteClaimName("some-hostname", "example.com", "/somepath")
+
+	// transformAndCheck runs the mutator against an existing claim and checks
+	// the resulting response headers.
+	transformAndCheck := func(t *testing.T, want map[string]string) func(string, string, routeclaims.Mutator) (*v1alpha1.RouteClaim, error) {
+		return func(namespace, name string, m routeclaims.Mutator) (*v1alpha1.RouteClaim, error) {
+			claim := &v1alpha1.RouteClaim{
+				Spec: v1alpha1.RouteClaimSpec{
+					ResponseHeaders: map[string]string{
+						"x-frame-options": "SAMEORIGIN",
+						"Cache-Control":   "no-store",
+					},
+				},
+			}
+			testutil.AssertNil(t, "mutator err", m(claim))
+			testutil.AssertEqual(t, "responseHeaders", want, claim.Spec.ResponseHeaders)
+			return claim, nil
+		}
+	}
+
+	for tn, tc := range map[string]struct {
+		Namespace string
+		Args      []string
+		Setup     func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient)
+		Assert    func(t *testing.T, buffer *bytes.Buffer, err error)
+	}{
+		"wrong number of args": {
+			Args:      []string{"example.com"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("accepts between 2 and 3 arg(s), received 1"), err)
+			},
+		},
+		"without namespace": {
+			Args: []string{"example.com", "X-Frame-Options", "DENY"},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(utils.EmptyNamespaceError), err)
+			},
+		},
+		"missing value": {
+			Args:      []string{"example.com", "X-Frame-Options"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("HEADER_VALUE is required unless --unset is set"), err)
+			},
+		},
+		"value with unset": {
+			Args:      []string{"example.com", "X-Frame-Options", "DENY", "--unset"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("HEADER_VALUE can't be used with --unset"), err)
+			},
+		},
+		"invalid name": {
+			Args:      []string{"example.com", "X Frame Options", "DENY"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorContainsAll(t, err, []string{"invalid response header", "X Frame Options"})
+			},
+		},
+		"transform fails": {
+			Args:      []string{"example.com", "X-Frame-Options", "DENY"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("some-error"))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("failed to update Route: some-error"), err)
+			},
+		},
+		"sets header": {
+			Args:      []string{"example.com", "X-Frame-Options", "DENY", "--hostname=some-hostname", "--path=somepath"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform("some-namespace", expectedName, gomock.Any()).
+					DoAndReturn(transformAndCheck(t, map[string]string{
+						"X-Frame-Options": "DENY",
+						"Cache-Control":   "no-store",
+					}))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertContainsAll(t, buffer.String(), []string{"Route response headers updated"})
+			},
+		},
+		"unsets header": {
+			Args:      []string{"example.com", "X-Frame-Options", "--hostname=some-hostname", "--path=somepath", "--unset"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform("some-namespace", expectedName, gomock.Any()).
+					DoAndReturn(transformAndCheck(t, map[string]string{
+						"Cache-Control": "no-store",
+					}))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeRouteClaims := fakerouteclaims.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeRouteClaims)
+			}
+
+			var buffer bytes.Buffer
+			cmd := routes.NewSetRouteHeaderCommand(
+				&config.KfParams{
+					Namespace: tc.Namespace,
+				},
+				fakeRouteClaims,
+			)
+			cmd.SetArgs(tc.Args)
+			cmd.SetOutput(&buffer)
+
+			gotErr := cmd.Execute()
+
+			if tc.Assert != nil {
+				tc.Assert(t, &buffer, gotErr)
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
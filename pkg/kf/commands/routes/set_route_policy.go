@@ -0,0 +1,122 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/routeclaims"
+	"github.com/spf13/cobra"
+)
+
+// stickySessionsNone is the --sticky-sessions value that turns session
+// affinity off.
+const stickySessionsNone = "none"
+
+// NewSetRoutePolicyCommand creates a SetRoutePolicy command. Route policies
+// control how requests for a route are balanced between the instances of
+// the Apps mapped to it.
+func NewSetRoutePolicyCommand(
+	p *config.KfParams,
+	c routeclaims.Client,
+) *cobra.Command {
+	var hostname, urlPath, stickySessions string
+
+	var validStickySessions []string
+	for _, t := range v1alpha1.SessionAffinityTypes() {
+		validStickySessions = append(validStickySessions, string(t))
+	}
+	validStickySessions = append(validStickySessions, stickySessionsNone)
+
+	cmd := &cobra.Command{
+		Use:   "set-route-policy DOMAIN [--hostname HOSTNAME] [--path PATH] --sticky-sessions TYPE",
+		Short: "Configure how requests for a route are balanced between app instances",
+		Long: `Sets the load balancing policy for a route.
+
+		--sticky-sessions pins each client to a single instance of the apps
+		mapped to the route, which is useful for apps that keep session state
+		in memory. With "cookie" the instance is chosen by a cookie set on the
+		first response; with "source-ip" it's chosen by the client's IP
+		address. "none" turns sticky sessions off.
+		`,
+		Example: `
+  kf set-route-policy example.com --hostname myapp --sticky-sessions cookie
+  kf set-route-policy example.com --hostname myapp --sticky-sessions source-ip
+  kf set-route-policy example.com --hostname myapp --sticky-sessions none
+  `,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			var affinity *v1alpha1.RouteSessionAffinity
+			switch stickySessions {
+			case stickySessionsNone:
+				// Leave affinity nil to clear it.
+			case string(v1alpha1.SessionAffinityCookie), string(v1alpha1.SessionAffinitySourceIP):
+				affinity = &v1alpha1.RouteSessionAffinity{
+					Type: v1alpha1.SessionAffinityType(stickySessions),
+				}
+			default:
+				return fmt.Errorf(
+					"invalid --sticky-sessions value %q, must be one of: %s",
+					stickySessions,
+					strings.Join(validStickySessions, ", "),
+				)
+			}
+
+			domain := args[0]
+			cmd.SilenceUsage = true
+
+			name := v1alpha1.GenerateRouteClaimName(hostname, domain, urlPath)
+			if _, err := c.Transform(p.Namespace, name, func(claim *v1alpha1.RouteClaim) error {
+				claim.Spec.SessionAffinity = affinity
+				return nil
+			}); err != nil {
+				return fmt.Errorf("failed to update Route: %s", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Route policy updated %s", utils.AsyncLogSuffix)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&hostname,
+		"hostname",
+		"",
+		"Hostname for the route",
+	)
+	cmd.Flags().StringVar(
+		&urlPath,
+		"path",
+		"",
+		"URL Path for the route",
+	)
+	cmd.Flags().StringVar(
+		&stickySessions,
+		"sticky-sessions",
+		"",
+		fmt.Sprintf("Session affinity for the route, one of: %s", strings.Join(validStickySessions, ", ")),
+	)
+
+	return cmd
+}
@@ -0,0 +1,162 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/commands/routes"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/routeclaims"
+	fakerouteclaims "github.com/google/kf/pkg/kf/routeclaims/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestSetRoutePolicy(t *testing.T) {
+	t.Parallel()
+
+	expectedName := v1alpha1.GenerateRouteClaimName("some-hostname", "example.com", "/somepath")
+
+	// transformAndCheck runs the mutator against an existing claim and checks
+	// the resulting session affinity.
+	transformAndCheck := func(t *testing.T, want *v1alpha1.RouteSessionAffinity) func(string, string, routeclaims.Mutator) (*v1alpha1.RouteClaim, error) {
+		return func(namespace, name string, m routeclaims.Mutator) (*v1alpha1.RouteClaim, error) {
+			claim := &v1alpha1.RouteClaim{
+				Spec: v1alpha1.RouteClaimSpec{
+					SessionAffinity: &v1alpha1.RouteSessionAffinity{Type: "old"},
+				},
+			}
+			testutil.AssertNil(t, "mutator err", m(claim))
+			testutil.AssertEqual(t, "sessionAffinity", want, claim.Spec.SessionAffinity)
+			return claim, nil
+		}
+	}
+
+	for tn, tc := range map[string]struct {
+		Namespace string
+		Args      []string
+		Setup     func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient)
+		Assert    func(t *testing.T, buffer *bytes.Buffer, err error)
+	}{
+		"wrong number of args": {
+			Args:      []string{"example.com", "extra", "--sticky-sessions=cookie"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("accepts 1 arg(s), received 2"), err)
+			},
+		},
+		"without namespace": {
+			Args: []string{"example.com", "--sticky-sessions=cookie"},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(utils.EmptyNamespaceError), err)
+			},
+		},
+		"missing sticky sessions": {
+			Args:      []string{"example.com"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(`invalid --sticky-sessions value "", must be one of: cookie, source-ip, none`), err)
+			},
+		},
+		"invalid sticky sessions": {
+			Args:      []string{"example.com", "--sticky-sessions=header"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(`invalid --sticky-sessions value "header", must be one of: cookie, source-ip, none`), err)
+			},
+		},
+		"transform fails": {
+			Args:      []string{"example.com", "--sticky-sessions=cookie"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("some-error"))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("failed to update Route: some-error"), err)
+			},
+		},
+		"cookie": {
+			Args:      []string{"example.com", "--hostname=some-hostname", "--path=somepath", "--sticky-sessions=cookie"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform("some-namespace", expectedName, gomock.Any()).
+					DoAndReturn(transformAndCheck(t, &v1alpha1.RouteSessionAffinity{Type: v1alpha1.SessionAffinityCookie}))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertContainsAll(t, buffer.String(), []string{"Route policy updated"})
+			},
+		},
+		"source-ip": {
+			Args:      []string{"example.com", "--hostname=some-hostname", "--path=somepath", "--sticky-sessions=source-ip"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform("some-namespace", expectedName, gomock.Any()).
+					DoAndReturn(transformAndCheck(t, &v1alpha1.RouteSessionAffinity{Type: v1alpha1.SessionAffinitySourceIP}))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+		"none": {
+			Args:      []string{"example.com", "--hostname=some-hostname", "--path=somepath", "--sticky-sessions=none"},
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeRouteClaims *fakerouteclaims.FakeClient) {
+				fakeRouteClaims.EXPECT().
+					Transform("some-namespace", expectedName, gomock.Any()).
+					DoAndReturn(transformAndCheck(t, nil))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeRouteClaims := fakerouteclaims.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeRouteClaims)
+			}
+
+			var buffer bytes.Buffer
+			cmd := routes.NewSetRoutePolicyCommand(
+				&config.KfParams{
+					Namespace: tc.Namespace,
+				},
+				fakeRouteClaims,
+			)
+			cmd.SetArgs(tc.Args)
+			cmd.SetOutput(&buffer)
+
+			gotErr := cmd.Execute()
+
+			if tc.Assert != nil {
+				tc.Assert(t, &buffer, gotErr)
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/kf/pkg/internal/parallel"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/describe"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/slos"
+	"github.com/spf13/cobra"
+)
+
+// NewSLOsCommand creates a SLOs command.
+func NewSLOsCommand(
+	p *config.KfParams,
+	c slos.Client,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "slos",
+		Short: "List route SLOs in the space and whether they're being met",
+		Long: `Lists the service level objectives in the space along with the
+		availability and 95th percentile latency of their routes over the last
+		5 minutes as reported by Prometheus.
+		`,
+		Example: `
+  kf slos
+  `,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Getting SLOs in space %s\n", p.Namespace)
+			fmt.Fprintln(cmd.OutOrStdout())
+
+			list, err := c.List(p.Namespace)
+			if err != nil {
+				return fmt.Errorf("failed to list SLOs: %s", err)
+			}
+
+			compliance := make([]*slos.Compliance, len(list))
+			var queries []func() error
+			for i := range list {
+				i := i
+				queries = append(queries, func() (err error) {
+					compliance[i], err = c.Compliance(p.Namespace, list[i])
+					return
+				})
+			}
+
+			if err := parallel.Do(queries...); err != nil {
+				return fmt.Errorf("failed to get SLO compliance: %s", err)
+			}
+
+			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+				fmt.Fprintln(w, "Route\tAvailability\tTarget\tLatency P95\tTarget\tStatus")
+				for i, slo := range list {
+					fmt.Fprintf(
+						w,
+						"%s\t%s\t%s\t%s\t%s\t%s\n",
+						slo.Route,
+						formatAvailability(compliance[i].Availability),
+						formatAvailabilityTarget(slo.Availability),
+						formatLatency(compliance[i].LatencyP95),
+						formatLatencyTarget(slo.LatencyP95),
+						compliance[i].Status(slo),
+					)
+				}
+			})
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func formatAvailability(availability *float64) string {
+	if availability == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.3f%%", *availability)
+}
+
+func formatAvailabilityTarget(target float64) string {
+	if target == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%g%%", target)
+}
+
+func formatLatency(latency *time.Duration) string {
+	if latency == nil {
+		return "-"
+	}
+	return latency.Round(time.Millisecond).String()
+}
+
+func formatLatencyTarget(target time.Duration) string {
+	if target == 0 {
+		return "-"
+	}
+	return target.String()
+}
@@ -0,0 +1,141 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/commands/routes"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/slos"
+	fakeslos "github.com/google/kf/pkg/kf/slos/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestSLOs(t *testing.T) {
+	t.Parallel()
+
+	meeting := slos.SLO{
+		Name:         "slo-a",
+		Route:        v1alpha1.RouteSpecFields{Hostname: "a", Domain: "example.com"},
+		Availability: 99.9,
+	}
+	missing := slos.SLO{
+		Name:       "slo-b",
+		Route:      v1alpha1.RouteSpecFields{Hostname: "b", Domain: "example.com", Path: "/api"},
+		LatencyP95: 300 * time.Millisecond,
+	}
+
+	availability := 99.95
+	latency := 412500 * time.Microsecond
+
+	for tn, tc := range map[string]struct {
+		Namespace string
+		Args      []string
+		Setup     func(t *testing.T, fakeSLOs *fakeslos.FakeClient)
+		Assert    func(t *testing.T, buffer *bytes.Buffer, err error)
+	}{
+		"wrong number of args": {
+			Args:      []string{"extra"},
+			Namespace: "some-namespace",
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("accepts 0 arg(s), received 1"), err)
+			},
+		},
+		"without namespace": {
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New(utils.EmptyNamespaceError), err)
+			},
+		},
+		"list fails": {
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeSLOs *fakeslos.FakeClient) {
+				fakeSLOs.EXPECT().List("some-namespace").Return(nil, errors.New("some-error"))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("failed to list SLOs: some-error"), err)
+			},
+		},
+		"compliance fails": {
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeSLOs *fakeslos.FakeClient) {
+				fakeSLOs.EXPECT().List("some-namespace").Return([]slos.SLO{meeting}, nil)
+				fakeSLOs.EXPECT().Compliance("some-namespace", meeting).Return(nil, errors.New("some-error"))
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertErrorContainsAll(t, err, []string{"failed to get SLO compliance", "some-error"})
+			},
+		},
+		"shows compliance": {
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, fakeSLOs *fakeslos.FakeClient) {
+				fakeSLOs.EXPECT().List("some-namespace").Return([]slos.SLO{meeting, missing}, nil)
+				fakeSLOs.EXPECT().Compliance("some-namespace", meeting).Return(&slos.Compliance{
+					Availability: &availability,
+				}, nil)
+				fakeSLOs.EXPECT().Compliance("some-namespace", missing).Return(&slos.Compliance{
+					Availability: &availability,
+					LatencyP95:   &latency,
+				}, nil)
+			},
+			Assert: func(t *testing.T, buffer *bytes.Buffer, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertContainsAll(t, buffer.String(), []string{
+					"a.example.com/",
+					"b.example.com/api",
+					"99.950%",
+					"99.9%",
+					"413ms",
+					"300ms",
+					slos.StatusOK,
+					slos.StatusViolated,
+				})
+			},
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSLOs := fakeslos.NewFakeClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeSLOs)
+			}
+
+			var buffer bytes.Buffer
+			cmd := routes.NewSLOsCommand(
+				&config.KfParams{
+					Namespace: tc.Namespace,
+				},
+				fakeSLOs,
+			)
+			cmd.SetArgs(tc.Args)
+			cmd.SetOutput(&buffer)
+
+			gotErr := cmd.Execute()
+
+			if tc.Assert != nil {
+				tc.Assert(t, &buffer, gotErr)
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
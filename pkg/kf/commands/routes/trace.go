@@ -0,0 +1,210 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/istio"
+	"github.com/google/kf/pkg/kf/tracing"
+	"github.com/spf13/cobra"
+)
+
+// tracePollInterval is how often the tracing backend is checked for the spans
+// of a traced request.
+const tracePollInterval = time.Second
+
+// TracingConfigLoader reads the tracing configuration, used to find the
+// backend traces are read from.
+type TracingConfigLoader func() (*tracing.Config, error)
+
+// NewTraceCommand creates a command that sends a traced request to a route
+// and prints the resulting span tree.
+func NewTraceCommand(
+	p *config.KfParams,
+	ingressLister istio.IngressLister,
+	loadTracingConfig TracingConfigLoader,
+) *cobra.Command {
+	var (
+		gateway, method, queryURL string
+		wait                      time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "trace ROUTE",
+		Short: "Send a traced request through the mesh and print its spans",
+		Long: `Sends a request for ROUTE through the gateway with B3 trace headers
+		set so the gateway, the app's sidecar and the app all record spans
+		for it.
+
+		If a tracing backend with a Zipkin v2 compatible API is configured
+		using the query-url key of the config-tracing ConfigMap, or passed
+		with --tracing-url, the spans are read back and printed as a tree
+		showing where the time was spent.
+		`,
+		Example: `
+  kf trace myapp.example.com
+  kf trace myapp.example.com/api/health --method HEAD
+  kf trace myapp.example.com --tracing-url http://localhost:9411
+  `,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			routeHost, urlPath := args[0], "/"
+			if i := strings.Index(routeHost, "/"); i >= 0 {
+				routeHost, urlPath = routeHost[:i], routeHost[i:]
+			}
+
+			cmd.SilenceUsage = true
+			w := cmd.OutOrStdout()
+
+			if gateway == "" {
+				fmt.Fprintln(w, "Autodetecting app gateway. Specify a custom gateway using the --gateway flag.")
+
+				ingress, err := istio.ExtractIngressFromList(ingressLister.ListIngresses())
+				if err != nil {
+					return err
+				}
+				gateway = ingress
+			}
+
+			traceID, err := randomHex(16)
+			if err != nil {
+				return err
+			}
+
+			spanID, err := randomHex(8)
+			if err != nil {
+				return err
+			}
+
+			req, err := http.NewRequest(method, fmt.Sprintf("http://%s%s", gateway, urlPath), nil)
+			if err != nil {
+				return err
+			}
+			req.Host = routeHost
+			req.Header.Set("X-B3-TraceId", traceID)
+			req.Header.Set("X-B3-SpanId", spanID)
+			req.Header.Set("X-B3-Sampled", "1")
+
+			client := &http.Client{
+				// Redirects are part of the response being traced, don't follow
+				// them into a new trace.
+				CheckRedirect: func(*http.Request, []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			}
+
+			start := time.Now()
+			resp, err := client.Do(req)
+			if err != nil {
+				return fmt.Errorf("request failed: %s", err)
+			}
+			resp.Body.Close()
+
+			fmt.Fprintf(w, "%s %s%s via %s\n", method, routeHost, urlPath, gateway)
+			fmt.Fprintf(w, "Response: %s in %s\n", resp.Status, time.Since(start).Round(time.Millisecond))
+			fmt.Fprintf(w, "Trace ID: %s\n", traceID)
+
+			if queryURL == "" {
+				// The tracing backend is optional, so a broken configuration is
+				// only a warning.
+				if cfg, err := loadTracingConfig(); err != nil {
+					fmt.Fprintf(cmd.ErrOrStderr(), "Warning: couldn't load the tracing configuration: %s\n", err)
+				} else if cfg != nil {
+					queryURL = cfg.QueryURL
+				}
+			}
+
+			if queryURL == "" {
+				fmt.Fprintln(w, "No tracing backend is configured, set --tracing-url to print the spans.")
+				return nil
+			}
+
+			deadline := time.Now().Add(wait)
+			for {
+				spans, err := tracing.FetchTrace(http.DefaultClient, queryURL, traceID)
+				switch {
+				case err == nil:
+					fmt.Fprintln(w)
+					tracing.WriteSpanTree(w, spans)
+					return nil
+
+				case err != tracing.ErrTraceNotFound:
+					return fmt.Errorf("failed to get trace: %s", err)
+
+				case time.Now().After(deadline):
+					return fmt.Errorf("trace %s wasn't found in %s, it may not have been sampled", traceID, queryURL)
+				}
+
+				time.Sleep(tracePollInterval)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&gateway,
+		"gateway",
+		"",
+		"HTTP gateway to send the request to (default: autodetected from cluster)",
+	)
+
+	cmd.Flags().StringVar(
+		&method,
+		"method",
+		http.MethodGet,
+		"HTTP method of the request",
+	)
+
+	cmd.Flags().StringVar(
+		&queryURL,
+		"tracing-url",
+		"",
+		"Base URL of a Zipkin v2 compatible API to read the trace from (default: from the cluster's tracing configuration)",
+	)
+
+	cmd.Flags().DurationVar(
+		&wait,
+		"wait",
+		10*time.Second,
+		"How long to wait for the spans to be reported",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.AppCompletion)
+
+	return cmd
+}
+
+// randomHex returns n random bytes encoded as hex, used for trace and span
+// IDs.
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("couldn't generate trace ID: %s", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package routes_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/commands/routes"
+	"github.com/google/kf/pkg/kf/istio/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/google/kf/pkg/kf/tracing"
+)
+
+func TestNewTraceCommand(t *testing.T) {
+	t.Parallel()
+
+	// The gateway records the trace ID of the last request so the fake
+	// tracing backend can return spans for it.
+	var (
+		mu      sync.Mutex
+		traceID string
+	)
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Host != "myhost.example.com" || r.Header.Get("X-B3-Sampled") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		traceID = r.Header.Get("X-B3-TraceId")
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer gateway.Close()
+	gatewayHost := strings.TrimPrefix(gateway.URL, "http://")
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.URL.Path != "/api/v2/trace/"+traceID {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		fmt.Fprintf(w, `[
+			{"traceId":%[1]q,"id":"a","name":"ingress","duration":2000,"localEndpoint":{"serviceName":"istio-ingressgateway"}},
+			{"traceId":%[1]q,"id":"b","parentId":"a","name":"inbound","duration":1000,"localEndpoint":{"serviceName":"myapp"}}
+		]`, traceID)
+	}))
+	defer backend.Close()
+
+	emptyBackend := httptest.NewServer(http.NotFoundHandler())
+	defer emptyBackend.Close()
+
+	cases := map[string]struct {
+		Namespace       string
+		Args            []string
+		TracingConfig   *tracing.Config
+		TracingErr      error
+		ExpectedStrings []string
+		ExpectedErr     error
+		Setup           func(t *testing.T, istio *fake.FakeIstioClient)
+	}{
+		"no route": {
+			Namespace:   "default",
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"autodetect failure": {
+			Namespace:   "default",
+			Args:        []string{"myhost.example.com"},
+			ExpectedErr: errors.New("istio-failure"),
+			Setup: func(t *testing.T, istio *fake.FakeIstioClient) {
+				istio.EXPECT().ListIngresses(gomock.Any()).Return(nil, errors.New("istio-failure"))
+			},
+		},
+		"no tracing backend": {
+			Namespace:     "default",
+			Args:          []string{"myhost.example.com/some/path", "--gateway", gatewayHost},
+			TracingConfig: &tracing.Config{},
+			ExpectedStrings: []string{
+				"GET myhost.example.com/some/path via " + gatewayHost,
+				"418 I'm a teapot",
+				"Trace ID:",
+				"No tracing backend is configured",
+			},
+		},
+		"tracing config fails": {
+			Namespace:  "default",
+			Args:       []string{"myhost.example.com", "--gateway", gatewayHost},
+			TracingErr: errors.New("some-error"),
+			ExpectedStrings: []string{
+				"Warning: couldn't load the tracing configuration: some-error",
+				"No tracing backend is configured",
+			},
+		},
+		"backend from config": {
+			Namespace:     "default",
+			Args:          []string{"myhost.example.com", "--gateway", gatewayHost},
+			TracingConfig: &tracing.Config{QueryURL: backend.URL},
+			ExpectedStrings: []string{
+				"istio-ingressgateway ingress 2ms",
+				"  myapp inbound 1ms",
+			},
+		},
+		"backend from flag": {
+			Namespace: "default",
+			Args:      []string{"myhost.example.com", "--gateway", gatewayHost, "--tracing-url", backend.URL},
+			ExpectedStrings: []string{
+				"istio-ingressgateway ingress 2ms",
+			},
+		},
+		"trace not found": {
+			Namespace:   "default",
+			Args:        []string{"myhost.example.com", "--gateway", gatewayHost, "--tracing-url", emptyBackend.URL, "--wait", "0s"},
+			ExpectedErr: errors.New("it may not have been sampled"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeIstio := fake.NewFakeIstioClient(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeIstio)
+			}
+
+			buf := new(bytes.Buffer)
+			p := &config.KfParams{
+				Namespace: tc.Namespace,
+			}
+
+			cmd := routes.NewTraceCommand(p, fakeIstio, func() (*tracing.Config, error) {
+				return tc.TracingConfig, tc.TracingErr
+			})
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil {
+				testutil.AssertErrorContainsAll(t, actualErr, []string{tc.ExpectedErr.Error()})
+				return
+			}
+
+			testutil.AssertNil(t, "err", actualErr)
+
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+			testutil.AssertEqual(t, "SilenceUsage", true, cmd.SilenceUsage)
+
+			ctrl.Finish()
+		})
+	}
+}
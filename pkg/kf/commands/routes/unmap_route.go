@@ -36,14 +36,16 @@ func NewUnmapRouteCommand(
 ) *cobra.Command {
 	var async utils.AsyncFlags
 	var hostname, urlPath string
+	var headers []string
 
 	cmd := &cobra.Command{
-		Use:   "unmap-route APP_NAME DOMAIN [--hostname HOSTNAME] [--path PATH]",
+		Use:   "unmap-route APP_NAME DOMAIN [--hostname HOSTNAME] [--path PATH] [--match-header HEADER]",
 		Short: "Unmap a route from an app",
 		Example: `
   kf unmap-route myapp example.com --hostname myapp # myapp.example.com
   kf unmap-route --namespace myspace myapp example.com --hostname myapp # myapp.example.com
   kf unmap-route myapp example.com --hostname myapp --path /mypath # myapp.example.com/mypath
+  kf unmap-route myapp-beta example.com --hostname myapp --match-header "X-Beta: true"
   `,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -54,10 +56,16 @@ func NewUnmapRouteCommand(
 			}
 			appName, domain := args[0], args[1]
 
+			headerMatches, err := parseHeaderMatches(headers)
+			if err != nil {
+				return err
+			}
+
 			route := v1alpha1.RouteSpecFields{
 				Hostname: hostname,
 				Domain:   domain,
 				Path:     path.Join("/", urlPath),
+				Headers:  headerMatches,
 			}
 
 			if err := unmapApp(p.Namespace, appName, route, appsClient, cmd.OutOrStdout()); err != nil {
@@ -86,6 +94,7 @@ func NewUnmapRouteCommand(
 		"",
 		"URL Path for the route",
 	)
+	addMatchHeaderFlag(cmd, &headers)
 
 	return cmd
 }
@@ -99,19 +108,15 @@ func unmapApp(
 ) error {
 	mutator := apps.Mutator(func(app *v1alpha1.App) error {
 		// Ensure the App has the Route, if not return an error.
-		if !algorithms.Search(
-			0,
-			v1alpha1.RouteSpecFieldsSlice{route},
-			v1alpha1.RouteSpecFieldsSlice(app.Spec.Routes),
-		) {
+		if !algorithms.Contains(app.Spec.Routes, route, v1alpha1.CompareRouteSpecFields) {
 			return fmt.Errorf("App %s not found", app.Name)
 		}
 
-		app.Spec.Routes = []v1alpha1.RouteSpecFields(
-			(algorithms.Delete(
-				v1alpha1.RouteSpecFieldsSlice(app.Spec.Routes),
-				v1alpha1.RouteSpecFieldsSlice{route},
-			)).(v1alpha1.RouteSpecFieldsSlice))
+		app.Spec.Routes = algorithms.Delete(
+			app.Spec.Routes,
+			[]v1alpha1.RouteSpecFields{route},
+			v1alpha1.CompareRouteSpecFields,
+		)
 		return nil
 	})
 
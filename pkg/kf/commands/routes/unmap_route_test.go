@@ -122,6 +122,25 @@ func TestUnmapRoute(t *testing.T) {
 				fake.EXPECT().WaitForConditionRoutesReadyTrue(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
 			},
 		},
+		"remove only the header route": {
+			Args:      []string{"some-app", "example.com", "--hostname=some-host", "--path=some-path", "--match-header", "X-Beta: true"},
+			Namespace: "some-space",
+			Setup: func(t *testing.T, fake *fake.FakeClient) {
+				fake.EXPECT().Transform(gomock.Any(), gomock.Any(), gomock.Any()).
+					Do(func(_, _ string, m apps.Mutator) {
+						app := v1alpha1.App{}
+						app.Spec.Routes = []v1alpha1.RouteSpecFields{
+							{Hostname: "some-host", Domain: "example.com", Path: "some-path"},
+							{Hostname: "some-host", Domain: "example.com", Path: "some-path", Headers: map[string]string{"x-beta": "true"}},
+						}
+
+						testutil.AssertNil(t, "err", m(&app))
+						testutil.AssertEqual(t, "len", 1, len(app.Spec.Routes))
+						testutil.AssertEqual(t, "headers", map[string]string(nil), app.Spec.Routes[0].Headers)
+					})
+				fake.EXPECT().WaitForConditionRoutesReadyTrue(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any())
+			},
+		},
 	} {
 		t.Run(tn, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
@@ -0,0 +1,163 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// SecretLabel marks Secrets created with kf create-secret so they can be
+	// told apart from Secrets Kf manages on its own.
+	SecretLabel = "kf.dev/secret"
+)
+
+// secretSelector selects the Secrets created with kf create-secret.
+func secretSelector() string {
+	return fmt.Sprintf("%s=kf,%s=true", v1alpha1.ManagedByLabel, SecretLabel)
+}
+
+// NewCreateSecretCommand creates a command that creates an opaque Secret in
+// the targeted space.
+func NewCreateSecretCommand(p *config.KfParams, secrets typedcorev1.SecretsGetter) *cobra.Command {
+	var (
+		literals  []string
+		fromFiles []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "create-secret SECRET_NAME [--literal KEY=VALUE]... [--from-file [KEY=]PATH]...",
+		Short: "Create a Secret in the targeted space",
+		Long: `Create an opaque Secret in the targeted space.
+
+		Secrets can be read by Apps with kf set-env --from-secret and by
+		buildpack builds with kf configure-space set-buildpack-env-from-secret.
+
+		Values given with --from-file are read from the file, the key defaults
+		to the file's name.
+		`,
+		Example: `
+		kf create-secret db-creds --literal username=admin --literal password=s3cr3t
+		kf create-secret tls-creds --from-file ca.crt --from-file key=./tls.key
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			name := args[0]
+			if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+				return fmt.Errorf("invalid Secret name %q: %s", name, strings.Join(errs, ", "))
+			}
+
+			if len(literals) == 0 && len(fromFiles) == 0 {
+				return errors.New("at least one --literal or --from-file is required")
+			}
+
+			data := make(map[string][]byte)
+			for _, literal := range literals {
+				parts := strings.SplitN(literal, "=", 2)
+				if len(parts) != 2 {
+					return fmt.Errorf("invalid --literal %q, must be in the form KEY=VALUE", literal)
+				}
+
+				if err := addSecretData(data, parts[0], []byte(parts[1])); err != nil {
+					return err
+				}
+			}
+
+			for _, fromFile := range fromFiles {
+				key, path := filepath.Base(fromFile), fromFile
+				if parts := strings.SplitN(fromFile, "=", 2); len(parts) == 2 {
+					key, path = parts[0], parts[1]
+				}
+
+				contents, err := ioutil.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("couldn't read --from-file %q: %v", fromFile, err)
+				}
+
+				if err := addSecretData(data, key, contents); err != nil {
+					return err
+				}
+			}
+
+			cmd.SilenceUsage = true
+
+			_, err := secrets.Secrets(p.Namespace).Create(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: p.Namespace,
+					Labels: map[string]string{
+						v1alpha1.ManagedByLabel: "kf",
+						SecretLabel:             "true",
+					},
+				},
+				Type: corev1.SecretTypeOpaque,
+				Data: data,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to create Secret: %s", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Secret %q created in space %q\n", name, p.Namespace)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(
+		&literals,
+		"literal",
+		nil,
+		"Key and value to store in the Secret in the form KEY=VALUE, may be repeated.",
+	)
+
+	cmd.Flags().StringArrayVar(
+		&fromFiles,
+		"from-file",
+		nil,
+		"File to store in the Secret in the form [KEY=]PATH, may be repeated.",
+	)
+
+	return cmd
+}
+
+// addSecretData validates the key and adds it to the Secret's data.
+func addSecretData(data map[string][]byte, key string, value []byte) error {
+	if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+		return fmt.Errorf("invalid Secret key %q: %s", key, strings.Join(errs, ", "))
+	}
+
+	if _, ok := data[key]; ok {
+		return fmt.Errorf("secret key %q is set more than once", key)
+	}
+
+	data[key] = value
+	return nil
+}
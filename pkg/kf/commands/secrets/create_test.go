@@ -0,0 +1,133 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewCreateSecretCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "create-secret")
+	testutil.AssertNil(t, "err", err)
+	defer os.RemoveAll(dir)
+
+	certPath := filepath.Join(dir, "ca.crt")
+	testutil.AssertNil(t, "err", ioutil.WriteFile(certPath, []byte("cert-data"), 0600))
+
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "db-creds", Namespace: "my-space"},
+	}
+
+	cases := map[string]struct {
+		Namespace       string
+		Args            []string
+		Secrets         []runtime.Object
+		ExpectedStrings []string
+		ExpectedErr     error
+		ExpectedData    map[string][]byte
+	}{
+		"wrong number of params": {
+			Namespace:   "my-space",
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"namespace is not provided": {
+			Args:        []string{"db-creds", "--literal", "password=s3cr3t"},
+			ExpectedErr: errors.New(utils.EmptyNamespaceError),
+		},
+		"no data": {
+			Namespace:   "my-space",
+			Args:        []string{"db-creds"},
+			ExpectedErr: errors.New("at least one --literal or --from-file is required"),
+		},
+		"invalid literal": {
+			Namespace:   "my-space",
+			Args:        []string{"db-creds", "--literal", "password"},
+			ExpectedErr: errors.New(`invalid --literal "password", must be in the form KEY=VALUE`),
+		},
+		"duplicate key": {
+			Namespace:   "my-space",
+			Args:        []string{"db-creds", "--literal", "password=a", "--literal", "password=b"},
+			ExpectedErr: errors.New(`secret key "password" is set more than once`),
+		},
+		"missing file": {
+			Namespace:   "my-space",
+			Args:        []string{"db-creds", "--from-file", filepath.Join(dir, "missing")},
+			ExpectedErr: errors.New(`couldn't read --from-file "` + filepath.Join(dir, "missing") + `": open ` + filepath.Join(dir, "missing") + `: no such file or directory`),
+		},
+		"already exists": {
+			Namespace:   "my-space",
+			Args:        []string{"db-creds", "--literal", "password=s3cr3t"},
+			Secrets:     []runtime.Object{existing},
+			ExpectedErr: errors.New(`failed to create Secret: secrets "db-creds" already exists`),
+		},
+		"creates secret": {
+			Namespace:       "my-space",
+			Args:            []string{"db-creds", "--literal", "password=s3cr3t", "--literal", "url=postgres://db?a=b", "--from-file", certPath, "--from-file", "custom.crt=" + certPath},
+			ExpectedStrings: []string{`Secret "db-creds" created in space "my-space"`},
+			ExpectedData: map[string][]byte{
+				"password":   []byte("s3cr3t"),
+				"url":        []byte("postgres://db?a=b"),
+				"ca.crt":     []byte("cert-data"),
+				"custom.crt": []byte("cert-data"),
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			k8sClient := k8sfake.NewSimpleClientset(tc.Secrets...).CoreV1()
+
+			buf := new(bytes.Buffer)
+			p := &config.KfParams{
+				Namespace: tc.Namespace,
+			}
+
+			cmd := NewCreateSecretCommand(p, k8sClient)
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+
+			secret, err := k8sClient.Secrets("my-space").Get("db-creds", metav1.GetOptions{})
+			testutil.AssertNil(t, "get secret err", err)
+			testutil.AssertEqual(t, "data", tc.ExpectedData, secret.Data)
+			testutil.AssertEqual(t, "type", corev1.SecretTypeOpaque, secret.Type)
+			testutil.AssertEqual(t, "labels", map[string]string{
+				v1alpha1.ManagedByLabel: "kf",
+				SecretLabel:             "true",
+			}, secret.Labels)
+		})
+	}
+}
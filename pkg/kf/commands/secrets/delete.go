@@ -0,0 +1,70 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// NewDeleteSecretCommand creates a command that deletes a Secret created with
+// kf create-secret from the targeted space.
+func NewDeleteSecretCommand(p *config.KfParams, secrets typedcorev1.SecretsGetter) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delete-secret SECRET_NAME",
+		Short: "Delete a Secret from the targeted space",
+		Long: `Delete a Secret created with kf create-secret from the targeted space.
+
+		Secrets Kf manages on its own or that were created with other tools
+		aren't deleted. Apps reading the Secret fail to start until the
+		reference to it is removed.
+		`,
+		Example: `kf delete-secret db-creds`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			name := args[0]
+
+			cmd.SilenceUsage = true
+
+			client := secrets.Secrets(p.Namespace)
+			secret, err := client.Get(name, metav1.GetOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to get Secret: %s", err)
+			}
+
+			if secret.Labels[SecretLabel] != "true" {
+				return fmt.Errorf("secret %q wasn't created with kf create-secret, delete it with kubectl instead", name)
+			}
+
+			if err := client.Delete(name, &metav1.DeleteOptions{}); err != nil {
+				return fmt.Errorf("failed to delete Secret: %s", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Secret %q deleted from space %q\n", name, p.Namespace)
+			return nil
+		},
+	}
+
+	return cmd
+}
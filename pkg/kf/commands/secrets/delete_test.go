@@ -0,0 +1,106 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewDeleteSecretCommand(t *testing.T) {
+	managed := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-creds",
+			Namespace: "my-space",
+			Labels: map[string]string{
+				v1alpha1.ManagedByLabel: "kf",
+				SecretLabel:             "true",
+			},
+		},
+	}
+
+	unmanaged := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-ci-trigger", Namespace: "my-space"},
+	}
+
+	cases := map[string]struct {
+		Namespace       string
+		Args            []string
+		ExpectedStrings []string
+		ExpectedErr     error
+		ExpectDeleted   bool
+	}{
+		"wrong number of params": {
+			Namespace:   "my-space",
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"namespace is not provided": {
+			Args:        []string{"db-creds"},
+			ExpectedErr: errors.New(utils.EmptyNamespaceError),
+		},
+		"missing secret": {
+			Namespace:   "my-space",
+			Args:        []string{"missing"},
+			ExpectedErr: errors.New(`failed to get Secret: secrets "missing" not found`),
+		},
+		"secret not created by kf": {
+			Namespace:   "my-space",
+			Args:        []string{"my-app-ci-trigger"},
+			ExpectedErr: errors.New(`secret "my-app-ci-trigger" wasn't created with kf create-secret, delete it with kubectl instead`),
+		},
+		"deletes secret": {
+			Namespace:       "my-space",
+			Args:            []string{"db-creds"},
+			ExpectedStrings: []string{`Secret "db-creds" deleted from space "my-space"`},
+			ExpectDeleted:   true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			k8sClient := k8sfake.NewSimpleClientset(managed, unmanaged).CoreV1()
+
+			buf := new(bytes.Buffer)
+			p := &config.KfParams{
+				Namespace: tc.Namespace,
+			}
+
+			cmd := NewDeleteSecretCommand(p, k8sClient)
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+
+			_, err := k8sClient.Secrets("my-space").Get("db-creds", metav1.GetOptions{})
+			testutil.AssertEqual(t, "deleted", tc.ExpectDeleted, apierrs.IsNotFound(err))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/describe"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta/table"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// NewListSecretsCommand creates a command that lists the Secrets created with
+// kf create-secret in the targeted space.
+func NewListSecretsCommand(p *config.KfParams, secrets typedcorev1.SecretsGetter) *cobra.Command {
+	var listFlags utils.ListFlags
+
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "List Secrets in the targeted space",
+		Long: `List the Secrets created with kf create-secret in the targeted space.
+
+		Only the names of the keys are printed, use kubectl to read the values.
+		Secrets don't have a status so --sort-by status orders them by name.
+		`,
+		Example: `
+		kf secrets
+		kf secrets --filter db- --sort-by age
+		kf secrets --limit 50
+		`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := listFlags.Validate(); err != nil {
+				return err
+			}
+
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			list, err := secrets.Secrets(p.Namespace).List(metav1.ListOptions{
+				LabelSelector: secretSelector(),
+				Limit:         listFlags.Limit(),
+				Continue:      listFlags.ContinueToken(),
+			})
+			if err != nil {
+				return fmt.Errorf("failed to list Secrets: %s", err)
+			}
+
+			var items []corev1.Secret
+			for _, secret := range list.Items {
+				if listFlags.MatchesName(secret.Name) {
+					items = append(items, secret)
+				}
+			}
+
+			listFlags.Sort(items, func(i int) utils.ListSortKey {
+				return utils.ListSortKey{
+					Name:    items[i].Name,
+					Created: items[i].CreationTimestamp.Time,
+				}
+			})
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Getting Secrets in space: %s\n\n", p.Namespace)
+
+			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+				fmt.Fprintln(w, "Name\tKeys\tAge")
+				for _, secret := range items {
+					var keys []string
+					for key := range secret.Data {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+
+					fmt.Fprintf(w, "%s\t%s\t%s\n",
+						secret.Name,
+						strings.Join(keys, ", "),
+						table.ConvertToHumanReadableDateType(secret.CreationTimestamp),
+					)
+				}
+			})
+
+			listFlags.PrintNextPage(cmd.OutOrStdout(), list.Continue)
+
+			return nil
+		},
+	}
+
+	listFlags.Add(cmd)
+
+	return cmd
+}
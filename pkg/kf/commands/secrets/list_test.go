@@ -0,0 +1,117 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewListSecretsCommand(t *testing.T) {
+	managed := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "db-creds",
+			Namespace: "my-space",
+			Labels: map[string]string{
+				v1alpha1.ManagedByLabel: "kf",
+				SecretLabel:             "true",
+			},
+		},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("s3cr3t"),
+		},
+	}
+
+	unmanaged := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-ci-trigger", Namespace: "my-space"},
+	}
+
+	cases := map[string]struct {
+		Namespace          string
+		Args               []string
+		Secrets            []runtime.Object
+		ExpectedStrings    []string
+		NotExpectedStrings []string
+		ExpectedErr        error
+	}{
+		"wrong number of params": {
+			Namespace:   "my-space",
+			Args:        []string{"extra"},
+			ExpectedErr: errors.New("accepts 0 arg(s), received 1"),
+		},
+		"namespace is not provided": {
+			Args:        []string{},
+			ExpectedErr: errors.New(utils.EmptyNamespaceError),
+		},
+		"lists kf secrets": {
+			Namespace:          "my-space",
+			Args:               []string{},
+			Secrets:            []runtime.Object{managed, unmanaged},
+			ExpectedStrings:    []string{"Name", "Keys", "db-creds", "password, username"},
+			NotExpectedStrings: []string{"my-app-ci-trigger", "s3cr3t"},
+		},
+		"filters by name prefix": {
+			Namespace:          "my-space",
+			Args:               []string{"--filter", "api-"},
+			Secrets:            []runtime.Object{managed},
+			ExpectedStrings:    []string{"Name", "Keys"},
+			NotExpectedStrings: []string{"db-creds"},
+		},
+		"invalid sort": {
+			Namespace:   "my-space",
+			Args:        []string{"--sort-by", "size"},
+			ExpectedErr: errors.New(`unsupported --sort-by "size", must be one of: name, age, status`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			k8sClient := k8sfake.NewSimpleClientset(tc.Secrets...).CoreV1()
+
+			buf := new(bytes.Buffer)
+			p := &config.KfParams{
+				Namespace: tc.Namespace,
+			}
+
+			cmd := NewListSecretsCommand(p, k8sClient)
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.Args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.ExpectedErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+			for _, unwanted := range tc.NotExpectedStrings {
+				if strings.Contains(buf.String(), unwanted) {
+					t.Errorf("expected output not to contain %q, got:\n%s", unwanted, buf.String())
+				}
+			}
+		})
+	}
+}
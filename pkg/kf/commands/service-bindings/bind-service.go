@@ -39,8 +39,13 @@ func NewBindServiceCommand(p *config.KfParams, client apps.Client) *cobra.Comman
 		Use:     "bind-service APP_NAME SERVICE_INSTANCE [-c PARAMETERS_AS_JSON] [--binding-name BINDING_NAME]",
 		Aliases: []string{"bs"},
 		Short:   "Bind a service instance to an app",
-		Example: `  kf bind-service myapp mydb -c '{"permissions":"read-only"}'`,
-		Args:    cobra.ExactArgs(2),
+		Example: `
+  # Bind with in-line parameters
+  kf bind-service myapp mydb -c '{"permissions":"read-only"}'
+
+  # Bind with parameters read from a file
+  kf bind-service myapp mydb -c ~/workspace/tmp/binding_params.json`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			appName := args[0]
 			instanceName := args[1]
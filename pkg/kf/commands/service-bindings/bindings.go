@@ -15,39 +15,76 @@
 package servicebindings
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	kfv1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/commands/config"
 	"github.com/google/kf/pkg/kf/describe"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
 	servicebindings "github.com/google/kf/pkg/kf/service-bindings"
+	"github.com/google/kf/pkg/kf/services"
+	"github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/util/duration"
 )
 
+// bindingInfo is the summary of a binding shown to users.
+type bindingInfo struct {
+	Name        string     `json:"name"`
+	App         string     `json:"app"`
+	BindingName string     `json:"bindingName"`
+	Service     string     `json:"service"`
+	Plan        string     `json:"plan"`
+	Secret      string     `json:"secret"`
+	Ready       string     `json:"ready"`
+	Reason      string     `json:"reason"`
+	LastRotated *time.Time `json:"lastRotated,omitempty"`
+}
+
 // NewListBindingsCommand allows users to list bindings.
-func NewListBindingsCommand(p *config.KfParams, client servicebindings.ClientInterface) *cobra.Command {
+func NewListBindingsCommand(p *config.KfParams, client servicebindings.ClientInterface, servicesClient services.Client) *cobra.Command {
 	var (
 		appName         string
 		serviceInstance string
+		output          string
 	)
 
 	listCmd := &cobra.Command{
-		Use:   "bindings [--app APP_NAME] [--service SERVICE_NAME]",
+		Use:   "bindings [APP_NAME] [--service SERVICE_NAME] [-o json]",
 		Short: "List bindings",
+		Long: `List bindings along with the plan of the bound service and when the
+		binding's credentials were last issued by the broker.
+		`,
 		Example: `
 		# Show all bindings
 		kf bindings
 
 		# Show bindings for "my-app"
-		kf bindings --app my-app
+		kf bindings my-app
 
 		# Show bindings for a particular service
 		kf bindings --service users-db
+
+		# Output bindings as JSON for scripts
+		kf bindings my-app -o json
 		`,
-		Args: cobra.ExactArgs(0),
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				if appName != "" && appName != args[0] {
+					return errors.New("APP_NAME and --app can't both be set")
+				}
+				appName = args[0]
+			}
+
+			if output != "" && output != "json" {
+				return fmt.Errorf("unsupported output format %q, only json is supported", output)
+			}
+
 			cmd.SilenceUsage = true
 
 			if err := utils.ValidateNamespace(p); err != nil {
@@ -62,21 +99,63 @@ func NewListBindingsCommand(p *config.KfParams, client servicebindings.ClientInt
 				return err
 			}
 
-			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
-				fmt.Fprintln(w, "Name\tApp\tBinding Name\tService\tSecret\tReady\tReason")
-				for _, b := range bindings {
-					status := ""
-					reason := ""
-					for _, cond := range b.Status.Conditions {
-						if cond.Type == "Ready" {
-							status = fmt.Sprintf("%v", cond.Status)
-							reason = cond.Reason
+			instances, err := servicesClient.List(p.Namespace)
+			if err != nil {
+				return err
+			}
+
+			plans := make(map[string]string)
+			for _, instance := range instances {
+				plans[instance.Name] = planName(instance)
+			}
+
+			var infos []bindingInfo
+			for _, b := range bindings {
+				info := bindingInfo{
+					Name:        b.Name,
+					App:         b.Labels[kfv1alpha1.NameLabel],
+					BindingName: b.Labels[kfv1alpha1.ComponentLabel],
+					Service:     b.Spec.InstanceRef.Name,
+					Plan:        plans[b.Spec.InstanceRef.Name],
+					Secret:      b.Spec.SecretName,
+				}
+
+				for _, cond := range b.Status.Conditions {
+					if cond.Type == v1beta1.ServiceBindingConditionReady {
+						info.Ready = fmt.Sprintf("%v", cond.Status)
+						info.Reason = cond.Reason
+
+						// Brokers issue new credentials each time the binding
+						// becomes ready.
+						if cond.Status == v1beta1.ConditionTrue {
+							lastRotated := cond.LastTransitionTime.Time
+							info.LastRotated = &lastRotated
 						}
 					}
-					app := b.Labels[kfv1alpha1.NameLabel]
-					bindingName := b.Labels[kfv1alpha1.ComponentLabel]
+				}
+
+				infos = append(infos, info)
+			}
+
+			if output == "json" {
+				if infos == nil {
+					infos = []bindingInfo{}
+				}
+
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(infos)
+			}
+
+			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+				fmt.Fprintln(w, "Name\tApp\tBinding Name\tService\tPlan\tSecret\tReady\tReason\tLast Rotated")
+				for _, info := range infos {
+					lastRotated := "<unknown>"
+					if info.LastRotated != nil {
+						lastRotated = duration.HumanDuration(time.Since(*info.LastRotated)) + " ago"
+					}
 
-					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s", b.Name, app, bindingName, b.Spec.InstanceRef.Name, b.Spec.SecretName, status, reason)
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s", info.Name, info.App, info.BindingName, info.Service, info.Plan, info.Secret, info.Ready, info.Reason, lastRotated)
 					fmt.Fprintln(w)
 				}
 			})
@@ -99,5 +178,21 @@ func NewListBindingsCommand(p *config.KfParams, client servicebindings.ClientInt
 		"",
 		"Service instance to display bindings for")
 
+	listCmd.Flags().StringVarP(
+		&output,
+		"output",
+		"o",
+		"",
+		"Output format, the only supported value is json")
+
 	return listCmd
 }
+
+// planName returns the user facing name of the instance's plan.
+func planName(instance v1beta1.ServiceInstance) string {
+	if instance.Spec.ServicePlanExternalName != "" {
+		return instance.Spec.ServicePlanExternalName
+	}
+
+	return instance.Spec.ClusterServicePlanExternalName
+}
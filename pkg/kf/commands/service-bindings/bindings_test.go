@@ -15,23 +15,50 @@
 package servicebindings_test
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/kf/commands/config"
 	servicebindingscmd "github.com/google/kf/pkg/kf/commands/service-bindings"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
 	servicebindings "github.com/google/kf/pkg/kf/service-bindings"
 	"github.com/google/kf/pkg/kf/service-bindings/fake"
+	servicesfake "github.com/google/kf/pkg/kf/services/fake"
 	"github.com/google/kf/pkg/kf/testutil"
 	"github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestNewListBindingsCommand(t *testing.T) {
 	cases := map[string]serviceTest{
 		"wrong number of args": {
-			Args:        []string{"FOO"},
-			ExpectedErr: errors.New("accepts 0 arg(s), received 1"),
+			Args:        []string{"FOO", "BAR"},
+			ExpectedErr: errors.New("accepts at most 1 arg(s), received 2"),
+		},
+		"app as argument": {
+			Args:      []string{"APP_NAME"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClientInterface) {
+				f.EXPECT().List(gomock.Any()).Do(func(opts ...servicebindings.ListOption) {
+					config := servicebindings.ListOptions(opts)
+					testutil.AssertEqual(t, "app name", "APP_NAME", config.AppName())
+				}).Return([]v1beta1.ServiceBinding{}, nil)
+			},
+		},
+		"conflicting app names": {
+			Args:        []string{"APP_NAME", "--app=OTHER_APP"},
+			Namespace:   "custom-ns",
+			ExpectedErr: errors.New("APP_NAME and --app can't both be set"),
+		},
+		"bad output format": {
+			Args:        []string{"-o", "yaml"},
+			Namespace:   "custom-ns",
+			ExpectedErr: errors.New(`unsupported output format "yaml", only json is supported`),
 		},
 		"command params get passed correctly": {
 			Args:      []string{"--app=APP_NAME", "--service=SERVICE_INSTANCE"},
@@ -82,7 +109,74 @@ func TestNewListBindingsCommand(t *testing.T) {
 
 	for tn, tc := range cases {
 		t.Run(tn, func(t *testing.T) {
-			runTest(t, tc, servicebindingscmd.NewListBindingsCommand)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// No service instances exist in the space.
+			servicesClient := servicesfake.NewFakeClient(ctrl)
+			servicesClient.EXPECT().List(gomock.Any()).AnyTimes()
+
+			runTest(t, tc, func(p *config.KfParams, client servicebindings.ClientInterface) *cobra.Command {
+				return servicebindingscmd.NewListBindingsCommand(p, client, servicesClient)
+			})
+		})
+	}
+}
+
+func TestNewListBindingsCommand_details(t *testing.T) {
+	rotated := metav1.NewTime(time.Date(2019, 9, 1, 12, 0, 0, 0, time.UTC))
+
+	binding := *dummyBindingInstance("app1", "instance1")
+	binding.Spec.InstanceRef.Name = "instance1"
+	binding.Spec.SecretName = "instance1-secret"
+	binding.Status.Conditions = []v1beta1.ServiceBindingCondition{
+		{Type: v1beta1.ServiceBindingConditionReady, Status: v1beta1.ConditionTrue, LastTransitionTime: rotated},
+	}
+
+	instance := v1beta1.ServiceInstance{}
+	instance.Name = "instance1"
+	instance.Spec.ClusterServicePlanExternalName = "gold"
+
+	cases := map[string]struct {
+		args   []string
+		assert func(t *testing.T, output string)
+	}{
+		"table": {
+			assert: func(t *testing.T, output string) {
+				testutil.AssertContainsAll(t, output, []string{"Plan", "Last Rotated", "gold", "instance1-secret", "ago"})
+			},
+		},
+		"json": {
+			args: []string{"-o", "json"},
+			assert: func(t *testing.T, output string) {
+				var actual []map[string]interface{}
+				testutil.AssertNil(t, "unmarshal error", json.Unmarshal([]byte(output), &actual))
+				testutil.AssertEqual(t, "count", 1, len(actual))
+				testutil.AssertEqual(t, "plan", "gold", actual[0]["plan"])
+				testutil.AssertEqual(t, "secret", "instance1-secret", actual[0]["secret"])
+				testutil.AssertEqual(t, "lastRotated", "2019-09-01T12:00:00Z", actual[0]["lastRotated"])
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			bindingsClient := fake.NewFakeClientInterface(ctrl)
+			bindingsClient.EXPECT().List(gomock.Any()).Return([]v1beta1.ServiceBinding{binding}, nil)
+
+			servicesClient := servicesfake.NewFakeClient(ctrl)
+			servicesClient.EXPECT().List("custom-ns").Return([]v1beta1.ServiceInstance{instance}, nil)
+
+			buf := new(bytes.Buffer)
+			cmd := servicebindingscmd.NewListBindingsCommand(&config.KfParams{Namespace: "custom-ns"}, bindingsClient, servicesClient)
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.args)
+			testutil.AssertNil(t, "execute error", cmd.Execute())
+
+			tc.assert(t, buf.String())
 		})
 	}
 }
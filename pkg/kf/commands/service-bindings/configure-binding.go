@@ -0,0 +1,330 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicebindings
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewConfigureBindingCommand creates a command that can set facets of an
+// app's service binding.
+func NewConfigureBindingCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "configure-binding [subcommand]",
+		Aliases: []string{"config-binding"},
+		Short:   "Set configuration for a service binding",
+		Long: `The configure-binding sub-command allows developers to configure
+		individual service bindings on an app.
+
+		Bindings can be configured to have their credentials rotated
+		automatically. Kf rotates credentials by asking the broker for a new
+		binding then rolling out a new revision of the app that uses it. The old
+		credentials are revoked before the new revision is ready so apps may see
+		errors during rotation, use a maintenance window to control when that
+		happens.
+		`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newSetRotationCommand(p, client),
+		newUnsetRotationCommand(p, client),
+		newGetRotationCommand(p, client),
+		newSetBackupHooksCommand(p, client),
+		newUnsetBackupHooksCommand(p, client),
+	)
+
+	return cmd
+}
+
+func newSetRotationCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var (
+		window    v1alpha1.MaintenanceWindow
+		diffFlags utils.DiffFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-rotation APP_NAME BINDING_NAME PERIOD",
+		Short: "Rotate the binding's credentials after PERIOD e.g. 30d or 12h.",
+		Long: `Rotate the binding's credentials after PERIOD e.g. 30d or 12h.
+
+		Use --window-start and --window-stop to only allow rotations in a
+		recurring maintenance window.
+		`,
+		Example: `
+		kf configure-binding set-rotation myapp mydb 30d
+		kf configure-binding set-rotation myapp mydb 7d --window-start "0 2 * * 6" --window-stop "0 4 * * 6"
+		`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appName := args[0]
+			bindingName := args[1]
+
+			period, err := parseRotationPeriod(args[2])
+			if err != nil {
+				return err
+			}
+
+			if (window.Start == "") != (window.Stop == "") {
+				return errors.New("--window-start and --window-stop must be set together")
+			}
+
+			rotation := &v1alpha1.AppSpecServiceBindingRotation{
+				Period: metav1.Duration{Duration: period},
+			}
+
+			if window.Start != "" {
+				rotation.Window = window.DeepCopy()
+			}
+
+			return transformBinding(cmd, p, client, diffFlags, appName, bindingName, func(binding *v1alpha1.AppSpecServiceBinding) {
+				binding.Rotation = rotation
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&window.Start,
+		"window-start",
+		"",
+		"Cron expression for when the maintenance window opens.")
+
+	cmd.Flags().StringVar(
+		&window.Stop,
+		"window-stop",
+		"",
+		"Cron expression for when the maintenance window closes.")
+
+	cmd.Flags().StringVar(
+		&window.TimeZone,
+		"window-time-zone",
+		"",
+		"IANA time zone the maintenance window is in (default: UTC).")
+
+	diffFlags.Add(cmd)
+
+	return cmd
+}
+
+func newUnsetRotationCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var diffFlags utils.DiffFlags
+
+	cmd := &cobra.Command{
+		Use:     "unset-rotation APP_NAME BINDING_NAME",
+		Short:   "Stop rotating the binding's credentials automatically.",
+		Example: "kf configure-binding unset-rotation myapp mydb",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return transformBinding(cmd, p, client, diffFlags, args[0], args[1], func(binding *v1alpha1.AppSpecServiceBinding) {
+				binding.Rotation = nil
+			})
+		},
+	}
+
+	diffFlags.Add(cmd)
+
+	return cmd
+}
+
+func newGetRotationCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:     "get-rotation APP_NAME BINDING_NAME",
+		Short:   "Show how often the binding's credentials are rotated.",
+		Example: "kf configure-binding get-rotation myapp mydb",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			appName := args[0]
+			bindingName := args[1]
+
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			app, err := client.Get(p.Namespace, appName)
+			if err != nil {
+				return err
+			}
+
+			binding, err := findBinding(app, bindingName)
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			rotation := binding.Rotation
+			if rotation == nil {
+				fmt.Fprintln(w, "Credentials aren't rotated automatically.")
+				return nil
+			}
+
+			fmt.Fprintf(w, "Period: %v\n", rotation.Period.Duration)
+			if rotation.Window != nil {
+				fmt.Fprintf(w, "Window Start: %s\n", rotation.Window.Start)
+				fmt.Fprintf(w, "Window Stop: %s\n", rotation.Window.Stop)
+				if rotation.Window.TimeZone != "" {
+					fmt.Fprintf(w, "Window Time Zone: %s\n", rotation.Window.TimeZone)
+				}
+			}
+
+			return nil
+		},
+	}
+}
+
+func newSetBackupHooksCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var (
+		hooks     v1alpha1.AppSpecServiceBindingBackupHooks
+		diffFlags utils.DiffFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-backup-hooks APP_NAME BINDING_NAME",
+		Short: "Set commands the app runs around backups and restores of the service.",
+		Long: `Set commands the app runs around backups and restores of the service.
+
+		The commands are run with /bin/sh in the app's container by Velero
+		before the space is backed up and after it's restored. Changing the
+		hooks rolls out a new revision of the app.
+		`,
+		Example: `kf configure-binding set-backup-hooks myapp mydb --pre-backup "./bin/flush-db" --post-restore "./bin/reload-db"`,
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if hooks.PreBackup == "" && hooks.PostRestore == "" {
+				return errors.New("at least one of --pre-backup or --post-restore must be set")
+			}
+
+			return transformBinding(cmd, p, client, diffFlags, args[0], args[1], func(binding *v1alpha1.AppSpecServiceBinding) {
+				binding.BackupHooks = hooks.DeepCopy()
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&hooks.PreBackup,
+		"pre-backup",
+		"",
+		"Command to run before the space is backed up.")
+
+	cmd.Flags().StringVar(
+		&hooks.PostRestore,
+		"post-restore",
+		"",
+		"Command to run after the space is restored.")
+
+	diffFlags.Add(cmd)
+
+	return cmd
+}
+
+func newUnsetBackupHooksCommand(p *config.KfParams, client apps.Client) *cobra.Command {
+	var diffFlags utils.DiffFlags
+
+	cmd := &cobra.Command{
+		Use:     "unset-backup-hooks APP_NAME BINDING_NAME",
+		Short:   "Stop running commands around backups and restores of the service.",
+		Example: "kf configure-binding unset-backup-hooks myapp mydb",
+		Args:    cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return transformBinding(cmd, p, client, diffFlags, args[0], args[1], func(binding *v1alpha1.AppSpecServiceBinding) {
+				binding.BackupHooks = nil
+			})
+		},
+	}
+
+	diffFlags.Add(cmd)
+
+	return cmd
+}
+
+// transformBinding applies the mutator to the named binding of the app.
+func transformBinding(
+	cmd *cobra.Command,
+	p *config.KfParams,
+	client apps.Client,
+	diffFlags utils.DiffFlags,
+	appName string,
+	bindingName string,
+	mutator func(*v1alpha1.AppSpecServiceBinding),
+) error {
+	if err := utils.ValidateNamespace(p); err != nil {
+		return err
+	}
+
+	diffOpts, err := diffFlags.Options()
+	if err != nil {
+		return err
+	}
+
+	cmd.SilenceUsage = true
+
+	_, err = client.Transform(p.Namespace, appName, apps.DiffWrapper(cmd.OutOrStdout(), func(app *v1alpha1.App) error {
+		binding, err := findBinding(app, bindingName)
+		if err != nil {
+			return err
+		}
+
+		mutator(binding)
+		return nil
+	}, diffOpts...))
+
+	return err
+}
+
+// findBinding returns the binding of the app with the given binding or
+// instance name.
+func findBinding(app *v1alpha1.App, name string) (*v1alpha1.AppSpecServiceBinding, error) {
+	for i, binding := range app.Spec.ServiceBindings {
+		if binding.BindingName == name || binding.Instance == name {
+			return &app.Spec.ServiceBindings[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("app %s has no binding %s", app.Name, name)
+}
+
+// parseRotationPeriod parses a Go duration with added support for days e.g.
+// 30d.
+func parseRotationPeriod(period string) (time.Duration, error) {
+	if days := strings.TrimSuffix(period, "d"); days != period {
+		count, err := strconv.Atoi(days)
+		if err != nil || count <= 0 {
+			return 0, fmt.Errorf("PERIOD must be a positive number of days or a duration, got %q", period)
+		}
+
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("PERIOD must be a positive number of days or a duration, got %q", period)
+	}
+
+	return duration, nil
+}
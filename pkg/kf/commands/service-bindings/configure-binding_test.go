@@ -0,0 +1,197 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicebindings_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/apps/fake"
+	servicebindingscmd "github.com/google/kf/pkg/kf/commands/service-bindings"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewConfigureBindingCommand(t *testing.T) {
+	// expectTransform applies the transformation to an App bound to mydb and
+	// checks the result.
+	expectTransform := func(t *testing.T, f *fake.FakeClient, check func(t *testing.T, binding v1alpha1.AppSpecServiceBinding, err error)) {
+		f.EXPECT().
+			Transform("custom-ns", "myapp", gomock.Any()).
+			DoAndReturn(func(_, _ string, mutator apps.Mutator) (*v1alpha1.App, error) {
+				app := &v1alpha1.App{}
+				app.Name = "myapp"
+				app.Spec.ServiceBindings = []v1alpha1.AppSpecServiceBinding{
+					{Instance: "mydb", BindingName: "mydb"},
+				}
+
+				err := mutator(app)
+				check(t, app.Spec.ServiceBindings[0], err)
+				return app, err
+			})
+	}
+
+	rotatedApp := &v1alpha1.App{}
+	rotatedApp.Spec.ServiceBindings = []v1alpha1.AppSpecServiceBinding{
+		{
+			Instance:    "mydb",
+			BindingName: "db",
+			Rotation: &v1alpha1.AppSpecServiceBindingRotation{
+				Period: metav1.Duration{Duration: 720 * time.Hour},
+				Window: &v1alpha1.MaintenanceWindow{Start: "0 2 * * 6", Stop: "0 4 * * 6"},
+			},
+		},
+	}
+
+	cases := map[string]appsTest{
+		"set-rotation wrong number of args": {
+			Args:        []string{"set-rotation", "myapp", "mydb"},
+			ExpectedErr: errors.New("accepts 3 arg(s), received 2"),
+		},
+		"set-rotation bad period": {
+			Args:        []string{"set-rotation", "myapp", "mydb", "monthly"},
+			Namespace:   "custom-ns",
+			ExpectedErr: errors.New(`PERIOD must be a positive number of days or a duration, got "monthly"`),
+		},
+		"set-rotation zero period": {
+			Args:        []string{"set-rotation", "myapp", "mydb", "0d"},
+			Namespace:   "custom-ns",
+			ExpectedErr: errors.New(`PERIOD must be a positive number of days or a duration, got "0d"`),
+		},
+		"set-rotation partial window": {
+			Args:        []string{"set-rotation", "myapp", "mydb", "30d", "--window-start", "0 2 * * 6"},
+			Namespace:   "custom-ns",
+			ExpectedErr: errors.New("--window-start and --window-stop must be set together"),
+		},
+		"set-rotation empty namespace": {
+			Args:        []string{"set-rotation", "myapp", "mydb", "30d"},
+			ExpectedErr: errors.New(utils.EmptyNamespaceError),
+		},
+		"set-rotation days": {
+			Args:      []string{"set-rotation", "myapp", "mydb", "30d"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				expectTransform(t, f, func(t *testing.T, binding v1alpha1.AppSpecServiceBinding, err error) {
+					testutil.AssertNil(t, "error", err)
+					testutil.AssertEqual(t, "rotation", &v1alpha1.AppSpecServiceBindingRotation{
+						Period: metav1.Duration{Duration: 720 * time.Hour},
+					}, binding.Rotation)
+				})
+			},
+		},
+		"set-rotation with window": {
+			Args:      []string{"set-rotation", "myapp", "mydb", "12h", "--window-start", "0 2 * * 6", "--window-stop", "0 4 * * 6", "--window-time-zone", "America/New_York"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				expectTransform(t, f, func(t *testing.T, binding v1alpha1.AppSpecServiceBinding, err error) {
+					testutil.AssertNil(t, "error", err)
+					testutil.AssertEqual(t, "rotation", &v1alpha1.AppSpecServiceBindingRotation{
+						Period: metav1.Duration{Duration: 12 * time.Hour},
+						Window: &v1alpha1.MaintenanceWindow{
+							Start:    "0 2 * * 6",
+							Stop:     "0 4 * * 6",
+							TimeZone: "America/New_York",
+						},
+					}, binding.Rotation)
+				})
+			},
+		},
+		"set-rotation missing binding": {
+			Args:      []string{"set-rotation", "myapp", "other-db", "30d"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				expectTransform(t, f, func(t *testing.T, binding v1alpha1.AppSpecServiceBinding, err error) {
+					testutil.AssertErrorsEqual(t, errors.New("app myapp has no binding other-db"), err)
+				})
+			},
+			ExpectedErr: errors.New("app myapp has no binding other-db"),
+		},
+		"unset-rotation": {
+			Args:      []string{"unset-rotation", "myapp", "mydb"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				expectTransform(t, f, func(t *testing.T, binding v1alpha1.AppSpecServiceBinding, err error) {
+					testutil.AssertNil(t, "error", err)
+					testutil.AssertEqual(t, "rotation cleared", true, binding.Rotation == nil)
+				})
+			},
+		},
+		"set-backup-hooks": {
+			Args:      []string{"set-backup-hooks", "myapp", "mydb", "--pre-backup", "./flush.sh", "--post-restore", "./reload.sh"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				expectTransform(t, f, func(t *testing.T, binding v1alpha1.AppSpecServiceBinding, err error) {
+					testutil.AssertNil(t, "error", err)
+					testutil.AssertEqual(t, "hooks", &v1alpha1.AppSpecServiceBindingBackupHooks{
+						PreBackup:   "./flush.sh",
+						PostRestore: "./reload.sh",
+					}, binding.BackupHooks)
+				})
+			},
+		},
+		"set-backup-hooks no hooks": {
+			Args:        []string{"set-backup-hooks", "myapp", "mydb"},
+			Namespace:   "custom-ns",
+			ExpectedErr: errors.New("at least one of --pre-backup or --post-restore must be set"),
+		},
+		"unset-backup-hooks": {
+			Args:      []string{"unset-backup-hooks", "myapp", "mydb"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				expectTransform(t, f, func(t *testing.T, binding v1alpha1.AppSpecServiceBinding, err error) {
+					testutil.AssertNil(t, "error", err)
+					testutil.AssertEqual(t, "hooks cleared", true, binding.BackupHooks == nil)
+				})
+			},
+		},
+		"get-rotation unset": {
+			Args:      []string{"get-rotation", "myapp", "mydb"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				app := &v1alpha1.App{}
+				app.Spec.ServiceBindings = []v1alpha1.AppSpecServiceBinding{{Instance: "mydb", BindingName: "mydb"}}
+				f.EXPECT().Get("custom-ns", "myapp").Return(app, nil)
+			},
+			ExpectedStrings: []string{"Credentials aren't rotated automatically."},
+		},
+		"get-rotation by instance name": {
+			Args:      []string{"get-rotation", "myapp", "mydb"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				f.EXPECT().Get("custom-ns", "myapp").Return(rotatedApp, nil)
+			},
+			ExpectedStrings: []string{"Period: 720h0m0s", "Window Start: 0 2 * * 6", "Window Stop: 0 4 * * 6"},
+		},
+		"get-rotation server error": {
+			Args:      []string{"get-rotation", "myapp", "mydb"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				f.EXPECT().Get("custom-ns", "myapp").Return(nil, errors.New("api-error"))
+			},
+			ExpectedErr: errors.New("api-error"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			runAppsTest(t, tc, servicebindingscmd.NewConfigureBindingCommand)
+		})
+	}
+}
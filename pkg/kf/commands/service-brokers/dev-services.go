@@ -0,0 +1,160 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package servicebrokers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	installutil "github.com/google/kf/pkg/kf/commands/install/util"
+	"github.com/google/kf/pkg/kf/devservices"
+	"github.com/spf13/cobra"
+)
+
+// NewDevServicesCommand creates a command to manage the in-cluster broker
+// that provides services for development spaces.
+func NewDevServicesCommand(p *config.KfParams, client devservices.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dev-services [subcommand]",
+		Short: "Manage in-cluster services for development spaces",
+		Long: `The dev-services sub-command installs a lightweight service broker
+		into a space. The broker provisions services like PostgreSQL, Redis, and
+		RabbitMQ as Helm charts in the space so apps can be developed without an
+		external broker.
+
+		Dev services aren't backed up or highly available, don't use them for
+		production data.
+		`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newEnableDevServicesCommand(client),
+		newDisableDevServicesCommand(client),
+		newDevServicesStatusCommand(client),
+	)
+
+	return cmd
+}
+
+func newEnableDevServicesCommand(client devservices.Client) *cobra.Command {
+	var image string
+
+	cmd := &cobra.Command{
+		Use:   "enable SPACE",
+		Short: "Install the dev services broker in a space",
+		Example: `  kf dev-services enable my-space
+  kf marketplace
+  kf create-service postgresql 11-0-0 mydb`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			space := args[0]
+			if err := client.Enable(space, image, cmd.OutOrStdout()); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Dev services enabled in space %s. Run 'kf marketplace' in the space to see the available services and plans once the broker is ready.\n", space)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&image,
+		"image",
+		devservices.DefaultBrokerImage,
+		"Container image of the broker to install",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
+
+func newDisableDevServicesCommand(client devservices.Client) *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:     "disable SPACE",
+		Short:   "Remove the dev services broker from a space",
+		Example: `  kf dev-services disable my-space`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			space := args[0]
+			if !force {
+				shouldDelete, err := installutil.SelectYesNo(context.Background(), fmt.Sprintf("Really remove dev services from space %s?", space))
+				if err != nil || !shouldDelete {
+					fmt.Fprintln(cmd.OutOrStdout(), "Skipping removal, use --force to remove without validation")
+					return err
+				}
+			}
+
+			if err := client.Disable(space, cmd.OutOrStdout()); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), "Service instances the broker provisioned weren't deleted, use 'kf delete-service' to remove them.")
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&force,
+		"force",
+		false,
+		"Set to remove without a confirmation prompt.",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
+
+func newDevServicesStatusCommand(client devservices.Client) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "status SPACE",
+		Short:   "Show whether dev services are enabled in a space",
+		Example: `  kf dev-services status my-space`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			space := args[0]
+			enabled, err := client.Enabled(space)
+			if err != nil {
+				return err
+			}
+
+			if enabled {
+				fmt.Fprintf(cmd.OutOrStdout(), "Dev services are enabled in space %s\n", space)
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "Dev services are disabled in space %s\n", space)
+			}
+
+			return nil
+		},
+	}
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
@@ -0,0 +1,61 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/describe"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	serviceshares "github.com/google/kf/pkg/kf/service-shares"
+	"github.com/spf13/cobra"
+)
+
+// NewServiceSharesCommand allows users to list the spaces a service instance
+// is shared with.
+func NewServiceSharesCommand(p *config.KfParams, client serviceshares.Client) *cobra.Command {
+	return &cobra.Command{
+		Use:     "service-shares SERVICE_INSTANCE",
+		Short:   "List the spaces a service instance is shared with",
+		Example: "kf service-shares mydb",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			instanceName := args[0]
+			spaces, err := client.List(p.Namespace, instanceName)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Getting spaces service instance %q is shared with\n", instanceName)
+
+			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
+				fmt.Fprintln(w, "Space\tSecret")
+				for _, space := range spaces {
+					fmt.Fprintf(w, "%s\t%s\n", space, serviceshares.SecretName(p.Namespace, instanceName))
+				}
+			})
+
+			return nil
+		},
+	}
+}
@@ -26,6 +26,7 @@ import (
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
 	"github.com/google/kf/pkg/kf/marketplace"
 	"github.com/google/kf/pkg/kf/services"
+	"github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"github.com/spf13/cobra"
 )
 
@@ -36,25 +37,53 @@ func NewListServicesCommand(
 	appsClient apps.Client,
 	marketplaceClient marketplace.ClientInterface,
 ) *cobra.Command {
+	var listFlags utils.ListFlags
+
 	servicesCommand := &cobra.Command{
 		Use:     "services",
 		Aliases: []string{"s"},
 		Short:   "List service instances",
-		Long:    `Lists all service instances in the target space.`,
-		Example: `kf services`,
-		Args:    cobra.ExactArgs(0),
+		Long: `Lists all service instances in the target space.
+
+		Use --limit and --continue to list service instances a page at a time.
+		`,
+		Example: `
+		kf services
+		kf services --filter db- --sort-by status
+		kf services --limit 50
+		`,
+		Args: cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := listFlags.Validate(); err != nil {
+				return err
+			}
+
 			cmd.SilenceUsage = true
 
 			if err := utils.ValidateNamespace(p); err != nil {
 				return err
 			}
 
-			instances, err := client.List(p.Namespace)
+			instances, continueToken, err := client.ListPage(
+				p.Namespace,
+				services.WithListLimit(listFlags.Limit()),
+				services.WithListContinueToken(listFlags.ContinueToken()),
+				services.WithListFilter(func(instance *v1beta1.ServiceInstance) bool {
+					return listFlags.MatchesName(instance.Name)
+				}),
+			)
 			if err != nil {
 				return err
 			}
 
+			listFlags.Sort(instances, func(i int) utils.ListSortKey {
+				return utils.ListSortKey{
+					Name:    instances[i].Name,
+					Created: instances[i].CreationTimestamp.Time,
+					Status:  services.LastStatusCondition(instances[i]).Reason,
+				}
+			})
+
 			apps, err := appsClient.List(p.Namespace)
 			if err != nil {
 				return err
@@ -92,10 +121,14 @@ func NewListServicesCommand(
 				}
 			})
 
+			listFlags.PrintNextPage(cmd.OutOrStdout(), continueToken)
+
 			return err
 		},
 	}
 
+	listFlags.Add(servicesCommand)
+
 	return servicesCommand
 }
 
@@ -48,7 +48,7 @@ func TestNewServicesCommand(t *testing.T) {
 			serviceTest: serviceTest{
 				Namespace: "test-ns",
 				Setup: func(t *testing.T, f *fake.FakeClient) {
-					f.EXPECT().List("test-ns").Return([]v1beta1.ServiceInstance{}, nil)
+					f.EXPECT().ListPage("test-ns", gomock.Any()).Return([]v1beta1.ServiceInstance{}, "", nil)
 				},
 			},
 		},
@@ -61,7 +61,7 @@ func TestNewServicesCommand(t *testing.T) {
 			serviceTest: serviceTest{
 				Namespace: "test-ns",
 				Setup: func(t *testing.T, f *fake.FakeClient) {
-					f.EXPECT().List(gomock.Any()).Return([]v1beta1.ServiceInstance{}, nil)
+					f.EXPECT().ListPage(gomock.Any(), gomock.Any()).Return([]v1beta1.ServiceInstance{}, "", nil)
 				},
 				ExpectedErr: nil, // explicitly expecting no failure with zero length list
 			},
@@ -73,7 +73,7 @@ func TestNewServicesCommand(t *testing.T) {
 			serviceTest: serviceTest{
 				Namespace: "test-ns",
 				Setup: func(t *testing.T, f *fake.FakeClient) {
-					f.EXPECT().List(gomock.Any()).Return([]v1beta1.ServiceInstance{}, nil)
+					f.EXPECT().ListPage(gomock.Any(), gomock.Any()).Return([]v1beta1.ServiceInstance{}, "", nil)
 				},
 				ExpectedErr: errors.New("some-error"),
 			},
@@ -89,7 +89,7 @@ func TestNewServicesCommand(t *testing.T) {
 						*dummyServerInstance("service-1"),
 						*dummyServerInstance("service-2"),
 					}
-					f.EXPECT().List(gomock.Any()).Return(serviceList, nil)
+					f.EXPECT().ListPage(gomock.Any(), gomock.Any()).Return(serviceList, "", nil)
 				},
 				ExpectedErr: errors.New("fetch-broker-name-error"),
 				ExpectedStrings: []string{
@@ -117,7 +117,7 @@ func TestNewServicesCommand(t *testing.T) {
 						service1,
 						*dummyServerInstance("service-2"),
 					}
-					f.EXPECT().List(gomock.Any()).Return(serviceList, nil)
+					f.EXPECT().ListPage(gomock.Any(), gomock.Any()).Return(serviceList, "", nil)
 				},
 				ExpectedStrings: []string{
 					"service-1", "service-2", // Binding Names
@@ -127,12 +127,32 @@ func TestNewServicesCommand(t *testing.T) {
 				},
 			},
 		},
+		"next page": {
+			serviceTest: serviceTest{
+				Namespace: "test-ns",
+				Args:      []string{"--limit", "1"},
+				Setup: func(t *testing.T, f *fake.FakeClient) {
+					serviceList := []v1beta1.ServiceInstance{
+						*dummyServerInstance("service-1"),
+					}
+					f.EXPECT().ListPage("test-ns", gomock.Any()).Return(serviceList, "next-token", nil)
+				},
+				ExpectedStrings: []string{"service-1", "--continue next-token"},
+			},
+		},
+		"invalid sort": {
+			serviceTest: serviceTest{
+				Namespace:   "test-ns",
+				Args:        []string{"--sort-by", "size"},
+				ExpectedErr: errors.New(`unsupported --sort-by "size", must be one of: name, age, status`),
+			},
+		},
 		"bad server call": {
 			serviceTest: serviceTest{
 				Namespace:   "test-ns",
 				ExpectedErr: errors.New("server-call-error"),
 				Setup: func(t *testing.T, f *fake.FakeClient) {
-					f.EXPECT().List(gomock.Any()).Return(nil, errors.New("server-call-error"))
+					f.EXPECT().ListPage(gomock.Any(), gomock.Any()).Return(nil, "", errors.New("server-call-error"))
 				},
 			},
 		},
@@ -0,0 +1,139 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/kf/pkg/kf/commands/config"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	serviceshares "github.com/google/kf/pkg/kf/service-shares"
+	"github.com/spf13/cobra"
+)
+
+// NewShareServiceCommand allows users to share service instances with other
+// spaces.
+func NewShareServiceCommand(p *config.KfParams, client serviceshares.Client) *cobra.Command {
+	var (
+		space   string
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:     "share-service SERVICE_INSTANCE --space SPACE",
+		Short:   "Share a service instance with another space",
+		Example: "kf share-service mydb --space other-space",
+		Long: `Share a service instance with another space.
+
+		The instance is bound in its own space and the credentials of the binding
+		are copied to the Secret kf-shared-SPACE-SERVICE_INSTANCE in the other
+		space, where apps can read them as environment variables.
+
+		The copied credentials don't follow rotations, share the instance again
+		to refresh them.
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			if space == "" {
+				return errors.New("--space is required")
+			}
+
+			cmd.SilenceUsage = true
+
+			instanceName := args[0]
+			fmt.Fprintf(cmd.OutOrStdout(), "Sharing service instance %q with space %q\n", instanceName, space)
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			if err := client.Share(ctx, p.Namespace, instanceName, space); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Credentials are in Secret %s in space %q\n", serviceshares.SecretName(p.Namespace, instanceName), space)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&space,
+		"space",
+		"",
+		"Space to share the service instance with",
+	)
+
+	cmd.Flags().DurationVar(
+		&timeout,
+		"timeout",
+		2*time.Minute,
+		"How long to wait for the credentials of the instance",
+	)
+
+	return cmd
+}
+
+// NewUnshareServiceCommand allows users to stop sharing service instances
+// with other spaces.
+func NewUnshareServiceCommand(p *config.KfParams, client serviceshares.Client) *cobra.Command {
+	var space string
+
+	cmd := &cobra.Command{
+		Use:     "unshare-service SERVICE_INSTANCE --space SPACE",
+		Short:   "Stop sharing a service instance with another space",
+		Example: "kf unshare-service mydb --space other-space",
+		Long: `Stop sharing a service instance with another space.
+
+		The binding made to share the instance is removed and the copied
+		credentials are deleted from the other space. Apps in the other space
+		that read them have to be restarted to stop using them.
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			if space == "" {
+				return errors.New("--space is required")
+			}
+
+			cmd.SilenceUsage = true
+
+			instanceName := args[0]
+			if err := client.Unshare(p.Namespace, instanceName, space); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Service instance %q is no longer shared with space %q\n", instanceName, space)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&space,
+		"space",
+		"",
+		"Space to stop sharing the service instance with",
+	)
+
+	return cmd
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/kf/commands/config"
+	servicescmd "github.com/google/kf/pkg/kf/commands/services"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	serviceshares "github.com/google/kf/pkg/kf/service-shares"
+	"github.com/google/kf/pkg/kf/service-shares/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/spf13/cobra"
+)
+
+type shareTest struct {
+	Args      []string
+	Setup     func(t *testing.T, f *fake.FakeClient)
+	Namespace string
+
+	ExpectedErr     error
+	ExpectedStrings []string
+}
+
+func runShareTest(t *testing.T, tc shareTest, newCommand func(p *config.KfParams, client serviceshares.Client) *cobra.Command) {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := fake.NewFakeClient(ctrl)
+	if tc.Setup != nil {
+		tc.Setup(t, client)
+	}
+
+	buf := new(bytes.Buffer)
+	p := &config.KfParams{
+		Namespace: tc.Namespace,
+	}
+
+	cmd := newCommand(p, client)
+	cmd.SetOutput(buf)
+	cmd.SetArgs(tc.Args)
+	_, actualErr := cmd.ExecuteC()
+	if tc.ExpectedErr != nil || actualErr != nil {
+		testutil.AssertErrorsEqual(t, tc.ExpectedErr, actualErr)
+		return
+	}
+
+	testutil.AssertContainsAll(t, buf.String(), tc.ExpectedStrings)
+}
+
+func TestNewShareServiceCommand(t *testing.T) {
+	cases := map[string]shareTest{
+		"too few params": {
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"empty namespace": {
+			Args:        []string{"mydb", "--space", "other"},
+			ExpectedErr: errors.New(utils.EmptyNamespaceError),
+		},
+		"missing space": {
+			Args:        []string{"mydb"},
+			Namespace:   "custom-ns",
+			ExpectedErr: errors.New("--space is required"),
+		},
+		"shares the instance": {
+			Args:      []string{"mydb", "--space", "other"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				f.EXPECT().Share(gomock.Any(), "custom-ns", "mydb", "other")
+			},
+			ExpectedStrings: []string{"kf-shared-custom-ns-mydb"},
+		},
+		"sharing fails": {
+			Args:      []string{"mydb", "--space", "other"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				f.EXPECT().Share(gomock.Any(), "custom-ns", "mydb", "other").Return(errors.New("some-error"))
+			},
+			ExpectedErr: errors.New("some-error"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			runShareTest(t, tc, servicescmd.NewShareServiceCommand)
+		})
+	}
+}
+
+func TestNewUnshareServiceCommand(t *testing.T) {
+	cases := map[string]shareTest{
+		"too few params": {
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"missing space": {
+			Args:        []string{"mydb"},
+			Namespace:   "custom-ns",
+			ExpectedErr: errors.New("--space is required"),
+		},
+		"unshares the instance": {
+			Args:      []string{"mydb", "--space", "other"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				f.EXPECT().Unshare("custom-ns", "mydb", "other")
+			},
+			ExpectedStrings: []string{"no longer shared"},
+		},
+		"unsharing fails": {
+			Args:      []string{"mydb", "--space", "other"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				f.EXPECT().Unshare("custom-ns", "mydb", "other").Return(errors.New("some-error"))
+			},
+			ExpectedErr: errors.New("some-error"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			runShareTest(t, tc, servicescmd.NewUnshareServiceCommand)
+		})
+	}
+}
+
+func TestNewServiceSharesCommand(t *testing.T) {
+	cases := map[string]shareTest{
+		"too few params": {
+			Args:        []string{},
+			ExpectedErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"lists spaces": {
+			Args:      []string{"mydb"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				f.EXPECT().List("custom-ns", "mydb").Return([]string{"space-a", "space-b"}, nil)
+			},
+			ExpectedStrings: []string{"space-a", "space-b", "kf-shared-custom-ns-mydb"},
+		},
+		"listing fails": {
+			Args:      []string{"mydb"},
+			Namespace: "custom-ns",
+			Setup: func(t *testing.T, f *fake.FakeClient) {
+				f.EXPECT().List("custom-ns", "mydb").Return(nil, errors.New("some-error"))
+			},
+			ExpectedErr: errors.New("some-error"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			runShareTest(t, tc, servicescmd.NewServiceSharesCommand)
+		})
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/describe"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	servicebindings "github.com/google/kf/pkg/kf/service-bindings"
+	"github.com/google/kf/pkg/kf/services"
+	servicecatalogv1beta1 "github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// NewUpdateServiceCommand allows users to change the plan and parameters of
+// existing service instances.
+func NewUpdateServiceCommand(p *config.KfParams, client services.Client, bindingsClient servicebindings.ClientInterface) *cobra.Command {
+	var (
+		configAsJSON string
+		plan         string
+		async        utils.AsyncFlags
+	)
+
+	updateCmd := &cobra.Command{
+		Use:   "update-service SERVICE_INSTANCE [-p NEW_PLAN] [-c PARAMETERS_AS_JSON]",
+		Short: "Change the plan or parameters of a service instance",
+		Long: `Update a service instance in place by asking its broker to migrate it to
+		a new plan or apply new parameters, for example to resize a database
+		without recreating it.
+
+		Brokers may rotate credentials as part of an update. Apps bound to the
+		instance won't see new credentials until they're restarted.
+		`,
+		Example: `
+  # Move mydb to the gold plan
+  kf update-service mydb -p gold
+
+  # Apply new provisioning parameters
+  kf update-service mydb -c '{"ram_gb":8}'
+
+  # Change both the plan and parameters, reading parameters from a file
+  kf update-service mydb -p gold -c ~/workspace/tmp/instance_config.json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			instanceName := args[0]
+
+			cmd.SilenceUsage = true
+
+			if err := utils.ValidateNamespace(p); err != nil {
+				return err
+			}
+
+			if plan == "" && configAsJSON == "" {
+				return errors.New("at least one of --plan or --config must be set")
+			}
+
+			var paramBytes []byte
+			if configAsJSON != "" {
+				var err error
+				if paramBytes, err = services.ParseJSONOrFile(configAsJSON); err != nil {
+					return err
+				}
+			}
+
+			updated, err := client.Transform(p.Namespace, instanceName, func(instance *servicecatalogv1beta1.ServiceInstance) error {
+				if plan != "" {
+					setPlan(&instance.Spec, plan)
+				}
+
+				if paramBytes != nil {
+					instance.Spec.Parameters = &runtime.RawExtension{
+						Raw: paramBytes,
+					}
+				}
+
+				// Force the broker to be called even if the spec is unchanged
+				// so retrying a failed update works.
+				instance.Spec.UpdateRequests++
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			action := fmt.Sprintf("Updating service instance %q in space %q", instanceName, p.Namespace)
+			if err := async.AwaitAndLog(cmd.OutOrStdout(), action, func() (err error) {
+				updated, err = client.WaitForProvisionSuccess(context.Background(), p.Namespace, instanceName, 1*time.Second)
+				return
+			}); err != nil {
+				return err
+			}
+
+			describe.ServiceInstance(cmd.OutOrStdout(), updated)
+
+			bindings, err := bindingsClient.List(
+				servicebindings.WithListNamespace(p.Namespace),
+				servicebindings.WithListServiceInstance(instanceName),
+			)
+			if err != nil {
+				return err
+			}
+
+			printRotationNotice(cmd.OutOrStdout(), bindings)
+			return nil
+		},
+	}
+
+	async.Add(updateCmd)
+
+	updateCmd.Flags().StringVarP(
+		&plan,
+		"plan",
+		"p",
+		"",
+		"New plan for the service instance.")
+
+	updateCmd.Flags().StringVarP(
+		&configAsJSON,
+		"config",
+		"c",
+		"",
+		"Valid JSON object containing service-specific configuration parameters, provided in-line or in a file.")
+
+	return updateCmd
+}
+
+// setPlan changes the plan of the instance, keeping it in the same scope
+// (cluster or namespace) as the plan it was created with.
+func setPlan(spec *servicecatalogv1beta1.ServiceInstanceSpec, plan string) {
+	// Clearing the references makes service catalog resolve the new plan.
+	if spec.ServiceClassExternalName != "" {
+		spec.ServicePlanExternalName = plan
+		spec.ServicePlanRef = nil
+		return
+	}
+
+	spec.ClusterServicePlanExternalName = plan
+	spec.ClusterServicePlanRef = nil
+}
+
+// printRotationNotice tells the user which apps need to be restarted to pick
+// up credentials the broker may have rotated.
+func printRotationNotice(w io.Writer, bindings []servicecatalogv1beta1.ServiceBinding) {
+	if len(bindings) == 0 {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "The broker may have rotated the credentials of the following bindings:")
+	describe.TabbedWriter(w, func(w io.Writer) {
+		fmt.Fprintln(w, "Binding\tApp")
+		for _, binding := range bindings {
+			fmt.Fprintf(w, "%s\t%s\n", binding.Name, binding.Labels[v1alpha1.NameLabel])
+		}
+	})
+	fmt.Fprintln(w, "Use 'kf restart APP_NAME' to make apps pick up new credentials.")
+}
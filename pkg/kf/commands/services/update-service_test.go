@@ -0,0 +1,196 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/config"
+	servicescmd "github.com/google/kf/pkg/kf/commands/services"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	servicebindingsfake "github.com/google/kf/pkg/kf/service-bindings/fake"
+	"github.com/google/kf/pkg/kf/services"
+	servicesfake "github.com/google/kf/pkg/kf/services/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	servicecatalogv1beta1 "github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewUpdateServiceCommand(t *testing.T) {
+	type fakes struct {
+		services *servicesfake.FakeClient
+		bindings *servicebindingsfake.FakeClientInterface
+	}
+
+	// transform applies the mutator to the given instance and checks the
+	// result.
+	transform := func(t *testing.T, instance servicecatalogv1beta1.ServiceInstance, check func(*testing.T, *servicecatalogv1beta1.ServiceInstance)) func(string, string, services.Mutator) (*servicecatalogv1beta1.ServiceInstance, error) {
+		return func(_, _ string, mutator services.Mutator) (*servicecatalogv1beta1.ServiceInstance, error) {
+			testutil.AssertNil(t, "mutator error", mutator(&instance))
+			check(t, &instance)
+			return &instance, nil
+		}
+	}
+
+	cases := map[string]struct {
+		args            []string
+		namespace       string
+		setup           func(*testing.T, fakes)
+		expectErr       error
+		expectedStrings []string
+	}{
+		"bad number of args": {
+			expectErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"bad namespace": {
+			args:      []string{"mydb", "-p", "gold"},
+			expectErr: errors.New(utils.EmptyNamespaceError),
+		},
+		"no changes": {
+			namespace: "test-ns",
+			args:      []string{"mydb"},
+			expectErr: errors.New("at least one of --plan or --config must be set"),
+		},
+		"bad path": {
+			namespace: "test-ns",
+			args:      []string{"mydb", "--config=/some/bad/path"},
+			expectErr: errors.New("couldn't read file: open /some/bad/path: no such file or directory"),
+		},
+		"transform failure": {
+			namespace: "test-ns",
+			args:      []string{"mydb", "-p", "gold"},
+			setup: func(t *testing.T, fakes fakes) {
+				fakes.services.EXPECT().Transform("test-ns", "mydb", gomock.Any()).Return(nil, errors.New("some-error"))
+			},
+			expectErr: errors.New("some-error"),
+		},
+		"cluster plan": {
+			namespace: "test-ns",
+			args:      []string{"mydb", "-p", "gold"},
+			setup: func(t *testing.T, fakes fakes) {
+				instance := servicecatalogv1beta1.ServiceInstance{}
+				instance.Spec.ClusterServiceClassExternalName = "db-service"
+				instance.Spec.ClusterServicePlanExternalName = "silver"
+				instance.Spec.ClusterServicePlanRef = &servicecatalogv1beta1.ClusterObjectReference{Name: "silver-id"}
+				instance.Spec.UpdateRequests = 3
+
+				fakes.services.EXPECT().
+					Transform("test-ns", "mydb", gomock.Any()).
+					DoAndReturn(transform(t, instance, func(t *testing.T, instance *servicecatalogv1beta1.ServiceInstance) {
+						testutil.AssertEqual(t, "plan", "gold", instance.Spec.ClusterServicePlanExternalName)
+						testutil.AssertEqual(t, "namespaced plan", "", instance.Spec.ServicePlanExternalName)
+						testutil.AssertEqual(t, "plan ref cleared", true, instance.Spec.ClusterServicePlanRef == nil)
+						testutil.AssertEqual(t, "parameters unset", true, instance.Spec.Parameters == nil)
+						testutil.AssertEqual(t, "update requests", int64(4), instance.Spec.UpdateRequests)
+					}))
+				fakes.services.EXPECT().WaitForProvisionSuccess(gomock.Any(), "test-ns", "mydb", gomock.Any()).Return(&instance, nil)
+				fakes.bindings.EXPECT().List(gomock.Any())
+			},
+		},
+		"namespaced plan and parameters": {
+			namespace: "test-ns",
+			args:      []string{"mydb", "-p", "gold", "-c", `{"ram_gb":8}`},
+			setup: func(t *testing.T, fakes fakes) {
+				instance := servicecatalogv1beta1.ServiceInstance{}
+				instance.Spec.ServiceClassExternalName = "db-service"
+				instance.Spec.ServicePlanExternalName = "silver"
+				instance.Spec.ServicePlanRef = &servicecatalogv1beta1.LocalObjectReference{Name: "silver-id"}
+
+				fakes.services.EXPECT().
+					Transform("test-ns", "mydb", gomock.Any()).
+					DoAndReturn(transform(t, instance, func(t *testing.T, instance *servicecatalogv1beta1.ServiceInstance) {
+						testutil.AssertEqual(t, "plan", "gold", instance.Spec.ServicePlanExternalName)
+						testutil.AssertEqual(t, "cluster plan", "", instance.Spec.ClusterServicePlanExternalName)
+						testutil.AssertEqual(t, "plan ref cleared", true, instance.Spec.ServicePlanRef == nil)
+						testutil.AssertEqual(t, "parameters", json.RawMessage(`{"ram_gb":8}`), json.RawMessage(instance.Spec.Parameters.Raw))
+					}))
+				fakes.services.EXPECT().WaitForProvisionSuccess(gomock.Any(), "test-ns", "mydb", gomock.Any()).Return(&instance, nil)
+				fakes.bindings.EXPECT().List(gomock.Any())
+			},
+		},
+		"update failure": {
+			namespace: "test-ns",
+			args:      []string{"mydb", "-p", "gold"},
+			setup: func(t *testing.T, fakes fakes) {
+				fakes.services.EXPECT().Transform("test-ns", "mydb", gomock.Any())
+				fakes.services.EXPECT().WaitForProvisionSuccess(gomock.Any(), "test-ns", "mydb", gomock.Any()).Return(nil, errors.New("provision failed"))
+			},
+			expectErr: errors.New("provision failed"),
+		},
+		"bound apps are listed": {
+			namespace: "test-ns",
+			args:      []string{"mydb", "-c", `{"ram_gb":8}`},
+			setup: func(t *testing.T, fakes fakes) {
+				fakes.services.EXPECT().Transform("test-ns", "mydb", gomock.Any())
+				fakes.services.EXPECT().WaitForProvisionSuccess(gomock.Any(), "test-ns", "mydb", gomock.Any()).Return(&servicecatalogv1beta1.ServiceInstance{}, nil)
+				fakes.bindings.EXPECT().List(gomock.Any()).Return([]servicecatalogv1beta1.ServiceBinding{
+					{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:   "kf-binding-myapp-mydb",
+							Labels: map[string]string{v1alpha1.NameLabel: "myapp"},
+						},
+					},
+				}, nil)
+			},
+			expectedStrings: []string{"kf-binding-myapp-mydb", "myapp", "kf restart"},
+		},
+		"async skips wait": {
+			namespace: "test-ns",
+			args:      []string{"mydb", "-p", "gold", "--async"},
+			setup: func(t *testing.T, fakes fakes) {
+				fakes.services.EXPECT().Transform("test-ns", "mydb", gomock.Any())
+				fakes.bindings.EXPECT().List(gomock.Any())
+				// expect WaitForProvisionSuccess not to be called
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			sClient := servicesfake.NewFakeClient(ctrl)
+			bClient := servicebindingsfake.NewFakeClientInterface(ctrl)
+			if tc.setup != nil {
+				tc.setup(t, fakes{
+					services: sClient,
+					bindings: bClient,
+				})
+			}
+
+			buf := new(bytes.Buffer)
+			p := &config.KfParams{
+				Namespace: tc.namespace,
+			}
+
+			cmd := servicescmd.NewUpdateServiceCommand(p, sClient, bClient)
+			cmd.SetOutput(buf)
+			cmd.SetArgs(tc.args)
+			_, actualErr := cmd.ExecuteC()
+			if tc.expectErr != nil || actualErr != nil {
+				testutil.AssertErrorsEqual(t, tc.expectErr, actualErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buf.String(), tc.expectedStrings)
+		})
+	}
+}
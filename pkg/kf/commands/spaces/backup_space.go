@@ -0,0 +1,193 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+const (
+	// SpaceLabel is added to backups to identify the space they capture.
+	SpaceLabel = "kf.dev/space"
+
+	// SpaceSpecAnnotation holds the JSON spec of the space at backup time so
+	// it can be re-applied with configure-space apply after a restore.
+	SpaceSpecAnnotation = "kf.dev/space-spec"
+)
+
+// veleroBackup is the subset of the velero.io/v1 Backup type kf produces.
+type veleroBackup struct {
+	APIVersion string           `json:"apiVersion"`
+	Kind       string           `json:"kind"`
+	Metadata   veleroBackupMeta `json:"metadata"`
+	Spec       veleroBackupSpec `json:"spec"`
+}
+
+type veleroBackupMeta struct {
+	Name        string            `json:"name"`
+	Namespace   string            `json:"namespace"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type veleroBackupSpec struct {
+	IncludedNamespaces []string `json:"includedNamespaces"`
+	StorageLocation    string   `json:"storageLocation,omitempty"`
+	TTL                string   `json:"ttl,omitempty"`
+}
+
+// NewBackupSpaceCommand creates a command that writes a Velero Backup for a
+// space.
+func NewBackupSpaceCommand(
+	p *config.KfParams,
+	client spaces.Client,
+	appsClient apps.Client,
+) *cobra.Command {
+	var (
+		backupName      string
+		veleroNamespace string
+		storageLocation string
+		ttl             time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "backup-space SPACE_NAME",
+		Short: "Write a Velero Backup that captures a space",
+		Long: `Write a Velero Backup that captures a space.
+
+		The Backup is written to stdout so it can be reviewed, checked in, or
+		applied with kubectl. It includes the space's namespace and records the
+		space's configuration in the kf.dev/space-spec annotation so it can be
+		restored with "kf configure-space apply".
+
+		Service bindings with backup hooks (see "kf configure-binding
+		set-backup-hooks") are run by Velero in each App instance before the
+		backup is taken and after it is restored.
+		`,
+		Example: `
+		kf backup-space my-space | kubectl apply -f -
+		kf backup-space my-space --name nightly --ttl 168h
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			spaceName := args[0]
+
+			space, err := client.Get(spaceName)
+			if err != nil {
+				return err
+			}
+
+			appList, err := appsClient.List(spaceName)
+			if err != nil {
+				return err
+			}
+
+			specJSON, err := json.Marshal(space.Spec)
+			if err != nil {
+				return err
+			}
+
+			if backupName == "" {
+				backupName = fmt.Sprintf("%s-%s", spaceName, time.Now().UTC().Format("20060102150405"))
+			}
+
+			backup := veleroBackup{
+				APIVersion: "velero.io/v1",
+				Kind:       "Backup",
+				Metadata: veleroBackupMeta{
+					Name:      backupName,
+					Namespace: veleroNamespace,
+					Labels: map[string]string{
+						SpaceLabel: spaceName,
+					},
+					Annotations: map[string]string{
+						SpaceSpecAnnotation: string(specJSON),
+					},
+				},
+				Spec: veleroBackupSpec{
+					IncludedNamespaces: []string{spaceName},
+					StorageLocation:    storageLocation,
+					TTL:                ttl.String(),
+				},
+			}
+
+			out, err := k8syaml.Marshal(backup)
+			if err != nil {
+				return err
+			}
+
+			for _, app := range appList {
+				for _, binding := range app.Spec.ServiceBindings {
+					if binding.BackupHooks == nil {
+						continue
+					}
+
+					name := binding.BindingName
+					if name == "" {
+						name = binding.Instance
+					}
+
+					fmt.Fprintf(cmd.ErrOrStderr(), "App %q will run backup hooks for binding %q\n", app.Name, name)
+				}
+			}
+
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&backupName,
+		"name",
+		"",
+		"Name of the Backup, defaults to the space name and the current time",
+	)
+
+	cmd.Flags().StringVar(
+		&veleroNamespace,
+		"velero-namespace",
+		"velero",
+		"Namespace Velero is installed in",
+	)
+
+	cmd.Flags().StringVar(
+		&storageLocation,
+		"storage-location",
+		"",
+		"Velero BackupStorageLocation to store the Backup in",
+	)
+
+	cmd.Flags().DurationVar(
+		&ttl,
+		"ttl",
+		720*time.Hour,
+		"How long the Backup is kept before Velero deletes it",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
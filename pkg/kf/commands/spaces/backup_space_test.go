@@ -0,0 +1,138 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	appsfake "github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/spaces/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewBackupSpaceCommand(t *testing.T) {
+	t.Parallel()
+
+	space := &v1alpha1.Space{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-space"},
+		Spec: v1alpha1.SpaceSpec{
+			Execution: v1alpha1.SpaceSpecExecution{
+				Env: []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		args       []string
+		setup      func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient)
+		wantErr    error
+		wantOut    []string
+		wantStderr []string
+	}{
+		"invalid number of args": {
+			args:    []string{},
+			wantErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"space get fails": {
+			args: []string{"my-space"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("my-space").Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("some-error"),
+		},
+		"apps list fails": {
+			args: []string{"my-space"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("my-space").Return(space, nil)
+				fakeApps.EXPECT().List("my-space").Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("some-error"),
+		},
+		"writes backup": {
+			args: []string{"my-space", "--name", "nightly", "--ttl", "24h", "--storage-location", "gcs"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("my-space").Return(space, nil)
+				fakeApps.EXPECT().List("my-space").Return([]v1alpha1.App{
+					{
+						ObjectMeta: metav1.ObjectMeta{Name: "my-app"},
+						Spec: v1alpha1.AppSpec{
+							ServiceBindings: []v1alpha1.AppSpecServiceBinding{
+								{Instance: "no-hooks"},
+								{
+									Instance: "my-db",
+									BackupHooks: &v1alpha1.AppSpecServiceBindingBackupHooks{
+										PreBackup: "flush",
+									},
+								},
+							},
+						},
+					},
+				}, nil)
+			},
+			wantOut: []string{
+				"apiVersion: velero.io/v1",
+				"kind: Backup",
+				"name: nightly",
+				"namespace: velero",
+				"kf.dev/space: my-space",
+				`kf.dev/space-spec: '{"`,
+				"- my-space",
+				"storageLocation: gcs",
+				"ttl: 24h0m0s",
+			},
+			wantStderr: []string{
+				`App "my-app" will run backup hooks for binding "my-db"`,
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+			fakeApps := appsfake.NewFakeClient(ctrl)
+
+			if tc.setup != nil {
+				tc.setup(t, fakeSpaces, fakeApps)
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			c := NewBackupSpaceCommand(&config.KfParams{}, fakeSpaces, fakeApps)
+			c.SetOutput(stdout)
+			c.SetErr(stderr)
+			c.SetArgs(tc.args)
+
+			gotErr := c.Execute()
+			testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+			if gotErr != nil {
+				return
+			}
+
+			testutil.AssertContainsAll(t, stdout.String(), tc.wantOut)
+			testutil.AssertContainsAll(t, stderr.String(), tc.wantStderr)
+
+			ctrl.Finish()
+		})
+	}
+}
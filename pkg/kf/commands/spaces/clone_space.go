@@ -0,0 +1,154 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	networkingv1client "k8s.io/client-go/kubernetes/typed/networking/v1"
+)
+
+// NewCloneSpaceCommand creates a command that copies a space's
+// configuration, and optionally its Apps, to a new space.
+func NewCloneSpaceCommand(
+	p *config.KfParams,
+	client spaces.Client,
+	appsClient apps.Client,
+	networkPolicies networkingv1client.NetworkPoliciesGetter,
+) *cobra.Command {
+	var withoutApps bool
+
+	cmd := &cobra.Command{
+		Use:   "clone-space SRC DEST [--without-apps]",
+		Short: "Create a space with the configuration and Apps of another",
+		Long: `Create a space with the configuration and Apps of another.
+
+		The new space gets the source space's environment variables, build
+		settings, quota, domains, and NetworkPolicies. Each domain label that
+		matches the source space's name is replaced with the new space's name,
+		so api.dev.example.com becomes api.staging.example.com when cloning dev
+		to staging.
+
+		Apps are re-pushed from the source images they were last pushed with
+		and built again in the new space. Service instances belong to the
+		space they were created in, so bindings aren't copied.
+
+		Secrets are copied by reference, not by value. Configuration that
+		reads from Secrets keeps referencing them by name, and the Secrets
+		must be created in the new space.
+		`,
+		Example: `
+		kf clone-space dev staging
+		kf clone-space dev staging --without-apps
+		`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			srcName, destName := args[0], args[1]
+			w := cmd.OutOrStdout()
+
+			src, err := client.Get(srcName)
+			if err != nil {
+				return err
+			}
+
+			var appList []v1alpha1.App
+			if !withoutApps {
+				if appList, err = appsClient.List(srcName); err != nil {
+					return err
+				}
+			}
+
+			policies, err := networkPolicies.NetworkPolicies(srcName).List(metav1.ListOptions{})
+			if err != nil {
+				return fmt.Errorf("failed to list NetworkPolicies: %s", err)
+			}
+
+			clone := spaces.CloneSpace(src, destName)
+			for i, domain := range src.Spec.Execution.Domains {
+				if cloned := clone.Spec.Execution.Domains[i].Domain; cloned != domain.Domain {
+					fmt.Fprintf(w, "Domain %s is now %s\n", domain.Domain, cloned)
+				}
+			}
+
+			if _, err := client.Create(clone); err != nil {
+				return err
+			}
+
+			fmt.Fprintln(w, "Space requested, waiting for subcomponents to be created")
+			if _, err := client.WaitFor(context.Background(), destName, 1*time.Second, spaces.IsStatusFinal); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "Space %s created\n", destName)
+
+			for _, policy := range policies.Items {
+				copied := &networkingv1.NetworkPolicy{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        policy.Name,
+						Labels:      policy.Labels,
+						Annotations: policy.Annotations,
+					},
+					Spec: *policy.Spec.DeepCopy(),
+				}
+
+				if _, err := networkPolicies.NetworkPolicies(destName).Create(copied); err != nil {
+					return fmt.Errorf("failed to copy NetworkPolicy %s: %s", policy.Name, err)
+				}
+				fmt.Fprintf(w, "Copied NetworkPolicy %s\n", policy.Name)
+			}
+
+			for i := range appList {
+				app := &appList[i]
+				if _, err := appsClient.Create(destName, spaces.CloneApp(app, srcName, destName)); err != nil {
+					return fmt.Errorf("failed to push App %s: %s", app.Name, err)
+				}
+				fmt.Fprintf(w, "Pushing App %s\n", app.Name)
+
+				for _, binding := range app.Spec.ServiceBindings {
+					fmt.Fprintf(cmd.ErrOrStderr(), "App %q wasn't bound to service %q, create and bind it in %s\n", app.Name, binding.Instance, destName)
+				}
+			}
+
+			if secrets := spaces.SecretReferences(src, appList); len(secrets) > 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Create these Secrets in %s, the cloned configuration references them: %s\n", destName, strings.Join(secrets, ", "))
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(
+		&withoutApps,
+		"without-apps",
+		false,
+		"Only copy the space's configuration, not its Apps.",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	appsfake "github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/spaces/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewCloneSpaceCommand(t *testing.T) {
+	t.Parallel()
+
+	space := &v1alpha1.Space{
+		ObjectMeta: metav1.ObjectMeta{Name: "dev"},
+		Spec: v1alpha1.SpaceSpec{
+			Execution: v1alpha1.SpaceSpecExecution{
+				Env: []corev1.EnvVar{{
+					Name: "TOKEN",
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "api-token"},
+							Key:                  "token",
+						},
+					},
+				}},
+				Domains: []v1alpha1.SpaceDomain{{Domain: "dev.example.com", Default: true}},
+			},
+		},
+	}
+
+	app := v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "dev"},
+		Spec: v1alpha1.AppSpec{
+			Source: v1alpha1.SourceSpec{
+				BuildpackBuild: v1alpha1.SourceSpecBuildpackBuild{
+					Source: "gcr.io/foo/src-dev-my-app:abc",
+				},
+			},
+			Routes: []v1alpha1.RouteSpecFields{
+				{Hostname: "my-app", Domain: "dev.example.com"},
+			},
+			ServiceBindings: []v1alpha1.AppSpecServiceBinding{
+				{Instance: "my-db"},
+			},
+		},
+	}
+
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "deny-all", Namespace: "dev"},
+	}
+
+	cases := map[string]struct {
+		args       []string
+		setup      func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient)
+		wantErr    error
+		wantOut    []string
+		wantStderr []string
+		wantCopied []string
+	}{
+		"invalid number of args": {
+			args:    []string{"dev"},
+			wantErr: errors.New("accepts 2 arg(s), received 1"),
+		},
+		"space get fails": {
+			args: []string{"dev", "staging"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("dev").Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("some-error"),
+		},
+		"space create fails": {
+			args: []string{"dev", "staging", "--without-apps"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("dev").Return(space, nil)
+				fakeSpaces.EXPECT().Create(gomock.Any()).Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("some-error"),
+		},
+		"app create fails": {
+			args: []string{"dev", "staging"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("dev").Return(space, nil)
+				fakeApps.EXPECT().List("dev").Return([]v1alpha1.App{app}, nil)
+				fakeSpaces.EXPECT().Create(gomock.Any())
+				fakeSpaces.EXPECT().WaitFor(gomock.Any(), "staging", gomock.Any(), gomock.Any())
+				fakeApps.EXPECT().Create("staging", gomock.Any()).Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("failed to push App my-app: some-error"),
+		},
+		"without apps": {
+			args: []string{"dev", "staging", "--without-apps"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("dev").Return(space, nil)
+				fakeSpaces.EXPECT().Create(gomock.Any()).Do(func(s *v1alpha1.Space) {
+					testutil.AssertEqual(t, "name", "staging", s.Name)
+					testutil.AssertEqual(t, "domain", "staging.example.com", s.Spec.Execution.Domains[0].Domain)
+				})
+				fakeSpaces.EXPECT().WaitFor(gomock.Any(), "staging", gomock.Any(), gomock.Any())
+			},
+			wantOut: []string{
+				"Domain dev.example.com is now staging.example.com",
+				"Space staging created",
+				"Copied NetworkPolicy deny-all",
+			},
+			wantStderr: []string{
+				"Create these Secrets in staging, the cloned configuration references them: api-token",
+			},
+			wantCopied: []string{"deny-all"},
+		},
+		"with apps": {
+			args: []string{"dev", "staging"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("dev").Return(space, nil)
+				fakeApps.EXPECT().List("dev").Return([]v1alpha1.App{app}, nil)
+				fakeSpaces.EXPECT().Create(gomock.Any())
+				fakeSpaces.EXPECT().WaitFor(gomock.Any(), "staging", gomock.Any(), gomock.Any())
+				fakeApps.EXPECT().Create("staging", gomock.Any()).Do(func(namespace string, a *v1alpha1.App) {
+					testutil.AssertEqual(t, "source", "gcr.io/foo/src-dev-my-app:abc", a.Spec.Source.BuildpackBuild.Source)
+					testutil.AssertEqual(t, "domain", "staging.example.com", a.Spec.Routes[0].Domain)
+					testutil.AssertEqual(t, "bindings", 0, len(a.Spec.ServiceBindings))
+				})
+			},
+			wantOut: []string{
+				"Pushing App my-app",
+			},
+			wantStderr: []string{
+				`App "my-app" wasn't bound to service "my-db", create and bind it in staging`,
+			},
+			wantCopied: []string{"deny-all"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+			fakeApps := appsfake.NewFakeClient(ctrl)
+			k8sClient := k8sfake.NewSimpleClientset([]runtime.Object{policy.DeepCopy()}...).NetworkingV1()
+
+			if tc.setup != nil {
+				tc.setup(t, fakeSpaces, fakeApps)
+			}
+
+			stdout := &bytes.Buffer{}
+			stderr := &bytes.Buffer{}
+
+			c := NewCloneSpaceCommand(&config.KfParams{}, fakeSpaces, fakeApps, k8sClient)
+			c.SetOutput(stdout)
+			c.SetErr(stderr)
+			c.SetArgs(tc.args)
+
+			gotErr := c.Execute()
+			testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+			if gotErr != nil {
+				return
+			}
+
+			testutil.AssertContainsAll(t, stdout.String(), tc.wantOut)
+			testutil.AssertContainsAll(t, stderr.String(), tc.wantStderr)
+
+			copied, err := k8sClient.NetworkPolicies("staging").List(metav1.ListOptions{})
+			testutil.AssertNil(t, "list err", err)
+
+			var names []string
+			for _, p := range copied.Items {
+				names = append(names, p.Name)
+			}
+			testutil.AssertEqual(t, "copied policies", tc.wantCopied, names)
+
+			ctrl.Finish()
+		})
+	}
+}
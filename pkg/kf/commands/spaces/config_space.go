@@ -15,7 +15,12 @@
 package spaces
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
@@ -24,6 +29,7 @@ import (
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
 	"github.com/google/kf/pkg/kf/commands/quotas"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
 	"github.com/google/kf/pkg/kf/spaces"
 	"github.com/spf13/cobra"
 	corev1 "k8s.io/api/core/v1"
@@ -55,12 +61,30 @@ func NewConfigSpaceCommand(p *config.KfParams, client spaces.Client) *cobra.Comm
 		newSetEnvMutator(),
 		newUnsetEnvMutator(),
 		newSetBuildpackEnvMutator(),
+		newSetBuildpackEnvFromSecretMutator(),
 		newUnsetBuildpackEnvMutator(),
 		newSetContainerRegistryMutator(),
 		newSetBuildpackBuilderMutator(),
+		newSetDefaultStackMutator(),
 		newAppendDomainMutator(),
 		newSetDefaultDomainMutator(),
 		newRemoveDomainMutator(),
+		newSetDomainTLSMutator(),
+		newSetHTTPSRedirectMutator(),
+		newSetMaxConcurrentBuildsMutator(),
+		newSetBuildLogBucketMutator(),
+		newSetSSHPolicyMutator(),
+		newSetDeletionProtectionMutator(),
+		newSetResponseHeaderMutator(),
+		newUnsetResponseHeaderMutator(),
+		newSetDefaultHealthCheckMutator(),
+		newSetStartupTimeoutMutator(),
+		newReserveHostnameMutator(),
+		newUnreserveHostnameMutator(),
+		newGrantHostnameMutator(),
+		newRevokeHostnameMutator(),
+		newUnsetNotificationMutator(),
+		newRemoveBuildpackMutator(),
 	}
 
 	for _, sm := range subcommands {
@@ -70,15 +94,37 @@ func NewConfigSpaceCommand(p *config.KfParams, client spaces.Client) *cobra.Comm
 	accessors := []spaceAccessor{
 		newGetContainerRegistryAccessor(),
 		newGetBuildpackBuilderAccessor(),
+		newGetDefaultStackAccessor(),
 		newGetExecutionEnvAccessor(),
 		newGetBuildpackEnvAccessor(),
 		newGetDomainsAccessor(),
+		newGetMaxConcurrentBuildsAccessor(),
+		newGetBuildLogBucketAccessor(),
+		newGetSSHPolicyAccessor(),
+		newGetResponseHeadersAccessor(),
+		newGetEgressPolicyAccessor(),
+		newGetDefaultHealthCheckAccessor(),
+		newGetReservedHostnamesAccessor(),
+		newGetSidecarResourcesAccessor(),
+		newGetNotificationsAccessor(),
+		newGetBuildpacksAccessor(),
 	}
 
 	for _, sa := range accessors {
 		cmd.AddCommand(sa.ToCommand(client))
 	}
 
+	cmd.AddCommand(
+		newGetSpaceCommand(client),
+		newSetEgressPolicyCommand(client),
+		newSetSidecarResourcesCommand(client),
+		newSetNotificationCommand(client),
+		newAddBuildpackCommand(client),
+		newPlanSpaceCommand(client),
+		newApplySpaceCommand(client),
+		newDiffSpaceCommand(client),
+	)
+
 	cmd.AddCommand(
 		quotas.NewGetQuotaCommand(p, client),
 		quotas.NewUpdateQuotaCommand(p, client),
@@ -97,6 +143,8 @@ type spaceMutator struct {
 }
 
 func (sm spaceMutator) ToCommand(client spaces.Client) *cobra.Command {
+	var diffFlags utils.DiffFlags
+
 	cmd := &cobra.Command{
 		Use:     fmt.Sprintf("%s SPACE_NAME %s", sm.Name, strings.Join(sm.Args, " ")),
 		Short:   sm.Short,
@@ -111,14 +159,21 @@ func (sm spaceMutator) ToCommand(client spaces.Client) *cobra.Command {
 				return err
 			}
 
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
 			cmd.SilenceUsage = true
 
-			diffPrintingMutator := spaces.DiffWrapper(cmd.OutOrStdout(), mutator)
+			diffPrintingMutator := spaces.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...)
 			_, err = client.Transform(spaceName, diffPrintingMutator)
 			return err
 		},
 	}
 
+	diffFlags.Add(cmd)
+
 	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
 
 	return cmd
@@ -160,6 +215,24 @@ func newSetBuildpackBuilderMutator() spaceMutator {
 	}
 }
 
+func newSetDefaultStackMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-default-stack",
+		Short:       "Set the stack apps are built with unless they choose one.",
+		Args:        []string{"STACK"},
+		ExampleArgs: []string{"cflinuxfs3"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			stack := args[0]
+
+			return func(space *v1alpha1.Space) error {
+				space.Spec.BuildpackBuild.DefaultStack = stack
+
+				return nil
+			}, nil
+		},
+	}
+}
+
 func newSetEnvMutator() spaceMutator {
 	return spaceMutator{
 		Name:        "set-env",
@@ -170,9 +243,15 @@ func newSetEnvMutator() spaceMutator {
 			name := args[0]
 			value := args[1]
 
+			if err := envutil.ValidateEnvVarName(name); err != nil {
+				return nil, err
+			}
+
 			return func(space *v1alpha1.Space) error {
-				tmp := envutil.RemoveEnvVars([]string{name}, space.Spec.Execution.Env)
-				space.Spec.Execution.Env = append(tmp, corev1.EnvVar{Name: name, Value: value})
+				space.Spec.Execution.Env = envutil.OverrideEnvVars(
+					space.Spec.Execution.Env,
+					[]corev1.EnvVar{{Name: name, Value: value}},
+				)
 
 				return nil
 			}, nil
@@ -208,9 +287,45 @@ func newSetBuildpackEnvMutator() spaceMutator {
 			name := args[0]
 			value := args[1]
 
+			if err := envutil.ValidateEnvVarName(name); err != nil {
+				return nil, err
+			}
+
 			return func(space *v1alpha1.Space) error {
-				tmp := envutil.RemoveEnvVars([]string{name}, space.Spec.BuildpackBuild.Env)
-				space.Spec.BuildpackBuild.Env = append(tmp, corev1.EnvVar{Name: name, Value: value})
+				space.Spec.BuildpackBuild.Env = envutil.OverrideEnvVars(
+					space.Spec.BuildpackBuild.Env,
+					[]corev1.EnvVar{{Name: name, Value: value}},
+				)
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newSetBuildpackEnvFromSecretMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-buildpack-env-from-secret",
+		Short:       "Set a buildpack build env var from a Secret, KEY defaults to the var name.",
+		Args:        []string{"ENV_VAR_NAME", "SECRET_NAME[:KEY]"},
+		ExampleArgs: []string{"NPM_TOKEN", "build-creds:npm-token"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			name := args[0]
+
+			if err := envutil.ValidateEnvVarName(name); err != nil {
+				return nil, err
+			}
+
+			env, err := envutil.NewSecretEnvVar(name, args[1])
+			if err != nil {
+				return nil, err
+			}
+
+			return func(space *v1alpha1.Space) error {
+				space.Spec.BuildpackBuild.Env = envutil.OverrideEnvVars(
+					space.Spec.BuildpackBuild.Env,
+					[]corev1.EnvVar{env},
+				)
 
 				return nil
 			}, nil
@@ -244,11 +359,23 @@ func newAppendDomainMutator() spaceMutator {
 		ExampleArgs: []string{"myspace.mycompany.com"},
 		Init: func(args []string) (spaces.Mutator, error) {
 			domain := args[0]
+			if err := v1alpha1.ValidateSpaceDomain(domain); err != nil {
+				return nil, err
+			}
 
 			return func(space *v1alpha1.Space) error {
+				hasDefault := false
+				for _, d := range space.Spec.Execution.Domains {
+					if d.Domain == domain {
+						return fmt.Errorf("space already has the domain %s", domain)
+					}
+					hasDefault = hasDefault || d.Default
+				}
+
+				// The first domain of a space becomes its default.
 				space.Spec.Execution.Domains = append(
 					space.Spec.Execution.Domains,
-					v1alpha1.SpaceDomain{Domain: domain},
+					v1alpha1.SpaceDomain{Domain: domain, Default: !hasDefault},
 				)
 
 				return nil
@@ -296,10 +423,11 @@ func newRemoveDomainMutator() spaceMutator {
 			domain := args[0]
 
 			return func(space *v1alpha1.Space) error {
-				space.Spec.Execution.Domains = []v1alpha1.SpaceDomain(algorithms.Delete(
-					v1alpha1.SpaceDomains(space.Spec.Execution.Domains),
-					v1alpha1.SpaceDomains{{Domain: domain}},
-				).(v1alpha1.SpaceDomains))
+				space.Spec.Execution.Domains = algorithms.Delete(
+					space.Spec.Execution.Domains,
+					[]v1alpha1.SpaceDomain{{Domain: domain}},
+					v1alpha1.CompareSpaceDomains,
+				)
 
 				return nil
 			}, nil
@@ -307,6 +435,409 @@ func newRemoveDomainMutator() spaceMutator {
 	}
 }
 
+func newSetDomainTLSMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-domain-tls",
+		Short:       "Set the Secret used to serve HTTPS for a domain, empty to remove it.",
+		Args:        []string{"DOMAIN", "SECRET_NAME"},
+		ExampleArgs: []string{"myspace.mycompany.com", "myspace-cert"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			domain := args[0]
+			secretName := args[1]
+
+			return func(space *v1alpha1.Space) error {
+				return transformDomain(space, domain, func(d *v1alpha1.SpaceDomain) {
+					d.TLSSecretName = secretName
+
+					// Redirecting without a certificate would make the domain
+					// unreachable.
+					if secretName == "" {
+						d.RedirectHTTPS = false
+					}
+				})
+			}, nil
+		},
+	}
+}
+
+const (
+	httpsRedirectEnabled  = "enabled"
+	httpsRedirectDisabled = "disabled"
+)
+
+func newSetHTTPSRedirectMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-https-redirect",
+		Short:       "Set whether HTTP requests for a domain redirect to HTTPS, enabled or disabled.",
+		Args:        []string{"DOMAIN", "POLICY"},
+		ExampleArgs: []string{"myspace.mycompany.com", httpsRedirectEnabled},
+		Init: func(args []string) (spaces.Mutator, error) {
+			domain := args[0]
+
+			var enabled bool
+			switch args[1] {
+			case httpsRedirectEnabled:
+				enabled = true
+			case httpsRedirectDisabled:
+				enabled = false
+			default:
+				return nil, fmt.Errorf("POLICY must be %s or %s, got %q", httpsRedirectEnabled, httpsRedirectDisabled, args[1])
+			}
+
+			return func(space *v1alpha1.Space) error {
+				return transformDomain(space, domain, func(d *v1alpha1.SpaceDomain) {
+					d.RedirectHTTPS = enabled
+				})
+			}, nil
+		},
+	}
+}
+
+// transformDomain applies the function to the named domain of the space.
+func transformDomain(space *v1alpha1.Space, domain string, f func(*v1alpha1.SpaceDomain)) error {
+	for i := range space.Spec.Execution.Domains {
+		if space.Spec.Execution.Domains[i].Domain == domain {
+			f(&space.Spec.Execution.Domains[i])
+			return nil
+		}
+	}
+
+	return fmt.Errorf("failed to find domain %s", domain)
+}
+
+func newSetMaxConcurrentBuildsMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-max-concurrent-builds",
+		Short:       "Set how many builds can run at once in a space, 0 means unlimited.",
+		Args:        []string{"MAX_BUILDS"},
+		ExampleArgs: []string{"5"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			maxBuilds, err := strconv.Atoi(args[0])
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse MAX_BUILDS: %v", err)
+			}
+
+			if maxBuilds < 0 {
+				return nil, errors.New("MAX_BUILDS must be 0 or greater")
+			}
+
+			return func(space *v1alpha1.Space) error {
+				space.Spec.BuildpackBuild.MaxConcurrentBuilds = maxBuilds
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newSetBuildLogBucketMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-build-log-bucket",
+		Short:       "Set the Cloud Storage bucket build logs are archived to, empty to stop.",
+		Args:        []string{"BUCKET"},
+		ExampleArgs: []string{"gs://my-build-logs"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			bucket := args[0]
+
+			if bucket != "" {
+				if err := v1alpha1.ValidateBuildLogBucket(bucket); err != nil {
+					return nil, err
+				}
+			}
+
+			return func(space *v1alpha1.Space) error {
+				space.Spec.BuildpackBuild.LogBucket = bucket
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+const (
+	sshPolicyEnabled  = "enabled"
+	sshPolicyDisabled = "disabled"
+)
+
+func newSetSSHPolicyMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-ssh-policy",
+		Short:       "Set whether kf ssh can open shells in apps, enabled or disabled.",
+		Args:        []string{"POLICY"},
+		ExampleArgs: []string{sshPolicyDisabled},
+		Init: func(args []string) (spaces.Mutator, error) {
+			var enabled bool
+			switch args[0] {
+			case sshPolicyEnabled:
+				enabled = true
+			case sshPolicyDisabled:
+				enabled = false
+			default:
+				return nil, fmt.Errorf("POLICY must be %s or %s, got %q", sshPolicyEnabled, sshPolicyDisabled, args[0])
+			}
+
+			return func(space *v1alpha1.Space) error {
+				space.Spec.Security.EnableSSH = enabled
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+const (
+	deletionProtectionOn  = "on"
+	deletionProtectionOff = "off"
+)
+
+func newSetDeletionProtectionMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-deletion-protection",
+		Short:       "Set whether deleting the space needs --force-delete-protected, on or off.",
+		Args:        []string{"SETTING"},
+		ExampleArgs: []string{deletionProtectionOn},
+		Init: func(args []string) (spaces.Mutator, error) {
+			var enabled bool
+			switch args[0] {
+			case deletionProtectionOn:
+				enabled = true
+			case deletionProtectionOff:
+				enabled = false
+			default:
+				return nil, fmt.Errorf("SETTING must be %s or %s, got %q", deletionProtectionOn, deletionProtectionOff, args[0])
+			}
+
+			return func(space *v1alpha1.Space) error {
+				v1alpha1.SetDeletionProtection(space, enabled)
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newSetResponseHeaderMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-response-header",
+		Short:       "Set a header on every response served by Apps in the space.",
+		Args:        []string{"HEADER_NAME", "HEADER_VALUE"},
+		ExampleArgs: []string{"Strict-Transport-Security", "max-age=31536000"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			name := http.CanonicalHeaderKey(args[0])
+			value := args[1]
+
+			headers := map[string]string{name: value}
+			if errs := v1alpha1.ValidateResponseHeaders(headers).ViaField("responseHeaders"); errs != nil {
+				return nil, fmt.Errorf("invalid response header: %s", errs.Error())
+			}
+
+			return func(space *v1alpha1.Space) error {
+				if space.Spec.Execution.ResponseHeaders == nil {
+					space.Spec.Execution.ResponseHeaders = make(map[string]string)
+				}
+
+				removeHeader(space.Spec.Execution.ResponseHeaders, name)
+				space.Spec.Execution.ResponseHeaders[name] = value
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newUnsetResponseHeaderMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "unset-response-header",
+		Short:       "Stop setting a header on responses served by Apps in the space.",
+		Args:        []string{"HEADER_NAME"},
+		ExampleArgs: []string{"Strict-Transport-Security"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			name := args[0]
+
+			return func(space *v1alpha1.Space) error {
+				removeHeader(space.Spec.Execution.ResponseHeaders, name)
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newSetDefaultHealthCheckMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-default-health-check",
+		Short:       "Set the default app health check, port or http[:ENDPOINT], empty to unset.",
+		Args:        []string{"TYPE"},
+		ExampleArgs: []string{"http:/healthz"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			healthCheck := v1alpha1.SpaceDefaultHealthCheck{Type: args[0]}
+			if i := strings.Index(args[0], ":"); i >= 0 {
+				healthCheck.Type, healthCheck.HTTPEndpoint = args[0][:i], args[0][i+1:]
+			}
+
+			if errs := healthCheck.Validate(context.Background()); errs != nil {
+				return nil, fmt.Errorf("invalid health check: %s", errs.Error())
+			}
+
+			return func(space *v1alpha1.Space) error {
+				space.Spec.Execution.DefaultHealthCheck.Type = healthCheck.Type
+				space.Spec.Execution.DefaultHealthCheck.HTTPEndpoint = healthCheck.HTTPEndpoint
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newSetStartupTimeoutMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "set-startup-timeout",
+		Short:       "Set the default time apps have to become healthy after starting, 0 to unset.",
+		Args:        []string{"SECONDS"},
+		ExampleArgs: []string{"180"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			timeout, err := strconv.Atoi(args[0])
+			if err != nil {
+				return nil, fmt.Errorf("couldn't parse SECONDS: %v", err)
+			}
+
+			if timeout < 0 {
+				return nil, errors.New("SECONDS must be 0 or greater")
+			}
+
+			return func(space *v1alpha1.Space) error {
+				space.Spec.Execution.DefaultHealthCheck.StartupTimeoutSeconds = timeout
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newReserveHostnameMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "reserve-hostname",
+		Short:       "Reserve a hostname so apps in the space can't use it unless they're granted it.",
+		Args:        []string{"HOSTNAME"},
+		ExampleArgs: []string{"api"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			hostname := args[0]
+
+			return func(space *v1alpha1.Space) error {
+				for _, r := range space.Spec.Execution.ReservedHostnames {
+					if strings.EqualFold(r.Hostname, hostname) {
+						return fmt.Errorf("hostname %s is already reserved", hostname)
+					}
+				}
+
+				space.Spec.Execution.ReservedHostnames = append(
+					space.Spec.Execution.ReservedHostnames,
+					v1alpha1.SpaceReservedHostname{Hostname: hostname},
+				)
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newUnreserveHostnameMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "unreserve-hostname",
+		Short:       "Remove a hostname reservation so any app in the space can use it.",
+		Args:        []string{"HOSTNAME"},
+		ExampleArgs: []string{"api"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			hostname := args[0]
+
+			return func(space *v1alpha1.Space) error {
+				var reserved []v1alpha1.SpaceReservedHostname
+				for _, r := range space.Spec.Execution.ReservedHostnames {
+					if !strings.EqualFold(r.Hostname, hostname) {
+						reserved = append(reserved, r)
+					}
+				}
+				space.Spec.Execution.ReservedHostnames = reserved
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newGrantHostnameMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "grant-hostname",
+		Short:       "Allow an app to use a reserved hostname.",
+		Args:        []string{"HOSTNAME", "APP_NAME"},
+		ExampleArgs: []string{"api", "api-gateway"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			hostname := args[0]
+			appName := args[1]
+
+			return func(space *v1alpha1.Space) error {
+				return transformReservedHostname(space, hostname, func(r *v1alpha1.SpaceReservedHostname) {
+					for _, allowed := range r.AllowedApps {
+						if allowed == appName {
+							return
+						}
+					}
+
+					r.AllowedApps = append(r.AllowedApps, appName)
+				})
+			}, nil
+		},
+	}
+}
+
+func newRevokeHostnameMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "revoke-hostname",
+		Short:       "Stop allowing an app to use a reserved hostname.",
+		Args:        []string{"HOSTNAME", "APP_NAME"},
+		ExampleArgs: []string{"api", "api-gateway"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			hostname := args[0]
+			appName := args[1]
+
+			return func(space *v1alpha1.Space) error {
+				return transformReservedHostname(space, hostname, func(r *v1alpha1.SpaceReservedHostname) {
+					var allowedApps []string
+					for _, allowed := range r.AllowedApps {
+						if allowed != appName {
+							allowedApps = append(allowedApps, allowed)
+						}
+					}
+					r.AllowedApps = allowedApps
+				})
+			}, nil
+		},
+	}
+}
+
+// transformReservedHostname applies the function to the reservation for the
+// hostname, hostnames are case insensitive.
+func transformReservedHostname(space *v1alpha1.Space, hostname string, f func(*v1alpha1.SpaceReservedHostname)) error {
+	for i := range space.Spec.Execution.ReservedHostnames {
+		if strings.EqualFold(space.Spec.Execution.ReservedHostnames[i].Hostname, hostname) {
+			f(&space.Spec.Execution.ReservedHostnames[i])
+			return nil
+		}
+	}
+
+	return fmt.Errorf("hostname %s isn't reserved", hostname)
+}
+
+// removeHeader deletes the header from the map, header names are case
+// insensitive.
+func removeHeader(headers map[string]string, name string) {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			delete(headers, k)
+		}
+	}
+}
+
 type spaceAccessor struct {
 	Name     string
 	Short    string
@@ -330,33 +861,89 @@ func (sm spaceAccessor) ToCommand(client spaces.Client) *cobra.Command {
 				return err
 			}
 
-			out := sm.Accessor(space)
+			return printYAML(cmd.OutOrStdout(), sm.Accessor(space))
+		},
+	}
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
 
-			// NOTE: use the K8s YAML marshal function because it works with builtin
-			// k8s types by marshaling using the JSON tags then converting to YAML
-			// as opposed to just using YAML tags natively.
-			m, err := k8syaml.Marshal(out)
+// newGetSpaceCommand creates a command that prints the whole spec of a space.
+func newGetSpaceCommand(client spaces.Client) *cobra.Command {
+	var showDefaults bool
+
+	cmd := &cobra.Command{
+		Use:   "get SPACE_NAME",
+		Short: "Get the full configuration of the space.",
+		Long: `Get the full configuration of the space.
+
+		By default only the values stored on the space are printed. Use
+		--show-defaults to also fill in the values Kf uses when a field isn't
+		set, including the builder image and container registry the cluster
+		provides.
+		`,
+		Example: "kf configure-space get my-space --show-defaults",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spaceName := args[0]
+
+			cmd.SilenceUsage = true
+
+			space, err := client.Get(spaceName)
 			if err != nil {
-				fmt.Fprintf(cmd.OutOrStdout(), "%#v", out)
-				return fmt.Errorf("couldn't convert value to YAML: %s", err)
+				return err
 			}
 
-			fmt.Fprint(cmd.OutOrStdout(), string(m))
-			return nil
+			if showDefaults {
+				// don't modify the object returned by the client
+				space = space.DeepCopy()
+				space.SetDefaults(context.Background())
+
+				// The build defaults come from the cluster, the controller
+				// records them on the status.
+				space.Spec.BuildpackBuild.BuilderImage = space.BaseBuilderImage()
+				space.Spec.BuildpackBuild.ContainerRegistry = space.EffectiveContainerRegistry()
+			}
+
+			return printYAML(cmd.OutOrStdout(), space.Spec)
 		},
 	}
 
+	cmd.Flags().BoolVar(
+		&showDefaults,
+		"show-defaults",
+		false,
+		"Fill in default values for fields that aren't set on the space.",
+	)
+
 	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
 
 	return cmd
 }
 
+// printYAML writes the value to w as YAML.
+func printYAML(w io.Writer, out interface{}) error {
+	// NOTE: use the K8s YAML marshal function because it works with builtin
+	// k8s types by marshaling using the JSON tags then converting to YAML
+	// as opposed to just using YAML tags natively.
+	m, err := k8syaml.Marshal(out)
+	if err != nil {
+		fmt.Fprintf(w, "%#v", out)
+		return fmt.Errorf("couldn't convert value to YAML: %s", err)
+	}
+
+	fmt.Fprint(w, string(m))
+	return nil
+}
+
 func newGetContainerRegistryAccessor() spaceAccessor {
 	return spaceAccessor{
 		Name:  "get-container-registry",
 		Short: "Get the container registry used for builds.",
 		Accessor: func(space *v1alpha1.Space) interface{} {
-			return space.Spec.BuildpackBuild.ContainerRegistry
+			return space.EffectiveContainerRegistry()
 		},
 	}
 }
@@ -366,7 +953,17 @@ func newGetBuildpackBuilderAccessor() spaceAccessor {
 		Name:  "get-buildpack-builder",
 		Short: "Get the buildpack builder used for builds.",
 		Accessor: func(space *v1alpha1.Space) interface{} {
-			return space.Spec.BuildpackBuild.BuilderImage
+			return space.EffectiveBuilderImage()
+		},
+	}
+}
+
+func newGetDefaultStackAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-default-stack",
+		Short: "Get the stack apps are built with unless they choose one.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			return space.Spec.BuildpackBuild.DefaultStack
 		},
 	}
 }
@@ -400,3 +997,87 @@ func newGetDomainsAccessor() spaceAccessor {
 		},
 	}
 }
+
+func newGetMaxConcurrentBuildsAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-max-concurrent-builds",
+		Short: "Get the maximum number of builds that can run at once in a space.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			return space.Spec.BuildpackBuild.MaxConcurrentBuilds
+		},
+	}
+}
+
+func newGetBuildLogBucketAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-build-log-bucket",
+		Short: "Get the Cloud Storage bucket completed build logs are archived to.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			return space.Spec.BuildpackBuild.LogBucket
+		},
+	}
+}
+
+func newGetSSHPolicyAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-ssh-policy",
+		Short: "Get whether developers can use kf ssh to open shells in apps.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			if space.Spec.Security.EnableSSH {
+				return sshPolicyEnabled
+			}
+
+			return sshPolicyDisabled
+		},
+	}
+}
+
+func newGetResponseHeadersAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-response-headers",
+		Short: "Get the headers set on every response served by Apps in the space.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			return space.Spec.Execution.ResponseHeaders
+		},
+	}
+}
+
+func newGetEgressPolicyAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-egress-policy",
+		Short: "Get the external destinations Apps in the space can reach.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			return space.Spec.Security.Egress
+		},
+	}
+}
+
+func newGetDefaultHealthCheckAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-default-health-check",
+		Short: "Get the health check and startup timeout apps use unless they choose their own.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			return space.Spec.Execution.DefaultHealthCheck
+		},
+	}
+}
+
+func newGetReservedHostnamesAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-reserved-hostnames",
+		Short: "Get the hostnames reserved in the space and the apps allowed to use them.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			return space.Spec.Execution.ReservedHostnames
+		},
+	}
+}
+
+func newGetSidecarResourcesAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-sidecar-resources",
+		Short: "Get the resources requested by the Istio sidecar in App pods.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			return space.Spec.Execution.SidecarResources
+		},
+	}
+}
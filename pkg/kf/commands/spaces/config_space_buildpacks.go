@@ -0,0 +1,191 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+)
+
+// newAddBuildpackCommand creates a command that adds a custom buildpack to
+// the builder of a space.
+func newAddBuildpackCommand(client spaces.Client) *cobra.Command {
+	var (
+		diffFlags utils.DiffFlags
+		git       string
+		image     string
+		position  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add-buildpack SPACE_NAME NAME (--git URL[@REF] | --image IMAGE) [--position N]",
+		Short: "Add a custom buildpack to the builder of the space.",
+		Long: `Add a custom buildpack to the builder of the space.
+
+		Kf composes a builder with the space's buildpacks added to its base
+		builder. Custom buildpacks are tried in order before the buildpacks
+		of the base builder. Apps keep using the previous builder until the
+		new one is ready, use get-buildpacks to see the buildpacks and
+		kf space to see the builder's status.
+
+		The space needs a container registry to store the builder. Adding a
+		buildpack with an existing name replaces it.
+		`,
+		Example: `
+		kf configure-space add-buildpack my-space node --git https://github.com/cloudfoundry/nodejs-buildpack@v1.7.0
+		kf configure-space add-buildpack my-space java --image gcr.io/my-project/java-buildpack:v2 --position 0
+		`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spaceName := args[0]
+			buildpack := v1alpha1.SpaceBuildpack{
+				Name:  args[1],
+				Git:   git,
+				Image: image,
+			}
+
+			if git == "" && image == "" {
+				return errors.New("one of --git or --image is required")
+			}
+
+			if git != "" && image != "" {
+				return errors.New("only one of --git or --image can be set")
+			}
+
+			if err := v1alpha1.ValidateSpaceBuildpacks([]v1alpha1.SpaceBuildpack{buildpack}); err != nil {
+				return fmt.Errorf("invalid buildpack: %s", err)
+			}
+
+			if position < -1 {
+				return fmt.Errorf("invalid --position %d, must be 0 or greater", position)
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(space *v1alpha1.Space) error {
+				space.Spec.BuildpackBuild.Buildpacks = insertBuildpack(
+					space.Spec.BuildpackBuild.Buildpacks,
+					buildpack,
+					position,
+				)
+				return nil
+			}
+
+			diffPrintingMutator := spaces.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...)
+			_, err = client.Transform(spaceName, diffPrintingMutator)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&git,
+		"git",
+		"",
+		"Git repository of the buildpack, a branch, tag or commit can be given after an @.",
+	)
+
+	cmd.Flags().StringVar(
+		&image,
+		"image",
+		"",
+		"Image with the buildpack in its root directory.",
+	)
+
+	cmd.Flags().IntVar(
+		&position,
+		"position",
+		-1,
+		"Zero based position of the buildpack in the detection order, defaults to the end or the existing position of a replaced buildpack.",
+	)
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
+
+// insertBuildpack adds the buildpack at the position, replacing any with the
+// same name. A negative position keeps the place of a replaced buildpack or
+// appends a new one.
+func insertBuildpack(buildpacks []v1alpha1.SpaceBuildpack, buildpack v1alpha1.SpaceBuildpack, position int) []v1alpha1.SpaceBuildpack {
+	var out []v1alpha1.SpaceBuildpack
+	for i, bp := range buildpacks {
+		if bp.Name != buildpack.Name {
+			out = append(out, bp)
+		} else if position < 0 {
+			position = i
+		}
+	}
+
+	if position < 0 || position > len(out) {
+		position = len(out)
+	}
+
+	out = append(out, v1alpha1.SpaceBuildpack{})
+	copy(out[position+1:], out[position:])
+	out[position] = buildpack
+
+	return out
+}
+
+func newRemoveBuildpackMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "remove-buildpack",
+		Short:       "Remove a custom buildpack from the builder of the space.",
+		Args:        []string{"NAME"},
+		ExampleArgs: []string{"node"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			name := args[0]
+
+			return func(space *v1alpha1.Space) error {
+				var buildpacks []v1alpha1.SpaceBuildpack
+				for _, bp := range space.Spec.BuildpackBuild.Buildpacks {
+					if bp.Name != name {
+						buildpacks = append(buildpacks, bp)
+					}
+				}
+
+				if len(buildpacks) == len(space.Spec.BuildpackBuild.Buildpacks) {
+					return fmt.Errorf("buildpack %s isn't configured", name)
+				}
+				space.Spec.BuildpackBuild.Buildpacks = buildpacks
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newGetBuildpacksAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-buildpacks",
+		Short: "Get the custom buildpacks added to the builder of the space.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			return space.Spec.BuildpackBuild.Buildpacks
+		},
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/diffutil"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+)
+
+// newDiffSpaceCommand creates a command that reports how a space has drifted
+// from the configuration in a file.
+func newDiffSpaceCommand(client spaces.Client) *cobra.Command {
+	var filename string
+
+	cmd := &cobra.Command{
+		Use:   "diff SPACE_NAME",
+		Short: "Report how the live space has drifted from a desired configuration.",
+		Long: `Report how the live space has drifted from a desired configuration.
+
+		The file holds the space configuration in the same format printed by
+		kf configure-space get. Fields changed out-of-band, for example
+		environment variables set with kubectl, are listed along with drift
+		the controller found in the space's namespace such as missing
+		required NetworkPolicies.
+
+		The command exits with an error if any drift is found so it can be
+		used as a compliance check.
+		`,
+		Example: "kf configure-space diff my-space -f desired.yaml",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spaceName := args[0]
+
+			desired, err := readSpaceSpec(filename)
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			space, err := client.Get(spaceName)
+			if err != nil {
+				return err
+			}
+
+			changes, err := diffutil.Compute(*desired, space.Spec)
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			drifted := false
+
+			if len(changes) > 0 {
+				drifted = true
+				if err := diffutil.Fprint(w, "Space Drift (-desired +live):", *desired, space.Spec); err != nil {
+					return err
+				}
+			}
+
+			if cond := space.Status.GetCondition(v1alpha1.SpaceConditionNoDrift); cond != nil && cond.IsFalse() {
+				drifted = true
+				fmt.Fprintln(w, "Namespace Drift:")
+				fmt.Fprintf(w, "  %s\n", cond.Message)
+			}
+
+			if drifted {
+				return fmt.Errorf("space %q has drifted from %s", spaceName, filename)
+			}
+
+			fmt.Fprintf(w, "No drift, space %q matches %s\n", spaceName, filename)
+			return nil
+		},
+	}
+
+	addFilenameFlag(cmd, &filename)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
@@ -0,0 +1,100 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+)
+
+// newSetEgressPolicyCommand creates a command that restricts the external
+// destinations Apps in a space can reach.
+func newSetEgressPolicyCommand(client spaces.Client) *cobra.Command {
+	var (
+		diffFlags utils.DiffFlags
+		allow     []string
+		denyAll   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-egress-policy SPACE_NAME [--allow HOST:PORT]... [--deny-all]",
+		Short: "Set the external destinations Apps in the space can reach.",
+		Long: `Set the external destinations Apps in the space can reach.
+
+		Each --allow destination is registered with the service mesh. When
+		--deny-all is set traffic from Apps to any other destination outside
+		the cluster is blocked. The policy replaces the existing one, running
+		the command with no flags removes all egress restrictions.
+
+		HOST may start with a "*." wildcard to allow every subdomain.
+		`,
+		Example: "kf configure-space set-egress-policy my-space --allow api.stripe.com:443 --deny-all",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spaceName := args[0]
+
+			for _, dest := range allow {
+				if _, _, err := v1alpha1.ParseEgressDestination(dest); err != nil {
+					return fmt.Errorf("invalid --allow %q: %v", dest, err)
+				}
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(space *v1alpha1.Space) error {
+				space.Spec.Security.Egress = v1alpha1.SpaceEgressPolicy{
+					Allow:   allow,
+					DenyAll: denyAll,
+				}
+
+				return nil
+			}
+
+			diffPrintingMutator := spaces.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...)
+			_, err = client.Transform(spaceName, diffPrintingMutator)
+			return err
+		},
+	}
+
+	cmd.Flags().StringArrayVar(
+		&allow,
+		"allow",
+		nil,
+		"External destination in HOST:PORT form Apps can reach, may be repeated.",
+	)
+
+	cmd.Flags().BoolVar(
+		&denyAll,
+		"deny-all",
+		false,
+		"Block traffic to external destinations that aren't allowed.",
+	)
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
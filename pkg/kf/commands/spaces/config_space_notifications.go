@@ -0,0 +1,170 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+)
+
+// newSetNotificationCommand creates a command that posts events in a space
+// to a webhook.
+func newSetNotificationCommand(client spaces.Client) *cobra.Command {
+	var (
+		diffFlags utils.DiffFlags
+		webhook   string
+		events    []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-notification SPACE_NAME --webhook URL --events EVENT[,EVENT]...",
+		Short: "Post build and push events in the space to a webhook.",
+		Long: `Post build and push events in the space to a webhook.
+
+		Each event is sent as a JSON payload with a summary in the "text" field
+		so Slack incoming webhooks can be used directly. Running the command
+		again with the same webhook replaces its events.
+
+		Events: ` + strings.Join(v1alpha1.NotificationEvents, ", ") + `
+		`,
+		Example: "kf configure-space set-notification my-space --webhook https://hooks.slack.com/services/T0/B0/X --events build-failed,push-complete",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spaceName := args[0]
+
+			if webhook == "" {
+				return errors.New("--webhook is required")
+			}
+
+			if len(events) == 0 {
+				return errors.New("--events is required")
+			}
+
+			notification := v1alpha1.SpaceNotification{
+				Webhook: webhook,
+				Events:  events,
+			}
+
+			for _, event := range events {
+				if !isNotificationEvent(event) {
+					return fmt.Errorf("invalid --events %q, must be one of: %s", event, strings.Join(v1alpha1.NotificationEvents, ", "))
+				}
+			}
+
+			// The events are valid so any remaining error is in the webhook.
+			if err := v1alpha1.ValidateNotifications([]v1alpha1.SpaceNotification{notification}); err != nil {
+				return fmt.Errorf("invalid --webhook %q, must be an http or https URL", webhook)
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(space *v1alpha1.Space) error {
+				for i, n := range space.Spec.Notifications {
+					if n.Webhook == webhook {
+						space.Spec.Notifications[i] = notification
+						return nil
+					}
+				}
+
+				space.Spec.Notifications = append(space.Spec.Notifications, notification)
+				return nil
+			}
+
+			diffPrintingMutator := spaces.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...)
+			_, err = client.Transform(spaceName, diffPrintingMutator)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&webhook,
+		"webhook",
+		"",
+		"URL event payloads are posted to.",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&events,
+		"events",
+		nil,
+		"Comma separated events to post to the webhook.",
+	)
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
+
+func isNotificationEvent(event string) bool {
+	for _, e := range v1alpha1.NotificationEvents {
+		if e == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newUnsetNotificationMutator() spaceMutator {
+	return spaceMutator{
+		Name:        "unset-notification",
+		Short:       "Stop posting events in the space to a webhook.",
+		Args:        []string{"WEBHOOK"},
+		ExampleArgs: []string{"https://hooks.slack.com/services/T0/B0/X"},
+		Init: func(args []string) (spaces.Mutator, error) {
+			webhook := args[0]
+
+			return func(space *v1alpha1.Space) error {
+				var notifications []v1alpha1.SpaceNotification
+				for _, n := range space.Spec.Notifications {
+					if n.Webhook != webhook {
+						notifications = append(notifications, n)
+					}
+				}
+
+				if len(notifications) == len(space.Spec.Notifications) {
+					return fmt.Errorf("webhook %s isn't configured", webhook)
+				}
+				space.Spec.Notifications = notifications
+
+				return nil
+			}, nil
+		},
+	}
+}
+
+func newGetNotificationsAccessor() spaceAccessor {
+	return spaceAccessor{
+		Name:  "get-notifications",
+		Short: "Get the webhooks events in the space are posted to.",
+		Accessor: func(space *v1alpha1.Space) interface{} {
+			return space.Spec.Notifications
+		},
+	}
+}
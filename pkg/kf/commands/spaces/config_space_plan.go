@@ -0,0 +1,202 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/diffutil"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+	k8syaml "sigs.k8s.io/yaml"
+)
+
+// newPlanSpaceCommand creates a command that previews the changes needed to
+// move a space to the configuration in a file.
+func newPlanSpaceCommand(client spaces.Client) *cobra.Command {
+	var filename string
+
+	cmd := &cobra.Command{
+		Use:   "plan SPACE_NAME",
+		Short: "Show the changes apply would make to the space.",
+		Long: `Show the changes apply would make to the space.
+
+		The file holds the space configuration in the same format printed by
+		kf configure-space get. The output lists each field that would change,
+		whether the change restarts or requires restaging the apps in the
+		space, and a hash of the current configuration that can be passed to
+		apply with --expect-hash.
+		`,
+		Example: "kf configure-space plan my-space -f space.yaml",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spaceName := args[0]
+
+			desired, err := readSpaceSpec(filename)
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			space, err := client.Get(spaceName)
+			if err != nil {
+				return err
+			}
+
+			plan, err := spaces.NewPlan(space, *desired)
+			if err != nil {
+				return err
+			}
+
+			return printPlan(cmd.OutOrStdout(), spaceName, filename, space.Spec, *desired, plan)
+		},
+	}
+
+	addFilenameFlag(cmd, &filename)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
+
+// newApplySpaceCommand creates a command that replaces the configuration of a
+// space with the contents of a file.
+func newApplySpaceCommand(client spaces.Client) *cobra.Command {
+	var (
+		filename   string
+		expectHash string
+		diffFlags  utils.DiffFlags
+	)
+
+	cmd := &cobra.Command{
+		Use:   "apply SPACE_NAME",
+		Short: "Replace the configuration of the space with the contents of a file.",
+		Long: `Replace the configuration of the space with the contents of a file.
+
+		Fields missing from the file are removed from the space. Pass the hash
+		printed by kf configure-space plan to --expect-hash to fail instead of
+		applying if the space changed after the plan was made.
+		`,
+		Example: "kf configure-space apply my-space -f space.yaml --expect-hash 3f2a...",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spaceName := args[0]
+
+			desired, err := readSpaceSpec(filename)
+			if err != nil {
+				return err
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			var mutator spaces.Mutator = func(space *v1alpha1.Space) error {
+				space.Spec = *desired
+				return nil
+			}
+
+			if expectHash != "" {
+				mutator = spaces.ExpectHashWrapper(expectHash, mutator)
+			}
+
+			diffPrintingMutator := spaces.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...)
+			_, err = client.Transform(spaceName, diffPrintingMutator)
+			return err
+		},
+	}
+
+	addFilenameFlag(cmd, &filename)
+
+	cmd.Flags().StringVar(
+		&expectHash,
+		"expect-hash",
+		"",
+		"Only apply if the space's current configuration has this hash, as printed by plan.",
+	)
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
+
+func addFilenameFlag(cmd *cobra.Command, filename *string) {
+	cmd.Flags().StringVarP(
+		filename,
+		"filename",
+		"f",
+		"",
+		"Path to a YAML file holding the space configuration.",
+	)
+}
+
+// readSpaceSpec reads a space configuration in the format printed by
+// kf configure-space get.
+func readSpaceSpec(filename string) (*v1alpha1.SpaceSpec, error) {
+	if filename == "" {
+		return nil, errors.New("--filename is required")
+	}
+
+	contents, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &v1alpha1.SpaceSpec{}
+	if err := k8syaml.UnmarshalStrict(contents, spec); err != nil {
+		return nil, fmt.Errorf("couldn't parse %s: %s", filename, err)
+	}
+
+	return spec, nil
+}
+
+func printPlan(w io.Writer, spaceName, filename string, current, desired v1alpha1.SpaceSpec, plan *spaces.Plan) error {
+	if len(plan.Changes) == 0 {
+		fmt.Fprintf(w, "No changes, space %q matches %s\n", spaceName, filename)
+		return nil
+	}
+
+	if err := diffutil.Fprint(w, "Space Diff (-current +desired):", current, desired); err != nil {
+		return err
+	}
+	fmt.Fprintln(w)
+
+	if plan.RestartsApps {
+		fmt.Fprintln(w, "Reconciling these changes will restart all apps in the space.")
+	} else {
+		fmt.Fprintln(w, "Reconciling these changes won't restart apps.")
+	}
+
+	if plan.RequiresRestage {
+		fmt.Fprintln(w, "Apps must be restaged to pick up the new build configuration.")
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "To apply exactly these changes, run:")
+	fmt.Fprintf(w, "  kf configure-space apply %s -f %s --expect-hash %s\n", spaceName, filename, plan.Hash)
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// newSetSidecarResourcesCommand creates a command that sets the resources
+// requested by the Istio sidecar in App pods in a space.
+func newSetSidecarResourcesCommand(client spaces.Client) *cobra.Command {
+	var (
+		diffFlags utils.DiffFlags
+		cpu       string
+		memory    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "set-sidecar-resources SPACE_NAME [--cpu CPU] [--memory MEMORY]",
+		Short: "Set the resources requested by the Istio sidecar in App pods.",
+		Long: `Set the resources requested by the Istio sidecar in App pods.
+
+		The default istio-proxy footprint can take up most of a small App's
+		quota. The resources replace the existing ones and a flag that isn't
+		set uses Istio's default, running the command with no flags restores
+		the defaults. Apps get a new revision with the new sidecar resources.
+		`,
+		Example: "kf configure-space set-sidecar-resources my-space --cpu 50m --memory 64Mi",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spaceName := args[0]
+
+			if cpu != "" {
+				if _, err := resource.ParseQuantity(cpu); err != nil {
+					return fmt.Errorf("invalid --cpu %q: %v", cpu, err)
+				}
+			}
+
+			if memory != "" {
+				if _, err := resource.ParseQuantity(memory); err != nil {
+					return fmt.Errorf("invalid --memory %q: %v", memory, err)
+				}
+			}
+
+			diffOpts, err := diffFlags.Options()
+			if err != nil {
+				return err
+			}
+
+			cmd.SilenceUsage = true
+
+			mutator := func(space *v1alpha1.Space) error {
+				space.Spec.Execution.SidecarResources = v1alpha1.SpaceSidecarResources{
+					CPU:    cpu,
+					Memory: memory,
+				}
+
+				return nil
+			}
+
+			diffPrintingMutator := spaces.DiffWrapper(cmd.OutOrStdout(), mutator, diffOpts...)
+			_, err = client.Transform(spaceName, diffPrintingMutator)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&cpu,
+		"cpu",
+		"",
+		"CPU requested by the sidecar, e.g. 50m.",
+	)
+
+	cmd.Flags().StringVar(
+		&memory,
+		"memory",
+		"",
+		"Memory requested by the sidecar, e.g. 64Mi.",
+	)
+
+	diffFlags.Add(cmd)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
@@ -17,6 +17,11 @@ package spaces
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -26,6 +31,8 @@ import (
 	"github.com/google/kf/pkg/kf/spaces"
 	"github.com/google/kf/pkg/kf/spaces/fake"
 	"github.com/google/kf/pkg/kf/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func TestNewConfigSpaceCommand(t *testing.T) {
@@ -50,6 +57,11 @@ func TestNewConfigSpaceCommand(t *testing.T) {
 			},
 		},
 
+		"invalid diff format": {
+			args:    []string{"set-container-registry", space, "gcr.io/foo", "--diff-format", "yaml"},
+			wantErr: errors.New(`unknown diff format "yaml", must be one of: text, json`),
+		},
+
 		"set-env valid": {
 			space: v1alpha1.Space{
 				Spec: v1alpha1.SpaceSpec{
@@ -135,12 +147,63 @@ func TestNewConfigSpaceCommand(t *testing.T) {
 			},
 		},
 
+		"set-default-stack valid": {
+			args: []string{"set-default-stack", space, "cflinuxfs3"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "default stack", "cflinuxfs3", space.Spec.BuildpackBuild.DefaultStack)
+			},
+		},
+
 		"append-domain valid": {
 			args: []string{"append-domain", space, "example.com"},
 			validate: func(t *testing.T, space *v1alpha1.Space) {
 				testutil.AssertEqual(t, "len(domains)", 1, len(space.Spec.Execution.Domains))
 				testutil.AssertEqual(t, "domains", "example.com", space.Spec.Execution.Domains[0].Domain)
+				testutil.AssertEqual(t, "default", true, space.Spec.Execution.Domains[0].Default)
+			},
+		},
+
+		"append-domain keeps the existing default": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						Domains: []v1alpha1.SpaceDomain{
+							{Domain: "example.com", Default: true},
+						},
+					},
+				},
+			},
+			args: []string{"append-domain", space, "other-example.com"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "domains", []v1alpha1.SpaceDomain{
+					{Domain: "example.com", Default: true},
+					{Domain: "other-example.com"},
+				}, space.Spec.Execution.Domains)
+			},
+		},
+
+		"append-domain duplicate": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						Domains: []v1alpha1.SpaceDomain{
+							{Domain: "example.com", Default: true},
+						},
+					},
+				},
 			},
+			args:    []string{"append-domain", space, "example.com"},
+			wantErr: errors.New("space already has the domain example.com"),
+		},
+
+		"append-domain wildcard": {
+			args:    []string{"append-domain", space, "*.example.com"},
+			wantErr: errors.New(`domain can't be a wildcard, use "example.com" which includes its subdomains`),
+		},
+
+		"append-domain invalid characters": {
+			args:    []string{"append-domain", space, "my_domain.com"},
+			wantErr: errors.New(`invalid domain "my_domain.com": ` + strings.Join(validation.IsDNS1123Subdomain("my_domain.com"), ", ")),
 		},
 
 		"set-default-domain valid": {
@@ -177,6 +240,394 @@ func TestNewConfigSpaceCommand(t *testing.T) {
 			args:    []string{"set-default-domain", space, "other-example.com"},
 		},
 
+		"set-env reserved name": {
+			args:    []string{"set-env", space, "VCAP_SERVICES", "{}"},
+			wantErr: errors.New("environment variable VCAP_SERVICES is reserved by Kf"),
+		},
+
+		"set-domain-tls valid": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						Domains: []v1alpha1.SpaceDomain{
+							{Domain: "example.com", Default: true},
+							{Domain: "secure.example.com"},
+						},
+					},
+				},
+			},
+			args: []string{"set-domain-tls", space, "secure.example.com", "secure-cert"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "domains", []v1alpha1.SpaceDomain{
+					{Domain: "example.com", Default: true},
+					{Domain: "secure.example.com", TLSSecretName: "secure-cert"},
+				}, space.Spec.Execution.Domains)
+			},
+		},
+		"set-domain-tls removing the secret disables redirects": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						Domains: []v1alpha1.SpaceDomain{
+							{Domain: "example.com", Default: true, TLSSecretName: "cert", RedirectHTTPS: true},
+						},
+					},
+				},
+			},
+			args: []string{"set-domain-tls", space, "example.com", ""},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "domains", []v1alpha1.SpaceDomain{
+					{Domain: "example.com", Default: true},
+				}, space.Spec.Execution.Domains)
+			},
+		},
+		"set-domain-tls missing domain": {
+			args:    []string{"set-domain-tls", space, "missing.example.com", "cert"},
+			wantErr: errors.New("failed to find domain missing.example.com"),
+		},
+		"set-https-redirect enabled": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						Domains: []v1alpha1.SpaceDomain{
+							{Domain: "example.com", Default: true, TLSSecretName: "cert"},
+						},
+					},
+				},
+			},
+			args: []string{"set-https-redirect", space, "example.com", "enabled"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "redirect", true, space.Spec.Execution.Domains[0].RedirectHTTPS)
+			},
+		},
+		"set-https-redirect invalid": {
+			args:    []string{"set-https-redirect", space, "example.com", "sometimes"},
+			wantErr: errors.New(`POLICY must be enabled or disabled, got "sometimes"`),
+		},
+
+		"set-max-concurrent-builds valid": {
+			args: []string{"set-max-concurrent-builds", space, "3"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "max concurrent builds", 3, space.Spec.BuildpackBuild.MaxConcurrentBuilds)
+			},
+		},
+
+		"set-max-concurrent-builds invalid": {
+			args:    []string{"set-max-concurrent-builds", space, "many"},
+			wantErr: errors.New(`couldn't parse MAX_BUILDS: strconv.Atoi: parsing "many": invalid syntax`),
+		},
+
+		"set-build-log-bucket valid": {
+			args: []string{"set-build-log-bucket", space, "gs://build-logs"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "log bucket", "gs://build-logs", space.Spec.BuildpackBuild.LogBucket)
+			},
+		},
+
+		"set-build-log-bucket clear": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					BuildpackBuild: v1alpha1.SpaceSpecBuildpackBuild{
+						LogBucket: "gs://build-logs",
+					},
+				},
+			},
+			args: []string{"set-build-log-bucket", space, ""},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "log bucket", "", space.Spec.BuildpackBuild.LogBucket)
+			},
+		},
+
+		"set-build-log-bucket invalid": {
+			args:    []string{"set-build-log-bucket", space, "build-logs"},
+			wantErr: errors.New(`bucket "build-logs" must be a Cloud Storage URL starting with gs://`),
+		},
+
+		"set-ssh-policy enabled": {
+			args: []string{"set-ssh-policy", space, "enabled"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "ssh enabled", true, space.Spec.Security.EnableSSH)
+			},
+		},
+
+		"set-ssh-policy disabled": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Security: v1alpha1.SpaceSpecSecurity{
+						EnableSSH: true,
+					},
+				},
+			},
+			args: []string{"set-ssh-policy", space, "disabled"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "ssh enabled", false, space.Spec.Security.EnableSSH)
+			},
+		},
+
+		"set-ssh-policy invalid": {
+			args:    []string{"set-ssh-policy", space, "sometimes"},
+			wantErr: errors.New(`POLICY must be enabled or disabled, got "sometimes"`),
+		},
+
+		"set-deletion-protection on": {
+			args: []string{"set-deletion-protection", space, "on"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "protected", true, v1alpha1.IsDeletionProtected(space))
+			},
+		},
+
+		"set-deletion-protection off": {
+			space: v1alpha1.Space{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{v1alpha1.DeletionProtectionAnnotation: "true"},
+				},
+			},
+			args: []string{"set-deletion-protection", space, "off"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "protected", false, v1alpha1.IsDeletionProtected(space))
+			},
+		},
+
+		"set-deletion-protection invalid": {
+			args:    []string{"set-deletion-protection", space, "maybe"},
+			wantErr: errors.New(`SETTING must be on or off, got "maybe"`),
+		},
+
+		"set-response-header valid": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						ResponseHeaders: map[string]string{
+							"x-frame-options": "SAMEORIGIN",
+						},
+					},
+				},
+			},
+			args: []string{"set-response-header", space, "X-Frame-Options", "DENY"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "response headers", map[string]string{
+					"X-Frame-Options": "DENY",
+				}, space.Spec.Execution.ResponseHeaders)
+			},
+		},
+
+		"set-response-header invalid name": {
+			args:    []string{"set-response-header", space, "X Frame Options", "DENY"},
+			wantErr: errors.New(`invalid response header: invalid key name "X Frame Options": responseHeaders`),
+		},
+
+		"set-default-health-check http": {
+			args: []string{"set-default-health-check", space, "http:/healthz"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "default health check", v1alpha1.SpaceDefaultHealthCheck{
+					Type:         "http",
+					HTTPEndpoint: "/healthz",
+				}, space.Spec.Execution.DefaultHealthCheck)
+			},
+		},
+
+		"set-default-health-check port keeps timeout": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						DefaultHealthCheck: v1alpha1.SpaceDefaultHealthCheck{
+							Type:                  "http",
+							HTTPEndpoint:          "/healthz",
+							StartupTimeoutSeconds: 180,
+						},
+					},
+				},
+			},
+			args: []string{"set-default-health-check", space, "port"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "default health check", v1alpha1.SpaceDefaultHealthCheck{
+					Type:                  "port",
+					StartupTimeoutSeconds: 180,
+				}, space.Spec.Execution.DefaultHealthCheck)
+			},
+		},
+
+		"set-default-health-check invalid": {
+			args:    []string{"set-default-health-check", space, "process"},
+			wantErr: errors.New(`invalid health check: invalid value: process: type`),
+		},
+
+		"set-startup-timeout valid": {
+			args: []string{"set-startup-timeout", space, "180"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "startup timeout", 180, space.Spec.Execution.DefaultHealthCheck.StartupTimeoutSeconds)
+			},
+		},
+
+		"set-startup-timeout invalid": {
+			args:    []string{"set-startup-timeout", space, "soon"},
+			wantErr: errors.New(`couldn't parse SECONDS: strconv.Atoi: parsing "soon": invalid syntax`),
+		},
+
+		"unset-response-header valid": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						ResponseHeaders: map[string]string{
+							"X-Frame-Options":           "DENY",
+							"Strict-Transport-Security": "max-age=31536000",
+						},
+					},
+				},
+			},
+			args: []string{"unset-response-header", space, "x-frame-options"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "response headers", map[string]string{
+					"Strict-Transport-Security": "max-age=31536000",
+				}, space.Spec.Execution.ResponseHeaders)
+			},
+		},
+
+		"set-buildpack-env-from-secret valid": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					BuildpackBuild: v1alpha1.SpaceSpecBuildpackBuild{
+						Env: envutil.MapToEnvVars(map[string]string{
+							"NPM_TOKEN": "plaintext",
+						}),
+					},
+				},
+			},
+			args: []string{"set-buildpack-env-from-secret", space, "NPM_TOKEN", "build-creds:npm-token"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				env := space.Spec.BuildpackBuild.Env
+				testutil.AssertEqual(t, "len(env)", 1, len(env))
+				testutil.AssertEqual(t, "value", "", env[0].Value)
+				testutil.AssertEqual(t, "secret", "build-creds", env[0].ValueFrom.SecretKeyRef.Name)
+				testutil.AssertEqual(t, "key", "npm-token", env[0].ValueFrom.SecretKeyRef.Key)
+			},
+		},
+
+		"reserve-hostname valid": {
+			args: []string{"reserve-hostname", space, "api"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "reserved hostnames", []v1alpha1.SpaceReservedHostname{
+					{Hostname: "api"},
+				}, space.Spec.Execution.ReservedHostnames)
+			},
+		},
+
+		"reserve-hostname already reserved": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						ReservedHostnames: []v1alpha1.SpaceReservedHostname{
+							{Hostname: "api"},
+						},
+					},
+				},
+			},
+			args:    []string{"reserve-hostname", space, "API"},
+			wantErr: errors.New("hostname API is already reserved"),
+		},
+
+		"unreserve-hostname valid": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						ReservedHostnames: []v1alpha1.SpaceReservedHostname{
+							{Hostname: "api"},
+							{Hostname: "www"},
+						},
+					},
+				},
+			},
+			args: []string{"unreserve-hostname", space, "api"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "reserved hostnames", []v1alpha1.SpaceReservedHostname{
+					{Hostname: "www"},
+				}, space.Spec.Execution.ReservedHostnames)
+			},
+		},
+
+		"grant-hostname valid": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						ReservedHostnames: []v1alpha1.SpaceReservedHostname{
+							{Hostname: "api", AllowedApps: []string{"api-gateway"}},
+						},
+					},
+				},
+			},
+			args: []string{"grant-hostname", space, "api", "api-v2"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "allowed apps", []string{"api-gateway", "api-v2"}, space.Spec.Execution.ReservedHostnames[0].AllowedApps)
+			},
+		},
+
+		"grant-hostname not reserved": {
+			args:    []string{"grant-hostname", space, "api", "api-gateway"},
+			wantErr: errors.New("hostname api isn't reserved"),
+		},
+
+		"revoke-hostname valid": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						ReservedHostnames: []v1alpha1.SpaceReservedHostname{
+							{Hostname: "api", AllowedApps: []string{"api-gateway", "api-v2"}},
+						},
+					},
+				},
+			},
+			args: []string{"revoke-hostname", space, "api", "api-gateway"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "allowed apps", []string{"api-v2"}, space.Spec.Execution.ReservedHostnames[0].AllowedApps)
+			},
+		},
+
+		"unset-notification valid": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Notifications: []v1alpha1.SpaceNotification{
+						{Webhook: "https://example.com/a", Events: []string{"build-failed"}},
+						{Webhook: "https://example.com/b", Events: []string{"push-complete"}},
+					},
+				},
+			},
+			args: []string{"unset-notification", space, "https://example.com/a"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "notifications", []v1alpha1.SpaceNotification{
+					{Webhook: "https://example.com/b", Events: []string{"push-complete"}},
+				}, space.Spec.Notifications)
+			},
+		},
+
+		"unset-notification not configured": {
+			args:    []string{"unset-notification", space, "https://example.com/a"},
+			wantErr: errors.New("webhook https://example.com/a isn't configured"),
+		},
+
+		"remove-buildpack valid": {
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					BuildpackBuild: v1alpha1.SpaceSpecBuildpackBuild{
+						Buildpacks: []v1alpha1.SpaceBuildpack{
+							{Name: "node", Git: "https://github.com/example/node-buildpack"},
+							{Name: "java", Image: "gcr.io/example/java-buildpack"},
+						},
+					},
+				},
+			},
+			args: []string{"remove-buildpack", space, "node"},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "buildpacks", []v1alpha1.SpaceBuildpack{
+					{Name: "java", Image: "gcr.io/example/java-buildpack"},
+				}, space.Spec.BuildpackBuild.Buildpacks)
+			},
+		},
+
+		"remove-buildpack not configured": {
+			args:    []string{"remove-buildpack", space, "node"},
+			wantErr: errors.New("buildpack node isn't configured"),
+		},
+
 		"remove-domain valid": {
 			space: v1alpha1.Space{
 				Spec: v1alpha1.SpaceSpec{
@@ -230,12 +681,328 @@ func TestNewConfigSpaceCommand(t *testing.T) {
 	}
 }
 
+func TestNewConfigSpaceCommand_setEgressPolicy(t *testing.T) {
+	cases := map[string]struct {
+		args       []string
+		space      v1alpha1.Space
+		wantErr    error
+		wantPolicy v1alpha1.SpaceEgressPolicy
+	}{
+		"allow and deny all": {
+			args: []string{"set-egress-policy", "space-name", "--allow", "api.stripe.com:443", "--allow", "*.googleapis.com:443", "--deny-all"},
+			wantPolicy: v1alpha1.SpaceEgressPolicy{
+				Allow:   []string{"api.stripe.com:443", "*.googleapis.com:443"},
+				DenyAll: true,
+			},
+		},
+		"no flags removes restrictions": {
+			args: []string{"set-egress-policy", "space-name"},
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Security: v1alpha1.SpaceSpecSecurity{
+						Egress: v1alpha1.SpaceEgressPolicy{
+							Allow:   []string{"api.stripe.com:443"},
+							DenyAll: true,
+						},
+					},
+				},
+			},
+			wantPolicy: v1alpha1.SpaceEgressPolicy{},
+		},
+		"invalid destination": {
+			args:    []string{"set-egress-policy", "space-name", "--allow", "api.stripe.com"},
+			wantErr: errors.New(`invalid --allow "api.stripe.com": destination must be in the form HOST:PORT`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+
+			output := tc.space.DeepCopy()
+			fakeSpaces.EXPECT().Transform("space-name", gomock.Any()).DoAndReturn(func(spaceName string, transformer spaces.Mutator) (*v1alpha1.Space, error) {
+				if err := transformer(output); err != nil {
+					return nil, err
+				}
+				return output, nil
+			}).AnyTimes()
+
+			buffer := &bytes.Buffer{}
+
+			c := NewConfigSpaceCommand(&config.KfParams{}, fakeSpaces)
+			c.SetOutput(buffer)
+			c.SetArgs(tc.args)
+
+			gotErr := c.Execute()
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+				return
+			}
+
+			testutil.AssertEqual(t, "egress policy", tc.wantPolicy, output.Spec.Security.Egress)
+			ctrl.Finish()
+		})
+	}
+}
+
+func TestNewConfigSpaceCommand_setSidecarResources(t *testing.T) {
+	cases := map[string]struct {
+		args        []string
+		space       v1alpha1.Space
+		wantErr     error
+		wantSidecar v1alpha1.SpaceSidecarResources
+	}{
+		"cpu and memory": {
+			args: []string{"set-sidecar-resources", "space-name", "--cpu", "50m", "--memory", "64Mi"},
+			wantSidecar: v1alpha1.SpaceSidecarResources{
+				CPU:    "50m",
+				Memory: "64Mi",
+			},
+		},
+		"no flags restores defaults": {
+			args: []string{"set-sidecar-resources", "space-name"},
+			space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						SidecarResources: v1alpha1.SpaceSidecarResources{
+							CPU:    "50m",
+							Memory: "64Mi",
+						},
+					},
+				},
+			},
+			wantSidecar: v1alpha1.SpaceSidecarResources{},
+		},
+		"invalid cpu": {
+			args:    []string{"set-sidecar-resources", "space-name", "--cpu", "lots"},
+			wantErr: errors.New(`invalid --cpu "lots": quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+
+			output := tc.space.DeepCopy()
+			fakeSpaces.EXPECT().Transform("space-name", gomock.Any()).DoAndReturn(func(spaceName string, transformer spaces.Mutator) (*v1alpha1.Space, error) {
+				if err := transformer(output); err != nil {
+					return nil, err
+				}
+				return output, nil
+			}).AnyTimes()
+
+			buffer := &bytes.Buffer{}
+
+			c := NewConfigSpaceCommand(&config.KfParams{}, fakeSpaces)
+			c.SetOutput(buffer)
+			c.SetArgs(tc.args)
+
+			gotErr := c.Execute()
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+				return
+			}
+
+			testutil.AssertEqual(t, "sidecar resources", tc.wantSidecar, output.Spec.Execution.SidecarResources)
+			ctrl.Finish()
+		})
+	}
+}
+
+func TestNewConfigSpaceCommand_setNotification(t *testing.T) {
+	existing := v1alpha1.Space{
+		Spec: v1alpha1.SpaceSpec{
+			Notifications: []v1alpha1.SpaceNotification{
+				{Webhook: "https://example.com/a", Events: []string{"build-failed"}},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		args              []string
+		space             v1alpha1.Space
+		wantErr           error
+		wantNotifications []v1alpha1.SpaceNotification
+	}{
+		"adds webhook": {
+			args:  []string{"set-notification", "space-name", "--webhook", "https://example.com/b", "--events", "build-failed,push-complete"},
+			space: existing,
+			wantNotifications: []v1alpha1.SpaceNotification{
+				{Webhook: "https://example.com/a", Events: []string{"build-failed"}},
+				{Webhook: "https://example.com/b", Events: []string{"build-failed", "push-complete"}},
+			},
+		},
+		"replaces events of existing webhook": {
+			args:  []string{"set-notification", "space-name", "--webhook", "https://example.com/a", "--events", "push-failed"},
+			space: existing,
+			wantNotifications: []v1alpha1.SpaceNotification{
+				{Webhook: "https://example.com/a", Events: []string{"push-failed"}},
+			},
+		},
+		"missing webhook": {
+			args:    []string{"set-notification", "space-name", "--events", "build-failed"},
+			wantErr: errors.New("--webhook is required"),
+		},
+		"missing events": {
+			args:    []string{"set-notification", "space-name", "--webhook", "https://example.com/a"},
+			wantErr: errors.New("--events is required"),
+		},
+		"unknown event": {
+			args:    []string{"set-notification", "space-name", "--webhook", "https://example.com/a", "--events", "deleted"},
+			wantErr: errors.New(`invalid --events "deleted", must be one of: build-succeeded, build-failed, push-complete, push-failed`),
+		},
+		"invalid webhook": {
+			args:    []string{"set-notification", "space-name", "--webhook", "example.com/a", "--events", "build-failed"},
+			wantErr: errors.New(`invalid --webhook "example.com/a", must be an http or https URL`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+
+			output := tc.space.DeepCopy()
+			fakeSpaces.EXPECT().Transform("space-name", gomock.Any()).DoAndReturn(func(spaceName string, transformer spaces.Mutator) (*v1alpha1.Space, error) {
+				if err := transformer(output); err != nil {
+					return nil, err
+				}
+				return output, nil
+			}).AnyTimes()
+
+			buffer := &bytes.Buffer{}
+
+			c := NewConfigSpaceCommand(&config.KfParams{}, fakeSpaces)
+			c.SetOutput(buffer)
+			c.SetArgs(tc.args)
+
+			gotErr := c.Execute()
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+				return
+			}
+
+			testutil.AssertEqual(t, "notifications", tc.wantNotifications, output.Spec.Notifications)
+			ctrl.Finish()
+		})
+	}
+}
+
+func TestNewConfigSpaceCommand_addBuildpack(t *testing.T) {
+	existing := v1alpha1.Space{
+		Spec: v1alpha1.SpaceSpec{
+			BuildpackBuild: v1alpha1.SpaceSpecBuildpackBuild{
+				Buildpacks: []v1alpha1.SpaceBuildpack{
+					{Name: "node", Git: "https://github.com/example/node-buildpack"},
+					{Name: "java", Image: "gcr.io/example/java-buildpack"},
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		args           []string
+		space          v1alpha1.Space
+		wantErr        error
+		wantBuildpacks []v1alpha1.SpaceBuildpack
+	}{
+		"appends buildpack": {
+			args:  []string{"add-buildpack", "space-name", "go", "--git", "https://github.com/example/go-buildpack@v0.3.1"},
+			space: existing,
+			wantBuildpacks: []v1alpha1.SpaceBuildpack{
+				{Name: "node", Git: "https://github.com/example/node-buildpack"},
+				{Name: "java", Image: "gcr.io/example/java-buildpack"},
+				{Name: "go", Git: "https://github.com/example/go-buildpack@v0.3.1"},
+			},
+		},
+		"inserts at position": {
+			args:  []string{"add-buildpack", "space-name", "go", "--image", "gcr.io/example/go-buildpack", "--position", "0"},
+			space: existing,
+			wantBuildpacks: []v1alpha1.SpaceBuildpack{
+				{Name: "go", Image: "gcr.io/example/go-buildpack"},
+				{Name: "node", Git: "https://github.com/example/node-buildpack"},
+				{Name: "java", Image: "gcr.io/example/java-buildpack"},
+			},
+		},
+		"replaces existing in place": {
+			args:  []string{"add-buildpack", "space-name", "node", "--git", "https://github.com/example/node-buildpack@v2"},
+			space: existing,
+			wantBuildpacks: []v1alpha1.SpaceBuildpack{
+				{Name: "node", Git: "https://github.com/example/node-buildpack@v2"},
+				{Name: "java", Image: "gcr.io/example/java-buildpack"},
+			},
+		},
+		"moves existing": {
+			args:  []string{"add-buildpack", "space-name", "node", "--git", "https://github.com/example/node-buildpack", "--position", "5"},
+			space: existing,
+			wantBuildpacks: []v1alpha1.SpaceBuildpack{
+				{Name: "java", Image: "gcr.io/example/java-buildpack"},
+				{Name: "node", Git: "https://github.com/example/node-buildpack"},
+			},
+		},
+		"missing source": {
+			args:    []string{"add-buildpack", "space-name", "go"},
+			wantErr: errors.New("one of --git or --image is required"),
+		},
+		"both sources": {
+			args:    []string{"add-buildpack", "space-name", "go", "--git", "https://github.com/example/go-buildpack", "--image", "gcr.io/example/go-buildpack"},
+			wantErr: errors.New("only one of --git or --image can be set"),
+		},
+		"invalid name": {
+			args:    []string{"add-buildpack", "space-name", "Go", "--image", "gcr.io/example/go-buildpack"},
+			wantErr: errors.New("invalid buildpack: invalid value: Go: [0].name\nnames must be DNS labels of at most 50 characters"),
+		},
+		"invalid position": {
+			args:    []string{"add-buildpack", "space-name", "go", "--image", "gcr.io/example/go-buildpack", "--position", "-2"},
+			wantErr: errors.New("invalid --position -2, must be 0 or greater"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+
+			output := tc.space.DeepCopy()
+			fakeSpaces.EXPECT().Transform("space-name", gomock.Any()).DoAndReturn(func(spaceName string, transformer spaces.Mutator) (*v1alpha1.Space, error) {
+				if err := transformer(output); err != nil {
+					return nil, err
+				}
+				return output, nil
+			}).AnyTimes()
+
+			buffer := &bytes.Buffer{}
+
+			c := NewConfigSpaceCommand(&config.KfParams{}, fakeSpaces)
+			c.SetOutput(buffer)
+			c.SetArgs(tc.args)
+
+			gotErr := c.Execute()
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+				return
+			}
+
+			testutil.AssertEqual(t, "buildpacks", tc.wantBuildpacks, output.Spec.BuildpackBuild.Buildpacks)
+			ctrl.Finish()
+		})
+	}
+}
+
 func TestNewConfigSpaceCommand_accessors(t *testing.T) {
 	space := v1alpha1.Space{
 		Spec: v1alpha1.SpaceSpec{
 			BuildpackBuild: v1alpha1.SpaceSpecBuildpackBuild{
-				ContainerRegistry: "gcr.io/foo",
-				BuilderImage:      "gcr.io/buildpack-builder:latest",
+				ContainerRegistry:   "gcr.io/foo",
+				BuilderImage:        "gcr.io/buildpack-builder:latest",
+				DefaultStack:        "cflinuxfs3",
+				MaxConcurrentBuilds: 4,
+				LogBucket:           "gs://build-logs",
+				Buildpacks: []v1alpha1.SpaceBuildpack{
+					{Name: "node", Git: "https://github.com/example/node-buildpack@v1.2.0"},
+				},
 				Env: envutil.MapToEnvVars(map[string]string{
 					"JAVA_VERSION": "11",
 					"BAR":          "BAZZ",
@@ -250,6 +1017,30 @@ func TestNewConfigSpaceCommand_accessors(t *testing.T) {
 					{Domain: "example.com", Default: true},
 					{Domain: "other-example.com"},
 				},
+				ResponseHeaders: map[string]string{
+					"X-Frame-Options": "DENY",
+				},
+				DefaultHealthCheck: v1alpha1.SpaceDefaultHealthCheck{
+					Type:                  "http",
+					HTTPEndpoint:          "/healthz",
+					StartupTimeoutSeconds: 180,
+				},
+				ReservedHostnames: []v1alpha1.SpaceReservedHostname{
+					{Hostname: "api", AllowedApps: []string{"api-gateway"}},
+				},
+				SidecarResources: v1alpha1.SpaceSidecarResources{
+					CPU:    "50m",
+					Memory: "64Mi",
+				},
+			},
+			Security: v1alpha1.SpaceSpecSecurity{
+				Egress: v1alpha1.SpaceEgressPolicy{
+					Allow:   []string{"api.stripe.com:443"},
+					DenyAll: true,
+				},
+			},
+			Notifications: []v1alpha1.SpaceNotification{
+				{Webhook: "https://example.com/hook", Events: []string{"build-failed"}},
 			},
 		},
 	}
@@ -283,11 +1074,93 @@ func TestNewConfigSpaceCommand_accessors(t *testing.T) {
 			space:      space,
 			wantOutput: "gcr.io/buildpack-builder:latest\n",
 		},
+		"get-default-stack valid": {
+			args:       []string{"get-default-stack", "space-name"},
+			space:      space,
+			wantOutput: "cflinuxfs3\n",
+		},
 		"get-container-registry valid": {
 			args:       []string{"get-container-registry", "space-name"},
 			space:      space,
 			wantOutput: "gcr.io/foo\n",
 		},
+		"get-container-registry cluster default": {
+			args: []string{"get-container-registry", "space-name"},
+			space: v1alpha1.Space{
+				Status: v1alpha1.SpaceStatus{
+					BuildpackBuild: v1alpha1.SpaceStatusBuildpackBuild{
+						ContainerRegistry: "gcr.io/cluster-project",
+					},
+				},
+			},
+			wantOutput: "gcr.io/cluster-project\n",
+		},
+		"get-max-concurrent-builds valid": {
+			args:       []string{"get-max-concurrent-builds", "space-name"},
+			space:      space,
+			wantOutput: "4\n",
+		},
+		"get-build-log-bucket valid": {
+			args:       []string{"get-build-log-bucket", "space-name"},
+			space:      space,
+			wantOutput: "gs://build-logs\n",
+		},
+		"get-ssh-policy valid": {
+			args:       []string{"get-ssh-policy", "space-name"},
+			space:      space,
+			wantOutput: "disabled\n",
+		},
+		"get-response-headers valid": {
+			args:       []string{"get-response-headers", "space-name"},
+			space:      space,
+			wantOutput: "X-Frame-Options: DENY\n",
+		},
+		"get-default-health-check valid": {
+			args:  []string{"get-default-health-check", "space-name"},
+			space: space,
+			wantOutput: `httpEndpoint: /healthz
+startupTimeoutSeconds: 180
+type: http
+`,
+		},
+		"get-egress-policy valid": {
+			args:  []string{"get-egress-policy", "space-name"},
+			space: space,
+			wantOutput: `allow:
+- api.stripe.com:443
+denyAll: true
+`,
+		},
+		"get-reserved-hostnames valid": {
+			args:  []string{"get-reserved-hostnames", "space-name"},
+			space: space,
+			wantOutput: `- allowedApps:
+  - api-gateway
+  hostname: api
+`,
+		},
+		"get-sidecar-resources valid": {
+			args:  []string{"get-sidecar-resources", "space-name"},
+			space: space,
+			wantOutput: `cpu: 50m
+memory: 64Mi
+`,
+		},
+		"get-notifications valid": {
+			args:  []string{"get-notifications", "space-name"},
+			space: space,
+			wantOutput: `- events:
+  - build-failed
+  webhook: https://example.com/hook
+`,
+		},
+		"get-buildpacks valid": {
+			args:  []string{"get-buildpacks", "space-name"},
+			space: space,
+			wantOutput: `- git: https://github.com/example/node-buildpack@v1.2.0
+  name: node
+`,
+		},
 		"get-domains valid": {
 			args:  []string{"get-domains", "space-name"},
 			space: space,
@@ -322,3 +1195,298 @@ func TestNewConfigSpaceCommand_accessors(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfigSpaceCommand_get(t *testing.T) {
+	space := v1alpha1.Space{
+		Spec: v1alpha1.SpaceSpec{
+			BuildpackBuild: v1alpha1.SpaceSpecBuildpackBuild{
+				ContainerRegistry: "gcr.io/foo",
+			},
+			Execution: v1alpha1.SpaceSpecExecution{
+				Env: envutil.MapToEnvVars(map[string]string{
+					"PROFILE": "development",
+				}),
+			},
+		},
+	}
+	space.Name = "space-name"
+
+	cases := map[string]struct {
+		args          []string
+		status        v1alpha1.SpaceStatus
+		wantOutput    []string
+		wantNotOutput []string
+	}{
+		"stored values": {
+			args: []string{"get", "space-name"},
+			wantOutput: []string{
+				"containerRegistry: gcr.io/foo",
+				"name: PROFILE",
+				"value: development",
+			},
+			wantNotOutput: []string{
+				v1alpha1.DefaultBuilderImage,
+				"domains:",
+			},
+		},
+		"with defaults": {
+			args: []string{"get", "space-name", "--show-defaults"},
+			wantOutput: []string{
+				"containerRegistry: gcr.io/foo",
+				"builderImage: " + v1alpha1.DefaultBuilderImage,
+				"domain: space-name.",
+				"enableDeveloperLogsAccess: true",
+			},
+		},
+		"with cluster defaults": {
+			args: []string{"get", "space-name", "--show-defaults"},
+			status: v1alpha1.SpaceStatus{
+				BuildpackBuild: v1alpha1.SpaceStatusBuildpackBuild{
+					BuilderImage:      "gcr.io/cluster-builder",
+					ContainerRegistry: "gcr.io/cluster-project",
+				},
+			},
+			wantOutput: []string{
+				"containerRegistry: gcr.io/foo",
+				"builderImage: gcr.io/cluster-builder",
+			},
+			wantNotOutput: []string{
+				"gcr.io/cluster-project",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+
+			withStatus := space.DeepCopy()
+			withStatus.Status = tc.status
+			fakeSpaces.EXPECT().Get("space-name").Return(withStatus, nil)
+
+			buffer := &bytes.Buffer{}
+
+			c := NewConfigSpaceCommand(&config.KfParams{}, fakeSpaces)
+			c.SetOutput(buffer)
+			c.SetArgs(tc.args)
+
+			testutil.AssertNil(t, "err", c.Execute())
+			testutil.AssertContainsAll(t, buffer.String(), tc.wantOutput)
+
+			for _, unwanted := range tc.wantNotOutput {
+				if strings.Contains(buffer.String(), unwanted) {
+					t.Errorf("expected output not to contain %q, got:\n%s", unwanted, buffer.String())
+				}
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
+
+func TestNewConfigSpaceCommand_planApply(t *testing.T) {
+	current := v1alpha1.Space{
+		Spec: v1alpha1.SpaceSpec{
+			BuildpackBuild: v1alpha1.SpaceSpecBuildpackBuild{
+				ContainerRegistry: "gcr.io/foo",
+			},
+		},
+	}
+	current.Name = "space-name"
+
+	currentHash, err := spaces.SpecHash(current.Spec)
+	testutil.AssertNil(t, "hash err", err)
+
+	dir, err := ioutil.TempDir("", "configure-space-plan")
+	testutil.AssertNil(t, "err", err)
+	defer os.RemoveAll(dir)
+
+	specPath := filepath.Join(dir, "space.yaml")
+	testutil.AssertNil(t, "err", ioutil.WriteFile(specPath, []byte(`
+buildpackBuild:
+  containerRegistry: gcr.io/bar
+execution:
+  env:
+  - name: PROFILE
+    value: production
+`), 0644))
+
+	unknownPath := filepath.Join(dir, "unknown.yaml")
+	testutil.AssertNil(t, "err", ioutil.WriteFile(unknownPath, []byte("unknownField: true\n"), 0644))
+
+	cases := map[string]struct {
+		args            []string
+		setup           func(fakeSpaces *fake.FakeClient, output *v1alpha1.Space)
+		wantErr         error
+		wantErrContains []string
+		wantOutput      []string
+		validate        func(*testing.T, *v1alpha1.Space)
+	}{
+		"plan prints changes": {
+			args: []string{"plan", "space-name", "-f", specPath},
+			setup: func(fakeSpaces *fake.FakeClient, _ *v1alpha1.Space) {
+				fakeSpaces.EXPECT().Get("space-name").Return(current.DeepCopy(), nil)
+			},
+			wantOutput: []string{
+				`~ .buildpackBuild.containerRegistry: "gcr.io/foo" -> "gcr.io/bar"`,
+				"+ .execution.env:",
+				"will restart all apps",
+				"must be restaged",
+				"--expect-hash " + currentHash,
+			},
+		},
+		"plan without file": {
+			args:    []string{"plan", "space-name"},
+			wantErr: errors.New("--filename is required"),
+		},
+		"plan unknown field": {
+			args: []string{"plan", "space-name", "-f", unknownPath},
+			wantErrContains: []string{
+				"couldn't parse " + unknownPath,
+				`unknown field "unknownField"`,
+			},
+		},
+		"apply replaces spec": {
+			args: []string{"apply", "space-name", "-f", specPath, "--expect-hash", currentHash},
+			setup: func(fakeSpaces *fake.FakeClient, output *v1alpha1.Space) {
+				fakeSpaces.EXPECT().Transform("space-name", gomock.Any()).DoAndReturn(func(spaceName string, transformer spaces.Mutator) (*v1alpha1.Space, error) {
+					return output, transformer(output)
+				})
+			},
+			validate: func(t *testing.T, space *v1alpha1.Space) {
+				testutil.AssertEqual(t, "container registry", "gcr.io/bar", space.Spec.BuildpackBuild.ContainerRegistry)
+				testutil.AssertEqual(t, "execution env", map[string]string{
+					"PROFILE": "production",
+				}, envutil.EnvVarsToMap(space.Spec.Execution.Env))
+			},
+		},
+		"apply drifted": {
+			args: []string{"apply", "space-name", "-f", specPath, "--expect-hash", "stale"},
+			setup: func(fakeSpaces *fake.FakeClient, output *v1alpha1.Space) {
+				fakeSpaces.EXPECT().Transform("space-name", gomock.Any()).DoAndReturn(func(spaceName string, transformer spaces.Mutator) (*v1alpha1.Space, error) {
+					return nil, transformer(output)
+				})
+			},
+			wantErr: fmt.Errorf(`space "space-name" changed since the plan was made: expected hash stale, got %s`, currentHash),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+
+			output := current.DeepCopy()
+			if tc.setup != nil {
+				tc.setup(fakeSpaces, output)
+			}
+
+			buffer := &bytes.Buffer{}
+
+			c := NewConfigSpaceCommand(&config.KfParams{}, fakeSpaces)
+			c.SetOutput(buffer)
+			c.SetArgs(tc.args)
+
+			gotErr := c.Execute()
+			if tc.wantErrContains != nil {
+				testutil.AssertErrorContainsAll(t, gotErr, tc.wantErrContains)
+				return
+			}
+
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+				return
+			}
+
+			testutil.AssertContainsAll(t, buffer.String(), tc.wantOutput)
+
+			if tc.validate != nil {
+				tc.validate(t, output)
+			}
+
+			ctrl.Finish()
+		})
+	}
+}
+
+func TestNewConfigSpaceCommand_diff(t *testing.T) {
+	dir, err := ioutil.TempDir("", "configure-space-diff")
+	testutil.AssertNil(t, "err", err)
+	defer os.RemoveAll(dir)
+
+	desiredPath := filepath.Join(dir, "desired.yaml")
+	testutil.AssertNil(t, "err", ioutil.WriteFile(desiredPath, []byte(`
+execution:
+  env:
+  - name: PROFILE
+    value: production
+`), 0644))
+
+	matching := v1alpha1.Space{
+		Spec: v1alpha1.SpaceSpec{
+			Execution: v1alpha1.SpaceSpecExecution{
+				Env: envutil.MapToEnvVars(map[string]string{
+					"PROFILE": "production",
+				}),
+			},
+		},
+	}
+	matching.Name = "space-name"
+
+	envChanged := *matching.DeepCopy()
+	envChanged.Spec.Execution.Env[0].Value = "development"
+
+	missingPolicies := *matching.DeepCopy()
+	missingPolicies.Status.PropagateNetworkPolicyDrift([]string{"deny-all"})
+
+	cases := map[string]struct {
+		space      v1alpha1.Space
+		wantErr    error
+		wantOutput []string
+	}{
+		"no drift": {
+			space:      matching,
+			wantOutput: []string{`No drift, space "space-name" matches ` + desiredPath},
+		},
+		"env changed out-of-band": {
+			space:   envChanged,
+			wantErr: fmt.Errorf(`space "space-name" has drifted from %s`, desiredPath),
+			wantOutput: []string{
+				`~ .execution.env[0].value: "production" -> "development"`,
+			},
+		},
+		"missing network policies": {
+			space:   missingPolicies,
+			wantErr: fmt.Errorf(`space "space-name" has drifted from %s`, desiredPath),
+			wantOutput: []string{
+				"Namespace Drift:",
+				"Required NetworkPolicies are missing: deny-all",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+
+			fakeSpaces.EXPECT().Get("space-name").Return(tc.space.DeepCopy(), nil)
+
+			buffer := &bytes.Buffer{}
+
+			c := NewConfigSpaceCommand(&config.KfParams{}, fakeSpaces)
+			c.SetOutput(buffer)
+			c.SetArgs([]string{"diff", "space-name", "-f", desiredPath})
+
+			gotErr := c.Execute()
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+			}
+
+			testutil.AssertContainsAll(t, buffer.String(), tc.wantOutput)
+
+			ctrl.Finish()
+		})
+	}
+}
@@ -48,7 +48,17 @@ func NewCreateSpaceCommand(p *config.KfParams, client spaces.Client) *cobra.Comm
 			toCreate.SetName(name)
 			toCreate.SetContainerRegistry(containerRegistry)
 
+			seen := make(map[string]bool)
 			for i, domain := range domains {
+				if err := v1alpha1.ValidateSpaceDomain(domain); err != nil {
+					return err
+				}
+
+				if seen[domain] {
+					return fmt.Errorf("the domain %s was given more than once", domain)
+				}
+				seen[domain] = true
+
 				toCreate.AppendDomains(v1alpha1.SpaceDomain{Domain: domain, Default: i == 0})
 			}
 
@@ -76,7 +86,7 @@ func NewCreateSpaceCommand(p *config.KfParams, client spaces.Client) *cobra.Comm
 		&containerRegistry,
 		"container-registry",
 		"",
-		"Container registry built apps and sources will be stored in.",
+		"Container registry built apps and sources will be stored in. Defaults to the cluster's registry.",
 	)
 
 	cmd.Flags().StringArrayVar(
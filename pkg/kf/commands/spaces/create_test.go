@@ -54,6 +54,14 @@ func TestNewCreateSpaceCommand(t *testing.T) {
 				fakeSpaces.EXPECT().WaitFor(gomock.Any(), "my-ns", 1*time.Second, gomock.Any()).Return(&v1alpha1.Space{}, nil)
 			},
 		},
+		"invalid domain": {
+			args:    []string{"my-ns", "--domain=*.example.com"},
+			wantErr: errors.New(`domain can't be a wildcard, use "example.com" which includes its subdomains`),
+		},
+		"duplicate domain": {
+			args:    []string{"my-ns", "--domain=example.com", "--domain=example.com"},
+			wantErr: errors.New("the domain example.com was given more than once"),
+		},
 		"server failure": {
 			args: []string{"my-ns"},
 			setup: func(t *testing.T, fakeSpaces *fake.FakeClient) {
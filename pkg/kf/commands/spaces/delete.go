@@ -17,6 +17,7 @@ package spaces
 import (
 	"fmt"
 
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
 	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
@@ -26,6 +27,8 @@ import (
 
 // NewDeleteSpaceCommand allows users to delete spaces.
 func NewDeleteSpaceCommand(p *config.KfParams, client spaces.Client) *cobra.Command {
+	var forceDeleteProtected bool
+
 	cmd := &cobra.Command{
 		Use:     "delete-space SPACE",
 		Short:   "Delete a space",
@@ -47,17 +50,37 @@ func NewDeleteSpaceCommand(p *config.KfParams, client spaces.Client) *cobra.Comm
 
 		You will be unable to make changes to resources in the space once deletion
 		has begun.
+
+		Spaces with deletion protection enabled can only be deleted with
+		--force-delete-protected, which turns protection off first.
 		`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			cmd.SilenceUsage = true
 
 			name := args[0]
+
+			if forceDeleteProtected {
+				if _, err := client.Transform(name, func(space *v1alpha1.Space) error {
+					v1alpha1.SetDeletionProtection(space, false)
+					return nil
+				}); err != nil {
+					return fmt.Errorf("couldn't turn off deletion protection: %s", err)
+				}
+			}
+
 			fmt.Fprintf(cmd.OutOrStdout(), "Deleting space %q %s", name, utils.AsyncLogSuffix)
 			return client.Delete(name)
 		},
 	}
 
+	cmd.Flags().BoolVar(
+		&forceDeleteProtected,
+		"force-delete-protected",
+		false,
+		"Turn off deletion protection and delete the space.",
+	)
+
 	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
 
 	return cmd
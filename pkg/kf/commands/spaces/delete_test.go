@@ -20,7 +20,9 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/spaces"
 	"github.com/google/kf/pkg/kf/spaces/fake"
 	"github.com/google/kf/pkg/kf/testutil"
 )
@@ -45,6 +47,23 @@ func TestNewDeleteSpaceCommand(t *testing.T) {
 					Delete("my-ns")
 			},
 		},
+		"force delete protected": {
+			args: []string{"my-ns", "--force-delete-protected"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient) {
+				fakeSpaces.
+					EXPECT().
+					Transform("my-ns", gomock.Any()).
+					Do(func(name string, mutator spaces.Mutator) {
+						space := &v1alpha1.Space{}
+						v1alpha1.SetDeletionProtection(space, true)
+						testutil.AssertNil(t, "mutator err", mutator(space))
+						testutil.AssertEqual(t, "protected", false, v1alpha1.IsDeletionProtected(space))
+					})
+				fakeSpaces.
+					EXPECT().
+					Delete("my-ns")
+			},
+		},
 		"server failure": {
 			args: []string{"my-ns"},
 			setup: func(t *testing.T, fakeSpaces *fake.FakeClient) {
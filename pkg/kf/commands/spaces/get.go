@@ -21,13 +21,16 @@ import (
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
 	"github.com/google/kf/pkg/kf/describe"
+	"github.com/google/kf/pkg/kf/events"
 	"github.com/google/kf/pkg/kf/spaces"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 // NewGetSpaceCommand allows users to create spaces.
-func NewGetSpaceCommand(p *config.KfParams, client spaces.Client) *cobra.Command {
+func NewGetSpaceCommand(p *config.KfParams, client spaces.Client, eventsClient v1.EventsGetter) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "space SPACE",
 		Short: "Show space info",
@@ -61,13 +64,16 @@ func NewGetSpaceCommand(p *config.KfParams, client spaces.Client) *cobra.Command
 			describe.SectionWriter(w, "Security", func(w io.Writer) {
 				security := space.Spec.Security
 				fmt.Fprintf(w, "Developers can read logs?\t%v\n", security.EnableDeveloperLogsAccess)
+				fmt.Fprintf(w, "Developers can use SSH?\t%v\n", security.EnableSSH)
 			})
 			fmt.Fprintln(w)
 
 			describe.SectionWriter(w, "Build", func(w io.Writer) {
 				buildpackBuild := space.Spec.BuildpackBuild
-				fmt.Fprintf(w, "Builder Image:\t%q\n", buildpackBuild.BuilderImage)
-				fmt.Fprintf(w, "Container Registry:\t%q\n", buildpackBuild.ContainerRegistry)
+				fmt.Fprintf(w, "Builder Image:\t%q\n", space.EffectiveBuilderImage())
+				fmt.Fprintf(w, "Default Stack:\t%q\n", buildpackBuild.DefaultStack)
+				fmt.Fprintf(w, "Container Registry:\t%q\n", space.EffectiveContainerRegistry())
+				fmt.Fprintf(w, "Build Log Bucket:\t%q\n", buildpackBuild.LogBucket)
 				describe.EnvVars(w, buildpackBuild.Env)
 			})
 			fmt.Fprintln(w)
@@ -91,6 +97,16 @@ func NewGetSpaceCommand(p *config.KfParams, client spaces.Client) *cobra.Command
 			})
 			fmt.Fprintln(w)
 
+			// Spaces are cluster scoped so their events are recorded in the
+			// default namespace.
+			spaceEvents, err := events.List(eventsClient, metav1.NamespaceDefault, "Space", space.Name)
+			if err != nil {
+				return err
+			}
+
+			describe.Events(w, spaceEvents)
+			fmt.Fprintln(w)
+
 			printAdditionalCommands(w, space.Name)
 
 			return nil
@@ -25,6 +25,8 @@ import (
 	"github.com/google/kf/pkg/kf/spaces/fake"
 	"github.com/google/kf/pkg/kf/testutil"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
 	"knative.dev/pkg/apis"
 )
 
@@ -79,6 +81,11 @@ func TestNewGetSpaceCommand(t *testing.T) {
 			space:      goodSpace,
 			wantOutput: []string{"Execution", "ExecVar", "ExecVal", "domain-1.com", "domain-2.com"},
 		},
+		"events": {
+			args:       []string{"my-space"},
+			space:      goodSpace,
+			wantOutput: []string{"Events", "Warning", "NamespaceNotOwned", "Space/my-space"},
+		},
 		"client error": {
 			args:    []string{"my-space"},
 			space:   nil,
@@ -99,7 +106,14 @@ func TestNewGetSpaceCommand(t *testing.T) {
 
 			buffer := &bytes.Buffer{}
 
-			c := NewGetSpaceCommand(&config.KfParams{Namespace: "default"}, fakeSpaces)
+			fakeEvents := k8sfake.NewSimpleClientset(&corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "my-space.1", Namespace: "default"},
+				InvolvedObject: corev1.ObjectReference{Kind: "Space", Name: "my-space"},
+				Type:           corev1.EventTypeWarning,
+				Reason:         "NamespaceNotOwned",
+			})
+
+			c := NewGetSpaceCommand(&config.KfParams{Namespace: "default"}, fakeSpaces, fakeEvents.CoreV1())
 			c.SetOutput(buffer)
 			c.SetArgs(tc.args)
 
@@ -20,6 +20,7 @@ import (
 
 	"github.com/google/kf/pkg/kf/commands/config"
 	"github.com/google/kf/pkg/kf/describe"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
 	"github.com/google/kf/pkg/kf/spaces"
 	"k8s.io/apimachinery/pkg/api/meta/table"
 
@@ -30,6 +31,8 @@ import (
 
 // NewListSpacesCommand allows users to list spaces.
 func NewListSpacesCommand(p *config.KfParams, client spaces.Client) *cobra.Command {
+	var listFlags utils.ListFlags
+
 	cmd := &cobra.Command{
 		Use:   "spaces",
 		Short: "List all kf spaces",
@@ -39,17 +42,43 @@ func NewListSpacesCommand(p *config.KfParams, client spaces.Client) *cobra.Comma
 
 		    kubectl get spaces.kf.dev
 
+		Use --limit and --continue to list spaces a page at a time.
+		`,
+		Example: `
+		kf spaces
+		kf spaces --filter team- --sort-by age
+		kf spaces --limit 50
 		`,
-		Example: `kf spaces`,
-		Args:    cobra.ExactArgs(0),
+		Args: cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := listFlags.Validate(); err != nil {
+				return err
+			}
+
 			cmd.SilenceUsage = true
 
-			list, err := client.List()
+			list, continueToken, err := client.ListPage(
+				spaces.WithListLimit(listFlags.Limit()),
+				spaces.WithListContinueToken(listFlags.ContinueToken()),
+				spaces.WithListFilter(func(space *v1alpha1.Space) bool {
+					return listFlags.MatchesName(space.Name)
+				}),
+			)
 			if err != nil {
 				return err
 			}
 
+			listFlags.Sort(list, func(i int) utils.ListSortKey {
+				key := utils.ListSortKey{
+					Name:    list[i].Name,
+					Created: list[i].CreationTimestamp.Time,
+				}
+				if cond := list[i].Status.GetCondition(v1alpha1.SpaceConditionReady); cond != nil {
+					key.Status = fmt.Sprintf("%v", cond.Status)
+				}
+				return key
+			})
+
 			describe.TabbedWriter(cmd.OutOrStdout(), func(w io.Writer) {
 				fmt.Fprintln(w, "Name\tAge\tReady\tReason")
 				for _, space := range list {
@@ -71,9 +100,13 @@ func NewListSpacesCommand(p *config.KfParams, client spaces.Client) *cobra.Comma
 				}
 			})
 
+			listFlags.PrintNextPage(cmd.OutOrStdout(), continueToken)
+
 			return nil
 		},
 	}
 
+	listFlags.Add(cmd)
+
 	return cmd
 }
@@ -46,8 +46,8 @@ func TestNewListSpacesCommand(t *testing.T) {
 				list := []v1alpha1.Space{}
 				fakeSpaces.
 					EXPECT().
-					List().
-					Return(list, nil)
+					ListPage(gomock.Any()).
+					Return(list, "", nil)
 			},
 			expectedStrings: []string{"Name", "Age", "Ready", "Reason"},
 		},
@@ -64,17 +64,34 @@ func TestNewListSpacesCommand(t *testing.T) {
 				list := []v1alpha1.Space{ns}
 				fakeSpaces.
 					EXPECT().
-					List().
-					Return(list, nil)
+					ListPage(gomock.Any()).
+					Return(list, "", nil)
 			},
 			expectedStrings: []string{"my-ns", "TESTING", "SomeMessage"},
 		},
+		"next page": {
+			args: []string{"--limit", "1"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient) {
+				ns := v1alpha1.Space{}
+				ns.Name = "my-ns"
+
+				fakeSpaces.
+					EXPECT().
+					ListPage(gomock.Any()).
+					Return([]v1alpha1.Space{ns}, "next-token", nil)
+			},
+			expectedStrings: []string{"my-ns", "--continue next-token"},
+		},
+		"invalid limit": {
+			args:    []string{"--limit", "-1"},
+			wantErr: errors.New("--limit must be zero or greater, got -1"),
+		},
 		"server failure": {
 			setup: func(t *testing.T, fakeSpaces *fake.FakeClient) {
 				fakeSpaces.
 					EXPECT().
-					List().
-					Return(nil, errors.New("some-server-error"))
+					ListPage(gomock.Any()).
+					Return(nil, "", errors.New("some-server-error"))
 			},
 			wantErr: errors.New("some-server-error"),
 		},
@@ -0,0 +1,228 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/commands/completion"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SnapshotDigestAlgorithm is used to sign snapshots when no signing key
+	// is given. It only detects accidental changes to a report.
+	SnapshotDigestAlgorithm = "sha256"
+
+	// SnapshotHMACAlgorithm is used to sign snapshots with a signing key.
+	SnapshotHMACAlgorithm = "hmac-sha256"
+)
+
+// SignedSnapshot is an inventory of a space and the signature of it.
+type SignedSnapshot struct {
+	Snapshot  Snapshot          `json:"snapshot"`
+	Signature SnapshotSignature `json:"signature"`
+}
+
+// SnapshotSignature signs the compact JSON encoding of a Snapshot.
+type SnapshotSignature struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"value"`
+}
+
+// Snapshot is a read-only inventory of a space for audits.
+type Snapshot struct {
+	Space      string        `json:"space"`
+	CapturedAt time.Time     `json:"capturedAt"`
+	Quota      SnapshotQuota `json:"quota"`
+	Apps       []SnapshotApp `json:"apps"`
+}
+
+// SnapshotQuota is the resource quota of a space.
+type SnapshotQuota struct {
+	Hard corev1.ResourceList `json:"hard,omitempty"`
+	Used corev1.ResourceList `json:"used,omitempty"`
+}
+
+// SnapshotApp is the deployed state of an App.
+type SnapshotApp struct {
+	Name      string                    `json:"name"`
+	Image     string                    `json:"image,omitempty"`
+	Revision  string                    `json:"revision,omitempty"`
+	Instances v1alpha1.AppSpecInstances `json:"instances"`
+	Routes    []string                  `json:"routes,omitempty"`
+	Bindings  []SnapshotBinding         `json:"bindings,omitempty"`
+}
+
+// SnapshotBinding is a service bound to an App.
+type SnapshotBinding struct {
+	Name     string `json:"name"`
+	Instance string `json:"instance"`
+}
+
+// SignSnapshot signs the snapshot with HMAC-SHA256 if key is set, otherwise
+// with a SHA-256 digest.
+func SignSnapshot(snapshot Snapshot, key []byte) (*SignedSnapshot, error) {
+	contents, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := SnapshotSignature{Algorithm: SnapshotDigestAlgorithm}
+	if len(key) > 0 {
+		mac := hmac.New(sha256.New, key)
+		mac.Write(contents)
+		signature.Algorithm = SnapshotHMACAlgorithm
+		signature.Value = hex.EncodeToString(mac.Sum(nil))
+	} else {
+		sum := sha256.Sum256(contents)
+		signature.Value = hex.EncodeToString(sum[:])
+	}
+
+	return &SignedSnapshot{
+		Snapshot:  snapshot,
+		Signature: signature,
+	}, nil
+}
+
+func newSnapshot(space *v1alpha1.Space, appList []v1alpha1.App, capturedAt time.Time) Snapshot {
+	snapshot := Snapshot{
+		Space:      space.Name,
+		CapturedAt: capturedAt.UTC(),
+		Quota: SnapshotQuota{
+			Hard: space.Status.Quota.Hard,
+			Used: space.Status.Quota.Used,
+		},
+		Apps: []SnapshotApp{},
+	}
+
+	for _, app := range appList {
+		snapshotApp := SnapshotApp{
+			Name:      app.Name,
+			Image:     app.Status.ImageReference(),
+			Revision:  app.Status.LatestReadyRevisionName,
+			Instances: app.Spec.Instances,
+		}
+
+		for _, route := range app.Spec.Routes {
+			snapshotApp.Routes = append(snapshotApp.Routes, route.String())
+		}
+
+		for _, binding := range app.Spec.ServiceBindings {
+			name := binding.BindingName
+			if name == "" {
+				name = binding.Instance
+			}
+
+			snapshotApp.Bindings = append(snapshotApp.Bindings, SnapshotBinding{
+				Name:     name,
+				Instance: binding.Instance,
+			})
+		}
+
+		snapshot.Apps = append(snapshot.Apps, snapshotApp)
+	}
+
+	return snapshot
+}
+
+// NewSnapshotCommand creates a command that writes a signed inventory of a
+// space.
+func NewSnapshotCommand(
+	p *config.KfParams,
+	client spaces.Client,
+	appsClient apps.Client,
+) *cobra.Command {
+	var signingKeyFile string
+
+	cmd := &cobra.Command{
+		Use:   "snapshot SPACE_NAME",
+		Short: "Write a signed inventory of a space for audits",
+		Long: `Write a signed inventory of a space for audits.
+
+		The report lists the space's quota and each App's image, revision,
+		scaling, routes, and service bindings as JSON on stdout. Only read
+		requests are made so the command can be run with --readonly.
+
+		The signature covers the compact JSON encoding of the snapshot field.
+		With --signing-key-file it is an HMAC-SHA256 using the contents of the
+		file as the key, otherwise it's a SHA-256 digest that only detects
+		accidental changes.
+		`,
+		Example: `
+		kf snapshot my-space > my-space-audit.json
+		kf snapshot my-space --signing-key-file audit.key
+		`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			spaceName := args[0]
+
+			var key []byte
+			if signingKeyFile != "" {
+				var err error
+				if key, err = ioutil.ReadFile(signingKeyFile); err != nil {
+					return fmt.Errorf("failed to read signing key: %s", err)
+				}
+			}
+
+			space, err := client.Get(spaceName)
+			if err != nil {
+				return err
+			}
+
+			appList, err := appsClient.List(spaceName)
+			if err != nil {
+				return err
+			}
+
+			signed, err := SignSnapshot(newSnapshot(space, appList, time.Now()), key)
+			if err != nil {
+				return err
+			}
+
+			out, err := json.MarshalIndent(signed, "", "  ")
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), string(out))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(
+		&signingKeyFile,
+		"signing-key-file",
+		"",
+		"File containing a key to sign the report with HMAC-SHA256.",
+	)
+
+	completion.MarkArgCompletionSupported(cmd, completion.SpaceCompletion)
+
+	return cmd
+}
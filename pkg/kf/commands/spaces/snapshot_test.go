@@ -0,0 +1,170 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	appsfake "github.com/google/kf/pkg/kf/apps/fake"
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/spaces/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewSnapshotCommand(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "snapshot")
+	testutil.AssertNil(t, "err", err)
+	defer os.RemoveAll(dir)
+
+	keyFile := filepath.Join(dir, "audit.key")
+	testutil.AssertNil(t, "err", ioutil.WriteFile(keyFile, []byte("secret"), 0600))
+
+	space := &v1alpha1.Space{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-space"},
+		Status: v1alpha1.SpaceStatus{
+			Quota: corev1.ResourceQuotaStatus{
+				Hard: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Used: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("512Mi")},
+			},
+		},
+	}
+
+	apps := []v1alpha1.App{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-app"},
+			Spec: v1alpha1.AppSpec{
+				Routes: []v1alpha1.RouteSpecFields{
+					{Hostname: "my-app", Domain: "example.com"},
+				},
+				ServiceBindings: []v1alpha1.AppSpecServiceBinding{
+					{Instance: "my-db"},
+					{Instance: "my-cache", BindingName: "cache"},
+				},
+			},
+			Status: v1alpha1.AppStatus{
+				SourceStatusFields: v1alpha1.SourceStatusFields{Image: "gcr.io/my-app@sha256:abc"},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		args          []string
+		setup         func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient)
+		wantErr       error
+		wantOut       []string
+		wantAlgorithm string
+		wantKey       []byte
+	}{
+		"invalid number of args": {
+			args:    []string{},
+			wantErr: errors.New("accepts 1 arg(s), received 0"),
+		},
+		"missing signing key": {
+			args:    []string{"my-space", "--signing-key-file", filepath.Join(dir, "missing")},
+			wantErr: errors.New("failed to read signing key: open " + filepath.Join(dir, "missing") + ": no such file or directory"),
+		},
+		"space get fails": {
+			args: []string{"my-space"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("my-space").Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("some-error"),
+		},
+		"apps list fails": {
+			args: []string{"my-space"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("my-space").Return(space, nil)
+				fakeApps.EXPECT().List("my-space").Return(nil, errors.New("some-error"))
+			},
+			wantErr: errors.New("some-error"),
+		},
+		"digest": {
+			args: []string{"my-space"},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("my-space").Return(space, nil)
+				fakeApps.EXPECT().List("my-space").Return(apps, nil)
+			},
+			wantOut: []string{
+				`"space": "my-space"`,
+				`"memory": "1Gi"`,
+				`"memory": "512Mi"`,
+				`"image": "gcr.io/my-app@sha256:abc"`,
+				`"my-app.example.com/"`,
+				`"name": "cache"`,
+				`"instance": "my-cache"`,
+			},
+			wantAlgorithm: SnapshotDigestAlgorithm,
+		},
+		"hmac": {
+			args: []string{"my-space", "--signing-key-file", keyFile},
+			setup: func(t *testing.T, fakeSpaces *fake.FakeClient, fakeApps *appsfake.FakeClient) {
+				fakeSpaces.EXPECT().Get("my-space").Return(space, nil)
+				fakeApps.EXPECT().List("my-space").Return(apps, nil)
+			},
+			wantAlgorithm: SnapshotHMACAlgorithm,
+			wantKey:       []byte("secret"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeSpaces := fake.NewFakeClient(ctrl)
+			fakeApps := appsfake.NewFakeClient(ctrl)
+
+			if tc.setup != nil {
+				tc.setup(t, fakeSpaces, fakeApps)
+			}
+
+			stdout := &bytes.Buffer{}
+
+			c := NewSnapshotCommand(&config.KfParams{}, fakeSpaces, fakeApps)
+			c.SetOutput(stdout)
+			c.SetArgs(tc.args)
+
+			gotErr := c.Execute()
+			testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+			if gotErr != nil {
+				return
+			}
+
+			testutil.AssertContainsAll(t, stdout.String(), tc.wantOut)
+
+			got := &SignedSnapshot{}
+			testutil.AssertNil(t, "unmarshal err", json.Unmarshal(stdout.Bytes(), got))
+			testutil.AssertEqual(t, "algorithm", tc.wantAlgorithm, got.Signature.Algorithm)
+
+			// The signature must be reproducible from the report alone.
+			resigned, err := SignSnapshot(got.Snapshot, tc.wantKey)
+			testutil.AssertNil(t, "sign err", err)
+			testutil.AssertEqual(t, "signature", resigned.Signature, got.Signature)
+
+			ctrl.Finish()
+		})
+	}
+}
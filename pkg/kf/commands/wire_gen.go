@@ -11,32 +11,49 @@ import (
 	"github.com/google/kf/pkg/client/servicecatalog/clientset/versioned"
 	"github.com/google/kf/pkg/client/servicecatalog/clientset/versioned/typed/servicecatalog/v1beta1"
 	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/buildlogs"
 	"github.com/google/kf/pkg/kf/buildpacks"
 	apps2 "github.com/google/kf/pkg/kf/commands/apps"
+	"github.com/google/kf/pkg/kf/commands/auth"
 	buildpacks2 "github.com/google/kf/pkg/kf/commands/buildpacks"
 	"github.com/google/kf/pkg/kf/commands/builds"
+	"github.com/google/kf/pkg/kf/commands/cluster"
 	"github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
 	"github.com/google/kf/pkg/kf/commands/quotas"
 	routes2 "github.com/google/kf/pkg/kf/commands/routes"
+	"github.com/google/kf/pkg/kf/commands/secrets"
 	servicebindings2 "github.com/google/kf/pkg/kf/commands/service-bindings"
 	"github.com/google/kf/pkg/kf/commands/service-brokers"
 	services2 "github.com/google/kf/pkg/kf/commands/services"
 	spaces2 "github.com/google/kf/pkg/kf/commands/spaces"
+	"github.com/google/kf/pkg/kf/devservices"
+	"github.com/google/kf/pkg/kf/externalsecrets"
+	"github.com/google/kf/pkg/kf/failures"
+	"github.com/google/kf/pkg/kf/hooks"
+	"github.com/google/kf/pkg/kf/internal/utils/cli"
 	"github.com/google/kf/pkg/kf/istio"
 	"github.com/google/kf/pkg/kf/logs"
 	"github.com/google/kf/pkg/kf/marketplace"
+	"github.com/google/kf/pkg/kf/metrics"
+	"github.com/google/kf/pkg/kf/report"
 	"github.com/google/kf/pkg/kf/routeclaims"
 	"github.com/google/kf/pkg/kf/routes"
 	"github.com/google/kf/pkg/kf/service-bindings"
+	"github.com/google/kf/pkg/kf/service-shares"
 	"github.com/google/kf/pkg/kf/services"
+	"github.com/google/kf/pkg/kf/slos"
+	"github.com/google/kf/pkg/kf/sourceimage"
 	"github.com/google/kf/pkg/kf/sources"
 	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/google/kf/pkg/kf/stacks"
+	"github.com/google/kf/pkg/kf/tracing"
 	logs2 "github.com/google/kf/third_party/knative-build/pkg/logs"
 	"github.com/google/wire"
-	"github.com/poy/kontext"
 	"github.com/spf13/cobra"
+	v12 "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	"k8s.io/client-go/kubernetes/typed/core/v1"
+	v13 "k8s.io/client-go/kubernetes/typed/networking/v1"
 )
 
 import (
@@ -51,13 +68,35 @@ func InjectPush(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
-	pusher := apps.NewPusher(appsClient)
+	routeclaimsClient := routeclaims.NewClient(kfV1alpha1Interface)
+	pusher := apps.NewPusher(appsClient, routeclaimsClient)
 	srcImageBuilder := provideSrcImageBuilder()
 	versionedInterface := config.GetServiceCatalogClient(p)
 	clientInterface := servicebindings.NewClient(versionedInterface)
-	command := apps2.NewPushCommand(p, appsClient, pusher, srcImageBuilder, clientInterface)
+	podsGetter := providePodsGetter(p)
+	taskRunner := hooks.NewTaskRunner(podsGetter)
+	stacksClient := InjectStacksClient(p)
+	tracingConfigLoader := provideTracingConfigLoader(p)
+	kubernetesInterface := config.GetKubernetes(p)
+	buildV1alpha1Interface := config.GetBuildClient(p)
+	explainer := failures.NewExplainer(kubernetesInterface, kfV1alpha1Interface, buildV1alpha1Interface)
+	command := apps2.NewPushCommand(p, appsClient, pusher, srcImageBuilder, clientInterface, taskRunner, stacksClient, tracingConfigLoader, explainer)
+	return command
+}
+
+func InjectDeployBlueGreen(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	pushCommandFactory := providePushCommandFactory(p)
+	command := apps2.NewDeployBlueGreenCommand(p, appsClient, pushCommandFactory)
 	return command
 }
 
@@ -66,7 +105,8 @@ func InjectDelete(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := apps2.NewDeleteCommand(p, appsClient)
 	return command
@@ -77,7 +117,8 @@ func InjectApps(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := apps2.NewAppsCommand(p, appsClient)
 	return command
@@ -88,9 +129,17 @@ func InjectGetApp(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
-	command := apps2.NewGetAppCommand(p, appsClient)
+	kubernetesInterface := config.GetKubernetes(p)
+	metricsClient := metrics.NewClient(kubernetesInterface)
+	eventsGetter := provideEventsGetter(p)
+	versionedInterface := config.GetServiceCatalogClient(p)
+	clientInterface := servicebindings.NewClient(versionedInterface)
+	serviceInstancesGetter := provideServiceInstancesGetter(versionedInterface)
+	servicesClient := services.NewClient(serviceInstancesGetter)
+	command := apps2.NewGetAppCommand(p, appsClient, metricsClient, eventsGetter, clientInterface, servicesClient)
 	return command
 }
 
@@ -99,7 +148,8 @@ func InjectScale(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := apps2.NewScaleCommand(p, appsClient)
 	return command
@@ -110,7 +160,8 @@ func InjectStart(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := apps2.NewStartCommand(p, appsClient)
 	return command
@@ -121,7 +172,8 @@ func InjectStop(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := apps2.NewStopCommand(p, appsClient)
 	return command
@@ -132,29 +184,85 @@ func InjectRestart(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := apps2.NewRestartCommand(p, appsClient)
 	return command
 }
 
+func InjectRestartAppInstance(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	coreV1Interface := provideCoreV1(p)
+	command := apps2.NewRestartAppInstanceCommand(p, appsClient, coreV1Interface)
+	return command
+}
+
 func InjectRestage(p *config.KfParams) *cobra.Command {
 	kfV1alpha1Interface := config.GetKfClient(p)
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := apps2.NewRestageCommand(p, appsClient)
 	return command
 }
 
+func InjectPromote(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	spacesGetter := provideKfSpaces(kfV1alpha1Interface)
+	spacesClient := spaces.NewClient(spacesGetter)
+	command := apps2.NewPromoteCommand(p, appsClient, spacesClient)
+	return command
+}
+
+func InjectEnableCITrigger(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	gitTriggersGetter := provideGitTriggersGetter(kfV1alpha1Interface)
+	secretsGetter := provideSecretsGetter(p)
+	command := apps2.NewEnableCITriggerCommand(p, appsClient, gitTriggersGetter, secretsGetter)
+	return command
+}
+
+func InjectConfigureApp(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	command := apps2.NewConfigureAppCommand(p, appsClient)
+	return command
+}
+
 func InjectProxy(p *config.KfParams) *cobra.Command {
 	kfV1alpha1Interface := config.GetKfClient(p)
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	kubernetesInterface := config.GetKubernetes(p)
 	ingressLister := istio.NewIstioClient(kubernetesInterface)
@@ -162,6 +270,21 @@ func InjectProxy(p *config.KfParams) *cobra.Command {
 	return command
 }
 
+func InjectOpen(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	kubernetesInterface := config.GetKubernetes(p)
+	ingressLister := istio.NewIstioClient(kubernetesInterface)
+	browserOpener := provideBrowserOpener()
+	command := apps2.NewOpenCommand(p, appsClient, ingressLister, browserOpener)
+	return command
+}
+
 func InjectLogs(p *config.KfParams) *cobra.Command {
 	coreV1Interface := provideCoreV1(p)
 	tailer := logs.NewTailer(coreV1Interface)
@@ -169,25 +292,94 @@ func InjectLogs(p *config.KfParams) *cobra.Command {
 	return command
 }
 
+func InjectTail(p *config.KfParams) *cobra.Command {
+	coreV1Interface := provideCoreV1(p)
+	kfV1alpha1Interface := config.GetKfClient(p)
+	tailer := logs.NewTailer(coreV1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	buildLogTailer := provideBuildLogTailer(client)
+	unifiedTailer := logs.NewUnifiedTailer(coreV1Interface, kfV1alpha1Interface, tailer, buildLogTailer)
+	command := apps2.NewTailCommand(p, unifiedTailer)
+	return command
+}
+
+func InjectSSH(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	coreV1Interface := provideCoreV1(p)
+	podExecer := providePodExecer(p)
+	command := apps2.NewSSHCommand(p, appsClient, coreV1Interface, podExecer)
+	return command
+}
+
+func InjectRun(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	coreV1Interface := provideCoreV1(p)
+	podAttacher := providePodAttacher(p)
+	command := apps2.NewRunCommand(p, appsClient, coreV1Interface, podAttacher)
+	return command
+}
+
+func InjectReport(p *config.KfParams) *cobra.Command {
+	kubernetesInterface := config.GetKubernetes(p)
+	kfV1alpha1Interface := config.GetKfClient(p)
+	buildV1alpha1Interface := config.GetBuildClient(p)
+	dynamicInterface := config.GetDynamicClient(p)
+	client := report.NewClient(kubernetesInterface, kfV1alpha1Interface, buildV1alpha1Interface, dynamicInterface)
+	command := apps2.NewReportCommand(p, client)
+	return command
+}
+
 func InjectEnv(p *config.KfParams) *cobra.Command {
 	kfV1alpha1Interface := config.GetKfClient(p)
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := apps2.NewEnvCommand(p, appsClient)
 	return command
 }
 
+func InjectEnvDiff(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	spacesGetter := provideKfSpaces(kfV1alpha1Interface)
+	spacesClient := spaces.NewClient(spacesGetter)
+	command := apps2.NewEnvDiffCommand(p, appsClient, spacesClient)
+	return command
+}
+
 func InjectSetEnv(p *config.KfParams) *cobra.Command {
 	kfV1alpha1Interface := config.GetKfClient(p)
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
-	command := apps2.NewSetEnvCommand(p, appsClient)
+	externalsecretsClient := InjectExternalSecretsClient(p)
+	command := apps2.NewSetEnvCommand(p, appsClient, externalsecretsClient)
 	return command
 }
 
@@ -196,12 +388,44 @@ func InjectUnsetEnv(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := apps2.NewUnsetEnvCommand(p, appsClient)
 	return command
 }
 
+func InjectCreateSecret(p *config.KfParams) *cobra.Command {
+	secretsGetter := provideSecretsGetter(p)
+	command := secrets.NewCreateSecretCommand(p, secretsGetter)
+	return command
+}
+
+func InjectListSecrets(p *config.KfParams) *cobra.Command {
+	secretsGetter := provideSecretsGetter(p)
+	command := secrets.NewListSecretsCommand(p, secretsGetter)
+	return command
+}
+
+func InjectDeleteSecret(p *config.KfParams) *cobra.Command {
+	secretsGetter := provideSecretsGetter(p)
+	command := secrets.NewDeleteSecretCommand(p, secretsGetter)
+	return command
+}
+
+func InjectAuth(p *config.KfParams) *cobra.Command {
+	identityLoader := provideIdentityLoader(p)
+	selfSubjectAccessReviewsGetter := provideSelfSubjectAccessReviewsGetter(p)
+	command := auth.NewAuthCommand(p, identityLoader, selfSubjectAccessReviewsGetter)
+	return command
+}
+
+func InjectConfigureCluster(p *config.KfParams) *cobra.Command {
+	client := config.GetClusterDefaultsClient(p)
+	command := cluster.NewConfigureClusterCommand(p, client)
+	return command
+}
+
 func InjectCreateService(p *config.KfParams) *cobra.Command {
 	versionedInterface := config.GetServiceCatalogClient(p)
 	serviceInstancesGetter := provideServiceInstancesGetter(versionedInterface)
@@ -228,6 +452,15 @@ func InjectGetService(p *config.KfParams) *cobra.Command {
 	return command
 }
 
+func InjectUpdateService(p *config.KfParams) *cobra.Command {
+	versionedInterface := config.GetServiceCatalogClient(p)
+	serviceInstancesGetter := provideServiceInstancesGetter(versionedInterface)
+	client := services.NewClient(serviceInstancesGetter)
+	clientInterface := servicebindings.NewClient(versionedInterface)
+	command := services2.NewUpdateServiceCommand(p, client, clientInterface)
+	return command
+}
+
 func InjectListServices(p *config.KfParams) *cobra.Command {
 	versionedInterface := config.GetServiceCatalogClient(p)
 	serviceInstancesGetter := provideServiceInstancesGetter(versionedInterface)
@@ -236,7 +469,8 @@ func InjectListServices(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	sourcesClient := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	sourcesClient := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, sourcesClient)
 	sClientFactory := config.GetSvcatApp(p)
 	clientInterface := marketplace.NewClient(sClientFactory, versionedInterface)
@@ -244,6 +478,38 @@ func InjectListServices(p *config.KfParams) *cobra.Command {
 	return command
 }
 
+func InjectExternalSecretsClient(p *config.KfParams) externalsecrets.Client {
+	dynamicInterface := config.GetDynamicClient(p)
+	secretsGetter := provideSecretsGetter(p)
+	client := externalsecrets.NewClient(dynamicInterface, secretsGetter)
+	return client
+}
+
+func InjectServiceSharesClient(p *config.KfParams) serviceshares.Client {
+	versionedInterface := config.GetServiceCatalogClient(p)
+	secretsGetter := provideSecretsGetter(p)
+	client := serviceshares.NewClient(versionedInterface, secretsGetter)
+	return client
+}
+
+func InjectShareService(p *config.KfParams) *cobra.Command {
+	client := InjectServiceSharesClient(p)
+	command := services2.NewShareServiceCommand(p, client)
+	return command
+}
+
+func InjectUnshareService(p *config.KfParams) *cobra.Command {
+	client := InjectServiceSharesClient(p)
+	command := services2.NewUnshareServiceCommand(p, client)
+	return command
+}
+
+func InjectServiceShares(p *config.KfParams) *cobra.Command {
+	client := InjectServiceSharesClient(p)
+	command := services2.NewServiceSharesCommand(p, client)
+	return command
+}
+
 func InjectMarketplace(p *config.KfParams) *cobra.Command {
 	sClientFactory := config.GetSvcatApp(p)
 	versionedInterface := config.GetServiceCatalogClient(p)
@@ -257,7 +523,8 @@ func InjectBindingService(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := servicebindings2.NewBindServiceCommand(p, appsClient)
 	return command
@@ -266,7 +533,9 @@ func InjectBindingService(p *config.KfParams) *cobra.Command {
 func InjectListBindings(p *config.KfParams) *cobra.Command {
 	versionedInterface := config.GetServiceCatalogClient(p)
 	clientInterface := servicebindings.NewClient(versionedInterface)
-	command := servicebindings2.NewListBindingsCommand(p, clientInterface)
+	serviceInstancesGetter := provideServiceInstancesGetter(versionedInterface)
+	client := services.NewClient(serviceInstancesGetter)
+	command := servicebindings2.NewListBindingsCommand(p, clientInterface, client)
 	return command
 }
 
@@ -275,12 +544,25 @@ func InjectUnbindService(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := servicebindings2.NewUnbindServiceCommand(p, appsClient)
 	return command
 }
 
+func InjectConfigureBinding(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	command := servicebindings2.NewConfigureBindingCommand(p, appsClient)
+	return command
+}
+
 func InjectVcapServices(p *config.KfParams) *cobra.Command {
 	kubernetesInterface := config.GetKubernetes(p)
 	command := servicebindings2.NewVcapServicesCommand(p, kubernetesInterface)
@@ -299,6 +581,14 @@ func InjectDeleteServiceBroker(p *config.KfParams) *cobra.Command {
 	return command
 }
 
+func InjectDevServices(p *config.KfParams) *cobra.Command {
+	kubernetesInterface := config.GetKubernetes(p)
+	versionedInterface := config.GetServiceCatalogClient(p)
+	client := devservices.NewClient(kubernetesInterface, versionedInterface)
+	command := servicebrokers.NewDevServicesCommand(p, client)
+	return command
+}
+
 func InjectBuildpacksClient(p *config.KfParams) buildpacks.Client {
 	remoteImageFetcher := provideRemoteImageFetcher()
 	client := buildpacks.NewClient(remoteImageFetcher)
@@ -311,9 +601,57 @@ func InjectBuildpacks(p *config.KfParams) *cobra.Command {
 	return command
 }
 
+func InjectRebaseApps(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	remoteImageFetcher := provideRemoteImageFetcher()
+	remoteImageWriter := provideRemoteImageWriter()
+	rebaser := buildpacks.NewRebaser(remoteImageFetcher, remoteImageWriter)
+	command := apps2.NewRebaseAppsCommand(p, appsClient, client, rebaser)
+	return command
+}
+
+func InjectImageInfo(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, client)
+	remoteImageFetcher := provideRemoteImageFetcher()
+	provenanceReader := buildpacks.NewProvenanceReader(remoteImageFetcher)
+	command := apps2.NewImageInfoCommand(p, appsClient, provenanceReader)
+	return command
+}
+
+func InjectStacksClient(p *config.KfParams) stacks.Client {
+	configMapsGetter := provideConfigMapsGetter(p)
+	client := stacks.NewClient(configMapsGetter)
+	return client
+}
+
 func InjectStacks(p *config.KfParams) *cobra.Command {
 	client := InjectBuildpacksClient(p)
-	command := buildpacks2.NewStacksCommand(p, client)
+	stacksClient := InjectStacksClient(p)
+	command := buildpacks2.NewStacksCommand(p, client, stacksClient)
+	return command
+}
+
+func InjectCreateStack(p *config.KfParams) *cobra.Command {
+	client := InjectStacksClient(p)
+	command := buildpacks2.NewCreateStackCommand(p, client)
+	return command
+}
+
+func InjectDeleteStack(p *config.KfParams) *cobra.Command {
+	client := InjectStacksClient(p)
+	command := buildpacks2.NewDeleteStackCommand(p, client)
 	return command
 }
 
@@ -329,7 +667,8 @@ func InjectSpace(p *config.KfParams) *cobra.Command {
 	kfV1alpha1Interface := config.GetKfClient(p)
 	spacesGetter := provideKfSpaces(kfV1alpha1Interface)
 	client := spaces.NewClient(spacesGetter)
-	command := spaces2.NewGetSpaceCommand(p, client)
+	eventsGetter := provideEventsGetter(p)
+	command := spaces2.NewGetSpaceCommand(p, client, eventsGetter)
 	return command
 }
 
@@ -357,6 +696,49 @@ func InjectConfigSpace(p *config.KfParams) *cobra.Command {
 	return command
 }
 
+func InjectBackupSpace(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	spacesGetter := provideKfSpaces(kfV1alpha1Interface)
+	client := spaces.NewClient(spacesGetter)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	sourcesClient := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, sourcesClient)
+	command := spaces2.NewBackupSpaceCommand(p, client, appsClient)
+	return command
+}
+
+func InjectSnapshot(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	spacesGetter := provideKfSpaces(kfV1alpha1Interface)
+	client := spaces.NewClient(spacesGetter)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	sourcesClient := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, sourcesClient)
+	command := spaces2.NewSnapshotCommand(p, client, appsClient)
+	return command
+}
+
+func InjectCloneSpace(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	spacesGetter := provideKfSpaces(kfV1alpha1Interface)
+	client := spaces.NewClient(spacesGetter)
+	appsGetter := provideAppsGetter(kfV1alpha1Interface)
+	sourcesGetter := provideKfSources(kfV1alpha1Interface)
+	buildTailer := provideSourcesBuildTailer()
+	archive := provideBuildLogArchive()
+	sourcesClient := sources.NewClient(sourcesGetter, buildTailer, archive)
+	appsClient := apps.NewClient(appsGetter, sourcesClient)
+	networkPoliciesGetter := provideNetworkPoliciesGetter(p)
+	command := spaces2.NewCloneSpaceCommand(p, client, appsClient, networkPoliciesGetter)
+	return command
+}
+
 func InjectUpdateQuota(p *config.KfParams) *cobra.Command {
 	kfV1alpha1Interface := config.GetKfClient(p)
 	spacesGetter := provideKfSpaces(kfV1alpha1Interface)
@@ -388,7 +770,8 @@ func InjectRoutes(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	sourcesClient := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	sourcesClient := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, sourcesClient)
 	command := routes2.NewRoutesCommand(p, client, routeclaimsClient, appsClient)
 	return command
@@ -407,18 +790,57 @@ func InjectDeleteRoute(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	sourcesClient := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	sourcesClient := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, sourcesClient)
 	command := routes2.NewDeleteRouteCommand(p, client, appsClient)
 	return command
 }
 
+func InjectSetMaintenance(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	client := routeclaims.NewClient(kfV1alpha1Interface)
+	command := routes2.NewSetMaintenanceCommand(p, client)
+	return command
+}
+
+func InjectSetRoutePolicy(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	client := routeclaims.NewClient(kfV1alpha1Interface)
+	command := routes2.NewSetRoutePolicyCommand(p, client)
+	return command
+}
+
+func InjectSetRouteCORS(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	client := routeclaims.NewClient(kfV1alpha1Interface)
+	command := routes2.NewSetRouteCORSCommand(p, client)
+	return command
+}
+
+func InjectSetRouteHeader(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	client := routeclaims.NewClient(kfV1alpha1Interface)
+	command := routes2.NewSetRouteHeaderCommand(p, client)
+	return command
+}
+
+func InjectRouterLogs(p *config.KfParams) *cobra.Command {
+	kfV1alpha1Interface := config.GetKfClient(p)
+	client := routeclaims.NewClient(kfV1alpha1Interface)
+	coreV1Interface := provideCoreV1(p)
+	routerTailer := logs.NewRouterTailer(coreV1Interface)
+	command := routes2.NewRouterLogsCommand(p, client, routerTailer)
+	return command
+}
+
 func InjectMapRoute(p *config.KfParams) *cobra.Command {
 	kfV1alpha1Interface := config.GetKfClient(p)
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := routes2.NewMapRouteCommand(p, appsClient)
 	return command
@@ -429,7 +851,8 @@ func InjectUnmapRoute(p *config.KfParams) *cobra.Command {
 	appsGetter := provideAppsGetter(kfV1alpha1Interface)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	appsClient := apps.NewClient(appsGetter, client)
 	command := routes2.NewUnmapRouteCommand(p, appsClient)
 	return command
@@ -442,11 +865,41 @@ func InjectProxyRoute(p *config.KfParams) *cobra.Command {
 	return command
 }
 
+func InjectTrace(p *config.KfParams) *cobra.Command {
+	kubernetesInterface := config.GetKubernetes(p)
+	ingressLister := istio.NewIstioClient(kubernetesInterface)
+	tracingConfigLoader := provideRoutesTracingConfigLoader(p)
+	command := routes2.NewTraceCommand(p, ingressLister, tracingConfigLoader)
+	return command
+}
+
+func InjectSLOsClient(p *config.KfParams) slos.Client {
+	dynamicInterface := config.GetDynamicClient(p)
+	servicesGetter := provideServicesGetter(p)
+	kfV1alpha1Interface := config.GetKfClient(p)
+	client := routes.NewClient(kfV1alpha1Interface)
+	slosClient := slos.NewClient(dynamicInterface, servicesGetter, client)
+	return slosClient
+}
+
+func InjectCreateSLO(p *config.KfParams) *cobra.Command {
+	client := InjectSLOsClient(p)
+	command := routes2.NewCreateSLOCommand(p, client)
+	return command
+}
+
+func InjectSLOs(p *config.KfParams) *cobra.Command {
+	client := InjectSLOsClient(p)
+	command := routes2.NewSLOsCommand(p, client)
+	return command
+}
+
 func InjectBuilds(p *config.KfParams) *cobra.Command {
 	kfV1alpha1Interface := config.GetKfClient(p)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	command := builds.NewListBuildsCommand(p, client)
 	return command
 }
@@ -455,7 +908,8 @@ func InjectBuildLogs(p *config.KfParams) *cobra.Command {
 	kfV1alpha1Interface := config.GetKfClient(p)
 	sourcesGetter := provideKfSources(kfV1alpha1Interface)
 	buildTailer := provideSourcesBuildTailer()
-	client := sources.NewClient(sourcesGetter, buildTailer)
+	archive := provideBuildLogArchive()
+	client := sources.NewClient(sourcesGetter, buildTailer, archive)
 	command := builds.NewBuildLogsCommand(p, client)
 	return command
 }
@@ -469,7 +923,41 @@ func InjectNamesCommand(p *config.KfParams) *cobra.Command {
 // wire_injector.go:
 
 func provideSrcImageBuilder() apps2.SrcImageBuilder {
-	return apps2.SrcImageBuilderFunc(kontext.BuildImageWithFilter)
+	return sourceimage.NewBuilder()
+}
+
+func provideTracingConfigLoader(p *config.KfParams) apps2.TracingConfigLoader {
+	return func() (*tracing.Config, error) {
+		return tracing.LoadClusterConfig(config.GetKubernetes(p))
+	}
+}
+
+func providePushCommandFactory(p *config.KfParams) apps2.PushCommandFactory {
+	return func() *cobra.Command {
+		return InjectPush(p)
+	}
+}
+
+func provideBuildLogTailer(c sources.Client) logs.BuildLogTailer {
+	return c
+}
+
+func provideRoutesTracingConfigLoader(p *config.KfParams) routes2.TracingConfigLoader {
+	return func() (*tracing.Config, error) {
+		return tracing.LoadClusterConfig(config.GetKubernetes(p))
+	}
+}
+
+func provideBrowserOpener() apps2.BrowserOpener {
+	return utils.OpenBrowser
+}
+
+func providePodExecer(p *config.KfParams) apps2.PodExecer {
+	return apps2.KubectlPodExecer(p)
+}
+
+func providePodAttacher(p *config.KfParams) apps2.PodAttacher {
+	return apps2.KubectlPodAttacher(p)
 }
 
 var AppsSet = wire.NewSet(
@@ -481,10 +969,44 @@ func provideAppsGetter(ki v1alpha1.KfV1alpha1Interface) v1alpha1.AppsGetter {
 	return ki
 }
 
+func provideGitTriggersGetter(ki v1alpha1.KfV1alpha1Interface) v1alpha1.GitTriggersGetter {
+	return ki
+}
+
 func provideCoreV1(p *config.KfParams) v1.CoreV1Interface {
 	return config.GetKubernetes(p).CoreV1()
 }
 
+func provideEventsGetter(p *config.KfParams) v1.EventsGetter {
+	return config.GetKubernetes(p).CoreV1()
+}
+
+func providePodsGetter(p *config.KfParams) v1.PodsGetter {
+	return config.GetKubernetes(p).CoreV1()
+}
+
+func provideSecretsGetter(p *config.KfParams) v1.SecretsGetter {
+	return config.GetKubernetes(p).CoreV1()
+}
+
+func provideNetworkPoliciesGetter(p *config.KfParams) v13.NetworkPoliciesGetter {
+	return config.GetKubernetes(p).NetworkingV1()
+}
+
+func provideServicesGetter(p *config.KfParams) v1.ServicesGetter {
+	return config.GetKubernetes(p).CoreV1()
+}
+
+func provideIdentityLoader(p *config.KfParams) auth.IdentityLoader {
+	return func() (*config.Identity, error) {
+		return config.GetIdentity(p)
+	}
+}
+
+func provideSelfSubjectAccessReviewsGetter(p *config.KfParams) v12.SelfSubjectAccessReviewsGetter {
+	return config.GetKubernetes(p).AuthorizationV1()
+}
+
 func provideServiceInstancesGetter(sc versioned.Interface) v1beta1.ServiceInstancesGetter {
 	return sc.ServicecatalogV1beta1()
 }
@@ -500,13 +1022,21 @@ func provideRemoteImageFetcher() buildpacks.RemoteImageFetcher {
 	return remote.Image
 }
 
+func provideRemoteImageWriter() buildpacks.RemoteImageWriter {
+	return remote.Write
+}
+
+func provideConfigMapsGetter(p *config.KfParams) v1.ConfigMapsGetter {
+	return config.GetKubernetes(p).CoreV1()
+}
+
 var SpacesSet = wire.NewSet(config.GetKfClient, provideKfSpaces, spaces.NewClient)
 
 func provideKfSpaces(ki v1alpha1.KfV1alpha1Interface) v1alpha1.SpacesGetter {
 	return ki
 }
 
-var SourcesSet = wire.NewSet(config.GetKfClient, provideSourcesBuildTailer, provideKfSources, sources.NewClient)
+var SourcesSet = wire.NewSet(config.GetKfClient, provideSourcesBuildTailer, provideBuildLogArchive, provideKfSources, sources.NewClient)
 
 func provideKfSources(ki v1alpha1.KfV1alpha1Interface) v1alpha1.SourcesGetter {
 	return ki
@@ -515,3 +1045,7 @@ func provideKfSources(ki v1alpha1.KfV1alpha1Interface) v1alpha1.SourcesGetter {
 func provideSourcesBuildTailer() sources.BuildTailer {
 	return sources.BuildTailerFunc(logs2.Tail)
 }
+
+func provideBuildLogArchive() buildlogs.Archive {
+	return buildlogs.NewGCSArchive()
+}
@@ -22,36 +22,65 @@ import (
 	servicecatalogclient "github.com/google/kf/pkg/client/servicecatalog/clientset/versioned"
 	scv1beta1 "github.com/google/kf/pkg/client/servicecatalog/clientset/versioned/typed/servicecatalog/v1beta1"
 	"github.com/google/kf/pkg/kf/apps"
+	"github.com/google/kf/pkg/kf/buildlogs"
 	"github.com/google/kf/pkg/kf/buildpacks"
 	capps "github.com/google/kf/pkg/kf/commands/apps"
+	cauth "github.com/google/kf/pkg/kf/commands/auth"
 	cbuildpacks "github.com/google/kf/pkg/kf/commands/buildpacks"
 	cbuilds "github.com/google/kf/pkg/kf/commands/builds"
+	ccluster "github.com/google/kf/pkg/kf/commands/cluster"
 	ccompletion "github.com/google/kf/pkg/kf/commands/completion"
 	"github.com/google/kf/pkg/kf/commands/config"
 	cquotas "github.com/google/kf/pkg/kf/commands/quotas"
 	croutes "github.com/google/kf/pkg/kf/commands/routes"
+	csecrets "github.com/google/kf/pkg/kf/commands/secrets"
 	servicebindingscmd "github.com/google/kf/pkg/kf/commands/service-bindings"
 	servicebrokerscmd "github.com/google/kf/pkg/kf/commands/service-brokers"
 	servicescmd "github.com/google/kf/pkg/kf/commands/services"
 	cspaces "github.com/google/kf/pkg/kf/commands/spaces"
+	"github.com/google/kf/pkg/kf/devservices"
+	"github.com/google/kf/pkg/kf/externalsecrets"
+	"github.com/google/kf/pkg/kf/failures"
+	"github.com/google/kf/pkg/kf/hooks"
+	utils "github.com/google/kf/pkg/kf/internal/utils/cli"
 	"github.com/google/kf/pkg/kf/istio"
 	kflogs "github.com/google/kf/pkg/kf/logs"
 	"github.com/google/kf/pkg/kf/marketplace"
+	"github.com/google/kf/pkg/kf/metrics"
+	"github.com/google/kf/pkg/kf/report"
 	"github.com/google/kf/pkg/kf/routeclaims"
 	"github.com/google/kf/pkg/kf/routes"
 	servicebindings "github.com/google/kf/pkg/kf/service-bindings"
+	serviceshares "github.com/google/kf/pkg/kf/service-shares"
 	"github.com/google/kf/pkg/kf/services"
+	"github.com/google/kf/pkg/kf/slos"
+	"github.com/google/kf/pkg/kf/sourceimage"
 	"github.com/google/kf/pkg/kf/sources"
 	"github.com/google/kf/pkg/kf/spaces"
+	"github.com/google/kf/pkg/kf/stacks"
+	"github.com/google/kf/pkg/kf/tracing"
 	"github.com/google/kf/third_party/knative-build/pkg/logs"
 	"github.com/google/wire"
-	"github.com/poy/kontext"
 	"github.com/spf13/cobra"
+	authorizationv1 "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	networkingv1 "k8s.io/client-go/kubernetes/typed/networking/v1"
 )
 
 func provideSrcImageBuilder() capps.SrcImageBuilder {
-	return capps.SrcImageBuilderFunc(kontext.BuildImageWithFilter)
+	return sourceimage.NewBuilder()
+}
+
+func provideTracingConfigLoader(p *config.KfParams) capps.TracingConfigLoader {
+	return func() (*tracing.Config, error) {
+		return tracing.LoadClusterConfig(config.GetKubernetes(p))
+	}
+}
+
+func providePushCommandFactory(p *config.KfParams) capps.PushCommandFactory {
+	return func() *cobra.Command {
+		return InjectPush(p)
+	}
 }
 
 ///////////////////
@@ -73,8 +102,25 @@ func InjectPush(p *config.KfParams) *cobra.Command {
 	wire.Build(
 		capps.NewPushCommand,
 		provideSrcImageBuilder,
+		provideTracingConfigLoader,
+		providePodsGetter,
+		hooks.NewTaskRunner,
+		InjectStacksClient,
 		servicebindings.NewClient,
 		config.GetServiceCatalogClient,
+		routeclaims.NewClient,
+		failures.NewExplainer,
+		config.GetKubernetes,
+		config.GetBuildClient,
+		AppsSet,
+	)
+	return nil
+}
+
+func InjectDeployBlueGreen(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewDeployBlueGreenCommand,
+		providePushCommandFactory,
 		AppsSet,
 	)
 	return nil
@@ -93,7 +139,15 @@ func InjectApps(p *config.KfParams) *cobra.Command {
 }
 
 func InjectGetApp(p *config.KfParams) *cobra.Command {
-	wire.Build(capps.NewGetAppCommand, AppsSet)
+	wire.Build(
+		capps.NewGetAppCommand,
+		AppsSet,
+		metrics.NewClient,
+		config.GetKubernetes,
+		provideEventsGetter,
+		servicebindings.NewClient,
+		ServicesSet,
+	)
 
 	return nil
 }
@@ -118,11 +172,50 @@ func InjectRestart(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func InjectRestartAppInstance(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewRestartAppInstanceCommand,
+		AppsSet,
+		provideCoreV1,
+	)
+	return nil
+}
+
 func InjectRestage(p *config.KfParams) *cobra.Command {
 	wire.Build(capps.NewRestageCommand, AppsSet)
 	return nil
 }
 
+func InjectPromote(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewPromoteCommand,
+		AppsSet,
+		provideKfSpaces,
+		spaces.NewClient,
+	)
+
+	return nil
+}
+
+func provideGitTriggersGetter(ki kfv1alpha1.KfV1alpha1Interface) kfv1alpha1.GitTriggersGetter {
+	return ki
+}
+
+func InjectEnableCITrigger(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewEnableCITriggerCommand,
+		AppsSet,
+		provideGitTriggersGetter,
+		provideSecretsGetter,
+	)
+	return nil
+}
+
+func InjectConfigureApp(p *config.KfParams) *cobra.Command {
+	wire.Build(capps.NewConfigureAppCommand, AppsSet)
+	return nil
+}
+
 func InjectProxy(p *config.KfParams) *cobra.Command {
 	wire.Build(
 		capps.NewProxyCommand,
@@ -133,6 +226,21 @@ func InjectProxy(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func provideBrowserOpener() capps.BrowserOpener {
+	return utils.OpenBrowser
+}
+
+func InjectOpen(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewOpenCommand,
+		AppsSet,
+		istio.NewIstioClient,
+		config.GetKubernetes,
+		provideBrowserOpener,
+	)
+	return nil
+}
+
 func InjectLogs(p *config.KfParams) *cobra.Command {
 	wire.Build(
 		capps.NewLogsCommand,
@@ -142,10 +250,80 @@ func InjectLogs(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func InjectTail(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewTailCommand,
+		kflogs.NewUnifiedTailer,
+		kflogs.NewTailer,
+		provideCoreV1,
+		provideBuildLogTailer,
+		SourcesSet,
+	)
+	return nil
+}
+
+func provideBuildLogTailer(c sources.Client) kflogs.BuildLogTailer {
+	return c
+}
+
+func provideRoutesTracingConfigLoader(p *config.KfParams) croutes.TracingConfigLoader {
+	return func() (*tracing.Config, error) {
+		return tracing.LoadClusterConfig(config.GetKubernetes(p))
+	}
+}
+
+func providePodExecer(p *config.KfParams) capps.PodExecer {
+	return capps.KubectlPodExecer(p)
+}
+
+func InjectSSH(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewSSHCommand,
+		AppsSet,
+		provideCoreV1,
+		providePodExecer,
+	)
+	return nil
+}
+
+func providePodAttacher(p *config.KfParams) capps.PodAttacher {
+	return capps.KubectlPodAttacher(p)
+}
+
+func InjectRun(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewRunCommand,
+		AppsSet,
+		provideCoreV1,
+		providePodAttacher,
+	)
+	return nil
+}
+
+func InjectReport(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewReportCommand,
+		report.NewClient,
+		config.GetKubernetes,
+		config.GetKfClient,
+		config.GetBuildClient,
+		config.GetDynamicClient,
+	)
+	return nil
+}
+
 func provideCoreV1(p *config.KfParams) corev1.CoreV1Interface {
 	return config.GetKubernetes(p).CoreV1()
 }
 
+func provideEventsGetter(p *config.KfParams) corev1.EventsGetter {
+	return config.GetKubernetes(p).CoreV1()
+}
+
+func providePodsGetter(p *config.KfParams) corev1.PodsGetter {
+	return config.GetKubernetes(p).CoreV1()
+}
+
 /////////////////////////////////////
 // Environment Variables Commands //
 ///////////////////////////////////
@@ -156,8 +334,19 @@ func InjectEnv(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func InjectEnvDiff(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewEnvDiffCommand,
+		AppsSet,
+		provideKfSpaces,
+		spaces.NewClient,
+	)
+
+	return nil
+}
+
 func InjectSetEnv(p *config.KfParams) *cobra.Command {
-	wire.Build(capps.NewSetEnvCommand, AppsSet)
+	wire.Build(capps.NewSetEnvCommand, AppsSet, InjectExternalSecretsClient)
 
 	return nil
 }
@@ -168,6 +357,65 @@ func InjectUnsetEnv(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+/////////////////////
+// Secrets Commands //
+/////////////////////
+
+func InjectCreateSecret(p *config.KfParams) *cobra.Command {
+	wire.Build(csecrets.NewCreateSecretCommand, provideSecretsGetter)
+
+	return nil
+}
+
+func InjectListSecrets(p *config.KfParams) *cobra.Command {
+	wire.Build(csecrets.NewListSecretsCommand, provideSecretsGetter)
+
+	return nil
+}
+
+func InjectDeleteSecret(p *config.KfParams) *cobra.Command {
+	wire.Build(csecrets.NewDeleteSecretCommand, provideSecretsGetter)
+
+	return nil
+}
+
+//////////////////
+// Auth Commands //
+//////////////////
+
+func provideIdentityLoader(p *config.KfParams) cauth.IdentityLoader {
+	return func() (*config.Identity, error) {
+		return config.GetIdentity(p)
+	}
+}
+
+func provideSelfSubjectAccessReviewsGetter(p *config.KfParams) authorizationv1.SelfSubjectAccessReviewsGetter {
+	return config.GetKubernetes(p).AuthorizationV1()
+}
+
+func InjectAuth(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		cauth.NewAuthCommand,
+		provideIdentityLoader,
+		provideSelfSubjectAccessReviewsGetter,
+	)
+
+	return nil
+}
+
+/////////////////////
+// Cluster Commands //
+/////////////////////
+
+func InjectConfigureCluster(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		ccluster.NewConfigureClusterCommand,
+		config.GetClusterDefaultsClient,
+	)
+
+	return nil
+}
+
 ////////////////
 // Services //
 /////////////
@@ -208,6 +456,15 @@ func InjectGetService(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func InjectUpdateService(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		servicescmd.NewUpdateServiceCommand,
+		servicebindings.NewClient,
+		ServicesSet,
+	)
+	return nil
+}
+
 func InjectListServices(p *config.KfParams) *cobra.Command {
 	wire.Build(
 		servicescmd.NewListServicesCommand,
@@ -217,6 +474,52 @@ func InjectListServices(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func provideSecretsGetter(p *config.KfParams) corev1.SecretsGetter {
+	return config.GetKubernetes(p).CoreV1()
+}
+
+func InjectExternalSecretsClient(p *config.KfParams) externalsecrets.Client {
+	wire.Build(
+		externalsecrets.NewClient,
+		config.GetDynamicClient,
+		provideSecretsGetter,
+	)
+	return nil
+}
+
+func InjectServiceSharesClient(p *config.KfParams) serviceshares.Client {
+	wire.Build(
+		serviceshares.NewClient,
+		config.GetServiceCatalogClient,
+		provideSecretsGetter,
+	)
+	return nil
+}
+
+func InjectShareService(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		servicescmd.NewShareServiceCommand,
+		InjectServiceSharesClient,
+	)
+	return nil
+}
+
+func InjectUnshareService(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		servicescmd.NewUnshareServiceCommand,
+		InjectServiceSharesClient,
+	)
+	return nil
+}
+
+func InjectServiceShares(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		servicescmd.NewServiceSharesCommand,
+		InjectServiceSharesClient,
+	)
+	return nil
+}
+
 func InjectMarketplace(p *config.KfParams) *cobra.Command {
 	wire.Build(
 		servicescmd.NewMarketplaceCommand,
@@ -240,7 +543,7 @@ func InjectListBindings(p *config.KfParams) *cobra.Command {
 	wire.Build(
 		servicebindings.NewClient,
 		servicebindingscmd.NewListBindingsCommand,
-		config.GetServiceCatalogClient,
+		ServicesSet,
 	)
 	return nil
 }
@@ -253,6 +556,14 @@ func InjectUnbindService(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func InjectConfigureBinding(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		servicebindingscmd.NewConfigureBindingCommand,
+		AppsSet,
+	)
+	return nil
+}
+
 func InjectVcapServices(p *config.KfParams) *cobra.Command {
 	wire.Build(
 		servicebindingscmd.NewVcapServicesCommand,
@@ -280,6 +591,16 @@ func InjectDeleteServiceBroker(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func InjectDevServices(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		servicebrokerscmd.NewDevServicesCommand,
+		devservices.NewClient,
+		config.GetKubernetes,
+		config.GetServiceCatalogClient,
+	)
+	return nil
+}
+
 /////////////////
 // Buildpacks //
 ///////////////
@@ -303,10 +624,64 @@ func InjectBuildpacks(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func provideConfigMapsGetter(p *config.KfParams) corev1.ConfigMapsGetter {
+	return config.GetKubernetes(p).CoreV1()
+}
+
+func provideRemoteImageWriter() buildpacks.RemoteImageWriter {
+	return remote.Write
+}
+
+func InjectRebaseApps(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewRebaseAppsCommand,
+		AppsSet,
+		buildpacks.NewRebaser,
+		provideRemoteImageFetcher,
+		provideRemoteImageWriter,
+	)
+	return nil
+}
+
+func InjectImageInfo(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		capps.NewImageInfoCommand,
+		AppsSet,
+		buildpacks.NewProvenanceReader,
+		provideRemoteImageFetcher,
+	)
+	return nil
+}
+
+func InjectStacksClient(p *config.KfParams) stacks.Client {
+	wire.Build(
+		stacks.NewClient,
+		provideConfigMapsGetter,
+	)
+	return nil
+}
+
 func InjectStacks(p *config.KfParams) *cobra.Command {
 	wire.Build(
 		cbuildpacks.NewStacksCommand,
 		InjectBuildpacksClient,
+		InjectStacksClient,
+	)
+	return nil
+}
+
+func InjectCreateStack(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		cbuildpacks.NewCreateStackCommand,
+		InjectStacksClient,
+	)
+	return nil
+}
+
+func InjectDeleteStack(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		cbuildpacks.NewDeleteStackCommand,
+		InjectStacksClient,
 	)
 	return nil
 }
@@ -328,7 +703,7 @@ func InjectSpaces(p *config.KfParams) *cobra.Command {
 }
 
 func InjectSpace(p *config.KfParams) *cobra.Command {
-	wire.Build(cspaces.NewGetSpaceCommand, SpacesSet)
+	wire.Build(cspaces.NewGetSpaceCommand, SpacesSet, provideEventsGetter)
 
 	return nil
 }
@@ -351,6 +726,44 @@ func InjectConfigSpace(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func InjectBackupSpace(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		cspaces.NewBackupSpaceCommand,
+		provideKfSpaces,
+		spaces.NewClient,
+		AppsSet,
+	)
+
+	return nil
+}
+
+func InjectSnapshot(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		cspaces.NewSnapshotCommand,
+		provideKfSpaces,
+		spaces.NewClient,
+		AppsSet,
+	)
+
+	return nil
+}
+
+func InjectCloneSpace(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		cspaces.NewCloneSpaceCommand,
+		provideKfSpaces,
+		spaces.NewClient,
+		AppsSet,
+		provideNetworkPoliciesGetter,
+	)
+
+	return nil
+}
+
+func provideNetworkPoliciesGetter(p *config.KfParams) networkingv1.NetworkPoliciesGetter {
+	return config.GetKubernetes(p).NetworkingV1()
+}
+
 ////////////////////
 // Quotas Command //
 ////////////////////
@@ -405,6 +818,53 @@ func InjectDeleteRoute(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func InjectSetMaintenance(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		croutes.NewSetMaintenanceCommand,
+		routeclaims.NewClient,
+		config.GetKfClient,
+	)
+	return nil
+}
+
+func InjectSetRoutePolicy(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		croutes.NewSetRoutePolicyCommand,
+		routeclaims.NewClient,
+		config.GetKfClient,
+	)
+	return nil
+}
+
+func InjectSetRouteCORS(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		croutes.NewSetRouteCORSCommand,
+		routeclaims.NewClient,
+		config.GetKfClient,
+	)
+	return nil
+}
+
+func InjectSetRouteHeader(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		croutes.NewSetRouteHeaderCommand,
+		routeclaims.NewClient,
+		config.GetKfClient,
+	)
+	return nil
+}
+
+func InjectRouterLogs(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		croutes.NewRouterLogsCommand,
+		routeclaims.NewClient,
+		config.GetKfClient,
+		kflogs.NewRouterTailer,
+		provideCoreV1,
+	)
+	return nil
+}
+
 func InjectMapRoute(p *config.KfParams) *cobra.Command {
 	wire.Build(
 		croutes.NewMapRouteCommand,
@@ -430,11 +890,46 @@ func InjectProxyRoute(p *config.KfParams) *cobra.Command {
 	return nil
 }
 
+func InjectTrace(p *config.KfParams) *cobra.Command {
+	wire.Build(
+		croutes.NewTraceCommand,
+		istio.NewIstioClient,
+		config.GetKubernetes,
+		provideRoutesTracingConfigLoader,
+	)
+	return nil
+}
+
+func provideServicesGetter(p *config.KfParams) corev1.ServicesGetter {
+	return config.GetKubernetes(p).CoreV1()
+}
+
+func InjectSLOsClient(p *config.KfParams) slos.Client {
+	wire.Build(
+		slos.NewClient,
+		routes.NewClient,
+		config.GetKfClient,
+		config.GetDynamicClient,
+		provideServicesGetter,
+	)
+	return nil
+}
+
+func InjectCreateSLO(p *config.KfParams) *cobra.Command {
+	wire.Build(croutes.NewCreateSLOCommand, InjectSLOsClient)
+	return nil
+}
+
+func InjectSLOs(p *config.KfParams) *cobra.Command {
+	wire.Build(croutes.NewSLOsCommand, InjectSLOsClient)
+	return nil
+}
+
 ////////////////////
 // Builds Command //
 ////////////////////
 
-var SourcesSet = wire.NewSet(config.GetKfClient, provideSourcesBuildTailer, provideKfSources, sources.NewClient)
+var SourcesSet = wire.NewSet(config.GetKfClient, provideSourcesBuildTailer, provideBuildLogArchive, provideKfSources, sources.NewClient)
 
 func provideKfSources(ki kfv1alpha1.KfV1alpha1Interface) kfv1alpha1.SourcesGetter {
 	return ki
@@ -444,6 +939,10 @@ func provideSourcesBuildTailer() sources.BuildTailer {
 	return sources.BuildTailerFunc(logs.Tail)
 }
 
+func provideBuildLogArchive() buildlogs.Archive {
+	return buildlogs.NewGCSArchive()
+}
+
 func InjectBuilds(p *config.KfParams) *cobra.Command {
 	wire.Build(cbuilds.NewListBuildsCommand, SourcesSet)
 
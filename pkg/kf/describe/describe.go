@@ -19,8 +19,10 @@ import (
 	"fmt"
 	"io"
 	"sort"
+	"strings"
 
 	kfv1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/metrics"
 	"github.com/google/kf/pkg/kf/services"
 	"github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	corev1 "k8s.io/api/core/v1"
@@ -42,6 +44,22 @@ func EnvVars(w io.Writer, vars []corev1.EnvVar) {
 	})
 }
 
+// BuildArgs prints out Dockerfile build args. The values of args read from
+// Secrets aren't known, so the Secret they come from is shown instead.
+func BuildArgs(w io.Writer, args []corev1.EnvVar) {
+
+	SectionWriter(w, "Build Args", func(w io.Writer) {
+		for _, arg := range args {
+			value := arg.Value
+			if ref := arg.ValueFrom; ref != nil && ref.SecretKeyRef != nil {
+				value = fmt.Sprintf("<from Secret %s key %s>", ref.SecretKeyRef.Name, ref.SecretKeyRef.Key)
+			}
+
+			fmt.Fprintf(w, "%s:\t%s\n", arg.Name, value)
+		}
+	})
+}
+
 // TypeMeta prints information about the type.
 func TypeMeta(w io.Writer, meta metav1.TypeMeta) {
 	TabbedWriter(w, func(w io.Writer) {
@@ -218,6 +236,10 @@ func SourceSpec(w io.Writer, spec kfv1alpha1.SourceSpec) {
 				fmt.Fprintf(w, "Source:\t%s\n", build.Source)
 				fmt.Fprintf(w, "Dockerfile Path:\t%s\n", build.Path)
 				fmt.Fprintf(w, "Destination:\t%s\n", build.Image)
+
+				if len(build.BuildArgs) > 0 {
+					BuildArgs(w, build.BuildArgs)
+				}
 			})
 		}
 	})
@@ -246,6 +268,10 @@ func AppSpecInstances(w io.Writer, instances kfv1alpha1.AppSpecInstances) {
 		} else if !hasExactly {
 			fmt.Fprint(w, "Max:\t∞\n")
 		}
+
+		if instances.ColdStartTimeoutSeconds != nil {
+			fmt.Fprintf(w, "Cold start timeout:\t%ds\n", *instances.ColdStartTimeoutSeconds)
+		}
 	})
 }
 
@@ -294,6 +320,10 @@ func HealthCheck(w io.Writer, healthCheck *corev1.Probe) {
 			fmt.Fprintln(w, "Type:\thttp")
 			fmt.Fprintf(w, "Endpoint:\t%s\n", healthCheck.HTTPGet.Path)
 		}
+
+		if healthCheck.Exec != nil {
+			fmt.Fprintln(w, "Type:\thttp (body check)")
+		}
 	})
 }
 
@@ -342,6 +372,81 @@ func ServiceInstance(w io.Writer, service *v1beta1.ServiceInstance) {
 	})
 }
 
+// ServiceBindings prints the state of the last operation on each binding and
+// on the service instance it's bound to so broker failures can be spotted.
+func ServiceBindings(w io.Writer, bindings []v1beta1.ServiceBinding, instances []v1beta1.ServiceInstance) {
+	SectionWriter(w, "Service Bindings", func(w io.Writer) {
+		if len(bindings) == 0 {
+			return
+		}
+
+		instancesByName := make(map[string]v1beta1.ServiceInstance)
+		for _, instance := range instances {
+			instancesByName[instance.Name] = instance
+		}
+
+		TabbedWriter(w, func(w io.Writer) {
+			fmt.Fprintln(w, "Name\tService\tBinding Status\tService Status\tMessage")
+
+			for _, binding := range bindings {
+				bindingStatus, bindingMessage := serviceBindingOperation(binding)
+
+				serviceStatus := "NotFound"
+				serviceMessage := fmt.Sprintf("service %s doesn't exist", binding.Spec.InstanceRef.Name)
+				if instance, ok := instancesByName[binding.Spec.InstanceRef.Name]; ok {
+					serviceStatus, serviceMessage = serviceInstanceOperation(instance)
+				}
+
+				message := bindingMessage
+				if message == "" {
+					message = serviceMessage
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+					binding.Labels[kfv1alpha1.ComponentLabel],
+					binding.Spec.InstanceRef.Name,
+					bindingStatus,
+					serviceStatus,
+					message)
+			}
+		})
+	})
+}
+
+// serviceBindingOperation gets the state of the last operation on a binding
+// and the broker's message if it failed.
+func serviceBindingOperation(binding v1beta1.ServiceBinding) (status, message string) {
+	if binding.Status.CurrentOperation != "" {
+		return fmt.Sprintf("%sInProgress", binding.Status.CurrentOperation), ""
+	}
+
+	for _, cond := range binding.Status.Conditions {
+		if cond.Type == v1beta1.ServiceBindingConditionReady {
+			if cond.Status == v1beta1.ConditionTrue {
+				return cond.Reason, ""
+			}
+			return cond.Reason, cond.Message
+		}
+	}
+
+	return "Unknown", ""
+}
+
+// serviceInstanceOperation gets the state of the last operation on a service
+// instance and the broker's message if it failed.
+func serviceInstanceOperation(instance v1beta1.ServiceInstance) (status, message string) {
+	if instance.Status.CurrentOperation != "" {
+		return fmt.Sprintf("%sInProgress", instance.Status.CurrentOperation), ""
+	}
+
+	cond := services.LastStatusCondition(instance)
+	if cond.Type == v1beta1.ServiceInstanceConditionReady && cond.Status == v1beta1.ConditionTrue {
+		return cond.Reason, ""
+	}
+
+	return cond.Reason, cond.Message
+}
+
 // RouteSpecFieldsList prints a list of routes
 func RouteSpecFieldsList(w io.Writer, routes []kfv1alpha1.RouteSpecFields) {
 	SectionWriter(w, "Routes", func(w io.Writer) {
@@ -350,15 +455,102 @@ func RouteSpecFieldsList(w io.Writer, routes []kfv1alpha1.RouteSpecFields) {
 		}
 
 		TabbedWriter(w, func(w io.Writer) {
-			fmt.Fprintln(w, "Hostname\tDomain\tPath\tURL")
+			fmt.Fprintln(w, "Hostname\tDomain\tPath\tHeaders\tURL")
 
 			for _, route := range routes {
-				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
 					route.Hostname,
 					route.Domain,
 					route.Path,
+					route.HeadersString(),
 					route.String())
 			}
 		})
 	})
 }
+
+// Events prints out the events for an object, most recent last, similar to
+// kubectl describe.
+func Events(w io.Writer, events []corev1.Event) {
+	SectionWriter(w, "Events", func(w io.Writer) {
+		if len(events) == 0 {
+			return
+		}
+
+		sorted := append([]corev1.Event(nil), events...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			return sorted[i].LastTimestamp.Before(&sorted[j].LastTimestamp)
+		})
+
+		fmt.Fprintln(w, "Last Seen\tType\tReason\tObject\tMessage")
+		for _, e := range sorted {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s/%s\t%s\n",
+				translateTimestampSince(e.LastTimestamp),
+				e.Type,
+				e.Reason,
+				e.InvolvedObject.Kind,
+				e.InvolvedObject.Name,
+				strings.TrimSpace(e.Message),
+			)
+		}
+	})
+}
+
+// AppMetrics prints a summary of the resource usage of each instance of an
+// App along with a sparkline of its history.
+func AppMetrics(w io.Writer, history *metrics.History) {
+	SectionWriter(w, fmt.Sprintf("Metrics (%s)", history.Source), func(w io.Writer) {
+		if len(history.Instances) == 0 {
+			return
+		}
+
+		TabbedWriter(w, func(w io.Writer) {
+			fmt.Fprintln(w, "Instance\tMetric\tMin\tAvg\tMax\tLatest\tHistory")
+
+			for _, instance := range history.Instances {
+				name := instance.Name
+				for _, series := range []struct {
+					name    string
+					samples []metrics.Sample
+					format  func(float64) string
+				}{
+					{name: "CPU", samples: instance.CPU, format: formatCores},
+					{name: "Memory", samples: instance.Memory, format: formatBytes},
+					{name: "Requests", samples: instance.Requests, format: formatRate},
+				} {
+					min, avg, max, latest := "-", "-", "-", "-"
+					if stats, ok := metrics.Summarize(series.samples); ok {
+						min = series.format(stats.Min)
+						avg = series.format(stats.Average)
+						max = series.format(stats.Max)
+						latest = series.format(stats.Latest)
+					}
+
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+						name,
+						series.name,
+						min,
+						avg,
+						max,
+						latest,
+						metrics.Sparkline(series.samples))
+
+					// Only print the instance name on its first row.
+					name = ""
+				}
+			}
+		})
+	})
+}
+
+func formatCores(cores float64) string {
+	return fmt.Sprintf("%dm", int64(cores*1000))
+}
+
+func formatBytes(bytes float64) string {
+	return fmt.Sprintf("%.1fMi", bytes/(1024*1024))
+}
+
+func formatRate(rate float64) string {
+	return fmt.Sprintf("%.2f/s", rate)
+}
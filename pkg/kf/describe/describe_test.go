@@ -22,6 +22,7 @@ import (
 
 	kfv1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/describe"
+	"github.com/google/kf/pkg/kf/metrics"
 	"github.com/google/kf/pkg/kf/testutil"
 	"github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	corev1 "k8s.io/api/core/v1"
@@ -283,6 +284,22 @@ func ExampleAppSpecInstances_minMax() {
 	//   Max:       5
 }
 
+func ExampleAppSpecInstances_coldStartTimeout() {
+	min := 0
+	timeout := int64(60)
+	instances := kfv1alpha1.AppSpecInstances{}
+	instances.Min = &min
+	instances.ColdStartTimeoutSeconds = &timeout
+
+	describe.AppSpecInstances(os.Stdout, instances)
+
+	// Output: Scale:
+	//   Stopped?:            false
+	//   Min:                 0
+	//   Max:                 ∞
+	//   Cold start timeout:  60s
+}
+
 func ExampleSourceSpec_buildpack() {
 	spec := kfv1alpha1.SourceSpec{
 		ServiceAccount: "builder-account",
@@ -345,6 +362,37 @@ func ExampleSourceSpec_dockerfile() {
 	//     Destination:      gcr.io/my-registry/my-image:latest
 }
 
+func ExampleSourceSpec_dockerfileBuildArgs() {
+	spec := kfv1alpha1.SourceSpec{
+		Dockerfile: kfv1alpha1.SourceSpecDockerfile{
+			Source: "gcr.io/my-registry/src-mysource",
+			Path:   "Dockerfile",
+			Image:  "gcr.io/my-registry/my-image:latest",
+			BuildArgs: []corev1.EnvVar{
+				{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{
+					SecretKeyRef: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "creds"},
+						Key:                  "token",
+					},
+				}},
+				{Name: "VERSION", Value: "1.2.3"},
+			},
+		},
+	}
+
+	describe.SourceSpec(os.Stdout, spec)
+
+	// Output: Source:
+	//   Build Type:  dockerfile
+	//   Dockerfile Build:
+	//     Source:           gcr.io/my-registry/src-mysource
+	//     Dockerfile Path:  Dockerfile
+	//     Destination:      gcr.io/my-registry/my-image:latest
+	//     Build Args:
+	//       TOKEN:    <from Secret creds key token>
+	//       VERSION:  1.2.3
+}
+
 func ExampleHealthCheck_nil() {
 	describe.HealthCheck(os.Stdout, nil)
 
@@ -378,6 +426,19 @@ func ExampleHealthCheck_tcp() {
 	//   Type:     port (tcp)
 }
 
+func ExampleHealthCheck_bodyCheck() {
+	describe.HealthCheck(os.Stdout, &corev1.Probe{
+		TimeoutSeconds: 42,
+		Handler: corev1.Handler{
+			Exec: &corev1.ExecAction{Command: []string{"/bin/sh", "-c", "exit 0"}},
+		},
+	})
+
+	// Output: Health Check:
+	//   Timeout:  42s
+	//   Type:     http (body check)
+}
+
 func ExampleAppSpecTemplate_resourceRequests() {
 
 	wantMem := resource.MustParse("2Gi")
@@ -442,3 +503,133 @@ func ExampleServiceInstance() {
 	//     some: params
 	//   Status:  Ready
 }
+
+func ExampleServiceBindings_empty() {
+	describe.ServiceBindings(os.Stdout, nil, nil)
+
+	// Output: Service Bindings: <empty>
+}
+
+func TestServiceBindings(t *testing.T) {
+	binding := func(name, instance string, status v1beta1.ServiceBindingStatus) v1beta1.ServiceBinding {
+		return v1beta1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{kfv1alpha1.ComponentLabel: name},
+			},
+			Spec: v1beta1.ServiceBindingSpec{
+				InstanceRef: v1beta1.LocalObjectReference{Name: instance},
+			},
+			Status: status,
+		}
+	}
+
+	bindings := []v1beta1.ServiceBinding{
+		binding("db", "users-db", v1beta1.ServiceBindingStatus{
+			Conditions: []v1beta1.ServiceBindingCondition{
+				{Type: v1beta1.ServiceBindingConditionReady, Status: v1beta1.ConditionTrue, Reason: "InjectedBindResult"},
+			},
+		}),
+		binding("cache", "redis", v1beta1.ServiceBindingStatus{
+			CurrentOperation: v1beta1.ServiceBindingOperationBind,
+		}),
+		binding("queue", "missing", v1beta1.ServiceBindingStatus{
+			Conditions: []v1beta1.ServiceBindingCondition{
+				{Type: v1beta1.ServiceBindingConditionReady, Status: v1beta1.ConditionFalse, Reason: "ReferencesNonexistentInstance"},
+			},
+		}),
+	}
+
+	instances := []v1beta1.ServiceInstance{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "users-db"},
+			Status: v1beta1.ServiceInstanceStatus{
+				Conditions: []v1beta1.ServiceInstanceCondition{
+					{Type: v1beta1.ServiceInstanceConditionReady, Status: v1beta1.ConditionTrue, Reason: "ProvisionedSuccessfully"},
+				},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "redis"},
+			Status: v1beta1.ServiceInstanceStatus{
+				Conditions: []v1beta1.ServiceInstanceCondition{
+					{Type: v1beta1.ServiceInstanceConditionReady, Status: v1beta1.ConditionFalse, Reason: "ProvisionCallFailed", Message: "broker returned 500"},
+				},
+			},
+		},
+	}
+
+	buffer := &bytes.Buffer{}
+	describe.ServiceBindings(buffer, bindings, instances)
+
+	testutil.AssertContainsAll(t, buffer.String(), []string{
+		"Service Bindings:",
+		"Name   Service   Binding Status                 Service Status           Message",
+		"db     users-db  InjectedBindResult             ProvisionedSuccessfully",
+		"cache  redis     BindInProgress                 ProvisionCallFailed      broker returned 500",
+		"queue  missing   ReferencesNonexistentInstance  NotFound                 service missing doesn't exist",
+	})
+}
+
+func ExampleEvents() {
+	describe.Events(os.Stdout, []corev1.Event{
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "Source", Name: "my-app-1"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "BuildFailed",
+			Message:        "Build failed: step exited with 1\n",
+		},
+		{
+			InvolvedObject: corev1.ObjectReference{Kind: "App", Name: "my-app"},
+			Type:           corev1.EventTypeNormal,
+			Reason:         "Ready",
+			Message:        "Ready condition is now True",
+		},
+	})
+
+	// Output: Events:
+	//   Last Seen  Type     Reason       Object           Message
+	//   <unknown>  Warning  BuildFailed  Source/my-app-1  Build failed: step exited with 1
+	//   <unknown>  Normal   Ready        App/my-app       Ready condition is now True
+}
+
+func ExampleEvents_empty() {
+	describe.Events(os.Stdout, nil)
+
+	// Output: Events: <empty>
+}
+
+func ExampleAppMetrics() {
+	series := func(values ...float64) []metrics.Sample {
+		var out []metrics.Sample
+		for _, v := range values {
+			out = append(out, metrics.Sample{Value: v})
+		}
+		return out
+	}
+
+	history := &metrics.History{
+		Source: metrics.SourcePrometheus,
+		Instances: []metrics.InstanceHistory{
+			{
+				Name:     "myapp-a",
+				CPU:      series(0.1, 0.15, 0.5),
+				Memory:   series(64*1024*1024, 96*1024*1024, 128*1024*1024),
+				Requests: series(1, 2, 3),
+			},
+		},
+	}
+
+	describe.AppMetrics(os.Stdout, history)
+
+	// Output: Metrics (prometheus):
+	//   Instance  Metric    Min     Avg     Max      Latest   History
+	//   myapp-a   CPU       100m    250m    500m     500m     ▁▁█
+	//             Memory    64.0Mi  96.0Mi  128.0Mi  128.0Mi  ▁▄█
+	//             Requests  1.00/s  2.00/s  3.00/s   3.00/s   ▁▄█
+}
+
+func ExampleAppMetrics_noInstances() {
+	describe.AppMetrics(os.Stdout, &metrics.History{Source: metrics.SourceMetricsServer})
+
+	// Output: Metrics (metrics-server): <empty>
+}
@@ -0,0 +1,151 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devservices
+
+import (
+	"fmt"
+	"io"
+
+	servicecatalogclient "github.com/google/kf/pkg/client/servicecatalog/clientset/versioned"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Client enables and disables dev services in spaces.
+type Client interface {
+	// Enable installs the dev services broker in the space using the given
+	// image. Resources that already exist are left as-is.
+	Enable(space, image string, w io.Writer) error
+
+	// Disable removes the dev services broker from the space. Service
+	// instances that were provisioned by it aren't removed.
+	Disable(space string, w io.Writer) error
+
+	// Enabled returns true if the dev services broker is installed in the
+	// space.
+	Enabled(space string) (bool, error)
+}
+
+type client struct {
+	k8s   kubernetes.Interface
+	svcat servicecatalogclient.Interface
+}
+
+// NewClient creates a new dev services client.
+func NewClient(k8s kubernetes.Interface, svcat servicecatalogclient.Interface) Client {
+	return &client{
+		k8s:   k8s,
+		svcat: svcat,
+	}
+}
+
+// Enable implements Client.Enable.
+func (c *client) Enable(space, image string, w io.Writer) error {
+	if image == "" {
+		image = DefaultBrokerImage
+	}
+
+	steps := []struct {
+		kind   string
+		create func() error
+	}{
+		{"ServiceAccount", func() error {
+			_, err := c.k8s.CoreV1().ServiceAccounts(space).Create(MakeServiceAccount(space))
+			return err
+		}},
+		{"RoleBinding", func() error {
+			_, err := c.k8s.RbacV1().RoleBindings(space).Create(MakeRoleBinding(space))
+			return err
+		}},
+		{"Deployment", func() error {
+			_, err := c.k8s.AppsV1().Deployments(space).Create(MakeDeployment(space, image))
+			return err
+		}},
+		{"Service", func() error {
+			_, err := c.k8s.CoreV1().Services(space).Create(MakeService(space))
+			return err
+		}},
+		{"ServiceBroker", func() error {
+			_, err := c.svcat.ServicecatalogV1beta1().ServiceBrokers(space).Create(MakeServiceBroker(space))
+			return err
+		}},
+	}
+
+	for _, step := range steps {
+		switch err := step.create(); {
+		case apierrs.IsAlreadyExists(err):
+			fmt.Fprintf(w, "%s %s already exists\n", step.kind, BrokerName)
+		case err != nil:
+			return fmt.Errorf("couldn't create %s %s: %v", step.kind, BrokerName, err)
+		default:
+			fmt.Fprintf(w, "Created %s %s\n", step.kind, BrokerName)
+		}
+	}
+
+	return nil
+}
+
+// Disable implements Client.Disable.
+func (c *client) Disable(space string, w io.Writer) error {
+	// The broker is removed first so no new instances are provisioned while
+	// the rest is being torn down.
+	steps := []struct {
+		kind   string
+		delete func() error
+	}{
+		{"ServiceBroker", func() error {
+			return c.svcat.ServicecatalogV1beta1().ServiceBrokers(space).Delete(BrokerName, &metav1.DeleteOptions{})
+		}},
+		{"Service", func() error {
+			return c.k8s.CoreV1().Services(space).Delete(BrokerName, &metav1.DeleteOptions{})
+		}},
+		{"Deployment", func() error {
+			return c.k8s.AppsV1().Deployments(space).Delete(BrokerName, &metav1.DeleteOptions{})
+		}},
+		{"RoleBinding", func() error {
+			return c.k8s.RbacV1().RoleBindings(space).Delete(BrokerName, &metav1.DeleteOptions{})
+		}},
+		{"ServiceAccount", func() error {
+			return c.k8s.CoreV1().ServiceAccounts(space).Delete(BrokerName, &metav1.DeleteOptions{})
+		}},
+	}
+
+	for _, step := range steps {
+		switch err := step.delete(); {
+		case apierrs.IsNotFound(err):
+			// Already gone.
+		case err != nil:
+			return fmt.Errorf("couldn't delete %s %s: %v", step.kind, BrokerName, err)
+		default:
+			fmt.Fprintf(w, "Deleted %s %s\n", step.kind, BrokerName)
+		}
+	}
+
+	return nil
+}
+
+// Enabled implements Client.Enabled.
+func (c *client) Enabled(space string) (bool, error) {
+	_, err := c.svcat.ServicecatalogV1beta1().ServiceBrokers(space).Get(BrokerName, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		return false, nil
+	case err != nil:
+		return false, err
+	default:
+		return true, nil
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devservices
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	fakescclient "github.com/google/kf/pkg/client/servicecatalog/clientset/versioned/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func ExampleBrokerURL() {
+	fmt.Println(BrokerURL("my-space"))
+
+	// Output: http://kf-dev-services.my-space.svc.cluster.local
+}
+
+func TestClient_Enable(t *testing.T) {
+	k8s := k8sfake.NewSimpleClientset()
+	svcat := fakescclient.NewSimpleClientset()
+	client := NewClient(k8s, svcat)
+
+	buf := &bytes.Buffer{}
+	testutil.AssertNil(t, "err", client.Enable("my-space", "", buf))
+	testutil.AssertContainsAll(t, buf.String(), []string{
+		"Created ServiceAccount kf-dev-services",
+		"Created RoleBinding kf-dev-services",
+		"Created Deployment kf-dev-services",
+		"Created Service kf-dev-services",
+		"Created ServiceBroker kf-dev-services",
+	})
+
+	deployment, err := k8s.AppsV1().Deployments("my-space").Get(BrokerName, metav1.GetOptions{})
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "image", DefaultBrokerImage, deployment.Spec.Template.Spec.Containers[0].Image)
+
+	broker, err := svcat.ServicecatalogV1beta1().ServiceBrokers("my-space").Get(BrokerName, metav1.GetOptions{})
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "url", "http://kf-dev-services.my-space.svc.cluster.local", broker.Spec.URL)
+
+	enabled, err := client.Enabled("my-space")
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "enabled", true, enabled)
+
+	// Enabling again is a no-op.
+	buf.Reset()
+	testutil.AssertNil(t, "err", client.Enable("my-space", "some-image", buf))
+	testutil.AssertContainsAll(t, buf.String(), []string{"Deployment kf-dev-services already exists"})
+}
+
+func TestClient_Disable(t *testing.T) {
+	k8s := k8sfake.NewSimpleClientset()
+	svcat := fakescclient.NewSimpleClientset()
+	client := NewClient(k8s, svcat)
+
+	testutil.AssertNil(t, "enable err", client.Enable("my-space", "some-image", &bytes.Buffer{}))
+
+	buf := &bytes.Buffer{}
+	testutil.AssertNil(t, "err", client.Disable("my-space", buf))
+	testutil.AssertContainsAll(t, buf.String(), []string{
+		"Deleted ServiceBroker kf-dev-services",
+		"Deleted ServiceAccount kf-dev-services",
+	})
+
+	enabled, err := client.Enabled("my-space")
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "enabled", false, enabled)
+
+	// Disabling again is a no-op.
+	buf.Reset()
+	testutil.AssertNil(t, "err", client.Disable("my-space", buf))
+	testutil.AssertEqual(t, "output", "", buf.String())
+}
@@ -0,0 +1,159 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package devservices
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	servicecatalogv1beta1 "github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// BrokerName is the name of the space scoped broker and the resources
+	// backing it.
+	BrokerName = "kf-dev-services"
+
+	// DefaultBrokerImage is the broker that's installed if no other image is
+	// given. It provisions services by installing Helm charts in the space.
+	DefaultBrokerImage = "quay.io/kubernetes-service-catalog/minibroker:latest"
+
+	// DefaultChartsURL is the Helm repository charts are installed from.
+	DefaultChartsURL = "https://charts.helm.sh/stable"
+
+	brokerPort = 8080
+)
+
+// labels returns the labels put on all dev services resources.
+func labels() map[string]string {
+	return map[string]string{
+		v1alpha1.NameLabel:      BrokerName,
+		v1alpha1.ManagedByLabel: "kf",
+		v1alpha1.ComponentLabel: "dev-services",
+	}
+}
+
+func objectMeta(space string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:      BrokerName,
+		Namespace: space,
+		Labels:    labels(),
+	}
+}
+
+// MakeServiceAccount creates the ServiceAccount the broker runs as.
+func MakeServiceAccount(space string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: objectMeta(space),
+	}
+}
+
+// MakeRoleBinding grants the broker's ServiceAccount permission to manage
+// the resources that back service instances, but only in the space.
+func MakeRoleBinding(space string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: objectMeta(space),
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "admin",
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      BrokerName,
+				Namespace: space,
+			},
+		},
+	}
+}
+
+// MakeDeployment creates the Deployment that runs the broker image.
+func MakeDeployment(space, image string) *appsv1.Deployment {
+	replicas := int32(1)
+
+	return &appsv1.Deployment{
+		ObjectMeta: objectMeta(space),
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels(),
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels(),
+				},
+				Spec: corev1.PodSpec{
+					ServiceAccountName: BrokerName,
+					Containers: []corev1.Container{
+						{
+							Name:  "broker",
+							Image: image,
+							Args: []string{
+								"--port", fmt.Sprint(brokerPort),
+								"--helmUrl", DefaultChartsURL,
+								"--defaultNamespace", space,
+							},
+							Ports: []corev1.ContainerPort{
+								{Name: "http", ContainerPort: brokerPort},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// MakeService creates the Service the service catalog reaches the broker
+// through.
+func MakeService(space string) *corev1.Service {
+	return &corev1.Service{
+		ObjectMeta: objectMeta(space),
+		Spec: corev1.ServiceSpec{
+			Selector: labels(),
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "http",
+					Port:       80,
+					TargetPort: intstr.FromInt(brokerPort),
+				},
+			},
+		},
+	}
+}
+
+// BrokerURL returns the in-cluster URL of the broker in the space.
+func BrokerURL(space string) string {
+	return fmt.Sprintf("http://%s.%s.svc.cluster.local", BrokerName, space)
+}
+
+// MakeServiceBroker creates the space scoped ServiceBroker that registers
+// the broker's services in the marketplace.
+func MakeServiceBroker(space string) *servicecatalogv1beta1.ServiceBroker {
+	return &servicecatalogv1beta1.ServiceBroker{
+		ObjectMeta: objectMeta(space),
+		Spec: servicecatalogv1beta1.ServiceBrokerSpec{
+			CommonServiceBrokerSpec: servicecatalogv1beta1.CommonServiceBrokerSpec{
+				URL: BrokerURL(space),
+			},
+		},
+	}
+}
@@ -16,14 +16,14 @@ package doctor
 
 import (
 	"github.com/google/kf/pkg/kf/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/discovery"
-	"k8s.io/client-go/kubernetes"
 )
 
 // ClusterDiagnostic tests that the cluster's Kubernetes version and components
 // are suitable for kf.
 type ClusterDiagnostic struct {
-	kubeClient kubernetes.Interface
+	discoveryClient discovery.DiscoveryInterface
 }
 
 var _ Diagnosable = (*ClusterDiagnostic)(nil)
@@ -32,19 +32,20 @@ var _ Diagnosable = (*ClusterDiagnostic)(nil)
 // cluster.
 func (c *ClusterDiagnostic) Diagnose(d *Diagnostic) {
 	d.Run("Version", func(d *Diagnostic) {
-		diagnoseKubernetesVersion(d, c.kubeClient.Discovery())
+		diagnoseKubernetesVersion(d, c.discoveryClient)
 	})
 
 	d.Run("Components", func(d *Diagnostic) {
-		diagnoseComponents(d, c.kubeClient.Discovery())
+		diagnoseComponents(d, c.discoveryClient)
 	})
 }
 
 // NewClusterDiagnostic creates a new ClusterDiagnostic to validate the
-// install pointed at by the client.
-func NewClusterDiagnostic(kubeClient kubernetes.Interface) *ClusterDiagnostic {
+// install pointed at by the client. Cached discovery clients are refreshed
+// before reporting a component as missing.
+func NewClusterDiagnostic(discoveryClient discovery.DiscoveryInterface) *ClusterDiagnostic {
 	return &ClusterDiagnostic{
-		kubeClient: kubeClient,
+		discoveryClient: discoveryClient,
 	}
 }
 
@@ -81,7 +82,7 @@ func diagnoseComponents(d *Diagnostic, vc discovery.ServerResourcesInterface) {
 
 	for tn, tc := range expectedComponents {
 		d.Run(tn, func(d *Diagnostic) {
-			resourceList, err := vc.ServerResourcesForGroupVersion(tc.groupVersion)
+			resourceList, err := serverResources(vc, tc.groupVersion, tc.expectedResources)
 			if err != nil {
 				d.Fatalf("Error getting resources for %s: %v", tc.groupVersion, err)
 			}
@@ -102,3 +103,39 @@ func diagnoseComponents(d *Diagnostic, vc discovery.ServerResourcesInterface) {
 		})
 	}
 }
+
+// serverResources gets the resources for groupVersion. If the client is
+// cached and the cached data is missing any of the expected resources, the
+// cache is invalidated and the resources are fetched again in case the
+// component was installed after the cache was built.
+func serverResources(vc discovery.ServerResourcesInterface, groupVersion string, expected []string) (*metav1.APIResourceList, error) {
+	resourceList, err := vc.ServerResourcesForGroupVersion(groupVersion)
+
+	cachedClient, ok := vc.(discovery.CachedDiscoveryInterface)
+	if !ok || cachedClient.Fresh() {
+		return resourceList, err
+	}
+
+	if err == nil && hasResources(resourceList, expected) {
+		return resourceList, nil
+	}
+
+	cachedClient.Invalidate()
+	return vc.ServerResourcesForGroupVersion(groupVersion)
+}
+
+// hasResources checks that every expected resource is in the list.
+func hasResources(resourceList *metav1.APIResourceList, expected []string) bool {
+	found := make(map[string]bool)
+	for _, r := range resourceList.APIResources {
+		found[r.Name] = true
+	}
+
+	for _, resource := range expected {
+		if !found[resource] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,133 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"knative.dev/pkg/configmap"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap in the kf namespace that
+	// holds the egress IP pools.
+	ConfigMapName = "config-egress-ip-pools"
+
+	// ConfigMapNamespace is the namespace the egress ConfigMap lives in.
+	ConfigMapNamespace = "kf"
+
+	poolsKey = "pools"
+)
+
+// Config holds the egress IP pools configured on the cluster. The cluster's
+// egress solution assigns the addresses, Kf records them so developers can
+// give them to the services that allowlist their Apps.
+type Config struct {
+	// Pools maps the name of each pool to the source IPs its traffic uses.
+	Pools map[string][]string
+}
+
+// IPs returns the source IPs of the pool, nil if the pool isn't configured.
+func (c *Config) IPs(pool string) []string {
+	if c == nil || pool == "" {
+		return nil
+	}
+
+	return c.Pools[pool]
+}
+
+// ToMap converts the Config into the data of the egress ConfigMap.
+func (c *Config) ToMap() (map[string]string, error) {
+	out, err := yaml.Marshal(c.Pools)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{poolsKey: string(out)}, nil
+}
+
+// NewConfigFromMap creates a Config from the data of the egress ConfigMap.
+func NewConfigFromMap(data map[string]string) (*Config, error) {
+	cfg := &Config{}
+
+	if raw, ok := data[poolsKey]; ok {
+		if err := yaml.Unmarshal([]byte(raw), &cfg.Pools); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %v", poolsKey, err)
+		}
+	}
+
+	for pool, ips := range cfg.Pools {
+		if errs := validation.IsDNS1123Subdomain(pool); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid pool name %q: %s", pool, strings.Join(errs, ", "))
+		}
+
+		if len(ips) == 0 {
+			return nil, fmt.Errorf("pool %s must have at least one IP", pool)
+		}
+
+		for _, ip := range ips {
+			if net.ParseIP(ip) == nil {
+				return nil, fmt.Errorf("pool %s has an invalid IP %q", pool, ip)
+			}
+		}
+
+		sort.Strings(ips)
+	}
+
+	return cfg, nil
+}
+
+// NewConfigFromConfigMap creates a Config from the egress ConfigMap.
+func NewConfigFromConfigMap(cm *corev1.ConfigMap) (*Config, error) {
+	return NewConfigFromMap(cm.Data)
+}
+
+// Store holds the latest Config read from the cluster so reconcilers can
+// resolve pools without fetching the ConfigMap. The zero value holds an
+// empty Config.
+type Store struct {
+	config atomic.Value
+}
+
+// Load returns the latest Config.
+func (s *Store) Load() *Config {
+	if cfg, ok := s.config.Load().(*Config); ok {
+		return cfg
+	}
+
+	return &Config{}
+}
+
+// WatchConfigs updates the Store whenever the egress ConfigMap changes.
+// Invalid changes are logged and ignored so the last good Config is kept.
+func (s *Store) WatchConfigs(cmw configmap.Watcher, logger *zap.SugaredLogger) {
+	cmw.Watch(ConfigMapName, func(cm *corev1.ConfigMap) {
+		cfg, err := NewConfigFromConfigMap(cm)
+		if err != nil {
+			logger.Errorw("Invalid egress IP pool configuration", zap.Error(err))
+			return
+		}
+
+		s.config.Store(cfg)
+	})
+}
@@ -0,0 +1,137 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package egress
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	"go.uber.org/zap/zaptest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/configmap"
+)
+
+func TestNewConfigFromMap(t *testing.T) {
+	cases := map[string]struct {
+		data        map[string]string
+		expected    *Config
+		expectedErr error
+	}{
+		"empty": {
+			data:     map[string]string{},
+			expected: &Config{},
+		},
+		"pools": {
+			data: map[string]string{
+				"pools": `
+partner-allowlist:
+- 203.0.113.11
+- 203.0.113.10
+payments:
+- 2001:db8::1
+`,
+			},
+			expected: &Config{
+				Pools: map[string][]string{
+					"partner-allowlist": {"203.0.113.10", "203.0.113.11"},
+					"payments":          {"2001:db8::1"},
+				},
+			},
+		},
+		"invalid pool name": {
+			data: map[string]string{
+				"pools": `{"Partner Allowlist": ["203.0.113.10"]}`,
+			},
+			expectedErr: errors.New(`invalid pool name "Partner Allowlist": a DNS-1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`),
+		},
+		"no IPs": {
+			data: map[string]string{
+				"pools": `{"payments": []}`,
+			},
+			expectedErr: errors.New("pool payments must have at least one IP"),
+		},
+		"invalid IP": {
+			data: map[string]string{
+				"pools": `{"payments": ["203.0.113"]}`,
+			},
+			expectedErr: errors.New(`pool payments has an invalid IP "203.0.113"`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			actual, err := NewConfigFromMap(tc.data)
+			if tc.expectedErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.expectedErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "config", tc.expected, actual)
+		})
+	}
+}
+
+func TestConfig_ToMap(t *testing.T) {
+	expected := &Config{
+		Pools: map[string][]string{
+			"a": {"203.0.113.10"},
+			"b": {"203.0.113.20", "203.0.113.21"},
+		},
+	}
+
+	data, err := expected.ToMap()
+	testutil.AssertNil(t, "ToMap error", err)
+
+	actual, err := NewConfigFromMap(data)
+	testutil.AssertNil(t, "NewConfigFromMap error", err)
+	testutil.AssertEqual(t, "config", expected, actual)
+}
+
+func TestConfig_IPs(t *testing.T) {
+	cfg := &Config{
+		Pools: map[string][]string{"a": {"203.0.113.10"}},
+	}
+
+	testutil.AssertEqual(t, "IPs", []string{"203.0.113.10"}, cfg.IPs("a"))
+	testutil.AssertEqual(t, "missing IPs", []string(nil), cfg.IPs("missing"))
+	testutil.AssertEqual(t, "nil IPs", []string(nil), (*Config)(nil).IPs("a"))
+}
+
+func TestStore(t *testing.T) {
+	store := &Store{}
+	testutil.AssertEqual(t, "initial config", &Config{}, store.Load())
+
+	cmw := &configmap.ManualWatcher{Namespace: ConfigMapNamespace}
+	store.WatchConfigs(cmw, zaptest.NewLogger(t).Sugar())
+
+	cmw.OnChange(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: ConfigMapNamespace},
+		Data: map[string]string{
+			"pools": `{"a": ["203.0.113.10"]}`,
+		},
+	})
+	testutil.AssertEqual(t, "IPs after update", []string{"203.0.113.10"}, store.Load().IPs("a"))
+
+	// Invalid configurations are ignored.
+	cmw.OnChange(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: ConfigMapNamespace},
+		Data: map[string]string{
+			"pools": `{"a": ["not-an-ip"]}`,
+		},
+	})
+	testutil.AssertEqual(t, "IPs after invalid update", []string{"203.0.113.10"}, store.Load().IPs("a"))
+}
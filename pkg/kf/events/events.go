@@ -0,0 +1,75 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// List gets the Events recorded for the object with the given kind and name.
+// Events for cluster scoped objects are recorded in the default namespace.
+func List(client v1.EventsGetter, namespace, kind, name string) ([]corev1.Event, error) {
+	selector := fields.Set{
+		"involvedObject.kind": kind,
+		"involvedObject.name": name,
+	}
+
+	list, err := client.Events(namespace).List(metav1.ListOptions{
+		FieldSelector: selector.AsSelector().String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter again in case the server didn't apply the selector.
+	var out []corev1.Event
+	for _, e := range list.Items {
+		if e.InvolvedObject.Kind == kind && e.InvolvedObject.Name == name {
+			out = append(out, e)
+		}
+	}
+
+	return out, nil
+}
+
+// Create records a Normal Event for the referenced object from the CLI.
+func Create(client v1.EventsGetter, ref corev1.ObjectReference, reason, message string) error {
+	now := metav1.Now()
+
+	_, err := client.Events(ref.Namespace).Create(&corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", ref.Name, time.Now().UnixNano()),
+			Namespace: ref.Namespace,
+		},
+		InvolvedObject: ref,
+		Reason:         reason,
+		Message:        message,
+		Type:           corev1.EventTypeNormal,
+		Source: corev1.EventSource{
+			Component: "kf-cli",
+		},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	})
+
+	return err
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events_test
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/kf/events"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestList(t *testing.T) {
+	t.Parallel()
+
+	event := func(name, kind, objectName string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "some-namespace"},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: objectName},
+		}
+	}
+
+	client := fake.NewSimpleClientset(
+		event("app-event", "App", "my-app"),
+		event("other-app-event", "App", "other-app"),
+		event("source-event", "Source", "my-app"),
+	)
+
+	got, err := events.List(client.CoreV1(), "some-namespace", "App", "my-app")
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "events count", 1, len(got))
+	testutil.AssertEqual(t, "event name", "app-event", got[0].Name)
+}
+
+func TestCreate(t *testing.T) {
+	t.Parallel()
+
+	client := fake.NewSimpleClientset()
+	ref := corev1.ObjectReference{
+		Kind:      "App",
+		Name:      "my-app",
+		Namespace: "some-namespace",
+	}
+
+	err := events.Create(client.CoreV1(), ref, "SomeReason", "some message")
+	testutil.AssertNil(t, "err", err)
+
+	got, err := events.List(client.CoreV1(), "some-namespace", "App", "my-app")
+	testutil.AssertNil(t, "list err", err)
+	testutil.AssertEqual(t, "events count", 1, len(got))
+	testutil.AssertEqual(t, "reason", "SomeReason", got[0].Reason)
+	testutil.AssertEqual(t, "message", "some message", got[0].Message)
+	testutil.AssertEqual(t, "type", corev1.EventTypeNormal, got[0].Type)
+}
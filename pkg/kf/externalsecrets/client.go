@@ -0,0 +1,157 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalsecrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// ExternalSecretResource is the resource the kubernetes-external-secrets
+// controller syncs into Secrets.
+var ExternalSecretResource = schema.GroupVersionResource{
+	Group:    "kubernetes-client.io",
+	Version:  "v1",
+	Resource: "externalsecrets",
+}
+
+// GSMSecretVersion identifies a version of a secret in Google Secret Manager.
+type GSMSecretVersion struct {
+	Project string
+	Secret  string
+	Version string
+}
+
+// String gets the resource name of the version.
+func (v GSMSecretVersion) String() string {
+	return fmt.Sprintf("projects/%s/secrets/%s/versions/%s", v.Project, v.Secret, v.Version)
+}
+
+// ParseGSMSecretVersion parses a Secret Manager resource name in the form
+// projects/PROJECT/secrets/SECRET[/versions/VERSION]. The version defaults to
+// latest.
+func ParseGSMSecretVersion(name string) (*GSMSecretVersion, error) {
+	parts := strings.Split(name, "/")
+	if len(parts) == 4 {
+		parts = append(parts, "versions", "latest")
+	}
+
+	if len(parts) != 6 || parts[0] != "projects" || parts[2] != "secrets" || parts[4] != "versions" {
+		return nil, fmt.Errorf("invalid Secret Manager secret %q, must be in the form projects/PROJECT/secrets/SECRET[/versions/VERSION]", name)
+	}
+
+	for _, part := range []string{parts[1], parts[3], parts[5]} {
+		if part == "" {
+			return nil, fmt.Errorf("invalid Secret Manager secret %q, PROJECT, SECRET and VERSION can't be empty", name)
+		}
+	}
+
+	return &GSMSecretVersion{
+		Project: parts[1],
+		Secret:  parts[3],
+		Version: parts[5],
+	}, nil
+}
+
+// Client syncs external secrets into Kubernetes Secrets.
+type Client interface {
+	// SyncGSM creates or updates an ExternalSecret that copies the Secret
+	// Manager version into the key of the named Secret. It waits until the
+	// context is done for the Secret to be synced.
+	SyncGSM(ctx context.Context, namespace, secretName, key string, version GSMSecretVersion) error
+}
+
+type client struct {
+	dynamicClient dynamic.Interface
+	secrets       v1.SecretsGetter
+	interval      time.Duration
+}
+
+// NewClient creates a new Client.
+func NewClient(dynamicClient dynamic.Interface, secrets v1.SecretsGetter) Client {
+	return &client{
+		dynamicClient: dynamicClient,
+		secrets:       secrets,
+		interval:      time.Second,
+	}
+}
+
+// SyncGSM implements Client.
+func (c *client) SyncGSM(ctx context.Context, namespace, secretName, key string, version GSMSecretVersion) error {
+	desired := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"backendType": "gcpSecretsManager",
+			"projectId":   version.Project,
+			"data": []interface{}{
+				map[string]interface{}{
+					"key":     version.Secret,
+					"version": version.Version,
+					"name":    key,
+				},
+			},
+		},
+	}}
+	desired.SetAPIVersion(ExternalSecretResource.GroupVersion().String())
+	desired.SetKind("ExternalSecret")
+	desired.SetName(secretName)
+	desired.SetNamespace(namespace)
+	desired.SetLabels(map[string]string{
+		v1alpha1.ManagedByLabel: "kf",
+	})
+
+	externalSecrets := c.dynamicClient.Resource(ExternalSecretResource).Namespace(namespace)
+	existing, err := externalSecrets.Get(secretName, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		_, err = externalSecrets.Create(desired, metav1.CreateOptions{})
+	case err == nil && existing.GetLabels()[v1alpha1.ManagedByLabel] != "kf":
+		return fmt.Errorf("ExternalSecret %s in space %s isn't managed by Kf", secretName, namespace)
+	case err == nil:
+		desired.SetResourceVersion(existing.GetResourceVersion())
+		_, err = externalSecrets.Update(desired, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to sync ExternalSecret, is kubernetes-external-secrets installed? %s", err)
+	}
+
+	err = wait.PollImmediateUntil(c.interval, func() (bool, error) {
+		secret, err := c.secrets.Secrets(namespace).Get(secretName, metav1.GetOptions{})
+		switch {
+		case apierrs.IsNotFound(err):
+			return false, nil
+		case err != nil:
+			return false, err
+		default:
+			_, ok := secret.Data[key]
+			return ok, nil
+		}
+	}, ctx.Done())
+	if err != nil {
+		return fmt.Errorf("secret %s wasn't synced from %s: %s", secretName, version, err)
+	}
+
+	return nil
+}
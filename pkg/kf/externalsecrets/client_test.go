@@ -0,0 +1,160 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package externalsecrets_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/externalsecrets"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func ExampleParseGSMSecretVersion() {
+	version, err := externalsecrets.ParseGSMSecretVersion("projects/my-project/secrets/db-password")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Project:", version.Project)
+	fmt.Println("Secret:", version.Secret)
+	fmt.Println("Version:", version.Version)
+	fmt.Println("String:", version)
+
+	// Output: Project: my-project
+	// Secret: db-password
+	// Version: latest
+	// String: projects/my-project/secrets/db-password/versions/latest
+}
+
+func TestParseGSMSecretVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		name    string
+		want    *externalsecrets.GSMSecretVersion
+		wantErr error
+	}{
+		"full name": {
+			name: "projects/p/secrets/s/versions/3",
+			want: &externalsecrets.GSMSecretVersion{Project: "p", Secret: "s", Version: "3"},
+		},
+		"default version": {
+			name: "projects/p/secrets/s",
+			want: &externalsecrets.GSMSecretVersion{Project: "p", Secret: "s", Version: "latest"},
+		},
+		"wrong format": {
+			name:    "p/s",
+			wantErr: errors.New(`invalid Secret Manager secret "p/s", must be in the form projects/PROJECT/secrets/SECRET[/versions/VERSION]`),
+		},
+		"empty part": {
+			name:    "projects//secrets/s",
+			wantErr: errors.New(`invalid Secret Manager secret "projects//secrets/s", PROJECT, SECRET and VERSION can't be empty`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got, err := externalsecrets.ParseGSMSecretVersion(tc.name)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "version", tc.want, got)
+		})
+	}
+}
+
+func TestClient_SyncGSM(t *testing.T) {
+	t.Parallel()
+
+	version := externalsecrets.GSMSecretVersion{Project: "p", Secret: "db-password", Version: "latest"}
+
+	syncedSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-db-password", Namespace: "my-space"},
+		Data:       map[string][]byte{"DB_PASSWORD": []byte("hunter2")},
+	}
+
+	unmanaged := &unstructured.Unstructured{}
+	unmanaged.SetAPIVersion("kubernetes-client.io/v1")
+	unmanaged.SetKind("ExternalSecret")
+	unmanaged.SetName("my-app-db-password")
+	unmanaged.SetNamespace("my-space")
+
+	managed := unmanaged.DeepCopy()
+	managed.SetLabels(map[string]string{v1alpha1.ManagedByLabel: "kf"})
+
+	cases := map[string]struct {
+		externalSecrets []runtime.Object
+		secrets         []runtime.Object
+		wantErr         error
+	}{
+		"secret never synced": {
+			wantErr: errors.New("secret my-app-db-password wasn't synced from projects/p/secrets/db-password/versions/latest: timed out waiting for the condition"),
+		},
+		"creates external secret": {
+			secrets: []runtime.Object{syncedSecret},
+		},
+		"updates external secret": {
+			externalSecrets: []runtime.Object{managed},
+			secrets:         []runtime.Object{syncedSecret},
+		},
+		"external secret not managed by Kf": {
+			externalSecrets: []runtime.Object{unmanaged},
+			wantErr:         errors.New("ExternalSecret my-app-db-password in space my-space isn't managed by Kf"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), tc.externalSecrets...)
+			k8s := k8sfake.NewSimpleClientset(tc.secrets...)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			client := externalsecrets.NewClient(dynamicClient, k8s.CoreV1())
+			err := client.SyncGSM(ctx, "my-space", "my-app-db-password", "DB_PASSWORD", version)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			actual, err := dynamicClient.
+				Resource(externalsecrets.ExternalSecretResource).
+				Namespace("my-space").
+				Get("my-app-db-password", metav1.GetOptions{})
+			testutil.AssertNil(t, "get err", err)
+			testutil.AssertEqual(t, "spec", map[string]interface{}{
+				"backendType": "gcpSecretsManager",
+				"projectId":   "p",
+				"data": []interface{}{
+					map[string]interface{}{
+						"key":     "db-password",
+						"version": "latest",
+						"name":    "DB_PASSWORD",
+					},
+				},
+			}, actual.Object["spec"])
+		})
+	}
+}
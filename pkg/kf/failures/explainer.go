@@ -0,0 +1,250 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package failures
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	cv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/describe"
+	build "github.com/google/kf/third_party/knative-build/pkg/client/clientset/versioned/typed/build/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// LogLines is the number of lines shown from the end of a failed build
+	// step's logs.
+	LogLines = 50
+
+	// EventLimit is the number of recent warnings shown for a failed deploy.
+	EventLimit = 10
+)
+
+// Explainer describes why an App failed so users don't have to dig through
+// its Builds, Pods and events.
+type Explainer interface {
+	// Explain writes why the App's latest push failed to out. Build failures
+	// show the failing build step's last LogLines lines of logs, deploy
+	// failures show the exit reason of the App's crashing containers and its
+	// recent warning events.
+	Explain(out io.Writer, namespace, appName string) error
+}
+
+// logReader reads the last lines of a container's logs.
+type logReader func(namespace, podName, containerName string, lines int64) ([]byte, error)
+
+type explainer struct {
+	k8s      kubernetes.Interface
+	kf       cv1alpha1.KfV1alpha1Interface
+	build    build.BuildV1alpha1Interface
+	readLogs logReader
+}
+
+// NewExplainer creates a new failure Explainer.
+func NewExplainer(
+	k8s kubernetes.Interface,
+	kf cv1alpha1.KfV1alpha1Interface,
+	build build.BuildV1alpha1Interface,
+) Explainer {
+	return &explainer{
+		k8s:   k8s,
+		kf:    kf,
+		build: build,
+		readLogs: func(namespace, podName, containerName string, lines int64) ([]byte, error) {
+			return k8s.CoreV1().
+				Pods(namespace).
+				GetLogs(podName, &corev1.PodLogOptions{Container: containerName, TailLines: &lines}).
+				DoRaw()
+		},
+	}
+}
+
+// Explain implements Explainer.
+func (e *explainer) Explain(out io.Writer, namespace, appName string) error {
+	app, err := e.kf.Apps(namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if sourceReady := app.Status.GetCondition(v1alpha1.AppConditionSourceReady); sourceReady != nil && sourceReady.IsFalse() {
+		return e.explainBuild(out, app)
+	}
+
+	return e.explainDeploy(out, app)
+}
+
+func (e *explainer) explainBuild(out io.Writer, app *v1alpha1.App) error {
+	sourceName := app.Status.LatestCreatedSourceName
+	if sourceName == "" {
+		fmt.Fprintln(out, "The build failed before it started.")
+		return nil
+	}
+
+	source, err := e.kf.Sources(app.Namespace).Get(sourceName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if source.Status.BuildName == "" {
+		fmt.Fprintf(out, "Source %s failed before its build started.\n", sourceName)
+		return nil
+	}
+
+	bld, err := e.build.Builds(app.Namespace).Get(source.Status.BuildName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if bld.Status.Cluster == nil || bld.Status.Cluster.PodName == "" {
+		fmt.Fprintf(out, "Build %s failed before its pod was scheduled.\n", bld.Name)
+		return nil
+	}
+
+	pod, err := e.k8s.CoreV1().Pods(app.Namespace).Get(bld.Status.Cluster.PodName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	step := FailedStep(pod)
+	if step == nil {
+		fmt.Fprintf(out, "Build %s failed but none of its steps did.\n", bld.Name)
+		return nil
+	}
+
+	terminated := step.State.Terminated
+	fmt.Fprintf(out, "Build step %s failed with exit code %d (%s). Last %d lines of its logs:\n", step.Name, terminated.ExitCode, terminated.Reason, LogLines)
+
+	logs, err := e.readLogs(app.Namespace, pod.Name, step.Name, LogLines)
+	if err != nil {
+		return fmt.Errorf("couldn't read the logs of build step %s: %s", step.Name, err)
+	}
+
+	describe.IndentWriter(out, func(w io.Writer) {
+		w.Write(logs)
+	})
+
+	return nil
+}
+
+func (e *explainer) explainDeploy(out io.Writer, app *v1alpha1.App) error {
+	// Only instances of the latest revision matter, older ones are being
+	// replaced by it.
+	selector := "serving.knative.dev/service=" + app.Name
+	if revision := app.Status.LatestCreatedRevisionName; revision != "" {
+		selector = "serving.knative.dev/revision=" + revision
+	}
+
+	pods, err := e.k8s.CoreV1().Pods(app.Namespace).List(metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Names of the objects whose warnings explain the failure.
+	related := map[string]bool{
+		app.Name:                             true,
+		app.Status.LatestCreatedRevisionName: true,
+	}
+
+	crashed := false
+	for _, pod := range pods.Items {
+		related[pod.Name] = true
+
+		for _, status := range pod.Status.ContainerStatuses {
+			if reason := CrashReason(status); reason != "" {
+				crashed = true
+				fmt.Fprintf(out, "Instance %s container %s %s\n", pod.Name, status.Name, reason)
+			}
+		}
+	}
+
+	if !crashed {
+		fmt.Fprintf(out, "No instances of %s crashed.\n", app.Name)
+	}
+
+	list, err := e.k8s.CoreV1().Events(app.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var warnings []corev1.Event
+	for _, event := range list.Items {
+		if event.Type == corev1.EventTypeWarning && related[event.InvolvedObject.Name] {
+			warnings = append(warnings, event)
+		}
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].LastTimestamp.Before(&warnings[j].LastTimestamp)
+	})
+	if len(warnings) > EventLimit {
+		warnings = warnings[len(warnings)-EventLimit:]
+	}
+
+	describe.Events(out, warnings)
+	return nil
+}
+
+// FailedStep returns the status of the first build step in the pod that
+// exited with an error or nil if none did. Build steps run as init
+// containers.
+func FailedStep(pod *corev1.Pod) *corev1.ContainerStatus {
+	for i, status := range pod.Status.InitContainerStatuses {
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			return &pod.Status.InitContainerStatuses[i]
+		}
+	}
+
+	return nil
+}
+
+// CrashReason describes why the container isn't running or an empty string
+// if it's running and has never exited.
+func CrashReason(status corev1.ContainerStatus) string {
+	var parts []string
+
+	if waiting := status.State.Waiting; waiting != nil && waiting.Reason != "" {
+		parts = append(parts, "is waiting: "+waiting.Reason)
+	}
+
+	terminated := status.State.Terminated
+	if terminated == nil {
+		terminated = status.LastTerminationState.Terminated
+	}
+
+	if terminated != nil {
+		exit := fmt.Sprintf("exited with code %d", terminated.ExitCode)
+		if terminated.Reason != "" {
+			exit += fmt.Sprintf(" (%s)", terminated.Reason)
+		}
+		if message := strings.TrimSpace(terminated.Message); message != "" {
+			exit += ": " + message
+		}
+		parts = append(parts, exit)
+	}
+
+	if len(parts) > 0 && status.RestartCount > 0 {
+		parts = append(parts, fmt.Sprintf("restarted %d times", status.RestartCount))
+	}
+
+	return strings.Join(parts, ", ")
+}
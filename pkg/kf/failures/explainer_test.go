@@ -0,0 +1,198 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package failures
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kffake "github.com/google/kf/pkg/client/clientset/versioned/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
+	buildfake "github.com/google/kf/third_party/knative-build/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"knative.dev/pkg/apis"
+)
+
+func TestExplainer_Explain_build(t *testing.T) {
+	t.Parallel()
+
+	app := &v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "my-space"},
+	}
+	app.Status.LatestCreatedSourceName = "my-app-source"
+	app.Status.Conditions = append(app.Status.Conditions, apis.Condition{
+		Type:   v1alpha1.AppConditionSourceReady,
+		Status: corev1.ConditionFalse,
+	})
+
+	source := &v1alpha1.Source{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-source", Namespace: "my-space"},
+	}
+	source.Status.BuildName = "my-app-build"
+
+	bld := &build.Build{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-build", Namespace: "my-space"},
+	}
+	bld.Status.Cluster = &build.ClusterSpec{PodName: "my-app-build-pod"}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-build-pod", Namespace: "my-space"},
+		Status: corev1.PodStatus{
+			InitContainerStatuses: []corev1.ContainerStatus{
+				{Name: "build-step-detect", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+				{Name: "build-step-build", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error"}}},
+				{Name: "build-step-export", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}}},
+			},
+		},
+	}
+
+	// The generated Build fake uses a different group than the scheme, so
+	// Builds can only be found if they're created through the client.
+	builds := buildfake.NewSimpleClientset().BuildV1alpha1()
+	_, err := builds.Builds("my-space").Create(bld)
+	testutil.AssertNil(t, "create build error", err)
+
+	e := NewExplainer(
+		k8sfake.NewSimpleClientset(pod),
+		kffake.NewSimpleClientset(app, source).KfV1alpha1(),
+		builds,
+	).(*explainer)
+	e.readLogs = func(namespace, podName, containerName string, lines int64) ([]byte, error) {
+		testutil.AssertEqual(t, "pod", "my-app-build-pod", podName)
+		testutil.AssertEqual(t, "container", "build-step-build", containerName)
+		testutil.AssertEqual(t, "lines", int64(LogLines), lines)
+		return []byte("npm ERR! missing script: build\n"), nil
+	}
+
+	out := &bytes.Buffer{}
+	testutil.AssertNil(t, "err", e.Explain(out, "my-space", "my-app"))
+	testutil.AssertEqual(t, "output", "Build step build-step-build failed with exit code 1 (Error). Last 50 lines of its logs:\n  npm ERR! missing script: build\n", out.String())
+}
+
+func TestExplainer_Explain_deploy(t *testing.T) {
+	t.Parallel()
+
+	app := &v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "my-space"},
+	}
+	app.Status.LatestCreatedRevisionName = "my-app-00002"
+
+	crashing := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-app-00002-abcde",
+			Namespace: "my-space",
+			Labels:    map[string]string{"serving.knative.dev/revision": "my-app-00002"},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "user-container",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+						ExitCode: 137,
+						Reason:   "OOMKilled",
+					}},
+					RestartCount: 4,
+				},
+				{Name: "queue-proxy", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+
+	oldRevision := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-app-00001-fghij",
+			Namespace: "my-space",
+			Labels:    map[string]string{"serving.knative.dev/revision": "my-app-00001"},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "user-container", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			},
+		},
+	}
+
+	warning := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "warning", Namespace: "my-space"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "my-app-00002-abcde"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+	}
+	normal := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "normal", Namespace: "my-space"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "my-app-00002-abcde"},
+		Type:           corev1.EventTypeNormal,
+		Reason:         "Pulled",
+	}
+	unrelated := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "unrelated", Namespace: "my-space"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: "other-app-00001-abcde"},
+		Type:           corev1.EventTypeWarning,
+		Reason:         "Unhealthy",
+	}
+
+	e := NewExplainer(
+		k8sfake.NewSimpleClientset(crashing, oldRevision, warning, normal, unrelated),
+		kffake.NewSimpleClientset(app).KfV1alpha1(),
+		buildfake.NewSimpleClientset().BuildV1alpha1(),
+	)
+
+	out := &bytes.Buffer{}
+	testutil.AssertNil(t, "err", e.Explain(out, "my-space", "my-app"))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	testutil.AssertEqual(t, "crash", "Instance my-app-00002-abcde container user-container is waiting: CrashLoopBackOff, exited with code 137 (OOMKilled), restarted 4 times", lines[0])
+	testutil.AssertEqual(t, "events header", "Events:", lines[1])
+	testutil.AssertEqual(t, "line count", 4, len(lines))
+	testutil.AssertContainsAll(t, lines[3], []string{"Warning", "BackOff", "Pod/my-app-00002-abcde", "Back-off restarting failed container"})
+}
+
+func TestCrashReason(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		status corev1.ContainerStatus
+		want   string
+	}{
+		"running": {
+			status: corev1.ContainerStatus{State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			want:   "",
+		},
+		"terminated with message": {
+			status: corev1.ContainerStatus{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+				ExitCode: 1,
+				Reason:   "Error",
+				Message:  "panic: missing DATABASE_URL\n",
+			}}},
+			want: "exited with code 1 (Error): panic: missing DATABASE_URL",
+		},
+		"image pull": {
+			status: corev1.ContainerStatus{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+			want:   "is waiting: ImagePullBackOff",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "reason", tc.want, CrashReason(tc.status))
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fake
+
+import hooks "github.com/google/kf/pkg/kf/hooks"
+
+//go:generate mockgen --package=fake --copyright_file ../../internal/tools/option-builder/LICENSE_HEADER --destination=fake_task_runner.go --mock_names=TaskRunner=FakeTaskRunner github.com/google/kf/pkg/kf/hooks/fake TaskRunner
+
+// TaskRunner is implemented by hooks.TaskRunner.
+type TaskRunner interface {
+	hooks.TaskRunner
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+)
+
+const (
+	// PhasePrePush hooks run before the App's source is uploaded.
+	PhasePrePush = "pre-push"
+
+	// PhasePostDeploy hooks run after the App is deployed.
+	PhasePostDeploy = "post-deploy"
+
+	// PhaseSmokeTest hooks check a new version of an App deployed blue-green
+	// before it receives traffic.
+	PhaseSmokeTest = "smoke-test"
+)
+
+// RunLocal runs each command with sh in dir and stops at the first one that
+// fails. The commands inherit the CLI's environment with env added to it so
+// they see the same variables as the App. Output from the commands is
+// written to out.
+func RunLocal(out io.Writer, phase, dir string, env map[string]string, commands []string) error {
+	environ := os.Environ()
+
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		environ = append(environ, fmt.Sprintf("%s=%s", k, env[k]))
+	}
+
+	for _, command := range commands {
+		fmt.Fprintf(out, "Running %s hook: %s\n", phase, command)
+
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Dir = dir
+		cmd.Env = environ
+		cmd.Stdout = out
+		cmd.Stderr = out
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s hook %q failed: %s", phase, command, err)
+		}
+	}
+
+	return nil
+}
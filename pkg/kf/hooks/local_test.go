@@ -0,0 +1,57 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/hooks"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestRunLocal(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		env        map[string]string
+		commands   []string
+		wantOutput string
+		wantErr    error
+	}{
+		"no commands": {},
+		"env": {
+			env:        map[string]string{"KF_APP": "myapp", "DATABASE": "postgres"},
+			commands:   []string{"echo $KF_APP $DATABASE"},
+			wantOutput: "Running pre-push hook: echo $KF_APP $DATABASE\nmyapp postgres\n",
+		},
+		"stops at failure": {
+			commands:   []string{"echo first", "exit 3", "echo never"},
+			wantOutput: "Running pre-push hook: echo first\nfirst\nRunning pre-push hook: exit 3\n",
+			wantErr:    errors.New(`pre-push hook "exit 3" failed: exit status 3`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			err := hooks.RunLocal(out, hooks.PhasePrePush, ".", tc.env, tc.commands)
+
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "output", tc.wantOutput, out.String())
+		})
+	}
+}
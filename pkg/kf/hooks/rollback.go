@@ -0,0 +1,48 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"errors"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/apps"
+)
+
+// Rollback creates a Mutator that restores the App to previous, its state
+// before the push. The image previous was running is deployed directly so
+// nothing is rebuilt.
+func Rollback(previous *v1alpha1.App) (apps.Mutator, error) {
+	if previous == nil || previous.Status.Image == "" {
+		return nil, errors.New("there's no previous version of the App to roll back to")
+	}
+
+	return func(app *v1alpha1.App) error {
+		sourceUpdateRequests := app.Spec.Source.UpdateRequests
+		templateUpdateRequests := app.Spec.Template.UpdateRequests
+
+		app.Spec = *previous.Spec.DeepCopy()
+
+		// UpdateRequests can't decrease and must change with the source.
+		app.Spec.Source = v1alpha1.SourceSpec{
+			UpdateRequests: sourceUpdateRequests + 1,
+			ServiceAccount: previous.Spec.Source.ServiceAccount,
+			ContainerImage: v1alpha1.SourceSpecContainerImage{Image: previous.Status.Image},
+		}
+		app.Spec.Template.UpdateRequests = templateUpdateRequests
+
+		return nil
+	}, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/hooks"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestRollback(t *testing.T) {
+	t.Parallel()
+
+	previous := &v1alpha1.App{}
+	previous.Spec.Source.UpdateRequests = 2
+	previous.Spec.Source.BuildpackBuild.Source = "gcr.io/project/src:old"
+	previous.Spec.Template.UpdateRequests = 1
+	previous.Spec.Template.Spec.ServiceAccountName = "old-sa"
+	previous.Status.Image = "gcr.io/project/app:old"
+
+	current := &v1alpha1.App{}
+	current.Spec.Source.UpdateRequests = 3
+	current.Spec.Source.BuildpackBuild.Source = "gcr.io/project/src:new"
+	current.Spec.Template.UpdateRequests = 4
+	current.Spec.Template.Spec.ServiceAccountName = "new-sa"
+
+	mutator, err := hooks.Rollback(previous)
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertNil(t, "mutator err", mutator(current))
+
+	testutil.AssertEqual(t, "source", v1alpha1.SourceSpec{
+		UpdateRequests: 4,
+		ContainerImage: v1alpha1.SourceSpecContainerImage{Image: "gcr.io/project/app:old"},
+	}, current.Spec.Source)
+	testutil.AssertEqual(t, "template update requests", 4, current.Spec.Template.UpdateRequests)
+	testutil.AssertEqual(t, "service account", "old-sa", current.Spec.Template.Spec.ServiceAccountName)
+}
+
+func TestRollback_noPreviousVersion(t *testing.T) {
+	t.Parallel()
+
+	wantErr := errors.New("there's no previous version of the App to roll back to")
+
+	_, err := hooks.Rollback(nil)
+	testutil.AssertErrorsEqual(t, wantErr, err)
+
+	_, err = hooks.Rollback(&v1alpha1.App{})
+	testutil.AssertErrorsEqual(t, wantErr, err)
+}
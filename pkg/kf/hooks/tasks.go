@@ -0,0 +1,146 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/reconciler/app/resources"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	// TaskComponent is the component label of Pods that run tasks.
+	TaskComponent = "task"
+
+	// taskContainerName is the name of the container that runs the task.
+	taskContainerName = "task"
+)
+
+// TaskRunner runs one-off commands in the cluster using an App's built image.
+type TaskRunner interface {
+	// Run runs the command to completion and writes its logs to out. It
+	// waits until the context is done for the command to finish.
+	Run(ctx context.Context, out io.Writer, app *v1alpha1.App, command string) error
+}
+
+type taskRunner struct {
+	pods     v1.PodsGetter
+	interval time.Duration
+}
+
+// NewTaskRunner creates a new TaskRunner.
+func NewTaskRunner(pods v1.PodsGetter) TaskRunner {
+	return &taskRunner{
+		pods:     pods,
+		interval: time.Second,
+	}
+}
+
+// MakeTaskPod creates a Pod that runs command with the App's built image,
+// environment, and service bindings. The command is passed to the image's
+// entrypoint the same way the App's command is.
+func MakeTaskPod(app *v1alpha1.App, command string) (*corev1.Pod, error) {
+	if app.Status.Image == "" {
+		return nil, fmt.Errorf("App %s doesn't have a built image to run tasks with", app.Name)
+	}
+
+	container := corev1.Container{}
+	if containers := app.Spec.Template.Spec.Containers; len(containers) > 0 {
+		container = *containers[0].DeepCopy()
+	}
+
+	container.Name = taskContainerName
+	container.Image = app.Status.Image
+	container.Args = []string{command}
+	container.Ports = nil
+	container.LivenessProbe = nil
+	container.ReadinessProbe = nil
+	container.EnvFrom = append(container.EnvFrom, corev1.EnvFromSource{
+		SecretRef: &corev1.SecretEnvSource{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: resources.KfInjectedEnvSecretName(app),
+			},
+		},
+	})
+
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: fmt.Sprintf("%s-task-", app.Name),
+			Namespace:    app.Namespace,
+			Labels:       app.ComponentLabels(TaskComponent),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(app),
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:      corev1.RestartPolicyNever,
+			ServiceAccountName: app.Spec.Template.Spec.ServiceAccountName,
+			Containers:         []corev1.Container{container},
+		},
+	}, nil
+}
+
+// Run implements TaskRunner.
+func (r *taskRunner) Run(ctx context.Context, out io.Writer, app *v1alpha1.App, command string) error {
+	desired, err := MakeTaskPod(app, command)
+	if err != nil {
+		return err
+	}
+
+	pods := r.pods.Pods(app.Namespace)
+	pod, err := pods.Create(desired)
+	if err != nil {
+		return fmt.Errorf("failed to create task: %s", err)
+	}
+
+	fmt.Fprintf(out, "Running task %s: %s\n", pod.Name, command)
+
+	var phase corev1.PodPhase
+	err = wait.PollImmediateUntil(r.interval, func() (bool, error) {
+		current, err := pods.Get(pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		phase = current.Status.Phase
+		return phase == corev1.PodSucceeded || phase == corev1.PodFailed, nil
+	}, ctx.Done())
+	if err != nil {
+		return fmt.Errorf("task %s didn't finish: %s", pod.Name, err)
+	}
+
+	if logs, err := pods.GetLogs(pod.Name, &corev1.PodLogOptions{Container: taskContainerName}).Stream(); err != nil {
+		fmt.Fprintf(out, "Couldn't get the logs of task %s: %s\n", pod.Name, err)
+	} else {
+		io.Copy(out, logs)
+		logs.Close()
+	}
+
+	if phase == corev1.PodFailed {
+		// The Pod is kept so the failure can be investigated.
+		return fmt.Errorf("task %s failed: %s", pod.Name, command)
+	}
+
+	return pods.Delete(pod.Name, &metav1.DeleteOptions{})
+}
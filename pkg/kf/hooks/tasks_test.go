@@ -0,0 +1,70 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hooks_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/hooks"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMakeTaskPod(t *testing.T) {
+	t.Parallel()
+
+	app := &v1alpha1.App{}
+	app.Name = "myapp"
+	app.Namespace = "myspace"
+	app.Status.Image = "gcr.io/project/myapp:abc"
+	app.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name:           "user-container",
+		Env:            []corev1.EnvVar{{Name: "RAILS_ENV", Value: "production"}},
+		Ports:          []corev1.ContainerPort{{ContainerPort: 8080}},
+		ReadinessProbe: &corev1.Probe{},
+		Args:           []string{"bundle exec rails server"},
+	}}
+
+	pod, err := hooks.MakeTaskPod(app, "bundle exec rake db:migrate")
+	testutil.AssertNil(t, "err", err)
+
+	testutil.AssertEqual(t, "generateName", "myapp-task-", pod.GenerateName)
+	testutil.AssertEqual(t, "namespace", "myspace", pod.Namespace)
+	testutil.AssertEqual(t, "component", hooks.TaskComponent, pod.Labels[v1alpha1.ComponentLabel])
+	testutil.AssertEqual(t, "owner", "myapp", pod.OwnerReferences[0].Name)
+	testutil.AssertEqual(t, "restartPolicy", corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+
+	container := pod.Spec.Containers[0]
+	testutil.AssertEqual(t, "image", "gcr.io/project/myapp:abc", container.Image)
+	testutil.AssertEqual(t, "args", []string{"bundle exec rake db:migrate"}, container.Args)
+	testutil.AssertEqual(t, "env", app.Spec.Template.Spec.Containers[0].Env, container.Env)
+	testutil.AssertEqual(t, "ports", []corev1.ContainerPort(nil), container.Ports)
+	testutil.AssertEqual(t, "injected env", "kf-injected-envs-myapp", container.EnvFrom[0].SecretRef.Name)
+	if container.ReadinessProbe != nil {
+		t.Fatal("expected the readiness probe to be removed")
+	}
+}
+
+func TestMakeTaskPod_notBuilt(t *testing.T) {
+	t.Parallel()
+
+	app := &v1alpha1.App{}
+	app.Name = "myapp"
+
+	_, err := hooks.MakeTaskPod(app, "migrate")
+	testutil.AssertErrorsEqual(t, errors.New("App myapp doesn't have a built image to run tasks with"), err)
+}
@@ -20,17 +20,19 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"strings"
 	"testing"
 	"time"
 
+	"github.com/google/kf/pkg/internal/diffutil"
 	"github.com/google/kf/pkg/kf/testutil"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
 	testclient "k8s.io/client-go/kubernetes/fake"
 	cv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	ktesting "k8s.io/client-go/testing"
 )
 
 func ExampleList_Filter() {
@@ -165,6 +167,40 @@ func TestClient_Delete(t *testing.T) {
 	}
 }
 
+func TestListOptions_ToListOptions(t *testing.T) {
+	cfg := ListOptions{
+		WithListFieldSelector(map[string]string{"metadata.name": "foo"}),
+		WithListLimit(50),
+		WithListContinueToken("next-page"),
+	}.toConfig()
+
+	actual := cfg.ToListOptions()
+
+	testutil.AssertEqual(t, "FieldSelector", "metadata.name=foo", actual.FieldSelector)
+	testutil.AssertEqual(t, "Limit", int64(50), actual.Limit)
+	testutil.AssertEqual(t, "Continue", "next-page", actual.Continue)
+}
+
+func TestClient_ListPage(t *testing.T) {
+	mockK8s := testclient.NewSimpleClientset().CoreV1()
+	client := NewExampleClient(mockK8s)
+
+	for _, name := range []string{"foo", "bar"} {
+		pod := &v1.Pod{}
+		pod.Name = name
+		_, err := client.Create("default", pod)
+		testutil.AssertNil(t, "creating pod", err)
+	}
+
+	out, token, err := client.ListPage("default", WithListFilter(func(p *v1.Pod) bool {
+		return p.Name == "foo"
+	}))
+	testutil.AssertNil(t, "ListPage err", err)
+	testutil.AssertEqual(t, "token", "", token)
+	testutil.AssertEqual(t, "count", 1, len(out))
+	testutil.AssertEqual(t, "name", "foo", out[0].Name)
+}
+
 func TestClient_Upsert(t *testing.T) {
 	fakePod := func(name string, hostname string) *v1.Pod {
 		s := &v1.Pod{}
@@ -268,11 +304,32 @@ func ExampleDiffWrapper_changes() {
 	})
 
 	fmt.Println("Error:", wrapper(obj))
-	firstLine := strings.Split(contents.String(), "\n")[0]
-	fmt.Println("First line:", firstLine)
+	fmt.Print(contents.String())
 
 	// Output: Error: <nil>
-	// First line: OperatorConfig Diff (-old +new):
+	// OperatorConfig Diff (-old +new):
+	// ~ .spec.hostname: "opaque" -> "docker-creds"
+}
+
+func ExampleDiffWrapper_json() {
+	obj := &v1.Pod{}
+
+	wrapper := DiffWrapper(os.Stdout, func(obj *v1.Pod) error {
+		obj.Spec.Hostname = "docker-creds"
+		return nil
+	}, diffutil.WithFormat(diffutil.FormatJSON))
+
+	wrapper(obj)
+
+	// Output: {
+	//   "changes": [
+	//     {
+	//       "type": "added",
+	//       "path": ".spec.hostname",
+	//       "new": "docker-creds"
+	//     }
+	//   ]
+	// }
 }
 
 func ExampleDiffWrapper_err() {
@@ -345,11 +402,95 @@ func ExampleClient_WaitForE_timeout() {
 	})
 	fmt.Println("Instance:", instance)
 	fmt.Println("Error:", err)
-	fmt.Println("Called?:", called) // 3 calls, immediately, 100ms later then 100ms after
+	fmt.Println("Called?:", called) // once for the initial get, no events follow
 
 	// Output: Instance: nil
 	// Error: waiting for OperatorConfig timed out
-	// Called?: 3
+	// Called?: 1
+}
+
+func TestClient_WaitForE_watch(t *testing.T) {
+	fakePod := func(name string, hostname string) *v1.Pod {
+		s := &v1.Pod{}
+		s.Name = name
+		s.Namespace = "default"
+		s.Spec.Hostname = hostname
+		return s
+	}
+
+	hasHostname := func(hostname string) ConditionFuncE {
+		return wrapPredicate(func(pod *v1.Pod) bool {
+			return pod.Spec.Hostname == hostname
+		})
+	}
+
+	cases := map[string]struct {
+		condition ConditionFuncE
+		events    func(t *testing.T, pods cv1.PodsGetter, watchers []*watch.FakeWatcher)
+
+		wantHostname string
+		wantNil      bool
+		wantErr      error
+	}{
+		"modified event completes": {
+			condition: hasHostname("new"),
+			events: func(t *testing.T, pods cv1.PodsGetter, watchers []*watch.FakeWatcher) {
+				watchers[0].Modify(fakePod("other-pod", "new"))
+				watchers[0].Modify(fakePod("my-pod", "still-old"))
+				watchers[0].Modify(fakePod("my-pod", "new"))
+			},
+			wantHostname: "new",
+		},
+		"deleted event gets not found": {
+			condition: ConditionDeleted,
+			events: func(t *testing.T, pods cv1.PodsGetter, watchers []*watch.FakeWatcher) {
+				if err := pods.Pods("default").Delete("my-pod", &metav1.DeleteOptions{}); err != nil {
+					t.Error(err)
+				}
+				watchers[0].Delete(fakePod("my-pod", "old"))
+			},
+			wantNil: true,
+		},
+		"reconnects after close": {
+			condition: hasHostname("new"),
+			events: func(t *testing.T, pods cv1.PodsGetter, watchers []*watch.FakeWatcher) {
+				if _, err := pods.Pods("default").Update(fakePod("my-pod", "new")); err != nil {
+					t.Error(err)
+				}
+				watchers[0].Stop()
+			},
+			wantHostname: "new",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			clientset := testclient.NewSimpleClientset(fakePod("my-pod", "old"))
+
+			watchers := []*watch.FakeWatcher{watch.NewFake(), watch.NewFake()}
+			watchCalls := 0
+			clientset.PrependWatchReactor("pods", func(action ktesting.Action) (bool, watch.Interface, error) {
+				w := watchers[watchCalls]
+				watchCalls++
+				return true, w, nil
+			})
+
+			client := NewExampleClient(clientset.CoreV1())
+
+			go tc.events(t, clientset.CoreV1(), watchers)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			instance, err := client.WaitForE(ctx, "default", "my-pod", 10*time.Millisecond, tc.condition)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			if tc.wantNil {
+				testutil.AssertEqual(t, "instance is nil", true, instance == nil)
+				return
+			}
+			testutil.AssertEqual(t, "hostname", tc.wantHostname, instance.Spec.Hostname)
+		})
+	}
 }
 
 func TestWrapPredicate(t *testing.T) {
@@ -45,6 +45,7 @@ type Client interface {
 	Get({{ $nssig }} name string, opts ...GetOption) (*{{.Type}}, error)
 	Delete({{ $nssig }} name string, opts ...DeleteOption) error
 	List({{ $nssig }} opts ...ListOption) ([]{{.Type}}, error)
+	ListPage({{ $nssig }} opts ...ListOption) ([]{{.Type}}, string, error)
 	Upsert({{ $nssig }} newObj *{{.Type}}, merge Merger) (*{{.Type}}, error)
 	WaitFor(ctx context.Context, {{ $nssig }} name string, interval time.Duration, condition Predicate) (*{{.Type}}, error)
 	WaitForE(ctx context.Context, {{ $nssig }} name string, interval time.Duration, condition ConditionFuncE) (*{{.Type}}, error)
@@ -160,11 +161,32 @@ func (core *coreClient) List({{ $nssig }} opts ...ListOption) ([]{{.Type}}, erro
 	return List(res.Items).Filter(cfg.filter), nil
 }
 
+// ListPage gets a single page of objects from the cluster sized by the limit
+// option and returns the token to pass with the continue token option to get
+// the next page. The token is empty once there are no more results.
+func (core *coreClient) ListPage({{ $nssig }} opts ...ListOption) ([]{{.Type}}, string, error) {
+	cfg := ListOptionDefaults().Extend(opts).toConfig()
+
+	res, err := core.kclient.{{ .Kubernetes.Plural }}({{ $ns }}).List(cfg.ToListOptions())
+	if err != nil {
+		return nil, "", fmt.Errorf("couldn't list {{.CF.Name}}s: %v", err)
+	}
+
+	if cfg.filter == nil {
+		return res.Items, res.Continue, nil
+	}
+
+	return List(res.Items).Filter(cfg.filter), res.Continue, nil
+}
+
 func (cfg listConfig) ToListOptions() (resp metav1.ListOptions) {
 	if cfg.fieldSelector != nil {
 		resp.FieldSelector = metav1.FormatLabelSelector(metav1.SetAsLabelSelector(cfg.fieldSelector))
 	}
 
+	resp.Limit = cfg.limit
+	resp.Continue = cfg.continueToken
+
 	return
 }
 
@@ -204,30 +226,104 @@ func (core *coreClient) WaitFor(ctx context.Context, {{ $nssig }} name string, i
 // function to decide how to handle the apiErr.
 type ConditionFuncE func(instance *{{.Type}}, apiErr error) (done bool, err error)
 
-// WaitForE polls for the given object every interval until the condition
-// function becomes done or the timeout expires. The first poll occurs
-// immediately after the function is invoked.
+// WaitForE watches the given object until the condition function becomes
+// done or the context is cancelled. The object is fetched immediately so the
+// condition is checked against its current state before any events arrive.
 //
-// The function polls infinitely if no timeout is supplied.
+// If the watch is closed by the server it's re-established after a jittered
+// delay of up to twice the interval, the object is fetched again first so
+// changes made while disconnected aren't missed.
+//
+// The function waits infinitely if the context has no deadline.
 func (core *coreClient) WaitForE(ctx context.Context, {{ $nssig }} name string, interval time.Duration, condition ConditionFuncE) (instance *{{.Type}}, err error) {
 	var done bool
-	tick := time.Tick(interval)
 
 	for {
-		instance, err = core.kclient.{{ .Kubernetes.Plural }}({{ $ns }}).Get(name, metav1.GetOptions{})
-		if done, err = condition(instance, err); done {
+		var apiErr error
+		instance, apiErr = core.kclient.{{ .Kubernetes.Plural }}({{ $ns }}).Get(name, metav1.GetOptions{})
+		if done, err = condition(instance, apiErr); done {
 			return
 		}
 
+		resourceVersion := ""
+		if apiErr == nil {
+			resourceVersion = instance.ResourceVersion
+		}
+
+		var watched *{{.Type}}
+		if done, watched, err = core.watchUntil(ctx, {{ $nsparam }} name, resourceVersion, condition); done {
+			return watched, err
+		}
+
+		if ctx.Err() != nil {
+			return nil, errors.New("waiting for {{.CF.Name}} timed out")
+		}
+
 		select {
-		case <-tick:
-			// repeat instance check
+		case <-time.After(wait.Jitter(interval, 1.0)):
+			// reconnect
 		case <-ctx.Done():
 			return nil, errors.New("waiting for {{.CF.Name}} timed out")
 		}
 	}
 }
 
+// watchUntil feeds events for the named object to the condition until it's
+// done. It returns false if the watch couldn't be started, was closed or the
+// context was cancelled so the caller can decide whether to reconnect.
+func (core *coreClient) watchUntil(ctx context.Context, {{ $nssig }} name, resourceVersion string, condition ConditionFuncE) (bool, *{{.Type}}, error) {
+	watcher, err := core.kclient.{{ .Kubernetes.Plural }}({{ $ns }}).Watch(metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+		ResourceVersion: resourceVersion,
+	})
+	if err != nil {
+		// Treat a failed watch like a closed one so it's retried.
+		return false, nil, nil
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil, nil
+
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false, nil, nil
+			}
+
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				obj, ok := event.Object.(*{{.Type}})
+				if !ok || obj.Name != name {
+					continue
+				}
+
+				if done, err := condition(obj, nil); done {
+					return true, obj, err
+				}
+
+			case watch.Deleted:
+				obj, ok := event.Object.(*{{.Type}})
+				if !ok || obj.Name != name {
+					continue
+				}
+
+				// Fetch the object so the condition gets the not found error
+				// from the API, or the new object if it was already re-created.
+				instance, apiErr := core.kclient.{{ .Kubernetes.Plural }}({{ $ns }}).Get(name, metav1.GetOptions{})
+				if done, err := condition(instance, apiErr); done {
+					return true, instance, err
+				}
+
+			case watch.Error:
+				// The resource version likely expired, reconnecting resyncs.
+				return false, nil, nil
+			}
+		}
+	}
+}
+
 // ConditionDeleted is a ConditionFuncE that succeeds if the error returned by
 // the cluster was a not found error.
 func ConditionDeleted(_ *{{.Type}}, apiErr error) (bool, error) {
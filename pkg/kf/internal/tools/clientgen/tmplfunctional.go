@@ -49,7 +49,7 @@ type Mutator func(*{{.Type}}) error
 
 // DiffWrapper wraps a mutator and prints out the diff between the original object
 // and the one it returns if there's no error.
-func DiffWrapper(w io.Writer, mutator Mutator) Mutator {
+func DiffWrapper(w io.Writer, mutator Mutator, opts ...diffutil.Option) Mutator {
 	return func(mutable *{{.Type}}) error {
 		before := mutable.DeepCopy()
 
@@ -57,29 +57,18 @@ func DiffWrapper(w io.Writer, mutator Mutator) Mutator {
 			return err
 		}
 
-		FormatDiff(w, "old", "new", before, mutable)
+		FormatDiff(w, "old", "new", before, mutable, opts...)
 
 		return nil
 	}
 }
 
-// FormatDiff creates a diff between two {{.Type}}s and writes it to the given
-// writer.
-func FormatDiff(w io.Writer, leftName, rightName string, left, right *{{.Type}}) {
-	diff, err := kmp.SafeDiff(left, right)
-	switch {
-	case err != nil:
+// FormatDiff creates a semantic diff between two {{.Type}}s and writes it to the
+// given writer.
+func FormatDiff(w io.Writer, leftName, rightName string, left, right *{{.Type}}, opts ...diffutil.Option) {
+	title := fmt.Sprintf("{{.CF.Name}} Diff (-%s +%s):", leftName, rightName)
+	if err := diffutil.Fprint(w, title, left, right, opts...); err != nil {
 		fmt.Fprintf(w, "couldn't format diff: %s\n", err.Error())
-
-	case diff == "":
-		fmt.Fprintln(w, "No changes")
-
-	default:
-		fmt.Fprintf(w, "{{.CF.Name}} Diff (-%s +%s):\n", leftName, rightName)
-		// go-cmp randomly chooses to prefix lines with non-breaking spaces or
-		// regular spaces to prevent people from using it as a real diff/patch
-		// tool. We normalize them so our outputs will be consistent.
-		fmt.Fprintln(w, strings.ReplaceAll(diff, " ", " "))
 	}
 }
 
@@ -33,14 +33,16 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"strings"
 	"time"
 
-	"knative.dev/pkg/kmp"
+	"github.com/google/kf/pkg/internal/diffutil"
 	{{ if .SupportsConditions }}"knative.dev/pkg/apis"
 	corev1 "k8s.io/api/core/v1"{{ end }}
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 
@@ -0,0 +1,68 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AllSpacesFlags is a flag set for listing resources in every space rather
+// than just the targeted one.
+type AllSpacesFlags struct {
+	allSpaces bool
+}
+
+// Add adds the all-spaces flag to the Cobra command.
+func (flags *AllSpacesFlags) Add(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(
+		&flags.allSpaces,
+		"all-spaces",
+		false,
+		"List resources in every space rather than just the targeted one.",
+	)
+}
+
+// IsAllSpaces returns true if the user wants to list resources in every space.
+func (flags *AllSpacesFlags) IsAllSpaces() bool {
+	return flags.allSpaces
+}
+
+// Namespace returns the namespace resources should be listed from. If the
+// user wants every space, metav1.NamespaceAll is returned so the resources
+// are fetched with a single cluster-wide list. Otherwise the targeted space
+// is validated and returned.
+func (flags *AllSpacesFlags) Namespace(p *config.KfParams) (string, error) {
+	if flags.allSpaces {
+		return metav1.NamespaceAll, nil
+	}
+
+	if err := ValidateNamespace(p); err != nil {
+		return "", err
+	}
+
+	return p.Namespace, nil
+}
+
+// Description describes the spaces resources are being listed from so
+// commands can tell the user.
+func (flags *AllSpacesFlags) Description(p *config.KfParams) string {
+	if flags.allSpaces {
+		return "all spaces"
+	}
+
+	return "space " + p.Namespace
+}
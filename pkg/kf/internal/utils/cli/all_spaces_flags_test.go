@@ -0,0 +1,84 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/commands/config"
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/spf13/cobra"
+)
+
+func ExampleAllSpacesFlags() {
+	var allSpaces AllSpacesFlags
+
+	cmd := &cobra.Command{
+		Run: func(cmd *cobra.Command, args []string) {
+			p := &config.KfParams{Namespace: "my-space"}
+			fmt.Println("Listing from", allSpaces.Description(p))
+		},
+	}
+	allSpaces.Add(cmd)
+
+	cmd.SetArgs([]string{})
+	cmd.ExecuteC()
+
+	cmd.SetArgs([]string{"--all-spaces"})
+	cmd.ExecuteC()
+
+	// Output: Listing from space my-space
+	// Listing from all spaces
+}
+
+func TestAllSpacesFlags_Namespace(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		allSpaces     bool
+		namespace     string
+		wantNamespace string
+		wantErr       error
+	}{
+		"targeted space": {
+			namespace:     "my-space",
+			wantNamespace: "my-space",
+		},
+		"no targeted space": {
+			wantErr: errors.New(EmptyNamespaceError),
+		},
+		"all spaces": {
+			allSpaces:     true,
+			wantNamespace: "",
+		},
+		"all spaces ignores target": {
+			allSpaces:     true,
+			namespace:     "my-space",
+			wantNamespace: "",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			flags := AllSpacesFlags{allSpaces: tc.allSpaces}
+
+			namespace, err := flags.Namespace(&config.KfParams{Namespace: tc.namespace})
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "namespace", tc.wantNamespace, namespace)
+		})
+	}
+}
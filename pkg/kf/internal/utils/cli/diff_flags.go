@@ -0,0 +1,54 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/kf/pkg/internal/diffutil"
+	"github.com/spf13/cobra"
+)
+
+// DiffFlags is a flag set for choosing how commands print the changes they
+// make to resources.
+type DiffFlags struct {
+	format string
+}
+
+// Add adds the diff-format flag to the Cobra command.
+func (flags *DiffFlags) Add(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&flags.format,
+		"diff-format",
+		string(diffutil.FormatText),
+		fmt.Sprintf("Format to print changes in, one of: %s", strings.Join(diffutil.Formats(), ", ")),
+	)
+}
+
+// Options validates the flag and returns the options to print diffs with.
+func (flags *DiffFlags) Options() ([]diffutil.Option, error) {
+	format := flags.format
+	if format == "" {
+		format = string(diffutil.FormatText)
+	}
+
+	parsed, err := diffutil.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	return []diffutil.Option{diffutil.WithFormat(parsed)}, nil
+}
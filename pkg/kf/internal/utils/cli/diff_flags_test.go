@@ -0,0 +1,69 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/spf13/cobra"
+)
+
+func ExampleDiffFlags() {
+	var diffFlags DiffFlags
+
+	cmd := &cobra.Command{
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := diffFlags.Options()
+			fmt.Println("Options:", len(opts), "Error:", err)
+			return nil
+		},
+	}
+	diffFlags.Add(cmd)
+
+	cmd.SetArgs([]string{})
+	cmd.ExecuteC()
+
+	cmd.SetArgs([]string{"--diff-format", "json"})
+	cmd.ExecuteC()
+
+	// Output: Options: 1 Error: <nil>
+	// Options: 1 Error: <nil>
+}
+
+func TestDiffFlags_Options(t *testing.T) {
+	cases := map[string]struct {
+		format  string
+		wantErr error
+	}{
+		"unset": {},
+		"text":  {format: "text"},
+		"json":  {format: "json"},
+		"yaml": {
+			format:  "yaml",
+			wantErr: errors.New(`unknown diff format "yaml", must be one of: text, json`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			flags := DiffFlags{format: tc.format}
+			_, err := flags.Options()
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// SortByName orders results alphabetically by name.
+	SortByName = "name"
+	// SortByAge orders results from oldest to newest.
+	SortByAge = "age"
+	// SortByStatus orders results by their status, then by name.
+	SortByStatus = "status"
+)
+
+// ListSortKey holds the values list commands can be sorted by.
+type ListSortKey struct {
+	Name    string
+	Created time.Time
+	Status  string
+}
+
+// ListFlags is a flag set for paging through, filtering and sorting the
+// results of list commands so large spaces don't need to be downloaded in a
+// single request.
+type ListFlags struct {
+	limit         int64
+	continueToken string
+	filter        string
+	sortBy        string
+}
+
+// Add adds the list flags to the Cobra command.
+func (flags *ListFlags) Add(cmd *cobra.Command) {
+	cmd.Flags().Int64Var(
+		&flags.limit,
+		"limit",
+		0,
+		"Maximum number of results to request from the server, zero lists everything.",
+	)
+
+	cmd.Flags().StringVar(
+		&flags.continueToken,
+		"continue",
+		"",
+		"Token printed by a previous page to continue listing from.",
+	)
+
+	cmd.Flags().StringVar(
+		&flags.filter,
+		"filter",
+		"",
+		"Only list results whose name starts with this prefix.",
+	)
+
+	cmd.Flags().StringVar(
+		&flags.sortBy,
+		"sort-by",
+		SortByName,
+		fmt.Sprintf("Order of the results, one of: %s, %s, %s.", SortByName, SortByAge, SortByStatus),
+	)
+}
+
+// Validate checks the flag values, it should be called before the flags are
+// used.
+func (flags *ListFlags) Validate() error {
+	if flags.limit < 0 {
+		return fmt.Errorf("--limit must be zero or greater, got %d", flags.limit)
+	}
+
+	switch flags.sortBy {
+	case SortByName, SortByAge, SortByStatus:
+		return nil
+	default:
+		return fmt.Errorf(
+			"unsupported --sort-by %q, must be one of: %s, %s, %s",
+			flags.sortBy,
+			SortByName,
+			SortByAge,
+			SortByStatus,
+		)
+	}
+}
+
+// Limit returns the maximum number of results to request from the server or
+// zero if all results should be fetched.
+func (flags *ListFlags) Limit() int64 {
+	return flags.limit
+}
+
+// ContinueToken returns the token of the page to fetch, empty for the first.
+func (flags *ListFlags) ContinueToken() string {
+	return flags.continueToken
+}
+
+// MatchesName returns true if the name passes the user's filter. Kubernetes
+// can't select on name prefixes so filtering happens after the server returns
+// a page, meaning pages may hold fewer than --limit results.
+func (flags *ListFlags) MatchesName(name string) bool {
+	return strings.HasPrefix(name, flags.filter)
+}
+
+// Sort orders the slice in place using the user's --sort-by choice. key
+// returns the sortable values of the element currently at the given index.
+//
+// Because the server returns pages in name order, sorting only applies to the
+// results of the current page.
+func (flags *ListFlags) Sort(slice interface{}, key func(i int) ListSortKey) {
+	sort.SliceStable(slice, func(i, j int) bool {
+		a, b := key(i), key(j)
+		switch flags.sortBy {
+		case SortByAge:
+			if !a.Created.Equal(b.Created) {
+				return a.Created.Before(b.Created)
+			}
+		case SortByStatus:
+			if a.Status != b.Status {
+				return a.Status < b.Status
+			}
+		}
+
+		return a.Name < b.Name
+	})
+}
+
+// PrintNextPage tells the user how to fetch the next page if the server
+// returned a continue token.
+func (flags *ListFlags) PrintNextPage(w io.Writer, token string) {
+	if token == "" {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "More results are available, get the next page with: --continue %s\n", token)
+}
@@ -0,0 +1,144 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/spf13/cobra"
+)
+
+func ExampleListFlags() {
+	var listFlags ListFlags
+
+	cmd := &cobra.Command{
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := listFlags.Validate(); err != nil {
+				return err
+			}
+
+			fmt.Println("Limit:", listFlags.Limit())
+			fmt.Println("Continue:", listFlags.ContinueToken())
+			fmt.Println("Matches app-a:", listFlags.MatchesName("app-a"))
+			fmt.Println("Matches db:", listFlags.MatchesName("db"))
+			return nil
+		},
+	}
+	listFlags.Add(cmd)
+
+	cmd.SetArgs([]string{"--limit", "50", "--continue", "abc123", "--filter", "app-"})
+	cmd.ExecuteC()
+
+	// Output: Limit: 50
+	// Continue: abc123
+	// Matches app-a: true
+	// Matches db: false
+}
+
+func TestListFlags_Validate(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		flags   ListFlags
+		wantErr error
+	}{
+		"defaults": {
+			flags: ListFlags{sortBy: SortByName},
+		},
+		"sort by age": {
+			flags: ListFlags{sortBy: SortByAge},
+		},
+		"sort by status": {
+			flags: ListFlags{limit: 10, sortBy: SortByStatus},
+		},
+		"negative limit": {
+			flags:   ListFlags{limit: -1, sortBy: SortByName},
+			wantErr: errors.New("--limit must be zero or greater, got -1"),
+		},
+		"unknown sort": {
+			flags:   ListFlags{sortBy: "size"},
+			wantErr: errors.New(`unsupported --sort-by "size", must be one of: name, age, status`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertErrorsEqual(t, tc.wantErr, tc.flags.Validate())
+		})
+	}
+}
+
+func TestListFlags_Sort(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	items := []ListSortKey{
+		{Name: "c", Created: now.Add(-1 * time.Hour), Status: "ready"},
+		{Name: "a", Created: now, Status: "stopped"},
+		{Name: "b", Created: now.Add(-2 * time.Hour), Status: "ready"},
+	}
+
+	cases := map[string]struct {
+		sortBy    string
+		wantNames []string
+	}{
+		"name": {
+			sortBy:    SortByName,
+			wantNames: []string{"a", "b", "c"},
+		},
+		"age": {
+			sortBy:    SortByAge,
+			wantNames: []string{"b", "c", "a"},
+		},
+		"status": {
+			sortBy:    SortByStatus,
+			wantNames: []string{"b", "c", "a"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			list := append([]ListSortKey{}, items...)
+			flags := ListFlags{sortBy: tc.sortBy}
+			flags.Sort(list, func(i int) ListSortKey {
+				return list[i]
+			})
+
+			var names []string
+			for _, item := range list {
+				names = append(names, item.Name)
+			}
+			testutil.AssertEqual(t, "names", tc.wantNames, names)
+		})
+	}
+}
+
+func TestListFlags_PrintNextPage(t *testing.T) {
+	t.Parallel()
+
+	var flags ListFlags
+
+	buf := &bytes.Buffer{}
+	flags.PrintNextPage(buf, "")
+	testutil.AssertEqual(t, "no token", "", buf.String())
+
+	flags.PrintNextPage(buf, "abc123")
+	testutil.AssertEqual(t, "token", "\nMore results are available, get the next page with: --continue abc123\n", buf.String())
+}
@@ -14,7 +14,7 @@
 //
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/google/kf/pkg/kf/logs/fake (interfaces: Tailer)
+// Source: github.com/google/kf/pkg/kf/logs/fake (interfaces: Tailer,RouterTailer,UnifiedTailer)
 
 // Package fake is a generated GoMock package.
 package fake
@@ -68,3 +68,82 @@ func (mr *FakeTailerMockRecorder) Tail(arg0, arg1, arg2 interface{}, arg3 ...int
 	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tail", reflect.TypeOf((*FakeTailer)(nil).Tail), varargs...)
 }
+
+// FakeRouterTailer is a mock of RouterTailer interface
+type FakeRouterTailer struct {
+	ctrl     *gomock.Controller
+	recorder *FakeRouterTailerMockRecorder
+}
+
+// FakeRouterTailerMockRecorder is the mock recorder for FakeRouterTailer
+type FakeRouterTailerMockRecorder struct {
+	mock *FakeRouterTailer
+}
+
+// NewFakeRouterTailer creates a new mock instance
+func NewFakeRouterTailer(ctrl *gomock.Controller) *FakeRouterTailer {
+	mock := &FakeRouterTailer{ctrl: ctrl}
+	mock.recorder = &FakeRouterTailerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *FakeRouterTailer) EXPECT() *FakeRouterTailerMockRecorder {
+	return m.recorder
+}
+
+// Tail mocks base method
+func (m *FakeRouterTailer) Tail(arg0 context.Context, arg1 logs.RouterLogFilter, arg2 io.Writer, arg3 ...logs.TailOption) error {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1, arg2}
+	for _, a := range arg3 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Tail", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tail indicates an expected call of Tail
+func (mr *FakeRouterTailerMockRecorder) Tail(arg0, arg1, arg2 interface{}, arg3 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1, arg2}, arg3...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tail", reflect.TypeOf((*FakeRouterTailer)(nil).Tail), varargs...)
+}
+
+// FakeUnifiedTailer is a mock of UnifiedTailer interface
+type FakeUnifiedTailer struct {
+	ctrl     *gomock.Controller
+	recorder *FakeUnifiedTailerMockRecorder
+}
+
+// FakeUnifiedTailerMockRecorder is the mock recorder for FakeUnifiedTailer
+type FakeUnifiedTailerMockRecorder struct {
+	mock *FakeUnifiedTailer
+}
+
+// NewFakeUnifiedTailer creates a new mock instance
+func NewFakeUnifiedTailer(ctrl *gomock.Controller) *FakeUnifiedTailer {
+	mock := &FakeUnifiedTailer{ctrl: ctrl}
+	mock.recorder = &FakeUnifiedTailerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *FakeUnifiedTailer) EXPECT() *FakeUnifiedTailerMockRecorder {
+	return m.recorder
+}
+
+// Tail mocks base method
+func (m *FakeUnifiedTailer) Tail(arg0 context.Context, arg1, arg2 string, arg3 int, arg4 func(logs.Entry)) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tail", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Tail indicates an expected call of Tail
+func (mr *FakeUnifiedTailerMockRecorder) Tail(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tail", reflect.TypeOf((*FakeUnifiedTailer)(nil).Tail), arg0, arg1, arg2, arg3, arg4)
+}
@@ -20,9 +20,19 @@ import (
 	"github.com/google/kf/pkg/kf/logs"
 )
 
-//go:generate mockgen --package=fake --copyright_file ../../internal/tools/option-builder/LICENSE_HEADER --destination=fake_logs.go --mock_names=Tailer=FakeTailer github.com/google/kf/pkg/kf/logs/fake Tailer
+//go:generate mockgen --package=fake --copyright_file ../../internal/tools/option-builder/LICENSE_HEADER --destination=fake_logs.go --mock_names=Tailer=FakeTailer,RouterTailer=FakeRouterTailer,UnifiedTailer=FakeUnifiedTailer github.com/google/kf/pkg/kf/logs/fake Tailer,RouterTailer,UnifiedTailer
 
 // Tailer is implemented by logs.Tailer.
 type Tailer interface {
 	logs.Tailer
 }
+
+// RouterTailer is implemented by logs.RouterTailer.
+type RouterTailer interface {
+	logs.RouterTailer
+}
+
+// UnifiedTailer is implemented by logs.UnifiedTailer.
+type UnifiedTailer interface {
+	logs.UnifiedTailer
+}
@@ -0,0 +1,359 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// GatewayNamespace is the namespace the Istio ingress gateway runs in.
+	GatewayNamespace = "istio-system"
+
+	gatewayLabelSelector = "istio=ingressgateway"
+	gatewayContainer     = "istio-proxy"
+
+	// recentRouterLogsWindow is how far back the gateway's logs are searched
+	// for recent requests.
+	recentRouterLogsWindow = time.Hour
+)
+
+// RouterLogEntry is a request read from the access logs of the ingress
+// gateway.
+type RouterLogEntry struct {
+	StartTime     string
+	Method        string
+	Path          string
+	Protocol      string
+	Status        int
+	BytesReceived string
+	BytesSent     string
+	Duration      time.Duration
+	ForwardedFor  string
+	UserAgent     string
+	RequestID     string
+	Authority     string
+	UpstreamHost  string
+}
+
+// Host returns the host the request was sent to without the port.
+func (e RouterLogEntry) Host() string {
+	if host, _, err := net.SplitHostPort(e.Authority); err == nil {
+		return host
+	}
+
+	return e.Authority
+}
+
+// String formats the entry in the style of Cloud Foundry router logs.
+func (e RouterLogEntry) String() string {
+	return fmt.Sprintf(
+		"%s - [%s] \"%s %s %s\" %d %s %s %q x_forwarded_for:%q x_request_id:%q response_time:%.6f upstream:%q",
+		e.Host(),
+		e.StartTime,
+		e.Method,
+		e.Path,
+		e.Protocol,
+		e.Status,
+		e.BytesReceived,
+		e.BytesSent,
+		e.UserAgent,
+		e.ForwardedFor,
+		e.RequestID,
+		e.Duration.Seconds(),
+		e.UpstreamHost,
+	)
+}
+
+// accessLogField is a field of an Envoy access log line.
+type accessLogField struct {
+	value  string
+	quoted bool
+}
+
+// splitAccessLog splits an Envoy access log line into fields. Fields are
+// separated by spaces, quoted or surrounded by square brackets.
+func splitAccessLog(line string) []accessLogField {
+	var fields []accessLogField
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimLeft(line, " ") {
+		var (
+			field accessLogField
+			end   int
+		)
+
+		switch line[0] {
+		case '"':
+			end = strings.Index(line[1:], `"`) + 1
+			field = accessLogField{quoted: true}
+		case '[':
+			end = strings.Index(line, "]")
+		default:
+			end = strings.Index(line, " ")
+			if end < 0 {
+				end = len(line)
+			}
+			fields = append(fields, accessLogField{value: line[:end]})
+			line = line[end:]
+			continue
+		}
+
+		if end <= 0 {
+			// Unterminated, take the rest of the line.
+			end = len(line)
+			field.value = line[1:]
+		} else {
+			field.value = line[1:end]
+			end++
+		}
+
+		fields = append(fields, field)
+		line = line[end:]
+	}
+
+	return fields
+}
+
+// ParseRouterLogEntry parses a line of the ingress gateway's access log. It
+// supports Envoy's default text format used by Istio, with or without the
+// Mixer status and upstream failure reason fields.
+func ParseRouterLogEntry(line string) (*RouterLogEntry, error) {
+	fields := splitAccessLog(line)
+	if len(fields) < 2 || fields[0].quoted || !fields[1].quoted {
+		return nil, errors.New("not an access log entry")
+	}
+
+	var bare, quoted []string
+	for _, f := range fields[2:] {
+		if f.quoted {
+			quoted = append(quoted, f.value)
+		} else if len(quoted) == 0 || len(bare) < 5 {
+			bare = append(bare, f.value)
+		}
+	}
+
+	// The last five quoted fields are the forwarded for address, user agent,
+	// request ID, authority and upstream host. Older versions of Istio don't
+	// have any quoted fields before them.
+	if len(quoted) < 5 || len(bare) < 5 {
+		return nil, errors.New("not an access log entry")
+	}
+	quoted = quoted[len(quoted)-5:]
+
+	request := strings.SplitN(fields[1].value, " ", 3)
+	for len(request) < 3 {
+		request = append(request, "-")
+	}
+
+	status, err := strconv.Atoi(bare[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid status %q", bare[0])
+	}
+
+	// Duration is in milliseconds.
+	duration, err := strconv.Atoi(bare[4])
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration %q", bare[4])
+	}
+
+	return &RouterLogEntry{
+		StartTime:     fields[0].value,
+		Method:        request[0],
+		Path:          request[1],
+		Protocol:      request[2],
+		Status:        status,
+		BytesReceived: bare[2],
+		BytesSent:     bare[3],
+		Duration:      time.Duration(duration) * time.Millisecond,
+		ForwardedFor:  quoted[0],
+		UserAgent:     quoted[1],
+		RequestID:     quoted[2],
+		Authority:     quoted[3],
+		UpstreamHost:  quoted[4],
+	}, nil
+}
+
+// RouterLogFilter returns true if the request should be included in the
+// router logs.
+type RouterLogFilter func(entry RouterLogEntry) bool
+
+// ScanRouterLogs reads access log lines from r and calls f for each request
+// matching the filter. Lines that aren't requests are skipped.
+func ScanRouterLogs(r io.Reader, filter RouterLogFilter, f func(RouterLogEntry) error) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		entry, err := ParseRouterLogEntry(scanner.Text())
+		if err != nil || !filter(*entry) {
+			continue
+		}
+
+		if err := f(*entry); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// RouterTailer reads the access logs of the ingress gateway, giving users
+// router logs for their routes without access to the gateway's namespace.
+type RouterTailer interface {
+	// Tail writes the requests matching the filter to the writer. The
+	// namespace option is the namespace the gateway runs in.
+	Tail(ctx context.Context, filter RouterLogFilter, out io.Writer, opts ...TailOption) error
+}
+
+type routerTailer struct {
+	client corev1client.CoreV1Interface
+}
+
+// NewRouterTailer creates a new RouterTailer.
+func NewRouterTailer(client corev1client.CoreV1Interface) RouterTailer {
+	return &routerTailer{
+		client: client,
+	}
+}
+
+// Tail writes the requests matching the filter to the writer.
+func (t *routerTailer) Tail(ctx context.Context, filter RouterLogFilter, out io.Writer, opts ...TailOption) error {
+	cfg := TailOptionDefaults().Extend(opts).toConfig()
+	if filter == nil {
+		return errors.New("filter is nil")
+	}
+
+	if cfg.NumberLines < 0 {
+		return errors.New("number of lines must be greater than or equal to 0")
+	}
+
+	pods, err := t.client.Pods(cfg.Namespace).List(metav1.ListOptions{
+		LabelSelector: gatewayLabelSelector,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list gateway pods: %s", err)
+	}
+
+	var names []string
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning {
+			names = append(names, pod.Name)
+		}
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("no running gateway pods found in %q", cfg.Namespace)
+	}
+
+	if cfg.Follow {
+		return t.follow(ctx, cfg.Namespace, names, filter, &MutexWriter{Writer: out})
+	}
+
+	return t.recent(ctx, cfg.Namespace, names, filter, out, cfg.NumberLines)
+}
+
+// recent writes the last n requests matching the filter, n of 0 writes all
+// the requests in the search window.
+func (t *routerTailer) recent(ctx context.Context, namespace string, pods []string, filter RouterLogFilter, out io.Writer, n int) error {
+	since := int64(recentRouterLogsWindow / time.Second)
+	logOpts := corev1.PodLogOptions{
+		Container:    gatewayContainer,
+		SinceSeconds: &since,
+	}
+
+	var entries []RouterLogEntry
+	for _, pod := range pods {
+		if err := t.scanPod(ctx, namespace, pod, logOpts, filter, func(e RouterLogEntry) error {
+			entries = append(entries, e)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Each gateway Pod serves part of the traffic, so interleave them.
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartTime < entries[j].StartTime
+	})
+
+	if n > 0 && len(entries) > n {
+		entries = entries[len(entries)-n:]
+	}
+
+	for _, e := range entries {
+		if _, err := fmt.Fprintln(out, e.String()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// follow streams new requests matching the filter from all the gateway Pods
+// until the context is done or the streams close.
+func (t *routerTailer) follow(ctx context.Context, namespace string, pods []string, filter RouterLogFilter, mw *MutexWriter) error {
+	// The gateway serves every space, so older logs aren't replayed.
+	var tailLines int64
+	logOpts := corev1.PodLogOptions{
+		Container: gatewayContainer,
+		Follow:    true,
+		TailLines: &tailLines,
+	}
+
+	var wg sync.WaitGroup
+	for _, pod := range pods {
+		wg.Add(1)
+		go func(pod string) {
+			defer wg.Done()
+
+			if err := t.scanPod(ctx, namespace, pod, logOpts, filter, func(e RouterLogEntry) error {
+				return mw.Write(e.String() + "\n")
+			}); err != nil && ctx.Err() == nil {
+				log.Printf("[WARN] %s", err)
+			}
+		}(pod)
+	}
+
+	wg.Wait()
+	return nil
+}
+
+func (t *routerTailer) scanPod(ctx context.Context, namespace, pod string, opts corev1.PodLogOptions, filter RouterLogFilter, f func(RouterLogEntry) error) error {
+	// XXX: This is not tested at a unit level and instead defers to
+	// integration tests.
+	stream, err := t.client.
+		Pods(namespace).
+		GetLogs(pod, &opts).
+		Context(ctx).
+		Stream()
+	if err != nil {
+		return fmt.Errorf("failed to read logs for gateway Pod %q: %s", pod, err)
+	}
+	defer stream.Close()
+
+	return ScanRouterLogs(stream, filter, f)
+}
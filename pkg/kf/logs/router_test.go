@@ -0,0 +1,199 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/logs"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	ktesting "k8s.io/client-go/testing"
+)
+
+const (
+	// Istio 1.1 and 1.2 access log format.
+	istio12AccessLog = `[2019-10-16T12:00:00.123Z] "GET /api/users HTTP/1.1" 200 - 0 13 5 4 "10.0.0.1" "curl/7.54.0" "abc-123" "myapp.example.com:80" "10.4.0.7:8080" outbound|80||myapp.myspace.svc.cluster.local - 10.4.0.2:80 10.0.0.1:51234 -`
+
+	// Istio 1.3+ access log format, with Mixer status and upstream failure.
+	istio13AccessLog = `[2019-10-16T12:00:01.000Z] "POST /login HTTP/2" 503 UF "-" "-" 42 0 1500 - "-" "Mozilla/5.0 (X11)" "def-456" "other.example.com" "-" - - 10.4.0.2:443 10.0.0.2:40000 other.example.com -`
+)
+
+func TestParseRouterLogEntry(t *testing.T) {
+	t.Parallel()
+
+	for tn, tc := range map[string]struct {
+		line    string
+		want    *logs.RouterLogEntry
+		wantErr error
+	}{
+		"istio 1.2 format": {
+			line: istio12AccessLog,
+			want: &logs.RouterLogEntry{
+				StartTime:     "2019-10-16T12:00:00.123Z",
+				Method:        "GET",
+				Path:          "/api/users",
+				Protocol:      "HTTP/1.1",
+				Status:        200,
+				BytesReceived: "0",
+				BytesSent:     "13",
+				Duration:      5 * time.Millisecond,
+				ForwardedFor:  "10.0.0.1",
+				UserAgent:     "curl/7.54.0",
+				RequestID:     "abc-123",
+				Authority:     "myapp.example.com:80",
+				UpstreamHost:  "10.4.0.7:8080",
+			},
+		},
+		"istio 1.3 format": {
+			line: istio13AccessLog,
+			want: &logs.RouterLogEntry{
+				StartTime:     "2019-10-16T12:00:01.000Z",
+				Method:        "POST",
+				Path:          "/login",
+				Protocol:      "HTTP/2",
+				Status:        503,
+				BytesReceived: "42",
+				BytesSent:     "0",
+				Duration:      1500 * time.Millisecond,
+				ForwardedFor:  "-",
+				UserAgent:     "Mozilla/5.0 (X11)",
+				RequestID:     "def-456",
+				Authority:     "other.example.com",
+				UpstreamHost:  "-",
+			},
+		},
+		"proxy log line": {
+			line:    `[2019-10-16 12:00:00.000][15][warning][config] gRPC config stream closed`,
+			wantErr: errors.New("not an access log entry"),
+		},
+		"empty": {
+			line:    "",
+			wantErr: errors.New("not an access log entry"),
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			got, err := logs.ParseRouterLogEntry(tc.line)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "entry", tc.want, got)
+		})
+	}
+}
+
+func TestRouterLogEntry_String(t *testing.T) {
+	t.Parallel()
+
+	entry, err := logs.ParseRouterLogEntry(istio12AccessLog)
+	testutil.AssertNil(t, "err", err)
+
+	testutil.AssertEqual(
+		t,
+		"string",
+		`myapp.example.com - [2019-10-16T12:00:00.123Z] "GET /api/users HTTP/1.1" 200 0 13 "curl/7.54.0" x_forwarded_for:"10.0.0.1" x_request_id:"abc-123" response_time:0.005000 upstream:"10.4.0.7:8080"`,
+		entry.String(),
+	)
+}
+
+func TestScanRouterLogs(t *testing.T) {
+	t.Parallel()
+
+	input := strings.Join([]string{
+		istio12AccessLog,
+		"some other log line",
+		istio13AccessLog,
+	}, "\n")
+
+	var hosts []string
+	err := logs.ScanRouterLogs(
+		strings.NewReader(input),
+		func(e logs.RouterLogEntry) bool {
+			return e.Host() == "myapp.example.com"
+		},
+		func(e logs.RouterLogEntry) error {
+			hosts = append(hosts, e.Host())
+			return nil
+		},
+	)
+
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "hosts", []string{"myapp.example.com"}, hosts)
+}
+
+func TestRouterTailer_Tail(t *testing.T) {
+	t.Parallel()
+
+	matchAll := func(logs.RouterLogEntry) bool { return true }
+
+	for tn, tc := range map[string]struct {
+		filter  logs.RouterLogFilter
+		opts    []logs.TailOption
+		pods    []corev1.Pod
+		listErr error
+		wantErr error
+	}{
+		"nil filter": {
+			wantErr: errors.New("filter is nil"),
+		},
+		"negative number of lines": {
+			filter:  matchAll,
+			opts:    []logs.TailOption{logs.WithTailNumberLines(-1)},
+			wantErr: errors.New("number of lines must be greater than or equal to 0"),
+		},
+		"listing pods fails": {
+			filter:  matchAll,
+			listErr: errors.New("some-error"),
+			wantErr: errors.New("failed to list gateway pods: some-error"),
+		},
+		"no running pods": {
+			filter: matchAll,
+			opts:   []logs.TailOption{logs.WithTailNamespace(logs.GatewayNamespace)},
+			pods: []corev1.Pod{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "istio-ingressgateway-1"},
+					Status:     corev1.PodStatus{Phase: corev1.PodPending},
+				},
+			},
+			wantErr: errors.New(`no running gateway pods found in "istio-system"`),
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			fakeClient := &fake.FakeCoreV1{
+				Fake: &ktesting.Fake{},
+			}
+
+			fakeClient.AddReactor("list", "pods", func(action ktesting.Action) (bool, runtime.Object, error) {
+				selector := action.(ktesting.ListAction).GetListRestrictions().Labels.String()
+				testutil.AssertEqual(t, "selector", "istio=ingressgateway", selector)
+
+				return true, &corev1.PodList{Items: tc.pods}, tc.listErr
+			})
+
+			err := logs.NewRouterTailer(fakeClient).Tail(
+				context.Background(),
+				tc.filter,
+				&mutexBuffer{},
+				tc.opts...,
+			)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"strconv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -83,14 +84,19 @@ func (t *tailer) Tail(ctx context.Context, appName string, out io.Writer, opts .
 }
 
 func (t *tailer) watchForPods(ctx context.Context, namespace, appName string, writer *MutexWriter, opts corev1.PodLogOptions) error {
-	w, err := t.client.Pods(namespace).Watch(metav1.ListOptions{
+	listOpts := metav1.ListOptions{
 		LabelSelector: "serving.knative.dev/service=" + appName,
-	})
+	}
+
+	w, err := t.client.Pods(namespace).Watch(listOpts)
 	if err != nil {
 		return err
 	}
 
-	defer w.Stop()
+	defer func() {
+		// w is replaced when the watch is re-established.
+		w.Stop()
+	}()
 
 	// We will only wait a second for the first log. If nothing happens after
 	// that period of time and we're not following, then stop.
@@ -102,6 +108,11 @@ func (t *tailer) watchForPods(ctx context.Context, namespace, appName string, wr
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
+	// tailing holds the Pods logs are being read from so they aren't read
+	// twice when the watch is re-established and the Pods are re-announced.
+	tailing := make(map[string]bool)
+	var latestRevision int64
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -111,20 +122,53 @@ func (t *tailer) watchForPods(ctx context.Context, namespace, appName string, wr
 			return nil
 		case e, ok := <-w.ResultChan():
 			if !ok {
-				return nil
+				if !opts.Follow {
+					return nil
+				}
+
+				// The server closes watches periodically and pushes replace
+				// Pods, so keep following until the user stops.
+				w.Stop()
+				next, err := t.rewatchPods(ctx, namespace, listOpts, writer)
+				if err != nil {
+					if ctx.Err() != nil {
+						return nil
+					}
+					return err
+				}
+				w = next
+				continue
 			}
 
 			pod, ok := e.Object.(*corev1.Pod)
 			if !ok {
 				log.Print("[WARN] watched object is not pod")
+				continue
 			}
 
 			switch e.Type {
 			case watch.Added:
+				if tailing[pod.Name] {
+					continue
+				}
+				tailing[pod.Name] = true
+
+				if revision := podRevision(pod); revision > latestRevision {
+					if latestRevision != 0 {
+						err = writer.Write(fmt.Sprintf("--- new revision %d ---\n", revision))
+						if err != nil {
+							return err
+						}
+					}
+					latestRevision = revision
+				}
+
 				go func(e watch.Event) {
 					t.readLogs(ctx, pod.Name, namespace, writer, opts)
 				}(e)
 			case watch.Deleted:
+				delete(tailing, pod.Name)
+
 				err = writer.Write(fmt.Sprintf("[INFO] Pod '%s/%s' is deleted\n", namespace, pod.Name))
 				if err != nil {
 					return err
@@ -134,6 +178,41 @@ func (t *tailer) watchForPods(ctx context.Context, namespace, appName string, wr
 	}
 }
 
+// rewatchPods re-establishes a closed Pod watch, retrying until it succeeds
+// or the context is done.
+func (t *tailer) rewatchPods(ctx context.Context, namespace string, listOpts metav1.ListOptions, mw *MutexWriter) (watch.Interface, error) {
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		w, err := t.client.Pods(namespace).Watch(listOpts)
+		if err == nil {
+			return w, nil
+		}
+
+		if err := mw.Write(fmt.Sprintf("[WARN] failed to watch pods, retrying: %s\n", err)); err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// podRevision returns the generation of the Knative Configuration the Pod
+// was created from or 0 if it's unknown.
+func podRevision(pod *corev1.Pod) int64 {
+	generation, err := strconv.ParseInt(pod.Labels["serving.knative.dev/configurationGeneration"], 10, 64)
+	if err != nil {
+		return 0
+	}
+
+	return generation
+}
+
 func (t *tailer) readLogs(ctx context.Context, name, namespace string, out *MutexWriter, opts corev1.PodLogOptions) {
 	var err error
 	var stop bool
@@ -18,6 +18,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -232,3 +233,70 @@ func createUpdatedEvent(es watch.Event) <-chan watch.Event {
 	c <- es
 	return c
 }
+
+func TestTailer_Tail_followReconnects(t *testing.T) {
+	t.Parallel()
+
+	revisionPod := func(name, generation string) *v1.Pod {
+		return &v1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					"serving.knative.dev/configurationGeneration": generation,
+				},
+				// Terminated pods stop the log readers quickly.
+				DeletionTimestamp: &metav1.Time{Time: time.Now()},
+			},
+		}
+	}
+
+	pods := map[string]*v1.Pod{
+		"some-app-pod1": revisionPod("some-app-pod1", "6"),
+		"some-app-pod2": revisionPod("some-app-pod2", "7"),
+	}
+
+	// Each watch announces the Pods that exist then closes, like a watch
+	// timing out on the server.
+	watches := [][]*v1.Pod{
+		{pods["some-app-pod1"]},
+		{pods["some-app-pod1"], pods["some-app-pod2"]},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fakeClient := &fake.FakeCoreV1{
+		Fake: &ktesting.Fake{},
+	}
+
+	var watchCalls int
+	fakeClient.AddWatchReactor("*", ktesting.WatchReactionFunc(func(action ktesting.Action) (handled bool, ret watch.Interface, err error) {
+		w := watch.NewFakeWithChanSize(2, false)
+		if watchCalls < len(watches) {
+			for _, pod := range watches[watchCalls] {
+				w.Add(pod)
+			}
+		} else {
+			cancel()
+		}
+		watchCalls++
+
+		w.Stop()
+		return true, w, nil
+	}))
+
+	fakeClient.AddReactor("get", "pods", func(action ktesting.Action) (handled bool, ret runtime.Object, err error) {
+		name := action.(ktesting.GetAction).GetName()
+		return true, pods[name].DeepCopy(), nil
+	})
+
+	buf := &mutexBuffer{}
+	gotErr := logs.NewTailer(fakeClient).Tail(ctx, "some-app", buf, logs.WithTailFollow(true))
+	testutil.AssertNil(t, "err", gotErr)
+	testutil.AssertEqual(t, "watch calls", 3, watchCalls)
+	testutil.AssertContainsAll(t, buf.String(), []string{"--- new revision 7 ---\n"})
+
+	if strings.Contains(buf.String(), "new revision 6") {
+		t.Fatalf("expected no marker for the first revision, got:\n%s", buf.String())
+	}
+}
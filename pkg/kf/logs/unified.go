@@ -0,0 +1,439 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kfv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// EntryKindApp is the kind of entries read from the App's logs.
+	EntryKindApp = "app"
+
+	// EntryKindBuild is the kind of entries read from build logs.
+	EntryKindBuild = "build"
+
+	// EntryKindEvent is the kind of entries read from Kubernetes Events.
+	EntryKindEvent = "event"
+
+	// EntryKindRoute is the kind of entries for routes being mapped and
+	// unmapped.
+	EntryKindRoute = "route"
+
+	// defaultReorderWindow is how long entries are held so ones that arrive
+	// late from slower streams can still be written in order.
+	defaultReorderWindow = 2 * time.Second
+)
+
+// Entry is a single line of an App's activity.
+type Entry struct {
+	// Time is when the entry happened, or when it was read for logs which
+	// don't carry a timestamp.
+	Time time.Time
+
+	// Kind is the stream the entry came from, e.g. EntryKindApp.
+	Kind string
+
+	// Text is the line without a trailing newline.
+	Text string
+}
+
+// BuildLogTailer is implemented by sources.Client.
+type BuildLogTailer interface {
+	// Tail streams the build logs of the Source to the writer.
+	Tail(ctx context.Context, namespace, name string, writer io.Writer) error
+}
+
+// UnifiedTailer merges an App's logs, the logs of its builds, Kubernetes
+// Events about it and changes to its routes into a single chronological
+// stream. It should be created via NewUnifiedTailer().
+type UnifiedTailer interface {
+	// Tail calls f with each entry in order until the context is done or one
+	// of the streams fails.
+	Tail(ctx context.Context, namespace, appName string, numberLines int, f func(Entry)) error
+}
+
+type unifiedTailer struct {
+	core       corev1client.CoreV1Interface
+	kf         kfv1alpha1.KfV1alpha1Interface
+	appLogs    Tailer
+	buildLogs  BuildLogTailer
+	window     time.Duration
+	retryDelay time.Duration
+	now        func() time.Time
+}
+
+// NewUnifiedTailer creates a new UnifiedTailer.
+func NewUnifiedTailer(
+	core corev1client.CoreV1Interface,
+	kf kfv1alpha1.KfV1alpha1Interface,
+	appLogs Tailer,
+	buildLogs BuildLogTailer,
+) UnifiedTailer {
+	return &unifiedTailer{
+		core:       core,
+		kf:         kf,
+		appLogs:    appLogs,
+		buildLogs:  buildLogs,
+		window:     defaultReorderWindow,
+		retryDelay: 5 * time.Second,
+		now:        time.Now,
+	}
+}
+
+// Tail implements UnifiedTailer.
+func (t *unifiedTailer) Tail(ctx context.Context, namespace, appName string, numberLines int, f func(Entry)) error {
+	if appName == "" {
+		return errors.New("appName is empty")
+	}
+
+	if numberLines < 0 {
+		return errors.New("number of lines must be greater than or equal to 0")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	entries := make(chan Entry, 100)
+	streams := []func(context.Context, string, string, chan<- Entry) error{
+		func(ctx context.Context, namespace, appName string, out chan<- Entry) error {
+			return t.appLogs.Tail(
+				ctx,
+				appName,
+				t.newEntryWriter(ctx, EntryKindApp, out),
+				WithTailNamespace(namespace),
+				WithTailNumberLines(numberLines),
+				WithTailFollow(true),
+			)
+		},
+		t.tailBuilds,
+		t.tailEvents,
+		t.tailRoutes,
+	}
+
+	errs := make(chan error, len(streams))
+	for _, stream := range streams {
+		go func(stream func(context.Context, string, string, chan<- Entry) error) {
+			errs <- stream(ctx, namespace, appName, entries)
+		}(stream)
+	}
+
+	buffer := &reorderBuffer{window: t.window}
+	ticker := time.NewTicker(t.window / 4)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case e := <-entries:
+			buffer.Add(e)
+
+		case <-ticker.C:
+			for _, e := range buffer.Ready(t.now()) {
+				f(e)
+			}
+
+		case err := <-errs:
+			if err == nil {
+				continue
+			}
+
+			for _, e := range buffer.Drain() {
+				f(e)
+			}
+			return err
+
+		case <-ctx.Done():
+			for _, e := range buffer.Drain() {
+				f(e)
+			}
+			return nil
+		}
+	}
+}
+
+// tailBuilds streams the logs of each build of the App that's running or
+// starts while tailing.
+func (t *unifiedTailer) tailBuilds(ctx context.Context, namespace, appName string, out chan<- Entry) error {
+	tailing := make(map[string]bool)
+	listOpts := metav1.ListOptions{
+		LabelSelector: labels.Set{v1alpha1.NameLabel: appName}.String(),
+	}
+
+	return t.watch(ctx, func() (watch.Interface, error) {
+		return t.kf.Sources(namespace).Watch(listOpts)
+	}, func(e watch.Event) {
+		source, ok := e.Object.(*v1alpha1.Source)
+		if !ok || e.Type == watch.Deleted {
+			return
+		}
+
+		// Builds that already finished aren't news, only follow ones that
+		// are in progress.
+		if source.Status.BuildName == "" || tailing[source.Name] || v1alpha1.IsStatusFinal(source.Status.Status) {
+			return
+		}
+		tailing[source.Name] = true
+
+		go func() {
+			w := t.newEntryWriter(ctx, EntryKindBuild, out)
+			if err := t.buildLogs.Tail(ctx, namespace, source.Name, w); err != nil && ctx.Err() == nil {
+				t.send(ctx, out, Entry{
+					Time: t.now(),
+					Kind: EntryKindBuild,
+					Text: fmt.Sprintf("failed to read logs for build %s: %s", source.Name, err),
+				})
+			}
+		}()
+	})
+}
+
+// tailEvents streams the Kubernetes Events about the App and the objects
+// created for it, which are all prefixed with its name.
+func (t *unifiedTailer) tailEvents(ctx context.Context, namespace, appName string, out chan<- Entry) error {
+	// Events are re-announced when the watch is re-established and updated in
+	// place when they recur, so only new occurrences are sent.
+	seen := make(map[types.UID]int32)
+
+	return t.watch(ctx, func() (watch.Interface, error) {
+		return t.core.Events(namespace).Watch(metav1.ListOptions{})
+	}, func(e watch.Event) {
+		event, ok := e.Object.(*corev1.Event)
+		if !ok || e.Type == watch.Deleted {
+			return
+		}
+
+		name := event.InvolvedObject.Name
+		if name != appName && !strings.HasPrefix(name, appName+"-") {
+			return
+		}
+
+		if count, ok := seen[event.UID]; ok && count >= event.Count {
+			return
+		}
+		seen[event.UID] = event.Count
+
+		t.send(ctx, out, Entry{
+			Time: eventTime(event),
+			Kind: EntryKindEvent,
+			Text: fmt.Sprintf(
+				"%s %s %s/%s: %s",
+				event.Type,
+				event.Reason,
+				event.InvolvedObject.Kind,
+				name,
+				strings.TrimSpace(event.Message),
+			),
+		})
+	})
+}
+
+// eventTime gets the last time the Event happened.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// tailRoutes streams the routes being mapped to and unmapped from the App.
+func (t *unifiedTailer) tailRoutes(ctx context.Context, namespace, appName string, out chan<- Entry) error {
+	seen := make(map[types.UID]bool)
+	listOpts := metav1.ListOptions{
+		LabelSelector: labels.Set{v1alpha1.RouteAppName: appName}.String(),
+	}
+
+	return t.watch(ctx, func() (watch.Interface, error) {
+		return t.kf.Routes(namespace).Watch(listOpts)
+	}, func(e watch.Event) {
+		route, ok := e.Object.(*v1alpha1.Route)
+		if !ok {
+			return
+		}
+
+		switch e.Type {
+		case watch.Added:
+			if seen[route.UID] {
+				return
+			}
+			seen[route.UID] = true
+
+			t.send(ctx, out, Entry{
+				Time: route.CreationTimestamp.Time,
+				Kind: EntryKindRoute,
+				Text: fmt.Sprintf("mapped %s", route.Spec.RouteSpecFields),
+			})
+
+		case watch.Deleted:
+			delete(seen, route.UID)
+
+			t.send(ctx, out, Entry{
+				Time: t.now(),
+				Kind: EntryKindRoute,
+				Text: fmt.Sprintf("unmapped %s", route.Spec.RouteSpecFields),
+			})
+		}
+	})
+}
+
+// watch calls f with every event from the watches start creates until the
+// context is done. Watches the server closes are re-established, only a
+// failure to start the first one is returned.
+func (t *unifiedTailer) watch(ctx context.Context, start func() (watch.Interface, error), f func(watch.Event)) error {
+	w, err := start()
+	if err != nil {
+		return err
+	}
+
+	for {
+		func() {
+			defer w.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case e, ok := <-w.ResultChan():
+					if !ok {
+						return
+					}
+					f(e)
+				}
+			}
+		}()
+
+		for {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			if w, err = start(); err == nil {
+				break
+			}
+
+			select {
+			case <-ctx.Done():
+			case <-time.After(t.retryDelay):
+			}
+		}
+	}
+}
+
+func (t *unifiedTailer) send(ctx context.Context, out chan<- Entry, e Entry) {
+	select {
+	case out <- e:
+	case <-ctx.Done():
+	}
+}
+
+func (t *unifiedTailer) newEntryWriter(ctx context.Context, kind string, out chan<- Entry) io.Writer {
+	return &entryWriter{
+		ctx:  ctx,
+		kind: kind,
+		out:  out,
+		t:    t,
+	}
+}
+
+// entryWriter sends each line written to it as an Entry timestamped when it
+// was read.
+type entryWriter struct {
+	ctx  context.Context
+	kind string
+	out  chan<- Entry
+	t    *unifiedTailer
+	buf  bytes.Buffer
+}
+
+// Write implements io.Writer.
+func (w *entryWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			break
+		}
+
+		line := strings.TrimRight(string(w.buf.Next(i+1)), "\r\n")
+		w.t.send(w.ctx, w.out, Entry{
+			Time: w.t.now(),
+			Kind: w.kind,
+			Text: line,
+		})
+	}
+
+	return len(p), nil
+}
+
+// reorderBuffer holds entries for a window of time so they can be released
+// in chronological order even if they arrive slightly out of order.
+type reorderBuffer struct {
+	window  time.Duration
+	pending []Entry
+}
+
+// Add adds an entry to the buffer.
+func (b *reorderBuffer) Add(e Entry) {
+	b.pending = append(b.pending, e)
+}
+
+// Ready removes and returns the entries older than the window in order.
+func (b *reorderBuffer) Ready(now time.Time) []Entry {
+	b.sort()
+
+	cutoff := now.Add(-b.window)
+	i := sort.Search(len(b.pending), func(i int) bool {
+		return b.pending[i].Time.After(cutoff)
+	})
+
+	ready := b.pending[:i:i]
+	b.pending = b.pending[i:]
+	return ready
+}
+
+// Drain removes and returns all the entries in order.
+func (b *reorderBuffer) Drain() []Entry {
+	b.sort()
+
+	ready := b.pending
+	b.pending = nil
+	return ready
+}
+
+func (b *reorderBuffer) sort() {
+	sort.SliceStable(b.pending, func(i, j int) bool {
+		return b.pending[i].Time.Before(b.pending[j].Time)
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestReorderBuffer(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int, text string) Entry {
+		return Entry{Time: start.Add(time.Duration(seconds) * time.Second), Text: text}
+	}
+
+	buffer := &reorderBuffer{window: 2 * time.Second}
+	buffer.Add(at(3, "c"))
+	buffer.Add(at(1, "a"))
+	buffer.Add(at(5, "e"))
+	buffer.Add(at(2, "b"))
+	buffer.Add(at(1, "a2"))
+
+	testutil.AssertEqual(t, "nothing ready", 0, len(buffer.Ready(start.Add(time.Second))))
+	testutil.AssertEqual(t, "ready", []Entry{
+		at(1, "a"),
+		at(1, "a2"),
+		at(2, "b"),
+	}, buffer.Ready(start.Add(4*time.Second)))
+
+	buffer.Add(at(4, "d"))
+	testutil.AssertEqual(t, "drained", []Entry{
+		at(3, "c"),
+		at(4, "d"),
+		at(5, "e"),
+	}, buffer.Drain())
+	testutil.AssertEqual(t, "empty", 0, len(buffer.Drain()))
+}
+
+func TestEntryWriter(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	tailer := &unifiedTailer{now: func() time.Time { return now }}
+	out := make(chan Entry, 10)
+	w := tailer.newEntryWriter(context.Background(), EntryKindApp, out)
+
+	fmt.Fprint(w, "first\r\nsec")
+	fmt.Fprint(w, "ond\nthird")
+	close(out)
+
+	var got []Entry
+	for e := range out {
+		got = append(got, e)
+	}
+
+	testutil.AssertEqual(t, "entries", []Entry{
+		{Time: now, Kind: EntryKindApp, Text: "first"},
+		{Time: now, Kind: EntryKindApp, Text: "second"},
+	}, got)
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import "errors"
+
+// Exit codes kf returns. These are part of kf's public interface and must not
+// change so pipelines can branch on them.
+const (
+	// ExitOK is returned when the command succeeded.
+	ExitOK = 0
+
+	// ExitError is returned for failures that don't have a more specific code.
+	ExitError = 1
+
+	// ExitUsage is returned when the command was invoked with bad flags or
+	// arguments.
+	ExitUsage = 2
+
+	// ExitBuildFailed is returned when an App's build failed.
+	ExitBuildFailed = 3
+
+	// ExitDeployFailed is returned when an App built but failed to become
+	// ready.
+	ExitDeployFailed = 4
+
+	// ExitTimeout is returned when a phase of an operation exceeded its time
+	// limit.
+	ExitTimeout = 5
+)
+
+// exitError associates an exit code with an error.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitError) Unwrap() error {
+	return e.err
+}
+
+// WithExitCode annotates err so kf exits with code when it is returned from a
+// command. A nil err stays nil.
+func WithExitCode(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &exitError{code: code, err: err}
+}
+
+// ExitCode gets the code kf should exit with for err.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var exitErr *exitError
+	if errors.As(err, &exitErr) {
+		return exitErr.code
+	}
+
+	return ExitError
+}
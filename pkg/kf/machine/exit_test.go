@@ -0,0 +1,67 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/machine"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		err      error
+		wantCode int
+	}{
+		"nil": {
+			err:      nil,
+			wantCode: machine.ExitOK,
+		},
+		"plain error": {
+			err:      errors.New("some-error"),
+			wantCode: machine.ExitError,
+		},
+		"annotated error": {
+			err:      machine.WithExitCode(machine.ExitBuildFailed, errors.New("build failed")),
+			wantCode: machine.ExitBuildFailed,
+		},
+		"wrapped annotated error": {
+			err:      fmt.Errorf("push: %w", machine.WithExitCode(machine.ExitDeployFailed, errors.New("deploy failed"))),
+			wantCode: machine.ExitDeployFailed,
+		},
+		"annotated nil": {
+			err:      machine.WithExitCode(machine.ExitUsage, nil),
+			wantCode: machine.ExitOK,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "exit code", tc.wantCode, machine.ExitCode(tc.err))
+		})
+	}
+}
+
+func TestWithExitCode_message(t *testing.T) {
+	t.Parallel()
+
+	err := machine.WithExitCode(machine.ExitUsage, errors.New("bad flag"))
+	testutil.AssertErrorsEqual(t, errors.New("bad flag"), err)
+}
@@ -0,0 +1,131 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sync"
+)
+
+// DefaultPhase is the phase of events written before any phase is set.
+const DefaultPhase = "log"
+
+// Event is a single progress update emitted in machine mode.
+type Event struct {
+	// Phase is the step of the operation the event belongs to e.g. upload,
+	// build, or deploy.
+	Phase string `json:"phase"`
+
+	// Percent is the estimated completion of the whole operation from 0 to
+	// 100.
+	Percent int `json:"percent"`
+
+	// Message is a human readable description of the event.
+	Message string `json:"message"`
+}
+
+// ansiEscape matches the terminal escape sequences used to color output.
+var ansiEscape = regexp.MustCompile("\x1b\\[[0-9;]*[a-zA-Z]")
+
+// Writer emits events to an underlying writer as line-delimited JSON. Text
+// written to it is converted to one event per line using the phase and
+// percent of the last emitted event so existing output is captured without
+// changes. Writer is safe for concurrent use.
+type Writer struct {
+	mu      sync.Mutex
+	out     io.Writer
+	phase   string
+	percent int
+	partial []byte
+}
+
+var _ io.Writer = (*Writer)(nil)
+
+// NewWriter creates a Writer that emits events to out.
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{
+		out:   out,
+		phase: DefaultPhase,
+	}
+}
+
+// Emit writes an event and makes its phase and percent the current ones for
+// subsequent text output.
+func (w *Writer) Emit(phase string, percent int, message string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.phase = phase
+	w.percent = percent
+	return w.emit(message)
+}
+
+// Write implements io.Writer. Each complete line becomes an event, partial
+// lines are held until the rest of the line is written.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.partial = append(w.partial, p...)
+	for {
+		idx := bytes.IndexByte(w.partial, '\n')
+		if idx < 0 {
+			break
+		}
+
+		line := w.partial[:idx]
+		w.partial = w.partial[idx+1:]
+		if err := w.emit(string(line)); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (w *Writer) emit(message string) error {
+	message = ansiEscape.ReplaceAllString(message, "")
+
+	out, err := json.Marshal(Event{
+		Phase:   w.phase,
+		Percent: w.percent,
+		Message: message,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.out.Write(append(out, '\n'))
+	return err
+}
+
+// Emit writes an event to w if it is in machine mode and does nothing
+// otherwise. It's used to report progress that has no text equivalent.
+func Emit(w io.Writer, phase string, percent int, message string) error {
+	if mw, ok := EventWriter(w); ok {
+		return mw.Emit(phase, percent, message)
+	}
+
+	return nil
+}
+
+// EventWriter returns the Writer backing w if w is in machine mode.
+func EventWriter(w io.Writer) (*Writer, bool) {
+	mw, ok := w.(*Writer)
+	return mw, ok
+}
@@ -0,0 +1,83 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine_test
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/machine"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		run     func(w *machine.Writer)
+		wantOut string
+	}{
+		"text uses the default phase": {
+			run: func(w *machine.Writer) {
+				fmt.Fprintln(w, "hello")
+			},
+			wantOut: `{"phase":"log","percent":0,"message":"hello"}` + "\n",
+		},
+		"text inherits the last event": {
+			run: func(w *machine.Writer) {
+				w.Emit("build", 25, "Starting build")
+				fmt.Fprintln(w, "Step 1/2")
+			},
+			wantOut: `{"phase":"build","percent":25,"message":"Starting build"}` + "\n" +
+				`{"phase":"build","percent":25,"message":"Step 1/2"}` + "\n",
+		},
+		"partial lines are joined": {
+			run: func(w *machine.Writer) {
+				fmt.Fprint(w, "first ")
+				fmt.Fprint(w, "half\nsecond line\nunterminated")
+			},
+			wantOut: `{"phase":"log","percent":0,"message":"first half"}` + "\n" +
+				`{"phase":"log","percent":0,"message":"second line"}` + "\n",
+		},
+		"colors are stripped": {
+			run: func(w *machine.Writer) {
+				fmt.Fprintln(w, "\033[32m[build]\033[0m done")
+			},
+			wantOut: `{"phase":"log","percent":0,"message":"[build] done"}` + "\n",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			tc.run(machine.NewWriter(buf))
+
+			testutil.AssertEqual(t, "output", tc.wantOut, buf.String())
+		})
+	}
+}
+
+func TestEventWriter(t *testing.T) {
+	t.Parallel()
+
+	_, ok := machine.EventWriter(&bytes.Buffer{})
+	testutil.AssertEqual(t, "plain writer", false, ok)
+
+	w := machine.NewWriter(&bytes.Buffer{})
+	got, ok := machine.EventWriter(w)
+	testutil.AssertEqual(t, "machine writer", true, ok)
+	testutil.AssertEqual(t, "writer", w, got)
+}
@@ -27,6 +27,7 @@ import (
 
 	"github.com/google/kf/pkg/internal/envutil"
 	"github.com/imdario/mergo"
+	corev1 "k8s.io/api/core/v1"
 	"knative.dev/pkg/kmp"
 	"sigs.k8s.io/yaml"
 )
@@ -84,11 +85,64 @@ type KfApplicationExtension struct {
 	Args       []string `json:"args,omitempty"`
 
 	Dockerfile Dockerfile `json:"dockerfile,omitempty"`
+
+	BindingFormat string `json:"binding-format,omitempty"`
+
+	// HealthCheckExpect is text the body of http health check responses
+	// must contain for the App to be ready.
+	HealthCheckExpect string `json:"health-check-expect,omitempty"`
+
+	// SharedPaths are directories outside of Path, relative to the directory
+	// being pushed, that are packaged with the App's source at the same
+	// relative path. They let Apps in a monorepo share code without
+	// uploading the whole repository.
+	SharedPaths []string `json:"shared-paths,omitempty"`
+
+	// PushTimeouts limit how long each phase of kf push may take.
+	PushTimeouts PushTimeouts `json:"push-timeouts,omitempty"`
+
+	// Hooks are commands run before and after the App is pushed.
+	Hooks Hooks `json:"hooks,omitempty"`
+}
+
+// Hooks holds commands run as part of kf push. PrePush and PostDeploy run
+// locally with sh and PostDeployTasks run in the cluster with the App's built
+// image, e.g. to migrate a database. A failing pre-push hook stops the push
+// and failing post-deploy hooks or tasks roll the App back to the version
+// that was running before.
+type Hooks struct {
+	PrePush         []string `json:"pre-push,omitempty"`
+	PostDeploy      []string `json:"post-deploy,omitempty"`
+	PostDeployTasks []string `json:"post-deploy-tasks,omitempty"`
+}
+
+// HasPostDeploy returns true if the App has hooks or tasks that run after it's
+// deployed.
+func (h Hooks) HasPostDeploy() bool {
+	return len(h.PostDeploy) > 0 || len(h.PostDeployTasks) > 0
+}
+
+// PushTimeouts holds the limit for each phase of a push as a duration string
+// such as 90s or 10m. Unset phases have no limit.
+type PushTimeouts struct {
+	Upload string `json:"upload,omitempty"`
+	Build  string `json:"build,omitempty"`
+	Deploy string `json:"deploy,omitempty"`
+	Route  string `json:"route,omitempty"`
 }
 
 // AppDockerImage is the struct for docker configuration.
 type AppDockerImage struct {
 	Image string `json:"image,omitempty"`
+
+	// BuildArgs are passed as --build-arg values when building the App's
+	// Dockerfile.
+	BuildArgs map[string]string `json:"build_args,omitempty"`
+
+	// BuildArgsFromSecrets are Dockerfile build args read from Secrets. Each
+	// value is a reference in the form SECRET_NAME[:KEY] so the build arg's
+	// value is never stored on the App.
+	BuildArgsFromSecrets map[string]string `json:"build_args_from_secrets,omitempty"`
 }
 
 // Route is a route name (including hostname, domain, and path) for an application.
@@ -133,6 +187,16 @@ func NewFromReader(reader io.Reader) (*Manifest, error) {
 	return &m, nil
 }
 
+// WriteFile saves the Manifest as YAML to a manifest file.
+func (m *Manifest) WriteFile(manifestFile string) error {
+	out, err := yaml.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(manifestFile, out, 0644)
+}
+
 // New creates a Manifest for a single app.
 func New(appName string) (*Manifest, error) {
 	if appName == "" {
@@ -186,7 +250,7 @@ func (m Manifest) App(name string) (*Application, error) {
 func (app *Application) Override(overrides *Application) error {
 	appEnv := envutil.MapToEnvVars(app.Env)
 	overrideEnv := envutil.MapToEnvVars(overrides.Env)
-	combined := append(appEnv, overrideEnv...)
+	combined := envutil.OverrideEnvVars(appEnv, overrideEnv)
 
 	if overrides.RandomRoute != nil {
 		app.RandomRoute = overrides.RandomRoute
@@ -203,12 +267,18 @@ func (app *Application) Override(overrides *Application) error {
 		app.Routes = overrides.Routes
 	}
 
+	buildArgs := mergeStringMaps(app.Docker.BuildArgs, overrides.Docker.BuildArgs)
+	secretBuildArgs := mergeStringMaps(app.Docker.BuildArgsFromSecrets, overrides.Docker.BuildArgsFromSecrets)
+
 	if err := mergo.Merge(app, overrides, mergo.WithOverride); err != nil {
 		return err
 	}
 
+	app.Docker.BuildArgs = buildArgs
+	app.Docker.BuildArgsFromSecrets = secretBuildArgs
+
 	if len(combined) > 0 {
-		app.Env = envutil.EnvVarsToMap(envutil.DeduplicateEnvVars(combined))
+		app.Env = envutil.EnvVarsToMap(combined)
 	}
 
 	if err := app.Validate(context.Background()); err.Error() != "" {
@@ -218,6 +288,50 @@ func (app *Application) Override(overrides *Application) error {
 	return nil
 }
 
+// mergeStringMaps combines the maps with keys in later maps taking priority.
+// It returns nil if there are no keys.
+func mergeStringMaps(maps ...map[string]string) map[string]string {
+	var out map[string]string
+	for _, m := range maps {
+		for k, v := range m {
+			if out == nil {
+				out = make(map[string]string)
+			}
+			out[k] = v
+		}
+	}
+
+	return out
+}
+
+// DockerfileBuildArgs returns the build args for the App's Dockerfile sorted
+// by name. Args read from Secrets reference the Secret rather than holding the
+// value.
+func (app *Application) DockerfileBuildArgs() ([]corev1.EnvVar, error) {
+	out := envutil.MapToEnvVars(app.Docker.BuildArgs)
+
+	var names []string
+	for name := range app.Docker.BuildArgsFromSecrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, ok := app.Docker.BuildArgs[name]; ok {
+			return nil, fmt.Errorf("build arg %s can't be set both directly and from a Secret", name)
+		}
+
+		arg, err := envutil.NewSecretEnvVar(name, app.Docker.BuildArgsFromSecrets[name])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, arg)
+	}
+
+	envutil.SortEnvVars(out)
+	return out, nil
+}
+
 // WarnUnofficialFields prints a message to the given writer if the user is
 // using any kf specific fields in their configuration.
 func (app *Application) WarnUnofficialFields(w io.Writer) error {
@@ -0,0 +1,307 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// LintError is a problem found in a manifest.
+type LintError struct {
+	// Line and Column are the 1-based position of the problem in the
+	// manifest, zero if it couldn't be found.
+	Line   int
+	Column int
+
+	// Field is the path to the field with the problem, e.g.
+	// applications[0].memory.
+	Field string
+
+	Message string
+}
+
+func (e LintError) Error() string {
+	location := ""
+	if e.Line > 0 {
+		location = fmt.Sprintf("%d:%d: ", e.Line, e.Column)
+	}
+
+	if e.Field == "" {
+		return location + e.Message
+	}
+
+	return fmt.Sprintf("%s%s: %s", location, e.Field, e.Message)
+}
+
+var yamlLineError = regexp.MustCompile(`line (\d+): `)
+
+// Lint checks a manifest against the manifest JSON schema and Kf's
+// validation rules. An error is returned if the manifest isn't valid YAML.
+func Lint(ctx context.Context, contents []byte) ([]LintError, error) {
+	jsonContents, err := yaml.YAMLToJSON(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(jsonContents))
+	decoder.UseNumber()
+
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	locator := newYAMLLocator(contents)
+
+	var lintErrs []LintError
+	for _, schemaErr := range JSONSchema().validate(nil, document) {
+		line, col := locator.locate(schemaErr.path)
+		lintErrs = append(lintErrs, LintError{
+			Line:    line,
+			Column:  col,
+			Field:   fieldPath(schemaErr.path),
+			Message: schemaErr.message,
+		})
+	}
+
+	// Kf's validation can only run on manifests that match the schema.
+	if len(lintErrs) > 0 {
+		return sortLintErrors(lintErrs), nil
+	}
+
+	m := Manifest{}
+	if err := yaml.Unmarshal(contents, &m); err != nil {
+		lintErr := LintError{Message: err.Error()}
+		if match := yamlLineError.FindStringSubmatch(err.Error()); match != nil {
+			lintErr.Line, _ = strconv.Atoi(match[1])
+			lintErr.Column = 1
+		}
+		return []LintError{lintErr}, nil
+	}
+
+	for i, app := range m.Applications {
+		if err := app.Validate(ctx); err != nil {
+			path := []interface{}{"applications", i}
+			line, col := locator.locate(path)
+			lintErrs = append(lintErrs, LintError{
+				Line:    line,
+				Column:  col,
+				Field:   fieldPath(path),
+				Message: err.Error(),
+			})
+		}
+	}
+
+	return sortLintErrors(lintErrs), nil
+}
+
+// sortLintErrors orders errors by their position in the manifest.
+func sortLintErrors(lintErrs []LintError) []LintError {
+	sort.SliceStable(lintErrs, func(i, j int) bool {
+		if lintErrs[i].Line != lintErrs[j].Line {
+			return lintErrs[i].Line < lintErrs[j].Line
+		}
+		return lintErrs[i].Column < lintErrs[j].Column
+	})
+	return lintErrs
+}
+
+// fieldPath formats a path of keys and indexes like applications[0].name.
+func fieldPath(path []interface{}) string {
+	out := ""
+	for _, elem := range path {
+		switch v := elem.(type) {
+		case int:
+			out += fmt.Sprintf("[%d]", v)
+		default:
+			if out != "" {
+				out += "."
+			}
+			out += fmt.Sprint(v)
+		}
+	}
+	return out
+}
+
+// yamlLocator finds the position of fields in block style YAML. It doesn't
+// understand flow style so for fields in flow style collections it returns
+// the closest block style parent.
+type yamlLocator struct {
+	lines []string
+}
+
+func newYAMLLocator(contents []byte) *yamlLocator {
+	return &yamlLocator{lines: strings.Split(string(contents), "\n")}
+}
+
+// yamlLine describes where the content of a line starts.
+type yamlLine struct {
+	// indent is the number of leading spaces.
+	indent int
+	// content is the column where the content starts after any sequence
+	// item markers.
+	content int
+	// items are the columns of sequence item markers on the line.
+	items []int
+	// skip is true for blank lines, comments and document markers.
+	skip bool
+}
+
+func (l *yamlLocator) parse(i int) yamlLine {
+	line := l.lines[i]
+	trimmed := strings.TrimLeft(line, " ")
+	out := yamlLine{indent: len(line) - len(trimmed)}
+
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "---") {
+		out.skip = true
+		return out
+	}
+
+	out.content = out.indent
+	for {
+		rest := line[out.content:]
+		if rest != "-" && !strings.HasPrefix(rest, "- ") {
+			break
+		}
+
+		out.items = append(out.items, out.content)
+		out.content += 1 + len(rest[1:]) - len(strings.TrimLeft(rest[1:], " "))
+	}
+
+	return out
+}
+
+// locate returns the 1-based line and column of the field at path.
+func (l *yamlLocator) locate(path []interface{}) (line, col int) {
+	// The search starts after the parent's line, the parent is a virtual
+	// node before the document for the root.
+	start, parentCol, parentIsItem := 0, -1, false
+	line, col = 1, 1
+
+	for _, elem := range path {
+		found := false
+
+		switch v := elem.(type) {
+		case int:
+			found, start, parentCol = l.findItem(start, parentCol, parentIsItem, v)
+			parentIsItem = true
+
+		case string:
+			found, start, parentCol = l.findKey(start, parentCol, parentIsItem, v)
+			parentIsItem = false
+		}
+
+		if !found {
+			return line, col
+		}
+
+		line, col = start+1, parentCol+1
+	}
+
+	return line, col
+}
+
+// findItem finds the index'th item of the sequence that's the value of the
+// key at (start, parentCol). Block sequences may be at the same column as
+// their key. Sequences nested directly in sequences aren't supported.
+func (l *yamlLocator) findItem(start, parentCol int, parentIsItem bool, index int) (bool, int, int) {
+	if parentIsItem {
+		return false, 0, 0
+	}
+
+	first := 0
+	if parentCol >= 0 {
+		first = start + 1
+	}
+
+	count, itemCol := 0, -1
+	for i := first; i < len(l.lines); i++ {
+		parsed := l.parse(i)
+		if parsed.skip {
+			continue
+		}
+
+		isItem := len(parsed.items) > 0
+		if parsed.indent < parentCol || (parsed.indent == parentCol && !isItem) {
+			return false, 0, 0
+		}
+
+		if itemCol == -1 {
+			if !isItem {
+				return false, 0, 0
+			}
+			itemCol = parsed.indent
+		}
+
+		switch {
+		case parsed.indent < itemCol, parsed.indent == itemCol && !isItem:
+			return false, 0, 0
+		case parsed.indent == itemCol:
+			if count == index {
+				return true, i, itemCol
+			}
+			count++
+		}
+	}
+
+	return false, 0, 0
+}
+
+// findKey finds a key in the mapping that's the value of the key or sequence
+// item at (start, parentCol). The mapping of a sequence item starts on the
+// item's line.
+func (l *yamlLocator) findKey(start, parentCol int, parentIsItem bool, key string) (bool, int, int) {
+	quoted := regexp.QuoteMeta(key)
+	keyPattern := regexp.MustCompile(`^(` + quoted + `|"` + quoted + `"|'` + quoted + `')\s*:(\s|$)`)
+
+	first := 0
+	if parentCol >= 0 {
+		first = start + 1
+		if parentIsItem {
+			first = start
+		}
+	}
+
+	keyCol := -1
+	for i := first; i < len(l.lines); i++ {
+		parsed := l.parse(i)
+		if parsed.skip || parsed.content >= len(l.lines[i]) {
+			continue
+		}
+
+		if i != start && parsed.indent <= parentCol {
+			return false, 0, 0
+		}
+
+		if keyCol == -1 {
+			keyCol = parsed.content
+		}
+
+		if parsed.content == keyCol && keyPattern.MatchString(l.lines[i][parsed.content:]) {
+			return true, i, parsed.content
+		}
+	}
+
+	return false, 0, 0
+}
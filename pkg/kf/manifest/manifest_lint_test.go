@@ -0,0 +1,125 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestLint(t *testing.T) {
+	cases := map[string]struct {
+		manifest string
+		want     []string
+		wantErr  error
+	}{
+		"valid": {
+			manifest: `---
+applications:
+- name: my-app
+  memory: 512M
+  instances: 2
+  env:
+    FOO: bar
+  routes:
+  - route: my-app.example.com
+`,
+		},
+		"invalid yaml": {
+			manifest: "applications: [",
+			wantErr:  errors.New("yaml: line 1: did not find expected node content"),
+		},
+		"missing applications": {
+			manifest: "foo: bar\n",
+			want: []string{
+				`1:1: missing required field "applications"`,
+				"1:1: foo: unknown field",
+			},
+		},
+		"schema errors": {
+			manifest: `applications:
+# comment
+- name: my-app
+  memory: 512
+  instances: -1
+  env:
+    FOO: bar
+    BAZ: 3
+  routes:
+  - route: a.example.com
+  - rout: b.example.com
+  health-check-type: process
+- name: other-app
+  unknown: x
+`,
+			want: []string{
+				"4:3: applications[0].memory: expected string, got integer",
+				"5:3: applications[0].instances: must be at least 0",
+				"8:5: applications[0].env.BAZ: expected string, got integer",
+				"11:5: applications[0].routes[1].rout: unknown field",
+				"12:3: applications[0].health-check-type: must be one of: http, port",
+				"14:3: applications[1].unknown: unknown field",
+			},
+		},
+		"indented sequences": {
+			manifest: `applications:
+  - path: .
+    buildpacks:
+      - java
+      - 3
+`,
+			want: []string{
+				`2:3: applications[0]: missing required field "name"`,
+				"5:7: applications[0].buildpacks[1]: expected string, got integer",
+			},
+		},
+		"flow style falls back to parent": {
+			manifest: `applications:
+- name: my-app
+  docker: {image: 3}
+`,
+			want: []string{
+				"3:3: applications[0].docker.image: expected string, got integer",
+			},
+		},
+		"kf validation": {
+			manifest: `applications:
+- name: my-app
+- name: other-app
+  command: python
+  args: ["-m", "SimpleHTTPServer"]
+`,
+			want: []string{
+				"3:1: applications[1]: expected exactly one, got both: args, command",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			lintErrs, err := Lint(context.Background(), []byte(tc.manifest))
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+
+			var got []string
+			for _, lintErr := range lintErrs {
+				got = append(got, lintErr.Error())
+			}
+			testutil.AssertEqual(t, "lint errors", tc.want, got)
+		})
+	}
+}
@@ -0,0 +1,265 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+)
+
+// SchemaVersion is the JSON schema draft the manifest schema is written in.
+const SchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// Schema is the subset of JSON schema used to describe manifests.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Minimum     *int               `json:"minimum,omitempty"`
+
+	// AdditionalProperties is false for objects built from structs so
+	// unknown fields are reported, or the schema of map values.
+	AdditionalProperties interface{} `json:"additionalProperties,omitempty"`
+}
+
+// schemaOverrides refine the schema generated for a field, keyed by the Go
+// type and the JSON name of the field.
+var schemaOverrides = map[string]func(s *Schema){
+	"Manifest.applications":  describe("Apps to deploy."),
+	"Application.name":       describe("Name of the App."),
+	"Application.path":       describe("Path to the App's source code, relative to the manifest."),
+	"Application.buildpack":  describe("Buildpack to build the App with, use buildpacks instead."),
+	"Application.stack":      describe("Stack to build and run the App on."),
+	"Application.env":        describe("Environment variables to set on the App."),
+	"Application.services":   describe("Service instances to bind to the App."),
+	"Application.memory":     describe("Memory limit of each instance, e.g. 512M or 1G."),
+	"Application.disk_quota": describe("Disk limit of each instance, e.g. 512M or 1G."),
+	"Application.instances": func(s *Schema) {
+		s.Description = "Number of instances to run."
+		s.Minimum = intPtr(0)
+	},
+	"Application.timeout": func(s *Schema) {
+		s.Description = "Health check timeout in seconds."
+		s.Minimum = intPtr(0)
+	},
+	"Application.health-check-type": func(s *Schema) {
+		s.Description = "Type of health check to run."
+		s.Enum = []string{"http", "port"}
+	},
+	"AppDockerImage.build_args":              describe("Build args to pass when building the App's Dockerfile."),
+	"AppDockerImage.build_args_from_secrets": describe("Build args to read from Secrets, in the form SECRET_NAME[:KEY]."),
+	"KfApplicationExtension.min-scale": func(s *Schema) {
+		s.Description = "Minimum number of instances when autoscaling."
+		s.Minimum = intPtr(0)
+	},
+	"KfApplicationExtension.max-scale": func(s *Schema) {
+		s.Description = "Maximum number of instances when autoscaling."
+		s.Minimum = intPtr(0)
+	},
+	"KfApplicationExtension.shared-paths":  describe("Directories outside of path to package with the App's source."),
+	"KfApplicationExtension.push-timeouts": describe("Limits for each phase of kf push, e.g. 90s or 10m."),
+	"PushTimeouts.upload":                  describe("Limit for packaging and uploading the App's source."),
+	"PushTimeouts.build":                   describe("Limit for building the App."),
+	"PushTimeouts.deploy":                  describe("Limit for the built App to roll out."),
+	"PushTimeouts.route":                   describe("Limit for the App's routes to be reconciled after it rolls out."),
+	"KfApplicationExtension.hooks":         describe("Commands run before and after the App is pushed."),
+	"Hooks.pre-push":                       describe("Commands run locally before the App's source is uploaded."),
+	"Hooks.post-deploy":                    describe("Commands run locally after the App is deployed."),
+	"Hooks.post-deploy-tasks":              describe("Commands run in the cluster with the App's image after it's deployed."),
+	"KfApplicationExtension.binding-format": func(s *Schema) {
+		s.Description = "How service credentials are provided to the App."
+		s.Enum = []string{v1alpha1.BindingFormatVCAP, v1alpha1.BindingFormatK8s}
+	},
+	"KfApplicationExtension.health-check-expect": describe("Text the body of http health check responses must contain."),
+}
+
+func describe(description string) func(s *Schema) {
+	return func(s *Schema) {
+		s.Description = description
+	}
+}
+
+// JSONSchema generates the JSON schema of manifests from the Manifest type so
+// the two can't drift apart.
+func JSONSchema() *Schema {
+	s := schemaForType(reflect.TypeOf(Manifest{}))
+	s.Schema = SchemaVersion
+	s.Title = "Kf App manifest"
+	s.Required = []string{"applications"}
+	s.Properties["applications"].Items.Required = []string{"name"}
+	return s
+}
+
+func schemaForType(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return schemaForType(t.Elem())
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Slice:
+		return &Schema{Type: "array", Items: schemaForType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaForType(t.Elem())}
+	case reflect.Struct:
+		s := &Schema{
+			Type:                 "object",
+			Properties:           map[string]*Schema{},
+			AdditionalProperties: false,
+		}
+		addStructProperties(s, t)
+		return s
+	default:
+		panic(fmt.Sprintf("manifest schema doesn't support %s", t))
+	}
+}
+
+func addStructProperties(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if field.Anonymous && name == "" {
+			addStructProperties(s, field.Type)
+			continue
+		}
+
+		if name == "" || name == "-" {
+			continue
+		}
+
+		prop := schemaForType(field.Type)
+		if override, ok := schemaOverrides[t.Name()+"."+name]; ok {
+			override(prop)
+		}
+		s.Properties[name] = prop
+	}
+}
+
+// schemaError is a violation of the schema at a path in the document.
+type schemaError struct {
+	path    []interface{}
+	message string
+}
+
+// validate checks a document decoded from JSON with UseNumber against the
+// schema.
+func (s *Schema) validate(path []interface{}, value interface{}) (errs []schemaError) {
+	fail := func(format string, args ...interface{}) []schemaError {
+		return []schemaError{{path: path, message: fmt.Sprintf(format, args...)}}
+	}
+
+	if value == nil {
+		return fail("expected %s, got null", s.Type)
+	}
+
+	if got := jsonType(value); got != s.Type && !(s.Type == "number" && got == "integer") {
+		return fail("expected %s, got %s", s.Type, got)
+	}
+
+	switch v := value.(type) {
+	case string:
+		if len(s.Enum) > 0 && !containsString(s.Enum, v) {
+			return fail("must be one of: %s", strings.Join(s.Enum, ", "))
+		}
+
+	case json.Number:
+		if n, err := v.Int64(); err == nil && s.Minimum != nil && n < int64(*s.Minimum) {
+			return fail("must be at least %d", *s.Minimum)
+		}
+
+	case []interface{}:
+		for i, item := range v {
+			errs = append(errs, s.Items.validate(appendPath(path, i), item)...)
+		}
+
+	case map[string]interface{}:
+		for _, required := range s.Required {
+			if _, ok := v[required]; !ok {
+				errs = append(errs, schemaError{path: path, message: fmt.Sprintf("missing required field %q", required)})
+			}
+		}
+
+		var keys []string
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			prop, ok := s.Properties[key]
+			if !ok {
+				prop, ok = s.AdditionalProperties.(*Schema)
+			}
+
+			if !ok {
+				errs = append(errs, schemaError{path: appendPath(path, key), message: "unknown field"})
+				continue
+			}
+
+			errs = append(errs, prop.validate(appendPath(path, key), v[key])...)
+		}
+	}
+
+	return errs
+}
+
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+func appendPath(path []interface{}, elem interface{}) []interface{} {
+	out := make([]interface{}, len(path), len(path)+1)
+	copy(out, path)
+	return append(out, elem)
+}
+
+func containsString(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
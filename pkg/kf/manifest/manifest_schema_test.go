@@ -0,0 +1,42 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package manifest
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema := JSONSchema()
+
+	testutil.AssertEqual(t, "$schema", SchemaVersion, schema.Schema)
+	testutil.AssertEqual(t, "required", []string{"applications"}, schema.Required)
+
+	app := schema.Properties["applications"].Items
+	testutil.AssertEqual(t, "app required", []string{"name"}, app.Required)
+	testutil.AssertEqual(t, "app additional properties", false, app.AdditionalProperties)
+
+	// Fields of embedded structs are inlined like they are in YAML.
+	testutil.AssertEqual(t, "min-scale type", "integer", app.Properties["min-scale"].Type)
+	testutil.AssertEqual(t, "min-scale minimum", 0, *app.Properties["min-scale"].Minimum)
+
+	testutil.AssertEqual(t, "env values", &Schema{Type: "string"}, app.Properties["env"].AdditionalProperties)
+	testutil.AssertEqual(t, "no-route type", "boolean", app.Properties["no-route"].Type)
+	testutil.AssertEqual(t, "routes item type", "object", app.Properties["routes"].Items.Type)
+	testutil.AssertEqual(t, "binding formats", []string{"vcap", "k8s"}, app.Properties["binding-format"].Enum)
+	testutil.AssertEqual(t, "push-timeouts build type", "string", app.Properties["push-timeouts"].Properties["build"].Type)
+}
@@ -204,6 +204,34 @@ applications:
 	}
 }
 
+func TestManifest_WriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kf-manifest-test")
+	testutil.AssertNil(t, "error creating test directory", err)
+	defer func() {
+		testutil.AssertNil(t, "error deleting test directory", os.RemoveAll(dir))
+	}()
+
+	instances := 2
+	expected := &manifest.Manifest{
+		Applications: []manifest.Application{
+			{
+				Name:        "my-app",
+				Memory:      "1G",
+				Instances:   &instances,
+				RandomRoute: ptr.Bool(true),
+				Buildpacks:  []string{"java"},
+			},
+		},
+	}
+
+	manifestFile := filepath.Join(dir, "manifest.yml")
+	testutil.AssertNil(t, "write error", expected.WriteFile(manifestFile))
+
+	actual, err := manifest.NewFromFile(manifestFile)
+	testutil.AssertNil(t, "read error", err)
+	testutil.AssertEqual(t, "manifest", expected, actual)
+}
+
 func TestOverride(t *testing.T) {
 	cases := map[string]struct {
 		base     manifest.Application
@@ -241,6 +269,40 @@ func TestOverride(t *testing.T) {
 			override: manifest.Application{Env: map[string]string{"override": "override", "base": "override"}},
 			expected: manifest.Application{Env: map[string]string{"base": "override", "override": "override"}},
 		},
+		"build args get merged": {
+			base: manifest.Application{
+				KfApplicationExtension: manifest.KfApplicationExtension{
+					Dockerfile: manifest.Dockerfile{Path: "Dockerfile"},
+				},
+				Docker: manifest.AppDockerImage{
+					BuildArgs:            map[string]string{"base": "base", "shared": "base"},
+					BuildArgsFromSecrets: map[string]string{"TOKEN": "creds"},
+				},
+			},
+			override: manifest.Application{Docker: manifest.AppDockerImage{
+				BuildArgs: map[string]string{"shared": "override"},
+			}},
+			expected: manifest.Application{
+				KfApplicationExtension: manifest.KfApplicationExtension{
+					Dockerfile: manifest.Dockerfile{Path: "Dockerfile"},
+				},
+				Docker: manifest.AppDockerImage{
+					BuildArgs:            map[string]string{"base": "base", "shared": "override"},
+					BuildArgsFromSecrets: map[string]string{"TOKEN": "creds"},
+				},
+			},
+		},
+		"push timeouts override per phase": {
+			base: manifest.Application{KfApplicationExtension: manifest.KfApplicationExtension{
+				PushTimeouts: manifest.PushTimeouts{Build: "10m", Deploy: "5m"},
+			}},
+			override: manifest.Application{KfApplicationExtension: manifest.KfApplicationExtension{
+				PushTimeouts: manifest.PushTimeouts{Build: "20m"},
+			}},
+			expected: manifest.Application{KfApplicationExtension: manifest.KfApplicationExtension{
+				PushTimeouts: manifest.PushTimeouts{Build: "20m", Deploy: "5m"},
+			}},
+		},
 		"buildpacks are strict override": {
 			base:     manifest.Application{Buildpacks: []string{"java", "maven"}},
 			override: manifest.Application{Buildpacks: []string{"node", "npm"}},
@@ -284,6 +346,28 @@ func ExampleApplication_Buildpack() {
 	// Two: maven,java
 }
 
+func ExampleApplication_DockerfileBuildArgs() {
+	app := manifest.Application{}
+	app.Docker.BuildArgs = map[string]string{"VERSION": "1.2.3"}
+	app.Docker.BuildArgsFromSecrets = map[string]string{"TOKEN": "creds:npm-token"}
+
+	args, err := app.DockerfileBuildArgs()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, arg := range args {
+		if ref := arg.ValueFrom; ref != nil {
+			fmt.Printf("%s from Secret %s key %s\n", arg.Name, ref.SecretKeyRef.Name, ref.SecretKeyRef.Key)
+		} else {
+			fmt.Printf("%s=%s\n", arg.Name, arg.Value)
+		}
+	}
+
+	// Output: TOKEN from Secret creds key npm-token
+	// VERSION=1.2.3
+}
+
 func ExampleApplication_CommandArgs() {
 	app := manifest.Application{}
 	fmt.Printf("Blank: %v\n", app.CommandArgs())
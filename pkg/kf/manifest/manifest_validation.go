@@ -16,12 +16,25 @@ package manifest
 
 import (
 	"context"
+	"errors"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/envutil"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
 // Validate checks for errors in the Application's fields.
 func (app *Application) Validate(ctx context.Context) (errs *apis.FieldError) {
+	if app.Name != "" {
+		errs = errs.Also(v1alpha1.ValidateDNS1123Label(app.Name, "name"))
+	}
+
 	// validate container execution
 	if app.Command != "" {
 		if len(app.Args) > 0 {
@@ -49,5 +62,119 @@ func (app *Application) Validate(ctx context.Context) (errs *apis.FieldError) {
 		}
 	}
 
+	switch app.BindingFormat {
+	case "", v1alpha1.BindingFormatVCAP, v1alpha1.BindingFormatK8s:
+	default:
+		errs = errs.Also(apis.ErrInvalidValue(app.BindingFormat, "binding-format"))
+	}
+
+	for i, sharedPath := range app.SharedPaths {
+		if err := validateSharedPath(sharedPath); err != nil {
+			fieldErr := apis.ErrInvalidArrayValue(sharedPath, "shared-paths", i)
+			fieldErr.Details = err.Error()
+			errs = errs.Also(fieldErr)
+		}
+	}
+
+	for _, timeout := range []struct {
+		field string
+		value string
+	}{
+		{field: "push-timeouts.upload", value: app.PushTimeouts.Upload},
+		{field: "push-timeouts.build", value: app.PushTimeouts.Build},
+		{field: "push-timeouts.deploy", value: app.PushTimeouts.Deploy},
+		{field: "push-timeouts.route", value: app.PushTimeouts.Route},
+	} {
+		if timeout.value == "" {
+			continue
+		}
+
+		if d, err := time.ParseDuration(timeout.value); err != nil || d <= 0 {
+			fieldErr := apis.ErrInvalidValue(timeout.value, timeout.field)
+			fieldErr.Details = "timeouts must be positive durations such as 90s or 10m"
+			errs = errs.Also(fieldErr)
+		}
+	}
+
+	for _, hooks := range []struct {
+		field    string
+		commands []string
+	}{
+		{field: "hooks.pre-push", commands: app.Hooks.PrePush},
+		{field: "hooks.post-deploy", commands: app.Hooks.PostDeploy},
+		{field: "hooks.post-deploy-tasks", commands: app.Hooks.PostDeployTasks},
+	} {
+		for i, command := range hooks.commands {
+			if strings.TrimSpace(command) == "" {
+				errs = errs.Also(apis.ErrInvalidArrayValue(command, hooks.field, i))
+			}
+		}
+	}
+
+	if len(app.Docker.BuildArgs) > 0 || len(app.Docker.BuildArgsFromSecrets) > 0 {
+		if app.Dockerfile.Path == "" {
+			errs = errs.Also(&apis.FieldError{
+				Message: "build args can only be used when building a Dockerfile",
+				Paths:   []string{"docker.build_args"},
+			})
+		}
+
+		if _, err := app.DockerfileBuildArgs(); err != nil {
+			errs = errs.Also(&apis.FieldError{
+				Message: err.Error(),
+				Paths:   []string{"docker.build_args"},
+			})
+		}
+
+		for _, name := range sortedKeys(app.Docker.BuildArgs, app.Docker.BuildArgsFromSecrets) {
+			if msgs := validation.IsCIdentifier(name); len(msgs) > 0 {
+				errs = errs.Also(apis.ErrInvalidKeyName(name, "docker.build_args", msgs...))
+			}
+		}
+	}
+
+	// validate environment variable names, sorted so errors are stable
+	var envNames []string
+	for name := range app.Env {
+		envNames = append(envNames, name)
+	}
+	sort.Strings(envNames)
+
+	for _, name := range envNames {
+		if err := envutil.ValidateEnvVarName(name); err != nil {
+			fieldErr := apis.ErrInvalidValue(name, "env")
+			fieldErr.Details = err.Error()
+			errs = errs.Also(fieldErr)
+		}
+	}
+
 	return
 }
+
+// validateSharedPath checks that a shared path is a subdirectory of the
+// directory being pushed.
+func validateSharedPath(sharedPath string) error {
+	cleaned := path.Clean(filepath.ToSlash(sharedPath))
+
+	switch {
+	case path.IsAbs(cleaned) || filepath.IsAbs(sharedPath):
+		return errors.New("shared paths must be relative to the directory being pushed")
+	case cleaned == ".":
+		return errors.New("shared paths must be a subdirectory of the directory being pushed")
+	case cleaned == ".." || strings.HasPrefix(cleaned, "../"):
+		return errors.New("shared paths can't be outside of the directory being pushed")
+	default:
+		return nil
+	}
+}
+
+// sortedKeys returns the unique keys of the maps in sorted order.
+func sortedKeys(maps ...map[string]string) []string {
+	var keys []string
+	for k := range mergeStringMaps(maps...) {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
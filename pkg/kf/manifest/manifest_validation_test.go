@@ -18,7 +18,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/testutil"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"knative.dev/pkg/apis"
 )
 
@@ -30,6 +32,12 @@ func TestApplication_Validation(t *testing.T) {
 		"valid": {
 			spec: Application{},
 		},
+		"invalid name": {
+			spec: Application{
+				Name: "My_App",
+			},
+			want: v1alpha1.ValidateDNS1123Label("My_App", "name"),
+		},
 		"entrypoint and args": {
 			spec: Application{
 				KfApplicationExtension: KfApplicationExtension{
@@ -81,6 +89,159 @@ func TestApplication_Validation(t *testing.T) {
 			},
 			want: apis.ErrMultipleOneOf("instances", "max-scale"),
 		},
+		"k8s binding format": {
+			spec: Application{
+				KfApplicationExtension: KfApplicationExtension{
+					BindingFormat: "k8s",
+				},
+			},
+		},
+		"invalid binding format": {
+			spec: Application{
+				KfApplicationExtension: KfApplicationExtension{
+					BindingFormat: "json",
+				},
+			},
+			want: apis.ErrInvalidValue("json", "binding-format"),
+		},
+		"valid shared paths": {
+			spec: Application{
+				KfApplicationExtension: KfApplicationExtension{
+					SharedPaths: []string{"libs/common", "./proto"},
+				},
+			},
+		},
+		"shared path outside push directory": {
+			spec: Application{
+				KfApplicationExtension: KfApplicationExtension{
+					SharedPaths: []string{"libs/common", "../other-repo"},
+				},
+			},
+			want: func() *apis.FieldError {
+				err := apis.ErrInvalidArrayValue("../other-repo", "shared-paths", 1)
+				err.Details = "shared paths can't be outside of the directory being pushed"
+				return err
+			}(),
+		},
+		"absolute shared path": {
+			spec: Application{
+				KfApplicationExtension: KfApplicationExtension{
+					SharedPaths: []string{"/libs"},
+				},
+			},
+			want: func() *apis.FieldError {
+				err := apis.ErrInvalidArrayValue("/libs", "shared-paths", 0)
+				err.Details = "shared paths must be relative to the directory being pushed"
+				return err
+			}(),
+		},
+		"valid push timeouts": {
+			spec: Application{
+				KfApplicationExtension: KfApplicationExtension{
+					PushTimeouts: PushTimeouts{Upload: "90s", Build: "15m", Route: "1m30s"},
+				},
+			},
+		},
+		"invalid push timeouts": {
+			spec: Application{
+				KfApplicationExtension: KfApplicationExtension{
+					PushTimeouts: PushTimeouts{Build: "15", Deploy: "-1m"},
+				},
+			},
+			want: func() *apis.FieldError {
+				build := apis.ErrInvalidValue("15", "push-timeouts.build")
+				build.Details = "timeouts must be positive durations such as 90s or 10m"
+				deploy := apis.ErrInvalidValue("-1m", "push-timeouts.deploy")
+				deploy.Details = "timeouts must be positive durations such as 90s or 10m"
+				return build.Also(deploy)
+			}(),
+		},
+		"valid hooks": {
+			spec: Application{
+				KfApplicationExtension: KfApplicationExtension{
+					Hooks: Hooks{
+						PrePush:         []string{"make test"},
+						PostDeploy:      []string{"./smoke-test.sh"},
+						PostDeployTasks: []string{"bundle exec rake db:migrate"},
+					},
+				},
+			},
+		},
+		"empty hook": {
+			spec: Application{
+				KfApplicationExtension: KfApplicationExtension{
+					Hooks: Hooks{
+						PrePush:         []string{"make test", " "},
+						PostDeployTasks: []string{""},
+					},
+				},
+			},
+			want: apis.ErrInvalidArrayValue(" ", "hooks.pre-push", 1).
+				Also(apis.ErrInvalidArrayValue("", "hooks.post-deploy-tasks", 0)),
+		},
+		"valid build args": {
+			spec: Application{
+				Docker: AppDockerImage{
+					BuildArgs:            map[string]string{"VERSION": "1.2.3"},
+					BuildArgsFromSecrets: map[string]string{"TOKEN": "creds:token"},
+				},
+				KfApplicationExtension: KfApplicationExtension{
+					Dockerfile: Dockerfile{Path: "Dockerfile"},
+				},
+			},
+		},
+		"build args without Dockerfile": {
+			spec: Application{
+				Docker: AppDockerImage{
+					BuildArgs: map[string]string{"VERSION": "1.2.3"},
+				},
+			},
+			want: &apis.FieldError{
+				Message: "build args can only be used when building a Dockerfile",
+				Paths:   []string{"docker.build_args"},
+			},
+		},
+		"build arg set twice": {
+			spec: Application{
+				Docker: AppDockerImage{
+					BuildArgs:            map[string]string{"TOKEN": "plain"},
+					BuildArgsFromSecrets: map[string]string{"TOKEN": "creds"},
+				},
+				KfApplicationExtension: KfApplicationExtension{
+					Dockerfile: Dockerfile{Path: "Dockerfile"},
+				},
+			},
+			want: &apis.FieldError{
+				Message: "build arg TOKEN can't be set both directly and from a Secret",
+				Paths:   []string{"docker.build_args"},
+			},
+		},
+		"invalid build arg name": {
+			spec: Application{
+				Docker: AppDockerImage{
+					BuildArgs: map[string]string{"NOT-VALID": "x"},
+				},
+				KfApplicationExtension: KfApplicationExtension{
+					Dockerfile: Dockerfile{Path: "Dockerfile"},
+				},
+			},
+			want: apis.ErrInvalidKeyName("NOT-VALID", "docker.build_args", validation.IsCIdentifier("NOT-VALID")...),
+		},
+		"valid env": {
+			spec: Application{
+				Env: map[string]string{"JAVA_OPTS": "-Xmx1g"},
+			},
+		},
+		"reserved env": {
+			spec: Application{
+				Env: map[string]string{"PORT": "8080"},
+			},
+			want: func() *apis.FieldError {
+				err := apis.ErrInvalidValue("PORT", "env")
+				err.Details = "environment variable PORT is reserved by Kf"
+				return err
+			}(),
+		},
 	}
 
 	for tn, tc := range cases {
@@ -0,0 +1,117 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// PrometheusNamespace is the namespace Knative installs Prometheus into.
+	PrometheusNamespace = "knative-monitoring"
+
+	// PrometheusService is the name of the Prometheus service.
+	PrometheusService = "prometheus-system-np"
+
+	// PrometheusPort is the port the Prometheus service serves its API on.
+	PrometheusPort = "8080"
+
+	// SourcePrometheus is used when the history came from Prometheus.
+	SourcePrometheus = "prometheus"
+
+	// SourceMetricsServer is used when the history came from metrics-server.
+	SourceMetricsServer = "metrics-server"
+)
+
+// Sample is a single measurement.
+type Sample struct {
+	Time  time.Time
+	Value float64
+}
+
+// InstanceHistory holds the resource usage of a single instance of an App.
+type InstanceHistory struct {
+	// Name is the name of the pod running the instance.
+	Name string
+
+	// CPU is the usage in cores.
+	CPU []Sample
+
+	// Memory is the working set in bytes.
+	Memory []Sample
+
+	// Requests is the number of requests per second the instance served.
+	Requests []Sample
+}
+
+// History is the resource usage of every instance of an App.
+type History struct {
+	// Source is the pipeline the history was read from.
+	Source string
+
+	// Instances is the usage of each instance sorted by name.
+	Instances []InstanceHistory
+}
+
+// Client reads metrics for Apps.
+type Client interface {
+	// History gets the resource usage of each instance of the App over the
+	// given duration. Prometheus is used if it's installed, otherwise
+	// metrics-server is used which can only report current usage.
+	History(namespace, appName string, since time.Duration) (*History, error)
+}
+
+// NewClient creates a new metrics Client.
+func NewClient(c kubernetes.Interface) Client {
+	return &client{
+		c:   c,
+		now: time.Now,
+	}
+}
+
+type client struct {
+	c   kubernetes.Interface
+	now func() time.Time
+}
+
+// History implements Client.History.
+func (c *client) History(namespace, appName string, since time.Duration) (*History, error) {
+	if appName == "" {
+		return nil, errors.New("appName is empty")
+	}
+
+	if since <= 0 {
+		return nil, errors.New("since must be greater than 0")
+	}
+
+	_, err := c.c.
+		CoreV1().
+		Services(PrometheusNamespace).
+		Get(PrometheusService, metav1.GetOptions{})
+	switch {
+	case err == nil:
+		return c.prometheusHistory(namespace, appName, since)
+	case apierrs.IsNotFound(err):
+		return c.metricsServerHistory(namespace, appName)
+	default:
+		return nil, fmt.Errorf("couldn't find Prometheus: %s", err)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// podMetricsList is the subset of a metrics.k8s.io/v1beta1 PodMetricsList
+// needed to report usage.
+type podMetricsList struct {
+	Items []struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Timestamp  time.Time `json:"timestamp"`
+		Containers []struct {
+			Name  string            `json:"name"`
+			Usage map[string]string `json:"usage"`
+		} `json:"containers"`
+	} `json:"items"`
+}
+
+func (c *client) metricsServerHistory(namespace, appName string) (*History, error) {
+	raw, err := c.c.
+		CoreV1().
+		RESTClient().
+		Get().
+		AbsPath("/apis/metrics.k8s.io/v1beta1/namespaces", namespace, "pods").
+		Param("labelSelector", "serving.knative.dev/service="+appName).
+		DoRaw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metrics from metrics-server: %s", err)
+	}
+
+	return parsePodMetrics(raw)
+}
+
+// parsePodMetrics converts a PodMetricsList into a History with a single
+// sample per instance. Request rates aren't available from metrics-server.
+func parsePodMetrics(raw []byte) (*History, error) {
+	var list podMetricsList
+	if err := json.Unmarshal(raw, &list); err != nil {
+		return nil, fmt.Errorf("couldn't parse metrics-server response: %s", err)
+	}
+
+	out := &History{Source: SourceMetricsServer}
+	for _, item := range list.Items {
+		for _, container := range item.Containers {
+			if container.Name != "user-container" {
+				continue
+			}
+
+			cpu, err := resource.ParseQuantity(container.Usage["cpu"])
+			if err != nil {
+				return nil, fmt.Errorf("invalid CPU usage for %s: %s", item.Metadata.Name, err)
+			}
+
+			memory, err := resource.ParseQuantity(container.Usage["memory"])
+			if err != nil {
+				return nil, fmt.Errorf("invalid memory usage for %s: %s", item.Metadata.Name, err)
+			}
+
+			out.Instances = append(out.Instances, InstanceHistory{
+				Name:   item.Metadata.Name,
+				CPU:    []Sample{{Time: item.Timestamp, Value: float64(cpu.MilliValue()) / 1000}},
+				Memory: []Sample{{Time: item.Timestamp, Value: float64(memory.Value())}},
+			})
+		}
+	}
+
+	sort.Slice(out.Instances, func(i, j int) bool {
+		return out.Instances[i].Name < out.Instances[j].Name
+	})
+
+	return out, nil
+}
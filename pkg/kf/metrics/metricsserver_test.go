@@ -0,0 +1,86 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestParsePodMetrics(t *testing.T) {
+	timestamp := time.Date(2019, time.August, 1, 12, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		raw         string
+		expected    *History
+		expectedErr error
+	}{
+		"pods": {
+			raw: `{"items":[
+				{"metadata":{"name":"myapp-b"},"timestamp":"2019-08-01T12:00:00Z","containers":[
+					{"name":"queue-proxy","usage":{"cpu":"1","memory":"1Gi"}},
+					{"name":"user-container","usage":{"cpu":"250m","memory":"64Mi"}}
+				]},
+				{"metadata":{"name":"myapp-a"},"timestamp":"2019-08-01T12:00:00Z","containers":[
+					{"name":"user-container","usage":{"cpu":"1500m","memory":"128Mi"}}
+				]}
+			]}`,
+			expected: &History{
+				Source: SourceMetricsServer,
+				Instances: []InstanceHistory{
+					{
+						Name:   "myapp-a",
+						CPU:    []Sample{{Time: timestamp, Value: 1.5}},
+						Memory: []Sample{{Time: timestamp, Value: 128 * 1024 * 1024}},
+					},
+					{
+						Name:   "myapp-b",
+						CPU:    []Sample{{Time: timestamp, Value: 0.25}},
+						Memory: []Sample{{Time: timestamp, Value: 64 * 1024 * 1024}},
+					},
+				},
+			},
+		},
+		"no pods": {
+			raw:      `{"items":[]}`,
+			expected: &History{Source: SourceMetricsServer},
+		},
+		"bad quantity": {
+			raw: `{"items":[
+				{"metadata":{"name":"myapp-a"},"containers":[
+					{"name":"user-container","usage":{"cpu":"lots","memory":"64Mi"}}
+				]}
+			]}`,
+			expectedErr: errors.New("invalid CPU usage for myapp-a"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			actual, err := parsePodMetrics([]byte(tc.raw))
+			if tc.expectedErr != nil {
+				testutil.AssertNotNil(t, "error", err)
+				testutil.AssertErrorContainsAll(t, err, []string{tc.expectedErr.Error()})
+				return
+			}
+			testutil.AssertNil(t, "error", err)
+
+			testutil.AssertEqual(t, "history", tc.expected, actual)
+		})
+	}
+}
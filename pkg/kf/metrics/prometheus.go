@@ -0,0 +1,221 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	// historySamples is the number of samples requested from Prometheus for
+	// each series.
+	historySamples = 60
+
+	// minStep is the smallest resolution Prometheus is queried at, it matches
+	// the default scrape interval.
+	minStep = 15 * time.Second
+
+	// podLabel is the label Prometheus uses to identify pods.
+	podLabel = "pod_name"
+)
+
+// cpuQuery gets the CPU cores used by each instance of the App.
+func cpuQuery(namespace, appName string) string {
+	return fmt.Sprintf(
+		`sum by (%s) (rate(container_cpu_usage_seconds_total{namespace=%q,%s=~%q,container_name="user-container"}[1m]))`,
+		podLabel, namespace, podLabel, podPattern(appName),
+	)
+}
+
+// memoryQuery gets the working set in bytes of each instance of the App.
+func memoryQuery(namespace, appName string) string {
+	return fmt.Sprintf(
+		`sum by (%s) (container_memory_working_set_bytes{namespace=%q,%s=~%q,container_name="user-container"})`,
+		podLabel, namespace, podLabel, podPattern(appName),
+	)
+}
+
+// requestsQuery gets the requests per second served by each instance of the
+// App as reported by Knative's queue proxy.
+func requestsQuery(namespace, appName string) string {
+	return fmt.Sprintf(
+		`sum by (%s) (rate(revision_request_count{namespace_name=%q,service_name=%q}[1m]))`,
+		podLabel, namespace, appName,
+	)
+}
+
+// podPattern matches the pods Knative creates for the App's revisions.
+func podPattern(appName string) string {
+	return appName + "-.+-deployment-.+"
+}
+
+func (c *client) prometheusHistory(namespace, appName string, since time.Duration) (*History, error) {
+	end := c.now()
+	start := end.Add(-since)
+
+	step := since / historySamples
+	if step < minStep {
+		step = minStep
+	}
+
+	instances := make(map[string]*InstanceHistory)
+	instance := func(name string) *InstanceHistory {
+		if _, ok := instances[name]; !ok {
+			instances[name] = &InstanceHistory{Name: name}
+		}
+		return instances[name]
+	}
+
+	queries := []struct {
+		query  string
+		assign func(*InstanceHistory, []Sample)
+	}{
+		{
+			query:  cpuQuery(namespace, appName),
+			assign: func(i *InstanceHistory, s []Sample) { i.CPU = s },
+		},
+		{
+			query:  memoryQuery(namespace, appName),
+			assign: func(i *InstanceHistory, s []Sample) { i.Memory = s },
+		},
+		{
+			query:  requestsQuery(namespace, appName),
+			assign: func(i *InstanceHistory, s []Sample) { i.Requests = s },
+		},
+	}
+
+	for _, q := range queries {
+		raw, err := c.c.
+			CoreV1().
+			Services(PrometheusNamespace).
+			ProxyGet("http", PrometheusService, PrometheusPort, "/api/v1/query_range", map[string]string{
+				"query": q.query,
+				"start": formatPrometheusTime(start),
+				"end":   formatPrometheusTime(end),
+				"step":  fmt.Sprintf("%ds", int64(step/time.Second)),
+			}).
+			DoRaw()
+		if err != nil {
+			return nil, fmt.Errorf("failed to query Prometheus: %s", err)
+		}
+
+		series, err := parsePrometheusMatrix(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, samples := range series {
+			q.assign(instance(name), samples)
+		}
+	}
+
+	out := &History{Source: SourcePrometheus}
+	for _, i := range instances {
+		out.Instances = append(out.Instances, *i)
+	}
+
+	sort.Slice(out.Instances, func(i, j int) bool {
+		return out.Instances[i].Name < out.Instances[j].Name
+	})
+
+	return out, nil
+}
+
+func formatPrometheusTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/float64(time.Second), 'f', 3, 64)
+}
+
+// prometheusResponse is the body of a Prometheus range query response.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values [][]interface{}   `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// parsePrometheusMatrix converts a Prometheus range query response into
+// samples keyed by pod name.
+func parsePrometheusMatrix(raw []byte) (map[string][]Sample, error) {
+	var resp prometheusResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("couldn't parse Prometheus response: %s", err)
+	}
+
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("the Prometheus query failed: %s", resp.Error)
+	}
+
+	if resp.Data.ResultType != "matrix" {
+		return nil, fmt.Errorf("expected a matrix from Prometheus, got %q", resp.Data.ResultType)
+	}
+
+	out := make(map[string][]Sample)
+	for _, result := range resp.Data.Result {
+		name := result.Metric[podLabel]
+		if name == "" {
+			continue
+		}
+
+		for _, value := range result.Values {
+			sample, err := parsePrometheusValue(value)
+			if err != nil {
+				return nil, err
+			}
+
+			out[name] = append(out[name], sample)
+		}
+	}
+
+	return out, nil
+}
+
+// parsePrometheusValue parses a [timestamp, "value"] pair.
+func parsePrometheusValue(value []interface{}) (Sample, error) {
+	if len(value) != 2 {
+		return Sample{}, errors.New("expected Prometheus values to be [timestamp, value] pairs")
+	}
+
+	timestamp, ok := value[0].(float64)
+	if !ok {
+		return Sample{}, fmt.Errorf("invalid Prometheus timestamp %v", value[0])
+	}
+
+	str, ok := value[1].(string)
+	if !ok {
+		return Sample{}, fmt.Errorf("invalid Prometheus value %v", value[1])
+	}
+
+	v, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return Sample{}, fmt.Errorf("invalid Prometheus value %q", str)
+	}
+
+	sec, frac := math.Modf(timestamp)
+	return Sample{
+		Time:  time.Unix(int64(sec), int64(frac*float64(time.Second))).UTC(),
+		Value: v,
+	}, nil
+}
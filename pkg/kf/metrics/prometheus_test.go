@@ -0,0 +1,104 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestParsePrometheusMatrix(t *testing.T) {
+	cases := map[string]struct {
+		raw         string
+		expected    map[string][]Sample
+		expectedErr error
+	}{
+		"matrix": {
+			raw: `{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"pod_name":"myapp-a"},"values":[[1564650000,"0.25"],[1564650015.5,"0.5"]]},
+				{"metric":{"pod_name":"myapp-b"},"values":[[1564650000,"NaN"]]},
+				{"metric":{},"values":[[1564650000,"1"]]}
+			]}}`,
+			expected: map[string][]Sample{
+				"myapp-a": {
+					{Time: time.Unix(1564650000, 0).UTC(), Value: 0.25},
+					{Time: time.Unix(1564650015, int64(500*time.Millisecond)).UTC(), Value: 0.5},
+				},
+				"myapp-b": {
+					{Time: time.Unix(1564650000, 0).UTC(), Value: math.NaN()},
+				},
+			},
+		},
+		"query error": {
+			raw:         `{"status":"error","error":"parse error"}`,
+			expectedErr: errors.New("the Prometheus query failed: parse error"),
+		},
+		"wrong result type": {
+			raw:         `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+			expectedErr: errors.New(`expected a matrix from Prometheus, got "vector"`),
+		},
+		"bad value": {
+			raw:         `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"pod_name":"myapp-a"},"values":[[1564650000,"lots"]]}]}}`,
+			expectedErr: errors.New(`invalid Prometheus value "lots"`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			actual, err := parsePrometheusMatrix([]byte(tc.raw))
+			if tc.expectedErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.expectedErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "pods", len(tc.expected), len(actual))
+			for pod, expected := range tc.expected {
+				testutil.AssertEqual(t, pod+" samples", len(expected), len(actual[pod]))
+				for i, sample := range expected {
+					testutil.AssertEqual(t, pod+" time", sample.Time, actual[pod][i].Time)
+					if math.IsNaN(sample.Value) {
+						testutil.AssertEqual(t, pod+" NaN", true, math.IsNaN(actual[pod][i].Value))
+					} else {
+						testutil.AssertEqual(t, pod+" value", sample.Value, actual[pod][i].Value)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestQueries(t *testing.T) {
+	testutil.AssertEqual(t,
+		"cpu",
+		`sum by (pod_name) (rate(container_cpu_usage_seconds_total{namespace="dev",pod_name=~"myapp-.+-deployment-.+",container_name="user-container"}[1m]))`,
+		cpuQuery("dev", "myapp"),
+	)
+
+	testutil.AssertEqual(t,
+		"memory",
+		`sum by (pod_name) (container_memory_working_set_bytes{namespace="dev",pod_name=~"myapp-.+-deployment-.+",container_name="user-container"})`,
+		memoryQuery("dev", "myapp"),
+	)
+
+	testutil.AssertEqual(t,
+		"requests",
+		`sum by (pod_name) (rate(revision_request_count{namespace_name="dev",service_name="myapp"}[1m]))`,
+		requestsQuery("dev", "myapp"),
+	)
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"math"
+	"strings"
+)
+
+// sparks are the characters used to draw sparklines from lowest to highest.
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// Sparkline renders the samples as a string of block characters scaled
+// between the lowest and highest values. Missing values are drawn as spaces.
+func Sparkline(samples []Sample) string {
+	stats, ok := Summarize(samples)
+	if !ok {
+		return ""
+	}
+
+	var sb strings.Builder
+	for _, s := range samples {
+		if math.IsNaN(s.Value) {
+			sb.WriteRune(' ')
+			continue
+		}
+
+		idx := 0
+		if spread := stats.Max - stats.Min; spread > 0 {
+			idx = int((s.Value - stats.Min) / spread * float64(len(sparks)-1))
+		}
+
+		sb.WriteRune(sparks[idx])
+	}
+
+	return sb.String()
+}
+
+// Stats summarizes a series of samples.
+type Stats struct {
+	Min     float64
+	Max     float64
+	Average float64
+	Latest  float64
+}
+
+// Summarize computes statistics over the samples, ignoring missing values.
+// False is returned if there are no values.
+func Summarize(samples []Sample) (Stats, bool) {
+	var (
+		stats Stats
+		sum   float64
+		count int
+	)
+
+	for _, s := range samples {
+		if math.IsNaN(s.Value) {
+			continue
+		}
+
+		if count == 0 || s.Value < stats.Min {
+			stats.Min = s.Value
+		}
+
+		if count == 0 || s.Value > stats.Max {
+			stats.Max = s.Value
+		}
+
+		sum += s.Value
+		count++
+		stats.Latest = s.Value
+	}
+
+	if count == 0 {
+		return Stats{}, false
+	}
+
+	stats.Average = sum / float64(count)
+	return stats, true
+}
@@ -0,0 +1,92 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func samples(values ...float64) []Sample {
+	var out []Sample
+	for _, v := range values {
+		out = append(out, Sample{Value: v})
+	}
+	return out
+}
+
+func ExampleSparkline() {
+	fmt.Println(Sparkline(samples(1, 2, 3, 4, 5, 6, 7, 8)))
+
+	// Output: ▁▂▃▄▅▆▇█
+}
+
+func TestSparkline(t *testing.T) {
+	cases := map[string]struct {
+		samples  []Sample
+		expected string
+	}{
+		"empty": {
+			samples:  nil,
+			expected: "",
+		},
+		"flat": {
+			samples:  samples(3, 3, 3),
+			expected: "▁▁▁",
+		},
+		"missing values": {
+			samples:  samples(0, math.NaN(), 10),
+			expected: "▁ █",
+		},
+		"all missing": {
+			samples:  samples(math.NaN(), math.NaN()),
+			expected: "",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "sparkline", tc.expected, Sparkline(tc.samples))
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	cases := map[string]struct {
+		samples    []Sample
+		expected   Stats
+		expectedOk bool
+	}{
+		"empty": {
+			samples: nil,
+		},
+		"values": {
+			samples:    samples(4, 1, math.NaN(), 7, 2),
+			expected:   Stats{Min: 1, Max: 7, Average: 3.5, Latest: 2},
+			expectedOk: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			actual, ok := Summarize(tc.samples)
+			testutil.AssertEqual(t, "ok", tc.expectedOk, ok)
+			testutil.AssertEqual(t, "stats", tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// UserProvidedLabel is the VCAP_SERVICES label of user-provided services.
+const UserProvidedLabel = "user-provided"
+
+// CFEnv is the environment of a CF app as returned by
+// `cf curl /v2/apps/GUID/env` or `cf curl /v3/apps/GUID/env`.
+type CFEnv struct {
+	// EnvironmentJSON holds user environment variables in the v2 API.
+	EnvironmentJSON map[string]interface{} `json:"environment_json,omitempty"`
+	// EnvironmentVariables holds user environment variables in the v3 API.
+	EnvironmentVariables map[string]interface{} `json:"environment_variables,omitempty"`
+
+	SystemEnvJSON      CFSystemEnv            `json:"system_env_json,omitempty"`
+	ApplicationEnvJSON CFApplicationEnv       `json:"application_env_json,omitempty"`
+	StagingEnvJSON     map[string]interface{} `json:"staging_env_json,omitempty"`
+	RunningEnvJSON     map[string]interface{} `json:"running_env_json,omitempty"`
+}
+
+// CFSystemEnv holds the environment variables CF provides.
+type CFSystemEnv struct {
+	VCAPServices map[string][]CFService `json:"VCAP_SERVICES,omitempty"`
+}
+
+// CFService is a single service binding in VCAP_SERVICES.
+type CFService struct {
+	Name         string   `json:"name"`
+	InstanceName string   `json:"instance_name,omitempty"`
+	BindingName  string   `json:"binding_name,omitempty"`
+	Label        string   `json:"label"`
+	Plan         string   `json:"plan,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+}
+
+// Instance returns the name of the service instance the binding is for.
+func (s *CFService) Instance() string {
+	if s.InstanceName != "" {
+		return s.InstanceName
+	}
+
+	return s.Name
+}
+
+// CFApplicationEnv holds the application specific environment variables.
+type CFApplicationEnv struct {
+	VCAPApplication CFApplication `json:"VCAP_APPLICATION,omitempty"`
+}
+
+// CFApplication is the subset of VCAP_APPLICATION used for migration.
+type CFApplication struct {
+	ApplicationName string `json:"application_name,omitempty"`
+	Name            string `json:"name,omitempty"`
+}
+
+// AppName returns the name of the app the environment belongs to if it's
+// known.
+func (e *CFEnv) AppName() string {
+	if name := e.ApplicationEnvJSON.VCAPApplication.ApplicationName; name != "" {
+		return name
+	}
+
+	return e.ApplicationEnvJSON.VCAPApplication.Name
+}
+
+// UserEnv returns the environment variables set by the user. Non-string
+// values are encoded as JSON the same way CF exposes them to apps.
+func (e *CFEnv) UserEnv() (map[string]string, error) {
+	out := make(map[string]string)
+	for _, vars := range []map[string]interface{}{e.EnvironmentJSON, e.EnvironmentVariables} {
+		for name, value := range vars {
+			if s, ok := value.(string); ok {
+				out[name] = s
+				continue
+			}
+
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't encode environment variable %s: %s", name, err)
+			}
+			out[name] = string(encoded)
+		}
+	}
+
+	return out, nil
+}
+
+// ParseCFEnv reads a CFEnv from JSON.
+func ParseCFEnv(r io.Reader) (*CFEnv, error) {
+	env := &CFEnv{}
+	if err := json.NewDecoder(r).Decode(env); err != nil {
+		return nil, fmt.Errorf("couldn't parse CF environment: %s", err)
+	}
+
+	return env, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestParseCFEnv(t *testing.T) {
+	cases := map[string]struct {
+		json            string
+		expectedErr     error
+		expectedAppName string
+		expectedEnv     map[string]string
+	}{
+		"v2": {
+			json: `{
+				"environment_json": {"GREETING": "hello", "DEBUG": true},
+				"application_env_json": {"VCAP_APPLICATION": {"application_name": "my-app"}}
+			}`,
+			expectedAppName: "my-app",
+			expectedEnv:     map[string]string{"GREETING": "hello", "DEBUG": "true"},
+		},
+		"v3": {
+			json: `{
+				"environment_variables": {"GREETING": "hello"},
+				"application_env_json": {"VCAP_APPLICATION": {"name": "my-app"}}
+			}`,
+			expectedAppName: "my-app",
+			expectedEnv:     map[string]string{"GREETING": "hello"},
+		},
+		"empty": {
+			json:        `{}`,
+			expectedEnv: map[string]string{},
+		},
+		"invalid": {
+			json:        `{`,
+			expectedErr: errors.New("couldn't parse CF environment: unexpected EOF"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			env, err := ParseCFEnv(strings.NewReader(tc.json))
+			if tc.expectedErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.expectedErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "app name", tc.expectedAppName, env.AppName())
+
+			userEnv, err := env.UserEnv()
+			testutil.AssertNil(t, "err", err)
+			testutil.AssertEqual(t, "env", tc.expectedEnv, userEnv)
+		})
+	}
+}
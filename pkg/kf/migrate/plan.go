@@ -0,0 +1,338 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/google/kf/pkg/internal/shellutil"
+	"github.com/google/kf/pkg/kf/algorithms"
+	"github.com/google/kf/pkg/kf/manifest"
+	"sigs.k8s.io/yaml"
+)
+
+// ServiceInstance is a service instance that needs to be created in Kf.
+type ServiceInstance struct {
+	Name    string
+	Service string
+	Plan    string
+}
+
+// Binding is a service binding that can't be expressed in the manifest
+// because it has a custom name.
+type Binding struct {
+	App         string
+	Instance    string
+	BindingName string
+}
+
+// Plan holds everything needed to recreate a set of CF apps on Kf.
+type Plan struct {
+	// Manifest is the Kf manifest to push the apps with.
+	Manifest *manifest.Manifest
+
+	// Services are the service instances to create before pushing.
+	Services []ServiceInstance
+
+	// Bindings are created after pushing.
+	Bindings []Binding
+
+	// Warnings describe CF features that weren't migrated.
+	Warnings []string
+}
+
+func (p *Plan) warnf(format string, args ...interface{}) {
+	p.Warnings = append(p.Warnings, fmt.Sprintf(format, args...))
+}
+
+// FromCF creates a Plan from the raw contents of a CF manifest and the
+// exported environments of its apps.
+func FromCF(rawManifest []byte, envs []*CFEnv) (*Plan, error) {
+	plan := &Plan{}
+
+	m := &manifest.Manifest{}
+	if err := yaml.Unmarshal(rawManifest, m); err != nil {
+		return nil, fmt.Errorf("couldn't parse manifest: %s", err)
+	}
+	plan.Manifest = m
+
+	if strings.Contains(string(rawManifest), "((") {
+		plan.warnf("the manifest appears to use variables, substitute them with their values before pushing")
+	}
+
+	if err := plan.checkUnknownFields(rawManifest); err != nil {
+		return nil, err
+	}
+
+	envsByApp := make(map[string]*CFEnv)
+	for _, env := range envs {
+		name := env.AppName()
+		if name == "" {
+			if len(m.Applications) != 1 {
+				return nil, fmt.Errorf("couldn't tell which app a CF environment belongs to, it doesn't contain VCAP_APPLICATION")
+			}
+			name = m.Applications[0].Name
+		}
+
+		if _, err := m.App(name); err != nil {
+			return nil, fmt.Errorf("the CF environment for %s doesn't match an app in the manifest", name)
+		}
+		envsByApp[name] = env
+	}
+
+	services := make(map[string]ServiceInstance)
+	for i := range m.Applications {
+		app := &m.Applications[i]
+		plan.checkApp(app)
+
+		if env, ok := envsByApp[app.Name]; ok {
+			if err := plan.applyEnv(app, env, services); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, instance := range app.Services {
+			if _, ok := services[instance]; !ok {
+				plan.warnf("app %s uses service %s which isn't in its CF environment, create it before pushing", app.Name, instance)
+			}
+		}
+	}
+
+	for _, instance := range services {
+		plan.Services = append(plan.Services, instance)
+	}
+	sort.Slice(plan.Services, func(i, j int) bool {
+		return plan.Services[i].Name < plan.Services[j].Name
+	})
+
+	return plan, nil
+}
+
+// checkApp flags and removes manifest settings Kf doesn't support.
+func (p *Plan) checkApp(app *manifest.Application) {
+	switch app.HealthCheckType {
+	case "process", "none":
+		p.warnf("app %s uses the %s health check type which Kf doesn't support, a port check will be used", app.Name, app.HealthCheckType)
+		app.HealthCheckType = ""
+	}
+
+	var routes []manifest.Route
+	for _, route := range app.Routes {
+		if strings.Contains(route.Route, ":") {
+			p.warnf("app %s has TCP route %s which Kf doesn't support, it was removed", app.Name, route.Route)
+			continue
+		}
+		routes = append(routes, route)
+	}
+	app.Routes = routes
+}
+
+// applyEnv copies user environment variables and service bindings from the
+// CF environment into the app.
+func (p *Plan) applyEnv(app *manifest.Application, env *CFEnv, services map[string]ServiceInstance) error {
+	userEnv, err := env.UserEnv()
+	if err != nil {
+		return err
+	}
+
+	if len(userEnv) > 0 && app.Env == nil {
+		app.Env = make(map[string]string)
+	}
+
+	// The exported environment reflects the current state of the app,
+	// including variables set with set-env, so it takes precedence.
+	for name, value := range userEnv {
+		app.Env[name] = value
+	}
+
+	if len(env.StagingEnvJSON) > 0 || len(env.RunningEnvJSON) > 0 {
+		p.warnf("app %s gets variables from CF environment variable groups which Kf doesn't support, add them to the app's env if they're needed", app.Name)
+	}
+
+	labels := make([]string, 0, len(env.SystemEnvJSON.VCAPServices))
+	for label := range env.SystemEnvJSON.VCAPServices {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+
+	for _, label := range labels {
+		for _, binding := range env.SystemEnvJSON.VCAPServices[label] {
+			instance := binding.Instance()
+
+			if binding.Label == UserProvidedLabel || label == UserProvidedLabel {
+				p.warnf("app %s is bound to user-provided service %s which Kf doesn't support, set its credentials as environment variables instead", app.Name, instance)
+				app.Services = algorithms.Delete(app.Services, []string{instance}, algorithms.Ordered[string])
+				continue
+			}
+
+			services[instance] = ServiceInstance{
+				Name:    instance,
+				Service: label,
+				Plan:    binding.Plan,
+			}
+
+			if binding.BindingName != "" && binding.BindingName != instance {
+				app.Services = algorithms.Delete(app.Services, []string{instance}, algorithms.Ordered[string])
+				p.Bindings = append(p.Bindings, Binding{
+					App:         app.Name,
+					Instance:    instance,
+					BindingName: binding.BindingName,
+				})
+			} else if !algorithms.Contains(app.Services, instance, algorithms.Ordered[string]) {
+				app.Services = append(app.Services, instance)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkUnknownFields flags manifest fields Kf doesn't understand.
+func (p *Plan) checkUnknownFields(rawManifest []byte) error {
+	var raw struct {
+		Applications []map[string]interface{} `json:"applications"`
+	}
+	if err := yaml.Unmarshal(rawManifest, &raw); err != nil {
+		return fmt.Errorf("couldn't parse manifest: %s", err)
+	}
+
+	var topLevel map[string]interface{}
+	if err := yaml.Unmarshal(rawManifest, &topLevel); err != nil {
+		return fmt.Errorf("couldn't parse manifest: %s", err)
+	}
+
+	var unknown []string
+	for key := range topLevel {
+		if key != "applications" {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, key := range unknown {
+		p.warnf("manifest field %s isn't supported by Kf and was ignored", key)
+	}
+
+	known := jsonFields(reflect.TypeOf(manifest.Application{}))
+	for _, app := range raw.Applications {
+		name, _ := app["name"].(string)
+
+		var unknownFields []string
+		for key := range app {
+			if !known[key] {
+				unknownFields = append(unknownFields, key)
+			}
+		}
+		sort.Strings(unknownFields)
+
+		for _, key := range unknownFields {
+			p.warnf("app %s uses manifest field %s which isn't supported by Kf and was ignored", name, key)
+		}
+	}
+
+	return nil
+}
+
+// RebasePaths rewrites app paths that are relative to manifestDir so they're
+// relative to outputDir instead. Apps without a path or Docker image get the
+// manifest's directory the same way CF treats them.
+func (p *Plan) RebasePaths(manifestDir, outputDir string) error {
+	for i := range p.Manifest.Applications {
+		app := &p.Manifest.Applications[i]
+		if app.Docker.Image != "" {
+			continue
+		}
+
+		src := app.Path
+		if !filepath.IsAbs(src) {
+			src = filepath.Join(manifestDir, src)
+		}
+
+		rel, err := filepath.Rel(outputDir, src)
+		if err != nil {
+			return fmt.Errorf("couldn't find the path of app %s: %s", app.Name, err)
+		}
+		app.Path = filepath.ToSlash(rel)
+	}
+
+	return nil
+}
+
+// WriteScript writes a shell script that creates the services, pushes the
+// apps using the manifest at manifestPath and creates any extra bindings. The
+// script runs from its own directory so paths are relative to it.
+func (p *Plan) WriteScript(w io.Writer, manifestPath string) {
+	fmt.Fprintln(w, "#!/usr/bin/env bash")
+	fmt.Fprintln(w, "# Generated by `kf migrate from-cf`, review it before running.")
+	fmt.Fprintln(w, "set -euo pipefail")
+	fmt.Fprintln(w, `cd "$(dirname "$0")"`)
+
+	if len(p.Warnings) > 0 {
+		fmt.Fprintln(w)
+		for _, warning := range p.Warnings {
+			fmt.Fprintf(w, "# WARNING: %s\n", warning)
+		}
+	}
+
+	if len(p.Services) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "# Create services")
+		for _, s := range p.Services {
+			fmt.Fprintln(w, shellutil.Command("kf", "create-service", s.Service, s.Plan, s.Name))
+		}
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "# Push apps")
+	for _, app := range p.Manifest.Applications {
+		fmt.Fprintln(w, shellutil.Command("kf", "push", app.Name, "--manifest", manifestPath))
+	}
+
+	if len(p.Bindings) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "# Bind services with custom binding names")
+		for _, b := range p.Bindings {
+			fmt.Fprintln(w, shellutil.Command("kf", "bind-service", b.App, b.Instance, "--binding-name", b.BindingName))
+		}
+	}
+}
+
+// jsonFields returns the JSON field names of a struct including inlined
+// structs.
+func jsonFields(t reflect.Type) map[string]bool {
+	out := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if name == "" && field.Type.Kind() == reflect.Struct {
+			for k := range jsonFields(field.Type) {
+				out[k] = true
+			}
+			continue
+		}
+
+		if name != "" && name != "-" {
+			out[name] = true
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,208 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package migrate
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/manifest"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestFromCF(t *testing.T) {
+	cases := map[string]struct {
+		manifest         string
+		envs             []*CFEnv
+		expectedErr      error
+		expectedApps     []manifest.Application
+		expectedServices []ServiceInstance
+		expectedBindings []Binding
+		expectedWarnings []string
+	}{
+		"minimal": {
+			manifest: `
+applications:
+- name: my-app
+`,
+			expectedApps: []manifest.Application{{Name: "my-app"}},
+		},
+		"invalid manifest": {
+			manifest:    `applications: {`,
+			expectedErr: errors.New("couldn't parse manifest: error converting YAML to JSON: yaml: line 1: did not find expected node content"),
+		},
+		"env and services": {
+			manifest: `
+applications:
+- name: my-app
+  env:
+    GREETING: hi
+    KEEP: me
+  services:
+  - my-db
+`,
+			envs: []*CFEnv{{
+				EnvironmentJSON: map[string]interface{}{"GREETING": "hello"},
+				SystemEnvJSON: CFSystemEnv{
+					VCAPServices: map[string][]CFService{
+						"cleardb": {{Name: "my-db", Label: "cleardb", Plan: "spark"}},
+						"redis":   {{Name: "my-cache", Label: "redis", Plan: "small", BindingName: "cache"}},
+					},
+				},
+			}},
+			expectedApps: []manifest.Application{{
+				Name:     "my-app",
+				Env:      map[string]string{"GREETING": "hello", "KEEP": "me"},
+				Services: []string{"my-db"},
+			}},
+			expectedServices: []ServiceInstance{
+				{Name: "my-cache", Service: "redis", Plan: "small"},
+				{Name: "my-db", Service: "cleardb", Plan: "spark"},
+			},
+			expectedBindings: []Binding{
+				{App: "my-app", Instance: "my-cache", BindingName: "cache"},
+			},
+		},
+		"env for missing app": {
+			manifest: `
+applications:
+- name: my-app
+`,
+			envs: []*CFEnv{{
+				ApplicationEnvJSON: CFApplicationEnv{VCAPApplication: CFApplication{ApplicationName: "other-app"}},
+			}},
+			expectedErr: errors.New("the CF environment for other-app doesn't match an app in the manifest"),
+		},
+		"ambiguous env": {
+			manifest: `
+applications:
+- name: app-a
+- name: app-b
+`,
+			envs:        []*CFEnv{{}},
+			expectedErr: errors.New("couldn't tell which app a CF environment belongs to, it doesn't contain VCAP_APPLICATION"),
+		},
+		"unsupported features": {
+			manifest: `
+version: 1
+applications:
+- name: my-app
+  health-check-type: process
+  sidecars:
+  - name: logger
+  routes:
+  - route: my-app.example.com
+  - route: tcp.example.com:1024
+  services:
+  - missing
+  - ups
+  memory: ((memory))
+`,
+			envs: []*CFEnv{{
+				RunningEnvJSON: map[string]interface{}{"GROUP": "1"},
+				SystemEnvJSON: CFSystemEnv{
+					VCAPServices: map[string][]CFService{
+						"user-provided": {{Name: "ups", Label: "user-provided"}},
+					},
+				},
+			}},
+			expectedApps: []manifest.Application{{
+				Name:     "my-app",
+				Memory:   "((memory))",
+				Routes:   []manifest.Route{{Route: "my-app.example.com"}},
+				Services: []string{"missing"},
+			}},
+			expectedWarnings: []string{
+				"the manifest appears to use variables, substitute them with their values before pushing",
+				"manifest field version isn't supported by Kf and was ignored",
+				"app my-app uses manifest field sidecars which isn't supported by Kf and was ignored",
+				"app my-app uses the process health check type which Kf doesn't support, a port check will be used",
+				"app my-app has TCP route tcp.example.com:1024 which Kf doesn't support, it was removed",
+				"app my-app gets variables from CF environment variable groups which Kf doesn't support, add them to the app's env if they're needed",
+				"app my-app is bound to user-provided service ups which Kf doesn't support, set its credentials as environment variables instead",
+				"app my-app uses service missing which isn't in its CF environment, create it before pushing",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			plan, err := FromCF([]byte(tc.manifest), tc.envs)
+			if tc.expectedErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.expectedErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "apps", tc.expectedApps, plan.Manifest.Applications)
+			testutil.AssertEqual(t, "services", tc.expectedServices, plan.Services)
+			testutil.AssertEqual(t, "bindings", tc.expectedBindings, plan.Bindings)
+			testutil.AssertEqual(t, "warnings", tc.expectedWarnings, plan.Warnings)
+		})
+	}
+}
+
+func TestPlan_RebasePaths(t *testing.T) {
+	plan := &Plan{
+		Manifest: &manifest.Manifest{
+			Applications: []manifest.Application{
+				{Name: "no-path"},
+				{Name: "relative", Path: "src"},
+				{Name: "absolute", Path: "/abs/src"},
+				{Name: "docker", Docker: manifest.AppDockerImage{Image: "nginx"}},
+			},
+		},
+	}
+
+	testutil.AssertNil(t, "err", plan.RebasePaths("/work/cf", "/work/kf"))
+
+	var paths []string
+	for _, app := range plan.Manifest.Applications {
+		paths = append(paths, app.Path)
+	}
+	testutil.AssertEqual(t, "paths", []string{"../cf", "../cf/src", "../../abs/src", ""}, paths)
+}
+
+func TestPlan_WriteScript(t *testing.T) {
+	plan := &Plan{
+		Manifest: &manifest.Manifest{
+			Applications: []manifest.Application{{Name: "my-app"}},
+		},
+		Services: []ServiceInstance{{Name: "my db", Service: "cleardb", Plan: "spark"}},
+		Bindings: []Binding{{App: "my-app", Instance: "my db", BindingName: "db"}},
+		Warnings: []string{"something wasn't migrated"},
+	}
+
+	buffer := &bytes.Buffer{}
+	plan.WriteScript(buffer, "manifest.yml")
+
+	expected := `#!/usr/bin/env bash
+# Generated by ` + "`kf migrate from-cf`" + `, review it before running.
+set -euo pipefail
+cd "$(dirname "$0")"
+
+# WARNING: something wasn't migrated
+
+# Create services
+kf create-service cleardb spark 'my db'
+
+# Push apps
+kf push my-app --manifest manifest.yml
+
+# Bind services with custom binding names
+kf bind-service my-app 'my db' --binding-name db
+`
+	testutil.AssertEqual(t, "script", expected, buffer.String())
+}
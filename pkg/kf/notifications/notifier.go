@@ -0,0 +1,218 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"knative.dev/pkg/apis"
+)
+
+// DefaultTimeout is how long a webhook has to respond before the
+// notification is dropped.
+const DefaultTimeout = 10 * time.Second
+
+// Payload is the JSON body posted to webhooks.
+type Payload struct {
+	// Text summarizes the event. It's named so Slack incoming webhooks can
+	// display it.
+	Text string `json:"text"`
+
+	// Event is the name of the event, e.g. build-failed.
+	Event string `json:"event"`
+
+	// Space is the space the event happened in.
+	Space string `json:"space"`
+
+	// App is the App the event happened to, if any.
+	App string `json:"app,omitempty"`
+
+	// Source is the Source that was built.
+	Source string `json:"source,omitempty"`
+
+	// Image is the container image that was built or is running.
+	Image string `json:"image,omitempty"`
+
+	// Reason is a machine readable reason for failures.
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human readable description of failures.
+	Message string `json:"message,omitempty"`
+
+	// Time is when the event was seen.
+	Time time.Time `json:"time"`
+}
+
+// ForSource gets the payload to send when the Succeeded condition of a Source
+// became final between before and after. It returns nil if there's nothing
+// to send.
+func ForSource(before, after *v1alpha1.Source, now time.Time) *Payload {
+	cond, changed := transition(
+		before.Status.GetCondition(v1alpha1.SourceConditionSucceeded),
+		after.Status.GetCondition(v1alpha1.SourceConditionSucceeded),
+	)
+	if !changed {
+		return nil
+	}
+
+	payload := &Payload{
+		Space:  after.Namespace,
+		App:    after.Labels[v1alpha1.NameLabel],
+		Source: after.Name,
+		Time:   now,
+	}
+
+	subject := fmt.Sprintf("Build %s", after.Name)
+	if payload.App != "" {
+		subject = fmt.Sprintf("Build %s of App %s", after.Name, payload.App)
+	}
+
+	if cond.IsTrue() {
+		payload.Event = v1alpha1.NotificationEventBuildSucceeded
+		payload.Image = after.Status.Image
+		payload.Text = fmt.Sprintf("%s in space %s succeeded", subject, after.Namespace)
+	} else {
+		payload.Event = v1alpha1.NotificationEventBuildFailed
+		payload.Reason = cond.Reason
+		payload.Message = cond.Message
+		payload.Text = fmt.Sprintf("%s in space %s failed: %s", subject, after.Namespace, cond.Message)
+	}
+
+	return payload
+}
+
+// ForApp gets the payload to send when the Ready condition of an App became
+// True or False between before and after. It returns nil if there's nothing
+// to send.
+func ForApp(before, after *v1alpha1.App, now time.Time) *Payload {
+	cond, changed := transition(
+		before.Status.GetCondition(v1alpha1.AppConditionReady),
+		after.Status.GetCondition(v1alpha1.AppConditionReady),
+	)
+	if !changed {
+		return nil
+	}
+
+	payload := &Payload{
+		Space:  after.Namespace,
+		App:    after.Name,
+		Source: after.Status.LatestReadySourceName,
+		Image:  after.Status.Image,
+		Time:   now,
+	}
+
+	if cond.IsTrue() {
+		payload.Event = v1alpha1.NotificationEventPushComplete
+		payload.Text = fmt.Sprintf("App %s in space %s is ready", after.Name, after.Namespace)
+	} else {
+		payload.Event = v1alpha1.NotificationEventPushFailed
+		payload.Reason = cond.Reason
+		payload.Message = cond.Message
+		payload.Text = fmt.Sprintf("App %s in space %s failed to become ready: %s", after.Name, after.Namespace, cond.Message)
+	}
+
+	return payload
+}
+
+// transition returns the after condition and true if it's now True or False
+// and wasn't already.
+func transition(before, after *apis.Condition) (*apis.Condition, bool) {
+	if after == nil || after.IsUnknown() {
+		return nil, false
+	}
+
+	if before != nil && before.Status == after.Status {
+		return nil, false
+	}
+
+	return after, true
+}
+
+// Notifier posts events to webhooks.
+type Notifier interface {
+	// Notify posts the payload to each of the space's webhooks subscribed to
+	// the payload's event.
+	Notify(ctx context.Context, space *v1alpha1.Space, payload Payload) error
+}
+
+type notifier struct {
+	client *http.Client
+}
+
+// NewNotifier creates a Notifier that posts with the given client.
+func NewNotifier(client *http.Client) Notifier {
+	return &notifier{client: client}
+}
+
+// Notify implements Notifier.
+func (n *notifier) Notify(ctx context.Context, space *v1alpha1.Space, payload Payload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, notification := range space.Spec.Notifications {
+		if !notification.Subscribed(payload.Event) {
+			continue
+		}
+
+		if err := n.post(ctx, notification.Webhook, body); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func (n *notifier) post(ctx context.Context, webhook string, body []byte) error {
+	// Webhook URLs often embed credentials, e.g. Slack's, so only the host is
+	// included in errors which end up in logs.
+	host := webhook
+	if u, err := url.Parse(webhook); err == nil {
+		host = u.Host
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("couldn't create request for webhook on %s", host)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("couldn't reach webhook on %s", host)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook on %s responded with %s", host, resp.Status)
+	}
+
+	return nil
+}
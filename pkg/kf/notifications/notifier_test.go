@@ -0,0 +1,227 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+)
+
+var now = time.Date(2019, time.August, 1, 12, 0, 0, 0, time.UTC)
+
+func withCondition(t apis.ConditionType, status corev1.ConditionStatus) duckv1beta1.Status {
+	return duckv1beta1.Status{
+		Conditions: duckv1beta1.Conditions{{
+			Type:    t,
+			Status:  status,
+			Reason:  "SomeReason",
+			Message: "some message",
+		}},
+	}
+}
+
+func TestForSource(t *testing.T) {
+	t.Parallel()
+
+	source := func(status corev1.ConditionStatus) *v1alpha1.Source {
+		return &v1alpha1.Source{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myapp-abc",
+				Namespace: "my-space",
+				Labels:    map[string]string{v1alpha1.NameLabel: "myapp"},
+			},
+			Status: v1alpha1.SourceStatus{
+				Status:             withCondition(apis.ConditionSucceeded, status),
+				SourceStatusFields: v1alpha1.SourceStatusFields{Image: "gcr.io/my-app"},
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		before *v1alpha1.Source
+		after  *v1alpha1.Source
+		want   *Payload
+	}{
+		"build succeeded": {
+			before: source(corev1.ConditionUnknown),
+			after:  source(corev1.ConditionTrue),
+			want: &Payload{
+				Text:   "Build myapp-abc of App myapp in space my-space succeeded",
+				Event:  v1alpha1.NotificationEventBuildSucceeded,
+				Space:  "my-space",
+				App:    "myapp",
+				Source: "myapp-abc",
+				Image:  "gcr.io/my-app",
+				Time:   now,
+			},
+		},
+		"build failed": {
+			before: &v1alpha1.Source{},
+			after:  source(corev1.ConditionFalse),
+			want: &Payload{
+				Text:    "Build myapp-abc of App myapp in space my-space failed: some message",
+				Event:   v1alpha1.NotificationEventBuildFailed,
+				Space:   "my-space",
+				App:     "myapp",
+				Source:  "myapp-abc",
+				Reason:  "SomeReason",
+				Message: "some message",
+				Time:    now,
+			},
+		},
+		"still building": {
+			before: source(corev1.ConditionUnknown),
+			after:  source(corev1.ConditionUnknown),
+		},
+		"already failed": {
+			before: source(corev1.ConditionFalse),
+			after:  source(corev1.ConditionFalse),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "payload", tc.want, ForSource(tc.before, tc.after, now))
+		})
+	}
+}
+
+func TestForApp(t *testing.T) {
+	t.Parallel()
+
+	app := func(status corev1.ConditionStatus) *v1alpha1.App {
+		return &v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myapp",
+				Namespace: "my-space",
+			},
+			Status: v1alpha1.AppStatus{
+				Status:                withCondition(apis.ConditionReady, status),
+				SourceStatusFields:    v1alpha1.SourceStatusFields{Image: "gcr.io/my-app"},
+				LatestReadySourceName: "myapp-abc",
+			},
+		}
+	}
+
+	cases := map[string]struct {
+		before *v1alpha1.App
+		after  *v1alpha1.App
+		want   *Payload
+	}{
+		"push complete": {
+			before: app(corev1.ConditionUnknown),
+			after:  app(corev1.ConditionTrue),
+			want: &Payload{
+				Text:   "App myapp in space my-space is ready",
+				Event:  v1alpha1.NotificationEventPushComplete,
+				Space:  "my-space",
+				App:    "myapp",
+				Source: "myapp-abc",
+				Image:  "gcr.io/my-app",
+				Time:   now,
+			},
+		},
+		"push failed": {
+			before: app(corev1.ConditionTrue),
+			after:  app(corev1.ConditionFalse),
+			want: &Payload{
+				Text:    "App myapp in space my-space failed to become ready: some message",
+				Event:   v1alpha1.NotificationEventPushFailed,
+				Space:   "my-space",
+				App:     "myapp",
+				Source:  "myapp-abc",
+				Image:   "gcr.io/my-app",
+				Reason:  "SomeReason",
+				Message: "some message",
+				Time:    now,
+			},
+		},
+		"still ready": {
+			before: app(corev1.ConditionTrue),
+			after:  app(corev1.ConditionTrue),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "payload", tc.want, ForApp(tc.before, tc.after, now))
+		})
+	}
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu       sync.Mutex
+		received []Payload
+	)
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload Payload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("couldn't decode payload: %s", err)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("expected Content-Type application/json, got %q", ct)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, payload)
+	}))
+	defer ok.Close()
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer failing.Close()
+
+	space := &v1alpha1.Space{
+		Spec: v1alpha1.SpaceSpec{
+			Notifications: []v1alpha1.SpaceNotification{
+				{Webhook: ok.URL + "/builds", Events: []string{v1alpha1.NotificationEventBuildFailed}},
+				{Webhook: ok.URL + "/pushes", Events: []string{v1alpha1.NotificationEventPushComplete}},
+			},
+		},
+	}
+
+	payload := Payload{Event: v1alpha1.NotificationEventBuildFailed, Space: "my-space", Time: now}
+
+	notifier := NewNotifier(&http.Client{Timeout: DefaultTimeout})
+	testutil.AssertNil(t, "notify err", notifier.Notify(context.Background(), space, payload))
+	testutil.AssertEqual(t, "received", []Payload{payload}, received)
+
+	space.Spec.Notifications = append(space.Spec.Notifications, v1alpha1.SpaceNotification{
+		Webhook: failing.URL + "/secret-token",
+		Events:  []string{v1alpha1.NotificationEventBuildFailed},
+	})
+
+	err := notifier.Notify(context.Background(), space, payload)
+	testutil.AssertErrorsEqual(t, errors.New("webhook on "+failing.Listener.Addr().String()+" responded with 403 Forbidden"), err)
+	testutil.AssertEqual(t, "received", []Payload{payload, payload}, received)
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+)
+
+// FileName is the name of the Procfile in the root of an App's source.
+const FileName = "Procfile"
+
+// Parse reads the process types in a Procfile in the order they're defined.
+// Blank lines and lines starting with # are ignored.
+func Parse(r io.Reader) ([]v1alpha1.AppSpecProcess, error) {
+	var processes []v1alpha1.AppSpecProcess
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(text, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("line %d: expected TYPE: COMMAND, got %q", line, text)
+		}
+
+		processType := strings.TrimSpace(parts[0])
+		command := strings.TrimSpace(parts[1])
+		switch {
+		case processType == "":
+			return nil, fmt.Errorf("line %d: the process type is empty", line)
+		case command == "":
+			return nil, fmt.Errorf("line %d: the %s process doesn't have a command", line, processType)
+		case seen[processType]:
+			return nil, fmt.Errorf("line %d: the %s process is defined more than once", line, processType)
+		}
+		seen[processType] = true
+
+		processes = append(processes, v1alpha1.AppSpecProcess{
+			Type:    processType,
+			Command: command,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return processes, nil
+}
+
+// Read parses the Procfile in dir. It returns nil if dir doesn't have one.
+func Read(dir string) ([]v1alpha1.AppSpecProcess, error) {
+	f, err := os.Open(filepath.Join(dir, FileName))
+	switch {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+	defer f.Close()
+
+	processes, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", FileName, err)
+	}
+
+	return processes, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package procfile_test
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/procfile"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func ExampleParse() {
+	processes, err := procfile.Parse(strings.NewReader(`
+# Processes for the store
+web: bundle exec puma -C config/puma.rb
+worker: bundle exec sidekiq
+`))
+	if err != nil {
+		panic(err)
+	}
+
+	for _, process := range processes {
+		fmt.Printf("%s runs %q\n", process.Type, process.Command)
+	}
+
+	// Output: web runs "bundle exec puma -C config/puma.rb"
+	// worker runs "bundle exec sidekiq"
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		procfile string
+		want     []v1alpha1.AppSpecProcess
+		wantErr  error
+	}{
+		"empty": {},
+		"command with colons": {
+			procfile: "web: python -m http.server --bind ::\n",
+			want: []v1alpha1.AppSpecProcess{
+				{Type: "web", Command: "python -m http.server --bind ::"},
+			},
+		},
+		"missing separator": {
+			procfile: "web: ./start.sh\nworker ./work.sh\n",
+			wantErr:  errors.New(`line 2: expected TYPE: COMMAND, got "worker ./work.sh"`),
+		},
+		"missing type": {
+			procfile: ": ./start.sh",
+			wantErr:  errors.New("line 1: the process type is empty"),
+		},
+		"missing command": {
+			procfile: "web:",
+			wantErr:  errors.New("line 1: the web process doesn't have a command"),
+		},
+		"duplicate type": {
+			procfile: "web: ./a.sh\n\nweb: ./b.sh",
+			wantErr:  errors.New("line 3: the web process is defined more than once"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got, err := procfile.Parse(strings.NewReader(tc.procfile))
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "processes", tc.want, got)
+		})
+	}
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "procfile")
+	testutil.AssertNil(t, "err", err)
+	defer os.RemoveAll(dir)
+
+	processes, err := procfile.Read(dir)
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "processes", []v1alpha1.AppSpecProcess(nil), processes)
+
+	err = ioutil.WriteFile(filepath.Join(dir, procfile.FileName), []byte("web"), 0644)
+	testutil.AssertNil(t, "err", err)
+
+	_, err = procfile.Read(dir)
+	testutil.AssertErrorsEqual(t, errors.New(`invalid Procfile: line 1: expected TYPE: COMMAND, got "web"`), err)
+}
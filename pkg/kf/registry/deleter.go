@@ -0,0 +1,94 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+// Deleter removes images from container registries.
+type Deleter interface {
+	// Delete removes the tag or digest from its repository. Images that are
+	// already gone aren't an error.
+	Delete(image string) error
+}
+
+type deleter struct {
+	keychain  authn.Keychain
+	transport http.RoundTripper
+}
+
+var _ Deleter = (*deleter)(nil)
+
+// NewDeleter creates a Deleter that authenticates to Google registries with
+// the application default credentials and to other registries with the
+// Docker config of the process, if there is one.
+func NewDeleter() Deleter {
+	return &deleter{
+		keychain:  authn.NewMultiKeychain(NewGoogleKeychain(), authn.DefaultKeychain),
+		transport: http.DefaultTransport,
+	}
+}
+
+// Delete implements Deleter.
+func (d *deleter) Delete(image string) error {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return err
+	}
+
+	registry := ref.Context().Registry
+	auth, err := d.keychain.Resolve(registry)
+	if err != nil {
+		return fmt.Errorf("couldn't authenticate to %s: %v", registry.RegistryStr(), err)
+	}
+
+	tr, err := transport.New(registry, auth, d.transport, []string{ref.Scope(transport.DeleteScope)})
+	if err != nil {
+		return fmt.Errorf("couldn't connect to %s: %v", registry.RegistryStr(), err)
+	}
+
+	target := fmt.Sprintf("%s://%s/v2/%s/manifests/%s",
+		registry.Scheme(),
+		registry.RegistryStr(),
+		ref.Context().RepositoryStr(),
+		ref.Identifier())
+
+	req, err := http.NewRequest(http.MethodDelete, target, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := (&http.Client{Transport: tr}).Do(req)
+	if err != nil {
+		return fmt.Errorf("couldn't delete %s: %v", image, err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusAccepted, http.StatusNotFound:
+		return nil
+	default:
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("couldn't delete %s: %s: %s", image, resp.Status, strings.TrimSpace(string(body)))
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestDeleter_Delete(t *testing.T) {
+	cases := map[string]struct {
+		status  int
+		wantErr []string
+	}{
+		"deleted": {
+			status: http.StatusAccepted,
+		},
+		"already gone": {
+			status: http.StatusNotFound,
+		},
+		"not allowed": {
+			status:  http.StatusForbidden,
+			wantErr: []string{"couldn't delete", "403 Forbidden", "no access"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var gotMethod, gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// The transport pings the registry before the first request.
+				if r.URL.Path == "/v2/" {
+					return
+				}
+
+				gotMethod = r.Method
+				gotPath = r.URL.Path
+				if tc.status == http.StatusForbidden {
+					http.Error(w, "no access", tc.status)
+					return
+				}
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			d := &deleter{
+				keychain:  authn.NewMultiKeychain(),
+				transport: http.DefaultTransport,
+			}
+
+			host := strings.TrimPrefix(server.URL, "http://")
+			err := d.Delete(host + "/my-project/app_my-space_my-app:1")
+
+			if tc.wantErr != nil {
+				testutil.AssertErrorContainsAll(t, err, tc.wantErr)
+			} else {
+				testutil.AssertNil(t, "err", err)
+			}
+			testutil.AssertEqual(t, "method", http.MethodDelete, gotMethod)
+			testutil.AssertEqual(t, "path", "/v2/my-project/app_my-space_my-app/manifests/1", gotPath)
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+// cloudPlatformScope grants access to Container Registry and Artifact
+// Registry.
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+type googleKeychain struct {
+	tokenSourceOnce sync.Once
+	newTokenSource  func(ctx context.Context) (oauth2.TokenSource, error)
+	tokenSource     oauth2.TokenSource
+	tokenSourceErr  error
+}
+
+var _ authn.Keychain = (*googleKeychain)(nil)
+
+// NewGoogleKeychain creates a Keychain that authenticates to Container
+// Registry and Artifact Registry with the application default credentials.
+// Other registries are accessed anonymously.
+func NewGoogleKeychain() authn.Keychain {
+	return &googleKeychain{
+		newTokenSource: func(ctx context.Context) (oauth2.TokenSource, error) {
+			return google.DefaultTokenSource(ctx, cloudPlatformScope)
+		},
+	}
+}
+
+// Resolve implements authn.Keychain.
+func (k *googleKeychain) Resolve(registry name.Registry) (authn.Authenticator, error) {
+	if !IsGoogleRegistry(registry.RegistryStr()) {
+		return authn.Anonymous, nil
+	}
+
+	k.tokenSourceOnce.Do(func() {
+		k.tokenSource, k.tokenSourceErr = k.newTokenSource(context.Background())
+	})
+
+	if k.tokenSourceErr != nil {
+		return nil, k.tokenSourceErr
+	}
+
+	return &tokenAuthenticator{tokenSource: k.tokenSource}, nil
+}
+
+// IsGoogleRegistry returns true if the host is Container Registry or
+// Artifact Registry.
+func IsGoogleRegistry(host string) bool {
+	return host == "gcr.io" ||
+		strings.HasSuffix(host, ".gcr.io") ||
+		strings.HasSuffix(host, "-docker.pkg.dev")
+}
+
+// tokenAuthenticator exchanges OAuth2 access tokens for registry tokens the
+// way docker-credential-gcr does.
+type tokenAuthenticator struct {
+	tokenSource oauth2.TokenSource
+}
+
+// Authorization implements authn.Authenticator.
+func (a *tokenAuthenticator) Authorization() (string, error) {
+	token, err := a.tokenSource.Token()
+	if err != nil {
+		return "", err
+	}
+
+	return (&authn.Basic{Username: "oauth2accesstoken", Password: token.AccessToken}).Authorization()
+}
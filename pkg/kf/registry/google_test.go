@@ -0,0 +1,61 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/kf/pkg/kf/testutil"
+	"golang.org/x/oauth2"
+)
+
+func ExampleIsGoogleRegistry() {
+	fmt.Println(IsGoogleRegistry("gcr.io"))
+	fmt.Println(IsGoogleRegistry("us.gcr.io"))
+	fmt.Println(IsGoogleRegistry("us-central1-docker.pkg.dev"))
+	fmt.Println(IsGoogleRegistry("index.docker.io"))
+
+	// Output: true
+	// true
+	// true
+	// false
+}
+
+func TestGoogleKeychain_Resolve(t *testing.T) {
+	keychain := &googleKeychain{
+		newTokenSource: func(context.Context) (oauth2.TokenSource, error) {
+			return oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "some-token"}), nil
+		},
+	}
+
+	auth, err := keychain.Resolve(name.Registry{})
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "default registry", authn.Anonymous, auth)
+
+	gcr, err := name.NewRegistry("gcr.io", name.WeakValidation)
+	testutil.AssertNil(t, "err", err)
+
+	auth, err = keychain.Resolve(gcr)
+	testutil.AssertNil(t, "err", err)
+
+	header, err := auth.Authorization()
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "header", "Basic "+base64.StdEncoding.EncodeToString([]byte("oauth2accesstoken:some-token")), header)
+}
@@ -0,0 +1,113 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ErrorsFile lists the parts of a bundle that couldn't be collected.
+const ErrorsFile = "errors.txt"
+
+// bundle writes files to a gzipped tarball under a single root directory.
+// Secret values known to the scrubber are removed from every file.
+type bundle struct {
+	root  string
+	now   time.Time
+	scrub *Scrubber
+
+	gz *gzip.Writer
+	tw *tar.Writer
+
+	errs []string
+}
+
+func newBundle(out io.Writer, root string, now time.Time) *bundle {
+	gz := gzip.NewWriter(out)
+
+	return &bundle{
+		root:  root,
+		now:   now,
+		scrub: NewScrubber(),
+		gz:    gz,
+		tw:    tar.NewWriter(gz),
+	}
+}
+
+// AddFile writes the scrubbed contents to a file at the given path in the
+// bundle.
+func (b *bundle) AddFile(name string, contents []byte) error {
+	contents = b.scrub.Scrub(contents)
+
+	hdr := &tar.Header{
+		Name:    path.Join(b.root, name),
+		Mode:    0644,
+		Size:    int64(len(contents)),
+		ModTime: b.now,
+	}
+
+	if err := b.tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err := b.tw.Write(contents)
+	return err
+}
+
+// AddObject writes the redacted YAML form of obj to the bundle.
+func (b *bundle) AddObject(name string, obj interface{}) error {
+	redacted, err := Redact(obj)
+	if err != nil {
+		b.AddError(name, err)
+		return nil
+	}
+
+	contents, err := yaml.Marshal(redacted)
+	if err != nil {
+		b.AddError(name, err)
+		return nil
+	}
+
+	return b.AddFile(name, contents)
+}
+
+// AddError records that part of the bundle couldn't be collected.
+func (b *bundle) AddError(what string, err error) {
+	b.errs = append(b.errs, fmt.Sprintf("%s: %s", what, err))
+}
+
+// Close writes the errors file, if any errors were recorded, and flushes the
+// bundle.
+func (b *bundle) Close() error {
+	if len(b.errs) > 0 {
+		if err := b.AddFile(ErrorsFile, []byte(strings.Join(b.errs, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+
+	if err := b.tw.Close(); err != nil {
+		return err
+	}
+
+	return b.gz.Close()
+}
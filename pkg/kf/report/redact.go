@@ -0,0 +1,191 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// Redacted replaces values that may hold secrets.
+const Redacted = "[REDACTED]"
+
+// sensitiveNameParts are substrings of environment variable and argument
+// names that are likely to hold secrets.
+var sensitiveNameParts = []string{
+	"PASSWORD",
+	"PASSWD",
+	"SECRET",
+	"TOKEN",
+	"KEY",
+	"CREDENTIAL",
+	"AUTH",
+	"VCAP_SERVICES",
+}
+
+// sensitiveFields are fields whose values are always redacted.
+var sensitiveFields = map[string]bool{
+	// Service binding parameters are passed to brokers and commonly hold
+	// credentials.
+	"parameters": true,
+	// Secret contents, in case one is referenced directly.
+	"data":       true,
+	"stringData": true,
+}
+
+// IsSensitiveName returns true if an environment variable or argument with
+// the given name is likely to hold a secret.
+func IsSensitiveName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, part := range sensitiveNameParts {
+		if strings.Contains(upper, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Redact converts obj to its JSON form with any values that may hold secrets
+// replaced by Redacted. Values are redacted if they belong to a sensitive
+// field or are name/value pairs, like environment variables, with a
+// sensitive name.
+func Redact(obj interface{}) (interface{}, error) {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var generic interface{}
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, err
+	}
+
+	return redactValue(generic), nil
+}
+
+func redactValue(v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		return redactMap(typed)
+	case []interface{}:
+		for i := range typed {
+			typed[i] = redactValue(typed[i])
+		}
+		return typed
+	default:
+		return v
+	}
+}
+
+func redactMap(m map[string]interface{}) map[string]interface{} {
+	for k, v := range m {
+		if sensitiveFields[k] && v != nil {
+			m[k] = Redacted
+			continue
+		}
+
+		m[k] = redactValue(v)
+	}
+
+	// Name/value pairs e.g. environment variables or build arguments.
+	if name, ok := m["name"].(string); ok && IsSensitiveName(name) {
+		if _, ok := m["value"].(string); ok {
+			m["value"] = Redacted
+		}
+	}
+
+	return m
+}
+
+// minScrubLength is the length of the shortest value Scrubber replaces.
+// Shorter values, like a port or a boolean flag, would redact unrelated text.
+const minScrubLength = 4
+
+// Scrubber replaces known secret values, like the contents of Secrets an App
+// reads, wherever they show up in text such as logs.
+type Scrubber struct {
+	values   map[string]bool
+	replacer *strings.Replacer
+}
+
+// NewScrubber creates a Scrubber without any values.
+func NewScrubber() *Scrubber {
+	return &Scrubber{values: map[string]bool{}}
+}
+
+// Add marks value as secret. If the value is JSON, like VCAP_SERVICES, the
+// strings it contains are marked as secret too so credentials are found
+// outside of the document.
+func (s *Scrubber) Add(value string) {
+	if len(value) < minScrubLength {
+		return
+	}
+
+	s.values[value] = true
+	s.replacer = nil
+
+	var generic interface{}
+	if err := json.Unmarshal([]byte(value), &generic); err == nil {
+		s.addLeaves(generic)
+	}
+}
+
+func (s *Scrubber) addLeaves(v interface{}) {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		for _, child := range typed {
+			s.addLeaves(child)
+		}
+	case []interface{}:
+		for _, child := range typed {
+			s.addLeaves(child)
+		}
+	case string:
+		s.Add(typed)
+	}
+}
+
+// Scrub returns data with every secret value replaced by Redacted.
+func (s *Scrubber) Scrub(data []byte) []byte {
+	if len(s.values) == 0 {
+		return data
+	}
+
+	if s.replacer == nil {
+		var values []string
+		for value := range s.values {
+			values = append(values, value)
+		}
+
+		// Longer values go first so a value containing another is replaced
+		// whole.
+		sort.Slice(values, func(i, j int) bool {
+			if len(values[i]) != len(values[j]) {
+				return len(values[i]) > len(values[j])
+			}
+			return values[i] < values[j]
+		})
+
+		var oldnew []string
+		for _, value := range values {
+			oldnew = append(oldnew, value, Redacted)
+		}
+		s.replacer = strings.NewReplacer(oldnew...)
+	}
+
+	return []byte(s.replacer.Replace(string(data)))
+}
@@ -0,0 +1,139 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		obj  interface{}
+		want string
+	}{
+		"sensitive env var": {
+			obj:  map[string]interface{}{"env": []map[string]string{{"name": "DB_PASSWORD", "value": "hunter2"}}},
+			want: `{"env":[{"name":"DB_PASSWORD","value":"[REDACTED]"}]}`,
+		},
+		"plain env var": {
+			obj:  map[string]interface{}{"env": []map[string]string{{"name": "PORT", "value": "8080"}}},
+			want: `{"env":[{"name":"PORT","value":"8080"}]}`,
+		},
+		"env var from secret": {
+			obj:  map[string]interface{}{"name": "API_KEY", "valueFrom": map[string]string{"secretKeyRef": "my-secret"}},
+			want: `{"name":"API_KEY","valueFrom":{"secretKeyRef":"my-secret"}}`,
+		},
+		"nested build argument": {
+			obj:  map[string]interface{}{"spec": map[string]interface{}{"args": []map[string]string{{"name": "REGISTRY_TOKEN", "value": "abc"}}}},
+			want: `{"spec":{"args":[{"name":"REGISTRY_TOKEN","value":"[REDACTED]"}]}}`,
+		},
+		"binding parameters": {
+			obj:  map[string]interface{}{"parameters": map[string]string{"username": "admin"}},
+			want: `{"parameters":"[REDACTED]"}`,
+		},
+		"null parameters": {
+			obj:  map[string]interface{}{"parameters": nil},
+			want: `{"parameters":null}`,
+		},
+		"secret data": {
+			obj:  map[string]interface{}{"data": map[string]string{"key": "dmFsdWU="}},
+			want: `{"data":"[REDACTED]"}`,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			redacted, err := Redact(tc.obj)
+			testutil.AssertNil(t, "error", err)
+
+			got, err := json.Marshal(redacted)
+			testutil.AssertNil(t, "marshal error", err)
+			testutil.AssertEqual(t, "redacted", tc.want, string(got))
+		})
+	}
+}
+
+func TestIsSensitiveName(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"PASSWORD":          true,
+		"db_password":       true,
+		"AWS_SECRET_ACCESS": true,
+		"GITHUB_TOKEN":      true,
+		"API_KEY":           true,
+		"VCAP_SERVICES":     true,
+		"PORT":              false,
+		"JAVA_OPTS":         false,
+		"":                  false,
+	}
+
+	for name, want := range cases {
+		t.Run(name, func(t *testing.T) {
+			testutil.AssertEqual(t, "sensitive", want, IsSensitiveName(name))
+		})
+	}
+}
+
+func TestScrubber_Scrub(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		values []string
+		data   string
+		want   string
+	}{
+		"no values": {
+			data: "connecting as admin:hunter2",
+			want: "connecting as admin:hunter2",
+		},
+		"secret value": {
+			values: []string{"hunter2"},
+			data:   "connecting as admin:hunter2",
+			want:   "connecting as admin:[REDACTED]",
+		},
+		"short values are ignored": {
+			values: []string{"on"},
+			data:   "logging on",
+			want:   "logging on",
+		},
+		"longest value wins": {
+			values: []string{"hunter", "hunter2"},
+			data:   "password hunter2",
+			want:   "password [REDACTED]",
+		},
+		"JSON credentials": {
+			values: []string{`[{"credentials":{"uri":"postgres://db","password":"hunter2"}}]`},
+			data:   "dial postgres://db failed for hunter2",
+			want:   "dial [REDACTED] failed for [REDACTED]",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			s := NewScrubber()
+			for _, value := range tc.values {
+				s.Add(value)
+			}
+
+			testutil.AssertEqual(t, "scrubbed", tc.want, string(s.Scrub([]byte(tc.data))))
+		})
+	}
+}
@@ -0,0 +1,392 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	cv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	"github.com/google/kf/pkg/reconciler/app/resources"
+	build "github.com/google/kf/third_party/knative-build/pkg/client/clientset/versioned/typed/build/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ControllerSelector selects the pods running the Kf controller.
+	ControllerSelector = "app=controller"
+
+	// LogLines is the number of lines collected from the end of each
+	// controller log.
+	LogLines = 1000
+)
+
+var virtualServiceResource = schema.GroupVersionResource{
+	Group:    "networking.istio.io",
+	Version:  "v1alpha3",
+	Resource: "virtualservices",
+}
+
+// Client collects support bundles for Apps.
+type Client interface {
+	// Collect writes a gzipped tarball describing the App to out. It contains
+	// the App, its Sources, Builds and build logs, Pods, Routes,
+	// VirtualServices, recent events and the controller logs. Values of the
+	// Secrets and service bindings the App reads are redacted from every
+	// file, including logs. Parts of the bundle that can't be collected are
+	// listed in errors.txt rather than failing the report.
+	Collect(out io.Writer, namespace, appName string) error
+}
+
+type client struct {
+	k8s     kubernetes.Interface
+	kf      cv1alpha1.KfV1alpha1Interface
+	build   build.BuildV1alpha1Interface
+	dynamic dynamic.Interface
+}
+
+// NewClient creates a new report client.
+func NewClient(
+	k8s kubernetes.Interface,
+	kf cv1alpha1.KfV1alpha1Interface,
+	build build.BuildV1alpha1Interface,
+	dynamic dynamic.Interface,
+) Client {
+	return &client{
+		k8s:     k8s,
+		kf:      kf,
+		build:   build,
+		dynamic: dynamic,
+	}
+}
+
+// Collect implements Client.
+func (c *client) Collect(out io.Writer, namespace, appName string) error {
+	app, err := c.kf.Apps(namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	b := newBundle(out, "kf-report-"+appName, time.Now())
+
+	// Names of every object collected so related events can be found.
+	related := map[string]bool{app.Name: true}
+
+	steps := []func(*bundle, *v1alpha1.App, map[string]bool) error{
+		// Secret values must be known before any file is written.
+		c.collectSecretValues,
+		c.collectApp,
+		c.collectSources,
+		c.collectPods,
+		c.collectRoutes,
+		c.collectVirtualServices,
+		c.collectEvents,
+		c.collectControllerLogs,
+	}
+
+	for _, step := range steps {
+		if err := step(b, app, related); err != nil {
+			return err
+		}
+	}
+
+	return b.Close()
+}
+
+// collectSecretValues marks the values of the Secrets the App reads and of
+// sensitive environment variables so they're scrubbed from the bundle. The
+// Secrets themselves are never added.
+func (c *client) collectSecretValues(b *bundle, app *v1alpha1.App, _ map[string]bool) error {
+	for _, name := range secretNames(app) {
+		secret, err := c.k8s.CoreV1().Secrets(app.Namespace).Get(name, metav1.GetOptions{})
+		switch {
+		case apierrs.IsNotFound(err):
+			continue
+		case err != nil:
+			b.AddError("secrets/"+name, err)
+			continue
+		}
+
+		for _, value := range secret.Data {
+			b.scrub.Add(string(value))
+		}
+	}
+
+	for _, container := range app.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if IsSensitiveName(env.Name) {
+				b.scrub.Add(env.Value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// secretNames returns the sorted names of the Secrets the App reads, either
+// directly or through its service bindings.
+func secretNames(app *v1alpha1.App) []string {
+	names := map[string]bool{
+		resources.KfInjectedEnvSecretName(app): true,
+		resources.KfBindingsSecretName(app):    true,
+	}
+
+	for i := range app.Spec.ServiceBindings {
+		// Service Catalog names binding Secrets after the binding.
+		names[resources.MakeServiceBindingName(app, &app.Spec.ServiceBindings[i])] = true
+	}
+
+	for _, container := range app.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				names[env.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				names[envFrom.SecretRef.Name] = true
+			}
+		}
+	}
+
+	var out []string
+	for name := range names {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+
+	return out
+}
+
+func (c *client) collectApp(b *bundle, app *v1alpha1.App, _ map[string]bool) error {
+	// If the type didn't come back with a kind, update it so the YAML is
+	// complete.
+	app.GetObjectKind().SetGroupVersionKind(app.GetGroupVersionKind())
+	return b.AddObject("app.yaml", app)
+}
+
+func (c *client) collectSources(b *bundle, app *v1alpha1.App, related map[string]bool) error {
+	sources, err := c.kf.Sources(app.Namespace).List(metav1.ListOptions{
+		LabelSelector: v1alpha1.NameLabel + "=" + app.Name,
+	})
+	if err != nil {
+		b.AddError("sources", err)
+		return nil
+	}
+
+	for _, source := range sources.Items {
+		related[source.Name] = true
+		if err := b.AddObject("sources/"+source.Name+".yaml", source); err != nil {
+			return err
+		}
+
+		if source.Status.BuildName == "" {
+			continue
+		}
+
+		if err := c.collectBuild(b, app.Namespace, source.Status.BuildName, related); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *client) collectBuild(b *bundle, namespace, buildName string, related map[string]bool) error {
+	related[buildName] = true
+
+	bld, err := c.build.Builds(namespace).Get(buildName, metav1.GetOptions{})
+	if err != nil {
+		b.AddError("builds/"+buildName, err)
+		return nil
+	}
+
+	if err := b.AddObject("builds/"+buildName+".yaml", bld); err != nil {
+		return err
+	}
+
+	if bld.Status.Cluster == nil || bld.Status.Cluster.PodName == "" {
+		return nil
+	}
+
+	podName := bld.Status.Cluster.PodName
+	related[podName] = true
+
+	pod, err := c.k8s.CoreV1().Pods(namespace).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		b.AddError("builds/"+buildName+".log", err)
+		return nil
+	}
+
+	// Each build step runs as an init container.
+	var containers []corev1.Container
+	containers = append(containers, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+
+	var logs []byte
+	for _, container := range containers {
+		contents, err := c.k8s.CoreV1().
+			Pods(namespace).
+			GetLogs(podName, &corev1.PodLogOptions{Container: container.Name}).
+			DoRaw()
+		if err != nil {
+			b.AddError(fmt.Sprintf("builds/%s.log (step %s)", buildName, container.Name), err)
+			continue
+		}
+
+		logs = append(logs, fmt.Sprintf("==> step %s <==\n", container.Name)...)
+		logs = append(logs, contents...)
+	}
+
+	return b.AddFile("builds/"+buildName+".log", logs)
+}
+
+func (c *client) collectPods(b *bundle, app *v1alpha1.App, related map[string]bool) error {
+	pods, err := c.k8s.CoreV1().Pods(app.Namespace).List(metav1.ListOptions{
+		LabelSelector: "serving.knative.dev/service=" + app.Name,
+	})
+	if err != nil {
+		b.AddError("pods", err)
+		return nil
+	}
+
+	for _, pod := range pods.Items {
+		related[pod.Name] = true
+		if revision := pod.Labels["serving.knative.dev/revision"]; revision != "" {
+			related[revision] = true
+		}
+
+		if err := b.AddObject("pods/"+pod.Name+".yaml", pod); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *client) collectRoutes(b *bundle, app *v1alpha1.App, related map[string]bool) error {
+	routes, err := c.kf.Routes(app.Namespace).List(metav1.ListOptions{
+		LabelSelector: v1alpha1.RouteAppName + "=" + app.Name,
+	})
+	if err != nil {
+		b.AddError("routes", err)
+		return nil
+	}
+
+	for _, route := range routes.Items {
+		related[route.Name] = true
+		if err := b.AddObject("routes/"+route.Name+".yaml", route); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *client) collectVirtualServices(b *bundle, app *v1alpha1.App, related map[string]bool) error {
+	// VirtualServices for every space live in the kf namespace and are shared
+	// by all apps bound to the same host.
+	virtualServices, err := c.dynamic.
+		Resource(virtualServiceResource).
+		Namespace(v1alpha1.KfNamespace).
+		List(metav1.ListOptions{
+			LabelSelector: v1alpha1.ManagedByLabel + "=kf",
+		})
+	if err != nil {
+		b.AddError("virtualservices", err)
+		return nil
+	}
+
+	hosts := map[string]bool{}
+	for _, route := range app.Spec.Routes {
+		hosts[route.Hostname+"."+route.Domain] = true
+	}
+
+	for _, vs := range virtualServices.Items {
+		annotations := vs.GetAnnotations()
+		if annotations["space"] != app.Namespace {
+			continue
+		}
+
+		if !hosts[annotations["hostname"]+"."+annotations["domain"]] {
+			continue
+		}
+
+		related[vs.GetName()] = true
+		if err := b.AddObject("virtualservices/"+vs.GetName()+".yaml", vs.Object); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *client) collectEvents(b *bundle, app *v1alpha1.App, related map[string]bool) error {
+	events, err := c.k8s.CoreV1().Events(app.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		b.AddError("events", err)
+		return nil
+	}
+
+	var matching []corev1.Event
+	for _, event := range events.Items {
+		if related[event.InvolvedObject.Name] {
+			matching = append(matching, event)
+		}
+	}
+
+	sort.Slice(matching, func(i, j int) bool {
+		return matching[i].LastTimestamp.Before(&matching[j].LastTimestamp)
+	})
+
+	return b.AddObject("events.yaml", matching)
+}
+
+func (c *client) collectControllerLogs(b *bundle, _ *v1alpha1.App, _ map[string]bool) error {
+	pods, err := c.k8s.CoreV1().Pods(v1alpha1.KfNamespace).List(metav1.ListOptions{
+		LabelSelector: ControllerSelector,
+	})
+	if err != nil {
+		b.AddError("controller logs", err)
+		return nil
+	}
+
+	tailLines := int64(LogLines)
+	for _, pod := range pods.Items {
+		logs, err := c.k8s.CoreV1().
+			Pods(pod.Namespace).
+			GetLogs(pod.Name, &corev1.PodLogOptions{TailLines: &tailLines}).
+			DoRaw()
+		if err != nil {
+			b.AddError("controller/"+pod.Name+".log", err)
+			continue
+		}
+
+		if err := b.AddFile("controller/"+pod.Name+".log", logs); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
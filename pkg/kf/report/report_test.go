@@ -0,0 +1,222 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package report
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kffake "github.com/google/kf/pkg/client/clientset/versioned/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
+	buildfake "github.com/google/kf/third_party/knative-build/pkg/client/clientset/versioned/fake"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func readBundle(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	testutil.AssertNil(t, "gzip error", err)
+
+	files := map[string]string{}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		testutil.AssertNil(t, "tar error", err)
+
+		contents, err := ioutil.ReadAll(tr)
+		testutil.AssertNil(t, "read error", err)
+		files[hdr.Name] = string(contents)
+	}
+
+	return files
+}
+
+func TestClient_Collect(t *testing.T) {
+	t.Parallel()
+
+	app := &v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "my-space"},
+		Spec: v1alpha1.AppSpec{
+			Template: v1alpha1.AppSpecTemplate{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Env: []corev1.EnvVar{
+							{Name: "DB_PASSWORD", Value: "hunter2"},
+							{Name: "PORT", Value: "8080"},
+							{Name: "DATABASE_URL", Value: "postgres://admin:s3cr3t@db"},
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	injectedEnv := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "kf-injected-envs-my-app", Namespace: "my-space"},
+		Data: map[string][]byte{
+			"VCAP_SERVICES": []byte(`{"db":[{"credentials":{"password":"s3cr3t"}}]}`),
+		},
+	}
+
+	source := &v1alpha1.Source{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-app-1",
+			Namespace: "my-space",
+			Labels:    map[string]string{v1alpha1.NameLabel: "my-app"},
+		},
+		Status: v1alpha1.SourceStatus{
+			SourceStatusFields: v1alpha1.SourceStatusFields{BuildName: "my-app-1-build"},
+		},
+	}
+
+	otherSource := &v1alpha1.Source{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "other-app-1",
+			Namespace: "my-space",
+			Labels:    map[string]string{v1alpha1.NameLabel: "other-app"},
+		},
+	}
+
+	bld := &build.Build{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app-1-build", Namespace: "my-space"},
+	}
+
+	route := &v1alpha1.Route{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-route",
+			Namespace: "my-space",
+			Labels:    map[string]string{v1alpha1.RouteAppName: "my-app"},
+		},
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-app-abc-deployment-123",
+			Namespace: "my-space",
+			Labels: map[string]string{
+				"serving.knative.dev/service":  "my-app",
+				"serving.knative.dev/revision": "my-app-abc",
+			},
+		},
+	}
+
+	appEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "my-app.1", Namespace: "my-space"},
+		InvolvedObject: corev1.ObjectReference{Name: "my-app"},
+		Message:        "app event",
+	}
+
+	revisionEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "my-app-abc.1", Namespace: "my-space"},
+		InvolvedObject: corev1.ObjectReference{Name: "my-app-abc"},
+		Message:        "revision event",
+	}
+
+	otherEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "other-app.1", Namespace: "my-space"},
+		InvolvedObject: corev1.ObjectReference{Name: "other-app"},
+		Message:        "other event",
+	}
+
+	// The generated Build fake uses a different group than the scheme, so
+	// Builds can only be found if they're created through the client.
+	builds := buildfake.NewSimpleClientset().BuildV1alpha1()
+	_, err := builds.Builds("my-space").Create(bld)
+	testutil.AssertNil(t, "create build error", err)
+
+	client := NewClient(
+		k8sfake.NewSimpleClientset(pod, appEvent, revisionEvent, otherEvent, injectedEnv),
+		kffake.NewSimpleClientset(app, source, otherSource, route).KfV1alpha1(),
+		builds,
+		dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+	)
+
+	out := &bytes.Buffer{}
+	testutil.AssertNil(t, "collect error", client.Collect(out, "my-space", "my-app"))
+
+	files := readBundle(t, out.Bytes())
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	testutil.AssertContainsAll(t, strings.Join(names, "\n"), []string{
+		"kf-report-my-app/app.yaml",
+		"kf-report-my-app/sources/my-app-1.yaml",
+		"kf-report-my-app/builds/my-app-1-build.yaml",
+		"kf-report-my-app/pods/my-app-abc-deployment-123.yaml",
+		"kf-report-my-app/routes/my-route.yaml",
+		"kf-report-my-app/events.yaml",
+	})
+
+	if _, ok := files["kf-report-my-app/sources/other-app-1.yaml"]; ok {
+		t.Error("expected sources of other apps to be excluded")
+	}
+
+	appYAML := files["kf-report-my-app/app.yaml"]
+	testutil.AssertContainsAll(t, appYAML, []string{"kind: App", Redacted, "8080"})
+	if strings.Contains(appYAML, "hunter2") {
+		t.Error("expected the password to be redacted")
+	}
+	if strings.Contains(appYAML, "s3cr3t") {
+		t.Error("expected the binding credentials to be redacted")
+	}
+
+	for name, contents := range files {
+		if strings.Contains(contents, "VCAP_SERVICES") {
+			t.Errorf("expected Secrets to be left out of the bundle, found one in %s", name)
+		}
+	}
+
+	events := files["kf-report-my-app/events.yaml"]
+	testutil.AssertContainsAll(t, events, []string{"app event", "revision event"})
+	if strings.Contains(events, "other event") {
+		t.Error("expected events of other apps to be excluded")
+	}
+}
+
+func TestClient_Collect_missingApp(t *testing.T) {
+	t.Parallel()
+
+	client := NewClient(
+		k8sfake.NewSimpleClientset(),
+		kffake.NewSimpleClientset().KfV1alpha1(),
+		buildfake.NewSimpleClientset().BuildV1alpha1(),
+		dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()),
+	)
+
+	out := &bytes.Buffer{}
+	err := client.Collect(out, "my-space", "my-app")
+	testutil.AssertErrorContainsAll(t, err, []string{"not found"})
+	testutil.AssertEqual(t, "bytes written", 0, out.Len())
+}
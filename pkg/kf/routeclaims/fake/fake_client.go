@@ -130,6 +130,27 @@ func (mr *FakeClientMockRecorder) List(arg0 interface{}, arg1 ...interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*FakeClient)(nil).List), varargs...)
 }
 
+// ListPage mocks base method
+func (m *FakeClient) ListPage(arg0 string, arg1 ...routeclaims.ListOption) ([]v1alpha1.RouteClaim, string, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListPage", varargs...)
+	ret0, _ := ret[0].([]v1alpha1.RouteClaim)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPage indicates an expected call of ListPage
+func (mr *FakeClientMockRecorder) ListPage(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*FakeClient)(nil).ListPage), varargs...)
+}
+
 // Transform mocks base method
 func (m *FakeClient) Transform(arg0, arg1 string, arg2 routeclaims.Mutator) (*v1alpha1.RouteClaim, error) {
 	m.ctrl.T.Helper()
@@ -22,13 +22,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"strings"
 	"time"
 
-	"knative.dev/pkg/kmp"
+	"github.com/google/kf/pkg/internal/diffutil"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
 )
 
 // User defined imports
@@ -57,7 +59,7 @@ type Mutator func(*v1alpha1.Route) error
 
 // DiffWrapper wraps a mutator and prints out the diff between the original object
 // and the one it returns if there's no error.
-func DiffWrapper(w io.Writer, mutator Mutator) Mutator {
+func DiffWrapper(w io.Writer, mutator Mutator, opts ...diffutil.Option) Mutator {
 	return func(mutable *v1alpha1.Route) error {
 		before := mutable.DeepCopy()
 
@@ -65,29 +67,18 @@ func DiffWrapper(w io.Writer, mutator Mutator) Mutator {
 			return err
 		}
 
-		FormatDiff(w, "old", "new", before, mutable)
+		FormatDiff(w, "old", "new", before, mutable, opts...)
 
 		return nil
 	}
 }
 
-// FormatDiff creates a diff between two v1alpha1.Routes and writes it to the given
-// writer.
-func FormatDiff(w io.Writer, leftName, rightName string, left, right *v1alpha1.Route) {
-	diff, err := kmp.SafeDiff(left, right)
-	switch {
-	case err != nil:
+// FormatDiff creates a semantic diff between two v1alpha1.Routes and writes it to the
+// given writer.
+func FormatDiff(w io.Writer, leftName, rightName string, left, right *v1alpha1.Route, opts ...diffutil.Option) {
+	title := fmt.Sprintf("Route Diff (-%s +%s):", leftName, rightName)
+	if err := diffutil.Fprint(w, title, left, right, opts...); err != nil {
 		fmt.Fprintf(w, "couldn't format diff: %s\n", err.Error())
-
-	case diff == "":
-		fmt.Fprintln(w, "No changes")
-
-	default:
-		fmt.Fprintf(w, "Route Diff (-%s +%s):\n", leftName, rightName)
-		// go-cmp randomly chooses to prefix lines with non-breaking spaces or
-		// regular spaces to prevent people from using it as a real diff/patch
-		// tool. We normalize them so our outputs will be consistent.
-		fmt.Fprintln(w, strings.ReplaceAll(diff, " ", " "))
 	}
 }
 
@@ -117,6 +108,7 @@ type Client interface {
 	Get(namespace string, name string, opts ...GetOption) (*v1alpha1.Route, error)
 	Delete(namespace string, name string, opts ...DeleteOption) error
 	List(namespace string, opts ...ListOption) ([]v1alpha1.Route, error)
+	ListPage(namespace string, opts ...ListOption) ([]v1alpha1.Route, string, error)
 	Upsert(namespace string, newObj *v1alpha1.Route, merge Merger) (*v1alpha1.Route, error)
 	WaitFor(ctx context.Context, namespace string, name string, interval time.Duration, condition Predicate) (*v1alpha1.Route, error)
 	WaitForE(ctx context.Context, namespace string, name string, interval time.Duration, condition ConditionFuncE) (*v1alpha1.Route, error)
@@ -229,11 +221,32 @@ func (core *coreClient) List(namespace string, opts ...ListOption) ([]v1alpha1.R
 	return List(res.Items).Filter(cfg.filter), nil
 }
 
+// ListPage gets a single page of objects from the cluster sized by the limit
+// option and returns the token to pass with the continue token option to get
+// the next page. The token is empty once there are no more results.
+func (core *coreClient) ListPage(namespace string, opts ...ListOption) ([]v1alpha1.Route, string, error) {
+	cfg := ListOptionDefaults().Extend(opts).toConfig()
+
+	res, err := core.kclient.Routes(namespace).List(cfg.ToListOptions())
+	if err != nil {
+		return nil, "", fmt.Errorf("couldn't list Routes: %v", err)
+	}
+
+	if cfg.filter == nil {
+		return res.Items, res.Continue, nil
+	}
+
+	return List(res.Items).Filter(cfg.filter), res.Continue, nil
+}
+
 func (cfg listConfig) ToListOptions() (resp metav1.ListOptions) {
 	if cfg.fieldSelector != nil {
 		resp.FieldSelector = metav1.FormatLabelSelector(metav1.SetAsLabelSelector(cfg.fieldSelector))
 	}
 
+	resp.Limit = cfg.limit
+	resp.Continue = cfg.continueToken
+
 	return
 }
 
@@ -273,30 +286,104 @@ func (core *coreClient) WaitFor(ctx context.Context, namespace string, name stri
 // function to decide how to handle the apiErr.
 type ConditionFuncE func(instance *v1alpha1.Route, apiErr error) (done bool, err error)
 
-// WaitForE polls for the given object every interval until the condition
-// function becomes done or the timeout expires. The first poll occurs
-// immediately after the function is invoked.
+// WaitForE watches the given object until the condition function becomes
+// done or the context is cancelled. The object is fetched immediately so the
+// condition is checked against its current state before any events arrive.
 //
-// The function polls infinitely if no timeout is supplied.
+// If the watch is closed by the server it's re-established after a jittered
+// delay of up to twice the interval, the object is fetched again first so
+// changes made while disconnected aren't missed.
+//
+// The function waits infinitely if the context has no deadline.
 func (core *coreClient) WaitForE(ctx context.Context, namespace string, name string, interval time.Duration, condition ConditionFuncE) (instance *v1alpha1.Route, err error) {
 	var done bool
-	tick := time.Tick(interval)
 
 	for {
-		instance, err = core.kclient.Routes(namespace).Get(name, metav1.GetOptions{})
-		if done, err = condition(instance, err); done {
+		var apiErr error
+		instance, apiErr = core.kclient.Routes(namespace).Get(name, metav1.GetOptions{})
+		if done, err = condition(instance, apiErr); done {
 			return
 		}
 
+		resourceVersion := ""
+		if apiErr == nil {
+			resourceVersion = instance.ResourceVersion
+		}
+
+		var watched *v1alpha1.Route
+		if done, watched, err = core.watchUntil(ctx, namespace, name, resourceVersion, condition); done {
+			return watched, err
+		}
+
+		if ctx.Err() != nil {
+			return nil, errors.New("waiting for Route timed out")
+		}
+
 		select {
-		case <-tick:
-			// repeat instance check
+		case <-time.After(wait.Jitter(interval, 1.0)):
+			// reconnect
 		case <-ctx.Done():
 			return nil, errors.New("waiting for Route timed out")
 		}
 	}
 }
 
+// watchUntil feeds events for the named object to the condition until it's
+// done. It returns false if the watch couldn't be started, was closed or the
+// context was cancelled so the caller can decide whether to reconnect.
+func (core *coreClient) watchUntil(ctx context.Context, namespace string, name, resourceVersion string, condition ConditionFuncE) (bool, *v1alpha1.Route, error) {
+	watcher, err := core.kclient.Routes(namespace).Watch(metav1.ListOptions{
+		FieldSelector:   fields.OneTermEqualSelector("metadata.name", name).String(),
+		ResourceVersion: resourceVersion,
+	})
+	if err != nil {
+		// Treat a failed watch like a closed one so it's retried.
+		return false, nil, nil
+	}
+	defer watcher.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false, nil, nil
+
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false, nil, nil
+			}
+
+			switch event.Type {
+			case watch.Added, watch.Modified:
+				obj, ok := event.Object.(*v1alpha1.Route)
+				if !ok || obj.Name != name {
+					continue
+				}
+
+				if done, err := condition(obj, nil); done {
+					return true, obj, err
+				}
+
+			case watch.Deleted:
+				obj, ok := event.Object.(*v1alpha1.Route)
+				if !ok || obj.Name != name {
+					continue
+				}
+
+				// Fetch the object so the condition gets the not found error
+				// from the API, or the new object if it was already re-created.
+				instance, apiErr := core.kclient.Routes(namespace).Get(name, metav1.GetOptions{})
+				if done, err := condition(instance, apiErr); done {
+					return true, instance, err
+				}
+
+			case watch.Error:
+				// The resource version likely expired, reconnecting resyncs.
+				return false, nil, nil
+			}
+		}
+	}
+}
+
 // ConditionDeleted is a ConditionFuncE that succeeds if the error returned by
 // the cluster was a not found error.
 func ConditionDeleted(_ *v1alpha1.Route, apiErr error) (bool, error) {
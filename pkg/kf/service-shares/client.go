@@ -0,0 +1,245 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceshares
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	servicecatalogclient "github.com/google/kf/pkg/client/servicecatalog/clientset/versioned"
+	servicecatalogv1beta1 "github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// SharedSpacesAnnotation records the spaces a service instance is shared
+	// with as a comma separated list.
+	SharedSpacesAnnotation = "kf.dev/shared-spaces"
+
+	// SharedFromAnnotation records the space and instance a replicated Secret
+	// was copied from as SPACE/INSTANCE.
+	SharedFromAnnotation = "kf.dev/shared-from"
+)
+
+// BindingName gets the name of the ServiceBinding made in the instance's
+// space to share it with another space.
+func BindingName(instance, space string) string {
+	return fmt.Sprintf("kf-share-%s-%s", instance, space)
+}
+
+// SecretName gets the name of the Secret holding the shared credentials in
+// the space the instance is shared with.
+func SecretName(namespace, instance string) string {
+	return fmt.Sprintf("kf-shared-%s-%s", namespace, instance)
+}
+
+// Client shares service instances with other spaces.
+type Client interface {
+	// Share binds the instance and copies the credentials to a Secret in the
+	// other space once the binding is ready. It waits until the context is
+	// done for the credentials.
+	Share(ctx context.Context, namespace, instance, space string) error
+
+	// Unshare removes the binding and the copied credentials.
+	Unshare(namespace, instance, space string) error
+
+	// List gets the spaces the instance is shared with.
+	List(namespace, instance string) ([]string, error)
+}
+
+type client struct {
+	svcatClient servicecatalogclient.Interface
+	secrets     v1.SecretsGetter
+	interval    time.Duration
+}
+
+// NewClient creates a new Client.
+func NewClient(svcatClient servicecatalogclient.Interface, secrets v1.SecretsGetter) Client {
+	return &client{
+		svcatClient: svcatClient,
+		secrets:     secrets,
+		interval:    time.Second,
+	}
+}
+
+// Share implements Client.
+func (c *client) Share(ctx context.Context, namespace, instance, space string) error {
+	if namespace == space {
+		return fmt.Errorf("service %s is already in space %s", instance, space)
+	}
+
+	if _, err := c.svcatClient.ServicecatalogV1beta1().ServiceInstances(namespace).Get(instance, metav1.GetOptions{}); err != nil {
+		return err
+	}
+
+	binding := &servicecatalogv1beta1.ServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BindingName(instance, space),
+			Namespace: namespace,
+			Labels: map[string]string{
+				v1alpha1.ManagedByLabel: "kf",
+			},
+		},
+		Spec: servicecatalogv1beta1.ServiceBindingSpec{
+			InstanceRef: servicecatalogv1beta1.LocalObjectReference{
+				Name: instance,
+			},
+			SecretName: BindingName(instance, space),
+		},
+	}
+
+	_, err := c.svcatClient.ServicecatalogV1beta1().ServiceBindings(namespace).Create(binding)
+	if err != nil && !apierrs.IsAlreadyExists(err) {
+		return err
+	}
+
+	if err := c.updateSharedSpaces(namespace, instance, func(spaces sets.String) {
+		spaces.Insert(space)
+	}); err != nil {
+		return err
+	}
+
+	var credentials *corev1.Secret
+	err = wait.PollImmediateUntil(c.interval, func() (bool, error) {
+		credentials, err = c.secrets.Secrets(namespace).Get(binding.Spec.SecretName, metav1.GetOptions{})
+		switch {
+		case apierrs.IsNotFound(err):
+			return false, nil
+		case err != nil:
+			return false, err
+		default:
+			return true, nil
+		}
+	}, ctx.Done())
+	if err != nil {
+		return fmt.Errorf("the credentials for service %s weren't ready: %s", instance, err)
+	}
+
+	return c.replicate(credentials, namespace, instance, space)
+}
+
+// replicate copies the binding's credentials into the other space.
+func (c *client) replicate(credentials *corev1.Secret, namespace, instance, space string) error {
+	replica := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      SecretName(namespace, instance),
+			Namespace: space,
+			Labels: map[string]string{
+				v1alpha1.ManagedByLabel: "kf",
+			},
+			Annotations: map[string]string{
+				SharedFromAnnotation: namespace + "/" + instance,
+			},
+		},
+		Type: credentials.Type,
+		Data: credentials.Data,
+	}
+
+	secrets := c.secrets.Secrets(space)
+	existing, err := secrets.Get(replica.Name, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		_, err = secrets.Create(replica)
+		return err
+	case err != nil:
+		return err
+	}
+
+	if existing.Annotations[SharedFromAnnotation] != replica.Annotations[SharedFromAnnotation] {
+		return fmt.Errorf("secret %s in space %s isn't managed by Kf", replica.Name, space)
+	}
+
+	replica.ResourceVersion = existing.ResourceVersion
+	_, err = secrets.Update(replica)
+	return err
+}
+
+// Unshare implements Client.
+func (c *client) Unshare(namespace, instance, space string) error {
+	err := c.svcatClient.
+		ServicecatalogV1beta1().
+		ServiceBindings(namespace).
+		Delete(BindingName(instance, space), &metav1.DeleteOptions{})
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	err = c.secrets.Secrets(space).Delete(SecretName(namespace, instance), &metav1.DeleteOptions{})
+	if err != nil && !apierrs.IsNotFound(err) {
+		return err
+	}
+
+	return c.updateSharedSpaces(namespace, instance, func(spaces sets.String) {
+		spaces.Delete(space)
+	})
+}
+
+// List implements Client.
+func (c *client) List(namespace, instance string) ([]string, error) {
+	serviceInstance, err := c.svcatClient.ServicecatalogV1beta1().ServiceInstances(namespace).Get(instance, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return sharedSpaces(serviceInstance).List(), nil
+}
+
+// updateSharedSpaces records the spaces the instance is shared with.
+func (c *client) updateSharedSpaces(namespace, instance string, mutator func(spaces sets.String)) error {
+	instances := c.svcatClient.ServicecatalogV1beta1().ServiceInstances(namespace)
+
+	serviceInstance, err := instances.Get(instance, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	spaces := sharedSpaces(serviceInstance)
+	mutator(spaces)
+
+	updated := serviceInstance.DeepCopy()
+	if updated.Annotations == nil {
+		updated.Annotations = make(map[string]string)
+	}
+
+	if spaces.Len() == 0 {
+		delete(updated.Annotations, SharedSpacesAnnotation)
+	} else {
+		updated.Annotations[SharedSpacesAnnotation] = strings.Join(spaces.List(), ",")
+	}
+
+	_, err = instances.Update(updated)
+	return err
+}
+
+// sharedSpaces reads the spaces an instance is shared with.
+func sharedSpaces(instance *servicecatalogv1beta1.ServiceInstance) sets.String {
+	out := sets.NewString()
+
+	for _, space := range strings.Split(instance.Annotations[SharedSpacesAnnotation], ",") {
+		if space = strings.TrimSpace(space); space != "" {
+			out.Insert(space)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,184 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceshares_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	svcatfake "github.com/google/kf/pkg/client/servicecatalog/clientset/versioned/fake"
+	serviceshares "github.com/google/kf/pkg/kf/service-shares"
+	"github.com/google/kf/pkg/kf/testutil"
+	servicecatalogv1beta1 "github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func instance(sharedSpaces string) *servicecatalogv1beta1.ServiceInstance {
+	out := &servicecatalogv1beta1.ServiceInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-db",
+			Namespace: "owner",
+		},
+	}
+
+	if sharedSpaces != "" {
+		out.Annotations = map[string]string{
+			serviceshares.SharedSpacesAnnotation: sharedSpaces,
+		}
+	}
+
+	return out
+}
+
+func bindingSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceshares.BindingName("my-db", "other"),
+			Namespace: "owner",
+		},
+		Data: map[string][]byte{"password": []byte("hunter2")},
+	}
+}
+
+func TestClient_Share(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		instance *servicecatalogv1beta1.ServiceInstance
+		secrets  []runtime.Object
+		space    string
+		wantErr  error
+		validate func(t *testing.T, svcat *svcatfake.Clientset, k8s *k8sfake.Clientset)
+	}{
+		"same space": {
+			instance: instance(""),
+			space:    "owner",
+			wantErr:  errors.New("service my-db is already in space owner"),
+		},
+		"missing instance": {
+			space:   "other",
+			wantErr: errors.New(`serviceinstances.servicecatalog.k8s.io "my-db" not found`),
+		},
+		"credentials never ready": {
+			instance: instance(""),
+			space:    "other",
+			wantErr:  errors.New("the credentials for service my-db weren't ready: timed out waiting for the condition"),
+		},
+		"shares the instance": {
+			instance: instance("another"),
+			secrets:  []runtime.Object{bindingSecret()},
+			space:    "other",
+			validate: func(t *testing.T, svcat *svcatfake.Clientset, k8s *k8sfake.Clientset) {
+				binding, err := svcat.ServicecatalogV1beta1().ServiceBindings("owner").Get("kf-share-my-db-other", metav1.GetOptions{})
+				testutil.AssertNil(t, "binding err", err)
+				testutil.AssertEqual(t, "instance", "my-db", binding.Spec.InstanceRef.Name)
+
+				updated, err := svcat.ServicecatalogV1beta1().ServiceInstances("owner").Get("my-db", metav1.GetOptions{})
+				testutil.AssertNil(t, "instance err", err)
+				testutil.AssertEqual(t, "shared spaces", "another,other", updated.Annotations[serviceshares.SharedSpacesAnnotation])
+
+				replica, err := k8s.CoreV1().Secrets("other").Get("kf-shared-owner-my-db", metav1.GetOptions{})
+				testutil.AssertNil(t, "replica err", err)
+				testutil.AssertEqual(t, "data", "hunter2", string(replica.Data["password"]))
+				testutil.AssertEqual(t, "shared from", "owner/my-db", replica.Annotations[serviceshares.SharedFromAnnotation])
+			},
+		},
+		"secret not managed by Kf": {
+			instance: instance(""),
+			secrets: []runtime.Object{
+				bindingSecret(),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "kf-shared-owner-my-db",
+						Namespace: "other",
+					},
+				},
+			},
+			space:   "other",
+			wantErr: errors.New("secret kf-shared-owner-my-db in space other isn't managed by Kf"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			var svcatObjs []runtime.Object
+			if tc.instance != nil {
+				svcatObjs = append(svcatObjs, tc.instance)
+			}
+
+			svcat := svcatfake.NewSimpleClientset(svcatObjs...)
+			k8s := k8sfake.NewSimpleClientset(tc.secrets...)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			err := serviceshares.NewClient(svcat, k8s.CoreV1()).Share(ctx, "owner", "my-db", tc.space)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+
+			if tc.validate != nil {
+				tc.validate(t, svcat, k8s)
+			}
+		})
+	}
+}
+
+func TestClient_Unshare(t *testing.T) {
+	t.Parallel()
+
+	svcat := svcatfake.NewSimpleClientset(
+		instance("other,another"),
+		&servicecatalogv1beta1.ServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "kf-share-my-db-other",
+				Namespace: "owner",
+			},
+		},
+	)
+	k8s := k8sfake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kf-shared-owner-my-db",
+			Namespace: "other",
+		},
+	})
+
+	err := serviceshares.NewClient(svcat, k8s.CoreV1()).Unshare("owner", "my-db", "other")
+	testutil.AssertNil(t, "err", err)
+
+	_, err = svcat.ServicecatalogV1beta1().ServiceBindings("owner").Get("kf-share-my-db-other", metav1.GetOptions{})
+	testutil.AssertErrorsEqual(t, errors.New(`servicebindings.servicecatalog.k8s.io "kf-share-my-db-other" not found`), err)
+
+	_, err = k8s.CoreV1().Secrets("other").Get("kf-shared-owner-my-db", metav1.GetOptions{})
+	testutil.AssertErrorsEqual(t, errors.New(`secrets "kf-shared-owner-my-db" not found`), err)
+
+	spaces, err := serviceshares.NewClient(svcat, k8s.CoreV1()).List("owner", "my-db")
+	testutil.AssertNil(t, "list err", err)
+	testutil.AssertEqual(t, "spaces", []string{"another"}, spaces)
+}
+
+func TestClient_List(t *testing.T) {
+	t.Parallel()
+
+	svcat := svcatfake.NewSimpleClientset(instance("b, a,,c"))
+	k8s := k8sfake.NewSimpleClientset()
+
+	spaces, err := serviceshares.NewClient(svcat, k8s.CoreV1()).List("owner", "my-db")
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "spaces", []string{"a", "b", "c"}, spaces)
+}
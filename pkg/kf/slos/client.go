@@ -0,0 +1,536 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/metrics"
+	"github.com/google/kf/pkg/kf/routes"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// PrometheusRuleResource is the resource the Prometheus Operator loads
+// recording and alerting rules from.
+var PrometheusRuleResource = schema.GroupVersionResource{
+	Group:    "monitoring.coreos.com",
+	Version:  "v1",
+	Resource: "prometheusrules",
+}
+
+const (
+	// ComponentName is the value of the component label on PrometheusRules
+	// that hold SLOs.
+	ComponentName = "slo"
+
+	// AvailabilityRecord is the series the percentage of requests to the
+	// route's Apps that didn't fail is recorded in.
+	AvailabilityRecord = "kf:slo_availability:percent_rate5m"
+
+	// LatencyRecord is the series the 95th percentile latency of requests to
+	// the route's Apps is recorded in, in milliseconds.
+	LatencyRecord = "kf:slo_latency_p95:milliseconds_rate5m"
+
+	// StatusOK is the status of an SLO meeting all its targets.
+	StatusOK = "OK"
+
+	// StatusViolated is the status of an SLO missing at least one target.
+	StatusViolated = "Violated"
+
+	// StatusNoData is the status of an SLO whose route hasn't served any
+	// requests recently.
+	StatusNoData = "No data"
+
+	hostnameAnnotation     = "slo.kf.dev/hostname"
+	domainAnnotation       = "slo.kf.dev/domain"
+	pathAnnotation         = "slo.kf.dev/path"
+	appsAnnotation         = "slo.kf.dev/apps"
+	availabilityAnnotation = "slo.kf.dev/availability"
+	latencyAnnotation      = "slo.kf.dev/latency-p95"
+
+	// alertFor is how long a target must be missed before the alert fires so
+	// short spikes don't page anyone.
+	alertFor = "10m"
+)
+
+// SLO is a service level objective for a route.
+type SLO struct {
+	// Name is the name of the PrometheusRule holding the SLO.
+	Name string
+
+	// Route is the route the SLO covers.
+	Route v1alpha1.RouteSpecFields
+
+	// Apps are the Apps the route was mapped to when the SLO was created.
+	// Requests they serve are what's measured.
+	Apps []string
+
+	// Availability is the percentage of requests that must not fail with a
+	// 5xx response, 0 if there's no target.
+	Availability float64
+
+	// LatencyP95 is the latency 95% of requests must be served within, 0 if
+	// there's no target.
+	LatencyP95 time.Duration
+}
+
+// SLOName gets the name of the PrometheusRule holding the route's SLO.
+func SLOName(route v1alpha1.RouteSpecFields) string {
+	return "slo-" + v1alpha1.GenerateRouteClaimName(route.Hostname, route.Domain, route.Path)
+}
+
+// ValidateTargets checks at least one target is set and that the set targets
+// are achievable.
+func ValidateTargets(availability float64, latencyP95 time.Duration) error {
+	if availability == 0 && latencyP95 == 0 {
+		return errors.New("at least one of the availability or p95 latency targets must be set")
+	}
+
+	if availability < 0 || availability >= 100 {
+		return fmt.Errorf("the availability target must be greater than 0 and less than 100, got %s", formatFloat(availability))
+	}
+
+	if latencyP95 < 0 || (latencyP95 > 0 && latencyP95 < time.Millisecond) {
+		return fmt.Errorf("the p95 latency target must be at least 1ms, got %s", latencyP95)
+	}
+
+	return nil
+}
+
+// Compliance is how an SLO's route is currently performing.
+type Compliance struct {
+	// Availability is the percentage of requests that didn't fail over the
+	// last 5 minutes, nil if there were no requests.
+	Availability *float64
+
+	// LatencyP95 is the 95th percentile latency over the last 5 minutes, nil
+	// if there were no requests.
+	LatencyP95 *time.Duration
+}
+
+// Status gets StatusViolated if any of the SLO's targets are missed,
+// StatusNoData if none of them could be measured and StatusOK otherwise.
+func (c *Compliance) Status(slo SLO) string {
+	var measured, violated bool
+
+	if slo.Availability > 0 && c.Availability != nil {
+		measured = true
+		violated = violated || *c.Availability < slo.Availability
+	}
+
+	if slo.LatencyP95 > 0 && c.LatencyP95 != nil {
+		measured = true
+		violated = violated || *c.LatencyP95 > slo.LatencyP95
+	}
+
+	switch {
+	case violated:
+		return StatusViolated
+	case !measured:
+		return StatusNoData
+	default:
+		return StatusOK
+	}
+}
+
+// Client manages SLOs.
+type Client interface {
+	// Create creates or updates the SLO for a route. The Name and Apps of the
+	// SLO are filled in from the route which must be mapped to at least one
+	// App.
+	Create(namespace string, slo SLO) (*SLO, error)
+
+	// List gets the SLOs in the space sorted by name.
+	List(namespace string) ([]SLO, error)
+
+	// Compliance queries Prometheus for how the SLO's route is currently
+	// performing.
+	Compliance(namespace string, slo SLO) (*Compliance, error)
+}
+
+type client struct {
+	dynamicClient dynamic.Interface
+	services      v1.ServicesGetter
+	routes        routes.Client
+}
+
+// NewClient creates a new Client.
+func NewClient(
+	dynamicClient dynamic.Interface,
+	services v1.ServicesGetter,
+	routes routes.Client,
+) Client {
+	return &client{
+		dynamicClient: dynamicClient,
+		services:      services,
+		routes:        routes,
+	}
+}
+
+// Create implements Client.
+func (c *client) Create(namespace string, slo SLO) (*SLO, error) {
+	if err := ValidateTargets(slo.Availability, slo.LatencyP95); err != nil {
+		return nil, err
+	}
+
+	routeList, err := c.routes.List(namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Routes: %s", err)
+	}
+
+	slo.Name = SLOName(slo.Route)
+	slo.Apps = mappedApps(routeList, slo.Route)
+	if len(slo.Apps) == 0 {
+		return nil, fmt.Errorf("route %s isn't mapped to any Apps", slo.Route)
+	}
+
+	desired := MakePrometheusRule(namespace, slo)
+
+	rules := c.dynamicClient.Resource(PrometheusRuleResource).Namespace(namespace)
+	existing, err := rules.Get(slo.Name, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		_, err = rules.Create(desired, metav1.CreateOptions{})
+	case err == nil && existing.GetLabels()[v1alpha1.ManagedByLabel] != "kf":
+		return nil, fmt.Errorf("PrometheusRule %s in space %s isn't managed by Kf", slo.Name, namespace)
+	case err == nil:
+		desired.SetResourceVersion(existing.GetResourceVersion())
+		_, err = rules.Update(desired, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save PrometheusRule, is the Prometheus Operator installed? %s", err)
+	}
+
+	return &slo, nil
+}
+
+// List implements Client.
+func (c *client) List(namespace string) ([]SLO, error) {
+	list, err := c.dynamicClient.
+		Resource(PrometheusRuleResource).
+		Namespace(namespace).
+		List(metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(labels.Set{
+				v1alpha1.ManagedByLabel: "kf",
+				v1alpha1.ComponentLabel: ComponentName,
+			}).String(),
+		})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list PrometheusRules, is the Prometheus Operator installed? %s", err)
+	}
+
+	var out []SLO
+	for i := range list.Items {
+		slo, err := sloFromPrometheusRule(&list.Items[i])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *slo)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	return out, nil
+}
+
+// Compliance implements Client.
+func (c *client) Compliance(namespace string, slo SLO) (*Compliance, error) {
+	availability, err := c.query(availabilityQuery(namespace, slo.Apps))
+	if err != nil {
+		return nil, err
+	}
+
+	latencyMillis, err := c.query(latencyQuery(namespace, slo.Apps))
+	if err != nil {
+		return nil, err
+	}
+
+	out := &Compliance{Availability: availability}
+	if latencyMillis != nil {
+		latency := time.Duration(*latencyMillis * float64(time.Millisecond))
+		out.LatencyP95 = &latency
+	}
+
+	return out, nil
+}
+
+func (c *client) query(query string) (*float64, error) {
+	raw, err := c.services.
+		Services(metrics.PrometheusNamespace).
+		ProxyGet("http", metrics.PrometheusService, metrics.PrometheusPort, "/api/v1/query", map[string]string{
+			"query": query,
+		}).
+		DoRaw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Prometheus: %s", err)
+	}
+
+	return parsePrometheusVector(raw)
+}
+
+// mappedApps gets the sorted names of the Apps the route is mapped to.
+func mappedApps(routeList []v1alpha1.Route, route v1alpha1.RouteSpecFields) []string {
+	names := sets.NewString()
+	for _, r := range routeList {
+		if r.GetDeletionTimestamp() != nil || r.Spec.AppName == "" {
+			continue
+		}
+
+		if r.Spec.Hostname != route.Hostname ||
+			r.Spec.Domain != route.Domain ||
+			path.Join("/", r.Spec.Path) != path.Join("/", route.Path) {
+			continue
+		}
+
+		names.Insert(r.Spec.AppName)
+	}
+
+	return names.List()
+}
+
+// MakePrometheusRule creates the PrometheusRule that records the SLO's
+// metrics and alerts when its targets are missed.
+func MakePrometheusRule(namespace string, slo SLO) *unstructured.Unstructured {
+	sloLabels := func(extra map[string]interface{}) map[string]interface{} {
+		out := map[string]interface{}{
+			"slo":   slo.Name,
+			"space": namespace,
+		}
+		for k, v := range extra {
+			out[k] = v
+		}
+		return out
+	}
+
+	rules := []interface{}{
+		map[string]interface{}{
+			"record": AvailabilityRecord,
+			"expr":   availabilityQuery(namespace, slo.Apps),
+			"labels": sloLabels(nil),
+		},
+		map[string]interface{}{
+			"record": LatencyRecord,
+			"expr":   latencyQuery(namespace, slo.Apps),
+			"labels": sloLabels(nil),
+		},
+	}
+
+	annotations := map[string]string{
+		hostnameAnnotation: slo.Route.Hostname,
+		domainAnnotation:   slo.Route.Domain,
+		pathAnnotation:     slo.Route.Path,
+		appsAnnotation:     strings.Join(slo.Apps, ","),
+	}
+
+	if slo.Availability > 0 {
+		target := formatFloat(slo.Availability)
+		annotations[availabilityAnnotation] = target
+		rules = append(rules, map[string]interface{}{
+			"alert": "KfSLOAvailability",
+			"expr":  fmt.Sprintf("%s{slo=%q,space=%q} < %s", AvailabilityRecord, slo.Name, namespace, target),
+			"for":   alertFor,
+			"labels": sloLabels(map[string]interface{}{
+				"severity": "warning",
+			}),
+			"annotations": map[string]interface{}{
+				"summary": fmt.Sprintf("Availability of %s is below %s%%", slo.Route, target),
+			},
+		})
+	}
+
+	if slo.LatencyP95 > 0 {
+		annotations[latencyAnnotation] = slo.LatencyP95.String()
+		rules = append(rules, map[string]interface{}{
+			"alert": "KfSLOLatency",
+			"expr": fmt.Sprintf(
+				"%s{slo=%q,space=%q} > %s",
+				LatencyRecord, slo.Name, namespace, formatFloat(float64(slo.LatencyP95)/float64(time.Millisecond)),
+			),
+			"for": alertFor,
+			"labels": sloLabels(map[string]interface{}{
+				"severity": "warning",
+			}),
+			"annotations": map[string]interface{}{
+				"summary": fmt.Sprintf("95th percentile latency of %s is above %s", slo.Route, slo.LatencyP95),
+			},
+		})
+	}
+
+	rule := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"groups": []interface{}{
+				map[string]interface{}{
+					"name":  slo.Name,
+					"rules": rules,
+				},
+			},
+		},
+	}}
+	rule.SetAPIVersion(PrometheusRuleResource.GroupVersion().String())
+	rule.SetKind("PrometheusRule")
+	rule.SetName(slo.Name)
+	rule.SetNamespace(namespace)
+	rule.SetLabels(map[string]string{
+		v1alpha1.ManagedByLabel: "kf",
+		v1alpha1.ComponentLabel: ComponentName,
+	})
+	rule.SetAnnotations(annotations)
+
+	return rule
+}
+
+// sloFromPrometheusRule reads an SLO back from the annotations
+// MakePrometheusRule set.
+func sloFromPrometheusRule(rule *unstructured.Unstructured) (*SLO, error) {
+	annotations := rule.GetAnnotations()
+
+	slo := &SLO{
+		Name: rule.GetName(),
+		Route: v1alpha1.RouteSpecFields{
+			Hostname: annotations[hostnameAnnotation],
+			Domain:   annotations[domainAnnotation],
+			Path:     annotations[pathAnnotation],
+		},
+	}
+
+	if apps := annotations[appsAnnotation]; apps != "" {
+		slo.Apps = strings.Split(apps, ",")
+	}
+
+	if target := annotations[availabilityAnnotation]; target != "" {
+		availability, err := strconv.ParseFloat(target, 64)
+		if err != nil {
+			return nil, fmt.Errorf("PrometheusRule %s has an invalid availability target: %s", rule.GetName(), err)
+		}
+		slo.Availability = availability
+	}
+
+	if target := annotations[latencyAnnotation]; target != "" {
+		latency, err := time.ParseDuration(target)
+		if err != nil {
+			return nil, fmt.Errorf("PrometheusRule %s has an invalid p95 latency target: %s", rule.GetName(), err)
+		}
+		slo.LatencyP95 = latency
+	}
+
+	return slo, nil
+}
+
+// appSelector matches the metrics Knative's queue proxy reports for the Apps.
+func appSelector(namespace string, apps []string) string {
+	var patterns []string
+	for _, app := range apps {
+		patterns = append(patterns, regexp.QuoteMeta(app))
+	}
+
+	return fmt.Sprintf("namespace_name=%q,service_name=~%q", namespace, strings.Join(patterns, "|"))
+}
+
+// availabilityQuery gets the percentage of requests to the Apps that didn't
+// fail with a 5xx response over the last 5 minutes.
+func availabilityQuery(namespace string, apps []string) string {
+	selector := appSelector(namespace, apps)
+	return fmt.Sprintf(
+		`100 * sum(rate(revision_request_count{%s,response_code_class!="5xx"}[5m])) / sum(rate(revision_request_count{%s}[5m]))`,
+		selector, selector,
+	)
+}
+
+// latencyQuery gets the 95th percentile latency in milliseconds of requests
+// to the Apps over the last 5 minutes.
+func latencyQuery(namespace string, apps []string) string {
+	return fmt.Sprintf(
+		`histogram_quantile(0.95, sum by (le) (rate(revision_request_latencies_bucket{%s}[5m])))`,
+		appSelector(namespace, apps),
+	)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// prometheusResponse is the body of a Prometheus instant query response.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Value []interface{} `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// parsePrometheusVector gets the value of the first series in a Prometheus
+// instant query response. It returns nil if there are no series or the
+// value isn't a number, e.g. because no requests were served.
+func parsePrometheusVector(raw []byte) (*float64, error) {
+	var resp prometheusResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("couldn't parse Prometheus response: %s", err)
+	}
+
+	if resp.Status != "success" {
+		return nil, fmt.Errorf("the Prometheus query failed: %s", resp.Error)
+	}
+
+	if resp.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("expected a vector from Prometheus, got %q", resp.Data.ResultType)
+	}
+
+	if len(resp.Data.Result) == 0 {
+		return nil, nil
+	}
+
+	value := resp.Data.Result[0].Value
+	if len(value) != 2 {
+		return nil, errors.New("expected Prometheus values to be [timestamp, value] pairs")
+	}
+
+	str, ok := value[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("expected Prometheus value to be a string, got %T", value[1])
+	}
+
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Prometheus value %q", str)
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, nil
+	}
+
+	return &f, nil
+}
@@ -0,0 +1,398 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slos
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	routesfake "github.com/google/kf/pkg/kf/routes/fake"
+	"github.com/google/kf/pkg/kf/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	ktesting "k8s.io/client-go/testing"
+)
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
+
+func TestValidateTargets(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		availability float64
+		latencyP95   time.Duration
+		wantErr      error
+	}{
+		"both targets": {
+			availability: 99.9,
+			latencyP95:   300 * time.Millisecond,
+		},
+		"availability only": {
+			availability: 99,
+		},
+		"latency only": {
+			latencyP95: time.Second,
+		},
+		"no targets": {
+			wantErr: errors.New("at least one of the availability or p95 latency targets must be set"),
+		},
+		"availability of 100": {
+			availability: 100,
+			wantErr:      errors.New("the availability target must be greater than 0 and less than 100, got 100"),
+		},
+		"negative availability": {
+			availability: -1,
+			wantErr:      errors.New("the availability target must be greater than 0 and less than 100, got -1"),
+		},
+		"latency under a millisecond": {
+			latencyP95: time.Microsecond,
+			wantErr:    errors.New("the p95 latency target must be at least 1ms, got 1µs"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertErrorsEqual(t, tc.wantErr, ValidateTargets(tc.availability, tc.latencyP95))
+		})
+	}
+}
+
+func TestCompliance_Status(t *testing.T) {
+	t.Parallel()
+
+	slo := SLO{
+		Availability: 99.9,
+		LatencyP95:   300 * time.Millisecond,
+	}
+
+	cases := map[string]struct {
+		slo        SLO
+		compliance Compliance
+		want       string
+	}{
+		"meeting targets": {
+			slo: slo,
+			compliance: Compliance{
+				Availability: float64Ptr(99.95),
+				LatencyP95:   durationPtr(250 * time.Millisecond),
+			},
+			want: StatusOK,
+		},
+		"availability missed": {
+			slo: slo,
+			compliance: Compliance{
+				Availability: float64Ptr(98),
+				LatencyP95:   durationPtr(250 * time.Millisecond),
+			},
+			want: StatusViolated,
+		},
+		"latency missed": {
+			slo: slo,
+			compliance: Compliance{
+				Availability: float64Ptr(100),
+				LatencyP95:   durationPtr(301 * time.Millisecond),
+			},
+			want: StatusViolated,
+		},
+		"no requests": {
+			slo:  slo,
+			want: StatusNoData,
+		},
+		"untargeted measurement ignored": {
+			slo: SLO{Availability: 99},
+			compliance: Compliance{
+				LatencyP95: durationPtr(time.Hour),
+			},
+			want: StatusNoData,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "status", tc.want, tc.compliance.Status(tc.slo))
+		})
+	}
+}
+
+func TestParsePrometheusVector(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		raw     string
+		want    *float64
+		wantErr error
+	}{
+		"value": {
+			raw:  `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1564650000,"99.5"]}]}}`,
+			want: float64Ptr(99.5),
+		},
+		"no series": {
+			raw: `{"status":"success","data":{"resultType":"vector","result":[]}}`,
+		},
+		"NaN": {
+			raw: `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1564650000,"NaN"]}]}}`,
+		},
+		"query error": {
+			raw:     `{"status":"error","error":"parse error"}`,
+			wantErr: errors.New("the Prometheus query failed: parse error"),
+		},
+		"wrong result type": {
+			raw:     `{"status":"success","data":{"resultType":"matrix","result":[]}}`,
+			wantErr: errors.New(`expected a vector from Prometheus, got "matrix"`),
+		},
+		"bad value": {
+			raw:     `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1564650000,"lots"]}]}}`,
+			wantErr: errors.New(`invalid Prometheus value "lots"`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			got, err := parsePrometheusVector([]byte(tc.raw))
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertEqual(t, "value", tc.want, got)
+		})
+	}
+}
+
+func routeFor(appName, hostname, urlPath string) v1alpha1.Route {
+	return v1alpha1.Route{
+		Spec: v1alpha1.RouteSpec{
+			AppName: appName,
+			RouteSpecFields: v1alpha1.RouteSpecFields{
+				Hostname: hostname,
+				Domain:   "example.com",
+				Path:     urlPath,
+			},
+		},
+	}
+}
+
+func TestClient_Create(t *testing.T) {
+	t.Parallel()
+
+	route := v1alpha1.RouteSpecFields{
+		Hostname: "myapp",
+		Domain:   "example.com",
+		Path:     "/api",
+	}
+	name := SLOName(route)
+
+	unmanaged := &unstructured.Unstructured{}
+	unmanaged.SetAPIVersion("monitoring.coreos.com/v1")
+	unmanaged.SetKind("PrometheusRule")
+	unmanaged.SetName(name)
+	unmanaged.SetNamespace("my-space")
+
+	managed := unmanaged.DeepCopy()
+	managed.SetLabels(map[string]string{v1alpha1.ManagedByLabel: "kf"})
+
+	mappedRoutes := []v1alpha1.Route{
+		routeFor("app-b", "myapp", "api"),
+		routeFor("app-a", "myapp", "/api"),
+		routeFor("other", "myapp", "/"),
+		routeFor("other", "other", "/api"),
+		routeFor("", "myapp", "/api"),
+	}
+
+	cases := map[string]struct {
+		slo             SLO
+		routes          []v1alpha1.Route
+		prometheusRules []runtime.Object
+		wantApps        []string
+		wantErr         error
+	}{
+		"creates rule": {
+			slo:      SLO{Route: route, Availability: 99.9, LatencyP95: 300 * time.Millisecond},
+			routes:   mappedRoutes,
+			wantApps: []string{"app-a", "app-b"},
+		},
+		"updates rule": {
+			slo:             SLO{Route: route, Availability: 99},
+			routes:          mappedRoutes,
+			prometheusRules: []runtime.Object{managed},
+			wantApps:        []string{"app-a", "app-b"},
+		},
+		"rule not managed by Kf": {
+			slo:             SLO{Route: route, Availability: 99},
+			routes:          mappedRoutes,
+			prometheusRules: []runtime.Object{unmanaged},
+			wantErr:         errors.New("PrometheusRule " + name + " in space my-space isn't managed by Kf"),
+		},
+		"route not mapped": {
+			slo:     SLO{Route: route, Availability: 99},
+			routes:  mappedRoutes[2:],
+			wantErr: errors.New("route myapp.example.com/api isn't mapped to any Apps"),
+		},
+		"invalid targets": {
+			slo:     SLO{Route: route},
+			wantErr: errors.New("at least one of the availability or p95 latency targets must be set"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			routesClient := routesfake.NewFakeClient(ctrl)
+			routesClient.EXPECT().List("my-space").Return(tc.routes, nil).AnyTimes()
+
+			dynamicClient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), tc.prometheusRules...)
+			client := NewClient(dynamicClient, k8sfake.NewSimpleClientset().CoreV1(), routesClient)
+
+			got, err := client.Create("my-space", tc.slo)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			if err != nil {
+				return
+			}
+
+			testutil.AssertEqual(t, "name", name, got.Name)
+			testutil.AssertEqual(t, "apps", tc.wantApps, got.Apps)
+
+			actual, err := dynamicClient.
+				Resource(PrometheusRuleResource).
+				Namespace("my-space").
+				Get(name, metav1.GetOptions{})
+			testutil.AssertNil(t, "get err", err)
+
+			saved, err := sloFromPrometheusRule(actual)
+			testutil.AssertNil(t, "parse err", err)
+			testutil.AssertEqual(t, "saved SLO", *got, *saved)
+		})
+	}
+}
+
+func TestMakePrometheusRule(t *testing.T) {
+	t.Parallel()
+
+	rule := MakePrometheusRule("my-space", SLO{
+		Name:         "slo-myapp",
+		Route:        v1alpha1.RouteSpecFields{Hostname: "myapp", Domain: "example.com"},
+		Apps:         []string{"app-a", "app-b"},
+		Availability: 99.9,
+		LatencyP95:   300 * time.Millisecond,
+	})
+
+	rules, _, err := unstructured.NestedSlice(rule.Object, "spec", "groups")
+	testutil.AssertNil(t, "groups err", err)
+	testutil.AssertEqual(t, "groups", 1, len(rules))
+
+	group := rules[0].(map[string]interface{})
+	testutil.AssertEqual(t, "rules", 4, len(group["rules"].([]interface{})))
+
+	var exprs []string
+	for _, r := range group["rules"].([]interface{}) {
+		exprs = append(exprs, r.(map[string]interface{})["expr"].(string))
+	}
+
+	testutil.AssertEqual(t, "exprs", []string{
+		`100 * sum(rate(revision_request_count{namespace_name="my-space",service_name=~"app-a|app-b",response_code_class!="5xx"}[5m])) / sum(rate(revision_request_count{namespace_name="my-space",service_name=~"app-a|app-b"}[5m]))`,
+		`histogram_quantile(0.95, sum by (le) (rate(revision_request_latencies_bucket{namespace_name="my-space",service_name=~"app-a|app-b"}[5m])))`,
+		`kf:slo_availability:percent_rate5m{slo="slo-myapp",space="my-space"} < 99.9`,
+		`kf:slo_latency_p95:milliseconds_rate5m{slo="slo-myapp",space="my-space"} > 300`,
+	}, exprs)
+}
+
+func TestClient_List(t *testing.T) {
+	t.Parallel()
+
+	// The fake dynamic client can only list kinds it knows the list type of.
+	scheme := runtime.NewScheme()
+	scheme.AddKnownTypeWithName(
+		schema.GroupVersionKind{Group: "fake-dynamic-client-group", Version: "v1", Kind: "List"},
+		&unstructured.UnstructuredList{},
+	)
+
+	b := SLO{
+		Name:         "slo-b",
+		Route:        v1alpha1.RouteSpecFields{Hostname: "b", Domain: "example.com", Path: "/"},
+		Apps:         []string{"b"},
+		Availability: 99.5,
+	}
+	a := SLO{
+		Name:       "slo-a",
+		Route:      v1alpha1.RouteSpecFields{Domain: "example.com", Path: "/a"},
+		Apps:       []string{"a1", "a2"},
+		LatencyP95: 2 * time.Second,
+	}
+
+	unmanaged := MakePrometheusRule("my-space", SLO{Name: "other"})
+	unmanaged.SetLabels(nil)
+
+	dynamicClient := dynamicfake.NewSimpleDynamicClient(
+		scheme,
+		MakePrometheusRule("my-space", b),
+		MakePrometheusRule("my-space", a),
+		MakePrometheusRule("other-space", SLO{Name: "slo-c"}),
+		unmanaged,
+	)
+	client := NewClient(dynamicClient, k8sfake.NewSimpleClientset().CoreV1(), nil)
+
+	got, err := client.List("my-space")
+	testutil.AssertNil(t, "list err", err)
+	testutil.AssertEqual(t, "slos", []SLO{a, b}, got)
+}
+
+type fakeResponse string
+
+func (r fakeResponse) DoRaw() ([]byte, error) {
+	return []byte(r), nil
+}
+
+func (r fakeResponse) Stream() (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader(string(r))), nil
+}
+
+func TestClient_Compliance(t *testing.T) {
+	t.Parallel()
+
+	k8s := k8sfake.NewSimpleClientset()
+	k8s.PrependProxyReactor("services", func(action ktesting.Action) (bool, rest.ResponseWrapper, error) {
+		query := action.(ktesting.ProxyGetAction).GetParams()["query"]
+		switch {
+		case strings.HasPrefix(query, "100 * "):
+			return true, fakeResponse(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1564650000,"99.95"]}]}}`), nil
+		case strings.HasPrefix(query, "histogram_quantile"):
+			return true, fakeResponse(`{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1564650000,"412.5"]}]}}`), nil
+		default:
+			return true, fakeResponse(`{"status":"error","error":"unexpected query"}`), nil
+		}
+	})
+
+	client := NewClient(dynamicfake.NewSimpleDynamicClient(runtime.NewScheme()), k8s.CoreV1(), nil)
+
+	got, err := client.Compliance("my-space", SLO{Apps: []string{"myapp"}, Availability: 99.9})
+	testutil.AssertNil(t, "compliance err", err)
+	testutil.AssertEqual(t, "availability", 99.95, *got.Availability)
+	testutil.AssertEqual(t, "latency", 412500*time.Microsecond, *got.LatencyP95)
+}
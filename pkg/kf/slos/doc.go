@@ -0,0 +1,20 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package slos manages service level objectives for routes. Each SLO is
+// stored as a PrometheusRule that records the availability and 95th
+// percentile latency of the Apps serving the route and alerts when either
+// misses its target. The rules are loaded by the Prometheus Operator which
+// must be installed on the cluster for alerts to fire.
+package slos
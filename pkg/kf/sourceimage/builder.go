@@ -0,0 +1,220 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceimage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+)
+
+const (
+	// BasePath is the directory source is placed in within the image. The
+	// base image copies it into the build's workspace.
+	BasePath = "/var/run/kontext"
+
+	// DefaultChunkSize is the uncompressed size each source layer is kept
+	// under unless a single file is larger.
+	DefaultChunkSize = 16 * 1024 * 1024
+
+	// defaultBaseImage extracts the source layers when it runs.
+	defaultBaseImage = "gcr.io/mattmoor-public/github.com/mattmoor/kontext/cmd/extractor:latest"
+
+	logPrefix = "\033[32m[source upload]\033[0m "
+)
+
+// Filter selects which paths, relative to the source directory and separated
+// by slashes, are packaged.
+type Filter = func(path string) (bool, error)
+
+// Builder packages source directories into images and pushes them.
+type Builder struct {
+	baseImage string
+	chunkSize int64
+	keychain  authn.Keychain
+	transport http.RoundTripper
+
+	// recordPath is the file completed chunks are recorded in, an empty path
+	// disables recording.
+	recordPath string
+}
+
+// NewBuilder creates a Builder that records completed chunks in the user's
+// cache directory.
+func NewBuilder() *Builder {
+	return &Builder{
+		baseImage:  defaultBaseImage,
+		chunkSize:  DefaultChunkSize,
+		keychain:   authn.DefaultKeychain,
+		transport:  http.DefaultTransport,
+		recordPath: defaultRecordPath(),
+	}
+}
+
+func defaultRecordPath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "kf", "source-chunks.json")
+}
+
+// BuildSrcImage packages the files in dir that pass the filter into an image
+// and pushes it as srcImage. Chunks recorded as uploaded by an earlier
+// attempt are skipped if the registry still has them. All requests are
+// canceled when ctx is done. Progress is written to out.
+func (b *Builder) BuildSrcImage(ctx context.Context, out io.Writer, dir, srcImage string, filter Filter) error {
+	logger := log.New(out, logPrefix, 0)
+
+	tag, err := name.NewTag(srcImage, name.WeakValidation)
+	if err != nil {
+		return fmt.Errorf("invalid image %q: %v", srcImage, err)
+	}
+
+	baseRef, err := name.ParseReference(b.baseImage, name.WeakValidation)
+	if err != nil {
+		return fmt.Errorf("invalid base image %q: %v", b.baseImage, err)
+	}
+
+	entries, err := collectEntries(dir, filter)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return errors.New("no source files to upload")
+	}
+
+	var layers []v1.Layer
+	for _, chunk := range splitChunks(entries, b.chunkSize) {
+		layer, err := chunkLayer(chunk)
+		if err != nil {
+			return fmt.Errorf("couldn't package source: %v", err)
+		}
+		layers = append(layers, layer)
+	}
+
+	logger.Printf("Uploading %s to image %s in %d chunks", dir, srcImage, len(layers))
+
+	t := &contextTransport{ctx: ctx, inner: b.transport}
+	auth, err := b.keychain.Resolve(tag.Registry)
+	if err != nil {
+		return fmt.Errorf("couldn't get credentials for %s: %v", tag.Registry, err)
+	}
+
+	reg, err := newRegistryClient(tag.Context(), auth, t, transport.PushScope)
+	if err != nil {
+		return fmt.Errorf("couldn't connect to %s: %v", tag.Registry, err)
+	}
+
+	if err := b.uploadChunks(ctx, logger, reg, layers); err != nil {
+		return err
+	}
+
+	base, err := remote.Image(baseRef, remote.WithAuthFromKeychain(b.keychain), remote.WithTransport(t))
+	if err != nil {
+		return fmt.Errorf("couldn't fetch base image %s: %v", b.baseImage, err)
+	}
+
+	img, err := mutate.AppendLayers(base, layers...)
+	if err != nil {
+		return fmt.Errorf("couldn't append source layers: %v", err)
+	}
+
+	// The source layers are already in the registry so this only sends the
+	// base image layers the registry is missing, the config and the manifest.
+	if err := remote.Write(tag, img, auth, t); err != nil {
+		return fmt.Errorf("couldn't publish image: %v", err)
+	}
+
+	logger.Printf("Published %s", srcImage)
+	return nil
+}
+
+// uploadChunks uploads each layer that the registry doesn't have, recording
+// the layers as they complete.
+func (b *Builder) uploadChunks(ctx context.Context, logger *log.Logger, reg *registryClient, layers []v1.Layer) error {
+	repository := reg.repo.String()
+
+	var record *chunkRecord
+	if b.recordPath != "" {
+		// A corrupt record shouldn't block uploads, it just means previously
+		// completed chunks are sent again.
+		record, _ = readChunkRecord(b.recordPath)
+	}
+
+	for i, layer := range layers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		digest, err := layer.Digest()
+		if err != nil {
+			return err
+		}
+
+		if record != nil && record.Has(repository, digest.String()) {
+			// The registry may have garbage collected the chunk since it was
+			// recorded, so the record is only trusted if the blob is still
+			// there.
+			exists, err := reg.blobExists(digest)
+			if err != nil {
+				return fmt.Errorf("couldn't check chunk %d of %d: %v", i+1, len(layers), err)
+			}
+
+			if exists {
+				logger.Printf("Chunk %d of %d was uploaded previously, skipping", i+1, len(layers))
+				continue
+			}
+
+			record.Remove(repository, digest.String())
+		}
+
+		size, err := layer.Size()
+		if err != nil {
+			return err
+		}
+
+		logger.Printf("Uploading chunk %d of %d (%s)", i+1, len(layers), formatSize(size))
+		if err := reg.uploadBlob(layer); err != nil {
+			return fmt.Errorf("couldn't upload chunk %d of %d: %v", i+1, len(layers), err)
+		}
+
+		if record != nil {
+			record.Add(repository, digest.String())
+			if err := record.Write(b.recordPath); err != nil {
+				logger.Printf("couldn't record chunk, a retry will upload it again: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func formatSize(bytes int64) string {
+	return fmt.Sprintf("%.1f MiB", float64(bytes)/(1024*1024))
+}
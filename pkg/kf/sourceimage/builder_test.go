@@ -0,0 +1,246 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceimage
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+type anonymousKeychain struct{}
+
+func (anonymousKeychain) Resolve(name.Registry) (authn.Authenticator, error) {
+	return authn.Anonymous, nil
+}
+
+// failingTransport fails the nth single request blob upload.
+type failingTransport struct {
+	failOn int32
+	puts   int32
+}
+
+func (t *failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodPut && strings.Contains(req.URL.Path, "/blobs/uploads/") {
+		if atomic.AddInt32(&t.puts, 1) == t.failOn {
+			return nil, errors.New("connection reset")
+		}
+	}
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type builderFixture struct {
+	builder  *Builder
+	registry *fakeRegistry
+	host     string
+	srcDir   string
+}
+
+func newBuilderFixture(t *testing.T) *builderFixture {
+	t.Helper()
+
+	registry := newFakeRegistry()
+	server := httptest.NewServer(registry)
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	base, err := random.Image(64, 1)
+	testutil.AssertNil(t, "random.Image", err)
+	baseTag, err := name.NewTag(host+"/extractor:latest", name.WeakValidation)
+	testutil.AssertNil(t, "NewTag", err)
+	testutil.AssertNil(t, "Write", remote.Write(baseTag, base, authn.Anonymous, http.DefaultTransport))
+
+	srcDir, err := ioutil.TempDir("", "source-image")
+	testutil.AssertNil(t, "TempDir", err)
+	t.Cleanup(func() { os.RemoveAll(srcDir) })
+
+	for name, contents := range map[string]string{
+		"a.txt":       "aaaaaaaaaa",
+		"b.txt":       "bbbbbbbbbb",
+		"sub/c.txt":   "cccccccccc",
+		".git/config": "ignored",
+	} {
+		path := filepath.Join(srcDir, filepath.FromSlash(name))
+		testutil.AssertNil(t, "MkdirAll", os.MkdirAll(filepath.Dir(path), 0700))
+		testutil.AssertNil(t, "WriteFile", ioutil.WriteFile(path, []byte(contents), 0600))
+	}
+
+	cacheDir, err := ioutil.TempDir("", "source-image-cache")
+	testutil.AssertNil(t, "TempDir", err)
+	t.Cleanup(func() { os.RemoveAll(cacheDir) })
+
+	return &builderFixture{
+		builder: &Builder{
+			baseImage:  baseTag.String(),
+			chunkSize:  15,
+			keychain:   anonymousKeychain{},
+			transport:  http.DefaultTransport,
+			recordPath: filepath.Join(cacheDir, "kf", "source-chunks.json"),
+		},
+		registry: registry,
+		host:     host,
+		srcDir:   srcDir,
+	}
+}
+
+func ignoreGit(path string) (bool, error) {
+	return path != ".git", nil
+}
+
+func imageFiles(t *testing.T, image string) []string {
+	t.Helper()
+
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	testutil.AssertNil(t, "ParseReference", err)
+	img, err := remote.Image(ref)
+	testutil.AssertNil(t, "remote.Image", err)
+
+	var files []string
+	tr := tar.NewReader(mutate.Extract(img))
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		testutil.AssertNil(t, "Next", err)
+
+		if strings.HasPrefix(header.Name, BasePath) {
+			files = append(files, header.Name)
+		}
+	}
+	sort.Strings(files)
+
+	return files
+}
+
+func TestBuilder_BuildSrcImage(t *testing.T) {
+	fixture := newBuilderFixture(t)
+	image := fixture.host + "/src-ns-app:1"
+
+	out := &bytes.Buffer{}
+	err := fixture.builder.BuildSrcImage(context.Background(), out, fixture.srcDir, image, ignoreGit)
+	testutil.AssertNil(t, "BuildSrcImage", err)
+
+	testutil.AssertEqual(t, "files", []string{
+		BasePath + "/a.txt",
+		BasePath + "/b.txt",
+		BasePath + "/sub",
+		BasePath + "/sub/c.txt",
+	}, imageFiles(t, image))
+
+	testutil.AssertContainsAll(t, out.String(), []string{
+		"[source upload]",
+		"Uploading " + fixture.srcDir + " to image " + image + " in 3 chunks",
+		"Uploading chunk 1 of 3",
+		"Uploading chunk 3 of 3",
+		"Published " + image,
+	})
+}
+
+func TestBuilder_BuildSrcImage_resume(t *testing.T) {
+	fixture := newBuilderFixture(t)
+	image := fixture.host + "/src-ns-app:1"
+
+	fixture.builder.transport = &failingTransport{failOn: 2}
+	err := fixture.builder.BuildSrcImage(context.Background(), &bytes.Buffer{}, fixture.srcDir, image, ignoreGit)
+	if err == nil || !strings.Contains(err.Error(), "couldn't upload chunk 2 of 3") {
+		t.Fatalf("expected chunk 2 to fail, got %v", err)
+	}
+
+	fixture.builder.transport = http.DefaultTransport
+	out := &bytes.Buffer{}
+	err = fixture.builder.BuildSrcImage(context.Background(), out, fixture.srcDir, image, ignoreGit)
+	testutil.AssertNil(t, "BuildSrcImage", err)
+
+	testutil.AssertContainsAll(t, out.String(), []string{
+		"Chunk 1 of 3 was uploaded previously, skipping",
+		"Uploading chunk 2 of 3",
+		"Uploading chunk 3 of 3",
+	})
+	if strings.Contains(out.String(), "Uploading chunk 1 of 3") {
+		t.Fatalf("expected chunk 1 to be skipped, got:\n%s", out.String())
+	}
+}
+
+func TestBuilder_BuildSrcImage_missingChunk(t *testing.T) {
+	fixture := newBuilderFixture(t)
+	image := fixture.host + "/src-ns-app:1"
+
+	err := fixture.builder.BuildSrcImage(context.Background(), &bytes.Buffer{}, fixture.srcDir, image, ignoreGit)
+	testutil.AssertNil(t, "BuildSrcImage", err)
+
+	record, err := readChunkRecord(fixture.builder.recordPath)
+	testutil.AssertNil(t, "readChunkRecord", err)
+	chunks := record.Repositories[fixture.host+"/src-ns-app"]
+	testutil.AssertEqual(t, "recorded chunks", 3, len(chunks))
+
+	// Chunks the registry no longer has must be uploaded again even though
+	// they were recorded.
+	fixture.registry.DeleteBlob(chunks[0])
+
+	out := &bytes.Buffer{}
+	err = fixture.builder.BuildSrcImage(context.Background(), out, fixture.srcDir, image, ignoreGit)
+	testutil.AssertNil(t, "BuildSrcImage", err)
+	testutil.AssertContainsAll(t, out.String(), []string{
+		"Uploading chunk 1 of 3",
+		"Chunk 2 of 3 was uploaded previously, skipping",
+		"Chunk 3 of 3 was uploaded previously, skipping",
+	})
+}
+
+func TestBuilder_BuildSrcImage_canceled(t *testing.T) {
+	fixture := newBuilderFixture(t)
+	requests := len(fixture.registry.Requests())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := fixture.builder.BuildSrcImage(ctx, &bytes.Buffer{}, fixture.srcDir, fixture.host+"/src-ns-app:1", ignoreGit)
+	if err == nil || !strings.Contains(err.Error(), context.Canceled.Error()) {
+		t.Fatalf("expected the upload to be canceled, got %v", err)
+	}
+	testutil.AssertEqual(t, "requests", requests, len(fixture.registry.Requests()))
+}
+
+func TestImageExists(t *testing.T) {
+	fixture := newBuilderFixture(t)
+
+	exists, err := ImageExists(context.Background(), fixture.host+"/extractor:latest")
+	testutil.AssertNil(t, "ImageExists", err)
+	testutil.AssertEqual(t, "pushed image exists", true, exists)
+
+	exists, err = ImageExists(context.Background(), fixture.host+"/extractor:missing")
+	testutil.AssertNil(t, "ImageExists", err)
+	testutil.AssertEqual(t, "missing image exists", false, exists)
+}
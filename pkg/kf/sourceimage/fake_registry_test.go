@@ -0,0 +1,171 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceimage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// fakeRegistry implements the parts of the Docker registry HTTP API used to
+// push and pull images.
+type fakeRegistry struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte
+	manifests map[string]fakeManifest
+	uploads   map[string]*bytes.Buffer
+	requests  []string
+}
+
+type fakeManifest struct {
+	mediaType string
+	contents  []byte
+}
+
+func newFakeRegistry() *fakeRegistry {
+	return &fakeRegistry{
+		blobs:     make(map[string][]byte),
+		manifests: make(map[string]fakeManifest),
+		uploads:   make(map[string]*bytes.Buffer),
+	}
+}
+
+// Requests returns the method and path of each request received.
+func (f *fakeRegistry) Requests() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append([]string(nil), f.requests...)
+}
+
+// DeleteBlob removes a blob as if it were garbage collected.
+func (f *fakeRegistry) DeleteBlob(digest string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.blobs, digest)
+}
+
+func (f *fakeRegistry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+	if r.URL.Path == "/v2/" {
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	switch {
+	case strings.Contains(r.URL.Path, "/blobs/uploads/"):
+		f.serveUpload(w, r, body)
+	case strings.Contains(r.URL.Path, "/blobs/"):
+		digest := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		contents, ok := f.blobs[digest]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(contents)))
+		w.Write(contents)
+	case strings.Contains(r.URL.Path, "/manifests/"):
+		f.serveManifest(w, r, body)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeRegistry) serveUpload(w http.ResponseWriter, r *http.Request, body []byte) {
+	base := r.URL.Path[:strings.Index(r.URL.Path, "/blobs/uploads/")+len("/blobs/uploads/")]
+	id := strings.TrimPrefix(r.URL.Path, base)
+
+	switch r.Method {
+	case http.MethodPost:
+		id = fmt.Sprint(len(f.uploads) + 1)
+		f.uploads[id] = &bytes.Buffer{}
+		w.Header().Set("Location", base+id)
+		w.WriteHeader(http.StatusAccepted)
+
+	case http.MethodPatch, http.MethodPut:
+		upload, ok := f.uploads[id]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		upload.Write(body)
+
+		if r.Method == http.MethodPatch {
+			w.Header().Set("Location", base+id)
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		digest := r.URL.Query().Get("digest")
+		if digest != sha256Digest(upload.Bytes()) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.blobs[digest] = upload.Bytes()
+		delete(f.uploads, id)
+		w.WriteHeader(http.StatusCreated)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeRegistry) serveManifest(w http.ResponseWriter, r *http.Request, body []byte) {
+	key := r.URL.Path
+	repository := key[:strings.LastIndex(key, "/")+1]
+
+	switch r.Method {
+	case http.MethodPut:
+		m := fakeManifest{mediaType: r.Header.Get("Content-Type"), contents: body}
+		f.manifests[key] = m
+		f.manifests[repository+sha256Digest(body)] = m
+		w.WriteHeader(http.StatusCreated)
+
+	case http.MethodHead, http.MethodGet:
+		m, ok := f.manifests[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", m.mediaType)
+		w.Header().Set("Docker-Content-Digest", sha256Digest(m.contents))
+		w.Header().Set("Content-Length", fmt.Sprint(len(m.contents)))
+		w.Write(m.contents)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func sha256Digest(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,220 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceimage
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+)
+
+// entry is a file or directory packaged into the image.
+type entry struct {
+	// name is the path relative to the source directory, separated by
+	// slashes.
+	name string
+
+	// path is the location of the entry on disk.
+	path string
+
+	info os.FileInfo
+}
+
+// epoch is the modification time of every packaged entry so the time source
+// was checked out or edited doesn't change the image.
+var epoch = time.Unix(0, 0)
+
+// Digest computes a digest of the files in dir that pass the filter. It
+// covers exactly what BuildSrcImage packages, so source with the same digest
+// produces the same layers.
+func Digest(dir string, filter Filter) (string, error) {
+	entries, err := collectEntries(dir, filter)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	tw := tar.NewWriter(hash)
+	for _, e := range entries {
+		if err := writeEntry(tw, e); err != nil {
+			return "", err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// collectEntries lists the directories and regular files under dir that pass
+// the filter, sorted by name. Symlinks are followed so the files they point
+// to are packaged.
+func collectEntries(dir string, filter Filter) ([]entry, error) {
+	var entries []entry
+
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+		include, err := filter(rel)
+		if err != nil {
+			return err
+		}
+
+		if !include {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		info, err = os.Stat(p)
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+
+		entries = append(entries, entry{name: rel, path: p, info: info})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].name < entries[j].name
+	})
+
+	return entries, nil
+}
+
+// splitChunks groups entries into chunks whose files add up to at most size
+// bytes. Files are never split, a file larger than size is a chunk of its
+// own.
+func splitChunks(entries []entry, size int64) [][]entry {
+	var chunks [][]entry
+	var current []entry
+	var currentSize int64
+
+	for _, e := range entries {
+		fileSize := int64(0)
+		if e.info.Mode().IsRegular() {
+			fileSize = e.info.Size()
+		}
+
+		if len(current) > 0 && currentSize+fileSize > size {
+			chunks = append(chunks, current)
+			current, currentSize = nil, 0
+		}
+
+		current = append(current, e)
+		currentSize += fileSize
+	}
+
+	if len(current) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	return chunks
+}
+
+// chunkLayer creates an image layer holding the entries under BasePath.
+func chunkLayer(entries []entry) (v1.Layer, error) {
+	buf := &bytes.Buffer{}
+	tw := tar.NewWriter(buf)
+
+	for _, e := range entries {
+		if err := writeEntry(tw, e); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+
+	contents := buf.Bytes()
+	return tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(contents)), nil
+	})
+}
+
+// writeEntry adds the entry to the archive. Only the name, contents and
+// whether the file is executable are kept, times, ownership and the rest of
+// the mode are fixed so the archive is the same on every machine.
+func writeEntry(tw *tar.Writer, e entry) error {
+	header := &tar.Header{
+		Name:    path.Join(BasePath, e.name),
+		Mode:    0644,
+		ModTime: epoch,
+		Uid:     0,
+		Gid:     0,
+		Format:  tar.FormatPAX,
+	}
+
+	if e.info.IsDir() {
+		header.Typeflag = tar.TypeDir
+		header.Mode = 0755
+		return tw.WriteHeader(header)
+	}
+
+	if e.info.Mode().Perm()&0111 != 0 {
+		header.Mode = 0755
+	}
+
+	header.Typeflag = tar.TypeReg
+	header.Size = e.info.Size()
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	f, err := os.Open(e.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Copy exactly the size in the header in case the file changes while
+	// it's being read.
+	_, err = io.CopyN(tw, f, header.Size)
+	return err
+}
@@ -0,0 +1,173 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceimage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func entryNames(chunks [][]entry) [][]string {
+	var out [][]string
+	for _, chunk := range chunks {
+		var names []string
+		for _, e := range chunk {
+			names = append(names, e.name)
+		}
+		out = append(out, names)
+	}
+
+	return out
+}
+
+func TestSplitChunks(t *testing.T) {
+	dir, err := ioutil.TempDir("", "split-chunks")
+	testutil.AssertNil(t, "TempDir", err)
+	defer os.RemoveAll(dir)
+
+	for name, size := range map[string]int{
+		"big.bin":   40,
+		"empty.txt": 0,
+		"one.txt":   10,
+		"pkg/a.go":  10,
+		"pkg/b.go":  10,
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		testutil.AssertNil(t, "MkdirAll", os.MkdirAll(filepath.Dir(path), 0700))
+		testutil.AssertNil(t, "WriteFile", ioutil.WriteFile(path, make([]byte, size), 0600))
+	}
+
+	entries, err := collectEntries(dir, func(string) (bool, error) { return true, nil })
+	testutil.AssertNil(t, "collectEntries", err)
+
+	testutil.AssertEqual(t, "chunks", [][]string{
+		{"big.bin"},
+		{"empty.txt", "one.txt", "pkg", "pkg/a.go"},
+		{"pkg/b.go"},
+	}, entryNames(splitChunks(entries, 20)))
+}
+
+func TestCollectEntries_filter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "collect-entries")
+	testutil.AssertNil(t, "TempDir", err)
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"main.go", "node_modules/dep/index.js", "static/app.js"} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		testutil.AssertNil(t, "MkdirAll", os.MkdirAll(filepath.Dir(path), 0700))
+		testutil.AssertNil(t, "WriteFile", ioutil.WriteFile(path, []byte(name), 0600))
+	}
+
+	entries, err := collectEntries(dir, func(path string) (bool, error) {
+		return path != "node_modules", nil
+	})
+	testutil.AssertNil(t, "collectEntries", err)
+
+	testutil.AssertEqual(t, "entries", [][]string{
+		{"main.go", "static", "static/app.js"},
+	}, entryNames([][]entry{entries}))
+}
+
+func writeSource(t *testing.T, dir string, perm os.FileMode, modTime time.Time) {
+	t.Helper()
+
+	for name, contents := range map[string]string{
+		"main.go":    "package main",
+		"go.mod":     "module example.com/app",
+		"a-b/x.txt":  "dash",
+		"a/b/y.txt":  "slash",
+		"run.sh":     "#!/bin/sh",
+		".git/index": "ignored",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		testutil.AssertNil(t, "MkdirAll", os.MkdirAll(filepath.Dir(path), 0700|perm))
+		testutil.AssertNil(t, "WriteFile", ioutil.WriteFile(path, []byte(contents), perm))
+		testutil.AssertNil(t, "Chtimes", os.Chtimes(path, modTime, modTime))
+	}
+
+	testutil.AssertNil(t, "Chmod", os.Chmod(filepath.Join(dir, "run.sh"), 0700|perm))
+}
+
+func layerDigests(t *testing.T, dir string) []string {
+	t.Helper()
+
+	entries, err := collectEntries(dir, ignoreGit)
+	testutil.AssertNil(t, "collectEntries", err)
+
+	var digests []string
+	for _, chunk := range splitChunks(entries, 20) {
+		layer, err := chunkLayer(chunk)
+		testutil.AssertNil(t, "chunkLayer", err)
+		digest, err := layer.Digest()
+		testutil.AssertNil(t, "Digest", err)
+		digests = append(digests, digest.String())
+	}
+
+	return digests
+}
+
+func TestDigest_reproducible(t *testing.T) {
+	first, err := ioutil.TempDir("", "digest-first")
+	testutil.AssertNil(t, "TempDir", err)
+	defer os.RemoveAll(first)
+	writeSource(t, first, 0600, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	// The same source checked out at a different time with a different umask.
+	second, err := ioutil.TempDir("", "digest-second")
+	testutil.AssertNil(t, "TempDir", err)
+	defer os.RemoveAll(second)
+	writeSource(t, second, 0644, time.Now())
+
+	firstDigest, err := Digest(first, ignoreGit)
+	testutil.AssertNil(t, "Digest", err)
+	secondDigest, err := Digest(second, ignoreGit)
+	testutil.AssertNil(t, "Digest", err)
+
+	testutil.AssertEqual(t, "digest", firstDigest, secondDigest)
+	testutil.AssertEqual(t, "layers", layerDigests(t, first), layerDigests(t, second))
+}
+
+func TestDigest_changes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "digest")
+	testutil.AssertNil(t, "TempDir", err)
+	defer os.RemoveAll(dir)
+	writeSource(t, dir, 0600, time.Now())
+
+	digest := func() string {
+		d, err := Digest(dir, ignoreGit)
+		testutil.AssertNil(t, "Digest", err)
+		return d
+	}
+	original := digest()
+
+	testutil.AssertNil(t, "WriteFile", ioutil.WriteFile(filepath.Join(dir, ".git", "HEAD"), []byte("ref"), 0600))
+	testutil.AssertEqual(t, "ignored files don't change digest", original, digest())
+
+	testutil.AssertNil(t, "Chmod", os.Chmod(filepath.Join(dir, "main.go"), 0700))
+	executable := digest()
+	if executable == original {
+		t.Fatal("expected making a file executable to change the digest")
+	}
+
+	testutil.AssertNil(t, "WriteFile", ioutil.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // changed"), 0700))
+	if digest() == executable {
+		t.Fatal("expected changed contents to change the digest")
+	}
+}
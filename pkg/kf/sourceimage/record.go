@@ -0,0 +1,107 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceimage
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// maxRecordedChunks is the number of chunks remembered for each repository,
+// the oldest are forgotten first.
+const maxRecordedChunks = 1000
+
+// chunkRecord lists the chunks each repository was sent so a retried or
+// interrupted upload can skip them.
+type chunkRecord struct {
+	// Repositories maps a repository to the digests of the chunks uploaded
+	// to it, oldest first.
+	Repositories map[string][]string `json:"repositories"`
+}
+
+func readChunkRecord(path string) (*chunkRecord, error) {
+	record := &chunkRecord{Repositories: make(map[string][]string)}
+
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return record, err
+	}
+
+	if err := json.Unmarshal(contents, record); err != nil {
+		return &chunkRecord{Repositories: make(map[string][]string)}, err
+	}
+
+	if record.Repositories == nil {
+		record.Repositories = make(map[string][]string)
+	}
+
+	return record, nil
+}
+
+// Has returns true if the chunk was recorded as uploaded to the repository.
+func (r *chunkRecord) Has(repository, digest string) bool {
+	for _, d := range r.Repositories[repository] {
+		if d == digest {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Add records the chunk as uploaded to the repository.
+func (r *chunkRecord) Add(repository, digest string) {
+	r.Remove(repository, digest)
+
+	digests := append(r.Repositories[repository], digest)
+	if len(digests) > maxRecordedChunks {
+		digests = digests[len(digests)-maxRecordedChunks:]
+	}
+
+	r.Repositories[repository] = digests
+}
+
+// Remove forgets the chunk was uploaded to the repository.
+func (r *chunkRecord) Remove(repository, digest string) {
+	var digests []string
+	for _, d := range r.Repositories[repository] {
+		if d != digest {
+			digests = append(digests, d)
+		}
+	}
+
+	if len(digests) == 0 {
+		delete(r.Repositories, repository)
+		return
+	}
+
+	r.Repositories[repository] = digests
+}
+
+// Write saves the record to the given path.
+func (r *chunkRecord) Write(path string) error {
+	contents, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, contents, 0600)
+}
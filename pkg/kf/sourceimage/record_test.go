@@ -0,0 +1,72 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceimage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestChunkRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chunk-record")
+	testutil.AssertNil(t, "TempDir", err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "kf", "source-chunks.json")
+
+	record, err := readChunkRecord(path)
+	if !os.IsNotExist(err) {
+		t.Fatalf("expected missing record error, got %v", err)
+	}
+
+	record.Add("gcr.io/proj/src-ns-app", "sha256:1")
+	record.Add("gcr.io/proj/src-ns-app", "sha256:2")
+	record.Add("gcr.io/proj/src-ns-other", "sha256:1")
+	record.Remove("gcr.io/proj/src-ns-app", "sha256:1")
+	testutil.AssertNil(t, "Write", record.Write(path))
+
+	record, err = readChunkRecord(path)
+	testutil.AssertNil(t, "readChunkRecord", err)
+	testutil.AssertEqual(t, "removed", false, record.Has("gcr.io/proj/src-ns-app", "sha256:1"))
+	testutil.AssertEqual(t, "kept", true, record.Has("gcr.io/proj/src-ns-app", "sha256:2"))
+	testutil.AssertEqual(t, "other repository", true, record.Has("gcr.io/proj/src-ns-other", "sha256:1"))
+}
+
+func TestChunkRecord_Add_limit(t *testing.T) {
+	record := &chunkRecord{Repositories: make(map[string][]string)}
+	for i := 0; i <= maxRecordedChunks; i++ {
+		record.Add("repo", string(rune('a'+i%26))+string(rune(i)))
+	}
+
+	testutil.AssertEqual(t, "recorded", maxRecordedChunks, len(record.Repositories["repo"]))
+	testutil.AssertEqual(t, "oldest forgotten", false, record.Has("repo", "a"+string(rune(0))))
+}
+
+func TestReadChunkRecord_corrupt(t *testing.T) {
+	f, err := ioutil.TempFile("", "chunk-record")
+	testutil.AssertNil(t, "TempFile", err)
+	defer os.Remove(f.Name())
+	f.WriteString("{not json")
+	f.Close()
+
+	record, err := readChunkRecord(f.Name())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	testutil.AssertEqual(t, "record is usable", false, record.Has("repo", "sha256:1"))
+}
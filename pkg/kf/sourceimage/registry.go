@@ -0,0 +1,180 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sourceimage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+// contextTransport cancels every request it sends when ctx is done.
+type contextTransport struct {
+	ctx   context.Context
+	inner http.RoundTripper
+}
+
+var _ http.RoundTripper = (*contextTransport)(nil)
+
+// RoundTrip implements http.RoundTripper.
+func (t *contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.inner.RoundTrip(req.WithContext(t.ctx))
+}
+
+// registryClient makes requests to a single repository using the Docker
+// registry HTTP API.
+type registryClient struct {
+	repo   name.Repository
+	client *http.Client
+}
+
+func newRegistryClient(repo name.Repository, auth authn.Authenticator, t http.RoundTripper, scope string) (*registryClient, error) {
+	rt, err := transport.New(repo.Registry, auth, t, []string{repo.Scope(scope)})
+	if err != nil {
+		return nil, err
+	}
+
+	return &registryClient{repo: repo, client: &http.Client{Transport: rt}}, nil
+}
+
+func (r *registryClient) url(resource string) *url.URL {
+	return &url.URL{
+		Scheme: r.repo.Registry.Scheme(),
+		Host:   r.repo.RegistryStr(),
+		Path:   fmt.Sprintf("/v2/%s/%s", r.repo.RepositoryStr(), resource),
+	}
+}
+
+func (r *registryClient) exists(resource string, accept ...string) (bool, error) {
+	req, err := http.NewRequest(http.MethodHead, r.url(resource).String(), nil)
+	if err != nil {
+		return false, err
+	}
+
+	if len(accept) > 0 {
+		req.Header.Set("Accept", strings.Join(accept, ","))
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if err := transport.CheckError(resp, http.StatusOK, http.StatusNotFound); err != nil {
+		return false, err
+	}
+
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// blobExists returns true if the repository has the blob.
+func (r *registryClient) blobExists(digest v1.Hash) (bool, error) {
+	return r.exists("blobs/" + digest.String())
+}
+
+// manifestExists returns true if the repository has a manifest for the tag
+// or digest.
+func (r *registryClient) manifestExists(reference string) (bool, error) {
+	return r.exists(
+		"manifests/"+reference,
+		string(types.DockerManifestSchema2),
+		string(types.OCIManifestSchema1),
+	)
+}
+
+// uploadBlob sends the compressed contents of the layer in a single request.
+func (r *registryClient) uploadBlob(layer v1.Layer) error {
+	digest, err := layer.Digest()
+	if err != nil {
+		return err
+	}
+
+	size, err := layer.Size()
+	if err != nil {
+		return err
+	}
+
+	start := r.url("blobs/uploads/")
+	resp, err := r.client.Post(start.String(), "", nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if err := transport.CheckError(resp, http.StatusAccepted); err != nil {
+		return err
+	}
+
+	location, err := start.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		return fmt.Errorf("invalid upload location: %v", err)
+	}
+
+	query := location.Query()
+	query.Set("digest", digest.String())
+	location.RawQuery = query.Encode()
+
+	blob, err := layer.Compressed()
+	if err != nil {
+		return err
+	}
+	defer blob.Close()
+
+	req, err := http.NewRequest(http.MethodPut, location.String(), blob)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err = r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return transport.CheckError(resp, http.StatusCreated)
+}
+
+// ImageExists returns true if the image's registry has it. Credentials are
+// taken from the default keychain.
+func ImageExists(ctx context.Context, image string) (bool, error) {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return false, fmt.Errorf("invalid image %q: %v", image, err)
+	}
+
+	auth, err := authn.DefaultKeychain.Resolve(ref.Context().Registry)
+	if err != nil {
+		return false, err
+	}
+
+	t := &contextTransport{ctx: ctx, inner: http.DefaultTransport}
+	reg, err := newRegistryClient(ref.Context(), auth, t, transport.PullScope)
+	if err != nil {
+		return false, err
+	}
+
+	return reg.manifestExists(ref.Identifier())
+}
@@ -21,12 +21,15 @@ import (
 	"io"
 
 	cv1alpha1 "github.com/google/kf/pkg/client/clientset/versioned/typed/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/buildlogs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // ClientExtension holds additional functions that should be exposed by client.
 type ClientExtension interface {
 	Tail(ctx context.Context, namespace, name string, writer io.Writer) error
 	Status(namespace, name string) (bool, error)
+	SetImageDigest(namespace, name, digest string) error
 }
 
 // BuildTailer is implemented by github.com/google/kf/third_party/knative-build/pkg/logs
@@ -38,15 +41,18 @@ type sourcesClient struct {
 	coreClient
 
 	buildTailer BuildTailer
+	archive     buildlogs.Archive
 }
 
-// NewClient creates a new build client.
-func NewClient(kclient cv1alpha1.SourcesGetter, buildTailer BuildTailer) Client {
+// NewClient creates a new build client. Logs are read from the archive if the
+// build they came from is gone.
+func NewClient(kclient cv1alpha1.SourcesGetter, buildTailer BuildTailer, archive buildlogs.Archive) Client {
 	return &sourcesClient{
 		coreClient: coreClient{
 			kclient: kclient,
 		},
 		buildTailer: buildTailer,
+		archive:     archive,
 	}
 }
 
@@ -61,6 +67,20 @@ func (c *sourcesClient) Status(namespace, name string) (bool, error) {
 	return SourceStatus(*bld)
 }
 
+// SetImageDigest records a new digest for the image the source built, e.g.
+// after the image was rebased. Apps running the source's image pick up the
+// digest the next time they're reconciled.
+func (c *sourcesClient) SetImageDigest(namespace, name, digest string) error {
+	source, err := c.kclient.Sources(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	source.Status.ImageDigest = digest
+	_, err = c.kclient.Sources(namespace).UpdateStatus(source)
+	return err
+}
+
 // Tail streams the build logs to a local writer.
 func (c *sourcesClient) Tail(ctx context.Context, namespace, name string, writer io.Writer) error {
 	bld, err := c.coreClient.Get(namespace, name)
@@ -74,5 +94,23 @@ func (c *sourcesClient) Tail(ctx context.Context, namespace, name string, writer
 	}
 
 	fmt.Fprintf(writer, "Logs for %s (backed by build: %s)\n", name, buildName)
-	return c.buildTailer.Tail(ctx, writer, buildName, namespace)
+	tailErr := c.buildTailer.Tail(ctx, writer, buildName, namespace)
+
+	archiveURL := bld.BuildLogArchive()
+	if tailErr == nil || archiveURL == "" || c.archive == nil {
+		return tailErr
+	}
+
+	// The build or its pod may have been cleaned up, fall back to the logs
+	// that were archived when it completed.
+	fmt.Fprintf(writer, "Couldn't read logs from the build (%v), reading the archive at %s\n", tailErr, archiveURL)
+
+	logs, err := c.archive.Open(ctx, archiveURL)
+	if err != nil {
+		return fmt.Errorf("couldn't read archived logs: %v", err)
+	}
+	defer logs.Close()
+
+	_, err = io.Copy(writer, logs)
+	return err
 }
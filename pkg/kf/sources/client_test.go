@@ -0,0 +1,141 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sources
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kffake "github.com/google/kf/pkg/client/clientset/versioned/fake"
+	"github.com/google/kf/pkg/kf/buildlogs"
+	"github.com/google/kf/pkg/kf/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeArchive is an in-memory buildlogs.Archive.
+type fakeArchive map[string]string
+
+func (a fakeArchive) Upload(ctx context.Context, url string, logs io.Reader) error {
+	contents, err := ioutil.ReadAll(logs)
+	a[url] = string(contents)
+	return err
+}
+
+func (a fakeArchive) Open(ctx context.Context, url string) (io.ReadCloser, error) {
+	logs, ok := a[url]
+	if !ok {
+		return nil, buildlogs.ErrNotFound
+	}
+
+	return ioutil.NopCloser(strings.NewReader(logs)), nil
+}
+
+func TestSourcesClient_Tail(t *testing.T) {
+	const archiveURL = "gs://build-logs/some-namespace/some-source.log"
+
+	cases := map[string]struct {
+		archived   string
+		tailErr    error
+		archive    fakeArchive
+		wantErr    error
+		wantOutput []string
+	}{
+		"build logs": {
+			wantOutput: []string{"Logs for some-source (backed by build: some-build)", "live logs"},
+		},
+		"build gone without archive": {
+			tailErr: errors.New("build pod some-build-pod no longer exists"),
+			wantErr: errors.New("build pod some-build-pod no longer exists"),
+		},
+		"build gone with archive": {
+			archived: archiveURL,
+			tailErr:  errors.New("build pod some-build-pod no longer exists"),
+			archive:  fakeArchive{archiveURL: "archived logs"},
+			wantOutput: []string{
+				"Couldn't read logs from the build (build pod some-build-pod no longer exists), reading the archive at " + archiveURL,
+				"archived logs",
+			},
+		},
+		"archive missing logs": {
+			archived: archiveURL,
+			tailErr:  errors.New("build pod some-build-pod no longer exists"),
+			archive:  fakeArchive{},
+			wantErr:  errors.New("couldn't read archived logs: build logs not found in the archive"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			source := &v1alpha1.Source{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "some-source",
+					Namespace: "some-namespace",
+				},
+			}
+			source.Status.BuildName = "some-build"
+			if tc.archived != "" {
+				source.Annotations = map[string]string{v1alpha1.BuildLogArchiveAnnotation: tc.archived}
+			}
+
+			tailer := BuildTailerFunc(func(ctx context.Context, out io.Writer, buildName, namespace string) error {
+				if tc.tailErr != nil {
+					return tc.tailErr
+				}
+
+				_, err := io.WriteString(out, "live logs\n")
+				return err
+			})
+
+			client := NewClient(kffake.NewSimpleClientset(source).KfV1alpha1(), tailer, tc.archive)
+
+			out := &bytes.Buffer{}
+			err := client.Tail(context.Background(), "some-namespace", "some-source", out)
+
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+			testutil.AssertContainsAll(t, out.String(), tc.wantOutput)
+		})
+	}
+}
+
+func TestSourcesClient_SetImageDigest(t *testing.T) {
+	source := &v1alpha1.Source{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "some-source",
+			Namespace: "some-namespace",
+		},
+	}
+	source.Status.Image = "gcr.io/some-app"
+	source.Status.ImageDigest = "sha256:built"
+
+	kclient := kffake.NewSimpleClientset(source).KfV1alpha1()
+	client := NewClient(kclient, nil, nil)
+
+	err := client.SetImageDigest("some-namespace", "some-source", "sha256:rebased")
+	testutil.AssertNil(t, "err", err)
+
+	actual, err := kclient.Sources("some-namespace").Get("some-source", metav1.GetOptions{})
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "image", "gcr.io/some-app", actual.Status.Image)
+	testutil.AssertEqual(t, "digest", "sha256:rebased", actual.Status.ImageDigest)
+
+	err = client.SetImageDigest("some-namespace", "missing-source", "sha256:rebased")
+	testutil.AssertNotNil(t, "err", err)
+}
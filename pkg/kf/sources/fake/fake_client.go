@@ -131,6 +131,41 @@ func (mr *FakeClientMockRecorder) List(arg0 interface{}, arg1 ...interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*FakeClient)(nil).List), varargs...)
 }
 
+// ListPage mocks base method
+func (m *FakeClient) ListPage(arg0 string, arg1 ...sources.ListOption) ([]v1alpha1.Source, string, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListPage", varargs...)
+	ret0, _ := ret[0].([]v1alpha1.Source)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPage indicates an expected call of ListPage
+func (mr *FakeClientMockRecorder) ListPage(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*FakeClient)(nil).ListPage), varargs...)
+}
+
+// SetImageDigest mocks base method
+func (m *FakeClient) SetImageDigest(arg0, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetImageDigest", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetImageDigest indicates an expected call of SetImageDigest
+func (mr *FakeClientMockRecorder) SetImageDigest(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetImageDigest", reflect.TypeOf((*FakeClient)(nil).SetImageDigest), arg0, arg1, arg2)
+}
+
 // Status mocks base method
 func (m *FakeClient) Status(arg0, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
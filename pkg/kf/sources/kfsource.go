@@ -128,6 +128,16 @@ func (k *KfSource) GetDockerfileImage() string {
 	return k.Spec.Dockerfile.Image
 }
 
+// SetDockerfileBuildArgs sets the build args for dockerfile based builds.
+func (k *KfSource) SetDockerfileBuildArgs(args []corev1.EnvVar) {
+	k.Spec.Dockerfile.BuildArgs = args
+}
+
+// GetDockerfileBuildArgs gets the build args for dockerfile based builds.
+func (k *KfSource) GetDockerfileBuildArgs() []corev1.EnvVar {
+	return k.Spec.Dockerfile.BuildArgs
+}
+
 // SetBuildpackBuildStack sets the stack to use with a buildpack build.
 func (k *KfSource) SetBuildpackBuildStack(stack string) {
 	k.Spec.BuildpackBuild.Stack = stack
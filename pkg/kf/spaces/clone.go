@@ -0,0 +1,151 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"sort"
+	"strings"
+
+	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TemplateDomain rewrites a domain of the src space for the dest space by
+// replacing each DNS label equal to the name of src with the name of dest,
+// e.g. api.dev.example.com becomes api.staging.example.com when cloning dev
+// to staging. Domains that don't mention src are returned unchanged.
+func TemplateDomain(domain, src, dest string) string {
+	labels := strings.Split(domain, ".")
+	for i, label := range labels {
+		if label == src {
+			labels[i] = dest
+		}
+	}
+
+	return strings.Join(labels, ".")
+}
+
+// CloneSpace creates a Space named dest with the configuration of src. The
+// domains of src are rewritten with TemplateDomain.
+func CloneSpace(src *v1alpha1.Space, dest string) *v1alpha1.Space {
+	clone := &v1alpha1.Space{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        dest,
+			Labels:      copyMap(src.Labels),
+			Annotations: copyMap(src.Annotations),
+		},
+	}
+	src.Spec.DeepCopyInto(&clone.Spec)
+
+	for i := range clone.Spec.Execution.Domains {
+		domain := &clone.Spec.Execution.Domains[i]
+		domain.Domain = TemplateDomain(domain.Domain, src.Name, dest)
+	}
+
+	return clone
+}
+
+// CloneApp creates a copy of the App to be pushed into the dest space. The
+// App's source is kept so it's rebuilt from the source image it was last
+// pushed with, and its routes are rewritten with TemplateDomain.
+//
+// Service instances belong to the space they were created in so the App's
+// bindings aren't copied.
+func CloneApp(app *v1alpha1.App, src, dest string) *v1alpha1.App {
+	clone := &v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        app.Name,
+			Namespace:   dest,
+			Labels:      copyMap(app.Labels),
+			Annotations: copyMap(app.Annotations),
+		},
+	}
+	app.Spec.DeepCopyInto(&clone.Spec)
+	clone.Spec.ServiceBindings = nil
+
+	for i := range clone.Spec.Routes {
+		route := &clone.Spec.Routes[i]
+		route.Domain = TemplateDomain(route.Domain, src, dest)
+	}
+
+	return clone
+}
+
+// SecretReferences returns the sorted names of the Secrets that the space
+// and Apps read values from. Cloned configuration keeps referencing the
+// Secrets by name, so they have to exist in the space the configuration is
+// cloned to.
+func SecretReferences(space *v1alpha1.Space, apps []v1alpha1.App) []string {
+	refs := make(map[string]bool)
+	addEnv := func(env []corev1.EnvVar) {
+		for _, e := range env {
+			if e.ValueFrom != nil && e.ValueFrom.SecretKeyRef != nil {
+				refs[e.ValueFrom.SecretKeyRef.Name] = true
+			}
+		}
+	}
+
+	addEnv(space.Spec.Execution.Env)
+	addEnv(space.Spec.BuildpackBuild.Env)
+
+	for _, app := range apps {
+		addEnv(app.Spec.Source.BuildpackBuild.Env)
+		addEnv(app.Spec.Source.Dockerfile.BuildArgs)
+
+		for _, container := range app.Spec.Template.Spec.Containers {
+			addEnv(container.Env)
+
+			for _, envFrom := range container.EnvFrom {
+				if envFrom.SecretRef != nil {
+					refs[envFrom.SecretRef.Name] = true
+				}
+			}
+		}
+
+		for _, volume := range app.Spec.Template.Spec.Volumes {
+			if volume.Secret != nil {
+				refs[volume.Secret.SecretName] = true
+			}
+		}
+
+		for _, restart := range app.Spec.RestartOnChange {
+			if restart.Kind == v1alpha1.RestartOnChangeKindSecret {
+				refs[restart.Name] = true
+			}
+		}
+	}
+
+	var names []string
+	for name := range refs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func copyMap(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+
+	return out
+}
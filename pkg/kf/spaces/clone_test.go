@@ -0,0 +1,172 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"testing"
+
+	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTemplateDomain(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		domain string
+		want   string
+	}{
+		"space label": {
+			domain: "api.dev.example.com",
+			want:   "api.staging.example.com",
+		},
+		"leading label": {
+			domain: "dev.example.com",
+			want:   "staging.example.com",
+		},
+		"partial label": {
+			domain: "devices.example.com",
+			want:   "devices.example.com",
+		},
+		"no mention": {
+			domain: "example.com",
+			want:   "example.com",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "domain", tc.want, TemplateDomain(tc.domain, "dev", "staging"))
+		})
+	}
+}
+
+func TestCloneSpace(t *testing.T) {
+	t.Parallel()
+
+	src := &v1alpha1.Space{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "dev",
+			ResourceVersion: "123",
+			Labels:          map[string]string{"team": "payments"},
+		},
+		Spec: v1alpha1.SpaceSpec{
+			Execution: v1alpha1.SpaceSpecExecution{
+				Domains: []v1alpha1.SpaceDomain{
+					{Domain: "dev.example.com", Default: true},
+					{Domain: "shared.example.com"},
+				},
+			},
+			Security: v1alpha1.SpaceSpecSecurity{
+				RequiredNetworkPolicies: []string{"deny-all"},
+			},
+		},
+	}
+
+	clone := CloneSpace(src, "staging")
+
+	testutil.AssertEqual(t, "name", "staging", clone.Name)
+	testutil.AssertEqual(t, "resource version", "", clone.ResourceVersion)
+	testutil.AssertEqual(t, "labels", src.Labels, clone.Labels)
+	testutil.AssertEqual(t, "domains", []v1alpha1.SpaceDomain{
+		{Domain: "staging.example.com", Default: true},
+		{Domain: "shared.example.com"},
+	}, clone.Spec.Execution.Domains)
+	testutil.AssertEqual(t, "network policies", []string{"deny-all"}, clone.Spec.Security.RequiredNetworkPolicies)
+	testutil.AssertEqual(t, "src domain", "dev.example.com", src.Spec.Execution.Domains[0].Domain)
+}
+
+func TestCloneApp(t *testing.T) {
+	t.Parallel()
+
+	app := &v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-app",
+			Namespace: "dev",
+			UID:       "some-uid",
+		},
+		Spec: v1alpha1.AppSpec{
+			Source: v1alpha1.SourceSpec{
+				BuildpackBuild: v1alpha1.SourceSpecBuildpackBuild{
+					Source: "gcr.io/foo/src-dev-my-app:abc",
+				},
+			},
+			Routes: []v1alpha1.RouteSpecFields{
+				{Hostname: "my-app", Domain: "dev.example.com"},
+			},
+			ServiceBindings: []v1alpha1.AppSpecServiceBinding{
+				{Instance: "my-db"},
+			},
+		},
+	}
+
+	clone := CloneApp(app, "dev", "staging")
+
+	testutil.AssertEqual(t, "name", "my-app", clone.Name)
+	testutil.AssertEqual(t, "namespace", "staging", clone.Namespace)
+	testutil.AssertEqual(t, "uid", "", string(clone.UID))
+	testutil.AssertEqual(t, "source", "gcr.io/foo/src-dev-my-app:abc", clone.Spec.Source.BuildpackBuild.Source)
+	testutil.AssertEqual(t, "routes", []v1alpha1.RouteSpecFields{
+		{Hostname: "my-app", Domain: "staging.example.com"},
+	}, clone.Spec.Routes)
+	testutil.AssertEqual(t, "bindings", 0, len(clone.Spec.ServiceBindings))
+}
+
+func TestSecretReferences(t *testing.T) {
+	t.Parallel()
+
+	secretEnv := func(name string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: "SECRET",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: name},
+					Key:                  "value",
+				},
+			},
+		}
+	}
+
+	space := &v1alpha1.Space{
+		Spec: v1alpha1.SpaceSpec{
+			Execution: v1alpha1.SpaceSpecExecution{
+				Env: []corev1.EnvVar{secretEnv("space-creds"), {Name: "PLAIN", Value: "x"}},
+			},
+		},
+	}
+
+	apps := []v1alpha1.App{
+		{
+			Spec: v1alpha1.AppSpec{
+				Template: v1alpha1.AppSpecTemplate{
+					Spec: corev1.PodSpec{
+						Containers: []corev1.Container{{
+							Env: []corev1.EnvVar{secretEnv("db-creds")},
+						}},
+					},
+				},
+				RestartOnChange: []v1alpha1.AppSpecRestartOnChange{
+					{Kind: v1alpha1.RestartOnChangeKindSecret, Name: "api-key"},
+					{Kind: v1alpha1.RestartOnChangeKindConfigMap, Name: "settings"},
+					{Kind: v1alpha1.RestartOnChangeKindSecret, Name: "db-creds"},
+				},
+			},
+		},
+	}
+
+	testutil.AssertEqual(t, "secrets", []string{"api-key", "db-creds", "space-creds"}, SecretReferences(space, apps))
+}
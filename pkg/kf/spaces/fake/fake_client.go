@@ -129,6 +129,26 @@ func (mr *FakeClientMockRecorder) List(arg0 ...interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*FakeClient)(nil).List), arg0...)
 }
 
+// ListPage mocks base method
+func (m *FakeClient) ListPage(arg0 ...spaces.ListOption) ([]v1alpha1.Space, string, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{}
+	for _, a := range arg0 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListPage", varargs...)
+	ret0, _ := ret[0].([]v1alpha1.Space)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListPage indicates an expected call of ListPage
+func (mr *FakeClientMockRecorder) ListPage(arg0 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPage", reflect.TypeOf((*FakeClient)(nil).ListPage), arg0...)
+}
+
 // Transform mocks base method
 func (m *FakeClient) Transform(arg0 string, arg1 spaces.Mutator) (*v1alpha1.Space, error) {
 	m.ctrl.T.Helper()
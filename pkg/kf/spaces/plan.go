@@ -0,0 +1,125 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/diffutil"
+)
+
+// restartFields are spec paths that are copied into every App's pods, so
+// changing them rolls out new instances of all Apps in the space.
+var restartFields = []string{
+	".execution.env",
+}
+
+// restageFields are spec paths that are copied into App builds. Apps don't
+// rebuild automatically when they change, they report that they need to be
+// restaged instead.
+var restageFields = []string{
+	".buildpackBuild.builderImage",
+	".buildpackBuild.containerRegistry",
+	".buildpackBuild.env",
+	".buildpackBuild.defaultStack",
+	".buildpackBuild.buildpacks",
+	".security.buildServiceAccount",
+}
+
+// Plan describes the changes needed to move a space to a desired
+// configuration.
+type Plan struct {
+	// Hash identifies the configuration the plan was made against.
+	Hash string `json:"hash"`
+
+	// Changes are the fields of the spec that would change.
+	Changes []diffutil.Change `json:"changes"`
+
+	// RestartsApps is true if reconciling the change restarts the Apps in
+	// the space.
+	RestartsApps bool `json:"restartsApps"`
+
+	// RequiresRestage is true if Apps must be restaged to pick up the change.
+	RequiresRestage bool `json:"requiresRestage"`
+}
+
+// NewPlan computes the changes needed to move the space's spec to desired.
+func NewPlan(current *v1alpha1.Space, desired v1alpha1.SpaceSpec) (*Plan, error) {
+	hash, err := SpecHash(current.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := diffutil.Compute(current.Spec, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		Hash:    hash,
+		Changes: changes,
+	}
+
+	for _, change := range changes {
+		plan.RestartsApps = plan.RestartsApps || matchesAny(change.Path, restartFields)
+		plan.RequiresRestage = plan.RequiresRestage || matchesAny(change.Path, restageFields)
+	}
+
+	return plan, nil
+}
+
+// SpecHash returns a digest of the spec that changes whenever any field of
+// the spec does.
+func SpecHash(spec v1alpha1.SpaceSpec) (string, error) {
+	// encoding/json sorts map keys so the output is stable.
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("couldn't hash space spec: %s", err)
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256(raw)), nil
+}
+
+// ExpectHashWrapper wraps a mutator so it fails if the space's spec doesn't
+// match the expected hash before being mutated.
+func ExpectHashWrapper(expected string, mutator Mutator) Mutator {
+	return func(space *v1alpha1.Space) error {
+		actual, err := SpecHash(space.Spec)
+		if err != nil {
+			return err
+		}
+
+		if actual != expected {
+			return fmt.Errorf("space %q changed since the plan was made: expected hash %s, got %s", space.Name, expected, actual)
+		}
+
+		return mutator(space)
+	}
+}
+
+// matchesAny returns true if path is one of the fields or nested inside one.
+func matchesAny(path string, fields []string) bool {
+	for _, field := range fields {
+		if path == field || strings.HasPrefix(path, field+".") || strings.HasPrefix(path, field+"[") {
+			return true
+		}
+	}
+
+	return false
+}
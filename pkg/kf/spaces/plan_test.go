@@ -0,0 +1,142 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package spaces
+
+import (
+	"errors"
+	"testing"
+
+	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/envutil"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestNewPlan(t *testing.T) {
+	t.Parallel()
+
+	current := &v1alpha1.Space{
+		Spec: v1alpha1.SpaceSpec{
+			BuildpackBuild: v1alpha1.SpaceSpecBuildpackBuild{
+				ContainerRegistry: "gcr.io/foo",
+			},
+			Execution: v1alpha1.SpaceSpecExecution{
+				Env: envutil.MapToEnvVars(map[string]string{"PROFILE": "dev"}),
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		mutate      func(spec *v1alpha1.SpaceSpec)
+		wantPaths   []string
+		wantRestart bool
+		wantRestage bool
+	}{
+		"no changes": {
+			mutate: func(spec *v1alpha1.SpaceSpec) {},
+		},
+		"execution env restarts apps": {
+			mutate: func(spec *v1alpha1.SpaceSpec) {
+				spec.Execution.Env[0].Value = "prod"
+			},
+			wantPaths:   []string{".execution.env[0].value"},
+			wantRestart: true,
+		},
+		"registry requires restage": {
+			mutate: func(spec *v1alpha1.SpaceSpec) {
+				spec.BuildpackBuild.ContainerRegistry = "gcr.io/bar"
+			},
+			wantPaths:   []string{".buildpackBuild.containerRegistry"},
+			wantRestage: true,
+		},
+		"build limit changes nothing in apps": {
+			mutate: func(spec *v1alpha1.SpaceSpec) {
+				spec.BuildpackBuild.MaxConcurrentBuilds = 3
+			},
+			wantPaths: []string{".buildpackBuild.maxConcurrentBuilds"},
+		},
+		"added domains": {
+			mutate: func(spec *v1alpha1.SpaceSpec) {
+				spec.Execution.Domains = []v1alpha1.SpaceDomain{{Domain: "example.com"}}
+			},
+			wantPaths: []string{".execution.domains"},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			desired := current.DeepCopy().Spec
+			tc.mutate(&desired)
+
+			plan, err := NewPlan(current, desired)
+			testutil.AssertNil(t, "err", err)
+
+			var paths []string
+			for _, change := range plan.Changes {
+				paths = append(paths, change.Path)
+			}
+
+			testutil.AssertEqual(t, "paths", tc.wantPaths, paths)
+			testutil.AssertEqual(t, "restarts apps", tc.wantRestart, plan.RestartsApps)
+			testutil.AssertEqual(t, "requires restage", tc.wantRestage, plan.RequiresRestage)
+
+			wantHash, err := SpecHash(current.Spec)
+			testutil.AssertNil(t, "hash err", err)
+			testutil.AssertEqual(t, "hash", wantHash, plan.Hash)
+		})
+	}
+}
+
+func TestSpecHash(t *testing.T) {
+	t.Parallel()
+
+	spec := v1alpha1.SpaceSpec{}
+	first, err := SpecHash(spec)
+	testutil.AssertNil(t, "err", err)
+
+	second, err := SpecHash(spec)
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "stable hash", first, second)
+
+	spec.Security.EnableSSH = true
+	changed, err := SpecHash(spec)
+	testutil.AssertNil(t, "err", err)
+	if changed == first {
+		t.Errorf("expected hash to change when the spec changes")
+	}
+}
+
+func TestExpectHashWrapper(t *testing.T) {
+	t.Parallel()
+
+	space := &v1alpha1.Space{}
+	space.Name = "my-space"
+
+	hash, err := SpecHash(space.Spec)
+	testutil.AssertNil(t, "err", err)
+
+	called := false
+	mutator := func(*v1alpha1.Space) error {
+		called = true
+		return nil
+	}
+
+	testutil.AssertNil(t, "matching hash", ExpectHashWrapper(hash, mutator)(space))
+	testutil.AssertEqual(t, "called", true, called)
+
+	called = false
+	gotErr := ExpectHashWrapper("stale", mutator)(space)
+	testutil.AssertErrorsEqual(t, errors.New(`space "my-space" changed since the plan was made: expected hash stale, got `+hash), gotErr)
+	testutil.AssertEqual(t, "called", false, called)
+}
@@ -172,6 +172,10 @@ type listConfig struct {
 	fieldSelector map[string]string
 	// filter is Filter to apply.
 	filter Predicate
+	// limit is Maximum number of results the server should return, zero for all.
+	limit int64
+	// continueToken is Token returned by a previous page to continue listing from.
+	continueToken string
 }
 
 // ListOption is a single option for configuring a listConfig
@@ -212,6 +216,18 @@ func (opts ListOptions) filter() Predicate {
 	return opts.toConfig().filter
 }
 
+// limit returns the last set value for limit or the empty value
+// if not set.
+func (opts ListOptions) limit() int64 {
+	return opts.toConfig().limit
+}
+
+// continueToken returns the last set value for continueToken or the empty value
+// if not set.
+func (opts ListOptions) continueToken() string {
+	return opts.toConfig().continueToken
+}
+
 // WithListFieldSelector creates an Option that sets A selector on the resource's fields.
 func WithListFieldSelector(val map[string]string) ListOption {
 	return func(cfg *listConfig) {
@@ -226,6 +242,20 @@ func WithListFilter(val Predicate) ListOption {
 	}
 }
 
+// WithListLimit creates an Option that sets Maximum number of results the server should return, zero for all.
+func WithListLimit(val int64) ListOption {
+	return func(cfg *listConfig) {
+		cfg.limit = val
+	}
+}
+
+// WithListContinueToken creates an Option that sets Token returned by a previous page to continue listing from.
+func WithListContinueToken(val string) ListOption {
+	return func(cfg *listConfig) {
+		cfg.continueToken = val
+	}
+}
+
 // ListOptionDefaults gets the default values for List.
 func ListOptionDefaults() ListOptions {
 	return ListOptions{}
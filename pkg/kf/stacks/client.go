@@ -0,0 +1,157 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// Client manages the Stacks configured on the cluster.
+type Client interface {
+	// List returns the Stacks configured on the cluster.
+	List() ([]Stack, error)
+
+	// Create adds a Stack to the cluster.
+	Create(stack Stack) error
+
+	// Delete removes a Stack from the cluster.
+	Delete(name string) error
+}
+
+type client struct {
+	configMaps v1.ConfigMapsGetter
+}
+
+// NewClient creates a new Client.
+func NewClient(configMaps v1.ConfigMapsGetter) Client {
+	return &client{
+		configMaps: configMaps,
+	}
+}
+
+// List implements Client.
+func (c *client) List() ([]Stack, error) {
+	cm, _, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := NewConfigFromConfigMap(cm)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg.Stacks, nil
+}
+
+// Create implements Client.
+func (c *client) Create(stack Stack) error {
+	if err := stack.Validate(); err != nil {
+		return err
+	}
+
+	return c.update(func(cfg *Config) error {
+		if _, ok := cfg.Find(stack.Name); ok {
+			return fmt.Errorf("stack %s already exists", stack.Name)
+		}
+
+		cfg.Stacks = append(cfg.Stacks, stack)
+		return nil
+	})
+}
+
+// Delete implements Client.
+func (c *client) Delete(name string) error {
+	return c.update(func(cfg *Config) error {
+		var kept []Stack
+		for _, s := range cfg.Stacks {
+			if s.Name != name {
+				kept = append(kept, s)
+			}
+		}
+
+		if len(kept) == len(cfg.Stacks) {
+			return fmt.Errorf("stack %s doesn't exist", name)
+		}
+
+		cfg.Stacks = kept
+		return nil
+	})
+}
+
+// get fetches the stacks ConfigMap, an empty one is returned if it doesn't
+// exist yet.
+func (c *client) get() (cm *corev1.ConfigMap, exists bool, err error) {
+	cm, err = c.configMaps.
+		ConfigMaps(ConfigMapNamespace).
+		Get(ConfigMapName, metav1.GetOptions{})
+	switch {
+	case apierrs.IsNotFound(err):
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      ConfigMapName,
+				Namespace: ConfigMapNamespace,
+			},
+		}, false, nil
+	case err != nil:
+		return nil, false, err
+	default:
+		return cm, true, nil
+	}
+}
+
+// update applies the mutator to the Config and saves it, creating the
+// ConfigMap if needed.
+func (c *client) update(mutator func(cfg *Config) error) error {
+	cm, exists, err := c.get()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := NewConfigFromConfigMap(cm)
+	if err != nil {
+		return err
+	}
+
+	if err := mutator(cfg); err != nil {
+		return err
+	}
+
+	data, err := cfg.ToMap()
+	if err != nil {
+		return err
+	}
+
+	// Keep other keys like the _example block.
+	if cm.Data == nil {
+		cm.Data = make(map[string]string)
+	}
+	for k, v := range data {
+		cm.Data[k] = v
+	}
+
+	if exists {
+		_, err = c.configMaps.ConfigMaps(ConfigMapNamespace).Update(cm)
+	} else {
+		_, err = c.configMaps.ConfigMaps(ConfigMapNamespace).Create(cm)
+	}
+
+	return err
+}
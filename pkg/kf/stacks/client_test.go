@@ -0,0 +1,130 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestClient(t *testing.T) {
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName,
+			Namespace: ConfigMapNamespace,
+		},
+		Data: map[string]string{
+			"_example": "some docs",
+			"stacks":   `[{"name": "a", "buildImage": "build-a", "runImage": "run-a"}]`,
+		},
+	}
+
+	stackB := Stack{Name: "b", BuildImage: "build-b", RunImage: "run-b"}
+
+	cases := map[string]struct {
+		objects    []runtime.Object
+		run        func(t *testing.T, c Client) error
+		wantErr    error
+		wantStacks []Stack
+	}{
+		"list missing ConfigMap": {
+			run: func(t *testing.T, c Client) error {
+				return nil
+			},
+		},
+		"create without ConfigMap": {
+			run: func(t *testing.T, c Client) error {
+				return c.Create(stackB)
+			},
+			wantStacks: []Stack{stackB},
+		},
+		"create appends": {
+			objects: []runtime.Object{existing.DeepCopy()},
+			run: func(t *testing.T, c Client) error {
+				return c.Create(stackB)
+			},
+			wantStacks: []Stack{
+				{Name: "a", BuildImage: "build-a", RunImage: "run-a"},
+				stackB,
+			},
+		},
+		"create duplicate": {
+			objects: []runtime.Object{existing.DeepCopy()},
+			run: func(t *testing.T, c Client) error {
+				return c.Create(Stack{Name: "a", BuildImage: "x", RunImage: "y"})
+			},
+			wantErr: errors.New("stack a already exists"),
+		},
+		"create invalid": {
+			run: func(t *testing.T, c Client) error {
+				return c.Create(Stack{Name: "a"})
+			},
+			wantErr: errors.New("stack a must have a build image"),
+		},
+		"delete": {
+			objects: []runtime.Object{existing.DeepCopy()},
+			run: func(t *testing.T, c Client) error {
+				return c.Delete("a")
+			},
+		},
+		"delete missing": {
+			objects: []runtime.Object{existing.DeepCopy()},
+			run: func(t *testing.T, c Client) error {
+				return c.Delete("b")
+			},
+			wantErr: errors.New("stack b doesn't exist"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			k8s := k8sfake.NewSimpleClientset(tc.objects...)
+			c := NewClient(k8s.CoreV1())
+
+			gotErr := tc.run(t, c)
+			if tc.wantErr != nil || gotErr != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, gotErr)
+				return
+			}
+
+			stacks, err := c.List()
+			testutil.AssertNil(t, "List error", err)
+			testutil.AssertEqual(t, "stacks", tc.wantStacks, stacks)
+		})
+	}
+}
+
+func TestClient_keepsOtherKeys(t *testing.T) {
+	k8s := k8sfake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ConfigMapName,
+			Namespace: ConfigMapNamespace,
+		},
+		Data: map[string]string{"_example": "some docs"},
+	})
+
+	err := NewClient(k8s.CoreV1()).Create(Stack{Name: "a", BuildImage: "b", RunImage: "r"})
+	testutil.AssertNil(t, "Create error", err)
+
+	cm, err := k8s.CoreV1().ConfigMaps(ConfigMapNamespace).Get(ConfigMapName, metav1.GetOptions{})
+	testutil.AssertNil(t, "Get error", err)
+	testutil.AssertEqual(t, "example", "some docs", cm.Data["_example"])
+}
@@ -0,0 +1,156 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/configmap"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap in the kf namespace that
+	// holds the Stacks.
+	ConfigMapName = "config-stacks"
+
+	// ConfigMapNamespace is the namespace the stacks ConfigMap lives in.
+	ConfigMapNamespace = "kf"
+
+	stacksKey = "stacks"
+)
+
+// Stack is a pair of images buildpack apps are built with and run on.
+type Stack struct {
+	// Name is used by spaces and apps to choose the Stack.
+	Name string `json:"name"`
+
+	// Description is shown to developers listing the Stacks.
+	Description string `json:"description,omitempty"`
+
+	// BuildImage is a buildpacks.io builder image.
+	BuildImage string `json:"buildImage"`
+
+	// RunImage is the image built apps use as their base.
+	RunImage string `json:"runImage"`
+}
+
+// Validate checks that the Stack has a name and both images.
+func (s *Stack) Validate() error {
+	switch {
+	case s.Name == "":
+		return errors.New("stacks must have a name")
+	case s.BuildImage == "":
+		return fmt.Errorf("stack %s must have a build image", s.Name)
+	case s.RunImage == "":
+		return fmt.Errorf("stack %s must have a run image", s.Name)
+	}
+
+	return nil
+}
+
+// Config holds the Stacks configured on the cluster.
+type Config struct {
+	Stacks []Stack
+}
+
+// Find returns the Stack with the given name.
+func (c *Config) Find(name string) (*Stack, bool) {
+	if c == nil || name == "" {
+		return nil, false
+	}
+
+	for i := range c.Stacks {
+		if c.Stacks[i].Name == name {
+			return &c.Stacks[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// ToMap converts the Config into the data of the stacks ConfigMap.
+func (c *Config) ToMap() (map[string]string, error) {
+	out, err := yaml.Marshal(c.Stacks)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{stacksKey: string(out)}, nil
+}
+
+// NewConfigFromMap creates a Config from the data of the stacks ConfigMap.
+func NewConfigFromMap(data map[string]string) (*Config, error) {
+	cfg := &Config{}
+
+	if raw, ok := data[stacksKey]; ok {
+		if err := yaml.Unmarshal([]byte(raw), &cfg.Stacks); err != nil {
+			return nil, fmt.Errorf("couldn't parse %s: %v", stacksKey, err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range cfg.Stacks {
+		if err := s.Validate(); err != nil {
+			return nil, err
+		}
+
+		if seen[s.Name] {
+			return nil, fmt.Errorf("stack %s is defined more than once", s.Name)
+		}
+		seen[s.Name] = true
+	}
+
+	return cfg, nil
+}
+
+// NewConfigFromConfigMap creates a Config from the stacks ConfigMap.
+func NewConfigFromConfigMap(cm *corev1.ConfigMap) (*Config, error) {
+	return NewConfigFromMap(cm.Data)
+}
+
+// Store holds the latest Config read from the cluster so reconcilers can
+// resolve Stacks without fetching the ConfigMap. The zero value holds an
+// empty Config.
+type Store struct {
+	config atomic.Value
+}
+
+// Load returns the latest Config.
+func (s *Store) Load() *Config {
+	if cfg, ok := s.config.Load().(*Config); ok {
+		return cfg
+	}
+
+	return &Config{}
+}
+
+// WatchConfigs updates the Store whenever the stacks ConfigMap changes.
+// Invalid changes are logged and ignored so the last good Config is kept.
+func (s *Store) WatchConfigs(cmw configmap.Watcher, logger *zap.SugaredLogger) {
+	cmw.Watch(ConfigMapName, func(cm *corev1.ConfigMap) {
+		cfg, err := NewConfigFromConfigMap(cm)
+		if err != nil {
+			logger.Errorw("Invalid stacks configuration", zap.Error(err))
+			return
+		}
+
+		s.config.Store(cfg)
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stacks
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	"go.uber.org/zap/zaptest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/configmap"
+)
+
+func TestNewConfigFromMap(t *testing.T) {
+	cases := map[string]struct {
+		data        map[string]string
+		expected    *Config
+		expectedErr error
+	}{
+		"empty": {
+			data:     map[string]string{},
+			expected: &Config{},
+		},
+		"stacks": {
+			data: map[string]string{
+				"stacks": `
+- name: cflinuxfs3
+  description: Cloud Foundry compatible
+  buildImage: gcr.io/kf-releases/buildpack-builder:latest
+  runImage: cloudfoundry/cflinuxfs3
+`,
+			},
+			expected: &Config{
+				Stacks: []Stack{{
+					Name:        "cflinuxfs3",
+					Description: "Cloud Foundry compatible",
+					BuildImage:  "gcr.io/kf-releases/buildpack-builder:latest",
+					RunImage:    "cloudfoundry/cflinuxfs3",
+				}},
+			},
+		},
+		"missing run image": {
+			data: map[string]string{
+				"stacks": `[{"name": "s", "buildImage": "b"}]`,
+			},
+			expectedErr: errors.New("stack s must have a run image"),
+		},
+		"duplicate": {
+			data: map[string]string{
+				"stacks": `[{"name": "s", "buildImage": "b", "runImage": "r"}, {"name": "s", "buildImage": "b", "runImage": "r"}]`,
+			},
+			expectedErr: errors.New("stack s is defined more than once"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			actual, err := NewConfigFromMap(tc.data)
+			if tc.expectedErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.expectedErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "config", tc.expected, actual)
+		})
+	}
+}
+
+func TestConfig_ToMap(t *testing.T) {
+	expected := &Config{
+		Stacks: []Stack{
+			{Name: "a", BuildImage: "build-a", RunImage: "run-a"},
+			{Name: "b", BuildImage: "build-b", RunImage: "run-b", Description: "B"},
+		},
+	}
+
+	data, err := expected.ToMap()
+	testutil.AssertNil(t, "ToMap error", err)
+
+	actual, err := NewConfigFromMap(data)
+	testutil.AssertNil(t, "NewConfigFromMap error", err)
+	testutil.AssertEqual(t, "config", expected, actual)
+}
+
+func TestConfig_Find(t *testing.T) {
+	cfg := &Config{
+		Stacks: []Stack{{Name: "a", BuildImage: "build-a", RunImage: "run-a"}},
+	}
+
+	stack, ok := cfg.Find("a")
+	testutil.AssertEqual(t, "found", true, ok)
+	testutil.AssertEqual(t, "run image", "run-a", stack.RunImage)
+
+	_, ok = cfg.Find("missing")
+	testutil.AssertEqual(t, "found missing", false, ok)
+
+	_, ok = (*Config)(nil).Find("a")
+	testutil.AssertEqual(t, "found in nil", false, ok)
+}
+
+func TestStore(t *testing.T) {
+	store := &Store{}
+	testutil.AssertEqual(t, "initial config", &Config{}, store.Load())
+
+	cmw := &configmap.ManualWatcher{Namespace: ConfigMapNamespace}
+	store.WatchConfigs(cmw, zaptest.NewLogger(t).Sugar())
+
+	cmw.OnChange(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: ConfigMapNamespace},
+		Data: map[string]string{
+			"stacks": `[{"name": "a", "buildImage": "build-a", "runImage": "run-a"}]`,
+		},
+	})
+	_, ok := store.Load().Find("a")
+	testutil.AssertEqual(t, "found after update", true, ok)
+
+	// Invalid configurations are ignored.
+	cmw.OnChange(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: ConfigMapNamespace},
+		Data: map[string]string{
+			"stacks": `[{"name": "a"}]`,
+		},
+	})
+	_, ok = store.Load().Find("a")
+	testutil.AssertEqual(t, "found after invalid update", true, ok)
+}
@@ -0,0 +1,104 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// ConfigMapName is the name of the ConfigMap in the kf namespace that
+	// holds the tracing configuration.
+	ConfigMapName = "config-tracing"
+
+	// ConfigMapNamespace is the namespace the tracing ConfigMap lives in.
+	ConfigMapNamespace = "kf"
+
+	endpointKey   = "endpoint"
+	sampleRateKey = "sample-rate"
+	queryURLKey   = "query-url"
+
+	// DefaultSampleRate is the fraction of traces exported if unset.
+	DefaultSampleRate = 1.0
+)
+
+// Config holds the tracing configuration.
+type Config struct {
+	// Endpoint is the host:port or URL of a collector that accepts OTLP over
+	// HTTP, such as the OpenTelemetry Collector's otlp receiver. Tracing is
+	// disabled if Endpoint is blank.
+	Endpoint string
+
+	// SampleRate is the fraction of traces to export between 0 and 1.
+	SampleRate float64
+
+	// QueryURL is the base URL of a Zipkin v2 compatible API that traces can
+	// be read back from. It's optional and only used by the CLI.
+	QueryURL string
+}
+
+// Enabled returns true if spans should be exported.
+func (c *Config) Enabled() bool {
+	return c != nil && c.Endpoint != ""
+}
+
+// NewConfigFromMap creates a Config from the data of the tracing ConfigMap.
+func NewConfigFromMap(data map[string]string) (*Config, error) {
+	cfg := &Config{
+		Endpoint:   data[endpointKey],
+		SampleRate: DefaultSampleRate,
+		QueryURL:   data[queryURLKey],
+	}
+
+	if raw, ok := data[sampleRateKey]; ok {
+		rate, err := strconv.ParseFloat(raw, 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("invalid %s %q, must be between 0 and 1", sampleRateKey, raw)
+		}
+
+		cfg.SampleRate = rate
+	}
+
+	return cfg, nil
+}
+
+// NewConfigFromConfigMap creates a Config from the tracing ConfigMap.
+func NewConfigFromConfigMap(cm *corev1.ConfigMap) (*Config, error) {
+	return NewConfigFromMap(cm.Data)
+}
+
+// LoadClusterConfig reads the tracing configuration from the cluster. A
+// disabled Config is returned if the ConfigMap doesn't exist or can't be read.
+func LoadClusterConfig(client kubernetes.Interface) (*Config, error) {
+	cm, err := client.
+		CoreV1().
+		ConfigMaps(ConfigMapNamespace).
+		Get(ConfigMapName, metav1.GetOptions{})
+	if apierrs.IsNotFound(err) || apierrs.IsForbidden(err) {
+		// Developers may not be able to read the kf namespace, treat that the
+		// same as tracing being disabled.
+		return &Config{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return NewConfigFromConfigMap(cm)
+}
@@ -0,0 +1,80 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestNewConfigFromMap(t *testing.T) {
+	cases := map[string]struct {
+		data        map[string]string
+		expected    *Config
+		expectedErr error
+	}{
+		"empty": {
+			data:     map[string]string{},
+			expected: &Config{SampleRate: DefaultSampleRate},
+		},
+		"endpoint": {
+			data: map[string]string{
+				"endpoint":    "otel-collector.observability:55678",
+				"sample-rate": "0.25",
+			},
+			expected: &Config{
+				Endpoint:   "otel-collector.observability:55678",
+				SampleRate: 0.25,
+			},
+		},
+		"query url": {
+			data: map[string]string{
+				"query-url": "http://zipkin.observability:9411",
+			},
+			expected: &Config{
+				SampleRate: DefaultSampleRate,
+				QueryURL:   "http://zipkin.observability:9411",
+			},
+		},
+		"invalid sample rate": {
+			data:        map[string]string{"sample-rate": "all"},
+			expectedErr: errors.New(`invalid sample-rate "all", must be between 0 and 1`),
+		},
+		"sample rate out of range": {
+			data:        map[string]string{"sample-rate": "1.5"},
+			expectedErr: errors.New(`invalid sample-rate "1.5", must be between 0 and 1`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			actual, err := NewConfigFromMap(tc.data)
+			if tc.expectedErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.expectedErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "config", tc.expected, actual)
+		})
+	}
+}
+
+func TestConfig_Enabled(t *testing.T) {
+	testutil.AssertEqual(t, "nil", false, (*Config)(nil).Enabled())
+	testutil.AssertEqual(t, "no endpoint", false, (&Config{}).Enabled())
+	testutil.AssertEqual(t, "endpoint", true, (&Config{Endpoint: "collector:55678"}).Enabled())
+}
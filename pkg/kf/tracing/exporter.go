@@ -0,0 +1,121 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opencensus.io/trace"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	mu       sync.Mutex
+	exporter *otlpExporter
+)
+
+// exportTimeout bounds how long sending a batch of spans can take so a slow
+// collector doesn't hold up a push.
+const exportTimeout = 10 * time.Second
+
+// Setup configures spans to be exported for the component, replacing any
+// previous configuration. Spans aren't sampled if the Config is disabled.
+func Setup(component string, cfg *Config) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if exporter != nil {
+		trace.UnregisterExporter(exporter)
+		exporter.Stop()
+		exporter = nil
+	}
+
+	if !cfg.Enabled() {
+		trace.ApplyConfig(trace.Config{DefaultSampler: trace.NeverSample()})
+		return nil
+	}
+
+	e, err := newOTLPExporter(cfg.Endpoint, component, &http.Client{Timeout: exportTimeout})
+	if err != nil {
+		return fmt.Errorf("couldn't create trace exporter: %s", err)
+	}
+
+	trace.RegisterExporter(e)
+	trace.ApplyConfig(trace.Config{DefaultSampler: trace.ProbabilitySampler(cfg.SampleRate)})
+	exporter = e
+
+	return nil
+}
+
+// Flush exports any buffered spans. It should be called before the process
+// exits.
+func Flush() {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if exporter != nil {
+		exporter.Flush()
+	}
+}
+
+// UpdateExporterFromConfigMap returns a function that can be passed to a
+// ConfigMap watcher to reconfigure tracing when the tracing ConfigMap
+// changes.
+func UpdateExporterFromConfigMap(component string, logger *zap.SugaredLogger) func(*corev1.ConfigMap) {
+	return func(cm *corev1.ConfigMap) {
+		cfg, err := NewConfigFromConfigMap(cm)
+		if err != nil {
+			logger.Errorw("Failed to parse tracing config", zap.Error(err))
+			return
+		}
+
+		if err := Setup(component, cfg); err != nil {
+			logger.Errorw("Failed to update trace exporter", zap.Error(err))
+			return
+		}
+
+		logger.Infof("Tracing endpoint set to %q", cfg.Endpoint)
+	}
+}
+
+// StartSpan starts a span that's a child of any span in the context.
+func StartSpan(ctx context.Context, name string, attributes ...trace.Attribute) (context.Context, *trace.Span) {
+	ctx, span := trace.StartSpan(ctx, name)
+	span.AddAttributes(attributes...)
+	return ctx, span
+}
+
+// EndSpan marks the span as failed if err is non-nil then ends it.
+func EndSpan(span *trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(trace.Status{
+			Code:    trace.StatusCodeUnknown,
+			Message: err.Error(),
+		})
+	}
+
+	span.End()
+}
+
+// StartReconcileSpan starts a span for a single reconciliation of the object
+// of the given kind identified by key.
+func StartReconcileSpan(ctx context.Context, kind, key string) (context.Context, *trace.Span) {
+	return StartSpan(ctx, "Reconcile "+kind, trace.StringAttribute("kf.dev/key", key))
+}
@@ -0,0 +1,314 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/trace"
+)
+
+const (
+	// otlpTracesPath is the path OTLP/HTTP receivers accept spans on.
+	otlpTracesPath = "/v1/traces"
+
+	// otlpBatchSize is the number of buffered spans that triggers an export.
+	otlpBatchSize = 256
+
+	// otlpFlushInterval is how often buffered spans are exported.
+	otlpFlushInterval = 5 * time.Second
+)
+
+// OTLP span kinds and status codes from the OpenTelemetry protocol.
+const (
+	otlpSpanKindInternal = 1
+	otlpSpanKindServer   = 2
+	otlpSpanKindClient   = 3
+
+	otlpStatusCodeError = 2
+)
+
+// otlpExporter sends spans to an OTLP/HTTP receiver using the JSON encoding
+// of the OpenTelemetry protocol. Spans are buffered and sent in batches.
+type otlpExporter struct {
+	url         string
+	serviceName string
+	client      *http.Client
+
+	mu    sync.Mutex
+	spans []*trace.SpanData
+
+	// sendMu keeps batches in the order they were taken from the buffer.
+	sendMu sync.Mutex
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+var _ trace.Exporter = (*otlpExporter)(nil)
+
+// newOTLPExporter creates an exporter for the receiver at endpoint and starts
+// exporting buffered spans in the background. endpoint is either a host:port
+// or a URL, the OTLP traces path is used if the URL doesn't have a path.
+func newOTLPExporter(endpoint, serviceName string, client *http.Client) (*otlpExporter, error) {
+	u, err := otlpURL(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &otlpExporter{
+		url:         u,
+		serviceName: serviceName,
+		client:      client,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+
+	go e.run()
+
+	return e, nil
+}
+
+func otlpURL(endpoint string) (string, error) {
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "http://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid endpoint %q, must be a host:port or URL", endpoint)
+	}
+
+	if u.Path == "" || u.Path == "/" {
+		u.Path = otlpTracesPath
+	}
+
+	return u.String(), nil
+}
+
+func (e *otlpExporter) run() {
+	defer close(e.done)
+
+	ticker := time.NewTicker(otlpFlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.Flush()
+		case <-e.stop:
+			return
+		}
+	}
+}
+
+// ExportSpan implements trace.Exporter.
+func (e *otlpExporter) ExportSpan(span *trace.SpanData) {
+	e.mu.Lock()
+	e.spans = append(e.spans, span)
+	full := len(e.spans) >= otlpBatchSize
+	e.mu.Unlock()
+
+	if full {
+		go e.Flush()
+	}
+}
+
+// Flush sends all buffered spans. Spans that can't be sent are dropped so a
+// missing collector never blocks the caller for long.
+func (e *otlpExporter) Flush() error {
+	e.sendMu.Lock()
+	defer e.sendMu.Unlock()
+
+	e.mu.Lock()
+	spans := e.spans
+	e.spans = nil
+	e.mu.Unlock()
+
+	if len(spans) == 0 {
+		return nil
+	}
+
+	body, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.client.Post(e.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("couldn't export spans: %s", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("couldn't export spans: %s returned %s", e.url, resp.Status)
+	}
+
+	return nil
+}
+
+// Stop stops exporting in the background and sends any buffered spans.
+func (e *otlpExporter) Stop() {
+	close(e.stop)
+	<-e.done
+	e.Flush()
+}
+
+func (e *otlpExporter) request(spans []*trace.SpanData) otlpRequest {
+	var out []otlpSpan
+	for _, span := range spans {
+		out = append(out, convertSpan(span))
+	}
+
+	return otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpKeyValue{
+					{Key: "service.name", Value: otlpAnyValue{StringValue: &e.serviceName}},
+				},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/google/kf"},
+				Spans: out,
+			}},
+		}},
+	}
+}
+
+func convertSpan(span *trace.SpanData) otlpSpan {
+	out := otlpSpan{
+		TraceID:           hex.EncodeToString(span.TraceID[:]),
+		SpanID:            hex.EncodeToString(span.SpanID[:]),
+		Name:              span.Name,
+		Kind:              otlpSpanKindInternal,
+		StartTimeUnixNano: strconv.FormatInt(span.StartTime.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.EndTime.UnixNano(), 10),
+		Attributes:        convertAttributes(span.Attributes),
+	}
+
+	if span.ParentSpanID != (trace.SpanID{}) {
+		out.ParentSpanID = hex.EncodeToString(span.ParentSpanID[:])
+	}
+
+	switch span.SpanKind {
+	case trace.SpanKindServer:
+		out.Kind = otlpSpanKindServer
+	case trace.SpanKindClient:
+		out.Kind = otlpSpanKindClient
+	}
+
+	if span.Code != trace.StatusCodeOK {
+		out.Status = &otlpStatus{
+			Code:    otlpStatusCodeError,
+			Message: span.Message,
+		}
+	}
+
+	return out
+}
+
+func convertAttributes(attributes map[string]interface{}) []otlpKeyValue {
+	var keys []string
+	for k := range attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var out []otlpKeyValue
+	for _, k := range keys {
+		var value otlpAnyValue
+		switch v := attributes[k].(type) {
+		case string:
+			value.StringValue = &v
+		case bool:
+			value.BoolValue = &v
+		case int64:
+			i := strconv.FormatInt(v, 10)
+			value.IntValue = &i
+		case float64:
+			value.DoubleValue = &v
+		default:
+			s := fmt.Sprint(v)
+			value.StringValue = &s
+		}
+
+		out = append(out, otlpKeyValue{Key: k, Value: value})
+	}
+
+	return out
+}
+
+// The types below are the subset of the OTLP JSON encoding Kf sends.
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            *otlpStatus    `json:"status,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"`
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,152 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	"go.opencensus.io/trace"
+)
+
+func TestOTLPURL(t *testing.T) {
+	cases := map[string]struct {
+		endpoint    string
+		expected    string
+		expectedErr error
+	}{
+		"host and port": {
+			endpoint: "otel-collector.observability:4318",
+			expected: "http://otel-collector.observability:4318/v1/traces",
+		},
+		"url without path": {
+			endpoint: "https://collector.example.com/",
+			expected: "https://collector.example.com/v1/traces",
+		},
+		"url with path": {
+			endpoint: "https://collector.example.com/otlp/v1/traces",
+			expected: "https://collector.example.com/otlp/v1/traces",
+		},
+		"no host": {
+			endpoint:    "http://",
+			expectedErr: errors.New(`invalid endpoint "http://", must be a host:port or URL`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			actual, err := otlpURL(tc.endpoint)
+			if tc.expectedErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.expectedErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "url", tc.expected, actual)
+		})
+	}
+}
+
+func TestOTLPExporter_Flush(t *testing.T) {
+	var requests []otlpRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testutil.AssertEqual(t, "path", "/v1/traces", r.URL.Path)
+		testutil.AssertEqual(t, "content type", "application/json", r.Header.Get("Content-Type"))
+
+		var req otlpRequest
+		testutil.AssertNil(t, "decode err", json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+	}))
+	defer server.Close()
+
+	e, err := newOTLPExporter(server.URL, "kf-cli", server.Client())
+	testutil.AssertNil(t, "err", err)
+	defer e.Stop()
+
+	start := time.Unix(0, 1000)
+	e.ExportSpan(&trace.SpanData{
+		SpanContext: trace.SpanContext{
+			TraceID: trace.TraceID{1},
+			SpanID:  trace.SpanID{2},
+		},
+		ParentSpanID: trace.SpanID{3},
+		Name:         "Push",
+		SpanKind:     trace.SpanKindClient,
+		StartTime:    start,
+		EndTime:      start.Add(time.Microsecond),
+		Attributes:   map[string]interface{}{"kf.dev/app": "my-app", "kf.dev/retries": int64(2)},
+		Status:       trace.Status{Code: trace.StatusCodeUnknown, Message: "build failed"},
+	})
+
+	testutil.AssertNil(t, "flush err", e.Flush())
+	testutil.AssertEqual(t, "requests", 1, len(requests))
+
+	serviceName := "kf-cli"
+	app := "my-app"
+	retries := "2"
+	testutil.AssertEqual(t, "request", otlpRequest{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: []otlpKeyValue{
+					{Key: "service.name", Value: otlpAnyValue{StringValue: &serviceName}},
+				},
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "github.com/google/kf"},
+				Spans: []otlpSpan{{
+					TraceID:           "01000000000000000000000000000000",
+					SpanID:            "0200000000000000",
+					ParentSpanID:      "0300000000000000",
+					Name:              "Push",
+					Kind:              otlpSpanKindClient,
+					StartTimeUnixNano: "1000",
+					EndTimeUnixNano:   "2000",
+					Attributes: []otlpKeyValue{
+						{Key: "kf.dev/app", Value: otlpAnyValue{StringValue: &app}},
+						{Key: "kf.dev/retries", Value: otlpAnyValue{IntValue: &retries}},
+					},
+					Status: &otlpStatus{Code: otlpStatusCodeError, Message: "build failed"},
+				}},
+			}},
+		}},
+	}, requests[0])
+
+	// Nothing is sent when the buffer is empty.
+	testutil.AssertNil(t, "flush err", e.Flush())
+	testutil.AssertEqual(t, "requests", 1, len(requests))
+}
+
+func TestOTLPExporter_Flush_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	e, err := newOTLPExporter(server.URL, "controller", server.Client())
+	testutil.AssertNil(t, "err", err)
+	defer e.Stop()
+
+	e.ExportSpan(&trace.SpanData{Name: "Reconcile App"})
+
+	testutil.AssertErrorsEqual(
+		t,
+		errors.New("couldn't export spans: "+server.URL+"/v1/traces returned 503 Service Unavailable"),
+		e.Flush(),
+	)
+}
@@ -0,0 +1,130 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrTraceNotFound is returned by FetchTrace if the backend doesn't have any
+// spans for the trace (yet).
+var ErrTraceNotFound = errors.New("trace not found")
+
+// Span is a single span in the Zipkin v2 JSON format.
+type Span struct {
+	TraceID       string    `json:"traceId"`
+	ID            string    `json:"id"`
+	ParentID      string    `json:"parentId,omitempty"`
+	Name          string    `json:"name,omitempty"`
+	Kind          string    `json:"kind,omitempty"`
+	Timestamp     int64     `json:"timestamp,omitempty"`
+	Duration      int64     `json:"duration,omitempty"`
+	LocalEndpoint *Endpoint `json:"localEndpoint,omitempty"`
+}
+
+// Endpoint is the network context of a Span.
+type Endpoint struct {
+	ServiceName string `json:"serviceName,omitempty"`
+}
+
+// ServiceName returns the name of the service that recorded the span or
+// "unknown" if it isn't set.
+func (s *Span) ServiceName() string {
+	if s.LocalEndpoint == nil || s.LocalEndpoint.ServiceName == "" {
+		return "unknown"
+	}
+
+	return s.LocalEndpoint.ServiceName
+}
+
+// FetchTrace reads all the spans for traceID from the Zipkin v2 compatible
+// API at queryURL.
+func FetchTrace(client *http.Client, queryURL, traceID string) ([]Span, error) {
+	u := strings.TrimSuffix(queryURL, "/") + "/api/v2/trace/" + url.PathEscape(traceID)
+
+	resp, err := client.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrTraceNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status from %s: %s", u, resp.Status)
+	}
+
+	var spans []Span
+	if err := json.NewDecoder(resp.Body).Decode(&spans); err != nil {
+		return nil, fmt.Errorf("couldn't decode trace: %s", err)
+	}
+
+	if len(spans) == 0 {
+		return nil, ErrTraceNotFound
+	}
+
+	return spans, nil
+}
+
+// WriteSpanTree writes the spans as an indented tree with children ordered
+// by start time. Spans whose parent isn't in the list are treated as roots.
+func WriteSpanTree(w io.Writer, spans []Span) {
+	ids := make(map[string]bool)
+	for _, span := range spans {
+		ids[span.ID] = true
+	}
+
+	children := make(map[string][]Span)
+	var roots []Span
+	for _, span := range spans {
+		if span.ParentID == "" || span.ParentID == span.ID || !ids[span.ParentID] {
+			roots = append(roots, span)
+			continue
+		}
+
+		children[span.ParentID] = append(children[span.ParentID], span)
+	}
+
+	var write func(spans []Span, depth int)
+	write = func(spans []Span, depth int) {
+		sort.SliceStable(spans, func(i, j int) bool {
+			return spans[i].Timestamp < spans[j].Timestamp
+		})
+
+		for _, span := range spans {
+			fmt.Fprintf(
+				w,
+				"%s%s %s %s\n",
+				strings.Repeat("  ", depth),
+				span.ServiceName(),
+				span.Name,
+				time.Duration(span.Duration)*time.Microsecond,
+			)
+
+			write(children[span.ID], depth+1)
+		}
+	}
+
+	write(roots, 0)
+}
@@ -0,0 +1,97 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestFetchTrace(t *testing.T) {
+	cases := map[string]struct {
+		status      int
+		body        string
+		expected    []Span
+		expectedErr error
+	}{
+		"found": {
+			status: http.StatusOK,
+			body:   `[{"traceId":"abc","id":"1","name":"get","duration":1500,"localEndpoint":{"serviceName":"gateway"}}]`,
+			expected: []Span{
+				{TraceID: "abc", ID: "1", Name: "get", Duration: 1500, LocalEndpoint: &Endpoint{ServiceName: "gateway"}},
+			},
+		},
+		"not found": {
+			status:      http.StatusNotFound,
+			expectedErr: ErrTraceNotFound,
+		},
+		"empty": {
+			status:      http.StatusOK,
+			body:        `[]`,
+			expectedErr: ErrTraceNotFound,
+		},
+		"bad json": {
+			status:      http.StatusOK,
+			body:        `{`,
+			expectedErr: errors.New("couldn't decode trace: unexpected EOF"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				testutil.AssertEqual(t, "path", "/api/v2/trace/abc", r.URL.Path)
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer server.Close()
+
+			actual, err := FetchTrace(server.Client(), server.URL+"/", "abc")
+			if tc.expectedErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.expectedErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "spans", tc.expected, actual)
+		})
+	}
+}
+
+func TestWriteSpanTree(t *testing.T) {
+	spans := []Span{
+		{ID: "3", ParentID: "2", Name: "app", Timestamp: 30, Duration: 500, LocalEndpoint: &Endpoint{ServiceName: "myapp"}},
+		{ID: "1", Name: "ingress", Timestamp: 10, Duration: 2000, LocalEndpoint: &Endpoint{ServiceName: "gateway"}},
+		{ID: "2", ParentID: "1", Name: "sidecar", Timestamp: 20, Duration: 1000, LocalEndpoint: &Endpoint{ServiceName: "myapp"}},
+		{ID: "4", ParentID: "1", Name: "late", Timestamp: 40, Duration: 100},
+		{ID: "5", ParentID: "missing", Name: "orphan", Timestamp: 50},
+	}
+
+	buffer := &bytes.Buffer{}
+	WriteSpanTree(buffer, spans)
+
+	expected := `gateway ingress 2ms
+  myapp sidecar 1ms
+    myapp app 500µs
+  unknown late 100µs
+unknown orphan 0s
+`
+	testutil.AssertEqual(t, "tree", expected, buffer.String())
+}
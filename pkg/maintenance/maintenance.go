@@ -0,0 +1,81 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+)
+
+const (
+	// PageHeader is the request header the VirtualService uses to pass the
+	// base64 encoded maintenance page to the server.
+	PageHeader = "X-Kf-Maintenance-Page"
+
+	// ServiceName is the name of the Service fronting the maintenance server.
+	ServiceName = "maintenance"
+
+	// DefaultPage is served if a Route doesn't have a custom page.
+	DefaultPage = `<!DOCTYPE html>
+<html>
+<head><title>Down for maintenance</title></head>
+<body>
+<h1>Down for maintenance</h1>
+<p>This site is temporarily unavailable, please check back soon.</p>
+</body>
+</html>
+`
+)
+
+// Host returns the fully qualified host of the maintenance Service.
+func Host() string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", ServiceName, v1alpha1.KfNamespace)
+}
+
+// EncodePage encodes a page so it's safe to transmit as a header value.
+func EncodePage(page string) string {
+	return base64.StdEncoding.EncodeToString([]byte(page))
+}
+
+// DecodePage reverses EncodePage. If the value is blank or can't be decoded
+// the default page is returned.
+func DecodePage(value string) string {
+	if value == "" {
+		return DefaultPage
+	}
+
+	page, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(page) == 0 {
+		return DefaultPage
+	}
+
+	return string(page)
+}
+
+// NewHandler creates a http.Handler that responds to every request with a
+// 503 and the maintenance page for the Route.
+func NewHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := DecodePage(r.Header.Get(PageHeader))
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, page)
+	})
+}
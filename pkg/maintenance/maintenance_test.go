@@ -0,0 +1,92 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package maintenance
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestEncodePage(t *testing.T) {
+	t.Parallel()
+
+	page := "<h1>Back soon</h1>\n"
+	testutil.AssertEqual(t, "round trip", page, DecodePage(EncodePage(page)))
+}
+
+func TestDecodePage(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		value string
+		want  string
+	}{
+		"blank": {
+			value: "",
+			want:  DefaultPage,
+		},
+		"invalid base64": {
+			value: "not base64!",
+			want:  DefaultPage,
+		},
+		"custom page": {
+			value: EncodePage("custom"),
+			want:  "custom",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "page", tc.want, DecodePage(tc.value))
+		})
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		header   string
+		wantBody string
+	}{
+		"default page": {
+			wantBody: DefaultPage,
+		},
+		"custom page": {
+			header:   EncodePage("<h1>Back soon</h1>"),
+			wantBody: "<h1>Back soon</h1>",
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/some/path", nil)
+			if tc.header != "" {
+				req.Header.Set(PageHeader, tc.header)
+			}
+			rec := httptest.NewRecorder()
+
+			NewHandler().ServeHTTP(rec, req)
+
+			testutil.AssertEqual(t, "status", http.StatusServiceUnavailable, rec.Code)
+			testutil.AssertEqual(t, "content type", "text/html; charset=utf-8", rec.Header().Get("Content-Type"))
+			testutil.AssertEqual(t, "cache control", "no-store", rec.Header().Get("Cache-Control"))
+			testutil.AssertEqual(t, "body", tc.wantBody, rec.Body.String())
+		})
+	}
+}
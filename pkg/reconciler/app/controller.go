@@ -26,12 +26,25 @@ import (
 	servicecatalogclient "github.com/google/kf/pkg/client/servicecatalog/injection/client"
 	servicebindinginformer "github.com/google/kf/pkg/client/servicecatalog/injection/informers/servicecatalog/v1beta1/servicebinding"
 	serviceinstanceinformer "github.com/google/kf/pkg/client/servicecatalog/injection/informers/servicecatalog/v1beta1/serviceinstance"
+	"github.com/google/kf/pkg/kf/egress"
+	"github.com/google/kf/pkg/kf/registry"
+	"github.com/google/kf/pkg/kf/stacks"
 	"github.com/google/kf/pkg/reconciler"
+	"github.com/google/kf/pkg/reconciler/app/resources"
+	"github.com/knative/serving/pkg/apis/serving"
 	krevisioninformer "github.com/knative/serving/pkg/client/injection/informers/serving/v1alpha1/revision"
 	kserviceinformer "github.com/knative/serving/pkg/client/injection/informers/serving/v1alpha1/service"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
+	destinationruleinformer "knative.dev/pkg/client/injection/informers/istio/v1alpha3/destinationrule"
+	virtualserviceinformer "knative.dev/pkg/client/injection/informers/istio/v1alpha3/virtualservice"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	deploymentinformer "knative.dev/pkg/injection/informers/kubeinformers/appsv1/deployment"
+	configmapinformer "knative.dev/pkg/injection/informers/kubeinformers/corev1/configmap"
+	podinformer "knative.dev/pkg/injection/informers/kubeinformers/corev1/pod"
 	secretinformer "knative.dev/pkg/injection/informers/kubeinformers/corev1/secret"
 )
 
@@ -50,9 +63,20 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 	serviceBindingInformer := servicebindinginformer.Get(ctx)
 	serviceInstanceInformer := serviceinstanceinformer.Get(ctx)
 	secretInformer := secretinformer.Get(ctx)
+	configMapInformer := configmapinformer.Get(ctx)
+	podInformer := podinformer.Get(ctx)
+	deploymentInformer := deploymentinformer.Get(ctx)
+	virtualServiceInformer := virtualserviceinformer.Get(ctx)
+	destinationRuleInformer := destinationruleinformer.Get(ctx)
 
 	serviceCatalogClient := servicecatalogclient.Get(ctx)
 
+	stackStore := &stacks.Store{}
+	stackStore.WatchConfigs(cmw, logger)
+
+	egressStore := &egress.Store{}
+	egressStore.WatchConfigs(cmw, logger)
+
 	// Create reconciler
 	c := &Reconciler{
 		Base:                  reconciler.NewBase(ctx, cmw),
@@ -62,20 +86,34 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		sourceLister:          sourceInformer.Lister(),
 		appLister:             appInformer.Lister(),
 		secretLister:          secretInformer.Lister(),
+		configMapLister:       configMapInformer.Lister(),
+		podLister:             podInformer.Lister(),
+		deploymentLister:      deploymentInformer.Lister(),
 		spaceLister:           spaceInformer.Lister(),
 		routeLister:           routeInformer.Lister(),
 		routeClaimLister:      routeClaimInformer.Lister(),
 		serviceBindingLister:  serviceBindingInformer.Lister(),
 		serviceInstanceLister: serviceInstanceInformer.Lister(),
+		virtualServiceLister:  virtualServiceInformer.Lister(),
+		destinationRuleLister: destinationRuleInformer.Lister(),
+		stackStore:            stackStore,
+		egressStore:           egressStore,
+		imageDeleter:          registry.NewDeleter(),
 	}
 
 	impl := controller.NewImpl(c, logger, "Apps")
+	c.enqueueAfter = impl.EnqueueAfter
 
 	logger.Info("Setting up event handlers")
 
 	// Watch for changes in sub-resources so we can sync accordingly
 	appInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
+	// The IPs of egress pools are reported on the Apps using them.
+	cmw.Watch(egress.ConfigMapName, func(*corev1.ConfigMap) {
+		impl.GlobalResync(appInformer.Informer())
+	})
+
 	sourceInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("App")),
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
@@ -86,6 +124,11 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
+	deploymentInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("App")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
 	serviceBindingInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("App")),
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
@@ -96,5 +139,79 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
+	destinationRuleInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("App")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	// RouteClaims aren't owned by Apps, but their routing policy (e.g. session
+	// affinity) is applied to the Apps that are bound to them.
+	routeClaimInformer.Informer().AddEventHandler(controller.HandleAll(
+		func(obj interface{}) {
+			claim, ok := obj.(*v1alpha1.RouteClaim)
+			if !ok {
+				return
+			}
+
+			routes, err := c.routeLister.
+				Routes(claim.GetNamespace()).
+				List(resources.MakeRouteSelector(claim.Spec.RouteSpecFields))
+			if err != nil {
+				logger.Warnf("failed to list Routes for RouteClaim %s: %s", claim.Name, err)
+				return
+			}
+
+			for _, route := range routes {
+				impl.EnqueueControllerOf(route)
+			}
+		},
+	))
+
+	// Secrets and ConfigMaps aren't owned by the Apps that restart on changes
+	// to them.
+	enqueueRestartOnChange := func(kind string) func(obj interface{}) {
+		return func(obj interface{}) {
+			object, err := meta.Accessor(obj)
+			if err != nil {
+				return
+			}
+
+			apps, err := c.appLister.Apps(object.GetNamespace()).List(labels.Everything())
+			if err != nil {
+				logger.Warnf("failed to list Apps for %s %s: %s", kind, object.GetName(), err)
+				return
+			}
+
+			for _, app := range apps {
+				for _, ref := range app.Spec.RestartOnChange {
+					if ref.Kind == kind && ref.Name == object.GetName() {
+						impl.Enqueue(app)
+						break
+					}
+				}
+			}
+		}
+	}
+
+	secretInformer.Informer().AddEventHandler(controller.HandleAll(
+		enqueueRestartOnChange(v1alpha1.RestartOnChangeKindSecret),
+	))
+
+	configMapInformer.Informer().AddEventHandler(controller.HandleAll(
+		enqueueRestartOnChange(v1alpha1.RestartOnChangeKindConfigMap),
+	))
+
+	// Revisions aren't owned by the App directly, but they're labeled with the
+	// name of the Knative Service which matches the App's name.
+	knativeRevisionInformer.Informer().AddEventHandler(controller.HandleAll(
+		impl.EnqueueLabelOfNamespaceScopedResource("", serving.ServiceLabelKey),
+	))
+
+	// Pods are labeled the same way as Revisions and need to be given an
+	// instance index when they're created.
+	podInformer.Informer().AddEventHandler(controller.HandleAll(
+		impl.EnqueueLabelOfNamespaceScopedResource("", serving.ServiceLabelKey),
+	))
+
 	return impl
 }
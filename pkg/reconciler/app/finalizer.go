@@ -0,0 +1,339 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/imageutil"
+	"github.com/google/kf/pkg/reconciler"
+	"github.com/google/kf/pkg/reconciler/app/resources"
+	routeresources "github.com/google/kf/pkg/reconciler/route/resources"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// finalizerName is set on Apps so deleting them waits for their
+	// dependents to be cleaned up. VirtualServices in particular live in the
+	// kf namespace so they can't be garbage collected with the App.
+	finalizerName = "apps.kf.dev"
+
+	// deletionPollInterval is how often an App that's being deleted checks
+	// whether its dependents are gone.
+	deletionPollInterval = 5 * time.Second
+
+	// imageCleanupTimeout is how long after an App starts deleting that
+	// failures to delete its images are retried. After that the images are
+	// left in the registry so an outage can't block the deletion forever.
+	imageCleanupTimeout = 10 * time.Minute
+)
+
+// ensureFinalizer adds the finalizer to the App if it's missing.
+func (r *Reconciler) ensureFinalizer(app *v1alpha1.App) error {
+	patch, err := reconciler.AddFinalizerPatch(app, finalizerName)
+	if err != nil || patch == nil {
+		return err
+	}
+
+	_, err = r.KfClientSet.KfV1alpha1().Apps(app.Namespace).Patch(app.Name, types.MergePatchType, patch)
+	return err
+}
+
+// finalize cleans up the dependents of an App that's being deleted then
+// removes the finalizer so the deletion can complete. Until then, the Ready
+// condition reports what the deletion is waiting on.
+func (r *Reconciler) finalize(ctx context.Context, original *v1alpha1.App) error {
+	if !reconciler.HasFinalizer(original, finalizerName) {
+		return nil
+	}
+
+	logger := logging.FromContext(ctx)
+
+	// Don't modify the informers copy
+	toFinalize := original.DeepCopy()
+
+	waitingOn, err := r.cleanUp(ctx, toFinalize)
+	if err != nil {
+		return err
+	}
+
+	if len(waitingOn) > 0 {
+		toFinalize.Status.MarkDeleting(fmt.Sprintf("Waiting for %s to be deleted", strings.Join(waitingOn, ", ")))
+		if _, err := r.updateStatus(ctx, toFinalize); err != nil {
+			return err
+		}
+
+		if r.enqueueAfter != nil {
+			r.enqueueAfter(original, deletionPollInterval)
+		}
+
+		return nil
+	}
+
+	logger.Infof("app %q cleaned up, removing finalizer", original.Name)
+
+	patch, err := reconciler.RemoveFinalizerPatch(original, finalizerName)
+	if err != nil || patch == nil {
+		return err
+	}
+
+	_, err = r.KfClientSet.KfV1alpha1().Apps(original.Namespace).Patch(original.Name, types.MergePatchType, patch)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// cleanUp deletes the dependents of the App and returns a description of the
+// ones that still exist.
+func (r *Reconciler) cleanUp(ctx context.Context, app *v1alpha1.App) ([]string, error) {
+	logger := logging.FromContext(ctx)
+	var waitingOn []string
+
+	// Routes
+	{
+		logger.Debug("deleting Routes")
+		routes, err := r.routeLister.
+			Routes(app.Namespace).
+			List(resources.MakeRouteAppSelector(app))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, route := range routes {
+			if route.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			if err := r.KfClientSet.
+				KfV1alpha1().
+				Routes(route.Namespace).
+				Delete(route.Name, &metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return nil, err
+			}
+		}
+
+		if len(routes) > 0 {
+			waitingOn = append(waitingOn, fmt.Sprintf("%d route(s)", len(routes)))
+		}
+	}
+
+	// VirtualServices
+	if err := r.removeFromVirtualServices(ctx, app); err != nil {
+		return nil, err
+	}
+
+	// Service bindings
+	{
+		logger.Debug("deleting ServiceBindings")
+		bindings, err := r.serviceBindingLister.
+			ServiceBindings(app.Namespace).
+			List(resources.MakeServiceBindingAppSelector(app.Name))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, binding := range bindings {
+			if binding.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			if err := r.serviceCatalogClient.
+				ServicecatalogV1beta1().
+				ServiceBindings(binding.Namespace).
+				Delete(binding.Name, &metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return nil, err
+			}
+		}
+
+		if len(bindings) > 0 {
+			waitingOn = append(waitingOn, fmt.Sprintf("%d service binding(s)", len(bindings)))
+		}
+	}
+
+	sources, err := r.sourceLister.
+		Sources(app.Namespace).
+		List(labels.SelectorFromSet(labels.Set{v1alpha1.NameLabel: app.Name}))
+	if err != nil {
+		return nil, err
+	}
+
+	// Images are deleted before the Sources that record them.
+	{
+		logger.Debug("deleting images")
+		failed, err := r.deleteImages(ctx, app, sources)
+		if err != nil {
+			return nil, err
+		}
+
+		if failed > 0 {
+			return append(waitingOn, fmt.Sprintf("%d image(s)", failed)), nil
+		}
+	}
+
+	// Sources are deleted in the foreground so their Builds are removed
+	// before they are.
+	{
+		logger.Debug("deleting Sources")
+		foreground := metav1.DeletePropagationForeground
+
+		var owned int
+		for _, source := range sources {
+			if !metav1.IsControlledBy(source, app) {
+				continue
+			}
+			owned++
+
+			if source.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			if err := r.KfClientSet.
+				KfV1alpha1().
+				Sources(source.Namespace).
+				Delete(source.Name, &metav1.DeleteOptions{PropagationPolicy: &foreground}); err != nil && !apierrs.IsNotFound(err) {
+				return nil, err
+			}
+		}
+
+		if owned > 0 {
+			waitingOn = append(waitingOn, fmt.Sprintf("%d source(s)", owned))
+		}
+	}
+
+	return waitingOn, nil
+}
+
+// deleteImages deletes the images built for the App from the container
+// registry and returns how many couldn't be deleted. Failures are retried
+// until imageCleanupTimeout has passed since the App started deleting.
+func (r *Reconciler) deleteImages(ctx context.Context, app *v1alpha1.App, sources []*v1alpha1.Source) (int, error) {
+	if r.imageDeleter == nil {
+		return 0, nil
+	}
+
+	// Lists Apps in every space because promoted and blue-green copies of
+	// the App run its images from other Apps.
+	apps, err := r.appLister.List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+
+	var failed int
+	for _, source := range imagesToDelete(app, sources, apps) {
+		if err := r.deleteSourceImage(source); err != nil {
+			failed++
+			r.Recorder.Eventf(app, corev1.EventTypeWarning, "ImageDeleteFailed",
+				"Couldn't delete image %s: %v", source.Status.Image, err)
+		}
+	}
+
+	if failed > 0 && app.DeletionTimestamp != nil && time.Since(app.DeletionTimestamp.Time) > imageCleanupTimeout {
+		logging.FromContext(ctx).Warnf("giving up deleting %d image(s) of app %q", failed, app.Name)
+		r.Recorder.Eventf(app, corev1.EventTypeWarning, "ImagesNotDeleted",
+			"Gave up deleting %d image(s) after %s, they must be removed from the registry manually", failed, imageCleanupTimeout)
+		return 0, nil
+	}
+
+	return failed, nil
+}
+
+// deleteSourceImage deletes the image a Source built. The tag is removed
+// first because registries like Container Registry refuse to delete tagged
+// manifests. Registries that can't remove tags remove them with the
+// manifest, so that failure is ignored if the digest is known.
+func (r *Reconciler) deleteSourceImage(source *v1alpha1.Source) error {
+	tagErr := r.imageDeleter.Delete(source.Status.Image)
+	if source.Status.ImageDigest == "" {
+		return tagErr
+	}
+
+	return r.imageDeleter.Delete(source.Status.ImageReference())
+}
+
+// imagesToDelete returns the Sources owned by the App whose images Kf built
+// and nothing else runs. Images of container builds belong to the user and
+// are never deleted. Repositories other Apps run images from are kept whole.
+func imagesToDelete(app *v1alpha1.App, sources []*v1alpha1.Source, apps []*v1alpha1.App) []*v1alpha1.Source {
+	inUse := sets.NewString()
+	for _, other := range apps {
+		if other.Namespace == app.Namespace && other.Name == app.Name {
+			continue
+		}
+
+		if image := other.Spec.Source.ContainerImage.Image; image != "" {
+			inUse.Insert(imageutil.Repository(image))
+		}
+	}
+
+	var out []*v1alpha1.Source
+	for _, source := range sources {
+		switch {
+		case !metav1.IsControlledBy(source, app):
+		case source.Spec.IsContainerBuild():
+		case source.Status.Image == "":
+		case inUse.Has(imageutil.Repository(source.Status.Image)):
+		default:
+			out = append(out, source)
+		}
+	}
+
+	return out
+}
+
+// removeFromVirtualServices removes the HTTP routes for the App from every
+// VirtualService. The Route reconciler only merges routes into
+// VirtualServices and doesn't run for terminating namespaces, so without
+// this they'd keep pointing at the deleted App.
+func (r *Reconciler) removeFromVirtualServices(ctx context.Context, app *v1alpha1.App) error {
+	logging.FromContext(ctx).Debug("removing App from VirtualServices")
+
+	virtualServices, err := r.virtualServiceLister.
+		VirtualServices(v1alpha1.KfNamespace).
+		List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	for _, vs := range virtualServices {
+		httpRoutes := routeresources.RemoveAppHTTPRoutes(vs.Spec.HTTP, app.Name, app.Namespace)
+		if len(httpRoutes) == len(vs.Spec.HTTP) {
+			continue
+		}
+
+		// Don't modify the informers copy.
+		existing := vs.DeepCopy()
+		existing.Spec.HTTP = httpRoutes
+
+		if _, err := r.SharedClientSet.
+			Networking().
+			VirtualServices(existing.Namespace).
+			Update(existing); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kflisters "github.com/google/kf/pkg/client/listers/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/google/kf/pkg/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestImagesToDelete(t *testing.T) {
+	app := &v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "my-space", UID: "app-uid"},
+	}
+
+	source := func(name, image string) *v1alpha1.Source {
+		s := &v1alpha1.Source{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "my-space",
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(app, v1alpha1.SchemeGroupVersion.WithKind("App"))},
+			},
+		}
+		s.Status.Image = image
+		return s
+	}
+
+	otherApp := func(image string) *v1alpha1.App {
+		other := &v1alpha1.App{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-app", Namespace: "other-space"},
+		}
+		other.Spec.Source.ContainerImage.Image = image
+		return other
+	}
+
+	built := source("built", "gcr.io/my-project/app_my-space_my-app:1")
+
+	containerBuild := source("container", "gcr.io/my-project/user-image:1")
+	containerBuild.Spec.ContainerImage.Image = "gcr.io/my-project/user-image:1"
+
+	notBuilt := source("not-built", "")
+
+	orphan := source("orphan", "gcr.io/my-project/orphan:1")
+	orphan.OwnerReferences = nil
+
+	cases := map[string]struct {
+		sources []*v1alpha1.Source
+		apps    []*v1alpha1.App
+		want    []*v1alpha1.Source
+	}{
+		"built image": {
+			sources: []*v1alpha1.Source{built},
+			apps:    []*v1alpha1.App{app},
+			want:    []*v1alpha1.Source{built},
+		},
+		"container builds are kept": {
+			sources: []*v1alpha1.Source{containerBuild},
+		},
+		"unbuilt sources are skipped": {
+			sources: []*v1alpha1.Source{notBuilt},
+		},
+		"sources the App doesn't control are skipped": {
+			sources: []*v1alpha1.Source{orphan},
+		},
+		"repositories other Apps run are kept": {
+			sources: []*v1alpha1.Source{built},
+			apps:    []*v1alpha1.App{app, otherApp("gcr.io/my-project/app_my-space_my-app@sha256:abc")},
+		},
+		"other repositories don't matter": {
+			sources: []*v1alpha1.Source{built},
+			apps:    []*v1alpha1.App{otherApp("gcr.io/my-project/another-app:1")},
+			want:    []*v1alpha1.Source{built},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "sources", tc.want, imagesToDelete(app, tc.sources, tc.apps))
+		})
+	}
+}
+
+type fakeDeleter struct {
+	deleted []string
+	errs    map[string]error
+}
+
+func (f *fakeDeleter) Delete(image string) error {
+	f.deleted = append(f.deleted, image)
+	return f.errs[image]
+}
+
+func TestReconciler_deleteImages(t *testing.T) {
+	const (
+		image  = "gcr.io/my-project/app_my-space_my-app:1"
+		digest = "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+		pinned = "gcr.io/my-project/app_my-space_my-app@" + digest
+	)
+
+	cases := map[string]struct {
+		digest     string
+		errs       map[string]error
+		deletingAt time.Time
+
+		wantDeleted []string
+		wantFailed  int
+		wantEvents  int
+	}{
+		"tag and digest": {
+			digest:      digest,
+			deletingAt:  time.Now(),
+			wantDeleted: []string{image, pinned},
+		},
+		"tag only": {
+			deletingAt:  time.Now(),
+			wantDeleted: []string{image},
+		},
+		"untag failure is ignored with a digest": {
+			digest:      digest,
+			errs:        map[string]error{image: errors.New("unsupported")},
+			deletingAt:  time.Now(),
+			wantDeleted: []string{image, pinned},
+		},
+		"failures are retried": {
+			digest:      digest,
+			errs:        map[string]error{pinned: errors.New("denied")},
+			deletingAt:  time.Now(),
+			wantDeleted: []string{image, pinned},
+			wantFailed:  1,
+			wantEvents:  1,
+		},
+		"failures are given up on after the timeout": {
+			digest:      digest,
+			errs:        map[string]error{pinned: errors.New("denied")},
+			deletingAt:  time.Now().Add(-2 * imageCleanupTimeout),
+			wantDeleted: []string{image, pinned},
+			wantEvents:  2,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			deletingAt := metav1.NewTime(tc.deletingAt)
+			app := &v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "my-app",
+					Namespace:         "my-space",
+					UID:               "app-uid",
+					DeletionTimestamp: &deletingAt,
+				},
+			}
+
+			source := &v1alpha1.Source{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "my-source",
+					Namespace:       "my-space",
+					OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(app, v1alpha1.SchemeGroupVersion.WithKind("App"))},
+				},
+			}
+			source.Status.Image = image
+			source.Status.ImageDigest = tc.digest
+
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			testutil.AssertNil(t, "err", indexer.Add(app))
+
+			deleter := &fakeDeleter{errs: tc.errs}
+			recorder := record.NewFakeRecorder(10)
+			r := &Reconciler{
+				Base:         &reconciler.Base{Recorder: recorder},
+				appLister:    kflisters.NewAppLister(indexer),
+				imageDeleter: deleter,
+			}
+
+			failed, err := r.deleteImages(context.Background(), app, []*v1alpha1.Source{source})
+			testutil.AssertNil(t, "err", err)
+			testutil.AssertEqual(t, "failed", tc.wantFailed, failed)
+			testutil.AssertEqual(t, "deleted", tc.wantDeleted, deleter.deleted)
+			testutil.AssertEqual(t, "events", tc.wantEvents, len(recorder.Events))
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	kflisters "github.com/google/kf/pkg/client/listers/kf/v1alpha1"
@@ -28,6 +29,11 @@ import (
 	servicecataloglisters "github.com/google/kf/pkg/client/servicecatalog/listers/servicecatalog/v1beta1"
 	"github.com/google/kf/pkg/kf/algorithms"
 	"github.com/google/kf/pkg/kf/cfutil"
+	"github.com/google/kf/pkg/kf/egress"
+	"github.com/google/kf/pkg/kf/notifications"
+	"github.com/google/kf/pkg/kf/registry"
+	"github.com/google/kf/pkg/kf/stacks"
+	"github.com/google/kf/pkg/kf/tracing"
 	"github.com/google/kf/pkg/reconciler"
 	"github.com/google/kf/pkg/reconciler/app/resources"
 	"github.com/knative/serving/pkg/apis/autoscaling"
@@ -35,13 +41,18 @@ import (
 	servinglisters "github.com/knative/serving/pkg/client/listers/serving/v1alpha1"
 	servicecatalogv1beta1 "github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	"go.uber.org/zap"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	appsv1listers "k8s.io/client-go/listers/apps/v1"
 	v1listers "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
+	networking "knative.dev/pkg/apis/istio/v1alpha3"
+	istiolisters "knative.dev/pkg/client/listers/istio/v1alpha3"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmp"
 	"knative.dev/pkg/logging"
@@ -62,16 +73,36 @@ type Reconciler struct {
 	spaceLister           kflisters.SpaceLister
 	routeLister           kflisters.RouteLister
 	secretLister          v1listers.SecretLister
+	configMapLister       v1listers.ConfigMapLister
+	podLister             v1listers.PodLister
+	deploymentLister      appsv1listers.DeploymentLister
 	routeClaimLister      kflisters.RouteClaimLister
 	serviceBindingLister  servicecataloglisters.ServiceBindingLister
 	serviceInstanceLister servicecataloglisters.ServiceInstanceLister
+	virtualServiceLister  istiolisters.VirtualServiceLister
+	destinationRuleLister istiolisters.DestinationRuleLister
+
+	// stackStore holds the Stacks configured on the cluster.
+	stackStore *stacks.Store
+
+	// egressStore holds the egress IP pools configured on the cluster.
+	egressStore *egress.Store
+
+	// imageDeleter removes the images built for Apps that are deleted.
+	imageDeleter registry.Deleter
+
+	// enqueueAfter schedules the App to be reconciled again after a delay.
+	enqueueAfter func(obj interface{}, after time.Duration)
 }
 
 // Check that our Reconciler implements controller.Reconciler
 var _ controller.Reconciler = (*Reconciler)(nil)
 
 // Reconcile is called by Kubernetes.
-func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+func (r *Reconciler) Reconcile(ctx context.Context, key string) (err error) {
+	ctx, span := tracing.StartReconcileSpan(ctx, "App", key)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		return err
@@ -97,7 +128,9 @@ func (r *Reconciler) reconcileApp(ctx context.Context, namespace, name string) (
 		return err
 
 	case original.GetDeletionTimestamp() != nil:
-		return nil
+		// Finalize even if the namespace is terminating, that's when the
+		// App's dependents outside of the namespace need to be cleaned up.
+		return r.finalize(ctx, original)
 	}
 
 	if r.IsNamespaceTerminating(namespace) {
@@ -105,6 +138,10 @@ func (r *Reconciler) reconcileApp(ctx context.Context, namespace, name string) (
 		return nil
 	}
 
+	if err := r.ensureFinalizer(original); err != nil {
+		return err
+	}
+
 	// Don't modify the informers copy
 	toReconcile := original.DeepCopy()
 
@@ -118,6 +155,11 @@ func (r *Reconciler) reconcileApp(ctx context.Context, namespace, name string) (
 	// Reconcile this copy of the service and then write back any status
 	// updates regardless of whether the reconciliation errored out.
 	reconcileErr := r.ApplyChanges(ctx, toReconcile)
+
+	// Surface failures users can act on as Events on the object.
+	r.RecordStatusChanges(toReconcile, original.Status.Status, toReconcile.Status.Status)
+	r.RecordReconcileError(toReconcile, reconcileErr)
+
 	if equality.Semantic.DeepEqual(original.Status, toReconcile.Status) {
 		// If we didn't change anything then don't call updateStatus.
 		// This is important because the copy we loaded from the informer's
@@ -127,6 +169,8 @@ func (r *Reconciler) reconcileApp(ctx context.Context, namespace, name string) (
 	} else if _, uErr := r.updateStatus(ctx, toReconcile); uErr != nil {
 		logger.Warnw("Failed to update App status", zap.Error(uErr))
 		return uErr
+	} else {
+		r.SendNotification(ctx, r.spaceLister, notifications.ForApp(original, toReconcile, time.Now()))
 	}
 
 	return reconcileErr
@@ -152,7 +196,7 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 	{
 		logger.Debug("reconciling Source")
 		condition := app.Status.SourceCondition()
-		desired, err := resources.MakeSource(app, space)
+		desired, err := resources.MakeSource(app, space, r.stackStore.Load())
 		if err != nil {
 			return condition.MarkTemplateError(err)
 		}
@@ -208,11 +252,7 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 		// Search to see if any of the existing bindings are not in the desired
 		// list of and therefore stale. If they are, delete them.
 		for _, binding := range existing {
-			if algorithms.Search(
-				0,
-				v1alpha1.ServiceBindings{*binding},
-				v1alpha1.ServiceBindings(desiredServiceBindings),
-			) {
+			if algorithms.Contains(desiredServiceBindings, *binding, v1alpha1.CompareServiceBindings) {
 				continue
 			}
 
@@ -225,7 +265,8 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 			}
 		}
 
-		for _, desired := range desiredServiceBindings {
+		var rotatingServiceBindings []string
+		for i, desired := range desiredServiceBindings {
 			actual, err := r.serviceBindingLister.
 				ServiceBindings(desired.GetNamespace()).
 				Get(desired.Name)
@@ -240,12 +281,25 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 				}
 			} else if err != nil {
 				return condition.MarkReconciliationError("getting latest", err)
+			} else if actual.GetDeletionTimestamp() != nil {
+				// The binding is being rotated, it'll be recreated once the
+				// broker has revoked the old credentials.
+				rotatingServiceBindings = append(rotatingServiceBindings, actual.Name)
+				continue
+			} else if rotated, err := r.rotateServiceBinding(app, &app.Spec.ServiceBindings[i], actual); err != nil {
+				return condition.MarkReconciliationError("rotating credentials for", err)
+			} else if rotated {
+				rotatingServiceBindings = append(rotatingServiceBindings, actual.Name)
+				continue
 			} else if actual, err = r.reconcileServiceBinding(&desired, actual); err != nil {
 				return condition.MarkReconciliationError("updating existing", err)
 			}
 			actualServiceBindings = append(actualServiceBindings, *actual)
 		}
 		app.Status.PropagateServiceBindingsStatus(actualServiceBindings)
+		for _, name := range rotatingServiceBindings {
+			app.Status.MarkCredentialsRotating(name)
+		}
 		if condition.IsPending() {
 			logger.Info("Waiting for service bindings; exiting early")
 			return nil
@@ -279,11 +333,53 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 		app.Status.PropagateEnvVarSecretStatus(actual)
 	}
 
+	// Reconcile service binding files secret
+	var bindingServices []cfutil.VcapService
+	if app.Spec.UsesK8sBindingFormat() {
+		logger.Debug("reconciling bindings secret")
+		condition := app.Status.EnvVarSecretCondition()
+		systemEnvInjector := cfutil.NewSystemEnvInjector(r.serviceCatalogClient, r.KubeClientSet)
+		services, err := systemEnvInjector.GetVcapServices(app.Name, actualServiceBindings)
+		if err != nil {
+			return condition.MarkTemplateError(err)
+		}
+		bindingServices = services
+		desired := resources.MakeKfBindingsSecret(app, space, services)
+
+		actual, err := r.secretLister.Secrets(desired.GetNamespace()).Get(desired.Name)
+		if apierrs.IsNotFound(err) {
+			_, err = r.KubeClientSet.CoreV1().Secrets(desired.GetNamespace()).Create(desired)
+			if err != nil {
+				return condition.MarkReconciliationError("creating", err)
+			}
+		} else if err != nil {
+			return condition.MarkReconciliationError("getting latest", err)
+		} else if !metav1.IsControlledBy(actual, app) {
+			return condition.MarkChildNotOwned(desired.Name)
+		} else if _, err = r.reconcileSecret(desired, actual); err != nil {
+			return condition.MarkReconciliationError("updating existing", err)
+		}
+	}
+
 	// reconcile serving
 	{
 		logger.Debug("reconciling Knative Serving")
 		condition := app.Status.KnativeServiceCondition()
-		desired, err := resources.MakeKnativeService(app, space)
+
+		objects, err := r.restartOnChangeObjects(app)
+		if err != nil {
+			return condition.MarkReconciliationError("getting restart-on-change objects for", err)
+		}
+		app.Status.RestartOnChangeChecksum = resources.RestartOnChangeChecksum(app, objects)
+		app.Status.EgressIPs = r.egressStore.Load().IPs(app.Spec.EgressIPPool)
+
+		// Apps outside of their scheduled hours are treated as stopped.
+		scheduledApp, err := r.applySchedule(app, time.Now())
+		if err != nil {
+			return condition.MarkTemplateError(err)
+		}
+
+		desired, err := resources.MakeKnativeService(scheduledApp, space, bindingServices)
 		if err != nil {
 			return condition.MarkTemplateError(err)
 		}
@@ -292,7 +388,7 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 			Services(desired.GetNamespace()).
 			Get(desired.Name)
 		if apierrs.IsNotFound(err) {
-			if !app.Spec.Instances.Stopped {
+			if !scheduledApp.Spec.Instances.Stopped {
 				// Knative Service doesn't exist, make one.
 				actual, err = r.ServingClientSet.
 					ServingV1alpha1().
@@ -306,7 +402,7 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 			return condition.MarkReconciliationError("getting latest", err)
 		} else if !metav1.IsControlledBy(actual, app) {
 			return condition.MarkChildNotOwned(desired.Name)
-		} else if app.Spec.Instances.Stopped {
+		} else if scheduledApp.Spec.Instances.Stopped {
 			// Found service for stopped app. We delete the service otherwise
 			// knative will bring back a single pod, even if when we set
 			// scaling to 0:
@@ -326,6 +422,70 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 		}
 
 		app.Status.PropagateKnativeServiceStatus(actual)
+
+		if err := r.reconcileRevisionStatus(app, scheduledApp.Spec.Instances.Stopped); err != nil {
+			return err
+		}
+	}
+
+	// Process Deployments run the App's non-web processes, e.g. workers
+	// defined in its Procfile.
+	{
+		logger.Debug("reconciling process Deployments")
+		condition := app.Status.KnativeServiceCondition()
+
+		scheduledApp, err := r.applySchedule(app, time.Now())
+		if err != nil {
+			return condition.MarkTemplateError(err)
+		}
+
+		desiredDeployments, err := resources.MakeProcessDeployments(scheduledApp, space)
+		if err != nil {
+			return condition.MarkTemplateError(err)
+		}
+
+		// Delete Deployments of processes that were removed from the App.
+		existingDeployments, err := r.deploymentLister.
+			Deployments(app.GetNamespace()).
+			List(resources.MakeProcessDeploymentSelector(app))
+		if err != nil {
+			return condition.MarkReconciliationError("scanning for stale process deployments", err)
+		}
+
+		for _, deployment := range existingDeployments {
+			if !metav1.IsControlledBy(deployment, app) || containsDeployment(desiredDeployments, deployment.Name) {
+				continue
+			}
+
+			if err := r.KubeClientSet.
+				AppsV1().
+				Deployments(deployment.GetNamespace()).
+				Delete(deployment.Name, &metav1.DeleteOptions{}); err != nil {
+				return condition.MarkReconciliationError("deleting stale process deployment", err)
+			}
+		}
+
+		for i := range desiredDeployments {
+			desired := &desiredDeployments[i]
+
+			actual, err := r.deploymentLister.
+				Deployments(desired.GetNamespace()).
+				Get(desired.Name)
+			if apierrs.IsNotFound(err) {
+				if _, err := r.KubeClientSet.
+					AppsV1().
+					Deployments(desired.GetNamespace()).
+					Create(desired); err != nil {
+					return condition.MarkReconciliationError("creating process deployment", err)
+				}
+			} else if err != nil {
+				return condition.MarkReconciliationError("getting latest process deployment", err)
+			} else if !metav1.IsControlledBy(actual, app) {
+				return condition.MarkChildNotOwned(desired.Name)
+			} else if _, err := r.reconcileDeployment(desired, actual); err != nil {
+				return condition.MarkReconciliationError("updating existing process deployment", err)
+			}
+		}
 	}
 
 	// Routes and RouteClaims
@@ -350,11 +510,7 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 		// Search to see if any of the existing routes are not in the desired
 		// list of routes and therefore stale. If they are, delete them.
 		for _, route := range existingRoutes {
-			if algorithms.Search(
-				0,
-				v1alpha1.Routes{*route},
-				v1alpha1.Routes(desiredRoutes),
-			) {
+			if algorithms.Contains(desiredRoutes, *route, v1alpha1.CompareRoutes) {
 				continue
 			}
 
@@ -385,6 +541,7 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 	}
 
 	// RouteClaim reconciler
+	var actualRouteClaims []*v1alpha1.RouteClaim
 	{
 		logger.Debug("reconciling Route Claims")
 
@@ -406,10 +563,209 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, app *v1alpha1.App) error
 			} else if actual, err = r.reconcileRouteClaim(&desired, actual); err != nil {
 				return condition.MarkReconciliationError("updating existing", err)
 			}
+
+			actualRouteClaims = append(actualRouteClaims, actual)
+		}
+	}
+
+	// DestinationRule reconciler
+	{
+		logger.Debug("reconciling DestinationRule")
+
+		desired, err := resources.MakeDestinationRule(app, actualRouteClaims)
+		if err != nil {
+			return condition.MarkTemplateError(err)
+		}
+
+		ruleName := resources.DestinationRuleName(app)
+		actual, err := r.destinationRuleLister.DestinationRules(app.Namespace).Get(ruleName)
+		switch {
+		case apierrs.IsNotFound(err) && desired == nil:
+			// No routes need session affinity and there's nothing to clean up.
+
+		case apierrs.IsNotFound(err):
+			if _, err := r.SharedClientSet.Networking().DestinationRules(desired.Namespace).Create(desired); err != nil {
+				return condition.MarkReconciliationError("creating destination rule", err)
+			}
+
+		case err != nil:
+			return condition.MarkReconciliationError("getting destination rule", err)
+
+		case !metav1.IsControlledBy(actual, app):
+			return condition.MarkChildNotOwned(ruleName)
+
+		case desired == nil:
+			err := r.SharedClientSet.Networking().DestinationRules(actual.Namespace).Delete(ruleName, &metav1.DeleteOptions{})
+			if err != nil && !apierrs.IsNotFound(err) {
+				return condition.MarkReconciliationError("deleting destination rule", err)
+			}
+
+		default:
+			if _, err := r.reconcileDestinationRule(desired, actual); err != nil {
+				return condition.MarkReconciliationError("updating destination rule", err)
+			}
+		}
+	}
+
+	app.Status.MarkRoutesReady()
+
+	if err := r.gcRevisions(ctx, app); err != nil {
+		return err
+	}
+
+	// Instance indexes are best effort, Pod events will cause the App to be
+	// reconciled again.
+	if err := r.reconcileInstanceIndexes(app); err != nil {
+		logger.Warnf("failed to assign instance indexes: %s", err)
+	}
+
+	return nil
+}
+
+// reconcileInstanceIndexes annotates each of the App's Pods with the index of
+// the instance it runs.
+func (r *Reconciler) reconcileInstanceIndexes(app *v1alpha1.App) error {
+	selector := labels.Set{"serving.knative.dev/service": app.Name}.AsSelector()
+	pods, err := r.podLister.Pods(app.Namespace).List(selector)
+	if err != nil {
+		return err
+	}
+
+	for name, index := range resources.AssignInstanceIndexes(pods) {
+		patch := fmt.Sprintf(
+			`{"metadata":{"annotations":{%q:%q}}}`,
+			v1alpha1.InstanceIndexAnnotation,
+			strconv.Itoa(index),
+		)
+
+		_, err := r.KubeClientSet.
+			CoreV1().
+			Pods(app.Namespace).
+			Patch(name, types.MergePatchType, []byte(patch))
+		if err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applySchedule returns a copy of the App that's stopped if it's outside of
+// the hours set by its schedule at the given time. The App is queued to be
+// reconciled again when the schedule next changes.
+func (r *Reconciler) applySchedule(app *v1alpha1.App, now time.Time) (*v1alpha1.App, error) {
+	schedule := app.Spec.Instances.Schedule
+	if schedule == nil || app.Spec.Instances.Stopped {
+		return app, nil
+	}
+
+	stopped, next, err := schedule.StoppedAt(now)
+	if err != nil {
+		return nil, err
+	}
+
+	if !next.IsZero() && r.enqueueAfter != nil {
+		r.enqueueAfter(app, next.Sub(now))
+	}
+
+	if !stopped {
+		return app, nil
+	}
+
+	out := app.DeepCopy()
+	out.Spec.Instances.Stopped = true
+	return out, nil
+}
+
+// rotateServiceBinding deletes the ServiceBinding if its credentials are due
+// to be rotated so it's recreated with new ones. The App is queued to be
+// reconciled again when rotation is next due.
+func (r *Reconciler) rotateServiceBinding(
+	app *v1alpha1.App,
+	binding *v1alpha1.AppSpecServiceBinding,
+	actual *servicecatalogv1beta1.ServiceBinding,
+) (bool, error) {
+	now := time.Now()
+	due, next, err := resources.CredentialsRotationDue(binding, actual, now)
+	if err != nil {
+		return false, err
+	}
+
+	if !due {
+		if !next.IsZero() && r.enqueueAfter != nil {
+			r.enqueueAfter(app, next.Sub(now))
+		}
+		return false, nil
+	}
+
+	if err := r.serviceCatalogClient.
+		ServicecatalogV1beta1().
+		ServiceBindings(actual.Namespace).
+		Delete(actual.Name, &metav1.DeleteOptions{}); err != nil {
+		return false, err
+	}
+
+	// Recording the time changes the revision template so the App is
+	// restarted with the new credentials once they're ready.
+	app.Status.CredentialsRotatedAt = &metav1.Time{Time: now}
+	r.Recorder.Eventf(app, v1.EventTypeNormal, "RotatingCredentials",
+		"Rotating credentials for service binding %s", actual.Name)
+
+	return true, nil
+}
+
+// restartOnChangeObjects gets the Secrets and ConfigMaps the App restarts on
+// changes to. Missing objects are left out.
+func (r *Reconciler) restartOnChangeObjects(app *v1alpha1.App) (resources.RestartOnChangeObjects, error) {
+	objects := resources.RestartOnChangeObjects{
+		Secrets:    make(map[string]*v1.Secret),
+		ConfigMaps: make(map[string]*v1.ConfigMap),
+	}
+
+	for _, ref := range app.Spec.RestartOnChange {
+		switch ref.Kind {
+		case v1alpha1.RestartOnChangeKindSecret:
+			secret, err := r.secretLister.Secrets(app.Namespace).Get(ref.Name)
+			if apierrs.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return objects, err
+			}
+			objects.Secrets[ref.Name] = secret
+
+		case v1alpha1.RestartOnChangeKindConfigMap:
+			configMap, err := r.configMapLister.ConfigMaps(app.Namespace).Get(ref.Name)
+			if apierrs.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return objects, err
+			}
+			objects.ConfigMaps[ref.Name] = configMap
 		}
 	}
 
-	return r.gcRevisions(ctx, app)
+	return objects, nil
+}
+
+// reconcileRevisionStatus records whether the App's latest ready revision has
+// been scaled to zero. Stopped Apps have no revisions to check.
+func (r *Reconciler) reconcileRevisionStatus(app *v1alpha1.App, stopped bool) error {
+	name := app.Status.LatestReadyRevisionName
+	if name == "" || stopped {
+		app.Status.PropagateRevisionStatus(nil)
+		return nil
+	}
+
+	revision, err := r.knativeRevisionLister.Revisions(app.Namespace).Get(name)
+	if apierrs.IsNotFound(err) {
+		app.Status.PropagateRevisionStatus(nil)
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	app.Status.PropagateRevisionStatus(revision)
+	return nil
 }
 
 func (*Reconciler) sourcesAreSemanticallyEqual(desired, actual *v1alpha1.Source) bool {
@@ -441,6 +797,43 @@ func (r *Reconciler) reconcileKnativeService(desired, actual *serving.Service) (
 	return r.ServingClientSet.ServingV1alpha1().Services(existing.Namespace).Update(existing)
 }
 
+func (r *Reconciler) reconcileDeployment(desired, actual *appsv1.Deployment) (*appsv1.Deployment, error) {
+	// Only compare the fields the App manages, the API server defaults the
+	// rest of the spec.
+	semanticEqual := equality.Semantic.DeepEqual(desired.ObjectMeta.Labels, actual.ObjectMeta.Labels)
+	semanticEqual = semanticEqual && equality.Semantic.DeepEqual(desired.Spec.Replicas, actual.Spec.Replicas)
+	semanticEqual = semanticEqual && equality.Semantic.DeepDerivative(desired.Spec.Template, actual.Spec.Template)
+
+	if semanticEqual {
+		return actual, nil
+	}
+
+	if _, err := kmp.SafeDiff(desired.Spec, actual.Spec); err != nil {
+		return nil, fmt.Errorf("failed to diff deployment: %v", err)
+	}
+
+	// Don't modify the informers copy.
+	existing := actual.DeepCopy()
+
+	// Preserve the rest of the object (e.g. ObjectMeta except for labels).
+	existing.ObjectMeta.Labels = desired.ObjectMeta.Labels
+	existing.Spec.Replicas = desired.Spec.Replicas
+	existing.Spec.Template = desired.Spec.Template
+	return r.KubeClientSet.AppsV1().Deployments(existing.Namespace).Update(existing)
+}
+
+// containsDeployment returns true if a Deployment with the given name is in
+// the list.
+func containsDeployment(deployments []appsv1.Deployment, name string) bool {
+	for _, deployment := range deployments {
+		if deployment.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (r *Reconciler) reconcileRoute(desired, actual *v1alpha1.Route) (*v1alpha1.Route, error) {
 	// Check for differences, if none we don't need to reconcile.
 	semanticEqual := equality.Semantic.DeepEqual(desired.ObjectMeta.Labels, actual.ObjectMeta.Labels)
@@ -467,15 +860,16 @@ func (r *Reconciler) reconcileRoute(desired, actual *v1alpha1.Route) (*v1alpha1.
 }
 
 func (r *Reconciler) reconcileRouteClaim(desired, actual *v1alpha1.RouteClaim) (*v1alpha1.RouteClaim, error) {
-	// Check for differences, if none we don't need to reconcile.
+	// Only the RouteSpecFields are managed by the App, settings like
+	// maintenance mode are set by users directly on the claim.
 	semanticEqual := equality.Semantic.DeepEqual(desired.ObjectMeta.Labels, actual.ObjectMeta.Labels)
-	semanticEqual = semanticEqual && equality.Semantic.DeepEqual(desired.Spec, actual.Spec)
+	semanticEqual = semanticEqual && equality.Semantic.DeepEqual(desired.Spec.RouteSpecFields, actual.Spec.RouteSpecFields)
 
 	if semanticEqual {
 		return actual, nil
 	}
 
-	if _, err := kmp.SafeDiff(desired.Spec, actual.Spec); err != nil {
+	if _, err := kmp.SafeDiff(desired.Spec.RouteSpecFields, actual.Spec.RouteSpecFields); err != nil {
 		return nil, fmt.Errorf("failed to diff serving: %v", err)
 	}
 
@@ -484,13 +878,35 @@ func (r *Reconciler) reconcileRouteClaim(desired, actual *v1alpha1.RouteClaim) (
 
 	// Preserve the rest of the object (e.g. ObjectMeta except for labels).
 	existing.ObjectMeta.Labels = desired.ObjectMeta.Labels
-	existing.Spec = desired.Spec
+	existing.Spec.RouteSpecFields = desired.Spec.RouteSpecFields
 	return r.KfClientSet.
 		KfV1alpha1().
 		RouteClaims(existing.Namespace).
 		Update(existing)
 }
 
+func (r *Reconciler) reconcileDestinationRule(desired, actual *networking.DestinationRule) (*networking.DestinationRule, error) {
+	// Check for differences, if none we don't need to reconcile.
+	semanticEqual := equality.Semantic.DeepEqual(desired.ObjectMeta.Labels, actual.ObjectMeta.Labels)
+	semanticEqual = semanticEqual && equality.Semantic.DeepEqual(desired.Spec, actual.Spec)
+
+	if semanticEqual {
+		return actual, nil
+	}
+
+	if _, err := kmp.SafeDiff(desired.Spec, actual.Spec); err != nil {
+		return nil, fmt.Errorf("failed to diff DestinationRule: %v", err)
+	}
+
+	// Don't modify the informers copy.
+	existing := actual.DeepCopy()
+
+	// Preserve the rest of the object (e.g. ObjectMeta except for labels).
+	existing.ObjectMeta.Labels = desired.ObjectMeta.Labels
+	existing.Spec = desired.Spec
+	return r.SharedClientSet.Networking().DestinationRules(existing.Namespace).Update(existing)
+}
+
 func (r *Reconciler) reconcileSecret(desired, actual *v1.Secret) (*v1.Secret, error) {
 	// Check for differences, if none we don't need to reconcile.
 	semanticEqual := equality.Semantic.DeepEqual(desired.ObjectMeta.Labels, actual.ObjectMeta.Labels)
@@ -516,6 +932,7 @@ func (r *Reconciler) reconcileSecret(desired, actual *v1.Secret) (*v1.Secret, er
 func (r *Reconciler) reconcileServiceBinding(desired, actual *servicecatalogv1beta1.ServiceBinding) (*servicecatalogv1beta1.ServiceBinding, error) {
 	// Check for differences, if none we don't need to reconcile.
 	semanticEqual := equality.Semantic.DeepEqual(desired.ObjectMeta.Labels, actual.ObjectMeta.Labels)
+	semanticEqual = semanticEqual && resources.BackupHookAnnotationsEqual(desired.Annotations, actual.Annotations)
 	semanticEqual = semanticEqual && equality.Semantic.DeepEqual(desired.Spec, actual.Spec)
 
 	if semanticEqual {
@@ -529,8 +946,10 @@ func (r *Reconciler) reconcileServiceBinding(desired, actual *servicecatalogv1be
 	// Don't modify the informers copy.
 	existing := actual.DeepCopy()
 
-	// Preserve the rest of the object (e.g. ObjectMeta except for labels).
+	// Preserve the rest of the object (e.g. ObjectMeta except for labels and
+	// the annotations Kf manages).
 	existing.ObjectMeta.Labels = desired.ObjectMeta.Labels
+	existing.ObjectMeta.Annotations = resources.MergeBackupHookAnnotations(actual.Annotations, desired.Annotations)
 	existing.Spec = desired.Spec
 	return r.serviceCatalogClient.
 		ServicecatalogV1beta1().
@@ -0,0 +1,100 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestReconciler_applySchedule(t *testing.T) {
+	weekdays := &v1alpha1.AppSpecInstancesSchedule{
+		Start: "0 9 * * 1-5",
+		Stop:  "0 17 * * 1-5",
+	}
+
+	// A Monday.
+	monday := func(hour int) time.Time {
+		return time.Date(2019, time.September, 16, hour, 0, 0, 0, time.UTC)
+	}
+
+	cases := map[string]struct {
+		schedule *v1alpha1.AppSpecInstancesSchedule
+		stopped  bool
+		now      time.Time
+
+		wantStopped bool
+		wantAfter   time.Duration
+		wantErr     error
+	}{
+		"no schedule": {
+			now: monday(18),
+		},
+		"stopped App ignores schedule": {
+			schedule:    weekdays,
+			stopped:     true,
+			now:         monday(10),
+			wantStopped: true,
+		},
+		"within hours": {
+			schedule:  weekdays,
+			now:       monday(10),
+			wantAfter: 7 * time.Hour,
+		},
+		"outside hours": {
+			schedule:    weekdays,
+			now:         monday(18),
+			wantStopped: true,
+			wantAfter:   15 * time.Hour,
+		},
+		"invalid schedule": {
+			schedule: &v1alpha1.AppSpecInstancesSchedule{Start: "not cron", Stop: "0 17 * * *"},
+			now:      monday(10),
+			wantErr:  errors.New(`expected 5 fields in cron expression "not cron", got 2`),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			app := &v1alpha1.App{}
+			app.Name = "my-app"
+			app.Spec.Instances.Schedule = tc.schedule
+			app.Spec.Instances.Stopped = tc.stopped
+			original := app.DeepCopy()
+
+			var gotAfter time.Duration
+			r := &Reconciler{
+				enqueueAfter: func(obj interface{}, after time.Duration) {
+					testutil.AssertEqual(t, "enqueued", app, obj)
+					gotAfter = after
+				},
+			}
+
+			got, err := r.applySchedule(app, tc.now)
+			if tc.wantErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "stopped", tc.wantStopped, got.Spec.Instances.Stopped)
+			testutil.AssertEqual(t, "enqueued after", tc.wantAfter, gotAfter)
+			testutil.AssertEqual(t, "original unchanged", original, app)
+		})
+	}
+}
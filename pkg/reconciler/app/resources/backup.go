@@ -0,0 +1,143 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+)
+
+const (
+	// PreBackupHookAnnotation is set on a ServiceBinding to the command the
+	// App runs before the space is backed up.
+	PreBackupHookAnnotation = "kf.dev/pre-backup-hook"
+
+	// PostRestoreHookAnnotation is set on a ServiceBinding to the command the
+	// App runs after the space is restored.
+	PostRestoreHookAnnotation = "kf.dev/post-restore-hook"
+
+	// Velero reads exec hooks from these pod annotations, see
+	// https://velero.io/docs/main/backup-hooks/ and
+	// https://velero.io/docs/main/restore-hooks/
+	veleroPreBackupContainerAnnotation   = "pre.hook.backup.velero.io/container"
+	veleroPreBackupCommandAnnotation     = "pre.hook.backup.velero.io/command"
+	veleroPostRestoreContainerAnnotation = "post.hook.restore.velero.io/container"
+	veleroPostRestoreCommandAnnotation   = "post.hook.restore.velero.io/command"
+
+	// userContainerName is the name Knative gives the App's container.
+	userContainerName = "user-container"
+)
+
+var backupHookAnnotationKeys = []string{
+	PreBackupHookAnnotation,
+	PostRestoreHookAnnotation,
+}
+
+// MakeBackupHookAnnotations creates the annotations describing the backup
+// hooks of a binding, nil if it has none.
+func MakeBackupHookAnnotations(binding *v1alpha1.AppSpecServiceBinding) map[string]string {
+	hooks := binding.BackupHooks
+	if hooks == nil {
+		return nil
+	}
+
+	annotations := make(map[string]string)
+	if hooks.PreBackup != "" {
+		annotations[PreBackupHookAnnotation] = hooks.PreBackup
+	}
+
+	if hooks.PostRestore != "" {
+		annotations[PostRestoreHookAnnotation] = hooks.PostRestore
+	}
+
+	return annotations
+}
+
+// BackupHookAnnotationsEqual returns true if both sets of annotations have the
+// same backup hooks.
+func BackupHookAnnotationsEqual(a, b map[string]string) bool {
+	for _, key := range backupHookAnnotationKeys {
+		if a[key] != b[key] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MergeBackupHookAnnotations returns a copy of annotations with the backup
+// hooks replaced by the ones in hooks. Other annotations are preserved.
+func MergeBackupHookAnnotations(annotations, hooks map[string]string) map[string]string {
+	out := make(map[string]string)
+	for key, value := range annotations {
+		out[key] = value
+	}
+
+	for _, key := range backupHookAnnotationKeys {
+		if value, ok := hooks[key]; ok {
+			out[key] = value
+		} else {
+			delete(out, key)
+		}
+	}
+
+	return out
+}
+
+// addVeleroHookAnnotations adds the Velero annotations that run the backup
+// hooks of all the App's bindings to the revision annotations. Velero only
+// supports one hook of each type per pod so the commands are chained in the
+// order the bindings are declared.
+func addVeleroHookAnnotations(app *v1alpha1.App, annotations map[string]string) {
+	var preBackup, postRestore []string
+	for _, binding := range app.Spec.ServiceBindings {
+		if binding.BackupHooks == nil {
+			continue
+		}
+
+		if binding.BackupHooks.PreBackup != "" {
+			preBackup = append(preBackup, binding.BackupHooks.PreBackup)
+		}
+
+		if binding.BackupHooks.PostRestore != "" {
+			postRestore = append(postRestore, binding.BackupHooks.PostRestore)
+		}
+	}
+
+	if len(preBackup) > 0 {
+		annotations[veleroPreBackupContainerAnnotation] = userContainerName
+		annotations[veleroPreBackupCommandAnnotation] = veleroCommand(preBackup)
+	}
+
+	if len(postRestore) > 0 {
+		annotations[veleroPostRestoreContainerAnnotation] = userContainerName
+		annotations[veleroPostRestoreCommandAnnotation] = veleroCommand(postRestore)
+	}
+}
+
+// veleroCommand formats the commands as the JSON array Velero expects, the
+// chain stops at the first failing command.
+func veleroCommand(commands []string) string {
+	// Don't escape the ampersands, the annotation is read by people too.
+	buf := &bytes.Buffer{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode([]string{"/bin/sh", "-c", strings.Join(commands, " && ")})
+
+	return strings.TrimSpace(buf.String())
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func TestMakeBackupHookAnnotations(t *testing.T) {
+	cases := map[string]struct {
+		hooks *v1alpha1.AppSpecServiceBindingBackupHooks
+		want  map[string]string
+	}{
+		"no hooks": {},
+		"both hooks": {
+			hooks: &v1alpha1.AppSpecServiceBindingBackupHooks{
+				PreBackup:   "./flush.sh",
+				PostRestore: "./reload.sh",
+			},
+			want: map[string]string{
+				PreBackupHookAnnotation:   "./flush.sh",
+				PostRestoreHookAnnotation: "./reload.sh",
+			},
+		},
+		"pre-backup only": {
+			hooks: &v1alpha1.AppSpecServiceBindingBackupHooks{
+				PreBackup: "./flush.sh",
+			},
+			want: map[string]string{
+				PreBackupHookAnnotation: "./flush.sh",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			binding := &v1alpha1.AppSpecServiceBinding{BackupHooks: tc.hooks}
+			testutil.AssertEqual(t, "annotations", tc.want, MakeBackupHookAnnotations(binding))
+		})
+	}
+}
+
+func TestMergeBackupHookAnnotations(t *testing.T) {
+	existing := map[string]string{
+		"other":                   "kept",
+		PreBackupHookAnnotation:   "old",
+		PostRestoreHookAnnotation: "removed",
+	}
+
+	got := MergeBackupHookAnnotations(existing, map[string]string{
+		PreBackupHookAnnotation: "new",
+	})
+
+	testutil.AssertEqual(t, "merged", map[string]string{
+		"other":                 "kept",
+		PreBackupHookAnnotation: "new",
+	}, got)
+	testutil.AssertEqual(t, "original unmodified", "old", existing[PreBackupHookAnnotation])
+	testutil.AssertEqual(t, "equal", true, BackupHookAnnotationsEqual(got, map[string]string{
+		PreBackupHookAnnotation: "new",
+	}))
+	testutil.AssertEqual(t, "not equal", false, BackupHookAnnotationsEqual(got, existing))
+}
+
+func TestRevisionAnnotations_backupHooks(t *testing.T) {
+	app := &v1alpha1.App{}
+	app.Spec.ServiceBindings = []v1alpha1.AppSpecServiceBinding{
+		{
+			BindingName: "db",
+			BackupHooks: &v1alpha1.AppSpecServiceBindingBackupHooks{
+				PreBackup:   "./flush-db.sh",
+				PostRestore: "./reload-db.sh",
+			},
+		},
+		{
+			BindingName: "cache",
+		},
+		{
+			BindingName: "queue",
+			BackupHooks: &v1alpha1.AppSpecServiceBindingBackupHooks{
+				PreBackup: "./drain-queue.sh",
+			},
+		},
+	}
+
+	annotations := revisionAnnotations(app)
+
+	testutil.AssertEqual(t, "pre-backup container", "user-container", annotations["pre.hook.backup.velero.io/container"])
+	testutil.AssertEqual(t, "pre-backup command",
+		`["/bin/sh","-c","./flush-db.sh && ./drain-queue.sh"]`,
+		annotations["pre.hook.backup.velero.io/command"])
+	testutil.AssertEqual(t, "post-restore container", "user-container", annotations["post.hook.restore.velero.io/container"])
+	testutil.AssertEqual(t, "post-restore command",
+		`["/bin/sh","-c","./reload-db.sh"]`,
+		annotations["post.hook.restore.velero.io/command"])
+}
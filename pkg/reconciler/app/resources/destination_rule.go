@@ -0,0 +1,95 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/knative/serving/pkg/network"
+	"github.com/knative/serving/pkg/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	networking "knative.dev/pkg/apis/istio/v1alpha3"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	// SessionCookieName is the cookie Istio sets to pin clients to an App
+	// instance when cookie session affinity is enabled.
+	SessionCookieName = "kf-session"
+
+	// sessionCookieTTL of zero makes the cookie last for the browser session.
+	sessionCookieTTL = "0s"
+)
+
+// DestinationRuleName gets the name of the DestinationRule for the App.
+func DestinationRuleName(app *v1alpha1.App) string {
+	return app.Name
+}
+
+// MakeDestinationRule creates a DestinationRule that configures session
+// affinity for the App based on the RouteClaims of its routes. Load balancing
+// applies to the whole App so if routes ask for different kinds of affinity
+// cookies are preferred. If none of the claims ask for session affinity nil is
+// returned.
+func MakeDestinationRule(app *v1alpha1.App, claims []*v1alpha1.RouteClaim) (*networking.DestinationRule, error) {
+	var affinity *v1alpha1.RouteSessionAffinity
+	for _, claim := range claims {
+		if claim.Spec.SessionAffinity == nil {
+			continue
+		}
+
+		if affinity == nil || claim.Spec.SessionAffinity.Type == v1alpha1.SessionAffinityCookie {
+			affinity = claim.Spec.SessionAffinity
+		}
+	}
+
+	if affinity == nil {
+		return nil, nil
+	}
+
+	hash := &networking.ConsistentHashLB{}
+	switch affinity.Type {
+	case v1alpha1.SessionAffinityCookie:
+		hash.HTTPCookie = &networking.HTTPCookie{
+			Name: SessionCookieName,
+			Path: "/",
+			TTL:  sessionCookieTTL,
+		}
+	case v1alpha1.SessionAffinitySourceIP:
+		hash.UseSourceIP = true
+	}
+
+	return &networking.DestinationRule{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.istio.io/v1alpha3",
+			Kind:       "DestinationRule",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      DestinationRuleName(app),
+			Namespace: app.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(app),
+			},
+			Labels: resources.UnionMaps(app.GetLabels(), app.ComponentLabels("destinationrule")),
+		},
+		Spec: networking.DestinationRuleSpec{
+			Host: network.GetServiceHostname(app.Name, app.Namespace),
+			TrafficPolicy: &networking.TrafficPolicy{
+				LoadBalancer: &networking.LoadBalancerSettings{
+					ConsistentHash: hash,
+				},
+			},
+		},
+	}, nil
+}
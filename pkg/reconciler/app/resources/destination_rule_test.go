@@ -0,0 +1,105 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/knative/serving/pkg/network"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	networking "knative.dev/pkg/apis/istio/v1alpha3"
+)
+
+func TestMakeDestinationRule(t *testing.T) {
+	t.Parallel()
+
+	app := &v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-app",
+			Namespace: "my-space",
+		},
+	}
+
+	claim := func(t v1alpha1.SessionAffinityType) *v1alpha1.RouteClaim {
+		c := &v1alpha1.RouteClaim{}
+		if t != "" {
+			c.Spec.SessionAffinity = &v1alpha1.RouteSessionAffinity{Type: t}
+		}
+		return c
+	}
+
+	cases := map[string]struct {
+		claims     []*v1alpha1.RouteClaim
+		wantNil    bool
+		wantPolicy *networking.ConsistentHashLB
+	}{
+		"no claims": {
+			wantNil: true,
+		},
+		"no session affinity": {
+			claims:  []*v1alpha1.RouteClaim{claim("")},
+			wantNil: true,
+		},
+		"cookie": {
+			claims: []*v1alpha1.RouteClaim{claim(v1alpha1.SessionAffinityCookie)},
+			wantPolicy: &networking.ConsistentHashLB{
+				HTTPCookie: &networking.HTTPCookie{
+					Name: SessionCookieName,
+					Path: "/",
+					TTL:  "0s",
+				},
+			},
+		},
+		"source IP": {
+			claims: []*v1alpha1.RouteClaim{claim(v1alpha1.SessionAffinitySourceIP)},
+			wantPolicy: &networking.ConsistentHashLB{
+				UseSourceIP: true,
+			},
+		},
+		"cookie preferred over source IP": {
+			claims: []*v1alpha1.RouteClaim{
+				claim(v1alpha1.SessionAffinityCookie),
+				claim(v1alpha1.SessionAffinitySourceIP),
+			},
+			wantPolicy: &networking.ConsistentHashLB{
+				HTTPCookie: &networking.HTTPCookie{
+					Name: SessionCookieName,
+					Path: "/",
+					TTL:  "0s",
+				},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			rule, err := MakeDestinationRule(app, tc.claims)
+			testutil.AssertNil(t, "err", err)
+
+			if tc.wantNil {
+				testutil.AssertEqual(t, "rule", (*networking.DestinationRule)(nil), rule)
+				return
+			}
+
+			testutil.AssertEqual(t, "name", "my-app", rule.Name)
+			testutil.AssertEqual(t, "namespace", "my-space", rule.Namespace)
+			testutil.AssertEqual(t, "host", network.GetServiceHostname("my-app", "my-space"), rule.Spec.Host)
+			testutil.AssertEqual(t, "owner", "my-app", rule.OwnerReferences[0].Name)
+			testutil.AssertEqual(t, "policy", tc.wantPolicy, rule.Spec.TrafficPolicy.LoadBalancer.ConsistentHash)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// InstanceIndex returns the instance index the Pod was annotated with or
+// false if it doesn't have a valid one.
+func InstanceIndex(pod *corev1.Pod) (int, bool) {
+	value, ok := pod.Annotations[v1alpha1.InstanceIndexAnnotation]
+	if !ok {
+		return 0, false
+	}
+
+	index, err := strconv.Atoi(value)
+	if err != nil || index < 0 {
+		return 0, false
+	}
+
+	return index, true
+}
+
+// AssignInstanceIndexes picks an index for each of an App's Pods that doesn't
+// have one yet. Indexes are handed out lowest first to the oldest Pods so an
+// App with N instances uses the indexes 0 through N-1. The result maps Pod
+// names to the index they should be annotated with.
+func AssignInstanceIndexes(pods []*corev1.Pod) map[string]int {
+	used := make(map[int]bool)
+	var unassigned []*corev1.Pod
+	for _, pod := range pods {
+		if index, ok := InstanceIndex(pod); ok && !used[index] {
+			used[index] = true
+		} else {
+			unassigned = append(unassigned, pod)
+		}
+	}
+
+	sort.Slice(unassigned, func(i, j int) bool {
+		ti, tj := unassigned[i].CreationTimestamp, unassigned[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return unassigned[i].Name < unassigned[j].Name
+	})
+
+	assignments := make(map[string]int)
+	next := 0
+	for _, pod := range unassigned {
+		for used[next] {
+			next++
+		}
+
+		assignments[pod.Name] = next
+		used[next] = true
+	}
+
+	return assignments
+}
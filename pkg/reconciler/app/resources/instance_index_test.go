@@ -0,0 +1,70 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAssignInstanceIndexes(t *testing.T) {
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	pod := func(name string, age int, index string) *corev1.Pod {
+		p := &corev1.Pod{}
+		p.Name = name
+		p.CreationTimestamp = metav1.NewTime(start.Add(time.Duration(age) * time.Minute))
+		if index != "" {
+			p.Annotations = map[string]string{v1alpha1.InstanceIndexAnnotation: index}
+		}
+		return p
+	}
+
+	cases := map[string]struct {
+		pods []*corev1.Pod
+		want map[string]int
+	}{
+		"no pods": {
+			want: map[string]int{},
+		},
+		"new pods are ordered by age": {
+			pods: []*corev1.Pod{pod("b", 2, ""), pod("a", 1, ""), pod("c", 2, "")},
+			want: map[string]int{"a": 0, "b": 1, "c": 2},
+		},
+		"existing indexes are kept": {
+			pods: []*corev1.Pod{pod("a", 1, "0"), pod("b", 2, "1")},
+			want: map[string]int{},
+		},
+		"freed indexes are reused": {
+			pods: []*corev1.Pod{pod("a", 1, "0"), pod("c", 3, "2"), pod("d", 4, "")},
+			want: map[string]int{"d": 1},
+		},
+		"invalid and duplicate indexes are replaced": {
+			pods: []*corev1.Pod{pod("a", 1, "0"), pod("b", 2, "0"), pod("c", 3, "-1"), pod("d", 4, "x")},
+			want: map[string]int{"b": 1, "c": 2, "d": 3},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "assignments", tc.want, AssignInstanceIndexes(tc.pods))
+		})
+	}
+}
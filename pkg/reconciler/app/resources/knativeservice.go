@@ -18,9 +18,11 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/internal/envutil"
+	"github.com/google/kf/pkg/kf/cfutil"
 	serving "github.com/knative/serving/pkg/apis/serving/v1alpha1"
 	servingv1beta1 "github.com/knative/serving/pkg/apis/serving/v1beta1"
 	"github.com/knative/serving/pkg/resources"
@@ -30,6 +32,34 @@ import (
 	"knative.dev/pkg/ptr"
 )
 
+// DefaultColdStartTimeoutSeconds is the maximum time a request may take,
+// including starting an App that was scaled to zero, if the App doesn't
+// override it.
+const DefaultColdStartTimeoutSeconds = 300
+
+// CredentialsRotatedAtAnnotation is set on the revision template to the last
+// time the App's service binding credentials were rotated so a new revision
+// is rolled out with the new credentials.
+const CredentialsRotatedAtAnnotation = "kf.dev/credentials-rotated-at"
+
+// EgressIPPoolAnnotation is set on the App's pods to the egress IP pool its
+// outbound traffic uses so the cluster's egress solution can route it.
+const EgressIPPoolAnnotation = "kf.dev/egress-ip-pool"
+
+const (
+	// SidecarProxyCPUAnnotation sets the CPU requested by the Istio sidecar
+	// injected into the App's pods.
+	SidecarProxyCPUAnnotation = "sidecar.istio.io/proxyCPU"
+
+	// SidecarProxyMemoryAnnotation sets the memory requested by the Istio
+	// sidecar injected into the App's pods.
+	SidecarProxyMemoryAnnotation = "sidecar.istio.io/proxyMemory"
+)
+
+// bindingsVolumeName is the name of the volume service binding files are
+// mounted from.
+const bindingsVolumeName = "kf-bindings"
+
 // KnativeServiceName gets the name of a Knative Service given the route.
 func KnativeServiceName(app *v1alpha1.App) string {
 	return app.Name
@@ -39,9 +69,12 @@ func KnativeServiceName(app *v1alpha1.App) string {
 func MakeKnativeService(
 	app *v1alpha1.App,
 	space *v1alpha1.Space,
+	services []cfutil.VcapService,
 ) (*serving.Service, error) {
 
-	image := app.Status.Image
+	// Deploy by digest when the build reported one so a tag that gets pushed
+	// over can't change what runs, and rolling back restores the exact image.
+	image := app.Status.ImageReference()
 	if image == "" {
 		return nil, errors.New("waiting for source image in latestReadySource")
 	}
@@ -67,8 +100,7 @@ func MakeKnativeService(
 	podSpec.Containers[0].Image = image
 	// Execution environment variables come before others because they're built
 	// to be overridden.
-	podSpec.Containers[0].Env = append(space.Spec.Execution.Env, podSpec.Containers[0].Env...)
-	podSpec.Containers[0].Env = envutil.DeduplicateEnvVars(podSpec.Containers[0].Env)
+	podSpec.Containers[0].Env = envutil.OverrideEnvVars(space.Spec.Execution.Env, podSpec.Containers[0].Env)
 
 	// Inject VCAP env vars from secret
 	podSpec.Containers[0].EnvFrom = []corev1.EnvFromSource{
@@ -81,6 +113,18 @@ func MakeKnativeService(
 		},
 	}
 
+	if app.Spec.UsesK8sBindingFormat() {
+		mountBindings(app, podSpec, services)
+	}
+
+	annotations := revisionAnnotations(app)
+	addSidecarResourceAnnotations(space, annotations)
+
+	timeoutSeconds := ptr.Int64(DefaultColdStartTimeoutSeconds)
+	if app.Spec.Instances.ColdStartTimeoutSeconds != nil {
+		timeoutSeconds = ptr.Int64(*app.Spec.Instances.ColdStartTimeoutSeconds)
+	}
+
 	return &serving.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      KnativeServiceName(app),
@@ -95,24 +139,109 @@ func MakeKnativeService(
 				Template: &serving.RevisionTemplateSpec{
 					ObjectMeta: metav1.ObjectMeta{
 						Labels:      app.ComponentLabels("app-server"),
-						Annotations: app.Spec.Instances.ScalingAnnotations(),
+						Annotations: annotations,
 					},
 					Spec: serving.RevisionSpec{
 						RevisionSpec: servingv1beta1.RevisionSpec{
-							TimeoutSeconds: ptr.Int64(300),
+							TimeoutSeconds: timeoutSeconds,
 							PodSpec:        *podSpec,
 						},
 					},
 				},
 			},
 			RouteSpec: serving.RouteSpec{
-				Traffic: []serving.TrafficTarget{
-					{TrafficTarget: servingv1beta1.TrafficTarget{
-						LatestRevision: ptr.Bool(true),
-						Percent:        100,
-					}},
-				},
+				Traffic: trafficTargets(app),
 			},
 		},
 	}, nil
 }
+
+// trafficTargets splits the App's traffic between its stable and latest
+// revisions during a rollout, otherwise the latest ready revision gets it all.
+func trafficTargets(app *v1alpha1.App) []serving.TrafficTarget {
+	latest := serving.TrafficTarget{TrafficTarget: servingv1beta1.TrafficTarget{
+		LatestRevision: ptr.Bool(true),
+		Percent:        100,
+	}}
+
+	rollout := app.Spec.Rollout
+	if rollout == nil || rollout.StableRevisionName == "" {
+		return []serving.TrafficTarget{latest}
+	}
+
+	latest.Percent = rollout.LatestPercent
+	return []serving.TrafficTarget{
+		{TrafficTarget: servingv1beta1.TrafficTarget{
+			RevisionName:   rollout.StableRevisionName,
+			LatestRevision: ptr.Bool(false),
+			Percent:        100 - rollout.LatestPercent,
+		}},
+		latest,
+	}
+}
+
+// mountBindings mounts the App's bindings Secret at BindingsMountPath in the
+// layout described by the Kubernetes Service Binding specification.
+func mountBindings(app *v1alpha1.App, podSpec *corev1.PodSpec, services []cfutil.VcapService) {
+	var items []corev1.KeyToPath
+	for _, file := range bindingFiles(services) {
+		items = append(items, corev1.KeyToPath{Key: file.Key, Path: file.Path})
+	}
+
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: bindingsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: KfBindingsSecretName(app),
+				Items:      items,
+			},
+		},
+	})
+
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      bindingsVolumeName,
+		MountPath: BindingsMountPath,
+		ReadOnly:  true,
+	})
+	container.Env = append(container.Env, corev1.EnvVar{
+		Name:  "SERVICE_BINDING_ROOT",
+		Value: BindingsMountPath,
+	})
+}
+
+// revisionAnnotations returns the annotations for the App's revisions.
+func revisionAnnotations(app *v1alpha1.App) map[string]string {
+	annotations := app.Spec.Instances.ScalingAnnotations()
+
+	if rotatedAt := app.Status.CredentialsRotatedAt; rotatedAt != nil {
+		annotations[CredentialsRotatedAtAnnotation] = rotatedAt.UTC().Format(time.RFC3339)
+	}
+
+	if checksum := app.Status.RestartOnChangeChecksum; checksum != "" {
+		annotations[RestartOnChangeChecksumAnnotation] = checksum
+	}
+
+	if pool := app.Spec.EgressIPPool; pool != "" {
+		annotations[EgressIPPoolAnnotation] = pool
+	}
+
+	addVeleroHookAnnotations(app, annotations)
+
+	return annotations
+}
+
+// addSidecarResourceAnnotations sets the Istio sidecar resources configured
+// on the space so small Apps aren't dominated by the proxy's default
+// footprint.
+func addSidecarResourceAnnotations(space *v1alpha1.Space, annotations map[string]string) {
+	sidecar := space.Spec.Execution.SidecarResources
+
+	if sidecar.CPU != "" {
+		annotations[SidecarProxyCPUAnnotation] = sidecar.CPU
+	}
+
+	if sidecar.Memory != "" {
+		annotations[SidecarProxyMemoryAnnotation] = sidecar.Memory
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	serving "github.com/knative/serving/pkg/apis/serving/v1alpha1"
+	servingv1beta1 "github.com/knative/serving/pkg/apis/serving/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestMakeKnativeService_traffic(t *testing.T) {
+	cases := map[string]struct {
+		rollout     *v1alpha1.AppSpecRollout
+		wantTraffic []serving.TrafficTarget
+	}{
+		"no rollout": {
+			wantTraffic: []serving.TrafficTarget{
+				{TrafficTarget: servingv1beta1.TrafficTarget{LatestRevision: ptr.Bool(true), Percent: 100}},
+			},
+		},
+		"rollout started": {
+			rollout: &v1alpha1.AppSpecRollout{StableRevisionName: "my-app-abc12"},
+			wantTraffic: []serving.TrafficTarget{
+				{TrafficTarget: servingv1beta1.TrafficTarget{RevisionName: "my-app-abc12", LatestRevision: ptr.Bool(false), Percent: 100}},
+				{TrafficTarget: servingv1beta1.TrafficTarget{LatestRevision: ptr.Bool(true), Percent: 0}},
+			},
+		},
+		"rollout in progress": {
+			rollout: &v1alpha1.AppSpecRollout{StableRevisionName: "my-app-abc12", LatestPercent: 40},
+			wantTraffic: []serving.TrafficTarget{
+				{TrafficTarget: servingv1beta1.TrafficTarget{RevisionName: "my-app-abc12", LatestRevision: ptr.Bool(false), Percent: 60}},
+				{TrafficTarget: servingv1beta1.TrafficTarget{LatestRevision: ptr.Bool(true), Percent: 40}},
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			app := &v1alpha1.App{}
+			app.Name = "my-app"
+			app.Namespace = "my-space"
+			app.Status.Image = "gcr.io/my-app"
+			app.Spec.Template.Spec.Containers = []corev1.Container{{}}
+			app.Spec.Rollout = tc.rollout
+
+			service, err := MakeKnativeService(app, &v1alpha1.Space{}, nil)
+			testutil.AssertNil(t, "err", err)
+			testutil.AssertEqual(t, "traffic", tc.wantTraffic, service.Spec.Traffic)
+		})
+	}
+}
+
+func TestMakeKnativeService_sidecarResources(t *testing.T) {
+	cases := map[string]struct {
+		sidecar         v1alpha1.SpaceSidecarResources
+		wantAnnotations map[string]string
+	}{
+		"istio defaults": {
+			wantAnnotations: map[string]string{},
+		},
+		"cpu and memory": {
+			sidecar: v1alpha1.SpaceSidecarResources{CPU: "50m", Memory: "64Mi"},
+			wantAnnotations: map[string]string{
+				SidecarProxyCPUAnnotation:    "50m",
+				SidecarProxyMemoryAnnotation: "64Mi",
+			},
+		},
+		"memory only": {
+			sidecar: v1alpha1.SpaceSidecarResources{Memory: "64Mi"},
+			wantAnnotations: map[string]string{
+				SidecarProxyMemoryAnnotation: "64Mi",
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			app := &v1alpha1.App{}
+			app.Name = "my-app"
+			app.Namespace = "my-space"
+			app.Status.Image = "gcr.io/my-app"
+			app.Spec.Template.Spec.Containers = []corev1.Container{{}}
+
+			space := &v1alpha1.Space{}
+			space.Spec.Execution.SidecarResources = tc.sidecar
+
+			service, err := MakeKnativeService(app, space, nil)
+			testutil.AssertNil(t, "err", err)
+			testutil.AssertEqual(t, "annotations", tc.wantAnnotations, service.Spec.Template.Annotations)
+		})
+	}
+}
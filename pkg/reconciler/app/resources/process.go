@@ -0,0 +1,143 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/internal/envutil"
+	"github.com/knative/serving/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"knative.dev/pkg/kmeta"
+	"knative.dev/pkg/ptr"
+)
+
+// ProcessTypeLabel is set on process Deployments and their Pods to the
+// process type they run.
+const ProcessTypeLabel = "kf.dev/process-type"
+
+// processComponent is the component label of process Deployments.
+const processComponent = "process"
+
+// ProcessDeploymentName gets the name of the Deployment that runs the given
+// process type of the App.
+func ProcessDeploymentName(app *v1alpha1.App, processType string) string {
+	return fmt.Sprintf("%s-%s", app.Name, processType)
+}
+
+// MakeProcessDeploymentSelector creates a label selector for all the
+// process Deployments of the App.
+func MakeProcessDeploymentSelector(app *v1alpha1.App) labels.Selector {
+	return labels.NewSelector().Add(
+		mustRequirement(v1alpha1.ManagedByLabel, selection.Equals, "kf"),
+		mustRequirement(v1alpha1.NameLabel, selection.Equals, app.Name),
+		mustRequirement(v1alpha1.ComponentLabel, selection.Equals, processComponent),
+	)
+}
+
+// MakeProcessDeployments creates a Deployment for each of the App's processes
+// other than web, which is served by the App's Knative Service.
+func MakeProcessDeployments(app *v1alpha1.App, space *v1alpha1.Space) ([]appsv1.Deployment, error) {
+	var deployments []appsv1.Deployment
+	for _, process := range app.Spec.Processes {
+		if process.Type == v1alpha1.ProcessTypeWeb {
+			continue
+		}
+
+		deployment, err := MakeProcessDeployment(app, space, process)
+		if err != nil {
+			return nil, err
+		}
+
+		deployments = append(deployments, *deployment)
+	}
+
+	return deployments, nil
+}
+
+// MakeProcessDeployment creates a Deployment that runs the process's command
+// using the App's image and environment. Processes don't receive traffic so
+// ports and probes are removed from the container.
+func MakeProcessDeployment(
+	app *v1alpha1.App,
+	space *v1alpha1.Space,
+	process v1alpha1.AppSpecProcess,
+) (*appsv1.Deployment, error) {
+	image := app.Status.ImageReference()
+	if image == "" {
+		return nil, errors.New("waiting for source image in latestReadySource")
+	}
+
+	podSpec := app.Spec.Template.Spec.DeepCopy()
+	if len(podSpec.Containers) == 0 {
+		podSpec.Containers = append(podSpec.Containers, corev1.Container{})
+	}
+
+	container := &podSpec.Containers[0]
+	container.Image = image
+	container.Command = nil
+	container.Args = []string{process.Command}
+	container.Ports = nil
+	container.ReadinessProbe = nil
+	container.LivenessProbe = nil
+	container.Env = envutil.OverrideEnvVars(space.Spec.Execution.Env, container.Env)
+	container.EnvFrom = []corev1.EnvFromSource{
+		{
+			SecretRef: &corev1.SecretEnvSource{
+				LocalObjectReference: corev1.LocalObjectReference{
+					Name: KfInjectedEnvSecretName(app),
+				},
+			},
+		},
+	}
+
+	var replicas int32
+	if process.Instances != nil && !app.Spec.Instances.Stopped {
+		replicas = int32(*process.Instances)
+	}
+
+	podLabels := resources.UnionMaps(app.ComponentLabels(processComponent), map[string]string{
+		ProcessTypeLabel: process.Type,
+	})
+
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ProcessDeploymentName(app, process.Type),
+			Namespace: app.Namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(app),
+			},
+			Labels: resources.UnionMaps(app.GetLabels(), podLabels),
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.Int32(replicas),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: podLabels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: podLabels,
+				},
+				Spec: *podSpec,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestMakeProcessDeployments(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+
+	space := &v1alpha1.Space{}
+	space.Spec.Execution.Env = []corev1.EnvVar{{Name: "SPACE", Value: "env"}}
+
+	cases := map[string]struct {
+		setup  func(app *v1alpha1.App)
+		assert func(t *testing.T, deployments []appsv1.Deployment, err error)
+	}{
+		"web is served by the Knative Service": {
+			assert: func(t *testing.T, deployments []appsv1.Deployment, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "count", 1, len(deployments))
+				testutil.AssertEqual(t, "name", "my-app-worker", deployments[0].Name)
+				testutil.AssertEqual(t, "namespace", "my-space", deployments[0].Namespace)
+			},
+		},
+		"runs the process command": {
+			assert: func(t *testing.T, deployments []appsv1.Deployment, err error) {
+				testutil.AssertNil(t, "err", err)
+
+				container := deployments[0].Spec.Template.Spec.Containers[0]
+				testutil.AssertEqual(t, "image", "gcr.io/my-app", container.Image)
+				testutil.AssertEqual(t, "args", []string{"bundle exec sidekiq"}, container.Args)
+				testutil.AssertEqual(t, "ports", 0, len(container.Ports))
+				testutil.AssertEqual(t, "readiness probe", (*corev1.Probe)(nil), container.ReadinessProbe)
+				testutil.AssertEqual(t, "env", []corev1.EnvVar{
+					{Name: "FOO", Value: "bar"},
+					{Name: "SPACE", Value: "env"},
+				}, container.Env)
+				testutil.AssertEqual(t, "env secret", "kf-injected-envs-my-app", container.EnvFrom[0].SecretRef.Name)
+			},
+		},
+		"labels select the process pods": {
+			assert: func(t *testing.T, deployments []appsv1.Deployment, err error) {
+				testutil.AssertNil(t, "err", err)
+
+				deployment := deployments[0]
+				testutil.AssertEqual(t, "selector", deployment.Spec.Template.Labels, deployment.Spec.Selector.MatchLabels)
+				testutil.AssertEqual(t, "process type", "worker", deployment.Labels[ProcessTypeLabel])
+				testutil.AssertEqual(t, "owner", "my-app", deployment.OwnerReferences[0].Name)
+			},
+		},
+		"replicas come from the process": {
+			assert: func(t *testing.T, deployments []appsv1.Deployment, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "replicas", int32(2), *deployments[0].Spec.Replicas)
+			},
+		},
+		"processes default to no replicas": {
+			setup: func(app *v1alpha1.App) {
+				app.Spec.Processes[1].Instances = nil
+			},
+			assert: func(t *testing.T, deployments []appsv1.Deployment, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "replicas", int32(0), *deployments[0].Spec.Replicas)
+			},
+		},
+		"stopped apps have no replicas": {
+			setup: func(app *v1alpha1.App) {
+				app.Spec.Instances.Stopped = true
+			},
+			assert: func(t *testing.T, deployments []appsv1.Deployment, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "replicas", int32(0), *deployments[0].Spec.Replicas)
+			},
+		},
+		"waits for the image": {
+			setup: func(app *v1alpha1.App) {
+				app.Status.Image = ""
+			},
+			assert: func(t *testing.T, deployments []appsv1.Deployment, err error) {
+				testutil.AssertErrorsEqual(t, errors.New("waiting for source image in latestReadySource"), err)
+			},
+		},
+		"no processes": {
+			setup: func(app *v1alpha1.App) {
+				app.Spec.Processes = nil
+			},
+			assert: func(t *testing.T, deployments []appsv1.Deployment, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "count", 0, len(deployments))
+			},
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			app := &v1alpha1.App{}
+			app.Name = "my-app"
+			app.Namespace = "my-space"
+			app.Status.Image = "gcr.io/my-app"
+			app.Spec.Template.Spec.Containers = []corev1.Container{{
+				Ports: []corev1.ContainerPort{{ContainerPort: 8080}},
+				Env:   []corev1.EnvVar{{Name: "FOO", Value: "bar"}},
+				ReadinessProbe: &corev1.Probe{
+					Handler: corev1.Handler{TCPSocket: &corev1.TCPSocketAction{}},
+				},
+			}}
+			app.Spec.Processes = []v1alpha1.AppSpecProcess{
+				{Type: "web", Command: "bundle exec puma"},
+				{Type: "worker", Command: "bundle exec sidekiq", Instances: intPtr(2)},
+			}
+
+			if tc.setup != nil {
+				tc.setup(app)
+			}
+
+			deployments, err := MakeProcessDeployments(app, space)
+			tc.assert(t, deployments, err)
+		})
+	}
+}
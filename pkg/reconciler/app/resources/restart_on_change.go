@@ -0,0 +1,96 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// RestartOnChangeChecksumAnnotation is set on the revision template to the
+// checksum of the objects the App restarts on changes to, so a new revision
+// is rolled out when their data changes.
+const RestartOnChangeChecksumAnnotation = "kf.dev/restart-on-change-checksum"
+
+// RestartOnChangeObjects holds the Secrets and ConfigMaps an App restarts on
+// changes to, keyed by name. Objects that don't exist are left out.
+type RestartOnChangeObjects struct {
+	Secrets    map[string]*corev1.Secret
+	ConfigMaps map[string]*corev1.ConfigMap
+}
+
+// RestartOnChangeChecksum hashes the data of the objects the App restarts on
+// changes to. Missing objects are hashed too, so creating or deleting them
+// also restarts the App. Apps without any return an empty checksum.
+func RestartOnChangeChecksum(app *v1alpha1.App, objects RestartOnChangeObjects) string {
+	if len(app.Spec.RestartOnChange) == 0 {
+		return ""
+	}
+
+	refs := append([]v1alpha1.AppSpecRestartOnChange{}, app.Spec.RestartOnChange...)
+	sort.Slice(refs, func(i, j int) bool {
+		return refs[i].String() < refs[j].String()
+	})
+
+	hash := sha256.New()
+	for _, ref := range refs {
+		fmt.Fprintf(hash, "%s\x00", ref)
+
+		switch ref.Kind {
+		case v1alpha1.RestartOnChangeKindSecret:
+			if secret, ok := objects.Secrets[ref.Name]; ok {
+				hashData(hash, secret.Data)
+			} else {
+				fmt.Fprint(hash, "missing\x00")
+			}
+
+		case v1alpha1.RestartOnChangeKindConfigMap:
+			if configMap, ok := objects.ConfigMaps[ref.Name]; ok {
+				data := make(map[string][]byte)
+				for k, v := range configMap.Data {
+					data[k] = []byte(v)
+				}
+				for k, v := range configMap.BinaryData {
+					data[k] = v
+				}
+				hashData(hash, data)
+			} else {
+				fmt.Fprint(hash, "missing\x00")
+			}
+		}
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// hashData writes the data to w in a stable order.
+func hashData(w io.Writer, data map[string][]byte) {
+	var keys []string
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(w, "%d\x00", len(keys))
+	for _, k := range keys {
+		fmt.Fprintf(w, "%s\x00%d\x00", k, len(data[k]))
+		w.Write(data[k])
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRestartOnChangeChecksum(t *testing.T) {
+	t.Parallel()
+
+	app := func(refs ...v1alpha1.AppSpecRestartOnChange) *v1alpha1.App {
+		return &v1alpha1.App{Spec: v1alpha1.AppSpec{RestartOnChange: refs}}
+	}
+	secretRef := v1alpha1.AppSpecRestartOnChange{Kind: v1alpha1.RestartOnChangeKindSecret, Name: "creds"}
+	configMapRef := v1alpha1.AppSpecRestartOnChange{Kind: v1alpha1.RestartOnChangeKindConfigMap, Name: "settings"}
+
+	objects := func(password, level string) RestartOnChangeObjects {
+		return RestartOnChangeObjects{
+			Secrets: map[string]*corev1.Secret{
+				"creds": {Data: map[string][]byte{"password": []byte(password)}},
+			},
+			ConfigMaps: map[string]*corev1.ConfigMap{
+				"settings": {Data: map[string]string{"level": level}},
+			},
+		}
+	}
+
+	base := RestartOnChangeChecksum(app(secretRef, configMapRef), objects("hunter2", "info"))
+
+	t.Run("no references", func(t *testing.T) {
+		testutil.AssertEqual(t, "checksum", "", RestartOnChangeChecksum(app(), objects("hunter2", "info")))
+	})
+
+	t.Run("stable", func(t *testing.T) {
+		testutil.AssertEqual(t, "checksum", base, RestartOnChangeChecksum(app(configMapRef, secretRef), objects("hunter2", "info")))
+	})
+
+	t.Run("secret changes", func(t *testing.T) {
+		got := RestartOnChangeChecksum(app(secretRef, configMapRef), objects("hunter3", "info"))
+		testutil.AssertEqual(t, "changed", true, got != base)
+	})
+
+	t.Run("config map changes", func(t *testing.T) {
+		got := RestartOnChangeChecksum(app(secretRef, configMapRef), objects("hunter2", "debug"))
+		testutil.AssertEqual(t, "changed", true, got != base)
+	})
+
+	t.Run("unreferenced objects are ignored", func(t *testing.T) {
+		withSecret := RestartOnChangeChecksum(app(secretRef), objects("hunter2", "info"))
+		testutil.AssertEqual(t, "checksum", withSecret, RestartOnChangeChecksum(app(secretRef), objects("hunter2", "debug")))
+	})
+
+	t.Run("missing object", func(t *testing.T) {
+		got := RestartOnChangeChecksum(app(secretRef, configMapRef), RestartOnChangeObjects{})
+		testutil.AssertEqual(t, "changed", true, got != base)
+		testutil.AssertEqual(t, "not empty", true, got != "")
+	})
+}
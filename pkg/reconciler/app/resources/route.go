@@ -104,14 +104,13 @@ func MakeRoutes(
 		appRoute := appRoute.DeepCopy()
 		appRoute.SetSpaceDefaults(space)
 
+		if err := space.Spec.Execution.CheckHostname(appRoute.Hostname, app.Name); err != nil {
+			return nil, nil, err
+		}
+
 		routes = append(routes, v1alpha1.Route{
 			ObjectMeta: metav1.ObjectMeta{
-				Name: v1alpha1.GenerateRouteName(
-					appRoute.Hostname,
-					appRoute.Domain,
-					appRoute.Path,
-					app.Name,
-				),
+				Name:      v1alpha1.GenerateRouteNameFromSpec(*appRoute, app.Name),
 				Namespace: space.Name,
 				Labels: UnionMaps(
 					app.GetLabels(),
@@ -129,7 +128,13 @@ func MakeRoutes(
 			},
 		})
 
-		// Claim route
+		// Claim route, claims reserve the whole path so they don't match on
+		// headers or rewrite it.
+		claimFields := *appRoute.DeepCopy()
+		claimFields.Headers = nil
+		claimFields.StripPathPrefix = false
+		claimFields.RewritePath = ""
+
 		claims = append(claims, v1alpha1.RouteClaim{
 			ObjectMeta: metav1.ObjectMeta{
 				Labels: MakeRouteLabels(*appRoute),
@@ -141,7 +146,7 @@ func MakeRoutes(
 				Namespace: space.Name,
 			},
 			Spec: v1alpha1.RouteClaimSpec{
-				RouteSpecFields: *appRoute,
+				RouteSpecFields: claimFields,
 			},
 		})
 	}
@@ -15,6 +15,7 @@
 package resources
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -72,6 +73,46 @@ func TestMakeRoutes(t *testing.T) {
 				testutil.AssertEqual(t, "route.Spec.Path", "/some-path", claims[0].Spec.Path)
 			},
 		},
+		"header routes": {
+			app: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "some-name",
+				},
+				Spec: v1alpha1.AppSpec{
+					Routes: []v1alpha1.RouteSpecFields{
+						{Hostname: "some-hostname", Domain: "example.com", Path: "/some-path"},
+						{Hostname: "some-hostname", Domain: "example.com", Path: "/some-path", Headers: map[string]string{"x-beta": "true"}},
+					},
+				},
+			},
+			assert: func(t *testing.T, routes []v1alpha1.Route, claims []v1alpha1.RouteClaim) {
+				testutil.AssertEqual(t, "len(routes)", 2, len(routes))
+				if routes[0].Name == routes[1].Name {
+					t.Fatalf("expected Routes with different headers to have different names, got %q", routes[0].Name)
+				}
+				testutil.AssertEqual(t, "route.Spec.Headers", map[string]string{"x-beta": "true"}, routes[1].Spec.Headers)
+
+				testutil.AssertEqual(t, "len(claims)", 2, len(claims))
+				testutil.AssertEqual(t, "claim names", claims[0].Name, claims[1].Name)
+				testutil.AssertEqual(t, "claim.Spec.Headers", map[string]string(nil), claims[1].Spec.Headers)
+			},
+		},
+		"rewrite routes": {
+			app: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "some-name",
+				},
+				Spec: v1alpha1.AppSpec{
+					Routes: []v1alpha1.RouteSpecFields{
+						{Hostname: "some-hostname", Domain: "example.com", Path: "/some-path", StripPathPrefix: true},
+					},
+				},
+			},
+			assert: func(t *testing.T, routes []v1alpha1.Route, claims []v1alpha1.RouteClaim) {
+				testutil.AssertEqual(t, "route.Spec.StripPathPrefix", true, routes[0].Spec.StripPathPrefix)
+				testutil.AssertEqual(t, "claim.Spec.StripPathPrefix", false, claims[0].Spec.StripPathPrefix)
+			},
+		},
 		"no domain, uses space default": {
 			space: v1alpha1.Space{
 				Spec: v1alpha1.SpaceSpec{
@@ -151,6 +192,61 @@ func TestMakeRoutes(t *testing.T) {
 	}
 }
 
+func TestMakeRoutes_reservedHostnames(t *testing.T) {
+	t.Parallel()
+
+	space := v1alpha1.Space{
+		Spec: v1alpha1.SpaceSpec{
+			Execution: v1alpha1.SpaceSpecExecution{
+				ReservedHostnames: []v1alpha1.SpaceReservedHostname{
+					{Hostname: "api", AllowedApps: []string{"api-gateway"}},
+				},
+			},
+		},
+	}
+
+	for tn, tc := range map[string]struct {
+		appName  string
+		hostname string
+		wantErr  error
+	}{
+		"unreserved hostname": {
+			appName:  "some-app",
+			hostname: "some-hostname",
+		},
+		"reserved hostname": {
+			appName:  "some-app",
+			hostname: "api",
+			wantErr:  errors.New("the hostname api is reserved in the space and App some-app isn't allowed to use it"),
+		},
+		"reserved hostname different case": {
+			appName:  "some-app",
+			hostname: "API",
+			wantErr:  errors.New("the hostname API is reserved in the space and App some-app isn't allowed to use it"),
+		},
+		"granted hostname": {
+			appName:  "api-gateway",
+			hostname: "api",
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			app := v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: tc.appName,
+				},
+				Spec: v1alpha1.AppSpec{
+					Routes: []v1alpha1.RouteSpecFields{
+						{Hostname: tc.hostname, Domain: "example.com"},
+					},
+				},
+			}
+
+			_, _, err := MakeRoutes(&app, &space)
+			testutil.AssertErrorsEqual(t, tc.wantErr, err)
+		})
+	}
+}
+
 func ExampleMakeRouteLabels() {
 	l := MakeRouteLabels(v1alpha1.RouteSpecFields{
 		Hostname: "some-hostname",
@@ -16,6 +16,8 @@ package resources
 
 import (
 	"fmt"
+	"path"
+	"sort"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/cfutil"
@@ -56,5 +58,82 @@ func MakeKfInjectedEnvSecret(app *v1alpha1.App, space *v1alpha1.Space, serviceBi
 		secret.Data[envVar.Name] = []byte(envVar.Value)
 	}
 
+	// Apps using the k8s binding format get their credentials as files
+	// instead.
+	if app.Spec.UsesK8sBindingFormat() {
+		delete(secret.Data, "VCAP_SERVICES")
+	}
+
 	return secret, nil
 }
+
+// KfBindingsSecretName gets the name of the secret holding the service
+// binding files for the given application.
+func KfBindingsSecretName(app *v1alpha1.App) string {
+	return fmt.Sprintf("kf-bindings-%s", app.Name)
+}
+
+// BindingsMountPath is where service binding files are mounted for Apps using
+// the k8s binding format. It's exposed to the App as SERVICE_BINDING_ROOT.
+const BindingsMountPath = "/bindings"
+
+// bindingFile is a single file in the Kubernetes Service Binding layout.
+type bindingFile struct {
+	// Key is the key of the file in the bindings Secret.
+	Key string
+	// Path is the path of the file relative to BindingsMountPath.
+	Path string
+	// Value is the contents of the file.
+	Value string
+}
+
+// bindingFiles lays out the services as a directory per binding with a type
+// file and a file for each credential, sorted by path.
+func bindingFiles(services []cfutil.VcapService) []bindingFile {
+	var files []bindingFile
+	add := func(service cfutil.VcapService, name, value string) {
+		files = append(files, bindingFile{
+			Key:   fmt.Sprintf("%s_%s", service.Name, name),
+			Path:  path.Join(service.Name, name),
+			Value: value,
+		})
+	}
+
+	for _, service := range services {
+		add(service, "type", service.Label)
+		for name, value := range service.Credentials {
+			// The spec reserves the type file, the broker's can't replace it.
+			if name != "type" {
+				add(service, name, value)
+			}
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files
+}
+
+// MakeKfBindingsSecret creates a Secret containing the service binding files
+// for the given application.
+func MakeKfBindingsSecret(app *v1alpha1.App, space *v1alpha1.Space, services []cfutil.VcapService) *v1.Secret {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      KfBindingsSecretName(app),
+			Namespace: space.Name,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(app),
+			},
+			Labels: resources.UnionMaps(app.GetLabels(), app.ComponentLabels("bindings")),
+		},
+		Data: make(map[string][]byte),
+	}
+
+	for _, file := range bindingFiles(services) {
+		secret.Data[file.Key] = []byte(file.Value)
+	}
+
+	return secret
+}
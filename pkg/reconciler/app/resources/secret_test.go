@@ -20,6 +20,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/cfutil"
 	cfutilfake "github.com/google/kf/pkg/kf/cfutil/fake"
 	"github.com/google/kf/pkg/kf/testutil"
 	apiv1beta1 "github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
@@ -115,3 +116,70 @@ func TestMakeKfInjectedEnvSecret_happyPath(t *testing.T) {
 		string(secret.Data[vcapServices.Name]),
 	)
 }
+
+func TestMakeKfInjectedEnvSecret_k8sBindingFormat(t *testing.T) {
+	envVars := []v1.EnvVar{
+		{Name: "VCAP_APPLICATION", Value: `{"application_name":"some-app"}`},
+		{Name: "VCAP_SERVICES", Value: "{}"},
+	}
+	ctrl := gomock.NewController(t)
+
+	fakeInjector := cfutilfake.NewFakeSystemEnvInjector(ctrl)
+	fakeInjector.EXPECT().ComputeSystemEnv(gomock.Any(), gomock.Any()).Return(envVars, nil)
+
+	app := v1alpha1.App{}
+	app.Name = "some-app-name"
+	app.Spec.BindingFormat = v1alpha1.BindingFormatK8s
+	space := v1alpha1.Space{}
+	space.Name = "some-namespace"
+
+	secret, err := MakeKfInjectedEnvSecret(&app, &space, nil, fakeInjector)
+
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "secret.Data", map[string][]byte{
+		"VCAP_APPLICATION": []byte(`{"application_name":"some-app"}`),
+	}, secret.Data)
+}
+
+func ExampleKfBindingsSecretName() {
+	app := &v1alpha1.App{}
+	app.Name = "my-app"
+
+	fmt.Println(KfBindingsSecretName(app))
+
+	// Output: kf-bindings-my-app
+}
+
+func TestMakeKfBindingsSecret(t *testing.T) {
+	app := v1alpha1.App{}
+	app.Name = "some-app-name"
+	space := v1alpha1.Space{}
+	space.Name = "some-namespace"
+
+	services := []cfutil.VcapService{
+		{
+			Name:  "db",
+			Label: "mysql",
+			Credentials: map[string]string{
+				"username": "admin",
+				"type":     "ignored",
+			},
+		},
+		{
+			Name:        "cache",
+			Label:       "redis",
+			Credentials: map[string]string{"uri": "redis://cache"},
+		},
+	}
+
+	secret := MakeKfBindingsSecret(&app, &space, services)
+
+	testutil.AssertEqual(t, "secret.Name", "kf-bindings-some-app-name", secret.Name)
+	testutil.AssertEqual(t, "secret.Namespace", "some-namespace", secret.Namespace)
+	testutil.AssertEqual(t, "secret.Data", map[string][]byte{
+		"db_type":     []byte("mysql"),
+		"db_username": []byte("admin"),
+		"cache_type":  []byte("redis"),
+		"cache_uri":   []byte("redis://cache"),
+	}, secret.Data)
+}
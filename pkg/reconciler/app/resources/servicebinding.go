@@ -17,6 +17,7 @@ package resources
 import (
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/knative/serving/pkg/resources"
@@ -71,7 +72,8 @@ func MakeServiceBinding(app *v1alpha1.App, binding *v1alpha1.AppSpecServiceBindi
 			OwnerReferences: []metav1.OwnerReference{
 				*kmeta.NewControllerRef(app),
 			},
-			Labels: resources.UnionMaps(app.GetLabels(), MakeServiceBindingLabels(app, binding)),
+			Labels:      resources.UnionMaps(app.GetLabels(), MakeServiceBindingLabels(app, binding)),
+			Annotations: MakeBackupHookAnnotations(binding),
 		},
 		Spec: servicecatalogv1beta1.ServiceBindingSpec{
 			InstanceRef: servicecatalogv1beta1.LocalObjectReference{
@@ -81,3 +83,36 @@ func MakeServiceBinding(app *v1alpha1.App, binding *v1alpha1.AppSpecServiceBindi
 		},
 	}, nil
 }
+
+// CredentialsRotationDue returns true if the credentials of the actual
+// ServiceBinding are older than the rotation period of the binding and its
+// maintenance window is open. If rotation isn't due, the time it should next
+// be checked is returned. The time is zero if rotation will never be due.
+func CredentialsRotationDue(
+	binding *v1alpha1.AppSpecServiceBinding,
+	actual *servicecatalogv1beta1.ServiceBinding,
+	now time.Time,
+) (bool, time.Time, error) {
+	rotation := binding.Rotation
+	if rotation == nil || rotation.Period.Duration <= 0 {
+		return false, time.Time{}, nil
+	}
+
+	// Bindings are recreated to rotate them so the credentials are as old as
+	// the binding.
+	expires := actual.CreationTimestamp.Add(rotation.Period.Duration)
+	if now.Before(expires) {
+		return false, expires, nil
+	}
+
+	if rotation.Window == nil {
+		return true, time.Time{}, nil
+	}
+
+	open, next, err := rotation.Window.OpenAt(now)
+	if err != nil || open {
+		return open, time.Time{}, err
+	}
+
+	return false, next, nil
+}
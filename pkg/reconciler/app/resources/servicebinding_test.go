@@ -18,10 +18,12 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/describe"
 	"github.com/google/kf/pkg/kf/testutil"
+	servicecatalogv1beta1 "github.com/poy/service-catalog/pkg/apis/servicecatalog/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -104,3 +106,62 @@ func TestMakeServiceBinding(t *testing.T) {
 
 	testutil.AssertEqual(t, "labels", expectedLabels, binding.Labels)
 }
+
+func TestCredentialsRotationDue(t *testing.T) {
+	// Thursday
+	created := time.Date(2019, time.August, 1, 12, 0, 0, 0, time.UTC)
+	actual := &servicecatalogv1beta1.ServiceBinding{}
+	actual.CreationTimestamp = metav1.NewTime(created)
+
+	weekly := &v1alpha1.AppSpecServiceBindingRotation{
+		Period: metav1.Duration{Duration: 7 * 24 * time.Hour},
+	}
+
+	weeklyInWindow := weekly.DeepCopy()
+	weeklyInWindow.Window = &v1alpha1.MaintenanceWindow{
+		Start: "0 2 * * 6",
+		Stop:  "0 4 * * 6",
+	}
+
+	cases := map[string]struct {
+		rotation *v1alpha1.AppSpecServiceBindingRotation
+		now      time.Time
+		wantDue  bool
+		wantNext time.Time
+	}{
+		"no rotation": {
+			now: created.Add(365 * 24 * time.Hour),
+		},
+		"not expired": {
+			rotation: weekly,
+			now:      created.Add(time.Hour),
+			wantNext: created.Add(7 * 24 * time.Hour),
+		},
+		"expired": {
+			rotation: weekly,
+			now:      created.Add(8 * 24 * time.Hour),
+			wantDue:  true,
+		},
+		"expired outside window": {
+			rotation: weeklyInWindow,
+			now:      created.Add(8 * 24 * time.Hour),
+			wantNext: time.Date(2019, time.August, 10, 2, 0, 0, 0, time.UTC),
+		},
+		"expired in window": {
+			rotation: weeklyInWindow,
+			now:      time.Date(2019, time.August, 10, 3, 0, 0, 0, time.UTC),
+			wantDue:  true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			binding := &v1alpha1.AppSpecServiceBinding{Rotation: tc.rotation}
+
+			due, next, err := CredentialsRotationDue(binding, actual, tc.now)
+			testutil.AssertNil(t, "error", err)
+			testutil.AssertEqual(t, "due", tc.wantDue, due)
+			testutil.AssertEqual(t, "next", tc.wantNext.Unix(), next.Unix())
+		})
+	}
+}
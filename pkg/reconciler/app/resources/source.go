@@ -19,6 +19,7 @@ import (
 	"path"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/stacks"
 	"github.com/knative/serving/pkg/resources"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"knative.dev/pkg/kmeta"
@@ -33,7 +34,7 @@ func MakeSourceName(app *v1alpha1.App) string {
 
 // BuildpackBuildImageDestination gets the image name for an application build.
 func BuildpackBuildImageDestination(app *v1alpha1.App, space *v1alpha1.Space) string {
-	registry := space.Spec.BuildpackBuild.ContainerRegistry
+	registry := space.EffectiveContainerRegistry()
 
 	// Use underscores because those aren't permitted in k8s names so you can't
 	// cause accidental conflicts.
@@ -43,7 +44,7 @@ func BuildpackBuildImageDestination(app *v1alpha1.App, space *v1alpha1.Space) st
 }
 
 // MakeSource creates a source for the given application.
-func MakeSource(app *v1alpha1.App, space *v1alpha1.Space) (*v1alpha1.Source, error) {
+func MakeSource(app *v1alpha1.App, space *v1alpha1.Space, stackConfig *stacks.Config) (*v1alpha1.Source, error) {
 	source := app.Spec.Source.DeepCopy()
 
 	source.ServiceAccount = space.Spec.Security.BuildServiceAccount
@@ -53,12 +54,25 @@ func MakeSource(app *v1alpha1.App, space *v1alpha1.Space) (*v1alpha1.Source, err
 		// user defined values in buildpackbuild.env take priority from buildpackbuild.env
 		source.BuildpackBuild.Env = append(space.Spec.BuildpackBuild.Env, source.BuildpackBuild.Env...)
 		source.BuildpackBuild.Image = BuildpackBuildImageDestination(app, space)
-		source.BuildpackBuild.BuildpackBuilder = space.Spec.BuildpackBuild.BuilderImage
+		source.BuildpackBuild.BuildpackBuilder = space.EffectiveBuilderImage()
+
+		if err := resolveStack(&source.BuildpackBuild, space, stackConfig); err != nil {
+			return nil, err
+		}
 
 	case source.IsDockerfileBuild():
 		source.Dockerfile.Image = BuildpackBuildImageDestination(app, space)
 	}
 
+	// Carry the build priority over so the Source can be queued correctly if
+	// the space limits concurrent builds.
+	var annotations map[string]string
+	if priority, ok := app.GetAnnotations()[v1alpha1.BuildPriorityAnnotation]; ok {
+		annotations = map[string]string{
+			v1alpha1.BuildPriorityAnnotation: priority,
+		}
+	}
+
 	return &v1alpha1.Source{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      MakeSourceName(app),
@@ -66,8 +80,30 @@ func MakeSource(app *v1alpha1.App, space *v1alpha1.Space) (*v1alpha1.Source, err
 			OwnerReferences: []metav1.OwnerReference{
 				*kmeta.NewControllerRef(app),
 			},
-			Labels: resources.UnionMaps(app.GetLabels(), app.ComponentLabels(buildComponentName)),
+			Labels:      resources.UnionMaps(app.GetLabels(), app.ComponentLabels(buildComponentName)),
+			Annotations: annotations,
 		},
 		Spec: *source,
 	}, nil
 }
+
+// resolveStack replaces the name of a cluster Stack with its build and run
+// images. Apps without a stack use the space's default. Stacks that don't
+// match a cluster Stack are used as the run image directly.
+func resolveStack(build *v1alpha1.SourceSpecBuildpackBuild, space *v1alpha1.Space, stackConfig *stacks.Config) error {
+	stackName := build.Stack
+	if stackName == "" {
+		stackName = space.Spec.BuildpackBuild.DefaultStack
+	}
+
+	stack, ok := stackConfig.Find(stackName)
+	switch {
+	case ok:
+		build.BuildpackBuilder = stack.BuildImage
+		build.Stack = stack.RunImage
+	case build.Stack == "" && stackName != "":
+		return fmt.Errorf("the space's default stack %s doesn't exist", stackName)
+	}
+
+	return nil
+}
@@ -15,10 +15,12 @@
 package resources
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/stacks"
 	"github.com/google/kf/pkg/kf/testutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -37,6 +39,21 @@ func ExampleBuildpackBuildImageDestination() {
 	// Output: gcr.io/my-project/app_myspace_myapp:facade
 }
 
+func ExampleBuildpackBuildImageDestination_clusterDefault() {
+	app := &v1alpha1.App{}
+	app.Name = "myapp"
+	app.Namespace = "myspace"
+	app.Spec.Source.UpdateRequests = 0xfacade
+
+	// The controller records the cluster's registry for spaces without one.
+	space := &v1alpha1.Space{}
+	space.Status.BuildpackBuild.ContainerRegistry = "gcr.io/cluster-project"
+
+	fmt.Println(BuildpackBuildImageDestination(app, space))
+
+	// Output: gcr.io/cluster-project/app_myspace_myapp:facade
+}
+
 func ExampleBuildpackBuildImageDestination_noRegistry() {
 	app := &v1alpha1.App{}
 	app.Name = "myapp"
@@ -115,8 +132,9 @@ func TestMakeSource(t *testing.T) {
 					UpdateRequests: 0xdeadbeef,
 					ServiceAccount: "build-service-account",
 					BuildpackBuild: v1alpha1.SourceSpecBuildpackBuild{
-						Source: "gcr.io/my-source-image:latest",
-						Image:  "gcr.io/dest/app_myspace_mybuildpackapp:deadbeef",
+						Source:           "gcr.io/my-source-image:latest",
+						Image:            "gcr.io/dest/app_myspace_mybuildpackapp:deadbeef",
+						BuildpackBuilder: v1alpha1.DefaultBuilderImage,
 					},
 				},
 			},
@@ -155,6 +173,50 @@ func TestMakeSource(t *testing.T) {
 				},
 			},
 		},
+		"scheduled priority": {
+			app: v1alpha1.App{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mybuildpackapp",
+					Namespace: "myspace",
+					Annotations: map[string]string{
+						v1alpha1.BuildPriorityAnnotation: v1alpha1.BuildPriorityScheduled,
+						"some-other-annotation":          "value",
+					},
+				},
+				Spec: v1alpha1.AppSpec{
+					Source: v1alpha1.SourceSpec{
+						UpdateRequests: 0xfacade,
+						ContainerImage: v1alpha1.SourceSpecContainerImage{
+							Image: "mysql/mysql:v1",
+						},
+					},
+				},
+			},
+			space: space,
+
+			expected: v1alpha1.Source{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mybuildpackapp-facade",
+					Namespace: "myspace",
+					Labels: map[string]string{
+						"app.kubernetes.io/component":  "build",
+						"app.kubernetes.io/managed-by": "kf",
+						"app.kubernetes.io/name":       "mybuildpackapp",
+					},
+					Annotations: map[string]string{
+						v1alpha1.BuildPriorityAnnotation: v1alpha1.BuildPriorityScheduled,
+					},
+					OwnerReferences: appOwnerRef,
+				},
+				Spec: v1alpha1.SourceSpec{
+					UpdateRequests: 0xfacade,
+					ServiceAccount: "build-service-account",
+					ContainerImage: v1alpha1.SourceSpecContainerImage{
+						Image: "mysql/mysql:v1",
+					},
+				},
+			},
+		},
 		"dockerfile": {
 			app: v1alpha1.App{
 				ObjectMeta: appObjectMeta,
@@ -196,7 +258,7 @@ func TestMakeSource(t *testing.T) {
 
 	for tn, tc := range cases {
 		t.Run(tn, func(t *testing.T) {
-			actual, err := MakeSource(&tc.app, &tc.space)
+			actual, err := MakeSource(&tc.app, &tc.space, &stacks.Config{})
 			testutil.AssertNil(t, "MakeSource error", err)
 
 			testutil.AssertEqual(t, "Source", &tc.expected, actual)
@@ -204,6 +266,70 @@ func TestMakeSource(t *testing.T) {
 	}
 }
 
+func TestMakeSource_stacks(t *testing.T) {
+	stackConfig := &stacks.Config{
+		Stacks: []stacks.Stack{
+			{Name: "cflinuxfs3", BuildImage: "gcr.io/builder:cflinuxfs3", RunImage: "cloudfoundry/cflinuxfs3"},
+			{Name: "bionic", BuildImage: "gcr.io/builder:bionic", RunImage: "gcr.io/run:bionic"},
+		},
+	}
+
+	cases := map[string]struct {
+		appStack     string
+		defaultStack string
+
+		wantBuilder string
+		wantStack   string
+		wantErr     error
+	}{
+		"no stacks uses the space builder": {
+			wantBuilder: "gcr.io/space-builder",
+		},
+		"app chooses a stack": {
+			appStack:     "bionic",
+			defaultStack: "cflinuxfs3",
+			wantBuilder:  "gcr.io/builder:bionic",
+			wantStack:    "gcr.io/run:bionic",
+		},
+		"space default stack": {
+			defaultStack: "cflinuxfs3",
+			wantBuilder:  "gcr.io/builder:cflinuxfs3",
+			wantStack:    "cloudfoundry/cflinuxfs3",
+		},
+		"app run image": {
+			appStack:    "gcr.io/my-run-image",
+			wantBuilder: "gcr.io/space-builder",
+			wantStack:   "gcr.io/my-run-image",
+		},
+		"missing default stack": {
+			defaultStack: "missing",
+			wantErr:      errors.New("the space's default stack missing doesn't exist"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			app := &v1alpha1.App{}
+			app.Name = "myapp"
+			app.Spec.Source.BuildpackBuild.Source = "gcr.io/my-source-image:latest"
+			app.Spec.Source.BuildpackBuild.Stack = tc.appStack
+
+			space := &v1alpha1.Space{}
+			space.Spec.BuildpackBuild.BuilderImage = "gcr.io/space-builder"
+			space.Spec.BuildpackBuild.DefaultStack = tc.defaultStack
+
+			actual, err := MakeSource(app, space, stackConfig)
+			if tc.wantErr != nil || err != nil {
+				testutil.AssertErrorsEqual(t, tc.wantErr, err)
+				return
+			}
+
+			testutil.AssertEqual(t, "builder", tc.wantBuilder, actual.Spec.BuildpackBuild.BuildpackBuilder)
+			testutil.AssertEqual(t, "stack", tc.wantStack, actual.Spec.BuildpackBuild.Stack)
+		})
+	}
+}
+
 func boolPtr(b bool) *bool {
 	tmp := &b
 	return tmp
@@ -16,17 +16,30 @@ package reconciler
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"sync"
 
 	kfclientset "github.com/google/kf/pkg/client/clientset/versioned"
 	kfscheme "github.com/google/kf/pkg/client/clientset/versioned/scheme"
 	kfclient "github.com/google/kf/pkg/client/injection/client"
+	kflisters "github.com/google/kf/pkg/client/listers/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/notifications"
+	"github.com/google/kf/pkg/kf/tracing"
 	knativeclientset "github.com/knative/serving/pkg/client/clientset/versioned"
 	knativeclient "github.com/knative/serving/pkg/client/injection/client"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	v1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/record"
+	"knative.dev/pkg/apis"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
 	sharedclientset "knative.dev/pkg/client/clientset/versioned"
 	sharedclient "knative.dev/pkg/client/injection/client"
 	"knative.dev/pkg/configmap"
@@ -36,6 +49,9 @@ import (
 	"knative.dev/pkg/logging/logkey"
 )
 
+// controllerAgentName is the source of Events recorded by the reconcilers.
+const controllerAgentName = "kf-controller"
+
 // Base implements the core controller logic, given a Reconciler.
 type Base struct {
 	// KubeClientSet allows us to talk to the k8s for core APIs
@@ -56,13 +72,47 @@ type Base struct {
 	// NamespaceLister allows us to list Namespaces. We use this to check for
 	// terminating namespaces.
 	NamespaceLister v1listers.NamespaceLister
+
+	// Recorder is an event recorder for recording Event resources to the
+	// Kubernetes API.
+	Recorder record.EventRecorder
+
+	// Notifier posts build and push events to the webhooks configured on
+	// spaces.
+	Notifier notifications.Notifier
 }
 
+// watchTracingOnce ensures the tracing ConfigMap is only watched once even
+// though every controller creates its own Base.
+var watchTracingOnce sync.Once
+
 // NewBase instantiates a new instance of Base implementing
 // the common & boilerplate code between our reconcilers.
 func NewBase(ctx context.Context, cmw configmap.Watcher) *Base {
 	kubeClient := kubeclient.Get(ctx)
 	nsInformer := namespaceinformer.Get(ctx)
+	logger := logging.FromContext(ctx)
+
+	watchTracingOnce.Do(func() {
+		cmw.Watch(tracing.ConfigMapName, tracing.UpdateExporterFromConfigMap("controller", logger))
+	})
+
+	// Create event broadcaster
+	logger.Debug("Creating event broadcaster")
+	eventBroadcaster := record.NewBroadcaster()
+	watches := []watch.Interface{
+		eventBroadcaster.StartLogging(logger.Named("event-broadcaster").Infof),
+		eventBroadcaster.StartRecordingToSink(
+			&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")}),
+	}
+	recorder := eventBroadcaster.NewRecorder(
+		scheme.Scheme, corev1.EventSource{Component: controllerAgentName})
+	go func() {
+		<-ctx.Done()
+		for _, w := range watches {
+			w.Stop()
+		}
+	}()
 
 	base := &Base{
 		KubeClientSet:    kubeClient,
@@ -72,6 +122,8 @@ func NewBase(ctx context.Context, cmw configmap.Watcher) *Base {
 		ConfigMapWatcher: cmw,
 
 		NamespaceLister: nsInformer.Lister(),
+		Recorder:        recorder,
+		Notifier:        notifications.NewNotifier(&http.Client{Timeout: notifications.DefaultTimeout}),
 	}
 
 	return base
@@ -94,6 +146,89 @@ func (base *Base) IsNamespaceTerminating(namespace string) bool {
 	return ns.Status.Phase == corev1.NamespaceTerminating
 }
 
+// RecordStatusChanges records Events for the condition transitions between
+// the before and after statuses of obj so users can see why it isn't ready.
+// A Warning is recorded for every condition that became False and a Normal
+// event is recorded when the Ready or Succeeded condition becomes True.
+func (base *Base) RecordStatusChanges(obj runtime.Object, before, after duckv1beta1.Status) {
+	// The top level condition usually repeats the reason of the condition
+	// that caused it to fail so only record each failure once.
+	recorded := make(map[string]bool)
+
+	for _, cond := range after.GetConditions() {
+		prev := before.GetCondition(cond.Type)
+
+		switch cond.Status {
+		case corev1.ConditionFalse:
+			if prev != nil && prev.IsFalse() && prev.Reason == cond.Reason && prev.Message == cond.Message {
+				continue
+			}
+
+			reason := cond.Reason
+			if reason == "" {
+				reason = fmt.Sprintf("%sFailed", cond.Type)
+			}
+
+			key := reason + "/" + cond.Message
+			if recorded[key] {
+				continue
+			}
+			recorded[key] = true
+
+			base.Recorder.Event(obj, corev1.EventTypeWarning, reason, cond.Message)
+
+		case corev1.ConditionTrue:
+			if cond.Type != apis.ConditionReady && cond.Type != apis.ConditionSucceeded {
+				continue
+			}
+
+			if prev != nil && prev.IsTrue() {
+				continue
+			}
+
+			base.Recorder.Eventf(obj, corev1.EventTypeNormal, string(cond.Type), "%s condition is now True", cond.Type)
+		}
+	}
+}
+
+// SendNotification posts the payload to the webhooks of its space in the
+// background so slow webhooks don't hold up reconciliation. Failures are only
+// logged, a missed notification shouldn't fail the reconcile.
+func (base *Base) SendNotification(ctx context.Context, spaces kflisters.SpaceLister, payload *notifications.Payload) {
+	if payload == nil {
+		return
+	}
+
+	logger := logging.FromContext(ctx)
+
+	space, err := spaces.Get(payload.Space)
+	switch {
+	case apierrs.IsNotFound(err):
+		return
+	case err != nil:
+		logger.Warnw("Failed to get Space for notification", zap.Error(err))
+		return
+	case len(space.Spec.Notifications) == 0:
+		return
+	}
+
+	go func() {
+		if err := base.Notifier.Notify(context.Background(), space, *payload); err != nil {
+			logger.Warnw("Failed to send notification", zap.String("event", payload.Event), zap.Error(err))
+		}
+	}()
+}
+
+// RecordReconcileError records a Warning Event on obj if reconciling it
+// failed.
+func (base *Base) RecordReconcileError(obj runtime.Object, err error) {
+	if err == nil {
+		return
+	}
+
+	base.Recorder.Eventf(obj, corev1.EventTypeWarning, "ReconcileFailed", "Failed to reconcile: %s", err)
+}
+
 func init() {
 	// Add serving types to the default Kubernetes Scheme so Events can be
 	// logged for serving types.
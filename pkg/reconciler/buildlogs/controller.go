@@ -0,0 +1,56 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildlogs
+
+import (
+	"context"
+
+	sourceinformer "github.com/google/kf/pkg/client/injection/informers/kf/v1alpha1/source"
+	spaceinformer "github.com/google/kf/pkg/client/injection/informers/kf/v1alpha1/space"
+	"github.com/google/kf/pkg/kf/buildlogs"
+	"github.com/google/kf/pkg/kf/sources"
+	"github.com/google/kf/pkg/reconciler"
+	"github.com/google/kf/third_party/knative-build/pkg/logs"
+	"knative.dev/pkg/configmap"
+	"knative.dev/pkg/controller"
+)
+
+// NewController creates a new controller that archives the logs of completed
+// builds to the bucket configured on their Space.
+func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl {
+	logger := reconciler.NewControllerLogger(ctx, "buildlogs.kf.dev")
+
+	// Get informers off context
+	sourceInformer := sourceinformer.Get(ctx)
+	spaceInformer := spaceinformer.Get(ctx)
+
+	// Create reconciler
+	c := &Reconciler{
+		Base:         reconciler.NewBase(ctx, cmw),
+		sourceLister: sourceInformer.Lister(),
+		spaceLister:  spaceInformer.Lister(),
+		buildTailer:  sources.BuildTailerFunc(logs.Tail),
+		archive:      buildlogs.NewGCSArchive(),
+	}
+
+	impl := controller.NewImpl(c, logger, "BuildLogs")
+
+	logger.Info("Setting up event handlers")
+
+	// Sources are archived once their build completes.
+	sourceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
+
+	return impl
+}
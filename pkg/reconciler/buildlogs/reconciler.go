@@ -0,0 +1,115 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package buildlogs
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kflisters "github.com/google/kf/pkg/client/listers/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/buildlogs"
+	"github.com/google/kf/pkg/kf/sources"
+	"github.com/google/kf/pkg/kf/tracing"
+	"github.com/google/kf/pkg/reconciler"
+	"github.com/google/kf/pkg/reconciler/buildlogs/resources"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+// tailTimeout is the longest the logs of a completed build are read for.
+const tailTimeout = 5 * time.Minute
+
+// Reconciler archives the build logs of Sources.
+type Reconciler struct {
+	*reconciler.Base
+
+	// listers index properties about resources
+	sourceLister kflisters.SourceLister
+	spaceLister  kflisters.SpaceLister
+
+	buildTailer sources.BuildTailer
+	archive     buildlogs.Archive
+}
+
+// Check that our Reconciler implements controller.Reconciler
+var _ controller.Reconciler = (*Reconciler)(nil)
+
+// Reconcile is called by Kubernetes.
+func (r *Reconciler) Reconcile(ctx context.Context, key string) (err error) {
+	ctx, span := tracing.StartReconcileSpan(ctx, "BuildLogs", key)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(ctx).With("namespace", namespace)
+
+	source, err := r.sourceLister.Sources(namespace).Get(name)
+	switch {
+	case errors.IsNotFound(err):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	space, err := r.spaceLister.Get(namespace)
+	switch {
+	case errors.IsNotFound(err):
+		space = nil
+	case err != nil:
+		return err
+	}
+
+	archiveURL := resources.ArchiveURL(source, space)
+	if archiveURL == "" {
+		return nil
+	}
+
+	tailCtx, cancel := context.WithTimeout(ctx, tailTimeout)
+	defer cancel()
+
+	logs := &bytes.Buffer{}
+	if err := r.buildTailer.Tail(tailCtx, logs, source.Status.BuildName, namespace); err != nil {
+		// The logs of a completed build can only fail to be read if the build or
+		// its pod is gone so retrying won't help.
+		logger.Warnw("Failed to read build logs", zap.Error(err))
+		r.Recorder.Eventf(source, corev1.EventTypeWarning, "BuildLogArchiveFailed", "Couldn't read the logs of build %q: %v", source.Status.BuildName, err)
+		return nil
+	}
+
+	if err := r.archive.Upload(ctx, archiveURL, logs); err != nil {
+		return err
+	}
+
+	logger.Infof("archived logs of source %q to %s", name, archiveURL)
+
+	// Don't modify the informers copy.
+	toUpdate := source.DeepCopy()
+	if toUpdate.Annotations == nil {
+		toUpdate.Annotations = make(map[string]string)
+	}
+	toUpdate.Annotations[v1alpha1.BuildLogArchiveAnnotation] = archiveURL
+
+	_, err = r.KfClientSet.KfV1alpha1().Sources(namespace).Update(toUpdate)
+	return err
+}
@@ -0,0 +1,40 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/buildlogs"
+)
+
+// ArchiveURL returns the URL the logs of the Source's build should be archived
+// to, or blank if they shouldn't be archived. Logs are archived once per
+// Source, after its build completes, if the Space has a log bucket.
+func ArchiveURL(source *v1alpha1.Source, space *v1alpha1.Space) string {
+	switch {
+	case source.DeletionTimestamp != nil:
+		return ""
+	case source.BuildLogArchive() != "":
+		return ""
+	case source.Status.BuildName == "":
+		return ""
+	case !v1alpha1.IsStatusFinal(source.Status.Status):
+		return ""
+	case space == nil || space.Spec.BuildpackBuild.LogBucket == "":
+		return ""
+	}
+
+	return buildlogs.ObjectURL(space.Spec.BuildpackBuild.LogBucket, source.Namespace, source.Name)
+}
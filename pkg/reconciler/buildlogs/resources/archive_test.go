@@ -0,0 +1,100 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	duckv1beta1 "knative.dev/pkg/apis/duck/v1beta1"
+)
+
+func TestArchiveURL(t *testing.T) {
+	completed := func() *v1alpha1.Source {
+		s := &v1alpha1.Source{}
+		s.Name = "my-source"
+		s.Namespace = "my-space"
+		s.Status.BuildName = "my-build"
+		s.Status.Conditions = duckv1beta1.Conditions{{
+			Type:   v1alpha1.SourceConditionSucceeded,
+			Status: corev1.ConditionFalse,
+		}}
+		return s
+	}
+
+	space := &v1alpha1.Space{}
+	space.Spec.BuildpackBuild.LogBucket = "gs://build-logs"
+
+	cases := map[string]struct {
+		source   func() *v1alpha1.Source
+		space    *v1alpha1.Space
+		expected string
+	}{
+		"completed build": {
+			source:   completed,
+			space:    space,
+			expected: "gs://build-logs/my-space/my-source.log",
+		},
+		"no log bucket": {
+			source: completed,
+			space:  &v1alpha1.Space{},
+		},
+		"no space": {
+			source: completed,
+		},
+		"already archived": {
+			source: func() *v1alpha1.Source {
+				s := completed()
+				s.Annotations = map[string]string{v1alpha1.BuildLogArchiveAnnotation: "gs://old/my-source.log"}
+				return s
+			},
+			space: space,
+		},
+		"build running": {
+			source: func() *v1alpha1.Source {
+				s := completed()
+				s.Status.Conditions = nil
+				return s
+			},
+			space: space,
+		},
+		"build queued": {
+			source: func() *v1alpha1.Source {
+				s := completed()
+				s.Status.BuildName = ""
+				return s
+			},
+			space: space,
+		},
+		"deleted": {
+			source: func() *v1alpha1.Source {
+				s := completed()
+				now := metav1.Now()
+				s.DeletionTimestamp = &now
+				return s
+			},
+			space: space,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			testutil.AssertEqual(t, "archive URL", tc.expected, ArchiveURL(tc.source(), tc.space))
+		})
+	}
+}
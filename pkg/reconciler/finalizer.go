@@ -0,0 +1,70 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+// HasFinalizer returns true if the object has the named finalizer.
+func HasFinalizer(obj metav1.Object, finalizer string) bool {
+	return sets.NewString(obj.GetFinalizers()...).Has(finalizer)
+}
+
+// AddFinalizerPatch creates a merge patch that adds the finalizer to the
+// object. A nil patch is returned if the object already has the finalizer.
+func AddFinalizerPatch(obj metav1.Object, finalizer string) ([]byte, error) {
+	if HasFinalizer(obj, finalizer) {
+		return nil, nil
+	}
+
+	// Copy the finalizers so the informer's cache isn't modified.
+	finalizers := append([]string{}, obj.GetFinalizers()...)
+
+	return finalizerPatch(obj, append(finalizers, finalizer))
+}
+
+// RemoveFinalizerPatch creates a merge patch that removes the finalizer from
+// the object. A nil patch is returned if the object doesn't have the
+// finalizer.
+func RemoveFinalizerPatch(obj metav1.Object, finalizer string) ([]byte, error) {
+	if !HasFinalizer(obj, finalizer) {
+		return nil, nil
+	}
+
+	finalizers := sets.NewString(obj.GetFinalizers()...)
+	finalizers.Delete(finalizer)
+
+	return finalizerPatch(obj, finalizers.List())
+}
+
+// finalizerPatch replaces the finalizers of the object. The resource version
+// is included so the patch fails if the object changed since it was read
+// rather than overwriting another controller's finalizer.
+func finalizerPatch(obj metav1.Object, finalizers []string) ([]byte, error) {
+	if finalizers == nil {
+		finalizers = []string{}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      finalizers,
+			"resourceVersion": obj.GetResourceVersion(),
+		},
+	})
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reconciler
+
+import (
+	"testing"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestFinalizerPatches(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		finalizers []string
+		patch      func(obj metav1.Object, finalizer string) ([]byte, error)
+		want       string
+	}{
+		"add missing": {
+			finalizers: []string{"other.example.com"},
+			patch:      AddFinalizerPatch,
+			want:       `{"metadata":{"finalizers":["other.example.com","apps.kf.dev"],"resourceVersion":"42"}}`,
+		},
+		"add existing": {
+			finalizers: []string{"apps.kf.dev"},
+			patch:      AddFinalizerPatch,
+		},
+		"remove existing": {
+			finalizers: []string{"apps.kf.dev", "other.example.com"},
+			patch:      RemoveFinalizerPatch,
+			want:       `{"metadata":{"finalizers":["other.example.com"],"resourceVersion":"42"}}`,
+		},
+		"remove last": {
+			finalizers: []string{"apps.kf.dev"},
+			patch:      RemoveFinalizerPatch,
+			want:       `{"metadata":{"finalizers":[],"resourceVersion":"42"}}`,
+		},
+		"remove missing": {
+			patch: RemoveFinalizerPatch,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			obj := &metav1.ObjectMeta{
+				ResourceVersion: "42",
+				Finalizers:      tc.finalizers,
+			}
+
+			patch, err := tc.patch(obj, "apps.kf.dev")
+			testutil.AssertNil(t, "err", err)
+			testutil.AssertEqual(t, "patch", tc.want, string(patch))
+		})
+	}
+}
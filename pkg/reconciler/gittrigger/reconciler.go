@@ -0,0 +1,171 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gittrigger
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kflisters "github.com/google/kf/pkg/client/listers/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/tracing"
+	"github.com/google/kf/pkg/reconciler"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	"knative.dev/pkg/controller"
+	"knative.dev/pkg/logging"
+)
+
+// Reconciler reconciles a GitTrigger object with the K8s cluster.
+type Reconciler struct {
+	*reconciler.Base
+
+	// listers index properties about resources
+	gitTriggerLister kflisters.GitTriggerLister
+	appLister        kflisters.AppLister
+}
+
+// Check that our Reconciler implements controller.Reconciler
+var _ controller.Reconciler = (*Reconciler)(nil)
+
+// Reconcile is called by Kubernetes.
+func (r *Reconciler) Reconcile(ctx context.Context, key string) (err error) {
+	ctx, span := tracing.StartReconcileSpan(ctx, "GitTrigger", key)
+	defer func() { tracing.EndSpan(span, err) }()
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.FromContext(ctx).With("namespace", namespace)
+	ctx = logging.WithLogger(ctx, logger)
+
+	original, err := r.gitTriggerLister.GitTriggers(namespace).Get(name)
+	switch {
+	case errors.IsNotFound(err):
+		logger.Errorf("git trigger %q no longer exists\n", name)
+		return nil
+
+	case err != nil:
+		return err
+
+	case original.GetDeletionTimestamp() != nil:
+		return nil
+	}
+
+	if r.IsNamespaceTerminating(namespace) {
+		logger.Errorf("skipping sync for git trigger %q, namespace %q is terminating\n", name, namespace)
+		return nil
+	}
+
+	// Don't modify the informers copy
+	toReconcile := original.DeepCopy()
+
+	// Reconcile this copy of the trigger and then write back any status
+	// updates regardless of whether the reconciliation errored out.
+	reconcileErr := r.ApplyChanges(ctx, toReconcile)
+
+	// Surface failures users can act on as Events on the object.
+	r.RecordStatusChanges(toReconcile, original.Status.Status, toReconcile.Status.Status)
+	r.RecordReconcileError(toReconcile, reconcileErr)
+
+	if equality.Semantic.DeepEqual(original.Status, toReconcile.Status) {
+		// If we didn't change anything then don't call updateStatus.
+		// This is important because the copy we loaded from the informer's
+		// cache may be stale and we don't want to overwrite a prior update
+		// to status with this stale state.
+
+	} else if _, uErr := r.updateStatus(namespace, toReconcile); uErr != nil {
+		logger.Warnw("Failed to update GitTrigger status", zap.Error(uErr))
+		return uErr
+	}
+
+	return reconcileErr
+}
+
+// ApplyChanges rebuilds the App from the latest revision of the branch if it
+// hasn't been already.
+func (r *Reconciler) ApplyChanges(ctx context.Context, trigger *v1alpha1.GitTrigger) error {
+	logger := logging.FromContext(ctx)
+	trigger.Status.InitializeConditions()
+
+	if trigger.Spec.Revision == "" {
+		trigger.Status.MarkWaitingForPush(trigger.Spec.Branch)
+		return nil
+	}
+
+	// Revisions are only applied once so a later kf push isn't overwritten
+	// until the branch is updated again.
+	if trigger.Spec.Revision == trigger.Status.TriggeredRevision {
+		trigger.Status.MarkAppUpdated(trigger.Spec.Revision)
+		return nil
+	}
+
+	actual, err := r.appLister.Apps(trigger.Namespace).Get(trigger.Spec.AppName)
+	switch {
+	case errors.IsNotFound(err):
+		trigger.Status.MarkAppNotFound(trigger.Spec.AppName)
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if actual.Spec.Source.IsContainerBuild() {
+		trigger.Status.MarkAppNotBuildable(actual.Name)
+		return nil
+	}
+
+	logger.Infof("rebuilding App %q from revision %q", actual.Name, trigger.Spec.Revision)
+
+	// Don't modify the informers copy
+	app := actual.DeepCopy()
+	app.Spec.Source.Git = &v1alpha1.SourceSpecGit{
+		URL:      trigger.Spec.Repository,
+		Revision: trigger.Spec.Revision,
+	}
+	app.Spec.Source.UpdateRequests++
+
+	// Nobody is waiting on rebuilds from pushes to the branch so they're
+	// queued behind interactive builds.
+	v1alpha1.SetBuildPriority(app, v1alpha1.BuildPriorityScheduled)
+
+	if _, err := r.KfClientSet.KfV1alpha1().Apps(app.Namespace).Update(app); err != nil {
+		return err
+	}
+
+	trigger.Status.MarkAppUpdated(trigger.Spec.Revision)
+	return nil
+}
+
+func (r *Reconciler) updateStatus(namespace string, desired *v1alpha1.GitTrigger) (*v1alpha1.GitTrigger, error) {
+	actual, err := r.gitTriggerLister.GitTriggers(namespace).Get(desired.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// If there's nothing to update, just return.
+	if reflect.DeepEqual(actual.Status, desired.Status) {
+		return actual, nil
+	}
+
+	// Don't modify the informers copy.
+	existing := actual.DeepCopy()
+	existing.Status = desired.Status
+
+	return r.KfClientSet.KfV1alpha1().GitTriggers(namespace).UpdateStatus(existing)
+}
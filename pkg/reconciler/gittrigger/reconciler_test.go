@@ -0,0 +1,64 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gittrigger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	kffake "github.com/google/kf/pkg/client/clientset/versioned/fake"
+	kflisters "github.com/google/kf/pkg/client/listers/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/google/kf/pkg/reconciler"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestReconciler_ApplyChanges(t *testing.T) {
+	app := &v1alpha1.App{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-app", Namespace: "my-space"},
+	}
+	app.Spec.Source.BuildpackBuild.Source = "gcr.io/my-app-source"
+
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	testutil.AssertNil(t, "err", indexer.Add(app))
+
+	kfClient := kffake.NewSimpleClientset(app)
+	r := &Reconciler{
+		Base:      &reconciler.Base{KfClientSet: kfClient},
+		appLister: kflisters.NewAppLister(indexer),
+	}
+
+	trigger := &v1alpha1.GitTrigger{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-trigger", Namespace: "my-space"},
+		Spec: v1alpha1.GitTriggerSpec{
+			AppName:    "my-app",
+			Repository: "https://github.com/example/my-app",
+			Branch:     "master",
+			Revision:   "abc123",
+		},
+	}
+
+	err := r.ApplyChanges(context.Background(), trigger)
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "triggered revision", "abc123", trigger.Status.TriggeredRevision)
+
+	actual, err := kfClient.KfV1alpha1().Apps("my-space").Get("my-app", metav1.GetOptions{})
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "git", &v1alpha1.SourceSpecGit{URL: "https://github.com/example/my-app", Revision: "abc123"}, actual.Spec.Source.Git)
+	testutil.AssertEqual(t, "update requests", 1, actual.Spec.Source.UpdateRequests)
+	testutil.AssertEqual(t, "build priority", v1alpha1.BuildPriorityScheduled, actual.Annotations[v1alpha1.BuildPriorityAnnotation])
+}
@@ -0,0 +1,225 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaderelection
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"knative.dev/pkg/logging"
+	"knative.dev/pkg/system"
+)
+
+const (
+	// DefaultLeaseDuration is the default time non-leaders wait after the
+	// last renewal before trying to become the leader.
+	DefaultLeaseDuration = 15 * time.Second
+
+	// DefaultRenewDeadline is the default time the leader retries renewing
+	// its lease before giving up leadership.
+	DefaultRenewDeadline = 10 * time.Second
+
+	// DefaultRetryPeriod is the default time between attempts to acquire or
+	// renew the lease.
+	DefaultRetryPeriod = 2 * time.Second
+
+	// DefaultResourceName is the default name of the ConfigMap used as the
+	// lock.
+	DefaultResourceName = "kf-controller"
+)
+
+// Config configures leader election.
+type Config struct {
+	// Enabled runs leader election, if it's false the replica assumes it's the
+	// only one.
+	Enabled bool
+
+	// LeaseDuration is the time non-leaders wait after the last renewal before
+	// trying to become the leader.
+	LeaseDuration time.Duration
+
+	// RenewDeadline is the time the leader retries renewing its lease before
+	// giving up leadership.
+	RenewDeadline time.Duration
+
+	// RetryPeriod is the time between attempts to acquire or renew the lease.
+	RetryPeriod time.Duration
+
+	// ResourceName is the name of the ConfigMap in the system namespace used
+	// as the lock.
+	ResourceName string
+}
+
+// NewDefaultConfig creates a Config with leader election enabled.
+func NewDefaultConfig() *Config {
+	return &Config{
+		Enabled:       true,
+		LeaseDuration: DefaultLeaseDuration,
+		RenewDeadline: DefaultRenewDeadline,
+		RetryPeriod:   DefaultRetryPeriod,
+		ResourceName:  DefaultResourceName,
+	}
+}
+
+// AddFlags registers flags to configure leader election.
+func (c *Config) AddFlags(fs *flag.FlagSet) {
+	fs.BoolVar(
+		&c.Enabled,
+		"leader-elect",
+		c.Enabled,
+		"Elect a leader to run the reconcilers, required when running more than one replica.",
+	)
+
+	fs.DurationVar(
+		&c.LeaseDuration,
+		"leader-elect-lease-duration",
+		c.LeaseDuration,
+		"Time non-leaders wait after the last renewal before trying to become the leader.",
+	)
+
+	fs.DurationVar(
+		&c.RenewDeadline,
+		"leader-elect-renew-deadline",
+		c.RenewDeadline,
+		"Time the leader retries renewing its lease before giving up leadership.",
+	)
+
+	fs.DurationVar(
+		&c.RetryPeriod,
+		"leader-elect-retry-period",
+		c.RetryPeriod,
+		"Time between attempts to acquire or renew the lease.",
+	)
+
+	fs.StringVar(
+		&c.ResourceName,
+		"leader-elect-resource-name",
+		c.ResourceName,
+		"Name of the ConfigMap in the system namespace used as the lock.",
+	)
+}
+
+// Validate checks the durations are usable, it doesn't check them if leader
+// election is disabled.
+func (c *Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	switch {
+	case c.RetryPeriod <= 0:
+		return errors.New("leader-elect-retry-period must be greater than zero")
+	case c.RenewDeadline <= time.Duration(leaderelection.JitterFactor*float64(c.RetryPeriod)):
+		return fmt.Errorf("leader-elect-renew-deadline must be greater than %v times leader-elect-retry-period", leaderelection.JitterFactor)
+	case c.LeaseDuration <= c.RenewDeadline:
+		return errors.New("leader-elect-lease-duration must be greater than leader-elect-renew-deadline")
+	case c.ResourceName == "":
+		return errors.New("leader-elect-resource-name must not be blank")
+	}
+
+	return nil
+}
+
+// Run calls run once this replica is elected the leader. The context passed
+// to run is cancelled if leadership is lost, in which case an error is
+// returned so the process can exit and rejoin the election with fresh state.
+// If leader election is disabled run is called immediately.
+func (c *Config) Run(ctx context.Context, client kubernetes.Interface, run func(context.Context)) error {
+	logger := logging.FromContext(ctx)
+
+	if !c.Enabled {
+		logger.Info("Leader election is disabled, running as the only replica")
+		recordStatus(ctx, true)
+		run(ctx)
+		return nil
+	}
+
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	id, err := identity()
+	if err != nil {
+		return err
+	}
+
+	lock := &resourcelock.ConfigMapLock{
+		ConfigMapMeta: metav1.ObjectMeta{
+			Namespace: system.Namespace(),
+			Name:      c.ResourceName,
+		},
+		Client: client.CoreV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: id,
+		},
+	}
+
+	recordStatus(ctx, false)
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: c.LeaseDuration,
+		RenewDeadline: c.RenewDeadline,
+		RetryPeriod:   c.RetryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				logger.Infof("%s was elected the leader", id)
+				recordStatus(ctx, true)
+				run(leaderCtx)
+			},
+			OnStoppedLeading: func() {
+				logger.Infof("%s stopped leading", id)
+				recordStatus(ctx, false)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != id {
+					logger.Infof("%s is the leader", leader)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("%s is waiting to be elected the leader", id)
+	elector.Run(ctx)
+
+	if ctx.Err() == nil {
+		return errors.New("leader election lost")
+	}
+
+	return nil
+}
+
+// identity uniquely identifies the replica, the hostname is included so it's
+// easy to find the leader's pod.
+func identity() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", fmt.Errorf("couldn't get hostname: %s", err)
+	}
+
+	return hostname + "_" + string(uuid.NewUUID()), nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaderelection
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/kf/testutil"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfig_AddFlags(t *testing.T) {
+	cfg := NewDefaultConfig()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cfg.AddFlags(fs)
+
+	err := fs.Parse([]string{
+		"--leader-elect=false",
+		"--leader-elect-lease-duration=30s",
+		"--leader-elect-renew-deadline=20s",
+		"--leader-elect-retry-period=5s",
+		"--leader-elect-resource-name=my-lock",
+	})
+	testutil.AssertNil(t, "parse error", err)
+
+	testutil.AssertEqual(t, "config", &Config{
+		Enabled:       false,
+		LeaseDuration: 30 * time.Second,
+		RenewDeadline: 20 * time.Second,
+		RetryPeriod:   5 * time.Second,
+		ResourceName:  "my-lock",
+	}, cfg)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cases := map[string]struct {
+		mutate  func(*Config)
+		wantErr error
+	}{
+		"defaults": {
+			mutate: func(*Config) {},
+		},
+		"disabled skips validation": {
+			mutate: func(c *Config) {
+				c.Enabled = false
+				c.LeaseDuration = 0
+			},
+		},
+		"zero retry period": {
+			mutate:  func(c *Config) { c.RetryPeriod = 0 },
+			wantErr: errors.New("leader-elect-retry-period must be greater than zero"),
+		},
+		"renew deadline too short": {
+			mutate:  func(c *Config) { c.RenewDeadline = c.RetryPeriod },
+			wantErr: errors.New("leader-elect-renew-deadline must be greater than 1.2 times leader-elect-retry-period"),
+		},
+		"lease shorter than renew deadline": {
+			mutate:  func(c *Config) { c.LeaseDuration = c.RenewDeadline },
+			wantErr: errors.New("leader-elect-lease-duration must be greater than leader-elect-renew-deadline"),
+		},
+		"blank resource name": {
+			mutate:  func(c *Config) { c.ResourceName = "" },
+			wantErr: errors.New("leader-elect-resource-name must not be blank"),
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			cfg := NewDefaultConfig()
+			tc.mutate(cfg)
+
+			testutil.AssertErrorsEqual(t, tc.wantErr, cfg.Validate())
+		})
+	}
+}
+
+func TestConfig_Run_disabled(t *testing.T) {
+	cfg := NewDefaultConfig()
+	cfg.Enabled = false
+
+	called := false
+	err := cfg.Run(context.Background(), fake.NewSimpleClientset(), func(context.Context) {
+		called = true
+	})
+
+	testutil.AssertNil(t, "run error", err)
+	testutil.AssertEqual(t, "called", true, called)
+}
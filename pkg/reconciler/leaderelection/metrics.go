@@ -0,0 +1,47 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaderelection
+
+import (
+	"context"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+)
+
+var statusM = stats.Int64(
+	"leader_election_status",
+	"Whether the replica is the elected leader, 1 if it is and 0 otherwise",
+	stats.UnitDimensionless,
+)
+
+func init() {
+	if err := view.Register(&view.View{
+		Description: statusM.Description(),
+		Measure:     statusM,
+		Aggregation: view.LastValue(),
+	}); err != nil {
+		panic(err)
+	}
+}
+
+func recordStatus(ctx context.Context, leader bool) {
+	var status int64
+	if leader {
+		status = 1
+	}
+
+	stats.Record(ctx, statusM.M(status))
+}
@@ -22,11 +22,13 @@ import (
 	v1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
 	routeinformer "github.com/google/kf/pkg/client/injection/informers/kf/v1alpha1/route"
 	routeclaiminformer "github.com/google/kf/pkg/client/injection/informers/kf/v1alpha1/routeclaim"
+	spaceinformer "github.com/google/kf/pkg/client/injection/informers/kf/v1alpha1/space"
 	kflisters "github.com/google/kf/pkg/client/listers/kf/v1alpha1"
 	"github.com/google/kf/pkg/reconciler"
 	appresources "github.com/google/kf/pkg/reconciler/app/resources"
 	"go.uber.org/zap"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 	networking "knative.dev/pkg/apis/istio/v1alpha3"
 	virtualserviceinformer "knative.dev/pkg/client/injection/informers/istio/v1alpha3/virtualservice"
@@ -42,12 +44,14 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 	vsInformer := virtualserviceinformer.Get(ctx)
 	routeInformer := routeinformer.Get(ctx)
 	routeClaimInformer := routeclaiminformer.Get(ctx)
+	spaceInformer := spaceinformer.Get(ctx)
 
 	// Create reconciler
 	c := &Reconciler{
 		Base:                 reconciler.NewBase(ctx, cmw),
 		routeLister:          routeInformer.Lister(),
 		routeClaimLister:     routeClaimInformer.Lister(),
+		spaceLister:          spaceInformer.Lister(),
 		virtualServiceLister: vsInformer.Lister(),
 	}
 
@@ -65,6 +69,11 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		controller.HandleAll(enqueue),
 	)
 
+	// Spaces hold response headers for all their routes.
+	spaceInformer.Informer().AddEventHandler(
+		controller.HandleAll(logError(logger, EnqueueRouteClaimsOfSpace(enqueue, c.routeClaimLister))),
+	)
+
 	vsInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: FilterVSWithNamespace(v1alpha1.KfNamespace),
 		Handler:    controller.HandleAll(logError(logger, EnqueueRoutesOfVirtualService(enqueue, c.routeLister))),
@@ -137,6 +146,33 @@ func FilterVSWithNamespace(namespace string) func(obj interface{}) bool {
 	}
 }
 
+// EnqueueRouteClaimsOfSpace will enqueue a key for each of the RouteClaims in
+// the Space so changes to the Space are reflected in the VirtualServices.
+func EnqueueRouteClaimsOfSpace(
+	enqueue func(interface{}),
+	routeClaimLister kflisters.RouteClaimLister,
+) func(obj interface{}) error {
+	return func(obj interface{}) error {
+		space, ok := obj.(*v1alpha1.Space)
+		if !ok {
+			return nil
+		}
+
+		claims, err := routeClaimLister.
+			RouteClaims(space.Name).
+			List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("failed to list corresponding route claims: %s", err)
+		}
+
+		for _, claim := range claims {
+			enqueue(claim)
+		}
+
+		return nil
+	}
+}
+
 // EnqueueRoutesOfVirtualService will find the corresponding routes for the
 // VirtualService.  It will Enqueue a key for each one. We aren't able to use
 // EnqueueControllerOf (as other components do), because a VirtualService is
@@ -195,3 +195,70 @@ func TestEnqueueRoutesOfVirtualService(t *testing.T) {
 		})
 	}
 }
+
+func TestEnqueueRouteClaimsOfSpace(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		ExpectedErr error
+		Obj         interface{}
+		Setup       func(t *testing.T, f *FakeRouteClaimLister, fn *FakeRouteClaimNamespaceLister)
+		Expected    []string
+	}{
+		"enqueues each route claim": {
+			Obj: &v1alpha1.Space{ObjectMeta: metav1.ObjectMeta{Name: "some-space"}},
+			Setup: func(t *testing.T, f *FakeRouteClaimLister, fn *FakeRouteClaimNamespaceLister) {
+				f.EXPECT().
+					RouteClaims("some-space").
+					Return(fn)
+
+				fn.EXPECT().
+					List(gomock.Any()).
+					Return([]*v1alpha1.RouteClaim{
+						{Spec: v1alpha1.RouteClaimSpec{RouteSpecFields: v1alpha1.RouteSpecFields{Hostname: "host-1"}}},
+						{Spec: v1alpha1.RouteClaimSpec{RouteSpecFields: v1alpha1.RouteSpecFields{Hostname: "host-2"}}},
+					}, nil)
+			},
+			Expected: []string{"host-1", "host-2"},
+		},
+		"handle non Spaces": {
+			Obj: 99,
+		},
+		"route claim lister fails": {
+			Obj:         &v1alpha1.Space{},
+			ExpectedErr: errors.New("failed to list corresponding route claims: some-error"),
+			Setup: func(t *testing.T, f *FakeRouteClaimLister, fn *FakeRouteClaimNamespaceLister) {
+				f.EXPECT().
+					RouteClaims(gomock.Any()).
+					Return(fn)
+
+				fn.EXPECT().
+					List(gomock.Any()).
+					Return(nil, errors.New("some-error"))
+			},
+		},
+	}
+
+	for tn, tc := range testCases {
+		t.Run(tn, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			fakeRouteClaimLister := NewFakeRouteClaimLister(ctrl)
+			fakeRouteClaimNamespaceLister := NewFakeRouteClaimNamespaceLister(ctrl)
+
+			if tc.Setup != nil {
+				tc.Setup(t, fakeRouteClaimLister, fakeRouteClaimNamespaceLister)
+			}
+
+			var enqueued []string
+			enqueue := func(obj interface{}) {
+				enqueued = append(enqueued, obj.(*v1alpha1.RouteClaim).Spec.Hostname)
+			}
+
+			err := EnqueueRouteClaimsOfSpace(enqueue, fakeRouteClaimLister)(tc.Obj)
+			testutil.AssertErrorsEqual(t, tc.ExpectedErr, err)
+			testutil.AssertEqual(t, "enqueued", tc.Expected, enqueued)
+
+			ctrl.Finish()
+		})
+	}
+}
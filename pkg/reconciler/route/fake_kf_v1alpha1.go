@@ -67,6 +67,20 @@ func (mr *FakeKfAlpha1InterfaceMockRecorder) Apps(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apps", reflect.TypeOf((*FakeKfAlpha1Interface)(nil).Apps), arg0)
 }
 
+// GitTriggers mocks base method
+func (m *FakeKfAlpha1Interface) GitTriggers(arg0 string) v1alpha10.GitTriggerInterface {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GitTriggers", arg0)
+	ret0, _ := ret[0].(v1alpha10.GitTriggerInterface)
+	return ret0
+}
+
+// GitTriggers indicates an expected call of GitTriggers
+func (mr *FakeKfAlpha1InterfaceMockRecorder) GitTriggers(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GitTriggers", reflect.TypeOf((*FakeKfAlpha1Interface)(nil).GitTriggers), arg0)
+}
+
 // RESTClient mocks base method
 func (m *FakeKfAlpha1Interface) RESTClient() rest.Interface {
 	m.ctrl.T.Helper()
@@ -14,7 +14,7 @@
 //
 
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/google/kf/pkg/client/listers/kf/v1alpha1 (interfaces: RouteLister,RouteClaimLister,RouteNamespaceLister,RouteClaimNamespaceLister)
+// Source: github.com/google/kf/pkg/client/listers/kf/v1alpha1 (interfaces: RouteLister,RouteClaimLister,RouteNamespaceLister,RouteClaimNamespaceLister,SpaceLister)
 
 // Package route is a generated GoMock package.
 package route
@@ -236,3 +236,56 @@ func (mr *FakeRouteClaimNamespaceListerMockRecorder) List(arg0 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*FakeRouteClaimNamespaceLister)(nil).List), arg0)
 }
+
+// FakeSpaceLister is a mock of SpaceLister interface
+type FakeSpaceLister struct {
+	ctrl     *gomock.Controller
+	recorder *FakeSpaceListerMockRecorder
+}
+
+// FakeSpaceListerMockRecorder is the mock recorder for FakeSpaceLister
+type FakeSpaceListerMockRecorder struct {
+	mock *FakeSpaceLister
+}
+
+// NewFakeSpaceLister creates a new mock instance
+func NewFakeSpaceLister(ctrl *gomock.Controller) *FakeSpaceLister {
+	mock := &FakeSpaceLister{ctrl: ctrl}
+	mock.recorder = &FakeSpaceListerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *FakeSpaceLister) EXPECT() *FakeSpaceListerMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method
+func (m *FakeSpaceLister) Get(arg0 string) (*v1alpha1.Space, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0)
+	ret0, _ := ret[0].(*v1alpha1.Space)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get
+func (mr *FakeSpaceListerMockRecorder) Get(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*FakeSpaceLister)(nil).Get), arg0)
+}
+
+// List mocks base method
+func (m *FakeSpaceLister) List(arg0 labels.Selector) ([]*v1alpha1.Space, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", arg0)
+	ret0, _ := ret[0].([]*v1alpha1.Space)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List
+func (mr *FakeSpaceListerMockRecorder) List(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*FakeSpaceLister)(nil).List), arg0)
+}
@@ -18,14 +18,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"sort"
+	"slices"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	kflisters "github.com/google/kf/pkg/client/listers/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/algorithms"
+	"github.com/google/kf/pkg/kf/tracing"
 	"github.com/google/kf/pkg/reconciler"
 	appresources "github.com/google/kf/pkg/reconciler/app/resources"
 	"github.com/google/kf/pkg/reconciler/route/resources"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -43,6 +45,7 @@ type Reconciler struct {
 	// listers index properties about resources
 	routeLister          kflisters.RouteLister
 	routeClaimLister     kflisters.RouteClaimLister
+	spaceLister          kflisters.SpaceLister
 	virtualServiceLister istiolisters.VirtualServiceLister
 }
 
@@ -50,7 +53,10 @@ type Reconciler struct {
 var _ controller.Reconciler = (*Reconciler)(nil)
 
 // Reconcile is called by Kubernetes.
-func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+func (r *Reconciler) Reconcile(ctx context.Context, key string) (err error) {
+	ctx, span := tracing.StartReconcileSpan(ctx, "Route", key)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	// Key is a JSON marshalled namespacedRouteSpecFields
 	var route namespacedRouteSpecFields
 	if err := json.Unmarshal([]byte(key), &route); err != nil {
@@ -132,7 +138,17 @@ func (r *Reconciler) ApplyChanges(
 		return err
 	}
 
-	desired, err := resources.MakeVirtualService(claims, routes)
+	// Spaces are optional, routes are still served without the space's
+	// response headers if it's missing.
+	space, err := r.spaceLister.Get(namespace)
+	switch {
+	case errors.IsNotFound(err):
+		space = nil
+	case err != nil:
+		return err
+	}
+
+	desired, err := resources.MakeVirtualService(claims, routes, space)
 	if err != nil {
 		return err
 	}
@@ -154,7 +170,11 @@ func (r *Reconciler) ApplyChanges(
 		return err
 	} else if actual.GetDeletionTimestamp() != nil {
 		return nil
-	} else if actual, err = r.update(
+	}
+
+	r.recordDomainConflicts(namespace, fields, claims, actual)
+
+	if actual, err = r.update(
 		ctx,
 		desired,
 		actual,
@@ -165,6 +185,32 @@ func (r *Reconciler) ApplyChanges(
 	return nil
 }
 
+// recordDomainConflicts records a Warning Event on the claims if the
+// VirtualService for the route was created for a different space. The
+// routes are still merged, so traffic for the host is shared between spaces.
+func (r *Reconciler) recordDomainConflicts(
+	namespace string,
+	fields v1alpha1.RouteSpecFields,
+	claims []*v1alpha1.RouteClaim,
+	actual *networking.VirtualService,
+) {
+	owner := actual.GetAnnotations()["space"]
+	if owner == "" || owner == namespace {
+		return
+	}
+
+	for _, claim := range claims {
+		r.Recorder.Eventf(
+			claim,
+			corev1.EventTypeWarning,
+			"DomainConflict",
+			"Route %s is also claimed by space %q, traffic is shared between both spaces",
+			fields,
+			owner,
+		)
+	}
+}
+
 func (r *Reconciler) update(
 	ctx context.Context,
 	desired *networking.VirtualService,
@@ -191,17 +237,19 @@ func (r *Reconciler) update(
 
 	// Merge new OwnerReferences and HTTPRoutes
 	existing.OwnerReferences = algorithms.Merge(
-		v1alpha1.OwnerReferences(existing.OwnerReferences),
-		v1alpha1.OwnerReferences(desired.OwnerReferences),
-	).(v1alpha1.OwnerReferences)
+		existing.OwnerReferences,
+		desired.OwnerReferences,
+		v1alpha1.CompareOwnerReferences,
+	)
 
 	existing.Spec.HTTP = algorithms.Merge(
-		v1alpha1.HTTPRoutes(existing.Spec.HTTP),
-		v1alpha1.HTTPRoutes(desired.Spec.HTTP),
-	).(v1alpha1.HTTPRoutes)
+		existing.Spec.HTTP,
+		desired.Spec.HTTP,
+		v1alpha1.CompareHTTPRoutes,
+	)
 
-	// Sort by reverse to defer to the longest matchers.
-	sort.Sort(sort.Reverse(v1alpha1.HTTPRoutes(existing.Spec.HTTP)))
+	// Sort to defer to the longest matchers, then those with headers.
+	slices.SortStableFunc(existing.Spec.HTTP, v1alpha1.CompareHTTPRoutePrecedence)
 
 	return r.SharedClientSet.
 		Networking().
@@ -30,9 +30,10 @@ import (
 	appresources "github.com/google/kf/pkg/reconciler/app/resources"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 )
 
-//go:generate mockgen --package=route --copyright_file ../../kf/internal/tools/option-builder/LICENSE_HEADER --destination=fake_listers.go --mock_names=RouteLister=FakeRouteLister,RouteNamespaceLister=FakeRouteNamespaceLister,RouteClaimLister=FakeRouteClaimLister,RouteClaimNamespaceLister=FakeRouteClaimNamespaceLister github.com/google/kf/pkg/client/listers/kf/v1alpha1 RouteLister,RouteClaimLister,RouteNamespaceLister,RouteClaimNamespaceLister
+//go:generate mockgen --package=route --copyright_file ../../kf/internal/tools/option-builder/LICENSE_HEADER --destination=fake_listers.go --mock_names=RouteLister=FakeRouteLister,RouteNamespaceLister=FakeRouteNamespaceLister,RouteClaimLister=FakeRouteClaimLister,RouteClaimNamespaceLister=FakeRouteClaimNamespaceLister,SpaceLister=FakeSpaceLister github.com/google/kf/pkg/client/listers/kf/v1alpha1 RouteLister,RouteClaimLister,RouteNamespaceLister,RouteClaimNamespaceLister,SpaceLister
 //go:generate mockgen --package=route --copyright_file ../../kf/internal/tools/option-builder/LICENSE_HEADER --destination=fake_corev1_listers.go --mock_names=NamespaceLister=FakeNamespaceLister k8s.io/client-go/listers/core/v1 NamespaceLister
 //go:generate mockgen --package=route --copyright_file ../../kf/internal/tools/option-builder/LICENSE_HEADER --destination=fake_shared_client.go --mock_names=Interface=FakeSharedClient knative.dev/pkg/client/clientset/versioned Interface
 //go:generate mockgen --package=route --copyright_file ../../kf/internal/tools/option-builder/LICENSE_HEADER --destination=fake_networking.go --mock_names=NetworkingV1alpha3Interface=FakeNetworking,VirtualServiceInterface=FakeVirtualServiceInterface knative.dev/pkg/client/clientset/versioned/typed/istio/v1alpha3 NetworkingV1alpha3Interface,VirtualServiceInterface
@@ -83,10 +84,12 @@ func TestReconciler_Reconcile_ApplyChanges(t *testing.T) {
 		frnl  *FakeRouteNamespaceLister
 		fvsl  *FakeVirtualServiceLister
 		fvsnl *FakeVirtualServiceNamespaceLister
+		fsl   *FakeSpaceLister
 	}
 
 	testCases := map[string]struct {
 		ExpectedErr     error
+		ExpectedEvents  []string
 		Setup           func(t *testing.T, f fakes)
 		RouteSpecFields v1alpha1.RouteSpecFields
 		Namespace       string
@@ -263,6 +266,28 @@ func TestReconciler_Reconcile_ApplyChanges(t *testing.T) {
 					Return(nil, errors.New("some-error"))
 			},
 		},
+		"getting Space fails": {
+			ExpectedErr: errors.New("some-error"),
+			Setup: func(t *testing.T, f fakes) {
+				f.frcnl.EXPECT().
+					List(gomock.Any()).
+					Return([]*v1alpha1.RouteClaim{
+						{},
+					}, nil)
+
+				f.frl.EXPECT().
+					Routes(gomock.Any()).
+					Return(f.frnl)
+
+				f.frnl.EXPECT().
+					List(gomock.Any()).
+					Return(nil, nil)
+
+				f.fsl.EXPECT().
+					Get(gomock.Any()).
+					Return(nil, errors.New("some-error"))
+			},
+		},
 		"making VirtualServices fails": {
 			ExpectedErr: errors.New(`failed to convert path to regexp: mux: unbalanced braces in "/}invalid{"`),
 			Setup: func(t *testing.T, f fakes) {
@@ -374,6 +399,56 @@ func TestReconciler_Reconcile_ApplyChanges(t *testing.T) {
 					Return(nil, nil)
 			},
 		},
+		"VirtualServices is not found, creating VirtualService with Space headers": {
+			Namespace: "some-namespace",
+			Setup: func(t *testing.T, f fakes) {
+				f.frcnl.EXPECT().
+					List(gomock.Any()).
+					Return([]*v1alpha1.RouteClaim{
+						{Spec: v1alpha1.RouteClaimSpec{RouteSpecFields: v1alpha1.RouteSpecFields{}}},
+					}, nil)
+
+				f.frl.EXPECT().
+					Routes(gomock.Any()).
+					Return(f.frnl)
+
+				f.frnl.EXPECT().
+					List(gomock.Any()).
+					Return(nil, nil)
+
+				f.fsl.EXPECT().
+					Get("some-namespace").
+					Return(&v1alpha1.Space{
+						Spec: v1alpha1.SpaceSpec{
+							Execution: v1alpha1.SpaceSpecExecution{
+								ResponseHeaders: map[string]string{"X-Frame-Options": "DENY"},
+							},
+						},
+					}, nil)
+
+				f.fvsl.EXPECT().
+					VirtualServices(gomock.Any()).
+					Return(f.fvsnl)
+
+				f.fvsnl.EXPECT().
+					Get(gomock.Any()).
+					Return(nil, apierrors.NewNotFound(v1alpha3.Resource("VirtualService"), "VirtualService"))
+
+				f.fn.EXPECT().
+					VirtualServices(v1alpha1.KfNamespace).
+					Return(f.fvsi)
+
+				f.fvsi.EXPECT().
+					Create(gomock.Any()).
+					DoAndReturn(func(vs *v1alpha3.VirtualService) (*v1alpha3.VirtualService, error) {
+						for _, h := range vs.Spec.HTTP {
+							testutil.AssertEqual(t, "response headers", map[string]string{"X-Frame-Options": "DENY"}, h.Headers.Response.Set)
+						}
+
+						return vs, nil
+					})
+			},
+		},
 		"VirtualServices is being deleted": {
 			Setup: func(t *testing.T, f fakes) {
 				f.frcnl.EXPECT().
@@ -478,6 +553,48 @@ func TestReconciler_Reconcile_ApplyChanges(t *testing.T) {
 					})
 			},
 		},
+		"VirtualService owned by another space": {
+			Namespace:      "some-namespace",
+			ExpectedEvents: []string{"Warning DomainConflict", `space "other-namespace"`},
+			RouteSpecFields: v1alpha1.RouteSpecFields{
+				Hostname: "some-host",
+				Domain:   "example.com",
+			},
+			Setup: func(t *testing.T, f fakes) {
+				f.frcnl.EXPECT().
+					List(gomock.Any()).
+					Return([]*v1alpha1.RouteClaim{
+						{Spec: v1alpha1.RouteClaimSpec{RouteSpecFields: v1alpha1.RouteSpecFields{}}},
+					}, nil)
+
+				f.frl.EXPECT().
+					Routes(gomock.Any()).
+					Return(f.frnl)
+
+				f.frnl.EXPECT().
+					List(gomock.Any()).
+					Return(nil, nil)
+
+				f.fvsl.EXPECT().
+					VirtualServices(gomock.Any()).
+					Return(f.fvsnl)
+
+				f.fvsnl.EXPECT().
+					Get(gomock.Any()).
+					Return(&v1alpha3.VirtualService{
+						ObjectMeta: metav1.ObjectMeta{
+							Annotations: map[string]string{"space": "other-namespace"},
+						},
+					}, nil)
+
+				f.fn.EXPECT().
+					VirtualServices(gomock.Any()).
+					Return(f.fvsi)
+
+				f.fvsi.EXPECT().
+					Update(gomock.Any())
+			},
+		},
 	}
 
 	for tn, tc := range testCases {
@@ -496,6 +613,7 @@ func TestReconciler_Reconcile_ApplyChanges(t *testing.T) {
 			fakeRouteNamespaceLister := NewFakeRouteNamespaceLister(ctrl)
 			fakeVirtualServiceLister := NewFakeVirtualServiceLister(ctrl)
 			fakeVirtualServiceNamespaceLister := NewFakeVirtualServiceNamespaceLister(ctrl)
+			fakeSpaceLister := NewFakeSpaceLister(ctrl)
 
 			fakeSharedClient.EXPECT().
 				Networking().
@@ -517,16 +635,27 @@ func TestReconciler_Reconcile_ApplyChanges(t *testing.T) {
 					frnl:  fakeRouteNamespaceLister,
 					fvsl:  fakeVirtualServiceLister,
 					fvsnl: fakeVirtualServiceNamespaceLister,
+					fsl:   fakeSpaceLister,
 				})
 			}
 
+			// Test cases that don't care about the Space get a missing one.
+			fakeSpaceLister.EXPECT().
+				Get(gomock.Any()).
+				Return(nil, apierrors.NewNotFound(v1alpha1.Resource("Space"), "Space")).
+				AnyTimes()
+
+			fakeRecorder := record.NewFakeRecorder(10)
+
 			r := &Reconciler{
 				Base: &reconciler.Base{
 					SharedClientSet: fakeSharedClient,
 					KfClientSet:     fakeKfInterface,
+					Recorder:        fakeRecorder,
 				},
 				routeClaimLister:     fakeRouteClaimLister,
 				routeLister:          fakeRouteLister,
+				spaceLister:          fakeSpaceLister,
 				virtualServiceLister: fakeVirtualServiceLister,
 			}
 
@@ -537,6 +666,15 @@ func TestReconciler_Reconcile_ApplyChanges(t *testing.T) {
 			)
 
 			testutil.AssertErrorsEqual(t, tc.ExpectedErr, err)
+
+			if len(tc.ExpectedEvents) > 0 {
+				select {
+				case event := <-fakeRecorder.Events:
+					testutil.AssertContainsAll(t, event, tc.ExpectedEvents)
+				default:
+					t.Fatal("expected an event to be recorded")
+				}
+			}
 		})
 	}
 }
@@ -19,9 +19,11 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strings"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/algorithms"
+	"github.com/google/kf/pkg/maintenance"
 	"github.com/knative/serving/pkg/network"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	istio "knative.dev/pkg/apis/istio/common/v1alpha1"
@@ -34,6 +36,13 @@ const (
 	GatewayHost           = "istio-ingressgateway.istio-system.svc.cluster.local"
 )
 
+// SpaceGateway returns the fully qualified name of the Gateway serving HTTPS
+// and redirects for the space's domains. The Gateway only exists if one of the
+// space's domains needs it, binding to a missing Gateway is a no-op.
+func SpaceGateway(space string) string {
+	return fmt.Sprintf("%s.%s.svc.cluster.local", v1alpha1.SpaceGatewayName(space), v1alpha1.KfNamespace)
+}
+
 // MakeVirtualServiceLabels creates Labels that can be used to tie a
 // VirtualService to a Route.
 func MakeVirtualServiceLabels(spec v1alpha1.RouteSpecFields) map[string]string {
@@ -45,8 +54,10 @@ func MakeVirtualServiceLabels(spec v1alpha1.RouteSpecFields) map[string]string {
 	}
 }
 
-// MakeVirtualService creates a VirtualService from a Route object.
-func MakeVirtualService(claims []*v1alpha1.RouteClaim, routes []*v1alpha1.Route) (*networking.VirtualService, error) {
+// MakeVirtualService creates a VirtualService from a Route object. The space
+// the routes belong to is optional, if set its response headers are added to
+// all responses.
+func MakeVirtualService(claims []*v1alpha1.RouteClaim, routes []*v1alpha1.Route, space *v1alpha1.Space) (*networking.VirtualService, error) {
 	if len(claims) == 0 {
 		return nil, errors.New("claims must not be empty")
 	}
@@ -70,39 +81,67 @@ func MakeVirtualService(claims []*v1alpha1.RouteClaim, routes []*v1alpha1.Route)
 	// associated), they will replace the claims.
 
 	for _, route := range claims {
-		urlPath := route.Spec.RouteSpecFields.Path
+		// Claims only reserve the path.
+		fields := v1alpha1.RouteSpecFields{
+			Path: route.Spec.RouteSpecFields.Path,
+		}
 
-		httpRoute, err := buildHTTPRoute(hostDomain, namespace, urlPath, nil)
+		httpRoute, err := buildHTTPRoute(hostDomain, namespace, fields, nil)
 		if err != nil {
 			return nil, err
 		}
 
-		httpRoutes = algorithms.Merge(
-			v1alpha1.HTTPRoutes(httpRoutes),
-			v1alpha1.HTTPRoutes(httpRoute),
-		).(v1alpha1.HTTPRoutes)
+		httpRoutes = algorithms.Merge(httpRoutes, httpRoute, v1alpha1.CompareHTTPRoutes)
 	}
 
 	for _, route := range routes {
 		var appNames []string
-		urlPath := route.Spec.RouteSpecFields.Path
 
 		// AppNames
 		if route.Spec.AppName != "" {
 			appNames = append(appNames, route.Spec.AppName)
 		}
 
-		httpRoute, err := buildHTTPRoute(hostDomain, namespace, urlPath, appNames)
+		httpRoute, err := buildHTTPRoute(hostDomain, namespace, route.Spec.RouteSpecFields, appNames)
+		if err != nil {
+			return nil, err
+		}
+
+		httpRoutes = algorithms.Merge(httpRoutes, httpRoute, v1alpha1.CompareHTTPRoutes)
+	}
+
+	// Claims under maintenance are merged last so they replace any Apps
+	// serving the same path.
+	for _, claim := range claims {
+		if claim.Spec.Maintenance == nil {
+			continue
+		}
+
+		httpRoute, err := buildMaintenanceHTTPRoute(claim.Spec.RouteSpecFields.Path, claim.Spec.Maintenance)
 		if err != nil {
 			return nil, err
 		}
 
-		httpRoutes = algorithms.Merge(
-			v1alpha1.HTTPRoutes(httpRoutes),
-			v1alpha1.HTTPRoutes(httpRoute),
-		).(v1alpha1.HTTPRoutes)
+		// Routes matching on headers would otherwise still reach their Apps.
+		httpRoutes = removeHTTPRoutesForURIs(httpRoutes, httpRoute[0].Match)
+		httpRoutes = algorithms.Merge(httpRoutes, httpRoute, v1alpha1.CompareHTTPRoutes)
+	}
+
+	for _, claim := range claims {
+		if claim.Spec.CORSPolicy == nil {
+			continue
+		}
+
+		httpRoutes = setCORSPolicy(httpRoutes, claim.Spec.RouteSpecFields.Path, claim.Spec.CORSPolicy)
+	}
+
+	var spaceHeaders map[string]string
+	if space != nil {
+		spaceHeaders = space.Spec.Execution.ResponseHeaders
 	}
 
+	httpRoutes = setResponseHeaders(httpRoutes, claims, spaceHeaders)
+
 	return &networking.VirtualService{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "networking.istio.io/v1alpha3",
@@ -122,14 +161,14 @@ func MakeVirtualService(claims []*v1alpha1.RouteClaim, routes []*v1alpha1.Route)
 			},
 		},
 		Spec: networking.VirtualServiceSpec{
-			Gateways: []string{KnativeIngressGateway},
+			Gateways: []string{KnativeIngressGateway, SpaceGateway(namespace)},
 			Hosts:    []string{hostDomain},
 			HTTP:     httpRoutes,
 		},
 	}, nil
 }
 
-func buildHTTPRoute(hostDomain, namespace, urlPath string, appNames []string) ([]networking.HTTPRoute, error) {
+func buildPathMatchers(urlPath string, headers map[string]string) ([]networking.HTTPMatchRequest, error) {
 	var pathMatchers []networking.HTTPMatchRequest
 	urlPath = path.Join("/", urlPath, "/")
 	regexpPath, err := v1alpha1.BuildPathRegexp(urlPath)
@@ -142,35 +181,252 @@ func buildHTTPRoute(hostDomain, namespace, urlPath string, appNames []string) ([
 			URI: &istio.StringMatch{
 				Regex: regexpPath,
 			},
+			Headers: buildHeaderMatchers(headers),
 		})
 	}
 
-	var httpRoutes []networking.HTTPRoute
+	return pathMatchers, nil
+}
 
-	for _, appName := range appNames {
-		httpRoutes = append(httpRoutes, networking.HTTPRoute{
+// buildHeaderMatchers converts headers into exact matches. Istio requires
+// header names to be lowercase.
+func buildHeaderMatchers(headers map[string]string) map[string]istio.StringMatch {
+	if len(headers) == 0 {
+		return nil
+	}
+
+	matchers := make(map[string]istio.StringMatch, len(headers))
+	for name, value := range headers {
+		matchers[strings.ToLower(name)] = istio.StringMatch{Exact: value}
+	}
+
+	return matchers
+}
+
+// removeHTTPRoutesForURIs returns the HTTP routes that don't match on the
+// same path as matchers, regardless of any header matches or path rewrites.
+func removeHTTPRoutesForURIs(httpRoutes []networking.HTTPRoute, matchers []networking.HTTPMatchRequest) []networking.HTTPRoute {
+	matchedPath := v1alpha1.HTTPRoutePath(networking.HTTPRoute{Match: matchers})
+
+	var out []networking.HTTPRoute
+	for _, httpRoute := range httpRoutes {
+		if v1alpha1.HTTPRoutePath(httpRoute) == matchedPath {
+			continue
+		}
+
+		out = append(out, httpRoute)
+	}
+
+	return out
+}
+
+// setCORSPolicy sets the CORS policy on all the HTTP routes for the path,
+// including those matching on headers or rewriting the path.
+func setCORSPolicy(httpRoutes []networking.HTTPRoute, urlPath string, policy *v1alpha1.RouteCORSPolicy) []networking.HTTPRoute {
+	// Match the format of v1alpha1.HTTPRoutePath.
+	matchedPath := strings.TrimSuffix(path.Join("/", urlPath), "/")
+
+	corsPolicy := &networking.CorsPolicy{
+		AllowOrigin:      policy.AllowOrigins,
+		AllowMethods:     policy.AllowMethods,
+		AllowHeaders:     policy.AllowHeaders,
+		ExposeHeaders:    policy.ExposeHeaders,
+		AllowCredentials: policy.AllowCredentials,
+	}
+
+	if policy.MaxAgeSeconds > 0 {
+		corsPolicy.MaxAge = fmt.Sprintf("%ds", policy.MaxAgeSeconds)
+	}
+
+	out := make([]networking.HTTPRoute, len(httpRoutes))
+	for i, httpRoute := range httpRoutes {
+		if v1alpha1.HTTPRoutePath(httpRoute) == matchedPath {
+			httpRoute.CorsPolicy = corsPolicy.DeepCopy()
+		}
+
+		out[i] = httpRoute
+	}
+
+	return out
+}
+
+// buildMaintenanceHTTPRoute sends all traffic for the path to the maintenance
+// server, passing along the page to serve.
+func buildMaintenanceHTTPRoute(urlPath string, m *v1alpha1.RouteMaintenance) ([]networking.HTTPRoute, error) {
+	pathMatchers, err := buildPathMatchers(urlPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return []networking.HTTPRoute{
+		{
 			Match: pathMatchers,
-			Route: buildRouteDestination(),
-			Rewrite: &networking.HTTPRewrite{
-				Authority: network.GetServiceHostname(appName, namespace),
+			Route: []networking.HTTPRouteDestination{
+				{
+					Destination: networking.Destination{
+						Host: maintenance.Host(),
+					},
+					Weight: 100,
+				},
 			},
 			Headers: &networking.Headers{
 				Request: &networking.HeaderOperations{
-					Add: map[string]string{
-						// Set forwarding headers so the app gets the real hostname it's serving
-						// at rather than the internal one:
-						// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Forwarded1
-						"X-Forwarded-Host": hostDomain,
-						"Forwarded":        fmt.Sprintf("host=%s", hostDomain),
+					Set: map[string]string{
+						maintenance.PageHeader: maintenance.EncodePage(m.Page),
 					},
 				},
 			},
+		},
+	}, nil
+}
+
+// setResponseHeaders sets the space's response headers on all the HTTP
+// routes and each claim's response headers on the HTTP routes for its path.
+// Headers on claims replace space headers with the same name.
+func setResponseHeaders(httpRoutes []networking.HTTPRoute, claims []*v1alpha1.RouteClaim, spaceHeaders map[string]string) []networking.HTTPRoute {
+	// Keyed by the format of v1alpha1.HTTPRoutePath.
+	pathHeaders := make(map[string]map[string]string)
+	for _, claim := range claims {
+		if len(claim.Spec.ResponseHeaders) == 0 {
+			continue
+		}
+
+		matchedPath := strings.TrimSuffix(path.Join("/", claim.Spec.RouteSpecFields.Path), "/")
+		if pathHeaders[matchedPath] == nil {
+			pathHeaders[matchedPath] = make(map[string]string)
+		}
+
+		for name, value := range claim.Spec.ResponseHeaders {
+			pathHeaders[matchedPath][http.CanonicalHeaderKey(name)] = value
+		}
+	}
+
+	out := make([]networking.HTTPRoute, len(httpRoutes))
+	for i, httpRoute := range httpRoutes {
+		set := make(map[string]string)
+		for name, value := range spaceHeaders {
+			set[http.CanonicalHeaderKey(name)] = value
+		}
+
+		for name, value := range pathHeaders[v1alpha1.HTTPRoutePath(httpRoute)] {
+			set[name] = value
+		}
+
+		if len(set) > 0 {
+			headers := httpRoute.Headers.DeepCopy()
+			if headers == nil {
+				headers = &networking.Headers{}
+			}
+
+			if headers.Response == nil {
+				headers.Response = &networking.HeaderOperations{}
+			}
+
+			if headers.Response.Set == nil {
+				headers.Response.Set = make(map[string]string)
+			}
+
+			for name, value := range set {
+				headers.Response.Set[name] = value
+			}
+
+			httpRoute.Headers = headers
+		}
+
+		out[i] = httpRoute
+	}
+
+	return out
+}
+
+// pathRewrite holds the matchers for part of a route's path and the URI
+// Istio replaces the matched part with.
+type pathRewrite struct {
+	matchers []networking.HTTPMatchRequest
+	uri      string
+}
+
+// buildPathRewrites creates the matchers for routes that rewrite their path.
+// Istio only replaces the matched part of the path for prefix matches, so
+// the path itself and everything under it are matched separately, otherwise
+// requests for "/path/foo" would be rewritten to just the new path.
+func buildPathRewrites(fields v1alpha1.RouteSpecFields) ([]pathRewrite, error) {
+	rewritePath := fields.PathRewrite()
+	if rewritePath == "" {
+		pathMatchers, err := buildPathMatchers(fields.Path, fields.Headers)
+		if err != nil {
+			return nil, err
+		}
+
+		return []pathRewrite{{matchers: pathMatchers}}, nil
+	}
+
+	headers := buildHeaderMatchers(fields.Headers)
+	urlPath := path.Join("/", fields.Path)
+	rewriteDir := strings.TrimSuffix(rewritePath, "/") + "/"
+
+	var rewrites []pathRewrite
+	if urlPath != "/" {
+		rewrites = append(rewrites, pathRewrite{
+			matchers: []networking.HTTPMatchRequest{{
+				URI:     &istio.StringMatch{Exact: urlPath},
+				Headers: headers,
+			}},
+			uri: rewritePath,
 		})
 	}
 
+	rewrites = append(rewrites, pathRewrite{
+		matchers: []networking.HTTPMatchRequest{{
+			URI:     &istio.StringMatch{Prefix: strings.TrimSuffix(urlPath, "/") + "/"},
+			Headers: headers,
+		}},
+		uri: rewriteDir,
+	})
+
+	return rewrites, nil
+}
+
+func buildHTTPRoute(hostDomain, namespace string, fields v1alpha1.RouteSpecFields, appNames []string) ([]networking.HTTPRoute, error) {
+	rewrites, err := buildPathRewrites(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	var httpRoutes []networking.HTTPRoute
+
+	for _, appName := range appNames {
+		for _, rewrite := range rewrites {
+			httpRoutes = append(httpRoutes, networking.HTTPRoute{
+				Match: rewrite.matchers,
+				Route: buildRouteDestination(),
+				Rewrite: &networking.HTTPRewrite{
+					Authority: network.GetServiceHostname(appName, namespace),
+					URI:       rewrite.uri,
+				},
+				Headers: &networking.Headers{
+					Request: &networking.HeaderOperations{
+						Add: map[string]string{
+							// Set forwarding headers so the app gets the real hostname it's serving
+							// at rather than the internal one:
+							// https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Forwarded1
+							"X-Forwarded-Host": hostDomain,
+							"Forwarded":        fmt.Sprintf("host=%s", hostDomain),
+						},
+					},
+				},
+			})
+		}
+	}
+
 	// If there aren't any services bound to the route, we just want to
 	// serve a 503.
 	if len(httpRoutes) == 0 {
+		pathMatchers, err := buildPathMatchers(fields.Path, fields.Headers)
+		if err != nil {
+			return nil, err
+		}
+
 		return []networking.HTTPRoute{
 			{
 				Match: pathMatchers,
@@ -198,3 +454,20 @@ func buildRouteDestination() []networking.HTTPRouteDestination {
 		},
 	}
 }
+
+// RemoveAppHTTPRoutes returns the HTTP routes that don't send traffic to the
+// App with the given name in the namespace.
+func RemoveAppHTTPRoutes(httpRoutes []networking.HTTPRoute, appName, namespace string) []networking.HTTPRoute {
+	authority := network.GetServiceHostname(appName, namespace)
+
+	var out []networking.HTTPRoute
+	for _, httpRoute := range httpRoutes {
+		if httpRoute.Rewrite != nil && httpRoute.Rewrite.Authority == authority {
+			continue
+		}
+
+		out = append(out, httpRoute)
+	}
+
+	return out
+}
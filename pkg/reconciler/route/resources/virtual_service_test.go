@@ -22,6 +22,7 @@ import (
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	"github.com/google/kf/pkg/kf/testutil"
+	"github.com/google/kf/pkg/maintenance"
 	"github.com/google/kf/pkg/reconciler/route/resources"
 	"github.com/knative/serving/pkg/network"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -59,6 +60,7 @@ func TestMakeVirtualService(t *testing.T) {
 	for tn, tc := range map[string]struct {
 		Claims []*v1alpha1.RouteClaim
 		Routes []*v1alpha1.Route
+		Space  *v1alpha1.Space
 		Assert func(t *testing.T, v *networking.VirtualService, err error)
 	}{
 		"empty list of claims": {
@@ -244,6 +246,326 @@ func TestMakeVirtualService(t *testing.T) {
 				testutil.AssertEqual(t, "HTTP", expectedHTTP, v.Spec.HTTP)
 			},
 		},
+		"header Routes come before Routes for the same path": {
+			Claims: []*v1alpha1.RouteClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteClaimSpec{
+						RouteSpecFields: makeRouteSpecFields("some-host", "example.com", "/some-path"),
+					},
+				},
+			},
+			Routes: []*v1alpha1.Route{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteSpec{
+						RouteSpecFields: makeRouteSpecFields("some-host", "example.com", "/some-path"),
+						AppName:         "stable",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteSpec{
+						RouteSpecFields: v1alpha1.RouteSpecFields{
+							Hostname: "some-host",
+							Domain:   "example.com",
+							Path:     "/some-path",
+							Headers:  map[string]string{"X-Beta": "true"},
+						},
+						AppName: "beta",
+					},
+				},
+			},
+			Assert: func(t *testing.T, v *networking.VirtualService, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "HTTP len", 2, len(v.Spec.HTTP))
+
+				testutil.AssertEqual(t, "first match", networking.HTTPMatchRequest{
+					URI:     &istio.StringMatch{Regex: "^/some-path(/.*)?"},
+					Headers: map[string]istio.StringMatch{"x-beta": {Exact: "true"}},
+				}, v.Spec.HTTP[0].Match[0])
+				testutil.AssertEqual(t, "first authority", network.GetServiceHostname("beta", "some-namespace"), v.Spec.HTTP[0].Rewrite.Authority)
+
+				testutil.AssertEqual(t, "second match", networking.HTTPMatchRequest{
+					URI: &istio.StringMatch{Regex: "^/some-path(/.*)?"},
+				}, v.Spec.HTTP[1].Match[0])
+				testutil.AssertEqual(t, "second authority", network.GetServiceHostname("stable", "some-namespace"), v.Spec.HTTP[1].Rewrite.Authority)
+			},
+		},
+		"strip path prefix": {
+			Claims: []*v1alpha1.RouteClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteClaimSpec{
+						RouteSpecFields: makeRouteSpecFields("some-host", "example.com", "/some-path"),
+					},
+				},
+			},
+			Routes: []*v1alpha1.Route{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteSpec{
+						RouteSpecFields: v1alpha1.RouteSpecFields{
+							Hostname:        "some-host",
+							Domain:          "example.com",
+							Path:            "/some-path",
+							StripPathPrefix: true,
+						},
+						AppName: "some-app",
+					},
+				},
+			},
+			Assert: func(t *testing.T, v *networking.VirtualService, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "HTTP len", 3, len(v.Spec.HTTP))
+
+				testutil.AssertEqual(t, "exact match", networking.HTTPMatchRequest{
+					URI: &istio.StringMatch{Exact: "/some-path"},
+				}, v.Spec.HTTP[0].Match[0])
+				testutil.AssertEqual(t, "exact rewrite", &networking.HTTPRewrite{
+					Authority: network.GetServiceHostname("some-app", "some-namespace"),
+					URI:       "/",
+				}, v.Spec.HTTP[0].Rewrite)
+
+				testutil.AssertEqual(t, "prefix match", networking.HTTPMatchRequest{
+					URI: &istio.StringMatch{Prefix: "/some-path/"},
+				}, v.Spec.HTTP[1].Match[0])
+				testutil.AssertEqual(t, "prefix rewrite", &networking.HTTPRewrite{
+					Authority: network.GetServiceHostname("some-app", "some-namespace"),
+					URI:       "/",
+				}, v.Spec.HTTP[1].Rewrite)
+
+				// The claim still reserves the path.
+				testutil.AssertEqual(t, "claim match", networking.HTTPMatchRequest{
+					URI: &istio.StringMatch{Regex: "^/some-path(/.*)?"},
+				}, v.Spec.HTTP[2].Match[0])
+			},
+		},
+		"rewrite path": {
+			Claims: []*v1alpha1.RouteClaim{
+				makeRouteClaim("some-host", "example.com", "/some-path"),
+			},
+			Routes: []*v1alpha1.Route{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteSpec{
+						RouteSpecFields: v1alpha1.RouteSpecFields{
+							Hostname:    "some-host",
+							Domain:      "example.com",
+							Path:        "/some-path",
+							Headers:     map[string]string{"x-beta": "true"},
+							RewritePath: "/new",
+						},
+						AppName: "some-app",
+					},
+				},
+			},
+			Assert: func(t *testing.T, v *networking.VirtualService, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "HTTP len", 3, len(v.Spec.HTTP))
+
+				testutil.AssertEqual(t, "exact match", networking.HTTPMatchRequest{
+					URI:     &istio.StringMatch{Exact: "/some-path"},
+					Headers: map[string]istio.StringMatch{"x-beta": {Exact: "true"}},
+				}, v.Spec.HTTP[0].Match[0])
+				testutil.AssertEqual(t, "exact rewrite", "/new", v.Spec.HTTP[0].Rewrite.URI)
+
+				testutil.AssertEqual(t, "prefix match", networking.HTTPMatchRequest{
+					URI:     &istio.StringMatch{Prefix: "/some-path/"},
+					Headers: map[string]istio.StringMatch{"x-beta": {Exact: "true"}},
+				}, v.Spec.HTTP[1].Match[0])
+				testutil.AssertEqual(t, "prefix rewrite", "/new/", v.Spec.HTTP[1].Rewrite.URI)
+			},
+		},
+		"CORS policy applies to Routes for the path": {
+			Claims: []*v1alpha1.RouteClaim{
+				makeRouteClaim("some-host", "example.com", "/"),
+				{
+					Spec: v1alpha1.RouteClaimSpec{
+						RouteSpecFields: makeRouteSpecFields("some-host", "example.com", "/api"),
+						CORSPolicy: &v1alpha1.RouteCORSPolicy{
+							AllowOrigins:  []string{"https://app.example.com"},
+							AllowMethods:  []string{"GET", "POST"},
+							MaxAgeSeconds: 60,
+						},
+					},
+				},
+			},
+			Routes: []*v1alpha1.Route{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteSpec{
+						RouteSpecFields: v1alpha1.RouteSpecFields{
+							Hostname:        "some-host",
+							Domain:          "example.com",
+							Path:            "/api",
+							StripPathPrefix: true,
+						},
+						AppName: "backend",
+					},
+				},
+			},
+			Assert: func(t *testing.T, v *networking.VirtualService, err error) {
+				testutil.AssertNil(t, "err", err)
+
+				expected := &networking.CorsPolicy{
+					AllowOrigin:  []string{"https://app.example.com"},
+					AllowMethods: []string{"GET", "POST"},
+					MaxAge:       "60s",
+				}
+
+				for _, h := range v.Spec.HTTP {
+					if v1alpha1.HTTPRoutePath(h) == "/api" {
+						testutil.AssertEqual(t, "CORS policy", expected, h.CorsPolicy)
+					} else {
+						testutil.AssertEqual(t, "CORS policy", (*networking.CorsPolicy)(nil), h.CorsPolicy)
+					}
+				}
+			},
+		},
+		"response headers from the space and claims": {
+			Claims: []*v1alpha1.RouteClaim{
+				makeRouteClaim("some-host", "example.com", "/"),
+				{
+					Spec: v1alpha1.RouteClaimSpec{
+						RouteSpecFields: makeRouteSpecFields("some-host", "example.com", "/admin"),
+						ResponseHeaders: map[string]string{
+							"x-frame-options": "DENY",
+							"Cache-Control":   "no-store",
+						},
+					},
+				},
+			},
+			Routes: []*v1alpha1.Route{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteSpec{
+						RouteSpecFields: makeRouteSpecFields("some-host", "example.com", "/admin"),
+						AppName:         "admin",
+					},
+				},
+			},
+			Space: &v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Execution: v1alpha1.SpaceSpecExecution{
+						ResponseHeaders: map[string]string{
+							"Strict-Transport-Security": "max-age=31536000",
+							"X-Frame-Options":           "SAMEORIGIN",
+						},
+					},
+				},
+			},
+			Assert: func(t *testing.T, v *networking.VirtualService, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "HTTP len", 2, len(v.Spec.HTTP))
+
+				for _, h := range v.Spec.HTTP {
+					expected := map[string]string{
+						"Strict-Transport-Security": "max-age=31536000",
+						"X-Frame-Options":           "SAMEORIGIN",
+					}
+
+					if v1alpha1.HTTPRoutePath(h) == "/admin" {
+						expected = map[string]string{
+							"Strict-Transport-Security": "max-age=31536000",
+							"X-Frame-Options":           "DENY",
+							"Cache-Control":             "no-store",
+						}
+
+						testutil.AssertEqual(t, "request headers", "some-host.example.com", h.Headers.Request.Add["X-Forwarded-Host"])
+					}
+
+					testutil.AssertEqual(t, "response headers", expected, h.Headers.Response.Set)
+				}
+			},
+		},
+		"claims under maintenance replace Routes": {
+			Claims: []*v1alpha1.RouteClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteClaimSpec{
+						RouteSpecFields: makeRouteSpecFields("some-host", "example.com", "/some-path"),
+						Maintenance: &v1alpha1.RouteMaintenance{
+							Page: "<h1>Back soon</h1>",
+						},
+					},
+				},
+				makeRouteClaim("some-host", "example.com", "/other-path"),
+			},
+			Routes: []*v1alpha1.Route{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteSpec{
+						RouteSpecFields: makeRouteSpecFields("some-host", "example.com", "/some-path"),
+						AppName:         "ksvc-1",
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: "some-namespace",
+					},
+					Spec: v1alpha1.RouteSpec{
+						RouteSpecFields: v1alpha1.RouteSpecFields{
+							Hostname: "some-host",
+							Domain:   "example.com",
+							Path:     "/some-path",
+							Headers:  map[string]string{"x-beta": "true"},
+						},
+						AppName: "ksvc-2",
+					},
+				},
+			},
+			Assert: func(t *testing.T, v *networking.VirtualService, err error) {
+				testutil.AssertNil(t, "err", err)
+				testutil.AssertEqual(t, "HTTP len", 2, len(v.Spec.HTTP))
+
+				var maintenanceRoute *networking.HTTPRoute
+				for i, h := range v.Spec.HTTP {
+					if h.Match[0].URI.Regex == "^/some-path(/.*)?" {
+						maintenanceRoute = &v.Spec.HTTP[i]
+					}
+				}
+
+				testutil.AssertEqual(t, "maintenance route", networking.HTTPRoute{
+					Match: []networking.HTTPMatchRequest{
+						{URI: &istio.StringMatch{Regex: "^/some-path(/.*)?"}},
+					},
+					Route: []networking.HTTPRouteDestination{
+						{
+							Destination: networking.Destination{Host: "maintenance.kf.svc.cluster.local"},
+							Weight:      100,
+						},
+					},
+					Headers: &networking.Headers{
+						Request: &networking.HeaderOperations{
+							Set: map[string]string{
+								maintenance.PageHeader: maintenance.EncodePage("<h1>Back soon</h1>"),
+							},
+						},
+					},
+				}, *maintenanceRoute)
+			},
+		},
 		"Hosts with subdomain": {
 			Claims: []*v1alpha1.RouteClaim{
 				makeRouteClaim("some-host", "example.com", "/some-path"),
@@ -264,7 +586,7 @@ func TestMakeVirtualService(t *testing.T) {
 		},
 	} {
 		t.Run(tn, func(t *testing.T) {
-			s, err := resources.MakeVirtualService(tc.Claims, tc.Routes)
+			s, err := resources.MakeVirtualService(tc.Claims, tc.Routes, tc.Space)
 			tc.Assert(t, s, err)
 		})
 	}
@@ -281,7 +603,7 @@ func ExampleMakeVirtualService() {
 		makeRoute("some-host", "example.com/", "/some-path-2"),
 	}
 
-	vs, err := resources.MakeVirtualService(claims, routes)
+	vs, err := resources.MakeVirtualService(claims, routes, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -294,3 +616,40 @@ func ExampleMakeVirtualService() {
 	// Regex 1: ^/some-path-1(/.*)?
 	// Regex 2: ^/some-path-2(/.*)?
 }
+
+func ExampleSpaceGateway() {
+	fmt.Println(resources.SpaceGateway("my-space"))
+
+	// Output: space-gateway-my-space.kf.svc.cluster.local
+}
+
+func TestRemoveAppHTTPRoutes(t *testing.T) {
+	t.Parallel()
+
+	appRoute := func(appName, namespace string) networking.HTTPRoute {
+		return networking.HTTPRoute{
+			Rewrite: &networking.HTTPRewrite{
+				Authority: network.GetServiceHostname(appName, namespace),
+			},
+		}
+	}
+
+	unbound := networking.HTTPRoute{
+		Fault: &networking.HTTPFaultInjection{
+			Abort: &networking.InjectAbort{Percent: 100, HTTPStatus: http.StatusServiceUnavailable},
+		},
+	}
+
+	got := resources.RemoveAppHTTPRoutes([]networking.HTTPRoute{
+		appRoute("my-app", "some-namespace"),
+		appRoute("my-app", "other-namespace"),
+		appRoute("other-app", "some-namespace"),
+		unbound,
+	}, "my-app", "some-namespace")
+
+	testutil.AssertEqual(t, "routes", []networking.HTTPRoute{
+		appRoute("my-app", "other-namespace"),
+		appRoute("other-app", "some-namespace"),
+		unbound,
+	}, got)
+}
@@ -19,12 +19,16 @@ import (
 
 	kfv1alpha1 "github.com/google/kf/pkg/apis/kf/v1alpha1"
 	sourceinformer "github.com/google/kf/pkg/client/injection/informers/kf/v1alpha1/source"
+	spaceinformer "github.com/google/kf/pkg/client/injection/informers/kf/v1alpha1/space"
 	"github.com/google/kf/pkg/reconciler"
+	"github.com/google/kf/pkg/system"
 	buildclient "github.com/google/kf/third_party/knative-build/pkg/client/injection/client"
 	buildinformer "github.com/google/kf/third_party/knative-build/pkg/client/injection/informers/build/v1alpha1/build"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
 	"knative.dev/pkg/configmap"
 	controller "knative.dev/pkg/controller"
+	"knative.dev/pkg/kmeta"
 )
 
 // NewController creates a new controller capable of reconciling Kf sources.
@@ -33,6 +37,7 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 
 	// Get informers off context
 	sourceInformer := sourceinformer.Get(ctx)
+	spaceInformer := spaceinformer.Get(ctx)
 	buildInformer := buildinformer.Get(ctx)
 	buildClient := buildclient.Get(ctx)
 
@@ -40,8 +45,10 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 	c := &Reconciler{
 		Base:         reconciler.NewBase(ctx, cmw),
 		sourceLister: sourceInformer.Lister(),
+		spaceLister:  spaceInformer.Lister(),
 		buildLister:  buildInformer.Lister(),
 		buildClient:  buildClient.BuildV1alpha1(),
+		kfVersion:    system.KfVersion(),
 	}
 
 	impl := controller.NewImpl(c, logger, "sources")
@@ -56,5 +63,28 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
+	// When a build changes state a slot may have opened up in the space's build
+	// queue so any waiting sources need to be checked again.
+	buildInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(kfv1alpha1.SchemeGroupVersion.WithKind("Source")),
+		Handler: controller.HandleAll(func(obj interface{}) {
+			object, err := kmeta.DeletionHandlingAccessor(obj)
+			if err != nil {
+				return
+			}
+
+			queued, err := sourceInformer.Lister().Sources(object.GetNamespace()).List(labels.Everything())
+			if err != nil {
+				return
+			}
+
+			for _, source := range queued {
+				if source.Status.BuildName == "" {
+					impl.Enqueue(source)
+				}
+			}
+		}),
+	})
+
 	return impl
 }
@@ -18,18 +18,27 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	kflisters "github.com/google/kf/pkg/client/listers/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/notifications"
+	"github.com/google/kf/pkg/kf/tracing"
 	"github.com/google/kf/pkg/reconciler"
 	"github.com/google/kf/pkg/reconciler/source/resources"
+	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
 	buildclient "github.com/google/kf/third_party/knative-build/pkg/client/clientset/versioned/typed/build/v1alpha1"
 	buildlisters "github.com/google/kf/third_party/knative-build/pkg/client/listers/build/v1alpha1"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/tools/cache"
+	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/logging"
 )
@@ -42,14 +51,24 @@ type Reconciler struct {
 
 	// listers index properties about resources
 	sourceLister kflisters.SourceLister
+	spaceLister  kflisters.SpaceLister
 	buildLister  buildlisters.BuildLister
+
+	// kfVersion is recorded on built images.
+	kfVersion string
+
+	// admissionMu serializes starting builds so space build limits hold.
+	admissionMu sync.Mutex
 }
 
 // Check that our Reconciler implements controller.Reconciler
 var _ controller.Reconciler = (*Reconciler)(nil)
 
 // Reconcile is called by Kubernetes.
-func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+func (r *Reconciler) Reconcile(ctx context.Context, key string) (err error) {
+	ctx, span := tracing.StartReconcileSpan(ctx, "Source", key)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	namespace, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		return err
@@ -94,6 +113,11 @@ func (r *Reconciler) reconcileSource(
 	// Reconcile this copy of the service and then write back any status
 	// updates regardless of whether the reconciliation errored out.
 	reconcileErr := r.ApplyChanges(ctx, toReconcile)
+
+	// Surface failures users can act on as Events on the object.
+	r.RecordStatusChanges(toReconcile, original.Status.Status, toReconcile.Status.Status)
+	r.RecordReconcileError(toReconcile, reconcileErr)
+
 	if equality.Semantic.DeepEqual(original.Status, toReconcile.Status) {
 		// If we didn't change anything then don't call updateStatus.
 		// This is important because the copy we loaded from the informer's
@@ -103,6 +127,8 @@ func (r *Reconciler) reconcileSource(
 	} else if _, uErr := r.updateStatus(namespace, toReconcile); uErr != nil {
 		logger.Warnw("Failed to update Source status", zap.Error(uErr))
 		return uErr
+	} else {
+		r.SendNotification(ctx, r.spaceLister, notifications.ForSource(original, toReconcile, time.Now()))
 	}
 
 	return reconcileErr
@@ -123,28 +149,103 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, source *v1alpha1.Source)
 	{
 		logger.Debug("reconciling Build")
 
-		desired, err := resources.MakeBuild(source)
+		desired, err := resources.MakeBuild(source, r.kfVersion)
 		if err != nil {
 			return err
 		}
 
 		actual, err := r.buildLister.Builds(source.Namespace).Get(desired.Name)
 		if errors.IsNotFound(err) {
-			actual, err = r.buildClient.Builds(desired.Namespace).Create(desired)
+			var position int
+			actual, position, err = r.admitBuild(source, desired)
 			if err != nil {
 				return err
 			}
+
+			if position > 0 {
+				logger.Infof("build queued at position %d", position)
+				source.Status.MarkBuildQueued(position)
+				return nil
+			}
 		} else if !metav1.IsControlledBy(actual, source) {
 			source.Status.MarkBuildNotOwned(desired.Name)
 			return fmt.Errorf("source: %q does not own build: %q", source.Name, desired.Name)
 		}
 
 		source.Status.PropagateBuildStatus(actual)
+		r.recordBuilderPullErrors(source, actual)
 	}
 
 	return nil
 }
 
+// recordBuilderPullErrors records a Warning Event on the source if the build's
+// pod can't pull its images. The build stays pending rather than failing in
+// this case so the status alone doesn't tell users anything is wrong.
+func (r *Reconciler) recordBuilderPullErrors(source *v1alpha1.Source, b *build.Build) {
+	cond := b.Status.GetCondition(duckv1alpha1.ConditionSucceeded)
+	if cond == nil || !cond.IsUnknown() {
+		return
+	}
+
+	if !strings.Contains(cond.Reason, "ImagePull") && !strings.Contains(cond.Message, "ImagePull") {
+		return
+	}
+
+	r.Recorder.Eventf(source, corev1.EventTypeWarning, "BuilderPullFailed", "Build %q can't pull its images: %s", b.Name, cond.Message)
+}
+
+// admitBuild creates the build if the space's build queue lets it start.
+// Otherwise the position of the source in the queue is returned.
+//
+// Admission is serialized and running builds are listed from the API server
+// rather than the informer cache so concurrent reconciles can't both see a
+// free slot and exceed the space's limit.
+func (r *Reconciler) admitBuild(source *v1alpha1.Source, desired *build.Build) (*build.Build, int, error) {
+	r.admissionMu.Lock()
+	defer r.admissionMu.Unlock()
+
+	position, err := r.buildQueuePosition(source)
+	if err != nil || position > 0 {
+		return nil, position, err
+	}
+
+	actual, err := r.buildClient.Builds(desired.Namespace).Create(desired)
+	return actual, 0, err
+}
+
+// buildQueuePosition gets the position of the source in the space's build
+// queue, zero means the build may start.
+func (r *Reconciler) buildQueuePosition(source *v1alpha1.Source) (int, error) {
+	space, err := r.spaceLister.Get(source.Namespace)
+	switch {
+	case errors.IsNotFound(err):
+		// Spaces without configuration have no build limits.
+		return 0, nil
+	case err != nil:
+		return 0, err
+	}
+
+	maxConcurrent := space.Spec.BuildpackBuild.MaxConcurrentBuilds
+	if maxConcurrent <= 0 {
+		return 0, nil
+	}
+
+	sources, err := r.sourceLister.Sources(source.Namespace).List(labels.Everything())
+	if err != nil {
+		return 0, err
+	}
+
+	builds, err := r.buildClient.Builds(source.Namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	running := resources.RunningBuilds(builds.Items)
+
+	return resources.BuildQueuePosition(source, sources, running, maxConcurrent), nil
+}
+
 func (r *Reconciler) updateStatus(namespace string, desired *v1alpha1.Source) (*v1alpha1.Source, error) {
 	actual, err := r.sourceLister.Sources(namespace).Get(desired.Name)
 	if err != nil {
@@ -15,6 +15,9 @@
 package resources
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
 	"github.com/knative/serving/pkg/resources"
@@ -54,37 +57,62 @@ func makeContainerImageBuild(source *v1alpha1.Source) (*build.Build, error) {
 	}, nil
 }
 
-func makeDockerImageBuild(source *v1alpha1.Source) (*build.Build, error) {
+func makeDockerImageBuild(source *v1alpha1.Source, kfVersion string) (*build.Build, error) {
+	args := []build.ArgumentSpec{
+		{Name: v1alpha1.BuildArgImage, Value: source.Spec.Dockerfile.Image},
+		{Name: v1alpha1.BuildArgDockerfile, Value: source.Spec.Dockerfile.Path},
+		{
+			Name:  v1alpha1.BuildArgLabels,
+			Value: makeImageLabels(source, source.Spec.Dockerfile.Source, dockerImageTemplate, kfVersion),
+		},
+	}
+
+	env, names := makeDockerfileBuildArgs(source.Spec.Dockerfile.BuildArgs)
+	if len(names) > 0 {
+		args = append(args, build.ArgumentSpec{
+			Name:  v1alpha1.BuildArgDockerfileArgs,
+			Value: strings.Join(names, " "),
+		})
+	}
+
 	return &build.Build{
 		ObjectMeta: makeObjectMeta(source),
 		Spec: build.BuildSpec{
 			ServiceAccountName: source.Spec.ServiceAccount,
-			Source: &build.SourceSpec{
-				Custom: &corev1.Container{
-					Image: source.Spec.Dockerfile.Source,
-				},
-			},
+			Source:             makeBuildSource(source.Spec.Dockerfile.Source, source.Spec.Git),
 			Template: &build.TemplateInstantiationSpec{
-				Name: dockerImageTemplate,
-				Kind: "ClusterBuildTemplate",
-				Arguments: []build.ArgumentSpec{
-					{Name: v1alpha1.BuildArgImage, Value: source.Spec.Dockerfile.Image},
-					{Name: v1alpha1.BuildArgDockerfile, Value: source.Spec.Dockerfile.Path},
-				},
+				Name:      dockerImageTemplate,
+				Kind:      "ClusterBuildTemplate",
+				Arguments: args,
+				Env:       env,
 			},
 		},
 	}, nil
 }
 
-func makeBuildpackBuild(source *v1alpha1.Source) (*build.Build, error) {
+// makeDockerfileBuildArgs converts build args into prefixed environment
+// variables for the kaniko template along with the names it should pass to
+// --build-arg. Values stay in the environment, so ones read from Secrets are
+// never written to the Build or its logs.
+func makeDockerfileBuildArgs(buildArgs []corev1.EnvVar) ([]corev1.EnvVar, []string) {
+	var env []corev1.EnvVar
+	var names []string
+	for _, arg := range buildArgs {
+		prefixed := arg.DeepCopy()
+		prefixed.Name = v1alpha1.DockerfileBuildArgEnvPrefix + arg.Name
+
+		env = append(env, *prefixed)
+		names = append(names, arg.Name)
+	}
+
+	return env, names
+}
+
+func makeBuildpackBuild(source *v1alpha1.Source, kfVersion string) (*build.Build, error) {
 	return &build.Build{
 		ObjectMeta: makeObjectMeta(source),
 		Spec: build.BuildSpec{
-			Source: &build.SourceSpec{
-				Custom: &corev1.Container{
-					Image: source.Spec.BuildpackBuild.Source,
-				},
-			},
+			Source:             makeBuildSource(source.Spec.BuildpackBuild.Source, source.Spec.Git),
 			ServiceAccountName: source.Spec.ServiceAccount,
 			Template: &build.TemplateInstantiationSpec{
 				Name: buildpackBuildTemplate,
@@ -106,6 +134,15 @@ func makeBuildpackBuild(source *v1alpha1.Source) (*build.Build, error) {
 						Name:  v1alpha1.BuildArgBuildpackRunImage,
 						Value: source.Spec.BuildpackBuild.Stack,
 					},
+					{
+						Name: v1alpha1.BuildArgLabels,
+						Value: makeImageLabels(
+							source,
+							source.Spec.BuildpackBuild.Source,
+							source.Spec.BuildpackBuild.BuildpackBuilder,
+							kfVersion,
+						),
+					},
 				},
 				Env: source.Spec.BuildpackBuild.Env,
 			},
@@ -113,6 +150,25 @@ func makeBuildpackBuild(source *v1alpha1.Source) (*build.Build, error) {
 	}, nil
 }
 
+// makeBuildSource clones the Git repository if one is set, otherwise it uses
+// the source image uploaded by the CLI.
+func makeBuildSource(sourceImage string, git *v1alpha1.SourceSpecGit) *build.SourceSpec {
+	if git != nil {
+		return &build.SourceSpec{
+			Git: &build.GitSourceSpec{
+				Url:      git.URL,
+				Revision: git.Revision,
+			},
+		}
+	}
+
+	return &build.SourceSpec{
+		Custom: &corev1.Container{
+			Image: sourceImage,
+		},
+	}
+}
+
 func makeObjectMeta(source *v1alpha1.Source) metav1.ObjectMeta {
 	return metav1.ObjectMeta{
 		Name:      BuildName(source),
@@ -128,14 +184,47 @@ func makeObjectMeta(source *v1alpha1.Source) metav1.ObjectMeta {
 	}
 }
 
-// MakeBuild creates a Build for a Source.
-func MakeBuild(source *v1alpha1.Source) (*build.Build, error) {
+// makeImageLabels creates the provenance labels for the built image in the
+// space separated key=value format the build templates expect.
+func makeImageLabels(source *v1alpha1.Source, sourceImage, builder, kfVersion string) string {
+	labels := map[string]string{
+		v1alpha1.ImageSpaceLabel:     source.Namespace,
+		v1alpha1.ImageAppLabel:       source.Labels[v1alpha1.NameLabel],
+		v1alpha1.ImageSourceLabel:    sourceImage,
+		v1alpha1.ImageBuilderLabel:   builder,
+		v1alpha1.ImageKfVersionLabel: kfVersion,
+	}
+
+	if git := source.Spec.Git; git != nil {
+		labels[v1alpha1.ImageSourceLabel] = git.URL + "#" + git.Revision
+		labels[v1alpha1.ImageOCISourceLabel] = git.URL
+		labels[v1alpha1.ImageOCIRevisionLabel] = git.Revision
+	}
+
+	var pairs []string
+	for key, value := range labels {
+		// Values can't contain spaces because they separate the labels.
+		value = strings.Join(strings.Fields(value), "")
+		if value == "" {
+			continue
+		}
+
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, " ")
+}
+
+// MakeBuild creates a Build for a Source. kfVersion is recorded on the built
+// image along with the rest of its provenance.
+func MakeBuild(source *v1alpha1.Source, kfVersion string) (*build.Build, error) {
 	switch {
 	case source.Spec.IsContainerBuild():
 		return makeContainerImageBuild(source)
 	case source.Spec.IsDockerfileBuild():
-		return makeDockerImageBuild(source)
+		return makeDockerImageBuild(source, kfVersion)
 	default:
-		return makeBuildpackBuild(source)
+		return makeBuildpackBuild(source, kfVersion)
 	}
 }
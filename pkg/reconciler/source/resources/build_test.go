@@ -16,6 +16,7 @@ package resources
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -46,7 +47,7 @@ func ExampleMakeBuild() {
 		},
 	}
 
-	build, err := MakeBuild(source)
+	build, err := MakeBuild(source, "v1.2.3")
 	if err != nil {
 		panic(err)
 	}
@@ -64,8 +65,118 @@ func ExampleMakeBuild() {
 	// Label Count: 1
 	// Managed By: kf
 	// Service Account: some-account
-	// Arg Count: 4
+	// Arg Count: 5
 	// Output Image: gcr.io/image:123
 	// Env: some = variable
 	// Stack: gcr.io/kf-releases/run:latest
 }
+
+func ExampleMakeBuild_git() {
+	source := &v1alpha1.Source{}
+	source.Name = "my-source"
+	source.Spec.BuildpackBuild.Source = "some-source"
+	source.Spec.Git = &v1alpha1.SourceSpecGit{
+		URL:      "https://github.com/some/repo",
+		Revision: "abc123",
+	}
+
+	build, err := MakeBuild(source, "v1.2.3")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Custom source:", build.Spec.Source.Custom != nil)
+	fmt.Println("Git URL:", build.Spec.Source.Git.Url)
+	fmt.Println("Git revision:", build.Spec.Source.Git.Revision)
+
+	// Output: Custom source: false
+	// Git URL: https://github.com/some/repo
+	// Git revision: abc123
+}
+
+func ExampleMakeBuild_imageLabels() {
+	source := &v1alpha1.Source{}
+	source.Name = "my-source"
+	source.Namespace = "my-space"
+	source.Labels = map[string]string{v1alpha1.NameLabel: "my-app"}
+	source.Spec.BuildpackBuild.Source = "gcr.io/src-my-space-my-app:abc123"
+	source.Spec.BuildpackBuild.BuildpackBuilder = "gcr.io/builder:v1"
+
+	build, err := MakeBuild(source, "v1.2.3")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, label := range strings.Fields(v1alpha1.GetBuildArg(build, v1alpha1.BuildArgLabels)) {
+		fmt.Println(label)
+	}
+
+	// Output: dev.kf.image.app=my-app
+	// dev.kf.image.builder=gcr.io/builder:v1
+	// dev.kf.image.kf-version=v1.2.3
+	// dev.kf.image.source=gcr.io/src-my-space-my-app:abc123
+	// dev.kf.image.space=my-space
+}
+
+func ExampleMakeBuild_gitImageLabels() {
+	source := &v1alpha1.Source{}
+	source.Name = "my-source"
+	source.Namespace = "my-space"
+	source.Spec.Dockerfile.Source = "some-source"
+	source.Spec.Dockerfile.Image = "gcr.io/image:123"
+	source.Spec.Git = &v1alpha1.SourceSpecGit{
+		URL:      "https://github.com/some/repo",
+		Revision: "abc123",
+	}
+
+	build, err := MakeBuild(source, "v1.2.3")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, label := range strings.Fields(v1alpha1.GetBuildArg(build, v1alpha1.BuildArgLabels)) {
+		fmt.Println(label)
+	}
+
+	// Output: dev.kf.image.builder=kaniko
+	// dev.kf.image.kf-version=v1.2.3
+	// dev.kf.image.source=https://github.com/some/repo#abc123
+	// dev.kf.image.space=my-space
+	// org.opencontainers.image.revision=abc123
+	// org.opencontainers.image.source=https://github.com/some/repo
+}
+
+func ExampleMakeBuild_dockerfileBuildArgs() {
+	source := &v1alpha1.Source{}
+	source.Name = "my-source"
+	source.Spec.Dockerfile.Source = "some-source"
+	source.Spec.Dockerfile.Path = "Dockerfile"
+	source.Spec.Dockerfile.Image = "gcr.io/image:123"
+	source.Spec.Dockerfile.BuildArgs = []corev1.EnvVar{
+		{Name: "VERSION", Value: "1.2.3"},
+		{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "creds"},
+				Key:                  "token",
+			},
+		}},
+	}
+
+	build, err := MakeBuild(source, "v1.2.3")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Build args:", v1alpha1.GetBuildArg(build, v1alpha1.BuildArgDockerfileArgs))
+	for _, env := range build.Spec.Template.Env {
+		if env.ValueFrom != nil {
+			fmt.Println("Env:", env.Name, "from Secret", env.ValueFrom.SecretKeyRef.Name)
+		} else {
+			fmt.Println("Env:", env.Name, "=", env.Value)
+		}
+	}
+
+	// Output: Build args: VERSION TOKEN
+	// Env: KF_BUILD_ARG_VERSION = 1.2.3
+	// Env: KF_BUILD_ARG_TOKEN from Secret creds
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"sort"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
+)
+
+// BuildQueuePosition returns the position of the source in the space's build
+// queue given every other Source in the same namespace and the number of
+// builds that are running. A position of zero means the build can be started
+// now.
+//
+// Sources that are waiting are ordered with interactive builds first, then by
+// creation time so each priority is served FIFO. Sources that were superseded
+// by a newer Source for the same App don't hold a place in the queue, they're
+// only started once every current Source has been.
+func BuildQueuePosition(source *v1alpha1.Source, sources []*v1alpha1.Source, running, maxConcurrent int) int {
+	if maxConcurrent <= 0 {
+		return 0
+	}
+
+	latest := latestSources(sources)
+
+	var waiting, superseded []*v1alpha1.Source
+	for _, s := range sources {
+		switch {
+		case s.DeletionTimestamp != nil:
+			continue
+		case v1alpha1.IsStatusFinal(s.Status.Status):
+			continue
+		case s.Status.BuildName != "":
+			continue
+		case !isLatestSource(s, latest):
+			superseded = append(superseded, s)
+		default:
+			waiting = append(waiting, s)
+		}
+	}
+
+	sortQueue(waiting)
+	sortQueue(superseded)
+
+	available := maxConcurrent - running
+	if available < 0 {
+		available = 0
+	}
+
+	for i, s := range append(waiting, superseded...) {
+		if s.Name != source.Name {
+			continue
+		}
+
+		if i < available {
+			return 0
+		}
+
+		return i - available + 1
+	}
+
+	// The source wasn't in the list (e.g. a stale cache), let it through so it
+	// doesn't get stuck forever.
+	return 0
+}
+
+// RunningBuilds returns the number of builds for Sources that haven't
+// completed.
+func RunningBuilds(builds []build.Build) int {
+	running := 0
+	for _, b := range builds {
+		owner := metav1.GetControllerOf(&b)
+		if owner == nil || owner.Kind != "Source" {
+			continue
+		}
+
+		cond := b.Status.GetCondition(duckv1alpha1.ConditionSucceeded)
+		if cond == nil || cond.IsUnknown() {
+			running++
+		}
+	}
+
+	return running
+}
+
+func sortQueue(sources []*v1alpha1.Source) {
+	sort.SliceStable(sources, func(i, j int) bool {
+		left, right := sources[i], sources[j]
+
+		if lp, rp := left.BuildPriority(), right.BuildPriority(); lp != rp {
+			return lp == v1alpha1.BuildPriorityInteractive
+		}
+
+		return isNewer(right, left)
+	})
+}
+
+// latestSources returns the newest Source for each controlling App.
+func latestSources(sources []*v1alpha1.Source) map[types.UID]*v1alpha1.Source {
+	latest := make(map[types.UID]*v1alpha1.Source)
+	for _, s := range sources {
+		owner := metav1.GetControllerOf(s)
+		if owner == nil {
+			continue
+		}
+
+		if current, ok := latest[owner.UID]; !ok || isNewer(s, current) {
+			latest[owner.UID] = s
+		}
+	}
+
+	return latest
+}
+
+func isLatestSource(source *v1alpha1.Source, latest map[types.UID]*v1alpha1.Source) bool {
+	owner := metav1.GetControllerOf(source)
+	if owner == nil {
+		return true
+	}
+
+	return latest[owner.UID].Name == source.Name
+}
+
+// isNewer returns true if left was created after right, names break ties.
+func isNewer(left, right *v1alpha1.Source) bool {
+	if !left.CreationTimestamp.Equal(&right.CreationTimestamp) {
+		return right.CreationTimestamp.Before(&left.CreationTimestamp)
+	}
+
+	return left.Name > right.Name
+}
@@ -0,0 +1,226 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/apis"
+	duckv1alpha1 "knative.dev/pkg/apis/duck/v1alpha1"
+	"knative.dev/pkg/ptr"
+)
+
+func TestBuildQueuePosition(t *testing.T) {
+	now := time.Now()
+
+	source := func(name string, age time.Duration, priority string) *v1alpha1.Source {
+		s := &v1alpha1.Source{}
+		s.Name = name
+		s.CreationTimestamp = metav1.NewTime(now.Add(-age))
+		if priority != "" {
+			s.Annotations = map[string]string{v1alpha1.BuildPriorityAnnotation: priority}
+		}
+		return s
+	}
+
+	running := func(s *v1alpha1.Source) *v1alpha1.Source {
+		s.Status.BuildName = s.Name
+		return s
+	}
+
+	finished := func(s *v1alpha1.Source) *v1alpha1.Source {
+		s.Status.BuildName = s.Name
+		s.Status.Conditions = append(s.Status.Conditions, apis.Condition{
+			Type:   v1alpha1.SourceConditionSucceeded,
+			Status: corev1.ConditionTrue,
+		})
+		return s
+	}
+
+	ownedBy := func(s *v1alpha1.Source, app string) *v1alpha1.Source {
+		s.OwnerReferences = []metav1.OwnerReference{{
+			Kind:       "App",
+			Name:       app,
+			UID:        types.UID(app),
+			Controller: ptr.Bool(true),
+		}}
+		return s
+	}
+
+	cases := map[string]struct {
+		source        string
+		sources       []*v1alpha1.Source
+		running       int
+		maxConcurrent int
+		expected      int
+	}{
+		"unlimited": {
+			source: "a",
+			sources: []*v1alpha1.Source{
+				running(source("b", time.Hour, "")),
+				source("a", time.Minute, ""),
+			},
+			running:       1,
+			maxConcurrent: 0,
+			expected:      0,
+		},
+		"slot available": {
+			source: "a",
+			sources: []*v1alpha1.Source{
+				running(source("b", time.Hour, "")),
+				source("a", time.Minute, ""),
+			},
+			running:       1,
+			maxConcurrent: 2,
+			expected:      0,
+		},
+		"finished builds don't wait": {
+			source: "a",
+			sources: []*v1alpha1.Source{
+				finished(source("b", time.Hour, "")),
+				source("a", time.Minute, ""),
+			},
+			maxConcurrent: 1,
+			expected:      0,
+		},
+		"full": {
+			source: "a",
+			sources: []*v1alpha1.Source{
+				running(source("b", time.Hour, "")),
+				source("a", time.Minute, ""),
+			},
+			running:       1,
+			maxConcurrent: 1,
+			expected:      1,
+		},
+		"fifo": {
+			source: "a",
+			sources: []*v1alpha1.Source{
+				running(source("b", time.Hour, "")),
+				source("a", time.Minute, ""),
+				source("c", 2*time.Minute, ""),
+			},
+			running:       1,
+			maxConcurrent: 1,
+			expected:      2,
+		},
+		"interactive before scheduled": {
+			source: "a",
+			sources: []*v1alpha1.Source{
+				source("c", 2*time.Minute, v1alpha1.BuildPriorityScheduled),
+				source("a", time.Minute, v1alpha1.BuildPriorityInteractive),
+			},
+			maxConcurrent: 1,
+			expected:      0,
+		},
+		"scheduled waits for interactive": {
+			source: "c",
+			sources: []*v1alpha1.Source{
+				source("c", 2*time.Minute, v1alpha1.BuildPriorityScheduled),
+				source("a", time.Minute, ""),
+			},
+			maxConcurrent: 1,
+			expected:      1,
+		},
+		"limit lowered below running": {
+			source: "a",
+			sources: []*v1alpha1.Source{
+				running(source("b", time.Hour, "")),
+				running(source("c", time.Hour, "")),
+				source("a", time.Minute, ""),
+			},
+			running:       2,
+			maxConcurrent: 1,
+			expected:      1,
+		},
+		"missing from list": {
+			source:        "a",
+			sources:       []*v1alpha1.Source{running(source("b", time.Hour, ""))},
+			running:       1,
+			maxConcurrent: 1,
+			expected:      0,
+		},
+		"superseded sources don't hold a place": {
+			source: "a",
+			sources: []*v1alpha1.Source{
+				ownedBy(source("x-1", 3*time.Minute, ""), "x"),
+				ownedBy(source("a", 2*time.Minute, ""), "a"),
+				ownedBy(source("x-2", time.Minute, ""), "x"),
+			},
+			running:       1,
+			maxConcurrent: 1,
+			expected:      1,
+		},
+		"superseded sources wait for current ones": {
+			source: "x-1",
+			sources: []*v1alpha1.Source{
+				ownedBy(source("x-1", 3*time.Minute, ""), "x"),
+				ownedBy(source("a", 2*time.Minute, ""), "a"),
+				ownedBy(source("x-2", time.Minute, ""), "x"),
+			},
+			running:       1,
+			maxConcurrent: 1,
+			expected:      3,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			target := &v1alpha1.Source{}
+			target.Name = tc.source
+
+			actual := BuildQueuePosition(target, tc.sources, tc.running, tc.maxConcurrent)
+			testutil.AssertEqual(t, "position", tc.expected, actual)
+		})
+	}
+}
+
+func TestRunningBuilds(t *testing.T) {
+	sourceBuild := func(status corev1.ConditionStatus) build.Build {
+		b := build.Build{}
+		b.OwnerReferences = []metav1.OwnerReference{{
+			Kind:       "Source",
+			Name:       "some-source",
+			Controller: ptr.Bool(true),
+		}}
+		if status != "" {
+			b.Status.SetCondition(&duckv1alpha1.Condition{
+				Type:   duckv1alpha1.ConditionSucceeded,
+				Status: status,
+			})
+		}
+		return b
+	}
+
+	builderBuild := sourceBuild("")
+	builderBuild.OwnerReferences[0].Kind = "Space"
+
+	builds := []build.Build{
+		sourceBuild(""),
+		sourceBuild(corev1.ConditionUnknown),
+		sourceBuild(corev1.ConditionTrue),
+		sourceBuild(corev1.ConditionFalse),
+		builderBuild,
+	}
+
+	testutil.AssertEqual(t, "running", 2, RunningBuilds(builds))
+}
@@ -18,19 +18,30 @@ import (
 	"context"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	appinformer "github.com/google/kf/pkg/client/injection/informers/kf/v1alpha1/app"
+	routeclaiminformer "github.com/google/kf/pkg/client/injection/informers/kf/v1alpha1/routeclaim"
 	spaceinformer "github.com/google/kf/pkg/client/injection/informers/kf/v1alpha1/space"
+	"github.com/google/kf/pkg/kf/clusterdefaults"
 	"github.com/google/kf/pkg/reconciler"
+	buildclient "github.com/google/kf/third_party/knative-build/pkg/client/injection/client"
+	buildinformer "github.com/google/kf/third_party/knative-build/pkg/client/injection/informers/build/v1alpha1/build"
 	namespaceinformer "knative.dev/pkg/injection/informers/kubeinformers/corev1/namespace"
 	roleinformer "knative.dev/pkg/injection/informers/kubeinformers/rbacv1/role"
 
 	// TODO (juliaguo): replace with knative informer pkgs once they are merged in
 	limitrangeinformer "github.com/google/kf/pkg/client/injection/informers/kubernetes/limitrange"
+	networkpolicyinformer "github.com/google/kf/pkg/client/injection/informers/kubernetes/networkpolicy"
 	quotainformer "github.com/google/kf/pkg/client/injection/informers/kubernetes/resourcequota"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/tools/cache"
 
+	gatewayinformer "knative.dev/pkg/client/injection/informers/istio/v1alpha3/gateway"
+	virtualserviceinformer "knative.dev/pkg/client/injection/informers/istio/v1alpha3/virtualservice"
 	"knative.dev/pkg/configmap"
 	"knative.dev/pkg/controller"
+	"knative.dev/pkg/injection/clients/dynamicclient"
+	"knative.dev/pkg/kmeta"
 )
 
 // NewController creates a new controller capable of reconciling Kf Spaces.
@@ -43,6 +54,15 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 	roleInformer := roleinformer.Get(ctx)
 	quotaInformer := quotainformer.Get(ctx)
 	limitRangeInformer := limitrangeinformer.Get(ctx)
+	appInformer := appinformer.Get(ctx)
+	routeClaimInformer := routeclaiminformer.Get(ctx)
+	virtualServiceInformer := virtualserviceinformer.Get(ctx)
+	gatewayInformer := gatewayinformer.Get(ctx)
+	networkPolicyInformer := networkpolicyinformer.Get(ctx)
+	buildInformer := buildinformer.Get(ctx)
+
+	defaultsStore := &clusterdefaults.Store{}
+	defaultsStore.WatchConfigs(cmw, logger)
 
 	// Create reconciler
 	c := &Reconciler{
@@ -52,14 +72,31 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		roleLister:          roleInformer.Lister(),
 		resourceQuotaLister: quotaInformer.Lister(),
 		limitRangeLister:    limitRangeInformer.Lister(),
+		gatewayLister:       gatewayInformer.Lister(),
+		networkPolicyLister: networkPolicyInformer.Lister(),
+		buildLister:         buildInformer.Lister(),
+
+		appLister:            appInformer.Lister(),
+		routeClaimLister:     routeClaimInformer.Lister(),
+		virtualServiceLister: virtualServiceInformer.Lister(),
+
+		buildClient:   buildclient.Get(ctx).BuildV1alpha1(),
+		dynamicClient: dynamicclient.Get(ctx),
+		defaultsStore: defaultsStore,
 	}
 
 	impl := controller.NewImpl(c, logger, "Spaces")
+	c.enqueueAfter = impl.EnqueueAfter
 
 	logger.Info("Setting up event handlers")
 	// Watch for changes in sub-resources so we can sync accordingly
 	spaceInformer.Informer().AddEventHandler(controller.HandleAll(impl.Enqueue))
 
+	// Spaces record the cluster's build defaults in their status.
+	cmw.Watch(clusterdefaults.ConfigMapName, func(*corev1.ConfigMap) {
+		impl.GlobalResync(spaceInformer.Informer())
+	})
+
 	nsInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("Space")),
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
@@ -80,5 +117,24 @@ func NewController(ctx context.Context, cmw configmap.Watcher) *controller.Impl
 		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
 	})
 
+	gatewayInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("Space")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	// Builds composing the custom builder are owned by the Space.
+	buildInformer.Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: controller.Filter(v1alpha1.SchemeGroupVersion.WithKind("Space")),
+		Handler:    controller.HandleAll(impl.EnqueueControllerOf),
+	})
+
+	// NetworkPolicies aren't owned by the Space, the namespace they're in has
+	// the same name as the Space that requires them.
+	networkPolicyInformer.Informer().AddEventHandler(controller.HandleAll(func(obj interface{}) {
+		if object, err := kmeta.DeletionHandlingAccessor(obj); err == nil {
+			impl.EnqueueKey(object.GetNamespace())
+		}
+	}))
+
 	return impl
 }
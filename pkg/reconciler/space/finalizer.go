@@ -0,0 +1,186 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package space
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/reconciler"
+	"github.com/google/kf/pkg/reconciler/space/resources"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"knative.dev/pkg/logging"
+)
+
+const (
+	// finalizerName is set on Spaces so deleting them waits for the Apps and
+	// routes in the Space to be cleaned up before the namespace is removed.
+	finalizerName = "spaces.kf.dev"
+
+	// deletionPollInterval is how often a Space that's being deleted checks
+	// whether its dependents are gone.
+	deletionPollInterval = 5 * time.Second
+)
+
+// ensureFinalizer adds the finalizer to the Space if it's missing.
+func (r *Reconciler) ensureFinalizer(space *v1alpha1.Space) error {
+	patch, err := reconciler.AddFinalizerPatch(space, finalizerName)
+	if err != nil || patch == nil {
+		return err
+	}
+
+	_, err = r.KfClientSet.KfV1alpha1().Spaces().Patch(space.Name, types.MergePatchType, patch)
+	return err
+}
+
+// finalize cleans up the contents of a Space that's being deleted then
+// removes the finalizer so the namespace can be garbage collected. Until
+// then, the Ready condition reports what the deletion is waiting on.
+func (r *Reconciler) finalize(ctx context.Context, original *v1alpha1.Space) error {
+	if !reconciler.HasFinalizer(original, finalizerName) {
+		return nil
+	}
+
+	logger := logging.FromContext(ctx)
+
+	// Don't modify the informers copy
+	toFinalize := original.DeepCopy()
+
+	waitingOn, err := r.cleanUp(ctx, toFinalize)
+	if err != nil {
+		return err
+	}
+
+	if waitingOn != "" {
+		toFinalize.Status.MarkDeleting(fmt.Sprintf("Waiting for %s to be deleted", waitingOn))
+		if _, err := r.updateStatus(toFinalize); err != nil {
+			return err
+		}
+
+		if r.enqueueAfter != nil {
+			r.enqueueAfter(original, deletionPollInterval)
+		}
+
+		return nil
+	}
+
+	logger.Infof("space %q cleaned up, removing finalizer", original.Name)
+
+	patch, err := reconciler.RemoveFinalizerPatch(original, finalizerName)
+	if err != nil || patch == nil {
+		return err
+	}
+
+	_, err = r.KfClientSet.KfV1alpha1().Spaces().Patch(original.Name, types.MergePatchType, patch)
+	if apierrs.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// cleanUp deletes the contents of the Space in order and returns a
+// description of what's still being deleted. Apps are deleted first so their
+// finalizers can remove their routes and registry images while the namespace
+// is still active, then the RouteClaims and finally any VirtualServices left
+// for the Space.
+func (r *Reconciler) cleanUp(ctx context.Context, space *v1alpha1.Space) (string, error) {
+	logger := logging.FromContext(ctx)
+	namespace := resources.NamespaceName(space)
+
+	// Apps
+	{
+		logger.Debug("deleting Apps")
+		apps, err := r.appLister.Apps(namespace).List(labels.Everything())
+		if err != nil {
+			return "", err
+		}
+
+		for _, app := range apps {
+			if app.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			if err := r.KfClientSet.
+				KfV1alpha1().
+				Apps(app.Namespace).
+				Delete(app.Name, &metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return "", err
+			}
+		}
+
+		if len(apps) > 0 {
+			return fmt.Sprintf("%d app(s)", len(apps)), nil
+		}
+	}
+
+	// RouteClaims
+	{
+		logger.Debug("deleting RouteClaims")
+		claims, err := r.routeClaimLister.RouteClaims(namespace).List(labels.Everything())
+		if err != nil {
+			return "", err
+		}
+
+		for _, claim := range claims {
+			if claim.GetDeletionTimestamp() != nil {
+				continue
+			}
+
+			if err := r.KfClientSet.
+				KfV1alpha1().
+				RouteClaims(claim.Namespace).
+				Delete(claim.Name, &metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return "", err
+			}
+		}
+
+		if len(claims) > 0 {
+			return fmt.Sprintf("%d route claim(s)", len(claims)), nil
+		}
+	}
+
+	// VirtualServices live in the kf namespace so they aren't removed with
+	// the Space's namespace. If another Space claims the same host, its
+	// Route reconciler recreates the VirtualService.
+	{
+		logger.Debug("deleting VirtualServices")
+		virtualServices, err := r.virtualServiceLister.
+			VirtualServices(v1alpha1.KfNamespace).
+			List(labels.Everything())
+		if err != nil {
+			return "", err
+		}
+
+		for _, vs := range virtualServices {
+			if vs.GetAnnotations()["space"] != namespace {
+				continue
+			}
+
+			if err := r.SharedClientSet.
+				Networking().
+				VirtualServices(vs.Namespace).
+				Delete(vs.Name, &metav1.DeleteOptions{}); err != nil && !apierrs.IsNotFound(err) {
+				return "", err
+			}
+		}
+	}
+
+	return "", nil
+}
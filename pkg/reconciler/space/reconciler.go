@@ -18,11 +18,17 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/google/kf/pkg/apis/kf/v1alpha1"
 	kflisters "github.com/google/kf/pkg/client/listers/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/clusterdefaults"
+	"github.com/google/kf/pkg/kf/tracing"
 	"github.com/google/kf/pkg/reconciler"
 	"github.com/google/kf/pkg/reconciler/space/resources"
+	"github.com/google/kf/pkg/system"
+	buildclient "github.com/google/kf/third_party/knative-build/pkg/client/clientset/versioned/typed/build/v1alpha1"
+	buildlisters "github.com/google/kf/third_party/knative-build/pkg/client/listers/build/v1alpha1"
 	"go.uber.org/zap"
 	v1 "k8s.io/api/core/v1"
 	rv1 "k8s.io/api/rbac/v1"
@@ -30,9 +36,16 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
 	v1listers "k8s.io/client-go/listers/core/v1"
+	networkingv1listers "k8s.io/client-go/listers/networking/v1"
 	rbacv1listers "k8s.io/client-go/listers/rbac/v1"
 	"k8s.io/client-go/tools/cache"
+	networking "knative.dev/pkg/apis/istio/v1alpha3"
+	istiolisters "knative.dev/pkg/client/listers/istio/v1alpha3"
 	"knative.dev/pkg/controller"
 	"knative.dev/pkg/kmp"
 	"knative.dev/pkg/logging"
@@ -48,13 +61,38 @@ type Reconciler struct {
 	roleLister          rbacv1listers.RoleLister
 	resourceQuotaLister v1listers.ResourceQuotaLister
 	limitRangeLister    v1listers.LimitRangeLister
+	gatewayLister       istiolisters.GatewayLister
+	networkPolicyLister networkingv1listers.NetworkPolicyLister
+	buildLister         buildlisters.BuildLister
+
+	// listers used to clean up the contents of deleted Spaces
+	appLister            kflisters.AppLister
+	routeClaimLister     kflisters.RouteClaimLister
+	virtualServiceLister istiolisters.VirtualServiceLister
+
+	// buildClient creates the Builds that compose custom builders.
+	buildClient buildclient.BuildV1alpha1Interface
+
+	// dynamicClient manages the Istio egress resources Kf has no typed
+	// client for.
+	dynamicClient dynamic.Interface
+
+	// defaultsStore holds the build defaults for Spaces that don't set their
+	// own.
+	defaultsStore *clusterdefaults.Store
+
+	// enqueueAfter schedules the Space to be reconciled again after a delay.
+	enqueueAfter func(obj interface{}, after time.Duration)
 }
 
 // Check that our Reconciler implements controller.Reconciler
 var _ controller.Reconciler = (*Reconciler)(nil)
 
 // Reconcile is called by Kubernetes.
-func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
+func (r *Reconciler) Reconcile(ctx context.Context, key string) (err error) {
+	ctx, span := tracing.StartReconcileSpan(ctx, "Space", key)
+	defer func() { tracing.EndSpan(span, err) }()
+
 	logger := logging.FromContext(ctx)
 	_, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
@@ -71,7 +109,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
 		return err
 
 	case original.GetDeletionTimestamp() != nil:
-		return nil
+		return r.finalize(ctx, original)
+	}
+
+	if err := r.ensureFinalizer(original); err != nil {
+		return err
 	}
 
 	// Don't modify the informers copy
@@ -80,6 +122,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, key string) error {
 	// Reconcile this copy of the service and then write back any status
 	// updates regardless of whether the reconciliation errored out.
 	reconcileErr := r.ApplyChanges(ctx, toReconcile)
+
+	// Surface failures users can act on as Events on the object.
+	r.RecordStatusChanges(toReconcile, original.Status.Status, toReconcile.Status.Status)
+	r.RecordReconcileError(toReconcile, reconcileErr)
+
 	if equality.Semantic.DeepEqual(original.Status, toReconcile.Status) {
 		// If we didn't change anything then don't call updateStatus.
 		// This is important because the copy we loaded from the informer's
@@ -101,6 +148,11 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, space *v1alpha1.Space) er
 	space.Status.InitializeConditions()
 	namespaceName := resources.NamespaceName(space)
 
+	space.Status.PropagateBuildpackBuildDefaults(
+		space.Spec.BuildpackBuild,
+		r.defaultsStore.Load().SpaceBuildpackBuild(),
+	)
+
 	// Sync Namespace
 	{
 		logger.Debug("reconciling Namespace")
@@ -240,6 +292,113 @@ func (r *Reconciler) ApplyChanges(ctx context.Context, space *v1alpha1.Space) er
 		space.Status.PropagateLimitRangeStatus(actual)
 	}
 
+	// Sync Gateway
+	{
+		logger.Debug("reconciling Gateway")
+		desired, err := resources.MakeGateway(space)
+		if err != nil {
+			return err
+		}
+
+		gatewayName := resources.GatewayName(space)
+		actual, err := r.gatewayLister.Gateways(v1alpha1.KfNamespace).Get(gatewayName)
+		switch {
+		case errors.IsNotFound(err) && desired == nil:
+			// No domains need TLS and there's nothing to clean up.
+
+		case errors.IsNotFound(err):
+			if _, err := r.SharedClientSet.Networking().Gateways(desired.Namespace).Create(desired); err != nil {
+				return err
+			}
+
+		case err != nil:
+			return err
+
+		case !metav1.IsControlledBy(actual, space):
+			return fmt.Errorf("space: %q does not own gateway: %q", space.Name, gatewayName)
+
+		case desired == nil:
+			err := r.SharedClientSet.Networking().Gateways(actual.Namespace).Delete(gatewayName, &metav1.DeleteOptions{})
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+
+		default:
+			if _, err := r.reconcileGateway(desired, actual); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Sync egress policy
+	{
+		logger.Debug("reconciling egress ServiceEntries")
+		desired, err := resources.MakeEgressServiceEntries(space)
+		if err != nil {
+			return err
+		}
+
+		if err := r.reconcileEgress(space, resources.ServiceEntryResource, desired); err != nil {
+			return err
+		}
+
+		logger.Debug("reconciling egress Sidecar")
+		var sidecars []*unstructured.Unstructured
+		if sidecar := resources.MakeEgressSidecar(space); sidecar != nil {
+			sidecars = append(sidecars, sidecar)
+		}
+
+		if err := r.reconcileEgress(space, resources.SidecarResource, sidecars); err != nil {
+			return err
+		}
+	}
+
+	// Sync custom builder
+	{
+		logger.Debug("reconciling custom builder")
+		switch {
+		case len(space.Spec.BuildpackBuild.Buildpacks) == 0:
+			space.Status.MarkNoCustomBuilder()
+
+		case resources.CustomBuilderImage(space) == "":
+			space.Status.MarkCustomBuilderNoRegistry()
+
+		default:
+			desired, err := resources.MakeCustomBuilderBuild(space, system.BuilderComposerImage())
+			if err != nil {
+				return err
+			}
+
+			// Builds are immutable, a change to the buildpacks or base builder
+			// gets a new Build name so the old one is left as is.
+			actual, err := r.buildLister.Builds(desired.Namespace).Get(desired.Name)
+			if errors.IsNotFound(err) {
+				actual, err = r.buildClient.Builds(desired.Namespace).Create(desired)
+				if err != nil {
+					return err
+				}
+			} else if err != nil {
+				return err
+			} else if !metav1.IsControlledBy(actual, space) {
+				space.Status.MarkCustomBuilderBuildNotOwned(desired.Name)
+				return fmt.Errorf("space: %q does not own build: %q", space.Name, desired.Name)
+			}
+
+			space.Status.PropagateCustomBuilderStatus(actual, resources.CustomBuilderImage(space))
+		}
+	}
+
+	// Check for drift Kf reports but doesn't fix
+	{
+		logger.Debug("checking NetworkPolicies")
+		actual, err := r.networkPolicyLister.NetworkPolicies(namespaceName).List(labels.Everything())
+		if err != nil {
+			return err
+		}
+
+		space.Status.PropagateNetworkPolicyDrift(resources.MissingNetworkPolicies(space, actual))
+	}
+
 	return nil
 }
 
@@ -265,6 +424,62 @@ func (r *Reconciler) reconcileNs(desired, actual *v1.Namespace) (*v1.Namespace,
 	return r.KubeClientSet.CoreV1().Namespaces().Update(existing)
 }
 
+// reconcileEgress makes the Kf managed egress objects of the given resource in
+// the space's namespace match the desired ones. There are no informers for
+// these resources so the API server is queried directly.
+func (r *Reconciler) reconcileEgress(space *v1alpha1.Space, gvr schema.GroupVersionResource, desired []*unstructured.Unstructured) error {
+	client := r.dynamicClient.Resource(gvr).Namespace(resources.NamespaceName(space))
+
+	actual, err := client.List(metav1.ListOptions{LabelSelector: resources.EgressSelector()})
+	switch {
+	case errors.IsNotFound(err) && len(desired) == 0:
+		// The resource isn't installed and the space doesn't need it.
+		return nil
+
+	case err != nil:
+		return err
+	}
+
+	existing := make(map[string]*unstructured.Unstructured)
+	for i := range actual.Items {
+		if item := &actual.Items[i]; metav1.IsControlledBy(item, space) {
+			existing[item.GetName()] = item
+		}
+	}
+
+	for _, want := range desired {
+		have, ok := existing[want.GetName()]
+		delete(existing, want.GetName())
+
+		switch {
+		case !ok:
+			if _, err := client.Create(want, metav1.CreateOptions{}); err != nil {
+				return err
+			}
+
+		case !equality.Semantic.DeepEqual(want.GetLabels(), have.GetLabels()) ||
+			!equality.Semantic.DeepEqual(want.Object["spec"], have.Object["spec"]):
+			// Preserve the rest of the object (e.g. ObjectMeta except for labels).
+			update := have.DeepCopy()
+			update.SetLabels(want.GetLabels())
+			update.Object["spec"] = want.Object["spec"]
+			if _, err := client.Update(update, metav1.UpdateOptions{}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Anything left over was removed from the policy.
+	for name := range existing {
+		err := client.Delete(name, &metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *Reconciler) reconcileGenericRole(desired, actual *rv1.Role) (*rv1.Role, error) {
 	// Check for differences, if none we don't need to reconcile.
 	semanticEqual := equality.Semantic.DeepEqual(desired.ObjectMeta.Labels, actual.ObjectMeta.Labels)
@@ -331,6 +546,28 @@ func (r *Reconciler) reconcileLimitRange(desired, actual *v1.LimitRange) (*v1.Li
 	return r.KubeClientSet.CoreV1().LimitRanges(existing.Namespace).Update(existing)
 }
 
+func (r *Reconciler) reconcileGateway(desired, actual *networking.Gateway) (*networking.Gateway, error) {
+	// Check for differences, if none we don't need to reconcile.
+	semanticEqual := equality.Semantic.DeepEqual(desired.ObjectMeta.Labels, actual.ObjectMeta.Labels)
+	semanticEqual = semanticEqual && equality.Semantic.DeepEqual(desired.Spec, actual.Spec)
+
+	if semanticEqual {
+		return actual, nil
+	}
+
+	if _, err := kmp.SafeDiff(desired.Spec, actual.Spec); err != nil {
+		return nil, fmt.Errorf("failed to diff Spec (Gateway): %v", err)
+	}
+
+	// Don't modify the informers copy.
+	existing := actual.DeepCopy()
+
+	// Preserve the rest of the object (e.g. ObjectMeta except for labels).
+	existing.ObjectMeta.Labels = desired.ObjectMeta.Labels
+	existing.Spec = desired.Spec
+	return r.SharedClientSet.Networking().Gateways(existing.Namespace).Update(existing)
+}
+
 func (r *Reconciler) updateStatus(desired *v1alpha1.Space) (*v1alpha1.Space, error) {
 	actual, err := r.spaceLister.Get(desired.Name)
 	if err != nil {
@@ -0,0 +1,138 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	build "github.com/google/kf/third_party/knative-build/pkg/apis/build/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	componentLabel = "app.kubernetes.io/component"
+
+	// customBuilderComponent labels the Builds that compose custom builders.
+	customBuilderComponent = "custom-builder"
+
+	// buildpacksDir is where buildpacks fetched from Git are cloned.
+	buildpacksDir = "/workspace/buildpacks"
+
+	// gitImage is used to clone buildpacks.
+	gitImage = "alpine/git"
+)
+
+// customBuilderHash identifies the inputs of the space's custom builder. It
+// changes whenever the base builder or the buildpacks do.
+func customBuilderHash(space *v1alpha1.Space) string {
+	inputs, _ := json.Marshal(struct {
+		Base       string                    `json:"base"`
+		Buildpacks []v1alpha1.SpaceBuildpack `json:"buildpacks"`
+	}{
+		Base:       space.BaseBuilderImage(),
+		Buildpacks: space.Spec.BuildpackBuild.Buildpacks,
+	})
+
+	return fmt.Sprintf("%x", sha256.Sum256(inputs))[:12]
+}
+
+// CustomBuilderBuildName gets the name of the Build that composes the space's
+// custom builder.
+func CustomBuilderBuildName(space *v1alpha1.Space) string {
+	return "kf-builder-" + customBuilderHash(space)
+}
+
+// CustomBuilderImage gets the image the space's custom builder is pushed to.
+// It's blank if the space has no container registry.
+func CustomBuilderImage(space *v1alpha1.Space) string {
+	registry := space.EffectiveContainerRegistry()
+	if registry == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("%s/kf-builder-%s:%s", strings.TrimSuffix(registry, "/"), space.Name, customBuilderHash(space))
+}
+
+// MakeCustomBuilderBuild creates a Build that adds the space's custom
+// buildpacks to its base builder. Buildpacks from Git are cloned first, then
+// the composer image fetches the ones from images and pushes the new builder.
+func MakeCustomBuilderBuild(space *v1alpha1.Space, composerImage string) (*build.Build, error) {
+	image := CustomBuilderImage(space)
+	if image == "" {
+		return nil, fmt.Errorf("space %q has no container registry for its builder", space.Name)
+	}
+
+	var steps []corev1.Container
+	composeArgs := []string{
+		"--base", space.BaseBuilderImage(),
+		"--image", image,
+	}
+
+	for _, bp := range space.Spec.BuildpackBuild.Buildpacks {
+		if bp.Git == "" {
+			composeArgs = append(composeArgs, bp.Image)
+			continue
+		}
+
+		url, ref := bp.GitURLAndRef()
+		dir := path.Join(buildpacksDir, bp.Name)
+		steps = append(steps, corev1.Container{
+			Name:    "fetch-" + bp.Name,
+			Image:   gitImage,
+			Command: []string{"/bin/sh"},
+			Args: []string{
+				"-c",
+				`git clone --quiet "${GIT_URL}" "${DIR}" && if [ -n "${GIT_REF}" ]; then git -C "${DIR}" checkout --quiet "${GIT_REF}"; fi`,
+			},
+			Env: []corev1.EnvVar{
+				{Name: "GIT_URL", Value: url},
+				{Name: "GIT_REF", Value: ref},
+				{Name: "DIR", Value: dir},
+			},
+		})
+		composeArgs = append(composeArgs, dir)
+	}
+
+	steps = append(steps, corev1.Container{
+		Name:  "compose",
+		Image: composerImage,
+		Args:  composeArgs,
+	})
+
+	return &build.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      CustomBuilderBuildName(space),
+			Namespace: NamespaceName(space),
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(space),
+			},
+			Labels: map[string]string{
+				managedByLabel: "kf",
+				componentLabel: customBuilderComponent,
+			},
+		},
+		Spec: build.BuildSpec{
+			ServiceAccountName: space.Spec.Security.BuildServiceAccount,
+			Steps:              steps,
+		},
+	}, nil
+}
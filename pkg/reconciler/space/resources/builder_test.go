@@ -0,0 +1,126 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func customBuilderSpace() *v1alpha1.Space {
+	space := &v1alpha1.Space{}
+	space.Name = "my-space"
+	space.Spec.Security.BuildServiceAccount = "kf-builder"
+	space.Spec.BuildpackBuild.ContainerRegistry = "gcr.io/my-project"
+	space.Spec.BuildpackBuild.BuilderImage = "gcr.io/my-project/builder"
+	space.Spec.BuildpackBuild.Buildpacks = []v1alpha1.SpaceBuildpack{
+		{Name: "node", Git: "https://github.com/example/node-buildpack@v1.2.0"},
+		{Name: "java", Image: "gcr.io/example/java-buildpack:v2"},
+	}
+	return space
+}
+
+func ExampleMakeCustomBuilderBuild() {
+	space := customBuilderSpace()
+
+	b, err := MakeCustomBuilderBuild(space, "gcr.io/kf-releases/builder-composer")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Namespace:", b.Namespace)
+	fmt.Println("Service account:", b.Spec.ServiceAccountName)
+	for _, step := range b.Spec.Steps {
+		fmt.Println("Step:", step.Name, step.Image)
+	}
+
+	compose := b.Spec.Steps[len(b.Spec.Steps)-1]
+	fmt.Println("Base:", compose.Args[1])
+	fmt.Println("Buildpacks:", strings.Join(compose.Args[4:], " "))
+
+	fetch := b.Spec.Steps[0]
+	for _, env := range fetch.Env {
+		fmt.Printf("Fetch %s=%s\n", env.Name, env.Value)
+	}
+
+	// Output: Namespace: my-space
+	// Service account: kf-builder
+	// Step: fetch-node alpine/git
+	// Step: compose gcr.io/kf-releases/builder-composer
+	// Base: gcr.io/my-project/builder
+	// Buildpacks: /workspace/buildpacks/node gcr.io/example/java-buildpack:v2
+	// Fetch GIT_URL=https://github.com/example/node-buildpack
+	// Fetch GIT_REF=v1.2.0
+	// Fetch DIR=/workspace/buildpacks/node
+}
+
+func TestMakeCustomBuilderBuild_noRegistry(t *testing.T) {
+	space := customBuilderSpace()
+	space.Spec.BuildpackBuild.ContainerRegistry = ""
+
+	_, err := MakeCustomBuilderBuild(space, "gcr.io/kf-releases/builder-composer")
+
+	testutil.AssertErrorsEqual(t, fmt.Errorf(`space "my-space" has no container registry for its builder`), err)
+}
+
+func TestCustomBuilderBuildName(t *testing.T) {
+	original := CustomBuilderBuildName(customBuilderSpace())
+
+	cases := map[string]struct {
+		mutate      func(*v1alpha1.Space)
+		wantChanged bool
+	}{
+		"unchanged": {
+			mutate: func(*v1alpha1.Space) {},
+		},
+		"unrelated setting": {
+			mutate: func(s *v1alpha1.Space) {
+				s.Spec.BuildpackBuild.DefaultStack = "cflinuxfs3"
+			},
+		},
+		"buildpack ref": {
+			mutate: func(s *v1alpha1.Space) {
+				s.Spec.BuildpackBuild.Buildpacks[0].Git = "https://github.com/example/node-buildpack@v1.3.0"
+			},
+			wantChanged: true,
+		},
+		"buildpack order": {
+			mutate: func(s *v1alpha1.Space) {
+				bps := s.Spec.BuildpackBuild.Buildpacks
+				bps[0], bps[1] = bps[1], bps[0]
+			},
+			wantChanged: true,
+		},
+		"base builder": {
+			mutate: func(s *v1alpha1.Space) {
+				s.Spec.BuildpackBuild.BuilderImage = "gcr.io/my-project/builder:v2"
+			},
+			wantChanged: true,
+		},
+	}
+
+	for tn, tc := range cases {
+		t.Run(tn, func(t *testing.T) {
+			space := customBuilderSpace()
+			tc.mutate(space)
+
+			testutil.AssertEqual(t, "changed", tc.wantChanged, CustomBuilderBuildName(space) != original)
+		})
+	}
+}
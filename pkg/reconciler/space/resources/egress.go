@@ -0,0 +1,184 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/knative/serving/pkg/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"knative.dev/pkg/kmeta"
+)
+
+const (
+	// EgressLabel is set on every ServiceEntry and Sidecar Kf creates to
+	// enforce a Space's egress policy.
+	EgressLabel = "kf.dev/egress"
+
+	// EgressSidecarName is the name of the Sidecar that blocks egress to
+	// unregistered destinations.
+	EgressSidecarName = "kf-egress"
+)
+
+var (
+	// ServiceEntryResource is the Istio ServiceEntry resource. The Istio
+	// client Kf builds against doesn't include ServiceEntries so they're
+	// managed as unstructured objects.
+	ServiceEntryResource = schema.GroupVersionResource{
+		Group:    "networking.istio.io",
+		Version:  "v1alpha3",
+		Resource: "serviceentries",
+	}
+
+	// SidecarResource is the Istio Sidecar resource.
+	SidecarResource = schema.GroupVersionResource{
+		Group:    "networking.istio.io",
+		Version:  "v1alpha3",
+		Resource: "sidecars",
+	}
+)
+
+// EgressSelector selects the egress resources Kf manages in a namespace.
+func EgressSelector() string {
+	return fmt.Sprintf("%s=kf,%s=true", managedByLabel, EgressLabel)
+}
+
+// MakeEgressServiceEntries creates one Istio ServiceEntry per port in the
+// space's egress allow list. Each ServiceEntry registers the hosts allowed on
+// that port with the mesh so they stay reachable when unregistered traffic is
+// blocked.
+func MakeEgressServiceEntries(space *v1alpha1.Space) ([]*unstructured.Unstructured, error) {
+	hostsByPort := make(map[int32][]string)
+	for _, dest := range space.Spec.Security.Egress.Allow {
+		host, port, err := v1alpha1.ParseEgressDestination(dest)
+		if err != nil {
+			return nil, err
+		}
+
+		hostsByPort[port] = append(hostsByPort[port], host)
+	}
+
+	var ports []int32
+	for port := range hostsByPort {
+		ports = append(ports, port)
+	}
+	sort.Slice(ports, func(i, j int) bool { return ports[i] < ports[j] })
+
+	var entries []*unstructured.Unstructured
+	for _, port := range ports {
+		var hosts []interface{}
+		resolution := "DNS"
+		for _, host := range uniqueSorted(hostsByPort[port]) {
+			hosts = append(hosts, host)
+
+			// Wildcard hosts can't be resolved, so Envoy forwards to the
+			// address the App connected to.
+			if strings.HasPrefix(host, "*.") {
+				resolution = "NONE"
+			}
+		}
+
+		protocol := egressProtocol(port)
+		entry := makeEgressObject(space, "ServiceEntry", fmt.Sprintf("kf-egress-%d", port))
+		entry.Object["spec"] = map[string]interface{}{
+			"hosts": hosts,
+			"ports": []interface{}{
+				map[string]interface{}{
+					"number":   int64(port),
+					"name":     fmt.Sprintf("%s-%d", strings.ToLower(protocol), port),
+					"protocol": protocol,
+				},
+			},
+			"location":   "MESH_EXTERNAL",
+			"resolution": resolution,
+			"exportTo":   []interface{}{"."},
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// MakeEgressSidecar creates an Istio Sidecar that only lets Apps in the space
+// reach destinations registered with the mesh. If the space doesn't deny
+// egress nil is returned.
+func MakeEgressSidecar(space *v1alpha1.Space) *unstructured.Unstructured {
+	if !space.Spec.Security.Egress.DenyAll {
+		return nil
+	}
+
+	sidecar := makeEgressObject(space, "Sidecar", EgressSidecarName)
+	sidecar.Object["spec"] = map[string]interface{}{
+		"egress": []interface{}{
+			map[string]interface{}{
+				"hosts": []interface{}{"*/*"},
+			},
+		},
+		"outboundTrafficPolicy": map[string]interface{}{
+			"mode": "REGISTRY_ONLY",
+		},
+	}
+
+	return sidecar
+}
+
+func makeEgressObject(space *v1alpha1.Space, kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetAPIVersion("networking.istio.io/v1alpha3")
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace(NamespaceName(space))
+	obj.SetOwnerReferences([]metav1.OwnerReference{
+		*kmeta.NewControllerRef(space),
+	})
+	obj.SetLabels(resources.UnionMaps(space.GetLabels(), map[string]string{
+		managedByLabel: "kf",
+		EgressLabel:    "true",
+	}))
+
+	return obj
+}
+
+// egressProtocol picks the Istio protocol for traffic to a well known port,
+// anything else is treated as opaque TCP.
+func egressProtocol(port int32) string {
+	switch port {
+	case 80:
+		return "HTTP"
+	case 443:
+		return "TLS"
+	default:
+		return "TCP"
+	}
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func ExampleMakeEgressServiceEntries() {
+	space := &v1alpha1.Space{}
+	space.Name = "my-space"
+	space.Spec.Security.Egress.Allow = []string{
+		"api.stripe.com:443",
+		"*.googleapis.com:443",
+		"api.stripe.com:443",
+		"db.example.com:5432",
+	}
+
+	entries, err := MakeEgressServiceEntries(space)
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		fmt.Println("Name:", entry.GetName())
+		fmt.Println("Namespace:", entry.GetNamespace())
+		fmt.Println("Managed by:", entry.GetLabels()[managedByLabel])
+		fmt.Println("Hosts:", entry.Object["spec"].(map[string]interface{})["hosts"])
+		fmt.Println("Ports:", entry.Object["spec"].(map[string]interface{})["ports"])
+		fmt.Println("Resolution:", entry.Object["spec"].(map[string]interface{})["resolution"])
+	}
+
+	// Output: Name: kf-egress-443
+	// Namespace: my-space
+	// Managed by: kf
+	// Hosts: [*.googleapis.com api.stripe.com]
+	// Ports: [map[name:tls-443 number:443 protocol:TLS]]
+	// Resolution: NONE
+	// Name: kf-egress-5432
+	// Namespace: my-space
+	// Managed by: kf
+	// Hosts: [db.example.com]
+	// Ports: [map[name:tcp-5432 number:5432 protocol:TCP]]
+	// Resolution: DNS
+}
+
+func ExampleMakeEgressSidecar() {
+	space := &v1alpha1.Space{}
+	space.Name = "my-space"
+	space.Spec.Security.Egress.DenyAll = true
+
+	sidecar := MakeEgressSidecar(space)
+
+	fmt.Println("Name:", sidecar.GetName())
+	fmt.Println("Namespace:", sidecar.GetNamespace())
+	fmt.Println("Egress label:", sidecar.GetLabels()[EgressLabel])
+	fmt.Println("Spec:", sidecar.Object["spec"])
+
+	// Output: Name: kf-egress
+	// Namespace: my-space
+	// Egress label: true
+	// Spec: map[egress:[map[hosts:[*/*]]] outboundTrafficPolicy:map[mode:REGISTRY_ONLY]]
+}
+
+func TestMakeEgressServiceEntries_invalidDestination(t *testing.T) {
+	space := &v1alpha1.Space{}
+	space.Name = "my-space"
+	space.Spec.Security.Egress.Allow = []string{"api.stripe.com"}
+
+	entries, err := MakeEgressServiceEntries(space)
+	testutil.AssertErrorsEqual(t, errors.New("destination must be in the form HOST:PORT"), err)
+	testutil.AssertEqual(t, "entries", 0, len(entries))
+}
+
+func TestMakeEgressSidecar_allowAll(t *testing.T) {
+	space := &v1alpha1.Space{}
+	space.Name = "my-space"
+	space.Spec.Security.Egress.Allow = []string{"api.stripe.com:443"}
+
+	sidecar := MakeEgressSidecar(space)
+	testutil.AssertEqual(t, "sidecar", true, sidecar == nil)
+}
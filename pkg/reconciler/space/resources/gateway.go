@@ -0,0 +1,101 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/knative/serving/pkg/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	networking "knative.dev/pkg/apis/istio/v1alpha3"
+	"knative.dev/pkg/kmeta"
+)
+
+// GatewayName gets the name of the Istio Gateway given the space.
+func GatewayName(space *v1alpha1.Space) string {
+	return v1alpha1.SpaceGatewayName(space.Name)
+}
+
+// MakeGateway creates an Istio Gateway with server blocks for every domain in
+// the space that serves HTTPS or redirects HTTP to HTTPS. The Gateway lives
+// in the Kf namespace alongside the VirtualServices that bind to it. If no
+// domains need a server block nil is returned.
+func MakeGateway(space *v1alpha1.Space) (*networking.Gateway, error) {
+	var servers []networking.Server
+
+	for i, domain := range space.Spec.Execution.Domains {
+		// Serve the domain and every hostname routes can be created on.
+		hosts := []string{domain.Domain, "*." + domain.Domain}
+
+		if domain.ServesHTTPS() {
+			servers = append(servers, networking.Server{
+				Hosts: hosts,
+				Port: networking.Port{
+					Name:     fmt.Sprintf("https-%d", i),
+					Number:   443,
+					Protocol: networking.ProtocolHTTPS,
+				},
+				TLS: &networking.TLSOptions{
+					Mode:              networking.TLSModeSimple,
+					ServerCertificate: "tls.crt",
+					PrivateKey:        "tls.key",
+					CredentialName:    domain.TLSSecretName,
+				},
+			})
+		}
+
+		if domain.RedirectHTTPS {
+			servers = append(servers, networking.Server{
+				Hosts: hosts,
+				Port: networking.Port{
+					Name:     fmt.Sprintf("http-%d", i),
+					Number:   80,
+					Protocol: networking.ProtocolHTTP,
+				},
+				TLS: &networking.TLSOptions{
+					HTTPSRedirect: true,
+				},
+			})
+		}
+	}
+
+	if len(servers) == 0 {
+		return nil, nil
+	}
+
+	return &networking.Gateway{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "networking.istio.io/v1alpha3",
+			Kind:       "Gateway",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GatewayName(space),
+			Namespace: v1alpha1.KfNamespace,
+			OwnerReferences: []metav1.OwnerReference{
+				*kmeta.NewControllerRef(space),
+			},
+			Labels: resources.UnionMaps(space.GetLabels(), map[string]string{
+				managedByLabel: "kf",
+			}),
+		},
+		Spec: networking.GatewaySpec{
+			Selector: map[string]string{
+				"istio": "ingressgateway",
+			},
+			Servers: servers,
+		},
+	}, nil
+}
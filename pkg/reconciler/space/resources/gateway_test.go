@@ -0,0 +1,65 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	"github.com/google/kf/pkg/kf/testutil"
+)
+
+func ExampleMakeGateway() {
+	space := &v1alpha1.Space{}
+	space.Name = "my-space"
+	space.Spec.Execution.Domains = []v1alpha1.SpaceDomain{
+		{Domain: "example.com", Default: true},
+		{Domain: "secure.example.com", TLSSecretName: "secure-cert", RedirectHTTPS: true},
+	}
+
+	gateway, err := MakeGateway(space)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println("Name:", gateway.Name)
+	fmt.Println("Namespace:", gateway.Namespace)
+	fmt.Println("Managed by:", gateway.Labels[managedByLabel])
+	for _, server := range gateway.Spec.Servers {
+		fmt.Println("Server:", server.Port.Name, server.Port.Protocol, server.Hosts)
+		fmt.Println("  Credential:", server.TLS.CredentialName, "Redirect:", server.TLS.HTTPSRedirect)
+	}
+
+	// Output: Name: space-gateway-my-space
+	// Namespace: kf
+	// Managed by: kf
+	// Server: https-1 HTTPS [secure.example.com *.secure.example.com]
+	//   Credential: secure-cert Redirect: false
+	// Server: http-1 HTTP [secure.example.com *.secure.example.com]
+	//   Credential:  Redirect: true
+}
+
+func TestMakeGateway_noTLSDomains(t *testing.T) {
+	space := &v1alpha1.Space{}
+	space.Name = "my-space"
+	space.Spec.Execution.Domains = []v1alpha1.SpaceDomain{
+		{Domain: "example.com", Default: true},
+	}
+
+	gateway, err := MakeGateway(space)
+	testutil.AssertNil(t, "err", err)
+	testutil.AssertEqual(t, "gateway is nil", true, gateway == nil)
+}
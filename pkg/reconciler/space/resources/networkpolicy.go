@@ -0,0 +1,39 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+// MissingNetworkPolicies returns the names of the space's required
+// NetworkPolicies that aren't in the list of existing policies, in the order
+// they're required.
+func MissingNetworkPolicies(space *v1alpha1.Space, existing []*networkingv1.NetworkPolicy) []string {
+	found := make(map[string]bool)
+	for _, policy := range existing {
+		found[policy.Name] = true
+	}
+
+	var missing []string
+	for _, name := range space.Spec.Security.RequiredNetworkPolicies {
+		if !found[name] {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,35 @@
+// Copyright 2019 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package resources
+
+import (
+	"fmt"
+
+	"github.com/google/kf/pkg/apis/kf/v1alpha1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func ExampleMissingNetworkPolicies() {
+	space := &v1alpha1.Space{}
+	space.Name = "my-space"
+	space.Spec.Security.RequiredNetworkPolicies = []string{"deny-all", "allow-gateway", "allow-dns"}
+
+	existing := []*networkingv1.NetworkPolicy{{}}
+	existing[0].Name = "allow-gateway"
+
+	fmt.Println("Missing:", MissingNetworkPolicies(space, existing))
+
+	// Output: Missing: [deny-all allow-dns]
+}
@@ -114,6 +114,34 @@ func developerPolicyRules(space *v1alpha1.Space) []v1.PolicyRule {
 		})
 	}
 
+	if space.Spec.Security.EnableSSH {
+		out = append(out,
+			// Open shells in App instances.
+			v1.PolicyRule{
+				APIGroups: []string{""}, // "" is the builtin API group
+				Verbs:     []string{"create"},
+				Resources: []string{"pods/exec"},
+			},
+			// Run ephemeral debug containers in App instances.
+			v1.PolicyRule{
+				APIGroups: []string{""}, // "" is the builtin API group
+				Verbs:     []string{"patch"},
+				Resources: []string{"pods/ephemeralcontainers"},
+			},
+			v1.PolicyRule{
+				APIGroups: []string{""}, // "" is the builtin API group
+				Verbs:     []string{"create"},
+				Resources: []string{"pods/attach"},
+			},
+			// Record an audit Event when a shell is opened.
+			v1.PolicyRule{
+				APIGroups: []string{""}, // "" is the builtin API group
+				Verbs:     []string{"create"},
+				Resources: []string{"events"},
+			},
+		)
+	}
+
 	return out
 }
 
@@ -69,6 +69,23 @@ func TestMakeDeveloperRole(t *testing.T) {
 			Space: v1alpha1.Space{},
 			Assert: func(t *testing.T, role *v1.Role) {
 				assertNotAllowed(t, role, "get", "", "pods/log")
+				assertNotAllowed(t, role, "create", "", "pods/exec")
+				assertNotAllowed(t, role, "patch", "", "pods/ephemeralcontainers")
+			},
+		},
+		"space allows SSH": {
+			Space: v1alpha1.Space{
+				Spec: v1alpha1.SpaceSpec{
+					Security: v1alpha1.SpaceSpecSecurity{
+						EnableSSH: true,
+					},
+				},
+			},
+			Assert: func(t *testing.T, role *v1.Role) {
+				assertAllowed(t, role, "create", "", "pods/exec")
+				assertAllowed(t, role, "patch", "", "pods/ephemeralcontainers")
+				assertAllowed(t, role, "create", "", "pods/attach")
+				assertAllowed(t, role, "create", "", "events")
 			},
 		},
 		"space allows logs": {
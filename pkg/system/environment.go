@@ -26,8 +26,40 @@ const (
 	// KnativeServingNamespaceEnvKey is the environment variable that holds
 	// the knative serving namespace.
 	KnativeServingNamespaceEnvKey = "KNATIVE_SERVING_NAMESPACE"
+
+	// KfVersionEnvKey is the environment variable that holds the version of
+	// Kf that's installed.
+	KfVersionEnvKey = "KF_VERSION"
+
+	// BuilderComposerImageEnvKey is the environment variable that holds the
+	// image used to compose builders with custom buildpacks.
+	BuilderComposerImageEnvKey = "BUILDER_COMPOSER_IMAGE"
+
+	// DefaultBuilderComposerImage is used if BuilderComposerImageEnvKey isn't
+	// set.
+	DefaultBuilderComposerImage = "gcr.io/kf-releases/builder-composer:latest"
 )
 
+// KfVersion gets the version of Kf that's installed, "dev" is returned if it
+// isn't set.
+func KfVersion() string {
+	if version := os.Getenv(KfVersionEnvKey); version != "" {
+		return version
+	}
+
+	return "dev"
+}
+
+// BuilderComposerImage gets the image used to compose builders with custom
+// buildpacks, DefaultBuilderComposerImage is returned if it isn't set.
+func BuilderComposerImage() string {
+	if image := os.Getenv(BuilderComposerImageEnvKey); image != "" {
+		return image
+	}
+
+	return DefaultBuilderComposerImage
+}
+
 // Namespace holds the K8s namespace where our serving system
 // components run.
 func Namespace() string {
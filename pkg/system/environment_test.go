@@ -33,3 +33,15 @@ func ExampleKnativeServingNamespace() {
 
 	// Output: knative-serving-testing
 }
+
+func ExampleKfVersion() {
+	fmt.Println(system.KfVersion())
+
+	// Output: dev
+}
+
+func ExampleBuilderComposerImage() {
+	fmt.Println(system.BuilderComposerImage())
+
+	// Output: gcr.io/kf-releases/builder-composer:latest
+}
@@ -23,6 +23,7 @@ import (
 
 	buildv1alpha1 "github.com/google/kf/third_party/knative-build/pkg/client/clientset/versioned/typed/build/v1alpha1"
 	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -33,6 +34,9 @@ import (
 
 const buildExecuteFailed = "BuildExecuteFailed"
 
+// maxLogChunk is the most log output that's buffered per container.
+const maxLogChunk = 16 * 1024
+
 // Tail tails the logs for a build.
 func Tail(ctx context.Context, out io.Writer, buildName, namespace string) error {
 	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
@@ -54,6 +58,16 @@ func Tail(ctx context.Context, out io.Writer, buildName, namespace string) error
 	}
 
 	pods := client.Pods(namespace)
+
+	// Pods of completed builds may be cleaned up, the watch below would never
+	// see them.
+	if _, err := pods.Get(podName, metav1.GetOptions{IncludeUninitialized: true}); err != nil {
+		if apierrs.IsNotFound(err) {
+			return fmt.Errorf("build pod %s no longer exists", podName)
+		}
+		return fmt.Errorf("getting build pod: %v", err)
+	}
+
 	watcher := podWatcher{
 		pods: pods,
 		name: podName,
@@ -186,7 +200,11 @@ func printContainerLogs(ctx context.Context, out io.Writer, pods corev1.PodExpan
 func streamLogs(ctx context.Context, out io.Writer, containerName string, rc io.Reader) error {
 	prefix := green(fmt.Sprintf("[%s]", containerName)) + " "
 
-	r := bufio.NewReader(rc)
+	// Lines are read through a fixed size buffer so a build that logs huge
+	// lines without newlines doesn't grow memory without bound. Long lines
+	// are written in chunks with the prefix only on the first.
+	r := bufio.NewReaderSize(rc, maxLogChunk)
+	startOfLine := true
 	for {
 		select {
 		case <-ctx.Done():
@@ -194,18 +212,27 @@ func streamLogs(ctx context.Context, out io.Writer, containerName string, rc io.
 		default:
 		}
 
-		line, err := r.ReadBytes('\n')
-		if err == io.EOF {
-			if len(line) > 0 {
-				fmt.Fprintf(out, "%s%s\n", prefix, line)
+		chunk, err := r.ReadSlice('\n')
+		if len(chunk) > 0 {
+			if startOfLine {
+				fmt.Fprintf(out, "%s%s", prefix, chunk)
+			} else {
+				out.Write(chunk)
 			}
-			return nil
+			startOfLine = chunk[len(chunk)-1] == '\n'
 		}
-		if err != nil {
+
+		switch {
+		case err == io.EOF:
+			if !startOfLine {
+				fmt.Fprintln(out)
+			}
+			return nil
+		case err == bufio.ErrBufferFull:
+			continue
+		case err != nil:
 			return err
 		}
-
-		fmt.Fprintf(out, "%s%s", prefix, line)
 	}
 }
 